@@ -23,7 +23,7 @@ export function SearchScreen({ route, navigation }: MainScreenProps<'Search'>) {
   const [queryText, setQueryText] = useState('');
   const [debouncedQuery, setDebouncedQuery] = useState('');
   const debounceRef = useRef<ReturnType<typeof setTimeout> | null>(null);
-  const [offset, setOffset] = useState(0);
+  const [cursor, setCursor] = useState<string>();
   const [allMessages, setAllMessages] = useState<SearchMessage[]>([]);
   const [channelId, setChannelId] = useState<string | undefined>();
   const [userId, setUserId] = useState<string | undefined>();
@@ -40,34 +40,34 @@ export function SearchScreen({ route, navigation }: MainScreenProps<'Search'>) {
     query: debouncedQuery,
     channelId,
     userId,
-    offset,
+    cursor,
     limit: 20,
   });
 
   useEffect(() => {
     if (data?.messages) {
-      if (offset === 0) {
+      if (cursor === undefined) {
         setAllMessages(data.messages);
       } else {
         setAllMessages((prev) => [...prev, ...data.messages]);
       }
     }
-  }, [data?.messages, offset]);
+  }, [data?.messages, cursor]);
 
   const handleTextChange = useCallback((text: string) => {
     setQueryText(text);
     if (debounceRef.current) clearTimeout(debounceRef.current);
     debounceRef.current = setTimeout(() => {
       setDebouncedQuery(text.trim());
-      setOffset(0);
+      setCursor(undefined);
     }, 300);
   }, []);
 
   const handleLoadMore = useCallback(() => {
-    if (data?.has_more && !isFetching) {
-      setOffset((prev) => prev + 20);
+    if (data?.next_cursor && !isFetching) {
+      setCursor(data.next_cursor);
     }
-  }, [data?.has_more, isFetching]);
+  }, [data?.next_cursor, isFetching]);
 
   const filterCount = (channelId ? 1 : 0) + (userId ? 1 : 0);
 
@@ -18,6 +18,7 @@ function makeImage(id: string, filename = `image-${id}.png`): Attachment {
     content_type: 'image/png',
     size_bytes: 1024,
     url: `/uploads/${filename}`,
+    status: 'ready',
     created_at: new Date().toISOString(),
   };
 }
@@ -29,6 +30,7 @@ function makeFile(id: string, filename = `doc-${id}.pdf`): Attachment {
     content_type: 'application/pdf',
     size_bytes: 2048,
     url: `/uploads/${filename}`,
+    status: 'ready',
     created_at: new Date().toISOString(),
   };
 }
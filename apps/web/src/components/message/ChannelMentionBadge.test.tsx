@@ -10,10 +10,16 @@ function makeChannel(overrides: Partial<ChannelWithMembership> = {}): ChannelWit
     name: 'general',
     type: 'public',
     is_default: false,
+    auto_join: false,
+    history_visibility: 'all',
+    post_policy: 'everyone',
+    restrict_thread_replies: false,
+    slow_mode_seconds: 0,
     created_at: new Date().toISOString(),
     updated_at: new Date().toISOString(),
     unread_count: 0,
     notification_count: 0,
+    member_count: 1,
     is_starred: false,
     ...overrides,
   };
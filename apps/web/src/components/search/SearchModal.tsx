@@ -61,7 +61,8 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
   const [userFilter, setUserFilter] = useState('');
   const [afterFilter, setAfterFilter] = useState<DateValue | null>(null);
   const [beforeFilter, setBeforeFilter] = useState<DateValue | null>(null);
-  const [offset, setOffset] = useState(0);
+  const [cursor, setCursor] = useState<string>();
+  const [totalCount, setTotalCount] = useState(0);
 
   const { data: channelsData } = useChannels(workspaceId);
   const { data: membersData } = useWorkspaceMembers(workspaceId);
@@ -74,14 +75,14 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
     after: dateValueToISO(afterFilter),
     before: dateValueToISO(beforeFilter, true),
     limit: 20,
-    offset,
+    cursor,
   });
 
   // Debounce query input
   useEffect(() => {
     const timer = setTimeout(() => {
       setDebouncedQuery(inputValue);
-      setOffset(0);
+      setCursor(undefined);
     }, 300);
     return () => clearTimeout(timer);
   }, [inputValue]);
@@ -95,7 +96,7 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
     setUserFilter('');
     setAfterFilter(null);
     setBeforeFilter(null);
-    setOffset(0);
+    setCursor(undefined);
   }
   if (isOpen !== prevIsOpen) {
     setPrevIsOpen(isOpen);
@@ -110,15 +111,19 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
   );
 
   const handleLoadMore = () => {
-    if (data && data.has_more) {
-      setOffset((prev) => prev + 20);
+    if (data?.next_cursor) {
+      setCursor(data.next_cursor);
     }
   };
 
   const channels = channelsData?.channels || [];
   const members = membersData?.members || [];
   const messages = data?.messages || [];
-  const totalCount = data?.total_count || 0;
+
+  // Only the first page carries total_count, so keep it while paging
+  if (data?.total_count !== undefined && data.total_count !== totalCount) {
+    setTotalCount(data.total_count);
+  }
 
   return (
     <ModalOverlay
@@ -163,7 +168,7 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
                 value={channelFilter}
                 onChange={(e) => {
                   setChannelFilter(e.target.value);
-                  setOffset(0);
+                  setCursor(undefined);
                 }}
                 className="rounded border border-gray-300 bg-white px-2 py-1 text-xs text-gray-700 dark:border-gray-600 dark:bg-gray-700 dark:text-gray-300"
               >
@@ -182,7 +187,7 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
                 value={userFilter}
                 onChange={(e) => {
                   setUserFilter(e.target.value);
-                  setOffset(0);
+                  setCursor(undefined);
                 }}
                 className="rounded border border-gray-300 bg-white px-2 py-1 text-xs text-gray-700 dark:border-gray-600 dark:bg-gray-700 dark:text-gray-300"
               >
@@ -198,7 +203,7 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
                 value={afterFilter}
                 onChange={(value) => {
                   setAfterFilter(value);
-                  setOffset(0);
+                  setCursor(undefined);
                 }}
                 maxValue={beforeFilter ?? undefined}
               />
@@ -207,7 +212,7 @@ export function SearchModal({ isOpen, onClose, initialChannelId, initialQuery }:
                 value={beforeFilter}
                 onChange={(value) => {
                   setBeforeFilter(value);
-                  setOffset(0);
+                  setCursor(undefined);
                 }}
                 minValue={afterFilter ?? undefined}
               />
@@ -32,6 +32,7 @@ export function createMockWorkspace(overrides: Partial<Workspace> = {}): Workspa
     id,
     name: `Test Workspace ${id}`,
     settings: '{}',
+    read_only: false,
     created_at: new Date().toISOString(),
     updated_at: new Date().toISOString(),
     ...overrides,
@@ -58,6 +59,11 @@ export function createMockChannel(overrides: Partial<Channel> = {}): Channel {
     name: `channel-${id}`,
     type: 'public',
     is_default: false,
+    auto_join: false,
+    history_visibility: 'all',
+    post_policy: 'everyone',
+    restrict_thread_replies: false,
+    slow_mode_seconds: 0,
     created_at: new Date().toISOString(),
     updated_at: new Date().toISOString(),
     ...overrides,
@@ -13,7 +13,7 @@ export interface paths {
         };
         /**
          * Get server information
-         * @description Returns server version, feature flags, and capability information. Useful for clients to detect which features are available (e.g. email, file uploads) before making further requests. Does not require authentication.
+         * @description Returns server version, feature flags, limits and event stream endpoints. Desktop and mobile clients call it before login to check they are supported (`min_client_version`) and to adapt to what the server offers (e.g. email, file uploads, push notifications). Does not require authentication.
          */
         get: operations["getServerInfo"];
         put?: never;
@@ -35,7 +35,7 @@ export interface paths {
         put?: never;
         /**
          * Register a new user
-         * @description Create a new user account with an email, password, and display name. Returns a session token that can be used for subsequent authenticated requests. If email verification is enabled on the server, a verification email will be sent.
+         * @description Create a new user account with an email, password, and display name. Returns an access token that can be used for subsequent authenticated requests, and a refresh token for obtaining new access tokens. If email verification is enabled on the server, a verification email will be sent.
          */
         post: operations["register"];
         delete?: never;
@@ -55,7 +55,7 @@ export interface paths {
         put?: never;
         /**
          * Log in a user
-         * @description Authenticate with email and password. Returns a session token and user object. The token should be included as a Bearer token in the Authorization header for all authenticated requests.
+         * @description Authenticate with email and password. Returns an access token, a refresh token, and the user object. The access token should be included as a Bearer token in the Authorization header for all authenticated requests. When it expires (see `expires_at`), exchange the refresh token for a new pair with `/auth/refresh`.
          */
         post: operations["login"];
         delete?: never;
@@ -75,7 +75,7 @@ export interface paths {
         put?: never;
         /**
          * Log out the current user
-         * @description Invalidate the current session token. After logging out, the token can no longer be used for authenticated requests.
+         * @description Revoke the current session. After logging out, neither its access token nor any of its refresh tokens can be used.
          */
         post: operations["logout"];
         delete?: never;
@@ -84,6 +84,26 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/auth/refresh": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Refresh an access token
+         * @description Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once; the old one stops working as soon as it is exchanged. Presenting a refresh token that has already been exchanged is treated as theft: the whole session is revoked and the request fails with `REFRESH_TOKEN_REUSED`.
+         */
+        post: operations["refreshToken"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/auth/forgot-password": {
         parameters: {
             query?: never;
@@ -236,6 +256,14 @@ export interface paths {
         /**
          * Create a new workspace
          * @description Create a new workspace. The authenticated user becomes the owner. Workspace names must be unique and a URL-friendly slug is generated automatically.
+         *
+         *     Pass `template_id` to create a channel template's channels along with the workspace.
+         *
+         *     Errors:
+         *     - 400: Missing name.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not an admin or owner of the template's workspace.
+         *     - 404: Channel template not found.
          */
         post: operations["createWorkspace"];
         delete?: never;
@@ -284,7 +312,7 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/members/list": {
+    "/workspaces/{wid}/read-only": {
         parameters: {
             query?: never;
             header?: never;
@@ -294,77 +322,105 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List workspace members
-         * @description List all members of a workspace with their roles, display names, and ban status.
+         * Turn read-only mode on or off
+         * @description Put the workspace into read-only mode, e.g. during a migration or an incident, or take it out again. While read-only, sending and editing messages, reactions and file uploads fail with 503 and code `READ_ONLY`; reading and live updates keep working. The optional message is returned with those errors and in the workspace payload so clients can show it as a banner. Requires the owner role. Members receive a `workspace.updated` event.
          */
-        post: operations["listWorkspaceMembers"];
+        post: operations["setWorkspaceReadOnly"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/members/remove": {
+    "/workspaces/{wid}/storage": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Remove a member from workspace
-         * @description Remove a member from the workspace. Admins can remove members, owners can remove anyone except themselves. The removed user loses access to all workspace channels.
+         * Get workspace storage usage
+         * @description Report how much attachment storage the workspace uses, the server-wide per-workspace quota, and the workspace's attachment retention period. Requires admin or owner role in the workspace.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
          */
-        post: operations["removeWorkspaceMember"];
+        get: operations["getWorkspaceStorage"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/leave": {
+    "/workspaces/{wid}/features": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Leave a workspace
-         * @description Voluntarily leave a workspace. The workspace owner cannot leave without first transferring ownership. Leaving removes access to all channels in the workspace.
+         * List workspace feature flags
+         * @description List every feature flag the server knows and whether it is on in the workspace, so clients can show or hide the UI for features that are being rolled out. A flag's value comes from a workspace override if one is set, otherwise from the server's `features` config, otherwise from its built-in default; `source` says which.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a member of the workspace.
          */
-        post: operations["leaveWorkspace"];
+        get: operations["listWorkspaceFeatures"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/members/update-role": {
+    "/workspaces/{wid}/features/{key}": {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+                /** @description Feature flag key */
+                key: string;
+            };
             cookie?: never;
         };
         get?: never;
-        put?: never;
         /**
-         * Update member role
-         * @description Change a member's role within the workspace (e.g. member to admin). Only owners can promote to admin, and only admins/owners can change roles. Cannot change your own role.
+         * Set a workspace feature flag
+         * @description Turn a feature flag on or off for the workspace, overriding the server setting. Requires admin or owner role in the workspace. The change is recorded in the audit log.
+         *
+         *     Errors:
+         *     - 400: Invalid body.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: Unknown feature flag.
          */
-        post: operations["updateWorkspaceMemberRole"];
-        delete?: never;
+        put: operations["setWorkspaceFeature"];
+        post?: never;
+        /**
+         * Reset a workspace feature flag
+         * @description Remove the workspace's override so the flag follows the server setting again. Returns the value now in effect. Requires admin or owner role in the workspace.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: Unknown feature flag.
+         */
+        delete: operations["resetWorkspaceFeature"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/invites/create": {
+    "/workspaces/{wid}/exports": {
         parameters: {
             query?: never;
             header?: never;
@@ -374,17 +430,22 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Create an invite
-         * @description Generate an invite link for the workspace. Invites can be configured with a maximum number of uses and an expiration date. Requires the appropriate permission level configured in workspace settings.
+         * Start a workspace export
+         * @description Queue a full export of the workspace. A background job builds a ZIP archive with a JSON file for the workspace (members and channel index) and one per channel (members, and messages with reactions and attachments), plus the attachment files. Every channel is included, private channels and direct messages too. Poll `GET /exports/{id}` until the status is `completed`, then fetch the archive from `GET /exports/{id}/download`. Archives are deleted 7 days after they are built. Only workspace owners can export.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace owner, or file storage is disabled.
+         *     - 409: An export of this workspace is already queued or running.
          */
-        post: operations["createWorkspaceInvite"];
+        post: operations["createWorkspaceExport"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/notifications": {
+    "/exports/{id}": {
         parameters: {
             query?: never;
             header?: never;
@@ -392,10 +453,14 @@ export interface paths {
             cookie?: never;
         };
         /**
-         * Get notification summaries for all workspaces
-         * @description Get unread message counts and mention counts for all workspaces the current user belongs to. Useful for showing notification badges in the workspace switcher.
+         * Get workspace export status
+         * @description Return the status of a workspace or channel export. Only owners of the exported workspace can view a workspace export; a channel export can be viewed by anyone who could start it.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 404: Export not found, or caller cannot view it.
          */
-        get: operations["getWorkspaceNotifications"];
+        get: operations["getWorkspaceExport"];
         put?: never;
         post?: never;
         delete?: never;
@@ -404,27 +469,31 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
-    "/workspaces/reorder": {
+    "/exports/{id}/download": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Reorder workspaces for current user
-         * @description Set a custom display order for workspaces in the sidebar. Accepts a mapping of workspace IDs to sort positions. Only affects the current user's view.
+         * Download a workspace export
+         * @description Download the file of a completed export: a ZIP archive for a workspace export, JSON or CSV for a channel export. With S3 storage the response is a redirect to a short-lived pre-signed URL. Exports can be downloaded by the same callers that can view them.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 404: Export not found, not completed yet, expired, or caller cannot view it.
          */
-        post: operations["reorderWorkspaces"];
+        get: operations["downloadWorkspaceExport"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/invites/{code}/accept": {
+    "/workspaces/{wid}/members/list": {
         parameters: {
             query?: never;
             header?: never;
@@ -434,37 +503,50 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Accept an invite
-         * @description Join a workspace using an invite code. The invite must be valid (not expired, not at max uses). The user is added as a member and automatically joins the workspace's default channels.
+         * List workspace members
+         * @description List the members of a workspace with their roles, display names, and ban status. Results are paged: pass the previous response's `next_cursor` as `cursor` while `has_more` is true. `q` filters by display name or email, `role` by workspace role, and `sort` orders by join date (the default) or name. Deactivated accounts are left out unless an admin sets `include_deactivated`. Send the previous response's `ETag` in `If-None-Match` to get `304 Not Modified` when the page is unchanged.
+         *
+         *     Errors:
+         *     - 400: Unknown cursor.
+         *     - 401: Not authenticated.
+         *     - 403: include_deactivated was set by someone who is not an admin.
          */
-        post: operations["acceptInvite"];
+        post: operations["listWorkspaceMembers"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/channels/create": {
+    "/workspaces/{wid}/notification-settings": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Create a channel
-         * @description Create a new channel in the workspace. Channel names must be unique within the workspace and contain only lowercase letters, numbers, and hyphens. The creator is automatically added as a member with admin role.
+         * Get workspace notification settings
+         * @description Get the current user's notification settings for a workspace. Channels without preferences of their own use them. `inherited` is true when the workspace follows the user's global settings.
          */
-        post: operations["createChannel"];
-        delete?: never;
+        get: operations["getWorkspaceNotificationSettings"];
+        /**
+         * Update workspace notification settings
+         * @description Set the current user's notification settings for a workspace, overriding their global settings there.
+         */
+        put: operations["updateWorkspaceNotificationSettings"];
+        post?: never;
+        /**
+         * Reset workspace notification settings
+         * @description Remove the current user's notification settings for a workspace so it follows their global settings again. Returns the settings now in effect.
+         */
+        delete: operations["resetWorkspaceNotificationSettings"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/channels/list": {
+    "/workspaces/{wid}/snooze": {
         parameters: {
             query?: never;
             header?: never;
@@ -474,37 +556,41 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List channels in workspace
-         * @description List all channels in the workspace that the current user has access to. Includes the user's membership status and unread counts for each channel. Private channels are only listed if the user is a member.
+         * Snooze workspace notifications
+         * @description Silence notifications from every channel in a workspace, including direct messages, for a while. Works like a channel snooze; where both are running the later end time applies.
          */
-        post: operations["listChannels"];
-        delete?: never;
+        post: operations["snoozeWorkspace"];
+        /**
+         * End a workspace snooze
+         * @description End the current user's workspace snooze before it runs out. Channel snoozes still apply.
+         */
+        delete: operations["unsnoozeWorkspace"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/channels/dm": {
+    "/workspaces/{wid}/members/count": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Create or get DM channel
-         * @description Create a direct message channel with one or more users. If a DM already exists between the same set of users, returns the existing channel. For two users, creates a 1:1 DM; for more, creates a group DM.
+         * Count workspace members
+         * @description Count the workspace's members without listing them. Deactivated accounts are not counted.
          */
-        post: operations["createDM"];
+        get: operations["countWorkspaceMembers"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/update": {
+    "/workspaces/{wid}/members/remove": {
         parameters: {
             query?: never;
             header?: never;
@@ -514,17 +600,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Update channel
-         * @description Update channel properties such as name, description, or visibility (public/private). Requires channel admin role or workspace admin/owner role.
+         * Remove a member from workspace
+         * @description Remove a member from the workspace. Admins can remove members, owners can remove anyone except themselves. The removed user loses access to all workspace channels.
          */
-        post: operations["updateChannel"];
+        post: operations["removeWorkspaceMember"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/convert": {
+    "/workspaces/{wid}/members/deactivate": {
         parameters: {
             query?: never;
             header?: never;
@@ -534,17 +620,22 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Convert group DM to channel
-         * @description Convert a group DM into a named public or private channel. Requires the caller to be a member of the group DM. All existing members and message history are preserved.
+         * Deactivate a member's account
+         * @description Deactivate a member's account. The user can no longer sign in, their sessions are revoked and they are shown as offline, but their memberships and messages are kept. Requires admin role, and the caller must outrank the user in every workspace the user belongs to, since deactivation applies to the whole account.
+         *
+         *     Errors:
+         *     - 400: Self-deactivation attempted, the user is a bot, or the user is already deactivated.
+         *     - 403: Caller lacks admin role or does not outrank the user everywhere.
+         *     - 404: The user is not a member of this workspace.
          */
-        post: operations["convertGroupDMToChannel"];
+        post: operations["deactivateMember"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/archive": {
+    "/workspaces/{wid}/members/reactivate": {
         parameters: {
             query?: never;
             header?: never;
@@ -554,17 +645,22 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Archive channel
-         * @description Archive a channel, preventing new messages from being sent. Archived channels remain visible and searchable but are moved to an archived section. Requires channel admin or workspace admin/owner role.
+         * Reactivate a member's account
+         * @description Allow a deactivated member to sign in again. Same permission rules as deactivation. Accounts deleted by their owner cannot be reactivated.
+         *
+         *     Errors:
+         *     - 400: The user is not deactivated, or the account has been deleted.
+         *     - 403: Caller lacks admin role or does not outrank the user everywhere.
+         *     - 404: The user is not a member of this workspace.
          */
-        post: operations["archiveChannel"];
+        post: operations["reactivateMember"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/members/add": {
+    "/workspaces/{wid}/leave": {
         parameters: {
             query?: never;
             header?: never;
@@ -574,17 +670,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Add member to channel
-         * @description Add a workspace member to a channel. For public channels, any member can add others. For private channels, only channel members can add others.
+         * Leave a workspace
+         * @description Voluntarily leave a workspace. The workspace owner cannot leave without first transferring ownership. Leaving removes access to all channels in the workspace.
          */
-        post: operations["addChannelMember"];
+        post: operations["leaveWorkspace"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/members/list": {
+    "/workspaces/{wid}/members/update-role": {
         parameters: {
             query?: never;
             header?: never;
@@ -594,37 +690,42 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List channel members
-         * @description List all members of a channel with their roles and join dates.
+         * Update member role
+         * @description Change a member's role within the workspace (e.g. member to admin). Only owners can promote to admin, and only admins/owners can change roles. Cannot change your own role.
          */
-        post: operations["listChannelMembers"];
+        post: operations["updateWorkspaceMemberRole"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/join": {
+    "/workspaces/{wid}/members/{userId}/profile": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Join a channel
-         * @description Join a public channel. Private channels cannot be joined directly — a current member must add you using the add member endpoint.
+         * Get member profile card
+         * @description Everything a profile hover card shows, in one call: the member's profile, workspace role and custom profile values, whether they are online and when they were last seen, their current local time, how many of their messages the caller can see, and the channels they share with the caller. At most 10 shared channels are returned, by name; `shared_channel_count` is the total.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a member of the workspace.
+         *     - 404: User is not a member of the workspace.
          */
-        post: operations["joinChannel"];
+        get: operations["getMemberProfile"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/leave": {
+    "/workspaces/{wid}/invites/create": {
         parameters: {
             query?: never;
             header?: never;
@@ -634,105 +735,120 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Leave a channel
-         * @description Leave a channel. You will no longer receive messages or notifications from this channel. The channel owner cannot leave without first transferring ownership.
+         * Create an invite
+         * @description Generate an invite link for the workspace. Invites can be configured with a maximum number of uses and an expiration date. Requires the appropriate permission level configured in workspace settings. When `invited_email` is set the invite link is emailed to that address (if email is configured), and only an account with that email can accept it.
          */
-        post: operations["leaveChannel"];
+        post: operations["createWorkspaceInvite"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/star": {
+    "/workspaces/{wid}/join": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Star a channel
-         * @description Star a channel to pin it to the top of your sidebar. Starred channels appear in a separate section for quick access.
+         * Get open signup details
+         * @description Show what the workspace's join link leads to: the workspace name and icon, the allowed email domains, and whether the caller can join. Only available while open signup is on.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 404: Workspace not found, or open signup is off.
          */
-        post: operations["starChannel"];
+        get: operations["getWorkspaceSignup"];
+        put?: never;
         /**
-         * Unstar a channel
-         * @description Remove a channel from your starred list.
+         * Join through open signup
+         * @description Join the workspace as a member without an invite, when open signup is on and the caller's email is on one of the allowed domains. The new member joins the default and auto-join channels and gets the same welcome DMs as after accepting an invite. Joining when already a member succeeds without changes. Joins are rate limited per workspace.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Email domain not allowed, email not verified, or banned from the workspace. Without email configured addresses can't be verified, so only accounts verified another way (such as created with `enzyme admin create-user`) can join.
+         *     - 404: Workspace not found, or open signup is off.
+         *     - 429: Too many members joined this workspace recently.
          */
-        delete: operations["unstarChannel"];
+        post: operations["joinWorkspace"];
+        delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/mark-read": {
+    "/workspaces/{wid}/invites": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Mark channel as read
-         * @description Mark a channel as read up to a specific message, or up to the latest message if no message ID is provided. Updates the unread count and clears notification badges for this channel.
+         * List invites
+         * @description List the workspace's invites, newest first, including expired and used-up ones until they are cleaned up. Admins and owners see every invite; other members who may create invites see only their own.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Not a member of the workspace, or not allowed to create invites.
          */
-        post: operations["markChannelRead"];
+        get: operations["listWorkspaceInvites"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/notifications": {
+    "/invites/{id}": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        /**
-         * Get channel notification preferences
-         * @description Get the current user's notification preferences for a specific channel, such as whether to receive notifications for all messages, mentions only, or nothing.
-         */
-        get: operations["getChannelNotifications"];
+        get?: never;
         put?: never;
+        post?: never;
         /**
-         * Update channel notification preferences
-         * @description Set notification preferences for a specific channel. Overrides the workspace-level notification defaults for this channel only.
+         * Revoke an invite
+         * @description Delete an invite so its code can no longer be accepted. Members who already joined with it are not affected. Admins and owners can revoke any invite; other members can revoke invites they created.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Not allowed to revoke this invite.
+         *     - 404: Invite not found.
          */
-        post: operations["updateChannelNotifications"];
-        delete?: never;
+        delete: operations["revokeInvite"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/channels/mark-all-read": {
+    "/workspaces/notifications": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Mark all channels as read
-         * @description Mark all channels in the workspace as read. Clears all unread counts and notification badges across every channel the user is a member of.
+         * Get notification summaries for all workspaces
+         * @description Get unread message counts, mention counts, and unread thread counts for all workspaces the current user belongs to. Useful for showing notification badges in the workspace switcher.
          */
-        post: operations["markAllChannelsRead"];
+        get: operations["getWorkspaceNotifications"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/unreads": {
+    "/workspaces/reorder": {
         parameters: {
             query?: never;
             header?: never;
@@ -742,17 +858,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List all unread messages across channels
-         * @description Fetch unread messages across all channels in the workspace. Returns messages grouped by channel with cursor-based pagination. Useful for building an "All Unreads" view.
+         * Reorder workspaces for current user
+         * @description Set a custom display order for workspaces in the sidebar. Accepts a mapping of workspace IDs to sort positions. Only affects the current user's view.
          */
-        post: operations["listAllUnreads"];
+        post: operations["reorderWorkspaces"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/messages/search": {
+    "/invites/{code}/accept": {
         parameters: {
             query?: never;
             header?: never;
@@ -762,17 +878,22 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Search messages in workspace
-         * @description Full-text search across messages in the workspace. Supports filtering by channel, user, and date range. Results include surrounding context and are ranked by relevance.
+         * Accept an invite
+         * @description Join a workspace using an invite code. The invite must be valid (not expired, not at max uses). The user is added as a member and automatically joins the workspace's default channels.
+         *
+         *     Errors:
+         *     - 400: The invite has expired or reached its maximum uses.
+         *     - 403: Banned from the workspace, or the invite is for a different email address. The caller's email must also be verified to use an email-bound invite; without email configured that is only true of accounts created with `enzyme admin create-user`.
+         *     - 404: No invite with this code, or it was revoked.
          */
-        post: operations["searchMessages"];
+        post: operations["acceptInvite"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/threads": {
+    "/workspaces/{wid}/channels/create": {
         parameters: {
             query?: never;
             header?: never;
@@ -782,97 +903,101 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List threads user is subscribed to
-         * @description List threads that the current user is subscribed to in the workspace. Includes the root message and latest replies with unread counts. Supports cursor-based pagination.
+         * Create a channel
+         * @description Create a new channel in the workspace. Channel names must be unique within the workspace and contain only lowercase letters, numbers, and hyphens. The creator is automatically added as a member with admin role.
          */
-        post: operations["listUserThreads"];
+        post: operations["createChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}": {
+    "/workspaces/{wid}/channels/list": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
+        get?: never;
+        put?: never;
         /**
-         * Get a single message
-         * @description Retrieve a single message by ID, including its author, reactions, thread metadata, and file attachments.
+         * List channels in workspace
+         * @description List all channels in the workspace that the current user has access to. Includes the user's membership status and unread counts for each channel. Private channels are only listed if the user is a member. Send the previous response's `ETag` in `If-None-Match` to get `304 Not Modified` when nothing in the list has changed.
          */
-        get: operations["getMessage"];
-        put?: never;
-        post?: never;
+        post: operations["listChannels"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/messages/send": {
+    "/workspaces/{wid}/channels/archived": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Send a message
-         * @description Send a new message to a channel. Supports plain text content, file attachments (by referencing previously uploaded file IDs), and threading (by setting a parent message ID). The sender must be a member of the channel.
+         * List archived channels
+         * @description List the workspace's archived public and private channels, most recently archived first, so admins can find and restore them with `/channels/{id}/unarchive`. Requires workspace admin/owner role.
          */
-        post: operations["sendMessage"];
+        get: operations["listArchivedChannels"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/messages/list": {
+    "/workspaces/{wid}/channels/browse": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * List messages in channel
-         * @description List messages in a channel with cursor-based pagination. Returns messages in reverse chronological order by default. Supports fetching around a specific message for scroll-to-message functionality.
+         * Browse public channels
+         * @description List the workspace's unarchived public channels for a channel browser, whether or not the caller has joined them, with member counts, the caller's membership and a preview of each channel's latest message. Results are paged with `limit` and `offset`; `total_count` is the number of channels matching `q`.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Not a member of the workspace.
          */
-        post: operations["listMessages"];
+        get: operations["browseChannels"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/update": {
+    "/workspaces/{wid}/unread-counts": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Update a message
-         * @description Edit the content of a previously sent message. Only the message author can edit their own messages. An edit indicator is shown on the message after updating.
+         * Get unread counts
+         * @description Get unread and notification counts for every channel the user belongs to, without the rest of the channel list. Clients polling for badge updates should send the previous response's `ETag` in `If-None-Match`; if nothing that affects the counts has changed, the server answers `304 Not Modified` without computing them.
          */
-        post: operations["updateMessage"];
+        get: operations["getUnreadCounts"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/delete": {
+    "/workspaces/{wid}/sync": {
         parameters: {
             query?: never;
             header?: never;
@@ -882,17 +1007,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Delete a message
-         * @description Delete a message. Authors can delete their own messages. Channel admins and workspace admins/owners can delete any message in channels they have access to.
+         * Catch up after a gap
+         * @description Catch up on several channels in one request, for clients resuming after sleep or a lost connection. Pass the ID of the newest message the client has for each channel. The response contains the messages posted after it (oldest first, up to 100 per channel), the user's current channel list with read state and unread counts, and the requested channels the user can no longer read. When `has_more` is set for a channel, page through the rest with `/channels/{id}/messages/list` using `direction: after`.
          */
-        post: operations["deleteMessage"];
+        post: operations["syncWorkspace"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/link-preview/delete": {
+    "/workspaces/{wid}/channels/dm": {
         parameters: {
             query?: never;
             header?: never;
@@ -902,17 +1027,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Delete a message's link preview
-         * @description Remove the link preview (unfurl) from a message. Only the message author can remove link previews from their own messages.
+         * Create or get DM channel
+         * @description Create a direct message channel with one or more users. If a DM already exists between the same set of users, returns the existing channel. For two users, creates a 1:1 DM; for more, creates a group DM.
          */
-        post: operations["deleteLinkPreview"];
+        post: operations["createDM"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/reactions/add": {
+    "/channels/{id}/update": {
         parameters: {
             query?: never;
             header?: never;
@@ -922,17 +1047,23 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Add reaction to message
-         * @description Add an emoji reaction to a message. Each user can only add each unique emoji once per message. Supports both standard Unicode emoji and custom workspace emoji.
+         * Update channel
+         * @description Update channel properties such as name, description, visibility (public/private), or who may post. Requires channel admin role or workspace admin/owner role.
+         *
+         *     Setting post_policy to `admins` makes an announcement channel: only channel and workspace admins can post, while everyone can still reply in threads unless restrict_thread_replies is set. Members receive a `channel.updated` event.
+         *
+         *     slow_mode_seconds sets the minimum interval between two messages from the same member, from 0 (off) to 21600 (6 hours). Channel and workspace admins are not limited.
+         *
+         *     Workspace admins can set auto_join so people who accept an invite join the channel along with the default channel. Pass backfill_members to add existing workspace members too; each added member receives a `channel.member_added` event.
          */
-        post: operations["addReaction"];
+        post: operations["updateChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/reactions/remove": {
+    "/channels/{id}/topic": {
         parameters: {
             query?: never;
             header?: never;
@@ -942,17 +1073,23 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Remove reaction from message
-         * @description Remove your emoji reaction from a message. You can only remove reactions that you previously added.
+         * Set channel topic
+         * @description Set the short topic shown in the channel header, separate from the description. Any member who can post in the channel may change it; an empty topic clears it. Posts a `channel_topic_changed` system message and broadcasts `channel.updated`.
+         *
+         *     Errors:
+         *     - 400: The channel is archived or the topic is too long.
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot post in the channel.
+         *     - 404: Channel not found.
          */
-        post: operations["removeReaction"];
+        post: operations["setChannelTopic"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/mark-unread": {
+    "/channels/{id}/convert": {
         parameters: {
             query?: never;
             header?: never;
@@ -962,17 +1099,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Mark message as unread
-         * @description Mark a message as unread, setting the channel's read position to just before this message. The channel will appear as having unread messages starting from this point.
+         * Convert group DM to channel
+         * @description Convert a group DM into a named public or private channel. Requires the caller to be a member of the group DM. All existing members and message history are preserved.
          */
-        post: operations["markMessageUnread"];
+        post: operations["convertGroupDMToChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/thread/mark-read": {
+    "/channels/{id}/export": {
         parameters: {
             query?: never;
             header?: never;
@@ -982,17 +1119,26 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Mark thread as read
-         * @description Mark a thread as read up to a specific reply, or up to the latest reply if no reply ID is provided. Updates the thread's unread count.
+         * Start a channel export
+         * @description Queue an export of a channel's messages, thread replies included, with their authors, timestamps and reactions. `json` produces a single document in the same shape as a channel file in a workspace export; `csv` produces one row per message, linked to its thread by `thread_parent_id`, with reactions as `emoji:count` and attachments by filename. Attachment contents are not included. A background job builds the file: poll `GET /exports/{id}`, which reports `messages_exported` out of `messages_total`, until the status is `completed`, then fetch the file from `GET /exports/{id}/download`. Files are deleted 7 days after they are built.
+         *
+         *     Channel admins and workspace admins can export a channel, but workspace admins can only export private channels and DMs they are members of. Workspace owners can export any channel.
+         *
+         *     Errors:
+         *     - 400: Unknown format.
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot export this channel, or file storage is disabled.
+         *     - 404: Channel not found.
+         *     - 409: An export of this channel is already queued or running.
          */
-        post: operations["markThreadRead"];
+        post: operations["createChannelExport"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/thread/list": {
+    "/channels/{id}/archive": {
         parameters: {
             query?: never;
             header?: never;
@@ -1002,37 +1148,43 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List thread replies
-         * @description List replies in a message thread with cursor-based pagination. Returns the thread replies in chronological order.
+         * Archive channel
+         * @description Archive a channel, preventing new messages from being sent. Archived channels remain visible and searchable but are moved to an archived section. Posts a `channel_archived` system message and sends `channel.archived`. Requires workspace admin/owner role.
          */
-        post: operations["listThread"];
+        post: operations["archiveChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/subscription": {
+    "/channels/{id}/unarchive": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
+        get?: never;
+        put?: never;
         /**
-         * Get thread subscription status
-         * @description Check whether the current user is subscribed to a thread. Subscribed users receive notifications for new replies.
+         * Unarchive channel
+         * @description Restore an archived channel so members can post again. Its members are kept from before it was archived. Posts a `channel_unarchived` system message and sends `channel.unarchived` to the workspace (or, for private channels, to its members). Requires workspace admin/owner role.
+         *
+         *     Errors:
+         *     - 400: Channel is not archived.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: Channel not found.
          */
-        get: operations["getThreadSubscription"];
-        put?: never;
-        post?: never;
+        post: operations["unarchiveChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/subscribe": {
+    "/channels/{id}/retention/update": {
         parameters: {
             query?: never;
             header?: never;
@@ -1042,17 +1194,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Subscribe to thread
-         * @description Subscribe to a thread to receive notifications for new replies. Users are automatically subscribed when they reply to a thread.
+         * Update channel message retention
+         * @description Set how long messages in a channel are kept. A thread is permanently deleted, with its replies, reactions and attachments, once its latest activity is older than the retention period. Omit `message_retention_days` or set it to null to use the workspace default. Requires workspace admin or owner role.
          */
-        post: operations["subscribeToThread"];
+        post: operations["updateChannelRetention"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/unsubscribe": {
+    "/channels/{id}/retention/preview": {
         parameters: {
             query?: never;
             header?: never;
@@ -1062,17 +1214,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Unsubscribe from thread
-         * @description Unsubscribe from a thread to stop receiving notifications for new replies.
+         * Preview channel message retention
+         * @description Report what the purge job would delete from a channel without deleting anything. Uses the channel's current retention unless `message_retention_days` is given. Requires workspace admin or owner role.
          */
-        post: operations["unsubscribeFromThread"];
+        post: operations["previewChannelRetention"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/pin": {
+    "/channels/{id}/mention-preview": {
         parameters: {
             query?: never;
             header?: never;
@@ -1082,48 +1234,47 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Pin a message
-         * @description Pin a message to its channel. Any workspace member with access to the channel can pin messages. A channel can have at most 50 pinned messages. Already-pinned messages cannot be pinned again. The pinned message is returned with updated metadata including the pinned_by user and pinned_at timestamp.
+         * Preview a channel-wide mention
+         * @description Report how many people @channel/@everyone and @here would notify in the channel, and whether the caller may use them, so clients can confirm before sending. The sender, members who muted the channel and users in a block relationship with the sender are not counted. @here counts members who are online.
          *
          *     Errors:
-         *     - 400: Message is already pinned, or the channel has reached the 50-pin limit.
          *     - 401: Not authenticated.
-         *     - 403: Caller does not have access to the channel.
-         *     - 404: Message not found.
+         *     - 403: Caller cannot read the channel.
+         *     - 404: Channel not found.
          */
-        post: operations["pinMessage"];
+        post: operations["previewChannelMention"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/messages/{id}/unpin": {
+    "/channels/{id}/mention-candidates": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Unpin a message
-         * @description Unpin a previously pinned message from its channel. Any workspace member with access to the channel can unpin messages (not restricted to the user who originally pinned it). The message is returned with its pin metadata cleared.
+         * List mention candidates
+         * @description Autocomplete for @mentions in the composer. Returns the channel's active members whose display name (or any word of it) or email starts with `q`, most recent posters in the channel first, with whether each is online. User groups whose handle or name starts with `q` are listed separately. An empty `q` returns the most recent posters.
          *
          *     Errors:
          *     - 401: Not authenticated.
-         *     - 403: Caller does not have access to the channel.
-         *     - 404: Message not found or message is not currently pinned.
+         *     - 403: Caller cannot read the channel.
+         *     - 404: Channel not found.
          */
-        post: operations["unpinMessage"];
+        get: operations["listMentionCandidates"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/pins/list": {
+    "/channels/{id}/focus": {
         parameters: {
             query?: never;
             header?: never;
@@ -1133,42 +1284,43 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List pinned messages in channel
-         * @description List all pinned messages in a channel with cursor-based pagination. Returns messages in reverse chronological order of when they were pinned. Only workspace members with access to the channel can list its pins.
+         * Report channel focus
+         * @description Report that one of the caller's event stream connections is (or stops) showing the channel on screen. A connection views at most one channel, so focusing a channel replaces its previous focus, and focus is cleared when the connection closes. While focused, the channel is marked read as messages arrive and no notifications are sent for them. Focusing also marks the channel read up to its latest message. Members of the channel receive `channel.viewers` events as viewers come and go.
          *
          *     Errors:
+         *     - 400: client_id is not one of the caller's open event stream connections.
          *     - 401: Not authenticated.
-         *     - 403: Caller does not have access to the channel.
+         *     - 403: Caller cannot read the channel.
          *     - 404: Channel not found.
          */
-        post: operations["listPinnedMessages"];
+        post: operations["focusChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/files/upload": {
+    "/channels/{id}/viewers": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Upload a file
-         * @description Upload a file to a channel. The file is stored on the server and a file object is returned with an ID that can be referenced when sending a message. Maximum file size and allowed types are configured server-side.
+         * List channel viewers
+         * @description List the users who currently have the channel on screen.
          */
-        post: operations["uploadFile"];
+        get: operations["listChannelViewers"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/files/{id}/download": {
+    "/channels/{id}/activity": {
         parameters: {
             query?: never;
             header?: never;
@@ -1176,10 +1328,16 @@ export interface paths {
             cookie?: never;
         };
         /**
-         * Download a file
-         * @description Download a file by ID. Supports both authenticated requests (Bearer token) and signed URLs (with expires, uid, and sig query parameters) for sharing files externally.
+         * Get channel activity
+         * @description Daily message counts for the channel over the last `days` days, ending today (UTC), with how many people posted during the period. Counts include thread replies but not system or deleted messages. Used for the activity graph in channel details, and to find quiet channels to archive.
+         *
+         *     Errors:
+         *     - 400: days is out of range.
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot read the channel.
+         *     - 404: Channel not found.
          */
-        get: operations["downloadFile"];
+        get: operations["getChannelActivity"];
         put?: never;
         post?: never;
         delete?: never;
@@ -1188,7 +1346,7 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
-    "/files/{id}/sign-url": {
+    "/channels/{id}/members/add": {
         parameters: {
             query?: never;
             header?: never;
@@ -1198,17 +1356,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Get a signed download URL for a file
-         * @description Generate a time-limited signed URL for downloading a file without authentication. Useful for embedding file links in emails or sharing with external users.
+         * Add member to channel
+         * @description Add a workspace member to a channel. For public channels, any member can add others. For private channels, only channel members can add others.
          */
-        post: operations["signFileUrl"];
+        post: operations["addChannelMember"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/files/sign-urls": {
+    "/channels/{id}/members/bulk": {
         parameters: {
             query?: never;
             header?: never;
@@ -1218,17 +1376,23 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Get signed download URLs for multiple files
-         * @description Generate signed download URLs for multiple files in a single request. More efficient than calling the single-file endpoint repeatedly.
+         * Add members to channel in bulk
+         * @description Add up to 500 workspace members to a channel in one transaction, by user ID, by user group, or both. Users who are already members or who do not belong to the workspace are skipped. The same permissions as adding a single member apply. One system message summarizes the change and members of the channel receive a single `channel.members_updated` event.
+         *
+         *     Errors:
+         *     - 400: No users given, more than 500 users, or the channel is a DM or archived.
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot add members to the channel.
+         *     - 404: Channel or user group not found.
          */
-        post: operations["signFileUrls"];
+        post: operations["bulkAddChannelMembers"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/files/{id}/delete": {
+    "/channels/{id}/members/list": {
         parameters: {
             query?: never;
             header?: never;
@@ -1238,17 +1402,22 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Delete a file
-         * @description Delete a file from the server. Only the file uploader or a workspace admin/owner can delete files.
+         * List channel members
+         * @description List the members of a channel with their roles, ordered by display name. Results are paged: pass the previous response's `next_cursor` as `cursor` while `has_more` is true. `q` filters by display name or email and `role` by channel role. Send the previous response's `ETag` in `If-None-Match` to get `304 Not Modified` when the page is unchanged.
+         *
+         *     Errors:
+         *     - 400: Unknown cursor.
+         *     - 401: Not authenticated.
+         *     - 404: Channel not found, or a private channel the caller is not in.
          */
-        post: operations["deleteFile"];
+        post: operations["listChannelMembers"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/emojis/upload": {
+    "/channels/{id}/join": {
         parameters: {
             query?: never;
             header?: never;
@@ -1258,17 +1427,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Upload a custom emoji
-         * @description Upload a custom emoji image for the workspace. Accepts PNG, GIF, or WebP. The emoji name must be unique within the workspace and use only lowercase letters, numbers, and underscores.
+         * Join a channel
+         * @description Join a public channel. Private channels cannot be joined directly — a current member must add you using the add member endpoint.
          */
-        post: operations["uploadCustomEmoji"];
+        post: operations["joinChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/emojis/list": {
+    "/channels/{id}/leave": {
         parameters: {
             query?: never;
             header?: never;
@@ -1278,17 +1447,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List custom emojis for a workspace
-         * @description List all custom emojis available in the workspace. Returns emoji names and their image URLs.
+         * Leave a channel
+         * @description Leave a channel. You will no longer receive messages or notifications from this channel. The channel owner cannot leave without first transferring ownership.
          */
-        post: operations["listCustomEmojis"];
+        post: operations["leaveChannel"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/emojis/{id}/delete": {
+    "/channels/{id}/star": {
         parameters: {
             query?: never;
             header?: never;
@@ -1298,17 +1467,21 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Delete a custom emoji
-         * @description Delete a custom emoji from the workspace. Only the emoji uploader or a workspace admin/owner can delete custom emojis.
+         * Star a channel
+         * @description Star a channel to pin it to the top of your sidebar. Starred channels appear in a separate section for quick access.
          */
-        post: operations["deleteCustomEmoji"];
-        delete?: never;
+        post: operations["starChannel"];
+        /**
+         * Unstar a channel
+         * @description Remove a channel from your starred list.
+         */
+        delete: operations["unstarChannel"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/channels/{id}/messages/schedule": {
+    "/channels/{id}/mark-read": {
         parameters: {
             query?: never;
             header?: never;
@@ -1318,57 +1491,70 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Schedule a message for future delivery
-         * @description Schedule a message for future delivery to a channel. The message will be automatically sent at the specified time. Scheduled messages can be edited or cancelled before delivery.
+         * Mark channel as read
+         * @description Mark a channel as read up to a specific message, or up to the latest message if no message ID is provided. Updates the unread count and clears notification badges for this channel.
          */
-        post: operations["scheduleMessage"];
+        post: operations["markChannelRead"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/scheduled-messages": {
+    "/channels/{id}/read-state": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * List user's scheduled messages in a workspace
-         * @description List all pending scheduled messages created by the current user in a workspace.
+         * Get channel read state
+         * @description The caller's read position in the channel, for placing the "New messages" divider after reconnecting: the last message they read, the first top-level message after it, and how many unread top-level messages there are. Thread replies and deleted messages are not counted.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a member of the channel.
+         *     - 404: Channel not found.
          */
-        post: operations["listScheduledMessages"];
+        get: operations["getChannelReadState"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/scheduled-messages/{id}": {
+    "/channels/{id}/notifications": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
+        /**
+         * Get channel notification preferences
+         * @description Get the current user's notification preferences for a specific channel, such as whether to receive notifications for all messages, mentions only, or nothing. Without preferences of its own, a channel follows the user's workspace settings, then their global settings; `inherited` is true in that case.
+         */
+        get: operations["getChannelNotifications"];
         put?: never;
         /**
-         * Get a scheduled message
-         * @description Retrieve a single scheduled message by ID, including its content, target channel, and scheduled delivery time.
+         * Update channel notification preferences
+         * @description Set notification preferences for a specific channel. Overrides the workspace-level notification defaults for this channel only.
          */
-        post: operations["getScheduledMessage"];
-        delete?: never;
+        post: operations["updateChannelNotifications"];
+        /**
+         * Reset channel notification preferences
+         * @description Remove the current user's notification preferences for a channel so it follows their workspace and global settings again. Returns the preferences now in effect.
+         */
+        delete: operations["resetChannelNotifications"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/scheduled-messages/{id}/update": {
+    "/channels/{id}/snooze": {
         parameters: {
             query?: never;
             header?: never;
@@ -1378,17 +1564,21 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Update a scheduled message
-         * @description Update the content or delivery time of a pending scheduled message. Cannot update messages that have already been sent.
+         * Snooze channel notifications
+         * @description Silence notifications from a channel for a while, whatever its notification preferences. Until the snooze ends no push, email or real-time notifications are sent for the channel and its notification_count reads as 0; unread counts are not affected. Snoozing again replaces the end time. Channel payloads include snoozed_until while a snooze is running.
          */
-        post: operations["updateScheduledMessage"];
-        delete?: never;
+        post: operations["snoozeChannel"];
+        /**
+         * End a channel snooze
+         * @description End the current user's snooze of a channel before it runs out. A workspace snooze still applies.
+         */
+        delete: operations["unsnoozeChannel"];
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/scheduled-messages/{id}/delete": {
+    "/workspaces/{wid}/channels/mark-all-read": {
         parameters: {
             query?: never;
             header?: never;
@@ -1398,48 +1588,81 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Delete a scheduled message
-         * @description Cancel and delete a pending scheduled message. Cannot delete messages that have already been sent.
+         * Mark all channels as read
+         * @description Mark all channels in the workspace as read. Clears all unread counts and notification badges across every channel the user is a member of.
+         *     The user's other clients receive a single `workspace.read` event listing the channels whose read position moved.
          */
-        post: operations["deleteScheduledMessage"];
+        post: operations["markAllChannelsRead"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/scheduled-messages/{id}/send-now": {
+    "/workspaces/{wid}/unreads": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
+        /**
+         * List all unread messages across channels (query parameters)
+         * @description Same as `POST /workspaces/{wid}/unreads`, with the pagination options in the query string.
+         */
+        get: operations["getAllUnreads"];
         put?: never;
         /**
-         * Send a scheduled message immediately
-         * @description Immediately send a scheduled message instead of waiting for its scheduled delivery time. The message is sent and the scheduled entry is removed.
+         * List all unread messages across channels
+         * @description Fetch unread messages across all channels in the workspace. Returns messages grouped by channel with cursor-based pagination. Useful for building an "All Unreads" view.
          */
-        post: operations["sendScheduledMessageNow"];
+        post: operations["listAllUnreads"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/users/{id}": {
+    "/workspaces/{wid}/messages/search": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
+        get?: never;
+        put?: never;
         /**
-         * Get user profile
-         * @description Retrieve a user's public profile by ID, including display name, avatar, and status.
+         * Search messages in workspace
+         * @description Full-text search across messages in the workspace. Supports filtering by channel, user, and date range. Results include surrounding context and are ranked by relevance.
+         *
+         *     The query may also contain operators: `from:@name` (or `from:me`), `in:#channel`, `has:link`, `has:attachment`, `is:thread`, `before:YYYY-MM-DD` and `after:YYYY-MM-DD`. Operator values containing spaces can be quoted (`from:@"Jane Doe"`), and other double-quoted text is matched as an exact phrase. A query made up only of operators returns matching messages newest first. Unrecognised operators are searched as plain text.
+         *
+         *     Results are paged by cursor: pass a page's `next_cursor` as `cursor` to fetch the next one. Pages stay consistent as new messages arrive, and only the first page counts matches.
          */
-        get: operations["getUser"];
+        post: operations["searchMessages"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/quick-switch": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Quick switcher search
+         * @description Search channels, DMs and workspace members by name in a single call, for a Cmd+K style switcher. Exact and prefix matches rank above matches later in the name, channels and DMs with recent messages are boosted, and joined channels rank above equally good matches the caller has not joined. Members the caller already has a 1:1 DM with are returned as the DM. An empty query returns the most recently active destinations.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Not a member of the workspace.
+         */
+        get: operations["quickSwitch"];
         put?: never;
         post?: never;
         delete?: never;
@@ -1448,7 +1671,7 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
-    "/users/me/profile": {
+    "/workspaces/{wid}/threads": {
         parameters: {
             query?: never;
             header?: never;
@@ -1458,17 +1681,37 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Update own profile
-         * @description Update the current user's profile fields such as display name and status. Changes are reflected across all workspaces.
+         * List threads user is subscribed to
+         * @description List threads that the current user is subscribed to in the workspace. Includes the root message and latest replies with unread counts. Supports cursor-based pagination.
          */
-        post: operations["updateProfile"];
+        post: operations["listUserThreads"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/users/me/avatar": {
+    "/workspaces/{wid}/activity": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List activity
+         * @description List events addressed to the current user in the workspace, newest first, with cursor-based pagination: mentions, replies to threads they started, reactions to their messages, and being added to channels. Activity for deleted messages, for channels the user can no longer see, and from blocked users is left out. New activity is also delivered as an `activity.new` event.
+         */
+        get: operations["listActivity"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/activity/mark-read": {
         parameters: {
             query?: never;
             header?: never;
@@ -1478,21 +1721,59 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Upload avatar image
-         * @description Upload a profile avatar image. Accepts JPEG, PNG, GIF, or WebP. The image is resized and stored on the server, replacing any existing avatar.
+         * Mark activity as read
+         * @description Mark the given activity items as read, or all of the current user's activity in the workspace when `activity_ids` is omitted.
          */
-        post: operations["uploadAvatar"];
+        post: operations["markActivityRead"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
         /**
-         * Remove avatar
-         * @description Remove the current user's avatar, reverting to the default Gravatar-based avatar.
+         * Get a single message
+         * @description Retrieve a single message by ID, including its author, reactions, thread metadata, and file attachments.
          */
-        delete: operations["deleteAvatar"];
+        get: operations["getMessage"];
+        put?: never;
+        post?: never;
+        delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/icon": {
+    "/messages/{id}/permalink": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get a message permalink
+         * @description Resolve a message to its canonical URL and the context a client needs to open it: the workspace, the channel, the thread parent for replies, and a cursor for listing channel messages with `direction: around` so the message (or, for a reply, its thread parent) lands in the middle of the page.
+         *
+         *     Returns 404 when the message does not exist or the caller cannot see it, so the existence of private channels is not revealed.
+         */
+        get: operations["getMessagePermalink"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/messages/send": {
         parameters: {
             query?: never;
             header?: never;
@@ -1502,21 +1783,47 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Upload workspace icon
-         * @description Upload an image to use as the workspace icon. Accepts JPEG, PNG, GIF, or WebP. The image is resized to fit. Requires admin or owner role.
+         * Send a message
+         * @description Send a new message to a channel. Supports plain text content, file attachments (by referencing previously uploaded file IDs), and threading (by setting a parent message ID). The sender must be a member of the channel.
+         *
+         *     Content is checked against the workspace's blocked words: a match on a `reject` entry returns 400 with code `CONTENT_BLOCKED`, and matches on `mask` entries are replaced with asterisks before the message is stored.
+         *
+         *     In channels with slow mode on, a member who posted less than slow_mode_seconds ago gets 429 with code `SLOW_MODE` and the seconds left to wait in retry_after. Channel and workspace admins are exempt.
+         *
+         *     Content longer than the server's message length limit (`limits.max_message_length` in server info) returns 400 with code `MESSAGE_TOO_LONG` and the limit in `limit`.
+         *
+         *     To make retries safe, pass a unique key per message in the `Idempotency-Key` header or `client_msg_id`. Sending again with a key already used in the last 24 hours returns the original message instead of posting a new one, or 409 if that message was sent to a different channel or has since been deleted.
+         *
+         *     On slow networks, set `async` to get a 202 with the message ID as soon as the message is stored. Everything that can reject the message is still checked first. Mentions, attachments, the `message.new` broadcast and notifications follow in the background, and then the sender's clients get a `message.finalized` event carrying the finished message.
          */
-        post: operations["uploadWorkspaceIcon"];
+        post: operations["sendMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/messages": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
         /**
-         * Remove workspace icon
-         * @description Remove the workspace icon, reverting to the default. Requires admin or owner role.
+         * List messages in channel (query parameters)
+         * @description Same as `POST /channels/{id}/messages/list`, with the pagination options in the query string so responses can be cached and links shared, e.g. `GET /channels/{id}/messages?cursor=...&limit=50&direction=before`. Pass `fields` to trim each message to the listed properties.
          */
-        delete: operations["deleteWorkspaceIcon"];
+        get: operations["getChannelMessages"];
+        put?: never;
+        post?: never;
+        delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/bans/create": {
+    "/channels/{id}/messages/list": {
         parameters: {
             query?: never;
             header?: never;
@@ -1526,48 +1833,37 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Ban a user from workspace
-         * @description Ban a user from the workspace. Only admins and owners can ban. Cannot ban users with equal or higher role rank (admins cannot ban other admins or the owner). Self-banning is prohibited. Specify duration_hours for a temporary ban (1-8760 hours), or omit for a permanent ban. Set hide_messages to true to hide the banned user's messages from all queries.
-         *
-         *     Errors:
-         *     - 400: Self-ban attempted, or target user is not a workspace member.
-         *     - 403: Caller lacks admin/owner role, or target has equal or higher role rank.
-         *     - 404: Workspace not found.
-         *     - 409: User is already banned in this workspace.
+         * List messages in channel
+         * @description List messages in a channel with cursor-based pagination. Returns messages in reverse chronological order by default. Supports fetching around a specific message for scroll-to-message functionality.
          */
-        post: operations["banUser"];
+        post: operations["listMessages"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/bans/remove": {
+    "/channels/{id}/messages/archived": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
-        get?: never;
-        put?: never;
         /**
-         * Unban a user from workspace
-         * @description Remove an active ban for a user in the workspace, restoring their access. Only admins and owners can unban users. The user's membership is not automatically restored; they must rejoin or be re-invited.
-         *
-         *     Errors:
-         *     - 401: Not authenticated.
-         *     - 403: Caller lacks admin/owner role.
-         *     - 404: Workspace not found or no active ban exists for the specified user.
+         * List archived messages in channel
+         * @description When the server has cold storage enabled (`messages.cold_storage_days`), threads with no activity for that long are moved out of channel history into an archive. Archived messages are read-only, don't count towards unreads and aren't returned by the other list endpoints; search still finds them and marks them `archived`. This endpoint pages through them newest first like `GET /channels/{id}/messages`, or through the replies of one archived thread with `thread_id`. Pass `at` to jump to a date: the page is loaded around the first archived message created at or after it.
          */
-        post: operations["unbanUser"];
+        get: operations["listArchivedMessages"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/bans/list": {
+    "/channels/{id}/messages/by-author": {
         parameters: {
             query?: never;
             header?: never;
@@ -1577,21 +1873,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List active bans in workspace
-         * @description List all currently active bans in the workspace with cursor-based pagination. Only admins and owners can view the ban list. Each ban includes the banned user's display name and email. Expired temporary bans are excluded from results.
-         *
-         *     Errors:
-         *     - 401: Not authenticated.
-         *     - 403: Caller lacks admin/owner role.
+         * List a user's messages in channel
+         * @description List every message a single user has posted in a channel, including thread replies, newest first with cursor-based pagination. Optionally restrict to a date range. Deleted messages are not included. Access rules are the same as for listing the channel's messages.
          */
-        post: operations["listBans"];
+        post: operations["listMessagesByAuthor"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/blocks/create": {
+    "/messages/{id}/update": {
         parameters: {
             query?: never;
             header?: never;
@@ -1601,23 +1893,19 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Block a user in workspace
-         * @description Block a user within the workspace scope. Blocking is workspace-scoped, meaning the block only applies within this workspace. Blocked users' messages are hidden from the blocker in message queries. Any authenticated workspace member can block another member. Self-blocking is not allowed.
+         * Update a message
+         * @description Edit the content of a previously sent message. Only the message author can edit their own messages. An edit indicator is shown on the message after updating.
          *
-         *     Errors:
-         *     - 400: Self-block attempted, or target user is not a workspace member.
-         *     - 401: Not authenticated.
-         *     - 403: Caller is not a member of the workspace.
-         *     - 404: Workspace or target user not found.
+         *     The new content is checked against the workspace's blocked words in the same way as when sending.
          */
-        post: operations["blockUser"];
+        post: operations["updateMessage"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/blocks/remove": {
+    "/messages/{id}/delete": {
         parameters: {
             query?: never;
             header?: never;
@@ -1627,45 +1915,37 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Unblock a user in workspace
-         * @description Remove a block on a user within the workspace scope. Only the user who created the block can remove it. After unblocking, the previously blocked user's messages will be visible again in message queries.
-         *
-         *     Errors:
-         *     - 401: Not authenticated.
-         *     - 403: Caller is not a member of the workspace.
+         * Delete a message
+         * @description Delete a message. Authors can delete their own messages. Channel admins and workspace admins/owners can delete any message in channels they have access to. Authors can restore a message they deleted within the undelete window.
          */
-        post: operations["unblockUser"];
+        post: operations["deleteMessage"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/blocks/list": {
+    "/messages/{id}/restore": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
+        get?: never;
+        put?: never;
         /**
-         * List blocked users in workspace
-         * @description List all users the caller has blocked within this workspace. Returns only the caller's own blocks (users cannot see other members' block lists). Each entry includes the blocked user's display name and email.
-         *
-         *     Errors:
-         *     - 401: Not authenticated.
-         *     - 403: Caller is not a member of the workspace.
+         * Restore a deleted message
+         * @description Undo the deletion of a message. Only the author can restore a message, only if they deleted it themselves, and only within the server's undelete window (24 hours by default). After the window passes the original content and attachments are permanently removed.
          */
-        get: operations["listBlocks"];
-        put?: never;
-        post?: never;
+        post: operations["restoreMessage"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/moderation-log/list": {
+    "/messages/{id}/link-preview/delete": {
         parameters: {
             query?: never;
             header?: never;
@@ -1675,41 +1955,62 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * List moderation audit log
-         * @description List the moderation audit log for the workspace with cursor-based pagination. Records all moderation actions including bans, unbans, and role changes. Only admins and owners can view the audit log. Each entry includes the acting user's display name and email for accountability.
+         * Delete a message's link preview
+         * @description Remove the link preview (unfurl) from a message. Only the message author can remove link previews from their own messages.
+         */
+        post: operations["deleteLinkPreview"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/reactions": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List who reacted
+         * @description List the users who reacted to a message, oldest reaction first, with cursor-based pagination. Pass `emoji` to list one reaction's users, e.g. for a hover card. `counts` summarizes every emoji on the message and whether the caller used it. Users the caller blocked and users banned with their messages hidden are left out of both.
          *
          *     Errors:
          *     - 401: Not authenticated.
-         *     - 403: Caller lacks admin/owner role.
+         *     - 403: Caller cannot read the channel.
+         *     - 404: Message not found.
          */
-        post: operations["listModerationLog"];
+        get: operations["listMessageReactions"];
+        put?: never;
+        post?: never;
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/events": {
+    "/messages/{id}/reactions/add": {
         parameters: {
             query?: never;
             header?: never;
             path?: never;
             cookie?: never;
         };
+        get?: never;
+        put?: never;
         /**
-         * SSE event stream
-         * @description Open a Server-Sent Events stream for real-time updates in a workspace. Events include new messages, reactions, typing indicators, presence changes, and channel updates. Supports reconnection with Last-Event-ID header to catch up on missed events.
+         * Add reaction to message
+         * @description Add an emoji reaction to a message. Each user can only add each unique emoji once per message. Supports both standard Unicode emoji and custom workspace emoji.
          */
-        get: operations["events"];
-        put?: never;
-        post?: never;
+        post: operations["addReaction"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/typing/start": {
+    "/messages/{id}/reactions/remove": {
         parameters: {
             query?: never;
             header?: never;
@@ -1719,17 +2020,17 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Start typing indicator
-         * @description Broadcast a typing indicator to other members of a channel. The indicator automatically expires after a few seconds if not refreshed.
+         * Remove reaction from message
+         * @description Remove your emoji reaction from a message. You can only remove reactions that you previously added.
          */
-        post: operations["startTyping"];
+        post: operations["removeReaction"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-    "/workspaces/{wid}/typing/stop": {
+    "/messages/{id}/reactions/batch": {
         parameters: {
             query?: never;
             header?: never;
@@ -1739,1345 +2040,9308 @@ export interface paths {
         get?: never;
         put?: never;
         /**
-         * Stop typing indicator
-         * @description Clear the typing indicator for a channel. Called when the user stops typing or sends their message.
+         * Apply a batch of reaction changes
+         * @description Add and remove several of your own reactions on a message in one request. Operations are applied in order inside a single transaction, so either all of them take effect or none do. Adding a reaction you already have, or removing one you don't, is a no-op. The response and the `reaction.batch` event describe the net change.
          */
-        post: operations["stopTyping"];
+        post: operations["batchReactions"];
         delete?: never;
         options?: never;
         head?: never;
         patch?: never;
         trace?: never;
     };
-}
-export type webhooks = Record<string, never>;
-export interface components {
-    schemas: {
-        UserProfile: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example Alice Chen */
-            display_name: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            gravatar_url?: string;
-            /** @example In a meeting */
-            status: string;
-            /** Format: date-time */
-            created_at: string;
+    "/messages/{id}/mark-unread": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        UpdateProfileInput: {
-            /** @example Alice Chen */
-            display_name?: string;
+        get?: never;
+        put?: never;
+        /**
+         * Mark message as unread
+         * @description Mark a message as unread, setting the channel's read position to just before this message. The channel will appear as having unread messages starting from this point.
+         */
+        post: operations["markMessageUnread"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/thread/mark-read": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        AvatarUploadResponse: {
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url: string;
+        get?: never;
+        put?: never;
+        /**
+         * Mark thread as read
+         * @description Mark a thread as read up to a specific reply, or up to the latest reply if no reply ID is provided. Updates the thread's unread count and sends a `thread.read` event to the user's other sessions.
+         */
+        post: operations["markThreadRead"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/thread": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        WorkspaceIconUploadResponse: {
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            icon_url: string;
+        /**
+         * List thread replies (query parameters)
+         * @description Same as `POST /messages/{id}/thread/list`, with the pagination options in the query string. Pass `fields` to trim each message to the listed properties.
+         */
+        get: operations["getThreadReplies"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/thread/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        User: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /**
-             * Format: email
-             * @example alice@example.com
-             */
-            email: string;
-            /** Format: date-time */
-            email_verified_at?: string;
-            /** @example Alice Chen */
-            display_name: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            gravatar_url?: string;
-            /** @example In a meeting */
-            status: string;
-            /** Format: date-time */
-            created_at: string;
-            /** Format: date-time */
-            updated_at: string;
+        get?: never;
+        put?: never;
+        /**
+         * List thread replies
+         * @description List replies in a message thread with cursor-based pagination. Each page holds replies in chronological order.
+         *
+         *     Without a cursor the newest page is returned; pass `direction: after` without a cursor to start from the first reply instead. With a cursor, `before` pages toward older replies, `after` toward newer ones, and `around` returns the cursor reply with replies on both sides of it. A cursor without a direction pages `after`, as earlier versions did. `next_cursor` continues in the same direction, and `has_older` and `has_newer` say whether there are replies beyond the page on either side.
+         */
+        post: operations["listThread"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/thread/participants": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
         /**
-         * @description Controls which workspace roles can perform an action
-         * @enum {string}
+         * List thread participants
+         * @description List everyone who has replied to a thread, ordered by their first reply, with cursor-based pagination. Messages and threads only carry a short preview of participants; use this to show the full list.
          */
-        PermissionLevel: "everyone" | "members" | "admins";
-        WorkspaceSettings: {
-            /**
-             * @description Whether to show system messages when users join or leave channels
-             * @default true
-             */
-            show_join_leave_messages: boolean;
-            /** @default members */
-            who_can_create_channels: components["schemas"]["PermissionLevel"];
-            /** @default admins */
-            who_can_create_invites: components["schemas"]["PermissionLevel"];
-            /** @default members */
-            who_can_pin_messages: components["schemas"]["PermissionLevel"];
-            /** @default members */
-            who_can_manage_custom_emoji: components["schemas"]["PermissionLevel"];
+        get: operations["listThreadParticipants"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/subscription": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        Workspace: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example general */
-            name: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            icon_url?: string;
-            /** @description JSON string containing workspace settings (for backward compatibility) */
-            settings: string;
-            parsed_settings?: components["schemas"]["WorkspaceSettings"];
-            /** Format: date-time */
-            created_at: string;
-            /** Format: date-time */
-            updated_at: string;
+        /**
+         * Get thread subscription status
+         * @description Check whether the current user is subscribed to a thread. Subscribed users receive notifications for new replies.
+         */
+        get: operations["getThreadSubscription"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/subscribe": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        WorkspaceSummary: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example general */
-            name: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            icon_url?: string;
-            role: components["schemas"]["WorkspaceRole"];
-            /**
-             * @description User's custom sort order for this workspace
-             * @example 1
-             */
-            sort_order?: number;
-            /** @description Present when the user is banned from this workspace */
-            ban?: {
-                /** @example Repeated spam */
-                reason?: string;
-                /** Format: date-time */
-                expires_at?: string;
-            };
+        get?: never;
+        put?: never;
+        /**
+         * Subscribe to thread
+         * @description Subscribe to a thread to receive notifications for new replies. Users are automatically subscribed when they reply to a thread.
+         */
+        post: operations["subscribeToThread"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/unsubscribe": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        WorkspaceNotificationSummary: {
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example 12 */
-            unread_count: number;
-            /** @example 3 */
-            notification_count: number;
+        get?: never;
+        put?: never;
+        /**
+         * Unsubscribe from thread
+         * @description Unsubscribe from a thread to stop receiving notifications for new replies.
+         */
+        post: operations["unsubscribeFromThread"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/pin": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
         };
-        WorkspaceMembership: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+        get?: never;
+        put?: never;
+        /**
+         * Pin a message
+         * @description Pin a message to its channel. Any workspace member with access to the channel can pin messages. A channel can have at most 50 pinned messages. Already-pinned messages cannot be pinned again. The pinned message is returned with updated metadata including the pinned_by user and pinned_at timestamp.
+         *
+         *     Errors:
+         *     - 400: Message is already pinned, or the channel has reached the 50-pin limit.
+         *     - 401: Not authenticated.
+         *     - 403: Caller does not have access to the channel.
+         *     - 404: Message not found.
+         */
+        post: operations["pinMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/ack": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Acknowledge a direct message as read
+         * @description Record a read receipt for a message in a DM or group DM and notify the channel with a `message.read` event. Acknowledging your own message, or a message you have already acknowledged, is a no-op.
+         *
+         *     Errors:
+         *     - 400: The message is not in a direct message channel.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a participant, or read receipts are disabled in the workspace.
+         *     - 404: Message not found.
+         */
+        post: operations["ackMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/messages/{id}/unpin": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Unpin a message
+         * @description Unpin a previously pinned message from its channel. Any workspace member with access to the channel can unpin messages (not restricted to the user who originally pinned it). The message is returned with its pin metadata cleared.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller does not have access to the channel.
+         *     - 404: Message not found or message is not currently pinned.
+         */
+        post: operations["unpinMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/pins/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List pinned messages in channel
+         * @description List all pinned messages in a channel with cursor-based pagination. Returns messages in reverse chronological order of when they were pinned. Only workspace members with access to the channel can list its pins.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller does not have access to the channel.
+         *     - 404: Channel not found.
+         */
+        post: operations["listPinnedMessages"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/files": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List channel files
+         * @description List the files posted in a channel for a Files tab, with who uploaded each one and the message it was posted in. Files of deleted messages are left out, as are files in private channels posted before the history the caller can see (`history_visibility`). Results are paged with `limit` and `offset`; `total_count` is the number of files matching `type`.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot read the channel.
+         *     - 404: Channel not found.
+         */
+        get: operations["listChannelFiles"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/files": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List workspace files
+         * @description List the files posted in the workspace's channels that the caller can read (channels they belong to, and public channels), newest first by default. In private channels, files posted before the history the caller can see are left out. Paged like the channel file list.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Not a member of the workspace.
+         */
+        get: operations["listWorkspaceFiles"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/files/upload": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Upload a file
+         * @description Upload a file to a channel. The file is stored on the server and a file object is returned with an ID that can be referenced when sending a message. Maximum file size and allowed types are configured server-side. Returns 403 with code `STORAGE_QUOTA_EXCEEDED` if the file would take the workspace over its storage quota.
+         */
+        post: operations["uploadFile"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/uploads": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Start a resumable upload
+         * @description Create an upload session for a file that will be sent in chunks. Append chunks with `PATCH /uploads/{id}` and finish with `POST /uploads/{id}/complete`, which returns the same file object as a single-request upload. Sessions that see no activity before `expires_at` are discarded. Returns 403 with code `STORAGE_QUOTA_EXCEEDED` if the file would take the workspace over its storage quota.
+         */
+        post: operations["createUpload"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/uploads/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Upload session ID */
+                id: string;
+            };
+            cookie?: never;
+        };
+        /**
+         * Get upload progress
+         * @description Return an upload session. Clients resuming an interrupted upload use `offset` to find where to continue.
+         */
+        get: operations["getUpload"];
+        put?: never;
+        post?: never;
+        /**
+         * Cancel an upload
+         * @description Discard an upload session and any chunks received so far.
+         */
+        delete: operations["cancelUpload"];
+        options?: never;
+        head?: never;
+        /**
+         * Append a chunk
+         * @description Append the request body to the upload. `offset` must equal the session's current offset; a mismatch (for example a chunk retried after it was already stored) returns 409 and the client should re-read the offset with `GET /uploads/{id}`.
+         */
+        patch: operations["appendUpload"];
+        trace?: never;
+    };
+    "/uploads/{id}/complete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Finish a resumable upload
+         * @description Assemble the received chunks into a file. Every byte declared when the session was created must have been received. The session is removed and the file can be referenced when sending a message. Returns 403 with code `STORAGE_QUOTA_EXCEEDED` if the file would take the workspace over its storage quota.
+         */
+        post: operations["completeUpload"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/files/{id}/download": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Download a file
+         * @description Download a file by ID. Supports both authenticated requests (Bearer token) and signed URLs (with expires, uid, and sig query parameters) for sharing files externally.
+         */
+        get: operations["downloadFile"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/files/{id}/sign-url": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Get a signed download URL for a file
+         * @description Generate a time-limited signed URL for downloading a file without authentication. Useful for embedding file links in emails or sharing with external users.
+         */
+        post: operations["signFileUrl"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/files/sign-urls": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Get signed download URLs for multiple files
+         * @description Generate signed download URLs for multiple files in a single request. More efficient than calling the single-file endpoint repeatedly.
+         */
+        post: operations["signFileUrls"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/files/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post?: never;
+        /**
+         * Delete a file
+         * @description Delete a file, including one already attached to a message. Only the file uploader or a workspace admin/owner can delete files. When the file belongs to a message, a `message.updated` event with the remaining attachments is broadcast to the channel.
+         */
+        delete: operations["removeFile"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/files/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a file
+         * @description Delete a file from the server. Only the file uploader or a workspace admin/owner can delete files. Equivalent to `DELETE /files/{id}`.
+         */
+        post: operations["deleteFile"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/emojis/upload": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Upload a custom emoji
+         * @description Upload a custom emoji image for the workspace. Accepts PNG, GIF, or WebP. The emoji name must be unique within the workspace and use only lowercase letters, numbers, and underscores.
+         */
+        post: operations["uploadCustomEmoji"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/emojis/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List custom emojis for a workspace
+         * @description List all custom emojis available in the workspace. Returns emoji names and their image URLs.
+         */
+        post: operations["listCustomEmojis"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/emojis/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a custom emoji
+         * @description Delete a custom emoji from the workspace. Only the emoji uploader or a workspace admin/owner can delete custom emojis.
+         */
+        post: operations["deleteCustomEmoji"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/profile-fields/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List custom profile fields
+         * @description List the workspace's admin-defined profile fields in display order. Members' values are returned in `profile_fields` on workspace member listings, keyed by field ID.
+         */
+        post: operations["listProfileFields"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/profile-fields/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create a custom profile field
+         * @description Add a custom field to member profiles in the workspace. New fields are placed after existing ones. Select fields need at least one option. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Missing name, unknown type, or a select field without options.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 409: A field with this name already exists.
+         */
+        post: operations["createProfileField"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/profile-fields/{id}/update": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Update a custom profile field
+         * @description Rename, reorder or change the options of a custom profile field. A field's type cannot be changed. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Empty name, or a select field without options.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: Profile field not found.
+         *     - 409: A field with this name already exists.
+         */
+        post: operations["updateProfileField"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/profile-fields/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a custom profile field
+         * @description Delete a custom profile field and every member's value for it. Requires admin or owner role.
+         */
+        post: operations["deleteProfileField"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/user-groups/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List user groups
+         * @description List the workspace's user groups ordered by handle. Mentioning a group by `@handle` (or `<!group:id>` in mrkdwn) notifies each of its members.
+         */
+        post: operations["listUserGroups"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/user-groups/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create a user group
+         * @description Create a user group with an optional initial set of members. Handles use lowercase letters, numbers and dashes, and cannot be `here`, `channel` or `everyone`. Users who are not workspace members are skipped. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Invalid handle, missing name, or too many members.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 409: A group with this handle already exists.
+         */
+        post: operations["createUserGroup"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/user-groups/{id}/update": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Update a user group
+         * @description Change a user group's handle, name or description. An empty description clears it. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Invalid handle or empty name.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: User group not found.
+         *     - 409: A group with this handle already exists.
+         */
+        post: operations["updateUserGroup"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/user-groups/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a user group
+         * @description Delete a user group. Messages that mentioned it keep notifying the members they already reached. Requires admin or owner role.
+         */
+        post: operations["deleteUserGroup"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/blocked-words/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List blocked words
+         * @description List the workspace's content filter entries, oldest first. Requires admin or owner role.
+         */
+        post: operations["listBlockedWords"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/blocked-words/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Block a word or pattern
+         * @description Add a content filter entry. A plain pattern matches the word on its own, ignoring case, so `darn` does not match `darning`. With `is_regex` the pattern is a regular expression (RE2 syntax, case-insensitive) matched anywhere in the message. Messages matching a `reject` entry are refused; matches of a `mask` entry are replaced with asterisks. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Empty, too long or invalid pattern, unknown action, or the workspace already has 500 entries.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 409: The pattern is already blocked.
+         */
+        post: operations["createBlockedWord"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/blocked-words/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Unblock a word or pattern
+         * @description Remove a content filter entry. Messages it already masked are not changed. Requires admin or owner role.
+         */
+        post: operations["deleteBlockedWord"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/user-groups/{id}/members/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List user group members
+         * @description List the members of a user group ordered by display name. Any workspace member can list them.
+         */
+        post: operations["listUserGroupMembers"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/user-groups/{id}/members/add": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Add user group members
+         * @description Add users to a user group. Existing members and users who are not workspace members are skipped. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: No user IDs, or too many.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: User group not found.
+         */
+        post: operations["addUserGroupMembers"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/user-groups/{id}/members/remove": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Remove user group members
+         * @description Remove users from a user group. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: No user IDs, or too many.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: User group not found.
+         */
+        post: operations["removeUserGroupMembers"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/channel-templates/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List channel templates
+         * @description List the workspace's channel templates ordered by name. Requires admin or owner role.
+         */
+        post: operations["listChannelTemplates"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/channel-templates/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create a channel template
+         * @description Define a named set of channels that can be created in one step with `/workspaces/{wid}/apply-template`, or when creating a new workspace. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Missing name, no channels, too many channels, or an invalid or duplicate channel name, type or member role.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 409: A template with this name already exists.
+         */
+        post: operations["createChannelTemplate"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channel-templates/{id}/update": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Update a channel template
+         * @description Change a channel template's name, description or channels. The channel list, when given, replaces the current one. An empty description clears it. Channels already created from the template are not affected. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 400: Empty name, or an invalid channel list.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a workspace admin or owner.
+         *     - 404: Channel template not found.
+         *     - 409: A template with this name already exists.
+         */
+        post: operations["updateChannelTemplate"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channel-templates/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a channel template
+         * @description Delete a channel template. Channels already created from it are kept. Requires admin or owner role.
+         */
+        post: operations["deleteChannelTemplate"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/apply-template": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Apply a channel template
+         * @description Create every channel in a template in a single transaction. The caller becomes admin of each channel, and workspace members whose role is listed in a channel's `member_roles` are added to it. Channels whose name is already taken are skipped, so applying a template twice is harmless. A `channel.created` event is sent for each new channel. The template may belong to this workspace or to another workspace the caller administers. Requires admin or owner role.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not an admin or owner of this workspace or of the template's workspace.
+         *     - 404: Channel template not found.
+         */
+        post: operations["applyChannelTemplate"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/incoming-webhooks/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create an incoming webhook
+         * @description Create an incoming webhook that posts messages into a channel. Only admins and owners can manage webhooks. The response contains the webhook URL including its secret token; the token is only returned once, so store it securely. Use the regenerate-token endpoint to issue a new URL.
+         *
+         *     Errors:
+         *     - 400: Name is empty, or the channel is not a public/private channel in this workspace.
+         *     - 403: Caller lacks admin/owner role.
+         */
+        post: operations["createIncomingWebhook"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/incoming-webhooks/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List incoming webhooks
+         * @description List all incoming webhooks in the workspace. Tokens are never included. Only admins and owners can list webhooks.
+         */
+        post: operations["listIncomingWebhooks"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/incoming-webhooks/{id}/update": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Update an incoming webhook
+         * @description Change the name, avatar, or target channel of an incoming webhook. Only admins and owners can update webhooks. Pass an empty avatar_url to clear the avatar.
+         */
+        post: operations["updateIncomingWebhook"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/incoming-webhooks/{id}/regenerate-token": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Regenerate an incoming webhook token
+         * @description Issue a new secret token for the webhook. The previous URL stops working immediately. Only admins and owners can regenerate tokens.
+         */
+        post: operations["regenerateIncomingWebhookToken"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/incoming-webhooks/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete an incoming webhook
+         * @description Delete an incoming webhook. Its URL stops working immediately. Messages previously posted through the webhook are kept. Only admins and owners can delete webhooks.
+         */
+        post: operations["deleteIncomingWebhook"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/hooks/{token}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Post a message through an incoming webhook
+         * @description Post a message to the webhook's channel. Does not require authentication; the token in the URL is the credential. The message is attributed to the webhook's name and avatar, which can be overridden per request with username and icon_url. Requests are rate limited per webhook.
+         *
+         *     Errors:
+         *     - 400: Text is empty or too long, or the target channel is archived.
+         *     - 404: Unknown webhook token.
+         *     - 429: Rate limit exceeded for this webhook.
+         */
+        post: operations["executeIncomingWebhook"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/bots/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create a bot account
+         * @description Create a bot account in the workspace. Bots are workspace members that authenticate with scoped API tokens instead of passwords; they can post messages and be added to channels like any other member. Only admins and owners can manage bots.
+         *
+         *     Errors:
+         *     - 400: Display name is empty or too long.
+         *     - 403: Caller lacks admin/owner role.
+         */
+        post: operations["createBot"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/bots/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List bot accounts
+         * @description List all bot accounts in the workspace. Only admins and owners can list bots.
+         */
+        post: operations["listBots"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/bots/{id}/update": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Update a bot account
+         * @description Change a bot's display name, avatar, or description. Only admins and owners can update bots. Pass an empty avatar_url or description to clear it.
+         */
+        post: operations["updateBot"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/bots/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a bot account
+         * @description Delete a bot account. All of its tokens stop working immediately and it is removed from the workspace and its channels. Messages it posted are kept. Only admins and owners can delete bots.
+         */
+        post: operations["deleteBot"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/bots/{id}/tokens/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create a bot token
+         * @description Issue an API token for a bot. The token authenticates as the bot via the Authorization header and may only call operations covered by its scopes. The token is only returned once, so store it securely. Only admins and owners can issue tokens.
+         *
+         *     Errors:
+         *     - 400: Name is empty, no scopes were given, or a scope is unknown.
+         */
+        post: operations["createBotToken"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/bots/{id}/tokens/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List bot tokens
+         * @description List the tokens issued to a bot. Secrets are never included. Only admins and owners can list tokens.
+         */
+        post: operations["listBotTokens"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/bot-tokens/{id}/revoke": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Revoke a bot token
+         * @description Revoke a bot token. Requests using it are rejected immediately. Only admins and owners can revoke tokens.
+         */
+        post: operations["revokeBotToken"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/announcements/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create an announcement
+         * @description Compose an announcement for the whole workspace or for selected channels. Workspace-wide announcements are posted to the default channel and addressed to every active member; channel announcements are posted to each selected channel and addressed to their members. Without scheduled_for (or with a time in the past) the announcement is delivered immediately, otherwise it is delivered at the scheduled time. Delivered announcements are posted as @channel messages so every recipient is notified. Only admins and owners can create announcements.
+         *
+         *     Errors:
+         *     - 400: Title or content is empty or too long, or a channel is not a public/private channel in this workspace.
+         *     - 403: Caller lacks admin/owner role.
+         */
+        post: operations["createAnnouncement"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/announcements/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List announcements
+         * @description List all announcements in the workspace, newest first, with their acknowledgement counts. Only admins and owners can list announcements.
+         */
+        post: operations["listAnnouncements"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/announcements/{id}/cancel": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Cancel a scheduled announcement
+         * @description Cancel an announcement that has not been delivered yet. Only admins and owners can cancel announcements.
+         *
+         *     Errors:
+         *     - 409: The announcement has already been delivered.
+         */
+        post: operations["cancelAnnouncement"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/announcements/{id}/acknowledge": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Acknowledge an announcement
+         * @description Record that the current user has read and acknowledged an announcement. Acknowledging again is a no-op.
+         *
+         *     Errors:
+         *     - 403: The current user is not a recipient of the announcement.
+         */
+        post: operations["acknowledgeAnnouncement"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/announcements/{id}/report": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Get an announcement acknowledgement report
+         * @description Get every recipient of an announcement and whether and when they acknowledged it. Recipients who have not acknowledged are listed first. Only admins and owners can view reports.
+         */
+        post: operations["getAnnouncementReport"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/messages/schedule": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Schedule a message for future delivery
+         * @description Schedule a message for future delivery to a channel. The message will be automatically sent at the specified time. Scheduled messages can be edited or cancelled before delivery.
+         */
+        post: operations["scheduleMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/scheduled-messages": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List user's scheduled messages in a workspace
+         * @description List all pending scheduled messages created by the current user in a workspace.
+         */
+        post: operations["listScheduledMessages"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/scheduled-messages/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Get a scheduled message
+         * @description Retrieve a single scheduled message by ID, including its content, target channel, and scheduled delivery time.
+         */
+        post: operations["getScheduledMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/scheduled-messages/{id}/update": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Update a scheduled message
+         * @description Update the content or delivery time of a pending scheduled message. Cannot update messages that have already been sent.
+         */
+        post: operations["updateScheduledMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/scheduled-messages/{id}/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete a scheduled message
+         * @description Cancel and delete a pending scheduled message. Cannot delete messages that have already been sent.
+         */
+        post: operations["deleteScheduledMessage"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/scheduled-messages/{id}/send-now": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Send a scheduled message immediately
+         * @description Immediately send a scheduled message instead of waiting for its scheduled delivery time. The message is sent and the scheduled entry is removed.
+         */
+        post: operations["sendScheduledMessageNow"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/polls/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Create a poll
+         * @description Post a poll to a channel as a message of type `poll` whose content is the question. Polls have 2 to 10 options, allow one or several choices, and can be anonymous, in which case results show counts but not who voted. A poll with `closes_at` is closed automatically once that time passes.
+         *
+         *     Errors:
+         *     - 400: Invalid question, options, or close time.
+         *     - 401: Not authenticated.
+         *     - 403: Not allowed to post in the channel.
+         *     - 404: Channel not found.
+         */
+        post: operations["createPoll"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/polls/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get a poll
+         * @description Get a poll with its current results and the caller's own votes.
+         */
+        get: operations["getPoll"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/polls/{id}/vote": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Vote in a poll
+         * @description Replace the caller's votes in a poll with the given options. An empty `option_ids` retracts the caller's vote. Single-choice polls accept at most one option. The updated results are broadcast to the channel as a `poll.updated` event.
+         *
+         *     Errors:
+         *     - 400: Unknown option, or several options in a single-choice poll.
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot read the channel.
+         *     - 404: Poll not found.
+         *     - 409: The poll is closed.
+         */
+        post: operations["votePoll"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/polls/{id}/close": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Close a poll
+         * @description End voting on a poll before its close time. Only the poll's creator or a workspace admin can close it. The final results are broadcast as a `poll.updated` event.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not the creator or an admin.
+         *     - 404: Poll not found.
+         *     - 409: The poll is already closed.
+         */
+        post: operations["closePoll"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/channels/{id}/calls/start": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Start a call
+         * @description Start a voice/video call in a channel with the caller as its first participant. The call is announced with a system message of event type `call` and a `call.started` event to the channel. A channel has at most one call in progress.
+         *
+         *     Errors:
+         *     - 400: The channel is archived.
+         *     - 401: Not authenticated.
+         *     - 403: Not allowed to post in the channel, or calls are turned off for the workspace (code `FEATURE_DISABLED`).
+         *     - 404: Channel not found.
+         *     - 409: A call is already in progress in the channel.
+         */
+        post: operations["startCall"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/calls/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get a call
+         * @description Get a call and who has joined it.
+         */
+        get: operations["getCall"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/calls/{id}/join": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Join a call
+         * @description Join a call in progress, or rejoin after leaving. The channel receives a `call.updated` event; the new participant then exchanges offers with the others through the signaling relay.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller cannot read the channel, or calls are turned off for the workspace (code `FEATURE_DISABLED`).
+         *     - 404: Call not found.
+         *     - 409: The call has ended.
+         */
+        post: operations["joinCall"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/calls/{id}/leave": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Leave a call
+         * @description Leave a call. When the last participant leaves, the call ends: the channel receives a `call.ended` event and the call's system message is updated with its duration and participant count. Participants whose event stream disconnects are removed automatically.
+         */
+        post: operations["leaveCall"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/calls/{id}/signal": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Relay a signaling message
+         * @description Relay a WebRTC offer, answer, or ICE candidate to another participant of the call, who receives it as a `call.signal` event. Signals are not stored. Both the sender and the recipient must be in the call.
+         *
+         *     Errors:
+         *     - 400: The recipient is not in the call, or the payload is too large.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not in the call.
+         *     - 404: Call not found.
+         */
+        post: operations["signalCall"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get user profile
+         * @description Retrieve a user's public profile by ID, including display name, avatar, and status.
+         */
+        get: operations["getUser"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/profile": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get own profile fields
+         * @description Get the current user's structured profile fields: title, pronouns, timezone and phone. When `workspace_id` is given, the response also includes that workspace's custom profile fields and the user's values for them.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Not a member of the given workspace.
+         */
+        get: operations["getMyProfile"];
+        /**
+         * Update own profile fields
+         * @description Update the current user's structured profile fields. Only the fields present in the request are changed, and an empty string clears a field. Title, pronouns and phone apply across all workspaces. Timezone must be an IANA name such as `Europe/Berlin`.
+         *
+         *     Custom field values are per workspace: `custom_fields` requires `workspace_id` and replaces all of the user's values in that workspace. Keys are profile field IDs. Select fields only accept one of their options, and URL fields only accept http(s) URLs.
+         *
+         *     Errors:
+         *     - 400: Unknown timezone, unknown custom field, invalid value, or a value longer than 256 characters.
+         *     - 401: Not authenticated.
+         *     - 403: Not a member of the given workspace.
+         */
+        put: operations["updateMyProfile"];
+        /**
+         * Update own profile
+         * @description Update the current user's profile fields such as display name and status. Changes are reflected across all workspaces.
+         */
+        post: operations["updateProfile"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/avatar": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Upload avatar image
+         * @description Upload a profile avatar image. Accepts JPEG, PNG, GIF, or WebP. The image is resized and stored on the server, replacing any existing avatar.
+         */
+        post: operations["uploadAvatar"];
+        /**
+         * Remove avatar
+         * @description Remove the current user's avatar, reverting to the default Gravatar-based avatar.
+         */
+        delete: operations["deleteAvatar"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/notification-settings": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get global notification settings
+         * @description Get the current user's default notification settings for every workspace. Workspace settings and channel preferences override them. `inherited` is true when the user has not set any and the built-in default (mentions only, email on) applies.
+         */
+        get: operations["getMyNotificationSettings"];
+        /**
+         * Update global notification settings
+         * @description Set the current user's default notification settings for every workspace. Workspaces and channels with settings of their own are unaffected. DMs always notify for every message unless muted individually.
+         */
+        put: operations["updateMyNotificationSettings"];
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/keywords": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List notification keywords
+         * @description List the current user's notification keywords, oldest first. A message containing one of them notifies the user as if it mentioned them.
+         */
+        get: operations["listMyKeywords"];
+        put?: never;
+        /**
+         * Add a notification keyword
+         * @description Add a word or phrase that notifies the current user like a mention. Keywords match whole words in messages sent to channels the user belongs to, ignoring case, and count towards the channel's notification badge.
+         *
+         *     Errors:
+         *     - 400: Empty or longer than 64 characters, or the user already has 50 keywords.
+         *     - 401: Not authenticated.
+         *     - 409: The user already has this keyword.
+         */
+        post: operations["addMyKeyword"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/keywords/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post?: never;
+        /**
+         * Remove a notification keyword
+         * @description Remove one of the current user's notification keywords. Messages already sent keep their mentions.
+         */
+        delete: operations["deleteMyKeyword"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/dms": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List direct messages across workspaces
+         * @description List the caller's direct and group messages in every workspace they belong to, most recently active first, with unread counts, a preview of the latest message and the workspace each belongs to. `GET /events` streams new activity in these conversations.
+         */
+        get: operations["listMyDMs"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/sessions": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List active sessions
+         * @description List the current user's active login sessions, most recently used first, with the device, IP address, and last activity of each. The session making the request is marked `current`. Bot tokens are not sessions and are not listed.
+         */
+        get: operations["listSessions"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/sessions/{id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post?: never;
+        /**
+         * Revoke a session
+         * @description Sign out one of the current user's sessions. Its token stops working immediately. Revoking the current session is the same as logging out.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 404: No such session for this user.
+         */
+        delete: operations["revokeSession"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/sessions/revoke-others": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Revoke all other sessions
+         * @description Sign out every session of the current user except the one making the request, for example after a lost device or a password change.
+         */
+        post: operations["revokeOtherSessions"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/settings": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get client settings
+         * @description Get the current user's client settings: a JSON object clients use to keep preferences such as theme, notification sound, compact mode and locale in sync across web and desktop. The server stores it as is; `updated_at` is omitted until the settings are first saved.
+         */
+        get: operations["getMyClientSettings"];
+        /**
+         * Update client settings
+         * @description Merge `settings` into the current user's client settings as a JSON merge patch (RFC 7396): keys set to null are removed, nested objects are merged key by key, and other values replace what is stored, so clients only send the keys they changed. Pass the `updated_at` last read to only apply the change if nobody else saved since, or `409` is returned. Stored settings are capped at 64 KiB. Every connected client of the user receives a `settings.updated` event.
+         */
+        put: operations["updateMyClientSettings"];
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/users/me/delete": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Delete your account
+         * @description Request deletion of the current user's account. The account is deactivated and every session is signed out immediately. A background job then anonymizes it: messages stay in their conversations but are shown as written by "Deactivated User", and the user's profile, uploaded files, avatar and per-user settings are deleted. An owner must transfer ownership of their workspaces first.
+         *
+         *     Errors:
+         *     - 400: Wrong password, bot account, or the user is the only owner of a workspace.
+         *     - 409: Deletion was already requested.
+         */
+        post: operations["deleteAccount"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/icon": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Upload workspace icon
+         * @description Upload an image to use as the workspace icon. Accepts JPEG, PNG, GIF, or WebP. The image is resized to fit. Requires admin or owner role.
+         */
+        post: operations["uploadWorkspaceIcon"];
+        /**
+         * Remove workspace icon
+         * @description Remove the workspace icon, reverting to the default. Requires admin or owner role.
+         */
+        delete: operations["deleteWorkspaceIcon"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/bans/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Ban a user from workspace
+         * @description Ban a user from the workspace. Only admins and owners can ban. Cannot ban users with equal or higher role rank (admins cannot ban other admins or the owner). Self-banning is prohibited. Specify duration_hours for a temporary ban (1-8760 hours), or omit for a permanent ban. Set hide_messages to true to hide the banned user's messages from all queries.
+         *
+         *     Errors:
+         *     - 400: Self-ban attempted, or target user is not a workspace member.
+         *     - 403: Caller lacks admin/owner role, or target has equal or higher role rank.
+         *     - 404: Workspace not found.
+         *     - 409: User is already banned in this workspace.
+         */
+        post: operations["banUser"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/bans/remove": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Unban a user from workspace
+         * @description Remove an active ban for a user in the workspace, restoring their access. Only admins and owners can unban users. The user's membership is not automatically restored; they must rejoin or be re-invited.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller lacks admin/owner role.
+         *     - 404: Workspace not found or no active ban exists for the specified user.
+         */
+        post: operations["unbanUser"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/bans/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List active bans in workspace
+         * @description List all currently active bans in the workspace with cursor-based pagination. Only admins and owners can view the ban list. Each ban includes the banned user's display name and email. Expired temporary bans are excluded from results.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller lacks admin/owner role.
+         */
+        post: operations["listBans"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/blocks/create": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Block a user in workspace
+         * @description Block a user within the workspace scope. Blocking is workspace-scoped, meaning the block only applies within this workspace. Blocked users' messages are hidden from the blocker in message queries. Any authenticated workspace member can block another member. Self-blocking is not allowed.
+         *
+         *     Errors:
+         *     - 400: Self-block attempted, or target user is not a workspace member.
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a member of the workspace.
+         *     - 404: Workspace or target user not found.
+         */
+        post: operations["blockUser"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/blocks/remove": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Unblock a user in workspace
+         * @description Remove a block on a user within the workspace scope. Only the user who created the block can remove it. After unblocking, the previously blocked user's messages will be visible again in message queries.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a member of the workspace.
+         */
+        post: operations["unblockUser"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/blocks/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List blocked users in workspace
+         * @description List all users the caller has blocked within this workspace. Returns only the caller's own blocks (users cannot see other members' block lists). Each entry includes the blocked user's display name and email.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller is not a member of the workspace.
+         */
+        get: operations["listBlocks"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/moderation-log/list": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * List moderation audit log
+         * @description List the moderation audit log for the workspace with cursor-based pagination. Records all moderation actions including bans, unbans, and role changes. Only admins and owners can view the audit log. Use `GET /workspaces/{wid}/audit-log` to filter by actor, action or date range. Each entry includes the acting user's display name and email for accountability.
+         *
+         *     Errors:
+         *     - 401: Not authenticated.
+         *     - 403: Caller lacks admin/owner role.
+         */
+        post: operations["listModerationLog"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/audit-log": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List audit log
+         * @description List security-relevant admin actions in the workspace, newest first, with cursor-based pagination. Covers moderation (bans, blocks, admin message deletes), membership changes (removals, role changes), invites, channel archiving and retention, workspace settings changes, and bots, webhooks, announcements and custom profile fields. Only admins and owners can view the audit log.
+         *
+         *     Filter by `actor_id`, by `action` (an exact action such as `member.role_changed`, or a prefix ending in a dot such as `member.`), and by a `since`/`until` time range (`until` is exclusive).
+         *
+         *     Errors:
+         *     - 400: `since` is not before `until`.
+         *     - 401: Not authenticated.
+         *     - 403: Caller lacks admin/owner role.
+         */
+        get: operations["listAuditLog"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/events": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * SSE event stream
+         * @description Open a Server-Sent Events stream for real-time updates in a workspace. Events include new messages, reactions, typing indicators, presence changes, and channel updates. Supports reconnection with Last-Event-ID header to catch up on missed events.
+         */
+        get: operations["events"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/events": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Direct message event stream
+         * @description Open a Server-Sent Events stream of the caller's direct and group message activity in all of their workspaces: the events a workspace stream delivers for those conversations, such as new, edited and deleted messages and reactions. The stream starts with a `connected` event and sends heartbeats; it carries no presence and does not replay missed events, so refetch `GET /users/me/dms` after reconnecting.
+         */
+        get: operations["userEvents"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/typing/start": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Start typing indicator
+         * @description Broadcast a typing indicator to other members of a channel. The indicator automatically expires after a few seconds if not refreshed.
+         */
+        post: operations["startTyping"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/workspaces/{wid}/typing/stop": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Stop typing indicator
+         * @description Clear the typing indicator for a channel. Called when the user stops typing or sends their message.
+         */
+        post: operations["stopTyping"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+}
+export type webhooks = Record<string, never>;
+export interface components {
+    schemas: {
+        UserProfile: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example Alice Chen */
+            display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            gravatar_url?: string;
+            /** @example Staff Engineer */
+            title?: string;
+            /** @example she/her */
+            pronouns?: string;
+            /**
+             * @description IANA timezone name
+             * @example Europe/Berlin
+             */
+            timezone?: string;
+            /** @example +1 555 0100 */
+            phone?: string;
+            /** @example In a meeting */
+            status: string;
+            /** @description Whether the user account has been deactivated */
+            is_deactivated?: boolean;
+            /** @description Whether the user is a bot account */
+            is_bot?: boolean;
+            /** Format: date-time */
+            created_at: string;
+        };
+        MemberProfile: {
+            user: components["schemas"]["UserProfile"];
+            role: components["schemas"]["WorkspaceRole"];
+            /** @description The member's custom profile values in the workspace, keyed by profile field ID */
+            custom_fields: {
+                [key: string]: string;
+            };
+            online: boolean;
+            /**
+             * Format: date-time
+             * @description When the member was last connected to the workspace. Absent if they never have been.
+             */
+            last_seen_at?: string;
+            /**
+             * Format: date-time
+             * @description The current time in the member's timezone, with its UTC offset. Absent if they have not set a timezone.
+             */
+            local_time?: string;
+            /**
+             * @description Messages the member has posted in channels and conversations the caller can read
+             * @example 1280
+             */
+            message_count: number;
+            shared_channels: components["schemas"]["SharedChannel"][];
+            /** @example 3 */
+            shared_channel_count: number;
+        };
+        SharedChannel: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            id: string;
+            /** @example general */
+            name: string;
+            type: components["schemas"]["ChannelType"];
+        };
+        UpdateProfileInput: {
+            /** @example Alice Chen */
+            display_name?: string;
+        };
+        MyProfile: {
+            /** @example Staff Engineer */
+            title?: string;
+            /** @example she/her */
+            pronouns?: string;
+            /**
+             * @description IANA timezone name
+             * @example Europe/Berlin
+             */
+            timezone?: string;
+            /** @example +1 555 0100 */
+            phone?: string;
+            /** @description The workspace's custom profile fields, when a workspace was given */
+            fields?: components["schemas"]["ProfileField"][];
+            /** @description The user's custom profile values in the workspace, keyed by profile field ID */
+            custom_fields?: {
+                [key: string]: string;
+            };
+        };
+        UpdateMyProfileInput: {
+            /** @example Staff Engineer */
+            title?: string;
+            /** @example she/her */
+            pronouns?: string;
+            /**
+             * @description IANA timezone name
+             * @example Europe/Berlin
+             */
+            timezone?: string;
+            /** @example +1 555 0100 */
+            phone?: string;
+            /**
+             * @description Workspace the custom field values belong to
+             * @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET
+             */
+            workspace_id?: string;
+            /** @description Replaces the user's custom values in the workspace, keyed by profile field ID */
+            custom_fields?: {
+                [key: string]: string;
+            };
+        };
+        ClientSettings: {
+            /**
+             * @description Settings saved by the user's clients. The server does not interpret them.
+             * @example {
+             *       "theme": "dark",
+             *       "notification_sound": "chime",
+             *       "compact_mode": true,
+             *       "locale": "de-DE"
+             *     }
+             */
+            settings: {
+                [key: string]: unknown;
+            };
+            /**
+             * Format: date-time
+             * @description When the settings were last saved. Doubles as their version for `PUT /users/me/settings`.
+             */
+            updated_at?: string;
+        };
+        UpdateClientSettingsInput: {
+            /**
+             * @description Keys to change, merged into the stored settings. A null value removes the key.
+             * @example {
+             *       "theme": "light",
+             *       "compact_mode": null
+             *     }
+             */
+            settings: {
+                [key: string]: unknown;
+            };
+            /**
+             * Format: date-time
+             * @description The `updated_at` the client last read. When given, the update fails with `409` if the settings were saved since.
+             */
+            updated_at?: string;
+        };
+        /** @enum {string} */
+        ProfileFieldType: "text" | "url" | "select";
+        ProfileField: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example Team */
+            name: string;
+            type: components["schemas"]["ProfileFieldType"];
+            /**
+             * @description Allowed values of a select field
+             * @example [
+             *       "Design",
+             *       "Engineering"
+             *     ]
+             */
+            options?: string[];
+            sort_order: number;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        CreateProfileFieldInput: {
+            /** @example Team */
+            name: string;
+            type?: components["schemas"]["ProfileFieldType"];
+            options?: string[];
+        };
+        UpdateProfileFieldInput: {
+            name?: string;
+            options?: string[];
+            sort_order?: number;
+        };
+        /**
+         * @description Whether matching messages are refused or have the match replaced with asterisks
+         * @default reject
+         * @enum {string}
+         */
+        BlockedWordAction: "reject" | "mask";
+        BlockedWord: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example darn */
+            pattern: string;
+            is_regex: boolean;
+            action: components["schemas"]["BlockedWordAction"];
+            created_by?: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        UserGroup: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /**
+             * @description Mentioned as @handle
+             * @example backend
+             */
+            handle: string;
+            /** @example Backend team */
+            name: string;
+            description?: string;
+            created_by?: string;
+            member_count: number;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        UserGroupMember: {
+            user_id: string;
+            display_name: string;
+            avatar_url?: string;
+            /**
+             * Format: date-time
+             * @description When the user joined the group
+             */
+            created_at: string;
+        };
+        CreateUserGroupInput: {
+            /** @example backend */
+            handle: string;
+            /** @example Backend team */
+            name: string;
+            description?: string;
+            member_ids?: string[];
+        };
+        UpdateUserGroupInput: {
+            handle?: string;
+            name?: string;
+            description?: string;
+        };
+        UserGroupMembersInput: {
+            user_ids: string[];
+        };
+        ChannelTemplate: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example Engineering onboarding */
+            name: string;
+            description?: string;
+            channels: components["schemas"]["ChannelTemplateChannel"][];
+            created_by?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        ChannelTemplateChannel: {
+            /** @example announcements */
+            name: string;
+            type: components["schemas"]["ChannelType"];
+            description?: string;
+            /** @description Workspace roles whose members are added to the channel when the template is applied. The person applying the template always joins as channel admin. */
+            member_roles?: components["schemas"]["WorkspaceRole"][];
+        };
+        CreateChannelTemplateInput: {
+            /** @example Engineering onboarding */
+            name: string;
+            description?: string;
+            channels: components["schemas"]["ChannelTemplateChannel"][];
+        };
+        UpdateChannelTemplateInput: {
+            name?: string;
+            description?: string;
+            channels?: components["schemas"]["ChannelTemplateChannel"][];
+        };
+        ApplyChannelTemplateResult: {
+            /** @description Channels created from the template */
+            channels: components["schemas"]["Channel"][];
+            /** @description Names of template channels that already existed */
+            skipped: string[];
+        };
+        Session: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /**
+             * @description Browser and operating system derived from the user agent
+             * @example Firefox on Windows
+             */
+            device: string;
+            user_agent: string;
+            /** @example 203.0.113.7 */
+            ip_address: string;
+            /** Format: date-time */
+            created_at: string;
+            /**
+             * Format: date-time
+             * @description Last request made with this session, accurate to about a minute
+             */
+            last_seen_at: string;
+            /** Format: date-time */
+            expires_at: string;
+            /** @description Whether this is the session making the request */
+            current: boolean;
+        };
+        AvatarUploadResponse: {
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url: string;
+        };
+        WorkspaceIconUploadResponse: {
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            icon_url: string;
+        };
+        User: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /**
+             * Format: email
+             * @example alice@example.com
+             */
+            email: string;
+            /** Format: date-time */
+            email_verified_at?: string;
+            /** @example Alice Chen */
+            display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            gravatar_url?: string;
+            /** @example Staff Engineer */
+            title?: string;
+            /** @example she/her */
+            pronouns?: string;
+            /**
+             * @description IANA timezone name
+             * @example Europe/Berlin
+             */
+            timezone?: string;
+            /** @example +1 555 0100 */
+            phone?: string;
+            /** @example In a meeting */
+            status: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        /**
+         * @description Controls which workspace roles can perform an action
+         * @enum {string}
+         */
+        PermissionLevel: "everyone" | "members" | "admins";
+        /**
+         * @description Which direct messages are opened automatically when a member joins the workspace:
+         *     none, the member who created the invite, the workspace owner and admins, or the
+         *     earliest members.
+         * @enum {string}
+         */
+        AutoDMPolicy: "none" | "inviter" | "admins" | "earliest_members";
+        WorkspaceSettings: {
+            /**
+             * @description Whether to show system messages when users join or leave channels
+             * @default true
+             */
+            show_join_leave_messages: boolean;
+            /** @default members */
+            who_can_create_channels: components["schemas"]["PermissionLevel"];
+            /** @default admins */
+            who_can_create_invites: components["schemas"]["PermissionLevel"];
+            /** @default members */
+            who_can_pin_messages: components["schemas"]["PermissionLevel"];
+            /** @default members */
+            who_can_manage_custom_emoji: components["schemas"]["PermissionLevel"];
+            /**
+             * @description Who may use @channel, @here and @everyone. `members` excludes guests; `admins` also allows channel admins. Channels can override it.
+             * @default everyone
+             */
+            who_can_mention_channel: components["schemas"]["PermissionLevel"];
+            /**
+             * @description Whether members can see when their direct messages have been read
+             * @default true
+             */
+            dm_read_receipts: boolean;
+            /** @default earliest_members */
+            auto_dm_policy: components["schemas"]["AutoDMPolicy"];
+            /**
+             * @description Whether previews are fetched for external links posted in the workspace. Links to other messages are always previewed.
+             * @default true
+             */
+            link_previews: boolean;
+            /**
+             * @description Attachments older than this many days are deleted by the background garbage collector, even if a message still links them. 0 keeps attachments forever.
+             * @default 0
+             */
+            attachment_retention_days: number;
+            /**
+             * @description Default message retention for channels in the workspace. Threads whose latest activity is older than this many days are permanently deleted by the background purge job. Channels can override it. 0 keeps messages forever.
+             * @default 0
+             */
+            message_retention_days: number;
+            /**
+             * @description Whether anyone with an email on `open_signup_domains` can join as a member through the workspace's join link (`/workspaces/{wid}/join`) without an invite.
+             * @default false
+             */
+            open_signup: boolean;
+            /**
+             * @description Email domains allowed to join through open signup. Matching is exact, so subdomains must be listed separately.
+             * @example [
+             *       "acme.com"
+             *     ]
+             */
+            open_signup_domains?: string[];
+            /**
+             * @description When non-empty, only uploads matching one of these file extensions or content types are accepted. Content types are detected from the file's bytes, not taken from the client.
+             * @example [
+             *       "image/*",
+             *       ".pdf"
+             *     ]
+             */
+            upload_allowed_types?: string[];
+            /**
+             * @description Uploads matching one of these file extensions or content types are rejected with `FILE_TYPE_NOT_ALLOWED`. The denylist wins over the allowlist.
+             * @example [
+             *       ".exe",
+             *       "application/x-msdownload"
+             *     ]
+             */
+            upload_blocked_types?: string[];
+            /**
+             * @description Sound clients play for members who have not picked one in their client settings (`GET /users/me/settings`). Omitted when unset, leaving it to the client.
+             * @example chime
+             */
+            default_notification_sound?: string;
+        };
+        WorkspaceSignup: {
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example Acme */
+            name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            icon_url?: string;
+            /**
+             * @example [
+             *       "acme.com"
+             *     ]
+             */
+            allowed_domains: string[];
+            is_member: boolean;
+            /** @description Whether the caller's email is on an allowed domain and verified. */
+            can_join: boolean;
+        };
+        WorkspaceStorage: {
+            /**
+             * Format: int64
+             * @description Total size of all attachments in the workspace
+             */
+            used_bytes: number;
+            /** Format: int64 */
+            attachment_count: number;
+            /**
+             * Format: int64
+             * @description Maximum total attachment size allowed per workspace. Omitted when there is no quota.
+             */
+            quota_bytes?: number;
+            /** @description Attachment retention period in days; 0 keeps attachments forever */
+            attachment_retention_days: number;
+        };
+        RetentionPreview: {
+            /** @description Retention period the preview was computed for; 0 keeps messages forever */
+            message_retention_days: number;
+            /**
+             * Format: date-time
+             * @description Threads whose latest activity is before this time would be deleted. Omitted when messages are kept forever.
+             */
+            before?: string;
+            /**
+             * Format: int64
+             * @description Messages that would be deleted, including thread replies
+             */
+            message_count: number;
+            /**
+             * Format: int64
+             * @description Attachments that would be deleted with those messages
+             */
+            attachment_count: number;
+        };
+        WorkspaceFeature: {
+            /**
+             * @description Flag key, e.g. threads_v2
+             * @example threads_v2
+             */
+            key: string;
+            /** @description What the flag turns on */
+            description: string;
+            /** @description Whether the flag is on in this workspace */
+            enabled: boolean;
+            /**
+             * @description Where the value comes from: the built-in default, the server config, or a workspace override
+             * @enum {string}
+             */
+            source: "default" | "server" | "workspace";
+        };
+        WorkspaceExport: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @description Set for an export of a single channel */
+            channel_id?: string;
+            /**
+             * @description zip for workspace exports; json or csv for channel exports
+             * @enum {string}
+             */
+            format: "zip" | "json" | "csv";
+            requested_by?: string;
+            /** @enum {string} */
+            status: "pending" | "running" | "completed" | "failed";
+            /**
+             * Format: int64
+             * @description Size of the archive. 0 until the export completes.
+             */
+            size_bytes: number;
+            /**
+             * Format: int64
+             * @description Messages the export will contain. 0 until the export starts.
+             */
+            messages_total: number;
+            /**
+             * Format: int64
+             * @description Messages written so far
+             */
+            messages_exported: number;
+            /** @description Why the export failed */
+            last_error?: string;
+            /**
+             * Format: date-time
+             * @description When the archive will be deleted
+             */
+            expires_at?: string;
+            /** Format: date-time */
+            completed_at?: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        AccountDeletion: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @enum {string} */
+            status: "pending" | "running" | "completed" | "failed";
+            /** Format: date-time */
+            created_at: string;
+        };
+        Workspace: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example general */
+            name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            icon_url?: string;
+            /** @description JSON string containing workspace settings (for backward compatibility) */
+            settings: string;
+            parsed_settings?: components["schemas"]["WorkspaceSettings"];
+            /** @description Whether the owner has put the workspace into read-only mode. Server-wide read-only mode is reported by /server-info. */
+            read_only: boolean;
+            /**
+             * @description Banner text to show while read-only
+             * @example Moving to a new server, back in 15 minutes
+             */
+            read_only_message?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        WorkspaceSummary: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example general */
+            name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            icon_url?: string;
+            role: components["schemas"]["WorkspaceRole"];
+            /**
+             * @description User's custom sort order for this workspace
+             * @example 1
+             */
+            sort_order?: number;
+            /** @description Present when the user is banned from this workspace */
+            ban?: {
+                /** @example Repeated spam */
+                reason?: string;
+                /** Format: date-time */
+                expires_at?: string;
+            };
+        };
+        WorkspaceNotificationSummary: {
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example 12 */
+            unread_count: number;
+            /** @example 3 */
+            notification_count: number;
+            /**
+             * @description Number of followed threads with new replies
+             * @example 2
+             */
+            unread_thread_count: number;
+        };
+        WorkspaceMembership: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            role: components["schemas"]["WorkspaceRole"];
+            display_name_override?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        WorkspaceMemberWithUser: components["schemas"]["WorkspaceMembership"] & {
+            /**
+             * Format: email
+             * @example alice@example.com
+             */
+            email: string;
+            /** @example Alice Chen */
+            display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            gravatar_url?: string;
+            /** @example Staff Engineer */
+            title?: string;
+            /** @example she/her */
+            pronouns?: string;
+            /** @example Europe/Berlin */
+            timezone?: string;
+            /** @description Custom profile values, keyed by profile field ID */
+            profile_fields?: {
+                [key: string]: string;
+            };
+            /** @description Whether the user is currently banned from the workspace */
+            is_banned?: boolean;
+            /** @description Whether the user account has been deactivated */
+            is_deactivated?: boolean;
+            /** @description Whether the user is a bot account */
+            is_bot?: boolean;
+        };
+        /** @enum {string} */
+        WorkspaceRole: "owner" | "admin" | "member" | "guest";
+        Invite: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example abc123def */
+            code: string;
+            /**
+             * Format: email
+             * @example newuser@example.com
+             */
+            invited_email?: string;
+            role: components["schemas"]["WorkspaceRole"];
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** @example 25 */
+            max_uses?: number;
+            /** @example 5 */
+            use_count: number;
+            /** Format: date-time */
+            expires_at?: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        Channel: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example general */
+            name: string;
+            description?: string;
+            /** @description Short line shown in the channel header */
+            topic?: string;
+            type: components["schemas"]["ChannelType"];
+            /** @description Whether this is the default channel (like */
+            is_default: boolean;
+            /** @description Whether new workspace members join this channel automatically. The default channel is always joined. */
+            auto_join: boolean;
+            history_visibility: components["schemas"]["ChannelHistoryVisibility"];
+            /** @example hash_abc123 */
+            dm_participant_hash?: string;
+            /** Format: date-time */
+            archived_at?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** @description Retention period set on this channel, in days. 0 keeps messages forever. Omitted when the channel uses the workspace default. */
+            message_retention_days?: number;
+            /** @description Who may use @channel, @here and @everyone in this channel. Omitted when the channel uses the workspace's who_can_mention_channel setting. */
+            who_can_mention_channel?: components["schemas"]["PermissionLevel"];
+            post_policy: components["schemas"]["ChannelPostPolicy"];
+            /** @description Workspace roles that may post when post_policy is `roles` */
+            post_roles?: components["schemas"]["WorkspaceRole"][];
+            /** @description Whether post_policy also applies to thread replies. When false, anyone who can read the channel may reply in threads. */
+            restrict_thread_replies: boolean;
+            /**
+             * @description Minimum seconds between two messages from the same member. 0 means slow mode is off. Channel and workspace admins are exempt.
+             * @example 30
+             */
+            slow_mode_seconds: number;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        /**
+         * @description `joined` lists the earliest members first, `name` alphabetically by display name.
+         * @default joined
+         * @enum {string}
+         */
+        WorkspaceMemberSort: "joined" | "name";
+        WorkspaceMemberListResult: {
+            members: components["schemas"]["WorkspaceMemberWithUser"][];
+            has_more: boolean;
+            next_cursor?: string;
+        };
+        WorkspaceMemberCount: {
+            /** @example 42 */
+            count: number;
+        };
+        ChannelListResult: {
+            channels: components["schemas"]["ChannelWithMembership"][];
+            /**
+             * @description Number of followed threads with new replies
+             * @example 2
+             */
+            unread_thread_count: number;
+        };
+        ChannelMemberListResult: {
+            members: components["schemas"]["ChannelMember"][];
+            has_more: boolean;
+            next_cursor?: string;
+        };
+        UnreadCountsResult: {
+            /** @description Map of channel ID to its counts */
+            channels: {
+                [key: string]: components["schemas"]["ChannelUnreadCount"];
+            };
+        };
+        ChannelUnreadCount: {
+            unread_count: number;
+            notification_count: number;
+        };
+        SyncInput: {
+            /** @description Map of channel ID to the ID of the newest message the client has in that channel. At most 200 channels. */
+            channels: {
+                [key: string]: string;
+            };
+        };
+        SyncResult: {
+            /** @description New messages for each requested channel the user can still read */
+            channels: components["schemas"]["ChannelSync"][];
+            /** @description The user's current channels, as returned by the channel list, including read state */
+            memberships: components["schemas"]["ChannelWithMembership"][];
+            /** @description Requested channels that no longer exist or that the user can no longer read */
+            removed_channel_ids: string[];
+        };
+        ChannelSync: {
+            channel_id: string;
+            /** @description Messages newer than the given ID, oldest first */
+            messages: components["schemas"]["MessageWithUser"][];
+            /** @description More new messages exist beyond this batch */
+            has_more: boolean;
+        };
+        ChannelWithMembership: components["schemas"]["Channel"] & {
+            /** @example 42 */
+            member_count: number;
+            channel_role?: components["schemas"]["ChannelRole"];
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            last_read_message_id?: string;
+            /** @example 12 */
+            unread_count: number;
+            /** @example 3 */
+            notification_count: number;
+            is_starred: boolean;
+            /** @description For DM channels, the other participants (excluding current user) */
+            dm_participants?: components["schemas"]["ChannelMember"][];
+            /**
+             * Format: date-time
+             * @description When the user's snooze of this channel or its workspace ends. Absent when neither is snoozed.
+             */
+            snoozed_until?: string;
+        };
+        DMConversation: components["schemas"]["ChannelWithMembership"] & {
+            /** @example Acme Corp */
+            workspace_name: string;
+            workspace_icon_url?: string;
+            /**
+             * Format: date-time
+             * @description When the latest top-level message was posted. Absent for empty conversations.
+             */
+            last_message_at?: string;
+            /**
+             * @description Start of the latest top-level message, with whitespace collapsed
+             * @example See you at standup
+             */
+            last_message_preview?: string;
+        };
+        DMInboxResult: {
+            conversations: components["schemas"]["DMConversation"][];
+        };
+        ChannelDirectoryEntry: components["schemas"]["Channel"] & {
+            /** @example 42 */
+            member_count: number;
+            is_member: boolean;
+            /**
+             * Format: date-time
+             * @description When the latest top-level message was posted. Absent for empty channels.
+             */
+            last_activity_at?: string;
+            /**
+             * @description Start of the latest top-level message, with whitespace collapsed
+             * @example Deploy is done, please check staging…
+             */
+            last_message_preview?: string;
+        };
+        ChannelDirectoryResult: {
+            channels: components["schemas"]["ChannelDirectoryEntry"][];
+            /** @example 87 */
+            total_count: number;
+            has_more: boolean;
+        };
+        /**
+         * @description `newest` lists recently created channels first, `members` the largest first, `name` alphabetically.
+         * @default newest
+         * @enum {string}
+         */
+        ChannelDirectorySort: "newest" | "members" | "name";
+        /** @enum {string} */
+        ChannelType: "public" | "private" | "dm" | "group_dm";
+        /** @enum {string} */
+        ChannelRole: "admin" | "poster" | "viewer";
+        /**
+         * @description Which messages posted before a member joined are visible to them.
+         *     Only enforced for private channels.
+         * @enum {string}
+         */
+        ChannelHistoryVisibility: "all" | "last_30_days" | "none";
+        ChannelMember: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /**
+             * Format: email
+             * @example alice@example.com
+             */
+            email: string;
+            /** @example Alice Chen */
+            display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            gravatar_url?: string;
+            /** @example Staff Engineer */
+            title?: string;
+            /** @example she/her */
+            pronouns?: string;
+            /**
+             * @description IANA timezone name
+             * @example Europe/Berlin
+             */
+            timezone?: string;
+            channel_role?: components["schemas"]["ChannelRole"];
+            /** @description Whether the user account has been deactivated */
+            is_deactivated?: boolean;
+        };
+        /** @enum {string} */
+        MessageType: "user" | "system" | "poll";
+        /** @enum {string} */
+        SystemEventType: "user_joined" | "user_left" | "user_added" | "user_converted_channel" | "channel_renamed" | "channel_visibility_changed" | "channel_description_updated" | "channel_topic_changed" | "channel_archived" | "channel_unarchived" | "message_pinned" | "message_unpinned" | "dm_welcome" | "call" | "users_added";
+        SystemEventData: {
+            event_type: components["schemas"]["SystemEventType"];
+            /**
+             * @description The user who joined/left/was added (the actor for users_added)
+             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
+             */
+            user_id: string;
+            /**
+             * @description Display name of the user at the time of the event
+             * @example Alice Chen
+             */
+            user_display_name: string;
+            /**
+             * @description Name of the channel (the workspace name for dm_welcome)
+             * @example general
+             */
+            channel_name: string;
+            /**
+             * @description The user who performed the action (for user_added)
+             * @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ
+             */
+            actor_id?: string;
+            /**
+             * @description Display name of the actor
+             * @example Bob Martinez
+             */
+            actor_display_name?: string;
+            /** @description The new topic, empty when it was cleared (for channel_topic_changed events) */
+            topic?: string;
+            /**
+             * @description Previous channel name (for rename events)
+             * @example old-channel-name
+             */
+            old_channel_name?: string;
+            /**
+             * @description New channel type (for visibility change events)
+             * @example public
+             */
+            channel_type?: string;
+            /**
+             * @description Referenced message ID (for pin/unpin events)
+             * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
+             */
+            message_id?: string;
+            /**
+             * @description Why the direct message was opened (for dm_welcome events)
+             * @enum {string}
+             */
+            welcome_reason?: "inviter" | "admin" | "member";
+            /**
+             * @description The call (for call events)
+             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
+             */
+            call_id?: string;
+            /** @description How long the call lasted, set once it has ended (for call events) */
+            call_duration_seconds?: number;
+            /** @description How many people joined, set once the call has ended (for call events) */
+            call_participant_count?: number;
+            /** @description How many people were added (for users_added events) */
+            user_count?: number;
+        };
+        Message: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id?: string;
+            /** @example Hello, world! */
+            content: string;
+            /** @description The content parsed into formatting nodes, with mentions and channel links resolved to IDs. Omitted for deleted and empty messages. */
+            content_rendered?: components["schemas"]["MrkdwnNode"][];
+            /** @description Set when a list endpoint cut the content short because the server limits content length in lists. Fetch the message for its full content ("show more"). */
+            content_truncated?: boolean;
+            type?: components["schemas"]["MessageType"];
+            system_event?: components["schemas"]["SystemEventData"];
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id?: string;
+            /** @example 3 */
+            reply_count: number;
+            /** Format: date-time */
+            last_reply_at?: string;
+            /** Format: date-time */
+            edited_at?: string;
+            /** Format: date-time */
+            deleted_at?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+            also_send_to_channel?: boolean;
+            /** Format: date-time */
+            pinned_at?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            pinned_by?: string;
+            /**
+             * @description Set when the message was posted through an incoming webhook. user_display_name and user_avatar_url then carry the webhook's bot identity.
+             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
+             */
+            webhook_id?: string;
+            /**
+             * @description Set when the message delivers an announcement. Clients should render it prominently and offer to acknowledge it.
+             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
+             */
+            announcement_id?: string;
+        };
+        /** @enum {string} */
+        MrkdwnNodeType: "text" | "bold" | "italic" | "strike" | "code" | "code_block" | "blockquote" | "bullet_list" | "ordered_list" | "user_mention" | "special_mention" | "group_mention" | "channel_mention" | "link" | "emoji_shortcode" | "line_break";
+        MrkdwnNode: {
+            type: components["schemas"]["MrkdwnNodeType"];
+            /** @description Text of text, formatting, code and link nodes */
+            text?: string;
+            /** @description Language of a code block, when given */
+            language?: string;
+            /** @description Target of a link; always http or https */
+            url?: string;
+            user_id?: string;
+            group_id?: string;
+            channel_id?: string;
+            /** @description here, channel or everyone for special mentions */
+            mention?: string;
+            /** @description Shortcode of an emoji, without colons */
+            name?: string;
+            /** @description Content of a block quote */
+            children?: components["schemas"]["MrkdwnNode"][];
+            /** @description Items of a list, each a sequence of nodes */
+            items?: components["schemas"]["MrkdwnNode"][][];
+        };
+        MessagePermalink: {
+            /** @description Canonical web URL of the message */
+            url: string;
+            workspace_id: string;
+            channel_id: string;
+            message_id: string;
+            /** @description Parent message ID when the message is a thread reply */
+            thread_parent_id?: string;
+            /** @description Cursor for listing channel messages with direction around. This is the message itself, or the thread parent for replies that are only in the thread. */
+            cursor: string;
+        };
+        MessageWithUser: components["schemas"]["Message"] & {
+            /** @example Alice Chen */
+            user_display_name?: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            user_avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            user_gravatar_url?: string;
+            /** @description True when the author has been deactivated or removed. Removed authors are reported with the display name "Former member". */
+            user_is_deactivated?: boolean;
+            /** @description True when the message was posted by a bot account or an incoming webhook */
+            is_bot?: boolean;
+            reactions?: components["schemas"]["Reaction"][];
+            /** @description The first few people to reply, in order of their first reply */
+            thread_participants?: components["schemas"]["ThreadParticipant"][];
+            /** @description Total number of distinct people who have replied to the thread */
+            thread_participant_count?: number;
+            attachments?: components["schemas"]["Attachment"][];
+            link_preview?: components["schemas"]["LinkPreview"];
+            /** @description Who has seen the message. Only populated for direct messages when the workspace has read receipts enabled. */
+            read_receipts?: components["schemas"]["MessageReceipt"][];
+            poll?: components["schemas"]["Poll"];
+        };
+        Call: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            started_by?: string;
+            /**
+             * @description The system message announcing the call
+             * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
+             */
+            message_id?: string;
+            /** Format: date-time */
+            started_at: string;
+            /** Format: date-time */
+            ended_at?: string;
+            /** @description Everyone who joined; those still in the call have no left_at */
+            participants: components["schemas"]["CallParticipant"][];
+        };
+        CallParticipant: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** Format: date-time */
+            joined_at: string;
+            /** Format: date-time */
+            left_at?: string;
+        };
+        /** @enum {string} */
+        CallSignalType: "offer" | "answer" | "ice_candidate";
+        CallSignalInput: {
+            to_user_id: string;
+            type: components["schemas"]["CallSignalType"];
+            /** @description The SDP or ICE candidate, passed through unchanged */
+            payload: string;
+        };
+        CallSignalData: {
+            call_id: string;
+            from_user_id: string;
+            type: components["schemas"]["CallSignalType"];
+            payload: string;
+        };
+        Poll: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            message_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** @example Where should we go for lunch? */
+            question: string;
+            multiple_choice: boolean;
+            /** @description When true, options do not list their voters */
+            anonymous: boolean;
+            /** Format: date-time */
+            closes_at?: string;
+            /** Format: date-time */
+            closed_at?: string;
+            options: components["schemas"]["PollOption"][];
+            /** @description Number of distinct users who voted */
+            voter_count: number;
+            /** @description IDs of the options the caller voted for. Not included in `poll.updated` events. */
+            my_votes?: string[];
+            /** Format: date-time */
+            created_at: string;
+        };
+        PollOption: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example Pizza */
+            text: string;
+            vote_count: number;
+            /** @description Users who chose the option, omitted for anonymous polls */
+            voter_ids?: string[];
+        };
+        CreatePollInput: {
+            question: string;
+            options: string[];
+            /** @default false */
+            multiple_choice: boolean;
+            /** @default false */
+            anonymous: boolean;
+            /**
+             * Format: date-time
+             * @description When to close the poll automatically; must be in the future
+             */
+            closes_at?: string;
+        };
+        VotePollInput: {
+            option_ids: string[];
+        };
+        MessageReceipt: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** Format: date-time */
+            read_at: string;
+        };
+        ThreadParticipant: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example Alice Chen */
+            display_name?: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            gravatar_url?: string;
+            /** @description Whether the user has been deactivated or removed */
+            is_deactivated?: boolean;
+        };
+        Reactor: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example Alice Chen */
+            display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            gravatar_url?: string;
+            /** @description Whether the user has been deactivated or removed */
+            is_deactivated?: boolean;
+            /** @example 🎉 */
+            emoji: string;
+            /**
+             * Format: date-time
+             * @description When the user reacted
+             */
+            created_at: string;
+        };
+        ReactionCount: {
+            /** @example 🎉 */
+            emoji: string;
+            /** @example 12 */
+            count: number;
+            /** @description Whether the caller reacted with this emoji */
+            reacted: boolean;
+        };
+        ReactorListResult: {
+            reactors: components["schemas"]["Reactor"][];
+            /** @description Every emoji on the message, in the order they were first used */
+            counts: components["schemas"]["ReactionCount"][];
+            has_more: boolean;
+            next_cursor?: string;
+        };
+        ThreadParticipantListResult: {
+            participants: components["schemas"]["ThreadParticipant"][];
+            /** @description Total number of distinct people who have replied to the thread */
+            participant_count: number;
+            has_more: boolean;
+            next_cursor?: string;
+        };
+        Attachment: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example report.pdf */
+            filename: string;
+            /** @example application/pdf */
+            content_type: string;
+            /**
+             * Format: int64
+             * @example 1048576
+             */
+            size_bytes: number;
+            /**
+             * @description Download URL for the attachment
+             * @example /files/01JQ3KMT6B/download?sig=abc
+             */
+            url: string;
+            status: components["schemas"]["AttachmentStatus"];
+            /**
+             * @description Pixel width of an image, as displayed. Set for JPEG, PNG, GIF and WebP images.
+             * @example 1024
+             */
+            width?: number;
+            /**
+             * @description Pixel height of an image, as displayed. Set for JPEG, PNG, GIF and WebP images.
+             * @example 768
+             */
+            height?: number;
+            /** Format: date-time */
+            created_at: string;
+        };
+        /**
+         * @description `quarantined` files failed the antivirus scan. They cannot be downloaded or attached to a message, and are removed with other unattached uploads.
+         * @enum {string}
+         */
+        AttachmentStatus: "ready" | "quarantined";
+        /**
+         * @description `images` are image/* files, `docs` PDFs and office documents, `snippets` text and code files.
+         * @enum {string}
+         */
+        FileType: "images" | "docs" | "snippets";
+        /**
+         * @default newest
+         * @enum {string}
+         */
+        FileSort: "newest" | "oldest" | "largest" | "name";
+        FileListEntry: {
+            file: components["schemas"]["Attachment"];
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example design */
+            channel_name: string;
+            /**
+             * @description The message the file was posted in
+             * @example 01JQ3KMR5ZWXT7C2NQJ8YPHD4B
+             */
+            message_id: string;
+            /** @description Set when the message is a thread reply */
+            thread_parent_id?: string;
+            /**
+             * @description The uploader. Omitted for webhook uploads.
+             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
+             */
+            user_id?: string;
+            /** @example Alice Chen */
+            user_display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            user_avatar_url?: string;
+        };
+        FileListResult: {
+            files: components["schemas"]["FileListEntry"][];
+            /** @example 214 */
+            total_count: number;
+            has_more: boolean;
+        };
+        LinkPreview: {
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            url: string;
+            /**
+             * @default external
+             * @enum {string}
+             */
+            type: "external" | "message";
+            /** @example Example Page */
+            title?: string;
+            description?: string;
+            /** @example https://example.com/image.png */
+            image_url?: string;
+            /** @example example.com */
+            site_name?: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            linked_message_id?: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            linked_channel_id?: string;
+            /** @example random */
+            linked_channel_name?: string;
+            /** @example public */
+            linked_channel_type?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            message_author_id?: string;
+            /** @example Alice Chen */
+            message_author_name?: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            message_author_avatar_url?: string;
+            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
+            message_author_gravatar_url?: string;
+            /** @example Check out this link! */
+            message_content?: string;
+            /** Format: date-time */
+            message_created_at?: string;
+        };
+        Reaction: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example 👍 */
+            emoji: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        ReactionOperation: {
+            /** @enum {string} */
+            op: "add" | "remove";
+            /** @example 👍 */
+            emoji: string;
+        };
+        ReactionSummary: {
+            /** @example 👍 */
+            emoji: string;
+            /** @example 5 */
+            count: number;
+            user_ids: string[];
+        };
+        MessageListResult: {
+            messages: components["schemas"]["MessageWithUser"][];
+            has_more: boolean;
+            has_newer?: boolean;
+            has_older?: boolean;
+            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+            next_cursor?: string;
+        };
+        UnreadMessage: components["schemas"]["MessageWithUser"] & {
+            /** @example general */
+            channel_name: string;
+            channel_type: components["schemas"]["ChannelType"];
+            /** @description The message is in cold storage. Open it with GET /channels/{id}/messages/archived rather than the channel history. */
+            archived?: boolean;
+        };
+        UnreadMessagesResult: {
+            messages: components["schemas"]["UnreadMessage"][];
+            has_more: boolean;
+            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+            next_cursor?: string;
+        };
+        SearchMessagesInput: {
+            /**
+             * @description Search terms, quoted phrases and operators such as `from:@name` or `has:link`
+             * @example deploy from:@alice in:#ops
+             */
+            query: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id?: string;
+            /** Format: date-time */
+            before?: string;
+            /** Format: date-time */
+            after?: string;
+            /** @default 20 */
+            limit: number;
+            /** @description next_cursor from the previous page */
+            cursor?: string;
+            /**
+             * @deprecated
+             * @description Results to skip. Use `cursor` instead; offset paging counts every match on every page, and servers can turn it off with `messages.search_offset`.
+             * @default 0
+             */
+            offset: number;
+        };
+        SearchMessage: components["schemas"]["MessageWithUser"] & {
+            /** @example general */
+            channel_name: string;
+            channel_type: components["schemas"]["ChannelType"];
+            /** @description The message is in cold storage. Open it with GET /channels/{id}/messages/archived rather than the channel history. */
+            archived?: boolean;
+        };
+        SearchMessagesResult: {
+            messages: components["schemas"]["SearchMessage"][];
+            /**
+             * @description Number of matches. Set on the first page and on pages fetched by offset.
+             * @example 42
+             */
+            total_count?: number;
+            /** @description The first page stopped counting at 1000 matches; there are at least total_count */
+            total_count_approximate?: boolean;
+            has_more: boolean;
+            /** @description Pass as cursor to fetch the next page */
+            next_cursor?: string;
+            /** @example search term */
+            query: string;
+        };
+        /** @enum {string} */
+        QuickSwitchKind: "channel" | "dm" | "member";
+        QuickSwitchItem: {
+            kind: components["schemas"]["QuickSwitchKind"];
+            /**
+             * @description Channel name, DM participants' names, or member display name
+             * @example engineering
+             */
+            name: string;
+            /**
+             * @description Channel to open. For members, set only if a 1:1 DM already exists.
+             * @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG
+             */
+            channel_id?: string;
+            channel_type?: components["schemas"]["ChannelType"];
+            /** @description Whether the caller has joined the channel */
+            is_member: boolean;
+            /** @description For DMs, the other participants */
+            participants?: components["schemas"]["ChannelMember"][];
+            user?: components["schemas"]["ChannelMember"];
+            /**
+             * Format: date-time
+             * @description Time of the newest message in the channel or DM
+             */
+            last_activity_at?: string;
+        };
+        QuickSwitchResult: {
+            results: components["schemas"]["QuickSwitchItem"][];
+        };
+        ThreadMessage: components["schemas"]["MessageWithUser"] & {
+            /** @example general */
+            channel_name: string;
+            channel_type: components["schemas"]["ChannelType"];
+            has_new_replies: boolean;
+            /**
+             * @description Replies after the user's last read reply
+             * @example 3
+             */
+            unread_reply_count: number;
+        };
+        ThreadListResult: {
+            threads: components["schemas"]["ThreadMessage"][];
+            has_more: boolean;
+            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+            next_cursor?: string;
+            /** @example 2 */
+            unread_thread_count: number;
+        };
+        /** @enum {string} */
+        ActivityType: "mention" | "thread_reply" | "reaction" | "channel_invite";
+        ActivityItem: {
+            /** @example 01JQ3KMT4BXCN7DE2HGR5WKP8A */
+            id: string;
+            type: components["schemas"]["ActivityType"];
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example general */
+            channel_name: string;
+            channel_type: components["schemas"]["ChannelType"];
+            /**
+             * @description The message that mentions or replies to the user, or that was reacted to
+             * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
+             */
+            message_id?: string;
+            /**
+             * @description Set when the message is a thread reply
+             * @example 01JQ3KMR1ZTPQ6VB3MAJXHNC9D
+             */
+            thread_parent_id?: string;
+            /** @example Can you take a look, <@01JQ3KMN7XFGY4P6WBR2SZTA9V>? */
+            message_content?: string;
+            /**
+             * @description The emoji, for reactions
+             * @example 🎉
+             */
+            emoji?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            actor_id?: string;
+            /** @example Alice Johnson */
+            actor_display_name?: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            actor_avatar_url?: string;
+            is_read: boolean;
+            /** Format: date-time */
+            created_at: string;
+        };
+        ActivityListResult: {
+            items: components["schemas"]["ActivityItem"][];
+            has_more: boolean;
+            /** @example 01JQ3KMT4BXCN7DE2HGR5WKP8A */
+            next_cursor?: string;
+            /** @example 3 */
+            unread_count: number;
+        };
+        UploadSession: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            channel_id: string;
+            /** @example recording.mp4 */
+            filename: string;
+            /** @example video/mp4 */
+            content_type: string;
+            /**
+             * Format: int64
+             * @description Total size declared when the upload was created
+             * @example 8388608
+             */
+            size: number;
+            /**
+             * Format: int64
+             * @description Bytes received so far; the next chunk starts here
+             * @example 4194304
+             */
+            offset: number;
+            /**
+             * Format: date-time
+             * @description When the session is discarded if no further chunks arrive
+             */
+            expires_at: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        CreateUploadInput: {
+            /** @example recording.mp4 */
+            filename: string;
+            /**
+             * @description Defaults to application/octet-stream
+             * @example video/mp4
+             */
+            content_type?: string;
+            /**
+             * Format: int64
+             * @description Total file size in bytes
+             * @example 8388608
+             */
+            size: number;
+        };
+        ApiError: {
+            /**
+             * @description Stable, machine-readable error code. Clients should branch on this rather than on the message.
+             * @example VALIDATION_ERROR
+             */
+            code: string;
+            /**
+             * @description Human-readable description. Codes with a fixed meaning are translated into the language picked from Accept-Language.
+             * @example Invalid request parameters
+             */
+            message: string;
+            /**
+             * @description For errors caused by going over a limit, such as MESSAGE_TOO_LONG, the limit that applies
+             * @example 40000
+             */
+            limit?: number;
+            /**
+             * @description Values that fill in the message for the code, such as the offending field, so clients can show their own text
+             * @example {
+             *       "field": "name",
+             *       "max": 80
+             *     }
+             */
+            params?: {
+                [key: string]: unknown;
+            };
+        };
+        ApiErrorResponse: {
+            error: components["schemas"]["ApiError"];
+        };
+        SlowModeErrorResponse: {
+            error: components["schemas"]["ApiError"];
+            /** @description Seconds until the sender may post again */
+            retry_after: number;
+        };
+        /** @description RFC 7807 problem document, sent instead of ApiErrorResponse (with Content-Type application/problem+json) when the request's Accept header asks for application/problem+json. The envelope's fields are carried as extension members. */
+        Problem: {
+            /** @example about:blank */
+            type: string;
+            /**
+             * @description Reason phrase for the status
+             * @example Bad Request
+             */
+            title: string;
+            /** @example 400 */
+            status: number;
+            /**
+             * @description The error message
+             * @example name is required
+             */
+            detail?: string;
+            /**
+             * @description Path of the request that failed
+             * @example /api/workspaces/01HZX3J2/channels/create
+             */
+            instance?: string;
+            /** @example REQUIRED */
+            code: string;
+            params?: {
+                [key: string]: unknown;
+            };
+            limit?: number;
+            retry_after?: number;
+            /** @description ID of the request, as in the X-Request-Id header, for matching server logs */
+            request_id?: string;
+        };
+        CustomEmoji: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example general */
+            name: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by: string;
+            /** @example application/pdf */
+            content_type: string;
+            /**
+             * Format: int64
+             * @example 1048576
+             */
+            size_bytes: number;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            url: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        IncomingWebhook: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example Deploy Bot */
+            name: string;
+            /** @example https://example.com/bot.png */
+            avatar_url?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** Format: date-time */
+            last_used_at?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        IncomingWebhookWithUrl: {
+            webhook: components["schemas"]["IncomingWebhook"];
+            /**
+             * @description Full webhook URL including the secret token. Only returned on create and token regeneration.
+             * @example https://chat.example.com/api/hooks/3f9c2a...
+             */
+            url: string;
+        };
+        CreateIncomingWebhookInput: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example Deploy Bot */
+            name: string;
+            /** @example https://example.com/bot.png */
+            avatar_url?: string;
+        };
+        UpdateIncomingWebhookInput: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id?: string;
+            /** @example Deploy Bot */
+            name?: string;
+            /** @example https://example.com/bot.png */
+            avatar_url?: string;
+        };
+        ExecuteIncomingWebhookInput: {
+            /** @example Deployed v1.4.2 to production */
+            text: string;
+            /**
+             * @description Overrides the webhook's display name for this message
+             * @example Release Train
+             */
+            username?: string;
+            /**
+             * @description Overrides the webhook's avatar for this message
+             * @example https://example.com/train.png
+             */
+            icon_url?: string;
+        };
+        Bot: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example Standup Bot */
+            display_name: string;
+            /** @example https://example.com/bot.png */
+            avatar_url?: string;
+            /** @example Collects daily standup notes */
+            description?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        CreateBotInput: {
+            /** @example Standup Bot */
+            display_name: string;
+            /** @example https://example.com/bot.png */
+            avatar_url?: string;
+            /** @example Collects daily standup notes */
+            description?: string;
+        };
+        UpdateBotInput: {
+            /** @example Standup Bot */
+            display_name?: string;
+            /** @example https://example.com/bot.png */
+            avatar_url?: string;
+            /** @example Collects daily standup notes */
+            description?: string;
+        };
+        /** @enum {string} */
+        BotScope: "chat:write" | "reactions:write" | "channels:read" | "channels:history" | "channels:join" | "users:read";
+        BotToken: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            bot_user_id: string;
+            /** @example Production */
+            name: string;
+            scopes: components["schemas"]["BotScope"][];
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** Format: date-time */
+            last_used_at?: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        CreateBotTokenInput: {
+            /** @example Production */
+            name: string;
+            scopes: components["schemas"]["BotScope"][];
+        };
+        Announcement: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            created_by?: string;
+            /** @example Updated travel policy */
+            title: string;
+            /** @example Please review the updated travel policy before your next trip. */
+            content: string;
+            /** @description Target channels. Empty when the announcement targets the whole workspace. */
+            channel_ids: string[];
+            /** Format: date-time */
+            scheduled_for: string;
+            /** @enum {string} */
+            status: "scheduled" | "sending" | "sent" | "failed";
+            /** Format: date-time */
+            sent_at?: string;
+            last_error?: string;
+            /** @example 42 */
+            recipient_count: number;
+            /** @example 17 */
+            acknowledged_count: number;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        CreateAnnouncementInput: {
+            /** @example Updated travel policy */
+            title: string;
+            /** @example Please review the updated travel policy before your next trip. */
+            content: string;
+            /** @description Channels to post to. Omit to target the whole workspace. */
+            channel_ids?: string[];
+            /**
+             * Format: date-time
+             * @description When to deliver the announcement. Omit to deliver immediately.
+             */
+            scheduled_for?: string;
+        };
+        AnnouncementRecipient: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example Alice Chen */
+            display_name: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+            /** Format: date-time */
+            acknowledged_at?: string;
+        };
+        SignedUrl: {
+            /** @example 01JQ3KMT6BMRXP0WCDG9HQSYNF */
+            file_id: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            url: string;
+            /** Format: date-time */
+            expires_at: string;
+        };
+        ServerInfo: {
+            /** @example 0.2.0 */
+            version: string;
+            /**
+             * @description Oldest client release this server works with. Older clients should ask the user to update.
+             * @example 0.1.0
+             */
+            min_client_version: string;
+            email_enabled?: boolean;
+            files_enabled?: boolean;
+            /** @description Whether the operator has put the whole server into read-only mode */
+            read_only?: boolean;
+            read_only_message?: string;
+            features: components["schemas"]["ServerFeatures"];
+            limits: components["schemas"]["ServerLimits"];
+            endpoints: components["schemas"]["ServerEndpoints"];
+        };
+        ServerFeatures: {
+            /** @description Whether mobile push notifications are delivered through a relay */
+            push_notifications: boolean;
+            /** @description Whether incoming webhooks can be created */
+            webhooks: boolean;
+            /** @description Whether uploads are stored in S3-compatible object storage rather than on local disk */
+            s3_storage: boolean;
+            /** @description Whether single sign-on is available. This server only supports password login, so it is always false. */
+            sso: boolean;
+        };
+        ServerLimits: {
+            /**
+             * Format: int64
+             * @description Largest file upload in bytes. Omitted when uploads are disabled.
+             * @example 10485760
+             */
+            max_upload_size?: number;
+            /**
+             * @description Longest message content in characters
+             * @example 40000
+             */
+            max_message_length: number;
+        };
+        /** @description Real-time event streams, relative to the server's base URL. Events are only delivered over SSE; there is no WebSocket endpoint. */
+        ServerEndpoints: {
+            /**
+             * @description SSE stream of one workspace's events
+             * @example /api/workspaces/{wid}/events
+             */
+            workspace_events: string;
+            /**
+             * @description SSE stream of the user's DM activity in every workspace
+             * @example /api/events
+             */
+            user_events: string;
+        };
+        SnoozeRequest: {
+            /**
+             * @description How long to snooze for, up to a week
+             * @example 60
+             */
+            duration_minutes: number;
+        };
+        SuccessResponse: {
+            success: boolean;
+        };
+        MarkReadResponse: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            last_read_message_id: string;
+            /**
+             * @description Top-level messages this marked read. Set when include_counts was requested.
+             * @example 4
+             */
+            marked_count?: number;
+            /**
+             * @description The first unread top-level message before this update, if there was one. Set when include_counts was requested.
+             * @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG
+             */
+            first_unread_message_id?: string;
+        };
+        ChannelReadState: {
+            /**
+             * @description Absent if the caller has never read the channel
+             * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
+             */
+            last_read_message_id?: string;
+            /**
+             * @description Where the "New messages" divider goes. Absent when everything is read.
+             * @example 01JQ3KMS2PWHX8RA4FJN6YVB3D
+             */
+            first_unread_message_id?: string;
+            /** @example 4 */
+            unread_count: number;
+        };
+        ChannelReadEventData: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            last_read_message_id: string;
+        };
+        WorkspaceReadEventData: {
+            /** @example 01JQ3KMP2RVHX8CNJW5TAGD4YB */
+            workspace_id: string;
+            /** @description Channels whose read position moved */
+            channels: components["schemas"]["ChannelReadEventData"][];
+        };
+        BulkAddChannelMembersInput: {
+            user_ids?: string[];
+            /** @description Add the group's current members as well */
+            user_group_id?: string;
+            role?: components["schemas"]["ChannelRole"];
+        };
+        ChannelMembersUpdated: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @description The users who were added; existing members are not listed */
+            added_user_ids: string[];
+            /**
+             * @description The user who added them
+             * @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ
+             */
+            actor_id?: string;
+        };
+        FocusChannelInput: {
+            /** @description The connection's ID, from its `connected` event */
+            client_id: string;
+            /**
+             * @description False when the connection navigates away from the channel or is hidden
+             * @default true
+             */
+            focused: boolean;
+        };
+        ChannelViewers: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            user_ids: string[];
+        };
+        ChannelActivity: {
+            /** @description One entry per day, oldest first */
+            days: components["schemas"]["ChannelActivityDay"][];
+            /** @example 42 */
+            total_messages: number;
+            /**
+             * @description Number of people who posted during the period
+             * @example 5
+             */
+            unique_posters: number;
+        };
+        ChannelActivityDay: {
+            /**
+             * @description UTC day, as YYYY-MM-DD
+             * @example 2026-10-16
+             */
+            date: string;
+            /** @example 3 */
+            message_count: number;
+        };
+        ThreadReadEventData: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMS2PWHX8RA4FJN6YVB3D */
+            last_read_reply_id: string;
+        };
+        /** @description Sent to the sender when a message sent with async set has been fully processed. Exactly one of message and error is set. */
+        MessageFinalizedData: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            message?: components["schemas"]["MessageWithUser"];
+            /** @description Why processing failed. The message is stored but may be missing attachments. */
+            error?: string;
+        };
+        MessageReadData: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** Format: date-time */
+            read_at: string;
+        };
+        /** @enum {string} */
+        SSEEventType: "connected" | "heartbeat" | "message.new" | "message.updated" | "message.deleted" | "reaction.added" | "reaction.removed" | "channel.created" | "channel.updated" | "channel.archived" | "channel.unarchived" | "channel.member_added" | "channel.member_removed" | "channel.read" | "typing.start" | "typing.stop" | "presence.changed" | "presence.initial" | "notification" | "emoji.created" | "emoji.deleted" | "message.pinned" | "message.unpinned" | "member.banned" | "member.unbanned" | "member.left" | "member.role_changed" | "workspace.updated" | "channels.invalidate" | "scheduled_message.created" | "scheduled_message.updated" | "scheduled_message.deleted" | "scheduled_message.sent" | "scheduled_message.failed" | "message.read" | "channel.starred" | "channel.unstarred" | "reaction.batch" | "channel.purged" | "message.restored" | "server.restarting" | "thread.read" | "activity.new" | "poll.updated" | "call.started" | "call.updated" | "call.ended" | "call.signal" | "channel.viewers" | "channel.members_updated" | "member.added" | "member.removed" | "channel.member_role_changed" | "workspace.read" | "message.finalized" | "settings.updated";
+        SSEEvent: components["schemas"]["SSEEventConnected"] | components["schemas"]["SSEEventHeartbeat"] | components["schemas"]["SSEEventMessageNew"] | components["schemas"]["SSEEventMessageUpdated"] | components["schemas"]["SSEEventMessageDeleted"] | components["schemas"]["SSEEventReactionAdded"] | components["schemas"]["SSEEventReactionRemoved"] | components["schemas"]["SSEEventChannelCreated"] | components["schemas"]["SSEEventChannelUpdated"] | components["schemas"]["SSEEventChannelArchived"] | components["schemas"]["SSEEventChannelUnarchived"] | components["schemas"]["SSEEventChannelMemberAdded"] | components["schemas"]["SSEEventChannelMemberRemoved"] | components["schemas"]["SSEEventChannelRead"] | components["schemas"]["SSEEventTypingStart"] | components["schemas"]["SSEEventTypingStop"] | components["schemas"]["SSEEventPresenceChanged"] | components["schemas"]["SSEEventPresenceInitial"] | components["schemas"]["SSEEventNotification"] | components["schemas"]["SSEEventEmojiCreated"] | components["schemas"]["SSEEventEmojiDeleted"] | components["schemas"]["SSEEventScheduledMessageCreated"] | components["schemas"]["SSEEventScheduledMessageUpdated"] | components["schemas"]["SSEEventScheduledMessageDeleted"] | components["schemas"]["SSEEventScheduledMessageSent"] | components["schemas"]["SSEEventMessagePinned"] | components["schemas"]["SSEEventMessageUnpinned"] | components["schemas"]["SSEEventMemberBanned"] | components["schemas"]["SSEEventMemberUnbanned"] | components["schemas"]["SSEEventMemberLeft"] | components["schemas"]["SSEEventMemberRoleChanged"] | components["schemas"]["SSEEventWorkspaceUpdated"] | components["schemas"]["SSEEventScheduledMessageFailed"] | components["schemas"]["SSEEventChannelsInvalidate"] | components["schemas"]["SSEEventMessageRead"] | components["schemas"]["SSEEventChannelStarred"] | components["schemas"]["SSEEventChannelUnstarred"] | components["schemas"]["SSEEventReactionBatch"] | components["schemas"]["SSEEventChannelPurged"] | components["schemas"]["SSEEventMessageRestored"] | components["schemas"]["SSEEventServerRestarting"] | components["schemas"]["SSEEventThreadRead"] | components["schemas"]["SSEEventActivityNew"] | components["schemas"]["SSEEventPollUpdated"] | components["schemas"]["SSEEventCallStarted"] | components["schemas"]["SSEEventCallUpdated"] | components["schemas"]["SSEEventCallEnded"] | components["schemas"]["SSEEventCallSignal"] | components["schemas"]["SSEEventChannelViewers"] | components["schemas"]["SSEEventChannelMembersUpdated"] | components["schemas"]["SSEEventMemberAdded"] | components["schemas"]["SSEEventMemberRemoved"] | components["schemas"]["SSEEventChannelMemberRoleChanged"] | components["schemas"]["SSEEventWorkspaceRead"] | components["schemas"]["SSEEventMessageFinalized"] | components["schemas"]["SSEEventSettingsUpdated"];
+        SSEEventConnected: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "connected";
+            data: components["schemas"]["ConnectedData"];
+        };
+        SSEEventHeartbeat: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "heartbeat";
+            data: components["schemas"]["HeartbeatData"];
+        };
+        SSEEventMessageNew: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.new";
+            data: components["schemas"]["MessageWithUser"];
+        };
+        SSEEventMessageUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.updated";
+            data: components["schemas"]["MessageWithUser"];
+        };
+        SSEEventMessageDeleted: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.deleted";
+            data: components["schemas"]["MessageDeletedData"];
+        };
+        SSEEventReactionAdded: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "reaction.added";
+            data: components["schemas"]["Reaction"];
+        };
+        SSEEventReactionRemoved: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "reaction.removed";
+            data: components["schemas"]["ReactionRemovedData"];
+        };
+        SSEEventChannelCreated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.created";
+            data: components["schemas"]["Channel"];
+        };
+        SSEEventChannelUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.updated";
+            data: components["schemas"]["Channel"];
+        };
+        SSEEventChannelArchived: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.archived";
+            data: components["schemas"]["Channel"];
+        };
+        SSEEventChannelUnarchived: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.unarchived";
+            data: components["schemas"]["Channel"];
+        };
+        SSEEventChannelMemberAdded: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.member_added";
+            data: components["schemas"]["ChannelMemberData"];
+        };
+        SSEEventChannelMemberRemoved: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.member_removed";
+            data: components["schemas"]["ChannelMemberData"];
+        };
+        SSEEventChannelRead: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.read";
+            data: components["schemas"]["ChannelReadEventData"];
+        };
+        SSEEventTypingStart: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "typing.start";
+            data: components["schemas"]["TypingEventData"];
+        };
+        SSEEventTypingStop: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "typing.stop";
+            data: components["schemas"]["TypingEventData"];
+        };
+        SSEEventPresenceChanged: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "presence.changed";
+            data: components["schemas"]["PresenceData"];
+        };
+        SSEEventPresenceInitial: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "presence.initial";
+            data: components["schemas"]["PresenceInitialData"];
+        };
+        PresenceInitialData: {
+            /** @description List of user IDs currently online in this workspace */
+            online_user_ids: string[];
+        };
+        SSEEventNotification: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "notification";
+            data: components["schemas"]["NotificationData"];
+        };
+        SSEEventEmojiCreated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "emoji.created";
+            data: components["schemas"]["CustomEmoji"];
+        };
+        SSEEventEmojiDeleted: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "emoji.deleted";
+            data: components["schemas"]["EmojiDeletedData"];
+        };
+        SSEEventScheduledMessageCreated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "scheduled_message.created";
+            data: components["schemas"]["ScheduledMessage"];
+        };
+        SSEEventScheduledMessageUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "scheduled_message.updated";
+            data: components["schemas"]["ScheduledMessage"];
+        };
+        SSEEventScheduledMessageDeleted: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "scheduled_message.deleted";
+            data: components["schemas"]["ScheduledMessageDeletedData"];
+        };
+        SSEEventScheduledMessageSent: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "scheduled_message.sent";
+            data: components["schemas"]["ScheduledMessageSentData"];
+        };
+        SSEEventMessagePinned: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.pinned";
+            data: components["schemas"]["MessageWithUser"];
+        };
+        SSEEventMessageUnpinned: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.unpinned";
+            data: components["schemas"]["MessageWithUser"];
+        };
+        SSEEventMemberBanned: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "member.banned";
+            data: components["schemas"]["WorkspaceMemberData"];
+        };
+        SSEEventMemberUnbanned: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "member.unbanned";
+            data: components["schemas"]["WorkspaceMemberData"];
+        };
+        SSEEventMemberLeft: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "member.left";
+            data: components["schemas"]["WorkspaceMemberData"];
+        };
+        SSEEventMemberRoleChanged: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "member.role_changed";
+            data: components["schemas"]["MemberRoleChangedData"];
+        };
+        SSEEventWorkspaceUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "workspace.updated";
+            data: components["schemas"]["Workspace"];
+        };
+        SSEEventScheduledMessageFailed: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "scheduled_message.failed";
+            data: components["schemas"]["ScheduledMessageFailedData"];
+        };
+        SSEEventChannelsInvalidate: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channels.invalidate";
+            data: Record<string, never>;
+        };
+        SSEEventMessageRead: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.read";
+            data: components["schemas"]["MessageReadData"];
+        };
+        SSEEventChannelStarred: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.starred";
+            data: components["schemas"]["ChannelStarredData"];
+        };
+        SSEEventChannelUnstarred: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.unstarred";
+            data: components["schemas"]["ChannelStarredData"];
+        };
+        SSEEventReactionBatch: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "reaction.batch";
+            data: components["schemas"]["ReactionBatchData"];
+        };
+        SSEEventChannelPurged: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.purged";
+            data: components["schemas"]["ChannelPurgedData"];
+        };
+        SSEEventMessageRestored: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.restored";
+            data: components["schemas"]["MessageWithUser"];
+        };
+        SSEEventServerRestarting: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "server.restarting";
+            data: components["schemas"]["ServerRestartingData"];
+        };
+        SSEEventThreadRead: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "thread.read";
+            data: components["schemas"]["ThreadReadEventData"];
+        };
+        SSEEventActivityNew: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "activity.new";
+            data: components["schemas"]["ActivityItem"];
+        };
+        SSEEventPollUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "poll.updated";
+            data: components["schemas"]["Poll"];
+        };
+        SSEEventCallStarted: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "call.started";
+            data: components["schemas"]["Call"];
+        };
+        SSEEventCallUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "call.updated";
+            data: components["schemas"]["Call"];
+        };
+        SSEEventCallEnded: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "call.ended";
+            data: components["schemas"]["Call"];
+        };
+        SSEEventCallSignal: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "call.signal";
+            data: components["schemas"]["CallSignalData"];
+        };
+        SSEEventChannelViewers: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.viewers";
+            data: components["schemas"]["ChannelViewers"];
+        };
+        SSEEventChannelMembersUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.members_updated";
+            data: components["schemas"]["ChannelMembersUpdated"];
+        };
+        SSEEventMemberAdded: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "member.added";
+            data: components["schemas"]["WorkspaceMemberData"];
+        };
+        SSEEventMemberRemoved: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "member.removed";
+            data: components["schemas"]["WorkspaceMemberData"];
+        };
+        SSEEventChannelMemberRoleChanged: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "channel.member_role_changed";
+            data: components["schemas"]["ChannelMemberRoleChangedData"];
+        };
+        SSEEventWorkspaceRead: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "workspace.read";
+            data: components["schemas"]["WorkspaceReadEventData"];
+        };
+        SSEEventMessageFinalized: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "message.finalized";
+            data: components["schemas"]["MessageFinalizedData"];
+        };
+        SSEEventSettingsUpdated: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id?: string;
+            /**
+             * @description discriminator enum property added by openapi-typescript
+             * @enum {string}
+             */
+            type: "settings.updated";
+            data: components["schemas"]["ClientSettings"];
+        };
+        ConnectedData: {
+            client_id: string;
+        };
+        HeartbeatData: {
+            /** Format: int64 */
+            timestamp: number;
+        };
+        MessageDeletedData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id?: string;
+        };
+        ReactionRemovedData: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example 👍 */
+            emoji: string;
+        };
+        ReactionBatchData: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            added: components["schemas"]["Reaction"][];
+            /** @description Emoji whose reaction was removed */
+            removed: string[];
+        };
+        ChannelMemberData: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+        };
+        ChannelStarredData: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+        };
+        ChannelPurgedData: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /**
+             * Format: date-time
+             * @description Threads whose latest activity was before this time were deleted
+             */
+            before: string;
+            /** @description Number of messages deleted, including thread replies */
+            count: number;
+        };
+        ServerRestartingData: {
+            /**
+             * @description How long to wait before reconnecting. The server closes the stream right after this event.
+             * @example 4200
+             */
+            reconnect_after_ms: number;
+        };
+        WorkspaceMemberData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+        };
+        ChannelMemberRoleChangedData: {
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            old_role?: components["schemas"]["ChannelRole"];
+            new_role: components["schemas"]["ChannelRole"];
+        };
+        MemberRoleChangedData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            old_role: string;
+            new_role: string;
+        };
+        EmojiDeletedData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example general */
+            name: string;
+        };
+        ScheduledMessageDeletedData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+        };
+        ScheduledMessageSentData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+        };
+        ScheduledMessageFailedData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            error: string;
+        };
+        NotificationData: {
+            /** @enum {string} */
+            type: "mention" | "dm" | "channel" | "here" | "everyone" | "thread_reply";
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example general */
+            channel_name?: string;
+            sender_name?: string;
+            preview?: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id?: string;
+        };
+        /** @enum {string} */
+        ThreadSubscriptionStatus: "subscribed" | "unsubscribed" | "none";
+        /** @enum {string} */
+        NotifyLevel: "all" | "mentions" | "none";
+        NotificationPreferences: {
+            notify_level: components["schemas"]["NotifyLevel"];
+            email_enabled: boolean;
+            /** @description True when nothing is set at this level and the values come from a broader one (channel, then workspace, then global) or the built-in default */
+            inherited?: boolean;
+        };
+        NotificationKeyword: {
+            id: string;
+            /** @example prod incident */
+            keyword: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        TypingEventData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example Alice Chen */
+            user_display_name?: string;
+        };
+        /** @enum {string} */
+        PresenceStatus: "online" | "offline";
+        PresenceData: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            status: components["schemas"]["PresenceStatus"];
+        };
+        RegisterInput: {
+            /**
+             * Format: email
+             * @example alice@example.com
+             */
+            email: string;
+            /** @example securepassword123 */
+            password: string;
+            /** @example Alice Chen */
+            display_name: string;
+        };
+        LoginInput: {
+            /**
+             * Format: email
+             * @example alice@example.com
+             */
+            email: string;
+            /** @example securepassword123 */
+            password: string;
+        };
+        AuthResponse: {
+            user: components["schemas"]["User"];
+            /**
+             * @description Access token
+             * @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ
+             */
+            token: string;
+            refresh_token: string;
+            /**
+             * Format: date-time
+             * @description When the access token expires
+             */
+            expires_at: string;
+        };
+        TokenResponse: {
+            /** @description Access token */
+            token: string;
+            refresh_token: string;
+            /**
+             * Format: date-time
+             * @description When the access token expires
+             */
+            expires_at: string;
+        };
+        RefreshInput: {
+            refresh_token: string;
+        };
+        MeResponse: {
+            user: components["schemas"]["User"];
+            workspaces?: components["schemas"]["WorkspaceSummary"][];
+        };
+        RegisterDeviceTokenRequest: {
+            /**
+             * @description The push notification token (FCM or APNs)
+             * @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ
+             */
+            token: string;
+            /**
+             * @description The push platform
+             * @enum {string}
+             */
+            platform: "fcm" | "apns";
+            /** @description A unique identifier for the device */
+            device_id: string;
+        };
+        RegisterDeviceTokenResponse: {
+            /**
+             * @description The device token record ID
+             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
+             */
+            id: string;
+        };
+        CreateWorkspaceInput: {
+            /** @example general */
+            name: string;
+            /** @description Channel template to apply to the new workspace. It must belong to a workspace where the caller is an admin or owner. */
+            template_id?: string;
+        };
+        UpdateWorkspaceInput: {
+            /** @example general */
+            name?: string;
+            /** @description Partial workspace settings to update. Only provided fields are changed. */
+            settings?: {
+                show_join_leave_messages?: boolean;
+                who_can_create_channels?: components["schemas"]["PermissionLevel"];
+                who_can_create_invites?: components["schemas"]["PermissionLevel"];
+                who_can_pin_messages?: components["schemas"]["PermissionLevel"];
+                who_can_manage_custom_emoji?: components["schemas"]["PermissionLevel"];
+                who_can_mention_channel?: components["schemas"]["PermissionLevel"];
+                dm_read_receipts?: boolean;
+                auto_dm_policy?: components["schemas"]["AutoDMPolicy"];
+                link_previews?: boolean;
+                attachment_retention_days?: number;
+                message_retention_days?: number;
+                /** @description Turning open signup on requires at least one allowed domain. */
+                open_signup?: boolean;
+                /** @description Replaces the allowed email domains. A leading `@` is ignored. */
+                open_signup_domains?: string[];
+                /** @description Replaces the upload allowlist. Entries are file extensions (`.pdf`), content types (`image/png`) or wildcards (`image/*`). */
+                upload_allowed_types?: string[];
+                /** @description Replaces the upload denylist, in the same format as `upload_allowed_types`. */
+                upload_blocked_types?: string[];
+                /** @description Sound clients play for members who have not picked one. An empty string clears it. */
+                default_notification_sound?: string;
+            };
+        };
+        UpdateChannelRetentionInput: {
+            /** @description Days to keep messages; 0 keeps them forever. Null uses the workspace default. */
+            message_retention_days?: number | null;
+        };
+        PreviewChannelRetentionInput: {
+            /** @description Retention period to preview instead of the channel's current one */
+            message_retention_days?: number;
+        };
+        CreateInviteInput: {
+            /**
+             * Format: email
+             * @example newuser@example.com
+             */
+            invited_email?: string;
+            role: components["schemas"]["WorkspaceRole"];
+            /** @example 25 */
+            max_uses?: number;
+            expires_in_hours?: number;
+        };
+        CreateDMInput: {
+            user_ids: string[];
+        };
+        ConvertGroupDMInput: {
+            /** @example general */
+            name: string;
+            description?: string;
+            /**
+             * @default private
+             * @enum {string}
+             */
+            type: "public" | "private";
+        };
+        CreateChannelExportInput: {
+            /**
+             * @description File format of the export. Defaults to json.
+             * @enum {string}
+             */
+            format?: "json" | "csv";
+        };
+        CreateChannelInput: {
+            /** @example general */
+            name: string;
+            description?: string;
+            type: components["schemas"]["ChannelType"];
+            history_visibility?: components["schemas"]["ChannelHistoryVisibility"];
+        };
+        UpdateChannelInput: {
+            /** @example general */
+            name?: string;
+            description?: string;
+            type?: components["schemas"]["ChannelType"];
+            history_visibility?: components["schemas"]["ChannelHistoryVisibility"];
+            who_can_mention_channel?: components["schemas"]["ChannelMentionPermission"];
+            post_policy?: components["schemas"]["ChannelPostPolicy"];
+            /** @description Workspace roles that may post when post_policy is `roles`. Replaces the current list. */
+            post_roles?: components["schemas"]["WorkspaceRole"][];
+            restrict_thread_replies?: boolean;
+            slow_mode_seconds?: number;
+            /** @description Add new workspace members to this channel when they accept an invite. Only workspace admins and owners can change it. */
+            auto_join?: boolean;
+            /** @description With auto_join turned on, also add every existing workspace member who is not in the channel yet. */
+            backfill_members?: boolean;
+        };
+        /**
+         * @description Who may post in a channel. `admins` makes it an announcement channel where only channel and workspace admins post; `roles` also allows the workspace roles in post_roles. Admins can always post.
+         * @enum {string}
+         */
+        ChannelPostPolicy: "everyone" | "admins" | "roles";
+        MentionPreview: {
+            /** @description Whether the caller may use @channel, @here and @everyone here */
+            can_mention_channel: boolean;
+            who_can_mention_channel: components["schemas"]["PermissionLevel"];
+            /**
+             * @description People @channel and @everyone would notify
+             * @example 248
+             */
+            member_count: number;
+            /**
+             * @description People @here would notify
+             * @example 31
+             */
+            online_count: number;
+        };
+        MentionCandidate: {
+            user: components["schemas"]["ChannelMember"];
+            /** @description Whether the member is connected to the workspace right now */
+            online: boolean;
+            /**
+             * Format: date-time
+             * @description Time of the member's newest message in the channel. Omitted if they never posted there.
+             */
+            last_posted_at?: string;
+        };
+        MentionCandidatesResult: {
+            members: components["schemas"]["MentionCandidate"][];
+            groups: components["schemas"]["UserGroup"][];
+        };
+        /**
+         * @description Who may use @channel, @here and @everyone in a channel. `workspace_default` clears the channel's override.
+         * @enum {string}
+         */
+        ChannelMentionPermission: "everyone" | "members" | "admins" | "workspace_default";
+        SendMessageInput: {
+            /** @example Hello, world! */
+            content?: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id?: string;
+            /** @description IDs of uploaded attachments to include with this message */
+            attachment_ids?: string[];
+            /** @description When replying in a thread, also show the reply in the channel */
+            also_send_to_channel?: boolean;
+            /** @description Idempotency key chosen by the client, unique per message. Resending with the same key within 24 hours returns the original message. */
+            client_msg_id?: string;
+            /** @description Return 202 as soon as the message is stored, and finish mention parsing, attachment linking and notifications in the background. A `message.finalized` event tells the sender when it is done. */
+            async?: boolean;
+        };
+        SendMessageAck: {
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            message_id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            client_msg_id?: string;
+        };
+        ListMessagesInput: {
+            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+            cursor?: string;
+            limit?: number;
+            direction?: components["schemas"]["MessageListDirection"];
+        };
+        /**
+         * @description Which way to page from the cursor. `around` returns messages on both sides of it.
+         * @enum {string}
+         */
+        MessageListDirection: "before" | "after" | "around";
+        ListMessagesByAuthorInput: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /**
+             * Format: date-time
+             * @description Only include messages created at or after this time
+             */
+            since?: string;
+            /**
+             * Format: date-time
+             * @description Only include messages created before this time
+             */
+            until?: string;
+            cursor?: string;
+            limit?: number;
+        };
+        ReorderWorkspacesInput: {
+            /** @description Ordered list of workspace IDs representing the new order */
+            workspace_ids: string[];
+        };
+        ScheduledMessage: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
+            channel_id: string;
+            /** @example general */
+            channel_name?: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id?: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example Hello, world! */
+            content: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id?: string;
+            also_send_to_channel?: boolean;
+            attachment_ids?: string[];
+            /** Format: date-time */
+            scheduled_for: string;
+            /**
+             * @example In a meeting
+             * @enum {string}
+             */
+            status?: "pending" | "sending" | "failed";
+            last_error?: string;
+            /** Format: date-time */
+            created_at: string;
+            /** Format: date-time */
+            updated_at: string;
+        };
+        ScheduleMessageInput: {
+            /** @example Hello, world! */
+            content: string;
+            /** Format: date-time */
+            scheduled_for: string;
+            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
+            thread_parent_id?: string;
+            also_send_to_channel?: boolean;
+            attachment_ids?: string[];
+        };
+        UpdateScheduledMessageInput: {
+            /** @example Hello, world! */
+            content?: string;
+            /** Format: date-time */
+            scheduled_for?: string;
+            attachment_ids?: string[];
+        };
+        BanUserInput: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            user_id: string;
+            /** @example Repeated spam */
+            reason?: string;
+            /** @default false */
+            hide_messages: boolean;
+            /**
+             * @description Hours until ban expires. Omit for permanent.
+             * @example 24
+             */
+            duration_hours?: number;
+        };
+        Ban: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
             user_id: string;
+            banned_by: string;
+            /** @example Repeated spam */
+            reason?: string;
+            hide_messages: boolean;
+            /** Format: date-time */
+            expires_at?: string;
+            /** Format: date-time */
+            created_at: string;
+        };
+        BanWithUser: components["schemas"]["Ban"] & {
+            /** @example Alice Chen */
+            user_display_name?: string;
+            /** @example alice@example.com */
+            user_email?: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            user_avatar_url?: string;
+            /** @example Bob Martinez */
+            banned_by_name?: string;
+        };
+        BlockWithUser: {
+            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
+            workspace_id: string;
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            blocker_id: string;
+            /** @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ */
+            blocked_id: string;
+            /** Format: date-time */
+            created_at: string;
+            /** @example Alice Chen */
+            display_name?: string;
+            /** @example alice@example.com */
+            email?: string;
+            /** @example /files/01JQ3KMT6B/download?sig=abc */
+            avatar_url?: string;
+        };
+        ModerationLogEntryWithActor: {
+            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+            id: string;
             /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
             workspace_id: string;
-            role: components["schemas"]["WorkspaceRole"];
-            display_name_override?: string;
+            /** @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ */
+            actor_id: string;
+            /** @example ban_user */
+            action: string;
+            /** @example user */
+            target_type: string;
+            /** @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ */
+            target_id: string;
+            metadata?: {
+                [key: string]: unknown;
+            };
             /** Format: date-time */
             created_at: string;
-            /** Format: date-time */
-            updated_at: string;
+            /** @example Bob Martinez */
+            actor_display_name?: string;
+            actor_avatar_url?: string;
+            /** @example Carol Williams */
+            target_display_name?: string;
+        };
+    };
+    responses: {
+        /** @description Bad request */
+        BadRequest: {
+            headers: {
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "VALIDATION_ERROR",
+                 *         "message": "Invalid request parameters"
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+        /** @description Unauthorized */
+        Unauthorized: {
+            headers: {
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "NOT_AUTHENTICATED",
+                 *         "message": "Not authenticated"
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+        /** @description Not found */
+        NotFound: {
+            headers: {
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "NOT_FOUND",
+                 *         "message": "Resource not found"
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+        /** @description Forbidden */
+        Forbidden: {
+            headers: {
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "PERMISSION_DENIED",
+                 *         "message": "You do not have permission to perform this action"
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+        /** @description Rate limit exceeded */
+        TooManyRequests: {
+            headers: {
+                /** @description Seconds until the rate limit window resets */
+                "Retry-After"?: number;
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "RATE_LIMITED",
+                 *         "message": "Too many requests. Try again in 30 seconds."
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+        /** @description The workspace or server is in read-only mode */
+        ReadOnly: {
+            headers: {
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "READ_ONLY",
+                 *         "message": "Moving to a new server, back in 15 minutes"
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+        /** @description Conflict with current resource state */
+        Conflict: {
+            headers: {
+                [name: string]: unknown;
+            };
+            content: {
+                /**
+                 * @example {
+                 *       "error": {
+                 *         "code": "CONFLICT",
+                 *         "message": "Resource already exists"
+                 *       }
+                 *     }
+                 */
+                "application/json": components["schemas"]["ApiErrorResponse"];
+            };
+        };
+    };
+    parameters: {
+        /** @description Workspace ID */
+        workspaceId: string;
+        /** @description Channel ID */
+        channelId: string;
+        /** @description Message ID */
+        messageId: string;
+        /** @description Call ID */
+        callId: string;
+        /**
+         * @description Comma-separated message properties to return instead of full messages, for lightweight clients. `id` is always included.
+         * @example id,content,user_id,created_at
+         */
+        messageFields?: string;
+    };
+    requestBodies: never;
+    headers: never;
+    pathItems: never;
+}
+export type $defs = Record<string, never>;
+export interface operations {
+    getServerInfo: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Server information */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ServerInfo"];
+                };
+            };
+        };
+    };
+    register: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["RegisterInput"];
+            };
+        };
+        responses: {
+            /** @description User registered successfully */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["AuthResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+        };
+    };
+    login: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["LoginInput"];
+            };
+        };
+        responses: {
+            /** @description User logged in successfully */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["AuthResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    logout: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description User logged out successfully */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+        };
+    };
+    refreshToken: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["RefreshInput"];
+            };
+        };
+        responses: {
+            /** @description Tokens rotated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["TokenResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    forgotPassword: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /**
+                     * Format: email
+                     * @example alice@example.com
+                     */
+                    email: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Password reset email sent */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        success: boolean;
+                        message: string;
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+        };
+    };
+    resetPassword: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ */
+                    token: string;
+                    /** @example newsecurepassword456 */
+                    new_password: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Password reset successfully */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+        };
+    };
+    verifyEmail: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ */
+                    token: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Email verified successfully */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+        };
+    };
+    resendVerification: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Verification email sent */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    getMe: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Current user information */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MeResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    registerDeviceToken: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["RegisterDeviceTokenRequest"];
+            };
+        };
+        responses: {
+            /** @description Device token registered */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["RegisterDeviceTokenResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    unregisterDeviceToken: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description The device token record ID */
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Device token removed */
+            204: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    createWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateWorkspaceInput"];
+            };
+        };
+        responses: {
+            /** @description Workspace created */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspace: components["schemas"]["Workspace"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    getWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Workspace details */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspace: components["schemas"]["Workspace"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    updateWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpdateWorkspaceInput"];
+            };
+        };
+        responses: {
+            /** @description Workspace updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspace: components["schemas"]["Workspace"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    setWorkspaceReadOnly: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    read_only: boolean;
+                    /** @example Moving to a new server, back in 15 minutes */
+                    message?: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Read-only mode updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspace: components["schemas"]["Workspace"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    getWorkspaceStorage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Storage usage */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceStorage"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    listWorkspaceFeatures: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Feature flags */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        features: components["schemas"]["WorkspaceFeature"][];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    setWorkspaceFeature: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+                /** @description Feature flag key */
+                key: string;
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    enabled: boolean;
+                };
+            };
+        };
+        responses: {
+            /** @description Feature flag updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        feature: components["schemas"]["WorkspaceFeature"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    resetWorkspaceFeature: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+                /** @description Feature flag key */
+                key: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Feature flag reset */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        feature: components["schemas"]["WorkspaceFeature"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    createWorkspaceExport: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Export queued */
+            202: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceExport"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            409: components["responses"]["Conflict"];
+        };
+    };
+    getWorkspaceExport: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Export status */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceExport"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    downloadWorkspaceExport: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Export file */
+            200: {
+                headers: {
+                    "Content-Disposition"?: string;
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": string;
+                    "application/zip": string;
+                    "text/csv": string;
+                };
+            };
+            /** @description Redirect to a pre-signed storage URL */
+            302: {
+                headers: {
+                    Location?: string;
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    listWorkspaceMembers: {
+        parameters: {
+            query?: {
+                /** @description The next_cursor from the previous page */
+                cursor?: string;
+                limit?: number;
+                /** @description Filter by display name or email */
+                q?: string;
+                role?: components["schemas"]["WorkspaceRole"];
+                sort?: components["schemas"]["WorkspaceMemberSort"];
+                include_deactivated?: boolean;
+            };
+            header?: {
+                "If-None-Match"?: string;
+            };
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description List of members */
+            200: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceMemberListResult"];
+                };
+            };
+            /** @description Members unchanged since the given ETag */
+            304: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    getWorkspaceNotificationSettings: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Notification settings */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        preferences: components["schemas"]["NotificationPreferences"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    updateWorkspaceNotificationSettings: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["NotificationPreferences"];
+            };
+        };
+        responses: {
+            /** @description Settings updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        preferences: components["schemas"]["NotificationPreferences"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    resetWorkspaceNotificationSettings: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Settings reset */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        preferences: components["schemas"]["NotificationPreferences"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    snoozeWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["SnoozeRequest"];
+            };
+        };
+        responses: {
+            /** @description Notifications snoozed */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        /** Format: date-time */
+                        snoozed_until: string;
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    unsnoozeWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Snooze ended */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    countWorkspaceMembers: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Member count */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceMemberCount"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    removeWorkspaceMember: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                    user_id: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Member removed */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    deactivateMember: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                    user_id: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Account deactivated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    reactivateMember: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                    user_id: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Account reactivated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    leaveWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Successfully left workspace */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    updateWorkspaceMemberRole: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                    user_id: string;
+                    role: components["schemas"]["WorkspaceRole"];
+                };
+            };
+        };
+        responses: {
+            /** @description Role updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    getMemberProfile: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+                /** @description User ID */
+                userId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Member profile */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MemberProfile"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    createWorkspaceInvite: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateInviteInput"];
+            };
+        };
+        responses: {
+            /** @description Invite created */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        invite: components["schemas"]["Invite"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    getWorkspaceSignup: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Open signup details */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceSignup"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    joinWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Joined */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspace: components["schemas"]["Workspace"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            429: components["responses"]["TooManyRequests"];
+        };
+    };
+    listWorkspaceInvites: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Invites */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        invites: components["schemas"]["Invite"][];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    revokeInvite: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Invite revoked */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    getWorkspaceNotifications: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Notification summaries per workspace */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspaces: components["schemas"]["WorkspaceNotificationSummary"][];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    reorderWorkspaces: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["ReorderWorkspacesInput"];
+            };
+        };
+        responses: {
+            /** @description Workspaces reordered */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    acceptInvite: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                code: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Invite accepted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        workspace: components["schemas"]["Workspace"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    createChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateChannelInput"];
+            };
+        };
+        responses: {
+            /** @description Channel created */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    listChannels: {
+        parameters: {
+            query?: never;
+            header?: {
+                "If-None-Match"?: string;
+            };
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description List of channels */
+            200: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelListResult"];
+                };
+            };
+            /** @description Channel list unchanged since the given ETag */
+            304: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    listArchivedChannels: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Archived channels */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channels: components["schemas"]["Channel"][];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    browseChannels: {
+        parameters: {
+            query?: {
+                /** @description Filter by channel name or description. A leading # is ignored. */
+                q?: string;
+                sort?: components["schemas"]["ChannelDirectorySort"];
+                limit?: number;
+                offset?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description A page of public channels */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelDirectoryResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    getUnreadCounts: {
+        parameters: {
+            query?: never;
+            header?: {
+                "If-None-Match"?: string;
+            };
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Unread counts by channel */
+            200: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["UnreadCountsResult"];
+                };
+            };
+            /** @description Counts unchanged since the given ETag */
+            304: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    syncWorkspace: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["SyncInput"];
+            };
+        };
+        responses: {
+            /** @description Changes since the given messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SyncResult"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    createDM: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateDMInput"];
+            };
+        };
+        responses: {
+            /** @description DM channel */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    updateChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpdateChannelInput"];
+            };
+        };
+        responses: {
+            /** @description Channel updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                        /** @description Members added by backfill_members */
+                        added_member_count?: number;
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    setChannelTopic: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example Q3 launch: ship date Friday */
+                    topic: string;
+                };
+            };
+        };
+        responses: {
+            /** @description Topic updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    convertGroupDMToChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["ConvertGroupDMInput"];
+            };
+        };
+        responses: {
+            /** @description Channel converted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    createChannelExport: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateChannelExportInput"];
+            };
+        };
+        responses: {
+            /** @description Export queued */
+            202: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceExport"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
+        };
+    };
+    archiveChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Channel archived */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    unarchiveChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Channel unarchived */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    updateChannelRetention: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpdateChannelRetentionInput"];
+            };
+        };
+        responses: {
+            /** @description Retention updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        channel: components["schemas"]["Channel"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    previewChannelRetention: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: {
+            content: {
+                "application/json": components["schemas"]["PreviewChannelRetentionInput"];
+            };
+        };
+        responses: {
+            /** @description Retention preview */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["RetentionPreview"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    previewChannelMention: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Mention preview */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MentionPreview"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    listMentionCandidates: {
+        parameters: {
+            query?: {
+                /** @description Prefix typed after the @ */
+                q?: string;
+                /** @description Most members and most groups to return */
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Mention candidates */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MentionCandidatesResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    focusChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["FocusChannelInput"];
+            };
+        };
+        responses: {
+            /** @description Current viewers of the channel */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelViewers"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    listChannelViewers: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Current viewers of the channel */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelViewers"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    getChannelActivity: {
+        parameters: {
+            query?: {
+                /** @description Number of days to count, including today */
+                days?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Channel activity */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelActivity"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    addChannelMember: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                    user_id: string;
+                    role?: components["schemas"]["ChannelRole"];
+                };
+            };
+        };
+        responses: {
+            /** @description Member added */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    bulkAddChannelMembers: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["BulkAddChannelMembersInput"];
+            };
+        };
+        responses: {
+            /** @description Members added */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelMembersUpdated"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    listChannelMembers: {
+        parameters: {
+            query?: {
+                /** @description The next_cursor from the previous page */
+                cursor?: string;
+                limit?: number;
+                /** @description Filter by display name or email */
+                q?: string;
+                role?: components["schemas"]["ChannelRole"];
+            };
+            header?: {
+                "If-None-Match"?: string;
+            };
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description List of members */
+            200: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelMemberListResult"];
+                };
+            };
+            /** @description Members unchanged since the given ETag */
+            304: {
+                headers: {
+                    ETag?: string;
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    joinChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Joined channel */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    leaveChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Left channel */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    starChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Channel starred */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    unstarChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        WorkspaceMemberWithUser: components["schemas"]["WorkspaceMembership"] & {
-            /**
-             * Format: email
-             * @example alice@example.com
-             */
-            email: string;
-            /** @example Alice Chen */
-            display_name: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            gravatar_url?: string;
-            /** @description Whether the user is currently banned from the workspace */
-            is_banned?: boolean;
+        requestBody?: never;
+        responses: {
+            /** @description Channel unstarred */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        /** @enum {string} */
-        WorkspaceRole: "owner" | "admin" | "member" | "guest";
-        Invite: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example abc123def */
-            code: string;
-            /**
-             * Format: email
-             * @example newuser@example.com
-             */
-            invited_email?: string;
-            role: components["schemas"]["WorkspaceRole"];
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            created_by?: string;
-            /** @example 25 */
-            max_uses?: number;
-            /** @example 5 */
-            use_count: number;
-            /** Format: date-time */
-            expires_at?: string;
-            /** Format: date-time */
-            created_at: string;
+    };
+    markChannelRead: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        Channel: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example general */
-            name: string;
-            description?: string;
-            type: components["schemas"]["ChannelType"];
-            /** @description Whether this is the default channel (like */
-            is_default: boolean;
-            /** @example hash_abc123 */
-            dm_participant_hash?: string;
-            /** Format: date-time */
-            archived_at?: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            created_by?: string;
-            /** Format: date-time */
-            created_at: string;
-            /** Format: date-time */
-            updated_at: string;
+        requestBody?: {
+            content: {
+                "application/json": {
+                    /**
+                     * @description Message ID to mark as last read (defaults to latest message)
+                     * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
+                     */
+                    message_id?: string;
+                    /**
+                     * @description Also return how many messages this marked read and which message was the first unread one before it
+                     * @default false
+                     */
+                    include_counts?: boolean;
+                };
+            };
         };
-        ChannelWithMembership: components["schemas"]["Channel"] & {
-            channel_role?: components["schemas"]["ChannelRole"];
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            last_read_message_id?: string;
-            /** @example 12 */
-            unread_count: number;
-            /** @example 3 */
-            notification_count: number;
-            is_starred: boolean;
-            /** @description For DM channels, the other participants (excluding current user) */
-            dm_participants?: components["schemas"]["ChannelMember"][];
+        responses: {
+            /** @description Channel marked as read */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MarkReadResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        /** @enum {string} */
-        ChannelType: "public" | "private" | "dm" | "group_dm";
-        /** @enum {string} */
-        ChannelRole: "admin" | "poster" | "viewer";
-        ChannelMember: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /**
-             * Format: email
-             * @example alice@example.com
-             */
-            email: string;
-            /** @example Alice Chen */
-            display_name: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            gravatar_url?: string;
-            channel_role?: components["schemas"]["ChannelRole"];
+    };
+    getChannelReadState: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Read state */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ChannelReadState"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    getChannelNotifications: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Notification preferences */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        preferences: components["schemas"]["NotificationPreferences"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    updateChannelNotifications: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["NotificationPreferences"];
+            };
+        };
+        responses: {
+            /** @description Preferences updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        preferences: components["schemas"]["NotificationPreferences"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    resetChannelNotifications: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Preferences reset */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        preferences: components["schemas"]["NotificationPreferences"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    snoozeChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        /** @enum {string} */
-        MessageType: "user" | "system";
-        /** @enum {string} */
-        SystemEventType: "user_joined" | "user_left" | "user_added" | "user_converted_channel" | "channel_renamed" | "channel_visibility_changed" | "channel_description_updated" | "message_pinned" | "message_unpinned";
-        SystemEventData: {
-            event_type: components["schemas"]["SystemEventType"];
-            /**
-             * @description The user who joined/left/was added
-             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
-             */
-            user_id: string;
-            /**
-             * @description Display name of the user at the time of the event
-             * @example Alice Chen
-             */
-            user_display_name: string;
-            /**
-             * @description Name of the channel
-             * @example general
-             */
-            channel_name: string;
-            /**
-             * @description The user who performed the action (for user_added)
-             * @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ
-             */
-            actor_id?: string;
-            /**
-             * @description Display name of the actor
-             * @example Bob Martinez
-             */
-            actor_display_name?: string;
-            /**
-             * @description Previous channel name (for rename events)
-             * @example old-channel-name
-             */
-            old_channel_name?: string;
-            /**
-             * @description New channel type (for visibility change events)
-             * @example public
-             */
-            channel_type?: string;
-            /**
-             * @description Referenced message ID (for pin/unpin events)
-             * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
-             */
-            message_id?: string;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["SnoozeRequest"];
+            };
         };
-        Message: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id?: string;
-            /** @example Hello, world! */
-            content: string;
-            type?: components["schemas"]["MessageType"];
-            system_event?: components["schemas"]["SystemEventData"];
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            thread_parent_id?: string;
-            /** @example 3 */
-            reply_count: number;
-            /** Format: date-time */
-            last_reply_at?: string;
-            /** Format: date-time */
-            edited_at?: string;
-            /** Format: date-time */
-            deleted_at?: string;
-            /** Format: date-time */
-            created_at: string;
-            /** Format: date-time */
-            updated_at: string;
-            also_send_to_channel?: boolean;
-            /** Format: date-time */
-            pinned_at?: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            pinned_by?: string;
+        responses: {
+            /** @description Notifications snoozed */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        /** Format: date-time */
+                        snoozed_until: string;
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        MessageWithUser: components["schemas"]["Message"] & {
-            /** @example Alice Chen */
-            user_display_name?: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            user_avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            user_gravatar_url?: string;
-            reactions?: components["schemas"]["Reaction"][];
-            thread_participants?: components["schemas"]["ThreadParticipant"][];
-            attachments?: components["schemas"]["Attachment"][];
-            link_preview?: components["schemas"]["LinkPreview"];
+    };
+    unsnoozeChannel: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        ThreadParticipant: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example Alice Chen */
-            display_name?: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            gravatar_url?: string;
+        requestBody?: never;
+        responses: {
+            /** @description Snooze ended */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        Attachment: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example report.pdf */
-            filename: string;
-            /** @example application/pdf */
-            content_type: string;
-            /**
-             * Format: int64
-             * @example 1048576
-             */
-            size_bytes: number;
-            /**
-             * @description Download URL for the attachment
-             * @example /files/01JQ3KMT6B/download?sig=abc
-             */
-            url: string;
-            /** Format: date-time */
-            created_at: string;
+    };
+    markAllChannelsRead: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        LinkPreview: {
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            url: string;
-            /**
-             * @default external
-             * @enum {string}
-             */
-            type: "external" | "message";
-            /** @example Example Page */
-            title?: string;
-            description?: string;
-            /** @example https://example.com/image.png */
-            image_url?: string;
-            /** @example example.com */
-            site_name?: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            linked_message_id?: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            linked_channel_id?: string;
-            /** @example random */
-            linked_channel_name?: string;
-            /** @example public */
-            linked_channel_type?: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            message_author_id?: string;
-            /** @example Alice Chen */
-            message_author_name?: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            message_author_avatar_url?: string;
-            /** @example https://www.gravatar.com/avatar/abc123?d=mp */
-            message_author_gravatar_url?: string;
-            /** @example Check out this link! */
-            message_content?: string;
-            /** Format: date-time */
-            message_created_at?: string;
+        requestBody?: never;
+        responses: {
+            /** @description All channels marked as read */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        Reaction: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            message_id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example 👍 */
-            emoji: string;
-            /** Format: date-time */
-            created_at: string;
+    };
+    getAllUnreads: {
+        parameters: {
+            query?: {
+                /** @description Cursor from a previous page's next_cursor. */
+                cursor?: string;
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description List of unread messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["UnreadMessagesResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+        };
+    };
+    listAllUnreads: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        ReactionSummary: {
-            /** @example 👍 */
-            emoji: string;
-            /** @example 5 */
-            count: number;
-            user_ids: string[];
+        requestBody?: {
+            content: {
+                "application/json": {
+                    /** @default 50 */
+                    limit?: number;
+                    /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+                    cursor?: string;
+                };
+            };
         };
-        MessageListResult: {
-            messages: components["schemas"]["MessageWithUser"][];
-            has_more: boolean;
-            has_newer?: boolean;
-            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-            next_cursor?: string;
+        responses: {
+            /** @description List of unread messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["UnreadMessagesResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        UnreadMessage: components["schemas"]["MessageWithUser"] & {
-            /** @example general */
-            channel_name: string;
-            channel_type: components["schemas"]["ChannelType"];
+    };
+    searchMessages: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        UnreadMessagesResult: {
-            messages: components["schemas"]["UnreadMessage"][];
-            has_more: boolean;
-            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-            next_cursor?: string;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["SearchMessagesInput"];
+            };
         };
-        SearchMessagesInput: {
-            /** @example search term */
-            query: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id?: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id?: string;
-            /** Format: date-time */
-            before?: string;
-            /** Format: date-time */
-            after?: string;
-            /** @default 20 */
-            limit: number;
-            /** @default 0 */
-            offset: number;
+        responses: {
+            /** @description Search results */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SearchMessagesResult"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        SearchMessage: components["schemas"]["MessageWithUser"] & {
-            /** @example general */
-            channel_name: string;
-            channel_type: components["schemas"]["ChannelType"];
+    };
+    quickSwitch: {
+        parameters: {
+            query?: {
+                /** @description Name to search for. A leading # or @ is ignored. */
+                q?: string;
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        SearchMessagesResult: {
-            messages: components["schemas"]["SearchMessage"][];
-            /** @example 42 */
-            total_count: number;
-            has_more: boolean;
-            /** @example search term */
-            query: string;
+        requestBody?: never;
+        responses: {
+            /** @description Ranked destinations */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["QuickSwitchResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        ThreadMessage: components["schemas"]["MessageWithUser"] & {
-            /** @example general */
-            channel_name: string;
-            channel_type: components["schemas"]["ChannelType"];
-            has_new_replies: boolean;
+    };
+    listUserThreads: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        ThreadListResult: {
-            threads: components["schemas"]["ThreadMessage"][];
-            has_more: boolean;
-            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-            next_cursor?: string;
-            /** @example 2 */
-            unread_thread_count: number;
+        requestBody?: {
+            content: {
+                "application/json": {
+                    /** @default 20 */
+                    limit?: number;
+                    /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+                    cursor?: string;
+                };
+            };
         };
-        ApiError: {
-            /** @example VALIDATION_ERROR */
-            code: string;
-            /** @example Invalid request parameters */
-            message: string;
+        responses: {
+            /** @description List of user threads */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ThreadListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        ApiErrorResponse: {
-            error: components["schemas"]["ApiError"];
+    };
+    listActivity: {
+        parameters: {
+            query?: {
+                /** @description Only return unread activity. */
+                unread_only?: boolean;
+                cursor?: string;
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        CustomEmoji: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example general */
-            name: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            created_by: string;
-            /** @example application/pdf */
-            content_type: string;
-            /**
-             * Format: int64
-             * @example 1048576
-             */
-            size_bytes: number;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            url: string;
-            /** Format: date-time */
-            created_at: string;
+        requestBody?: never;
+        responses: {
+            /** @description Activity for the current user */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ActivityListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        SignedUrl: {
-            /** @example 01JQ3KMT6BMRXP0WCDG9HQSYNF */
-            file_id: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            url: string;
-            /** Format: date-time */
-            expires_at: string;
+    };
+    markActivityRead: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        ServerInfo: {
-            /** @example 0.2.0 */
-            version: string;
-            email_enabled?: boolean;
-            files_enabled?: boolean;
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @description Activity to mark as read (defaults to all) */
+                    activity_ids?: string[];
+                };
+            };
         };
-        SuccessResponse: {
-            success: boolean;
+        responses: {
+            /** @description Activity marked as read */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        /** @example 0 */
+                        unread_count: number;
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
+    getMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        MarkReadResponse: {
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            last_read_message_id: string;
+        requestBody?: never;
+        responses: {
+            /** @description Message details */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        ChannelReadEventData: {
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            last_read_message_id: string;
+    };
+    getMessagePermalink: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        /** @enum {string} */
-        SSEEventType: "connected" | "heartbeat" | "message.new" | "message.updated" | "message.deleted" | "reaction.added" | "reaction.removed" | "channel.created" | "channel.updated" | "channel.archived" | "channel.member_added" | "channel.member_removed" | "channel.read" | "typing.start" | "typing.stop" | "presence.changed" | "presence.initial" | "notification" | "emoji.created" | "emoji.deleted" | "message.pinned" | "message.unpinned" | "member.banned" | "member.unbanned" | "member.left" | "member.role_changed" | "workspace.updated" | "channels.invalidate" | "scheduled_message.created" | "scheduled_message.updated" | "scheduled_message.deleted" | "scheduled_message.sent" | "scheduled_message.failed";
-        SSEEvent: components["schemas"]["SSEEventConnected"] | components["schemas"]["SSEEventHeartbeat"] | components["schemas"]["SSEEventMessageNew"] | components["schemas"]["SSEEventMessageUpdated"] | components["schemas"]["SSEEventMessageDeleted"] | components["schemas"]["SSEEventReactionAdded"] | components["schemas"]["SSEEventReactionRemoved"] | components["schemas"]["SSEEventChannelCreated"] | components["schemas"]["SSEEventChannelUpdated"] | components["schemas"]["SSEEventChannelArchived"] | components["schemas"]["SSEEventChannelMemberAdded"] | components["schemas"]["SSEEventChannelMemberRemoved"] | components["schemas"]["SSEEventChannelRead"] | components["schemas"]["SSEEventTypingStart"] | components["schemas"]["SSEEventTypingStop"] | components["schemas"]["SSEEventPresenceChanged"] | components["schemas"]["SSEEventPresenceInitial"] | components["schemas"]["SSEEventNotification"] | components["schemas"]["SSEEventEmojiCreated"] | components["schemas"]["SSEEventEmojiDeleted"] | components["schemas"]["SSEEventScheduledMessageCreated"] | components["schemas"]["SSEEventScheduledMessageUpdated"] | components["schemas"]["SSEEventScheduledMessageDeleted"] | components["schemas"]["SSEEventScheduledMessageSent"] | components["schemas"]["SSEEventMessagePinned"] | components["schemas"]["SSEEventMessageUnpinned"] | components["schemas"]["SSEEventMemberBanned"] | components["schemas"]["SSEEventMemberUnbanned"] | components["schemas"]["SSEEventMemberLeft"] | components["schemas"]["SSEEventMemberRoleChanged"] | components["schemas"]["SSEEventWorkspaceUpdated"] | components["schemas"]["SSEEventScheduledMessageFailed"] | components["schemas"]["SSEEventChannelsInvalidate"];
-        SSEEventConnected: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "connected";
-            data: components["schemas"]["ConnectedData"];
+        requestBody?: never;
+        responses: {
+            /** @description Message permalink */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessagePermalink"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventHeartbeat: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "heartbeat";
-            data: components["schemas"]["HeartbeatData"];
+    };
+    sendMessage: {
+        parameters: {
+            query?: never;
+            header?: {
+                /** @description Same as client_msg_id in the body */
+                "Idempotency-Key"?: string;
+            };
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        SSEEventMessageNew: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "message.new";
-            data: components["schemas"]["MessageWithUser"];
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["SendMessageInput"];
+            };
         };
-        SSEEventMessageUpdated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "message.updated";
-            data: components["schemas"]["MessageWithUser"];
+        responses: {
+            /** @description Message sent */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
+                };
+            };
+            /** @description Message stored and still being processed (async sends) */
+            202: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SendMessageAck"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
+            /** @description Slow mode is on and the sender must wait before posting again */
+            429: {
+                headers: {
+                    /** @description Seconds until the sender may post again */
+                    "Retry-After"?: number;
+                    [name: string]: unknown;
+                };
+                content: {
+                    /**
+                     * @example {
+                     *       "error": {
+                     *         "code": "SLOW_MODE",
+                     *         "message": "Slow mode is on. Try again in 12 seconds."
+                     *       },
+                     *       "retry_after": 12
+                     *     }
+                     */
+                    "application/json": components["schemas"]["SlowModeErrorResponse"];
+                };
+            };
+            503: components["responses"]["ReadOnly"];
         };
-        SSEEventMessageDeleted: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "message.deleted";
-            data: components["schemas"]["MessageDeletedData"];
+    };
+    getChannelMessages: {
+        parameters: {
+            query?: {
+                /** @description Cursor from a previous page's next_cursor. */
+                cursor?: string;
+                limit?: number;
+                direction?: components["schemas"]["MessageListDirection"];
+                /**
+                 * @description Comma-separated message properties to return instead of full messages, for lightweight clients. `id` is always included.
+                 * @example id,content,user_id,created_at
+                 */
+                fields?: components["parameters"]["messageFields"];
+            };
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        SSEEventReactionAdded: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "reaction.added";
-            data: components["schemas"]["Reaction"];
+        requestBody?: never;
+        responses: {
+            /** @description List of messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessageListResult"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventReactionRemoved: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "reaction.removed";
-            data: components["schemas"]["ReactionRemovedData"];
+    };
+    listMessages: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        SSEEventChannelCreated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channel.created";
-            data: components["schemas"]["Channel"];
+        requestBody?: {
+            content: {
+                "application/json": components["schemas"]["ListMessagesInput"];
+            };
         };
-        SSEEventChannelUpdated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channel.updated";
-            data: components["schemas"]["Channel"];
+        responses: {
+            /** @description List of messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessageListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventChannelArchived: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channel.archived";
-            data: components["schemas"]["Channel"];
+    };
+    listArchivedMessages: {
+        parameters: {
+            query?: {
+                /** @description Cursor from a previous page's next_cursor, or a message ID to load around. */
+                cursor?: string;
+                limit?: number;
+                direction?: components["schemas"]["MessageListDirection"];
+                /** @description Load the page around this time. Ignored when cursor is set. */
+                at?: string;
+                /** @description List the replies of this archived thread instead of the channel's messages. */
+                thread_id?: string;
+            };
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description List of archived messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessageListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventChannelMemberAdded: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channel.member_added";
-            data: components["schemas"]["ChannelMemberData"];
+    };
+    listMessagesByAuthor: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        SSEEventChannelMemberRemoved: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channel.member_removed";
-            data: components["schemas"]["ChannelMemberData"];
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["ListMessagesByAuthorInput"];
+            };
         };
-        SSEEventChannelRead: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channel.read";
-            data: components["schemas"]["ChannelReadEventData"];
+        responses: {
+            /** @description The user's messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessageListResult"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventTypingStart: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "typing.start";
-            data: components["schemas"]["TypingEventData"];
+    };
+    updateMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventTypingStop: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "typing.stop";
-            data: components["schemas"]["TypingEventData"];
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example Hello, world! */
+                    content: string;
+                };
+            };
         };
-        SSEEventPresenceChanged: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "presence.changed";
-            data: components["schemas"]["PresenceData"];
+        responses: {
+            /** @description Message updated */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        SSEEventPresenceInitial: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "presence.initial";
-            data: components["schemas"]["PresenceInitialData"];
+    };
+    deleteMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        PresenceInitialData: {
-            /** @description List of user IDs currently online in this workspace */
-            online_user_ids: string[];
+        requestBody?: never;
+        responses: {
+            /** @description Message deleted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventNotification: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "notification";
-            data: components["schemas"]["NotificationData"];
+    };
+    restoreMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventEmojiCreated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "emoji.created";
-            data: components["schemas"]["CustomEmoji"];
+        requestBody?: never;
+        responses: {
+            /** @description Message restored */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventEmojiDeleted: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "emoji.deleted";
-            data: components["schemas"]["EmojiDeletedData"];
+    };
+    deleteLinkPreview: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventScheduledMessageCreated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "scheduled_message.created";
-            data: components["schemas"]["ScheduledMessage"];
+        requestBody?: never;
+        responses: {
+            /** @description Link preview deleted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventScheduledMessageUpdated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "scheduled_message.updated";
-            data: components["schemas"]["ScheduledMessage"];
+    };
+    listMessageReactions: {
+        parameters: {
+            query?: {
+                /** @description Only list users who reacted with this emoji */
+                emoji?: string;
+                limit?: number;
+                /** @description Cursor from a previous page's next_cursor */
+                cursor?: string;
+            };
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Reactions */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ReactorListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        SSEEventScheduledMessageDeleted: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "scheduled_message.deleted";
-            data: components["schemas"]["ScheduledMessageDeletedData"];
+    };
+    addReaction: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventScheduledMessageSent: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "scheduled_message.sent";
-            data: components["schemas"]["ScheduledMessageSentData"];
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 👍 */
+                    emoji: string;
+                };
+            };
         };
-        SSEEventMessagePinned: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "message.pinned";
-            data: components["schemas"]["MessageWithUser"];
+        responses: {
+            /** @description Reaction added */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        reaction: components["schemas"]["Reaction"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        SSEEventMessageUnpinned: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "message.unpinned";
-            data: components["schemas"]["MessageWithUser"];
+    };
+    removeReaction: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventMemberBanned: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "member.banned";
-            data: components["schemas"]["WorkspaceMemberData"];
+        requestBody: {
+            content: {
+                "application/json": {
+                    /** @example 👍 */
+                    emoji: string;
+                };
+            };
         };
-        SSEEventMemberUnbanned: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "member.unbanned";
-            data: components["schemas"]["WorkspaceMemberData"];
+        responses: {
+            /** @description Reaction removed */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        SSEEventMemberLeft: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "member.left";
-            data: components["schemas"]["WorkspaceMemberData"];
+    };
+    batchReactions: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventMemberRoleChanged: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "member.role_changed";
-            data: components["schemas"]["MemberRoleChangedData"];
+        requestBody: {
+            content: {
+                "application/json": {
+                    operations: components["schemas"]["ReactionOperation"][];
+                };
+            };
         };
-        SSEEventWorkspaceUpdated: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "workspace.updated";
-            data: components["schemas"]["Workspace"];
+        responses: {
+            /** @description Reaction changes applied */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        added: components["schemas"]["Reaction"][];
+                        /** @description Emoji whose reaction was removed */
+                        removed: string[];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        SSEEventScheduledMessageFailed: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "scheduled_message.failed";
-            data: components["schemas"]["ScheduledMessageFailedData"];
+    };
+    markMessageUnread: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        SSEEventChannelsInvalidate: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id?: string;
-            /**
-             * @description discriminator enum property added by openapi-typescript
-             * @enum {string}
-             */
-            type: "channels.invalidate";
-            data: Record<string, never>;
+        requestBody?: never;
+        responses: {
+            /** @description Message marked as unread */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        ConnectedData: {
-            client_id: string;
+    };
+    markThreadRead: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        HeartbeatData: {
-            /** Format: int64 */
-            timestamp: number;
+        requestBody?: {
+            content: {
+                "application/json": {
+                    /**
+                     * @description ID of the last read reply (defaults to latest reply)
+                     * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
+                     */
+                    last_read_reply_id?: string;
+                };
+            };
         };
-        MessageDeletedData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            thread_parent_id?: string;
+        responses: {
+            /** @description Thread marked as read */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        ReactionRemovedData: {
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            message_id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example 👍 */
-            emoji: string;
+    };
+    getThreadReplies: {
+        parameters: {
+            query?: {
+                /** @description Cursor from a previous page's next_cursor. */
+                cursor?: string;
+                limit?: number;
+                direction?: components["schemas"]["MessageListDirection"];
+                /**
+                 * @description Comma-separated message properties to return instead of full messages, for lightweight clients. `id` is always included.
+                 * @example id,content,user_id,created_at
+                 */
+                fields?: components["parameters"]["messageFields"];
+            };
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        ChannelMemberData: {
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
+        requestBody?: never;
+        responses: {
+            /** @description Thread messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessageListResult"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        WorkspaceMemberData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
+    };
+    listThread: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        MemberRoleChangedData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            old_role: string;
-            new_role: string;
+        requestBody?: {
+            content: {
+                "application/json": components["schemas"]["ListMessagesInput"];
+            };
         };
-        EmojiDeletedData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example general */
-            name: string;
+        responses: {
+            /** @description Thread messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["MessageListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        ScheduledMessageDeletedData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
+    };
+    listThreadParticipants: {
+        parameters: {
+            query?: {
+                limit?: number;
+                /** @description Cursor from a previous page's next_cursor */
+                cursor?: string;
+            };
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        ScheduledMessageSentData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            message_id: string;
+        requestBody?: never;
+        responses: {
+            /** @description Thread participants */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ThreadParticipantListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        ScheduledMessageFailedData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            error: string;
+    };
+    getThreadSubscription: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        NotificationData: {
-            /** @enum {string} */
-            type: "mention" | "dm" | "channel" | "here" | "everyone" | "thread_reply";
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            message_id: string;
-            /** @example general */
-            channel_name?: string;
-            sender_name?: string;
-            preview?: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            thread_parent_id?: string;
+        requestBody?: never;
+        responses: {
+            /** @description Thread subscription status */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        status: components["schemas"]["ThreadSubscriptionStatus"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        /** @enum {string} */
-        ThreadSubscriptionStatus: "subscribed" | "unsubscribed" | "none";
-        /** @enum {string} */
-        NotifyLevel: "all" | "mentions" | "none";
-        NotificationPreferences: {
-            notify_level: components["schemas"]["NotifyLevel"];
-            email_enabled: boolean;
+    };
+    subscribeToThread: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        TypingEventData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example Alice Chen */
-            user_display_name?: string;
+        requestBody?: never;
+        responses: {
+            /** @description Subscribed to thread */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        status: components["schemas"]["ThreadSubscriptionStatus"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        /** @enum {string} */
-        PresenceStatus: "online" | "offline";
-        PresenceData: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            status: components["schemas"]["PresenceStatus"];
+    };
+    unsubscribeFromThread: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        RegisterInput: {
-            /**
-             * Format: email
-             * @example alice@example.com
-             */
-            email: string;
-            /** @example securepassword123 */
-            password: string;
-            /** @example Alice Chen */
-            display_name: string;
+        requestBody?: never;
+        responses: {
+            /** @description Unsubscribed from thread */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        status: components["schemas"]["ThreadSubscriptionStatus"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        LoginInput: {
-            /**
-             * Format: email
-             * @example alice@example.com
-             */
-            email: string;
-            /** @example securepassword123 */
-            password: string;
+    };
+    pinMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        AuthResponse: {
-            user: components["schemas"]["User"];
-            /** @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ */
-            token: string;
+        requestBody?: never;
+        responses: {
+            /** @description Message pinned */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        MeResponse: {
-            user: components["schemas"]["User"];
-            workspaces?: components["schemas"]["WorkspaceSummary"][];
+    };
+    ackMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        RegisterDeviceTokenRequest: {
-            /**
-             * @description The push notification token (FCM or APNs)
-             * @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ
-             */
-            token: string;
-            /**
-             * @description The push platform
-             * @enum {string}
-             */
-            platform: "fcm" | "apns";
-            /** @description A unique identifier for the device */
-            device_id: string;
+        requestBody?: never;
+        responses: {
+            /** @description Receipt recorded */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        RegisterDeviceTokenResponse: {
-            /**
-             * @description The device token record ID
-             * @example 01JQ3KMN7XFGY4P6WBR2SZTA9V
-             */
-            id: string;
+    };
+    unpinMessage: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Message ID */
+                id: components["parameters"]["messageId"];
+            };
+            cookie?: never;
         };
-        CreateWorkspaceInput: {
-            /** @example general */
-            name: string;
+        requestBody?: never;
+        responses: {
+            /** @description Message unpinned */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        UpdateWorkspaceInput: {
-            /** @example general */
-            name?: string;
-            /** @description Partial workspace settings to update. Only provided fields are changed. */
-            settings?: {
-                show_join_leave_messages?: boolean;
-                who_can_create_channels?: components["schemas"]["PermissionLevel"];
-                who_can_create_invites?: components["schemas"]["PermissionLevel"];
-                who_can_pin_messages?: components["schemas"]["PermissionLevel"];
-                who_can_manage_custom_emoji?: components["schemas"]["PermissionLevel"];
+    };
+    listPinnedMessages: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
             };
+            cookie?: never;
         };
-        CreateInviteInput: {
-            /**
-             * Format: email
-             * @example newuser@example.com
-             */
-            invited_email?: string;
-            role: components["schemas"]["WorkspaceRole"];
-            /** @example 25 */
-            max_uses?: number;
-            expires_in_hours?: number;
+        requestBody?: {
+            content: {
+                "application/json": {
+                    /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+                    cursor?: string;
+                    /** @default 50 */
+                    limit?: number;
+                };
+            };
         };
-        CreateDMInput: {
-            user_ids: string[];
+        responses: {
+            /** @description List of pinned messages */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        messages: components["schemas"]["MessageWithUser"][];
+                        has_more: boolean;
+                        /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
+                        next_cursor?: string;
+                    };
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        ConvertGroupDMInput: {
-            /** @example general */
-            name: string;
-            description?: string;
-            /**
-             * @default private
-             * @enum {string}
-             */
-            type: "public" | "private";
+    };
+    listChannelFiles: {
+        parameters: {
+            query?: {
+                type?: components["schemas"]["FileType"];
+                sort?: components["schemas"]["FileSort"];
+                limit?: number;
+                offset?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        CreateChannelInput: {
-            /** @example general */
-            name: string;
-            description?: string;
-            type: components["schemas"]["ChannelType"];
+        requestBody?: never;
+        responses: {
+            /** @description A page of files */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["FileListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
-        UpdateChannelInput: {
-            /** @example general */
-            name?: string;
-            description?: string;
-            type?: components["schemas"]["ChannelType"];
+    };
+    listWorkspaceFiles: {
+        parameters: {
+            query?: {
+                type?: components["schemas"]["FileType"];
+                sort?: components["schemas"]["FileSort"];
+                limit?: number;
+                offset?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
-        SendMessageInput: {
-            /** @example Hello, world! */
-            content?: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            thread_parent_id?: string;
-            /** @description IDs of uploaded attachments to include with this message */
-            attachment_ids?: string[];
-            /** @description When replying in a thread, also show the reply in the channel */
-            also_send_to_channel?: boolean;
+        requestBody?: never;
+        responses: {
+            /** @description A page of files */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["FileListResult"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
-        ListMessagesInput: {
-            /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-            cursor?: string;
-            limit?: number;
-            /** @enum {string} */
-            direction?: "before" | "after" | "around";
+    };
+    uploadFile: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        ReorderWorkspacesInput: {
-            /** @description Ordered list of workspace IDs representing the new order */
-            workspace_ids: string[];
+        requestBody: {
+            content: {
+                "multipart/form-data": {
+                    /** Format: binary */
+                    file: string;
+                };
+            };
         };
-        ScheduledMessage: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMQ8YNBC3DFHM6RWVS7AG */
-            channel_id: string;
-            /** @example general */
-            channel_name?: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id?: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example Hello, world! */
-            content: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            thread_parent_id?: string;
-            also_send_to_channel?: boolean;
-            attachment_ids?: string[];
-            /** Format: date-time */
-            scheduled_for: string;
-            /**
-             * @example In a meeting
-             * @enum {string}
-             */
-            status?: "pending" | "sending" | "failed";
-            last_error?: string;
-            /** Format: date-time */
-            created_at: string;
-            /** Format: date-time */
-            updated_at: string;
+        responses: {
+            /** @description File uploaded */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        file: {
+                            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                            id: string;
+                            /** @example report.pdf */
+                            filename: string;
+                            /** @example 1048576 */
+                            size: number;
+                            /** @example application/pdf */
+                            content_type: string;
+                            status: components["schemas"]["AttachmentStatus"];
+                        };
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        ScheduleMessageInput: {
-            /** @example Hello, world! */
-            content: string;
-            /** Format: date-time */
-            scheduled_for: string;
-            /** @example 01JQ3KMR5KVDW2TG9NHP0XEJBL */
-            thread_parent_id?: string;
-            also_send_to_channel?: boolean;
-            attachment_ids?: string[];
+    };
+    createUpload: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
+            };
+            cookie?: never;
         };
-        UpdateScheduledMessageInput: {
-            /** @example Hello, world! */
-            content?: string;
-            /** Format: date-time */
-            scheduled_for?: string;
-            attachment_ids?: string[];
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateUploadInput"];
+            };
         };
-        BanUserInput: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            /** @example Repeated spam */
-            reason?: string;
-            /** @default false */
-            hide_messages: boolean;
-            /**
-             * @description Hours until ban expires. Omit for permanent.
-             * @example 24
-             */
-            duration_hours?: number;
+        responses: {
+            /** @description Upload session created */
+            201: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["UploadSession"];
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        Ban: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            user_id: string;
-            banned_by: string;
-            /** @example Repeated spam */
-            reason?: string;
-            hide_messages: boolean;
-            /** Format: date-time */
-            expires_at?: string;
-            /** Format: date-time */
-            created_at: string;
+    };
+    getUpload: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Upload session ID */
+                id: string;
+            };
+            cookie?: never;
         };
-        BanWithUser: components["schemas"]["Ban"] & {
-            /** @example Alice Chen */
-            user_display_name?: string;
-            /** @example alice@example.com */
-            user_email?: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            user_avatar_url?: string;
-            /** @example Bob Martinez */
-            banned_by_name?: string;
+        requestBody?: never;
+        responses: {
+            /** @description Upload session */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["UploadSession"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
-        BlockWithUser: {
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            blocker_id: string;
-            /** @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ */
-            blocked_id: string;
-            /** Format: date-time */
-            created_at: string;
-            /** @example Alice Chen */
-            display_name?: string;
-            /** @example alice@example.com */
-            email?: string;
-            /** @example /files/01JQ3KMT6B/download?sig=abc */
-            avatar_url?: string;
+    };
+    cancelUpload: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Upload session ID */
+                id: string;
+            };
+            cookie?: never;
         };
-        ModerationLogEntryWithActor: {
-            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-            id: string;
-            /** @example 01JQ3KMP2RQHYJ5ZV8NMWCX4ET */
-            workspace_id: string;
-            /** @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ */
-            actor_id: string;
-            /** @example ban_user */
-            action: string;
-            /** @example user */
-            target_type: string;
-            /** @example 01JQ3KMS4WTVY6BN8FRCJD2HAQ */
-            target_id: string;
-            metadata?: {
-                [key: string]: unknown;
+        requestBody?: never;
+        responses: {
+            /** @description Upload cancelled */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
             };
-            /** Format: date-time */
-            created_at: string;
-            /** @example Bob Martinez */
-            actor_display_name?: string;
-            actor_avatar_url?: string;
-            /** @example Carol Williams */
-            target_display_name?: string;
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
     };
-    responses: {
-        /** @description Bad request */
-        BadRequest: {
-            headers: {
-                [name: string]: unknown;
+    appendUpload: {
+        parameters: {
+            query: {
+                /** @description Byte offset this chunk starts at */
+                offset: number;
+            };
+            header?: never;
+            path: {
+                /** @description Upload session ID */
+                id: string;
             };
+            cookie?: never;
+        };
+        requestBody: {
             content: {
-                /**
-                 * @example {
-                 *       "error": {
-                 *         "code": "VALIDATION_ERROR",
-                 *         "message": "Invalid request parameters"
-                 *       }
-                 *     }
-                 */
-                "application/json": components["schemas"]["ApiErrorResponse"];
+                "application/octet-stream": string;
             };
         };
-        /** @description Unauthorized */
-        Unauthorized: {
-            headers: {
-                [name: string]: unknown;
+        responses: {
+            /** @description Chunk stored */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["UploadSession"];
+                };
             };
-            content: {
-                /**
-                 * @example {
-                 *       "error": {
-                 *         "code": "NOT_AUTHENTICATED",
-                 *         "message": "Not authenticated"
-                 *       }
-                 *     }
-                 */
-                "application/json": components["schemas"]["ApiErrorResponse"];
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
+        };
+    };
+    completeUpload: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Upload session ID */
+                id: string;
             };
+            cookie?: never;
         };
-        /** @description Not found */
-        NotFound: {
-            headers: {
-                [name: string]: unknown;
-            };
-            content: {
-                /**
-                 * @example {
-                 *       "error": {
-                 *         "code": "NOT_FOUND",
-                 *         "message": "Resource not found"
-                 *       }
-                 *     }
-                 */
-                "application/json": components["schemas"]["ApiErrorResponse"];
+        requestBody?: never;
+        responses: {
+            /** @description File uploaded */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        file: {
+                            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                            id: string;
+                            /** @example report.pdf */
+                            filename: string;
+                            /** @example 1048576 */
+                            size: number;
+                            /** @example application/pdf */
+                            content_type: string;
+                            status: components["schemas"]["AttachmentStatus"];
+                        };
+                    };
+                };
             };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            503: components["responses"]["ReadOnly"];
         };
-        /** @description Forbidden */
-        Forbidden: {
-            headers: {
-                [name: string]: unknown;
+    };
+    downloadFile: {
+        parameters: {
+            query?: {
+                /** @description Unix timestamp when the signed URL expires */
+                expires?: number;
+                /** @description User ID for signed URL verification */
+                uid?: string;
+                /** @description HMAC-SHA256 signature for signed URL verification */
+                sig?: string;
             };
-            content: {
-                /**
-                 * @example {
-                 *       "error": {
-                 *         "code": "PERMISSION_DENIED",
-                 *         "message": "You do not have permission to perform this action"
-                 *       }
-                 *     }
-                 */
-                "application/json": components["schemas"]["ApiErrorResponse"];
+            header?: never;
+            path: {
+                id: string;
             };
+            cookie?: never;
         };
-        /** @description Conflict with current resource state */
-        Conflict: {
-            headers: {
-                [name: string]: unknown;
-            };
-            content: {
-                /**
-                 * @example {
-                 *       "error": {
-                 *         "code": "CONFLICT",
-                 *         "message": "Resource already exists"
-                 *       }
-                 *     }
-                 */
-                "application/json": components["schemas"]["ApiErrorResponse"];
+        requestBody?: never;
+        responses: {
+            /** @description File content */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/octet-stream": string;
+                };
             };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    parameters: {
-        /** @description Workspace ID */
-        workspaceId: string;
-        /** @description Channel ID */
-        channelId: string;
-        /** @description Message ID */
-        messageId: string;
-    };
-    requestBodies: never;
-    headers: never;
-    pathItems: never;
-}
-export type $defs = Record<string, never>;
-export interface operations {
-    getServerInfo: {
+    signFileUrl: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                id: string;
+            };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Server information */
+            /** @description Signed URL */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["ServerInfo"];
+                    "application/json": components["schemas"]["SignedUrl"];
                 };
             };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    register: {
+    signFileUrls: {
         parameters: {
             query?: never;
             header?: never;
@@ -3086,169 +11350,269 @@ export interface operations {
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["RegisterInput"];
+                "application/json": {
+                    file_ids: string[];
+                };
             };
         };
         responses: {
-            /** @description User registered successfully */
+            /** @description Signed URLs */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["AuthResponse"];
+                    "application/json": {
+                        urls: components["schemas"]["SignedUrl"][];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
         };
     };
-    login: {
+    removeFile: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description File deleted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    deleteFile: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                id: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description File deleted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
+                };
+            };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    uploadCustomEmoji: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                wid: string;
+            };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["LoginInput"];
+                "multipart/form-data": {
+                    /** Format: binary */
+                    file: string;
+                    /** @example general */
+                    name: string;
+                };
             };
         };
         responses: {
-            /** @description User logged in successfully */
+            /** @description Emoji uploaded */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["AuthResponse"];
+                    "application/json": {
+                        emoji: components["schemas"]["CustomEmoji"];
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    logout: {
+    listCustomEmojis: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                wid: string;
+            };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description User logged out successfully */
+            /** @description List of custom emojis */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        emojis: components["schemas"]["CustomEmoji"][];
+                    };
                 };
             };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    forgotPassword: {
+    deleteCustomEmoji: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                id: string;
+            };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": {
-                    /**
-                     * Format: email
-                     * @example alice@example.com
-                     */
-                    email: string;
+        requestBody?: never;
+        responses: {
+            /** @description Emoji deleted */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+        };
+    };
+    listProfileFields: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
         };
+        requestBody?: never;
         responses: {
-            /** @description Password reset email sent */
+            /** @description Profile fields */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        success: boolean;
-                        message: string;
+                        fields: components["schemas"]["ProfileField"][];
                     };
                 };
             };
-            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    resetPassword: {
+    createProfileField: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": {
-                    /** @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ */
-                    token: string;
-                    /** @example newsecurepassword456 */
-                    new_password: string;
-                };
+                "application/json": components["schemas"]["CreateProfileFieldInput"];
             };
         };
         responses: {
-            /** @description Password reset successfully */
+            /** @description Profile field created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        field: components["schemas"]["ProfileField"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            409: components["responses"]["Conflict"];
         };
     };
-    verifyEmail: {
+    updateProfileField: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                id: string;
+            };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": {
-                    /** @example enz_v1_01JQ3KMWX8FVN4CPRD6BHTYGSZ */
-                    token: string;
-                };
+                "application/json": components["schemas"]["UpdateProfileFieldInput"];
             };
         };
         responses: {
-            /** @description Email verified successfully */
+            /** @description Profile field updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        field: components["schemas"]["ProfileField"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    resendVerification: {
+    deleteProfileField: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                id: string;
+            };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Verification email sent */
+            /** @description Profile field deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
@@ -3257,109 +11621,130 @@ export interface operations {
                     "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    getMe: {
+    listUserGroups: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Current user information */
+            /** @description User groups */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["MeResponse"];
+                    "application/json": {
+                        groups: components["schemas"]["UserGroup"][];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    registerDeviceToken: {
+    createUserGroup: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["RegisterDeviceTokenRequest"];
+                "application/json": components["schemas"]["CreateUserGroupInput"];
             };
         };
         responses: {
-            /** @description Device token registered */
+            /** @description User group created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["RegisterDeviceTokenResponse"];
+                    "application/json": {
+                        group: components["schemas"]["UserGroup"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            409: components["responses"]["Conflict"];
         };
     };
-    unregisterDeviceToken: {
+    updateUserGroup: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description The device token record ID */
                 id: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpdateUserGroupInput"];
+            };
+        };
         responses: {
-            /** @description Device token removed */
-            204: {
+            /** @description User group updated */
+            200: {
                 headers: {
                     [name: string]: unknown;
                 };
-                content?: never;
+                content: {
+                    "application/json": {
+                        group: components["schemas"]["UserGroup"];
+                    };
+                };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    createWorkspace: {
+    deleteUserGroup: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
-            cookie?: never;
-        };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["CreateWorkspaceInput"];
+            path: {
+                id: string;
             };
+            cookie?: never;
         };
+        requestBody?: never;
         responses: {
-            /** @description Workspace created */
+            /** @description User group deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        workspace: components["schemas"]["Workspace"];
-                    };
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    getWorkspace: {
+    listBlockedWords: {
         parameters: {
             query?: never;
             header?: never;
@@ -3371,22 +11756,22 @@ export interface operations {
         };
         requestBody?: never;
         responses: {
-            /** @description Workspace details */
+            /** @description Blocked words */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        workspace: components["schemas"]["Workspace"];
+                        blocked_words: components["schemas"]["BlockedWord"][];
                     };
                 };
             };
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    updateWorkspace: {
+    createBlockedWord: {
         parameters: {
             query?: never;
             header?: never;
@@ -3398,140 +11783,140 @@ export interface operations {
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["UpdateWorkspaceInput"];
+                "application/json": {
+                    /** @example darn */
+                    pattern: string;
+                    /** @default false */
+                    is_regex?: boolean;
+                    action?: components["schemas"]["BlockedWordAction"];
+                };
             };
         };
         responses: {
-            /** @description Workspace updated */
+            /** @description Blocked word created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        workspace: components["schemas"]["Workspace"];
+                        blocked_word: components["schemas"]["BlockedWord"];
                     };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    listWorkspaceMembers: {
+    deleteBlockedWord: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description List of members */
+            /** @description Blocked word deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        members: components["schemas"]["WorkspaceMemberWithUser"][];
-                    };
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    removeWorkspaceMember: {
+    listUserGroupMembers: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": {
-                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-                    user_id: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Member removed */
+            /** @description User group members */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        members: components["schemas"]["UserGroupMember"][];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    leaveWorkspace: {
+    addUserGroupMembers: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UserGroupMembersInput"];
+            };
+        };
         responses: {
-            /** @description Successfully left workspace */
+            /** @description Members added */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        group: components["schemas"]["UserGroup"];
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    updateWorkspaceMemberRole: {
+    removeUserGroupMembers: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": {
-                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-                    user_id: string;
-                    role: components["schemas"]["WorkspaceRole"];
-                };
+                "application/json": components["schemas"]["UserGroupMembersInput"];
             };
         };
         responses: {
-            /** @description Role updated */
+            /** @description Members removed */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        group: components["schemas"]["UserGroup"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
@@ -3540,7 +11925,7 @@ export interface operations {
             404: components["responses"]["NotFound"];
         };
     };
-    createWorkspaceInvite: {
+    listChannelTemplates: {
         parameters: {
             query?: never;
             header?: never;
@@ -3550,20 +11935,16 @@ export interface operations {
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["CreateInviteInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Invite created */
+            /** @description Channel templates */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        invite: components["schemas"]["Invite"];
+                        templates: components["schemas"]["ChannelTemplate"][];
                     };
                 };
             };
@@ -3571,75 +11952,90 @@ export interface operations {
             403: components["responses"]["Forbidden"];
         };
     };
-    getWorkspaceNotifications: {
+    createChannelTemplate: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateChannelTemplateInput"];
+            };
+        };
         responses: {
-            /** @description Notification summaries per workspace */
+            /** @description Channel template created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        workspaces: components["schemas"]["WorkspaceNotificationSummary"][];
+                        template: components["schemas"]["ChannelTemplate"];
                     };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            409: components["responses"]["Conflict"];
         };
     };
-    reorderWorkspaces: {
+    updateChannelTemplate: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                id: string;
+            };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["ReorderWorkspacesInput"];
+                "application/json": components["schemas"]["UpdateChannelTemplateInput"];
             };
         };
         responses: {
-            /** @description Workspaces reordered */
+            /** @description Channel template updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        template: components["schemas"]["ChannelTemplate"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    acceptInvite: {
+    deleteChannelTemplate: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                code: string;
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Invite accepted */
+            /** @description Channel template deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        workspace: components["schemas"]["Workspace"];
-                    };
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
             401: components["responses"]["Unauthorized"];
@@ -3647,7 +12043,7 @@ export interface operations {
             404: components["responses"]["NotFound"];
         };
     };
-    createChannel: {
+    applyChannelTemplate: {
         parameters: {
             query?: never;
             header?: never;
@@ -3659,27 +12055,27 @@ export interface operations {
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["CreateChannelInput"];
+                "application/json": {
+                    template_id: string;
+                };
             };
         };
         responses: {
-            /** @description Channel created */
+            /** @description Template applied */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        channel: components["schemas"]["Channel"];
-                    };
+                    "application/json": components["schemas"]["ApplyChannelTemplateResult"];
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    listChannels: {
+    createIncomingWebhook: {
         parameters: {
             query?: never;
             header?: never;
@@ -3689,23 +12085,27 @@ export interface operations {
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateIncomingWebhookInput"];
+            };
+        };
         responses: {
-            /** @description List of channels */
+            /** @description Webhook created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        channels: components["schemas"]["ChannelWithMembership"][];
-                    };
+                    "application/json": components["schemas"]["IncomingWebhookWithUrl"];
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    createDM: {
+    listIncomingWebhooks: {
         parameters: {
             query?: never;
             header?: never;
@@ -3715,51 +12115,46 @@ export interface operations {
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["CreateDMInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description DM channel */
+            /** @description List of webhooks */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        channel: components["schemas"]["Channel"];
+                        webhooks: components["schemas"]["IncomingWebhook"][];
                     };
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    updateChannel: {
+    updateIncomingWebhook: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["UpdateChannelInput"];
+                "application/json": components["schemas"]["UpdateIncomingWebhookInput"];
             };
         };
         responses: {
-            /** @description Channel updated */
+            /** @description Webhook updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        channel: components["schemas"]["Channel"];
+                        webhook: components["schemas"]["IncomingWebhook"];
                     };
                 };
             };
@@ -3769,51 +12164,43 @@ export interface operations {
             404: components["responses"]["NotFound"];
         };
     };
-    convertGroupDMToChannel: {
+    regenerateIncomingWebhookToken: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["ConvertGroupDMInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Channel converted */
+            /** @description Token regenerated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        channel: components["schemas"]["Channel"];
-                    };
+                    "application/json": components["schemas"]["IncomingWebhookWithUrl"];
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    archiveChannel: {
+    deleteIncomingWebhook: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Channel archived */
+            /** @description Webhook deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
@@ -3822,139 +12209,147 @@ export interface operations {
                     "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    addChannelMember: {
+    executeIncomingWebhook: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                token: string;
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": {
-                    /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-                    user_id: string;
-                    role?: components["schemas"]["ChannelRole"];
-                };
+                "application/json": components["schemas"]["ExecuteIncomingWebhookInput"];
             };
         };
         responses: {
-            /** @description Member added */
+            /** @description Message posted */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
+                        message_id: string;
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
-            401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            429: components["responses"]["TooManyRequests"];
+            503: components["responses"]["ReadOnly"];
         };
     };
-    listChannelMembers: {
+    createBot: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateBotInput"];
+            };
+        };
         responses: {
-            /** @description List of members */
+            /** @description Bot created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        members: components["schemas"]["ChannelMember"][];
+                        bot: components["schemas"]["Bot"];
                     };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    joinChannel: {
+    listBots: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Joined channel */
+            /** @description List of bots */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        bots: components["schemas"]["Bot"][];
+                    };
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    leaveChannel: {
+    updateBot: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpdateBotInput"];
+            };
+        };
         responses: {
-            /** @description Left channel */
+            /** @description Bot updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        bot: components["schemas"]["Bot"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    starChannel: {
+    deleteBot: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Channel starred */
+            /** @description Bot deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
@@ -3964,129 +12359,132 @@ export interface operations {
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    unstarChannel: {
+    createBotToken: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CreateBotTokenInput"];
+            };
+        };
         responses: {
-            /** @description Channel unstarred */
+            /** @description Token created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        token: components["schemas"]["BotToken"];
+                        /**
+                         * @description Plaintext token. Only returned on creation.
+                         * @example bot_3f9c2a...
+                         */
+                        secret: string;
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    markChannelRead: {
+    listBotTokens: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": {
-                    /**
-                     * @description Message ID to mark as last read (defaults to latest message)
-                     * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
-                     */
-                    message_id?: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Channel marked as read */
+            /** @description List of tokens */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["MarkReadResponse"];
+                    "application/json": {
+                        tokens: components["schemas"]["BotToken"][];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    getChannelNotifications: {
+    revokeBotToken: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Notification preferences */
+            /** @description Token revoked */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        preferences: components["schemas"]["NotificationPreferences"];
-                    };
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    updateChannelNotifications: {
+    createAnnouncement: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["NotificationPreferences"];
+                "application/json": components["schemas"]["CreateAnnouncementInput"];
             };
         };
         responses: {
-            /** @description Preferences updated */
+            /** @description Announcement created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        preferences: components["schemas"]["NotificationPreferences"];
+                        announcement: components["schemas"]["Announcement"];
                     };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    markAllChannelsRead: {
+    listAnnouncements: {
         parameters: {
             query?: never;
             header?: never;
@@ -4098,231 +12496,209 @@ export interface operations {
         };
         requestBody?: never;
         responses: {
-            /** @description All channels marked as read */
+            /** @description List of announcements */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        announcements: components["schemas"]["Announcement"][];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    listAllUnreads: {
+    cancelAnnouncement: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": {
-                    /** @default 50 */
-                    limit?: number;
-                    /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-                    cursor?: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description List of unread messages */
+            /** @description Announcement cancelled */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["UnreadMessagesResult"];
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    searchMessages: {
+    acknowledgeAnnouncement: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["SearchMessagesInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Search results */
+            /** @description Announcement acknowledged */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SearchMessagesResult"];
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    listUserThreads: {
+    getAnnouncementReport: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: components["parameters"]["workspaceId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": {
-                    /** @default 20 */
-                    limit?: number;
-                    /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-                    cursor?: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description List of user threads */
+            /** @description Acknowledgement report */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["ThreadListResult"];
+                    "application/json": {
+                        announcement: components["schemas"]["Announcement"];
+                        recipients: components["schemas"]["AnnouncementRecipient"][];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+            404: components["responses"]["NotFound"];
         };
     };
-    getMessage: {
+    scheduleMessage: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Channel ID */
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["ScheduleMessageInput"];
+            };
+        };
         responses: {
-            /** @description Message details */
+            /** @description Message scheduled */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        message: components["schemas"]["MessageWithUser"];
+                        scheduled_message: components["schemas"]["ScheduledMessage"];
                     };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    sendMessage: {
+    listScheduledMessages: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                /** @description Workspace ID */
+                wid: string;
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["SendMessageInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Message sent */
+            /** @description Scheduled messages */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        message: components["schemas"]["MessageWithUser"];
+                        scheduled_messages: components["schemas"]["ScheduledMessage"][];
+                        /** @example 5 */
+                        count: number;
                     };
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
         };
     };
-    listMessages: {
+    getScheduledMessage: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": components["schemas"]["ListMessagesInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description List of messages */
+            /** @description Scheduled message */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["MessageListResult"];
+                    "application/json": {
+                        scheduled_message: components["schemas"]["ScheduledMessage"];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    updateMessage: {
+    updateScheduledMessage: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": {
-                    /** @example Hello, world! */
-                    content: string;
-                };
+                "application/json": components["schemas"]["UpdateScheduledMessageInput"];
             };
         };
         responses: {
-            /** @description Message updated */
+            /** @description Updated scheduled message */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        message: components["schemas"]["MessageWithUser"];
+                        scheduled_message: components["schemas"]["ScheduledMessage"];
                     };
                 };
             };
@@ -4330,21 +12706,21 @@ export interface operations {
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    deleteMessage: {
+    deleteScheduledMessage: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Message deleted */
+            /** @description Scheduled message deleted */
             200: {
                 headers: {
                     [name: string]: unknown;
@@ -4356,61 +12732,62 @@ export interface operations {
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    deleteLinkPreview: {
+    sendScheduledMessageNow: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Link preview deleted */
+            /** @description Message sent */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        message: components["schemas"]["MessageWithUser"];
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    addReaction: {
+    createPoll: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": {
-                    /** @example 👍 */
-                    emoji: string;
-                };
+                "application/json": components["schemas"]["CreatePollInput"];
             };
         };
         responses: {
-            /** @description Reaction added */
+            /** @description Poll created */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        reaction: components["schemas"]["Reaction"];
+                        message: components["schemas"]["MessageWithUser"];
                     };
                 };
             };
@@ -4420,203 +12797,204 @@ export interface operations {
             404: components["responses"]["NotFound"];
         };
     };
-    removeReaction: {
+    getPoll: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Poll ID */
+                id: string;
             };
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": {
-                    /** @example 👍 */
-                    emoji: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Reaction removed */
+            /** @description Poll */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        poll: components["schemas"]["Poll"];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    markMessageUnread: {
+    votePoll: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Poll ID */
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["VotePollInput"];
+            };
+        };
         responses: {
-            /** @description Message marked as unread */
+            /** @description Vote recorded */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        poll: components["schemas"]["Poll"];
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    markThreadRead: {
+    closePoll: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Poll ID */
+                id: string;
             };
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": {
-                    /**
-                     * @description ID of the last read reply (defaults to latest reply)
-                     * @example 01JQ3KMR5KVDW2TG9NHP0XEJBL
-                     */
-                    last_read_reply_id?: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Thread marked as read */
+            /** @description Poll closed */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        poll: components["schemas"]["Poll"];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    listThread: {
+    startCall: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Channel ID */
+                id: components["parameters"]["channelId"];
             };
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": components["schemas"]["ListMessagesInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Thread messages */
+            /** @description Call started */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["MessageListResult"];
+                    "application/json": {
+                        call: components["schemas"]["Call"];
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    getThreadSubscription: {
+    getCall: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Call ID */
+                id: components["parameters"]["callId"];
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Thread subscription status */
+            /** @description Call */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        status: components["schemas"]["ThreadSubscriptionStatus"];
+                        call: components["schemas"]["Call"];
                     };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    subscribeToThread: {
+    joinCall: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Call ID */
+                id: components["parameters"]["callId"];
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Subscribed to thread */
+            /** @description Joined */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        status: components["schemas"]["ThreadSubscriptionStatus"];
+                        call: components["schemas"]["Call"];
                     };
                 };
             };
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
+            409: components["responses"]["Conflict"];
         };
     };
-    unsubscribeFromThread: {
+    leaveCall: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Call ID */
+                id: components["parameters"]["callId"];
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Unsubscribed from thread */
+            /** @description Left */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        status: components["schemas"]["ThreadSubscriptionStatus"];
+                        call: components["schemas"]["Call"];
                     };
                 };
             };
@@ -4624,27 +13002,29 @@ export interface operations {
             404: components["responses"]["NotFound"];
         };
     };
-    pinMessage: {
+    signalCall: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description Call ID */
+                id: components["parameters"]["callId"];
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["CallSignalInput"];
+            };
+        };
         responses: {
-            /** @description Message pinned */
+            /** @description Signal relayed */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        message: components["schemas"]["MessageWithUser"];
-                    };
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
             400: components["responses"]["BadRequest"];
@@ -4653,177 +13033,118 @@ export interface operations {
             404: components["responses"]["NotFound"];
         };
     };
-    unpinMessage: {
+    getUser: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Message ID */
-                id: components["parameters"]["messageId"];
+                /** @description User ID */
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Message unpinned */
+            /** @description User profile */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        message: components["schemas"]["MessageWithUser"];
+                        user: components["schemas"]["UserProfile"];
                     };
                 };
             };
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
         };
     };
-    listPinnedMessages: {
+    getMyProfile: {
         parameters: {
-            query?: never;
-            header?: never;
-            path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
+            query?: {
+                /** @description Workspace whose custom fields to include */
+                workspace_id?: string;
             };
+            header?: never;
+            path?: never;
             cookie?: never;
         };
-        requestBody?: {
-            content: {
-                "application/json": {
-                    /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-                    cursor?: string;
-                    /** @default 50 */
-                    limit?: number;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description List of pinned messages */
+            /** @description Profile fields */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        messages: components["schemas"]["MessageWithUser"][];
-                        has_more: boolean;
-                        /** @example eyJpZCI6IjAxSkVYQU1QTEUifQ */
-                        next_cursor?: string;
+                        profile: components["schemas"]["MyProfile"];
                     };
                 };
             };
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
         };
     };
-    uploadFile: {
+    updateMyProfile: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                /** @description Channel ID */
-                id: components["parameters"]["channelId"];
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody: {
             content: {
-                "multipart/form-data": {
-                    /** Format: binary */
-                    file: string;
-                };
+                "application/json": components["schemas"]["UpdateMyProfileInput"];
             };
         };
         responses: {
-            /** @description File uploaded */
+            /** @description Profile fields updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        file: {
-                            /** @example 01JQ3KMN7XFGY4P6WBR2SZTA9V */
-                            id: string;
-                            /** @example report.pdf */
-                            filename: string;
-                            /** @example 1048576 */
-                            size: number;
-                            /** @example application/pdf */
-                            content_type: string;
-                        };
+                        profile: components["schemas"]["MyProfile"];
                     };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
             403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
-        };
-    };
-    downloadFile: {
-        parameters: {
-            query?: {
-                /** @description Unix timestamp when the signed URL expires */
-                expires?: number;
-                /** @description User ID for signed URL verification */
-                uid?: string;
-                /** @description HMAC-SHA256 signature for signed URL verification */
-                sig?: string;
-            };
-            header?: never;
-            path: {
-                id: string;
-            };
-            cookie?: never;
-        };
-        requestBody?: never;
-        responses: {
-            /** @description File content */
-            200: {
-                headers: {
-                    [name: string]: unknown;
-                };
-                content: {
-                    "application/octet-stream": string;
-                };
-            };
-            401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
         };
     };
-    signFileUrl: {
+    updateProfile: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpdateProfileInput"];
+            };
+        };
         responses: {
-            /** @description Signed URL */
+            /** @description Profile updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SignedUrl"];
+                    "application/json": {
+                        user: components["schemas"]["User"];
+                    };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
         };
     };
-    signFileUrls: {
+    uploadAvatar: {
         parameters: {
             query?: never;
             header?: never;
@@ -4832,39 +13153,37 @@ export interface operations {
         };
         requestBody: {
             content: {
-                "application/json": {
-                    file_ids: string[];
+                "multipart/form-data": {
+                    /** Format: binary */
+                    file: string;
                 };
             };
         };
         responses: {
-            /** @description Signed URLs */
+            /** @description Avatar uploaded */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        urls: components["schemas"]["SignedUrl"][];
-                    };
+                    "application/json": components["schemas"]["AvatarUploadResponse"];
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
         };
     };
-    deleteFile: {
+    deleteAvatar: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description File deleted */
+            /** @description Avatar removed */
             200: {
                 headers: {
                     [name: string]: unknown;
@@ -4874,228 +13193,196 @@ export interface operations {
                 };
             };
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
         };
     };
-    uploadCustomEmoji: {
+    getMyNotificationSettings: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                wid: string;
-            };
+            path?: never;
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "multipart/form-data": {
-                    /** Format: binary */
-                    file: string;
-                    /** @example general */
-                    name: string;
-                };
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Emoji uploaded */
+            /** @description Notification settings */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        emoji: components["schemas"]["CustomEmoji"];
+                        preferences: components["schemas"]["NotificationPreferences"];
                     };
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
         };
     };
-    listCustomEmojis: {
+    updateMyNotificationSettings: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                wid: string;
-            };
+            path?: never;
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["NotificationPreferences"];
+            };
+        };
         responses: {
-            /** @description List of custom emojis */
+            /** @description Settings updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        emojis: components["schemas"]["CustomEmoji"][];
+                        preferences: components["schemas"]["NotificationPreferences"];
                     };
                 };
             };
+            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
         };
     };
-    deleteCustomEmoji: {
+    listMyKeywords: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Emoji deleted */
+            /** @description Keywords */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": {
+                        keywords: components["schemas"]["NotificationKeyword"][];
+                    };
                 };
             };
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
         };
     };
-    scheduleMessage: {
+    addMyKeyword: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                /** @description Channel ID */
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["ScheduleMessageInput"];
+                "application/json": {
+                    /** @example prod incident */
+                    keyword: string;
+                };
             };
         };
         responses: {
-            /** @description Message scheduled */
+            /** @description Keyword added */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        scheduled_message: components["schemas"]["ScheduledMessage"];
+                        keyword: components["schemas"]["NotificationKeyword"];
                     };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
+            409: components["responses"]["Conflict"];
         };
     };
-    listScheduledMessages: {
+    deleteMyKeyword: {
         parameters: {
             query?: never;
             header?: never;
             path: {
-                /** @description Workspace ID */
-                wid: string;
+                /** @description Keyword ID */
+                id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Scheduled messages */
+            /** @description Keyword removed */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        scheduled_messages: components["schemas"]["ScheduledMessage"][];
-                        /** @example 5 */
-                        count: number;
-                    };
+                    "application/json": components["schemas"]["SuccessResponse"];
                 };
             };
             401: components["responses"]["Unauthorized"];
+            404: components["responses"]["NotFound"];
         };
     };
-    getScheduledMessage: {
+    listMyDMs: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Scheduled message */
+            /** @description Direct messages */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        scheduled_message: components["schemas"]["ScheduledMessage"];
-                    };
+                    "application/json": components["schemas"]["DMInboxResult"];
                 };
             };
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
         };
     };
-    updateScheduledMessage: {
+    listSessions: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
-        requestBody: {
-            content: {
-                "application/json": components["schemas"]["UpdateScheduledMessageInput"];
-            };
-        };
+        requestBody?: never;
         responses: {
-            /** @description Updated scheduled message */
+            /** @description Active sessions */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        scheduled_message: components["schemas"]["ScheduledMessage"];
+                        sessions: components["schemas"]["Session"][];
                     };
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
-            409: components["responses"]["Conflict"];
         };
     };
-    deleteScheduledMessage: {
+    revokeSession: {
         parameters: {
             query?: never;
             header?: never;
             path: {
+                /** @description Session ID */
                 id: string;
             };
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Scheduled message deleted */
+            /** @description Session revoked */
             200: {
                 headers: {
                     [name: string]: unknown;
@@ -5105,68 +13392,55 @@ export interface operations {
                 };
             };
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
             404: components["responses"]["NotFound"];
-            409: components["responses"]["Conflict"];
         };
     };
-    sendScheduledMessageNow: {
+    revokeOtherSessions: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description Message sent */
+            /** @description Other sessions revoked */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
                     "application/json": {
-                        message: components["schemas"]["MessageWithUser"];
+                        /** @description Number of sessions signed out */
+                        revoked: number;
                     };
                 };
             };
-            400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-            404: components["responses"]["NotFound"];
-            409: components["responses"]["Conflict"];
         };
     };
-    getUser: {
+    getMyClientSettings: {
         parameters: {
             query?: never;
             header?: never;
-            path: {
-                /** @description User ID */
-                id: string;
-            };
+            path?: never;
             cookie?: never;
         };
         requestBody?: never;
         responses: {
-            /** @description User profile */
+            /** @description Client settings */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        user: components["schemas"]["UserProfile"];
-                    };
+                    "application/json": components["schemas"]["ClientSettings"];
                 };
             };
             401: components["responses"]["Unauthorized"];
-            404: components["responses"]["NotFound"];
         };
     };
-    updateProfile: {
+    updateMyClientSettings: {
         parameters: {
             query?: never;
             header?: never;
@@ -5175,26 +13449,25 @@ export interface operations {
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["UpdateProfileInput"];
+                "application/json": components["schemas"]["UpdateClientSettingsInput"];
             };
         };
         responses: {
-            /** @description Profile updated */
+            /** @description Settings updated */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        user: components["schemas"]["User"];
-                    };
+                    "application/json": components["schemas"]["ClientSettings"];
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
+            409: components["responses"]["Conflict"];
         };
     };
-    uploadAvatar: {
+    deleteAccount: {
         parameters: {
             query?: never;
             header?: never;
@@ -5203,46 +13476,38 @@ export interface operations {
         };
         requestBody: {
             content: {
-                "multipart/form-data": {
-                    /** Format: binary */
-                    file: string;
+                "application/json": {
+                    /**
+                     * Format: password
+                     * @description Current password, to confirm the request
+                     */
+                    password: string;
                 };
             };
         };
         responses: {
-            /** @description Avatar uploaded */
-            200: {
+            /** @description Deletion queued */
+            202: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["AvatarUploadResponse"];
+                    "application/json": {
+                        deletion: components["schemas"]["AccountDeletion"];
+                    };
                 };
             };
             400: components["responses"]["BadRequest"];
             401: components["responses"]["Unauthorized"];
-            403: components["responses"]["Forbidden"];
-        };
-    };
-    deleteAvatar: {
-        parameters: {
-            query?: never;
-            header?: never;
-            path?: never;
-            cookie?: never;
-        };
-        requestBody?: never;
-        responses: {
-            /** @description Avatar removed */
-            200: {
+            /** @description Deletion already requested */
+            409: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["SuccessResponse"];
+                    "application/json": components["schemas"]["ApiErrorResponse"];
                 };
             };
-            401: components["responses"]["Unauthorized"];
         };
     };
     uploadWorkspaceIcon: {
@@ -5549,6 +13814,47 @@ export interface operations {
             403: components["responses"]["Forbidden"];
         };
     };
+    listAuditLog: {
+        parameters: {
+            query?: {
+                /** @description Only entries performed by this user. */
+                actor_id?: string;
+                /** @description Exact action, or a prefix when it ends in a dot. */
+                action?: string;
+                /** @description Only entries at or after this time. */
+                since?: string;
+                /** @description Only entries before this time. */
+                until?: string;
+                cursor?: string;
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description Workspace ID */
+                wid: components["parameters"]["workspaceId"];
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Matching audit log entries */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        entries: components["schemas"]["ModerationLogEntryWithActor"][];
+                        has_more: boolean;
+                        next_cursor?: string;
+                    };
+                };
+            };
+            400: components["responses"]["BadRequest"];
+            401: components["responses"]["Unauthorized"];
+            403: components["responses"]["Forbidden"];
+        };
+    };
     events: {
         parameters: {
             query?: never;
@@ -5572,6 +13878,26 @@ export interface operations {
             };
         };
     };
+    userEvents: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description SSE event stream */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "text/event-stream": components["schemas"]["SSEEvent"];
+                };
+            };
+        };
+    };
     startTyping: {
         parameters: {
             query?: never;
@@ -10,7 +10,7 @@ export interface UseSearchOptions {
   before?: string;
   after?: string;
   limit?: number;
-  offset?: number;
+  cursor?: string;
 }
 
 export function useSearch({
@@ -21,10 +21,10 @@ export function useSearch({
   before,
   after,
   limit = 20,
-  offset = 0,
+  cursor,
 }: UseSearchOptions) {
   return useQuery({
-    queryKey: searchKeys.query(workspaceId, query, channelId, userId, before, after, limit, offset),
+    queryKey: searchKeys.query(workspaceId, query, channelId, userId, before, after, limit, cursor),
     queryFn: () =>
       messagesApi.search(workspaceId, {
         query,
@@ -33,7 +33,7 @@ export function useSearch({
         before,
         after,
         limit,
-        offset,
+        cursor,
       }),
     enabled: !!workspaceId && query.trim().length > 0,
     placeholderData: keepPreviousData,
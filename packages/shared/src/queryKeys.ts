@@ -77,8 +77,8 @@ export const searchKeys = {
     before?: string,
     after?: string,
     limit?: number,
-    offset?: number,
-  ) => ['search', workspaceId, query, channelId, userId, before, after, limit, offset] as const,
+    cursor?: string,
+  ) => ['search', workspaceId, query, channelId, userId, before, after, limit, cursor] as const,
 };
 
 export const serverKeys = {
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/enzyme/server/internal/admin"
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/database"
+	"github.com/enzyme/server/internal/logging"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/spf13/pflag"
+)
+
+const adminUsage = `usage: enzyme admin <command> [flags]
+
+Commands:
+  list-users                                     List all users
+  create-user <email> <name> [--password P]      Create a user (a password is generated if omitted)
+  deactivate-user <email|id>                     Block a user from logging in and revoke their sessions
+  reset-password <email|id> [--password P]       Set a new password and revoke sessions (generated if omitted)
+  promote-owner <workspace-id> <email|id>        Make a user an owner of a workspace
+  delete-workspace <workspace-id> --yes          Permanently delete a workspace and its files
+  reindex-search                                 Rebuild the message search index (also enzyme reindex-search)
+
+All commands accept the same --config and --database.path flags as the server.`
+
+// adminCommand describes one `enzyme admin` subcommand. flags adds the
+// command's own flags, and run is called once the database is open.
+type adminCommand struct {
+	args  int
+	flags func(fs *pflag.FlagSet)
+	run   func(ctx context.Context, svc *admin.Service, fs *pflag.FlagSet) error
+}
+
+var adminCommands = map[string]adminCommand{
+	"list-users": {
+		run: adminListUsers,
+	},
+	"create-user": {
+		args: 2,
+		flags: func(fs *pflag.FlagSet) {
+			fs.String("password", "", "Password (generated if omitted)")
+		},
+		run: adminCreateUser,
+	},
+	"deactivate-user": {
+		args: 1,
+		run:  adminDeactivateUser,
+	},
+	"reset-password": {
+		args: 1,
+		flags: func(fs *pflag.FlagSet) {
+			fs.String("password", "", "New password (generated if omitted)")
+		},
+		run: adminResetPassword,
+	},
+	"promote-owner": {
+		args: 2,
+		run:  adminPromoteOwner,
+	},
+	"delete-workspace": {
+		args: 1,
+		flags: func(fs *pflag.FlagSet) {
+			fs.Bool("yes", false, "Confirm that the workspace should be deleted")
+		},
+		run: adminDeleteWorkspace,
+	},
+	"reindex-search": {
+		run: adminReindexSearch,
+	},
+}
+
+// runAdmin dispatches `enzyme admin` subcommands. They open the database
+// directly, applying migrations like `enzyme seed`, so they work whether or
+// not the server is running.
+func runAdmin(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		os.Exit(2)
+	}
+	cmd, ok := adminCommands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown admin command %q\n\n%s\n", args[0], adminUsage)
+		os.Exit(2)
+	}
+
+	flags := config.SetupFlags()
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		slog.Error("error parsing flags", "error", err)
+		os.Exit(2)
+	}
+	if flags.NArg() != cmd.args {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		os.Exit(2)
+	}
+
+	configPath, _ := flags.GetString("config")
+
+	cfg, err := config.Load(configPath, flags)
+	if err != nil {
+		slog.Error("error loading config", "error", err)
+		os.Exit(1)
+	}
+
+	logging.Setup(cfg.Log, false, cfg.Telemetry.ServiceName)
+
+	db, err := database.Open(cfg.Database.Path, database.Options{
+		MaxOpenConns:     cfg.Database.MaxOpenConns,
+		BusyTimeout:      cfg.Database.BusyTimeout,
+		CacheSize:        cfg.Database.CacheSize,
+		MmapSize:         cfg.Database.MmapSize,
+		JournalSizeLimit: cfg.Database.JournalSizeLimit,
+	})
+	if err != nil {
+		slog.Error("error opening database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := db.Migrate(); err != nil {
+		slog.Error("error running migrations", "error", err)
+		os.Exit(1)
+	}
+
+	var store storage.Storage
+	switch cfg.Storage.Type {
+	case "local":
+		store = storage.NewLocal(cfg.Storage.Local.Path)
+	case "s3":
+		s3Store, err := storage.NewS3(cfg.Storage.S3)
+		if err != nil {
+			slog.Error("error initializing S3 storage", "error", err)
+			os.Exit(1)
+		}
+		store = s3Store
+	}
+
+	svc := admin.NewService(db.DB, store, cfg.Auth.BcryptCost)
+	if err := cmd.run(context.Background(), svc, flags); err != nil {
+		slog.Error("admin command failed", "command", args[0], "error", err)
+		os.Exit(1)
+	}
+}
+
+func adminListUsers(ctx context.Context, svc *admin.Service, _ *pflag.FlagSet) error {
+	users, err := svc.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tNAME\tSTATUS\tWORKSPACES\tCREATED")
+	for _, u := range users {
+		status := u.Status
+		if u.IsBot {
+			status += " (bot)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", u.ID, u.Email, u.DisplayName, status, u.Workspaces, u.CreatedAt.Format(time.DateOnly))
+	}
+	return w.Flush()
+}
+
+func adminCreateUser(ctx context.Context, svc *admin.Service, fs *pflag.FlagSet) error {
+	password, generated := passwordFlag(fs)
+
+	u, err := svc.CreateUser(ctx, fs.Arg(0), fs.Arg(1), password)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created user %s (%s)\n", u.Email, u.ID)
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+	return nil
+}
+
+func adminDeactivateUser(ctx context.Context, svc *admin.Service, fs *pflag.FlagSet) error {
+	u, sessions, err := svc.DeactivateUser(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deactivated %s (%s), revoked %d sessions\n", u.Email, u.ID, sessions)
+	return nil
+}
+
+func adminResetPassword(ctx context.Context, svc *admin.Service, fs *pflag.FlagSet) error {
+	password, generated := passwordFlag(fs)
+
+	u, sessions, err := svc.ResetPassword(ctx, fs.Arg(0), password)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("reset password for %s (%s), revoked %d sessions\n", u.Email, u.ID, sessions)
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+	return nil
+}
+
+func adminPromoteOwner(ctx context.Context, svc *admin.Service, fs *pflag.FlagSet) error {
+	ws, u, err := svc.PromoteOwner(ctx, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s is now an owner of %s (%s)\n", u.Email, ws.Name, ws.ID)
+	return nil
+}
+
+func adminDeleteWorkspace(ctx context.Context, svc *admin.Service, fs *pflag.FlagSet) error {
+	if yes, _ := fs.GetBool("yes"); !yes {
+		return fmt.Errorf("deleting a workspace cannot be undone; pass --yes to confirm")
+	}
+	ws, files, err := svc.DeleteWorkspace(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("deleted workspace %s (%s) and %d files\n", ws.Name, ws.ID, files)
+	return nil
+}
+
+func adminReindexSearch(ctx context.Context, svc *admin.Service, _ *pflag.FlagSet) error {
+	start := time.Now()
+	if err := svc.ReindexSearch(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("rebuilt search index in %s\n", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// passwordFlag returns the --password flag, or a random password if it was
+// not given. generated reports which.
+func passwordFlag(fs *pflag.FlagSet) (password string, generated bool) {
+	if password, _ = fs.GetString("password"); password != "" {
+		return password, false
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b), true
+}
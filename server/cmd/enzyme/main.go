@@ -2,14 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/enzyme/server/internal/app"
+	"github.com/enzyme/server/internal/backup"
 	"github.com/enzyme/server/internal/config"
 	"github.com/enzyme/server/internal/database"
 	"github.com/enzyme/server/internal/logging"
@@ -22,6 +25,31 @@ func main() {
 		runSeed(os.Args[2:])
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "dev" {
+		runDev(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		runBackup(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		runRestore(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		runAdmin(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reindex-search" {
+		// Shorthand for `enzyme admin reindex-search`, for recovery runbooks
+		runAdmin(os.Args[1:])
+		return
+	}
 
 	// Setup CLI flags
 	flags := config.SetupFlags()
@@ -34,7 +62,8 @@ func main() {
 	configPath, _ := flags.GetString("config")
 
 	// Load configuration
-	cfg, err := config.Load(configPath, flags)
+	load := func() (*config.Config, error) { return config.Load(configPath, flags) }
+	cfg, err := load()
 	if err != nil {
 		slog.Error("error loading config", "error", err)
 		os.Exit(1)
@@ -43,42 +72,147 @@ func main() {
 	// Setup structured logging
 	logging.Setup(cfg.Log, cfg.Telemetry.Enabled && cfg.Telemetry.Logs, cfg.Telemetry.ServiceName)
 
+	if err := serve(cfg, load, nil); err != nil {
+		os.Exit(1)
+	}
+}
+
+// serve creates the application, runs prepare (if non-nil) once the
+// database is migrated, and blocks until the server stops. On SIGHUP the
+// config is re-read with load and the reloadable settings applied. Errors are
+// logged before being returned.
+func serve(cfg *config.Config, load func() (*config.Config, error), prepare func(ctx context.Context, a *app.App) error) error {
 	// Create application
 	application, err := app.New(cfg)
 	if err != nil {
 		slog.Error("error creating application", "error", err)
-		os.Exit(1)
+		return err
 	}
+	application.SetConfigLoader(load)
 
 	// Setup context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if prepare != nil {
+		if err := prepare(ctx, application); err != nil {
+			slog.Error("error preparing application", "error", err)
+			_ = application.Shutdown(ctx)
+			return err
+		}
+	}
+
+	// Reload the config on SIGHUP. A rejected config is logged by
+	// ReloadConfig and the running settings are kept.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	go func() {
+		for {
+			select {
+			case <-hupCh:
+				slog.Info("received SIGHUP, reloading config")
+				_, _ = application.ReloadConfig(ctx, "sighup")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	// Handle graceful shutdown
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
+	shutdownDone := make(chan struct{})
 	go func() {
 		<-sigCh
 		slog.Info("received shutdown signal")
 		cancel()
 
-		// Give server time to shutdown gracefully
+		// Give in-flight requests time to finish
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer shutdownCancel()
 
 		if err := application.Shutdown(shutdownCtx); err != nil {
 			slog.Error("error during shutdown", "error", err)
 		}
+		close(shutdownDone)
 	}()
 
 	// Start application
 	if err := application.Start(ctx); err != nil && err != http.ErrServerClosed {
 		slog.Error("server error", "error", err)
-		os.Exit(1)
+		return err
 	}
+	// Start returns as soon as shutdown begins; wait for it to complete
+	<-shutdownDone
 
 	slog.Info("server stopped")
+	return nil
+}
+
+// runDev starts a throwaway development server: debug logging, a freshly
+// seeded database and uploads directory in a temp dir (removed on exit),
+// CORS open to any origin, and the API reference at /api/docs. Flags and
+// config files still apply, and an explicit --database.path or
+// --storage.local.path is kept.
+func runDev(args []string) {
+	flags := config.SetupFlags()
+	if err := flags.Parse(args); err != nil {
+		slog.Error("error parsing flags", "error", err)
+		os.Exit(1)
+	}
+
+	configPath, _ := flags.GetString("config")
+
+	dataDir, err := os.MkdirTemp("", "enzyme-dev-")
+	if err != nil {
+		slog.Error("error creating dev data directory", "error", err)
+		os.Exit(1)
+	}
+
+	// Dev overrides are applied on every load so a reload keeps them
+	load := func() (*config.Config, error) {
+		cfg, err := config.Load(configPath, flags)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Log.Level = "debug"
+		cfg.Log.Format = "text"
+		if !flags.Changed("database.path") {
+			cfg.Database.Path = filepath.Join(dataDir, "enzyme.db")
+		}
+		if cfg.Storage.Type == "local" && !flags.Changed("storage.local.path") {
+			cfg.Storage.Local.Path = filepath.Join(dataDir, "uploads")
+		}
+		cfg.Server.AllowedOrigins = []string{"*"}
+		if cfg.Server.APIDocsSpec == "" {
+			if _, err := os.Stat("openapi.yaml"); err == nil {
+				cfg.Server.APIDocsSpec = "openapi.yaml"
+			}
+		}
+		return cfg, nil
+	}
+	cfg, err := load()
+	if err != nil {
+		slog.Error("error loading config", "error", err)
+		_ = os.RemoveAll(dataDir)
+		os.Exit(1)
+	}
+
+	logging.Setup(cfg.Log, cfg.Telemetry.Enabled && cfg.Telemetry.Logs, cfg.Telemetry.ServiceName)
+	slog.Info("starting in dev mode", "data_dir", dataDir, "database", cfg.Database.Path)
+	if cfg.Server.APIDocsSpec == "" {
+		slog.Warn("openapi.yaml not found in the working directory; /api/docs is disabled (set --server.api_docs_spec)")
+	}
+
+	err = serve(cfg, load, func(ctx context.Context, a *app.App) error {
+		return seed.RunWithOptions(ctx, a.DB.DB, seed.Options{Small: true})
+	})
+	_ = os.RemoveAll(dataDir)
+	if err != nil {
+		os.Exit(1)
+	}
 }
 
 func runSeed(args []string) {
@@ -124,3 +258,96 @@ func runSeed(args []string) {
 		os.Exit(1)
 	}
 }
+
+// runBackup writes a backup archive of the database and local uploads. It is
+// safe to run while the server is up.
+func runBackup(args []string) {
+	flags := config.SetupFlags()
+	output := flags.String("output", "", "Directory to write the archive to (default: backup.dir)")
+	if err := flags.Parse(args); err != nil {
+		slog.Error("error parsing flags", "error", err)
+		os.Exit(1)
+	}
+
+	configPath, _ := flags.GetString("config")
+
+	cfg, err := config.Load(configPath, flags)
+	if err != nil {
+		slog.Error("error loading config", "error", err)
+		os.Exit(1)
+	}
+
+	logging.Setup(cfg.Log, false, cfg.Telemetry.ServiceName)
+
+	dir := cfg.Backup.Dir
+	if *output != "" {
+		dir = *output
+	}
+
+	if _, err := os.Stat(cfg.Database.Path); err != nil {
+		slog.Error("database not found", "path", cfg.Database.Path, "error", err)
+		os.Exit(1)
+	}
+	db, err := database.Open(cfg.Database.Path, database.Options{
+		MaxOpenConns:     1,
+		BusyTimeout:      cfg.Database.BusyTimeout,
+		CacheSize:        cfg.Database.CacheSize,
+		JournalSizeLimit: cfg.Database.JournalSizeLimit,
+	})
+	if err != nil {
+		slog.Error("error opening database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	path, err := backup.Create(context.Background(), db.DB, backup.UploadsDir(cfg.Storage), dir, time.Now())
+	if err != nil {
+		slog.Error("error creating backup", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("backup created", "path", path)
+}
+
+// runRestore unpacks a backup archive into the configured database path and
+// uploads directory. The server must be stopped first.
+func runRestore(args []string) {
+	flags := config.SetupFlags()
+	force := flags.Bool("force", false, "Replace an existing database and uploads (they are kept with a .pre-restore suffix)")
+	if err := flags.Parse(args); err != nil {
+		slog.Error("error parsing flags", "error", err)
+		os.Exit(1)
+	}
+	if flags.NArg() != 1 {
+		slog.Error("usage: enzyme restore [--force] [flags] <archive>")
+		os.Exit(2)
+	}
+
+	configPath, _ := flags.GetString("config")
+
+	cfg, err := config.Load(configPath, flags)
+	if err != nil {
+		slog.Error("error loading config", "error", err)
+		os.Exit(1)
+	}
+
+	logging.Setup(cfg.Log, false, cfg.Telemetry.ServiceName)
+
+	manifest, err := backup.Restore(flags.Arg(0), backup.RestoreOptions{
+		DatabasePath: cfg.Database.Path,
+		UploadsDir:   backup.UploadsDir(cfg.Storage),
+		Force:        *force,
+	})
+	if errors.Is(err, backup.ErrTargetExists) {
+		slog.Error("refusing to overwrite existing data; stop the server and pass --force to replace it", "error", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Error("error restoring backup", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("backup restored",
+		"created_at", manifest.CreatedAt,
+		"schema_version", manifest.SchemaVersion,
+		"database", cfg.Database.Path,
+	)
+}
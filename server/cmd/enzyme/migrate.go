@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/database"
+	"github.com/enzyme/server/internal/logging"
+)
+
+const migrateUsage = `usage: enzyme migrate <command> [flags]
+
+Commands:
+  status            Show which migrations have been applied
+  up                Apply all pending migrations
+  down              Roll back the most recent migration
+  to <version>      Migrate up or down to a version (0 rolls back everything)
+  force <version>   Record the database as being at a version without running
+                    any migrations, and clear the dirty flag
+
+All commands accept the same --config and --database.path flags as the server.`
+
+// runMigrate inspects and changes the schema version of the configured
+// database. The server applies pending migrations on startup, so this is for
+// rolling back, recovering from a failed migration, or migrating ahead of a
+// deploy.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+	command := args[0]
+	wantArgs := 0
+	switch command {
+	case "status", "up", "down":
+	case "to", "force":
+		wantArgs = 1
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s\n", command, migrateUsage)
+		os.Exit(2)
+	}
+
+	flags := config.SetupFlags()
+	if err := flags.Parse(args[1:]); err != nil {
+		slog.Error("error parsing flags", "error", err)
+		os.Exit(2)
+	}
+	if flags.NArg() != wantArgs {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+	var version int64
+	if wantArgs == 1 {
+		v, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+		if err != nil || v < 0 {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", flags.Arg(0))
+			os.Exit(2)
+		}
+		version = v
+	}
+
+	configPath, _ := flags.GetString("config")
+
+	cfg, err := config.Load(configPath, flags)
+	if err != nil {
+		slog.Error("error loading config", "error", err)
+		os.Exit(1)
+	}
+
+	logging.Setup(cfg.Log, false, cfg.Telemetry.ServiceName)
+
+	db, err := database.Open(cfg.Database.Path, database.Options{
+		MaxOpenConns:     cfg.Database.MaxOpenConns,
+		BusyTimeout:      cfg.Database.BusyTimeout,
+		CacheSize:        cfg.Database.CacheSize,
+		JournalSizeLimit: cfg.Database.JournalSizeLimit,
+	})
+	if err != nil {
+		slog.Error("error opening database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	m, err := database.NewMigrator(db.DB)
+	if err != nil {
+		slog.Error("error loading migrations", "error", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "status":
+		err = printMigrationStatus(ctx, m)
+	case "up":
+		var ran []database.Migration
+		ran, err = m.Up(ctx)
+		if err == nil && len(ran) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		var mig *database.Migration
+		mig, err = m.Down(ctx)
+		if err == nil && mig == nil {
+			fmt.Println("no migrations to roll back")
+		}
+	case "to":
+		var ran []database.Migration
+		ran, err = m.To(ctx, version)
+		if err == nil && len(ran) == 0 {
+			fmt.Printf("already at version %d\n", version)
+		}
+	case "force":
+		err = m.Force(ctx, version)
+		if err == nil {
+			fmt.Printf("database recorded at version %d\n", version)
+		}
+	}
+
+	var dirty *database.DirtyError
+	if errors.As(err, &dirty) {
+		slog.Error("a migration was interrupted and may have been partly applied; repair the schema by hand, then run `enzyme migrate force <version>`",
+			"version", dirty.Version, "direction", dirty.Direction)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Error("migrate failed", "command", command, "error", err)
+		os.Exit(1)
+	}
+}
+
+func printMigrationStatus(ctx context.Context, m *database.Migrator) error {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, ms := range st.Migrations {
+		applied := "pending"
+		if ms.Applied {
+			applied = ms.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", ms.Version, ms.Name, applied)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\ncurrent version: %d, latest: %d\n", st.Current, st.Latest)
+	if len(st.Unknown) > 0 {
+		fmt.Printf("applied by a newer version of enzyme: %v\n", st.Unknown)
+	}
+	if st.Dirty != nil {
+		fmt.Printf("DIRTY: migration %d did not finish running %s\n", st.Dirty.Version, st.Dirty.Direction)
+	}
+	if st.Lock != nil {
+		fmt.Printf("locked by %s until %s\n", st.Lock.Owner, st.Lock.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package accountdeletion
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDeletionNotFound = errors.New("account deletion not found")
+	ErrAlreadyRequested = errors.New("account deletion already requested")
+)
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// DeletedDisplayName replaces the display name of a deleted account, so its
+// messages are shown as written by "Deactivated User".
+const DeletedDisplayName = "Deactivated User"
+
+// Deletion is a user's request to delete their account. The account is
+// deactivated when the request is made and anonymized in the background.
+type Deletion struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	Status       string     `json:"status"`
+	FilesDeleted int        `json:"files_deleted"`
+	LastError    string     `json:"last_error,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
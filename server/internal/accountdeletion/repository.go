@@ -0,0 +1,270 @@
+package accountdeletion
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const deletionColumns = `id, user_id, status, files_deleted, last_error, completed_at, created_at, updated_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// IsRequested reports whether the user has asked for their account to be
+// deleted. Failed deletions still count: the account stays deactivated
+// until an operator deals with it.
+func (r *Repository) IsRequested(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM account_deletions WHERE user_id = ?)
+	`, userID).Scan(&exists)
+	return exists, err
+}
+
+// Create queues the deletion of a user's account. Returns ErrAlreadyRequested
+// if a deletion was already requested.
+func (r *Repository) Create(ctx context.Context, d *Deletion) error {
+	exists, err := r.IsRequested(ctx, d.UserID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrAlreadyRequested
+	}
+
+	d.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	d.CreatedAt = now
+	d.UpdatedAt = now
+	d.Status = StatusPending
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO account_deletions (id, user_id, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, d.ID, d.UserID, d.Status, now.Format(time.RFC3339), now.Format(time.RFC3339))
+	return err
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*Deletion, error) {
+	return scanDeletion(r.db.QueryRowContext(ctx, `
+		SELECT `+deletionColumns+` FROM account_deletions WHERE id = ?
+	`, id))
+}
+
+// ListPending returns queued deletions, oldest first.
+func (r *Repository) ListPending(ctx context.Context) ([]Deletion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deletionColumns+` FROM account_deletions WHERE status = ? ORDER BY created_at, id
+	`, StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deletions []Deletion
+	for rows.Next() {
+		d, err := scanDeletion(rows)
+		if err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, *d)
+	}
+	return deletions, rows.Err()
+}
+
+// MarkRunning atomically claims a pending deletion. Returns true if the row
+// was claimed, false if another worker got it first.
+func (r *Repository) MarkRunning(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE account_deletions SET status = ?, updated_at = ? WHERE id = ? AND status = ?
+	`, StatusRunning, time.Now().UTC().Format(time.RFC3339), id, StatusPending)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *Repository) MarkCompleted(ctx context.Context, id string, filesDeleted int) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE account_deletions
+		SET status = ?, files_deleted = ?, completed_at = ?, last_error = NULL, updated_at = ?
+		WHERE id = ?
+	`, StatusCompleted, filesDeleted, now, now, id)
+	return err
+}
+
+func (r *Repository) MarkFailed(ctx context.Context, id, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE account_deletions SET status = ?, last_error = ?, updated_at = ? WHERE id = ?
+	`, StatusFailed, lastError, time.Now().UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// RequeueRunning puts deletions left running by a previous process back in
+// the queue. Anonymizing an account is idempotent, so they are simply re-run.
+func (r *Repository) RequeueRunning(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE account_deletions SET status = ?, updated_at = ? WHERE status = ?
+	`, StatusPending, time.Now().UTC().Format(time.RFC3339), StatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanDeletion(row interface{ Scan(dest ...any) error }) (*Deletion, error) {
+	var d Deletion
+	var lastError, completedAt sql.NullString
+	var createdAt, updatedAt string
+
+	err := row.Scan(&d.ID, &d.UserID, &d.Status, &d.FilesDeleted, &lastError, &completedAt, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrDeletionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.LastError = lastError.String
+	if completedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, completedAt.String)
+		d.CompletedAt = &t
+	}
+	d.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	d.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &d, nil
+}
+
+// anonymized describes what is left to clean up outside the database once a
+// user row has been anonymized.
+type anonymized struct {
+	// Storage keys of the user's uploads, whose attachment rows were removed
+	storagePaths []string
+	avatarURL    *string
+	workspaceIDs []string
+}
+
+// userDataTables hold rows that only make sense while the account exists.
+// Memberships, messages and reactions are kept so conversations stay intact.
+var userDataTables = []string{
+	"sessions",
+	"password_resets",
+	"email_verifications",
+	"device_tokens",
+	"user_presence",
+	"notification_preferences",
+	"workspace_notification_settings",
+	"user_notification_settings",
+	"notification_keywords",
+	"user_client_settings",
+	"pending_notifications",
+	"thread_subscriptions",
+	"scheduled_messages",
+	"user_group_members",
+	"activities",
+}
+
+// anonymize strips a user's personal data in one transaction: the user row
+// keeps its ID (so messages still resolve to an author) but loses its name,
+// email, password and profile, and per-user rows are deleted. Attachments
+// uploaded by the user are removed and their storage keys returned.
+func (r *Repository) anonymize(ctx context.Context, userID string) (_ *anonymized, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var result anonymized
+	var avatarURL sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT avatar_url FROM users WHERE id = ?`, userID).Scan(&avatarURL); err != nil {
+		return nil, err
+	}
+	if avatarURL.Valid {
+		result.avatarURL = &avatarURL.String
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT workspace_id FROM workspace_memberships WHERE user_id = ? ORDER BY workspace_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.workspaceIDs = append(result.workspaceIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.QueryContext(ctx, `SELECT storage_path FROM attachments WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.storagePaths = append(result.storagePaths, path)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users SET
+			email = 'deleted-' || id || '@deleted.invalid', email_verified_at = NULL, password_hash = '',
+			display_name = ?, avatar_url = NULL, title = NULL, pronouns = NULL, timezone = NULL, phone = NULL,
+			status = 'deactivated', updated_at = ?
+		WHERE id = ?
+	`, DeletedDisplayName, now, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE workspace_memberships SET display_name_override = NULL, profile_fields = NULL, updated_at = ?
+		WHERE user_id = ?
+	`, now, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM attachments WHERE user_id = ?`, userID); err != nil {
+		return nil, err
+	}
+
+	for _, table := range userDataTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE user_id = ?`, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,114 @@
+package accountdeletion
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/storage"
+)
+
+// Worker carries out queued account deletions: the user row is anonymized,
+// per-user data is removed and the user's uploads and avatar are deleted
+// from storage. Each workspace the user belonged to gets an audit entry.
+type Worker struct {
+	repo      *Repository
+	audit     *moderation.Repository
+	store     storage.Storage
+	recovered bool
+}
+
+// NewWorker creates a new account deletion worker. store may be nil, in
+// which case only database rows are removed.
+func NewWorker(repo *Repository, audit *moderation.Repository, store storage.Storage) *Worker {
+	return &Worker{
+		repo:  repo,
+		audit: audit,
+		store: store,
+	}
+}
+
+// ProcessPending runs every queued deletion, one at a time. On its first run
+// it requeues deletions left running by a previous process.
+func (w *Worker) ProcessPending(ctx context.Context) error {
+	if !w.recovered {
+		n, err := w.repo.RequeueRunning(ctx)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slog.Warn("requeued account deletions interrupted by restart", "component", "account-deletion", "count", n)
+		}
+		w.recovered = true
+	}
+
+	deletions, err := w.repo.ListPending(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range deletions {
+		if err := w.run(ctx, &deletions[i]); err != nil {
+			slog.Error("failed to delete account",
+				"component", "account-deletion",
+				"id", deletions[i].ID,
+				"user_id", deletions[i].UserID,
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// run claims a deletion, anonymizes the account and records the outcome. It
+// is a no-op if the deletion was already claimed.
+func (w *Worker) run(ctx context.Context, d *Deletion) error {
+	claimed, err := w.repo.MarkRunning(ctx, d.ID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	result, err := w.repo.anonymize(ctx, d.UserID)
+	if err != nil {
+		if markErr := w.repo.MarkFailed(ctx, d.ID, err.Error()); markErr != nil {
+			slog.Error("failed to mark account deletion as failed", "component", "account-deletion", "id", d.ID, "error", markErr)
+		}
+		return err
+	}
+
+	// The rows are gone, so a file that fails to delete here is only logged;
+	// nothing references it any more.
+	filesDeleted := 0
+	if w.store != nil {
+		keys := result.storagePaths
+		if key, ok := avatarKey(result.avatarURL); ok {
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			if err := w.store.Delete(ctx, key); err != nil {
+				slog.Warn("failed to delete file of deleted account", "component", "account-deletion", "key", key, "error", err)
+				continue
+			}
+			filesDeleted++
+		}
+	}
+
+	for _, workspaceID := range result.workspaceIDs {
+		if err := w.audit.CreateAuditLogEntryWithMetadata(ctx, workspaceID, d.UserID, moderation.ActionUserDeleted, moderation.TargetTypeUser, d.UserID, map[string]interface{}{
+			"deletion_id": d.ID,
+		}); err != nil {
+			slog.Error("failed to create audit log entry for account deletion", "component", "account-deletion", "workspace_id", workspaceID, "error", err)
+		}
+	}
+
+	return w.repo.MarkCompleted(ctx, d.ID, filesDeleted)
+}
+
+// avatarKey maps a locally hosted avatar URL to its storage key. Avatars
+// hosted elsewhere have nothing to delete.
+func avatarKey(avatarURL *string) (string, bool) {
+	if avatarURL == nil || !strings.HasPrefix(*avatarURL, "/api/avatars/") {
+		return "", false
+	}
+	return "avatars/" + strings.TrimPrefix(*avatarURL, "/api/avatars/"), true
+}
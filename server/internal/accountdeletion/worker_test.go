@@ -0,0 +1,152 @@
+package accountdeletion
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/oklog/ulid/v2"
+)
+
+func TestProcessPending_AnonymizesAccount(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	audit := moderation.NewRepository(db)
+	store := storage.NewLocal(t.TempDir())
+	w := NewWorker(repo, audit, store)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	if _, err := db.Exec(`
+		INSERT INTO workspace_memberships (id, user_id, workspace_id, role, display_name_override)
+		VALUES (?, ?, ?, 'member', 'Ali')
+	`, ulid.Make().String(), alice.ID, ws.ID); err != nil {
+		t.Fatalf("adding member: %v", err)
+	}
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, alice.ID, "hello from alice")
+
+	avatarKey := "avatars/" + alice.ID + "/me.png"
+	fileKey := "files/" + ulid.Make().String()
+	for _, key := range []string{avatarKey, fileKey} {
+		if err := store.Put(ctx, key, bytes.NewReader([]byte("data")), 4, "application/octet-stream"); err != nil {
+			t.Fatalf("storing %s: %v", key, err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE users SET avatar_url = ?, title = 'Engineer' WHERE id = ?`, "/api/"+avatarKey, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at)
+		VALUES (?, ?, ?, ?, 'notes.txt', 'text/plain', 4, ?, ?)
+	`, ulid.Make().String(), msg.ID, ch.ID, alice.ID, fileKey, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		t.Fatalf("inserting attachment: %v", err)
+	}
+	if _, err := auth.NewSessionStore(db, time.Hour, 0).Create(alice.ID, auth.ClientInfo{}); err != nil {
+		t.Fatalf("creating session: %v", err)
+	}
+
+	d := &Deletion{UserID: alice.ID}
+	if err := repo.Create(ctx, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(ctx, &Deletion{UserID: alice.ID}); err != ErrAlreadyRequested {
+		t.Fatalf("expected ErrAlreadyRequested, got %v", err)
+	}
+
+	if err := w.ProcessPending(ctx); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, d.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != StatusCompleted || got.FilesDeleted != 2 {
+		t.Fatalf("deletion = %+v, want completed with 2 files deleted", got)
+	}
+
+	var email, displayName, status string
+	var avatarURL, title *string
+	if err := db.QueryRow(`SELECT email, display_name, status, avatar_url, title FROM users WHERE id = ?`, alice.ID).
+		Scan(&email, &displayName, &status, &avatarURL, &title); err != nil {
+		t.Fatal(err)
+	}
+	if displayName != DeletedDisplayName || status != "deactivated" || email == "alice@example.com" || avatarURL != nil || title != nil {
+		t.Errorf("user not anonymized: email=%q name=%q status=%q avatar=%v title=%v", email, displayName, status, avatarURL, title)
+	}
+
+	var override *string
+	if err := db.QueryRow(`SELECT display_name_override FROM workspace_memberships WHERE user_id = ?`, alice.ID).Scan(&override); err != nil {
+		t.Fatalf("expected the membership to be kept: %v", err)
+	}
+	if override != nil {
+		t.Errorf("expected the display name override to be cleared, got %q", *override)
+	}
+
+	// The message is kept and now resolves to the anonymized author
+	messages := message.NewRepository(db)
+	kept, err := messages.GetByIDWithUser(ctx, msg.ID)
+	if err != nil {
+		t.Fatalf("expected the message to be kept: %v", err)
+	}
+	if kept.UserDisplayName != DeletedDisplayName || !kept.UserIsDeactivated {
+		t.Errorf("author = %q (deactivated %v), want %q", kept.UserDisplayName, kept.UserIsDeactivated, DeletedDisplayName)
+	}
+
+	var count int
+	db.QueryRow(`SELECT COUNT(*) FROM attachments WHERE user_id = ?`, alice.ID).Scan(&count)
+	if count != 0 {
+		t.Errorf("expected attachments to be deleted, %d left", count)
+	}
+	db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE user_id = ?`, alice.ID).Scan(&count)
+	if count != 0 {
+		t.Errorf("expected sessions to be deleted, %d left", count)
+	}
+	for _, key := range []string{avatarKey, fileKey} {
+		if rc, err := store.Get(ctx, key); err == nil {
+			rc.Close()
+			t.Errorf("expected %s to be removed from storage", key)
+		}
+	}
+
+	entries, _, _, err := audit.ListAuditLog(ctx, ws.ID, moderation.AuditLogFilter{Action: moderation.ActionUserDeleted}, "", 10)
+	if err != nil {
+		t.Fatalf("ListAuditLog: %v", err)
+	}
+	if len(entries) != 1 || entries[0].TargetID != alice.ID {
+		t.Errorf("expected one user.deleted audit entry, got %+v", entries)
+	}
+}
+
+func TestProcessPending_RequeuesInterrupted(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	w := NewWorker(repo, moderation.NewRepository(db), nil)
+
+	u := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	d := &Deletion{UserID: u.ID}
+	if err := repo.Create(ctx, d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if claimed, err := repo.MarkRunning(ctx, d.ID); err != nil || !claimed {
+		t.Fatalf("MarkRunning = %v, %v", claimed, err)
+	}
+
+	if err := w.ProcessPending(ctx); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+	got, _ := repo.GetByID(ctx, d.ID)
+	if got.Status != StatusCompleted {
+		t.Errorf("status = %q, want the interrupted deletion to be re-run", got.Status)
+	}
+}
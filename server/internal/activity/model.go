@@ -0,0 +1,54 @@
+package activity
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrActivityNotFound = errors.New("activity not found")
+
+const (
+	TypeMention       = "mention"
+	TypeThreadReply   = "thread_reply"
+	TypeReaction      = "reaction"
+	TypeChannelInvite = "channel_invite"
+)
+
+// Activity is an event in a user's activity feed. UserID is the user the
+// event is addressed to and ActorID the user who caused it.
+type Activity struct {
+	ID          string     `json:"id"`
+	WorkspaceID string     `json:"workspace_id"`
+	UserID      string     `json:"user_id"`
+	ActorID     *string    `json:"actor_id,omitempty"`
+	Type        string     `json:"type"`
+	ChannelID   string     `json:"channel_id"`
+	MessageID   *string    `json:"message_id,omitempty"`
+	Emoji       *string    `json:"emoji,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Item is an activity with the channel, message and actor details needed to
+// display it.
+type Item struct {
+	Activity
+	ChannelName      string  `json:"channel_name"`
+	ChannelType      string  `json:"channel_type"`
+	ThreadParentID   *string `json:"thread_parent_id,omitempty"`
+	MessageContent   *string `json:"message_content,omitempty"`
+	ActorDisplayName *string `json:"actor_display_name,omitempty"`
+	ActorAvatarURL   *string `json:"actor_avatar_url,omitempty"`
+}
+
+type ListOptions struct {
+	Cursor     string
+	Limit      int
+	UnreadOnly bool
+}
+
+type ListResult struct {
+	Items      []Item `json:"items"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,188 @@
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+const itemColumns = `a.id, a.workspace_id, a.user_id, a.actor_id, a.type, a.channel_id, a.message_id, a.emoji, a.read_at, a.created_at,
+	c.name, c.type, m.thread_parent_id, m.content, u.display_name, u.avatar_url`
+
+const itemJoins = `
+	FROM activities a
+	JOIN channels c ON c.id = a.channel_id
+	LEFT JOIN messages m ON m.id = a.message_id
+	LEFT JOIN users u ON u.id = a.actor_id`
+
+// visibleSQL hides activity for deleted messages and for non-public channels
+// the user is no longer a member of.
+const visibleSQL = `
+	AND (a.message_id IS NULL OR m.deleted_at IS NULL)
+	AND (c.type = 'public' OR EXISTS (
+		SELECT 1 FROM channel_memberships cm WHERE cm.channel_id = a.channel_id AND cm.user_id = a.user_id
+	))`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create records an activity for a.UserID.
+func (r *Repository) Create(ctx context.Context, a *Activity) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "activity.Create")
+	defer func() { endSpan(err) }()
+
+	a.ID = ulid.Make().String()
+	a.CreatedAt = time.Now().UTC()
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO activities (id, workspace_id, user_id, actor_id, type, channel_id, message_id, emoji, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, a.ID, a.WorkspaceID, a.UserID, a.ActorID, a.Type, a.ChannelID, a.MessageID, a.Emoji, a.CreatedAt.Format(time.RFC3339))
+	return err
+}
+
+// GetItem returns an activity with its display details.
+func (r *Repository) GetItem(ctx context.Context, id string) (*Item, error) {
+	item, err := scanItem(r.db.QueryRowContext(ctx, `
+		SELECT `+itemColumns+itemJoins+`
+		WHERE a.id = ?
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrActivityNotFound
+	}
+	return item, err
+}
+
+// List returns the user's activity in a workspace, newest first. Activity
+// from users the requester has blocked or who are banned with hidden
+// messages is left out.
+func (r *Repository) List(ctx context.Context, workspaceID, userID string, opts ListOptions, filter *moderation.FilterOptions) (_ *ListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "activity.List")
+	defer func() { endSpan(err) }()
+
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
+	}
+
+	args := []interface{}{userID, workspaceID}
+	var clauses []string
+	if opts.UnreadOnly {
+		clauses = append(clauses, "AND a.read_at IS NULL")
+	}
+	if opts.Cursor != "" {
+		clauses = append(clauses, "AND a.id < ?")
+		args = append(args, opts.Cursor)
+	}
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "COALESCE(a.actor_id, '')")
+	args = append(args, filterArgs...)
+	args = append(args, opts.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+itemColumns+itemJoins+`
+		WHERE a.user_id = ? AND a.workspace_id = ?`+visibleSQL+`
+		`+strings.Join(clauses, " ")+filterSQL+`
+		ORDER BY a.id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{Items: items}
+	if len(items) > opts.Limit {
+		result.Items = items[:opts.Limit]
+		result.HasMore = true
+		result.NextCursor = result.Items[len(result.Items)-1].ID
+	}
+	return result, nil
+}
+
+// CountUnread counts the user's unread activity in a workspace.
+func (r *Repository) CountUnread(ctx context.Context, workspaceID, userID string, filter *moderation.FilterOptions) (int, error) {
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "COALESCE(a.actor_id, '')")
+	args := append([]interface{}{userID, workspaceID}, filterArgs...)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)`+itemJoins+`
+		WHERE a.user_id = ? AND a.workspace_id = ? AND a.read_at IS NULL`+visibleSQL+filterSQL,
+		args...).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks the given activities as read, or all of the user's activity
+// in the workspace when ids is empty.
+func (r *Repository) MarkRead(ctx context.Context, workspaceID, userID string, ids []string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := `
+		UPDATE activities SET read_at = ?
+		WHERE user_id = ? AND workspace_id = ? AND read_at IS NULL`
+	args := []interface{}{now, userID, workspaceID}
+	if len(ids) > 0 {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		query += ` AND id IN (` + strings.Join(placeholders, ",") + `)`
+	}
+
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// DeleteReaction removes the activity recorded for a reaction, once the
+// reaction itself is removed.
+func (r *Repository) DeleteReaction(ctx context.Context, messageID, actorID, emoji string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM activities WHERE type = ? AND message_id = ? AND actor_id = ? AND emoji = ?
+	`, TypeReaction, messageID, actorID, emoji)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanItem(row scanner) (*Item, error) {
+	var item Item
+	var readAt sql.NullString
+	var createdAt string
+	err := row.Scan(
+		&item.ID, &item.WorkspaceID, &item.UserID, &item.ActorID, &item.Type, &item.ChannelID, &item.MessageID, &item.Emoji, &readAt, &createdAt,
+		&item.ChannelName, &item.ChannelType, &item.ThreadParentID, &item.MessageContent, &item.ActorDisplayName, &item.ActorAvatarURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if readAt.Valid {
+		t, _ := time.Parse(time.RFC3339, readAt.String)
+		item.ReadAt = &t
+	}
+	item.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &item, nil
+}
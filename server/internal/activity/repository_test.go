@@ -0,0 +1,159 @@
+package activity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestRepository_ListAndMarkRead(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	actor := testutil.CreateTestUser(t, db, "actor@example.com", "Actor")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, actor.ID, "hello")
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		a := &Activity{WorkspaceID: ws.ID, UserID: owner.ID, ActorID: &actor.ID, Type: TypeMention, ChannelID: ch.ID, MessageID: &msg.ID}
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, a.ID)
+	}
+
+	page, err := repo.List(ctx, ws.ID, owner.ID, ListOptions{Limit: 2}, nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(page.Items) != 2 || !page.HasMore {
+		t.Fatalf("first page = %d items (has_more %v), want 2 and more", len(page.Items), page.HasMore)
+	}
+	if page.Items[0].ID != ids[2] {
+		t.Errorf("first item = %s, want newest %s", page.Items[0].ID, ids[2])
+	}
+	if page.Items[0].ActorDisplayName == nil || *page.Items[0].ActorDisplayName != "Actor" {
+		t.Errorf("actor display name = %v, want Actor", page.Items[0].ActorDisplayName)
+	}
+	if page.Items[0].MessageContent == nil || *page.Items[0].MessageContent != "hello" {
+		t.Errorf("message content = %v, want hello", page.Items[0].MessageContent)
+	}
+
+	next, err := repo.List(ctx, ws.ID, owner.ID, ListOptions{Limit: 2, Cursor: page.NextCursor}, nil)
+	if err != nil {
+		t.Fatalf("List() page 2 error = %v", err)
+	}
+	if len(next.Items) != 1 || next.HasMore || next.Items[0].ID != ids[0] {
+		t.Errorf("second page = %+v, want only %s", next, ids[0])
+	}
+
+	if err := repo.MarkRead(ctx, ws.ID, owner.ID, ids[:1]); err != nil {
+		t.Fatalf("MarkRead() error = %v", err)
+	}
+	if n, _ := repo.CountUnread(ctx, ws.ID, owner.ID, nil); n != 2 {
+		t.Errorf("unread after marking one = %d, want 2", n)
+	}
+	unread, err := repo.List(ctx, ws.ID, owner.ID, ListOptions{UnreadOnly: true}, nil)
+	if err != nil {
+		t.Fatalf("List(unread) error = %v", err)
+	}
+	if len(unread.Items) != 2 {
+		t.Errorf("unread items = %d, want 2", len(unread.Items))
+	}
+
+	if err := repo.MarkRead(ctx, ws.ID, owner.ID, nil); err != nil {
+		t.Fatalf("MarkRead(all) error = %v", err)
+	}
+	if n, _ := repo.CountUnread(ctx, ws.ID, owner.ID, nil); n != 0 {
+		t.Errorf("unread after marking all = %d, want 0", n)
+	}
+}
+
+func TestRepository_List_HidesInvisibleActivity(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	actor := testutil.CreateTestUser(t, db, "actor@example.com", "Actor")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	public := testutil.CreateTestChannel(t, db, ws.ID, actor.ID, "general", "public")
+	private := testutil.CreateTestChannel(t, db, ws.ID, actor.ID, "secret", "private")
+	deleted := testutil.CreateTestMessage(t, db, public.ID, actor.ID, "gone")
+	kept := testutil.CreateTestMessage(t, db, public.ID, actor.ID, "kept")
+	if _, err := db.Exec(`UPDATE messages SET deleted_at = created_at WHERE id = ?`, deleted.ID); err != nil {
+		t.Fatalf("deleting message: %v", err)
+	}
+
+	for _, a := range []*Activity{
+		{ChannelID: public.ID, MessageID: &kept.ID, Type: TypeMention},
+		{ChannelID: public.ID, MessageID: &deleted.ID, Type: TypeMention},
+		{ChannelID: private.ID, Type: TypeChannelInvite},
+	} {
+		a.WorkspaceID, a.UserID, a.ActorID = ws.ID, owner.ID, &actor.ID
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	result, err := repo.List(ctx, ws.ID, owner.ID, ListOptions{}, nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(result.Items) != 1 || *result.Items[0].MessageID != kept.ID {
+		t.Fatalf("items = %+v, want only the mention in the kept message", result.Items)
+	}
+
+	// Blocking the actor hides their activity too.
+	if _, err := db.Exec(`
+		INSERT INTO user_blocks (workspace_id, blocker_id, blocked_id) VALUES (?, ?, ?)
+	`, ws.ID, owner.ID, actor.ID); err != nil {
+		t.Fatalf("blocking: %v", err)
+	}
+	filter := &moderation.FilterOptions{WorkspaceID: ws.ID, RequestingUserID: owner.ID}
+	result, err = repo.List(ctx, ws.ID, owner.ID, ListOptions{}, filter)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(result.Items) != 0 {
+		t.Errorf("items from blocked actor = %d, want 0", len(result.Items))
+	}
+}
+
+func TestRepository_DeleteReaction(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	actor := testutil.CreateTestUser(t, db, "actor@example.com", "Actor")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "react to me")
+
+	for _, emoji := range []string{"👍", "🎉"} {
+		if err := repo.Create(ctx, &Activity{
+			WorkspaceID: ws.ID, UserID: owner.ID, ActorID: &actor.ID, Type: TypeReaction,
+			ChannelID: ch.ID, MessageID: &msg.ID, Emoji: &emoji,
+		}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if err := repo.DeleteReaction(ctx, msg.ID, actor.ID, "👍"); err != nil {
+		t.Fatalf("DeleteReaction() error = %v", err)
+	}
+	result, err := repo.List(ctx, ws.ID, owner.ID, ListOptions{}, nil)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(result.Items) != 1 || *result.Items[0].Emoji != "🎉" {
+		t.Errorf("items = %+v, want only the 🎉 reaction", result.Items)
+	}
+}
@@ -0,0 +1,292 @@
+// Package admin implements the operator commands behind `enzyme admin`. They
+// work directly on the database, so they are available when the HTTP API is
+// not: a locked-out owner, a lost password, or a server that is not running.
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// Service runs admin commands against a database.
+type Service struct {
+	db         *sql.DB
+	users      *user.Repository
+	workspaces *workspace.Repository
+	store      storage.Storage
+	bcryptCost int
+}
+
+// NewService creates a Service. store may be nil, in which case deleting a
+// workspace leaves its files in storage.
+func NewService(db *sql.DB, store storage.Storage, bcryptCost int) *Service {
+	return &Service{
+		db:         db,
+		users:      user.NewRepository(db),
+		workspaces: workspace.NewRepository(db),
+		store:      store,
+		bcryptCost: bcryptCost,
+	}
+}
+
+// UserSummary is a row of ListUsers.
+type UserSummary struct {
+	ID          string
+	Email       string
+	DisplayName string
+	Status      string
+	IsBot       bool
+	Workspaces  int
+	CreatedAt   time.Time
+}
+
+// ListUsers returns every user, oldest first, with the number of workspaces
+// each belongs to.
+func (s *Service) ListUsers(ctx context.Context) ([]UserSummary, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.display_name, u.status, u.is_bot, u.created_at,
+		       (SELECT COUNT(*) FROM workspace_memberships wm WHERE wm.user_id = u.id)
+		FROM users u
+		ORDER BY u.created_at, u.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []UserSummary
+	for rows.Next() {
+		var u UserSummary
+		var createdAt string
+		if err := rows.Scan(&u.ID, &u.Email, &u.DisplayName, &u.Status, &u.IsBot, &createdAt, &u.Workspaces); err != nil {
+			return nil, err
+		}
+		u.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// CreateUser creates an active user with a verified email address. The
+// operator creating the account stands in for the verification email.
+func (s *Service) CreateUser(ctx context.Context, email, displayName, password string) (*user.User, error) {
+	email = strings.TrimSpace(email)
+	if !strings.Contains(email, "@") {
+		return nil, auth.ErrInvalidEmail
+	}
+	if strings.TrimSpace(displayName) == "" {
+		return nil, auth.ErrDisplayNameRequired
+	}
+	if len(password) < 8 {
+		return nil, auth.ErrPasswordTooShort
+	}
+
+	hash, err := auth.HashPassword(password, s.bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.Create(ctx, user.CreateUserInput{
+		Email:        email,
+		DisplayName:  strings.TrimSpace(displayName),
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.VerifyEmail(ctx, u.ID); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// DeactivateUser blocks a user from logging in and signs them out everywhere.
+// Their messages and memberships are kept. It returns the number of sessions
+// revoked.
+func (s *Service) DeactivateUser(ctx context.Context, ref string) (*user.User, int, error) {
+	u, err := s.findUser(ctx, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	u.Status = user.StatusDeactivated
+	u.UpdatedAt = time.Now().UTC()
+	if err := s.users.Update(ctx, u); err != nil {
+		return nil, 0, err
+	}
+
+	n, err := s.revokeSessions(ctx, u.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return u, n, nil
+}
+
+// ResetPassword sets a new password for a user and revokes their sessions,
+// so anyone holding the old credentials is signed out. It returns the number
+// of sessions revoked.
+func (s *Service) ResetPassword(ctx context.Context, ref, password string) (*user.User, int, error) {
+	if len(password) < 8 {
+		return nil, 0, auth.ErrPasswordTooShort
+	}
+	u, err := s.findUser(ctx, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	if u.IsBot {
+		return nil, 0, fmt.Errorf("%s is a bot account and has no password", u.Email)
+	}
+
+	hash, err := auth.HashPassword(password, s.bcryptCost)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.users.UpdatePassword(ctx, u.ID, hash); err != nil {
+		return nil, 0, err
+	}
+
+	n, err := s.revokeSessions(ctx, u.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return u, n, nil
+}
+
+// PromoteOwner makes a user an owner of a workspace, adding them as a member
+// first if they are not one. This is how an operator restores access to a
+// workspace whose owners have all left or lost their accounts.
+func (s *Service) PromoteOwner(ctx context.Context, workspaceID, ref string) (*workspace.Workspace, *user.User, error) {
+	ws, err := s.workspaces.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := s.findUser(ctx, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.IsBot {
+		return nil, nil, fmt.Errorf("%s is a bot account and cannot own a workspace", u.Email)
+	}
+
+	_, err = s.workspaces.GetMembership(ctx, u.ID, ws.ID)
+	switch {
+	case errors.Is(err, workspace.ErrNotAMember):
+		if _, err := s.workspaces.AddMember(ctx, u.ID, ws.ID, workspace.RoleOwner); err != nil {
+			return nil, nil, err
+		}
+	case err != nil:
+		return nil, nil, err
+	default:
+		if err := s.workspaces.UpdateMemberRole(ctx, u.ID, ws.ID, workspace.RoleOwner); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ws, u, nil
+}
+
+// DeleteWorkspace permanently deletes a workspace and everything in it, then
+// removes its files from storage. A file that cannot be removed is left
+// behind rather than failing the command; the number removed is returned.
+func (s *Service) DeleteWorkspace(ctx context.Context, workspaceID string) (*workspace.Workspace, int, error) {
+	ws, err := s.workspaces.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keys, err := s.workspaceFiles(ctx, ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM workspaces WHERE id = ?`, ws.ID); err != nil {
+		return nil, 0, err
+	}
+
+	if s.store == nil {
+		return ws, 0, nil
+	}
+	removed := 0
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err == nil {
+			removed++
+		}
+	}
+	return ws, removed, nil
+}
+
+// ReindexSearch rebuilds the full-text message index from the messages table.
+func (s *Service) ReindexSearch(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO messages_fts(messages_fts) VALUES ('rebuild')`); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `INSERT INTO messages_fts(messages_fts) VALUES ('optimize')`)
+	return err
+}
+
+// findUser looks a user up by email address, or by ID if ref has no @.
+func (s *Service) findUser(ctx context.Context, ref string) (*user.User, error) {
+	if strings.Contains(ref, "@") {
+		return s.users.GetByEmail(ctx, ref)
+	}
+	return s.users.GetByID(ctx, ref)
+}
+
+func (s *Service) revokeSessions(ctx context.Context, userID string) (int, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// workspaceFiles lists the storage keys of every file owned by a workspace:
+// attachments, in-progress upload chunks, custom emoji, exports, and a
+// locally stored icon.
+func (s *Service) workspaceFiles(ctx context.Context, ws *workspace.Workspace) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.storage_path FROM attachments a
+		JOIN channels c ON c.id = a.channel_id
+		WHERE c.workspace_id = ?1
+		UNION ALL
+		SELECT uc.storage_path FROM upload_chunks uc
+		JOIN upload_sessions us ON us.id = uc.upload_id
+		JOIN channels c ON c.id = us.channel_id
+		WHERE c.workspace_id = ?1
+		UNION ALL
+		SELECT storage_path FROM custom_emojis WHERE workspace_id = ?1
+		UNION ALL
+		SELECT storage_path FROM workspace_exports WHERE workspace_id = ?1 AND storage_path IS NOT NULL
+	`, ws.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if ws.IconURL != nil {
+		if key, ok := strings.CutPrefix(*ws.IconURL, "/api/workspace-icons/"); ok {
+			keys = append(keys, "workspace-icons/"+key)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+func TestCreateUser(t *testing.T) {
+	db := testutil.TestDB(t)
+	svc := NewService(db, nil, 4)
+	ctx := context.Background()
+
+	u, err := svc.CreateUser(ctx, "ops@example.com", "Ops", "hunter2hunter2")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	got, err := user.NewRepository(db).GetByID(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.EmailVerifiedAt == nil {
+		t.Error("expected admin-created user to have a verified email")
+	}
+	if !auth.CheckPassword("hunter2hunter2", got.PasswordHash) {
+		t.Error("password was not stored")
+	}
+
+	if _, err := svc.CreateUser(ctx, "ops@example.com", "Ops", "hunter2hunter2"); !errors.Is(err, user.ErrEmailAlreadyInUse) {
+		t.Errorf("duplicate email: got %v, want ErrEmailAlreadyInUse", err)
+	}
+	if _, err := svc.CreateUser(ctx, "other@example.com", "Other", "short"); !errors.Is(err, auth.ErrPasswordTooShort) {
+		t.Errorf("short password: got %v, want ErrPasswordTooShort", err)
+	}
+
+	users, err := svc.ListUsers(ctx)
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(users) != 1 || users[0].Email != "ops@example.com" {
+		t.Errorf("ListUsers = %+v, want the one created user", users)
+	}
+}
+
+func TestDeactivateUser_RevokesSessions(t *testing.T) {
+	db := testutil.TestDB(t)
+	svc := NewService(db, nil, 4)
+	ctx := context.Background()
+
+	u := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	sessions := auth.NewSessionStore(db, time.Hour, 0)
+	tokens, err := sessions.Create(u.ID, auth.ClientInfo{})
+	if err != nil {
+		t.Fatalf("creating session: %v", err)
+	}
+
+	_, revoked, err := svc.DeactivateUser(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	if revoked != 1 {
+		t.Errorf("revoked = %d, want 1", revoked)
+	}
+	if _, err := sessions.Validate(tokens.AccessToken); err == nil {
+		t.Error("expected session to be revoked")
+	}
+
+	got, _ := user.NewRepository(db).GetByID(ctx, u.ID)
+	if got.Status != user.StatusDeactivated {
+		t.Errorf("status = %q, want %q", got.Status, user.StatusDeactivated)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	db := testutil.TestDB(t)
+	svc := NewService(db, nil, 4)
+	ctx := context.Background()
+
+	u := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+
+	if _, _, err := svc.ResetPassword(ctx, u.ID, "a-new-password"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	got, _ := user.NewRepository(db).GetByID(ctx, u.ID)
+	if !auth.CheckPassword("a-new-password", got.PasswordHash) {
+		t.Error("password was not changed")
+	}
+
+	if _, _, err := svc.ResetPassword(ctx, "nobody@example.com", "a-new-password"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("unknown user: got %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestPromoteOwner(t *testing.T) {
+	db := testutil.TestDB(t)
+	svc := NewService(db, nil, 4)
+	ctx := context.Background()
+	workspaces := workspace.NewRepository(db)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@example.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	if _, err := workspaces.AddMember(ctx, member.ID, ws.ID, workspace.RoleMember); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	for _, u := range []*testutil.TestUser{member, outsider} {
+		if _, _, err := svc.PromoteOwner(ctx, ws.ID, u.Email); err != nil {
+			t.Fatalf("PromoteOwner(%s): %v", u.Email, err)
+		}
+		m, err := workspaces.GetMembership(ctx, u.ID, ws.ID)
+		if err != nil {
+			t.Fatalf("GetMembership(%s): %v", u.Email, err)
+		}
+		if m.Role != workspace.RoleOwner {
+			t.Errorf("%s role = %q, want owner", u.Email, m.Role)
+		}
+	}
+
+	if _, _, err := svc.PromoteOwner(ctx, "missing", member.ID); !errors.Is(err, workspace.ErrWorkspaceNotFound) {
+		t.Errorf("unknown workspace: got %v, want ErrWorkspaceNotFound", err)
+	}
+}
+
+func TestDeleteWorkspace_RemovesFiles(t *testing.T) {
+	db := testutil.TestDB(t)
+	store := storage.NewLocal(t.TempDir())
+	svc := NewService(db, store, 4)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	other := testutil.CreateTestWorkspace(t, db, owner.ID, "Other")
+	testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "hello")
+
+	key := "attachments/" + ws.ID + "/file.txt"
+	if err := store.Put(ctx, key, bytes.NewReader([]byte("hi")), 2, "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO attachments (id, channel_id, user_id, filename, content_type, size_bytes, storage_path)
+		VALUES ('att1', ?, ?, 'file.txt', 'text/plain', 2, ?)
+	`, ch.ID, owner.ID, key); err != nil {
+		t.Fatalf("inserting attachment: %v", err)
+	}
+
+	_, removed, err := svc.DeleteWorkspace(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("DeleteWorkspace: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+	if _, err := store.Get(ctx, key); err == nil {
+		t.Error("expected attachment file to be deleted")
+	}
+
+	workspaces := workspace.NewRepository(db)
+	if _, err := workspaces.GetByID(ctx, ws.ID); !errors.Is(err, workspace.ErrWorkspaceNotFound) {
+		t.Errorf("GetByID after delete: got %v, want ErrWorkspaceNotFound", err)
+	}
+	if _, err := workspaces.GetByID(ctx, other.ID); err != nil {
+		t.Errorf("other workspace was affected: %v", err)
+	}
+}
+
+func TestReindexSearch(t *testing.T) {
+	db := testutil.TestDB(t)
+	svc := NewService(db, nil, 4)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "the quarterly roadmap")
+
+	if _, err := db.Exec(`INSERT INTO messages_fts(messages_fts) VALUES ('delete-all')`); err != nil {
+		t.Fatalf("clearing index: %v", err)
+	}
+	if err := svc.ReindexSearch(ctx); err != nil {
+		t.Fatalf("ReindexSearch: %v", err)
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages_fts WHERE messages_fts MATCH 'roadmap'`).Scan(&n); err != nil {
+		t.Fatalf("searching: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("matches = %d, want 1", n)
+	}
+}
@@ -0,0 +1,52 @@
+package announcement
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	ErrNotRecipient         = errors.New("user is not a recipient of this announcement")
+)
+
+const (
+	StatusScheduled = "scheduled"
+	StatusSending   = "sending"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+)
+
+// Announcement is an admin-authored message delivered to the whole workspace
+// or to selected channels, with per-recipient acknowledgement tracking.
+// An empty ChannelIDs targets the whole workspace.
+type Announcement struct {
+	ID                string     `json:"id"`
+	WorkspaceID       string     `json:"workspace_id"`
+	CreatedBy         *string    `json:"created_by,omitempty"`
+	Title             string     `json:"title"`
+	Content           string     `json:"content"`
+	ChannelIDs        []string   `json:"channel_ids"`
+	ScheduledFor      time.Time  `json:"scheduled_for"`
+	Status            string     `json:"status"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	RecipientCount    int        `json:"recipient_count"`
+	AcknowledgedCount int        `json:"acknowledged_count"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TargetsWorkspace reports whether the announcement is addressed to every
+// workspace member rather than to selected channels.
+func (a *Announcement) TargetsWorkspace() bool {
+	return len(a.ChannelIDs) == 0
+}
+
+// Recipient is a user an announcement was delivered to.
+type Recipient struct {
+	UserID         string     `json:"user_id"`
+	DisplayName    string     `json:"display_name"`
+	AvatarURL      *string    `json:"avatar_url,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
@@ -0,0 +1,325 @@
+package announcement
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+const announcementColumns = `a.id, a.workspace_id, a.created_by, a.title, a.content, a.scheduled_for, a.status, a.sent_at, a.last_error, a.created_at, a.updated_at,
+	(SELECT COUNT(*) FROM announcement_recipients ar WHERE ar.announcement_id = a.id),
+	(SELECT COUNT(*) FROM announcement_recipients ar WHERE ar.announcement_id = a.id AND ar.acknowledged_at IS NOT NULL)`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create inserts a scheduled announcement and its target channels.
+func (r *Repository) Create(ctx context.Context, a *Announcement) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "announcement.Create")
+	defer func() { endSpan(err) }()
+
+	a.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	a.Status = StatusScheduled
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO announcements (id, workspace_id, created_by, title, content, scheduled_for, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, a.ID, a.WorkspaceID, a.CreatedBy, a.Title, a.Content, a.ScheduledFor.UTC().Format(time.RFC3339), a.Status,
+		now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+
+	for _, channelID := range a.ChannelIDs {
+		_, err = tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO announcement_channels (announcement_id, channel_id) VALUES (?, ?)
+		`, a.ID, channelID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*Announcement, error) {
+	a, err := scanAnnouncement(r.db.QueryRowContext(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements a WHERE a.id = ?
+	`, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadChannelIDs(ctx, []*Announcement{a}); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListByWorkspace returns all announcements of a workspace, newest first.
+func (r *Repository) ListByWorkspace(ctx context.Context, workspaceID string) ([]Announcement, error) {
+	return r.list(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements a WHERE a.workspace_id = ?
+		ORDER BY a.created_at DESC, a.id DESC
+	`, workspaceID)
+}
+
+// ListDue returns scheduled announcements whose delivery time has passed.
+func (r *Repository) ListDue(ctx context.Context) ([]Announcement, error) {
+	return r.list(ctx, `
+		SELECT `+announcementColumns+`
+		FROM announcements a WHERE a.status = ? AND a.scheduled_for <= ?
+		ORDER BY a.scheduled_for ASC
+	`, StatusScheduled, time.Now().UTC().Format(time.RFC3339))
+}
+
+func (r *Repository) list(ctx context.Context, query string, args ...any) ([]Announcement, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ptrs []*Announcement
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			return nil, err
+		}
+		ptrs = append(ptrs, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.loadChannelIDs(ctx, ptrs); err != nil {
+		return nil, err
+	}
+
+	announcements := make([]Announcement, len(ptrs))
+	for i, a := range ptrs {
+		announcements[i] = *a
+	}
+	return announcements, nil
+}
+
+func (r *Repository) loadChannelIDs(ctx context.Context, announcements []*Announcement) error {
+	if len(announcements) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Announcement, len(announcements))
+	placeholders := make([]string, len(announcements))
+	args := make([]any, len(announcements))
+	for i, a := range announcements {
+		a.ChannelIDs = []string{}
+		byID[a.ID] = a
+		placeholders[i] = "?"
+		args[i] = a.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT announcement_id, channel_id FROM announcement_channels
+		WHERE announcement_id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY channel_id
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var announcementID, channelID string
+		if err := rows.Scan(&announcementID, &channelID); err != nil {
+			return err
+		}
+		if a, ok := byID[announcementID]; ok {
+			a.ChannelIDs = append(a.ChannelIDs, channelID)
+		}
+	}
+	return rows.Err()
+}
+
+// Delete removes an announcement that has not been delivered yet.
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM announcements WHERE id = ? AND status = ?`, id, StatusScheduled)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+// MarkSending atomically claims a scheduled announcement for delivery.
+// Returns true if the row was claimed, false if another worker got it first.
+func (r *Repository) MarkSending(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE announcements SET status = ?, updated_at = ? WHERE id = ? AND status = ?
+	`, StatusSending, time.Now().UTC().Format(time.RFC3339), id, StatusScheduled)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *Repository) MarkSent(ctx context.Context, id string) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE announcements SET status = ?, sent_at = ?, last_error = NULL, updated_at = ? WHERE id = ?
+	`, StatusSent, now, now, id)
+	return err
+}
+
+func (r *Repository) MarkFailed(ctx context.Context, id, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE announcements SET status = ?, last_error = ?, updated_at = ? WHERE id = ?
+	`, StatusFailed, lastError, time.Now().UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// SnapshotRecipients records the announcement's current audience: every
+// active human workspace member for workspace-wide announcements, or the
+// members of the target channels otherwise. The author is never a recipient.
+// Returns the number of recipients recorded.
+func (r *Repository) SnapshotRecipients(ctx context.Context, a *Announcement) (_ int64, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "announcement.SnapshotRecipients")
+	defer func() { endSpan(err) }()
+
+	var author string
+	if a.CreatedBy != nil {
+		author = *a.CreatedBy
+	}
+
+	var result sql.Result
+	if a.TargetsWorkspace() {
+		result, err = r.db.ExecContext(ctx, `
+			INSERT OR IGNORE INTO announcement_recipients (announcement_id, user_id)
+			SELECT ?, wm.user_id
+			FROM workspace_memberships wm
+			JOIN users u ON u.id = wm.user_id
+			WHERE wm.workspace_id = ? AND u.is_bot = 0 AND u.status = 'active' AND wm.user_id != ?
+		`, a.ID, a.WorkspaceID, author)
+	} else {
+		result, err = r.db.ExecContext(ctx, `
+			INSERT OR IGNORE INTO announcement_recipients (announcement_id, user_id)
+			SELECT DISTINCT ?, cm.user_id
+			FROM announcement_channels ac
+			JOIN channel_memberships cm ON cm.channel_id = ac.channel_id
+			JOIN users u ON u.id = cm.user_id
+			WHERE ac.announcement_id = ? AND u.is_bot = 0 AND u.status = 'active' AND cm.user_id != ?
+		`, a.ID, a.ID, author)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Acknowledge records that a recipient has acknowledged the announcement.
+// Acknowledging twice keeps the original timestamp.
+func (r *Repository) Acknowledge(ctx context.Context, id, userID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE announcement_recipients SET acknowledged_at = COALESCE(acknowledged_at, ?)
+		WHERE announcement_id = ? AND user_id = ?
+	`, time.Now().UTC().Format(time.RFC3339), id, userID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotRecipient
+	}
+	return nil
+}
+
+// ListRecipients returns the recipients of an announcement, unacknowledged
+// first, then by display name.
+func (r *Repository) ListRecipients(ctx context.Context, id string) ([]Recipient, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ar.user_id, u.display_name, u.avatar_url, ar.acknowledged_at
+		FROM announcement_recipients ar
+		JOIN users u ON u.id = ar.user_id
+		WHERE ar.announcement_id = ?
+		ORDER BY ar.acknowledged_at IS NOT NULL, u.display_name
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := []Recipient{}
+	for rows.Next() {
+		var rec Recipient
+		var avatarURL, acknowledgedAt sql.NullString
+		if err := rows.Scan(&rec.UserID, &rec.DisplayName, &avatarURL, &acknowledgedAt); err != nil {
+			return nil, err
+		}
+		if avatarURL.Valid {
+			rec.AvatarURL = &avatarURL.String
+		}
+		if acknowledgedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, acknowledgedAt.String)
+			rec.AcknowledgedAt = &t
+		}
+		recipients = append(recipients, rec)
+	}
+	return recipients, rows.Err()
+}
+
+func scanAnnouncement(row interface{ Scan(dest ...any) error }) (*Announcement, error) {
+	var a Announcement
+	var createdBy, sentAt, lastError sql.NullString
+	var scheduledFor, createdAt, updatedAt string
+
+	err := row.Scan(&a.ID, &a.WorkspaceID, &createdBy, &a.Title, &a.Content, &scheduledFor, &a.Status, &sentAt, &lastError,
+		&createdAt, &updatedAt, &a.RecipientCount, &a.AcknowledgedCount)
+	if err == sql.ErrNoRows {
+		return nil, ErrAnnouncementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		a.CreatedBy = &createdBy.String
+	}
+	if sentAt.Valid {
+		t, _ := time.Parse(time.RFC3339, sentAt.String)
+		a.SentAt = &t
+	}
+	if lastError.Valid {
+		a.LastError = lastError.String
+	}
+	a.ScheduledFor, _ = time.Parse(time.RFC3339, scheduledFor)
+	a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	a.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	return &a, nil
+}
@@ -0,0 +1,160 @@
+package announcement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestRepository_SnapshotRecipients_Workspace(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	author := testutil.CreateTestUser(t, db, "author@example.com", "Author")
+	ws := testutil.CreateTestWorkspace(t, db, author.ID, "Test Workspace")
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	gone := testutil.CreateTestUser(t, db, "gone@example.com", "Gone")
+	for _, id := range []string{alice.ID, gone.ID} {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+			VALUES (?, ?, ?, 'member', datetime('now'), datetime('now'))
+		`, id+"-m", id, ws.ID); err != nil {
+			t.Fatalf("adding member: %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE users SET status = 'deactivated' WHERE id = ?`, gone.ID); err != nil {
+		t.Fatalf("deactivating user: %v", err)
+	}
+
+	a := &Announcement{WorkspaceID: ws.ID, CreatedBy: &author.ID, Title: "Policy", Content: "Read me", ScheduledFor: time.Now()}
+	if err := repo.Create(ctx, a); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	n, err := repo.SnapshotRecipients(ctx, a)
+	if err != nil {
+		t.Fatalf("SnapshotRecipients() error = %v", err)
+	}
+	// Only Alice: the author and deactivated members are excluded
+	if n != 1 {
+		t.Fatalf("recipients = %d, want 1", n)
+	}
+
+	recipients, err := repo.ListRecipients(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("ListRecipients() error = %v", err)
+	}
+	if len(recipients) != 1 || recipients[0].UserID != alice.ID {
+		t.Fatalf("recipients = %+v, want only Alice", recipients)
+	}
+}
+
+func TestRepository_SnapshotRecipients_Channels(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	author := testutil.CreateTestUser(t, db, "author@example.com", "Author")
+	ws := testutil.CreateTestWorkspace(t, db, author.ID, "Test Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, author.ID, "hr", "public")
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO channel_memberships (id, user_id, channel_id, created_at, updated_at)
+		VALUES ('cm-alice', ?, ?, datetime('now'), datetime('now'))
+	`, alice.ID, ch.ID); err != nil {
+		t.Fatalf("adding channel member: %v", err)
+	}
+
+	a := &Announcement{WorkspaceID: ws.ID, CreatedBy: &author.ID, Title: "Policy", Content: "Read me", ChannelIDs: []string{ch.ID}, ScheduledFor: time.Now()}
+	if err := repo.Create(ctx, a); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	n, err := repo.SnapshotRecipients(ctx, a)
+	if err != nil {
+		t.Fatalf("SnapshotRecipients() error = %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("recipients = %d, want 1", n)
+	}
+
+	got, err := repo.GetByID(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if len(got.ChannelIDs) != 1 || got.ChannelIDs[0] != ch.ID {
+		t.Errorf("ChannelIDs = %v, want [%s]", got.ChannelIDs, ch.ID)
+	}
+	if got.RecipientCount != 1 {
+		t.Errorf("RecipientCount = %d, want 1", got.RecipientCount)
+	}
+}
+
+func TestRepository_Acknowledge(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	author := testutil.CreateTestUser(t, db, "author@example.com", "Author")
+	ws := testutil.CreateTestWorkspace(t, db, author.ID, "Test Workspace")
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+		VALUES ('wm-alice', ?, ?, 'member', datetime('now'), datetime('now'))
+	`, alice.ID, ws.ID); err != nil {
+		t.Fatalf("adding member: %v", err)
+	}
+
+	a := &Announcement{WorkspaceID: ws.ID, CreatedBy: &author.ID, Title: "Policy", Content: "Read me", ScheduledFor: time.Now()}
+	if err := repo.Create(ctx, a); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := repo.SnapshotRecipients(ctx, a); err != nil {
+		t.Fatalf("SnapshotRecipients() error = %v", err)
+	}
+
+	if err := repo.Acknowledge(ctx, a.ID, author.ID); !errors.Is(err, ErrNotRecipient) {
+		t.Errorf("Acknowledge(author) error = %v, want ErrNotRecipient", err)
+	}
+	if err := repo.Acknowledge(ctx, a.ID, alice.ID); err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	// Acknowledging again is a no-op
+	if err := repo.Acknowledge(ctx, a.ID, alice.ID); err != nil {
+		t.Fatalf("second Acknowledge() error = %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.AcknowledgedCount != 1 {
+		t.Errorf("AcknowledgedCount = %d, want 1", got.AcknowledgedCount)
+	}
+}
+
+func TestRepository_DeleteOnlyScheduled(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	author := testutil.CreateTestUser(t, db, "author@example.com", "Author")
+	ws := testutil.CreateTestWorkspace(t, db, author.ID, "Test Workspace")
+
+	a := &Announcement{WorkspaceID: ws.ID, CreatedBy: &author.ID, Title: "Policy", Content: "Read me", ScheduledFor: time.Now()}
+	if err := repo.Create(ctx, a); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	claimed, err := repo.MarkSending(ctx, a.ID)
+	if err != nil || !claimed {
+		t.Fatalf("MarkSending() = %v, %v", claimed, err)
+	}
+
+	if err := repo.Delete(ctx, a.ID); !errors.Is(err, ErrAnnouncementNotFound) {
+		t.Errorf("Delete() error = %v, want ErrAnnouncementNotFound", err)
+	}
+}
@@ -0,0 +1,64 @@
+package announcement
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Deliverer posts an announcement into its target channels.
+// Implemented by handler.Handler via DeliverAnnouncement.
+type Deliverer interface {
+	DeliverAnnouncement(ctx context.Context, a *Announcement) error
+}
+
+// Worker delivers announcements whose scheduled time has passed.
+type Worker struct {
+	repo      *Repository
+	deliverer Deliverer
+}
+
+// NewWorker creates a new announcement worker.
+func NewWorker(repo *Repository, deliverer Deliverer) *Worker {
+	return &Worker{
+		repo:      repo,
+		deliverer: deliverer,
+	}
+}
+
+// ProcessDue delivers all due announcements.
+func (w *Worker) ProcessDue(ctx context.Context) error {
+	announcements, err := w.repo.ListDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range announcements {
+		if err := Send(ctx, w.repo, w.deliverer, &announcements[i]); err != nil {
+			slog.Error("failed to deliver announcement",
+				"component", "announcement",
+				"id", announcements[i].ID,
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// Send claims a scheduled announcement, delivers it, and records the outcome.
+// It is a no-op if the announcement was already claimed. Delivery failures
+// are not retried: the announcement is marked failed with the error.
+func Send(ctx context.Context, repo *Repository, deliverer Deliverer, a *Announcement) error {
+	claimed, err := repo.MarkSending(ctx, a.ID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := deliverer.DeliverAnnouncement(ctx, a); err != nil {
+		if markErr := repo.MarkFailed(ctx, a.ID, err.Error()); markErr != nil {
+			slog.Error("failed to mark announcement as failed", "component", "announcement", "id", a.ID, "error", markErr)
+		}
+		return err
+	}
+
+	return repo.MarkSent(ctx, a.ID)
+}
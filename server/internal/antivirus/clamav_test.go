@@ -0,0 +1,100 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd accepts one INSTREAM scan and replies FOUND when the streamed
+// data contains "EICAR".
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				cmd, err := r.ReadString(0)
+				if err != nil || cmd != "zINSTREAM\x00" {
+					_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+				var data bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+						return
+					}
+				}
+				if strings.Contains(data.String(), "EICAR") {
+					_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				_, _ = conn.Write([]byte("stream: OK\x00"))
+			}(conn)
+		}
+	}()
+	return "tcp://" + ln.Addr().String()
+}
+
+func TestClamAV_Scan(t *testing.T) {
+	scanner, err := NewClamAV(fakeClamd(t), 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewClamAV: %v", err)
+	}
+
+	// Larger than one chunk so the stream is split
+	clean := bytes.Repeat([]byte("a"), chunkSize+10)
+	res, err := scanner.Scan(context.Background(), bytes.NewReader(clean))
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if res.Infected {
+		t.Errorf("expected clean file, got %+v", res)
+	}
+
+	res, err = scanner.Scan(context.Background(), strings.NewReader("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*"))
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !res.Infected || res.Signature != "Eicar-Test-Signature" {
+		t.Errorf("expected Eicar-Test-Signature, got %+v", res)
+	}
+}
+
+func TestNewClamAV_InvalidAddress(t *testing.T) {
+	for _, addr := range []string{"", "localhost:3310", "http://localhost:3310", "unix://"} {
+		if _, err := NewClamAV(addr, time.Second); err == nil {
+			t.Errorf("expected error for %q", addr)
+		}
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	if _, err := parseReply("INSTREAM size limit exceeded. ERROR"); err == nil {
+		t.Error("expected error for clamd error reply")
+	}
+}
@@ -12,21 +12,36 @@ import (
 	"strings"
 	"time"
 
+	"github.com/enzyme/server/internal/accountdeletion"
+	"github.com/enzyme/server/internal/activity"
+	"github.com/enzyme/server/internal/announcement"
+	"github.com/enzyme/server/internal/antivirus"
 	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/backup"
+	"github.com/enzyme/server/internal/bot"
+	"github.com/enzyme/server/internal/call"
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/channeltemplate"
 	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/contentfilter"
 	"github.com/enzyme/server/internal/database"
 	"github.com/enzyme/server/internal/email"
 	"github.com/enzyme/server/internal/emoji"
+	"github.com/enzyme/server/internal/export"
+	"github.com/enzyme/server/internal/features"
 	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/gc"
 	"github.com/enzyme/server/internal/handler"
 	"github.com/enzyme/server/internal/linkpreview"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/poll"
 	"github.com/enzyme/server/internal/presence"
 	"github.com/enzyme/server/internal/pushnotification"
+	"github.com/enzyme/server/internal/quickswitch"
 	"github.com/enzyme/server/internal/ratelimit"
+	"github.com/enzyme/server/internal/retention"
 	"github.com/enzyme/server/internal/scheduled"
 	"github.com/enzyme/server/internal/scheduler"
 	"github.com/enzyme/server/internal/server"
@@ -36,33 +51,57 @@ import (
 	"github.com/enzyme/server/internal/telemetry"
 	"github.com/enzyme/server/internal/thread"
 	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/usergroup"
 	"github.com/enzyme/server/internal/version"
 	"github.com/enzyme/server/internal/web"
+	"github.com/enzyme/server/internal/webhook"
 	"github.com/enzyme/server/internal/workspace"
 )
 
 type App struct {
-	Config                *config.Config
-	DB                    *database.DB
-	Server                *server.Server
-	Hub                   *sse.Hub
-	PresenceManager       *presence.Manager
-	EmailService          *email.Service
-	NotificationService   *notification.Service
-	EmailWorker           *notification.EmailWorker
-	RateLimiter           *ratelimit.Limiter
-	SessionStore          *auth.SessionStore
-	emailVerificationRepo *auth.EmailVerificationRepo
-	LinkPreviewRepo       *linkpreview.Repository
-	ScheduledWorker       *scheduled.Worker
-	passwordResetRepo     *auth.PasswordResetRepo
-	pushTokenRepo         *pushnotification.Repository
-	moderationRepo        *moderation.Repository
-	scheduler             *scheduler.Scheduler
-	Telemetry             *telemetry.Telemetry
+	Config              *config.Config
+	DB                  *database.DB
+	Server              *server.Server
+	Hub                 *sse.Hub
+	PresenceManager     *presence.Manager
+	EmailService        *email.Service
+	NotificationService *notification.Service
+	notificationQueue   *notification.Dispatcher
+	handler             *handler.Handler
+	EmailWorker         *notification.EmailWorker
+	RateLimiter         *ratelimit.Limiter
+	apiLimiter          *ratelimit.ClassLimiter
+	webhookLimiter      *ratelimit.Limiter
+	signupLimiter       *ratelimit.Limiter
+	SessionStore        *auth.SessionStore
+	LinkPreviewRepo     *linkpreview.Repository
+	ScheduledWorker     *scheduled.Worker
+	AnnouncementWorker  *announcement.Worker
+	PollWorker          *poll.Worker
+	CallWorker          *call.Worker
+	exportWorker        *export.Worker
+	deletionWorker      *accountdeletion.Worker
+	purger              *retention.Purger
+	archiver            *retention.Archiver
+	collector           *gc.Collector
+	pushTokenRepo       *pushnotification.Repository
+	moderationRepo      *moderation.Repository
+	scheduler           *scheduler.Scheduler
+	Telemetry           *telemetry.Telemetry
+	reloader            *reloader
 }
 
 func New(cfg *config.Config) (*App, error) {
+	// Keep the config as loaded for comparison on reload; New fills in
+	// generated values below
+	loaded := *cfg
+
+	// Slow-query log (disabled with a zero threshold)
+	var slowQueryLog *database.SlowQueryLog
+	if cfg.Database.SlowQueryThreshold > 0 {
+		slowQueryLog = database.NewSlowQueryLog(cfg.Database.SlowQueryThreshold)
+	}
+
 	// Open database
 	db, err := database.Open(cfg.Database.Path, database.Options{
 		MaxOpenConns:     cfg.Database.MaxOpenConns,
@@ -70,6 +109,7 @@ func New(cfg *config.Config) (*App, error) {
 		CacheSize:        cfg.Database.CacheSize,
 		MmapSize:         cfg.Database.MmapSize,
 		JournalSizeLimit: cfg.Database.JournalSizeLimit,
+		SlowQueryLog:     slowQueryLog,
 	})
 	if err != nil {
 		return nil, err
@@ -96,6 +136,19 @@ func New(cfg *config.Config) (*App, error) {
 
 	// Initialize SSE hub
 	hub := sse.NewHub(db.DB, cfg.SSE.EventRetention)
+	hub.SetOverflowPolicy(sse.OverflowPolicy(cfg.SSE.OverflowPolicy))
+	if cfg.SSE.Broadcast.Backend == "redis" {
+		connectCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		backend, err := sse.NewRedisBackend(connectCtx, cfg.SSE.Broadcast.RedisURL, cfg.SSE.Broadcast.Channel)
+		cancel()
+		if err != nil {
+			// Keep serving; only clients on other instances miss events
+			slog.Error("sse redis broadcast backend unavailable, falling back to in-memory", "error", err)
+		} else {
+			hub.SetBackend(backend)
+			slog.Info("sse broadcasts relayed through redis", "channel", cfg.SSE.Broadcast.Channel)
+		}
+	}
 
 	// Initialize presence manager
 	presenceManager := presence.NewManager(db.DB, hub)
@@ -114,21 +167,39 @@ func New(cfg *config.Config) (*App, error) {
 	workspaceRepo := workspace.NewRepository(db.DB)
 	channelRepo := channel.NewRepository(db.DB)
 	messageRepo := message.NewRepository(db.DB)
+	messageRepo.SetThreadParticipantPreview(cfg.Messages.ThreadParticipantPreview)
 	fileRepo := file.NewRepository(db.DB)
 	linkPreviewRepo := linkpreview.NewRepository(db.DB)
 	linkPreviewFetcher := linkpreview.NewFetcher(linkPreviewRepo)
+	linkPreviewFetcher.SetDomainLists(cfg.LinkPreviews.AllowedDomains, cfg.LinkPreviews.BlockedDomains)
 	emojiRepo := emoji.NewRepository(db.DB)
 	threadRepo := thread.NewRepository(db.DB)
 	scheduledRepo := scheduled.NewRepository(db.DB)
 	moderationRepo := moderation.NewRepository(db.DB)
+	webhookRepo := webhook.NewRepository(db.DB)
+	botRepo := bot.NewRepository(db.DB)
+	announcementRepo := announcement.NewRepository(db.DB)
+	exportRepo := export.NewRepository(db.DB)
+	retentionRepo := retention.NewRepository(db.DB)
+	quickSwitchRepo := quickswitch.NewRepository(db.DB)
+	activityRepo := activity.NewRepository(db.DB)
+	userGroupRepo := usergroup.NewRepository(db.DB)
+	channelTemplateRepo := channeltemplate.NewRepository(db.DB)
+	pollRepo := poll.NewRepository(db.DB)
+	callRepo := call.NewRepository(db.DB)
+	accountDeletionRepo := accountdeletion.NewRepository(db.DB)
+	contentFilterRepo := contentfilter.NewRepository(db.DB)
+	featuresRepo := features.NewRepository(db.DB)
 
 	// Initialize services
+	featureService := features.NewService(featuresRepo, cfg.Features)
 	authService := auth.NewService(userRepo, passwordResetRepo, emailVerificationRepo, cfg.Auth.BcryptCost)
 
 	// Initialize notification service
 	notificationPrefsRepo := notification.NewPreferencesRepository(db.DB)
 	notificationPendingRepo := notification.NewPendingRepository(db.DB)
-	notificationService := notification.NewService(notificationPrefsRepo, notificationPendingRepo, channelRepo, hub)
+	notificationKeywordRepo := notification.NewKeywordRepository(db.DB)
+	notificationService := notification.NewService(notificationPrefsRepo, notificationPendingRepo, notificationKeywordRepo, channelRepo, hub)
 	notificationService.SetThreadSubscriptionProvider(threadRepo)
 
 	// Initialize push notification service
@@ -140,11 +211,20 @@ func New(cfg *config.Config) (*App, error) {
 		slog.Info("push notifications enabled", "relay_url", cfg.PushNotifications.RelayURL)
 	}
 
+	// Notifications are delivered in the background from a bounded queue
+	notificationQueue := notification.NewDispatcher(notificationService, notification.DispatcherOptions{
+		Workers:      cfg.Notifications.Workers,
+		QueueSize:    cfg.Notifications.QueueSize,
+		MaxAttempts:  cfg.Notifications.MaxAttempts,
+		RetryBackoff: cfg.Notifications.RetryBackoff,
+	})
+	notificationService.SetDispatcher(notificationQueue)
+
 	// Initialize email worker
 	emailWorker := notification.NewEmailWorker(notificationPendingRepo, userRepo, emailService, hub)
 
 	// Initialize session store
-	sessionStore := auth.NewSessionStore(db.DB, cfg.Auth.SessionDuration)
+	sessionStore := auth.NewSessionStore(db.DB, cfg.Auth.SessionDuration, cfg.Auth.AccessTokenTTL)
 
 	// Initialize storage backend
 	var store storage.Storage
@@ -166,6 +246,17 @@ func New(cfg *config.Config) (*App, error) {
 		// store remains nil — upload endpoints return 403
 	}
 
+	// Initialize upload virus scanner (nil when no clamd is configured)
+	var scanner antivirus.Scanner
+	if store != nil && cfg.Storage.ClamAV.Address != "" {
+		clamav, err := antivirus.NewClamAV(cfg.Storage.ClamAV.Address, cfg.Storage.ClamAV.Timeout)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		scanner = clamav
+	}
+
 	// Initialize file URL signer (only needed for local storage)
 	if cfg.Storage.Type == "local" && cfg.Storage.Local.SigningSecret == "" {
 		secretPath := filepath.Join(filepath.Dir(cfg.Database.Path), ".signing_secret")
@@ -196,7 +287,19 @@ func New(cfg *config.Config) (*App, error) {
 	cfg.Server.PublicURL = strings.TrimRight(cfg.Server.PublicURL, "/")
 
 	// Initialize SSE handler (kept separate as it requires streaming)
-	sseHandler := sse.NewHandler(hub, workspaceRepo, channelRepo, cfg.SSE.HeartbeatInterval, cfg.SSE.ClientBufferSize)
+	sseHandler := sse.NewHandler(hub, workspaceRepo, channelRepo, cfg.SSE.HeartbeatInterval, cfg.SSE.ClientBufferSize, cfg.SSE.WriteTimeout)
+
+	// Per-webhook rate limiter (nil if rate limiting is disabled)
+	var webhookLimiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		webhookLimiter = ratelimit.NewLimiter(webhookRateLimitRules(cfg.RateLimit))
+	}
+
+	// Per-workspace open signup rate limiter (nil if rate limiting is disabled)
+	var signupLimiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		signupLimiter = ratelimit.NewLimiter(signupRateLimitRules(cfg.RateLimit))
+	}
 
 	// Initialize main handler implementing StrictServerInterface
 	h := handler.New(handler.Dependencies{
@@ -216,34 +319,89 @@ func New(cfg *config.Config) (*App, error) {
 		NotificationService: notificationService,
 		PushTokenRepo:       pushTokenRepo,
 		ModerationRepo:      moderationRepo,
+		WebhookRepo:         webhookRepo,
+		BotRepo:             botRepo,
+		AnnouncementRepo:    announcementRepo,
+		ExportRepo:          exportRepo,
+		RetentionRepo:       retentionRepo,
+		QuickSwitchRepo:     quickSwitchRepo,
+		ActivityRepo:        activityRepo,
+		UserGroupRepo:       userGroupRepo,
+		ChannelTemplateRepo: channelTemplateRepo,
+		PollRepo:            pollRepo,
+		CallRepo:            callRepo,
+		AccountDeletionRepo: accountDeletionRepo,
+		ContentFilterRepo:   contentFilterRepo,
+		ContentFilter:       contentfilter.NewFilter(contentFilterRepo),
+		Features:            featureService,
+		WebhookLimiter:      webhookLimiter,
+		SignupLimiter:       signupLimiter,
 		Hub:                 hub,
 		Signer:              signer,
 		Storage:             store,
+		Scanner:             scanner,
 		MaxUploadSize:       cfg.Storage.MaxUploadSize,
+		UploadSessionTTL:    cfg.Storage.UploadSessionTTL,
+		WorkspaceQuota:      cfg.Storage.WorkspaceQuota,
+		UndeleteWindow:      cfg.Messages.UndeleteWindow,
+		MaxMessageLength:    cfg.Messages.MaxLength,
+		ListContentLength:   cfg.Messages.ListContentLength,
+		SearchOffset:        cfg.Messages.SearchOffset,
+		ReadOnly:            cfg.Maintenance.ReadOnly,
+		ReadOnlyMessage:     cfg.Maintenance.Message,
 		PublicURL:           cfg.Server.PublicURL,
 	})
 
 	// Initialize scheduled message worker
 	scheduledWorker := scheduled.NewWorker(scheduledRepo, h)
 
+	// Initialize announcement delivery worker
+	announcementWorker := announcement.NewWorker(announcementRepo, h)
+
+	// Initialize worker that closes polls at their close time
+	pollWorker := poll.NewWorker(pollRepo, h)
+
+	// Initialize worker that removes call participants who disconnected
+	callWorker := call.NewWorker(callRepo, hub, h)
+
+	// Initialize workspace export worker (archives are kept in file storage)
+	var exportWorker *export.Worker
+	if store != nil {
+		exportWorker = export.NewWorker(exportRepo, store)
+	}
+
+	// Initialize worker that anonymizes accounts whose deletion was requested
+	deletionWorker := accountdeletion.NewWorker(accountDeletionRepo, moderationRepo, store)
+
+	// Initialize message retention purger
+	purger := retention.NewPurger(retentionRepo, store, hub)
+
+	// Initialize cold storage archiver (nil if disabled)
+	var archiver *retention.Archiver
+	if cfg.Messages.ColdStorageDays > 0 {
+		archiver = retention.NewArchiver(retentionRepo, cfg.Messages.ColdStorageDays)
+	}
+
+	// Initialize garbage collector
+	collector := gc.NewCollector(fileRepo, messageRepo, workspaceRepo, passwordResetRepo, emailVerificationRepo, store, cfg.GC.AttachmentTTL, cfg.Messages.UndeleteWindow)
+
 	// Build rate limiter (nil if disabled)
 	var limiter *ratelimit.Limiter
 	if cfg.RateLimit.Enabled {
-		rules := []ratelimit.Rule{
-			{Method: "POST", Path: "/api/auth/login", Limit: cfg.RateLimit.Login.Limit, Window: cfg.RateLimit.Login.Window},
-			{Method: "POST", Path: "/api/auth/register", Limit: cfg.RateLimit.Register.Limit, Window: cfg.RateLimit.Register.Window},
-			{Method: "POST", Path: "/api/auth/forgot-password", Limit: cfg.RateLimit.ForgotPassword.Limit, Window: cfg.RateLimit.ForgotPassword.Window},
-			{Method: "POST", Path: "/api/auth/reset-password", Limit: cfg.RateLimit.ResetPassword.Limit, Window: cfg.RateLimit.ResetPassword.Window},
-			{Method: "POST", Path: "/api/auth/verify-email", Limit: cfg.RateLimit.VerifyEmail.Limit, Window: cfg.RateLimit.VerifyEmail.Window},
-			{Method: "POST", Path: "/api/auth/resend-verification", Limit: cfg.RateLimit.ResendVerification.Limit, Window: cfg.RateLimit.ResendVerification.Window},
-			{Method: "POST", Path: "/api/auth/device-tokens", Limit: cfg.RateLimit.DeviceTokenRegister.Limit, Window: cfg.RateLimit.DeviceTokenRegister.Window},
-		}
-		limiter = ratelimit.NewLimiter(rules)
+		limiter = ratelimit.NewLimiter(authRateLimitRules(cfg.RateLimit))
+	}
+
+	// Per-user (or per-IP) token buckets by route class (nil if disabled)
+	var apiLimiter *ratelimit.ClassLimiter
+	if cfg.RateLimit.Enabled {
+		apiLimiter = ratelimit.NewClassLimiter(ratelimit.NewMemoryStore(), apiRateLimitClassifier(cfg.RateLimit))
 	}
 
 	// Create embedded SPA handler if web client is bundled
 	var spaHandler http.Handler
-	if web.HasContent() {
+	if !cfg.Server.ServeClient {
+		slog.Info("embedded web client disabled, serving the API only")
+	} else if web.HasContent() {
 		spaHandler = web.Handler(cfg.Telemetry.Enabled && cfg.Telemetry.Traces)
 		slog.Info("embedded web client enabled")
 	} else {
@@ -256,8 +414,43 @@ func New(cfg *config.Config) (*App, error) {
 		otlpProxy = telemetry.NewOTLPProxy(cfg.Telemetry)
 	}
 
+	// Serve the API reference if a spec path is configured
+	var apiDocs http.Handler
+	if cfg.Server.APIDocsSpec != "" {
+		apiDocs = server.NewAPIDocsHandler(cfg.Server.APIDocsSpec)
+		slog.Info("API docs enabled", "path", "/api/docs", "spec", cfg.Server.APIDocsSpec)
+	}
+
+	// Settings that can be changed on a running server with a config reload
+	reload := &reloader{
+		running:        &loaded,
+		live:           cfg,
+		handler:        h,
+		cors:           server.NewCORS(cfg.Server.AllowedOrigins, cfg.Telemetry.Enabled),
+		email:          emailService,
+		linkPreviews:   linkPreviewFetcher,
+		features:       featureService,
+		limiter:        limiter,
+		apiLimiter:     apiLimiter,
+		webhookLimiter: webhookLimiter,
+		signupLimiter:  signupLimiter,
+		audit:          moderationRepo,
+	}
+	app := &App{reloader: reload}
+
+	// The admin endpoints answer 404 unless an admin token is configured
+	admin := server.NewAdminHandler(cfg.Server.AdminToken, app.ReloadConfig, slowQueryLog)
+
 	// Create router with generated handlers
-	router := server.NewRouter(h, sseHandler, sessionStore, moderationRepo, limiter, cfg.Server.AllowedOrigins, cfg.Telemetry.Enabled, spaHandler, otlpProxy)
+	router := server.NewRouter(h, sseHandler, sessionStore, botRepo, moderationRepo, limiter, apiLimiter, server.RouterOptions{
+		CORS:             reload.cors,
+		CompressionLevel: cfg.Server.CompressionLevel,
+		TelemetryEnabled: cfg.Telemetry.Enabled,
+		Admin:            admin,
+		APIDocs:          apiDocs,
+		OTLPProxy:        otlpProxy,
+		SPA:              spaHandler,
+	})
 
 	// Build TLS options
 	tlsOpts := server.TLSOptions{
@@ -279,26 +472,39 @@ func New(cfg *config.Config) (*App, error) {
 	srv := server.New(cfg.Server.Host, cfg.Server.Port, router, tlsOpts,
 		cfg.Server.ReadTimeout, cfg.Server.WriteTimeout, cfg.Server.IdleTimeout)
 
-	return &App{
-		Config:                cfg,
-		DB:                    db,
-		Server:                srv,
-		Hub:                   hub,
-		PresenceManager:       presenceManager,
-		EmailService:          emailService,
-		NotificationService:   notificationService,
-		EmailWorker:           emailWorker,
-		RateLimiter:           limiter,
-		SessionStore:          sessionStore,
-		emailVerificationRepo: emailVerificationRepo,
-		LinkPreviewRepo:       linkPreviewRepo,
-		ScheduledWorker:       scheduledWorker,
-		passwordResetRepo:     passwordResetRepo,
-		pushTokenRepo:         pushTokenRepo,
-		moderationRepo:        moderationRepo,
-		scheduler:             scheduler.New(),
-		Telemetry:             tel,
-	}, nil
+	*app = App{
+		Config:              cfg,
+		DB:                  db,
+		Server:              srv,
+		Hub:                 hub,
+		PresenceManager:     presenceManager,
+		EmailService:        emailService,
+		NotificationService: notificationService,
+		notificationQueue:   notificationQueue,
+		handler:             h,
+		EmailWorker:         emailWorker,
+		RateLimiter:         limiter,
+		apiLimiter:          apiLimiter,
+		webhookLimiter:      webhookLimiter,
+		signupLimiter:       signupLimiter,
+		SessionStore:        sessionStore,
+		LinkPreviewRepo:     linkPreviewRepo,
+		ScheduledWorker:     scheduledWorker,
+		AnnouncementWorker:  announcementWorker,
+		PollWorker:          pollWorker,
+		CallWorker:          callWorker,
+		exportWorker:        exportWorker,
+		deletionWorker:      deletionWorker,
+		purger:              purger,
+		archiver:            archiver,
+		collector:           collector,
+		pushTokenRepo:       pushTokenRepo,
+		moderationRepo:      moderationRepo,
+		scheduler:           scheduler.New(),
+		Telemetry:           tel,
+		reloader:            reload,
+	}
+	return app, nil
 }
 
 func (a *App) Start(ctx context.Context) error {
@@ -308,12 +514,23 @@ func (a *App) Start(ctx context.Context) error {
 	// Start SSE hub (needs its own goroutine for client register/unregister channels)
 	go a.Hub.Run(ctx)
 
+	a.notificationQueue.Start()
+
 	// Register periodic tasks
 	s := a.scheduler
 
 	if a.RateLimiter != nil {
 		s.Register(scheduler.Task{Name: "rate-limiter-cleanup", Interval: 10 * time.Minute, Fn: func(ctx context.Context) error { a.RateLimiter.Cleanup(); return nil }})
 	}
+	if a.apiLimiter != nil {
+		s.Register(scheduler.Task{Name: "api-rate-limiter-cleanup", Interval: 10 * time.Minute, Fn: func(ctx context.Context) error { a.apiLimiter.Cleanup(); return nil }})
+	}
+	if a.webhookLimiter != nil {
+		s.Register(scheduler.Task{Name: "webhook-rate-limiter-cleanup", Interval: 10 * time.Minute, Fn: func(ctx context.Context) error { a.webhookLimiter.Cleanup(); return nil }})
+	}
+	if a.signupLimiter != nil {
+		s.Register(scheduler.Task{Name: "signup-rate-limiter-cleanup", Interval: 10 * time.Minute, Fn: func(ctx context.Context) error { a.signupLimiter.Cleanup(); return nil }})
+	}
 	s.Register(scheduler.Task{Name: "session-cleanup", Interval: time.Hour, Fn: func(ctx context.Context) error { return a.SessionStore.DeleteExpired() }})
 	s.Register(scheduler.Task{Name: "link-preview-cleanup", Interval: 24 * time.Hour, Fn: func(ctx context.Context) error { return a.LinkPreviewRepo.CleanExpiredCache(ctx) }})
 
@@ -323,13 +540,30 @@ func (a *App) Start(ctx context.Context) error {
 
 	s.Register(scheduler.Task{Name: "presence-check", Interval: 10 * time.Second, Fn: a.PresenceManager.CheckPresence})
 	s.Register(scheduler.Task{Name: "scheduled-messages", Interval: 30 * time.Second, Fn: a.ScheduledWorker.ProcessDue})
+	s.Register(scheduler.Task{Name: "announcements", Interval: 30 * time.Second, Fn: a.AnnouncementWorker.ProcessDue})
+	s.Register(scheduler.Task{Name: "poll-closing", Interval: 30 * time.Second, Fn: a.PollWorker.ProcessDue})
+	s.Register(scheduler.Task{Name: "call-cleanup", Interval: time.Minute, Fn: a.CallWorker.RemoveDisconnected})
+	if a.exportWorker != nil {
+		s.Register(scheduler.Task{Name: "workspace-exports", Interval: 30 * time.Second, Fn: a.exportWorker.ProcessPending, RunOnStart: true})
+	}
+	s.Register(scheduler.Task{Name: "account-deletions", Interval: 30 * time.Second, Fn: a.deletionWorker.ProcessPending, RunOnStart: true})
+	s.Register(scheduler.Task{Name: "message-retention", Interval: time.Hour, Fn: a.purger.Purge})
+	if a.archiver != nil {
+		s.Register(scheduler.Task{Name: "message-cold-storage", Interval: time.Hour, Fn: a.archiver.Archive})
+	}
 	s.Register(scheduler.Task{Name: "expired-ban-cleanup", Interval: time.Hour, Fn: a.moderationRepo.CleanupExpiredBans})
 	s.Register(scheduler.Task{Name: "sqlite-optimize", Interval: 24 * time.Hour, Fn: func(ctx context.Context) error { _, err := a.DB.Exec("PRAGMA optimize(0x10002)"); return err }})
 
 	if a.EmailService.IsEnabled() {
 		s.Register(scheduler.Task{Name: "email-notifications", Interval: time.Minute, Fn: a.EmailWorker.ProcessPending})
-		s.Register(scheduler.Task{Name: "password-reset-cleanup", Interval: 24 * time.Hour, Fn: a.passwordResetRepo.DeleteExpired})
-		s.Register(scheduler.Task{Name: "email-verification-cleanup", Interval: 24 * time.Hour, Fn: a.emailVerificationRepo.DeleteExpired})
+	}
+
+	if a.Config.GC.Interval > 0 {
+		s.Register(scheduler.Task{Name: "garbage-collection", Interval: a.Config.GC.Interval, Fn: a.collector.Run})
+	}
+
+	if a.Config.Backup.Interval > 0 {
+		s.Register(scheduler.Task{Name: "backup", Interval: a.Config.Backup.Interval, Fn: a.runBackup})
 	}
 
 	if a.pushTokenRepo != nil {
@@ -364,13 +598,46 @@ func (a *App) Start(ctx context.Context) error {
 	return a.Server.Start()
 }
 
+// runBackup writes a scheduled backup and prunes old ones.
+func (a *App) runBackup(ctx context.Context) error {
+	start := time.Now()
+	path, err := backup.Create(ctx, a.DB.DB, backup.UploadsDir(a.Config.Storage), a.Config.Backup.Dir, start)
+	if err != nil {
+		return err
+	}
+	slog.Info("backup created", "path", path, "duration", time.Since(start))
+
+	if a.Config.Backup.Keep > 0 {
+		removed, err := backup.Prune(a.Config.Backup.Dir, a.Config.Backup.Keep)
+		if err != nil {
+			return fmt.Errorf("pruning backups: %w", err)
+		}
+		if removed > 0 {
+			slog.Info("pruned old backups", "count", removed)
+		}
+	}
+	return nil
+}
+
 func (a *App) Shutdown(ctx context.Context) error {
-	// Stop scheduler first so in-flight tasks finish before DB closes
+	// Tell SSE clients to reconnect later and end their streams, which would
+	// otherwise hold the server open until the deadline
+	a.Hub.Drain()
+
+	// Stop scheduler so in-flight tasks finish before DB closes
 	a.scheduler.Stop(ctx)
 
 	if err := a.Server.Shutdown(ctx); err != nil {
 		return err
 	}
+	// Let async message sends finish, since they queue notifications
+	if err := a.handler.Wait(ctx); err != nil {
+		slog.Error("async message sends not finished before shutdown", "error", err)
+	}
+	// Deliver queued notifications now that nothing more can be sent
+	if err := a.notificationQueue.Shutdown(ctx); err != nil {
+		slog.Error("notification queue shutdown error", "error", err)
+	}
 	// Flush telemetry before closing database
 	if err := a.Telemetry.Shutdown(ctx); err != nil {
 		slog.Error("telemetry shutdown error", "error", err)
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/email"
+	"github.com/enzyme/server/internal/features"
+	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/linkpreview"
+	"github.com/enzyme/server/internal/logging"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/ratelimit"
+	"github.com/enzyme/server/internal/server"
+)
+
+// reloader applies a re-read configuration to the running components. Only
+// the settings config.CopyReloadable covers are applied; changes to anything
+// else are reported as needing a restart.
+type reloader struct {
+	mu      sync.Mutex
+	load    func() (*config.Config, error) // nil until SetConfigLoader
+	running *config.Config                 // as loaded, before New filled in defaults
+	live    *config.Config                 // the config the app was built with
+
+	handler        *handler.Handler
+	cors           *server.CORS
+	email          *email.Service
+	linkPreviews   *linkpreview.Fetcher
+	features       *features.Service
+	limiter        *ratelimit.Limiter
+	apiLimiter     *ratelimit.ClassLimiter
+	webhookLimiter *ratelimit.Limiter
+	signupLimiter  *ratelimit.Limiter
+	audit          *moderation.Repository
+}
+
+// SetConfigLoader sets how ReloadConfig reads the configuration, normally
+// the same file, environment and flags the server was started with.
+func (a *App) SetConfigLoader(load func() (*config.Config, error)) {
+	a.reloader.mu.Lock()
+	defer a.reloader.mu.Unlock()
+	a.reloader.load = load
+}
+
+// ReloadConfig re-reads the configuration and applies the log level, rate
+// limits, CORS origins, maintenance mode, link preview domain lists, search
+// offset support, feature flags and SMTP settings. The new config is
+// validated first; if it is invalid nothing changes. What changed is logged
+// and recorded in the server audit log with source, e.g. "sighup".
+func (a *App) ReloadConfig(ctx context.Context, source string) (config.ReloadResult, error) {
+	r := a.reloader
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.load == nil {
+		return config.ReloadResult{}, errors.New("config reload is not available")
+	}
+	loaded, err := r.load()
+	if err != nil {
+		slog.WarnContext(ctx, "config reload rejected", "source", source, "error", err)
+		return config.ReloadResult{}, err
+	}
+
+	next, result := config.Reload(r.running, loaded)
+	r.apply(next)
+	config.CopyReloadable(r.live, next)
+	r.running = next
+
+	changes := make([]string, len(result.Changed))
+	for i, c := range result.Changed {
+		changes[i] = fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+	}
+	// Handled directly so the entry is kept even when the new log level is
+	// above info
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "config reloaded", 0)
+	record.AddAttrs(
+		slog.String("source", source),
+		slog.Any("changed", changes),
+		slog.Any("restart_required", result.RestartRequired),
+	)
+	_ = slog.Default().Handler().Handle(ctx, record)
+
+	if err := r.audit.CreateServerAuditLogEntry(ctx, moderation.ActionConfigReloaded, source, map[string]interface{}{
+		"changed":          result.Changed,
+		"restart_required": result.RestartRequired,
+	}); err != nil {
+		slog.ErrorContext(ctx, "failed to record config reload in the audit log", "error", err)
+	}
+	return result, nil
+}
+
+// apply pushes the reloadable settings of cfg to the running components.
+func (r *reloader) apply(cfg *config.Config) {
+	logging.SetLevel(cfg.Log.Level)
+	r.cors.SetOrigins(cfg.Server.AllowedOrigins)
+	r.email.Reconfigure(cfg.Email)
+	r.linkPreviews.SetDomainLists(cfg.LinkPreviews.AllowedDomains, cfg.LinkPreviews.BlockedDomains)
+	r.handler.Configure(handlerSettings(cfg))
+	r.features.SetServerFlags(cfg.Features)
+
+	// The limiters only exist when rate limiting was on at startup
+	if r.limiter != nil {
+		r.limiter.SetRules(authRateLimitRules(cfg.RateLimit))
+	}
+	if r.apiLimiter != nil {
+		r.apiLimiter.SetClassifier(apiRateLimitClassifier(cfg.RateLimit))
+	}
+	if r.webhookLimiter != nil {
+		r.webhookLimiter.SetRules(webhookRateLimitRules(cfg.RateLimit))
+	}
+	if r.signupLimiter != nil {
+		r.signupLimiter.SetRules(signupRateLimitRules(cfg.RateLimit))
+	}
+}
+
+func handlerSettings(cfg *config.Config) handler.Settings {
+	return handler.Settings{
+		SearchOffset:    cfg.Messages.SearchOffset,
+		ReadOnly:        cfg.Maintenance.ReadOnly,
+		ReadOnlyMessage: cfg.Maintenance.Message,
+	}
+}
+
+// authRateLimitRules are the per-IP limits on the auth endpoints.
+func authRateLimitRules(cfg config.RateLimitConfig) []ratelimit.Rule {
+	return []ratelimit.Rule{
+		{Method: "POST", Path: "/api/auth/login", Limit: cfg.Login.Limit, Window: cfg.Login.Window},
+		{Method: "POST", Path: "/api/auth/register", Limit: cfg.Register.Limit, Window: cfg.Register.Window},
+		{Method: "POST", Path: "/api/auth/forgot-password", Limit: cfg.ForgotPassword.Limit, Window: cfg.ForgotPassword.Window},
+		{Method: "POST", Path: "/api/auth/reset-password", Limit: cfg.ResetPassword.Limit, Window: cfg.ResetPassword.Window},
+		{Method: "POST", Path: "/api/auth/verify-email", Limit: cfg.VerifyEmail.Limit, Window: cfg.VerifyEmail.Window},
+		{Method: "POST", Path: "/api/auth/resend-verification", Limit: cfg.ResendVerification.Limit, Window: cfg.ResendVerification.Window},
+		{Method: "POST", Path: "/api/auth/device-tokens", Limit: cfg.DeviceTokenRegister.Limit, Window: cfg.DeviceTokenRegister.Window},
+	}
+}
+
+// apiRateLimitClassifier sorts API requests into the per-user token bucket
+// classes.
+func apiRateLimitClassifier(cfg config.RateLimitConfig) ratelimit.Classifier {
+	return server.APIRateLimitClassifier(
+		ratelimit.Class{Name: "auth", Limit: cfg.Auth.Limit, Window: cfg.Auth.Window},
+		ratelimit.Class{Name: "send_message", Limit: cfg.SendMessage.Limit, Window: cfg.SendMessage.Window},
+		ratelimit.Class{Name: "api", Limit: cfg.API.Limit, Window: cfg.API.Window},
+	)
+}
+
+func webhookRateLimitRules(cfg config.RateLimitConfig) []ratelimit.Rule {
+	return []ratelimit.Rule{
+		{Method: "POST", Path: handler.WebhookRateLimitPath, Limit: cfg.IncomingWebhook.Limit, Window: cfg.IncomingWebhook.Window},
+	}
+}
+
+func signupRateLimitRules(cfg config.RateLimitConfig) []ratelimit.Rule {
+	return []ratelimit.Rule{
+		{Method: "POST", Path: handler.SignupRateLimitPath, Limit: cfg.OpenSignup.Limit, Window: cfg.OpenSignup.Window},
+	}
+}
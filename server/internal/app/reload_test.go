@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/email"
+	"github.com/enzyme/server/internal/features"
+	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/linkpreview"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/server"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestReloadConfig_AuditLog(t *testing.T) {
+	db := testutil.TestDB(t)
+	running := config.Defaults()
+	loaded := config.Defaults()
+	loaded.Maintenance.ReadOnly = true
+	loaded.Email.Password = "hunter2"
+	loaded.Server.Port = 9090
+
+	app := &App{reloader: &reloader{
+		load:         func() (*config.Config, error) { return loaded, nil },
+		running:      running,
+		live:         config.Defaults(),
+		handler:      handler.New(handler.Dependencies{}),
+		cors:         server.NewCORS(nil, false),
+		email:        email.NewTestService(false, ""),
+		linkPreviews: linkpreview.NewFetcher(linkpreview.NewRepository(db)),
+		features:     features.NewService(features.NewRepository(db), nil),
+		audit:        moderation.NewRepository(db),
+	}}
+
+	if _, err := app.ReloadConfig(context.Background(), "sighup"); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+
+	var action, source, metadata string
+	if err := db.QueryRow(`SELECT action, source, metadata FROM server_audit_log`).Scan(&action, &source, &metadata); err != nil {
+		t.Fatalf("reading the audit log: %v", err)
+	}
+	if action != moderation.ActionConfigReloaded || source != "sighup" {
+		t.Errorf("entry = %s from %s, want %s from sighup", action, source, moderation.ActionConfigReloaded)
+	}
+
+	var recorded config.ReloadResult
+	if err := json.Unmarshal([]byte(metadata), &recorded); err != nil {
+		t.Fatalf("decoding metadata %s: %v", metadata, err)
+	}
+	want := []config.Change{
+		{Key: "email.password", Old: "[redacted]", New: "[redacted]"},
+		{Key: "maintenance.read_only", Old: "false", New: "true"},
+	}
+	if len(recorded.Changed) != len(want) {
+		t.Fatalf("changed = %+v, want %+v", recorded.Changed, want)
+	}
+	for i := range want {
+		if recorded.Changed[i] != want[i] {
+			t.Errorf("changed[%d] = %+v, want %+v", i, recorded.Changed[i], want[i])
+		}
+	}
+	if len(recorded.RestartRequired) != 1 || recorded.RestartRequired[0] != "server.port" {
+		t.Errorf("restart_required = %v, want [server.port]", recorded.RestartRequired)
+	}
+}
@@ -83,9 +83,12 @@ func (r *EmailVerificationRepo) DeleteForUser(ctx context.Context, userID string
 	return nil
 }
 
-func (r *EmailVerificationRepo) DeleteExpired(ctx context.Context) error {
-	if _, err := r.db.ExecContext(ctx, `DELETE FROM email_verifications WHERE expires_at < ?`, time.Now().UTC().Format(time.RFC3339)); err != nil {
-		return fmt.Errorf("deleting expired verification tokens: %w", err)
+// DeleteExpired removes verification tokens that have passed their expiry and
+// returns how many were deleted.
+func (r *EmailVerificationRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM email_verifications WHERE expires_at < ?`, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired verification tokens: %w", err)
 	}
-	return nil
+	return result.RowsAffected()
 }
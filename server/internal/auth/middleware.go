@@ -5,22 +5,41 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+
+	"github.com/enzyme/server/internal/bot"
 )
 
 type contextKey string
 
 const (
-	userIDKey contextKey = "user_id"
-	tokenKey  contextKey = "auth_token"
+	userIDKey    contextKey = "user_id"
+	tokenKey     contextKey = "auth_token"
+	botScopesKey contextKey = "bot_scopes"
 )
 
+// BotTokenValidator resolves a bot API token to the bot's user ID and the
+// token's scopes.
+type BotTokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (string, []string, error)
+}
+
 // TokenMiddleware extracts a bearer token, validates it, and sets user ID + token in context.
-// Passes through if no token is present (does not reject).
-func TokenMiddleware(store *SessionStore) func(http.Handler) http.Handler {
+// Bot tokens are validated against bots (if non-nil) and additionally set the
+// token's scopes in context. Passes through if no token is present (does not reject).
+func TokenMiddleware(store *SessionStore, bots BotTokenValidator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token := extractBearerToken(r)
-			if token != "" {
+			if token != "" && bot.IsToken(token) {
+				if bots != nil {
+					userID, scopes, err := bots.ValidateToken(r.Context(), token)
+					if err == nil && userID != "" {
+						ctx := context.WithValue(r.Context(), userIDKey, userID)
+						ctx = context.WithValue(ctx, botScopesKey, scopes)
+						r = r.WithContext(ctx)
+					}
+				}
+			} else if token != "" {
 				userID, err := store.Validate(token)
 				if err == nil && userID != "" {
 					ctx := context.WithValue(r.Context(), userIDKey, userID)
@@ -46,6 +65,20 @@ func RequireAuth() func(http.Handler) http.Handler {
 	}
 }
 
+// RejectBotTokens rejects requests authenticated with a bot token. Use it on
+// routes that have no corresponding bot scope.
+func RejectBotTokens() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetBotScopes(r.Context()); ok {
+				writeError(w, http.StatusForbidden, "PERMISSION_DENIED", "Bot tokens cannot access this endpoint")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // WithUserID returns a context with the given user ID set (for testing).
 func WithUserID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, userIDKey, id)
@@ -68,6 +101,19 @@ func GetToken(ctx context.Context) string {
 	return token
 }
 
+// WithBotScopes returns a context marking the request as bot-authenticated
+// with the given scopes (for testing).
+func WithBotScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, botScopesKey, scopes)
+}
+
+// GetBotScopes returns the scopes of the bot token that authenticated the
+// request. ok is false for requests not authenticated with a bot token.
+func GetBotScopes(ctx context.Context) (scopes []string, ok bool) {
+	scopes, ok = ctx.Value(botScopesKey).([]string)
+	return scopes, ok
+}
+
 // extractBearerToken checks the Authorization header only.
 func extractBearerToken(r *http.Request) string {
 	auth := r.Header.Get("Authorization")
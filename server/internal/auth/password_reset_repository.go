@@ -59,9 +59,13 @@ func (r *PasswordResetRepo) MarkUsed(ctx context.Context, id string) error {
 	return err
 }
 
-// DeleteExpired removes password reset tokens that have passed their expiry.
-func (r *PasswordResetRepo) DeleteExpired(ctx context.Context) error {
+// DeleteExpired removes password reset tokens that have passed their expiry
+// and returns how many were deleted.
+func (r *PasswordResetRepo) DeleteExpired(ctx context.Context) (int64, error) {
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := r.db.ExecContext(ctx, `DELETE FROM password_resets WHERE expires_at < ?`, now)
-	return err
+	result, err := r.db.ExecContext(ctx, `DELETE FROM password_resets WHERE expires_at < ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
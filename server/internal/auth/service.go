@@ -95,10 +95,15 @@ func (s *Service) Login(ctx context.Context, input LoginInput) (*user.User, erro
 		return nil, err
 	}
 
-	if u.Status == "deactivated" {
+	if u.Status == user.StatusDeactivated {
 		return nil, ErrUserDeactivated
 	}
 
+	// Bot accounts authenticate with API tokens only.
+	if u.IsBot {
+		return nil, ErrInvalidCredentials
+	}
+
 	if !CheckPassword(input.Password, u.PasswordHash) {
 		return nil, ErrInvalidCredentials
 	}
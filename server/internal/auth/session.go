@@ -2,47 +2,180 @@ package auth
 
 import (
 	"crypto/rand"
-	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
 	"errors"
 	"time"
+
+	"github.com/enzyme/server/internal/signing"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
 )
 
-var ErrSessionNotFound = errors.New("session not found")
+// lastSeenInterval limits how often Validate writes last_seen_at, so an
+// active client does not turn every request into a database write.
+const lastSeenInterval = time.Minute
 
+// SessionStore manages login sessions. A session is a refresh token family:
+// it lasts for lifetime from the last refresh, while each access token it
+// hands out lasts for accessTTL.
 type SessionStore struct {
-	db       *sql.DB
-	lifetime time.Duration
+	db        *sql.DB
+	lifetime  time.Duration
+	accessTTL time.Duration
 }
 
-func NewSessionStore(db *sql.DB, lifetime time.Duration) *SessionStore {
-	return &SessionStore{db: db, lifetime: lifetime}
+// NewSessionStore creates a SessionStore. An accessTTL of zero makes access
+// tokens last as long as the session, so clients never need to refresh.
+func NewSessionStore(db *sql.DB, lifetime, accessTTL time.Duration) *SessionStore {
+	if accessTTL == 0 || accessTTL > lifetime {
+		accessTTL = lifetime
+	}
+	return &SessionStore{db: db, lifetime: lifetime, accessTTL: accessTTL}
 }
 
-// Create inserts a new session and returns the plaintext token.
-// Only the SHA-256 hash is stored in the database.
-func (s *SessionStore) Create(userID string) (string, error) {
-	token := generateSessionToken()
-	expiry := time.Now().Add(s.lifetime).UTC().Format(time.RFC3339)
+// Tokens is the plaintext credential pair handed to a client on login or refresh.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time // when AccessToken expires
+}
+
+// ClientInfo describes the client a session was created from.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session is an active login session. The token itself is never exposed;
+// sessions are addressed by ID.
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	Current    bool
+}
+
+// Create inserts a new session and returns its plaintext tokens.
+// Only the SHA-256 hashes are stored in the database.
+func (s *SessionStore) Create(userID string, client ClientInfo) (Tokens, error) {
+	now := time.Now().UTC()
+	tokens := s.newTokens(now)
 
 	_, err := s.db.Exec(
-		"INSERT INTO sessions (token, user_id, expiry) VALUES (?, ?, ?)",
-		hashToken(token), userID, expiry,
+		`INSERT INTO sessions (token, id, user_id, expiry, refresh_token, refresh_expiry, created_at, last_seen_at, user_agent, ip_address)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		signing.HashToken(tokens.AccessToken), ulid.Make().String(), userID, tokens.ExpiresAt.Format(time.RFC3339),
+		signing.HashToken(tokens.RefreshToken), now.Add(s.lifetime).Format(time.RFC3339),
+		now.Format(time.RFC3339), now.Format(time.RFC3339), client.UserAgent, client.IPAddress,
 	)
 	if err != nil {
-		return "", err
+		return Tokens{}, err
+	}
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// The old refresh token is retired; presenting it again returns
+// ErrRefreshTokenReused and revokes the whole session, since it means the
+// token was copied by someone else.
+func (s *SessionStore) Refresh(refreshToken string) (Tokens, error) {
+	hashed := signing.HashToken(refreshToken)
+	var id, refreshExpiryStr string
+	err := s.db.QueryRow(
+		"SELECT id, refresh_expiry FROM sessions WHERE refresh_token = ?", hashed,
+	).Scan(&id, &refreshExpiryStr)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Tokens{}, s.revokeIfRotated(hashed)
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	refreshExpiry, err := time.Parse(time.RFC3339, refreshExpiryStr)
+	if err != nil {
+		return Tokens{}, err
+	}
+	now := time.Now().UTC()
+	if now.After(refreshExpiry) {
+		_, _ = s.db.Exec("DELETE FROM sessions WHERE id = ?", id)
+		return Tokens{}, ErrSessionNotFound
+	}
+
+	tokens := s.newTokens(now)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Tokens{}, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`UPDATE sessions SET token = ?, expiry = ?, refresh_token = ?, refresh_expiry = ?, last_seen_at = ?
+		 WHERE id = ? AND refresh_token = ?`,
+		signing.HashToken(tokens.AccessToken), tokens.ExpiresAt.Format(time.RFC3339),
+		signing.HashToken(tokens.RefreshToken), now.Add(s.lifetime).Format(time.RFC3339), now.Format(time.RFC3339),
+		id, hashed,
+	)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// A concurrent refresh rotated the token first
+		return Tokens{}, ErrSessionNotFound
+	}
+	if _, err := tx.Exec(
+		"INSERT INTO rotated_refresh_tokens (token, session_id, rotated_at) VALUES (?, ?, ?)",
+		hashed, id, now.Format(time.RFC3339),
+	); err != nil {
+		return Tokens{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return Tokens{}, err
+	}
+	return tokens, nil
+}
+
+// revokeIfRotated deletes the session a retired refresh token belonged to and
+// returns ErrRefreshTokenReused, or ErrSessionNotFound if the token is unknown.
+func (s *SessionStore) revokeIfRotated(hashed string) error {
+	var sessionID string
+	err := s.db.QueryRow("SELECT session_id FROM rotated_refresh_tokens WHERE token = ?", hashed).Scan(&sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("DELETE FROM sessions WHERE id = ?", sessionID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *SessionStore) newTokens(now time.Time) Tokens {
+	return Tokens{
+		AccessToken:  generateSessionToken(),
+		RefreshToken: generateSessionToken(),
+		ExpiresAt:    now.Add(s.accessTTL),
 	}
-	return token, nil
 }
 
 // Validate looks up a session by its hashed token and returns the user ID if valid.
+// Revoked sessions are deleted, so they fail here like unknown tokens.
 func (s *SessionStore) Validate(token string) (string, error) {
-	hashed := hashToken(token)
-	var userID, expiryStr string
+	hashed := signing.HashToken(token)
+	var userID, expiryStr, lastSeenStr string
 	err := s.db.QueryRow(
-		"SELECT user_id, expiry FROM sessions WHERE token = ?", hashed,
-	).Scan(&userID, &expiryStr)
+		"SELECT user_id, expiry, last_seen_at FROM sessions WHERE token = ?", hashed,
+	).Scan(&userID, &expiryStr, &lastSeenStr)
 	if errors.Is(err, sql.ErrNoRows) {
 		return "", ErrSessionNotFound
 	}
@@ -54,24 +187,96 @@ func (s *SessionStore) Validate(token string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if time.Now().After(expiry) {
-		// Clean up expired session
-		_, _ = s.db.Exec("DELETE FROM sessions WHERE token = ?", hashed)
+	now := time.Now()
+	if now.After(expiry) {
+		// Clean up the session too if it can no longer be refreshed
+		_, _ = s.db.Exec("DELETE FROM sessions WHERE token = ? AND refresh_expiry < ?", hashed, now.UTC().Format(time.RFC3339))
 		return "", ErrSessionNotFound
 	}
 
+	if lastSeen, err := time.Parse(time.RFC3339, lastSeenStr); err != nil || now.Sub(lastSeen) >= lastSeenInterval {
+		_, _ = s.db.Exec("UPDATE sessions SET last_seen_at = ? WHERE token = ?", now.UTC().Format(time.RFC3339), hashed)
+	}
+
 	return userID, nil
 }
 
 // Delete removes a session by its hashed token.
 func (s *SessionStore) Delete(token string) error {
-	_, err := s.db.Exec("DELETE FROM sessions WHERE token = ?", hashToken(token))
+	_, err := s.db.Exec("DELETE FROM sessions WHERE token = ?", signing.HashToken(token))
 	return err
 }
 
-// DeleteExpired removes all expired sessions.
+// ListForUser returns the user's unexpired sessions, most recently active first.
+// A session is unexpired while it can still be refreshed.
+// The session belonging to currentToken is flagged as Current.
+func (s *SessionStore) ListForUser(userID, currentToken string) ([]Session, error) {
+	rows, err := s.db.Query(`
+		SELECT token, id, user_id, user_agent, ip_address, created_at, last_seen_at, refresh_expiry
+		FROM sessions
+		WHERE user_id = ? AND refresh_expiry >= ?
+		ORDER BY last_seen_at DESC, created_at DESC
+	`, userID, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	currentHash := signing.HashToken(currentToken)
+	var sessions []Session
+	for rows.Next() {
+		var sess Session
+		var hashed, createdAt, lastSeenAt, expiry string
+		if err := rows.Scan(&hashed, &sess.ID, &sess.UserID, &sess.UserAgent, &sess.IPAddress, &createdAt, &lastSeenAt, &expiry); err != nil {
+			return nil, err
+		}
+		sess.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		sess.LastSeenAt, _ = time.Parse(time.RFC3339, lastSeenAt)
+		sess.ExpiresAt, _ = time.Parse(time.RFC3339, expiry)
+		sess.Current = currentToken != "" && hashed == currentHash
+		sessions = append(sessions, sess)
+	}
+	return sessions, rows.Err()
+}
+
+// DeleteByID revokes one of the user's sessions. It returns ErrSessionNotFound
+// if the session does not exist or belongs to someone else.
+func (s *SessionStore) DeleteByID(userID, id string) error {
+	res, err := s.db.Exec("DELETE FROM sessions WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteOthers revokes all of the user's sessions except the one for keepToken
+// and returns how many were removed.
+func (s *SessionStore) DeleteOthers(userID, keepToken string) (int, error) {
+	res, err := s.db.Exec("DELETE FROM sessions WHERE user_id = ? AND token != ?", userID, signing.HashToken(keepToken))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// DeleteAllForUser revokes every session of the user and returns how many
+// were removed.
+func (s *SessionStore) DeleteAllForUser(userID string) (int, error) {
+	res, err := s.db.Exec("DELETE FROM sessions WHERE user_id = ?", userID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// DeleteExpired removes all sessions that can no longer be refreshed.
 func (s *SessionStore) DeleteExpired() error {
-	_, err := s.db.Exec("DELETE FROM sessions WHERE expiry < ?", time.Now().UTC().Format(time.RFC3339))
+	_, err := s.db.Exec("DELETE FROM sessions WHERE refresh_expiry < ?", time.Now().UTC().Format(time.RFC3339))
 	return err
 }
 
@@ -80,9 +285,3 @@ func generateSessionToken() string {
 	rand.Read(b)
 	return hex.EncodeToString(b)
 }
-
-// hashToken returns the hex-encoded SHA-256 hash of a plaintext token.
-func hashToken(token string) string {
-	h := sha256.Sum256([]byte(token))
-	return hex.EncodeToString(h[:])
-}
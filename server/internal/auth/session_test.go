@@ -9,15 +9,16 @@ import (
 
 func TestSessionStore_CreateAndValidate(t *testing.T) {
 	db := testutil.TestDB(t)
-	store := NewSessionStore(db, 24*time.Hour)
+	store := NewSessionStore(db, 24*time.Hour, 0)
 
-	token, err := store.Create("user-123")
+	tokens, err := store.Create("user-123", ClientInfo{})
 	if err != nil {
 		t.Fatalf("Create: %v", err)
 	}
-	if token == "" {
-		t.Fatal("expected non-empty token")
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatal("expected non-empty tokens")
 	}
+	token := tokens.AccessToken
 
 	userID, err := store.Validate(token)
 	if err != nil {
@@ -30,12 +31,13 @@ func TestSessionStore_CreateAndValidate(t *testing.T) {
 
 func TestSessionStore_ValidateExpired(t *testing.T) {
 	db := testutil.TestDB(t)
-	store := NewSessionStore(db, -1*time.Hour) // already expired
+	store := NewSessionStore(db, -1*time.Hour, 0) // already expired
 
-	token, err := store.Create("user-123")
+	tokens, err := store.Create("user-123", ClientInfo{})
 	if err != nil {
 		t.Fatalf("Create: %v", err)
 	}
+	token := tokens.AccessToken
 
 	_, err = store.Validate(token)
 	if err != ErrSessionNotFound {
@@ -45,12 +47,13 @@ func TestSessionStore_ValidateExpired(t *testing.T) {
 
 func TestSessionStore_Delete(t *testing.T) {
 	db := testutil.TestDB(t)
-	store := NewSessionStore(db, 24*time.Hour)
+	store := NewSessionStore(db, 24*time.Hour, 0)
 
-	token, err := store.Create("user-123")
+	tokens, err := store.Create("user-123", ClientInfo{})
 	if err != nil {
 		t.Fatalf("Create: %v", err)
 	}
+	token := tokens.AccessToken
 
 	if err := store.Delete(token); err != nil {
 		t.Fatalf("Delete: %v", err)
@@ -64,9 +67,9 @@ func TestSessionStore_Delete(t *testing.T) {
 
 func TestSessionStore_DeleteExpired(t *testing.T) {
 	db := testutil.TestDB(t)
-	store := NewSessionStore(db, -1*time.Hour) // already expired
+	store := NewSessionStore(db, -1*time.Hour, 0) // already expired
 
-	_, err := store.Create("user-123")
+	_, err := store.Create("user-123", ClientInfo{})
 	if err != nil {
 		t.Fatalf("Create: %v", err)
 	}
@@ -88,10 +91,192 @@ func TestSessionStore_DeleteExpired(t *testing.T) {
 
 func TestSessionStore_ValidateNonexistent(t *testing.T) {
 	db := testutil.TestDB(t)
-	store := NewSessionStore(db, 24*time.Hour)
+	store := NewSessionStore(db, 24*time.Hour, 0)
 
 	_, err := store.Validate("nonexistent-token")
 	if err != ErrSessionNotFound {
 		t.Fatalf("expected ErrSessionNotFound, got %v", err)
 	}
 }
+
+func TestSessionStore_ListAndRevoke(t *testing.T) {
+	db := testutil.TestDB(t)
+	store := NewSessionStore(db, 24*time.Hour, 0)
+
+	create := func(userID string, client ClientInfo) string {
+		tokens, err := store.Create(userID, client)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return tokens.AccessToken
+	}
+	laptop := create("user-123", ClientInfo{UserAgent: "Firefox/128.0", IPAddress: "203.0.113.7"})
+	phone := create("user-123", ClientInfo{UserAgent: "Android"})
+	tablet := create("user-123", ClientInfo{})
+	other := create("user-456", ClientInfo{})
+
+	sessions, err := store.ListForUser("user-123", laptop)
+	if err != nil {
+		t.Fatalf("ListForUser: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected 3 sessions, got %d", len(sessions))
+	}
+	var current *Session
+	for i := range sessions {
+		if sessions[i].Current {
+			current = &sessions[i]
+		}
+	}
+	if current == nil || current.UserAgent != "Firefox/128.0" || current.IPAddress != "203.0.113.7" {
+		t.Fatalf("expected the laptop session to be current, got %+v", current)
+	}
+
+	// Another user's session cannot be revoked by ID
+	otherSessions, _ := store.ListForUser("user-456", other)
+	if err := store.DeleteByID("user-123", otherSessions[0].ID); err != ErrSessionNotFound {
+		t.Errorf("expected ErrSessionNotFound for another user's session, got %v", err)
+	}
+
+	for _, s := range sessions {
+		if s.UserAgent == "Android" {
+			if err := store.DeleteByID("user-123", s.ID); err != nil {
+				t.Fatalf("DeleteByID: %v", err)
+			}
+		}
+	}
+	if _, err := store.Validate(phone); err != ErrSessionNotFound {
+		t.Errorf("expected revoked token to be rejected, got %v", err)
+	}
+
+	revoked, err := store.DeleteOthers("user-123", laptop)
+	if err != nil || revoked != 1 {
+		t.Fatalf("DeleteOthers = %d, %v; want 1", revoked, err)
+	}
+	if _, err := store.Validate(tablet); err != ErrSessionNotFound {
+		t.Error("expected other sessions to be revoked")
+	}
+	if _, err := store.Validate(laptop); err != nil {
+		t.Errorf("expected the kept session to stay valid, got %v", err)
+	}
+	if _, err := store.Validate(other); err != nil {
+		t.Errorf("expected other users' sessions to be untouched, got %v", err)
+	}
+
+	revoked, err = store.DeleteAllForUser("user-123")
+	if err != nil || revoked != 1 {
+		t.Fatalf("DeleteAllForUser = %d, %v; want 1", revoked, err)
+	}
+	if _, err := store.Validate(laptop); err != ErrSessionNotFound {
+		t.Error("expected every session to be revoked")
+	}
+	if _, err := store.Validate(other); err != nil {
+		t.Errorf("expected other users' sessions to be untouched, got %v", err)
+	}
+}
+
+func TestSessionStore_ValidateUpdatesLastSeen(t *testing.T) {
+	db := testutil.TestDB(t)
+	store := NewSessionStore(db, 24*time.Hour, 0)
+
+	tokens, err := store.Create("user-123", ClientInfo{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	token := tokens.AccessToken
+	stale := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	if _, err := db.Exec("UPDATE sessions SET last_seen_at = ?", stale); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+
+	if _, err := store.Validate(token); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	sessions, _ := store.ListForUser("user-123", token)
+	if len(sessions) != 1 || time.Since(sessions[0].LastSeenAt) > time.Minute {
+		t.Errorf("expected last_seen_at to be refreshed, got %+v", sessions)
+	}
+}
+
+func TestSessionStore_Refresh(t *testing.T) {
+	db := testutil.TestDB(t)
+	store := NewSessionStore(db, 24*time.Hour, 15*time.Minute)
+
+	first, err := store.Create("user-123", ClientInfo{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if d := time.Until(first.ExpiresAt); d > 15*time.Minute || d < 14*time.Minute {
+		t.Errorf("expected access token to expire in 15m, got %v", d)
+	}
+
+	second, err := store.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.AccessToken == first.AccessToken || second.RefreshToken == first.RefreshToken {
+		t.Fatal("expected refresh to rotate both tokens")
+	}
+	if _, err := store.Validate(first.AccessToken); err != ErrSessionNotFound {
+		t.Errorf("expected the old access token to be rejected, got %v", err)
+	}
+	if userID, err := store.Validate(second.AccessToken); err != nil || userID != "user-123" {
+		t.Errorf("Validate(new access token) = %q, %v", userID, err)
+	}
+
+	sessions, _ := store.ListForUser("user-123", second.AccessToken)
+	if len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("expected refresh to keep a single current session, got %+v", sessions)
+	}
+
+	if _, err := store.Refresh("unknown"); err != ErrSessionNotFound {
+		t.Errorf("expected ErrSessionNotFound for an unknown token, got %v", err)
+	}
+}
+
+func TestSessionStore_RefreshReuseRevokesFamily(t *testing.T) {
+	db := testutil.TestDB(t)
+	store := NewSessionStore(db, 24*time.Hour, 15*time.Minute)
+
+	first, _ := store.Create("user-123", ClientInfo{})
+	second, err := store.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	unrelated, _ := store.Create("user-123", ClientInfo{})
+
+	if _, err := store.Refresh(first.RefreshToken); err != ErrRefreshTokenReused {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, err := store.Validate(second.AccessToken); err != ErrSessionNotFound {
+		t.Errorf("expected reuse to revoke the current access token, got %v", err)
+	}
+	if _, err := store.Refresh(second.RefreshToken); err != ErrSessionNotFound {
+		t.Errorf("expected reuse to revoke the current refresh token, got %v", err)
+	}
+	if _, err := store.Validate(unrelated.AccessToken); err != nil {
+		t.Errorf("expected other sessions to be untouched, got %v", err)
+	}
+}
+
+func TestSessionStore_ExpiredAccessTokenKeepsSession(t *testing.T) {
+	db := testutil.TestDB(t)
+	store := NewSessionStore(db, 24*time.Hour, 15*time.Minute)
+
+	tokens, _ := store.Create("user-123", ClientInfo{})
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	if _, err := db.Exec("UPDATE sessions SET expiry = ?", past); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+
+	if _, err := store.Validate(tokens.AccessToken); err != ErrSessionNotFound {
+		t.Fatalf("expected expired access token to be rejected, got %v", err)
+	}
+	refreshed, err := store.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected the session to survive access token expiry, got %v", err)
+	}
+	if _, err := store.Validate(refreshed.AccessToken); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import "strings"
+
+// DescribeUserAgent turns a User-Agent header into a short label such as
+// "Firefox on Windows" for the sessions list. Unrecognised agents are
+// described as well as possible, or "Unknown device".
+func DescribeUserAgent(ua string) string {
+	browser := detectBrowser(ua)
+	platform := detectOS(ua)
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
+
+func detectBrowser(ua string) string {
+	// Order matters: Edge and Opera include "Chrome", and Chrome includes "Safari"
+	switch {
+	case strings.Contains(ua, "Edg/"):
+		return "Edge"
+	case strings.Contains(ua, "OPR/"):
+		return "Opera"
+	case strings.Contains(ua, "Firefox/"):
+		return "Firefox"
+	case strings.Contains(ua, "Chrome/"), strings.Contains(ua, "CriOS/"):
+		return "Chrome"
+	case strings.Contains(ua, "Safari/"):
+		return "Safari"
+	case strings.HasPrefix(ua, "curl/"):
+		return "curl"
+	}
+	return ""
+}
+
+func detectOS(ua string) string {
+	// iOS and Android user agents also mention macOS and Linux respectively
+	switch {
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"):
+		return "iOS"
+	case strings.Contains(ua, "Android"):
+		return "Android"
+	case strings.Contains(ua, "Windows"):
+		return "Windows"
+	case strings.Contains(ua, "Mac OS X"), strings.Contains(ua, "Macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	}
+	return ""
+}
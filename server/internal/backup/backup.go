@@ -0,0 +1,425 @@
+// Package backup creates and restores archives of a server's data: a
+// consistent copy of the SQLite database taken while the server runs, plus
+// locally stored uploads.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/database"
+)
+
+// formatVersion is bumped when the archive layout changes incompatibly.
+const formatVersion = 1
+
+// Archive layout
+const (
+	manifestName  = "manifest.json"
+	databaseName  = "enzyme.db"
+	uploadsPrefix = "uploads/"
+)
+
+const (
+	filePrefix = "enzyme-backup-"
+	fileSuffix = ".tar.gz"
+	timeLayout = "20060102T150405Z"
+)
+
+var (
+	// ErrTargetExists is returned by Restore when the database or uploads
+	// already exist and Force is not set.
+	ErrTargetExists = errors.New("restore target already exists")
+	// ErrNewerSchema is returned by Restore for backups taken by a newer
+	// version of the server.
+	ErrNewerSchema = errors.New("backup is from a newer server version")
+	// ErrInvalidArchive is returned by Restore for archives it did not create.
+	ErrInvalidArchive = errors.New("not a valid backup archive")
+)
+
+// Manifest describes an archive. It is stored as its first entry.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	SchemaVersion int64     `json:"schema_version"`
+	Uploads       bool      `json:"uploads"`
+}
+
+// UploadsDir returns the directory holding uploads for storage, or "" when
+// they are not on the local filesystem. S3 buckets are backed up separately.
+func UploadsDir(storage config.StorageConfig) string {
+	if storage.Type != "local" {
+		return ""
+	}
+	return storage.Local.Path
+}
+
+// Create writes a backup archive named after now into dir and returns its
+// path. The database is copied with VACUUM INTO, which is safe while the
+// server is running. If uploadsDir is not empty its files are included.
+func Create(ctx context.Context, db *sql.DB, uploadsDir, dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	stamp := now.UTC().Format(timeLayout)
+	dbCopy := filepath.Join(dir, "."+filePrefix+stamp+".db")
+	_ = os.Remove(dbCopy)
+	defer os.Remove(dbCopy)
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, dbCopy); err != nil {
+		return "", fmt.Errorf("copying database: %w", err)
+	}
+
+	schemaVersion, err := database.SchemaVersion(db)
+	if err != nil {
+		return "", fmt.Errorf("reading schema version: %w", err)
+	}
+	manifest := Manifest{
+		FormatVersion: formatVersion,
+		CreatedAt:     now.UTC(),
+		SchemaVersion: schemaVersion,
+		Uploads:       uploadsDir != "",
+	}
+
+	final := filepath.Join(dir, filePrefix+stamp+fileSuffix)
+	partial := final + ".partial"
+	if err := writeArchive(ctx, partial, manifest, dbCopy, uploadsDir); err != nil {
+		_ = os.Remove(partial)
+		return "", err
+	}
+	if err := os.Rename(partial, final); err != nil {
+		_ = os.Remove(partial)
+		return "", fmt.Errorf("finishing archive: %w", err)
+	}
+	return final, nil
+}
+
+func writeArchive(ctx context.Context, name string, manifest Manifest, dbCopy, uploadsDir string) (err error) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("closing archive: %w", cerr)
+		}
+	}()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0o600, Size: int64(len(manifestJSON)), ModTime: manifest.CreatedAt}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestJSON); err != nil {
+		return err
+	}
+
+	if err := addFile(tw, dbCopy, databaseName); err != nil {
+		return fmt.Errorf("adding database: %w", err)
+	}
+
+	if uploadsDir != "" {
+		err := filepath.WalkDir(uploadsDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && p == uploadsDir {
+					return fs.SkipAll
+				}
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// Symlinks and other special files are not part of the upload store
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(uploadsDir, p)
+			if err != nil {
+				return err
+			}
+			return addFile(tw, p, uploadsPrefix+filepath.ToSlash(rel))
+		})
+		if err != nil {
+			return fmt.Errorf("adding uploads: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, src, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: info.Size(), ModTime: info.ModTime()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Prune deletes the oldest archives in dir so that at most keep remain, and
+// returns how many were deleted. Files not created by Create are left alone.
+func Prune(dir string, keep int) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var archives []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasPrefix(e.Name(), filePrefix) && strings.HasSuffix(e.Name(), fileSuffix) {
+			archives = append(archives, e.Name())
+		}
+	}
+	if len(archives) <= keep {
+		return 0, nil
+	}
+	// Names embed a sortable UTC timestamp
+	slices.Sort(archives)
+	removed := 0
+	for _, name := range archives[:len(archives)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RestoreOptions controls Restore.
+type RestoreOptions struct {
+	// DatabasePath is where the database is restored to.
+	DatabasePath string
+	// UploadsDir is where uploads are restored to. Leave empty to skip them.
+	UploadsDir string
+	// Force replaces an existing database and uploads directory. They are
+	// renamed with a .pre-restore suffix rather than deleted.
+	Force bool
+}
+
+// Restore unpacks archive into place. The server must not be running. The
+// archive is fully extracted and the database checked before anything
+// existing is touched.
+func Restore(archive string, opts RestoreOptions) (*Manifest, error) {
+	restoreUploads := opts.UploadsDir != ""
+	if !opts.Force {
+		if exists(opts.DatabasePath) {
+			return nil, fmt.Errorf("%w: %s", ErrTargetExists, opts.DatabasePath)
+		}
+		if restoreUploads && !emptyDir(opts.UploadsDir) {
+			return nil, fmt.Errorf("%w: %s", ErrTargetExists, opts.UploadsDir)
+		}
+	}
+
+	// Extract next to the database so the final moves are renames
+	dbDir := filepath.Dir(opts.DatabasePath)
+	if err := os.MkdirAll(dbDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating database directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(dbDir, ".enzyme-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	manifest, err := extract(archive, staging)
+	if err != nil {
+		return nil, err
+	}
+	stagedDB := filepath.Join(staging, databaseName)
+	if err := checkDatabase(stagedDB, manifest); err != nil {
+		return nil, err
+	}
+
+	suffix := ".pre-restore-" + time.Now().UTC().Format(timeLayout)
+	if opts.Force {
+		for _, p := range []string{opts.DatabasePath, opts.DatabasePath + "-wal", opts.DatabasePath + "-shm"} {
+			if err := moveAside(p, suffix); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := os.Rename(stagedDB, opts.DatabasePath); err != nil {
+		return nil, fmt.Errorf("moving database into place: %w", err)
+	}
+
+	if restoreUploads && manifest.Uploads {
+		if opts.Force {
+			if err := moveAside(opts.UploadsDir, suffix); err != nil {
+				return nil, err
+			}
+		} else if err := os.Remove(opts.UploadsDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		stagedUploads := filepath.Join(staging, strings.TrimSuffix(uploadsPrefix, "/"))
+		if err := os.MkdirAll(stagedUploads, 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(opts.UploadsDir), 0o755); err != nil {
+			return nil, err
+		}
+		// A rename across filesystems fails; uploads often live on their own volume
+		if err := os.Rename(stagedUploads, opts.UploadsDir); err != nil {
+			if err := os.CopyFS(opts.UploadsDir, os.DirFS(stagedUploads)); err != nil {
+				return nil, fmt.Errorf("copying uploads into place: %w", err)
+			}
+		}
+	}
+	return manifest, nil
+}
+
+// extract unpacks archive into dir and returns its manifest. Entries outside
+// the expected layout are rejected.
+func extract(archive, dir string) (*Manifest, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	hasDatabase := false
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: unexpected entry type for %q", ErrInvalidArchive, hdr.Name)
+		}
+
+		switch {
+		case hdr.Name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("%w: reading manifest: %v", ErrInvalidArchive, err)
+			}
+			if manifest.FormatVersion != formatVersion {
+				return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, manifest.FormatVersion)
+			}
+			continue
+		case hdr.Name == databaseName:
+			hasDatabase = true
+		case strings.HasPrefix(hdr.Name, uploadsPrefix):
+			clean := path.Clean(hdr.Name)
+			if clean != hdr.Name || !strings.HasPrefix(clean, uploadsPrefix) || !filepath.IsLocal(clean) {
+				return nil, fmt.Errorf("%w: unsafe path %q", ErrInvalidArchive, hdr.Name)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected entry %q", ErrInvalidArchive, hdr.Name)
+		}
+
+		dest := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, err
+		}
+		out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(out, tr)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("extracting %s: %w", hdr.Name, err)
+		}
+	}
+
+	if manifest == nil || !hasDatabase {
+		return nil, fmt.Errorf("%w: missing manifest or database", ErrInvalidArchive)
+	}
+	return manifest, nil
+}
+
+// checkDatabase verifies the extracted database is intact and not newer than
+// this binary's migrations.
+func checkDatabase(p string, manifest *Manifest) error {
+	db, err := sql.Open("sqlite", p)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow(`PRAGMA integrity_check`).Scan(&result); err != nil {
+		return fmt.Errorf("checking database integrity: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%w: database failed integrity check: %s", ErrInvalidArchive, result)
+	}
+
+	version, err := database.SchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	latest, err := database.LatestMigrationVersion()
+	if err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("%w: schema version %d, this server supports up to %d", ErrNewerSchema, version, latest)
+	}
+	if version != manifest.SchemaVersion {
+		return fmt.Errorf("%w: manifest schema version %d does not match database version %d", ErrInvalidArchive, manifest.SchemaVersion, version)
+	}
+	return nil
+}
+
+func moveAside(p, suffix string) error {
+	if !exists(p) {
+		return nil
+	}
+	if err := os.Rename(p, p+suffix); err != nil {
+		return fmt.Errorf("moving aside %s: %w", p, err)
+	}
+	return nil
+}
+
+func exists(p string) bool {
+	_, err := os.Lstat(p)
+	return err == nil
+}
+
+func emptyDir(p string) bool {
+	entries, err := os.ReadDir(p)
+	return err != nil || len(entries) == 0
+}
@@ -0,0 +1,161 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func writeFile(t *testing.T, p, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	db := testutil.TestDB(t)
+	user := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+
+	root := t.TempDir()
+	uploads := filepath.Join(root, "uploads")
+	writeFile(t, filepath.Join(uploads, "ws1", "a.txt"), "hello")
+
+	archive, err := Create(context.Background(), db, uploads, filepath.Join(root, "backups"), time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if filepath.Base(archive) != "enzyme-backup-20260301T120000Z.tar.gz" {
+		t.Errorf("archive name = %q", filepath.Base(archive))
+	}
+
+	target := filepath.Join(root, "restored")
+	dbPath := filepath.Join(target, "enzyme.db")
+	restoredUploads := filepath.Join(target, "uploads")
+	manifest, err := Restore(archive, RestoreOptions{DatabasePath: dbPath, UploadsDir: restoredUploads})
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if manifest.SchemaVersion == 0 {
+		t.Error("expected the manifest to record the schema version")
+	}
+
+	restored, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	var email string
+	if err := restored.QueryRow(`SELECT email FROM users WHERE id = ?`, user.ID).Scan(&email); err != nil {
+		t.Fatalf("reading restored user: %v", err)
+	}
+	if email != "alice@example.com" {
+		t.Errorf("email = %q", email)
+	}
+	if data, err := os.ReadFile(filepath.Join(restoredUploads, "ws1", "a.txt")); err != nil || string(data) != "hello" {
+		t.Errorf("restored upload = %q, %v", data, err)
+	}
+
+	// Existing data is not replaced without Force
+	_, err = Restore(archive, RestoreOptions{DatabasePath: dbPath, UploadsDir: restoredUploads})
+	if !errors.Is(err, ErrTargetExists) {
+		t.Fatalf("Restore() over existing data error = %v, want ErrTargetExists", err)
+	}
+
+	// With Force the old data is kept aside
+	if _, err := Restore(archive, RestoreOptions{DatabasePath: dbPath, UploadsDir: restoredUploads, Force: true}); err != nil {
+		t.Fatalf("Restore(Force) error = %v", err)
+	}
+	aside, _ := filepath.Glob(dbPath + ".pre-restore-*")
+	if len(aside) != 1 {
+		t.Errorf("expected the previous database to be kept, found %v", aside)
+	}
+	aside, _ = filepath.Glob(restoredUploads + ".pre-restore-*")
+	if len(aside) != 1 {
+		t.Errorf("expected the previous uploads to be kept, found %v", aside)
+	}
+}
+
+func TestRestore_RejectsNewerSchema(t *testing.T) {
+	db := testutil.TestDB(t)
+	if _, err := db.Exec(`INSERT INTO goose_db_version (version_id, is_applied) VALUES (999999, 1)`); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	archive, err := Create(context.Background(), db, "", root, time.Now())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	dbPath := filepath.Join(root, "restored", "enzyme.db")
+	if _, err := Restore(archive, RestoreOptions{DatabasePath: dbPath}); !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("Restore() error = %v, want ErrNewerSchema", err)
+	}
+	if _, err := os.Stat(dbPath); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected nothing to be restored")
+	}
+}
+
+func TestRestore_RejectsUnsafePaths(t *testing.T) {
+	root := t.TempDir()
+	archive := filepath.Join(root, "evil.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	body := []byte("x")
+	if err := tw.WriteHeader(&tar.Header{Name: "uploads/../../escape.txt", Mode: 0o600, Size: int64(len(body))}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tw.Write(body)
+	_ = tw.Close()
+	_ = gz.Close()
+	_ = f.Close()
+
+	_, err = Restore(archive, RestoreOptions{DatabasePath: filepath.Join(root, "db", "enzyme.db")})
+	if !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("Restore() error = %v, want ErrInvalidArchive", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("archive entry escaped the staging directory")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"enzyme-backup-20260101T000000Z.tar.gz",
+		"enzyme-backup-20260102T000000Z.tar.gz",
+		"enzyme-backup-20260103T000000Z.tar.gz",
+		"notes.txt",
+	} {
+		writeFile(t, filepath.Join(dir, name), "x")
+	}
+
+	removed, err := Prune(dir, 2)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "enzyme-backup-20260101T000000Z.tar.gz")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the oldest archive to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("expected unrelated files to be kept")
+	}
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes that can be granted to a bot token. Each scope unlocks a fixed set
+// of API operations; requests outside a token's scopes are rejected.
+const (
+	ScopeChatWrite       = "chat:write"
+	ScopeReactionsWrite  = "reactions:write"
+	ScopeChannelsRead    = "channels:read"
+	ScopeChannelsHistory = "channels:history"
+	ScopeChannelsJoin    = "channels:join"
+	ScopeUsersRead       = "users:read"
+)
+
+// AllScopes lists every scope a bot token may be granted.
+var AllScopes = []string{
+	ScopeChatWrite,
+	ScopeReactionsWrite,
+	ScopeChannelsRead,
+	ScopeChannelsHistory,
+	ScopeChannelsJoin,
+	ScopeUsersRead,
+}
+
+// TokenPrefix distinguishes bot tokens from session tokens in the
+// Authorization header.
+const TokenPrefix = "bot_"
+
+// Bot is a non-human workspace member backed by a user row with is_bot set.
+// Bots authenticate with scoped API tokens instead of sessions.
+type Bot struct {
+	UserID      string    `json:"user_id"`
+	WorkspaceID string    `json:"workspace_id"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   *string   `json:"avatar_url,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	CreatedBy   *string   `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Token is an API token issued to a bot. Only the SHA-256 hash of the token
+// is persisted; the plaintext token is returned once at creation time.
+type Token struct {
+	ID         string     `json:"id"`
+	BotUserID  string     `json:"bot_user_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  *string    `json:"created_by,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsValidScope reports whether s is a known bot token scope.
+func IsValidScope(s string) bool {
+	for _, scope := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsToken reports whether an Authorization bearer value looks like a bot token.
+func IsToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
@@ -0,0 +1,297 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/signing"
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrBotNotFound   = errors.New("bot not found")
+	ErrTokenNotFound = errors.New("bot token not found")
+)
+
+const botColumns = `b.user_id, b.workspace_id, u.display_name, u.avatar_url, b.description, b.created_by, b.created_at`
+
+const tokenColumns = `id, bot_user_id, name, scopes, created_by, last_used_at, created_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create inserts the bot's user row, its bot record, and a member-role
+// workspace membership in a single transaction. Bot users have no password
+// and a placeholder email, so they cannot sign in.
+func (r *Repository) Create(ctx context.Context, bot *Bot) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "bot.Create")
+	defer func() { endSpan(err) }()
+
+	bot.UserID = ulid.Make().String()
+	now := time.Now().UTC()
+	bot.CreatedAt = now
+	ts := now.Format(time.RFC3339)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO users (id, email, password_hash, display_name, avatar_url, status, is_bot, created_at, updated_at)
+		VALUES (?, ?, '', ?, ?, 'active', 1, ?, ?)
+	`, bot.UserID, strings.ToLower(bot.UserID)+"@bots.invalid", bot.DisplayName, bot.AvatarURL, ts, ts)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO bots (user_id, workspace_id, description, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, bot.UserID, bot.WorkspaceID, bot.Description, bot.CreatedBy, ts)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+		VALUES (?, ?, ?, 'member', ?, ?)
+	`, ulid.Make().String(), bot.UserID, bot.WorkspaceID, ts, ts)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetByUserID(ctx context.Context, userID string) (*Bot, error) {
+	return scanBot(r.db.QueryRowContext(ctx, `
+		SELECT `+botColumns+`
+		FROM bots b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.user_id = ?
+	`, userID))
+}
+
+func (r *Repository) ListByWorkspace(ctx context.Context, workspaceID string) ([]Bot, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+botColumns+`
+		FROM bots b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.workspace_id = ?
+		ORDER BY b.created_at ASC, b.user_id ASC
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bots := []Bot{}
+	for rows.Next() {
+		bot, err := scanBot(rows)
+		if err != nil {
+			return nil, err
+		}
+		bots = append(bots, *bot)
+	}
+	return bots, rows.Err()
+}
+
+// Update saves the bot's display name, avatar, and description.
+func (r *Repository) Update(ctx context.Context, bot *Bot) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE bots SET description = ? WHERE user_id = ?`, bot.Description, bot.UserID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrBotNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users SET display_name = ?, avatar_url = ?, updated_at = ? WHERE id = ?
+	`, bot.DisplayName, bot.AvatarURL, time.Now().UTC().Format(time.RFC3339), bot.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the bot's user row. Its bot record, tokens, and memberships
+// are removed by cascade; messages it posted remain with no author.
+func (r *Repository) Delete(ctx context.Context, userID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = ? AND is_bot = 1`, userID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrBotNotFound
+	}
+	return nil
+}
+
+// CreateToken issues a new token for a bot and returns the plaintext token.
+// The token is not recoverable afterwards.
+func (r *Repository) CreateToken(ctx context.Context, tok *Token) (_ string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "bot.CreateToken")
+	defer func() { endSpan(err) }()
+
+	token := generateToken()
+	tok.ID = ulid.Make().String()
+	tok.CreatedAt = time.Now().UTC()
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO bot_tokens (id, bot_user_id, name, token_hash, scopes, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, tok.ID, tok.BotUserID, tok.Name, signing.HashToken(token), strings.Join(tok.Scopes, " "), tok.CreatedBy, tok.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *Repository) GetToken(ctx context.Context, id string) (*Token, error) {
+	return scanToken(r.db.QueryRowContext(ctx, `SELECT `+tokenColumns+` FROM bot_tokens WHERE id = ?`, id))
+}
+
+func (r *Repository) ListTokens(ctx context.Context, botUserID string) ([]Token, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+tokenColumns+`
+		FROM bot_tokens WHERE bot_user_id = ? ORDER BY created_at ASC, id ASC
+	`, botUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []Token{}
+	for rows.Next() {
+		tok, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *tok)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *Repository) RevokeToken(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM bot_tokens WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// ValidateToken looks up a bot token by its plaintext value and returns the
+// bot's user ID and the token's scopes. Tokens belonging to deactivated bots
+// are rejected. A successful lookup records the token as used.
+func (r *Repository) ValidateToken(ctx context.Context, token string) (_ string, _ []string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "bot.ValidateToken")
+	defer func() { endSpan(err) }()
+
+	hashed := signing.HashToken(token)
+	var userID, scopes string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT t.bot_user_id, t.scopes
+		FROM bot_tokens t
+		JOIN users u ON u.id = t.bot_user_id
+		WHERE t.token_hash = ? AND u.status = 'active'
+	`, hashed).Scan(&userID, &scopes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, _ = r.db.ExecContext(ctx, `
+		UPDATE bot_tokens SET last_used_at = ? WHERE token_hash = ?
+	`, time.Now().UTC().Format(time.RFC3339), hashed)
+
+	return userID, strings.Fields(scopes), nil
+}
+
+func scanBot(row interface{ Scan(dest ...any) error }) (*Bot, error) {
+	var bot Bot
+	var avatarURL, description, createdBy sql.NullString
+	var createdAt string
+
+	err := row.Scan(&bot.UserID, &bot.WorkspaceID, &bot.DisplayName, &avatarURL, &description, &createdBy, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrBotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if avatarURL.Valid {
+		bot.AvatarURL = &avatarURL.String
+	}
+	if description.Valid {
+		bot.Description = &description.String
+	}
+	if createdBy.Valid {
+		bot.CreatedBy = &createdBy.String
+	}
+	bot.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &bot, nil
+}
+
+func scanToken(row interface{ Scan(dest ...any) error }) (*Token, error) {
+	var tok Token
+	var scopes string
+	var createdBy, lastUsedAt sql.NullString
+	var createdAt string
+
+	err := row.Scan(&tok.ID, &tok.BotUserID, &tok.Name, &scopes, &createdBy, &lastUsedAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tok.Scopes = strings.Fields(scopes)
+	if createdBy.Valid {
+		tok.CreatedBy = &createdBy.String
+	}
+	if lastUsedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, lastUsedAt.String)
+		tok.LastUsedAt = &t
+	}
+	tok.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+
+	return &tok, nil
+}
+
+func generateToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return TokenPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestRepository_CreateAddsWorkspaceMember(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+
+	b := &Bot{WorkspaceID: ws.ID, DisplayName: "Standup Bot", CreatedBy: &owner.ID}
+	if err := repo.Create(ctx, b); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var role string
+	var isBot bool
+	err := db.QueryRow(`
+		SELECT wm.role, u.is_bot FROM workspace_memberships wm JOIN users u ON u.id = wm.user_id
+		WHERE wm.user_id = ? AND wm.workspace_id = ?
+	`, b.UserID, ws.ID).Scan(&role, &isBot)
+	if err != nil {
+		t.Fatalf("query membership: %v", err)
+	}
+	if role != "member" || !isBot {
+		t.Errorf("role = %q, is_bot = %v; want member, true", role, isBot)
+	}
+
+	got, err := repo.GetByUserID(ctx, b.UserID)
+	if err != nil {
+		t.Fatalf("GetByUserID() error = %v", err)
+	}
+	if got.DisplayName != "Standup Bot" {
+		t.Errorf("DisplayName = %q, want %q", got.DisplayName, "Standup Bot")
+	}
+}
+
+func TestRepository_ValidateToken(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	b := &Bot{WorkspaceID: ws.ID, DisplayName: "Standup Bot"}
+	if err := repo.Create(ctx, b); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tok := &Token{BotUserID: b.UserID, Name: "prod", Scopes: []string{ScopeChatWrite, ScopeChannelsRead}}
+	secret, err := repo.CreateToken(ctx, tok)
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if !IsToken(secret) {
+		t.Errorf("token %q missing prefix %q", secret, TokenPrefix)
+	}
+
+	userID, scopes, err := repo.ValidateToken(ctx, secret)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if userID != b.UserID {
+		t.Errorf("userID = %q, want %q", userID, b.UserID)
+	}
+	if len(scopes) != 2 || scopes[0] != ScopeChatWrite || scopes[1] != ScopeChannelsRead {
+		t.Errorf("scopes = %v", scopes)
+	}
+
+	if _, _, err := repo.ValidateToken(ctx, TokenPrefix+"bogus"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("ValidateToken(bogus) error = %v, want %v", err, ErrTokenNotFound)
+	}
+
+	if err := repo.RevokeToken(ctx, tok.ID); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	if _, _, err := repo.ValidateToken(ctx, secret); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("ValidateToken(revoked) error = %v, want %v", err, ErrTokenNotFound)
+	}
+}
+
+func TestRepository_DeleteRevokesTokens(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	b := &Bot{WorkspaceID: ws.ID, DisplayName: "Standup Bot"}
+	if err := repo.Create(ctx, b); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	secret, err := repo.CreateToken(ctx, &Token{BotUserID: b.UserID, Name: "prod", Scopes: []string{ScopeChatWrite}})
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+
+	if err := repo.Delete(ctx, b.UserID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, _, err := repo.ValidateToken(ctx, secret); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("ValidateToken() error = %v, want %v", err, ErrTokenNotFound)
+	}
+	if err := repo.Delete(ctx, owner.ID); !errors.Is(err, ErrBotNotFound) {
+		t.Errorf("Delete(human) error = %v, want %v", err, ErrBotNotFound)
+	}
+}
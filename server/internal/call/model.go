@@ -0,0 +1,60 @@
+package call
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrCallNotFound   = errors.New("call not found")
+	ErrCallInProgress = errors.New("a call is already in progress in this channel")
+	ErrCallEnded      = errors.New("call has ended")
+)
+
+// Signal types relayed between participants
+const (
+	SignalOffer        = "offer"
+	SignalAnswer       = "answer"
+	SignalICECandidate = "ice_candidate"
+)
+
+// Call is a voice/video call in a channel. The server tracks who is in the
+// call and relays signaling between them; media does not pass through it.
+type Call struct {
+	ID           string        `json:"id"`
+	WorkspaceID  string        `json:"workspace_id"`
+	ChannelID    string        `json:"channel_id"`
+	StartedBy    *string       `json:"started_by,omitempty"`
+	MessageID    *string       `json:"message_id,omitempty"`
+	StartedAt    time.Time     `json:"started_at"`
+	EndedAt      *time.Time    `json:"ended_at,omitempty"`
+	Participants []Participant `json:"participants"`
+}
+
+// Participant is a user who joined a call. LeftAt is set once they leave.
+type Participant struct {
+	UserID   string     `json:"user_id"`
+	JoinedAt time.Time  `json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at,omitempty"`
+}
+
+// IsActiveParticipant reports whether userID is currently in the call.
+func (c *Call) IsActiveParticipant(userID string) bool {
+	for _, p := range c.Participants {
+		if p.UserID == userID && p.LeftAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveParticipantCount returns how many users are currently in the call.
+func (c *Call) ActiveParticipantCount() int {
+	n := 0
+	for _, p := range c.Participants {
+		if p.LeftAt == nil {
+			n++
+		}
+	}
+	return n
+}
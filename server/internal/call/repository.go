@@ -0,0 +1,267 @@
+package call
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+const callColumns = `id, workspace_id, channel_id, started_by, message_id, started_at, ended_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create starts a call with its starter as the first participant. Returns
+// ErrCallInProgress if the channel already has a call in progress.
+func (r *Repository) Create(ctx context.Context, c *Call) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "call.Create")
+	defer func() { endSpan(err) }()
+
+	c.ID = ulid.Make().String()
+	c.StartedAt = time.Now().UTC()
+	now := c.StartedAt.Format(time.RFC3339)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO calls (id, workspace_id, channel_id, started_by, started_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, c.ID, c.WorkspaceID, c.ChannelID, c.StartedBy, now)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrCallInProgress
+		}
+		return err
+	}
+
+	c.Participants = []Participant{}
+	if c.StartedBy != nil {
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO call_participants (call_id, user_id, joined_at) VALUES (?, ?, ?)
+		`, c.ID, *c.StartedBy, now); err != nil {
+			return err
+		}
+		c.Participants = append(c.Participants, Participant{UserID: *c.StartedBy, JoinedAt: c.StartedAt})
+	}
+
+	return tx.Commit()
+}
+
+// SetMessageID links a call to the system message that announces it.
+func (r *Repository) SetMessageID(ctx context.Context, id, messageID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE calls SET message_id = ? WHERE id = ?`, messageID, id)
+	return err
+}
+
+// GetByID returns a call with its participants.
+func (r *Repository) GetByID(ctx context.Context, id string) (*Call, error) {
+	c, err := scanCall(r.db.QueryRowContext(ctx, `SELECT `+callColumns+` FROM calls WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCallNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadParticipants(ctx, []*Call{c}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListActive returns all calls in progress with their participants.
+func (r *Repository) ListActive(ctx context.Context) ([]*Call, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+callColumns+` FROM calls WHERE ended_at IS NULL ORDER BY started_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calls []*Call
+	for rows.Next() {
+		c, err := scanCall(rows)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.loadParticipants(ctx, calls); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// Join adds the user to a call in progress, or brings them back if they left.
+func (r *Repository) Join(ctx context.Context, id, userID string) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "call.Join")
+	defer func() { endSpan(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var endedAt sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT ended_at FROM calls WHERE id = ?`, id).Scan(&endedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCallNotFound
+		}
+		return err
+	}
+	if endedAt.Valid {
+		return ErrCallEnded
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO call_participants (call_id, user_id, joined_at) VALUES (?, ?, ?)
+		ON CONFLICT (call_id, user_id) DO UPDATE SET left_at = NULL
+	`, id, userID, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Leave removes the users from a call. Returns false if none of them were in
+// the call.
+func (r *Repository) Leave(ctx context.Context, id string, userIDs ...string) (bool, error) {
+	if len(userIDs) == 0 {
+		return false, nil
+	}
+	args := []any{time.Now().UTC().Format(time.RFC3339), id}
+	placeholders := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		placeholders[i] = "?"
+		args = append(args, userID)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE call_participants SET left_at = ?
+		WHERE call_id = ? AND left_at IS NULL AND user_id IN (`+strings.Join(placeholders, ",")+`)
+	`, args...)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// End ends a call and marks anyone still in it as having left. Returns false
+// if the call had already ended.
+func (r *Repository) End(ctx context.Context, id string) (_ bool, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "call.End")
+	defer func() { endSpan(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := tx.ExecContext(ctx, `UPDATE calls SET ended_at = ? WHERE id = ? AND ended_at IS NULL`, now, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil || rows == 0 {
+		return false, err
+	}
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE call_participants SET left_at = ? WHERE call_id = ? AND left_at IS NULL
+	`, now, id); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *Repository) loadParticipants(ctx context.Context, calls []*Call) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Call, len(calls))
+	placeholders := make([]string, len(calls))
+	args := make([]any, len(calls))
+	for i, c := range calls {
+		c.Participants = []Participant{}
+		byID[c.ID] = c
+		placeholders[i] = "?"
+		args[i] = c.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT call_id, user_id, joined_at, left_at FROM call_participants
+		WHERE call_id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY joined_at, user_id
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var callID, joinedAt string
+		var leftAt sql.NullString
+		var p Participant
+		if err := rows.Scan(&callID, &p.UserID, &joinedAt, &leftAt); err != nil {
+			return err
+		}
+		p.JoinedAt, _ = time.Parse(time.RFC3339, joinedAt)
+		p.LeftAt = parseTime(leftAt)
+		byID[callID].Participants = append(byID[callID].Participants, p)
+	}
+	return rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCall(row scanner) (*Call, error) {
+	var c Call
+	var startedAt string
+	var endedAt sql.NullString
+	if err := row.Scan(&c.ID, &c.WorkspaceID, &c.ChannelID, &c.StartedBy, &c.MessageID, &startedAt, &endedAt); err != nil {
+		return nil, err
+	}
+	c.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+	c.EndedAt = parseTime(endedAt)
+	return &c, nil
+}
+
+func parseTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, _ := time.Parse(time.RFC3339, s.String)
+	return &t
+}
+
+func isUniqueConstraintError(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key"))
+}
@@ -0,0 +1,124 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestRepository_Lifecycle(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+
+	c := &Call{WorkspaceID: ws.ID, ChannelID: ch.ID, StartedBy: &alice.ID}
+	if err := repo.Create(ctx, c); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Create(ctx, &Call{WorkspaceID: ws.ID, ChannelID: ch.ID, StartedBy: &bob.ID}); !errors.Is(err, ErrCallInProgress) {
+		t.Fatalf("second Create() error = %v, want ErrCallInProgress", err)
+	}
+
+	if err := repo.Join(ctx, c.ID, bob.ID); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if left, err := repo.Leave(ctx, c.ID, bob.ID); err != nil || !left {
+		t.Fatalf("Leave() = %v, %v, want true", left, err)
+	}
+	// Rejoining brings the participant back
+	if err := repo.Join(ctx, c.ID, bob.ID); err != nil {
+		t.Fatalf("rejoin error = %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if len(got.Participants) != 2 || !got.IsActiveParticipant(bob.ID) || got.ActiveParticipantCount() != 2 {
+		t.Fatalf("participants = %+v, want alice and bob in the call", got.Participants)
+	}
+
+	if ended, err := repo.End(ctx, c.ID); err != nil || !ended {
+		t.Fatalf("End() = %v, %v, want true", ended, err)
+	}
+	if ended, _ := repo.End(ctx, c.ID); ended {
+		t.Error("ending twice reported true")
+	}
+	if err := repo.Join(ctx, c.ID, bob.ID); !errors.Is(err, ErrCallEnded) {
+		t.Errorf("Join() after end error = %v, want ErrCallEnded", err)
+	}
+	got, _ = repo.GetByID(ctx, c.ID)
+	if got.EndedAt == nil || got.ActiveParticipantCount() != 0 {
+		t.Errorf("ended call = %+v, want ended with nobody in it", got)
+	}
+
+	// The channel is free for a new call
+	if err := repo.Create(ctx, &Call{WorkspaceID: ws.ID, ChannelID: ch.ID, StartedBy: &bob.ID}); err != nil {
+		t.Errorf("Create() after end error = %v", err)
+	}
+}
+
+type fakePresence map[string]bool
+
+func (p fakePresence) IsUserConnected(workspaceID, userID string) bool { return p[userID] }
+
+type recordingNotifier struct {
+	changed []string
+}
+
+func (n *recordingNotifier) CallChanged(ctx context.Context, callID string) {
+	n.changed = append(n.changed, callID)
+}
+
+func TestWorker_RemoveDisconnected(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+
+	c := &Call{WorkspaceID: ws.ID, ChannelID: ch.ID, StartedBy: &alice.ID}
+	if err := repo.Create(ctx, c); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Join(ctx, c.ID, bob.ID); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	old := time.Now().Add(-2 * joinGrace).UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`UPDATE call_participants SET joined_at = ? WHERE call_id = ?`, old, c.ID); err != nil {
+		t.Fatalf("backdating joins: %v", err)
+	}
+
+	notifier := &recordingNotifier{}
+	w := NewWorker(repo, fakePresence{alice.ID: true}, notifier)
+	if err := w.RemoveDisconnected(ctx); err != nil {
+		t.Fatalf("RemoveDisconnected() error = %v", err)
+	}
+	if len(notifier.changed) != 1 {
+		t.Fatalf("changed = %v, want the call", notifier.changed)
+	}
+	got, _ := repo.GetByID(ctx, c.ID)
+	if !got.IsActiveParticipant(alice.ID) || got.IsActiveParticipant(bob.ID) {
+		t.Errorf("participants = %+v, want only alice left in the call", got.Participants)
+	}
+
+	// Nothing changes while everyone is connected
+	notifier.changed = nil
+	if err := w.RemoveDisconnected(ctx); err != nil {
+		t.Fatalf("RemoveDisconnected() error = %v", err)
+	}
+	if len(notifier.changed) != 0 {
+		t.Errorf("changed = %v, want none", notifier.changed)
+	}
+}
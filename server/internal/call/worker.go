@@ -0,0 +1,70 @@
+package call
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// joinGrace is how long a participant may go without an event stream
+// connection after joining before they are considered gone.
+const joinGrace = time.Minute
+
+// Presence reports whether a user has a live event stream connection.
+// Implemented by sse.Hub.
+type Presence interface {
+	IsUserConnected(workspaceID, userID string) bool
+}
+
+// Notifier is told about calls whose participants the worker removed, so it
+// can publish the change and end calls nobody is left in. Implemented by
+// handler.Handler via CallChanged.
+type Notifier interface {
+	CallChanged(ctx context.Context, callID string)
+}
+
+// Worker removes participants who disconnected without leaving, such as
+// when a browser tab is closed mid-call.
+type Worker struct {
+	repo     *Repository
+	presence Presence
+	notifier Notifier
+}
+
+// NewWorker creates a new call worker.
+func NewWorker(repo *Repository, presence Presence, notifier Notifier) *Worker {
+	return &Worker{
+		repo:     repo,
+		presence: presence,
+		notifier: notifier,
+	}
+}
+
+// RemoveDisconnected removes disconnected participants from calls in progress.
+func (w *Worker) RemoveDisconnected(ctx context.Context) error {
+	calls, err := w.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-joinGrace)
+	for _, c := range calls {
+		var gone []string
+		for _, p := range c.Participants {
+			if p.LeftAt == nil && p.JoinedAt.Before(cutoff) && !w.presence.IsUserConnected(c.WorkspaceID, p.UserID) {
+				gone = append(gone, p.UserID)
+			}
+		}
+		// A call nobody is in any more is ended by the notifier as well
+		if len(gone) == 0 && c.ActiveParticipantCount() > 0 {
+			continue
+		}
+
+		if _, err := w.repo.Leave(ctx, c.ID, gone...); err != nil {
+			slog.Error("failed to remove disconnected call participants", "component", "call", "id", c.ID, "error", err)
+			continue
+		}
+		w.notifier.CallChanged(ctx, c.ID)
+	}
+	return nil
+}
@@ -1,16 +1,39 @@
 package channel
 
 import (
+	"slices"
 	"time"
+
+	"github.com/enzyme/server/internal/workspace"
 )
 
 type Channel struct {
-	ID                string     `json:"id"`
-	WorkspaceID       string     `json:"workspace_id"`
-	Name              string     `json:"name"`
-	Description       *string    `json:"description,omitempty"`
-	Type              string     `json:"type"`
-	IsDefault         bool       `json:"is_default"`
+	ID          string  `json:"id"`
+	WorkspaceID string  `json:"workspace_id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Topic       *string `json:"topic,omitempty"`
+	Type        string  `json:"type"`
+	IsDefault   bool    `json:"is_default"`
+	// AutoJoin adds new workspace members to the channel, as they always
+	// are to the default channel.
+	AutoJoin          bool   `json:"auto_join"`
+	HistoryVisibility string `json:"history_visibility"`
+	// MessageRetentionDays overrides the workspace retention default when
+	// set; 0 keeps messages forever.
+	MessageRetentionDays *int `json:"message_retention_days,omitempty"`
+	// WhoCanMentionChannel overrides the workspace's who_can_mention_channel
+	// setting when set.
+	WhoCanMentionChannel *string `json:"who_can_mention_channel,omitempty"`
+	// PostPolicy limits who may post; see CanPost. PostRoles lists the
+	// workspace roles allowed to post under PostPolicyRoles.
+	PostPolicy            string   `json:"post_policy"`
+	PostRoles             []string `json:"post_roles,omitempty"`
+	RestrictThreadReplies bool     `json:"restrict_thread_replies"`
+	// SlowModeSeconds is the minimum interval between two messages from the
+	// same member; 0 disables slow mode. Channel and workspace admins are
+	// exempt.
+	SlowModeSeconds   int        `json:"slow_mode_seconds"`
 	DMParticipantHash *string    `json:"dm_participant_hash,omitempty"`
 	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
 	CreatedBy         *string    `json:"created_by,omitempty"`
@@ -18,6 +41,21 @@ type Channel struct {
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
+// ReadState is a member's read position in a channel. Only top-level,
+// undeleted messages count as unread.
+type ReadState struct {
+	LastReadMessageID    *string
+	FirstUnreadMessageID *string
+	UnreadCount          int
+}
+
+// ReadMark is a channel whose read position MarkAllRead moved, and the
+// message it now points at.
+type ReadMark struct {
+	ChannelID string
+	MessageID string
+}
+
 type ChannelMembership struct {
 	ID                string    `json:"id"`
 	UserID            string    `json:"user_id"`
@@ -37,15 +75,110 @@ type ChannelWithMembership struct {
 	NotificationCount int          `json:"notification_count"`
 	IsStarred         bool         `json:"is_starred"`
 	IsDefault         bool         `json:"is_default"`
+	MemberCount       int          `json:"member_count"`
 	DMParticipants    []MemberInfo `json:"dm_participants,omitempty"`
+	// SnoozedUntil is when the channel's or workspace's snooze ends, if one
+	// is running
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+}
+
+// UnreadCount is a channel's badge counts for one member
+type UnreadCount struct {
+	Unread        int
+	Notifications int
 }
 
 type MemberInfo struct {
-	UserID      string  `json:"user_id"`
-	Email       string  `json:"email"`
-	DisplayName string  `json:"display_name"`
-	AvatarURL   *string `json:"avatar_url,omitempty"`
-	ChannelRole *string `json:"channel_role,omitempty"`
+	UserID        string  `json:"user_id"`
+	Email         string  `json:"email"`
+	DisplayName   string  `json:"display_name"`
+	AvatarURL     *string `json:"avatar_url,omitempty"`
+	Title         *string `json:"title,omitempty"`
+	Pronouns      *string `json:"pronouns,omitempty"`
+	Timezone      *string `json:"timezone,omitempty"`
+	ChannelRole   *string `json:"channel_role,omitempty"`
+	IsDeactivated bool    `json:"is_deactivated"`
+}
+
+// DMInboxEntry is a direct message conversation as listed in the user's
+// inbox across workspaces
+type DMInboxEntry struct {
+	ChannelWithMembership
+	WorkspaceName      string
+	WorkspaceIconURL   *string
+	LastMessageAt      *time.Time
+	LastMessagePreview *string
+	// LastMessageSystemEvent is the raw system_event of the latest message
+	// when it is a system message, whose preview the API layer renders in
+	// the reader's language
+	LastMessageSystemEvent *string
+}
+
+// DirectoryEntry is a public channel as listed in the channel browser
+type DirectoryEntry struct {
+	Channel
+	MemberCount int
+	IsMember    bool
+	// LastActivityAt and LastMessagePreview describe the latest top-level
+	// message, and are nil for empty channels.
+	LastActivityAt         *time.Time
+	LastMessagePreview     *string
+	LastMessageSystemEvent *string // as on DMInboxEntry
+}
+
+// Channel browser sort orders
+const (
+	DirectorySortNewest  = "newest"
+	DirectorySortMembers = "members"
+	DirectorySortName    = "name"
+)
+
+// MemberListOptions filters and pages a channel's member list. Cursor is the
+// user ID of the last member on the previous page.
+type MemberListOptions struct {
+	Query  string
+	Role   string
+	Cursor string
+	Limit  int
+}
+
+// MemberListResult is one page of a channel's members
+type MemberListResult struct {
+	Members    []MemberInfo
+	HasMore    bool
+	NextCursor string
+}
+
+// MentionCandidate is a channel member offered by mention autocomplete.
+// LastPostedAt is their newest message in the channel, nil if they never
+// posted there.
+type MentionCandidate struct {
+	MemberInfo
+	LastPostedAt *time.Time
+}
+
+// DirectoryOptions filters and pages the channel browser
+type DirectoryOptions struct {
+	Query  string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// Spec describes a channel to create in bulk, such as from a channel
+// template. Workspace members whose role is in MemberRoles join it.
+type Spec struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description *string  `json:"description,omitempty"`
+	MemberRoles []string `json:"member_roles,omitempty"`
+}
+
+// CreatedChannel is a channel created from a Spec and the IDs of everyone who
+// joined it, the creator included
+type CreatedChannel struct {
+	Channel   *Channel
+	MemberIDs []string
 }
 
 const (
@@ -55,6 +188,74 @@ const (
 	TypeGroupDM = "group_dm"
 )
 
+// History visibility policies control which messages posted before a member
+// joined are visible to them. Only enforced for private channels.
+const (
+	HistoryVisibilityAll        = "all"
+	HistoryVisibilityLast30Days = "last_30_days"
+	HistoryVisibilityNone       = "none"
+)
+
+// historyGracePeriod is how far before their join time members of a
+// last_30_days channel can see.
+const historyGracePeriod = 30 * 24 * time.Hour
+
+// IsValidHistoryVisibility reports whether v is a known history visibility policy
+func IsValidHistoryVisibility(v string) bool {
+	return v == HistoryVisibilityAll || v == HistoryVisibilityLast30Days || v == HistoryVisibilityNone
+}
+
+// HistoryVisibleSince returns the earliest message time visible to a member
+// who joined at joinedAt, or nil if the member can see the full history.
+func (c *Channel) HistoryVisibleSince(joinedAt time.Time) *time.Time {
+	if c.Type != TypePrivate {
+		return nil
+	}
+	switch c.HistoryVisibility {
+	case HistoryVisibilityNone:
+		return &joinedAt
+	case HistoryVisibilityLast30Days:
+		t := joinedAt.Add(-historyGracePeriod)
+		return &t
+	}
+	return nil
+}
+
+// Post policies control who may post in a channel. Channel admins and
+// workspace admins can always post.
+const (
+	PostPolicyEveryone = "everyone"
+	PostPolicyAdmins   = "admins"
+	PostPolicyRoles    = "roles"
+)
+
+// IsValidPostPolicy reports whether p is a known post policy
+func IsValidPostPolicy(p string) bool {
+	return p == PostPolicyEveryone || p == PostPolicyAdmins || p == PostPolicyRoles
+}
+
+// CanPost reports whether a member with the given workspace and channel
+// roles may post in the channel. Thread replies are open to everyone who may
+// post at all unless RestrictThreadReplies is set.
+func (c *Channel) CanPost(workspaceRole string, channelRole *string, threadReply bool) bool {
+	if !CanPost(channelRole) {
+		return false
+	}
+	if c.PostPolicy == "" || c.PostPolicy == PostPolicyEveryone {
+		return true
+	}
+	if threadReply && !c.RestrictThreadReplies {
+		return true
+	}
+	if CanManageChannel(channelRole) || workspace.CanManageMembers(workspaceRole) {
+		return true
+	}
+	return c.PostPolicy == PostPolicyRoles && slices.Contains(c.PostRoles, workspaceRole)
+}
+
+// MaxSlowModeSeconds is the longest slow mode interval a channel can have
+const MaxSlowModeSeconds = 6 * 60 * 60
+
 // DefaultChannelName is the name of the default channel created for every workspace
 const DefaultChannelName = "general"
 
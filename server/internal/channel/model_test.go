@@ -1,6 +1,9 @@
 package channel
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestCanPost(t *testing.T) {
 	admin := ChannelRoleAdmin
@@ -60,6 +63,42 @@ func TestCanManageChannel(t *testing.T) {
 	}
 }
 
+func TestChannel_CanPost(t *testing.T) {
+	admin := ChannelRoleAdmin
+	viewer := ChannelRoleViewer
+
+	announcements := &Channel{PostPolicy: PostPolicyAdmins}
+	restricted := &Channel{PostPolicy: PostPolicyAdmins, RestrictThreadReplies: true}
+	staff := &Channel{PostPolicy: PostPolicyRoles, PostRoles: []string{"member"}}
+
+	tests := []struct {
+		name          string
+		ch            *Channel
+		workspaceRole string
+		channelRole   *string
+		threadReply   bool
+		want          bool
+	}{
+		{"everyone policy allows members", &Channel{PostPolicy: PostPolicyEveryone}, "member", nil, false, true},
+		{"viewer role is never allowed", &Channel{PostPolicy: PostPolicyEveryone}, "admin", &viewer, false, false},
+		{"admins policy blocks members", announcements, "member", nil, false, false},
+		{"admins policy allows workspace admins", announcements, "admin", nil, false, true},
+		{"admins policy allows channel admins", announcements, "member", &admin, false, true},
+		{"thread replies stay open", announcements, "member", nil, true, true},
+		{"restricted thread replies", restricted, "member", nil, true, false},
+		{"roles policy allows listed role", staff, "member", nil, false, true},
+		{"roles policy blocks other roles", staff, "guest", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ch.CanPost(tt.workspaceRole, tt.channelRole, tt.threadReply); got != tt.want {
+				t.Errorf("CanPost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestChannelTypeConstants(t *testing.T) {
 	// Verify type constants have expected values
 	if TypePublic != "public" {
@@ -88,3 +127,33 @@ func TestChannelRoleConstants(t *testing.T) {
 		t.Errorf("ChannelRoleViewer = %q, want %q", ChannelRoleViewer, "viewer")
 	}
 }
+
+func TestHistoryVisibleSince(t *testing.T) {
+	joined := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		channelType string
+		visibility  string
+		want        *time.Time
+	}{
+		{"private all", TypePrivate, HistoryVisibilityAll, nil},
+		{"private none", TypePrivate, HistoryVisibilityNone, &joined},
+		{"private last 30 days", TypePrivate, HistoryVisibilityLast30Days, ptrTime(joined.AddDate(0, 0, -30))},
+		{"public ignores policy", TypePublic, HistoryVisibilityNone, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Channel{Type: tt.channelType, HistoryVisibility: tt.visibility}
+			got := c.HistoryVisibleSince(joined)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("HistoryVisibleSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"sort"
 	"strings"
@@ -19,11 +20,13 @@ var (
 	ErrNotChannelMember     = errors.New("not a member of this channel")
 	ErrAlreadyMember        = errors.New("already a member of this channel")
 	ErrChannelArchived      = errors.New("channel is archived")
+	ErrChannelNotArchived   = errors.New("channel is not archived")
 	ErrCannotLeaveChannel   = errors.New("cannot leave this channel")
 	ErrDMAlreadyExists      = errors.New("DM channel already exists")
 	ErrCannotLeaveDefault   = errors.New("cannot leave the default channel")
 	ErrCannotArchiveDefault = errors.New("cannot archive the default channel")
 	ErrChannelNameTaken     = errors.New("channel name already taken")
+	ErrInvalidCursor        = errors.New("invalid cursor")
 )
 
 type Repository struct {
@@ -40,6 +43,12 @@ func (r *Repository) Create(ctx context.Context, channel *Channel, creatorID str
 	channel.CreatedAt = now
 	channel.UpdatedAt = now
 	channel.CreatedBy = &creatorID
+	if channel.HistoryVisibility == "" {
+		channel.HistoryVisibility = HistoryVisibilityAll
+	}
+	if channel.PostPolicy == "" {
+		channel.PostPolicy = PostPolicyEveryone
+	}
 
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -52,10 +61,13 @@ func (r *Repository) Create(ctx context.Context, channel *Channel, creatorID str
 		isDefault = 1
 	}
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO channels (id, workspace_id, name, description, type, dm_participant_hash, is_default, created_by, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, channel.ID, channel.WorkspaceID, channel.Name, channel.Description, channel.Type, channel.DMParticipantHash, isDefault, channel.CreatedBy, now.Format(time.RFC3339), now.Format(time.RFC3339))
+		INSERT INTO channels (id, workspace_id, name, description, type, dm_participant_hash, is_default, history_visibility, post_policy, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, channel.ID, channel.WorkspaceID, channel.Name, channel.Description, channel.Type, channel.DMParticipantHash, isDefault, channel.HistoryVisibility, channel.PostPolicy, channel.CreatedBy, now.Format(time.RFC3339), now.Format(time.RFC3339))
 	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrChannelNameTaken
+		}
 		return err
 	}
 
@@ -72,19 +84,100 @@ func (r *Repository) Create(ctx context.Context, channel *Channel, creatorID str
 	return tx.Commit()
 }
 
+// CreateFromSpecs creates the channels in a single transaction. The creator
+// becomes admin of each, and workspace members with one of a spec's member
+// roles join as regular members. Specs whose name is already taken in the
+// workspace are skipped and returned by name.
+func (r *Repository) CreateFromSpecs(ctx context.Context, workspaceID, creatorID string, specs []Spec) (created []CreatedChannel, skipped []string, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	nowStr := now.Format(time.RFC3339)
+	for _, spec := range specs {
+		var exists int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM channels
+			WHERE workspace_id = ? AND name = ? AND type IN ('public', 'private')
+		`, workspaceID, spec.Name).Scan(&exists)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exists > 0 {
+			skipped = append(skipped, spec.Name)
+			continue
+		}
+
+		ch := &Channel{
+			ID:                ulid.Make().String(),
+			WorkspaceID:       workspaceID,
+			Name:              spec.Name,
+			Description:       spec.Description,
+			Type:              spec.Type,
+			HistoryVisibility: HistoryVisibilityAll,
+			PostPolicy:        PostPolicyEveryone,
+			CreatedBy:         &creatorID,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO channels (id, workspace_id, name, description, type, is_default, history_visibility, post_policy, created_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?)
+		`, ch.ID, ch.WorkspaceID, ch.Name, ch.Description, ch.Type, ch.HistoryVisibility, ch.PostPolicy, ch.CreatedBy, nowStr, nowStr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		adminRole := ChannelRoleAdmin
+		if err := insertMembership(ctx, tx, creatorID, ch.ID, &adminRole, nowStr); err != nil {
+			return nil, nil, err
+		}
+		memberIDs := []string{creatorID}
+
+		if len(spec.MemberRoles) > 0 {
+			placeholders := make([]string, len(spec.MemberRoles))
+			args := []interface{}{workspaceID, creatorID}
+			for i, role := range spec.MemberRoles {
+				placeholders[i] = "?"
+				args = append(args, role)
+			}
+			userIDs, err := queryStrings(ctx, tx, `
+				SELECT user_id FROM workspace_memberships
+				WHERE workspace_id = ? AND user_id != ? AND role IN (`+strings.Join(placeholders, ",")+`)
+				ORDER BY user_id
+			`, args...)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, userID := range userIDs {
+				if err := insertMembership(ctx, tx, userID, ch.ID, nil, nowStr); err != nil {
+					return nil, nil, err
+				}
+			}
+			memberIDs = append(memberIDs, userIDs...)
+		}
+
+		created = append(created, CreatedChannel{Channel: ch, MemberIDs: memberIDs})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return created, skipped, nil
+}
+
 func (r *Repository) CreateDM(ctx context.Context, workspaceID string, userIDs []string) (*Channel, error) {
 	hash := ComputeDMHash(userIDs)
 
 	// Check if DM already exists
-	var existingID string
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id FROM channels
-		WHERE workspace_id = ? AND dm_participant_hash = ?
-	`, workspaceID, hash).Scan(&existingID)
+	existing, err := r.GetDM(ctx, workspaceID, userIDs)
 	if err == nil {
-		return r.GetByID(ctx, existingID)
+		return existing, nil
 	}
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, ErrChannelNotFound) {
 		return nil, err
 	}
 
@@ -99,6 +192,8 @@ func (r *Repository) CreateDM(ctx context.Context, workspaceID string, userIDs [
 		Name:              "Direct Message",
 		Type:              channelType,
 		DMParticipantHash: &hash,
+		HistoryVisibility: HistoryVisibilityAll,
+		PostPolicy:        PostPolicyEveryone,
 	}
 	now := time.Now().UTC()
 	channel.CreatedAt = now
@@ -137,10 +232,27 @@ func (r *Repository) CreateDM(ctx context.Context, workspaceID string, userIDs [
 	return channel, nil
 }
 
+// GetDM returns the DM or group DM between exactly the given users, or
+// ErrChannelNotFound if they have none
+func (r *Repository) GetDM(ctx context.Context, workspaceID string, userIDs []string) (*Channel, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id FROM channels
+		WHERE workspace_id = ? AND dm_participant_hash = ?
+	`, workspaceID, ComputeDMHash(userIDs)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, ErrChannelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
 func (r *Repository) GetByID(ctx context.Context, id string) (*Channel, error) {
 	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.GetByID")
 	ch, err := r.scanChannel(r.db.QueryRowContext(ctx, `
-		SELECT id, workspace_id, name, description, type, dm_participant_hash, is_default, archived_at, created_by, created_at, updated_at
+		SELECT id, workspace_id, name, description, topic, type, dm_participant_hash, is_default, auto_join, history_visibility, message_retention_days, who_can_mention_channel, post_policy, post_roles, restrict_thread_replies, slow_mode_seconds, archived_at, created_by, created_at, updated_at
 		FROM channels WHERE id = ?
 	`, id))
 	endSpan(err)
@@ -149,7 +261,7 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*Channel, error) {
 
 func (r *Repository) GetByWorkspaceAndName(ctx context.Context, workspaceID, name string) (*Channel, error) {
 	ch, err := r.scanChannel(r.db.QueryRowContext(ctx, `
-		SELECT id, workspace_id, name, description, type, dm_participant_hash, is_default, archived_at, created_by, created_at, updated_at
+		SELECT id, workspace_id, name, description, topic, type, dm_participant_hash, is_default, auto_join, history_visibility, message_retention_days, who_can_mention_channel, post_policy, post_roles, restrict_thread_replies, slow_mode_seconds, archived_at, created_by, created_at, updated_at
 		FROM channels WHERE workspace_id = ? AND name = ? AND type IN ('public', 'private')
 	`, workspaceID, name))
 	if err != nil {
@@ -164,9 +276,11 @@ func (r *Repository) GetByWorkspaceAndName(ctx context.Context, workspaceID, nam
 func (r *Repository) Update(ctx context.Context, channel *Channel) error {
 	channel.UpdatedAt = time.Now().UTC()
 	result, err := r.db.ExecContext(ctx, `
-		UPDATE channels SET name = ?, description = ?, type = ?, updated_at = ?
+		UPDATE channels SET name = ?, description = ?, type = ?, history_visibility = ?, who_can_mention_channel = ?,
+			post_policy = ?, post_roles = ?, restrict_thread_replies = ?, slow_mode_seconds = ?, auto_join = ?, updated_at = ?
 		WHERE id = ?
-	`, channel.Name, channel.Description, channel.Type, channel.UpdatedAt.Format(time.RFC3339), channel.ID)
+	`, channel.Name, channel.Description, channel.Type, channel.HistoryVisibility, channel.WhoCanMentionChannel,
+		channel.PostPolicy, formatPostRoles(channel.PostRoles), channel.RestrictThreadReplies, channel.SlowModeSeconds, channel.AutoJoin, channel.UpdatedAt.Format(time.RFC3339), channel.ID)
 	if err != nil {
 		if isUniqueConstraintError(err) {
 			return ErrChannelNameTaken
@@ -180,6 +294,23 @@ func (r *Repository) Update(ctx context.Context, channel *Channel) error {
 	return nil
 }
 
+// SetMessageRetention sets the channel's message retention in days. A nil
+// days clears the override so the workspace default applies.
+func (r *Repository) SetMessageRetention(ctx context.Context, channelID string, days *int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE channels SET message_retention_days = ?, updated_at = ?
+		WHERE id = ?
+	`, days, time.Now().UTC().Format(time.RFC3339), channelID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
 func (r *Repository) Archive(ctx context.Context, channelID string) error {
 	// Check if channel is default
 	channel, err := r.GetByID(ctx, channelID)
@@ -205,44 +336,267 @@ func (r *Repository) Archive(ctx context.Context, channelID string) error {
 	return nil
 }
 
+// Unarchive restores an archived channel
+func (r *Repository) Unarchive(ctx context.Context, channel *Channel) error {
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE channels SET archived_at = NULL, updated_at = ?
+		WHERE id = ? AND archived_at IS NOT NULL
+	`, now.Format(time.RFC3339), channel.ID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrChannelNotArchived
+	}
+	channel.ArchivedAt = nil
+	channel.UpdatedAt = now
+	return nil
+}
+
+// ListArchived returns a workspace's archived public and private channels,
+// most recently archived first
+func (r *Repository) ListArchived(ctx context.Context, workspaceID string) ([]Channel, error) {
+	return r.listByIDQuery(ctx, `
+		SELECT id FROM channels
+		WHERE workspace_id = ? AND archived_at IS NOT NULL AND type IN ('public', 'private')
+		ORDER BY archived_at DESC, name
+	`, workspaceID)
+}
+
+// directoryPreviewLength is how many characters of the latest message the
+// channel browser shows
+const directoryPreviewLength = 120
+
+// ListDirectory returns a page of the workspace's unarchived public channels
+// with their member counts and latest message, plus the total number of
+// channels matching the query.
+func (r *Repository) ListDirectory(ctx context.Context, workspaceID, userID string, opts DirectoryOptions) (_ []DirectoryEntry, _ int, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.ListDirectory")
+	defer func() { endSpan(err) }()
+
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	where := `c.workspace_id = ? AND c.type = 'public' AND c.archived_at IS NULL`
+	whereArgs := []interface{}{workspaceID}
+	if q := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(opts.Query)), "#"); q != "" {
+		where += ` AND (c.name LIKE ? ESCAPE '\' OR LOWER(COALESCE(c.description, '')) LIKE ? ESCAPE '\')`
+		pattern := "%" + escapeLike(q) + "%"
+		whereArgs = append(whereArgs, pattern, pattern)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM channels c WHERE `+where, whereArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	var orderBy string
+	switch opts.Sort {
+	case DirectorySortMembers:
+		orderBy = `member_count DESC, c.name`
+	case DirectorySortName:
+		orderBy = `c.name`
+	default:
+		orderBy = `c.created_at DESC, c.id DESC`
+	}
+
+	args := append([]interface{}{userID}, whereArgs...)
+	args = append(args, opts.Limit, opts.Offset)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id,
+		       (SELECT COUNT(*) FROM channel_memberships cm WHERE cm.channel_id = c.id) AS member_count,
+		       EXISTS (SELECT 1 FROM channel_memberships cm WHERE cm.channel_id = c.id AND cm.user_id = ?) AS is_member,
+		       lm.created_at, lm.content, lm.system_event
+		FROM channels c
+		LEFT JOIN messages lm ON lm.id = (
+			SELECT m.id FROM messages m
+			WHERE m.channel_id = c.id AND m.thread_parent_id IS NULL AND m.deleted_at IS NULL
+			ORDER BY m.id DESC LIMIT 1
+		)
+		WHERE `+where+`
+		ORDER BY `+orderBy+`
+		LIMIT ? OFFSET ?
+	`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []DirectoryEntry
+	for rows.Next() {
+		var e DirectoryEntry
+		var isMember int
+		var lastAt, lastContent, lastEvent sql.NullString
+		if err := rows.Scan(&e.ID, &e.MemberCount, &isMember, &lastAt, &lastContent, &lastEvent); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		e.IsMember = isMember != 0
+		if lastAt.Valid {
+			t, _ := time.Parse(time.RFC3339, lastAt.String)
+			e.LastActivityAt = &t
+			preview := previewText(lastContent.String, directoryPreviewLength)
+			e.LastMessagePreview = &preview
+			if lastEvent.Valid {
+				e.LastMessageSystemEvent = &lastEvent.String
+			}
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	for i := range entries {
+		ch, err := r.GetByID(ctx, entries[i].ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries[i].Channel = *ch
+	}
+	return entries, total, nil
+}
+
+// ListDMInbox returns the user's unarchived direct and group messages in
+// every workspace they belong to and aren't banned from, most recently
+// active first, with the latest top-level message of each
+func (r *Repository) ListDMInbox(ctx context.Context, userID string) (_ []DMInboxEntry, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.ListDMInbox")
+	defer func() { endSpan(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, w.name, w.icon_url,
+		       cm.channel_role, cm.last_read_message_id, cm.is_starred, cm.unread_count, cm.notification_count,
+		       cm.snoozed_until, wm.snoozed_until,
+		       (SELECT COUNT(*) FROM channel_memberships mc WHERE mc.channel_id = c.id) as member_count,
+		       lm.created_at, lm.content, lm.system_event
+		FROM channel_memberships cm
+		JOIN channels c ON c.id = cm.channel_id
+		JOIN workspaces w ON w.id = c.workspace_id
+		JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
+		LEFT JOIN messages lm ON lm.id = (
+			SELECT m.id FROM messages m
+			WHERE m.channel_id = c.id AND m.thread_parent_id IS NULL AND m.deleted_at IS NULL
+			ORDER BY m.id DESC LIMIT 1
+		)
+		WHERE cm.user_id = ? AND c.type IN ('dm', 'group_dm') AND c.archived_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM workspace_bans wb
+			WHERE wb.workspace_id = c.workspace_id AND wb.user_id = cm.user_id
+			AND (wb.expires_at IS NULL OR wb.expires_at > strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+		  )
+		ORDER BY COALESCE(lm.created_at, c.created_at) DESC, c.id DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []DMInboxEntry
+	for rows.Next() {
+		var e DMInboxEntry
+		var iconURL, channelRole, lastReadID, channelSnooze, workspaceSnooze, lastAt, lastContent, lastEvent sql.NullString
+		var isStarred int
+		if err := rows.Scan(&e.ID, &e.WorkspaceName, &iconURL, &channelRole, &lastReadID, &isStarred, &e.UnreadCount, &e.NotificationCount, &channelSnooze, &workspaceSnooze, &e.MemberCount, &lastAt, &lastContent, &lastEvent); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if iconURL.Valid {
+			e.WorkspaceIconURL = &iconURL.String
+		}
+		if channelRole.Valid {
+			e.ChannelRole = &channelRole.String
+		}
+		if lastReadID.Valid {
+			e.LastReadMessageID = &lastReadID.String
+		}
+		e.IsStarred = isStarred != 0
+		e.SnoozedUntil = activeSnooze(channelSnooze, workspaceSnooze)
+		if e.SnoozedUntil != nil {
+			e.NotificationCount = 0
+		}
+		if lastAt.Valid {
+			t, _ := time.Parse(time.RFC3339, lastAt.String)
+			e.LastMessageAt = &t
+			preview := previewText(lastContent.String, directoryPreviewLength)
+			e.LastMessagePreview = &preview
+			if lastEvent.Valid {
+				e.LastMessageSystemEvent = &lastEvent.String
+			}
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	channels := make([]ChannelWithMembership, len(entries))
+	channelIDs := make([]string, len(entries))
+	channelIndex := make(map[string]int, len(entries))
+	for i := range entries {
+		ch, err := r.GetByID(ctx, entries[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Channel = *ch
+		entries[i].IsDefault = ch.IsDefault
+		channelIDs[i] = ch.ID
+		channelIndex[ch.ID] = i
+	}
+	if err := r.fetchDMParticipants(ctx, channels, channelIDs, channelIndex, userID); err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].DMParticipants = channels[i].DMParticipants
+	}
+	return entries, nil
+}
+
+// PreviewText shortens message text the way LastMessagePreview is.
+func PreviewText(s string) string {
+	return previewText(s, directoryPreviewLength)
+}
+
+// previewText collapses whitespace in s and cuts it to n characters
+func previewText(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ListForWorkspace returns the unarchived channels the user belongs to plus
+// public channels they have not joined. Unread counts come from the membership
+// counters and are zero for channels the user has not joined. Notification
+// counts are also zero while the channel or workspace is snoozed.
 func (r *Repository) ListForWorkspace(ctx context.Context, workspaceID, userID string) (_ []ChannelWithMembership, err error) {
 	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.ListForWorkspace")
 	defer func() { endSpan(err) }()
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT c.id, c.workspace_id, c.name, c.description, c.type, c.dm_participant_hash, c.is_default, c.archived_at, c.created_by, c.created_at, c.updated_at,
+		SELECT c.id, c.workspace_id, c.name, c.description, c.topic, c.type, c.dm_participant_hash, c.is_default, c.auto_join, c.history_visibility, c.message_retention_days, c.who_can_mention_channel, c.post_policy, c.post_roles, c.restrict_thread_replies, c.slow_mode_seconds, c.archived_at, c.created_by, c.created_at, c.updated_at,
 		       cm.channel_role, cm.last_read_message_id, COALESCE(cm.is_starred, 0) as is_starred,
-		       COALESCE((
-		           SELECT COUNT(*) FROM messages m
-		           WHERE m.channel_id = c.id
-		             AND m.thread_parent_id IS NULL
-		             AND m.deleted_at IS NULL
-		             AND (cm.last_read_message_id IS NULL OR m.id > cm.last_read_message_id)
-		       ), 0) as unread_count,
-		       COALESCE((
-		           SELECT COUNT(*) FROM messages m
-		           WHERE m.channel_id = c.id
-		             AND m.thread_parent_id IS NULL
-		             AND m.deleted_at IS NULL
-		             AND (cm.last_read_message_id IS NULL OR m.id > cm.last_read_message_id)
-		             AND CASE
-		               WHEN c.type IN ('dm', 'group_dm') THEN 1
-		               WHEN np.notify_level = 'none' THEN 0
-		               WHEN np.notify_level = 'all' THEN 1
-		               WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
-		                 EXISTS (
-		                   SELECT 1 FROM json_each(m.mentions) je
-		                   WHERE je.value = ? OR je.value IN ('@channel', '@everyone')
-		                 )
-		               ELSE 0
-		             END = 1
-		       ), 0) as notification_count
+		       COALESCE(cm.unread_count, 0) as unread_count, COALESCE(cm.notification_count, 0) as notification_count,
+		       cm.snoozed_until, wm.snoozed_until,
+		       (SELECT COUNT(*) FROM channel_memberships mc WHERE mc.channel_id = c.id) as member_count
 		FROM channels c
 		LEFT JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
-		LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = ?
+		LEFT JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
 		WHERE c.workspace_id = ? AND c.archived_at IS NULL
 		  AND (c.type = 'public' OR cm.id IS NOT NULL)
 		ORDER BY c.name
-	`, userID, userID, userID, workspaceID)
+	`, userID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -254,15 +608,17 @@ func (r *Repository) ListForWorkspace(ctx context.Context, workspaceID, userID s
 
 	for rows.Next() {
 		var c ChannelWithMembership
-		var description, dmHash, archivedAt, createdBy, channelRole, lastReadID sql.NullString
+		var description, topic, dmHash, mentionPermission, postRoles, archivedAt, createdBy, channelRole, lastReadID sql.NullString
+		var channelSnooze, workspaceSnooze sql.NullString
+		var retentionDays sql.NullInt64
 		var createdAt, updatedAt string
-		var isDefault int
+		var isDefault, autoJoin, restrictThreadReplies int
 		var isStarred int
 		var unreadCount int
 		var notificationCount int
 
-		err := rows.Scan(&c.ID, &c.WorkspaceID, &c.Name, &description, &c.Type, &dmHash, &isDefault, &archivedAt, &createdBy, &createdAt, &updatedAt,
-			&channelRole, &lastReadID, &isStarred, &unreadCount, &notificationCount)
+		err := rows.Scan(&c.ID, &c.WorkspaceID, &c.Name, &description, &topic, &c.Type, &dmHash, &isDefault, &autoJoin, &c.HistoryVisibility, &retentionDays, &mentionPermission, &c.PostPolicy, &postRoles, &restrictThreadReplies, &c.SlowModeSeconds, &archivedAt, &createdBy, &createdAt, &updatedAt,
+			&channelRole, &lastReadID, &isStarred, &unreadCount, &notificationCount, &channelSnooze, &workspaceSnooze, &c.MemberCount)
 		if err != nil {
 			return nil, err
 		}
@@ -270,9 +626,21 @@ func (r *Repository) ListForWorkspace(ctx context.Context, workspaceID, userID s
 		if description.Valid {
 			c.Description = &description.String
 		}
+		if topic.Valid {
+			c.Topic = &topic.String
+		}
 		if dmHash.Valid {
 			c.DMParticipantHash = &dmHash.String
 		}
+		if retentionDays.Valid {
+			days := int(retentionDays.Int64)
+			c.MessageRetentionDays = &days
+		}
+		if mentionPermission.Valid {
+			c.WhoCanMentionChannel = &mentionPermission.String
+		}
+		c.PostRoles = parsePostRoles(postRoles)
+		c.RestrictThreadReplies = restrictThreadReplies != 0
 		if archivedAt.Valid {
 			t, _ := time.Parse(time.RFC3339, archivedAt.String)
 			c.ArchivedAt = &t
@@ -290,8 +658,13 @@ func (r *Repository) ListForWorkspace(ctx context.Context, workspaceID, userID s
 		c.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 		c.UnreadCount = unreadCount
 		c.NotificationCount = notificationCount
+		c.SnoozedUntil = activeSnooze(channelSnooze, workspaceSnooze)
+		if c.SnoozedUntil != nil {
+			c.NotificationCount = 0
+		}
 		c.IsStarred = isStarred != 0
 		c.IsDefault = isDefault != 0
+		c.AutoJoin = autoJoin != 0
 
 		// Track DM channels for participant lookup
 		if c.Type == TypeDM || c.Type == TypeGroupDM {
@@ -316,6 +689,161 @@ func (r *Repository) ListForWorkspace(ctx context.Context, workspaceID, userID s
 	return channels, nil
 }
 
+// ListUnreadCounts returns unread and notification counts for each unarchived
+// channel the user is a member of in the workspace, keyed by channel ID. The
+// counts match those of ListForWorkspace.
+func (r *Repository) ListUnreadCounts(ctx context.Context, workspaceID, userID string) (_ map[string]UnreadCount, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.ListUnreadCounts")
+	defer func() { endSpan(err) }()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, cm.unread_count,
+		       CASE WHEN cm.snoozed_until > strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		              OR wm.snoozed_until > strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		            THEN 0 ELSE cm.notification_count END
+		FROM channel_memberships cm
+		JOIN channels c ON c.id = cm.channel_id
+		LEFT JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
+		WHERE cm.user_id = ? AND c.workspace_id = ? AND c.archived_at IS NULL
+	`, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]UnreadCount)
+	for rows.Next() {
+		var channelID string
+		var count UnreadCount
+		if err := rows.Scan(&channelID, &count.Unread, &count.Notifications); err != nil {
+			return nil, err
+		}
+		counts[channelID] = count
+	}
+	return counts, rows.Err()
+}
+
+// UnreadCountsVersion returns a fingerprint that changes whenever the user's
+// unread counts in the workspace may have changed, without counting anything.
+// It covers the newest stored workspace event (new, deleted and restored
+// messages are all recorded there) and the user's read positions,
+// memberships, notification levels and running snoozes.
+func (r *Repository) UnreadCountsVersion(ctx context.Context, workspaceID, userID string) (_ string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.UnreadCountsVersion")
+	defer func() { endSpan(err) }()
+
+	h := sha256.New()
+
+	var latestEvent string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(id), '') FROM workspace_events WHERE workspace_id = ?
+	`, workspaceID).Scan(&latestEvent)
+	if err != nil {
+		return "", err
+	}
+	h.Write([]byte(latestEvent))
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cm.channel_id, COALESCE(cm.last_read_message_id, ''),
+		       COALESCE(np.notify_level, wns.notify_level, uns.notify_level, ''),
+		       cm.snoozed_until, wm.snoozed_until
+		FROM channel_memberships cm
+		JOIN channels c ON c.id = cm.channel_id
+		LEFT JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
+		LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = cm.user_id
+		LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = cm.user_id
+		LEFT JOIN user_notification_settings uns ON uns.user_id = cm.user_id
+		WHERE cm.user_id = ? AND c.workspace_id = ? AND c.archived_at IS NULL
+		ORDER BY cm.channel_id
+	`, userID, workspaceID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var channelID, lastRead, notifyLevel string
+		var channelSnooze, workspaceSnooze sql.NullString
+		if err := rows.Scan(&channelID, &lastRead, &notifyLevel, &channelSnooze, &workspaceSnooze); err != nil {
+			return "", err
+		}
+		var snoozedUntil string
+		if until := activeSnooze(channelSnooze, workspaceSnooze); until != nil {
+			snoozedUntil = until.Format(time.RFC3339)
+		}
+		h.Write([]byte("\x00" + channelID + "\x00" + lastRead + "\x00" + notifyLevel + "\x00" + snoozedUntil))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+// ListVersion returns a fingerprint that changes whenever the user's channel
+// list in the workspace may have changed. On top of UnreadCountsVersion it
+// covers the count and newest update of the workspace's channels, of the
+// user's memberships and the memberships of their DMs, of those DM
+// participants' profiles, and of the user's thread subscriptions, plus the
+// count and newest ID of all the workspace's memberships for member counts.
+func (r *Repository) ListVersion(ctx context.Context, workspaceID, userID string) (_ string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.ListVersion")
+	defer func() { endSpan(err) }()
+
+	unread, err := r.UnreadCountsVersion(ctx, workspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	var channels, memberships, threads, allMemberships string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) || '/' || COALESCE(MAX(updated_at), '')
+			 FROM channels WHERE workspace_id = ?),
+			(SELECT COUNT(*) || '/' || COALESCE(MAX(cm.updated_at), '') || '/' || COALESCE(MAX(u.updated_at), '')
+			 FROM channel_memberships cm
+			 JOIN channels c ON c.id = cm.channel_id
+			 JOIN users u ON u.id = cm.user_id
+			 WHERE c.workspace_id = ?
+			   AND (cm.user_id = ? OR (c.type IN ('dm', 'group_dm')
+			     AND c.id IN (SELECT channel_id FROM channel_memberships WHERE user_id = ?)))),
+			(SELECT COUNT(*) || '/' || COALESCE(MAX(updated_at), '') || '/' || COALESCE(MAX(last_read_reply_id), '')
+			 FROM thread_subscriptions WHERE user_id = ?),
+			(SELECT COUNT(*) || '/' || COALESCE(MAX(cm.id), '')
+			 FROM channel_memberships cm
+			 JOIN channels c ON c.id = cm.channel_id
+			 WHERE c.workspace_id = ?)
+	`, workspaceID, workspaceID, userID, userID, userID, workspaceID).Scan(&channels, &memberships, &threads, &allMemberships)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(unread + "\x00" + channels + "\x00" + memberships + "\x00" + threads + "\x00" + allMemberships))
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+// MembersVersion returns a fingerprint that changes whenever ListMembers may
+// return something different for the channel: the count and newest update of
+// its memberships and of its members' profiles.
+func (r *Repository) MembersVersion(ctx context.Context, channelID string) (_ string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.MembersVersion")
+	defer func() { endSpan(err) }()
+
+	var version string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) || '/' || COALESCE(MAX(cm.id), '') || '/' || COALESCE(MAX(cm.updated_at), '') || '/' || COALESCE(MAX(u.updated_at), '')
+		FROM channel_memberships cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE cm.channel_id = ?
+	`, channelID).Scan(&version)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(version))
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
 // fetchDMParticipants loads participant info for DM channels, excluding the current user
 func (r *Repository) fetchDMParticipants(ctx context.Context, channels []ChannelWithMembership, dmChannelIDs []string, channelIndex map[string]int, currentUserID string) error {
 	if len(dmChannelIDs) == 0 {
@@ -332,7 +860,8 @@ func (r *Repository) fetchDMParticipants(ctx context.Context, channels []Channel
 	args = append(args, currentUserID)
 
 	query := `
-		SELECT cm.channel_id, u.id, u.email, u.display_name, u.avatar_url, cm.channel_role
+		SELECT cm.channel_id, u.id, u.email, u.display_name, u.avatar_url, cm.channel_role,
+		       u.status = 'deactivated' as is_deactivated
 		FROM channel_memberships cm
 		JOIN users u ON u.id = cm.user_id
 		WHERE cm.channel_id IN (` + strings.Join(placeholders, ",") + `)
@@ -351,7 +880,7 @@ func (r *Repository) fetchDMParticipants(ctx context.Context, channels []Channel
 		var member MemberInfo
 		var avatarURL, channelRole sql.NullString
 
-		if err := rows.Scan(&channelID, &member.UserID, &member.Email, &member.DisplayName, &avatarURL, &channelRole); err != nil {
+		if err := rows.Scan(&channelID, &member.UserID, &member.Email, &member.DisplayName, &avatarURL, &channelRole, &member.IsDeactivated); err != nil {
 			return err
 		}
 
@@ -375,37 +904,18 @@ func (r *Repository) fetchDMParticipants(ctx context.Context, channels []Channel
 func (r *Repository) GetWorkspaceNotificationSummaries(ctx context.Context, userID string) ([]WorkspaceNotificationSummary, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT c.workspace_id,
+		       COALESCE(SUM(cm.unread_count), 0) as unread_count,
 		       COALESCE(SUM(
-		           (SELECT COUNT(*) FROM messages m
-		            WHERE m.channel_id = c.id
-		              AND m.thread_parent_id IS NULL
-		              AND m.deleted_at IS NULL
-		              AND (cm.last_read_message_id IS NULL OR m.id > cm.last_read_message_id))
-		       ), 0) as unread_count,
-		       COALESCE(SUM(
-		           (SELECT COUNT(*) FROM messages m
-		            WHERE m.channel_id = c.id
-		              AND m.thread_parent_id IS NULL
-		              AND m.deleted_at IS NULL
-		              AND (cm.last_read_message_id IS NULL OR m.id > cm.last_read_message_id)
-		              AND CASE
-		                WHEN c.type IN ('dm', 'group_dm') THEN 1
-		                WHEN np.notify_level = 'none' THEN 0
-		                WHEN np.notify_level = 'all' THEN 1
-		                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
-		                  EXISTS (
-		                    SELECT 1 FROM json_each(m.mentions) je
-		                    WHERE je.value = ? OR je.value IN ('@channel', '@everyone')
-		                  )
-		                ELSE 0
-		              END = 1)
+		           CASE WHEN cm.snoozed_until > strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		                  OR wm.snoozed_until > strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		                THEN 0 ELSE cm.notification_count END
 		       ), 0) as notification_count
 		FROM channels c
 		JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
-		LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = ?
+		LEFT JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
 		WHERE c.archived_at IS NULL
 		GROUP BY c.workspace_id
-	`, userID, userID, userID)
+	`, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -673,25 +1183,58 @@ func (r *Repository) ConvertToChannel(ctx context.Context, channelID, name strin
 	return r.GetByID(ctx, channelID)
 }
 
-func (r *Repository) ListMembers(ctx context.Context, channelID string) ([]MemberInfo, error) {
+// ListMembers returns a page of the channel's members ordered by display
+// name, filtered by name or email and by channel role. Members without a
+// role count as posters.
+func (r *Repository) ListMembers(ctx context.Context, channelID string, opts MemberListOptions) (*MemberListResult, error) {
+	if opts.Limit <= 0 || opts.Limit > 1000 {
+		opts.Limit = 100
+	}
+
+	where := `cm.channel_id = ?`
+	args := []interface{}{channelID}
+	if q := strings.ToLower(strings.TrimSpace(opts.Query)); q != "" {
+		where += ` AND (LOWER(u.display_name) LIKE ? ESCAPE '\' OR LOWER(u.email) LIKE ? ESCAPE '\')`
+		pattern := "%" + escapeLike(q) + "%"
+		args = append(args, pattern, pattern)
+	}
+	if opts.Role != "" {
+		where += ` AND COALESCE(cm.channel_role, 'poster') = ?`
+		args = append(args, opts.Role)
+	}
+	if opts.Cursor != "" {
+		var cursorName string
+		err := r.db.QueryRowContext(ctx, `SELECT display_name FROM users WHERE id = ?`, opts.Cursor).Scan(&cursorName)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidCursor
+		}
+		if err != nil {
+			return nil, err
+		}
+		where += ` AND (u.display_name > ? OR (u.display_name = ? AND u.id > ?))`
+		args = append(args, cursorName, cursorName, opts.Cursor)
+	}
+
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT u.id, u.email, u.display_name, u.avatar_url, cm.channel_role
+		SELECT u.id, u.email, u.display_name, u.avatar_url, u.title, u.pronouns, u.timezone, cm.channel_role,
+		       u.status = 'deactivated' as is_deactivated
 		FROM channel_memberships cm
 		JOIN users u ON u.id = cm.user_id
-		WHERE cm.channel_id = ?
-		ORDER BY u.display_name
-	`, channelID)
+		WHERE `+where+`
+		ORDER BY u.display_name, u.id
+		LIMIT ?
+	`, append(args, opts.Limit+1)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var members []MemberInfo
+	members := []MemberInfo{}
 	for rows.Next() {
 		var m MemberInfo
-		var avatarURL, channelRole sql.NullString
+		var avatarURL, title, pronouns, timezone, channelRole sql.NullString
 
-		err := rows.Scan(&m.UserID, &m.Email, &m.DisplayName, &avatarURL, &channelRole)
+		err := rows.Scan(&m.UserID, &m.Email, &m.DisplayName, &avatarURL, &title, &pronouns, &timezone, &channelRole, &m.IsDeactivated)
 		if err != nil {
 			return nil, err
 		}
@@ -699,13 +1242,89 @@ func (r *Repository) ListMembers(ctx context.Context, channelID string) ([]Membe
 		if avatarURL.Valid {
 			m.AvatarURL = &avatarURL.String
 		}
+		if title.Valid {
+			m.Title = &title.String
+		}
+		if pronouns.Valid {
+			m.Pronouns = &pronouns.String
+		}
+		if timezone.Valid {
+			m.Timezone = &timezone.String
+		}
 		if channelRole.Valid {
 			m.ChannelRole = &channelRole.String
 		}
 		members = append(members, m)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &MemberListResult{Members: members}
+	if len(members) > opts.Limit {
+		result.Members = members[:opts.Limit]
+		result.HasMore = true
+		result.NextCursor = result.Members[opts.Limit-1].UserID
+	}
+	return result, nil
+}
+
+// ListMentionCandidates returns active channel members whose display name
+// (or any word of it) or email starts with query, most recent posters in the
+// channel first. An empty query matches every member.
+func (r *Repository) ListMentionCandidates(ctx context.Context, channelID, query string, limit int) ([]MentionCandidate, error) {
+	where := `cm.channel_id = ? AND u.status != 'deactivated'`
+	args := []interface{}{channelID, channelID}
+	if q := strings.ToLower(strings.TrimSpace(query)); q != "" {
+		where += ` AND (LOWER(u.display_name) LIKE ? ESCAPE '\' OR LOWER(u.display_name) LIKE ? ESCAPE '\' OR LOWER(u.email) LIKE ? ESCAPE '\')`
+		prefix := escapeLike(q) + "%"
+		args = append(args, prefix, "% "+prefix, prefix)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.display_name, u.avatar_url, u.title, u.pronouns, u.timezone, cm.channel_role,
+		       (SELECT MAX(m.created_at) FROM messages m
+		        WHERE m.channel_id = ? AND m.user_id = u.id AND m.deleted_at IS NULL) AS last_posted_at
+		FROM channel_memberships cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE `+where+`
+		ORDER BY last_posted_at IS NULL, last_posted_at DESC, u.display_name, u.id
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return members, rows.Err()
+	candidates := []MentionCandidate{}
+	for rows.Next() {
+		var c MentionCandidate
+		var avatarURL, title, pronouns, timezone, channelRole, lastPostedAt sql.NullString
+		if err := rows.Scan(&c.UserID, &c.Email, &c.DisplayName, &avatarURL, &title, &pronouns, &timezone, &channelRole, &lastPostedAt); err != nil {
+			return nil, err
+		}
+		if avatarURL.Valid {
+			c.AvatarURL = &avatarURL.String
+		}
+		if title.Valid {
+			c.Title = &title.String
+		}
+		if pronouns.Valid {
+			c.Pronouns = &pronouns.String
+		}
+		if timezone.Valid {
+			c.Timezone = &timezone.String
+		}
+		if channelRole.Valid {
+			c.ChannelRole = &channelRole.String
+		}
+		if lastPostedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, lastPostedAt.String)
+			c.LastPostedAt = &t
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
 }
 
 func (r *Repository) UpdateLastRead(ctx context.Context, userID, channelID, messageID string) error {
@@ -717,6 +1336,67 @@ func (r *Repository) UpdateLastRead(ctx context.Context, userID, channelID, mess
 	return err
 }
 
+// GetReadState returns the user's read position in a channel.
+func (r *Repository) GetReadState(ctx context.Context, userID, channelID string) (*ReadState, error) {
+	var state ReadState
+	var lastRead sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT last_read_message_id, unread_count FROM channel_memberships
+		WHERE user_id = ? AND channel_id = ?
+	`, userID, channelID).Scan(&lastRead, &state.UnreadCount)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotChannelMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lastRead.Valid {
+		state.LastReadMessageID = &lastRead.String
+	}
+
+	var firstUnread sql.NullString
+	err = r.db.QueryRowContext(ctx, `
+		SELECT MIN(id) FROM messages
+		WHERE channel_id = ? AND thread_parent_id IS NULL AND deleted_at IS NULL AND id > ?
+	`, channelID, lastRead.String).Scan(&firstUnread)
+	if err != nil {
+		return nil, err
+	}
+	if firstUnread.Valid {
+		state.FirstUnreadMessageID = &firstUnread.String
+	}
+	return &state, nil
+}
+
+// CountReadBetween counts the top-level, undeleted messages after afterID
+// up to and including throughID: those that moving a read position from
+// afterID to throughID marks read. An empty afterID counts from the start.
+func (r *Repository) CountReadBetween(ctx context.Context, channelID, afterID, throughID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM messages
+		WHERE channel_id = ? AND thread_parent_id IS NULL AND deleted_at IS NULL AND id > ? AND id <= ?
+	`, channelID, afterID, throughID).Scan(&count)
+	return count, err
+}
+
+// SetTopic sets the channel's topic; nil clears it.
+func (r *Repository) SetTopic(ctx context.Context, channel *Channel, topic *string) error {
+	channel.UpdatedAt = time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE channels SET topic = ?, updated_at = ? WHERE id = ?
+	`, topic, channel.UpdatedAt.Format(time.RFC3339), channel.ID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrChannelNotFound
+	}
+	channel.Topic = topic
+	return nil
+}
+
 func (r *Repository) StarChannel(ctx context.Context, userID, channelID string) error {
 	now := time.Now().UTC()
 	result, err := r.db.ExecContext(ctx, `
@@ -749,6 +1429,53 @@ func (r *Repository) UnstarChannel(ctx context.Context, userID, channelID string
 	return nil
 }
 
+// SnoozeChannel silences the user's notifications and notification badge for
+// the channel until the given time
+func (r *Repository) SnoozeChannel(ctx context.Context, userID, channelID string, until time.Time) error {
+	value := until.UTC().Format(time.RFC3339)
+	return r.setSnoozedUntil(ctx, userID, channelID, &value)
+}
+
+// UnsnoozeChannel ends a channel snooze early
+func (r *Repository) UnsnoozeChannel(ctx context.Context, userID, channelID string) error {
+	return r.setSnoozedUntil(ctx, userID, channelID, nil)
+}
+
+func (r *Repository) setSnoozedUntil(ctx context.Context, userID, channelID string, until *string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE channel_memberships SET snoozed_until = ?, updated_at = ?
+		WHERE user_id = ? AND channel_id = ?
+	`, until, time.Now().UTC().Format(time.RFC3339), userID, channelID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotChannelMember
+	}
+	return nil
+}
+
+// activeSnooze returns the later of a channel's snooze and its workspace's
+// snooze, or nil when neither is still running
+func activeSnooze(channelUntil, workspaceUntil sql.NullString) *time.Time {
+	now := time.Now()
+	var until *time.Time
+	for _, s := range []sql.NullString{channelUntil, workspaceUntil} {
+		if !s.Valid {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s.String)
+		if err != nil || !t.After(now) {
+			continue
+		}
+		if until == nil || t.After(*until) {
+			until = &t
+		}
+	}
+	return until
+}
+
 func (r *Repository) GetLatestMessageID(ctx context.Context, channelID string) (string, error) {
 	var messageID string
 	err := r.db.QueryRowContext(ctx, `
@@ -807,6 +1534,71 @@ func (r *Repository) ListMemberChannelIDs(ctx context.Context, workspaceID, user
 	return channelIDs, rows.Err()
 }
 
+// MarkAllRead moves the user's read position in every unarchived channel of
+// the workspace to the channel's latest top-level, undeleted message. It is a
+// single statement, so every membership moves or none does. Channels that were
+// already read are left alone and not returned.
+func (r *Repository) MarkAllRead(ctx context.Context, workspaceID, userID string) (marks []ReadMark, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "channel.MarkAllRead")
+	defer func() { endSpan(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH latest AS (
+			SELECT m.channel_id, MAX(m.id) AS message_id
+			FROM messages m
+			JOIN channels c ON c.id = m.channel_id
+			JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
+			WHERE c.workspace_id = ? AND c.archived_at IS NULL
+			  AND m.thread_parent_id IS NULL AND m.deleted_at IS NULL
+			GROUP BY m.channel_id
+		)
+		UPDATE channel_memberships
+		SET last_read_message_id = latest.message_id, updated_at = ?
+		FROM latest
+		WHERE channel_memberships.user_id = ?
+		  AND channel_memberships.channel_id = latest.channel_id
+		  AND (channel_memberships.last_read_message_id IS NULL OR channel_memberships.last_read_message_id < latest.message_id)
+		RETURNING channel_memberships.channel_id, channel_memberships.last_read_message_id
+	`, userID, workspaceID, time.Now().UTC().Format(time.RFC3339), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m ReadMark
+		if err := rows.Scan(&m.ChannelID, &m.MessageID); err != nil {
+			return nil, err
+		}
+		marks = append(marks, m)
+	}
+	return marks, rows.Err()
+}
+
+// ListShared returns up to limit unarchived channels, by name, that both
+// users are members of, and how many such channels there are in total.
+// Direct messages are not included.
+func (r *Repository) ListShared(ctx context.Context, workspaceID, userID, otherID string, limit int) ([]Channel, int, error) {
+	const shared = `
+		FROM channels c
+		JOIN channel_memberships a ON a.channel_id = c.id AND a.user_id = ?
+		JOIN channel_memberships b ON b.channel_id = c.id AND b.user_id = ?
+		WHERE c.workspace_id = ? AND c.archived_at IS NULL AND c.type IN ('public', 'private')
+	`
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+shared, userID, otherID, workspaceID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []Channel{}, 0, nil
+	}
+	channels, err := r.listByIDQuery(ctx, `SELECT c.id `+shared+` ORDER BY c.name LIMIT ?`, userID, otherID, workspaceID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return channels, total, nil
+}
+
 func (r *Repository) GetMemberUserIDs(ctx context.Context, channelID string) ([]string, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT user_id FROM channel_memberships WHERE channel_id = ?
@@ -827,10 +1619,125 @@ func (r *Repository) GetMemberUserIDs(ctx context.Context, channelID string) ([]
 	return userIDs, rows.Err()
 }
 
+// GetChannelMentionRecipientIDs returns the members a channel-wide mention
+// from senderID would notify: everyone but the sender, members whose
+// notifications for the channel resolve to none or are snoozed, and users in
+// a block relationship with the sender.
+func (r *Repository) GetChannelMentionRecipientIDs(ctx context.Context, channelID, senderID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cm.user_id
+		FROM channel_memberships cm
+		JOIN channels c ON c.id = cm.channel_id
+		LEFT JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
+		LEFT JOIN notification_preferences np ON np.channel_id = cm.channel_id AND np.user_id = cm.user_id
+		LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = cm.user_id
+		LEFT JOIN user_notification_settings uns ON uns.user_id = cm.user_id
+		WHERE cm.channel_id = ? AND cm.user_id != ?
+		  AND COALESCE(np.notify_level, wns.notify_level, uns.notify_level, '') != 'none'
+		  AND COALESCE(cm.snoozed_until, '') <= strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		  AND COALESCE(wm.snoozed_until, '') <= strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		  AND NOT EXISTS (
+			SELECT 1 FROM user_blocks ub
+			WHERE ub.workspace_id = c.workspace_id
+			  AND ((ub.blocker_id = cm.user_id AND ub.blocked_id = ?) OR (ub.blocker_id = ? AND ub.blocked_id = cm.user_id))
+		  )
+		ORDER BY cm.user_id
+	`, channelID, senderID, senderID, senderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// ListAutoJoinChannels returns the unarchived channels that new workspace
+// members join: the default channel and any channel marked auto_join
+func (r *Repository) ListAutoJoinChannels(ctx context.Context, workspaceID string) ([]Channel, error) {
+	return r.listByIDQuery(ctx, `
+		SELECT id FROM channels
+		WHERE workspace_id = ? AND (is_default = 1 OR auto_join = 1) AND archived_at IS NULL
+		ORDER BY is_default DESC, name
+	`, workspaceID)
+}
+
+// AddWorkspaceMembers adds every member of the channel's workspace who is not
+// already in the channel as a poster, returning the IDs of those added
+func (r *Repository) AddWorkspaceMembers(ctx context.Context, ch *Channel) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userIDs, err := queryStrings(ctx, tx, `
+		SELECT wm.user_id FROM workspace_memberships wm
+		WHERE wm.workspace_id = ? AND NOT EXISTS (
+			SELECT 1 FROM channel_memberships cm WHERE cm.channel_id = ? AND cm.user_id = wm.user_id
+		)
+		ORDER BY wm.user_id
+	`, ch.WorkspaceID, ch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	role := ChannelRolePoster
+	for _, userID := range userIDs {
+		if err := insertMembership(ctx, tx, userID, ch.ID, &role, now); err != nil {
+			return nil, err
+		}
+	}
+	return userIDs, tx.Commit()
+}
+
+// AddMembers adds the given users to the channel in one transaction, skipping
+// existing members and users outside the channel's workspace. It returns the
+// IDs of those added.
+func (r *Repository) AddMembers(ctx context.Context, ch *Channel, userIDs []string, role *string) ([]string, error) {
+	if ch.ArchivedAt != nil {
+		return nil, ErrChannelArchived
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	var added []string
+	for _, userID := range userIDs {
+		result, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO channel_memberships (id, user_id, channel_id, channel_role, created_at, updated_at)
+			SELECT ?, user_id, ?, ?, ?, ? FROM workspace_memberships WHERE user_id = ? AND workspace_id = ?
+		`, ulid.Make().String(), ch.ID, role, now, now, userID, ch.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			added = append(added, userID)
+		}
+	}
+	return added, tx.Commit()
+}
+
 // GetDefaultChannel returns the default channel for a workspace
 func (r *Repository) GetDefaultChannel(ctx context.Context, workspaceID string) (*Channel, error) {
 	return r.scanChannel(r.db.QueryRowContext(ctx, `
-		SELECT id, workspace_id, name, description, type, dm_participant_hash, is_default, archived_at, created_by, created_at, updated_at
+		SELECT id, workspace_id, name, description, topic, type, dm_participant_hash, is_default, auto_join, history_visibility, message_retention_days, who_can_mention_channel, post_policy, post_roles, restrict_thread_replies, slow_mode_seconds, archived_at, created_by, created_at, updated_at
 		FROM channels WHERE workspace_id = ? AND is_default = 1
 	`, workspaceID))
 }
@@ -849,13 +1756,59 @@ func (r *Repository) CreateDefaultChannel(ctx context.Context, workspaceID, crea
 	return ch, nil
 }
 
+func insertMembership(ctx context.Context, tx *sql.Tx, userID, channelID string, role *string, now string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO channel_memberships (id, user_id, channel_id, channel_role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, ulid.Make().String(), userID, channelID, role, now, now)
+	return err
+}
+
+// listByIDQuery loads the channels whose IDs the query selects, in order
+func (r *Repository) listByIDQuery(ctx context.Context, query string, args ...interface{}) ([]Channel, error) {
+	ids, err := queryStrings(ctx, r.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	channels := make([]Channel, 0, len(ids))
+	for _, id := range ids {
+		ch, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *ch)
+	}
+	return channels, nil
+}
+
+func queryStrings(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
 func (r *Repository) scanChannel(row *sql.Row) (*Channel, error) {
 	var c Channel
-	var description, dmHash, archivedAt, createdBy sql.NullString
+	var description, topic, dmHash, mentionPermission, postRoles, archivedAt, createdBy sql.NullString
+	var retentionDays sql.NullInt64
 	var createdAt, updatedAt string
-	var isDefault int
+	var isDefault, autoJoin, restrictThreadReplies int
 
-	err := row.Scan(&c.ID, &c.WorkspaceID, &c.Name, &description, &c.Type, &dmHash, &isDefault, &archivedAt, &createdBy, &createdAt, &updatedAt)
+	err := row.Scan(&c.ID, &c.WorkspaceID, &c.Name, &description, &topic, &c.Type, &dmHash, &isDefault, &autoJoin, &c.HistoryVisibility, &retentionDays, &mentionPermission, &c.PostPolicy, &postRoles, &restrictThreadReplies, &c.SlowModeSeconds, &archivedAt, &createdBy, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return nil, ErrChannelNotFound
 	}
@@ -866,9 +1819,21 @@ func (r *Repository) scanChannel(row *sql.Row) (*Channel, error) {
 	if description.Valid {
 		c.Description = &description.String
 	}
+	if topic.Valid {
+		c.Topic = &topic.String
+	}
 	if dmHash.Valid {
 		c.DMParticipantHash = &dmHash.String
 	}
+	if retentionDays.Valid {
+		days := int(retentionDays.Int64)
+		c.MessageRetentionDays = &days
+	}
+	if mentionPermission.Valid {
+		c.WhoCanMentionChannel = &mentionPermission.String
+	}
+	c.PostRoles = parsePostRoles(postRoles)
+	c.RestrictThreadReplies = restrictThreadReplies != 0
 	if archivedAt.Valid {
 		t, _ := time.Parse(time.RFC3339, archivedAt.String)
 		c.ArchivedAt = &t
@@ -879,6 +1844,7 @@ func (r *Repository) scanChannel(row *sql.Row) (*Channel, error) {
 	c.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	c.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 	c.IsDefault = isDefault != 0
+	c.AutoJoin = autoJoin != 0
 
 	return &c, nil
 }
@@ -935,3 +1901,22 @@ func (r *Repository) RemoveAllNonDMMemberships(ctx context.Context, tx *sql.Tx,
 
 	return channelIDs, nil
 }
+
+// formatPostRoles encodes post roles for storage; nil clears them.
+func formatPostRoles(roles []string) *string {
+	if len(roles) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(roles)
+	encoded := string(data)
+	return &encoded
+}
+
+func parsePostRoles(s sql.NullString) []string {
+	if !s.Valid {
+		return nil
+	}
+	var roles []string
+	_ = json.Unmarshal([]byte(s.String), &roles)
+	return roles
+}
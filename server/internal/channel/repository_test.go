@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -364,13 +365,89 @@ func TestRepository_ListMembers(t *testing.T) {
 	role := ChannelRolePoster
 	repo.AddMember(ctx, member.ID, ch.ID, &role)
 
-	members, err := repo.ListMembers(ctx, ch.ID)
+	result, err := repo.ListMembers(ctx, ch.ID, MemberListOptions{})
 	if err != nil {
 		t.Fatalf("ListMembers() error = %v", err)
 	}
 
-	if len(members) != 2 {
-		t.Fatalf("len(members) = %d, want 2", len(members))
+	if len(result.Members) != 2 {
+		t.Fatalf("len(members) = %d, want 2", len(result.Members))
+	}
+	if result.HasMore {
+		t.Error("HasMore = true, want false")
+	}
+}
+
+func TestRepository_ListMembers_Paging(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Ada")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := &Channel{WorkspaceID: ws.ID, Name: "general", Type: TypePublic}
+	repo.Create(ctx, ch, owner.ID)
+
+	viewer := ChannelRoleViewer
+	for _, name := range []string{"Bea", "Cal", "Dee", "Eve"} {
+		u := testutil.CreateTestUser(t, db, strings.ToLower(name)+"@example.com", name)
+		var role *string
+		if name == "Cal" {
+			role = &viewer
+		}
+		repo.AddMember(ctx, u.ID, ch.ID, role)
+	}
+
+	var names []string
+	opts := MemberListOptions{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("too many pages")
+		}
+		result, err := repo.ListMembers(ctx, ch.ID, opts)
+		if err != nil {
+			t.Fatalf("ListMembers() error = %v", err)
+		}
+		for _, m := range result.Members {
+			names = append(names, m.DisplayName)
+		}
+		if !result.HasMore {
+			break
+		}
+		opts.Cursor = result.NextCursor
+	}
+	if got := strings.Join(names, ","); got != "Ada,Bea,Cal,Dee,Eve" {
+		t.Errorf("paged members = %s, want all five in name order", got)
+	}
+
+	result, err := repo.ListMembers(ctx, ch.ID, MemberListOptions{Query: "EVE@"})
+	if err != nil {
+		t.Fatalf("ListMembers() error = %v", err)
+	}
+	if len(result.Members) != 1 || result.Members[0].DisplayName != "Eve" {
+		t.Errorf("members matching eve@ = %+v, want Eve", result.Members)
+	}
+
+	result, err = repo.ListMembers(ctx, ch.ID, MemberListOptions{Role: ChannelRoleViewer})
+	if err != nil {
+		t.Fatalf("ListMembers() error = %v", err)
+	}
+	if len(result.Members) != 1 || result.Members[0].DisplayName != "Cal" {
+		t.Errorf("viewers = %+v, want Cal", result.Members)
+	}
+
+	if _, err := repo.ListMembers(ctx, ch.ID, MemberListOptions{Cursor: "missing"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ListMembers() with an unknown cursor error = %v, want ErrInvalidCursor", err)
+	}
+
+	channels, err := repo.ListForWorkspace(ctx, ws.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("ListForWorkspace() error = %v", err)
+	}
+	for _, c := range channels {
+		if c.ID == ch.ID && c.MemberCount != 5 {
+			t.Errorf("MemberCount = %d, want 5", c.MemberCount)
+		}
 	}
 }
 
@@ -778,6 +855,29 @@ func setNotificationPreference(t *testing.T, db *sql.DB, userID, channelID, noti
 	}
 }
 
+// setNotificationSettings sets a user's workspace notification level, or
+// their global level when workspaceID is empty
+func setNotificationSettings(t *testing.T, db *sql.DB, userID, workspaceID, notifyLevel string) {
+	t.Helper()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	var err error
+	if workspaceID == "" {
+		_, err = db.ExecContext(context.Background(), `
+			INSERT INTO user_notification_settings (user_id, notify_level, created_at, updated_at)
+			VALUES (?, ?, ?, ?)
+		`, userID, notifyLevel, now, now)
+	} else {
+		_, err = db.ExecContext(context.Background(), `
+			INSERT INTO workspace_notification_settings (user_id, workspace_id, notify_level, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, userID, workspaceID, notifyLevel, now, now)
+	}
+	if err != nil {
+		t.Fatalf("setting notification settings: %v", err)
+	}
+}
+
 func TestRepository_ListForWorkspace_NotificationCount_DMsAlwaysNotify(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -1001,6 +1101,142 @@ func TestRepository_ListForWorkspace_NotificationCount_MentionsExplicit(t *testi
 	}
 }
 
+func TestRepository_ListForWorkspace_NotificationCount_InheritedSettings(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user1 := testutil.CreateTestUser(t, db, "user1@example.com", "User 1")
+	user2 := testutil.CreateTestUser(t, db, "user2@example.com", "User 2")
+	ws := testutil.CreateTestWorkspace(t, db, user1.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user1.ID, "general", "public")
+
+	createMessageWithMentions(t, db, ch.ID, user2.ID, "Hey @User 1", []string{user1.ID})
+	testutil.CreateTestMessage(t, db, ch.ID, user2.ID, "Hello")
+
+	notificationCount := func() int {
+		t.Helper()
+		channels, err := repo.ListForWorkspace(ctx, ws.ID, user1.ID)
+		if err != nil {
+			t.Fatalf("ListForWorkspace() error = %v", err)
+		}
+		for _, c := range channels {
+			if c.ID == ch.ID {
+				return c.NotificationCount
+			}
+		}
+		t.Fatal("channel not found in results")
+		return 0
+	}
+
+	// Global 'all' applies to channels without settings of their own
+	setNotificationSettings(t, db, user1.ID, "", "all")
+	if got := notificationCount(); got != 2 {
+		t.Errorf("global all: NotificationCount = %d, want 2", got)
+	}
+
+	// The workspace setting overrides the global one
+	setNotificationSettings(t, db, user1.ID, ws.ID, "none")
+	if got := notificationCount(); got != 0 {
+		t.Errorf("workspace none: NotificationCount = %d, want 0", got)
+	}
+
+	// The channel preference overrides both
+	setNotificationPreference(t, db, user1.ID, ch.ID, "mentions")
+	if got := notificationCount(); got != 1 {
+		t.Errorf("channel mentions: NotificationCount = %d, want 1", got)
+	}
+
+	// Removing the channel and workspace overrides falls back to global
+	if _, err := db.Exec(`DELETE FROM notification_preferences WHERE user_id = ?`, user1.ID); err != nil {
+		t.Fatalf("deleting preference: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM workspace_notification_settings WHERE user_id = ?`, user1.ID); err != nil {
+		t.Fatalf("deleting workspace settings: %v", err)
+	}
+	if got := notificationCount(); got != 2 {
+		t.Errorf("back to global all: NotificationCount = %d, want 2", got)
+	}
+}
+
+func TestRepository_ListForWorkspace_NotificationCount_Snoozed(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user1 := testutil.CreateTestUser(t, db, "user1@example.com", "User 1")
+	user2 := testutil.CreateTestUser(t, db, "user2@example.com", "User 2")
+	ws := testutil.CreateTestWorkspace(t, db, user1.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user1.ID, "general", "public")
+
+	createMessageWithMentions(t, db, ch.ID, user2.ID, "Hey @User 1", []string{user1.ID})
+
+	listed := func() ChannelWithMembership {
+		t.Helper()
+		channels, err := repo.ListForWorkspace(ctx, ws.ID, user1.ID)
+		if err != nil {
+			t.Fatalf("ListForWorkspace() error = %v", err)
+		}
+		for _, c := range channels {
+			if c.ID == ch.ID {
+				return c
+			}
+		}
+		t.Fatal("channel not found in results")
+		return ChannelWithMembership{}
+	}
+	summaryCount := func() int {
+		t.Helper()
+		summaries, err := repo.GetWorkspaceNotificationSummaries(ctx, user1.ID)
+		if err != nil {
+			t.Fatalf("GetWorkspaceNotificationSummaries() error = %v", err)
+		}
+		if len(summaries) != 1 {
+			t.Fatalf("got %d summaries, want 1", len(summaries))
+		}
+		return summaries[0].NotificationCount
+	}
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := repo.SnoozeChannel(ctx, user1.ID, ch.ID, until); err != nil {
+		t.Fatalf("SnoozeChannel() error = %v", err)
+	}
+	c := listed()
+	if c.NotificationCount != 0 || c.UnreadCount != 1 {
+		t.Errorf("snoozed: counts = %d/%d, want 0 notifications and 1 unread", c.NotificationCount, c.UnreadCount)
+	}
+	if c.SnoozedUntil == nil || !c.SnoozedUntil.Equal(until) {
+		t.Errorf("SnoozedUntil = %v, want %v", c.SnoozedUntil, until)
+	}
+	if got := summaryCount(); got != 0 {
+		t.Errorf("snoozed: summary NotificationCount = %d, want 0", got)
+	}
+
+	// The badge comes back once the snooze runs out
+	if err := repo.SnoozeChannel(ctx, user1.ID, ch.ID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("SnoozeChannel() error = %v", err)
+	}
+	if c := listed(); c.NotificationCount != 1 || c.SnoozedUntil != nil {
+		t.Errorf("expired: NotificationCount = %d, SnoozedUntil = %v, want 1 and nil", c.NotificationCount, c.SnoozedUntil)
+	}
+
+	// A workspace snooze covers the channel too
+	if _, err := db.Exec(`UPDATE workspace_memberships SET snoozed_until = ? WHERE user_id = ? AND workspace_id = ?`,
+		until.Format(time.RFC3339), user1.ID, ws.ID); err != nil {
+		t.Fatalf("snoozing workspace: %v", err)
+	}
+	if c := listed(); c.NotificationCount != 0 || c.SnoozedUntil == nil {
+		t.Errorf("workspace snoozed: NotificationCount = %d, SnoozedUntil = %v, want 0 and set", c.NotificationCount, c.SnoozedUntil)
+	}
+	if got := summaryCount(); got != 0 {
+		t.Errorf("workspace snoozed: summary NotificationCount = %d, want 0", got)
+	}
+
+	if err := repo.SnoozeChannel(ctx, user2.ID, ch.ID, until); err != ErrNotChannelMember {
+		t.Errorf("SnoozeChannel() for a non-member error = %v, want ErrNotChannelMember", err)
+	}
+}
+
 func TestRepository_GetWorkspaceNotificationSummaries(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -1101,3 +1337,281 @@ func TestRepository_GetWorkspaceNotificationSummaries_NoUnreads(t *testing.T) {
 		t.Error("expected workspace to appear in summaries")
 	}
 }
+
+func TestRepository_UnreadCounters_FollowMessageChanges(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user1 := testutil.CreateTestUser(t, db, "user1@example.com", "User 1")
+	user2 := testutil.CreateTestUser(t, db, "user2@example.com", "User 2")
+	user3 := testutil.CreateTestUser(t, db, "user3@example.com", "User 3")
+	ws := testutil.CreateTestWorkspace(t, db, user1.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user1.ID, "general", "public")
+
+	counts := func(userID string) UnreadCount {
+		t.Helper()
+		all, err := repo.ListUnreadCounts(ctx, ws.ID, userID)
+		if err != nil {
+			t.Fatalf("ListUnreadCounts() error = %v", err)
+		}
+		return all[ch.ID]
+	}
+	expect := func(step string, got, want UnreadCount) {
+		t.Helper()
+		if got != want {
+			t.Errorf("%s: counts = %+v, want %+v", step, got, want)
+		}
+	}
+
+	createMessageWithMentions(t, db, ch.ID, user2.ID, "Hey @User 1", []string{user1.ID})
+	read := testutil.CreateTestMessage(t, db, ch.ID, user2.ID, "Hello")
+	plain := testutil.CreateTestMessage(t, db, ch.ID, user2.ID, "World")
+	expect("new messages", counts(user1.ID), UnreadCount{Unread: 3, Notifications: 1})
+
+	if err := repo.UpdateLastRead(ctx, user1.ID, ch.ID, read.ID); err != nil {
+		t.Fatalf("UpdateLastRead() error = %v", err)
+	}
+	expect("after marking read", counts(user1.ID), UnreadCount{Unread: 1})
+
+	everyone := createMessageWithMentions(t, db, ch.ID, user2.ID, "@everyone look", []string{"@everyone"})
+	expect("after @everyone", counts(user1.ID), UnreadCount{Unread: 2, Notifications: 1})
+
+	// Thread replies do not count
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO messages (id, channel_id, user_id, content, thread_parent_id, created_at, updated_at)
+		VALUES (?, ?, ?, 'reply', ?, ?, ?)
+	`, ulid.Make().String(), ch.ID, user2.ID, plain.ID, time.Now().UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339)); err != nil {
+		t.Fatalf("creating reply: %v", err)
+	}
+	expect("after thread reply", counts(user1.ID), UnreadCount{Unread: 2, Notifications: 1})
+
+	if _, err := db.ExecContext(ctx, `UPDATE messages SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), everyone); err != nil {
+		t.Fatalf("deleting message: %v", err)
+	}
+	expect("after delete", counts(user1.ID), UnreadCount{Unread: 1})
+
+	if _, err := db.ExecContext(ctx, `UPDATE messages SET deleted_at = NULL WHERE id = ?`, everyone); err != nil {
+		t.Fatalf("restoring message: %v", err)
+	}
+	expect("after restore", counts(user1.ID), UnreadCount{Unread: 2, Notifications: 1})
+
+	setNotificationPreference(t, db, user1.ID, ch.ID, "all")
+	expect("after notify all", counts(user1.ID), UnreadCount{Unread: 2, Notifications: 2})
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM notification_preferences WHERE user_id = ? AND channel_id = ?`, user1.ID, ch.ID); err != nil {
+		t.Fatalf("deleting preference: %v", err)
+	}
+	expect("after preference removed", counts(user1.ID), UnreadCount{Unread: 2, Notifications: 1})
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM messages WHERE id = ?`, plain.ID); err != nil {
+		t.Fatalf("purging message: %v", err)
+	}
+	expect("after purge", counts(user1.ID), UnreadCount{Unread: 1, Notifications: 1})
+
+	// Marking an earlier message unread recounts from there
+	if err := repo.UpdateLastRead(ctx, user1.ID, ch.ID, ""); err != nil {
+		t.Fatalf("UpdateLastRead() error = %v", err)
+	}
+	expect("after marking unread", counts(user1.ID), UnreadCount{Unread: 3, Notifications: 2})
+
+	// Joining counts the existing history
+	if _, err := repo.AddMember(ctx, user3.ID, ch.ID, nil); err != nil {
+		t.Fatalf("AddMember() error = %v", err)
+	}
+	expect("after join", counts(user3.ID), UnreadCount{Unread: 3, Notifications: 1})
+}
+
+func TestRepository_UnreadCounters_FollowMentionChanges(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user1 := testutil.CreateTestUser(t, db, "user1@example.com", "User 1")
+	user2 := testutil.CreateTestUser(t, db, "user2@example.com", "User 2")
+	ws := testutil.CreateTestWorkspace(t, db, user1.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user1.ID, "general", "public")
+	if _, err := repo.AddMember(ctx, user2.ID, ch.ID, nil); err != nil {
+		t.Fatalf("AddMember() error = %v", err)
+	}
+
+	counts := func(userID string) UnreadCount {
+		t.Helper()
+		all, err := repo.ListUnreadCounts(ctx, ws.ID, userID)
+		if err != nil {
+			t.Fatalf("ListUnreadCounts() error = %v", err)
+		}
+		return all[ch.ID]
+	}
+	targets := func(messageID string) []string {
+		t.Helper()
+		rows, err := db.QueryContext(ctx, `SELECT target FROM message_mentions WHERE message_id = ? ORDER BY target`, messageID)
+		if err != nil {
+			t.Fatalf("querying mentions: %v", err)
+		}
+		defer rows.Close()
+		var out []string
+		for rows.Next() {
+			var target string
+			if err := rows.Scan(&target); err != nil {
+				t.Fatalf("scanning mention: %v", err)
+			}
+			out = append(out, target)
+		}
+		return out
+	}
+	setMentions := func(messageID string, mentions string) {
+		t.Helper()
+		if _, err := db.ExecContext(ctx, `UPDATE messages SET mentions = ? WHERE id = ?`, mentions, messageID); err != nil {
+			t.Fatalf("updating mentions: %v", err)
+		}
+	}
+
+	// user2 gets every message through their workspace setting
+	setNotificationSettings(t, db, user2.ID, ws.ID, "all")
+
+	msg := createMessageWithMentions(t, db, ch.ID, user1.ID, "Hi", []string{user2.ID, user2.ID})
+	if got := targets(msg); len(got) != 1 || got[0] != user2.ID {
+		t.Errorf("targets = %v, want [%s]", got, user2.ID)
+	}
+	if got := counts(user1.ID); got.Notifications != 0 {
+		t.Errorf("before mention: Notifications = %d, want 0", got.Notifications)
+	}
+
+	setMentions(msg, `["`+user1.ID+`"]`)
+	if got := targets(msg); len(got) != 1 || got[0] != user1.ID {
+		t.Errorf("targets after update = %v, want [%s]", got, user1.ID)
+	}
+	if got := counts(user1.ID); got.Notifications != 1 {
+		t.Errorf("after mention: Notifications = %d, want 1", got.Notifications)
+	}
+	if got := counts(user2.ID); got.Notifications != 1 {
+		t.Errorf("workspace all: Notifications = %d, want 1", got.Notifications)
+	}
+
+	setMentions(msg, `[]`)
+	if got := targets(msg); len(got) != 0 {
+		t.Errorf("targets after clearing = %v, want none", got)
+	}
+	if got := counts(user1.ID); got.Notifications != 0 {
+		t.Errorf("after clearing: Notifications = %d, want 0", got.Notifications)
+	}
+	if got := counts(user2.ID); got.Notifications != 1 {
+		t.Errorf("workspace all after clearing: Notifications = %d, want 1", got.Notifications)
+	}
+
+	if _, err := db.ExecContext(ctx, `DELETE FROM messages WHERE id = ?`, msg); err != nil {
+		t.Fatalf("purging message: %v", err)
+	}
+	if got := targets(msg); len(got) != 0 {
+		t.Errorf("targets after purge = %v, want none", got)
+	}
+}
+
+func TestRepository_CreateFromSpecs(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	guest := testutil.CreateTestUser(t, db, "guest@example.com", "Guest")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	now := time.Now().UTC().Format(time.RFC3339)
+	for userID, role := range map[string]string{member.ID: "member", guest.ID: "guest"} {
+		if _, err := db.Exec(`
+			INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, ulid.Make().String(), userID, ws.ID, role, now, now); err != nil {
+			t.Fatalf("adding member: %v", err)
+		}
+	}
+	if err := repo.Create(ctx, &Channel{WorkspaceID: ws.ID, Name: "random", Type: TypePublic}, owner.ID); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	created, skipped, err := repo.CreateFromSpecs(ctx, ws.ID, owner.ID, []Spec{
+		{Name: "announcements", Type: TypePublic, MemberRoles: []string{"member", "guest"}},
+		{Name: "random", Type: TypePublic},
+		{Name: "leads", Type: TypePrivate},
+	})
+	if err != nil {
+		t.Fatalf("CreateFromSpecs() error = %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "random" {
+		t.Errorf("skipped = %v, want [random]", skipped)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d channels, want 2", len(created))
+	}
+	if got := len(created[0].MemberIDs); got != 3 {
+		t.Errorf("announcements members = %d, want 3", got)
+	}
+	if got := created[1].MemberIDs; len(got) != 1 || got[0] != owner.ID {
+		t.Errorf("leads members = %v, want only the creator", got)
+	}
+
+	membership, err := repo.GetMembership(ctx, guest.ID, created[0].Channel.ID)
+	if err != nil {
+		t.Fatalf("GetMembership() error = %v", err)
+	}
+	if membership.ChannelRole != nil {
+		t.Errorf("ChannelRole = %v, want nil", *membership.ChannelRole)
+	}
+	stored, err := repo.GetByID(ctx, created[1].Channel.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.Type != TypePrivate || stored.PostPolicy != PostPolicyEveryone {
+		t.Errorf("stored = %+v, want a private channel with the default post policy", stored)
+	}
+}
+
+func TestRepository_AutoJoinChannels(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`
+		INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+		VALUES (?, ?, ?, 'member', ?, ?)
+	`, ulid.Make().String(), member.ID, ws.ID, now, now); err != nil {
+		t.Fatalf("adding member: %v", err)
+	}
+
+	general := &Channel{WorkspaceID: ws.ID, Name: "general", Type: TypePublic, IsDefault: true}
+	onboarding := &Channel{WorkspaceID: ws.ID, Name: "onboarding", Type: TypePublic}
+	random := &Channel{WorkspaceID: ws.ID, Name: "random", Type: TypePublic}
+	for _, ch := range []*Channel{general, onboarding, random} {
+		if err := repo.Create(ctx, ch, owner.ID); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	onboarding.AutoJoin = true
+	if err := repo.Update(ctx, onboarding); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	channels, err := repo.ListAutoJoinChannels(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("ListAutoJoinChannels() error = %v", err)
+	}
+	if len(channels) != 2 || channels[0].ID != general.ID || channels[1].ID != onboarding.ID || !channels[1].AutoJoin {
+		t.Errorf("auto-join channels = %+v, want general then onboarding", channels)
+	}
+
+	added, err := repo.AddWorkspaceMembers(ctx, onboarding)
+	if err != nil {
+		t.Fatalf("AddWorkspaceMembers() error = %v", err)
+	}
+	if len(added) != 1 || added[0] != member.ID {
+		t.Errorf("added = %v, want only the member (the creator is already in)", added)
+	}
+	if added, _ := repo.AddWorkspaceMembers(ctx, onboarding); len(added) != 0 {
+		t.Errorf("second backfill added %v, want nobody", added)
+	}
+}
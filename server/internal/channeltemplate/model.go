@@ -0,0 +1,26 @@
+package channeltemplate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("channel template not found")
+	ErrNameExists       = errors.New("a channel template with this name already exists")
+)
+
+// Template is a named set of channels that admins can create in one step,
+// either when creating a workspace or later on.
+type Template struct {
+	ID          string         `json:"id"`
+	WorkspaceID string         `json:"workspace_id"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Channels    []channel.Spec `json:"channels"`
+	CreatedBy   *string        `json:"created_by,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,123 @@
+package channeltemplate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/oklog/ulid/v2"
+)
+
+const templateColumns = `id, workspace_id, name, description, channels, created_by, created_at, updated_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// List returns a workspace's templates ordered by name
+func (r *Repository) List(ctx context.Context, workspaceID string) ([]Template, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+templateColumns+`
+		FROM channel_templates
+		WHERE workspace_id = ?
+		ORDER BY LOWER(name), id
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []Template{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*Template, error) {
+	t, err := scanTemplate(r.db.QueryRowContext(ctx, `
+		SELECT `+templateColumns+`
+		FROM channel_templates
+		WHERE id = ?
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (r *Repository) Create(ctx context.Context, t *Template) error {
+	channels, err := json.Marshal(t.Channels)
+	if err != nil {
+		return err
+	}
+
+	t.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO channel_templates (id, workspace_id, name, description, channels, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, t.ID, t.WorkspaceID, t.Name, t.Description, string(channels), t.CreatedBy, now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if isUniqueConstraintError(err) {
+		return ErrNameExists
+	}
+	return err
+}
+
+// Update saves a template's name, description and channels
+func (r *Repository) Update(ctx context.Context, t *Template) error {
+	channels, err := json.Marshal(t.Channels)
+	if err != nil {
+		return err
+	}
+
+	t.UpdatedAt = time.Now().UTC()
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE channel_templates SET name = ?, description = ?, channels = ?, updated_at = ?
+		WHERE id = ?
+	`, t.Name, t.Description, string(channels), t.UpdatedAt.Format(time.RFC3339), t.ID)
+	if isUniqueConstraintError(err) {
+		return ErrNameExists
+	}
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM channel_templates WHERE id = ?`, id)
+	return err
+}
+
+func scanTemplate(row interface{ Scan(...any) error }) (*Template, error) {
+	var t Template
+	var channels, createdAt, updatedAt string
+	err := row.Scan(&t.ID, &t.WorkspaceID, &t.Name, &t.Description, &channels, &t.CreatedBy, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.Channels = []channel.Spec{}
+	if err := json.Unmarshal([]byte(channels), &t.Channels); err != nil {
+		return nil, err
+	}
+	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &t, nil
+}
+
+func isUniqueConstraintError(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key"))
+}
@@ -0,0 +1,64 @@
+package channeltemplate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestRepository_CRUD(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+
+	desc := "Company news"
+	tmpl := &Template{
+		WorkspaceID: ws.ID,
+		Name:        "Onboarding",
+		Channels: []channel.Spec{
+			{Name: "announcements", Type: channel.TypePublic, Description: &desc, MemberRoles: []string{"member"}},
+			{Name: "leads", Type: channel.TypePrivate},
+		},
+		CreatedBy: &owner.ID,
+	}
+	if err := repo.Create(ctx, tmpl); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Create(ctx, &Template{WorkspaceID: ws.ID, Name: "Onboarding"}); !errors.Is(err, ErrNameExists) {
+		t.Errorf("duplicate name error = %v, want ErrNameExists", err)
+	}
+
+	got, err := repo.GetByID(ctx, tmpl.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if len(got.Channels) != 2 || got.Channels[0].Description == nil || *got.Channels[0].Description != desc || got.Channels[0].MemberRoles[0] != "member" {
+		t.Errorf("channels = %+v, want the stored specs", got.Channels)
+	}
+
+	got.Name = "Starter"
+	got.Channels = got.Channels[:1]
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	list, err := repo.List(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "Starter" || len(list[0].Channels) != 1 {
+		t.Errorf("list = %+v, want the updated template", list)
+	}
+
+	if err := repo.Delete(ctx, tmpl.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, tmpl.ID); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("GetByID() after delete error = %v, want ErrTemplateNotFound", err)
+	}
+}
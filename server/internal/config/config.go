@@ -11,8 +11,15 @@ type Config struct {
 	Email             EmailConfig            `koanf:"email"`
 	RateLimit         RateLimitConfig        `koanf:"rate_limit"`
 	SSE               SSEConfig              `koanf:"sse"`
+	Messages          MessagesConfig         `koanf:"messages"`
+	Maintenance       MaintenanceConfig      `koanf:"maintenance"`
+	LinkPreviews      LinkPreviewConfig      `koanf:"link_previews"`
+	GC                GCConfig               `koanf:"gc"`
+	Backup            BackupConfig           `koanf:"backup"`
+	Notifications     NotificationConfig     `koanf:"notifications"`
 	PushNotifications PushNotificationConfig `koanf:"push_notifications"`
 	Telemetry         TelemetryConfig        `koanf:"telemetry"`
+	Features          map[string]bool        `koanf:"features"` // server-wide feature flag values; workspaces can override them
 }
 
 type LogConfig struct {
@@ -21,14 +28,18 @@ type LogConfig struct {
 }
 
 type ServerConfig struct {
-	Host           string        `koanf:"host"`
-	Port           int           `koanf:"port"`
-	PublicURL      string        `koanf:"public_url"`
-	AllowedOrigins []string      `koanf:"allowed_origins"`
-	TLS            TLSConfig     `koanf:"tls"`
-	ReadTimeout    time.Duration `koanf:"read_timeout"`
-	WriteTimeout   time.Duration `koanf:"write_timeout"`
-	IdleTimeout    time.Duration `koanf:"idle_timeout"`
+	Host             string        `koanf:"host"`
+	Port             int           `koanf:"port"`
+	PublicURL        string        `koanf:"public_url"`
+	AllowedOrigins   []string      `koanf:"allowed_origins"`
+	TLS              TLSConfig     `koanf:"tls"`
+	ReadTimeout      time.Duration `koanf:"read_timeout"`
+	WriteTimeout     time.Duration `koanf:"write_timeout"`
+	IdleTimeout      time.Duration `koanf:"idle_timeout"`
+	APIDocsSpec      string        `koanf:"api_docs_spec"`     // OpenAPI spec served with Swagger UI at /api/docs; empty disables
+	CompressionLevel int           `koanf:"compression_level"` // brotli/gzip level for JSON, text and script responses (1-9); 0 disables
+	ServeClient      bool          `koanf:"serve_client"`      // serve the embedded web client; false for API-only deployments
+	AdminToken       string        `koanf:"admin_token"`       // bearer token for /admin endpoints; empty disables them
 }
 
 type TLSConfig struct {
@@ -45,24 +56,29 @@ type AutoTLSConfig struct {
 }
 
 type DatabaseConfig struct {
-	Path             string `koanf:"path"`
-	MaxOpenConns     int    `koanf:"max_open_conns"`
-	BusyTimeout      int    `koanf:"busy_timeout"`
-	CacheSize        int    `koanf:"cache_size"`
-	MmapSize         int64  `koanf:"mmap_size"`
-	JournalSizeLimit int64  `koanf:"journal_size_limit"`
+	Path               string        `koanf:"path"`
+	MaxOpenConns       int           `koanf:"max_open_conns"`
+	BusyTimeout        int           `koanf:"busy_timeout"`
+	CacheSize          int           `koanf:"cache_size"`
+	MmapSize           int64         `koanf:"mmap_size"`
+	JournalSizeLimit   int64         `koanf:"journal_size_limit"`
+	SlowQueryThreshold time.Duration `koanf:"slow_query_threshold"` // 0 disables slow-query logging
 }
 
 type AuthConfig struct {
-	SessionDuration time.Duration `koanf:"session_duration"`
+	SessionDuration time.Duration `koanf:"session_duration"` // refresh token lifetime, extended on each refresh
+	AccessTokenTTL  time.Duration `koanf:"access_token_ttl"` // 0 makes access tokens last the whole session
 	BcryptCost      int           `koanf:"bcrypt_cost"`
 }
 
 type StorageConfig struct {
-	Type          string      `koanf:"type"` // "off", "local", or "s3"
-	MaxUploadSize int64       `koanf:"max_upload_size"`
-	Local         LocalConfig `koanf:"local"`
-	S3            S3Config    `koanf:"s3"`
+	Type             string        `koanf:"type"` // "off", "local", or "s3"
+	MaxUploadSize    int64         `koanf:"max_upload_size"`
+	UploadSessionTTL time.Duration `koanf:"upload_session_ttl"` // idle resumable uploads are discarded after this
+	WorkspaceQuota   int64         `koanf:"workspace_quota"`    // max attachment bytes per workspace; 0 is unlimited
+	Local            LocalConfig   `koanf:"local"`
+	S3               S3Config      `koanf:"s3"`
+	ClamAV           ClamAVConfig  `koanf:"clamav"`
 }
 
 type LocalConfig struct {
@@ -80,6 +96,11 @@ type S3Config struct {
 	UseSSL    bool   `koanf:"use_ssl"`
 }
 
+type ClamAVConfig struct {
+	Address string        `koanf:"address"` // clamd socket, "unix:///path" or "tcp://host:port"; empty disables scanning
+	Timeout time.Duration `koanf:"timeout"` // per-file scan limit
+}
+
 type EmailConfig struct {
 	Enabled  bool   `koanf:"enabled"`
 	Host     string `koanf:"host"`
@@ -98,6 +119,13 @@ type RateLimitConfig struct {
 	VerifyEmail         RateLimitEndpoint `koanf:"verify_email"`
 	ResendVerification  RateLimitEndpoint `koanf:"resend_verification"`
 	DeviceTokenRegister RateLimitEndpoint `koanf:"device_token_register"`
+	IncomingWebhook     RateLimitEndpoint `koanf:"incoming_webhook"`
+	OpenSignup          RateLimitEndpoint `koanf:"open_signup"` // joins per workspace through its open signup link
+
+	// Token buckets per route class, counted per user (per IP when signed out)
+	Auth        RateLimitEndpoint `koanf:"auth"`         // all /api/auth/* endpoints
+	SendMessage RateLimitEndpoint `koanf:"send_message"` // sending messages
+	API         RateLimitEndpoint `koanf:"api"`          // every other API request
 }
 
 type RateLimitEndpoint struct {
@@ -106,10 +134,63 @@ type RateLimitEndpoint struct {
 }
 
 type SSEConfig struct {
-	EventRetention    time.Duration `koanf:"event_retention"`
-	CleanupInterval   time.Duration `koanf:"cleanup_interval"`
-	HeartbeatInterval time.Duration `koanf:"heartbeat_interval"`
-	ClientBufferSize  int           `koanf:"client_buffer_size"`
+	EventRetention    time.Duration      `koanf:"event_retention"`
+	CleanupInterval   time.Duration      `koanf:"cleanup_interval"`
+	HeartbeatInterval time.Duration      `koanf:"heartbeat_interval"`
+	ClientBufferSize  int                `koanf:"client_buffer_size"`
+	OverflowPolicy    string             `koanf:"overflow_policy"` // "drop_oldest" or "disconnect" when a client's buffer is full
+	WriteTimeout      time.Duration      `koanf:"write_timeout"`   // how long one write to a stream may take before the client is dropped
+	Broadcast         SSEBroadcastConfig `koanf:"broadcast"`
+}
+
+// SSEBroadcastConfig selects how events reach clients connected to other
+// server instances.
+type SSEBroadcastConfig struct {
+	Backend  string `koanf:"backend"` // "memory" (single instance) or "redis"
+	RedisURL string `koanf:"redis_url"`
+	Channel  string `koanf:"channel"` // Redis pub/sub channel shared by all instances
+}
+
+type MessagesConfig struct {
+	ThreadParticipantPreview int           `koanf:"thread_participant_preview"` // participants attached to thread parents
+	UndeleteWindow           time.Duration `koanf:"undelete_window"`            // how long authors can restore a deleted message
+	MaxLength                int           `koanf:"max_length"`                 // longest message content in characters
+	ListContentLength        int           `koanf:"list_content_length"`        // list endpoints cut longer content short; 0 sends it whole
+	SearchOffset             bool          `koanf:"search_offset"`              // accept the deprecated offset parameter in message search
+	ColdStorageDays          int           `koanf:"cold_storage_days"`          // archive threads dormant this long out of the hot table; 0 disables
+}
+
+// MaintenanceConfig puts the whole server into read-only mode, e.g. during a
+// migration. Workspace owners can also turn read-only mode on for a single
+// workspace.
+type MaintenanceConfig struct {
+	ReadOnly bool   `koanf:"read_only"` // reject sends, edits, reactions and uploads
+	Message  string `koanf:"message"`   // shown to users while read-only
+}
+
+type LinkPreviewConfig struct {
+	AllowedDomains []string `koanf:"allowed_domains"` // if non-empty, only these domains are unfurled
+	BlockedDomains []string `koanf:"blocked_domains"` // never unfurled
+}
+
+type GCConfig struct {
+	Interval      time.Duration `koanf:"interval"`       // 0 disables garbage collection
+	AttachmentTTL time.Duration `koanf:"attachment_ttl"` // unlinked uploads older than this are deleted
+}
+
+type BackupConfig struct {
+	Interval time.Duration `koanf:"interval"` // 0 disables scheduled backups
+	Dir      string        `koanf:"dir"`      // where archives are written
+	Keep     int           `koanf:"keep"`     // scheduled backups keep this many archives; 0 keeps all
+}
+
+// NotificationConfig sizes the queue that delivers notifications in the
+// background.
+type NotificationConfig struct {
+	Workers      int           `koanf:"workers"`       // notifications delivered at once
+	QueueSize    int           `koanf:"queue_size"`    // messages waiting for a worker before senders wait
+	MaxAttempts  int           `koanf:"max_attempts"`  // deliveries tried before a message is dead-lettered
+	RetryBackoff time.Duration `koanf:"retry_backoff"` // wait before the first retry; doubled for each one after
 }
 
 type PushNotificationConfig struct {
@@ -149,31 +230,38 @@ func Defaults() *Config {
 					CacheDir: "./data/certs",
 				},
 			},
-			ReadTimeout:  30 * time.Second,
-			WriteTimeout: 60 * time.Second,
-			IdleTimeout:  120 * time.Second,
+			ReadTimeout:      30 * time.Second,
+			WriteTimeout:     60 * time.Second,
+			IdleTimeout:      120 * time.Second,
+			CompressionLevel: 5,
+			ServeClient:      true,
 		},
 		Database: DatabaseConfig{
-			Path:             "./data/enzyme.db",
-			MaxOpenConns:     10,
-			BusyTimeout:      5000,
-			CacheSize:        -8000,
-			MmapSize:         268435456, // 256MB
-			JournalSizeLimit: 67108864,  // 64MB
+			Path:               "./data/enzyme.db",
+			MaxOpenConns:       10,
+			BusyTimeout:        5000,
+			CacheSize:          -8000,
+			MmapSize:           268435456, // 256MB
+			JournalSizeLimit:   67108864,  // 64MB
+			SlowQueryThreshold: 200 * time.Millisecond,
 		},
 		Auth: AuthConfig{
 			SessionDuration: 720 * time.Hour, // 30 days
 			BcryptCost:      12,
 		},
 		Storage: StorageConfig{
-			Type:          "local",
-			MaxUploadSize: 10 * 1024 * 1024, // 10MB
+			Type:             "local",
+			MaxUploadSize:    10 * 1024 * 1024, // 10MB
+			UploadSessionTTL: 24 * time.Hour,
 			Local: LocalConfig{
 				Path: "./data/uploads",
 			},
 			S3: S3Config{
 				UseSSL: true,
 			},
+			ClamAV: ClamAVConfig{
+				Timeout: 30 * time.Second,
+			},
 		},
 		Email: EmailConfig{
 			Enabled: false,
@@ -188,12 +276,47 @@ func Defaults() *Config {
 			VerifyEmail:         RateLimitEndpoint{Limit: 10, Window: 15 * time.Minute},
 			ResendVerification:  RateLimitEndpoint{Limit: 5, Window: time.Hour},
 			DeviceTokenRegister: RateLimitEndpoint{Limit: 10, Window: time.Minute},
+			IncomingWebhook:     RateLimitEndpoint{Limit: 30, Window: time.Minute},
+			OpenSignup:          RateLimitEndpoint{Limit: 50, Window: time.Hour},
+			Auth:                RateLimitEndpoint{Limit: 30, Window: time.Minute},
+			SendMessage:         RateLimitEndpoint{Limit: 60, Window: time.Minute},
+			API:                 RateLimitEndpoint{Limit: 600, Window: time.Minute},
 		},
 		SSE: SSEConfig{
 			EventRetention:    24 * time.Hour,
 			CleanupInterval:   time.Hour,
 			HeartbeatInterval: 30 * time.Second,
 			ClientBufferSize:  256,
+			OverflowPolicy:    "drop_oldest",
+			WriteTimeout:      10 * time.Second,
+			Broadcast: SSEBroadcastConfig{
+				Backend: "memory",
+				Channel: "enzyme:sse",
+			},
+		},
+		Messages: MessagesConfig{
+			ThreadParticipantPreview: 3,
+			UndeleteWindow:           24 * time.Hour,
+			MaxLength:                40000,
+			SearchOffset:             true,
+		},
+		LinkPreviews: LinkPreviewConfig{
+			AllowedDomains: []string{},
+			BlockedDomains: []string{},
+		},
+		GC: GCConfig{
+			Interval:      6 * time.Hour,
+			AttachmentTTL: 24 * time.Hour,
+		},
+		Backup: BackupConfig{
+			Dir:  "./data/backups",
+			Keep: 7,
+		},
+		Notifications: NotificationConfig{
+			Workers:      4,
+			QueueSize:    1000,
+			MaxAttempts:  5,
+			RetryBackoff: 2 * time.Second,
 		},
 		PushNotifications: PushNotificationConfig{
 			Enabled:        false,
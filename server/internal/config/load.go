@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/enzyme/server/internal/features"
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
@@ -61,12 +62,23 @@ func Load(configPath string, flags *pflag.FlagSet) (*Config, error) {
 		envKey := "ENZYME_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
 		envMap[envKey] = key
 	}
+	// Feature flags have no defaults to take their keys from
+	for _, def := range features.Registry {
+		envMap["ENZYME_FEATURES_"+strings.ToUpper(def.Key)] = "features." + def.Key
+	}
 
-	if err := k.Load(env.Provider("ENZYME_", ".", func(s string) string {
-		if key, ok := envMap[s]; ok {
-			return key
+	// List-valued keys (e.g. link_previews.blocked_domains) take a
+	// comma-separated value.
+	if err := k.Load(env.ProviderWithValue("ENZYME_", ".", func(s, v string) (string, interface{}) {
+		key, ok := envMap[s]
+		if !ok {
+			return "", nil
+		}
+		switch k.Get(key).(type) {
+		case []string, []interface{}:
+			return key, splitList(v)
 		}
-		return ""
+		return key, v
 	}), nil); err != nil {
 		return nil, fmt.Errorf("loading env vars: %w", err)
 	}
@@ -94,6 +106,17 @@ func Load(configPath string, flags *pflag.FlagSet) (*Config, error) {
 	return &cfg, nil
 }
 
+// splitList splits a comma-separated env value, dropping empty entries.
+func splitList(v string) []string {
+	items := []string{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 type defaultsProviderStruct struct {
 	defaults *Config
 }
@@ -127,24 +150,32 @@ func (d *defaultsProviderStruct) Read() (map[string]interface{}, error) {
 					"cache_dir": d.defaults.Server.TLS.Auto.CacheDir,
 				},
 			},
-			"read_timeout":  d.defaults.Server.ReadTimeout.String(),
-			"write_timeout": d.defaults.Server.WriteTimeout.String(),
-			"idle_timeout":  d.defaults.Server.IdleTimeout.String(),
+			"read_timeout":      d.defaults.Server.ReadTimeout.String(),
+			"write_timeout":     d.defaults.Server.WriteTimeout.String(),
+			"idle_timeout":      d.defaults.Server.IdleTimeout.String(),
+			"api_docs_spec":     d.defaults.Server.APIDocsSpec,
+			"compression_level": d.defaults.Server.CompressionLevel,
+			"serve_client":      d.defaults.Server.ServeClient,
+			"admin_token":       d.defaults.Server.AdminToken,
 		},
 		"database": map[string]interface{}{
-			"path":           d.defaults.Database.Path,
-			"max_open_conns": d.defaults.Database.MaxOpenConns,
-			"busy_timeout":   d.defaults.Database.BusyTimeout,
-			"cache_size":     d.defaults.Database.CacheSize,
-			"mmap_size":      d.defaults.Database.MmapSize,
+			"path":                 d.defaults.Database.Path,
+			"max_open_conns":       d.defaults.Database.MaxOpenConns,
+			"busy_timeout":         d.defaults.Database.BusyTimeout,
+			"cache_size":           d.defaults.Database.CacheSize,
+			"mmap_size":            d.defaults.Database.MmapSize,
+			"slow_query_threshold": d.defaults.Database.SlowQueryThreshold.String(),
 		},
 		"auth": map[string]interface{}{
 			"session_duration": d.defaults.Auth.SessionDuration.String(),
+			"access_token_ttl": d.defaults.Auth.AccessTokenTTL.String(),
 			"bcrypt_cost":      d.defaults.Auth.BcryptCost,
 		},
 		"storage": map[string]interface{}{
-			"type":            d.defaults.Storage.Type,
-			"max_upload_size": d.defaults.Storage.MaxUploadSize,
+			"type":               d.defaults.Storage.Type,
+			"max_upload_size":    d.defaults.Storage.MaxUploadSize,
+			"upload_session_ttl": d.defaults.Storage.UploadSessionTTL.String(),
+			"workspace_quota":    d.defaults.Storage.WorkspaceQuota,
 			"local": map[string]interface{}{
 				"path":           d.defaults.Storage.Local.Path,
 				"signing_secret": d.defaults.Storage.Local.SigningSecret,
@@ -158,6 +189,10 @@ func (d *defaultsProviderStruct) Read() (map[string]interface{}, error) {
 				"path_style": d.defaults.Storage.S3.PathStyle,
 				"use_ssl":    d.defaults.Storage.S3.UseSSL,
 			},
+			"clamav": map[string]interface{}{
+				"address": d.defaults.Storage.ClamAV.Address,
+				"timeout": d.defaults.Storage.ClamAV.Timeout.String(),
+			},
 		},
 		"email": map[string]interface{}{
 			"enabled":  d.defaults.Email.Enabled,
@@ -197,6 +232,32 @@ func (d *defaultsProviderStruct) Read() (map[string]interface{}, error) {
 				"limit":  d.defaults.RateLimit.DeviceTokenRegister.Limit,
 				"window": d.defaults.RateLimit.DeviceTokenRegister.Window.String(),
 			},
+			"incoming_webhook": map[string]interface{}{
+				"limit":  d.defaults.RateLimit.IncomingWebhook.Limit,
+				"window": d.defaults.RateLimit.IncomingWebhook.Window.String(),
+			},
+			"open_signup": map[string]interface{}{
+				"limit":  d.defaults.RateLimit.OpenSignup.Limit,
+				"window": d.defaults.RateLimit.OpenSignup.Window.String(),
+			},
+			"auth": map[string]interface{}{
+				"limit":  d.defaults.RateLimit.Auth.Limit,
+				"window": d.defaults.RateLimit.Auth.Window.String(),
+			},
+			"send_message": map[string]interface{}{
+				"limit":  d.defaults.RateLimit.SendMessage.Limit,
+				"window": d.defaults.RateLimit.SendMessage.Window.String(),
+			},
+			"api": map[string]interface{}{
+				"limit":  d.defaults.RateLimit.API.Limit,
+				"window": d.defaults.RateLimit.API.Window.String(),
+			},
+		},
+		"notifications": map[string]interface{}{
+			"workers":       d.defaults.Notifications.Workers,
+			"queue_size":    d.defaults.Notifications.QueueSize,
+			"max_attempts":  d.defaults.Notifications.MaxAttempts,
+			"retry_backoff": d.defaults.Notifications.RetryBackoff.String(),
 		},
 		"push_notifications": map[string]interface{}{
 			"enabled":         d.defaults.PushNotifications.Enabled,
@@ -208,6 +269,38 @@ func (d *defaultsProviderStruct) Read() (map[string]interface{}, error) {
 			"cleanup_interval":   d.defaults.SSE.CleanupInterval.String(),
 			"heartbeat_interval": d.defaults.SSE.HeartbeatInterval.String(),
 			"client_buffer_size": d.defaults.SSE.ClientBufferSize,
+			"overflow_policy":    d.defaults.SSE.OverflowPolicy,
+			"write_timeout":      d.defaults.SSE.WriteTimeout.String(),
+			"broadcast": map[string]interface{}{
+				"backend":   d.defaults.SSE.Broadcast.Backend,
+				"redis_url": d.defaults.SSE.Broadcast.RedisURL,
+				"channel":   d.defaults.SSE.Broadcast.Channel,
+			},
+		},
+		"messages": map[string]interface{}{
+			"thread_participant_preview": d.defaults.Messages.ThreadParticipantPreview,
+			"undelete_window":            d.defaults.Messages.UndeleteWindow.String(),
+			"max_length":                 d.defaults.Messages.MaxLength,
+			"list_content_length":        d.defaults.Messages.ListContentLength,
+			"search_offset":              d.defaults.Messages.SearchOffset,
+			"cold_storage_days":          d.defaults.Messages.ColdStorageDays,
+		},
+		"maintenance": map[string]interface{}{
+			"read_only": d.defaults.Maintenance.ReadOnly,
+			"message":   d.defaults.Maintenance.Message,
+		},
+		"link_previews": map[string]interface{}{
+			"allowed_domains": d.defaults.LinkPreviews.AllowedDomains,
+			"blocked_domains": d.defaults.LinkPreviews.BlockedDomains,
+		},
+		"gc": map[string]interface{}{
+			"interval":       d.defaults.GC.Interval.String(),
+			"attachment_ttl": d.defaults.GC.AttachmentTTL.String(),
+		},
+		"backup": map[string]interface{}{
+			"interval": d.defaults.Backup.Interval.String(),
+			"dir":      d.defaults.Backup.Dir,
+			"keep":     d.defaults.Backup.Keep,
 		},
 		"telemetry": map[string]interface{}{
 			"enabled":           d.defaults.Telemetry.Enabled,
@@ -235,11 +328,15 @@ func SetupFlags() *pflag.FlagSet {
 	flags.String("server.public_url", "", "Public URL")
 	flags.String("database.path", "", "Database path")
 	flags.Duration("auth.session_duration", 0, "Session duration")
+	flags.Duration("auth.access_token_ttl", 0, "Access token lifetime (0 = session duration)")
 	flags.String("storage.type", "", "Storage type: off, local, or s3")
 	flags.String("storage.local.path", "", "Local storage path")
 	flags.Int64("storage.max_upload_size", 0, "Max upload size in bytes")
+	flags.Int64("storage.workspace_quota", 0, "Max attachment bytes per workspace (0 = unlimited)")
 	flags.Bool("email.enabled", false, "Enable email sending")
 	flags.StringSlice("server.allowed_origins", nil, "Allowed CORS origins")
+	flags.String("server.api_docs_spec", "", "OpenAPI spec to serve with Swagger UI at /api/docs")
+	flags.Bool("server.serve_client", true, "Serve the embedded web client (false for API-only deployments)")
 	flags.String("server.tls.mode", "", "TLS mode: off, auto, or manual")
 	flags.String("server.tls.cert_file", "", "TLS certificate file (manual mode)")
 	flags.String("server.tls.key_file", "", "TLS key file (manual mode)")
@@ -252,9 +349,20 @@ func SetupFlags() *pflag.FlagSet {
 	flags.Bool("telemetry.insecure", false, "Use plaintext (no TLS) for OTLP export")
 	flags.Float64("telemetry.sample_rate", 0, "Trace sample rate (0.0 to 1.0)")
 	flags.String("telemetry.service_name", "", "Service name for telemetry")
+	flags.SetNormalizeFunc(flagAliases)
 	return flags
 }
 
 func ParseDuration(s string) (time.Duration, error) {
 	return time.ParseDuration(s)
 }
+
+// flagAliases maps shorthand flag names onto their config keys, so
+// --serve-client=false sets server.serve_client.
+func flagAliases(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "serve-client":
+		name = "server.serve_client"
+	}
+	return pflag.NormalizedName(name)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -105,6 +106,23 @@ func TestLoad_EnvUnderscoreInLeafKey(t *testing.T) {
 	}
 }
 
+func TestLoad_EnvDomainList(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "nonexistent.yaml")
+
+	t.Setenv("ENZYME_LINK_PREVIEWS_BLOCKED_DOMAINS", "tracker.io,ads.example.com")
+
+	cfg, err := Load(cfgPath, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	got := cfg.LinkPreviews.BlockedDomains
+	if len(got) != 2 || got[0] != "tracker.io" || got[1] != "ads.example.com" {
+		t.Fatalf("expected blocked_domains [tracker.io ads.example.com], got %v", got)
+	}
+}
+
 func TestLoad_EnvDeepNestedUnderscore(t *testing.T) {
 	dir := t.TempDir()
 	cfgPath := filepath.Join(dir, "nonexistent.yaml")
@@ -193,6 +211,60 @@ func TestLoad_TLSFromFlags(t *testing.T) {
 	}
 }
 
+func TestLoad_ServeClient(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "nonexistent.yaml")
+
+	cfg, err := Load(cfgPath, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Server.ServeClient {
+		t.Fatal("expected the web client to be served by default")
+	}
+
+	for _, arg := range []string{"--serve-client=false", "--server.serve_client=false"} {
+		flags := SetupFlags()
+		if err := flags.Parse([]string{arg}); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(cfgPath, flags)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Server.ServeClient {
+			t.Fatalf("expected %s to disable the web client", arg)
+		}
+	}
+}
+
+func TestLoad_Features(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("features:\n  threads_v2: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ENZYME_FEATURES_CALLS", "false")
+
+	cfg, err := Load(cfgPath, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if calls, ok := cfg.Features["calls"]; !ok || calls {
+		t.Fatalf("expected the env var to turn calls off, got %v", cfg.Features)
+	}
+	if !cfg.Features["threads_v2"] {
+		t.Fatalf("expected threads_v2 from the file, got %v", cfg.Features)
+	}
+
+	if err := os.WriteFile(cfgPath, []byte("features:\n  thread_v2: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(cfgPath, nil); err == nil || !strings.Contains(err.Error(), "thread_v2") {
+		t.Fatalf("expected an unknown flag to fail validation, got %v", err)
+	}
+}
+
 func TestLoad_TLSYAMLOverridesDefaults(t *testing.T) {
 	dir := t.TempDir()
 	cfgPath := filepath.Join(dir, "config.yaml")
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is a setting whose value differs between two configs. Values of
+// secrets are redacted.
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// ReloadResult describes a config reload: the settings that were applied,
+// and the keys that changed on disk but only take effect after a restart.
+type ReloadResult struct {
+	Changed         []Change `json:"changed"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// CopyReloadable copies the settings that can change while the server runs
+// from src into dst. Everything else in dst is left alone.
+func CopyReloadable(dst, src *Config) {
+	dst.Log.Level = src.Log.Level
+	dst.Server.AllowedOrigins = src.Server.AllowedOrigins
+	dst.Email = src.Email
+	enabled := dst.RateLimit.Enabled // turning limiting on or off needs a restart
+	dst.RateLimit = src.RateLimit
+	dst.RateLimit.Enabled = enabled
+	dst.Maintenance = src.Maintenance
+	dst.LinkPreviews = src.LinkPreviews
+	dst.Messages.SearchOffset = src.Messages.SearchOffset
+	dst.Features = src.Features
+}
+
+// Reload works out what applying loaded to the running config would do. It
+// returns the config to run with next, which takes the reloadable settings
+// from loaded, and a summary of the change.
+func Reload(running, loaded *Config) (*Config, ReloadResult) {
+	next := *running
+	CopyReloadable(&next, loaded)
+	result := ReloadResult{
+		Changed:         Diff(running, &next),
+		RestartRequired: []string{},
+	}
+	for _, c := range Diff(&next, loaded) {
+		result.RestartRequired = append(result.RestartRequired, c.Key)
+	}
+	return &next, result
+}
+
+// secretKeys are the leaf keys whose values are never shown in a Change.
+var secretKeys = []string{"password", "signing_secret", "access_key", "secret_key", "admin_token", "redis_url", "headers"}
+
+// Diff lists the settings that differ between a and b, keyed like the config
+// file (e.g. "rate_limit.login.limit") and in key order.
+func Diff(a, b *Config) []Change {
+	oldValues := flatten(reflect.ValueOf(*a), "", map[string]string{})
+	newValues := flatten(reflect.ValueOf(*b), "", map[string]string{})
+
+	changes := []Change{}
+	for key, oldValue := range oldValues {
+		newValue := newValues[key]
+		if oldValue == newValue {
+			continue
+		}
+		if slices.Contains(secretKeys, key[strings.LastIndex(key, ".")+1:]) {
+			oldValue, newValue = "[redacted]", "[redacted]"
+		}
+		changes = append(changes, Change{Key: key, Old: oldValue, New: newValue})
+	}
+	slices.SortFunc(changes, func(x, y Change) int { return strings.Compare(x.Key, y.Key) })
+	return changes
+}
+
+// flatten records the value of every leaf field of v under its dotted koanf
+// key.
+func flatten(v reflect.Value, prefix string, out map[string]string) map[string]string {
+	t := v.Type()
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("koanf")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			flatten(field, key, out)
+			continue
+		}
+		out[key] = fmt.Sprint(field.Interface())
+	}
+	return out
+}
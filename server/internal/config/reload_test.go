@@ -0,0 +1,47 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	running := Defaults()
+	loaded := Defaults()
+	loaded.Log.Level = "debug"
+	loaded.RateLimit.Login.Limit = 3
+	loaded.RateLimit.Enabled = !running.RateLimit.Enabled
+	loaded.Email.Password = "hunter2"
+	loaded.Server.Port = 9090
+
+	next, result := Reload(running, loaded)
+
+	if next.Log.Level != "debug" || next.RateLimit.Login.Limit != 3 || next.Email.Password != "hunter2" {
+		t.Errorf("expected the reloadable settings to be applied, got %+v", next)
+	}
+	if next.Server.Port != running.Server.Port || next.RateLimit.Enabled != running.RateLimit.Enabled {
+		t.Error("expected settings that need a restart to keep their running values")
+	}
+	if running.Log.Level != "info" {
+		t.Error("Reload must not modify the running config")
+	}
+
+	want := []Change{
+		{Key: "email.password", Old: "[redacted]", New: "[redacted]"},
+		{Key: "log.level", Old: "info", New: "debug"},
+		{Key: "rate_limit.login.limit", Old: "10", New: "3"},
+	}
+	if !slices.Equal(result.Changed, want) {
+		t.Errorf("Changed = %+v, want %+v", result.Changed, want)
+	}
+	if !slices.Equal(result.RestartRequired, []string{"rate_limit.enabled", "server.port"}) {
+		t.Errorf("RestartRequired = %v", result.RestartRequired)
+	}
+}
+
+func TestReload_NoChanges(t *testing.T) {
+	_, result := Reload(Defaults(), Defaults())
+	if len(result.Changed) != 0 || len(result.RestartRequired) != 0 {
+		t.Errorf("expected no changes, got %+v", result)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/url"
 	"time"
+
+	"github.com/enzyme/server/internal/features"
 )
 
 func Validate(cfg *Config) error {
@@ -74,6 +76,9 @@ func Validate(cfg *Config) error {
 	if cfg.Server.IdleTimeout < time.Second {
 		errs = append(errs, fmt.Errorf("server.idle_timeout must be at least 1s"))
 	}
+	if cfg.Server.CompressionLevel < 0 || cfg.Server.CompressionLevel > 9 {
+		errs = append(errs, fmt.Errorf("server.compression_level must be between 0 and 9"))
+	}
 
 	// Database validation
 	if cfg.Database.Path == "" {
@@ -88,11 +93,17 @@ func Validate(cfg *Config) error {
 	if cfg.Database.MmapSize < 0 {
 		errs = append(errs, fmt.Errorf("database.mmap_size must be at least 0"))
 	}
+	if cfg.Database.SlowQueryThreshold < 0 {
+		errs = append(errs, fmt.Errorf("database.slow_query_threshold must be at least 0"))
+	}
 
 	// Auth validation
 	if cfg.Auth.SessionDuration < time.Hour {
 		errs = append(errs, fmt.Errorf("auth.session_duration must be at least 1 hour"))
 	}
+	if cfg.Auth.AccessTokenTTL != 0 && (cfg.Auth.AccessTokenTTL < time.Minute || cfg.Auth.AccessTokenTTL > cfg.Auth.SessionDuration) {
+		errs = append(errs, fmt.Errorf("auth.access_token_ttl must be 0 or between 1 minute and auth.session_duration"))
+	}
 	if cfg.Auth.BcryptCost < 10 || cfg.Auth.BcryptCost > 31 {
 		errs = append(errs, fmt.Errorf("auth.bcrypt_cost must be between 10 and 31"))
 	}
@@ -124,6 +135,21 @@ func Validate(cfg *Config) error {
 	if cfg.Storage.Type != "off" && cfg.Storage.MaxUploadSize < 1024 {
 		errs = append(errs, fmt.Errorf("storage.max_upload_size must be at least 1KB"))
 	}
+	if cfg.Storage.Type != "off" && cfg.Storage.UploadSessionTTL < time.Minute {
+		errs = append(errs, fmt.Errorf("storage.upload_session_ttl must be at least 1m"))
+	}
+	if cfg.Storage.WorkspaceQuota < 0 {
+		errs = append(errs, fmt.Errorf("storage.workspace_quota must not be negative"))
+	}
+	if addr := cfg.Storage.ClamAV.Address; addr != "" {
+		u, err := url.Parse(addr)
+		if err != nil || !(u.Scheme == "unix" && u.Path != "" || u.Scheme == "tcp" && u.Host != "") {
+			errs = append(errs, fmt.Errorf("storage.clamav.address must be unix:///path/to/clamd.sock or tcp://host:port"))
+		}
+		if cfg.Storage.ClamAV.Timeout < time.Second {
+			errs = append(errs, fmt.Errorf("storage.clamav.timeout must be at least 1s"))
+		}
+	}
 
 	// Email validation (only if enabled)
 	if cfg.Email.Enabled {
@@ -149,6 +175,11 @@ func Validate(cfg *Config) error {
 			{"rate_limit.forgot_password", cfg.RateLimit.ForgotPassword},
 			{"rate_limit.reset_password", cfg.RateLimit.ResetPassword},
 			{"rate_limit.device_token_register", cfg.RateLimit.DeviceTokenRegister},
+			{"rate_limit.incoming_webhook", cfg.RateLimit.IncomingWebhook},
+			{"rate_limit.open_signup", cfg.RateLimit.OpenSignup},
+			{"rate_limit.auth", cfg.RateLimit.Auth},
+			{"rate_limit.send_message", cfg.RateLimit.SendMessage},
+			{"rate_limit.api", cfg.RateLimit.API},
 		} {
 			if ep.cfg.Limit < 1 {
 				errs = append(errs, fmt.Errorf("%s.limit must be at least 1", ep.name))
@@ -159,6 +190,20 @@ func Validate(cfg *Config) error {
 		}
 	}
 
+	// Notification queue validation
+	if cfg.Notifications.Workers < 1 {
+		errs = append(errs, fmt.Errorf("notifications.workers must be at least 1"))
+	}
+	if cfg.Notifications.QueueSize < 1 {
+		errs = append(errs, fmt.Errorf("notifications.queue_size must be at least 1"))
+	}
+	if cfg.Notifications.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("notifications.max_attempts must be at least 1"))
+	}
+	if cfg.Notifications.RetryBackoff < 100*time.Millisecond {
+		errs = append(errs, fmt.Errorf("notifications.retry_backoff must be at least 100ms"))
+	}
+
 	// Push notification validation (only when enabled)
 	if cfg.PushNotifications.Enabled {
 		if cfg.PushNotifications.RelayURL == "" {
@@ -180,6 +225,64 @@ func Validate(cfg *Config) error {
 	if cfg.SSE.ClientBufferSize < 16 {
 		errs = append(errs, fmt.Errorf("sse.client_buffer_size must be at least 16"))
 	}
+	if cfg.SSE.OverflowPolicy != "drop_oldest" && cfg.SSE.OverflowPolicy != "disconnect" {
+		errs = append(errs, fmt.Errorf("sse.overflow_policy must be drop_oldest or disconnect"))
+	}
+	if cfg.SSE.WriteTimeout < time.Second {
+		errs = append(errs, fmt.Errorf("sse.write_timeout must be at least 1s"))
+	}
+	switch cfg.SSE.Broadcast.Backend {
+	case "memory":
+		// no validation needed
+	case "redis":
+		if cfg.SSE.Broadcast.RedisURL == "" {
+			errs = append(errs, fmt.Errorf("sse.broadcast.redis_url is required when sse.broadcast.backend is redis"))
+		}
+		if cfg.SSE.Broadcast.Channel == "" {
+			errs = append(errs, fmt.Errorf("sse.broadcast.channel is required when sse.broadcast.backend is redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("sse.broadcast.backend must be memory or redis"))
+	}
+
+	// Messages validation
+	if cfg.Messages.ThreadParticipantPreview < 1 || cfg.Messages.ThreadParticipantPreview > 20 {
+		errs = append(errs, fmt.Errorf("messages.thread_participant_preview must be between 1 and 20"))
+	}
+	if cfg.Messages.UndeleteWindow < 0 {
+		errs = append(errs, fmt.Errorf("messages.undelete_window must not be negative"))
+	}
+	if cfg.Messages.MaxLength < 1 || cfg.Messages.MaxLength > 1000000 {
+		errs = append(errs, fmt.Errorf("messages.max_length must be between 1 and 1000000"))
+	}
+	if cfg.Messages.ListContentLength < 0 {
+		errs = append(errs, fmt.Errorf("messages.list_content_length must not be negative"))
+	}
+	if cfg.Messages.ColdStorageDays < 0 {
+		errs = append(errs, fmt.Errorf("messages.cold_storage_days must not be negative"))
+	}
+
+	// Garbage collection validation
+	if cfg.GC.Interval < 0 {
+		errs = append(errs, fmt.Errorf("gc.interval must not be negative"))
+	} else if cfg.GC.Interval > 0 && cfg.GC.Interval < time.Minute {
+		errs = append(errs, fmt.Errorf("gc.interval must be at least 1m (or 0 to disable)"))
+	}
+	if cfg.GC.AttachmentTTL < time.Hour {
+		errs = append(errs, fmt.Errorf("gc.attachment_ttl must be at least 1h"))
+	}
+
+	if cfg.Backup.Interval < 0 {
+		errs = append(errs, fmt.Errorf("backup.interval must not be negative"))
+	} else if cfg.Backup.Interval > 0 && cfg.Backup.Interval < time.Hour {
+		errs = append(errs, fmt.Errorf("backup.interval must be at least 1h (or 0 to disable)"))
+	}
+	if cfg.Backup.Interval > 0 && cfg.Backup.Dir == "" {
+		errs = append(errs, fmt.Errorf("backup.dir is required when backup.interval is set"))
+	}
+	if cfg.Backup.Keep < 0 {
+		errs = append(errs, fmt.Errorf("backup.keep must not be negative"))
+	}
 
 	// Telemetry validation (only when enabled)
 	if cfg.Telemetry.Enabled {
@@ -197,6 +300,10 @@ func Validate(cfg *Config) error {
 		}
 	}
 
+	if err := features.Validate(cfg.Features); err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -335,3 +335,225 @@ func TestValidate_RateLimitMultipleErrors(t *testing.T) {
 		t.Fatalf("expected forgot_password window error, got: %v", err)
 	}
 }
+
+func TestValidate_ThreadParticipantPreviewOutOfRange(t *testing.T) {
+	for _, n := range []int{0, 21} {
+		cfg := validConfig()
+		cfg.Messages.ThreadParticipantPreview = n
+		err := Validate(cfg)
+		if err == nil {
+			t.Fatalf("expected error for thread_participant_preview %d", n)
+		}
+		if !strings.Contains(err.Error(), "messages.thread_participant_preview") {
+			t.Fatalf("expected error about messages.thread_participant_preview, got: %v", err)
+		}
+	}
+}
+
+func TestValidate_UndeleteWindow(t *testing.T) {
+	cfg := validConfig()
+	cfg.Messages.UndeleteWindow = 0
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected messages.undelete_window 0 to disable undelete, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Messages.UndeleteWindow = -time.Minute
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "messages.undelete_window") {
+		t.Fatalf("expected error about messages.undelete_window, got: %v", err)
+	}
+}
+
+func TestValidate_MessageLimits(t *testing.T) {
+	cfg := validConfig()
+	cfg.Messages.MaxLength = 0
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "messages.max_length") {
+		t.Fatalf("expected error about messages.max_length, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Messages.ListContentLength = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "messages.list_content_length") {
+		t.Fatalf("expected error about messages.list_content_length, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Messages.ColdStorageDays = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "messages.cold_storage_days") {
+		t.Fatalf("expected error about messages.cold_storage_days, got: %v", err)
+	}
+}
+
+func TestValidate_ClamAV(t *testing.T) {
+	for _, addr := range []string{"unix:///var/run/clamav/clamd.ctl", "tcp://127.0.0.1:3310"} {
+		cfg := validConfig()
+		cfg.Storage.ClamAV.Address = addr
+		if err := Validate(cfg); err != nil {
+			t.Errorf("expected storage.clamav.address %q to be valid, got: %v", addr, err)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.Storage.ClamAV.Address = "localhost:3310"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "storage.clamav.address") {
+		t.Fatalf("expected error about storage.clamav.address, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Storage.ClamAV.Address = "tcp://127.0.0.1:3310"
+	cfg.Storage.ClamAV.Timeout = 0
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "storage.clamav.timeout") {
+		t.Fatalf("expected error about storage.clamav.timeout, got: %v", err)
+	}
+}
+
+func TestValidate_WorkspaceQuota(t *testing.T) {
+	cfg := validConfig()
+	cfg.Storage.WorkspaceQuota = 0
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected storage.workspace_quota 0 to mean unlimited, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Storage.WorkspaceQuota = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "storage.workspace_quota") {
+		t.Fatalf("expected error about storage.workspace_quota, got: %v", err)
+	}
+}
+
+func TestValidate_CompressionLevel(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.CompressionLevel = 0
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected server.compression_level 0 to disable compression, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Server.CompressionLevel = 10
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "server.compression_level") {
+		t.Fatalf("expected error about server.compression_level, got: %v", err)
+	}
+}
+
+func TestValidate_AccessTokenTTL(t *testing.T) {
+	cfg := validConfig()
+	cfg.Auth.AccessTokenTTL = 15 * time.Minute
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected auth.access_token_ttl 15m to pass, got: %v", err)
+	}
+
+	for _, ttl := range []time.Duration{-time.Minute, time.Second, cfg.Auth.SessionDuration + time.Hour} {
+		cfg = validConfig()
+		cfg.Auth.AccessTokenTTL = ttl
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "auth.access_token_ttl") {
+			t.Fatalf("expected error about auth.access_token_ttl for %v, got: %v", ttl, err)
+		}
+	}
+}
+
+func TestValidate_SSEBroadcast(t *testing.T) {
+	cfg := validConfig()
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected the default memory backend to pass, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.SSE.Broadcast.Backend = "redis"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "sse.broadcast.redis_url") {
+		t.Fatalf("expected error about sse.broadcast.redis_url, got: %v", err)
+	}
+	cfg.SSE.Broadcast.RedisURL = "redis://localhost:6379/0"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected redis backend with a URL to pass, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.SSE.Broadcast.Backend = "nats"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "sse.broadcast.backend") {
+		t.Fatalf("expected error about sse.broadcast.backend, got: %v", err)
+	}
+}
+
+func TestValidate_SSEBackpressure(t *testing.T) {
+	cfg := validConfig()
+	cfg.SSE.OverflowPolicy = "disconnect"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected the disconnect policy to pass, got: %v", err)
+	}
+
+	cfg.SSE.OverflowPolicy = "block"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "sse.overflow_policy") {
+		t.Fatalf("expected error about sse.overflow_policy, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.SSE.WriteTimeout = 0
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "sse.write_timeout") {
+		t.Fatalf("expected error about sse.write_timeout, got: %v", err)
+	}
+}
+
+func TestValidate_Notifications(t *testing.T) {
+	for _, tc := range []struct {
+		key    string
+		modify func(*Config)
+	}{
+		{"notifications.workers", func(c *Config) { c.Notifications.Workers = 0 }},
+		{"notifications.queue_size", func(c *Config) { c.Notifications.QueueSize = 0 }},
+		{"notifications.max_attempts", func(c *Config) { c.Notifications.MaxAttempts = 0 }},
+		{"notifications.retry_backoff", func(c *Config) { c.Notifications.RetryBackoff = 0 }},
+	} {
+		cfg := validConfig()
+		tc.modify(cfg)
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), tc.key) {
+			t.Errorf("expected error about %s, got: %v", tc.key, err)
+		}
+	}
+}
+
+func TestValidate_GC(t *testing.T) {
+	cfg := validConfig()
+	cfg.GC.Interval = 0
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected gc.interval 0 to disable collection, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.GC.Interval = 30 * time.Second
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "gc.interval") {
+		t.Fatalf("expected error about gc.interval, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.GC.AttachmentTTL = time.Minute
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "gc.attachment_ttl") {
+		t.Fatalf("expected error about gc.attachment_ttl, got: %v", err)
+	}
+}
+
+func TestValidate_Backup(t *testing.T) {
+	cfg := validConfig()
+	cfg.Backup.Interval = 24 * time.Hour
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("expected daily backups to pass, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Backup.Interval = 10 * time.Minute
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "backup.interval") {
+		t.Fatalf("expected error about backup.interval, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Backup.Interval = 24 * time.Hour
+	cfg.Backup.Dir = ""
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "backup.dir") {
+		t.Fatalf("expected error about backup.dir, got: %v", err)
+	}
+
+	cfg = validConfig()
+	cfg.Backup.Keep = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "backup.keep") {
+		t.Fatalf("expected error about backup.keep, got: %v", err)
+	}
+}
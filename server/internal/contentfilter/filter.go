@@ -0,0 +1,178 @@
+package contentfilter
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// cacheTTL bounds how long compiled rules are reused. Changes made through
+// the API invalidate the cache straight away; the TTL only matters for edits
+// made behind the server's back.
+const cacheTTL = 5 * time.Minute
+
+type matcher struct {
+	ruleID string
+	action string
+	re     *regexp.Regexp
+	// word rules only match whole words
+	word bool
+}
+
+type cacheEntry struct {
+	matchers []matcher
+	loadedAt time.Time
+}
+
+// Filter checks message content against a workspace's blocklist. Compiled
+// rules are cached per workspace.
+type Filter struct {
+	repo *Repository
+
+	mu    sync.RWMutex
+	cache map[string]*cacheEntry
+}
+
+// NewFilter creates a filter backed by the given repository
+func NewFilter(repo *Repository) *Filter {
+	return &Filter{
+		repo:  repo,
+		cache: make(map[string]*cacheEntry),
+	}
+}
+
+// Compile validates a pattern and returns the expression used to match it.
+// Plain words are matched literally; both kinds ignore case.
+func Compile(pattern string, isRegex bool) (*regexp.Regexp, error) {
+	if !isRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, ErrInvalidPattern
+	}
+	return re, nil
+}
+
+// Invalidate drops the cached rules of a workspace. Call after its rules change.
+func (f *Filter) Invalidate(workspaceID string) {
+	f.mu.Lock()
+	delete(f.cache, workspaceID)
+	f.mu.Unlock()
+}
+
+// Check matches content against the workspace's rules. A reject match sets
+// Blocked; mask matches are replaced with asterisks in Content.
+func (f *Filter) Check(ctx context.Context, workspaceID, content string) (Result, error) {
+	result := Result{Content: content}
+	if content == "" {
+		return result, nil
+	}
+
+	matchers, err := f.matchers(ctx, workspaceID)
+	if err != nil {
+		return result, err
+	}
+
+	var masks [][]int
+	for _, m := range matchers {
+		var spans [][]int
+		for _, span := range m.re.FindAllStringIndex(content, -1) {
+			if span[0] == span[1] || (m.word && !isWordBounded(content, span)) {
+				continue
+			}
+			spans = append(spans, span)
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		result.RuleIDs = append(result.RuleIDs, m.ruleID)
+		if m.action == ActionReject {
+			result.Blocked = true
+		} else {
+			masks = append(masks, spans...)
+		}
+	}
+
+	if !result.Blocked && len(masks) > 0 {
+		result.Content = mask(content, masks)
+	}
+	return result, nil
+}
+
+func (f *Filter) matchers(ctx context.Context, workspaceID string) ([]matcher, error) {
+	f.mu.RLock()
+	entry := f.cache[workspaceID]
+	f.mu.RUnlock()
+	if entry != nil && time.Since(entry.loadedAt) < cacheTTL {
+		return entry.matchers, nil
+	}
+
+	rules, err := f.repo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	matchers := make([]matcher, 0, len(rules))
+	for _, rule := range rules {
+		re, err := Compile(rule.Pattern, rule.IsRegex)
+		if err != nil {
+			slog.Warn("skipping invalid blocked word pattern", "component", "contentfilter", "rule_id", rule.ID, "error", err)
+			continue
+		}
+		matchers = append(matchers, matcher{ruleID: rule.ID, action: rule.Action, re: re, word: !rule.IsRegex})
+	}
+
+	f.mu.Lock()
+	f.cache[workspaceID] = &cacheEntry{matchers: matchers, loadedAt: time.Now()}
+	f.mu.Unlock()
+	return matchers, nil
+}
+
+// isWordBounded reports whether the match is not part of a longer word, so
+// "ass" does not match "class". Unlike \b this also works for non-ASCII words.
+func isWordBounded(s string, span []int) bool {
+	if span[0] > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(s[:span[0]]); isWordRune(r) {
+			return false
+		}
+	}
+	if span[1] < len(s) {
+		if r, _ := utf8.DecodeRuneInString(s[span[1]:]); isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// mask replaces every rune inside the given byte spans with an asterisk.
+// Spans may overlap.
+func mask(s string, spans [][]int) string {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var b strings.Builder
+	pos := 0
+	for _, span := range spans {
+		start, end := span[0], span[1]
+		if end <= pos {
+			continue
+		}
+		if start < pos {
+			start = pos
+		}
+		b.WriteString(s[pos:start])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(s[start:end])))
+		pos = end
+	}
+	b.WriteString(s[pos:])
+	return b.String()
+}
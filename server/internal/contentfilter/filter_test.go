@@ -0,0 +1,104 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestFilter_Check(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	f := NewFilter(repo)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+
+	add := func(pattern string, isRegex bool, action string) *Rule {
+		t.Helper()
+		rule := &Rule{WorkspaceID: ws.ID, Pattern: pattern, IsRegex: isRegex, Action: action, CreatedBy: &owner.ID}
+		if err := repo.Create(ctx, rule); err != nil {
+			t.Fatalf("Create(%q): %v", pattern, err)
+		}
+		return rule
+	}
+	darn := add("darn", false, ActionMask)
+	add("café", false, ActionMask)
+	secret := add(`project\s+x\d+`, true, ActionReject)
+
+	tests := []struct {
+		content string
+		blocked bool
+		want    string
+	}{
+		{"well DARN it, darn", false, "well **** it, ****"},
+		{"darning socks", false, "darning socks"},
+		{"meet at the Café.", false, "meet at the ****."},
+		{"cafés are open", false, "cafés are open"},
+		{"the PROJECT  X42 launch", true, "the PROJECT  X42 launch"},
+		{"nothing to see", false, "nothing to see"},
+	}
+	for _, tt := range tests {
+		result, err := f.Check(ctx, ws.ID, tt.content)
+		if err != nil {
+			t.Fatalf("Check(%q): %v", tt.content, err)
+		}
+		if result.Blocked != tt.blocked || result.Content != tt.want {
+			t.Errorf("Check(%q) = blocked %v, %q; want blocked %v, %q", tt.content, result.Blocked, result.Content, tt.blocked, tt.want)
+		}
+	}
+
+	result, _ := f.Check(ctx, ws.ID, "darn, project x1")
+	if !result.Blocked || !slices.Contains(result.RuleIDs, darn.ID) || !slices.Contains(result.RuleIDs, secret.ID) {
+		t.Errorf("expected both rules to match, got %+v", result)
+	}
+
+	// Cached rules are reloaded after Invalidate
+	if err := repo.Delete(ctx, secret.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if result, _ := f.Check(ctx, ws.ID, "project x1"); !result.Blocked {
+		t.Error("expected the cached rules to be used until invalidated")
+	}
+	f.Invalidate(ws.ID)
+	if result, _ := f.Check(ctx, ws.ID, "project x1"); result.Blocked {
+		t.Error("expected the deleted rule to stop matching after Invalidate")
+	}
+
+	// Other workspaces are unaffected
+	other := testutil.CreateTestWorkspace(t, db, owner.ID, "Other")
+	if result, _ := f.Check(ctx, other.ID, "darn"); result.Content != "darn" {
+		t.Errorf("expected rules to be scoped to their workspace, got %q", result.Content)
+	}
+}
+
+func TestRepository_Create(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+
+	if err := repo.Create(ctx, &Rule{WorkspaceID: ws.ID, Pattern: "darn", Action: ActionReject}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	err := repo.Create(ctx, &Rule{WorkspaceID: ws.ID, Pattern: "darn", Action: ActionMask})
+	if !errors.Is(err, ErrRuleExists) {
+		t.Errorf("expected ErrRuleExists, got %v", err)
+	}
+	if err := repo.Create(ctx, &Rule{WorkspaceID: ws.ID, Pattern: "darn", IsRegex: true, Action: ActionMask}); err != nil {
+		t.Errorf("expected the same text as a regex to be allowed, got %v", err)
+	}
+
+	if _, err := Compile("(unclosed", true); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("expected ErrInvalidPattern, got %v", err)
+	}
+	if _, err := Compile("(unclosed", false); err != nil {
+		t.Errorf("expected plain words to be quoted, got %v", err)
+	}
+}
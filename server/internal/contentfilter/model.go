@@ -0,0 +1,54 @@
+package contentfilter
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrRuleNotFound   = errors.New("blocked word not found")
+	ErrRuleExists     = errors.New("this pattern is already blocked")
+	ErrTooManyRules   = errors.New("too many blocked words")
+	ErrInvalidPattern = errors.New("invalid pattern")
+)
+
+const (
+	// ActionReject refuses a message that matches the rule
+	ActionReject = "reject"
+	// ActionMask replaces each match with asterisks and keeps the message
+	ActionMask = "mask"
+)
+
+const (
+	MaxPatternLength     = 200
+	MaxRulesPerWorkspace = 500
+)
+
+// IsValidAction reports whether action is a known rule action
+func IsValidAction(action string) bool {
+	return action == ActionReject || action == ActionMask
+}
+
+// Rule is one entry in a workspace's blocklist. A plain pattern matches the
+// word case-insensitively on word boundaries; a regex pattern is matched
+// case-insensitively anywhere in the message.
+type Rule struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Pattern     string    `json:"pattern"`
+	IsRegex     bool      `json:"is_regex"`
+	Action      string    `json:"action"`
+	CreatedBy   *string   `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Result is the outcome of checking a message against a workspace's rules
+type Result struct {
+	// Blocked is set when a reject rule matched; the message must not be saved
+	Blocked bool
+	// Content is the message with mask matches replaced. Equal to the input
+	// when nothing was masked.
+	Content string
+	// RuleIDs lists the rules that matched
+	RuleIDs []string
+}
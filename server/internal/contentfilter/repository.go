@@ -0,0 +1,106 @@
+package contentfilter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const ruleColumns = `id, workspace_id, pattern, is_regex, action, created_by, created_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// List returns a workspace's rules, oldest first
+func (r *Repository) List(ctx context.Context, workspaceID string) ([]Rule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+ruleColumns+` FROM blocked_words WHERE workspace_id = ? ORDER BY created_at, id
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*Rule, error) {
+	rule, err := scanRule(r.db.QueryRowContext(ctx, `
+		SELECT `+ruleColumns+` FROM blocked_words WHERE id = ?
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRuleNotFound
+	}
+	return rule, err
+}
+
+// Create adds a rule. Returns ErrRuleExists for a duplicate pattern and
+// ErrTooManyRules once the workspace has MaxRulesPerWorkspace rules.
+func (r *Repository) Create(ctx context.Context, rule *Rule) error {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM blocked_words WHERE workspace_id = ?
+	`, rule.WorkspaceID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= MaxRulesPerWorkspace {
+		return ErrTooManyRules
+	}
+
+	rule.ID = ulid.Make().String()
+	rule.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO blocked_words (id, workspace_id, pattern, is_regex, action, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, rule.ID, rule.WorkspaceID, rule.Pattern, rule.IsRegex, rule.Action, rule.CreatedBy, rule.CreatedAt.Format(time.RFC3339))
+	if isUniqueConstraintError(err) {
+		return ErrRuleExists
+	}
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM blocked_words WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func scanRule(row interface{ Scan(dest ...any) error }) (*Rule, error) {
+	var rule Rule
+	var createdBy sql.NullString
+	var createdAt string
+	if err := row.Scan(&rule.ID, &rule.WorkspaceID, &rule.Pattern, &rule.IsRegex, &rule.Action, &createdBy, &createdAt); err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		rule.CreatedBy = &createdBy.String
+	}
+	rule.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &rule, nil
+}
+
+func isUniqueConstraintError(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key"))
+}
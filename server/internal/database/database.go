@@ -21,6 +21,9 @@ type Options struct {
 	CacheSize        int   // negative = KB, positive = pages (default: -2000)
 	MmapSize         int64 // bytes, 0 = disabled (default: 0)
 	JournalSizeLimit int64 // bytes, caps WAL file size (default: 67108864 = 64MB)
+
+	// SlowQueryLog, if set, receives the timing of every statement.
+	SlowQueryLog *SlowQueryLog
 }
 
 func Open(path string, opts Options) (*DB, error) {
@@ -50,6 +53,15 @@ func Open(path string, opts Options) (*DB, error) {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
 
+	// sql.Open does not connect, so swapping in the instrumented connector
+	// here only costs the handle. Reuse the registered driver so any global
+	// driver configuration still applies.
+	if opts.SlowQueryLog != nil {
+		drv := db.Driver()
+		_ = db.Close()
+		db = sql.OpenDB(&instrumentedConnector{dsn: dsn, driver: drv, log: opts.SlowQueryLog})
+	}
+
 	db.SetMaxOpenConns(opts.MaxOpenConns)
 
 	// Verify WAL mode took effect. journal_mode returns a result rather than
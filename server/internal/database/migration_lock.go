@@ -0,0 +1,206 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// ErrMigrationLocked is returned when another process holds the migration
+// lock for longer than a migration run is willing to wait.
+var ErrMigrationLocked = errors.New("migrations are locked by another process")
+
+const (
+	// lockLease is how long a lock stays valid without a heartbeat. A
+	// process that dies while migrating blocks others for at most this long.
+	lockLease = 2 * time.Minute
+
+	lockHeartbeat = 30 * time.Second
+	lockPoll      = 500 * time.Millisecond
+
+	// lockTimeFormat is fixed width so expiry times compare correctly as
+	// strings in SQL.
+	lockTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// LockInfo describes the process holding the migration lock.
+type LockInfo struct {
+	Owner     string // host:pid of the holder
+	ExpiresAt time.Time
+}
+
+// migrationLock is a lease held in a single-row table, since SQLite has no
+// advisory locks. The same row records a migration in progress that cannot
+// be rolled back, so an interrupted one is noticed by the next run.
+type migrationLock struct {
+	db        *sql.DB
+	owner     string
+	lease     time.Duration
+	heartbeat time.Duration
+	wait      time.Duration
+}
+
+func newMigrationLock(db *sql.DB) *migrationLock {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return &migrationLock{
+		db:        db,
+		owner:     fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(b)),
+		lease:     lockLease,
+		heartbeat: lockHeartbeat,
+		// Wait long enough for the lease of a crashed holder to run out.
+		wait: lockLease + 10*time.Second,
+	}
+}
+
+func (l *migrationLock) ensureTable(ctx context.Context) error {
+	if _, err := l.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migration_state (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			lock_owner TEXT,
+			lock_expires_at TEXT,
+			dirty_version INTEGER,
+			dirty_direction TEXT
+		)
+	`); err != nil {
+		return fmt.Errorf("creating migration state table: %w", err)
+	}
+	_, err := l.db.ExecContext(ctx, `INSERT OR IGNORE INTO schema_migration_state (id) VALUES (1)`)
+	return err
+}
+
+// acquire takes the lock, waiting for another holder to finish or for its
+// lease to run out. The returned function releases it.
+func (l *migrationLock) acquire(ctx context.Context) (release func(), err error) {
+	deadline := time.Now().Add(l.wait)
+	for {
+		ok, err := l.tryAcquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			holder, _ := l.holder(ctx)
+			if holder != nil {
+				return nil, fmt.Errorf("%w (%s, until %s)", ErrMigrationLocked, holder.Owner, holder.ExpiresAt.Format(time.RFC3339))
+			}
+			return nil, ErrMigrationLocked
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPoll):
+		}
+	}
+
+	// Keep the lease alive for as long as the migrations take.
+	hbCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(l.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-hbCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := l.db.ExecContext(hbCtx, `
+					UPDATE schema_migration_state SET lock_expires_at = ? WHERE id = 1 AND lock_owner = ?
+				`, l.expiry(), l.owner); err != nil && hbCtx.Err() == nil {
+					slog.Warn("failed to renew migration lock", "error", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		stop()
+		<-done
+		if _, err := l.db.ExecContext(context.WithoutCancel(ctx), `
+			UPDATE schema_migration_state SET lock_owner = NULL, lock_expires_at = NULL
+			WHERE id = 1 AND lock_owner = ?
+		`, l.owner); err != nil {
+			slog.Warn("failed to release migration lock", "error", err)
+		}
+	}, nil
+}
+
+func (l *migrationLock) tryAcquire(ctx context.Context) (bool, error) {
+	res, err := l.db.ExecContext(ctx, `
+		UPDATE schema_migration_state SET lock_owner = ?, lock_expires_at = ?
+		WHERE id = 1 AND (lock_owner IS NULL OR lock_expires_at < ?)
+	`, l.owner, l.expiry(), time.Now().UTC().Format(lockTimeFormat))
+	if err != nil {
+		return false, fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// holder returns the current unexpired lock, or nil if the lock is free.
+func (l *migrationLock) holder(ctx context.Context) (*LockInfo, error) {
+	var owner, expires sql.NullString
+	if err := l.db.QueryRowContext(ctx, `
+		SELECT lock_owner, lock_expires_at FROM schema_migration_state WHERE id = 1
+	`).Scan(&owner, &expires); err != nil {
+		return nil, err
+	}
+	if !owner.Valid {
+		return nil, nil
+	}
+	expiresAt, _ := time.Parse(lockTimeFormat, expires.String)
+	if expiresAt.Before(time.Now()) {
+		return nil, nil
+	}
+	return &LockInfo{Owner: owner.String, ExpiresAt: expiresAt}, nil
+}
+
+// dirty returns the migration left unfinished, or nil if there is none.
+func (l *migrationLock) dirty(ctx context.Context) (*DirtyError, error) {
+	var version sql.NullInt64
+	var direction sql.NullString
+	if err := l.db.QueryRowContext(ctx, `
+		SELECT dirty_version, dirty_direction FROM schema_migration_state WHERE id = 1
+	`).Scan(&version, &direction); err != nil {
+		return nil, err
+	}
+	if !version.Valid {
+		return nil, nil
+	}
+	return &DirtyError{Version: version.Int64, Direction: direction.String}, nil
+}
+
+func (l *migrationLock) setDirty(ctx context.Context, version int64, direction string) error {
+	_, err := l.db.ExecContext(ctx, `
+		UPDATE schema_migration_state SET dirty_version = ?, dirty_direction = ? WHERE id = 1
+	`, version, direction)
+	return err
+}
+
+func (l *migrationLock) clearDirty(ctx context.Context) error {
+	_, err := l.db.ExecContext(ctx, `
+		UPDATE schema_migration_state SET dirty_version = NULL, dirty_direction = NULL WHERE id = 1
+	`)
+	return err
+}
+
+func (l *migrationLock) clearDirtyTx(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE schema_migration_state SET dirty_version = NULL, dirty_direction = NULL WHERE id = 1
+	`)
+	return err
+}
+
+func (l *migrationLock) expiry() string {
+	return time.Now().Add(l.lease).UTC().Format(lockTimeFormat)
+}
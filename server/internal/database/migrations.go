@@ -1,8 +1,18 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pressly/goose/v3"
 )
@@ -10,30 +20,430 @@ import (
 //go:embed migrations/*.sql
 var embedMigrations embed.FS
 
-func (db *DB) Migrate() error {
-	goose.SetBaseFS(embedMigrations)
+// ErrUnknownVersion is returned when asked to migrate to a version that no
+// migration built into this binary has.
+var ErrUnknownVersion = errors.New("unknown migration version")
 
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		return fmt.Errorf("setting dialect: %w", err)
-	}
+// DirtyError reports a migration that started but did not finish. Migrations
+// normally run in a transaction and roll back cleanly; only those marked
+// "+goose NO TRANSACTION" can leave the schema half changed, so only those
+// mark the database dirty. It must be repaired by hand and the version set
+// with Force before migrating again.
+type DirtyError struct {
+	Version   int64
+	Direction string // "up" or "down"
+}
 
-	if err := goose.Up(db.DB, "migrations"); err != nil {
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("database is dirty: migration %d did not finish running %s", e.Version, e.Direction)
+}
+
+// Migrate applies all pending migrations.
+func (db *DB) Migrate() error {
+	m, err := NewMigrator(db.DB)
+	if err != nil {
+		return err
+	}
+	if _, err := m.Up(context.Background()); err != nil {
 		return fmt.Errorf("running migrations: %w", err)
 	}
-
 	return nil
 }
 
+// MigrateDown rolls back the most recently applied migration.
 func (db *DB) MigrateDown() error {
-	goose.SetBaseFS(embedMigrations)
+	m, err := NewMigrator(db.DB)
+	if err != nil {
+		return err
+	}
+	if _, err := m.Down(context.Background()); err != nil {
+		return fmt.Errorf("running down migration: %w", err)
+	}
+	return nil
+}
+
+// Migration is one migration file built into the binary.
+type Migration struct {
+	Version int64
+	Name    string // file name, e.g. "001_create_users.sql"
+	UseTx   bool
+}
 
-	if err := goose.SetDialect("sqlite3"); err != nil {
-		return fmt.Errorf("setting dialect: %w", err)
+// MigrationStatus is a migration and whether it has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Status describes the schema of a database relative to this binary.
+type Status struct {
+	Current    int64 // newest applied version, 0 if none
+	Latest     int64 // newest version built into the binary
+	Migrations []MigrationStatus
+	// Unknown lists applied versions this binary has no migration for,
+	// typically because a newer release migrated the database.
+	Unknown []int64
+	Dirty   *DirtyError
+	Lock    *LockInfo
+}
+
+// Migrator applies and rolls back the migrations embedded in the binary.
+// Every operation that changes the schema holds the migration lock, so two
+// processes pointed at the same database never migrate it at once.
+type Migrator struct {
+	db         *sql.DB
+	provider   *goose.Provider
+	migrations []Migration
+	byVersion  map[int64]Migration
+	lock       *migrationLock
+}
+
+// NewMigrator creates a Migrator for db.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	fsys, err := fs.Sub(embedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, fsys, goose.WithDisableGlobalRegistry(true))
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
 	}
 
-	if err := goose.Down(db.DB, "migrations"); err != nil {
-		return fmt.Errorf("running down migration: %w", err)
+	m := &Migrator{
+		db:        db,
+		provider:  provider,
+		byVersion: make(map[int64]Migration),
+		lock:      newMigrationLock(db),
+	}
+	for _, src := range provider.ListSources() {
+		name := path.Base(src.Path)
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", name, err)
+		}
+		mig := Migration{
+			Version: src.Version,
+			Name:    name,
+			UseTx:   !strings.Contains(string(data), "+goose NO TRANSACTION"),
+		}
+		m.migrations = append(m.migrations, mig)
+		m.byVersion[mig.Version] = mig
+	}
+	return m, nil
+}
+
+// Status reports which migrations have been applied. It does not take the
+// migration lock, so it can be run while another process is migrating.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	if err := m.lock.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Status{}
+	if n := len(m.migrations); n > 0 {
+		st.Latest = m.migrations[n-1].Version
+	}
+	for _, mig := range m.migrations {
+		ms := MigrationStatus{Migration: mig}
+		if at, ok := applied[mig.Version]; ok {
+			ms.Applied = true
+			ms.AppliedAt = at
+		}
+		st.Migrations = append(st.Migrations, ms)
+	}
+	for v := range applied {
+		st.Current = max(st.Current, v)
+		if _, ok := m.byVersion[v]; !ok {
+			st.Unknown = append(st.Unknown, v)
+		}
+	}
+	slices.Sort(st.Unknown)
+
+	if st.Dirty, err = m.lock.dirty(ctx); err != nil {
+		return nil, err
+	}
+	if st.Lock, err = m.lock.holder(ctx); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Up applies all pending migrations and returns those it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.To(ctx, m.latest())
+}
+
+// Down rolls back the most recently applied migration and returns it, or
+// nil if nothing has been applied.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var rolledBack *Migration
+	err := m.withLock(ctx, func() error {
+		mig, err := m.newest(ctx)
+		if err != nil || mig == nil {
+			return err
+		}
+		if err := m.step(ctx, *mig, "down"); err != nil {
+			return err
+		}
+		rolledBack = mig
+		return nil
+	})
+	return rolledBack, err
+}
+
+// To migrates up or down until version is the newest applied migration and
+// returns the migrations it ran, in order. Version 0 rolls everything back.
+func (m *Migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	if _, ok := m.byVersion[version]; !ok && version != 0 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	var ran []Migration
+	err := m.withLock(ctx, func() error {
+		current, err := m.provider.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+		// Decide the direction once: migrating up never rolls anything
+		// back, even if a newer binary has applied migrations this one
+		// does not know about.
+		down := version < current
+		for {
+			mig, err := m.nextToward(ctx, version, down)
+			if err != nil || mig == nil {
+				return err
+			}
+			direction := "up"
+			if down {
+				direction = "down"
+			}
+			if err := m.step(ctx, *mig, direction); err != nil {
+				return err
+			}
+			ran = append(ran, *mig)
+		}
+	})
+	return ran, err
+}
+
+// Force records the database as being at version, marking every known
+// migration up to it applied and everything after it not applied, and
+// clears the dirty flag. It runs no SQL from the migrations themselves; it
+// is for recovering after the schema has been repaired by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if _, ok := m.byVersion[version]; !ok && version != 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLockAllowDirty(ctx, func() error {
+		// Make sure goose's version table exists before rewriting it.
+		if _, err := m.provider.GetDBVersion(ctx); err != nil {
+			return err
+		}
+		applied, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		tx, err := m.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM goose_db_version WHERE version_id > ?`, version); err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, 1)`, mig.Version); err != nil {
+				return err
+			}
+		}
+		if err := m.lock.clearDirtyTx(ctx, tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// step runs a single migration in direction.
+func (m *Migrator) step(ctx context.Context, mig Migration, direction string) error {
+	// A migration that runs in a transaction is rolled back if it fails or
+	// the process dies, so only the others can leave the schema dirty.
+	if !mig.UseTx {
+		if err := m.lock.setDirty(ctx, mig.Version, direction); err != nil {
+			return err
+		}
+	}
+
+	res, err := m.provider.ApplyVersion(ctx, mig.Version, direction == "up")
+	if err != nil {
+		return fmt.Errorf("migration %s %s: %w", mig.Name, direction, err)
 	}
 
+	if !mig.UseTx {
+		if err := m.lock.clearDirty(ctx); err != nil {
+			return err
+		}
+	}
+	slog.Info("applied migration", "version", mig.Version, "name", mig.Name, "direction", direction, "duration", res.Duration)
 	return nil
 }
+
+// nextToward returns the migration to run next to bring the database to
+// version, or nil once it is there.
+func (m *Migrator) nextToward(ctx context.Context, version int64, down bool) (*Migration, error) {
+	if down {
+		mig, err := m.newest(ctx)
+		if err != nil || mig == nil || mig.Version <= version {
+			return nil, err
+		}
+		return mig, nil
+	}
+
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		if _, ok := applied[mig.Version]; !ok {
+			return &mig, nil
+		}
+	}
+	return nil, nil
+}
+
+// newest returns the most recently applied migration, or nil if none is.
+func (m *Migrator) newest(ctx context.Context) (*Migration, error) {
+	current, err := m.provider.GetDBVersion(ctx)
+	if err != nil || current == 0 {
+		return nil, err
+	}
+	mig, ok := m.byVersion[current]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d is applied but this binary has no migration for it", ErrUnknownVersion, current)
+	}
+	return &mig, nil
+}
+
+// withLock runs fn holding the migration lock, refusing to start if the
+// database is dirty.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	return m.withLockAllowDirty(ctx, func() error {
+		dirty, err := m.lock.dirty(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty != nil {
+			return dirty
+		}
+		return fn()
+	})
+}
+
+func (m *Migrator) withLockAllowDirty(ctx context.Context, fn func() error) error {
+	if err := m.lock.ensureTable(ctx); err != nil {
+		return err
+	}
+	release, err := m.lock.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+// appliedVersions returns the applied versions recorded by goose and when
+// each was applied. The bookkeeping row for version 0 is left out.
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	applied := make(map[int64]time.Time)
+
+	var exists int
+	if err := m.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT version_id, MAX(tstamp) FROM goose_db_version
+		WHERE is_applied = 1 AND version_id > 0
+		GROUP BY version_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v int64
+		var at sql.NullString
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, err
+		}
+		t, _ := time.Parse(time.DateTime, at.String)
+		applied[v] = t
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) latest() int64 {
+	if n := len(m.migrations); n > 0 {
+		return m.migrations[n-1].Version
+	}
+	return 0
+}
+
+// LatestMigrationVersion returns the version of the newest migration built
+// into this binary.
+func LatestMigrationVersion() (int64, error) {
+	entries, err := fs.ReadDir(embedMigrations, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("reading migrations: %w", err)
+	}
+	var latest int64
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, v)
+	}
+	return latest, nil
+}
+
+// SchemaVersion returns the newest migration applied to db, or 0 if it has
+// never been migrated.
+func SchemaVersion(db *sql.DB) (int64, error) {
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		return 0, nil
+	}
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version_id) FROM goose_db_version WHERE is_applied = 1`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version.Int64, nil
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE incoming_webhooks (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    avatar_url TEXT,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_incoming_webhooks_workspace ON incoming_webhooks(workspace_id);
+CREATE INDEX idx_incoming_webhooks_channel ON incoming_webhooks(channel_id);
+
+ALTER TABLE messages ADD COLUMN webhook_id TEXT REFERENCES incoming_webhooks(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN bot_name TEXT;
+ALTER TABLE messages ADD COLUMN bot_avatar_url TEXT;
+
+-- +goose Down
+ALTER TABLE messages DROP COLUMN bot_avatar_url;
+ALTER TABLE messages DROP COLUMN bot_name;
+ALTER TABLE messages DROP COLUMN webhook_id;
+DROP TABLE incoming_webhooks;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_bot INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE bots (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    description TEXT,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_bots_workspace ON bots(workspace_id);
+
+CREATE TABLE bot_tokens (
+    id TEXT PRIMARY KEY,
+    bot_user_id TEXT NOT NULL REFERENCES bots(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT NOT NULL,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_bot_tokens_bot ON bot_tokens(bot_user_id);
+
+-- +goose Down
+DROP TABLE bot_tokens;
+DROP TABLE bots;
+ALTER TABLE users DROP COLUMN is_bot;
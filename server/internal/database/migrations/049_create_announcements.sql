@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE TABLE announcements (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    scheduled_for TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'sending', 'sent', 'failed')),
+    sent_at TEXT,
+    last_error TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_announcements_workspace ON announcements(workspace_id, created_at);
+CREATE INDEX idx_announcements_due ON announcements(status, scheduled_for);
+
+-- Target channels. An announcement without rows here targets the whole workspace.
+CREATE TABLE announcement_channels (
+    announcement_id TEXT NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    PRIMARY KEY (announcement_id, channel_id)
+);
+
+-- Recipients are snapshotted at delivery time so the acknowledgement report
+-- reflects who actually received the announcement.
+CREATE TABLE announcement_recipients (
+    announcement_id TEXT NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    acknowledged_at TEXT,
+    PRIMARY KEY (announcement_id, user_id)
+);
+CREATE INDEX idx_announcement_recipients_user ON announcement_recipients(user_id);
+
+ALTER TABLE messages ADD COLUMN announcement_id TEXT REFERENCES announcements(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE messages DROP COLUMN announcement_id;
+DROP TABLE announcement_recipients;
+DROP TABLE announcement_channels;
+DROP TABLE announcements;
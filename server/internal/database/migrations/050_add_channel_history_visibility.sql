@@ -0,0 +1,6 @@
+-- +goose Up
+ALTER TABLE channels ADD COLUMN history_visibility TEXT NOT NULL DEFAULT 'all'
+    CHECK (history_visibility IN ('all', 'last_30_days', 'none'));
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN history_visibility;
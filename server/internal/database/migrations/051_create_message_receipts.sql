@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE message_receipts (
+    message_id TEXT NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    read_at TEXT NOT NULL,
+    PRIMARY KEY (message_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE message_receipts;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Index for listing one author's messages in a channel (ListByAuthor), so
+-- moderators can page through a user's history without scanning the channel.
+CREATE INDEX idx_messages_channel_author ON messages(channel_id, user_id, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_messages_channel_author;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Resumable uploads in progress. Each appended chunk is stored as its own
+-- object and the chunks are concatenated into an attachment on completion.
+CREATE TABLE upload_sessions (
+    id TEXT PRIMARY KEY,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    offset_bytes INTEGER NOT NULL DEFAULT 0,
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_upload_sessions_expires ON upload_sessions(expires_at);
+
+CREATE TABLE upload_chunks (
+    upload_id TEXT NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+    offset_bytes INTEGER NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    storage_path TEXT NOT NULL,
+    PRIMARY KEY (upload_id, offset_bytes)
+);
+
+-- +goose Down
+DROP TABLE upload_chunks;
+DROP TABLE upload_sessions;
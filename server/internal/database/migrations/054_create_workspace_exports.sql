@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE workspace_exports (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    requested_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    storage_path TEXT,
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    expires_at TEXT,
+    completed_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_workspace_exports_workspace ON workspace_exports(workspace_id, created_at);
+CREATE INDEX idx_workspace_exports_status ON workspace_exports(status);
+
+-- +goose Down
+DROP TABLE workspace_exports;
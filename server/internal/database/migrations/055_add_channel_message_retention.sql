@@ -0,0 +1,8 @@
+-- +goose Up
+-- Days to keep messages in the channel. NULL uses the workspace default and
+-- 0 keeps messages forever.
+ALTER TABLE channels ADD COLUMN message_retention_days INTEGER
+    CHECK (message_retention_days >= 0);
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN message_retention_days;
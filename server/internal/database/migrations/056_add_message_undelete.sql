@@ -0,0 +1,11 @@
+-- +goose Up
+-- Deleting a message keeps its original content here until the undelete
+-- window passes, so the author can restore it. The purge job then clears it.
+ALTER TABLE messages ADD COLUMN deleted_content TEXT;
+ALTER TABLE messages ADD COLUMN deleted_by TEXT;
+CREATE INDEX idx_messages_deleted_at ON messages(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_messages_deleted_at;
+ALTER TABLE messages DROP COLUMN deleted_by;
+ALTER TABLE messages DROP COLUMN deleted_content;
@@ -0,0 +1,32 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN title TEXT;
+ALTER TABLE users ADD COLUMN pronouns TEXT;
+ALTER TABLE users ADD COLUMN timezone TEXT;
+ALTER TABLE users ADD COLUMN phone TEXT;
+
+-- Admin-defined profile fields. Members' values are stored per workspace as
+-- a JSON object keyed by field ID in workspace_memberships.profile_fields.
+CREATE TABLE workspace_profile_fields (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    field_type TEXT NOT NULL DEFAULT 'text' CHECK (field_type IN ('text', 'url', 'select')),
+    options TEXT,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    UNIQUE(workspace_id, name)
+);
+
+CREATE INDEX idx_workspace_profile_fields_workspace ON workspace_profile_fields(workspace_id, sort_order);
+
+ALTER TABLE workspace_memberships ADD COLUMN profile_fields TEXT;
+
+-- +goose Down
+ALTER TABLE workspace_memberships DROP COLUMN profile_fields;
+DROP INDEX IF EXISTS idx_workspace_profile_fields_workspace;
+DROP TABLE IF EXISTS workspace_profile_fields;
+ALTER TABLE users DROP COLUMN phone;
+ALTER TABLE users DROP COLUMN timezone;
+ALTER TABLE users DROP COLUMN pronouns;
+ALTER TABLE users DROP COLUMN title;
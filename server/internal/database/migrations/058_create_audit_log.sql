@@ -0,0 +1,60 @@
+-- +goose Up
+-- Generalize moderation_log into audit_log. The action and target_type CHECK
+-- constraints are dropped: admin actions are logged from many handlers and the
+-- set keeps growing, so validation lives in the application.
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE audit_log (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    actor_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    metadata TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+INSERT INTO audit_log SELECT * FROM moderation_log;
+
+DROP TABLE moderation_log;
+
+CREATE INDEX idx_audit_log_workspace ON audit_log(workspace_id, created_at);
+CREATE INDEX idx_audit_log_workspace_action ON audit_log(workspace_id, action);
+CREATE INDEX idx_audit_log_workspace_actor ON audit_log(workspace_id, actor_id);
+
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE moderation_log (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    actor_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN (
+        'user.banned', 'user.unbanned',
+        'user.blocked', 'user.unblocked',
+        'message.deleted', 'member.removed',
+        'member.role_changed', 'channel.archived'
+    )),
+    target_type TEXT NOT NULL CHECK (target_type IN ('user', 'message', 'channel')),
+    target_id TEXT NOT NULL,
+    metadata TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+INSERT INTO moderation_log
+SELECT * FROM audit_log
+WHERE action IN (
+    'user.banned', 'user.unbanned',
+    'user.blocked', 'user.unblocked',
+    'message.deleted', 'member.removed',
+    'member.role_changed', 'channel.archived'
+) AND target_type IN ('user', 'message', 'channel');
+
+DROP TABLE audit_log;
+
+CREATE INDEX idx_moderation_log_workspace ON moderation_log(workspace_id, created_at);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Sessions get a public ID (the token hash must never leave the server) and
+-- the client details shown on the sessions page.
+ALTER TABLE sessions ADD COLUMN id TEXT;
+ALTER TABLE sessions ADD COLUMN created_at TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN last_seen_at TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE sessions ADD COLUMN ip_address TEXT NOT NULL DEFAULT '';
+
+-- Existing sessions have no history; treat them as created now
+UPDATE sessions SET
+    id = lower(hex(randomblob(16))),
+    created_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now'),
+    last_seen_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
+
+CREATE UNIQUE INDEX idx_sessions_id ON sessions(id);
+CREATE INDEX idx_sessions_user ON sessions(user_id);
+
+-- +goose Down
+DROP INDEX idx_sessions_user;
+DROP INDEX idx_sessions_id;
+ALTER TABLE sessions DROP COLUMN ip_address;
+ALTER TABLE sessions DROP COLUMN user_agent;
+ALTER TABLE sessions DROP COLUMN last_seen_at;
+ALTER TABLE sessions DROP COLUMN created_at;
+ALTER TABLE sessions DROP COLUMN id;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- Each session is a refresh token family: the row holds the current access
+-- token (token, expiry) and the current refresh token. Refresh tokens that
+-- have been rotated out are kept until the session ends so that presenting
+-- one again can be detected as reuse.
+ALTER TABLE sessions ADD COLUMN refresh_token TEXT;
+ALTER TABLE sessions ADD COLUMN refresh_expiry TEXT NOT NULL DEFAULT '';
+
+-- Existing sessions have no refresh token and end when their token expires
+UPDATE sessions SET refresh_expiry = expiry;
+
+CREATE UNIQUE INDEX idx_sessions_refresh_token ON sessions(refresh_token);
+
+CREATE TABLE rotated_refresh_tokens (
+    token TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    rotated_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_rotated_refresh_tokens_session ON rotated_refresh_tokens(session_id);
+
+-- +goose Down
+DROP TABLE rotated_refresh_tokens;
+DROP INDEX idx_sessions_refresh_token;
+ALTER TABLE sessions DROP COLUMN refresh_expiry;
+ALTER TABLE sessions DROP COLUMN refresh_token;
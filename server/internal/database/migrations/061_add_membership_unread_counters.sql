@@ -0,0 +1,362 @@
+-- +goose Up
+-- Unread and notification counts are kept on each channel membership instead
+-- of being counted from messages on every channel list. Triggers maintain
+-- them: new, deleted and restored top-level messages adjust the counters of
+-- members who have not read past them, and anything that changes what counts
+-- (read position, joining, notification level, channel type) recounts the
+-- affected memberships.
+--
+-- A message notifies a member when the channel is a DM, when their level is
+-- 'all', or when their level is 'mentions' (the default) and the message
+-- mentions them, @channel or @everyone.
+ALTER TABLE channel_memberships ADD COLUMN unread_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE channel_memberships ADD COLUMN notification_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backfill
+UPDATE channel_memberships SET
+    unread_count = (
+        SELECT COUNT(*) FROM messages m
+        WHERE m.channel_id = channel_memberships.channel_id
+          AND m.thread_parent_id IS NULL
+          AND m.deleted_at IS NULL
+          AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+    ),
+    notification_count = (
+        SELECT COUNT(*) FROM messages m
+        JOIN channels c ON c.id = m.channel_id
+        LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+        WHERE m.channel_id = channel_memberships.channel_id
+          AND m.thread_parent_id IS NULL
+          AND m.deleted_at IS NULL
+          AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+          AND CASE
+            WHEN c.type IN ('dm', 'group_dm') THEN 1
+            WHEN np.notify_level = 'none' THEN 0
+            WHEN np.notify_level = 'all' THEN 1
+            WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+              EXISTS (
+                SELECT 1 FROM json_each(m.mentions) je
+                WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+              )
+            ELSE 0
+          END = 1
+    );
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_insert
+AFTER INSERT ON messages
+WHEN NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = unread_count + 1,
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_delete
+AFTER UPDATE OF deleted_at ON messages
+WHEN NEW.thread_parent_id IS NULL AND OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = MAX(unread_count - 1, 0),
+        notification_count = MAX(notification_count - (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        ), 0)
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_restore
+AFTER UPDATE OF deleted_at ON messages
+WHEN NEW.thread_parent_id IS NULL AND OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = unread_count + 1,
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- Hard deletes come from the retention purge
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_purge
+AFTER DELETE ON messages
+WHEN OLD.thread_parent_id IS NULL AND OLD.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = MAX(unread_count - 1, 0),
+        notification_count = MAX(notification_count - (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(OLD.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = OLD.channel_id
+        ), 0)
+    WHERE channel_id = OLD.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < OLD.id);
+END;
+-- +goose StatementEnd
+
+-- Recounting only scans messages after the read position, so marking a
+-- channel read is cheap however long its history is.
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_read
+AFTER UPDATE OF last_read_message_id ON channel_memberships
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = (
+            SELECT COUNT(*) FROM messages m
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+        ),
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+              END = 1
+        )
+    WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_join
+AFTER INSERT ON channel_memberships
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = (
+            SELECT COUNT(*) FROM messages m
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+        ),
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+              END = 1
+        )
+    WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_insert
+AFTER INSERT ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+              END = 1
+        )
+    WHERE channel_id = NEW.channel_id AND user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_update
+AFTER UPDATE OF notify_level ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+              END = 1
+        )
+    WHERE channel_id = NEW.channel_id AND user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_delete
+AFTER DELETE ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+              END = 1
+        )
+    WHERE channel_id = OLD.channel_id AND user_id = OLD.user_id;
+END;
+-- +goose StatementEnd
+
+-- Converting a group DM into a channel changes which messages notify
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_channel_type
+AFTER UPDATE OF type ON channels
+WHEN OLD.type != NEW.type
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+              END = 1
+        )
+    WHERE channel_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_channel_type;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_preference_delete;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_preference_update;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_preference_insert;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_join;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_read;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_purge;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_restore;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_delete;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_insert;
+ALTER TABLE channel_memberships DROP COLUMN notification_count;
+ALTER TABLE channel_memberships DROP COLUMN unread_count;
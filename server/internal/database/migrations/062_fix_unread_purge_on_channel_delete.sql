@@ -0,0 +1,65 @@
+-- +goose Up
+-- When a channel is deleted its messages are removed by cascade after the
+-- channel row is gone, so the purge trigger's notification lookup finds no
+-- channel and yields NULL. Treat that as "did not notify" instead of writing
+-- NULL into the counter.
+DROP TRIGGER channel_memberships_unread_message_purge;
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_purge
+AFTER DELETE ON messages
+WHEN OLD.thread_parent_id IS NULL AND OLD.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = MAX(unread_count - 1, 0),
+        notification_count = MAX(notification_count - COALESCE((
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(OLD.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = OLD.channel_id
+        ), 0), 0)
+    WHERE channel_id = OLD.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < OLD.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER channel_memberships_unread_message_purge;
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_purge
+AFTER DELETE ON messages
+WHEN OLD.thread_parent_id IS NULL AND OLD.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = MAX(unread_count - 1, 0),
+        notification_count = MAX(notification_count - (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN np.notify_level = 'none' THEN 0
+                WHEN np.notify_level = 'all' THEN 1
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(OLD.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = OLD.channel_id
+        ), 0)
+    WHERE channel_id = OLD.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < OLD.id);
+END;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Per-user activity feed: mentions, replies to the user's threads, reactions
+-- to the user's messages, and being added to channels.
+CREATE TABLE activities (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    type TEXT NOT NULL CHECK (type IN ('mention', 'thread_reply', 'reaction', 'channel_invite')),
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    message_id TEXT REFERENCES messages(id) ON DELETE CASCADE,
+    emoji TEXT,
+    read_at TEXT,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_activities_user_workspace ON activities(user_id, workspace_id, id);
+CREATE INDEX idx_activities_message ON activities(message_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_activities_message;
+DROP INDEX IF EXISTS idx_activities_user_workspace;
+DROP TABLE IF EXISTS activities;
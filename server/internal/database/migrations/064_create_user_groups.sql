@@ -0,0 +1,28 @@
+-- +goose Up
+-- User groups let members mention a team by handle (e.g. @backend), which
+-- notifies every member of the group.
+CREATE TABLE user_groups (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    handle TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    UNIQUE(workspace_id, handle)
+);
+
+CREATE TABLE user_group_members (
+    group_id TEXT NOT NULL REFERENCES user_groups(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX idx_user_group_members_user ON user_group_members(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_group_members_user;
+DROP TABLE IF EXISTS user_group_members;
+DROP TABLE IF EXISTS user_groups;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Who may use @channel, @here and @everyone in the channel. NULL uses the
+-- workspace's who_can_mention_channel setting.
+ALTER TABLE channels ADD COLUMN who_can_mention_channel TEXT
+    CHECK (who_can_mention_channel IN ('everyone', 'members', 'admins'));
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN who_can_mention_channel;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- A poll is attached to a message of type 'poll', whose content is the
+-- question. Votes are replaced as a whole when a user votes again.
+CREATE TABLE polls (
+    id TEXT PRIMARY KEY,
+    message_id TEXT NOT NULL UNIQUE REFERENCES messages(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    question TEXT NOT NULL,
+    multiple_choice INTEGER NOT NULL DEFAULT 0,
+    anonymous INTEGER NOT NULL DEFAULT 0,
+    closes_at TEXT,
+    closed_at TEXT,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_polls_closes_at ON polls(closes_at) WHERE closed_at IS NULL AND closes_at IS NOT NULL;
+
+CREATE TABLE poll_options (
+    id TEXT PRIMARY KEY,
+    poll_id TEXT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    text TEXT NOT NULL
+);
+
+CREATE INDEX idx_poll_options_poll ON poll_options(poll_id, position);
+
+CREATE TABLE poll_votes (
+    poll_id TEXT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    option_id TEXT NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (option_id, user_id)
+);
+
+CREATE INDEX idx_poll_votes_poll_user ON poll_votes(poll_id, user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_poll_votes_poll_user;
+DROP TABLE IF EXISTS poll_votes;
+DROP INDEX IF EXISTS idx_poll_options_poll;
+DROP TABLE IF EXISTS poll_options;
+DROP INDEX IF EXISTS idx_polls_closes_at;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Voice/video calls in a channel. The server only tracks who is in the call
+-- and relays WebRTC signaling; media flows between the participants. A
+-- channel has at most one call in progress.
+CREATE TABLE calls (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    started_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    message_id TEXT REFERENCES messages(id) ON DELETE SET NULL,
+    started_at TEXT NOT NULL,
+    ended_at TEXT
+);
+
+CREATE UNIQUE INDEX idx_calls_active_channel ON calls(channel_id) WHERE ended_at IS NULL;
+
+CREATE TABLE call_participants (
+    call_id TEXT NOT NULL REFERENCES calls(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TEXT NOT NULL,
+    left_at TEXT,
+    PRIMARY KEY (call_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS call_participants;
+DROP INDEX IF EXISTS idx_calls_active_channel;
+DROP TABLE IF EXISTS calls;
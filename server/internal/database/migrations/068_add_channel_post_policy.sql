@@ -0,0 +1,14 @@
+-- +goose Up
+-- Who may post in the channel. 'admins' limits posting to channel and
+-- workspace admins (announcement channels); 'roles' also lets the workspace
+-- roles in post_roles (a JSON array) post. Thread replies stay open to
+-- everyone unless restrict_thread_replies is set.
+ALTER TABLE channels ADD COLUMN post_policy TEXT NOT NULL DEFAULT 'everyone'
+    CHECK (post_policy IN ('everyone', 'admins', 'roles'));
+ALTER TABLE channels ADD COLUMN post_roles TEXT;
+ALTER TABLE channels ADD COLUMN restrict_thread_replies INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN restrict_thread_replies;
+ALTER TABLE channels DROP COLUMN post_roles;
+ALTER TABLE channels DROP COLUMN post_policy;
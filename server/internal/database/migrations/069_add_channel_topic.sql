@@ -0,0 +1,7 @@
+-- +goose Up
+-- A short line shown in the channel header, separate from the longer
+-- description. Any member who can post may change it.
+ALTER TABLE channels ADD COLUMN topic TEXT;
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN topic;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Channel templates are admin-defined sets of channels that can be created in
+-- one step, when creating a workspace or later. The channels are stored as a
+-- JSON array of {name, type, description, member_roles}.
+CREATE TABLE channel_templates (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT,
+    channels TEXT NOT NULL,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    UNIQUE(workspace_id, name)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS channel_templates;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Channels marked auto_join are joined automatically by new workspace
+-- members, in addition to the workspace's default channel.
+ALTER TABLE channels ADD COLUMN auto_join INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN auto_join;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Account deletion requests. The account is deactivated as soon as the
+-- request is made; a background job then anonymizes the user row and removes
+-- their profile data and files. The row is kept as the record of the deletion.
+CREATE TABLE account_deletions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    files_deleted INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    completed_at TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+CREATE INDEX idx_account_deletions_user ON account_deletions(user_id);
+CREATE INDEX idx_account_deletions_status ON account_deletions(status);
+
+-- +goose Down
+DROP TABLE account_deletions;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Per-workspace content filter. Each entry is a plain word or a regular
+-- expression; matching messages are rejected or have the match masked,
+-- depending on the entry's action.
+CREATE TABLE blocked_words (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    pattern TEXT NOT NULL,
+    is_regex INTEGER NOT NULL DEFAULT 0,
+    action TEXT NOT NULL DEFAULT 'reject' CHECK (action IN ('reject', 'mask')),
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    UNIQUE(workspace_id, pattern, is_regex)
+);
+CREATE INDEX idx_blocked_words_workspace ON blocked_words(workspace_id);
+
+-- +goose Down
+DROP TABLE blocked_words;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Slow mode: the minimum number of seconds a member must wait between two
+-- messages in the channel. 0 disables it.
+ALTER TABLE channels ADD COLUMN slow_mode_seconds INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE channels DROP COLUMN slow_mode_seconds;
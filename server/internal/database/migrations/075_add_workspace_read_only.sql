@@ -0,0 +1,10 @@
+-- +goose Up
+-- Read-only (maintenance) mode, toggled by the workspace owner. While set,
+-- sending, editing, reacting and uploading are refused; read_only_message is
+-- shown to members as a banner.
+ALTER TABLE workspaces ADD COLUMN read_only INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE workspaces ADD COLUMN read_only_message TEXT;
+
+-- +goose Down
+ALTER TABLE workspaces DROP COLUMN read_only_message;
+ALTER TABLE workspaces DROP COLUMN read_only;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Idempotency key the sender supplied, so a retried send returns the original
+-- message. Keys are unique per user; a key is released after 24 hours.
+ALTER TABLE messages ADD COLUMN client_msg_id TEXT;
+CREATE UNIQUE INDEX idx_messages_client_msg_id ON messages(user_id, client_msg_id) WHERE client_msg_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX idx_messages_client_msg_id;
+ALTER TABLE messages DROP COLUMN client_msg_id;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Words and phrases that notify a user like a mention. Matches are added to
+-- the message's mentions when it is sent, so badge counts pick them up.
+CREATE TABLE notification_keywords (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    keyword TEXT NOT NULL COLLATE NOCASE,
+    created_at TEXT NOT NULL,
+    UNIQUE(user_id, keyword)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_keywords;
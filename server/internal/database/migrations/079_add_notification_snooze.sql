@@ -0,0 +1,10 @@
+-- +goose Up
+-- Snoozing silences a channel, or every channel in a workspace, until the
+-- given time. Unlike notification levels it expires on its own, so it's kept
+-- on the membership rather than with the preferences.
+ALTER TABLE channel_memberships ADD COLUMN snoozed_until TEXT;
+ALTER TABLE workspace_memberships ADD COLUMN snoozed_until TEXT;
+
+-- +goose Down
+ALTER TABLE workspace_memberships DROP COLUMN snoozed_until;
+ALTER TABLE channel_memberships DROP COLUMN snoozed_until;
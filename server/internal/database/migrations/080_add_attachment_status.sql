@@ -0,0 +1,8 @@
+-- +goose Up
+-- Upload scan outcome. Quarantined attachments failed the antivirus scan:
+-- they cannot be downloaded or attached to a message, and the garbage
+-- collector removes them with other unlinked uploads.
+ALTER TABLE attachments ADD COLUMN status TEXT NOT NULL DEFAULT 'ready';
+
+-- +goose Down
+ALTER TABLE attachments DROP COLUMN status;
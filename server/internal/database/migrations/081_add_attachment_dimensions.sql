@@ -0,0 +1,9 @@
+-- +goose Up
+-- Pixel dimensions of image attachments, measured after EXIF orientation is
+-- applied, so clients can reserve layout space before the image loads.
+ALTER TABLE attachments ADD COLUMN width INTEGER;
+ALTER TABLE attachments ADD COLUMN height INTEGER;
+
+-- +goose Down
+ALTER TABLE attachments DROP COLUMN height;
+ALTER TABLE attachments DROP COLUMN width;
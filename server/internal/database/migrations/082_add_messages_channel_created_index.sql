@@ -0,0 +1,7 @@
+-- +goose Up
+-- Index for counting a channel's messages by day (DailyActivity), so activity
+-- graphs read only the requested period instead of the whole channel.
+CREATE INDEX idx_messages_channel_created ON messages(channel_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_messages_channel_created;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Messages sent with async set get their mentions after they are stored, so
+-- notification counts follow a change of mentions the same way they follow a
+-- new message. Only members on the 'mentions' level are affected: DMs and the
+-- 'all' and 'none' levels do not depend on who is mentioned.
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_mentions
+AFTER UPDATE OF mentions ON messages
+WHEN NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = MAX(notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 0
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  ) - EXISTS (
+                    SELECT 1 FROM json_each(OLD.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        ), 0)
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_mentions;
@@ -0,0 +1,1083 @@
+-- +goose Up
+-- Mentions are recorded one row per target so the unread counters can look
+-- them up by index instead of parsing every message's JSON array. The JSON
+-- column stays as the API representation; the triggers below keep this table
+-- in step with it.
+CREATE TABLE message_mentions (
+    message_id TEXT NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    target TEXT NOT NULL,
+    PRIMARY KEY (message_id, target)
+) WITHOUT ROWID;
+
+CREATE INDEX idx_message_mentions_target ON message_mentions(target, message_id);
+
+INSERT OR IGNORE INTO message_mentions (message_id, target)
+SELECT m.id, je.value FROM messages m, json_each(m.mentions) je
+WHERE je.type = 'text';
+
+-- Recreate the unread counter triggers to read mentions from the table. The
+-- insert and mentions triggers record a message's mentions themselves,
+-- before counting, so the rows are there when they are needed; the mentions
+-- trigger also now resolves the level through the same hierarchy as the
+-- others. The purge trigger is left reading OLD.mentions, since the cascade
+-- may already have removed the rows when it runs.
+DROP TRIGGER channel_memberships_unread_recount_workspace_setting_delete;
+DROP TRIGGER channel_memberships_unread_recount_workspace_setting_update;
+DROP TRIGGER channel_memberships_unread_recount_workspace_setting_insert;
+DROP TRIGGER channel_memberships_unread_recount_user_setting_delete;
+DROP TRIGGER channel_memberships_unread_recount_user_setting_update;
+DROP TRIGGER channel_memberships_unread_recount_user_setting_insert;
+DROP TRIGGER channel_memberships_unread_recount_channel_type;
+DROP TRIGGER channel_memberships_unread_recount_preference_delete;
+DROP TRIGGER channel_memberships_unread_recount_preference_update;
+DROP TRIGGER channel_memberships_unread_recount_preference_insert;
+DROP TRIGGER channel_memberships_unread_recount_join;
+DROP TRIGGER channel_memberships_unread_recount_read;
+DROP TRIGGER channel_memberships_unread_message_mentions;
+DROP TRIGGER channel_memberships_unread_message_restore;
+DROP TRIGGER channel_memberships_unread_message_delete;
+DROP TRIGGER channel_memberships_unread_message_insert;
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_insert
+AFTER INSERT ON messages
+BEGIN
+    INSERT OR IGNORE INTO message_mentions (message_id, target)
+    SELECT NEW.id, value FROM json_each(NEW.mentions) WHERE type = 'text';
+
+    UPDATE channel_memberships SET
+        unread_count = unread_count + 1,
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = NEW.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+      AND channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_delete
+AFTER UPDATE OF deleted_at ON messages
+WHEN NEW.thread_parent_id IS NULL AND OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = MAX(unread_count - 1, 0),
+        notification_count = MAX(notification_count - (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = NEW.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        ), 0)
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_restore
+AFTER UPDATE OF deleted_at ON messages
+WHEN NEW.thread_parent_id IS NULL AND OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = unread_count + 1,
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = NEW.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_mentions
+AFTER UPDATE OF mentions ON messages
+WHEN OLD.mentions IS NOT NEW.mentions
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = MAX(notification_count - (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level, 'mentions') = 'mentions' THEN
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = NEW.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        ), 0)
+    WHERE NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+      AND channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+
+    DELETE FROM message_mentions WHERE message_id = NEW.id;
+    INSERT OR IGNORE INTO message_mentions (message_id, target)
+    SELECT NEW.id, value FROM json_each(NEW.mentions) WHERE type = 'text';
+
+    UPDATE channel_memberships SET
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level, 'mentions') = 'mentions' THEN
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = NEW.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+      AND channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_read
+AFTER UPDATE OF last_read_message_id ON channel_memberships
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = (
+            SELECT COUNT(*) FROM messages m
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+        ),
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_join
+AFTER INSERT ON channel_memberships
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = (
+            SELECT COUNT(*) FROM messages m
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+        ),
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_insert
+AFTER INSERT ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = NEW.channel_id AND user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_update
+AFTER UPDATE OF notify_level ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = NEW.channel_id AND user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_delete
+AFTER DELETE ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = OLD.channel_id AND user_id = OLD.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_channel_type
+AFTER UPDATE OF type ON channels
+WHEN OLD.type != NEW.type
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_user_setting_insert
+AFTER INSERT ON user_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_user_setting_update
+AFTER UPDATE OF notify_level ON user_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_user_setting_delete
+AFTER DELETE ON user_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = OLD.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_workspace_setting_insert
+AFTER INSERT ON workspace_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id AND channel_id IN (SELECT id FROM channels WHERE workspace_id = NEW.workspace_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_workspace_setting_update
+AFTER UPDATE OF notify_level ON workspace_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id AND channel_id IN (SELECT id FROM channels WHERE workspace_id = NEW.workspace_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_workspace_setting_delete
+AFTER DELETE ON workspace_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM message_mentions mm
+                    WHERE mm.message_id = m.id
+                      AND mm.target IN (channel_memberships.user_id, '@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = OLD.user_id AND channel_id IN (SELECT id FROM channels WHERE workspace_id = OLD.workspace_id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_workspace_setting_delete;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_workspace_setting_update;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_workspace_setting_insert;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_user_setting_delete;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_user_setting_update;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_user_setting_insert;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_channel_type;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_preference_delete;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_preference_update;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_preference_insert;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_join;
+DROP TRIGGER IF EXISTS channel_memberships_unread_recount_read;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_mentions;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_restore;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_delete;
+DROP TRIGGER IF EXISTS channel_memberships_unread_message_insert;
+DROP TABLE IF EXISTS message_mentions;
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_insert
+AFTER INSERT ON messages
+WHEN NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = unread_count + 1,
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_delete
+AFTER UPDATE OF deleted_at ON messages
+WHEN NEW.thread_parent_id IS NULL AND OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = MAX(unread_count - 1, 0),
+        notification_count = MAX(notification_count - (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        ), 0)
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_restore
+AFTER UPDATE OF deleted_at ON messages
+WHEN NEW.thread_parent_id IS NULL AND OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = unread_count + 1,
+        notification_count = notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        )
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_read
+AFTER UPDATE OF last_read_message_id ON channel_memberships
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = (
+            SELECT COUNT(*) FROM messages m
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+        ),
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_join
+AFTER INSERT ON channel_memberships
+BEGIN
+    UPDATE channel_memberships SET
+        unread_count = (
+            SELECT COUNT(*) FROM messages m
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+        ),
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_insert
+AFTER INSERT ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = NEW.channel_id AND user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_update
+AFTER UPDATE OF notify_level ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = NEW.channel_id AND user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_preference_delete
+AFTER DELETE ON notification_preferences
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = OLD.channel_id AND user_id = OLD.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_channel_type
+AFTER UPDATE OF type ON channels
+WHEN OLD.type != NEW.type
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE channel_id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_user_setting_insert
+AFTER INSERT ON user_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_user_setting_update
+AFTER UPDATE OF notify_level ON user_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_user_setting_delete
+AFTER DELETE ON user_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = OLD.user_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_workspace_setting_insert
+AFTER INSERT ON workspace_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id AND channel_id IN (SELECT id FROM channels WHERE workspace_id = NEW.workspace_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_workspace_setting_update
+AFTER UPDATE OF notify_level ON workspace_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = NEW.user_id AND channel_id IN (SELECT id FROM channels WHERE workspace_id = NEW.workspace_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_recount_workspace_setting_delete
+AFTER DELETE ON workspace_notification_settings
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = (
+            SELECT COUNT(*) FROM messages m
+            JOIN channels c ON c.id = m.channel_id
+            LEFT JOIN notification_preferences np ON np.channel_id = m.channel_id AND np.user_id = channel_memberships.user_id
+            LEFT JOIN workspace_notification_settings wns ON wns.workspace_id = c.workspace_id AND wns.user_id = channel_memberships.user_id
+            LEFT JOIN user_notification_settings uns ON uns.user_id = channel_memberships.user_id
+            WHERE m.channel_id = channel_memberships.channel_id
+              AND m.thread_parent_id IS NULL
+              AND m.deleted_at IS NULL
+              AND (channel_memberships.last_read_message_id IS NULL OR m.id > channel_memberships.last_read_message_id)
+              AND CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 1
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'none' THEN 0
+                WHEN COALESCE(np.notify_level, wns.notify_level, uns.notify_level) = 'all' THEN 1
+                ELSE
+                  EXISTS (
+                    SELECT 1 FROM json_each(m.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+              END = 1
+        )
+    WHERE user_id = OLD.user_id AND channel_id IN (SELECT id FROM channels WHERE workspace_id = OLD.workspace_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER channel_memberships_unread_message_mentions
+AFTER UPDATE OF mentions ON messages
+WHEN NEW.thread_parent_id IS NULL AND NEW.deleted_at IS NULL
+BEGIN
+    UPDATE channel_memberships SET
+        notification_count = MAX(notification_count + (
+            SELECT CASE
+                WHEN c.type IN ('dm', 'group_dm') THEN 0
+                WHEN np.notify_level = 'mentions' OR np.notify_level IS NULL THEN
+                  EXISTS (
+                    SELECT 1 FROM json_each(NEW.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  ) - EXISTS (
+                    SELECT 1 FROM json_each(OLD.mentions) je
+                    WHERE je.value = channel_memberships.user_id OR je.value IN ('@channel', '@everyone')
+                  )
+                ELSE 0
+            END
+            FROM channels c
+            LEFT JOIN notification_preferences np ON np.channel_id = c.id AND np.user_id = channel_memberships.user_id
+            WHERE c.id = NEW.channel_id
+        ), 0)
+    WHERE channel_id = NEW.channel_id
+      AND (last_read_message_id IS NULL OR last_read_message_id < NEW.id);
+END;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- The triggers from 029 index every message and update the index on edit,
+-- soft delete (which replaces the content), restore and purge. The backfill
+-- in 029 skipped deleted and system messages, though, so when one of those
+-- was later edited, restored or purged, the index was told to remove an
+-- entry it never had, which corrupts external-content FTS5 tables. Rebuild
+-- the index from messages so it holds every row, as the triggers expect.
+-- Search filters out deleted and system messages itself.
+INSERT INTO messages_fts(messages_fts) VALUES ('rebuild');
+
+-- +goose Down
+-- Nothing to undo: the rebuilt index is what 029's triggers maintain
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE workspace_exports ADD COLUMN channel_id TEXT REFERENCES channels(id) ON DELETE CASCADE;
+ALTER TABLE workspace_exports ADD COLUMN format TEXT NOT NULL DEFAULT 'zip' CHECK (format IN ('zip', 'json', 'csv'));
+ALTER TABLE workspace_exports ADD COLUMN messages_total INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE workspace_exports ADD COLUMN messages_exported INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX idx_workspace_exports_channel ON workspace_exports(channel_id) WHERE channel_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX idx_workspace_exports_channel;
+ALTER TABLE workspace_exports DROP COLUMN messages_exported;
+ALTER TABLE workspace_exports DROP COLUMN messages_total;
+ALTER TABLE workspace_exports DROP COLUMN format;
+ALTER TABLE workspace_exports DROP COLUMN channel_id;
@@ -0,0 +1,90 @@
+-- +goose Up
+-- Cold storage. Threads with no activity for messages.cold_storage_days are
+-- moved here from messages, with their reactions, so the hot table and its
+-- indexes only hold what people still read and write. Archived messages are
+-- read-only: they are listed by their own endpoint and found by search, and
+-- count towards nothing unread.
+CREATE TABLE messages_archive (
+    id TEXT PRIMARY KEY,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    content TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'user',
+    system_event TEXT,
+    -- Threads are archived whole, so the parent is always archived too
+    thread_parent_id TEXT,
+    also_send_to_channel BOOLEAN NOT NULL DEFAULT FALSE,
+    reply_count INTEGER NOT NULL DEFAULT 0,
+    last_reply_at TEXT,
+    mentions TEXT NOT NULL DEFAULT '[]',
+    edited_at TEXT,
+    deleted_at TEXT,
+    deleted_by TEXT,
+    pinned_at TEXT,
+    pinned_by TEXT,
+    webhook_id TEXT,
+    bot_name TEXT,
+    bot_avatar_url TEXT,
+    announcement_id TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    archived_at TEXT NOT NULL
+);
+CREATE INDEX idx_messages_archive_channel ON messages_archive(channel_id, id);
+CREATE INDEX idx_messages_archive_channel_created ON messages_archive(channel_id, created_at);
+CREATE INDEX idx_messages_archive_thread ON messages_archive(thread_parent_id, id);
+
+CREATE TABLE reactions_archive (
+    id TEXT PRIMARY KEY,
+    message_id TEXT NOT NULL REFERENCES messages_archive(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX idx_reactions_archive_message ON reactions_archive(message_id);
+
+CREATE VIRTUAL TABLE messages_archive_fts USING fts5(
+    content,
+    content='messages_archive',
+    content_rowid='rowid',
+    tokenize='porter unicode61 remove_diacritics 2'
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER messages_archive_fts_insert AFTER INSERT ON messages_archive BEGIN
+    INSERT INTO messages_archive_fts(rowid, content) VALUES (NEW.rowid, NEW.content);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER messages_archive_fts_delete AFTER DELETE ON messages_archive BEGIN
+    INSERT INTO messages_archive_fts(messages_archive_fts, rowid, content) VALUES ('delete', OLD.rowid, OLD.content);
+END;
+-- +goose StatementEnd
+
+-- Deleting a message unlinks its attachments, so attachments of archived
+-- messages point at them here instead
+ALTER TABLE attachments ADD COLUMN archived_message_id TEXT REFERENCES messages_archive(id) ON DELETE SET NULL;
+CREATE INDEX idx_attachments_archived_message ON attachments(archived_message_id) WHERE archived_message_id IS NOT NULL;
+
+-- +goose Down
+-- Move archived threads back, parents before their replies
+INSERT INTO messages (id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel,
+    reply_count, last_reply_at, mentions, edited_at, deleted_at, deleted_by, pinned_at, pinned_by, webhook_id,
+    bot_name, bot_avatar_url, announcement_id, created_at, updated_at)
+SELECT id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel,
+    reply_count, last_reply_at, mentions, edited_at, deleted_at, deleted_by, pinned_at, pinned_by, webhook_id,
+    bot_name, bot_avatar_url, announcement_id, created_at, updated_at
+FROM messages_archive
+ORDER BY thread_parent_id IS NOT NULL, id;
+INSERT INTO reactions (id, message_id, user_id, emoji, created_at)
+SELECT id, message_id, user_id, emoji, created_at FROM reactions_archive;
+UPDATE attachments SET message_id = archived_message_id WHERE archived_message_id IS NOT NULL;
+
+DROP INDEX idx_attachments_archived_message;
+ALTER TABLE attachments DROP COLUMN archived_message_id;
+DROP TRIGGER messages_archive_fts_delete;
+DROP TRIGGER messages_archive_fts_insert;
+DROP TABLE messages_archive_fts;
+DROP TABLE reactions_archive;
+DROP TABLE messages_archive;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE TABLE user_client_settings (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    settings TEXT NOT NULL DEFAULT '{}',
+    updated_at TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE user_client_settings;
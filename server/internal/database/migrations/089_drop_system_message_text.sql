@@ -0,0 +1,8 @@
+-- +goose Up
+-- New system messages store only system_event and their text is rendered in
+-- the reader's language. Older system messages keep their English text as a
+-- fallback for readers that don't render events (exports, FTS, older
+-- clients), so nothing is rewritten here.
+
+-- +goose Down
+-- Nothing to undo
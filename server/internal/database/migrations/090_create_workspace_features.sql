@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE workspace_features (
+    workspace_id TEXT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    feature TEXT NOT NULL,
+    enabled INTEGER NOT NULL,
+    updated_at TEXT NOT NULL,
+    PRIMARY KEY (workspace_id, feature)
+);
+
+-- +goose Down
+DROP TABLE workspace_features;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Server-wide admin actions, such as config reloads. They belong to no
+-- workspace and are taken by the operator rather than a user, so they can't
+-- go in audit_log.
+CREATE TABLE server_audit_log (
+    id TEXT PRIMARY KEY,
+    action TEXT NOT NULL,
+    source TEXT NOT NULL,
+    metadata TEXT,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE INDEX idx_server_audit_log_created ON server_audit_log(created_at);
+
+-- +goose Down
+DROP TABLE server_audit_log;
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testMigrator(t *testing.T) (*DB, *Migrator) {
+	t.Helper()
+	db, err := Open(":memory:", Options{MaxOpenConns: 1, BusyTimeout: 5000, CacheSize: -2000})
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := NewMigrator(db.DB)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	return db, m
+}
+
+func TestMigrator_UpDownTo(t *testing.T) {
+	_, m := testMigrator(t)
+	ctx := context.Background()
+
+	ran, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	latest := m.latest()
+	if len(ran) != len(m.migrations) {
+		t.Errorf("Up ran %d migrations, want %d", len(ran), len(m.migrations))
+	}
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.Current != latest || st.Latest != latest {
+		t.Errorf("status current=%d latest=%d, want both %d", st.Current, st.Latest, latest)
+	}
+	if again, err := m.Up(ctx); err != nil || len(again) != 0 {
+		t.Errorf("second Up ran %d migrations (err %v), want none", len(again), err)
+	}
+
+	down, err := m.Down(ctx)
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if down == nil || down.Version != latest {
+		t.Fatalf("Down rolled back %+v, want version %d", down, latest)
+	}
+
+	// Every down migration must work, and every up must apply again after.
+	if _, err := m.To(ctx, 0); err != nil {
+		t.Fatalf("To(0): %v", err)
+	}
+	if st, _ := m.Status(ctx); st.Current != 0 {
+		t.Errorf("after To(0) current = %d, want 0", st.Current)
+	}
+	if _, err := m.To(ctx, 10); err != nil {
+		t.Fatalf("To(10): %v", err)
+	}
+	if st, _ := m.Status(ctx); st.Current != 10 {
+		t.Errorf("after To(10) current = %d, want 10", st.Current)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up after To: %v", err)
+	}
+
+	if _, err := m.To(ctx, latest+1); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("To(unknown) = %v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestMigrator_RefusesWhileLocked(t *testing.T) {
+	db, m := testMigrator(t)
+	ctx := context.Background()
+
+	other := newMigrationLock(db.DB)
+	if err := other.ensureTable(ctx); err != nil {
+		t.Fatalf("ensureTable: %v", err)
+	}
+	release, err := other.acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	m.lock.wait = 0
+	if _, err := m.Up(ctx); !errors.Is(err, ErrMigrationLocked) {
+		t.Fatalf("Up while locked = %v, want ErrMigrationLocked", err)
+	}
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.Lock == nil || st.Lock.Owner != other.owner {
+		t.Errorf("status lock = %+v, want held by %s", st.Lock, other.owner)
+	}
+
+	release()
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up after release: %v", err)
+	}
+}
+
+func TestMigrator_TakesOverExpiredLock(t *testing.T) {
+	db, m := testMigrator(t)
+	ctx := context.Background()
+
+	// A holder that died without releasing its lock.
+	other := newMigrationLock(db.DB)
+	other.lease = -time.Second
+	if err := other.ensureTable(ctx); err != nil {
+		t.Fatalf("ensureTable: %v", err)
+	}
+	if ok, err := other.tryAcquire(ctx); err != nil || !ok {
+		t.Fatalf("tryAcquire = %v, %v", ok, err)
+	}
+
+	m.lock.wait = 0
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up with expired lock: %v", err)
+	}
+}
+
+func TestMigrator_DirtyAndForce(t *testing.T) {
+	_, m := testMigrator(t)
+	ctx := context.Background()
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := m.lock.setDirty(ctx, 20, "up"); err != nil {
+		t.Fatalf("setDirty: %v", err)
+	}
+
+	var dirty *DirtyError
+	if _, err := m.Down(ctx); !errors.As(err, &dirty) || dirty.Version != 20 {
+		t.Fatalf("Down on dirty database = %v, want DirtyError for 20", err)
+	}
+	if st, _ := m.Status(ctx); st.Dirty == nil {
+		t.Error("status does not report the dirty migration")
+	}
+
+	if err := m.Force(ctx, 19); err != nil {
+		t.Fatalf("Force: %v", err)
+	}
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if st.Dirty != nil {
+		t.Errorf("dirty = %+v after Force, want nil", st.Dirty)
+	}
+	if st.Current != 19 {
+		t.Errorf("current = %d after Force, want 19", st.Current)
+	}
+	for _, ms := range st.Migrations {
+		if want := ms.Version <= 19; ms.Applied != want {
+			t.Errorf("migration %d applied = %v, want %v", ms.Version, ms.Applied, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSlowQueryDigests bounds the number of distinct digests tracked so a
+// workload with many unique statements cannot grow the log without limit.
+const maxSlowQueryDigests = 500
+
+// SlowQueryStat aggregates the slow executions of one query digest.
+type SlowQueryStat struct {
+	Digest        string
+	Count         int64
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	LastCaller    string
+	LastSeenAt    time.Time
+}
+
+// SlowQueryLog records statements that take longer than a threshold. Each
+// slow statement is logged with its digest, duration and calling function,
+// and aggregated per digest for the lifetime of the process.
+type SlowQueryLog struct {
+	threshold time.Duration
+	startedAt time.Time
+
+	mu      sync.Mutex
+	digests map[string]*SlowQueryStat
+}
+
+// NewSlowQueryLog creates a slow-query log with the given threshold.
+func NewSlowQueryLog(threshold time.Duration) *SlowQueryLog {
+	return &SlowQueryLog{
+		threshold: threshold,
+		startedAt: time.Now().UTC(),
+		digests:   make(map[string]*SlowQueryStat),
+	}
+}
+
+// Threshold returns the duration above which statements are recorded.
+func (l *SlowQueryLog) Threshold() time.Duration {
+	return l.threshold
+}
+
+// StartedAt returns when the log started collecting.
+func (l *SlowQueryLog) StartedAt() time.Time {
+	return l.startedAt
+}
+
+// Record notes a statement execution, logging and aggregating it if it
+// exceeded the threshold.
+func (l *SlowQueryLog) Record(query string, duration time.Duration) {
+	if duration < l.threshold {
+		return
+	}
+
+	digest := Digest(query)
+	caller := externalCaller()
+	slog.Warn("slow query", "component", "database", "digest", digest, "duration_ms", duration.Milliseconds(), "caller", caller)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stat, ok := l.digests[digest]
+	if !ok {
+		if len(l.digests) >= maxSlowQueryDigests {
+			return
+		}
+		stat = &SlowQueryStat{Digest: digest}
+		l.digests[digest] = stat
+	}
+	stat.Count++
+	stat.TotalDuration += duration
+	if duration > stat.MaxDuration {
+		stat.MaxDuration = duration
+	}
+	stat.LastCaller = caller
+	stat.LastSeenAt = time.Now().UTC()
+}
+
+// Top returns up to n digests ordered by total time spent, slowest first.
+func (l *SlowQueryLog) Top(n int) []SlowQueryStat {
+	l.mu.Lock()
+	stats := make([]SlowQueryStat, 0, len(l.digests))
+	for _, stat := range l.digests {
+		stats = append(stats, *stat)
+	}
+	l.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalDuration != stats[j].TotalDuration {
+			return stats[i].TotalDuration > stats[j].TotalDuration
+		}
+		return stats[i].Digest < stats[j].Digest
+	})
+	if n >= 0 && len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+}
+
+var (
+	digestStringLiteral   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	digestNumberLiteral   = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	digestPlaceholderList = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+	digestWhitespace      = regexp.MustCompile(`\s+`)
+)
+
+// Digest normalizes a SQL statement so executions that differ only in
+// literal values, placeholder list lengths or formatting share one digest.
+func Digest(query string) string {
+	d := digestStringLiteral.ReplaceAllString(query, "?")
+	d = digestNumberLiteral.ReplaceAllString(d, "?")
+	d = digestPlaceholderList.ReplaceAllString(d, "?, ...")
+	return digestWhitespace.ReplaceAllString(strings.TrimSpace(d), " ")
+}
+
+// externalCaller returns the first stack frame outside database/sql, the
+// SQLite driver and this package — normally the repository method that
+// issued the statement.
+func externalCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isDatabaseFrame(frame.Function) {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+func isDatabaseFrame(function string) bool {
+	return strings.HasPrefix(function, "database/sql.") ||
+		strings.HasPrefix(function, "modernc.org/") ||
+		strings.HasPrefix(function, "github.com/enzyme/server/internal/database.") ||
+		strings.HasPrefix(function, "runtime.")
+}
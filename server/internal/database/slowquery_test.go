@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestDigest(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "collapses whitespace",
+			query: "SELECT id\n\t\tFROM users\n\t\tWHERE id = ?",
+			want:  "SELECT id FROM users WHERE id = ?",
+		},
+		{
+			name:  "strips string literals",
+			query: "SELECT id FROM channels WHERE type = 'private' AND name = 'it''s'",
+			want:  "SELECT id FROM channels WHERE type = ? AND name = ?",
+		},
+		{
+			name:  "strips numeric literals but not identifiers",
+			query: "SELECT t1.id FROM messages t1 LIMIT 50 OFFSET 0",
+			want:  "SELECT t1.id FROM messages t1 LIMIT ? OFFSET ?",
+		},
+		{
+			name:  "collapses placeholder lists",
+			query: "SELECT id FROM users WHERE id IN (?, ?,?)",
+			want:  "SELECT id FROM users WHERE id IN (?, ...)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Digest(tt.query); got != tt.want {
+				t.Errorf("Digest(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlowQueryLog_RecordAndTop(t *testing.T) {
+	log := NewSlowQueryLog(100 * time.Millisecond)
+
+	log.Record("SELECT 1", 10*time.Millisecond) // below threshold
+	log.Record("SELECT * FROM users WHERE id = 'a'", 150*time.Millisecond)
+	log.Record("SELECT * FROM users WHERE id = 'b'", 250*time.Millisecond)
+	log.Record("SELECT * FROM messages", 300*time.Millisecond)
+
+	top := log.Top(10)
+	if len(top) != 2 {
+		t.Fatalf("expected 2 digests, got %d", len(top))
+	}
+
+	users := top[0]
+	if users.Digest != "SELECT * FROM users WHERE id = ?" {
+		t.Fatalf("expected users digest first, got %q", users.Digest)
+	}
+	if users.Count != 2 || users.TotalDuration != 400*time.Millisecond || users.MaxDuration != 250*time.Millisecond {
+		t.Errorf("unexpected aggregate: %+v", users)
+	}
+	if users.LastCaller == "" {
+		t.Error("expected caller to be recorded")
+	}
+
+	if got := log.Top(1); len(got) != 1 {
+		t.Errorf("expected Top(1) to return 1 digest, got %d", len(got))
+	}
+}
+
+func TestOpen_SlowQueryLogTimesStatements(t *testing.T) {
+	log := NewSlowQueryLog(0) // record everything
+
+	db, err := Open(":memory:", Options{MaxOpenConns: 1, BusyTimeout: 5000, CacheSize: -2000, SlowQueryLog: log})
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("creating table: %v", err)
+	}
+	err = db.WithTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", "first")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("inserting row: %v", err)
+	}
+
+	var name string
+	if err := db.QueryRowContext(ctx, "SELECT name FROM items WHERE id = ?", 1).Scan(&name); err != nil {
+		t.Fatalf("querying row: %v", err)
+	}
+	if name != "first" {
+		t.Fatalf("expected %q, got %q", "first", name)
+	}
+
+	seen := make(map[string]bool)
+	for _, stat := range log.Top(-1) {
+		seen[stat.Digest] = true
+	}
+	for _, digest := range []string{
+		"INSERT INTO items (name) VALUES (?)",
+		"SELECT name FROM items WHERE id = ?",
+	} {
+		if !seen[digest] {
+			t.Errorf("expected digest %q to be recorded, got %v", digest, seen)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package email
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"net/url"
+	"sync"
 
 	"github.com/enzyme/server/internal/config"
 )
@@ -15,6 +17,7 @@ import (
 var templateFS embed.FS
 
 type Service struct {
+	mu        sync.RWMutex // guards sender and enabled, which Reconfigure swaps
 	sender    Sender
 	templates *template.Template
 	publicURL string
@@ -22,31 +25,50 @@ type Service struct {
 }
 
 func NewService(cfg config.EmailConfig, publicURL string) (*Service, error) {
-	var sender Sender
-	if cfg.Enabled {
-		sender = NewSMTPSender(cfg)
-	} else {
-		sender = &NoOpSender{}
-	}
-
 	templates, err := template.ParseFS(templateFS, "templates/*.html", "templates/*.txt")
 	if err != nil {
 		// Templates might not exist yet, create empty template
 		templates = template.New("empty")
 	}
 
-	return &Service{
-		sender:    sender,
+	s := &Service{
 		templates: templates,
 		publicURL: publicURL,
-		enabled:   cfg.Enabled,
-	}, nil
+	}
+	s.Reconfigure(cfg)
+	return s, nil
+}
+
+// Reconfigure switches to new SMTP settings. Emails already being sent
+// finish with the old ones.
+func (s *Service) Reconfigure(cfg config.EmailConfig) {
+	var sender Sender
+	if cfg.Enabled {
+		sender = NewSMTPSender(cfg)
+	} else {
+		sender = &NoOpSender{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sender = sender
+	s.enabled = cfg.Enabled
 }
 
 func (s *Service) IsEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.enabled
 }
 
+// send delivers a message through the current sender.
+func (s *Service) send(ctx context.Context, to, subject, textBody, htmlBody string) error {
+	s.mu.RLock()
+	sender := s.sender
+	s.mu.RUnlock()
+	return sender.Send(ctx, to, subject, textBody, htmlBody)
+}
+
 // NewTestService creates an email service for testing with a NoOpSender.
 // Use enabled=true to test email-enabled code paths without real SMTP.
 func NewTestService(enabled bool, publicURL string) *Service {
@@ -66,22 +88,39 @@ type InviteEmailData struct {
 }
 
 func (s *Service) SendWorkspaceInvite(ctx context.Context, to string, data InviteEmailData) error {
-	if !s.enabled {
+	if !s.IsEnabled() {
 		slog.Debug("would send workspace invite", "component", "email", "to", to, "workspace", data.WorkspaceName)
 		return nil
 	}
 
 	subject := "You've been invited to join " + data.WorkspaceName
-	body := "You've been invited to join " + data.WorkspaceName + " on Enzyme.\n\n"
-	body += "Click here to accept: " + data.InviteURL + "\n"
+	body, htmlBody := s.render("invite", data)
+	if body == "" {
+		body = "You've been invited to join " + data.WorkspaceName + " on Enzyme.\n\n"
+		body += "Click here to accept: " + data.InviteURL + "\n"
+	}
+
+	return s.send(ctx, to, subject, body, htmlBody)
+}
 
-	return s.sender.Send(ctx, to, subject, body, "")
+// render executes the name.txt and name.html templates, returning empty
+// strings for templates that are missing or fail to execute
+func (s *Service) render(name string, data any) (text, html string) {
+	var buf bytes.Buffer
+	if t := s.templates.Lookup(name + ".txt"); t != nil && t.Execute(&buf, data) == nil {
+		text = buf.String()
+	}
+	buf.Reset()
+	if t := s.templates.Lookup(name + ".html"); t != nil && t.Execute(&buf, data) == nil {
+		html = buf.String()
+	}
+	return text, html
 }
 
 func (s *Service) SendPasswordReset(ctx context.Context, to string, token string) error {
 	resetURL := s.publicURL + "/reset-password?" + url.Values{"token": {token}}.Encode()
 
-	if !s.enabled {
+	if !s.IsEnabled() {
 		slog.Debug("would send password reset", "component", "email", "to", to)
 		return nil
 	}
@@ -91,13 +130,13 @@ func (s *Service) SendPasswordReset(ctx context.Context, to string, token string
 	body += "Click here to reset: " + resetURL + "\n\n"
 	body += "If you didn't request this, you can ignore this email.\n"
 
-	return s.sender.Send(ctx, to, subject, body, "")
+	return s.send(ctx, to, subject, body, "")
 }
 
 func (s *Service) SendEmailVerification(ctx context.Context, to string, token string) error {
 	verifyURL := s.publicURL + "/verify-email?" + url.Values{"token": {token}}.Encode()
 
-	if !s.enabled {
+	if !s.IsEnabled() {
 		slog.Debug("would send email verification", "component", "email", "to", to)
 		return nil
 	}
@@ -106,7 +145,7 @@ func (s *Service) SendEmailVerification(ctx context.Context, to string, token st
 	body := "Please verify your email address by clicking the link below:\n\n"
 	body += verifyURL + "\n"
 
-	return s.sender.Send(ctx, to, subject, body, "")
+	return s.send(ctx, to, subject, body, "")
 }
 
 // NotificationDigestItem represents a single notification in a digest
@@ -125,7 +164,7 @@ type NotificationDigestData struct {
 }
 
 func (s *Service) SendNotificationDigest(ctx context.Context, to string, data NotificationDigestData) error {
-	if !s.enabled {
+	if !s.IsEnabled() {
 		slog.Debug("would send notification digest", "component", "email", "to", to, "count", len(data.Items), "workspace", data.WorkspaceName)
 		return nil
 	}
@@ -158,7 +197,7 @@ func (s *Service) SendNotificationDigest(ctx context.Context, to string, data No
 	}
 	body += "\nOpen Enzyme: " + data.WorkspaceURL + "\n"
 
-	return s.sender.Send(ctx, to, subject, body, "")
+	return s.send(ctx, to, subject, body, "")
 }
 
 // GetPublicURL returns the public URL for the service
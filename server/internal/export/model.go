@@ -0,0 +1,119 @@
+package export
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrExportNotFound = errors.New("export not found")
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// Export formats. Workspace exports are ZIP archives; channel exports are a
+// single JSON or CSV file.
+const (
+	FormatZIP  = "zip"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Export is a requested archive of a workspace's channels, messages and
+// attachments, or with ChannelID set, of one channel's messages. The file is
+// built in the background and kept in storage until ExpiresAt.
+type Export struct {
+	ID               string     `json:"id"`
+	WorkspaceID      string     `json:"workspace_id"`
+	ChannelID        *string    `json:"channel_id,omitempty"`
+	Format           string     `json:"format"`
+	RequestedBy      *string    `json:"requested_by,omitempty"`
+	Status           string     `json:"status"`
+	StoragePath      string     `json:"-"`
+	SizeBytes        int64      `json:"size_bytes"`
+	MessagesTotal    int64      `json:"messages_total"`
+	MessagesExported int64      `json:"messages_exported"`
+	LastError        string     `json:"last_error,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Extension returns the file extension of the export's file.
+func (e *Export) Extension() string {
+	return "." + e.Format
+}
+
+// The types below make up the JSON documents inside the archive. Timestamps
+// are copied through as stored (RFC 3339, UTC).
+
+type workspaceDoc struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	CreatedAt  string       `json:"created_at"`
+	ExportedAt string       `json:"exported_at"`
+	Members    []memberDoc  `json:"members"`
+	Channels   []channelDoc `json:"channels"`
+}
+
+type memberDoc struct {
+	UserID      string `json:"user_id"`
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name"`
+	Role        string `json:"role"`
+	JoinedAt    string `json:"joined_at"`
+}
+
+type channelDoc struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Description *string `json:"description,omitempty"`
+	Topic       *string `json:"topic,omitempty"`
+	CreatedBy   *string `json:"created_by,omitempty"`
+	ArchivedAt  *string `json:"archived_at,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	// Path of the channel's JSON file inside the archive; empty in a channel
+	// export
+	Path string `json:"path,omitempty"`
+}
+
+type channelMemberDoc struct {
+	UserID   string  `json:"user_id"`
+	Role     *string `json:"role,omitempty"`
+	JoinedAt string  `json:"joined_at"`
+}
+
+type messageDoc struct {
+	ID              string          `json:"id"`
+	UserID          *string         `json:"user_id,omitempty"`
+	UserDisplayName *string         `json:"user_display_name,omitempty"`
+	Type            string          `json:"type"`
+	Content         string          `json:"content"`
+	ThreadParentID  *string         `json:"thread_parent_id,omitempty"`
+	EditedAt        *string         `json:"edited_at,omitempty"`
+	CreatedAt       string          `json:"created_at"`
+	Reactions       []reactionDoc   `json:"reactions"`
+	Attachments     []attachmentDoc `json:"attachments"`
+}
+
+type reactionDoc struct {
+	UserID string `json:"user_id"`
+	Emoji  string `json:"emoji"`
+}
+
+type attachmentDoc struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	// Path of the file inside the archive; empty if it could not be read
+	// from storage.
+	Path string `json:"path,omitempty"`
+
+	storagePath string
+}
@@ -0,0 +1,481 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+const exportColumns = `id, workspace_id, channel_id, format, requested_by, status, storage_path, size_bytes, messages_total, messages_exported, last_error, expires_at, completed_at, created_at, updated_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create queues a new export.
+func (r *Repository) Create(ctx context.Context, e *Export) error {
+	e.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	e.Status = StatusPending
+	if e.Format == "" {
+		e.Format = FormatZIP
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO workspace_exports (id, workspace_id, channel_id, format, requested_by, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.ID, e.WorkspaceID, e.ChannelID, e.Format, e.RequestedBy, e.Status, now.Format(time.RFC3339), now.Format(time.RFC3339))
+	return err
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*Export, error) {
+	return scanExport(r.db.QueryRowContext(ctx, `
+		SELECT `+exportColumns+` FROM workspace_exports WHERE id = ?
+	`, id))
+}
+
+// HasActive reports whether the workspace has a full export that is queued
+// or being built. Channel exports are not counted.
+func (r *Repository) HasActive(ctx context.Context, workspaceID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM workspace_exports WHERE workspace_id = ? AND channel_id IS NULL AND status IN (?, ?))
+	`, workspaceID, StatusPending, StatusRunning).Scan(&exists)
+	return exists, err
+}
+
+// HasActiveForChannel reports whether the channel has an export that is
+// queued or being built.
+func (r *Repository) HasActiveForChannel(ctx context.Context, channelID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM workspace_exports WHERE channel_id = ? AND status IN (?, ?))
+	`, channelID, StatusPending, StatusRunning).Scan(&exists)
+	return exists, err
+}
+
+// ListPending returns queued exports, oldest first.
+func (r *Repository) ListPending(ctx context.Context) ([]Export, error) {
+	return r.list(ctx, `
+		SELECT `+exportColumns+` FROM workspace_exports WHERE status = ? ORDER BY created_at, id
+	`, StatusPending)
+}
+
+// ListExpired returns completed exports whose archive should be deleted.
+func (r *Repository) ListExpired(ctx context.Context, now time.Time) ([]Export, error) {
+	return r.list(ctx, `
+		SELECT `+exportColumns+` FROM workspace_exports WHERE status = ? AND expires_at < ?
+	`, StatusCompleted, now.UTC().Format(time.RFC3339))
+}
+
+func (r *Repository) list(ctx context.Context, query string, args ...any) ([]Export, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []Export
+	for rows.Next() {
+		e, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, *e)
+	}
+	return exports, rows.Err()
+}
+
+// MarkRunning atomically claims a pending export. Returns true if the row
+// was claimed, false if another worker got it first.
+func (r *Repository) MarkRunning(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_exports SET status = ?, updated_at = ? WHERE id = ? AND status = ?
+	`, StatusRunning, time.Now().UTC().Format(time.RFC3339), id, StatusPending)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// SetProgress records how many of the export's messages have been written
+// out of total.
+func (r *Repository) SetProgress(ctx context.Context, id string, exported, total int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_exports SET messages_exported = ?, messages_total = ?, updated_at = ? WHERE id = ?
+	`, exported, total, time.Now().UTC().Format(time.RFC3339), id)
+	return err
+}
+
+func (r *Repository) MarkCompleted(ctx context.Context, id, storagePath string, size int64, expiresAt time.Time) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_exports
+		SET status = ?, storage_path = ?, size_bytes = ?, expires_at = ?, completed_at = ?, last_error = NULL, updated_at = ?
+		WHERE id = ?
+	`, StatusCompleted, storagePath, size, expiresAt.UTC().Format(time.RFC3339), now, now, id)
+	return err
+}
+
+func (r *Repository) MarkFailed(ctx context.Context, id, lastError string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_exports SET status = ?, last_error = ?, updated_at = ? WHERE id = ?
+	`, StatusFailed, lastError, time.Now().UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// FailRunning marks every running export as failed. Used on startup, when
+// any export still running was interrupted by a restart.
+func (r *Repository) FailRunning(ctx context.Context, lastError string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_exports SET status = ?, last_error = ?, updated_at = ? WHERE status = ?
+	`, StatusFailed, lastError, time.Now().UTC().Format(time.RFC3339), StatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM workspace_exports WHERE id = ?`, id)
+	return err
+}
+
+func scanExport(row interface{ Scan(dest ...any) error }) (*Export, error) {
+	var e Export
+	var channelID, requestedBy, storagePath, lastError, expiresAt, completedAt sql.NullString
+	var createdAt, updatedAt string
+
+	err := row.Scan(&e.ID, &e.WorkspaceID, &channelID, &e.Format, &requestedBy, &e.Status, &storagePath, &e.SizeBytes,
+		&e.MessagesTotal, &e.MessagesExported, &lastError, &expiresAt, &completedAt, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.ChannelID = nullStringPtr(channelID)
+	if requestedBy.Valid {
+		e.RequestedBy = &requestedBy.String
+	}
+	e.StoragePath = storagePath.String
+	e.LastError = lastError.String
+	if expiresAt.Valid {
+		t, _ := time.Parse(time.RFC3339, expiresAt.String)
+		e.ExpiresAt = &t
+	}
+	if completedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, completedAt.String)
+		e.CompletedAt = &t
+	}
+	e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	e.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &e, nil
+}
+
+// The queries below read the workspace contents that go into an archive.
+
+func (r *Repository) getWorkspace(ctx context.Context, workspaceID string) (*workspaceDoc, error) {
+	var doc workspaceDoc
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, name, created_at FROM workspaces WHERE id = ?
+	`, workspaceID).Scan(&doc.ID, &doc.Name, &doc.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *Repository) listMembers(ctx context.Context, workspaceID string) ([]memberDoc, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT wm.user_id, u.email, u.display_name, wm.role, wm.created_at
+		FROM workspace_memberships wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = ?
+		ORDER BY wm.created_at, wm.user_id
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []memberDoc{}
+	for rows.Next() {
+		var m memberDoc
+		if err := rows.Scan(&m.UserID, &m.Email, &m.DisplayName, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func (r *Repository) listChannels(ctx context.Context, workspaceID string) ([]channelDoc, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, type, description, topic, created_by, archived_at, created_at
+		FROM channels WHERE workspace_id = ?
+		ORDER BY created_at, id
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []channelDoc
+	for rows.Next() {
+		var c channelDoc
+		var description, topic, createdBy, archivedAt sql.NullString
+		if err := rows.Scan(&c.ID, &c.Name, &c.Type, &description, &topic, &createdBy, &archivedAt, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.Description = nullStringPtr(description)
+		c.Topic = nullStringPtr(topic)
+		c.CreatedBy = nullStringPtr(createdBy)
+		c.ArchivedAt = nullStringPtr(archivedAt)
+		channels = append(channels, c)
+	}
+	return channels, rows.Err()
+}
+
+func (r *Repository) getChannel(ctx context.Context, channelID string) (*channelDoc, error) {
+	var c channelDoc
+	var description, topic, createdBy, archivedAt sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, name, type, description, topic, created_by, archived_at, created_at
+		FROM channels WHERE id = ?
+	`, channelID).Scan(&c.ID, &c.Name, &c.Type, &description, &topic, &createdBy, &archivedAt, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Description = nullStringPtr(description)
+	c.Topic = nullStringPtr(topic)
+	c.CreatedBy = nullStringPtr(createdBy)
+	c.ArchivedAt = nullStringPtr(archivedAt)
+	return &c, nil
+}
+
+// countMessages counts the live messages, thread replies and archived ones
+// included, that an export will write: those of one channel, or with
+// channelID nil, of every channel in the workspace.
+func (r *Repository) countMessages(ctx context.Context, workspaceID string, channelID *string) (int64, error) {
+	var total int64
+	for _, table := range []string{"messages", "messages_archive"} {
+		var n int64
+		err := r.db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM `+table+` m
+			JOIN channels c ON c.id = m.channel_id
+			WHERE c.workspace_id = ? AND (? IS NULL OR m.channel_id = ?) AND m.deleted_at IS NULL
+		`, workspaceID, channelID, channelID).Scan(&n)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (r *Repository) listChannelMembers(ctx context.Context, channelID string) ([]channelMemberDoc, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, channel_role, created_at FROM channel_memberships
+		WHERE channel_id = ?
+		ORDER BY created_at, user_id
+	`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []channelMemberDoc{}
+	for rows.Next() {
+		var m channelMemberDoc
+		var role sql.NullString
+		if err := rows.Scan(&m.UserID, &role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		m.Role = nullStringPtr(role)
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// listMessages returns up to limit messages of a channel, thread replies
+// and archived messages included, with IDs greater than afterID, each with
+// its author's display name. Deleted messages are skipped.
+// Reactions and attachments are loaded for every returned message.
+func (r *Repository) listMessages(ctx context.Context, channelID, afterID string, limit int) (_ []messageDoc, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "export.listMessages")
+	defer func() { endSpan(err) }()
+
+	messages, err := r.queryMessages(ctx, "messages", channelID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := r.queryMessages(ctx, "messages_archive", channelID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) > 0 {
+		messages = append(messages, archived...)
+		slices.SortFunc(messages, func(a, b messageDoc) int { return strings.Compare(a.ID, b.ID) })
+		if len(messages) > limit {
+			messages = messages[:limit]
+		}
+	}
+
+	if len(messages) == 0 {
+		return messages, nil
+	}
+
+	byID := make(map[string]*messageDoc, len(messages))
+	placeholders := make([]string, len(messages))
+	args := make([]any, len(messages))
+	for i := range messages {
+		byID[messages[i].ID] = &messages[i]
+		placeholders[i] = "?"
+		args[i] = messages[i].ID
+	}
+	in := strings.Join(placeholders, ",")
+
+	reactionRows, err := r.db.QueryContext(ctx, `
+		SELECT message_id, user_id, emoji, created_at, id FROM reactions
+		WHERE message_id IN (`+in+`)
+		UNION ALL
+		SELECT message_id, user_id, emoji, created_at, id FROM reactions_archive
+		WHERE message_id IN (`+in+`)
+		ORDER BY created_at, id
+	`, append(args, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer reactionRows.Close()
+	for reactionRows.Next() {
+		var messageID string
+		var rd reactionDoc
+		var createdAt, id string
+		if err := reactionRows.Scan(&messageID, &rd.UserID, &rd.Emoji, &createdAt, &id); err != nil {
+			return nil, err
+		}
+		if m, ok := byID[messageID]; ok {
+			m.Reactions = append(m.Reactions, rd)
+		}
+	}
+	if err := reactionRows.Err(); err != nil {
+		return nil, err
+	}
+	reactionRows.Close()
+
+	attachmentRows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(message_id, archived_message_id), id, filename, content_type, size_bytes, storage_path FROM attachments
+		WHERE message_id IN (`+in+`) OR archived_message_id IN (`+in+`)
+		ORDER BY created_at, id
+	`, append(args, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer attachmentRows.Close()
+	for attachmentRows.Next() {
+		var messageID string
+		var a attachmentDoc
+		if err := attachmentRows.Scan(&messageID, &a.ID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.storagePath); err != nil {
+			return nil, err
+		}
+		if m, ok := byID[messageID]; ok {
+			m.Attachments = append(m.Attachments, a)
+		}
+	}
+	return messages, attachmentRows.Err()
+}
+
+// queryMessages returns up to limit undeleted messages of a channel in
+// table, with IDs greater than afterID, without reactions or attachments.
+func (r *Repository) queryMessages(ctx context.Context, table, channelID, afterID string, limit int) ([]messageDoc, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.user_id, u.display_name, m.type, m.content, m.system_event, m.thread_parent_id, m.edited_at, m.created_at
+		FROM `+table+` m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE m.channel_id = ? AND m.id > ? AND m.deleted_at IS NULL
+		ORDER BY m.id
+		LIMIT ?
+	`, channelID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []messageDoc
+	for rows.Next() {
+		var m messageDoc
+		var userID, userDisplayName, systemEvent, threadParentID, editedAt sql.NullString
+		if err := rows.Scan(&m.ID, &userID, &userDisplayName, &m.Type, &m.Content, &systemEvent, &threadParentID, &editedAt, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		// System messages store only their event; archives get its text in
+		// the default language
+		if systemEvent.Valid {
+			m.Content = message.SystemText(i18n.Default, m.Content, systemEvent.String)
+		}
+		m.UserID = nullStringPtr(userID)
+		m.UserDisplayName = nullStringPtr(userDisplayName)
+		m.ThreadParentID = nullStringPtr(threadParentID)
+		m.EditedAt = nullStringPtr(editedAt)
+		m.Reactions = []reactionDoc{}
+		m.Attachments = []attachmentDoc{}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// listChannelAttachments returns up to limit attachments linked to live
+// messages in a channel, archived ones included, with IDs greater than
+// afterID.
+func (r *Repository) listChannelAttachments(ctx context.Context, channelID, afterID string, limit int) ([]attachmentDoc, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.filename, a.content_type, a.size_bytes, a.storage_path
+		FROM attachments a
+		LEFT JOIN messages m ON m.id = a.message_id
+		LEFT JOIN messages_archive ma ON ma.id = a.archived_message_id
+		WHERE a.channel_id = ? AND a.id > ? AND COALESCE(m.id, ma.id) IS NOT NULL
+		  AND COALESCE(m.deleted_at, ma.deleted_at) IS NULL
+		ORDER BY a.id
+		LIMIT ?
+	`, channelID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []attachmentDoc
+	for rows.Next() {
+		var a attachmentDoc
+		if err := rows.Scan(&a.ID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.storagePath); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
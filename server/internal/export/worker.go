@@ -0,0 +1,462 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/storage"
+)
+
+const (
+	// ArchiveTTL is how long a finished archive is kept before it is deleted.
+	ArchiveTTL = 7 * 24 * time.Hour
+
+	// messageBatchSize bounds how many messages are held in memory while a
+	// channel is written out.
+	messageBatchSize = 500
+)
+
+// Worker builds queued exports. Files are written to a temporary file and
+// then copied into storage under exports/<workspace>/<export>.<format>.
+type Worker struct {
+	repo      *Repository
+	store     storage.Storage
+	recovered bool
+}
+
+// NewWorker creates a new export worker.
+func NewWorker(repo *Repository, store storage.Storage) *Worker {
+	return &Worker{
+		repo:  repo,
+		store: store,
+	}
+}
+
+// ProcessPending builds every queued export, one at a time, and deletes
+// archives that have expired. On its first run it fails exports left
+// running by a previous process.
+func (w *Worker) ProcessPending(ctx context.Context) error {
+	if !w.recovered {
+		n, err := w.repo.FailRunning(ctx, "interrupted by server restart")
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slog.Warn("failed exports interrupted by restart", "component", "export", "count", n)
+		}
+		w.recovered = true
+	}
+
+	if err := w.deleteExpired(ctx); err != nil {
+		slog.Error("failed to delete expired exports", "component", "export", "error", err)
+	}
+
+	exports, err := w.repo.ListPending(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range exports {
+		if err := w.run(ctx, &exports[i]); err != nil {
+			slog.Error("failed to build export",
+				"component", "export",
+				"id", exports[i].ID,
+				"workspace_id", exports[i].WorkspaceID,
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// run claims an export, builds its archive and records the outcome. It is a
+// no-op if the export was already claimed.
+func (w *Worker) run(ctx context.Context, e *Export) error {
+	claimed, err := w.repo.MarkRunning(ctx, e.ID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	key := "exports/" + e.WorkspaceID + "/" + e.ID + e.Extension()
+	size, err := w.build(ctx, e, key)
+	if err != nil {
+		if markErr := w.repo.MarkFailed(ctx, e.ID, err.Error()); markErr != nil {
+			slog.Error("failed to mark export as failed", "component", "export", "id", e.ID, "error", markErr)
+		}
+		return err
+	}
+
+	slog.Info("export completed", "component", "export", "id", e.ID, "workspace_id", e.WorkspaceID, "channel_id", e.ChannelID, "size_bytes", size)
+	return w.repo.MarkCompleted(ctx, e.ID, key, size, time.Now().Add(ArchiveTTL))
+}
+
+// build writes the export's file to a temporary file and stores it under
+// key, returning its size.
+func (w *Worker) build(ctx context.Context, e *Export, key string) (int64, error) {
+	total, err := w.repo.countMessages(ctx, e.WorkspaceID, e.ChannelID)
+	if err != nil {
+		return 0, err
+	}
+	p := &progress{repo: w.repo, id: e.ID, total: total}
+	if err := p.add(ctx, 0); err != nil {
+		return 0, err
+	}
+
+	f, err := os.CreateTemp("", "enzyme-export-*"+e.Extension())
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	var contentType string
+	switch {
+	case e.ChannelID == nil:
+		contentType = "application/zip"
+		err = w.writeArchive(ctx, f, e.WorkspaceID, p)
+	case e.Format == FormatJSON:
+		contentType = "application/json"
+		err = w.writeChannelJSON(ctx, f, *e.ChannelID, p)
+	case e.Format == FormatCSV:
+		contentType = "text/csv"
+		err = w.writeChannelCSV(ctx, f, *e.ChannelID, p)
+	default:
+		err = fmt.Errorf("unsupported channel export format %q", e.Format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := w.store.Put(ctx, key, f, size, contentType); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func (w *Worker) deleteExpired(ctx context.Context) error {
+	exports, err := w.repo.ListExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, e := range exports {
+		if err := w.store.Delete(ctx, e.StoragePath); err != nil {
+			return err
+		}
+		if err := w.repo.Delete(ctx, e.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArchive writes a ZIP of the workspace to dst:
+//
+//	workspace.json                  workspace, members and channel index
+//	channels/<channel id>.json      channel, its members, and its messages with reactions and attachments
+//	attachments/<id>/<filename>     attachment contents
+//
+// Every channel is included, private channels and direct messages too.
+func (w *Worker) writeArchive(ctx context.Context, dst io.Writer, workspaceID string, p *progress) error {
+	zw := zip.NewWriter(dst)
+
+	ws, err := w.repo.getWorkspace(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	ws.ExportedAt = time.Now().UTC().Format(time.RFC3339)
+	if ws.Members, err = w.repo.listMembers(ctx, workspaceID); err != nil {
+		return err
+	}
+	if ws.Channels, err = w.repo.listChannels(ctx, workspaceID); err != nil {
+		return err
+	}
+	for i := range ws.Channels {
+		ws.Channels[i].Path = "channels/" + ws.Channels[i].ID + ".json"
+	}
+	if ws.Channels == nil {
+		ws.Channels = []channelDoc{}
+	}
+
+	fw, err := zw.Create("workspace.json")
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(fw).Encode(ws); err != nil {
+		return err
+	}
+
+	for i := range ws.Channels {
+		if err := w.writeChannel(ctx, zw, &ws.Channels[i], p); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeChannel writes a channel's attachments followed by its JSON file.
+func (w *Worker) writeChannel(ctx context.Context, zw *zip.Writer, ch *channelDoc, p *progress) error {
+	written, err := w.writeAttachments(ctx, zw, ch.ID)
+	if err != nil {
+		return err
+	}
+
+	fw, err := zw.Create(ch.Path)
+	if err != nil {
+		return err
+	}
+	return w.writeChannelDoc(ctx, fw, ch, written, p)
+}
+
+// writeChannelDoc writes a channel's JSON document: the channel, its members,
+// and its messages with reactions and attachments. written maps attachment
+// IDs to their path in the archive. Messages are streamed a batch at a time
+// so large channels are never held in memory at once.
+func (w *Worker) writeChannelDoc(ctx context.Context, dst io.Writer, ch *channelDoc, written map[string]string, p *progress) error {
+	members, err := w.repo.listChannelMembers(ctx, ch.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := writeRaw(dst, `{"channel":`, ch, `,"members":`, members, `,"messages":[`); err != nil {
+		return err
+	}
+
+	first := true
+	err = w.eachMessageBatch(ctx, ch.ID, p, func(messages []messageDoc) error {
+		for i := range messages {
+			m := &messages[i]
+			for j := range m.Attachments {
+				m.Attachments[j].Path = written[m.Attachments[j].ID]
+			}
+			sep := ","
+			if first {
+				sep = ""
+				first = false
+			}
+			if err := writeRaw(dst, sep, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(dst, "]}\n")
+	return err
+}
+
+// writeChannelJSON writes a channel export as a single JSON document, in the
+// same shape as a channel file in a workspace archive. Attachments are
+// described but their contents are left out.
+func (w *Worker) writeChannelJSON(ctx context.Context, dst io.Writer, channelID string, p *progress) error {
+	ch, err := w.repo.getChannel(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	return w.writeChannelDoc(ctx, dst, ch, nil, p)
+}
+
+// csvHeader names the columns of a CSV channel export. Reactions are listed
+// as emoji:count and attachments by filename, both separated by "; ".
+var csvHeader = []string{"id", "created_at", "edited_at", "user_id", "user_display_name", "type", "thread_parent_id", "content", "reactions", "attachments"}
+
+// writeChannelCSV writes a channel export as CSV, one row per message in the
+// order they were posted. Thread replies are rows like any other, linked to
+// their parent by thread_parent_id.
+func (w *Worker) writeChannelCSV(ctx context.Context, dst io.Writer, channelID string, p *progress) error {
+	cw := csv.NewWriter(dst)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := w.eachMessageBatch(ctx, channelID, p, func(messages []messageDoc) error {
+		for i := range messages {
+			if err := cw.Write(csvRecord(&messages[i])); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvRecord(m *messageDoc) []string {
+	var reactions []string
+	counts := make(map[string]int)
+	for _, r := range m.Reactions {
+		if counts[r.Emoji] == 0 {
+			reactions = append(reactions, r.Emoji)
+		}
+		counts[r.Emoji]++
+	}
+	for i, emoji := range reactions {
+		reactions[i] = emoji + ":" + strconv.Itoa(counts[emoji])
+	}
+
+	attachments := make([]string, len(m.Attachments))
+	for i, a := range m.Attachments {
+		attachments[i] = a.Filename
+	}
+
+	return []string{
+		m.ID,
+		m.CreatedAt,
+		derefString(m.EditedAt),
+		derefString(m.UserID),
+		csvText(derefString(m.UserDisplayName)),
+		m.Type,
+		derefString(m.ThreadParentID),
+		csvText(m.Content),
+		csvText(strings.Join(reactions, "; ")),
+		csvText(strings.Join(attachments, "; ")),
+	}
+}
+
+// csvText guards user-written text against being run as a formula when the
+// file is opened in a spreadsheet, by quoting cells that start with a
+// formula character.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// eachMessageBatch calls fn with a channel's live messages a batch at a time,
+// oldest first, recording progress after each batch.
+func (w *Worker) eachMessageBatch(ctx context.Context, channelID string, p *progress, fn func([]messageDoc) error) error {
+	afterID := ""
+	for {
+		messages, err := w.repo.listMessages(ctx, channelID, afterID, messageBatchSize)
+		if err != nil {
+			return err
+		}
+		if err := fn(messages); err != nil {
+			return err
+		}
+		if err := p.add(ctx, len(messages)); err != nil {
+			return err
+		}
+		if len(messages) < messageBatchSize {
+			return nil
+		}
+		afterID = messages[len(messages)-1].ID
+	}
+}
+
+// progress counts the messages an export has written and saves the count so
+// it can be polled while the export runs.
+type progress struct {
+	repo  *Repository
+	id    string
+	done  int64
+	total int64
+}
+
+func (p *progress) add(ctx context.Context, n int) error {
+	p.done += int64(n)
+	return p.repo.SetProgress(ctx, p.id, p.done, p.total)
+}
+
+// writeAttachments copies the files attached to a channel's messages into
+// the archive and returns the archive path of each, keyed by attachment ID.
+// Files that cannot be read from storage are logged and left out.
+func (w *Worker) writeAttachments(ctx context.Context, zw *zip.Writer, channelID string) (map[string]string, error) {
+	written := make(map[string]string)
+	afterID := ""
+	for {
+		attachments, err := w.repo.listChannelAttachments(ctx, channelID, afterID, messageBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attachments {
+			name := path.Base(a.Filename)
+			if name == "." || name == "/" {
+				name = a.ID
+			}
+			archivePath := "attachments/" + a.ID + "/" + name
+
+			ok, err := w.copyAttachment(ctx, zw, a.storagePath, archivePath)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				written[a.ID] = archivePath
+			}
+		}
+		if len(attachments) < messageBatchSize {
+			return written, nil
+		}
+		afterID = attachments[len(attachments)-1].ID
+	}
+}
+
+// copyAttachment reports false without an error when the stored file is
+// missing, so one lost file does not fail the whole export.
+func (w *Worker) copyAttachment(ctx context.Context, zw *zip.Writer, storagePath, archivePath string) (bool, error) {
+	rc, err := w.store.Get(ctx, storagePath)
+	if err != nil {
+		slog.Warn("skipping unreadable attachment in export", "component", "export", "key", storagePath, "error", err)
+		return false, nil
+	}
+	defer rc.Close()
+
+	fw, err := zw.Create(archivePath)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(fw, rc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeRaw writes strings as-is and JSON-encodes everything else.
+func writeRaw(w io.Writer, parts ...any) error {
+	for _, p := range parts {
+		var b []byte
+		if s, ok := p.(string); ok {
+			b = []byte(s)
+		} else {
+			var err error
+			if b, err = json.Marshal(p); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
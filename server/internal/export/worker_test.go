@@ -0,0 +1,295 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/oklog/ulid/v2"
+)
+
+func readArchive(t *testing.T, store storage.Storage, key string) map[string][]byte {
+	t.Helper()
+	rc, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("opening archive: %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+	return files
+}
+
+func TestProcessPending_BuildsArchive(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	store := storage.NewLocal(t.TempDir())
+	w := NewWorker(repo, store)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Export Co")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", "private")
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "hello")
+	deleted := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "oops")
+
+	messages := message.NewRepository(db)
+	reply := &message.Message{ChannelID: ch.ID, UserID: &owner.ID, Content: "a reply", ThreadParentID: &parent.ID}
+	if err := messages.Create(ctx, reply); err != nil {
+		t.Fatalf("creating reply: %v", err)
+	}
+	if _, err := messages.AddReaction(ctx, parent.ID, owner.ID, "tada"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	if err := messages.Delete(ctx, deleted.ID, owner.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	attachmentID := ulid.Make().String()
+	if err := store.Put(ctx, "files/"+attachmentID, bytes.NewReader([]byte("report")), 6, "text/plain"); err != nil {
+		t.Fatalf("storing attachment: %v", err)
+	}
+	_, err := db.Exec(`
+		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at)
+		VALUES (?, ?, ?, ?, 'report.txt', 'text/plain', 6, ?, ?)
+	`, attachmentID, parent.ID, ch.ID, owner.ID, "files/"+attachmentID, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("inserting attachment: %v", err)
+	}
+
+	e := &Export{WorkspaceID: ws.ID, RequestedBy: &owner.ID}
+	if err := repo.Create(ctx, e); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := w.ProcessPending(ctx); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+
+	e, err = repo.GetByID(ctx, e.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if e.Status != StatusCompleted || e.SizeBytes == 0 || e.ExpiresAt == nil {
+		t.Fatalf("expected completed export with size and expiry, got %+v", e)
+	}
+
+	files := readArchive(t, store, e.StoragePath)
+
+	var wsDoc workspaceDoc
+	if err := json.Unmarshal(files["workspace.json"], &wsDoc); err != nil {
+		t.Fatalf("decoding workspace.json: %v", err)
+	}
+	if wsDoc.Name != "Export Co" || len(wsDoc.Members) != 1 || wsDoc.Members[0].Email != "owner@example.com" {
+		t.Errorf("unexpected workspace document: %+v", wsDoc)
+	}
+
+	var chDoc struct {
+		Channel  channelDoc         `json:"channel"`
+		Members  []channelMemberDoc `json:"members"`
+		Messages []messageDoc       `json:"messages"`
+	}
+	channelFile, ok := files["channels/"+ch.ID+".json"]
+	if !ok {
+		t.Fatalf("expected private channel file in archive, got %v", len(files))
+	}
+	if err := json.Unmarshal(channelFile, &chDoc); err != nil {
+		t.Fatalf("decoding channel file: %v", err)
+	}
+	if chDoc.Channel.Name != "secret" || len(chDoc.Members) != 1 {
+		t.Errorf("unexpected channel document: %+v", chDoc.Channel)
+	}
+	if len(chDoc.Messages) != 2 {
+		t.Fatalf("expected parent and reply without the deleted message, got %d messages", len(chDoc.Messages))
+	}
+	first := chDoc.Messages[0]
+	if first.ID != parent.ID || len(first.Reactions) != 1 || first.Reactions[0].Emoji != "tada" {
+		t.Errorf("expected parent message with its reaction first, got %+v", first)
+	}
+	if len(first.Attachments) != 1 || first.Attachments[0].Path != "attachments/"+attachmentID+"/report.txt" {
+		t.Fatalf("expected attachment with archive path, got %+v", first.Attachments)
+	}
+	if chDoc.Messages[1].ThreadParentID == nil || *chDoc.Messages[1].ThreadParentID != parent.ID {
+		t.Errorf("expected thread reply to reference its parent, got %+v", chDoc.Messages[1])
+	}
+	if got := string(files[first.Attachments[0].Path]); got != "report" {
+		t.Errorf("expected attachment contents in archive, got %q", got)
+	}
+}
+
+func TestProcessPending_RecoversAndExpires(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	store := storage.NewLocal(t.TempDir())
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Export Co")
+
+	// An export left running by a previous process
+	interrupted := &Export{WorkspaceID: ws.ID}
+	if err := repo.Create(ctx, interrupted); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := repo.MarkRunning(ctx, interrupted.ID); err != nil {
+		t.Fatalf("MarkRunning: %v", err)
+	}
+
+	// A finished export past its expiry
+	expired := &Export{WorkspaceID: ws.ID}
+	if err := repo.Create(ctx, expired); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	key := "exports/" + ws.ID + "/" + expired.ID + ".zip"
+	if err := store.Put(ctx, key, bytes.NewReader([]byte("zip")), 3, "application/zip"); err != nil {
+		t.Fatalf("storing archive: %v", err)
+	}
+	if err := repo.MarkCompleted(ctx, expired.ID, key, 3, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("MarkCompleted: %v", err)
+	}
+
+	if err := NewWorker(repo, store).ProcessPending(ctx); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, interrupted.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != StatusFailed || got.LastError == "" {
+		t.Errorf("expected interrupted export to be failed with a reason, got %+v", got)
+	}
+
+	if _, err := repo.GetByID(ctx, expired.ID); err != ErrExportNotFound {
+		t.Errorf("expected expired export to be deleted, got %v", err)
+	}
+	if _, err := store.Get(ctx, key); err == nil {
+		t.Error("expected expired archive to be removed from storage")
+	}
+}
+
+func TestProcessPending_ChannelExport(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	store := storage.NewLocal(t.TempDir())
+	w := NewWorker(repo, store)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Export Co")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	other := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "random", "public")
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "=SUM(A1:A2)")
+	testutil.CreateTestMessage(t, db, other.ID, owner.ID, "elsewhere")
+
+	messages := message.NewRepository(db)
+	reply := &message.Message{ChannelID: ch.ID, UserID: &owner.ID, Content: "a reply", ThreadParentID: &parent.ID}
+	if err := messages.Create(ctx, reply); err != nil {
+		t.Fatalf("creating reply: %v", err)
+	}
+	if _, err := messages.AddReaction(ctx, parent.ID, owner.ID, "tada"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+
+	jsonExport := &Export{WorkspaceID: ws.ID, ChannelID: &ch.ID, Format: FormatJSON, RequestedBy: &owner.ID}
+	csvExport := &Export{WorkspaceID: ws.ID, ChannelID: &ch.ID, Format: FormatCSV, RequestedBy: &owner.ID}
+	for _, e := range []*Export{jsonExport, csvExport} {
+		if err := repo.Create(ctx, e); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := w.ProcessPending(ctx); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+
+	read := func(e *Export) (*Export, []byte) {
+		t.Helper()
+		e, err := repo.GetByID(ctx, e.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if e.Status != StatusCompleted || e.MessagesTotal != 2 || e.MessagesExported != 2 {
+			t.Fatalf("expected completed export of 2 messages, got %+v", e)
+		}
+		rc, err := store.Get(ctx, e.StoragePath)
+		if err != nil {
+			t.Fatalf("reading export: %v", err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("reading export: %v", err)
+		}
+		return e, data
+	}
+
+	e, data := read(jsonExport)
+	if path.Ext(e.StoragePath) != ".json" {
+		t.Errorf("expected a .json file, got %s", e.StoragePath)
+	}
+	var chDoc struct {
+		Channel  channelDoc   `json:"channel"`
+		Messages []messageDoc `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &chDoc); err != nil {
+		t.Fatalf("decoding channel export: %v", err)
+	}
+	if chDoc.Channel.Name != "general" || len(chDoc.Messages) != 2 {
+		t.Fatalf("expected general with 2 messages, got %s with %d", chDoc.Channel.Name, len(chDoc.Messages))
+	}
+	first := chDoc.Messages[0]
+	if first.UserDisplayName == nil || *first.UserDisplayName != "Owner" || len(first.Reactions) != 1 {
+		t.Errorf("expected the parent with its author and reaction, got %+v", first)
+	}
+
+	_, data = read(csvExport)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("parsing CSV: %v", err)
+	}
+	if len(records) != 3 || !slices.Equal(records[0], csvHeader) {
+		t.Fatalf("expected a header and 2 rows, got %v", records)
+	}
+	row := make(map[string]string)
+	for i, name := range csvHeader {
+		row[name] = records[1][i]
+	}
+	if row["id"] != parent.ID || row["user_display_name"] != "Owner" || row["reactions"] != "tada:1" {
+		t.Errorf("unexpected parent row: %v", row)
+	}
+	if row["content"] != "'=SUM(A1:A2)" {
+		t.Errorf("expected formula-like content to be quoted, got %q", row["content"])
+	}
+	if records[2][6] != parent.ID {
+		t.Errorf("expected the reply to reference its parent, got %v", records[2])
+	}
+}
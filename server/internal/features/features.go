@@ -0,0 +1,107 @@
+package features
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"sync/atomic"
+)
+
+// Service resolves feature flags. A workspace override wins over the server
+// config, which wins over the registry default.
+type Service struct {
+	repo   *Repository
+	server atomic.Pointer[map[string]bool]
+}
+
+// NewService creates a service with the flags set in the server config
+func NewService(repo *Repository, server map[string]bool) *Service {
+	s := &Service{repo: repo}
+	s.SetServerFlags(server)
+	return s
+}
+
+// SetServerFlags replaces the flags set in the server config. Called on a
+// config reload.
+func (s *Service) SetServerFlags(flags map[string]bool) {
+	flags = maps.Clone(flags)
+	s.server.Store(&flags)
+}
+
+// Enabled reports whether a flag is on in a workspace. Unknown flags are off.
+// If the workspace's overrides can't be read the server value is used, so a
+// database hiccup doesn't flip behavior for everyone. A nil Service reports
+// the registry defaults.
+func (s *Service) Enabled(ctx context.Context, workspaceID, key string) bool {
+	def, ok := Lookup(key)
+	if !ok {
+		return false
+	}
+	if s == nil {
+		return def.Default
+	}
+	overrides, err := s.repo.ListOverrides(ctx, workspaceID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load workspace feature flags", "workspace_id", workspaceID, "error", err)
+		overrides = nil
+	}
+	return s.resolve(def, overrides).Enabled
+}
+
+// List returns every known flag as it applies to a workspace
+func (s *Service) List(ctx context.Context, workspaceID string) ([]Flag, error) {
+	overrides, err := s.repo.ListOverrides(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]Flag, 0, len(Registry))
+	for _, def := range Registry {
+		flags = append(flags, s.resolve(def, overrides))
+	}
+	return flags, nil
+}
+
+// Get returns one flag as it applies to a workspace
+func (s *Service) Get(ctx context.Context, workspaceID, key string) (Flag, error) {
+	def, ok := Lookup(key)
+	if !ok {
+		return Flag{}, ErrUnknownFlag
+	}
+	overrides, err := s.repo.ListOverrides(ctx, workspaceID)
+	if err != nil {
+		return Flag{}, err
+	}
+	return s.resolve(def, overrides), nil
+}
+
+// Set overrides a flag for a workspace and returns its new value
+func (s *Service) Set(ctx context.Context, workspaceID, key string, enabled bool) (Flag, error) {
+	if _, ok := Lookup(key); !ok {
+		return Flag{}, ErrUnknownFlag
+	}
+	if err := s.repo.SetOverride(ctx, workspaceID, key, enabled); err != nil {
+		return Flag{}, err
+	}
+	return s.Get(ctx, workspaceID, key)
+}
+
+// Reset removes a workspace's override and returns the value it falls back to
+func (s *Service) Reset(ctx context.Context, workspaceID, key string) (Flag, error) {
+	if _, ok := Lookup(key); !ok {
+		return Flag{}, ErrUnknownFlag
+	}
+	if err := s.repo.DeleteOverride(ctx, workspaceID, key); err != nil {
+		return Flag{}, err
+	}
+	return s.Get(ctx, workspaceID, key)
+}
+
+func (s *Service) resolve(def Definition, overrides map[string]bool) Flag {
+	if enabled, ok := overrides[def.Key]; ok {
+		return Flag{Definition: def, Enabled: enabled, Source: SourceWorkspace}
+	}
+	if enabled, ok := (*s.server.Load())[def.Key]; ok {
+		return Flag{Definition: def, Enabled: enabled, Source: SourceServer}
+	}
+	return Flag{Definition: def, Enabled: def.Default, Source: SourceDefault}
+}
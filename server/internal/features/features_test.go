@@ -0,0 +1,90 @@
+package features
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestService(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	s := NewService(NewRepository(db), map[string]bool{ThreadsV2: true})
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	other := testutil.CreateTestWorkspace(t, db, owner.ID, "Other")
+
+	check := func(workspaceID, key string, enabled bool, source Source) {
+		t.Helper()
+		flag, err := s.Get(ctx, workspaceID, key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", key, err)
+		}
+		if flag.Enabled != enabled || flag.Source != source {
+			t.Errorf("%s = %v from %s, want %v from %s", key, flag.Enabled, flag.Source, enabled, source)
+		}
+		if got := s.Enabled(ctx, workspaceID, key); got != enabled {
+			t.Errorf("Enabled(%q) = %v, want %v", key, got, enabled)
+		}
+	}
+	check(ws.ID, Calls, true, SourceDefault)
+	check(ws.ID, ThreadsV2, true, SourceServer)
+
+	if _, err := s.Set(ctx, ws.ID, Calls, false); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := s.Set(ctx, ws.ID, ThreadsV2, false); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	check(ws.ID, Calls, false, SourceWorkspace)
+	check(ws.ID, ThreadsV2, false, SourceWorkspace)
+	check(other.ID, Calls, true, SourceDefault)
+
+	// The server value is reloadable and applies wherever there's no override
+	s.SetServerFlags(map[string]bool{Calls: false})
+	check(other.ID, Calls, false, SourceServer)
+	check(other.ID, ThreadsV2, false, SourceDefault)
+
+	flag, err := s.Reset(ctx, ws.ID, ThreadsV2)
+	if err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if flag.Enabled || flag.Source != SourceDefault {
+		t.Errorf("Reset = %+v, want the default", flag)
+	}
+
+	flags, err := s.List(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(flags) != len(Registry) || flags[0].Key != Calls || flags[0].Source != SourceWorkspace {
+		t.Errorf("unexpected list %+v", flags)
+	}
+
+	if _, err := s.Set(ctx, ws.ID, "teleport", true); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("Set(unknown) error = %v, want ErrUnknownFlag", err)
+	}
+	if s.Enabled(ctx, ws.ID, "teleport") {
+		t.Error("unknown flags must be off")
+	}
+}
+
+func TestNilService(t *testing.T) {
+	var s *Service
+	if !s.Enabled(context.Background(), "ws", Calls) || s.Enabled(context.Background(), "ws", ThreadsV2) {
+		t.Error("a nil service should report the registry defaults")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(map[string]bool{Calls: false}); err != nil {
+		t.Errorf("Validate(known) = %v", err)
+	}
+	err := Validate(map[string]bool{"zeta": true, "alpha": true, Calls: true})
+	if !errors.Is(err, ErrUnknownFlag) || err.Error() != "features: unknown feature flag: alpha, zeta" {
+		t.Errorf("Validate(unknown) = %v", err)
+	}
+}
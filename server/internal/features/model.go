@@ -0,0 +1,78 @@
+package features
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownFlag = errors.New("unknown feature flag")
+
+// Flag keys. Handlers pass these to Service.Enabled; clients see them in the
+// workspace features list.
+const (
+	// Calls gates starting and joining voice and video calls
+	Calls = "calls"
+	// ThreadsV2 turns on the redesigned thread view in clients
+	ThreadsV2 = "threads_v2"
+)
+
+// Definition describes a flag the server knows about
+type Definition struct {
+	Key         string
+	Description string
+	// Default applies when neither the server config nor the workspace sets
+	// the flag
+	Default bool
+}
+
+// Registry lists every known flag in the order clients show them. Flags
+// staging new behavior start off; flags that let a workspace opt out of an
+// existing one start on.
+var Registry = []Definition{
+	{Key: Calls, Description: "Voice and video calls in channels", Default: true},
+	{Key: ThreadsV2, Description: "Redesigned thread view", Default: false},
+}
+
+// Lookup returns the definition of a flag
+func Lookup(key string) (Definition, bool) {
+	for _, d := range Registry {
+		if d.Key == key {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Validate checks that every key in flags is a known flag, so a typo in the
+// config file is caught instead of silently doing nothing.
+func Validate(flags map[string]bool) error {
+	var unknown []string
+	for key := range flags {
+		if _, ok := Lookup(key); !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("features: %w: %s", ErrUnknownFlag, strings.Join(unknown, ", "))
+}
+
+// Source says where a flag's value came from
+type Source string
+
+const (
+	SourceDefault   Source = "default"
+	SourceServer    Source = "server"
+	SourceWorkspace Source = "workspace"
+)
+
+// Flag is a flag's value in one workspace
+type Flag struct {
+	Definition
+	Enabled bool
+	Source  Source
+}
@@ -0,0 +1,60 @@
+package features
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// ListOverrides returns the flags a workspace has set, keyed by flag.
+// Overrides for flags no longer in the registry are included; callers skip
+// them.
+func (r *Repository) ListOverrides(ctx context.Context, workspaceID string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT feature, enabled FROM workspace_features WHERE workspace_id = ?
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		var enabled bool
+		if err := rows.Scan(&key, &enabled); err != nil {
+			return nil, err
+		}
+		overrides[key] = enabled
+	}
+	return overrides, rows.Err()
+}
+
+// SetOverride sets a flag for a workspace, replacing any earlier override
+func (r *Repository) SetOverride(ctx context.Context, workspaceID, key string, enabled bool) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO workspace_features (workspace_id, feature, enabled, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(workspace_id, feature) DO UPDATE SET
+			enabled = excluded.enabled,
+			updated_at = excluded.updated_at
+	`, workspaceID, key, enabled, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteOverride removes a workspace's override so the flag falls back to the
+// server setting. Removing an override that isn't there is not an error.
+func (r *Repository) DeleteOverride(ctx context.Context, workspaceID, key string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM workspace_features WHERE workspace_id = ? AND feature = ?
+	`, workspaceID, key)
+	return err
+}
@@ -0,0 +1,306 @@
+package file
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder for image.DecodeConfig
+	"image/jpeg"
+	"image/png"
+)
+
+// Image is an image upload after ProcessImage.
+type Image struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// jpegQuality is used when a JPEG has to be re-encoded to apply its
+// orientation.
+const jpegQuality = 90
+
+var errMalformedImage = errors.New("malformed image")
+
+// IsImage reports whether ProcessImage handles contentType.
+func IsImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// ProcessImage prepares an image upload for storage: it strips metadata that
+// can identify where or on what device a photo was taken, turns JPEG and PNG
+// images upright according to their EXIF orientation, and measures them. It
+// returns nil for content types it does not handle.
+func ProcessImage(data []byte, contentType string) (*Image, error) {
+	var out []byte
+	var orientation int
+	var err error
+	switch contentType {
+	case "image/jpeg":
+		out, orientation, err = stripJPEG(data)
+	case "image/png":
+		out, orientation, err = stripPNG(data)
+	case "image/gif":
+		out = data
+	case "image/webp":
+		return processWebP(data)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if orientation > 1 && orientation <= 8 {
+		src, _, err := image.Decode(bytes.NewReader(out))
+		if err != nil {
+			return nil, err
+		}
+		dst := orient(src, orientation)
+		var buf bytes.Buffer
+		if contentType == "image/jpeg" {
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return nil, err
+		}
+		b := dst.Bounds()
+		return &Image{Data: buf.Bytes(), Width: b.Dx(), Height: b.Dy()}, nil
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+	return &Image{Data: out, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// stripJPEG removes EXIF, XMP and IPTC segments and comments from a JPEG and
+// returns the EXIF orientation it found (0 if none). The image data itself
+// is copied unchanged.
+func stripJPEG(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, errMalformedImage
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	orientation := 0
+
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF || i+1 >= len(data) {
+			return nil, 0, errMalformedImage
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte
+			i++
+			continue
+		case marker == 0xD8 || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			out = append(out, data[i:i+2]...)
+			i += 2
+			continue
+		case marker == 0xD9 || marker == 0xDA:
+			// End of image, or start of scan: the rest is image data
+			return append(out, data[i:]...), orientation, nil
+		}
+
+		if i+4 > len(data) {
+			return nil, 0, errMalformedImage
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + size
+		if size < 2 || end > len(data) {
+			return nil, 0, errMalformedImage
+		}
+		payload := data[i+4 : end]
+
+		drop := false
+		switch marker {
+		case 0xE1: // APP1: EXIF or XMP
+			if bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+				if o := exifOrientation(payload[6:]); o != 0 {
+					orientation = o
+				}
+			}
+			drop = true
+		case 0xED, 0xFE: // APP13 (Photoshop/IPTC), comment
+			drop = true
+		}
+		if !drop {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	return out, orientation, nil
+}
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNG removes the eXIf chunk and text chunks from a PNG and returns the
+// EXIF orientation it found (0 if none).
+func stripPNG(data []byte) ([]byte, int, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, 0, errMalformedImage
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	orientation := 0
+
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, 0, errMalformedImage
+		}
+		size := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + size // length, type, data, CRC
+		if size < 0 || end > len(data) {
+			return nil, 0, errMalformedImage
+		}
+		switch string(data[i+4 : i+8]) {
+		case "eXIf":
+			orientation = exifOrientation(data[i+8 : i+8+size])
+		case "tEXt", "zTXt", "iTXt":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	return out, orientation, nil
+}
+
+// exifOrientation reads the Orientation tag from IFD0 of a TIFF-structured
+// EXIF block, returning 0 if it is missing or unreadable.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		e := ifd + 2 + n*12
+		if e+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[e:]) == 0x0112 { // Orientation, a SHORT
+			return int(order.Uint16(tiff[e+8:]))
+		}
+	}
+	return 0
+}
+
+// orient returns src transformed so it displays upright, given its EXIF
+// orientation (2-8).
+func orient(src image.Image, orientation int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// Work on an RGBA copy; At on decoded images is slow per pixel
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // upside down
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored upside down
+				dx, dy = x, h-1-y
+			case 5: // mirrored, rotated 90° counter-clockwise
+				dx, dy = y, x
+			case 6: // rotated 90° counter-clockwise
+				dx, dy = h-1-y, x
+			case 7: // mirrored, rotated 90° clockwise
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90° clockwise
+				dx, dy = y, w-1-x
+			}
+			si := rgba.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], rgba.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// processWebP strips EXIF and XMP chunks from an extended WebP and reads its
+// dimensions. WebP has no decoder in the standard library, so the image
+// is not reoriented.
+func processWebP(data []byte) (*Image, error) {
+	if len(data) < 30 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errMalformedImage
+	}
+
+	var width, height int
+	switch string(data[12:16]) {
+	case "VP8 ":
+		width = int(binary.LittleEndian.Uint16(data[26:]) & 0x3fff)
+		height = int(binary.LittleEndian.Uint16(data[28:]) & 0x3fff)
+		return &Image{Data: data, Width: width, Height: height}, nil
+	case "VP8L":
+		b := data[21:25]
+		width = 1 + (int(b[0]) | int(b[1]&0x3f)<<8)
+		height = 1 + (int(b[1])>>6 | int(b[2])<<2 | int(b[3]&0x0f)<<10)
+		return &Image{Data: data, Width: width, Height: height}, nil
+	case "VP8X":
+	default:
+		return nil, errMalformedImage
+	}
+
+	width = 1 + (int(data[24]) | int(data[25])<<8 | int(data[26])<<16)
+	height = 1 + (int(data[27]) | int(data[28])<<8 | int(data[29])<<16)
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	i := 12
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, errMalformedImage
+		}
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2 // chunks are padded to an even length
+		if end > len(data) {
+			return nil, errMalformedImage
+		}
+		switch string(data[i : i+4]) {
+		case "EXIF", "XMP ":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	// Clear the EXIF and XMP flags and fix the RIFF size
+	out[20] &^= 0x08 | 0x04
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return &Image{Data: out, Width: width, Height: height}, nil
+}
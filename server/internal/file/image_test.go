@@ -0,0 +1,123 @@
+package file
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifBlock returns a little-endian TIFF block with an Orientation tag and
+// a marker string standing in for GPS data.
+func exifBlock(orientation uint16) []byte {
+	var b bytes.Buffer
+	b.WriteString("II")
+	binary.Write(&b, binary.LittleEndian, uint16(42))
+	binary.Write(&b, binary.LittleEndian, uint32(8)) // IFD0 offset
+	binary.Write(&b, binary.LittleEndian, uint16(1)) // entries
+	binary.Write(&b, binary.LittleEndian, uint16(0x0112))
+	binary.Write(&b, binary.LittleEndian, uint16(3)) // SHORT
+	binary.Write(&b, binary.LittleEndian, uint32(1)) // count
+	binary.Write(&b, binary.LittleEndian, orientation)
+	binary.Write(&b, binary.LittleEndian, uint16(0)) // padding
+	binary.Write(&b, binary.LittleEndian, uint32(0)) // no next IFD
+	b.WriteString("GPS-SECRET")
+	return b.Bytes()
+}
+
+// halves returns a 4x2 image, red on the left and blue on the right.
+func halves() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= 2 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestProcessImage_JPEG(t *testing.T) {
+	var enc bytes.Buffer
+	if err := jpeg.Encode(&enc, halves(), &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	payload := append([]byte("Exif\x00\x00"), exifBlock(6)...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	data := append(append(append([]byte{0xFF, 0xD8}, app1...), payload...), enc.Bytes()[2:]...)
+
+	img, err := ProcessImage(data, "image/jpeg")
+	if err != nil {
+		t.Fatalf("ProcessImage: %v", err)
+	}
+	if bytes.Contains(img.Data, []byte("Exif")) || bytes.Contains(img.Data, []byte("GPS-SECRET")) {
+		t.Error("expected EXIF to be stripped")
+	}
+	// Orientation 6 is rotated; upright the image is 2 wide and 4 tall
+	if img.Width != 2 || img.Height != 4 {
+		t.Fatalf("expected 2x4, got %dx%d", img.Width, img.Height)
+	}
+
+	decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	// Turned clockwise, the red left half ends up on top
+	if r, _, b, _ := decoded.At(0, 0).RGBA(); r < b {
+		t.Error("expected red at the top")
+	}
+	if r, _, b, _ := decoded.At(0, 3).RGBA(); b < r {
+		t.Error("expected blue at the bottom")
+	}
+}
+
+func TestProcessImage_PNG(t *testing.T) {
+	var enc bytes.Buffer
+	if err := png.Encode(&enc, halves()); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	raw := enc.Bytes()
+
+	// Insert eXIf and tEXt chunks after IHDR; CRCs are not checked on the
+	// way through
+	chunk := func(typ string, body []byte) []byte {
+		c := binary.BigEndian.AppendUint32(nil, uint32(len(body)))
+		c = append(c, typ...)
+		c = append(c, body...)
+		return append(c, 0, 0, 0, 0)
+	}
+	ihdrEnd := len(pngSignature) + 12 + 13
+	var data []byte
+	data = append(data, raw[:ihdrEnd]...)
+	data = append(data, chunk("eXIf", exifBlock(1))...)
+	data = append(data, chunk("tEXt", []byte("Comment\x00taken at home"))...)
+	data = append(data, raw[ihdrEnd:]...)
+
+	img, err := ProcessImage(data, "image/png")
+	if err != nil {
+		t.Fatalf("ProcessImage: %v", err)
+	}
+	if !bytes.Equal(img.Data, raw) {
+		t.Error("expected eXIf and tEXt chunks to be stripped")
+	}
+	if img.Width != 4 || img.Height != 2 {
+		t.Errorf("expected 4x2, got %dx%d", img.Width, img.Height)
+	}
+}
+
+func TestProcessImage_Unhandled(t *testing.T) {
+	img, err := ProcessImage([]byte("%PDF-1.7"), "application/pdf")
+	if err != nil || img != nil {
+		t.Errorf("expected nil for non-images, got %v, %v", img, err)
+	}
+	if _, err := ProcessImage([]byte("not a jpeg"), "image/jpeg"); err == nil {
+		t.Error("expected error for malformed JPEG")
+	}
+}
@@ -13,5 +13,79 @@ type Attachment struct {
 	ContentType string    `json:"content_type"`
 	SizeBytes   int64     `json:"size_bytes"`
 	StoragePath string    `json:"-"`
+	Status      string    `json:"status"`
+	Width       *int      `json:"width,omitempty"`  // set for images, after orientation
+	Height      *int      `json:"height,omitempty"` // set for images, after orientation
 	CreatedAt   time.Time `json:"created_at"`
 }
+
+// Attachment statuses
+const (
+	StatusReady       = "ready"
+	StatusQuarantined = "quarantined" // failed the antivirus scan; never served
+)
+
+// Entry is an attachment as listed in a Files tab, with the message it was
+// posted in and who uploaded it
+type Entry struct {
+	Attachment
+	ChannelName     string
+	ThreadParentID  *string
+	UserDisplayName string
+	UserAvatarURL   *string
+}
+
+// File list type filters
+const (
+	TypeImages   = "images"
+	TypeDocs     = "docs"
+	TypeSnippets = "snippets"
+)
+
+// File list sort orders
+const (
+	SortNewest  = "newest"
+	SortOldest  = "oldest"
+	SortLargest = "largest"
+	SortName    = "name"
+)
+
+// ListOptions filters and pages a file list
+type ListOptions struct {
+	Type   string
+	Sort   string
+	Limit  int
+	Offset int
+
+	// VisibleSince hides files posted in messages created before this time
+	// (private channel history visibility). Nil means no restriction.
+	VisibleSince *time.Time
+}
+
+// Usage summarises the attachments stored for a workspace.
+type Usage struct {
+	Attachments int64
+	Bytes       int64
+}
+
+// UploadSession tracks a resumable upload in progress. Chunks are stored as
+// separate objects and concatenated into an attachment on completion.
+type UploadSession struct {
+	ID          string    `json:"id"`
+	ChannelID   string    `json:"channel_id"`
+	UserID      string    `json:"user_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Offset      int64     `json:"offset"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UploadChunk is one stored piece of an upload session.
+type UploadChunk struct {
+	Offset      int64
+	SizeBytes   int64
+	StoragePath string
+}
@@ -7,11 +7,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/telemetry"
 	"github.com/oklog/ulid/v2"
 )
 
 var (
-	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrUploadSessionNotFound = errors.New("upload session not found")
+	ErrUploadOffsetMismatch  = errors.New("upload offset does not match")
 )
 
 type Repository struct {
@@ -25,38 +29,26 @@ func NewRepository(db *sql.DB) *Repository {
 func (r *Repository) Create(ctx context.Context, attachment *Attachment) error {
 	attachment.ID = ulid.Make().String()
 	attachment.CreatedAt = time.Now().UTC()
+	if attachment.Status == "" {
+		attachment.Status = StatusReady
+	}
 
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, attachment.ID, attachment.MessageID, attachment.ChannelID, attachment.UserID, attachment.Filename, attachment.ContentType, attachment.SizeBytes, attachment.StoragePath, attachment.CreatedAt.Format(time.RFC3339))
+		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, status, width, height, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, attachment.ID, attachment.MessageID, attachment.ChannelID, attachment.UserID, attachment.Filename, attachment.ContentType, attachment.SizeBytes, attachment.StoragePath, attachment.Status, attachment.Width, attachment.Height, attachment.CreatedAt.Format(time.RFC3339))
 	return err
 }
 
 func (r *Repository) GetByID(ctx context.Context, id string) (*Attachment, error) {
-	var a Attachment
-	var messageID, userID sql.NullString
-	var createdAt string
-
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at
-		FROM attachments WHERE id = ?
-	`, id).Scan(&a.ID, &messageID, &a.ChannelID, &userID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.StoragePath, &createdAt)
+	row := r.db.QueryRowContext(ctx, `SELECT `+attachmentColumns+` FROM attachments a WHERE a.id = ?`, id)
+	a, err := scanAttachment(row.Scan)
 	if err == sql.ErrNoRows {
 		return nil, ErrAttachmentNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
-
-	if messageID.Valid {
-		a.MessageID = &messageID.String
-	}
-	if userID.Valid {
-		a.UserID = &userID.String
-	}
-	a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-
 	return &a, nil
 }
 
@@ -73,38 +65,11 @@ func (r *Repository) Delete(ctx context.Context, id string) error {
 }
 
 func (r *Repository) ListForMessage(ctx context.Context, messageID string) ([]Attachment, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at
-		FROM attachments WHERE message_id = ?
-	`, messageID)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+attachmentColumns+` FROM attachments a WHERE a.message_id = ?`, messageID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var attachments []Attachment
-	for rows.Next() {
-		var a Attachment
-		var msgID, userID sql.NullString
-		var createdAt string
-
-		err := rows.Scan(&a.ID, &msgID, &a.ChannelID, &userID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.StoragePath, &createdAt)
-		if err != nil {
-			return nil, err
-		}
-
-		if msgID.Valid {
-			a.MessageID = &msgID.String
-		}
-		if userID.Valid {
-			a.UserID = &userID.String
-		}
-		a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-
-		attachments = append(attachments, a)
-	}
-
-	return attachments, rows.Err()
+	return scanAttachments(rows)
 }
 
 func (r *Repository) UpdateMessageID(ctx context.Context, attachmentID, messageID string) error {
@@ -128,41 +93,397 @@ func (r *Repository) ListForMessages(ctx context.Context, messageIDs []string) (
 	}
 
 	query := `
-		SELECT id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at
-		FROM attachments
-		WHERE message_id IN (` + strings.Join(placeholders, ",") + `)
-		ORDER BY created_at
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		WHERE a.message_id IN (` + strings.Join(placeholders, ",") + `)
+			OR a.archived_message_id IN (` + strings.Join(placeholders, ",") + `)
+		ORDER BY a.created_at
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, append(args, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	list, err := scanAttachments(rows)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	attachments := make(map[string][]Attachment)
+	for _, a := range list {
+		if a.MessageID != nil {
+			attachments[*a.MessageID] = append(attachments[*a.MessageID], a)
+		}
+	}
+	return attachments, nil
+}
+
+// ListUnlinkedBefore returns up to limit attachments that were never linked
+// to a message (or whose message is gone) and were uploaded before the given
+// time. Attachments still referenced by an unsent scheduled message are kept.
+func (r *Repository) ListUnlinkedBefore(ctx context.Context, before time.Time, limit int) ([]Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM attachments a
+		WHERE a.message_id IS NULL AND a.archived_message_id IS NULL AND a.created_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM scheduled_messages sm
+				WHERE sm.status IN ('pending', 'sending', 'failed') AND sm.attachment_ids LIKE '%' || a.id || '%'
+			)
+		ORDER BY a.created_at
+		LIMIT ?
+	`, before.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAttachments(rows)
+}
+
+// WorkspaceUsage reports how many attachments a workspace holds and their
+// total size.
+func (r *Repository) WorkspaceUsage(ctx context.Context, workspaceID string) (*Usage, error) {
+	var u Usage
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(a.size_bytes), 0)
+		FROM attachments a
+		JOIN channels c ON c.id = a.channel_id
+		WHERE c.workspace_id = ?
+	`, workspaceID).Scan(&u.Attachments, &u.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// ListInWorkspaceBefore returns up to limit attachments in a workspace that
+// were uploaded before the given time, oldest first.
+func (r *Repository) ListInWorkspaceBefore(ctx context.Context, workspaceID string, before time.Time, limit int) ([]Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM attachments a
+		JOIN channels c ON c.id = a.channel_id
+		WHERE c.workspace_id = ? AND a.created_at < ?
+		ORDER BY a.created_at
+		LIMIT ?
+	`, workspaceID, before.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAttachments(rows)
+}
+
+// ListForDeletedMessagesBefore returns up to limit attachments of messages,
+// archived ones included, that were deleted before the given time, oldest
+// deletion first.
+func (r *Repository) ListForDeletedMessagesBefore(ctx context.Context, before time.Time, limit int) ([]Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM attachments a
+		LEFT JOIN messages m ON m.id = a.message_id
+		LEFT JOIN messages_archive ma ON ma.id = a.archived_message_id
+		WHERE COALESCE(m.deleted_at, ma.deleted_at) < ?
+		ORDER BY COALESCE(m.deleted_at, ma.deleted_at)
+		LIMIT ?
+	`, before.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAttachments(rows)
+}
+
+// typeFilters are the SQL conditions for each file list type
+var typeFilters = map[string]string{
+	TypeImages: `a.content_type LIKE 'image/%'`,
+	TypeDocs: `(a.content_type IN ('application/pdf', 'application/msword', 'application/rtf')
+		OR a.content_type LIKE 'application/vnd.openxmlformats-officedocument.%'
+		OR a.content_type LIKE 'application/vnd.ms-%'
+		OR a.content_type LIKE 'application/vnd.oasis.opendocument.%')`,
+	TypeSnippets: `(a.content_type LIKE 'text/%'
+		OR a.content_type IN ('application/json', 'application/xml', 'application/javascript', 'application/x-yaml', 'application/x-sh'))`,
+}
+
+// ListForChannel returns a page of the files posted in a channel and the
+// number of files matching the filters.
+func (r *Repository) ListForChannel(ctx context.Context, channelID string, opts ListOptions, filter *moderation.FilterOptions) ([]Entry, int, error) {
+	return r.listEntries(ctx, `a.channel_id = ?`, []interface{}{channelID}, opts, filter)
+}
+
+// ListForWorkspace returns a page of the files posted in the workspace's
+// channels that userID can read: channels they belong to and public ones.
+// In private channels, files from before the history the user can see are
+// left out.
+func (r *Repository) ListForWorkspace(ctx context.Context, workspaceID, userID string, opts ListOptions, filter *moderation.FilterOptions) ([]Entry, int, error) {
+	where := `c.workspace_id = ? AND (c.type = 'public' OR EXISTS (
+		SELECT 1 FROM channel_memberships cm WHERE cm.channel_id = c.id AND cm.user_id = ?
+		AND (c.type != 'private' OR c.history_visibility = 'all'
+		  OR (c.history_visibility = 'none' AND m.created_at >= cm.created_at)
+		  OR (c.history_visibility = 'last_30_days' AND m.created_at >= strftime('%Y-%m-%dT%H:%M:%SZ', cm.created_at, '-30 days')))
+	))`
+	return r.listEntries(ctx, where, []interface{}{workspaceID, userID}, opts, filter)
+}
+
+// listEntries lists attachments of undeleted messages matching where.
+func (r *Repository) listEntries(ctx context.Context, where string, whereArgs []interface{}, opts ListOptions, filter *moderation.FilterOptions) (_ []Entry, _ int, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "file.listEntries")
+	defer func() { endSpan(err) }()
+
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	where += ` AND m.deleted_at IS NULL`
+	if opts.VisibleSince != nil {
+		where += ` AND m.created_at >= ?`
+		whereArgs = append(whereArgs, opts.VisibleSince.UTC().Format(time.RFC3339))
+	}
+	if cond, ok := typeFilters[opts.Type]; ok {
+		where += ` AND ` + cond
+	}
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "a.user_id")
+	where += filterSQL
+	whereArgs = append(whereArgs, filterArgs...)
+
+	const from = `
+		FROM attachments a
+		JOIN messages m ON m.id = a.message_id
+		JOIN channels c ON c.id = a.channel_id
+		LEFT JOIN users u ON u.id = a.user_id`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from+` WHERE `+where, whereArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	var orderBy string
+	switch opts.Sort {
+	case SortOldest:
+		orderBy = `a.created_at, a.id`
+	case SortLargest:
+		orderBy = `a.size_bytes DESC, a.id DESC`
+	case SortName:
+		orderBy = `LOWER(a.filename), a.id`
+	default:
+		orderBy = `a.created_at DESC, a.id DESC`
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+attachmentColumns+`,
+		       c.name, m.thread_parent_id, COALESCE(u.display_name, 'Former member'), u.avatar_url`+from+`
+		WHERE `+where+`
+		ORDER BY `+orderBy+`
+		LIMIT ? OFFSET ?
+	`, append(whereArgs, opts.Limit, opts.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
 	for rows.Next() {
-		var a Attachment
-		var messageID, userID sql.NullString
-		var createdAt string
+		var e Entry
+		var threadParentID, avatarURL sql.NullString
+		e.Attachment, err = scanAttachment(rows.Scan, &e.ChannelName, &threadParentID, &e.UserDisplayName, &avatarURL)
+		if err != nil {
+			return nil, 0, err
+		}
+		if threadParentID.Valid {
+			e.ThreadParentID = &threadParentID.String
+		}
+		if avatarURL.Valid {
+			e.UserAvatarURL = &avatarURL.String
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+// attachmentColumns is the column list read by scanAttachment. Queries
+// alias the attachments table as a. The message ID is that of the archived
+// message for attachments moved to cold storage.
+const attachmentColumns = `a.id, COALESCE(a.message_id, a.archived_message_id), a.channel_id, a.user_id, a.filename, a.content_type, a.size_bytes, a.storage_path, a.status, a.width, a.height, a.created_at`
+
+// scanAttachment reads one row selecting attachmentColumns followed by any
+// extra columns.
+func scanAttachment(scan func(dest ...interface{}) error, extra ...interface{}) (Attachment, error) {
+	var a Attachment
+	var messageID, userID sql.NullString
+	var width, height sql.NullInt64
+	var createdAt string
 
-		err := rows.Scan(&a.ID, &messageID, &a.ChannelID, &userID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.StoragePath, &createdAt)
+	dest := append([]interface{}{&a.ID, &messageID, &a.ChannelID, &userID, &a.Filename, &a.ContentType, &a.SizeBytes, &a.StoragePath, &a.Status, &width, &height, &createdAt}, extra...)
+	if err := scan(dest...); err != nil {
+		return a, err
+	}
+	if messageID.Valid {
+		a.MessageID = &messageID.String
+	}
+	if userID.Valid {
+		a.UserID = &userID.String
+	}
+	if width.Valid && height.Valid {
+		w, h := int(width.Int64), int(height.Int64)
+		a.Width, a.Height = &w, &h
+	}
+	a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return a, nil
+}
+
+// scanAttachments reads every row of an attachment query selecting
+// attachmentColumns.
+func scanAttachments(rows *sql.Rows) ([]Attachment, error) {
+	defer rows.Close()
+
+	var attachments []Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows.Scan)
 		if err != nil {
 			return nil, err
 		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
 
-		if messageID.Valid {
-			a.MessageID = &messageID.String
-		}
-		if userID.Valid {
-			a.UserID = &userID.String
+func (r *Repository) CreateUploadSession(ctx context.Context, session *UploadSession) error {
+	session.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO upload_sessions (id, channel_id, user_id, filename, content_type, size_bytes, offset_bytes, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?, ?)
+	`, session.ID, session.ChannelID, session.UserID, session.Filename, session.ContentType, session.SizeBytes,
+		session.ExpiresAt.UTC().Format(time.RFC3339), now.Format(time.RFC3339), now.Format(time.RFC3339))
+	return err
+}
+
+func (r *Repository) GetUploadSession(ctx context.Context, id string) (*UploadSession, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT id, channel_id, user_id, filename, content_type, size_bytes, offset_bytes, expires_at, created_at, updated_at
+		FROM upload_sessions WHERE id = ?
+	`, id)
+	s, err := scanUploadSession(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrUploadSessionNotFound
+	}
+	return s, err
+}
+
+// AppendUploadChunk records a stored chunk at the session's current offset
+// and pushes out the session's expiry. It returns ErrUploadOffsetMismatch if
+// the session has moved past chunk.Offset, e.g. because a retried chunk was
+// already recorded.
+func (r *Repository) AppendUploadChunk(ctx context.Context, id string, chunk UploadChunk, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE upload_sessions
+		SET offset_bytes = offset_bytes + ?, expires_at = ?, updated_at = ?
+		WHERE id = ? AND offset_bytes = ?
+	`, chunk.SizeBytes, expiresAt.UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339), id, chunk.Offset)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrUploadOffsetMismatch
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO upload_chunks (upload_id, offset_bytes, size_bytes, storage_path)
+		VALUES (?, ?, ?, ?)
+	`, id, chunk.Offset, chunk.SizeBytes, chunk.StoragePath)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListUploadChunks returns a session's chunks in offset order.
+func (r *Repository) ListUploadChunks(ctx context.Context, id string) ([]UploadChunk, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT offset_bytes, size_bytes, storage_path
+		FROM upload_chunks WHERE upload_id = ?
+		ORDER BY offset_bytes
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []UploadChunk
+	for rows.Next() {
+		var c UploadChunk
+		if err := rows.Scan(&c.Offset, &c.SizeBytes, &c.StoragePath); err != nil {
+			return nil, err
 		}
-		a.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		chunks = append(chunks, c)
+	}
+
+	return chunks, rows.Err()
+}
 
-		if messageID.Valid {
-			attachments[messageID.String] = append(attachments[messageID.String], a)
+// DeleteUploadSession removes a session and its chunk records. The stored
+// chunk objects must be deleted by the caller.
+func (r *Repository) DeleteUploadSession(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM upload_sessions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrUploadSessionNotFound
+	}
+	return nil
+}
+
+// ListExpiredUploadSessions returns up to limit upload sessions whose expiry
+// is before the given time.
+func (r *Repository) ListExpiredUploadSessions(ctx context.Context, before time.Time, limit int) ([]UploadSession, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, channel_id, user_id, filename, content_type, size_bytes, offset_bytes, expires_at, created_at, updated_at
+		FROM upload_sessions WHERE expires_at < ?
+		ORDER BY expires_at
+		LIMIT ?
+	`, before.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []UploadSession
+	for rows.Next() {
+		s, err := scanUploadSession(rows)
+		if err != nil {
+			return nil, err
 		}
+		sessions = append(sessions, *s)
 	}
 
-	return attachments, rows.Err()
+	return sessions, rows.Err()
+}
+
+func scanUploadSession(row interface{ Scan(dest ...any) error }) (*UploadSession, error) {
+	var s UploadSession
+	var expiresAt, createdAt, updatedAt string
+
+	err := row.Scan(&s.ID, &s.ChannelID, &s.UserID, &s.Filename, &s.ContentType, &s.SizeBytes, &s.Offset, &expiresAt, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+	s.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	s.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	return &s, nil
 }
@@ -0,0 +1,69 @@
+package file
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// SniffLen is how many leading bytes DetectContentType looks at.
+const SniffLen = 512
+
+// zipFormats are document formats stored as zip archives, which sniff as
+// application/zip.
+var zipFormats = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".epub": "application/epub+zip",
+}
+
+// DetectContentType returns the content type of an upload from its leading
+// bytes, using the filename's extension only to refine a generic result.
+// The type the client declared is never consulted.
+func DetectContentType(head []byte, filename string) string {
+	sniffed := baseType(http.DetectContentType(head))
+	ext := strings.ToLower(filepath.Ext(filename))
+	byExt := baseType(mime.TypeByExtension(ext))
+
+	switch sniffed {
+	case "application/zip":
+		if t, ok := zipFormats[ext]; ok {
+			return t
+		}
+	case "text/plain":
+		// Plain text can be any text format, but never one a browser
+		// would render as a page
+		if isTextType(byExt) && byExt != "text/html" {
+			return byExt
+		}
+	case "application/octet-stream":
+		// Binary formats the sniffer does not know; a text type here
+		// would be a lie
+		if byExt != "" && !isTextType(byExt) {
+			return byExt
+		}
+	}
+	return sniffed
+}
+
+// baseType strips parameters such as charset from a content type.
+func baseType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return t
+}
+
+func isTextType(t string) bool {
+	return strings.HasPrefix(t, "text/") || t == "application/json" || t == "application/xml" ||
+		t == "application/javascript" || t == "image/svg+xml"
+}
@@ -0,0 +1,332 @@
+// Package gc reclaims storage held by data nothing refers to any more:
+// uploads that were never attached to a message or never finished, reactions
+// left on deleted messages, stale full-text index entries, and expired invites
+// and tokens. It also enforces per-workspace attachment retention and purges
+// deleted messages once their undelete window has passed.
+package gc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/workspace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// batchSize bounds how many rows are loaded per query so a
+// large backlog does not have to fit in memory at once.
+const batchSize = 500
+
+// Pre-computed metric attribute sets for each kind of collected row.
+var (
+	kindAttachment        = metric.WithAttributes(attribute.String("kind", "attachment"))
+	kindExpiredAttachment = metric.WithAttributes(attribute.String("kind", "expired_attachment"))
+	kindDeletedAttachment = metric.WithAttributes(attribute.String("kind", "deleted_message_attachment"))
+	kindDeletedMessage    = metric.WithAttributes(attribute.String("kind", "deleted_message"))
+	kindUploadSession     = metric.WithAttributes(attribute.String("kind", "upload_session"))
+	kindReaction          = metric.WithAttributes(attribute.String("kind", "reaction"))
+	kindInvite            = metric.WithAttributes(attribute.String("kind", "invite"))
+	kindPasswordReset     = metric.WithAttributes(attribute.String("kind", "password_reset"))
+	kindEmailVerification = metric.WithAttributes(attribute.String("kind", "email_verification"))
+)
+
+// Result summarises a single collection run.
+type Result struct {
+	Attachments        int64
+	ExpiredAttachments int64 // removed by a workspace retention policy
+	DeletedAttachments int64 // attached to messages deleted before the undelete window
+	DeletedMessages    int64 // deleted messages whose original content was purged
+	UploadSessions     int64
+	ReclaimedBytes     int64
+	Reactions          int64
+	SearchIndexRebuilt bool
+	Invites            int64
+	PasswordResets     int64
+	EmailVerifications int64
+}
+
+// Collector deletes unreferenced data on a schedule.
+type Collector struct {
+	files              *file.Repository
+	messages           *message.Repository
+	workspaces         *workspace.Repository
+	passwordResets     *auth.PasswordResetRepo
+	emailVerifications *auth.EmailVerificationRepo
+	store              storage.Storage
+	attachmentTTL      time.Duration
+	undeleteWindow     time.Duration
+
+	// OTel metrics (no-op when telemetry is disabled)
+	rowsDeleted    metric.Int64Counter
+	bytesReclaimed metric.Int64Counter
+}
+
+// NewCollector creates a collector. store may be nil when uploads are
+// disabled, in which case attachments are left alone. Unlinked attachments
+// are only collected once they are older than attachmentTTL, and deleted
+// messages are only purged once they were deleted longer ago than
+// undeleteWindow.
+func NewCollector(
+	files *file.Repository,
+	messages *message.Repository,
+	workspaces *workspace.Repository,
+	passwordResets *auth.PasswordResetRepo,
+	emailVerifications *auth.EmailVerificationRepo,
+	store storage.Storage,
+	attachmentTTL time.Duration,
+	undeleteWindow time.Duration,
+) *Collector {
+	meter := otel.Meter("enzyme.gc")
+	rowsDeleted, err := meter.Int64Counter("gc.rows.deleted",
+		metric.WithDescription("Rows removed by garbage collection"),
+	)
+	if err != nil {
+		slog.Error("failed to create gc.rows.deleted metric", "error", err)
+	}
+	bytesReclaimed, err := meter.Int64Counter("gc.storage.reclaimed",
+		metric.WithDescription("Bytes of attachment storage freed by garbage collection"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		slog.Error("failed to create gc.storage.reclaimed metric", "error", err)
+	}
+
+	return &Collector{
+		files:              files,
+		messages:           messages,
+		workspaces:         workspaces,
+		passwordResets:     passwordResets,
+		emailVerifications: emailVerifications,
+		store:              store,
+		attachmentTTL:      attachmentTTL,
+		undeleteWindow:     undeleteWindow,
+		rowsDeleted:        rowsDeleted,
+		bytesReclaimed:     bytesReclaimed,
+	}
+}
+
+// Run performs one collection pass. It matches the scheduler task signature.
+func (c *Collector) Run(ctx context.Context) error {
+	_, err := c.Collect(ctx)
+	return err
+}
+
+// Collect performs one collection pass and reports what it removed. Each
+// step runs independently so a failure in one does not stop the rest; the
+// first error is returned.
+func (c *Collector) Collect(ctx context.Context) (Result, error) {
+	var res Result
+	var firstErr error
+	fail := func(step string, err error) {
+		slog.Error("garbage collection step failed", "component", "gc", "step", step, "error", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if c.store != nil {
+		if err := c.collectAttachments(ctx, &res); err != nil {
+			fail("attachments", err)
+		}
+		if err := c.collectUploadSessions(ctx, &res); err != nil {
+			fail("upload_sessions", err)
+		}
+		if err := c.collectExpiredAttachments(ctx, &res); err != nil {
+			fail("retention", err)
+		}
+		if err := c.collectDeletedAttachments(ctx, &res); err != nil {
+			fail("deleted_attachments", err)
+		}
+	}
+
+	if n, err := c.messages.PurgeDeletedContent(ctx, time.Now().Add(-c.undeleteWindow)); err != nil {
+		fail("deleted_messages", err)
+	} else {
+		res.DeletedMessages = n
+		c.rowsDeleted.Add(ctx, n, kindDeletedMessage)
+	}
+
+	if n, err := c.messages.DeleteOrphanedReactions(ctx); err != nil {
+		fail("reactions", err)
+	} else {
+		res.Reactions = n
+		c.rowsDeleted.Add(ctx, n, kindReaction)
+	}
+
+	if rebuilt, err := c.messages.RepairSearchIndex(ctx); err != nil {
+		fail("search_index", err)
+	} else {
+		res.SearchIndexRebuilt = rebuilt
+	}
+
+	if n, err := c.workspaces.DeleteExpiredInvites(ctx); err != nil {
+		fail("invites", err)
+	} else {
+		res.Invites = n
+		c.rowsDeleted.Add(ctx, n, kindInvite)
+	}
+
+	if n, err := c.passwordResets.DeleteExpired(ctx); err != nil {
+		fail("password_resets", err)
+	} else {
+		res.PasswordResets = n
+		c.rowsDeleted.Add(ctx, n, kindPasswordReset)
+	}
+
+	if n, err := c.emailVerifications.DeleteExpired(ctx); err != nil {
+		fail("email_verifications", err)
+	} else {
+		res.EmailVerifications = n
+		c.rowsDeleted.Add(ctx, n, kindEmailVerification)
+	}
+
+	slog.Info("garbage collection finished",
+		"component", "gc",
+		"attachments", res.Attachments,
+		"expired_attachments", res.ExpiredAttachments,
+		"deleted_attachments", res.DeletedAttachments,
+		"deleted_messages", res.DeletedMessages,
+		"upload_sessions", res.UploadSessions,
+		"reclaimed_bytes", res.ReclaimedBytes,
+		"reactions", res.Reactions,
+		"search_index_rebuilt", res.SearchIndexRebuilt,
+		"invites", res.Invites,
+		"password_resets", res.PasswordResets,
+		"email_verifications", res.EmailVerifications,
+	)
+
+	return res, firstErr
+}
+
+// collectAttachments deletes unlinked attachments older than the TTL.
+func (c *Collector) collectAttachments(ctx context.Context, res *Result) error {
+	cutoff := time.Now().Add(-c.attachmentTTL)
+	n, err := c.deleteAttachments(ctx, res, kindAttachment, func(limit int) ([]file.Attachment, error) {
+		return c.files.ListUnlinkedBefore(ctx, cutoff, limit)
+	})
+	res.Attachments += n
+	return err
+}
+
+// collectExpiredAttachments deletes attachments that are older than their
+// workspace's retention period, whether or not a message still links them.
+func (c *Collector) collectExpiredAttachments(ctx context.Context, res *Result) error {
+	retention, err := c.workspaces.ListAttachmentRetention(ctx)
+	if err != nil {
+		return err
+	}
+
+	for workspaceID, days := range retention {
+		cutoff := time.Now().AddDate(0, 0, -days)
+		n, err := c.deleteAttachments(ctx, res, kindExpiredAttachment, func(limit int) ([]file.Attachment, error) {
+			return c.files.ListInWorkspaceBefore(ctx, workspaceID, cutoff, limit)
+		})
+		res.ExpiredAttachments += n
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectDeletedAttachments deletes the attachments of messages deleted
+// longer ago than the undelete window.
+func (c *Collector) collectDeletedAttachments(ctx context.Context, res *Result) error {
+	cutoff := time.Now().Add(-c.undeleteWindow)
+	n, err := c.deleteAttachments(ctx, res, kindDeletedAttachment, func(limit int) ([]file.Attachment, error) {
+		return c.files.ListForDeletedMessagesBefore(ctx, cutoff, limit)
+	})
+	res.DeletedAttachments += n
+	return err
+}
+
+// deleteAttachments deletes every attachment returned by list, a batch at a
+// time, and reports how many it removed. The stored object is removed before
+// the row so a failed delete is retried on the next run rather than leaking
+// the file.
+func (c *Collector) deleteAttachments(ctx context.Context, res *Result, kind metric.MeasurementOption, list func(limit int) ([]file.Attachment, error)) (int64, error) {
+	var deleted int64
+	// Attachments that fail to delete stay in the table; skip past them so
+	// the loop always makes progress.
+	failed := make(map[string]bool)
+
+	for {
+		limit := batchSize + len(failed)
+		attachments, err := list(limit)
+		if err != nil {
+			return deleted, err
+		}
+
+		progressed := false
+		for _, a := range attachments {
+			if failed[a.ID] {
+				continue
+			}
+			if err := c.store.Delete(ctx, a.StoragePath); err != nil {
+				slog.Error("failed to delete attachment file", "component", "gc", "id", a.ID, "error", err)
+				failed[a.ID] = true
+				continue
+			}
+			if err := c.files.Delete(ctx, a.ID); err != nil {
+				slog.Error("failed to delete attachment row", "component", "gc", "id", a.ID, "error", err)
+				failed[a.ID] = true
+				continue
+			}
+			progressed = true
+			deleted++
+			res.ReclaimedBytes += a.SizeBytes
+			c.rowsDeleted.Add(ctx, 1, kind)
+			c.bytesReclaimed.Add(ctx, a.SizeBytes)
+		}
+
+		if !progressed || len(attachments) < limit {
+			return deleted, nil
+		}
+	}
+}
+
+// collectUploadSessions deletes resumable uploads that expired before being
+// completed, along with their stored chunks.
+func (c *Collector) collectUploadSessions(ctx context.Context, res *Result) error {
+	for {
+		sessions, err := c.files.ListExpiredUploadSessions(ctx, time.Now(), batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range sessions {
+			chunks, err := c.files.ListUploadChunks(ctx, s.ID)
+			if err != nil {
+				return err
+			}
+			if err := c.files.DeleteUploadSession(ctx, s.ID); err != nil {
+				if errors.Is(err, file.ErrUploadSessionNotFound) {
+					continue // completed or cancelled concurrently
+				}
+				return err
+			}
+			for _, chunk := range chunks {
+				if err := c.store.Delete(ctx, chunk.StoragePath); err != nil {
+					slog.Error("failed to delete upload chunk", "component", "gc", "key", chunk.StoragePath, "error", err)
+					continue
+				}
+				res.ReclaimedBytes += chunk.SizeBytes
+				c.bytesReclaimed.Add(ctx, chunk.SizeBytes)
+			}
+			res.UploadSessions++
+			c.rowsDeleted.Add(ctx, 1, kindUploadSession)
+		}
+
+		if len(sessions) < batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package gc
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+	"github.com/oklog/ulid/v2"
+)
+
+func newTestCollector(t *testing.T, db *sql.DB) (*Collector, string) {
+	t.Helper()
+	dir := t.TempDir()
+	c := NewCollector(
+		file.NewRepository(db),
+		message.NewRepository(db),
+		workspace.NewRepository(db),
+		auth.NewPasswordResetRepo(db),
+		auth.NewEmailVerificationRepo(db),
+		storage.NewLocal(dir),
+		24*time.Hour,
+		24*time.Hour,
+	)
+	return c, dir
+}
+
+func createAttachment(t *testing.T, db *sql.DB, store storage.Storage, channelID, userID string, messageID *string, age time.Duration, data []byte) string {
+	t.Helper()
+	id := ulid.Make().String()
+	key := "attachments/" + id
+	if err := store.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), "text/plain"); err != nil {
+		t.Fatalf("storing attachment: %v", err)
+	}
+	_, err := db.Exec(`
+		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at)
+		VALUES (?, ?, ?, ?, 'file.txt', 'text/plain', ?, ?, ?)
+	`, id, messageID, channelID, userID, len(data), key, time.Now().Add(-age).UTC().Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("inserting attachment: %v", err)
+	}
+	return id
+}
+
+func exists(t *testing.T, db *sql.DB, table, id string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE id = ?`, id).Scan(&n); err != nil {
+		t.Fatalf("counting %s: %v", table, err)
+	}
+	return n > 0
+}
+
+func TestCollect_Attachments(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	c, dir := newTestCollector(t, db)
+
+	user := testutil.CreateTestUser(t, db, "gc@example.com", "GC User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "GC Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "hello")
+
+	stale := createAttachment(t, db, c.store, ch.ID, user.ID, nil, 48*time.Hour, []byte("stale upload"))
+	fresh := createAttachment(t, db, c.store, ch.ID, user.ID, nil, time.Hour, []byte("fresh"))
+	linked := createAttachment(t, db, c.store, ch.ID, user.ID, &msg.ID, 48*time.Hour, []byte("linked"))
+	scheduled := createAttachment(t, db, c.store, ch.ID, user.ID, nil, 48*time.Hour, []byte("scheduled"))
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := db.Exec(`
+		INSERT INTO scheduled_messages (id, channel_id, user_id, content, also_send_to_channel, attachment_ids, scheduled_for, status, retry_count, created_at, updated_at)
+		VALUES (?, ?, ?, 'later', 0, ?, ?, 'pending', 0, ?, ?)
+	`, ulid.Make().String(), ch.ID, user.ID, `["`+scheduled+`"]`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339), now, now)
+	if err != nil {
+		t.Fatalf("inserting scheduled message: %v", err)
+	}
+
+	res, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if res.Attachments != 1 || res.ReclaimedBytes != int64(len("stale upload")) {
+		t.Fatalf("expected 1 attachment and %d bytes reclaimed, got %+v", len("stale upload"), res)
+	}
+
+	if exists(t, db, "attachments", stale) {
+		t.Error("expected stale unlinked attachment to be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "attachments", stale)); !os.IsNotExist(err) {
+		t.Errorf("expected stale attachment file to be removed, got %v", err)
+	}
+	for name, id := range map[string]string{"fresh": fresh, "linked": linked, "scheduled": scheduled} {
+		if !exists(t, db, "attachments", id) {
+			t.Errorf("expected %s attachment to be kept", name)
+		}
+	}
+}
+
+func TestCollect_ReactionsInvitesAndTokens(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	c, _ := newTestCollector(t, db)
+
+	user := testutil.CreateTestUser(t, db, "gc@example.com", "GC User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "GC Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", "public")
+	live := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "still here")
+	dead := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "going away")
+
+	messageRepo := message.NewRepository(db)
+	if _, err := messageRepo.AddReaction(ctx, live.ID, user.ID, "👍"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	if _, err := messageRepo.AddReaction(ctx, dead.ID, user.ID, "👍"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	if err := messageRepo.Delete(ctx, dead.ID, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, expiresAt := range []string{past, future} {
+		_, err := db.Exec(`
+			INSERT INTO workspace_invites (id, workspace_id, code, role, created_by, use_count, expires_at, created_at)
+			VALUES (?, ?, ?, 'member', ?, 0, ?, ?)
+		`, ulid.Make().String(), ws.ID, ulid.Make().String(), user.ID, expiresAt, now)
+		if err != nil {
+			t.Fatalf("inserting invite: %v", err)
+		}
+		_, err = db.Exec(`
+			INSERT INTO password_resets (id, user_id, token, expires_at, created_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, ulid.Make().String(), user.ID, ulid.Make().String(), expiresAt, now)
+		if err != nil {
+			t.Fatalf("inserting password reset: %v", err)
+		}
+	}
+
+	res, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if res.Reactions != 1 {
+		t.Errorf("expected 1 orphaned reaction removed, got %d", res.Reactions)
+	}
+	if res.Invites != 1 {
+		t.Errorf("expected 1 expired invite removed, got %d", res.Invites)
+	}
+	if res.PasswordResets != 1 {
+		t.Errorf("expected 1 expired password reset removed, got %d", res.PasswordResets)
+	}
+
+	reactions, err := messageRepo.GetReactionsForMessage(ctx, live.ID, nil)
+	if err != nil {
+		t.Fatalf("GetReactionsForMessage: %v", err)
+	}
+	if len(reactions) != 1 {
+		t.Errorf("expected reaction on live message to be kept, got %d", len(reactions))
+	}
+
+	// A second pass has nothing left to do
+	res, err = c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if res.Reactions != 0 || res.Invites != 0 || res.PasswordResets != 0 || res.SearchIndexRebuilt {
+		t.Errorf("expected second pass to be a no-op, got %+v", res)
+	}
+}
+
+func TestCollect_ExpiredUploadSessions(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	c, dir := newTestCollector(t, db)
+
+	user := testutil.CreateTestUser(t, db, "gc@example.com", "GC User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "GC Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", "public")
+
+	files := file.NewRepository(db)
+	newSession := func(expiresAt time.Time) *file.UploadSession {
+		t.Helper()
+		s := &file.UploadSession{ChannelID: ch.ID, UserID: user.ID, Filename: "big.bin", ContentType: "application/octet-stream", SizeBytes: 100, ExpiresAt: expiresAt}
+		if err := files.CreateUploadSession(ctx, s); err != nil {
+			t.Fatalf("CreateUploadSession: %v", err)
+		}
+		chunk := file.UploadChunk{Offset: 0, SizeBytes: 5, StoragePath: "uploads/" + s.ID + "/0"}
+		if err := c.store.Put(ctx, chunk.StoragePath, bytes.NewReader([]byte("chunk")), 5, "application/octet-stream"); err != nil {
+			t.Fatalf("storing chunk: %v", err)
+		}
+		if err := files.AppendUploadChunk(ctx, s.ID, chunk, expiresAt); err != nil {
+			t.Fatalf("AppendUploadChunk: %v", err)
+		}
+		return s
+	}
+
+	expired := newSession(time.Now().Add(-time.Hour))
+	active := newSession(time.Now().Add(time.Hour))
+
+	res, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if res.UploadSessions != 1 || res.ReclaimedBytes != 5 {
+		t.Fatalf("expected 1 upload session and 5 bytes reclaimed, got %+v", res)
+	}
+
+	if _, err := files.GetUploadSession(ctx, expired.ID); err != file.ErrUploadSessionNotFound {
+		t.Errorf("expected expired session to be deleted, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "uploads", expired.ID, "0")); !os.IsNotExist(err) {
+		t.Errorf("expected expired chunk to be removed, got %v", err)
+	}
+	if _, err := files.GetUploadSession(ctx, active.ID); err != nil {
+		t.Errorf("expected active session to be kept, got %v", err)
+	}
+}
+
+func TestCollect_AttachmentRetention(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	c, dir := newTestCollector(t, db)
+
+	user := testutil.CreateTestUser(t, db, "gc@example.com", "GC User")
+	retained := testutil.CreateTestWorkspace(t, db, user.ID, "Retained")
+	unlimited := testutil.CreateTestWorkspace(t, db, user.ID, "Unlimited")
+
+	settings := workspace.DefaultSettings()
+	settings.AttachmentRetentionDays = 30
+	if _, err := db.Exec(`UPDATE workspaces SET settings = ? WHERE id = ?`, settings.ToJSON(), retained.ID); err != nil {
+		t.Fatalf("updating settings: %v", err)
+	}
+
+	retainedCh := testutil.CreateTestChannel(t, db, retained.ID, user.ID, "general", "public")
+	unlimitedCh := testutil.CreateTestChannel(t, db, unlimited.ID, user.ID, "general", "public")
+	retainedMsg := testutil.CreateTestMessage(t, db, retainedCh.ID, user.ID, "old file")
+	unlimitedMsg := testutil.CreateTestMessage(t, db, unlimitedCh.ID, user.ID, "old file")
+
+	const age = 40 * 24 * time.Hour
+	expired := createAttachment(t, db, c.store, retainedCh.ID, user.ID, &retainedMsg.ID, age, []byte("expired"))
+	recent := createAttachment(t, db, c.store, retainedCh.ID, user.ID, &retainedMsg.ID, 24*time.Hour, []byte("recent"))
+	kept := createAttachment(t, db, c.store, unlimitedCh.ID, user.ID, &unlimitedMsg.ID, age, []byte("kept"))
+
+	res, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if res.ExpiredAttachments != 1 || res.ReclaimedBytes != int64(len("expired")) {
+		t.Fatalf("expected 1 expired attachment and %d bytes reclaimed, got %+v", len("expired"), res)
+	}
+
+	if exists(t, db, "attachments", expired) {
+		t.Error("expected attachment past the retention period to be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "attachments", expired)); !os.IsNotExist(err) {
+		t.Errorf("expected expired attachment file to be removed, got %v", err)
+	}
+	if !exists(t, db, "attachments", recent) {
+		t.Error("expected attachment within the retention period to be kept")
+	}
+	if !exists(t, db, "attachments", kept) {
+		t.Error("expected attachment in workspace without retention to be kept")
+	}
+}
+
+func TestCollect_DeletedMessages(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	c, dir := newTestCollector(t, db)
+	messageRepo := message.NewRepository(db)
+
+	user := testutil.CreateTestUser(t, db, "gc@example.com", "GC User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "GC WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", "public")
+	old := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "deleted long ago")
+	recent := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "deleted just now")
+	oldFile := createAttachment(t, db, c.store, ch.ID, user.ID, &old.ID, time.Hour, []byte("old"))
+	recentFile := createAttachment(t, db, c.store, ch.ID, user.ID, &recent.ID, time.Hour, []byte("recent"))
+
+	for _, id := range []string{old.ID, recent.ID} {
+		if err := messageRepo.Delete(ctx, id, user.ID); err != nil {
+			t.Fatalf("deleting message: %v", err)
+		}
+	}
+	longAgo := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`UPDATE messages SET deleted_at = ? WHERE id = ?`, longAgo, old.ID); err != nil {
+		t.Fatalf("backdating deletion: %v", err)
+	}
+
+	res, err := c.Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if res.DeletedMessages != 1 || res.DeletedAttachments != 1 {
+		t.Fatalf("expected 1 purged message and attachment, got %+v", res)
+	}
+
+	if exists(t, db, "attachments", oldFile) {
+		t.Error("expected attachment of message deleted past the undelete window to be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "attachments", oldFile)); !os.IsNotExist(err) {
+		t.Errorf("expected attachment file to be removed, got %v", err)
+	}
+	if !exists(t, db, "attachments", recentFile) {
+		t.Error("expected attachment of recently deleted message to be kept")
+	}
+
+	var content sql.NullString
+	if err := db.QueryRow(`SELECT deleted_content FROM messages WHERE id = ?`, old.ID).Scan(&content); err != nil {
+		t.Fatalf("reading message: %v", err)
+	}
+	if content.Valid {
+		t.Errorf("expected original content to be purged, got %q", content.String)
+	}
+	if err := db.QueryRow(`SELECT deleted_content FROM messages WHERE id = ?`, recent.ID).Scan(&content); err != nil {
+		t.Fatalf("reading message: %v", err)
+	}
+	if content.String != "deleted just now" {
+		t.Errorf("expected recently deleted content to be kept for restore, got %q", content.String)
+	}
+}
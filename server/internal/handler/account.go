@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/enzyme/server/internal/accountdeletion"
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// DeactivateMember blocks a member's account from signing in. Memberships and
+// messages are kept; the user's sessions are revoked and their SSE
+// connections closed, so they drop to offline.
+func (h *Handler) DeactivateMember(ctx context.Context, request openapi.DeactivateMemberRequestObject) (openapi.DeactivateMemberResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeactivateMember401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	targetUserID := request.Body.UserId
+	if targetUserID == userID {
+		return openapi.DeactivateMember400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot deactivate yourself")}, nil
+	}
+
+	target, memberships, msg, err := h.accountAdminTarget(ctx, userID, string(request.Wid), targetUserID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.DeactivateMember404JSONResponse{NotFoundJSONResponse: notFoundResponse("User is not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+	if msg != "" {
+		return openapi.DeactivateMember403JSONResponse{ForbiddenJSONResponse: forbiddenResponse(msg)}, nil
+	}
+	if target.IsBot {
+		return openapi.DeactivateMember400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Bots are managed from the bot settings")}, nil
+	}
+	if target.Status == user.StatusDeactivated {
+		return openapi.DeactivateMember400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "User is already deactivated")}, nil
+	}
+
+	target.Status = user.StatusDeactivated
+	if err := h.userRepo.Update(ctx, target); err != nil {
+		return nil, err
+	}
+	revoked := h.signOutEverywhere(ctx, target.ID, memberships)
+
+	if err := h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, string(request.Wid), userID, moderation.ActionUserDeactivated, moderation.TargetTypeUser, target.ID, map[string]interface{}{
+		"sessions_revoked": revoked,
+	}); err != nil {
+		slog.Error("failed to create audit log entry for deactivation", "error", err)
+	}
+
+	return openapi.DeactivateMember200JSONResponse{Success: true}, nil
+}
+
+// ReactivateMember lets a deactivated member sign in again. Accounts whose
+// owner asked for them to be deleted stay deactivated.
+func (h *Handler) ReactivateMember(ctx context.Context, request openapi.ReactivateMemberRequestObject) (openapi.ReactivateMemberResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ReactivateMember401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	target, _, msg, err := h.accountAdminTarget(ctx, userID, string(request.Wid), request.Body.UserId)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ReactivateMember404JSONResponse{NotFoundJSONResponse: notFoundResponse("User is not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+	if msg != "" {
+		return openapi.ReactivateMember403JSONResponse{ForbiddenJSONResponse: forbiddenResponse(msg)}, nil
+	}
+	if target.Status != user.StatusDeactivated {
+		return openapi.ReactivateMember400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "User is not deactivated")}, nil
+	}
+	deleted, err := h.accountDeletionRepo.IsRequested(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if deleted {
+		return openapi.ReactivateMember400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "This account has been deleted")}, nil
+	}
+
+	target.Status = user.StatusActive
+	if err := h.userRepo.Update(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, string(request.Wid), userID, moderation.ActionUserReactivated, moderation.TargetTypeUser, target.ID, nil); err != nil {
+		slog.Error("failed to create audit log entry for reactivation", "error", err)
+	}
+
+	return openapi.ReactivateMember200JSONResponse{Success: true}, nil
+}
+
+// accountAdminTarget loads the target of an account-wide admin action.
+// Deactivation applies to every workspace the target belongs to, so the actor
+// must be an admin here and strictly outrank the target in each of them. A
+// non-empty message means the actor is not allowed; workspace.ErrNotAMember
+// means the target is not in this workspace.
+func (h *Handler) accountAdminTarget(ctx context.Context, actorID, workspaceID, targetID string) (*user.User, []workspace.Membership, string, error) {
+	actor, err := h.workspaceRepo.GetMembership(ctx, actorID, workspaceID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return nil, nil, "Not a workspace member", nil
+		}
+		return nil, nil, "", err
+	}
+	if !workspace.CanManageMembers(actor.Role) {
+		return nil, nil, "Only admins can deactivate or reactivate accounts", nil
+	}
+
+	memberships, err := h.workspaceRepo.ListMembershipsForUser(ctx, targetID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	inWorkspace := false
+	for _, m := range memberships {
+		actorRole := ""
+		if m.WorkspaceID == workspaceID {
+			inWorkspace = true
+			actorRole = actor.Role
+		} else if am, err := h.workspaceRepo.GetMembership(ctx, actorID, m.WorkspaceID); err == nil {
+			actorRole = am.Role
+		} else if !errors.Is(err, workspace.ErrNotAMember) {
+			return nil, nil, "", err
+		}
+		if actorRole == "" || workspace.RoleRank(actorRole) <= workspace.RoleRank(m.Role) {
+			return nil, nil, "User belongs to workspaces where you do not outrank them", nil
+		}
+	}
+	if !inWorkspace {
+		return nil, nil, "", workspace.ErrNotAMember
+	}
+
+	target, err := h.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return target, memberships, "", nil
+}
+
+// signOutEverywhere revokes all of a user's sessions and closes their SSE
+// connections. Returns the number of sessions revoked.
+func (h *Handler) signOutEverywhere(ctx context.Context, userID string, memberships []workspace.Membership) int {
+	revoked, err := h.sessionStore.DeleteAllForUser(userID)
+	if err != nil {
+		slog.Error("failed to revoke sessions", "user_id", userID, "error", err)
+	}
+	if h.hub != nil {
+		for _, m := range memberships {
+			h.hub.DisconnectUserClients(m.WorkspaceID, userID)
+		}
+	}
+	return revoked
+}
+
+// DeleteAccount deactivates the current user's account and queues it for
+// anonymization by the account deletion worker.
+func (h *Handler) DeleteAccount(ctx context.Context, request openapi.DeleteAccountRequestObject) (openapi.DeleteAccountResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteAccount401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	u, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.IsBot {
+		return openapi.DeleteAccount400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Bot accounts cannot be deleted this way")}, nil
+	}
+	if !auth.CheckPassword(request.Body.Password, u.PasswordHash) {
+		return openapi.DeleteAccount400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Incorrect password")}, nil
+	}
+
+	memberships, err := h.workspaceRepo.ListMembershipsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memberships {
+		if m.Role != workspace.RoleOwner {
+			continue
+		}
+		owners, err := h.workspaceRepo.CountOwners(ctx, m.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if owners <= 1 {
+			return openapi.DeleteAccount400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Transfer ownership of your workspaces before deleting your account")}, nil
+		}
+	}
+
+	deletion := &accountdeletion.Deletion{UserID: userID}
+	if err := h.accountDeletionRepo.Create(ctx, deletion); err != nil {
+		if errors.Is(err, accountdeletion.ErrAlreadyRequested) {
+			return openapi.DeleteAccount409JSONResponse(newErrorResponse(ErrCodeConflict, "Account deletion was already requested")), nil
+		}
+		return nil, err
+	}
+
+	u.Status = user.StatusDeactivated
+	if err := h.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	h.signOutEverywhere(ctx, userID, memberships)
+
+	return openapi.DeleteAccount202JSONResponse{
+		Deletion: openapi.AccountDeletion{
+			Id:        deletion.ID,
+			Status:    openapi.AccountDeletionStatus(deletion.Status),
+			CreatedAt: deletion.CreatedAt,
+		},
+	}, nil
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/user"
+)
+
+func TestDeactivateMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	admin := testutil.CreateTestUser(t, db, "admin@test.com", "Admin")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, admin.ID, ws.ID, "admin")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	memberCtx := ctxWithUser(t, h, member.ID)
+	adminCtx := ctxWithUser(t, h, admin.ID)
+
+	deactivate := func(ctx context.Context, userID string) openapi.DeactivateMemberResponseObject {
+		t.Helper()
+		resp, err := h.DeactivateMember(ctx, openapi.DeactivateMemberRequestObject{
+			Wid:  ws.ID,
+			Body: &openapi.DeactivateMemberJSONRequestBody{UserId: userID},
+		})
+		if err != nil {
+			t.Fatalf("DeactivateMember: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := deactivate(memberCtx, admin.ID).(openapi.DeactivateMember403JSONResponse); !ok {
+		t.Fatal("expected members to be unable to deactivate")
+	}
+	if _, ok := deactivate(adminCtx, owner.ID).(openapi.DeactivateMember403JSONResponse); !ok {
+		t.Fatal("expected admins to be unable to deactivate the owner")
+	}
+
+	// Deactivation is account-wide, so it is refused while the member owns
+	// a workspace the admin is not part of
+	other := testutil.CreateTestWorkspace(t, db, member.ID, "Other")
+	if _, ok := deactivate(adminCtx, member.ID).(openapi.DeactivateMember403JSONResponse); !ok {
+		t.Fatal("expected 403 for a user who owns another workspace")
+	}
+	if _, err := db.Exec(`DELETE FROM workspace_memberships WHERE workspace_id = ?`, other.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := deactivate(adminCtx, member.ID).(openapi.DeactivateMember200JSONResponse); !ok {
+		t.Fatal("expected admin to deactivate a member")
+	}
+	u, _ := h.userRepo.GetByID(context.Background(), member.ID)
+	if u.Status != user.StatusDeactivated {
+		t.Errorf("status = %q, want deactivated", u.Status)
+	}
+	if _, err := h.sessionStore.Validate(auth.GetToken(memberCtx)); err == nil {
+		t.Error("expected the member's sessions to be revoked")
+	}
+	if _, err := h.workspaceRepo.GetMembership(context.Background(), member.ID, ws.ID); err != nil {
+		t.Errorf("expected membership to be kept, got %v", err)
+	}
+
+	if _, ok := deactivate(adminCtx, member.ID).(openapi.DeactivateMember400JSONResponse); !ok {
+		t.Fatal("expected 400 when already deactivated")
+	}
+
+	resp, err := h.ReactivateMember(adminCtx, openapi.ReactivateMemberRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.ReactivateMemberJSONRequestBody{UserId: member.ID},
+	})
+	if err != nil {
+		t.Fatalf("ReactivateMember: %v", err)
+	}
+	if _, ok := resp.(openapi.ReactivateMember200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	u, _ = h.userRepo.GetByID(context.Background(), member.ID)
+	if u.Status != user.StatusActive {
+		t.Errorf("status = %q, want active", u.Status)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	del := func(userID, password string) openapi.DeleteAccountResponseObject {
+		t.Helper()
+		resp, err := h.DeleteAccount(ctxWithUser(t, h, userID), openapi.DeleteAccountRequestObject{
+			Body: &openapi.DeleteAccountJSONRequestBody{Password: password},
+		})
+		if err != nil {
+			t.Fatalf("DeleteAccount: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := del(owner.ID, "password123").(openapi.DeleteAccount400JSONResponse); !ok {
+		t.Fatal("expected the sole owner to be unable to delete their account")
+	}
+	if _, ok := del(member.ID, "wrong").(openapi.DeleteAccount400JSONResponse); !ok {
+		t.Fatal("expected 400 for a wrong password")
+	}
+
+	resp, ok := del(member.ID, "password123").(openapi.DeleteAccount202JSONResponse)
+	if !ok {
+		t.Fatal("expected the deletion to be queued")
+	}
+	if resp.Deletion.Status != openapi.AccountDeletionStatusPending {
+		t.Errorf("status = %q, want pending", resp.Deletion.Status)
+	}
+	u, _ := h.userRepo.GetByID(context.Background(), member.ID)
+	if u.Status != user.StatusDeactivated {
+		t.Errorf("expected the account to be deactivated immediately, got %q", u.Status)
+	}
+
+	// A deleted account cannot be reactivated by an admin
+	reactivate, err := h.ReactivateMember(ctxWithUser(t, h, owner.ID), openapi.ReactivateMemberRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.ReactivateMemberJSONRequestBody{UserId: member.ID},
+	})
+	if err != nil {
+		t.Fatalf("ReactivateMember: %v", err)
+	}
+	if _, ok := reactivate.(openapi.ReactivateMember400JSONResponse); !ok {
+		t.Fatalf("expected 400 reactivating a deleted account, got %T", reactivate)
+	}
+}
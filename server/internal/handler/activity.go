@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/enzyme/server/internal/activity"
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+)
+
+// maxActivityMarkRead bounds the number of activity IDs in one mark-read request
+const maxActivityMarkRead = 100
+
+// ListActivity lists activity addressed to the current user in a workspace
+func (h *Handler) ListActivity(ctx context.Context, request openapi.ListActivityRequestObject) (openapi.ListActivityResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListActivity401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		return openapi.ListActivity403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	opts := activity.ListOptions{}
+	params := request.Params
+	if params.Cursor != nil {
+		opts.Cursor = *params.Cursor
+	}
+	if params.Limit != nil {
+		opts.Limit = *params.Limit
+	}
+	if params.UnreadOnly != nil {
+		opts.UnreadOnly = *params.UnreadOnly
+	}
+
+	filter := &moderation.FilterOptions{WorkspaceID: workspaceID, RequestingUserID: userID}
+	result, err := h.activityRepo.List(ctx, workspaceID, userID, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := h.activityRepo.CountUnread(ctx, workspaceID, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]openapi.ActivityItem, len(result.Items))
+	for i := range result.Items {
+		items[i] = activityItemToAPI(&result.Items[i])
+	}
+
+	resp := openapi.ListActivity200JSONResponse{
+		Items:       items,
+		HasMore:     result.HasMore,
+		UnreadCount: unread,
+	}
+	if result.HasMore {
+		resp.NextCursor = &result.NextCursor
+	}
+	return resp, nil
+}
+
+// MarkActivityRead marks some or all of the current user's activity as read
+func (h *Handler) MarkActivityRead(ctx context.Context, request openapi.MarkActivityReadRequestObject) (openapi.MarkActivityReadResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.MarkActivityRead401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		return openapi.MarkActivityRead403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	var ids []string
+	if request.Body != nil && request.Body.ActivityIds != nil {
+		ids = *request.Body.ActivityIds
+		if len(ids) == 0 {
+			return openapi.MarkActivityRead400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "activity_ids must not be empty")}, nil
+		}
+		if len(ids) > maxActivityMarkRead {
+			return openapi.MarkActivityRead400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("At most %d activity IDs are allowed", maxActivityMarkRead))}, nil
+		}
+	}
+
+	if err := h.activityRepo.MarkRead(ctx, workspaceID, userID, ids); err != nil {
+		return nil, err
+	}
+
+	unread, err := h.activityRepo.CountUnread(ctx, workspaceID, userID, &moderation.FilterOptions{WorkspaceID: workspaceID, RequestingUserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	return openapi.MarkActivityRead200JSONResponse{UnreadCount: unread}, nil
+}
+
+// recordMessageActivity records activity for a new message: a mention for
+// each user it mentions by name, and a reply for the author of the thread it
+// belongs to unless they were mentioned as well.
+func (h *Handler) recordMessageActivity(ctx context.Context, ch *channel.Channel, msg *message.Message, threadParent *message.Message, mentions []string) {
+	if msg.UserID == nil {
+		return
+	}
+
+	mentioned := make(map[string]bool)
+	for _, userID := range mentions {
+		if notification.IsSpecialMention(userID) || mentioned[userID] {
+			continue
+		}
+		mentioned[userID] = true
+		h.recordActivity(ctx, ch, &activity.Activity{
+			UserID:    userID,
+			ActorID:   msg.UserID,
+			Type:      activity.TypeMention,
+			MessageID: &msg.ID,
+		})
+	}
+
+	if threadParent != nil && threadParent.UserID != nil && !mentioned[*threadParent.UserID] {
+		h.recordActivity(ctx, ch, &activity.Activity{
+			UserID:    *threadParent.UserID,
+			ActorID:   msg.UserID,
+			Type:      activity.TypeThreadReply,
+			MessageID: &msg.ID,
+		})
+	}
+}
+
+// recordReactionActivity records a reaction for the author of the message.
+func (h *Handler) recordReactionActivity(ctx context.Context, ch *channel.Channel, msg *message.Message, userID, emoji string) {
+	if msg.UserID == nil {
+		return
+	}
+	h.recordActivity(ctx, ch, &activity.Activity{
+		UserID:    *msg.UserID,
+		ActorID:   &userID,
+		Type:      activity.TypeReaction,
+		MessageID: &msg.ID,
+		Emoji:     &emoji,
+	})
+}
+
+// recordActivity stores an activity in ch and pushes it to the recipient's
+// clients. Nothing is recorded for the actor's own actions, between users who
+// have blocked each other, or for a recipient who cannot read the channel.
+// Failures are only logged: activity is secondary to the action that caused it.
+func (h *Handler) recordActivity(ctx context.Context, ch *channel.Channel, a *activity.Activity) {
+	if h.activityRepo == nil || a.ActorID == nil || *a.ActorID == a.UserID {
+		return
+	}
+
+	blocked, err := h.moderationRepo.IsBlockedEitherDirection(ctx, ch.WorkspaceID, *a.ActorID, a.UserID)
+	if err != nil {
+		slog.Error("failed to check blocks for activity", "type", a.Type, "error", err)
+		return
+	}
+	if blocked || !h.canReadChannel(ctx, ch, a.UserID) {
+		return
+	}
+
+	a.WorkspaceID = ch.WorkspaceID
+	a.ChannelID = ch.ID
+	if err := h.activityRepo.Create(ctx, a); err != nil {
+		slog.Error("failed to record activity", "type", a.Type, "error", err)
+		return
+	}
+
+	if h.hub != nil {
+		item, err := h.activityRepo.GetItem(ctx, a.ID)
+		if err != nil {
+			slog.Error("failed to load activity for broadcast", "activity_id", a.ID, "error", err)
+			return
+		}
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, a.UserID, sse.NewActivityNewEvent(activityItemToAPI(item)))
+	}
+}
+
+// canReadChannel reports whether the user is a member of ch, or a workspace
+// member when ch is public.
+func (h *Handler) canReadChannel(ctx context.Context, ch *channel.Channel, userID string) bool {
+	if _, err := h.channelRepo.GetMembership(ctx, userID, ch.ID); err == nil {
+		return true
+	}
+	if ch.Type != channel.TypePublic {
+		return false
+	}
+	_, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	return err == nil
+}
+
+func activityItemToAPI(item *activity.Item) openapi.ActivityItem {
+	return openapi.ActivityItem{
+		Id:               item.ID,
+		Type:             openapi.ActivityType(item.Type),
+		WorkspaceId:      item.WorkspaceID,
+		ChannelId:        item.ChannelID,
+		ChannelName:      item.ChannelName,
+		ChannelType:      openapi.ChannelType(item.ChannelType),
+		MessageId:        item.MessageID,
+		ThreadParentId:   item.ThreadParentID,
+		MessageContent:   item.MessageContent,
+		Emoji:            item.Emoji,
+		ActorId:          item.ActorID,
+		ActorDisplayName: item.ActorDisplayName,
+		ActorAvatarUrl:   item.ActorAvatarURL,
+		IsRead:           item.ReadAt != nil,
+		CreatedAt:        item.CreatedAt,
+	}
+}
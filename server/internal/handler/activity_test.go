@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func listActivity(t *testing.T, h *Handler, userID, workspaceID string) openapi.ListActivity200JSONResponse {
+	t.Helper()
+
+	resp, err := h.ListActivity(ctxWithUser(t, h, userID), openapi.ListActivityRequestObject{Wid: workspaceID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListActivity200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	return r
+}
+
+func TestListActivity_MentionsRepliesAndReactions(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, other.ID, ch.ID, nil)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Thread parent")
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+	otherCtx := ctxWithUser(t, h, other.ID)
+
+	// A mention of the thread's author counts once, as a mention
+	for _, content := range []string{"<@" + owner.ID + "> take a look", "plain reply"} {
+		if _, err := h.SendMessage(otherCtx, openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content, ThreadParentId: &parent.ID},
+		}); err != nil {
+			t.Fatalf("sending reply: %v", err)
+		}
+	}
+	if _, err := h.AddReaction(otherCtx, openapi.AddReactionRequestObject{
+		Id:   parent.ID,
+		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
+	}); err != nil {
+		t.Fatalf("adding reaction: %v", err)
+	}
+	// Reacting to your own message is not activity
+	if _, err := h.AddReaction(ctxWithUser(t, h, owner.ID), openapi.AddReactionRequestObject{
+		Id:   parent.ID,
+		Body: &openapi.AddReactionJSONRequestBody{Emoji: "🎉"},
+	}); err != nil {
+		t.Fatalf("adding own reaction: %v", err)
+	}
+
+	var activityEvents int
+	timeout := time.After(time.Second)
+	for activityEvents < 3 {
+		select {
+		case ev := <-client.Send:
+			if strings.Contains(string(ev.Frame), `"type":"`+sse.EventActivityNew+`"`) {
+				activityEvents++
+			}
+		case <-timeout:
+			t.Fatalf("received %d activity.new events, want 3", activityEvents)
+		}
+	}
+
+	r := listActivity(t, h, owner.ID, ws.ID)
+	var types []openapi.ActivityType
+	for _, item := range r.Items {
+		types = append(types, item.Type)
+	}
+	want := []openapi.ActivityType{openapi.ActivityTypeReaction, openapi.ActivityTypeThreadReply, openapi.ActivityTypeMention}
+	if len(types) != len(want) {
+		t.Fatalf("activity types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("activity types = %v, want %v", types, want)
+		}
+	}
+	if r.UnreadCount != 3 {
+		t.Errorf("unread_count = %d, want 3", r.UnreadCount)
+	}
+	if r.Items[0].ActorId == nil || *r.Items[0].ActorId != other.ID {
+		t.Errorf("actor_id = %v, want %s", r.Items[0].ActorId, other.ID)
+	}
+
+	// Removing the reaction removes its activity
+	if _, err := h.RemoveReaction(otherCtx, openapi.RemoveReactionRequestObject{
+		Id:   parent.ID,
+		Body: &openapi.RemoveReactionJSONRequestBody{Emoji: "👍"},
+	}); err != nil {
+		t.Fatalf("removing reaction: %v", err)
+	}
+	if r := listActivity(t, h, owner.ID, ws.ID); len(r.Items) != 2 {
+		t.Errorf("activity after removing reaction = %d items, want 2", len(r.Items))
+	}
+
+	if got := listActivity(t, h, other.ID, ws.ID); len(got.Items) != 0 {
+		t.Errorf("sender has %d activity items, want 0", len(got.Items))
+	}
+}
+
+func TestListActivity_ChannelInvite(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+
+	if _, err := h.AddChannelMember(ctxWithUser(t, h, owner.ID), openapi.AddChannelMemberRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.AddChannelMemberJSONRequestBody{UserId: other.ID},
+	}); err != nil {
+		t.Fatalf("adding member: %v", err)
+	}
+
+	r := listActivity(t, h, other.ID, ws.ID)
+	if len(r.Items) != 1 || r.Items[0].Type != openapi.ActivityTypeChannelInvite {
+		t.Fatalf("activity = %+v, want one channel invite", r.Items)
+	}
+	if r.Items[0].ChannelName != "secret" {
+		t.Errorf("channel_name = %q, want secret", r.Items[0].ChannelName)
+	}
+
+	// Once removed from the private channel, the invite is no longer listed
+	if _, err := db.Exec(`DELETE FROM channel_memberships WHERE user_id = ? AND channel_id = ?`, other.ID, ch.ID); err != nil {
+		t.Fatalf("removing membership: %v", err)
+	}
+	if r := listActivity(t, h, other.ID, ws.ID); len(r.Items) != 0 {
+		t.Errorf("activity after leaving = %d items, want 0", len(r.Items))
+	}
+}
+
+func TestMarkActivityRead(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, other.ID, ch.ID, nil)
+
+	otherCtx := ctxWithUser(t, h, other.ID)
+	for i := 0; i < 2; i++ {
+		content := "hey <@" + owner.ID + ">"
+		if _, err := h.SendMessage(otherCtx, openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+		}); err != nil {
+			t.Fatalf("sending message: %v", err)
+		}
+	}
+
+	items := listActivity(t, h, owner.ID, ws.ID).Items
+	if len(items) != 2 {
+		t.Fatalf("activity = %d items, want 2", len(items))
+	}
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	ids := []string{items[0].Id}
+	resp, err := h.MarkActivityRead(ctx, openapi.MarkActivityReadRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.MarkActivityReadJSONRequestBody{ActivityIds: &ids},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := resp.(openapi.MarkActivityRead200JSONResponse); !ok || r.UnreadCount != 1 {
+		t.Fatalf("mark one read = %#v, want unread_count 1", resp)
+	}
+	if items := listActivity(t, h, owner.ID, ws.ID).Items; !items[0].IsRead || items[1].IsRead {
+		t.Errorf("is_read = %v, %v, want true, false", items[0].IsRead, items[1].IsRead)
+	}
+
+	resp, err = h.MarkActivityRead(ctx, openapi.MarkActivityReadRequestObject{Wid: ws.ID, Body: &openapi.MarkActivityReadJSONRequestBody{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := resp.(openapi.MarkActivityRead200JSONResponse); !ok || r.UnreadCount != 0 {
+		t.Fatalf("mark all read = %#v, want unread_count 0", resp)
+	}
+}
+
+func TestListActivity_NotAMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+	resp, err := h.ListActivity(ctxWithUser(t, h, outsider.ID), openapi.ListActivityRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ListActivity403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
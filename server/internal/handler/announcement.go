@@ -0,0 +1,373 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/announcement"
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+)
+
+const maxAnnouncementTitleLength = 200
+
+func announcementToAPI(a *announcement.Announcement) openapi.Announcement {
+	apiA := openapi.Announcement{
+		Id:                a.ID,
+		WorkspaceId:       a.WorkspaceID,
+		CreatedBy:         a.CreatedBy,
+		Title:             a.Title,
+		Content:           a.Content,
+		ChannelIds:        a.ChannelIDs,
+		ScheduledFor:      a.ScheduledFor,
+		Status:            openapi.AnnouncementStatus(a.Status),
+		SentAt:            a.SentAt,
+		RecipientCount:    a.RecipientCount,
+		AcknowledgedCount: a.AcknowledgedCount,
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+	}
+	if apiA.ChannelIds == nil {
+		apiA.ChannelIds = []string{}
+	}
+	if a.LastError != "" {
+		apiA.LastError = &a.LastError
+	}
+	return apiA
+}
+
+func announcementRecipientToAPI(r *announcement.Recipient) openapi.AnnouncementRecipient {
+	return openapi.AnnouncementRecipient{
+		UserId:         r.UserID,
+		DisplayName:    r.DisplayName,
+		AvatarUrl:      r.AvatarURL,
+		AcknowledgedAt: r.AcknowledgedAt,
+	}
+}
+
+// CreateAnnouncement schedules an announcement, delivering it right away if
+// no future delivery time is given
+func (h *Handler) CreateAnnouncement(ctx context.Context, request openapi.CreateAnnouncementRequestObject) (openapi.CreateAnnouncementResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateAnnouncement401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if !h.canManageIntegrations(ctx, userID, workspaceID) {
+		return openapi.CreateAnnouncement403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can send announcements")}, nil
+	}
+
+	title := strings.TrimSpace(request.Body.Title)
+	content := strings.TrimSpace(request.Body.Content)
+	if title == "" {
+		return openapi.CreateAnnouncement400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Title is required")}, nil
+	}
+	if utf8.RuneCountInString(title) > maxAnnouncementTitleLength {
+		return openapi.CreateAnnouncement400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Title exceeds maximum length of %d characters", maxAnnouncementTitleLength))}, nil
+	}
+	if content == "" {
+		return openapi.CreateAnnouncement400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Content is required")}, nil
+	}
+	if utf8.RuneCountInString(announcementMessageContent(title, content)) > h.maxMessageLength {
+		return openapi.CreateAnnouncement400JSONResponse{BadRequestJSONResponse: messageTooLongResponse("Announcement", h.maxMessageLength)}, nil
+	}
+
+	var channelIDs []string
+	if request.Body.ChannelIds != nil {
+		seen := make(map[string]bool)
+		for _, channelID := range *request.Body.ChannelIds {
+			if seen[channelID] {
+				continue
+			}
+			seen[channelID] = true
+
+			msg, err := h.validateAnnouncementChannel(ctx, workspaceID, channelID)
+			if err != nil {
+				return nil, err
+			}
+			if msg != "" {
+				return openapi.CreateAnnouncement400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+			}
+			channelIDs = append(channelIDs, channelID)
+		}
+	}
+
+	scheduledFor := time.Now().UTC()
+	if request.Body.ScheduledFor != nil && request.Body.ScheduledFor.After(scheduledFor) {
+		scheduledFor = request.Body.ScheduledFor.UTC()
+	}
+
+	a := &announcement.Announcement{
+		WorkspaceID:  workspaceID,
+		CreatedBy:    &userID,
+		Title:        title,
+		Content:      content,
+		ChannelIDs:   channelIDs,
+		ScheduledFor: scheduledFor,
+	}
+	if err := h.announcementRepo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "announcement.created", "announcement", a.ID, map[string]interface{}{
+		"title":         a.Title,
+		"channel_ids":   a.ChannelIDs,
+		"scheduled_for": a.ScheduledFor.Format(time.RFC3339),
+	})
+
+	// Deliver immediately unless scheduled for later; the worker picks up the rest
+	if !a.ScheduledFor.After(time.Now().UTC()) {
+		if err := announcement.Send(ctx, h.announcementRepo, h, a); err != nil {
+			slog.Error("failed to deliver announcement", "component", "announcement", "id", a.ID, "error", err)
+		}
+		if refreshed, err := h.announcementRepo.GetByID(ctx, a.ID); err == nil {
+			a = refreshed
+		}
+	}
+
+	return openapi.CreateAnnouncement200JSONResponse{
+		Announcement: announcementToAPI(a),
+	}, nil
+}
+
+// ListAnnouncements lists the announcements of a workspace
+func (h *Handler) ListAnnouncements(ctx context.Context, request openapi.ListAnnouncementsRequestObject) (openapi.ListAnnouncementsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListAnnouncements401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if !h.canManageIntegrations(ctx, userID, workspaceID) {
+		return openapi.ListAnnouncements403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can view announcements")}, nil
+	}
+
+	announcements, err := h.announcementRepo.ListByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiAnnouncements := make([]openapi.Announcement, len(announcements))
+	for i := range announcements {
+		apiAnnouncements[i] = announcementToAPI(&announcements[i])
+	}
+
+	return openapi.ListAnnouncements200JSONResponse{
+		Announcements: apiAnnouncements,
+	}, nil
+}
+
+// CancelAnnouncement deletes an announcement that has not been delivered yet
+func (h *Handler) CancelAnnouncement(ctx context.Context, request openapi.CancelAnnouncementRequestObject) (openapi.CancelAnnouncementResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CancelAnnouncement401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	a, err := h.announcementRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, announcement.ErrAnnouncementNotFound) {
+			return openapi.CancelAnnouncement404JSONResponse{NotFoundJSONResponse: notFoundResponse("Announcement not found")}, nil
+		}
+		return nil, err
+	}
+
+	if !h.canManageIntegrations(ctx, userID, a.WorkspaceID) {
+		return openapi.CancelAnnouncement403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can cancel announcements")}, nil
+	}
+
+	if a.Status != announcement.StatusScheduled {
+		return openapi.CancelAnnouncement409JSONResponse{ConflictJSONResponse: conflictResponse("Announcement has already been delivered")}, nil
+	}
+
+	if err := h.announcementRepo.Delete(ctx, a.ID); err != nil {
+		// Lost the race with the delivery worker
+		if errors.Is(err, announcement.ErrAnnouncementNotFound) {
+			return openapi.CancelAnnouncement409JSONResponse{ConflictJSONResponse: conflictResponse("Announcement has already been delivered")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, a.WorkspaceID, userID, "announcement.cancelled", "announcement", a.ID, map[string]interface{}{
+		"title": a.Title,
+	})
+
+	return openapi.CancelAnnouncement200JSONResponse{Success: true}, nil
+}
+
+// AcknowledgeAnnouncement records that the current user has read an announcement
+func (h *Handler) AcknowledgeAnnouncement(ctx context.Context, request openapi.AcknowledgeAnnouncementRequestObject) (openapi.AcknowledgeAnnouncementResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.AcknowledgeAnnouncement401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if err := h.announcementRepo.Acknowledge(ctx, request.Id, userID); err != nil {
+		if errors.Is(err, announcement.ErrNotRecipient) {
+			if _, getErr := h.announcementRepo.GetByID(ctx, request.Id); errors.Is(getErr, announcement.ErrAnnouncementNotFound) {
+				return openapi.AcknowledgeAnnouncement404JSONResponse{NotFoundJSONResponse: notFoundResponse("Announcement not found")}, nil
+			}
+			return openapi.AcknowledgeAnnouncement403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are not a recipient of this announcement")}, nil
+		}
+		return nil, err
+	}
+
+	return openapi.AcknowledgeAnnouncement200JSONResponse{Success: true}, nil
+}
+
+// GetAnnouncementReport returns the acknowledgement status of every recipient
+func (h *Handler) GetAnnouncementReport(ctx context.Context, request openapi.GetAnnouncementReportRequestObject) (openapi.GetAnnouncementReportResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetAnnouncementReport401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	a, err := h.announcementRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, announcement.ErrAnnouncementNotFound) {
+			return openapi.GetAnnouncementReport404JSONResponse{NotFoundJSONResponse: notFoundResponse("Announcement not found")}, nil
+		}
+		return nil, err
+	}
+
+	if !h.canManageIntegrations(ctx, userID, a.WorkspaceID) {
+		return openapi.GetAnnouncementReport403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can view announcement reports")}, nil
+	}
+
+	recipients, err := h.announcementRepo.ListRecipients(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiRecipients := make([]openapi.AnnouncementRecipient, len(recipients))
+	for i := range recipients {
+		apiRecipients[i] = announcementRecipientToAPI(&recipients[i])
+	}
+
+	return openapi.GetAnnouncementReport200JSONResponse{
+		Announcement: announcementToAPI(a),
+		Recipients:   apiRecipients,
+	}, nil
+}
+
+// DeliverAnnouncement posts an announcement as an @channel message in each
+// target channel (the default channel for workspace-wide announcements) and
+// records its recipients. Called by the announcement worker.
+func (h *Handler) DeliverAnnouncement(ctx context.Context, a *announcement.Announcement) error {
+	if a.CreatedBy == nil {
+		return fmt.Errorf("announcement author no longer exists")
+	}
+
+	var targets []*channel.Channel
+	if a.TargetsWorkspace() {
+		ch, err := h.channelRepo.GetDefaultChannel(ctx, a.WorkspaceID)
+		if err != nil {
+			return fmt.Errorf("finding default channel: %w", err)
+		}
+		targets = append(targets, ch)
+	} else {
+		for _, channelID := range a.ChannelIDs {
+			ch, err := h.channelRepo.GetByID(ctx, channelID)
+			if err != nil {
+				if errors.Is(err, channel.ErrChannelNotFound) {
+					continue
+				}
+				return err
+			}
+			if ch.ArchivedAt != nil {
+				continue
+			}
+			targets = append(targets, ch)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no channels to deliver to")
+	}
+
+	senderName := ""
+	if sender, err := h.userRepo.GetByID(ctx, *a.CreatedBy); err == nil {
+		senderName = sender.DisplayName
+	}
+	content := announcementMessageContent(a.Title, a.Content)
+	mentions := []string{notification.MentionChannel}
+
+	for _, ch := range targets {
+		msg := &message.Message{
+			ChannelID:      ch.ID,
+			UserID:         a.CreatedBy,
+			Content:        content,
+			Mentions:       mentions,
+			AnnouncementID: &a.ID,
+		}
+		if err := h.messageRepo.Create(ctx, msg); err != nil {
+			return fmt.Errorf("creating message: %w", err)
+		}
+
+		msgWithUser, err := h.messageRepo.GetByIDWithUser(ctx, msg.ID)
+		if err != nil {
+			msgWithUser = &message.MessageWithUser{Message: *msg, UserDisplayName: senderName}
+		}
+
+		if h.hub != nil {
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(messageWithUserToAPI(msgWithUser)))
+		}
+
+		if h.notificationService != nil {
+			channelInfo := &notification.ChannelInfo{
+				ID:          ch.ID,
+				WorkspaceID: ch.WorkspaceID,
+				Name:        ch.Name,
+				Type:        ch.Type,
+			}
+			msgInfo := &notification.MessageInfo{
+				ID:         msg.ID,
+				ChannelID:  msg.ChannelID,
+				SenderID:   *a.CreatedBy,
+				SenderName: senderName,
+				Content:    msg.Content,
+				Mentions:   mentions,
+			}
+			h.notificationService.Enqueue(ctx, channelInfo, msgInfo)
+		}
+	}
+
+	if _, err := h.announcementRepo.SnapshotRecipients(ctx, a); err != nil {
+		return fmt.Errorf("recording recipients: %w", err)
+	}
+	return nil
+}
+
+// validateAnnouncementChannel returns a user-facing error message if the
+// channel cannot receive announcements, or "" if it can.
+func (h *Handler) validateAnnouncementChannel(ctx context.Context, workspaceID, channelID string) (string, error) {
+	ch, err := h.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return "Channel not found", nil
+		}
+		return "", err
+	}
+	if ch.WorkspaceID != workspaceID {
+		return "Channel not found", nil
+	}
+	if ch.Type != channel.TypePublic && ch.Type != channel.TypePrivate {
+		return "Announcements can only target public or private channels", nil
+	}
+	if ch.ArchivedAt != nil {
+		return "Cannot post to archived channel", nil
+	}
+	return "", nil
+}
+
+func announcementMessageContent(title, content string) string {
+	return "**" + title + "**\n\n" + content
+}
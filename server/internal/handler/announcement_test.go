@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+func TestCreateAnnouncement_RequiresAdmin(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, workspace.RoleMember)
+
+	ctx := ctxWithUser(t, h, member.ID)
+	resp, err := h.CreateAnnouncement(ctx, openapi.CreateAnnouncementRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateAnnouncementJSONRequestBody{Title: "Policy", Content: "Please read"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateAnnouncement403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestCreateAnnouncement_DeliversAndTracksAcknowledgements(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, workspace.RoleMember)
+	addWorkspaceMember(t, db, outsider.ID, ws.ID, workspace.RoleMember)
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "hr", "public")
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	channelIDs := []string{ch.ID}
+	resp, err := h.CreateAnnouncement(ownerCtx, openapi.CreateAnnouncementRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateAnnouncementJSONRequestBody{Title: "Policy", Content: "Please read", ChannelIds: &channelIDs},
+	})
+	if err != nil {
+		t.Fatalf("CreateAnnouncement: %v", err)
+	}
+	r, ok := resp.(openapi.CreateAnnouncement200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if r.Announcement.Status != openapi.AnnouncementStatusSent {
+		t.Fatalf("status = %q, want sent", r.Announcement.Status)
+	}
+	if r.Announcement.RecipientCount != 1 {
+		t.Errorf("recipient_count = %d, want 1", r.Announcement.RecipientCount)
+	}
+
+	var posted int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages WHERE channel_id = ? AND announcement_id = ?`, ch.ID, r.Announcement.Id).Scan(&posted); err != nil {
+		t.Fatalf("counting messages: %v", err)
+	}
+	if posted != 1 {
+		t.Errorf("announcement messages = %d, want 1", posted)
+	}
+
+	// Non-recipients cannot acknowledge
+	ackResp, err := h.AcknowledgeAnnouncement(ctxWithUser(t, h, outsider.ID), openapi.AcknowledgeAnnouncementRequestObject{Id: r.Announcement.Id})
+	if err != nil {
+		t.Fatalf("AcknowledgeAnnouncement: %v", err)
+	}
+	if _, ok := ackResp.(openapi.AcknowledgeAnnouncement403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", ackResp)
+	}
+
+	ackResp, err = h.AcknowledgeAnnouncement(ctxWithUser(t, h, member.ID), openapi.AcknowledgeAnnouncementRequestObject{Id: r.Announcement.Id})
+	if err != nil {
+		t.Fatalf("AcknowledgeAnnouncement: %v", err)
+	}
+	if _, ok := ackResp.(openapi.AcknowledgeAnnouncement200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", ackResp)
+	}
+
+	reportResp, err := h.GetAnnouncementReport(ownerCtx, openapi.GetAnnouncementReportRequestObject{Id: r.Announcement.Id})
+	if err != nil {
+		t.Fatalf("GetAnnouncementReport: %v", err)
+	}
+	report, ok := reportResp.(openapi.GetAnnouncementReport200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", reportResp)
+	}
+	if len(report.Recipients) != 1 || report.Recipients[0].UserId != member.ID || report.Recipients[0].AcknowledgedAt == nil {
+		t.Errorf("recipients = %+v, want member acknowledged", report.Recipients)
+	}
+	if report.Announcement.AcknowledgedCount != 1 {
+		t.Errorf("acknowledged_count = %d, want 1", report.Announcement.AcknowledgedCount)
+	}
+}
+
+func TestCancelAnnouncement(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ctx := ctxWithUser(t, h, owner.ID)
+
+	later := time.Now().Add(time.Hour)
+	resp, err := h.CreateAnnouncement(ctx, openapi.CreateAnnouncementRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateAnnouncementJSONRequestBody{Title: "Policy", Content: "Please read", ScheduledFor: &later},
+	})
+	if err != nil {
+		t.Fatalf("CreateAnnouncement: %v", err)
+	}
+	r := resp.(openapi.CreateAnnouncement200JSONResponse)
+	if r.Announcement.Status != openapi.AnnouncementStatusScheduled {
+		t.Fatalf("status = %q, want scheduled", r.Announcement.Status)
+	}
+
+	cancelResp, err := h.CancelAnnouncement(ctx, openapi.CancelAnnouncementRequestObject{Id: r.Announcement.Id})
+	if err != nil {
+		t.Fatalf("CancelAnnouncement: %v", err)
+	}
+	if _, ok := cancelResp.(openapi.CancelAnnouncement200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", cancelResp)
+	}
+
+	cancelResp, err = h.CancelAnnouncement(ctx, openapi.CancelAnnouncementRequestObject{Id: r.Announcement.Id})
+	if err != nil {
+		t.Fatalf("CancelAnnouncement: %v", err)
+	}
+	if _, ok := cancelResp.(openapi.CancelAnnouncement404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", cancelResp)
+	}
+}
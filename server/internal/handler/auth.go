@@ -9,6 +9,7 @@ import (
 
 	"github.com/enzyme/server/internal/auth"
 	"github.com/enzyme/server/internal/gravatar"
+	"github.com/enzyme/server/internal/logging"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/pushnotification"
 	"github.com/enzyme/server/internal/user"
@@ -28,13 +29,13 @@ func (h *Handler) Register(ctx context.Context, request openapi.RegisterRequestO
 		var code, msg string
 		switch {
 		case errors.Is(err, user.ErrEmailAlreadyInUse):
-			code, msg = "EMAIL_IN_USE", "Email is already registered"
+			code, msg = ErrCodeEmailInUse, "Email is already registered"
 		case errors.Is(err, auth.ErrPasswordTooShort):
-			code, msg = "PASSWORD_TOO_SHORT", "Password must be at least 8 characters"
+			code, msg = ErrCodePasswordTooShort, "Password must be at least 8 characters"
 		case errors.Is(err, auth.ErrDisplayNameRequired):
-			code, msg = "DISPLAY_NAME_REQUIRED", "Display name is required"
+			code, msg = ErrCodeDisplayNameRequired, "Display name is required"
 		case errors.Is(err, auth.ErrInvalidEmail):
-			code, msg = "INVALID_EMAIL", "Invalid email address"
+			code, msg = ErrCodeInvalidEmail, "Invalid email address"
 		default:
 			code, msg = ErrCodeInternalError, "An error occurred"
 		}
@@ -43,8 +44,8 @@ func (h *Handler) Register(ctx context.Context, request openapi.RegisterRequestO
 		}, nil
 	}
 
-	// Create session token
-	token, err := h.sessionStore.Create(u.ID)
+	// Create session tokens
+	tokens, err := h.sessionStore.Create(u.ID, clientInfo(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -57,18 +58,20 @@ func (h *Handler) Register(ctx context.Context, request openapi.RegisterRequestO
 			slog.Error("failed to create email verification token", "user_id", u.ID, "error", err)
 		} else {
 			go func() {
-				sendCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				sendCtx, cancel := context.WithTimeout(logging.Detach(ctx), 30*time.Second)
 				defer cancel()
 				if err := h.emailService.SendEmailVerification(sendCtx, u.Email, verifyToken); err != nil {
-					slog.Error("failed to send verification email", "user_id", u.ID, "error", err)
+					slog.ErrorContext(sendCtx, "failed to send verification email", "user_id", u.ID, "error", err)
 				}
 			}()
 		}
 	}
 
 	return openapi.Register200JSONResponse{
-		User:  userToAPI(u),
-		Token: token,
+		User:         userToAPI(u),
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
 	}, nil
 }
 
@@ -84,9 +87,9 @@ func (h *Handler) Login(ctx context.Context, request openapi.LoginRequestObject)
 		var code, msg string
 		switch {
 		case errors.Is(err, auth.ErrInvalidCredentials):
-			code, msg = "INVALID_CREDENTIALS", "Invalid email or password"
+			code, msg = ErrCodeInvalidCredentials, "Invalid email or password"
 		case errors.Is(err, auth.ErrUserDeactivated):
-			code, msg = "USER_DEACTIVATED", "Account is deactivated"
+			code, msg = ErrCodeUserDeactivated, "Account is deactivated"
 		default:
 			code, msg = ErrCodeInternalError, "An error occurred"
 		}
@@ -95,15 +98,43 @@ func (h *Handler) Login(ctx context.Context, request openapi.LoginRequestObject)
 		}, nil
 	}
 
-	// Create session token
-	token, err := h.sessionStore.Create(u.ID)
+	// Create session tokens
+	tokens, err := h.sessionStore.Create(u.ID, clientInfo(ctx))
 	if err != nil {
 		return nil, err
 	}
 
 	return openapi.Login200JSONResponse{
-		User:  userToAPI(u),
-		Token: token,
+		User:         userToAPI(u),
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair
+func (h *Handler) RefreshToken(ctx context.Context, request openapi.RefreshTokenRequestObject) (openapi.RefreshTokenResponseObject, error) {
+	tokens, err := h.sessionStore.Refresh(request.Body.RefreshToken)
+	if err != nil {
+		var code, msg string
+		switch {
+		case errors.Is(err, auth.ErrRefreshTokenReused):
+			slog.Warn("refresh token reused, session revoked")
+			code, msg = ErrCodeRefreshTokenReused, "Refresh token has already been used; the session has been revoked"
+		case errors.Is(err, auth.ErrSessionNotFound):
+			code, msg = ErrCodeInvalidRefreshToken, "Invalid or expired refresh token"
+		default:
+			return nil, err
+		}
+		return openapi.RefreshToken401JSONResponse{
+			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(code, msg)),
+		}, nil
+	}
+
+	return openapi.RefreshToken200JSONResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
 	}, nil
 }
 
@@ -126,7 +157,7 @@ func (h *Handler) GetMe(ctx context.Context, request openapi.GetMeRequestObject)
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.GetMe401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -134,7 +165,7 @@ func (h *Handler) GetMe(ctx context.Context, request openapi.GetMeRequestObject)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return openapi.GetMe401JSONResponse{
-				UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+				UnauthorizedJSONResponse: unauthorizedResponse(),
 			}, nil
 		}
 		return nil, err
@@ -175,7 +206,7 @@ func (h *Handler) GetMe(ctx context.Context, request openapi.GetMeRequestObject)
 func (h *Handler) ForgotPassword(ctx context.Context, request openapi.ForgotPasswordRequestObject) (openapi.ForgotPasswordResponseObject, error) {
 	if !h.emailService.IsEnabled() {
 		return openapi.ForgotPassword400JSONResponse{
-			BadRequestJSONResponse: badRequestResponse("EMAIL_NOT_ENABLED", "Email is not configured on this server"),
+			BadRequestJSONResponse: badRequestResponse(ErrCodeEmailNotEnabled, "Email is not configured on this server"),
 		}, nil
 	}
 
@@ -187,10 +218,10 @@ func (h *Handler) ForgotPassword(ctx context.Context, request openapi.ForgotPass
 	if token != "" {
 		emailAddr := string(request.Body.Email)
 		go func() {
-			sendCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			sendCtx, cancel := context.WithTimeout(logging.Detach(ctx), 30*time.Second)
 			defer cancel()
 			if err := h.emailService.SendPasswordReset(sendCtx, emailAddr, token); err != nil {
-				slog.Error("failed to send password reset email", "error", err)
+				slog.ErrorContext(sendCtx, "failed to send password reset email", "error", err)
 			}
 		}()
 	}
@@ -208,11 +239,11 @@ func (h *Handler) ResetPassword(ctx context.Context, request openapi.ResetPasswo
 		switch {
 		case errors.Is(err, auth.ErrInvalidResetToken):
 			return openapi.ResetPassword400JSONResponse{
-				BadRequestJSONResponse: badRequestResponse("INVALID_RESET_TOKEN", "Invalid or expired reset token"),
+				BadRequestJSONResponse: badRequestResponse(ErrCodeInvalidResetToken, "Invalid or expired reset token"),
 			}, nil
 		case errors.Is(err, auth.ErrPasswordTooShort):
 			return openapi.ResetPassword400JSONResponse{
-				BadRequestJSONResponse: badRequestResponse("PASSWORD_TOO_SHORT", "Password must be at least 8 characters"),
+				BadRequestJSONResponse: badRequestResponse(ErrCodePasswordTooShort, "Password must be at least 8 characters"),
 			}, nil
 		default:
 			return nil, err
@@ -230,7 +261,7 @@ func (h *Handler) VerifyEmail(ctx context.Context, request openapi.VerifyEmailRe
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidVerificationToken) {
 			return openapi.VerifyEmail400JSONResponse{
-				BadRequestJSONResponse: badRequestResponse("INVALID_VERIFICATION_TOKEN", "Invalid or expired verification token"),
+				BadRequestJSONResponse: badRequestResponse(ErrCodeInvalidVerificationToken, "Invalid or expired verification token"),
 			}, nil
 		}
 		return nil, err
@@ -244,13 +275,13 @@ func (h *Handler) ResendVerification(ctx context.Context, request openapi.Resend
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.ResendVerification401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
 	if !h.emailService.IsEnabled() {
 		return openapi.ResendVerification400JSONResponse{
-			BadRequestJSONResponse: badRequestResponse("EMAIL_NOT_ENABLED", "Email is not configured on this server"),
+			BadRequestJSONResponse: badRequestResponse(ErrCodeEmailNotEnabled, "Email is not configured on this server"),
 		}, nil
 	}
 
@@ -261,7 +292,7 @@ func (h *Handler) ResendVerification(ctx context.Context, request openapi.Resend
 
 	if u.EmailVerifiedAt != nil {
 		return openapi.ResendVerification400JSONResponse{
-			BadRequestJSONResponse: badRequestResponse("ALREADY_VERIFIED", "Email is already verified"),
+			BadRequestJSONResponse: badRequestResponse(ErrCodeAlreadyVerified, "Email is already verified"),
 		}, nil
 	}
 
@@ -271,10 +302,10 @@ func (h *Handler) ResendVerification(ctx context.Context, request openapi.Resend
 	}
 
 	go func() {
-		sendCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		sendCtx, cancel := context.WithTimeout(logging.Detach(ctx), 30*time.Second)
 		defer cancel()
 		if err := h.emailService.SendEmailVerification(sendCtx, u.Email, token); err != nil {
-			slog.Error("failed to send verification email", "user_id", userID, "error", err)
+			slog.ErrorContext(sendCtx, "failed to send verification email", "user_id", userID, "error", err)
 		}
 	}()
 
@@ -288,24 +319,24 @@ func (h *Handler) RegisterDeviceToken(ctx context.Context, request openapi.Regis
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.RegisterDeviceToken401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
 	if h.pushTokenRepo == nil {
 		return openapi.RegisterDeviceToken400JSONResponse{
-			BadRequestJSONResponse: openapi.BadRequestJSONResponse(newErrorResponse("PUSH_NOT_ENABLED", "Push notifications are not enabled on this server")),
+			BadRequestJSONResponse: openapi.BadRequestJSONResponse(newErrorResponse(ErrCodePushNotEnabled, "Push notifications are not enabled on this server")),
 		}, nil
 	}
 
 	if len(request.Body.Token) == 0 || len(request.Body.Token) > 4096 {
 		return openapi.RegisterDeviceToken400JSONResponse{
-			BadRequestJSONResponse: openapi.BadRequestJSONResponse(newErrorResponse("INVALID_TOKEN", "Token must be between 1 and 4096 characters")),
+			BadRequestJSONResponse: openapi.BadRequestJSONResponse(newErrorResponse(ErrCodeInvalidDeviceToken, "Token must be between 1 and 4096 characters")),
 		}, nil
 	}
 	if len(request.Body.DeviceId) == 0 || len(request.Body.DeviceId) > 256 {
 		return openapi.RegisterDeviceToken400JSONResponse{
-			BadRequestJSONResponse: openapi.BadRequestJSONResponse(newErrorResponse("INVALID_DEVICE_ID", "Device ID must be between 1 and 256 characters")),
+			BadRequestJSONResponse: openapi.BadRequestJSONResponse(newErrorResponse(ErrCodeInvalidDeviceID, "Device ID must be between 1 and 256 characters")),
 		}, nil
 	}
 
@@ -330,13 +361,13 @@ func (h *Handler) UnregisterDeviceToken(ctx context.Context, request openapi.Unr
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.UnregisterDeviceToken401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
 	if h.pushTokenRepo == nil {
 		return openapi.UnregisterDeviceToken404JSONResponse{
-			NotFoundJSONResponse: openapi.NotFoundJSONResponse(newErrorResponse("PUSH_NOT_ENABLED", "Push notifications are not enabled on this server")),
+			NotFoundJSONResponse: openapi.NotFoundJSONResponse(newErrorResponse(ErrCodePushNotEnabled, "Push notifications are not enabled on this server")),
 		}, nil
 	}
 
@@ -344,7 +375,7 @@ func (h *Handler) UnregisterDeviceToken(ctx context.Context, request openapi.Unr
 	if err != nil {
 		if errors.Is(err, pushnotification.ErrTokenNotFound) {
 			return openapi.UnregisterDeviceToken404JSONResponse{
-				NotFoundJSONResponse: openapi.NotFoundJSONResponse(newErrorResponse("TOKEN_NOT_FOUND", "Device token not found")),
+				NotFoundJSONResponse: openapi.NotFoundJSONResponse(newErrorResponse(ErrCodeDeviceTokenNotFound, "Device token not found")),
 			}, nil
 		}
 		return nil, err
@@ -369,6 +400,10 @@ func userToAPI(u *user.User) openapi.User {
 	if u.AvatarURL != nil {
 		apiUser.AvatarUrl = u.AvatarURL
 	}
+	apiUser.Title = u.Title
+	apiUser.Pronouns = u.Pronouns
+	apiUser.Timezone = u.Timezone
+	apiUser.Phone = u.Phone
 	if g := gravatar.URL(u.Email); g != "" {
 		apiUser.GravatarUrl = &g
 	}
@@ -135,6 +135,55 @@ func TestResendVerification_EmailDisabled(t *testing.T) {
 	}
 }
 
+func TestRefreshToken(t *testing.T) {
+	h, db := testHandler(t)
+	ctx := context.Background()
+	testutil.CreateTestUser(t, db, "refresh@example.com", "Refresh User")
+
+	loginResp, err := h.Login(ctx, openapi.LoginRequestObject{
+		Body: &openapi.LoginInput{Email: "refresh@example.com", Password: "password123"},
+	})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	login, ok := loginResp.(openapi.Login200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", loginResp)
+	}
+	if login.RefreshToken == "" || login.ExpiresAt.IsZero() {
+		t.Fatalf("expected a refresh token and expiry, got %+v", login)
+	}
+
+	refreshResp, err := h.RefreshToken(ctx, openapi.RefreshTokenRequestObject{
+		Body: &openapi.RefreshInput{RefreshToken: login.RefreshToken},
+	})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	refreshed, ok := refreshResp.(openapi.RefreshToken200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", refreshResp)
+	}
+	if refreshed.Token == login.Token || refreshed.RefreshToken == login.RefreshToken {
+		t.Error("expected both tokens to be rotated")
+	}
+
+	// Replaying the first refresh token revokes the session
+	replayResp, err := h.RefreshToken(ctx, openapi.RefreshTokenRequestObject{
+		Body: &openapi.RefreshInput{RefreshToken: login.RefreshToken},
+	})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	replay, ok := replayResp.(openapi.RefreshToken401JSONResponse)
+	if !ok || replay.Error.Code != "REFRESH_TOKEN_REUSED" {
+		t.Fatalf("expected REFRESH_TOKEN_REUSED, got %+v", replayResp)
+	}
+	if _, err := h.sessionStore.Validate(refreshed.Token); err == nil {
+		t.Error("expected the session to be revoked after reuse")
+	}
+}
+
 func TestNewErrorResponse(t *testing.T) {
 	resp := newErrorResponse("TEST_ERROR", "Test error message")
 
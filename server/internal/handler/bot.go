@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/bot"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+const (
+	maxBotNameLength      = 80
+	maxBotTokenNameLength = 80
+)
+
+func botToAPI(b *bot.Bot) openapi.Bot {
+	return openapi.Bot{
+		UserId:      b.UserID,
+		WorkspaceId: b.WorkspaceID,
+		DisplayName: b.DisplayName,
+		AvatarUrl:   b.AvatarURL,
+		Description: b.Description,
+		CreatedBy:   b.CreatedBy,
+		CreatedAt:   b.CreatedAt,
+	}
+}
+
+func botTokenToAPI(t *bot.Token) openapi.BotToken {
+	scopes := make([]openapi.BotScope, len(t.Scopes))
+	for i, s := range t.Scopes {
+		scopes[i] = openapi.BotScope(s)
+	}
+	return openapi.BotToken{
+		Id:         t.ID,
+		BotUserId:  t.BotUserID,
+		Name:       t.Name,
+		Scopes:     scopes,
+		CreatedBy:  t.CreatedBy,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// validateBotName trims and checks a bot display name. Returns a user-facing
+// message when it is invalid.
+func validateBotName(raw string) (string, string) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", "Display name is required"
+	}
+	if utf8.RuneCountInString(name) > maxBotNameLength {
+		return "", fmt.Sprintf("Display name exceeds maximum length of %d characters", maxBotNameLength)
+	}
+	return name, ""
+}
+
+// getManagedBot loads a bot and reports whether the user may manage it.
+// Returns a nil bot when it does not exist.
+func (h *Handler) getManagedBot(ctx context.Context, userID, botUserID string) (*bot.Bot, bool, error) {
+	b, err := h.botRepo.GetByUserID(ctx, botUserID)
+	if err != nil {
+		if errors.Is(err, bot.ErrBotNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return b, h.canManageIntegrations(ctx, userID, b.WorkspaceID), nil
+}
+
+// CreateBot creates a bot account in a workspace
+func (h *Handler) CreateBot(ctx context.Context, request openapi.CreateBotRequestObject) (openapi.CreateBotResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateBot401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if !h.canManageIntegrations(ctx, userID, workspaceID) {
+		return openapi.CreateBot403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	name, msg := validateBotName(request.Body.DisplayName)
+	if msg != "" {
+		return openapi.CreateBot400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	var avatarURL *string
+	if request.Body.AvatarUrl != nil && *request.Body.AvatarUrl != "" {
+		if !isValidImageURL(*request.Body.AvatarUrl) {
+			return openapi.CreateBot400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "avatar_url must be an http(s) URL")}, nil
+		}
+		avatarURL = request.Body.AvatarUrl
+	}
+
+	var description *string
+	if request.Body.Description != nil && strings.TrimSpace(*request.Body.Description) != "" {
+		d := strings.TrimSpace(*request.Body.Description)
+		description = &d
+	}
+
+	b := &bot.Bot{
+		WorkspaceID: workspaceID,
+		DisplayName: name,
+		AvatarURL:   avatarURL,
+		Description: description,
+		CreatedBy:   &userID,
+	}
+	if err := h.botRepo.Create(ctx, b); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "bot.created", "user", b.UserID, map[string]interface{}{
+		"display_name": b.DisplayName,
+	})
+
+	return openapi.CreateBot200JSONResponse{
+		Bot: botToAPI(b),
+	}, nil
+}
+
+// ListBots lists the bot accounts of a workspace
+func (h *Handler) ListBots(ctx context.Context, request openapi.ListBotsRequestObject) (openapi.ListBotsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListBots401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if !h.canManageIntegrations(ctx, userID, workspaceID) {
+		return openapi.ListBots403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	bots, err := h.botRepo.ListByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiBots := make([]openapi.Bot, len(bots))
+	for i := range bots {
+		apiBots[i] = botToAPI(&bots[i])
+	}
+
+	return openapi.ListBots200JSONResponse{
+		Bots: apiBots,
+	}, nil
+}
+
+// UpdateBot changes a bot's display name, avatar, or description
+func (h *Handler) UpdateBot(ctx context.Context, request openapi.UpdateBotRequestObject) (openapi.UpdateBotResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateBot401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	b, canManage, err := h.getManagedBot(ctx, userID, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return openapi.UpdateBot404JSONResponse{NotFoundJSONResponse: notFoundResponse("Bot not found")}, nil
+	}
+	if !canManage {
+		return openapi.UpdateBot403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	if request.Body.DisplayName != nil {
+		name, msg := validateBotName(*request.Body.DisplayName)
+		if msg != "" {
+			return openapi.UpdateBot400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+		}
+		b.DisplayName = name
+	}
+
+	if request.Body.AvatarUrl != nil {
+		if *request.Body.AvatarUrl == "" {
+			b.AvatarURL = nil
+		} else if !isValidImageURL(*request.Body.AvatarUrl) {
+			return openapi.UpdateBot400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "avatar_url must be an http(s) URL")}, nil
+		} else {
+			b.AvatarURL = request.Body.AvatarUrl
+		}
+	}
+
+	if request.Body.Description != nil {
+		if d := strings.TrimSpace(*request.Body.Description); d == "" {
+			b.Description = nil
+		} else {
+			b.Description = &d
+		}
+	}
+
+	if err := h.botRepo.Update(ctx, b); err != nil {
+		return nil, err
+	}
+
+	return openapi.UpdateBot200JSONResponse{
+		Bot: botToAPI(b),
+	}, nil
+}
+
+// DeleteBot deletes a bot account and revokes all of its tokens
+func (h *Handler) DeleteBot(ctx context.Context, request openapi.DeleteBotRequestObject) (openapi.DeleteBotResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteBot401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	b, canManage, err := h.getManagedBot(ctx, userID, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return openapi.DeleteBot404JSONResponse{NotFoundJSONResponse: notFoundResponse("Bot not found")}, nil
+	}
+	if !canManage {
+		return openapi.DeleteBot403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	if err := h.botRepo.Delete(ctx, b.UserID); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, b.WorkspaceID, userID, "bot.deleted", "user", b.UserID, map[string]interface{}{
+		"display_name": b.DisplayName,
+	})
+
+	return openapi.DeleteBot200JSONResponse{
+		Success: true,
+	}, nil
+}
+
+// CreateBotToken issues a scoped API token for a bot
+func (h *Handler) CreateBotToken(ctx context.Context, request openapi.CreateBotTokenRequestObject) (openapi.CreateBotTokenResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateBotToken401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	b, canManage, err := h.getManagedBot(ctx, userID, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return openapi.CreateBotToken404JSONResponse{NotFoundJSONResponse: notFoundResponse("Bot not found")}, nil
+	}
+	if !canManage {
+		return openapi.CreateBotToken403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	name := strings.TrimSpace(request.Body.Name)
+	if name == "" {
+		return openapi.CreateBotToken400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Name is required")}, nil
+	}
+	if utf8.RuneCountInString(name) > maxBotTokenNameLength {
+		return openapi.CreateBotToken400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Name exceeds maximum length of %d characters", maxBotTokenNameLength))}, nil
+	}
+
+	if len(request.Body.Scopes) == 0 {
+		return openapi.CreateBotToken400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "At least one scope is required")}, nil
+	}
+	scopes := make([]string, 0, len(request.Body.Scopes))
+	seen := make(map[string]bool, len(request.Body.Scopes))
+	for _, s := range request.Body.Scopes {
+		scope := string(s)
+		if !bot.IsValidScope(scope) {
+			return openapi.CreateBotToken400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Unknown scope %q", scope))}, nil
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	tok := &bot.Token{
+		BotUserID: b.UserID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedBy: &userID,
+	}
+	secret, err := h.botRepo.CreateToken(ctx, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, b.WorkspaceID, userID, "bot.token_created", "user", b.UserID, map[string]interface{}{
+		"token_id": tok.ID,
+		"scopes":   scopes,
+	})
+
+	return openapi.CreateBotToken200JSONResponse{
+		Token:  botTokenToAPI(tok),
+		Secret: secret,
+	}, nil
+}
+
+// ListBotTokens lists the tokens issued to a bot
+func (h *Handler) ListBotTokens(ctx context.Context, request openapi.ListBotTokensRequestObject) (openapi.ListBotTokensResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListBotTokens401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	b, canManage, err := h.getManagedBot(ctx, userID, request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return openapi.ListBotTokens404JSONResponse{NotFoundJSONResponse: notFoundResponse("Bot not found")}, nil
+	}
+	if !canManage {
+		return openapi.ListBotTokens403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	tokens, err := h.botRepo.ListTokens(ctx, b.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiTokens := make([]openapi.BotToken, len(tokens))
+	for i := range tokens {
+		apiTokens[i] = botTokenToAPI(&tokens[i])
+	}
+
+	return openapi.ListBotTokens200JSONResponse{
+		Tokens: apiTokens,
+	}, nil
+}
+
+// RevokeBotToken revokes a bot token
+func (h *Handler) RevokeBotToken(ctx context.Context, request openapi.RevokeBotTokenRequestObject) (openapi.RevokeBotTokenResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RevokeBotToken401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	tok, err := h.botRepo.GetToken(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, bot.ErrTokenNotFound) {
+			return openapi.RevokeBotToken404JSONResponse{NotFoundJSONResponse: notFoundResponse("Token not found")}, nil
+		}
+		return nil, err
+	}
+
+	b, canManage, err := h.getManagedBot(ctx, userID, tok.BotUserID)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return openapi.RevokeBotToken404JSONResponse{NotFoundJSONResponse: notFoundResponse("Token not found")}, nil
+	}
+	if !canManage {
+		return openapi.RevokeBotToken403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage bots")}, nil
+	}
+
+	if err := h.botRepo.RevokeToken(ctx, tok.ID); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, b.WorkspaceID, userID, "bot.token_revoked", "user", b.UserID, map[string]interface{}{
+		"token_id": tok.ID,
+	})
+
+	return openapi.RevokeBotToken200JSONResponse{
+		Success: true,
+	}, nil
+}
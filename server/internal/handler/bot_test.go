@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// ctxWithBot builds a request context authenticated as a bot, as the token
+// middleware would for a bot token.
+func ctxWithBot(botUserID string, scopes []string) context.Context {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := auth.WithUserID(r.Context(), botUserID)
+	ctx = auth.WithBotScopes(ctx, scopes)
+	return WithRequest(ctx, r.WithContext(ctx))
+}
+
+func createBotViaAPI(t *testing.T, h *Handler, userID, workspaceID string) openapi.Bot {
+	t.Helper()
+
+	resp, err := h.CreateBot(ctxWithUser(t, h, userID), openapi.CreateBotRequestObject{
+		Wid:  workspaceID,
+		Body: &openapi.CreateBotJSONRequestBody{DisplayName: "Standup Bot"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBot: %v", err)
+	}
+	r, ok := resp.(openapi.CreateBot200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	return r.Bot
+}
+
+func TestCreateBot_RequiresAdmin(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, workspace.RoleMember)
+
+	resp, err := h.CreateBot(ctxWithUser(t, h, member.ID), openapi.CreateBotRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateBotJSONRequestBody{DisplayName: "Standup Bot"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateBot403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestCreateBotToken_RejectsUnknownScope(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	b := createBotViaAPI(t, h, owner.ID, ws.ID)
+
+	resp, err := h.CreateBotToken(ctxWithUser(t, h, owner.ID), openapi.CreateBotTokenRequestObject{
+		Id: b.UserId,
+		Body: &openapi.CreateBotTokenJSONRequestBody{
+			Name:   "prod",
+			Scopes: []openapi.BotScope{openapi.ChatWrite, "admin:everything"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateBotToken400JSONResponse); !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestBotMessage_IsFlagged(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	b := createBotViaAPI(t, h, owner.ID, ws.ID)
+
+	content := "Standup time!"
+	resp, err := h.SendMessage(ctxWithBot(b.UserId, []string{"chat:write"}), openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	r, ok := resp.(openapi.SendMessage200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	listResp, err := h.ListMessages(ctxWithUser(t, h, owner.ID), openapi.ListMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("ListMessages: %v", err)
+	}
+	list, ok := listResp.(openapi.ListMessages200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", listResp)
+	}
+	for _, m := range list.Messages {
+		if m.Id != r.Message.Id {
+			continue
+		}
+		if m.IsBot == nil || !*m.IsBot {
+			t.Error("expected bot message to have is_bot set")
+		}
+		if m.UserDisplayName == nil || *m.UserDisplayName != "Standup Bot" {
+			t.Errorf("UserDisplayName = %v, want Standup Bot", m.UserDisplayName)
+		}
+		return
+	}
+	t.Fatal("bot message not found in channel")
+}
@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/enzyme/server/internal/call"
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/features"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+)
+
+// maxCallSignalPayload bounds relayed SDP and ICE payloads
+const maxCallSignalPayload = 64 * 1024
+
+// StartCall starts a call in a channel and announces it with a system message
+func (h *Handler) StartCall(ctx context.Context, request openapi.StartCallRequestObject) (openapi.StartCallResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.StartCall401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.StartCall404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Ban check required here because this route uses channel ID, not workspace ID,
+	// so the ban middleware cannot intercept it.
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID); ban != nil {
+		return openapi.StartCall403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	if !h.features.Enabled(ctx, ch.WorkspaceID, features.Calls) {
+		return openapi.StartCall403JSONResponse{ForbiddenJSONResponse: featureDisabledResponse()}, nil
+	}
+	if ch.ArchivedAt != nil {
+		return openapi.StartCall400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot start a call in an archived channel")}, nil
+	}
+
+	membership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.StartCall403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+	if ok, err := h.canPostIn(ctx, ch, userID, membership.ChannelRole, false); err != nil {
+		return nil, err
+	} else if !ok {
+		return openapi.StartCall403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
+
+	c := &call.Call{
+		WorkspaceID: ch.WorkspaceID,
+		ChannelID:   ch.ID,
+		StartedBy:   &userID,
+	}
+	if err := h.callRepo.Create(ctx, c); err != nil {
+		if errors.Is(err, call.ErrCallInProgress) {
+			return openapi.StartCall409JSONResponse{ConflictJSONResponse: conflictResponse("A call is already in progress in this channel")}, nil
+		}
+		return nil, err
+	}
+
+	starter, _ := h.userRepo.GetByID(ctx, userID)
+	starterName := "Someone"
+	if starter != nil {
+		starterName = starter.DisplayName
+	}
+
+	sysMsg, err := h.messageRepo.CreateSystemMessage(ctx, ch.ID, &message.SystemEventData{
+		EventType:       message.SystemEventCall,
+		UserID:          userID,
+		UserDisplayName: starterName,
+		ChannelName:     ch.Name,
+		CallID:          &c.ID,
+	})
+	if err != nil {
+		slog.Error("failed to create call system message", "call_id", c.ID, "error", err)
+	} else if err := h.callRepo.SetMessageID(ctx, c.ID, sysMsg.ID); err != nil {
+		slog.Error("failed to link call to its system message", "call_id", c.ID, "error", err)
+	} else {
+		c.MessageID = &sysMsg.ID
+	}
+
+	apiCall := callToAPI(c)
+	if h.hub != nil {
+		if c.MessageID != nil {
+			if sysMsgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, *c.MessageID); sysMsgWithUser != nil {
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(messageWithUserToAPI(sysMsgWithUser)))
+			}
+		}
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewCallStartedEvent(apiCall))
+	}
+
+	return openapi.StartCall200JSONResponse{Call: apiCall}, nil
+}
+
+// GetCall returns a call and its participants
+func (h *Handler) GetCall(ctx context.Context, request openapi.GetCallRequestObject) (openapi.GetCallResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetCall401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	c, _, err := h.getReadableCall(ctx, request.Id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, call.ErrCallNotFound):
+			return openapi.GetCall404JSONResponse{NotFoundJSONResponse: notFoundResponse("Call not found")}, nil
+		case errors.Is(err, errCallForbidden):
+			return openapi.GetCall403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot view this call")}, nil
+		}
+		return nil, err
+	}
+
+	return openapi.GetCall200JSONResponse{Call: callToAPI(c)}, nil
+}
+
+// JoinCall adds the caller to a call in progress
+func (h *Handler) JoinCall(ctx context.Context, request openapi.JoinCallRequestObject) (openapi.JoinCallResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.JoinCall401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	c, ch, err := h.getReadableCall(ctx, request.Id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, call.ErrCallNotFound):
+			return openapi.JoinCall404JSONResponse{NotFoundJSONResponse: notFoundResponse("Call not found")}, nil
+		case errors.Is(err, errCallForbidden):
+			return openapi.JoinCall403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot join this call")}, nil
+		}
+		return nil, err
+	}
+
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID); ban != nil {
+		return openapi.JoinCall403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	if !h.features.Enabled(ctx, ch.WorkspaceID, features.Calls) {
+		return openapi.JoinCall403JSONResponse{ForbiddenJSONResponse: featureDisabledResponse()}, nil
+	}
+
+	if err := h.callRepo.Join(ctx, c.ID, userID); err != nil {
+		switch {
+		case errors.Is(err, call.ErrCallEnded):
+			return openapi.JoinCall409JSONResponse{ConflictJSONResponse: conflictResponse("This call has ended")}, nil
+		case errors.Is(err, call.ErrCallNotFound):
+			return openapi.JoinCall404JSONResponse{NotFoundJSONResponse: notFoundResponse("Call not found")}, nil
+		}
+		return nil, err
+	}
+
+	c, err = h.callRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	apiCall := callToAPI(c)
+	if h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewCallUpdatedEvent(apiCall))
+	}
+
+	return openapi.JoinCall200JSONResponse{Call: apiCall}, nil
+}
+
+// LeaveCall removes the caller from a call, ending it if nobody is left
+func (h *Handler) LeaveCall(ctx context.Context, request openapi.LeaveCallRequestObject) (openapi.LeaveCallResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.LeaveCall401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	c, err := h.callRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, call.ErrCallNotFound) {
+			return openapi.LeaveCall404JSONResponse{NotFoundJSONResponse: notFoundResponse("Call not found")}, nil
+		}
+		return nil, err
+	}
+
+	left, err := h.callRepo.Leave(ctx, c.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if left {
+		h.CallChanged(ctx, c.ID)
+	}
+
+	c, err = h.callRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.LeaveCall200JSONResponse{Call: callToAPI(c)}, nil
+}
+
+// SignalCall relays a WebRTC signaling message to another participant
+func (h *Handler) SignalCall(ctx context.Context, request openapi.SignalCallRequestObject) (openapi.SignalCallResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SignalCall401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	c, err := h.callRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, call.ErrCallNotFound) {
+			return openapi.SignalCall404JSONResponse{NotFoundJSONResponse: notFoundResponse("Call not found")}, nil
+		}
+		return nil, err
+	}
+	if !c.IsActiveParticipant(userID) {
+		return openapi.SignalCall403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are not in this call")}, nil
+	}
+
+	body := request.Body
+	switch body.Type {
+	case openapi.CallSignalOffer, openapi.CallSignalAnswer, openapi.CallSignalIceCandidate:
+	default:
+		return openapi.SignalCall400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Unknown signal type")}, nil
+	}
+	if len(body.Payload) > maxCallSignalPayload {
+		return openapi.SignalCall400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Signal payload is too large")}, nil
+	}
+	if body.ToUserId == userID || !c.IsActiveParticipant(body.ToUserId) {
+		return openapi.SignalCall400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "The recipient is not in this call")}, nil
+	}
+
+	if h.hub != nil {
+		h.hub.BroadcastToUser(ctx, c.WorkspaceID, body.ToUserId, sse.NewCallSignalEvent(openapi.CallSignalData{
+			CallId:     c.ID,
+			FromUserId: userID,
+			Type:       body.Type,
+			Payload:    body.Payload,
+		}))
+	}
+
+	return openapi.SignalCall200JSONResponse{Success: true}, nil
+}
+
+// CallChanged implements call.Notifier. It publishes a call's participants,
+// or ends the call once nobody is left in it.
+func (h *Handler) CallChanged(ctx context.Context, callID string) {
+	c, err := h.callRepo.GetByID(ctx, callID)
+	if err != nil {
+		slog.Error("failed to load changed call", "call_id", callID, "error", err)
+		return
+	}
+	if c.EndedAt == nil && c.ActiveParticipantCount() == 0 {
+		h.endCall(ctx, c)
+		return
+	}
+	if h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, c.WorkspaceID, c.ChannelID, sse.NewCallUpdatedEvent(callToAPI(c)))
+	}
+}
+
+// endCall ends a call and records its duration and participant count on the
+// system message that announced it.
+func (h *Handler) endCall(ctx context.Context, c *call.Call) {
+	ended, err := h.callRepo.End(ctx, c.ID)
+	if err != nil {
+		slog.Error("failed to end call", "call_id", c.ID, "error", err)
+		return
+	}
+	if !ended {
+		return
+	}
+	c, err = h.callRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		slog.Error("failed to load ended call", "call_id", c.ID, "error", err)
+		return
+	}
+
+	var updatedMsg *message.MessageWithUser
+	if c.MessageID != nil {
+		updatedMsg, err = h.recordCallSummary(ctx, c)
+		if err != nil {
+			slog.Error("failed to update call system message", "call_id", c.ID, "error", err)
+		}
+	}
+
+	if h.hub != nil {
+		if updatedMsg != nil {
+			h.hub.BroadcastToChannel(ctx, c.WorkspaceID, c.ChannelID, sse.NewMessageUpdatedEvent(messageWithUserToAPI(updatedMsg)))
+		}
+		h.hub.BroadcastToChannel(ctx, c.WorkspaceID, c.ChannelID, sse.NewCallEndedEvent(callToAPI(c)))
+	}
+}
+
+// recordCallSummary adds the duration and participant count of an ended
+// call to its system message and returns the updated message.
+func (h *Handler) recordCallSummary(ctx context.Context, c *call.Call) (*message.MessageWithUser, error) {
+	msg, err := h.messageRepo.GetByID(ctx, *c.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.SystemEvent == nil {
+		return nil, nil
+	}
+
+	duration := int(c.EndedAt.Sub(c.StartedAt) / time.Second)
+	participants := len(c.Participants)
+	event := *msg.SystemEvent
+	event.CallDurationSeconds = &duration
+	event.CallParticipantCount = &participants
+	if err := h.messageRepo.UpdateSystemEvent(ctx, msg.ID, &event); err != nil {
+		return nil, err
+	}
+	return h.messageRepo.GetByIDWithUser(ctx, msg.ID)
+}
+
+// errCallForbidden is returned by getReadableCall when the user cannot read
+// the call's channel.
+var errCallForbidden = errors.New("call not readable")
+
+// getReadableCall loads a call along with its channel, checking that userID
+// can read the channel.
+func (h *Handler) getReadableCall(ctx context.Context, id, userID string) (*call.Call, *channel.Channel, error) {
+	c, err := h.callRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch, err := h.channelRepo.GetByID(ctx, c.ChannelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return nil, nil, errCallForbidden
+	}
+	return c, ch, nil
+}
+
+func callToAPI(c *call.Call) openapi.Call {
+	participants := make([]openapi.CallParticipant, len(c.Participants))
+	for i, p := range c.Participants {
+		participants[i] = openapi.CallParticipant{
+			UserId:   p.UserID,
+			JoinedAt: p.JoinedAt,
+			LeftAt:   p.LeftAt,
+		}
+	}
+	return openapi.Call{
+		Id:           c.ID,
+		ChannelId:    c.ChannelID,
+		StartedBy:    c.StartedBy,
+		MessageId:    c.MessageID,
+		StartedAt:    c.StartedAt,
+		EndedAt:      c.EndedAt,
+		Participants: participants,
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestCall_StartJoinSignalLeave(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	client := connectSSEClient(t, h, ws.ID, member.ID)
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+
+	startResp, err := h.StartCall(ownerCtx, openapi.StartCallRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("StartCall: %v", err)
+	}
+	started, ok := startResp.(openapi.StartCall200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %#v", startResp)
+	}
+	if started.Call.MessageId == nil || len(started.Call.Participants) != 1 {
+		t.Fatalf("call = %+v, want a system message and the starter as participant", started.Call)
+	}
+	expectSSEEvent(t, client, sse.EventMessageNew)
+	expectSSEEvent(t, client, sse.EventCallStarted)
+	callID := started.Call.Id
+
+	// Only one call at a time per channel
+	startResp, err = h.StartCall(memberCtx, openapi.StartCallRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("StartCall: %v", err)
+	}
+	if _, ok := startResp.(openapi.StartCall409JSONResponse); !ok {
+		t.Fatalf("second call = %T, want 409", startResp)
+	}
+
+	signal := func(from string) openapi.SignalCallResponseObject {
+		t.Helper()
+		resp, err := h.SignalCall(ctxWithUser(t, h, from), openapi.SignalCallRequestObject{
+			Id:   callID,
+			Body: &openapi.SignalCallJSONRequestBody{ToUserId: member.ID, Type: openapi.CallSignalOffer, Payload: "v=0"},
+		})
+		if err != nil {
+			t.Fatalf("SignalCall: %v", err)
+		}
+		return resp
+	}
+	if _, ok := signal(owner.ID).(openapi.SignalCall400JSONResponse); !ok {
+		t.Error("signaling a user who has not joined should be rejected")
+	}
+
+	joinResp, err := h.JoinCall(memberCtx, openapi.JoinCallRequestObject{Id: callID})
+	if err != nil {
+		t.Fatalf("JoinCall: %v", err)
+	}
+	if j, ok := joinResp.(openapi.JoinCall200JSONResponse); !ok || len(j.Call.Participants) != 2 {
+		t.Fatalf("join = %#v, want two participants", joinResp)
+	}
+	expectSSEEvent(t, client, sse.EventCallUpdated)
+
+	if _, ok := signal(owner.ID).(openapi.SignalCall200JSONResponse); !ok {
+		t.Fatal("expected signal to be relayed")
+	}
+	expectSSEEvent(t, client, sse.EventCallSignal)
+
+	for _, ctx := range []context.Context{ownerCtx, memberCtx} {
+		if _, err := h.LeaveCall(ctx, openapi.LeaveCallRequestObject{Id: callID}); err != nil {
+			t.Fatalf("LeaveCall: %v", err)
+		}
+	}
+	expectSSEEvent(t, client, sse.EventCallUpdated)
+	expectSSEEvent(t, client, sse.EventMessageUpdated)
+	expectSSEEvent(t, client, sse.EventCallEnded)
+
+	msg, err := h.messageRepo.GetByID(ownerCtx, *started.Call.MessageId)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if msg.SystemEvent == nil || msg.SystemEvent.EventType != message.SystemEventCall {
+		t.Fatalf("system event = %+v, want a call event", msg.SystemEvent)
+	}
+	if n := msg.SystemEvent.CallParticipantCount; n == nil || *n != 2 || msg.SystemEvent.CallDurationSeconds == nil {
+		t.Errorf("system event = %+v, want duration and two participants", msg.SystemEvent)
+	}
+
+	joinResp, err = h.JoinCall(memberCtx, openapi.JoinCallRequestObject{Id: callID})
+	if err != nil {
+		t.Fatalf("JoinCall: %v", err)
+	}
+	if _, ok := joinResp.(openapi.JoinCall409JSONResponse); !ok {
+		t.Errorf("joining an ended call = %T, want 409", joinResp)
+	}
+}
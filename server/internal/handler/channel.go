@@ -2,22 +2,34 @@ package handler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"math"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/enzyme/server/internal/activity"
 	"github.com/enzyme/server/internal/channel"
 	"github.com/enzyme/server/internal/gravatar"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/notification"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/usergroup"
 	"github.com/enzyme/server/internal/workspace"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 var validChannelName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 
+const maxChannelTopicLength = 250
+
 // CreateChannel creates a new channel
 func (h *Handler) CreateChannel(ctx context.Context, request openapi.CreateChannelRequestObject) (openapi.CreateChannelResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -28,6 +40,9 @@ func (h *Handler) CreateChannel(ctx context.Context, request openapi.CreateChann
 	// Check workspace membership and permissions
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.CreateChannel403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -43,10 +58,10 @@ func (h *Handler) CreateChannel(ctx context.Context, request openapi.CreateChann
 
 	name := strings.TrimSpace(request.Body.Name)
 	if name == "" {
-		return openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Channel name is required")}, nil
+		return openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: requiredResponse("name")}, nil
 	}
 	if !validChannelName.MatchString(name) {
-		return openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Channel name must contain only lowercase letters, numbers, and dashes")}, nil
+		return openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: invalidChannelNameResponse()}, nil
 	}
 
 	// Validate type
@@ -55,14 +70,26 @@ func (h *Handler) CreateChannel(ctx context.Context, request openapi.CreateChann
 		channelType = channel.TypePublic
 	}
 
+	historyVisibility := channel.HistoryVisibilityAll
+	if request.Body.HistoryVisibility != nil {
+		historyVisibility = string(*request.Body.HistoryVisibility)
+		if !channel.IsValidHistoryVisibility(historyVisibility) {
+			return openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: invalidValueResponse("history_visibility")}, nil
+		}
+	}
+
 	ch := &channel.Channel{
-		WorkspaceID: string(request.Wid),
-		Name:        name,
-		Description: request.Body.Description,
-		Type:        channelType,
+		WorkspaceID:       string(request.Wid),
+		Name:              name,
+		Description:       request.Body.Description,
+		Type:              channelType,
+		HistoryVisibility: historyVisibility,
 	}
 
 	if err := h.channelRepo.Create(ctx, ch, userID); err != nil {
+		if errors.Is(err, channel.ErrChannelNameTaken) {
+			return openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: channelNameTakenResponse()}, nil
+		}
 		return nil, err
 	}
 
@@ -73,9 +100,9 @@ func (h *Handler) CreateChannel(ctx context.Context, request openapi.CreateChann
 		h.hub.AddChannelMember(ch.ID, userID)
 		if ch.Type == channel.TypePrivate {
 			// Private channels: only notify channel members (the creator at this point)
-			h.hub.BroadcastToChannel(ch.WorkspaceID, ch.ID, sse.NewChannelCreatedEvent(apiCh))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelCreatedEvent(apiCh))
 		} else {
-			h.hub.BroadcastToWorkspace(ch.WorkspaceID, sse.NewChannelCreatedEvent(apiCh))
+			h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, sse.NewChannelCreatedEvent(apiCh))
 		}
 	}
 
@@ -84,7 +111,7 @@ func (h *Handler) CreateChannel(ctx context.Context, request openapi.CreateChann
 	}, nil
 }
 
-// ListChannels lists channels in a workspace
+// ListChannels lists channels in a workspace, or returns 304 if the list is unchanged
 func (h *Handler) ListChannels(ctx context.Context, request openapi.ListChannelsRequestObject) (openapi.ListChannelsResponseObject, error) {
 	userID := h.getUserID(ctx)
 	if userID == "" {
@@ -93,9 +120,21 @@ func (h *Handler) ListChannels(ctx context.Context, request openapi.ListChannels
 
 	// Check workspace membership
 	_, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ListChannels403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	version, err := h.channelRepo.ListVersion(ctx, string(request.Wid), userID)
 	if err != nil {
 		return nil, err
 	}
+	etag := `"` + version + `"`
+	if request.Params.IfNoneMatch != nil && etagMatches(*request.Params.IfNoneMatch, etag) {
+		return openapi.ListChannels304Response{Headers: openapi.ListChannels304ResponseHeaders{ETag: etag}}, nil
+	}
 
 	channels, err := h.channelRepo.ListForWorkspace(ctx, string(request.Wid), userID)
 	if err != nil {
@@ -107,11 +146,103 @@ func (h *Handler) ListChannels(ctx context.Context, request openapi.ListChannels
 		apiChannels[i] = channelWithMembershipToAPI(ch)
 	}
 
+	unreadThreads, err := h.threadRepo.CountUnreadThreads(ctx, string(request.Wid), userID)
+	if err != nil {
+		return nil, err
+	}
+
 	return openapi.ListChannels200JSONResponse{
-		Channels: apiChannels,
+		Body: openapi.ChannelListResult{
+			Channels:          apiChannels,
+			UnreadThreadCount: unreadThreads,
+		},
+		Headers: openapi.ListChannels200ResponseHeaders{ETag: etag},
+	}, nil
+}
+
+// ListMyDMs lists the user's direct messages across all of their workspaces
+func (h *Handler) ListMyDMs(ctx context.Context, request openapi.ListMyDMsRequestObject) (openapi.ListMyDMsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListMyDMs401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	entries, err := h.channelRepo.ListDMInbox(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversations := make([]openapi.DMConversation, len(entries))
+	for i := range entries {
+		localizePreview(ctx, &entries[i].LastMessagePreview, entries[i].LastMessageSystemEvent)
+		conversations[i] = dmConversationToAPI(entries[i])
+	}
+	return openapi.ListMyDMs200JSONResponse{Conversations: conversations}, nil
+}
+
+// GetUnreadCounts returns unread counts for the user's channels, or 304 if they are unchanged
+func (h *Handler) GetUnreadCounts(ctx context.Context, request openapi.GetUnreadCountsRequestObject) (openapi.GetUnreadCountsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetUnreadCounts401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.GetUnreadCounts403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	version, err := h.channelRepo.UnreadCountsVersion(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	etag := `"` + version + `"`
+	if request.Params.IfNoneMatch != nil && etagMatches(*request.Params.IfNoneMatch, etag) {
+		return openapi.GetUnreadCounts304Response{Headers: openapi.GetUnreadCounts304ResponseHeaders{ETag: etag}}, nil
+	}
+
+	counts, err := h.channelRepo.ListUnreadCounts(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	apiCounts := make(map[string]openapi.ChannelUnreadCount, len(counts))
+	for channelID, c := range counts {
+		apiCounts[channelID] = openapi.ChannelUnreadCount{UnreadCount: c.Unread, NotificationCount: c.Notifications}
+	}
+
+	return openapi.GetUnreadCounts200JSONResponse{
+		Body:    openapi.UnreadCountsResult{Channels: apiCounts},
+		Headers: openapi.GetUnreadCounts200ResponseHeaders{ETag: etag},
 	}, nil
 }
 
+// etagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison HTTP specifies for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// pageETag returns the ETag for one page of a list: the list's version
+// qualified by the options that select the page.
+func pageETag(version string, options ...string) string {
+	h := sha256.New()
+	h.Write([]byte(version))
+	for _, o := range options {
+		h.Write([]byte{0})
+		h.Write([]byte(o))
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
 // CreateDM creates or gets a DM channel
 func (h *Handler) CreateDM(ctx context.Context, request openapi.CreateDMRequestObject) (openapi.CreateDMResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -122,6 +253,9 @@ func (h *Handler) CreateDM(ctx context.Context, request openapi.CreateDMRequestO
 	// Check workspace membership
 	_, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.CreateDM403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -185,12 +319,18 @@ func (h *Handler) UpdateChannel(ctx context.Context, request openapi.UpdateChann
 
 	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
 	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.UpdateChannel404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
 		return nil, err
 	}
 
 	// Check workspace membership
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UpdateChannel403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -216,10 +356,10 @@ func (h *Handler) UpdateChannel(ctx context.Context, request openapi.UpdateChann
 	if request.Body.Name != nil {
 		name := strings.TrimSpace(*request.Body.Name)
 		if name == "" {
-			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Channel name cannot be empty")}, nil
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: requiredResponse("name")}, nil
 		}
 		if !validChannelName.MatchString(name) {
-			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Channel name must contain only lowercase letters, numbers, and dashes")}, nil
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: invalidChannelNameResponse()}, nil
 		}
 		// Check for duplicate name if name is changing
 		if name != ch.Name {
@@ -228,7 +368,7 @@ func (h *Handler) UpdateChannel(ctx context.Context, request openapi.UpdateChann
 				return nil, err
 			}
 			if existing != nil {
-				return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "A channel with this name already exists")}, nil
+				return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: channelNameTakenResponse()}, nil
 			}
 		}
 		ch.Name = name
@@ -240,7 +380,7 @@ func (h *Handler) UpdateChannel(ctx context.Context, request openapi.UpdateChann
 		newType := string(*request.Body.Type)
 		// Only allow public or private
 		if newType != channel.TypePublic && newType != channel.TypePrivate {
-			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Channel type must be public or private")}, nil
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: invalidValueResponse("type")}, nil
 		}
 		// Cannot change type on DM/group_dm channels
 		if ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM {
@@ -252,22 +392,112 @@ func (h *Handler) UpdateChannel(ctx context.Context, request openapi.UpdateChann
 		}
 		ch.Type = newType
 	}
+	if request.Body.HistoryVisibility != nil {
+		historyVisibility := string(*request.Body.HistoryVisibility)
+		if !channel.IsValidHistoryVisibility(historyVisibility) {
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: invalidValueResponse("history_visibility")}, nil
+		}
+		ch.HistoryVisibility = historyVisibility
+	}
+	if request.Body.WhoCanMentionChannel != nil {
+		switch v := *request.Body.WhoCanMentionChannel; v {
+		case openapi.ChannelMentionWorkspaceDefault:
+			ch.WhoCanMentionChannel = nil
+		case openapi.ChannelMentionEveryone, openapi.ChannelMentionMembers, openapi.ChannelMentionAdmins:
+			level := string(v)
+			ch.WhoCanMentionChannel = &level
+		default:
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: invalidValueResponse("who_can_mention_channel")}, nil
+		}
+	}
+	if request.Body.PostPolicy != nil {
+		postPolicy := string(*request.Body.PostPolicy)
+		if !channel.IsValidPostPolicy(postPolicy) {
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: invalidValueResponse("post_policy")}, nil
+		}
+		if postPolicy != channel.PostPolicyEveryone && (ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM) {
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot restrict posting in DM channels")}, nil
+		}
+		ch.PostPolicy = postPolicy
+	}
+	if request.Body.PostRoles != nil {
+		roles := make([]string, 0, len(*request.Body.PostRoles))
+		for _, role := range *request.Body.PostRoles {
+			switch string(role) {
+			case workspace.RoleOwner, workspace.RoleAdmin, workspace.RoleMember, workspace.RoleGuest:
+			default:
+				return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: invalidValueResponse("post_roles")}, nil
+			}
+			if !slices.Contains(roles, string(role)) {
+				roles = append(roles, string(role))
+			}
+		}
+		ch.PostRoles = roles
+	}
+	if request.Body.RestrictThreadReplies != nil {
+		ch.RestrictThreadReplies = *request.Body.RestrictThreadReplies
+	}
+	if request.Body.SlowModeSeconds != nil {
+		seconds := *request.Body.SlowModeSeconds
+		if seconds < 0 || seconds > channel.MaxSlowModeSeconds {
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: outOfRangeResponse("slow_mode_seconds", 0, channel.MaxSlowModeSeconds)}, nil
+		}
+		if seconds > 0 && (ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM) {
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot enable slow mode in DM channels")}, nil
+		}
+		ch.SlowModeSeconds = seconds
+	}
+	if request.Body.AutoJoin != nil && *request.Body.AutoJoin != ch.AutoJoin {
+		if !workspace.CanManageMembers(membership.Role) {
+			return openapi.UpdateChannel403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only workspace admins can change auto-join")}, nil
+		}
+		if ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM {
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "DM channels cannot be auto-joined")}, nil
+		}
+		ch.AutoJoin = *request.Body.AutoJoin
+	}
+	backfill := request.Body.BackfillMembers != nil && *request.Body.BackfillMembers
+	if backfill && !ch.AutoJoin {
+		return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "backfill_members requires auto_join")}, nil
+	}
+	if backfill && !workspace.CanManageMembers(membership.Role) {
+		return openapi.UpdateChannel403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only workspace admins can add every member")}, nil
+	}
 
 	if err := h.channelRepo.Update(ctx, ch); err != nil {
 		if errors.Is(err, channel.ErrChannelNameTaken) {
-			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "A channel with this name already exists")}, nil
+			return openapi.UpdateChannel400JSONResponse{BadRequestJSONResponse: channelNameTakenResponse()}, nil
 		}
 		return nil, err
 	}
 
+	var addedCount *int
+	if backfill {
+		added, err := h.channelRepo.AddWorkspaceMembers(ctx, ch)
+		if err != nil {
+			return nil, err
+		}
+		n := len(added)
+		addedCount = &n
+		if h.hub != nil {
+			for _, memberID := range added {
+				h.hub.AddChannelMember(ch.ID, memberID)
+				h.hub.BroadcastToUser(ctx, ch.WorkspaceID, memberID, sse.NewChannelMemberAddedEvent(openapi.ChannelMemberData{
+					ChannelId: ch.ID,
+					UserId:    memberID,
+				}))
+			}
+		}
+	}
+
 	apiCh := channelToAPI(ch)
 
 	// Broadcast SSE channel.updated event
 	if h.hub != nil {
 		if ch.Type == channel.TypePrivate {
-			h.hub.BroadcastToChannel(ch.WorkspaceID, ch.ID, sse.NewChannelUpdatedEvent(apiCh))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelUpdatedEvent(apiCh))
 		} else {
-			h.hub.BroadcastToWorkspace(ch.WorkspaceID, sse.NewChannelUpdatedEvent(apiCh))
+			h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, sse.NewChannelUpdatedEvent(apiCh))
 		}
 	}
 
@@ -287,10 +517,91 @@ func (h *Handler) UpdateChannel(ctx context.Context, request openapi.UpdateChann
 	}
 
 	return openapi.UpdateChannel200JSONResponse{
-		Channel: apiCh,
+		Channel:          apiCh,
+		AddedMemberCount: addedCount,
 	}, nil
 }
 
+// SetChannelTopic sets the short topic shown in the channel header
+func (h *Handler) SetChannelTopic(ctx context.Context, request openapi.SetChannelTopicRequestObject) (openapi.SetChannelTopicResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SetChannelTopic401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.SetChannelTopic404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Ban check required here because this route uses channel ID, not workspace ID,
+	// so the ban middleware cannot intercept it.
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID); ban != nil {
+		return openapi.SetChannelTopic403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	if ch.ArchivedAt != nil {
+		return openapi.SetChannelTopic400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot change the topic of an archived channel")}, nil
+	}
+
+	membership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.SetChannelTopic403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+	if ok, err := h.canPostIn(ctx, ch, userID, membership.ChannelRole, false); err != nil {
+		return nil, err
+	} else if !ok {
+		return openapi.SetChannelTopic403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
+
+	topic := strings.Join(strings.Fields(request.Body.Topic), " ")
+	if utf8.RuneCountInString(topic) > maxChannelTopicLength {
+		return openapi.SetChannelTopic400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Topic must be at most %d characters", maxChannelTopicLength))}, nil
+	}
+
+	oldTopic := ""
+	if ch.Topic != nil {
+		oldTopic = *ch.Topic
+	}
+	if topic == oldTopic {
+		return openapi.SetChannelTopic200JSONResponse{Channel: channelToAPI(ch)}, nil
+	}
+
+	var newTopic *string
+	if topic != "" {
+		newTopic = &topic
+	}
+	if err := h.channelRepo.SetTopic(ctx, ch, newTopic); err != nil {
+		return nil, err
+	}
+
+	apiCh := channelToAPI(ch)
+	if h.hub != nil {
+		if ch.Type == channel.TypePublic {
+			h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, sse.NewChannelUpdatedEvent(apiCh))
+		} else {
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelUpdatedEvent(apiCh))
+		}
+	}
+
+	if user, err := h.userRepo.GetByID(ctx, userID); err == nil {
+		h.createChannelSystemMessage(ctx, ch, &message.SystemEventData{
+			EventType:       message.SystemEventChannelTopicChanged,
+			UserID:          userID,
+			UserDisplayName: user.DisplayName,
+			ChannelName:     ch.Name,
+			Topic:           &topic,
+		})
+	}
+
+	return openapi.SetChannelTopic200JSONResponse{Channel: apiCh}, nil
+}
+
 // ArchiveChannel archives a channel
 func (h *Handler) ArchiveChannel(ctx context.Context, request openapi.ArchiveChannelRequestObject) (openapi.ArchiveChannelResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -316,6 +627,9 @@ func (h *Handler) ArchiveChannel(ctx context.Context, request openapi.ArchiveCha
 	// Check workspace membership
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ArchiveChannel403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -333,13 +647,15 @@ func (h *Handler) ArchiveChannel(ctx context.Context, request openapi.ArchiveCha
 		if archived, err := h.channelRepo.GetByID(ctx, string(request.Id)); err == nil {
 			if ch.Type == channel.TypePrivate {
 				// Private channels: only notify channel members
-				h.hub.BroadcastToChannel(ch.WorkspaceID, ch.ID, sse.NewChannelArchivedEvent(channelToAPI(archived)))
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelArchivedEvent(channelToAPI(archived)))
 			} else {
-				h.hub.BroadcastToWorkspace(ch.WorkspaceID, sse.NewChannelArchivedEvent(channelToAPI(archived)))
+				h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, sse.NewChannelArchivedEvent(channelToAPI(archived)))
 			}
 		}
 	}
 
+	h.createChannelArchiveSystemMessage(ctx, ch, userID, message.SystemEventChannelArchived)
+
 	// Audit log: channel archived
 	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, ch.WorkspaceID, userID, "channel.archived", "channel", string(request.Id), map[string]interface{}{
 		"channel_name": ch.Name,
@@ -350,6 +666,125 @@ func (h *Handler) ArchiveChannel(ctx context.Context, request openapi.ArchiveCha
 	}, nil
 }
 
+// UnarchiveChannel restores an archived channel
+func (h *Handler) UnarchiveChannel(ctx context.Context, request openapi.UnarchiveChannelRequestObject) (openapi.UnarchiveChannelResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UnarchiveChannel401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.UnarchiveChannel404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UnarchiveChannel404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.UnarchiveChannel403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
+
+	if err := h.channelRepo.Unarchive(ctx, ch); err != nil {
+		if errors.Is(err, channel.ErrChannelNotArchived) {
+			return openapi.UnarchiveChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Channel is not archived")}, nil
+		}
+		return nil, err
+	}
+
+	apiCh := channelToAPI(ch)
+	if h.hub != nil {
+		if ch.Type == channel.TypePrivate {
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelUnarchivedEvent(apiCh))
+		} else {
+			h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, sse.NewChannelUnarchivedEvent(apiCh))
+		}
+	}
+
+	h.createChannelArchiveSystemMessage(ctx, ch, userID, message.SystemEventChannelUnarchived)
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, ch.WorkspaceID, userID, "channel.unarchived", "channel", ch.ID, map[string]interface{}{
+		"channel_name": ch.Name,
+	})
+
+	return openapi.UnarchiveChannel200JSONResponse{Channel: apiCh}, nil
+}
+
+// ListArchivedChannels lists a workspace's archived channels for admins
+func (h *Handler) ListArchivedChannels(ctx context.Context, request openapi.ListArchivedChannelsRequestObject) (openapi.ListArchivedChannelsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListArchivedChannels401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil {
+		return openapi.ListArchivedChannels403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.ListArchivedChannels403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
+
+	channels, err := h.channelRepo.ListArchived(ctx, string(request.Wid))
+	if err != nil {
+		return nil, err
+	}
+	apiChannels := make([]openapi.Channel, len(channels))
+	for i := range channels {
+		apiChannels[i] = channelToAPI(&channels[i])
+	}
+	return openapi.ListArchivedChannels200JSONResponse{Channels: apiChannels}, nil
+}
+
+// BrowseChannels lists the workspace's public channels for the channel browser
+func (h *Handler) BrowseChannels(ctx context.Context, request openapi.BrowseChannelsRequestObject) (openapi.BrowseChannelsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.BrowseChannels401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid)); err != nil {
+		return openapi.BrowseChannels403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	opts := channel.DirectoryOptions{Sort: channel.DirectorySortNewest}
+	if request.Params.Q != nil {
+		opts.Query = *request.Params.Q
+	}
+	if request.Params.Sort != nil {
+		opts.Sort = string(*request.Params.Sort)
+	}
+	if request.Params.Limit != nil {
+		opts.Limit = *request.Params.Limit
+	}
+	if request.Params.Offset != nil {
+		opts.Offset = *request.Params.Offset
+	}
+
+	entries, total, err := h.channelRepo.ListDirectory(ctx, string(request.Wid), userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	apiEntries := make([]openapi.ChannelDirectoryEntry, len(entries))
+	for i := range entries {
+		localizePreview(ctx, &entries[i].LastMessagePreview, entries[i].LastMessageSystemEvent)
+		apiEntries[i] = channelDirectoryEntryToAPI(&entries[i])
+	}
+	return openapi.BrowseChannels200JSONResponse{
+		Channels:   apiEntries,
+		TotalCount: total,
+		HasMore:    opts.Offset+len(entries) < total,
+	}, nil
+}
+
 // AddChannelMember adds a member to a channel
 func (h *Handler) AddChannelMember(ctx context.Context, request openapi.AddChannelMemberRequestObject) (openapi.AddChannelMemberResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -365,6 +800,9 @@ func (h *Handler) AddChannelMember(ctx context.Context, request openapi.AddChann
 	// Check workspace membership
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.AddChannelMember403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -421,7 +859,7 @@ func (h *Handler) AddChannelMember(ctx context.Context, request openapi.AddChann
 			h.hub.AddChannelMember(string(request.Id), request.Body.UserId)
 
 			// Broadcast member added event
-			h.hub.BroadcastToChannel(ch.WorkspaceID, string(request.Id), sse.NewChannelMemberAddedEvent(openapi.ChannelMemberData{
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, string(request.Id), sse.NewChannelMemberAddedEvent(openapi.ChannelMemberData{
 				ChannelId: string(request.Id),
 				UserId:    request.Body.UserId,
 			}))
@@ -429,12 +867,13 @@ func (h *Handler) AddChannelMember(ctx context.Context, request openapi.AddChann
 			// Broadcast channel updated if type changed (dm -> group_dm)
 			if updatedCh.Type != ch.Type {
 				apiCh := channelToAPI(updatedCh)
-				h.hub.BroadcastToChannel(ch.WorkspaceID, string(request.Id), sse.NewChannelUpdatedEvent(apiCh))
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, string(request.Id), sse.NewChannelUpdatedEvent(apiCh))
 			}
 		}
 
 		// Create system message
 		h.createAddedSystemMessage(ctx, ch, request.Body.UserId, userID)
+		h.recordActivity(ctx, updatedCh, &activity.Activity{UserID: request.Body.UserId, ActorID: &userID, Type: activity.TypeChannelInvite})
 
 		return openapi.AddChannelMember200JSONResponse{Success: true}, nil
 	}
@@ -455,20 +894,160 @@ func (h *Handler) AddChannelMember(ctx context.Context, request openapi.AddChann
 		return nil, err
 	}
 
-	// Update SSE hub cache for channel membership
-	if h.hub != nil {
-		h.hub.AddChannelMember(string(request.Id), request.Body.UserId)
-	}
+	h.broadcastChannelMemberAdded(ctx, ch, request.Body.UserId)
 
 	// Create system message for user being added
 	h.createAddedSystemMessage(ctx, ch, request.Body.UserId, userID)
+	h.recordActivity(ctx, ch, &activity.Activity{UserID: request.Body.UserId, ActorID: &userID, Type: activity.TypeChannelInvite})
 
 	return openapi.AddChannelMember200JSONResponse{
 		Success: true,
 	}, nil
 }
 
-// ListChannelMembers lists members of a channel
+// broadcastChannelMemberAdded records a new channel member in the SSE hub and
+// announces it: to the whole workspace for public channels, which anyone
+// can see, and to the channel's members otherwise
+func (h *Handler) broadcastChannelMemberAdded(ctx context.Context, ch *channel.Channel, userID string) {
+	if h.hub == nil {
+		return
+	}
+	h.hub.AddChannelMember(ch.ID, userID)
+	event := sse.NewChannelMemberAddedEvent(openapi.ChannelMemberData{
+		ChannelId: ch.ID,
+		UserId:    userID,
+	})
+	if ch.Type == channel.TypePublic {
+		h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, event)
+	} else {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, event)
+	}
+}
+
+// maxBulkChannelMembers caps how many users one bulk add may name
+const maxBulkChannelMembers = 500
+
+// BulkAddChannelMembers adds many workspace members to a channel at once,
+// announcing them with a single system message and event
+func (h *Handler) BulkAddChannelMembers(ctx context.Context, request openapi.BulkAddChannelMembersRequestObject) (openapi.BulkAddChannelMembersResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.BulkAddChannelMembers401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.BulkAddChannelMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil {
+		return openapi.BulkAddChannelMembers403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID); ban != nil {
+		return openapi.BulkAddChannelMembers403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+
+	// Same rule as adding one member: workspace admins or channel members
+	channelMembership, _ := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if !workspace.CanManageMembers(membership.Role) && channelMembership == nil {
+		return openapi.BulkAddChannelMembers403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
+	if ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM {
+		return openapi.BulkAddChannelMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Use the single member endpoint to add people to a DM")}, nil
+	}
+	if ch.ArchivedAt != nil {
+		return openapi.BulkAddChannelMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot add members to an archived channel")}, nil
+	}
+
+	seen := make(map[string]bool)
+	var userIDs []string
+	addUser := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	if request.Body.UserIds != nil {
+		for _, id := range *request.Body.UserIds {
+			addUser(id)
+		}
+	}
+	if request.Body.UserGroupId != nil {
+		group, err := h.userGroupRepo.GetByID(ctx, *request.Body.UserGroupId)
+		if err != nil && !errors.Is(err, usergroup.ErrGroupNotFound) {
+			return nil, err
+		}
+		if group == nil || group.WorkspaceID != ch.WorkspaceID {
+			return openapi.BulkAddChannelMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+		}
+		members, err := h.userGroupRepo.ListMembers(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			addUser(m.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return openapi.BulkAddChannelMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "user_ids or user_group_id is required")}, nil
+	}
+	if len(userIDs) > maxBulkChannelMembers {
+		return openapi.BulkAddChannelMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("At most %d users can be added at once", maxBulkChannelMembers))}, nil
+	}
+
+	role := channel.ChannelRolePoster
+	if request.Body.Role != nil {
+		role = string(*request.Body.Role)
+	}
+	added, err := h.channelRepo.AddMembers(ctx, ch, userIDs, &role)
+	if err != nil {
+		return nil, err
+	}
+
+	result := openapi.ChannelMembersUpdated{
+		ChannelId:    ch.ID,
+		AddedUserIds: added,
+		ActorId:      &userID,
+	}
+	if result.AddedUserIds == nil {
+		result.AddedUserIds = []string{}
+	}
+	if len(added) == 0 {
+		return openapi.BulkAddChannelMembers200JSONResponse(result), nil
+	}
+
+	if h.hub != nil {
+		for _, memberID := range added {
+			h.hub.AddChannelMember(ch.ID, memberID)
+		}
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelMembersUpdatedEvent(result))
+	}
+
+	if len(added) == 1 {
+		h.createAddedSystemMessage(ctx, ch, added[0], userID)
+	} else if actor, err := h.userRepo.GetByID(ctx, userID); err == nil {
+		count := len(added)
+		h.createChannelSystemMessage(ctx, ch, &message.SystemEventData{
+			EventType:       message.SystemEventUsersAdded,
+			UserID:          userID,
+			UserDisplayName: actor.DisplayName,
+			ChannelName:     ch.Name,
+			UserCount:       &count,
+		})
+	}
+	for _, memberID := range added {
+		h.recordActivity(ctx, ch, &activity.Activity{UserID: memberID, ActorID: &userID, Type: activity.TypeChannelInvite})
+	}
+
+	return openapi.BulkAddChannelMembers200JSONResponse(result), nil
+}
+
+// ListChannelMembers lists a page of a channel's members, or returns 304 if
+// the page is unchanged
 func (h *Handler) ListChannelMembers(ctx context.Context, request openapi.ListChannelMembersRequestObject) (openapi.ListChannelMembersResponseObject, error) {
 	userID := h.getUserID(ctx)
 	if userID == "" {
@@ -483,6 +1062,9 @@ func (h *Handler) ListChannelMembers(ctx context.Context, request openapi.ListCh
 	// Check workspace membership
 	_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ListChannelMembers403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -497,18 +1079,49 @@ func (h *Handler) ListChannelMembers(ctx context.Context, request openapi.ListCh
 		}
 	}
 
-	members, err := h.channelRepo.ListMembers(ctx, string(request.Id))
+	var opts channel.MemberListOptions
+	if request.Params.Cursor != nil {
+		opts.Cursor = *request.Params.Cursor
+	}
+	if request.Params.Limit != nil {
+		opts.Limit = *request.Params.Limit
+	}
+	if request.Params.Q != nil {
+		opts.Query = *request.Params.Q
+	}
+	if request.Params.Role != nil {
+		opts.Role = string(*request.Params.Role)
+	}
+
+	version, err := h.channelRepo.MembersVersion(ctx, string(request.Id))
 	if err != nil {
 		return nil, err
 	}
+	etag := pageETag(version, opts.Cursor, strconv.Itoa(opts.Limit), opts.Query, opts.Role)
+	if request.Params.IfNoneMatch != nil && etagMatches(*request.Params.IfNoneMatch, etag) {
+		return openapi.ListChannelMembers304Response{Headers: openapi.ListChannelMembers304ResponseHeaders{ETag: etag}}, nil
+	}
+
+	result, err := h.channelRepo.ListMembers(ctx, string(request.Id), opts)
+	if err != nil {
+		if errors.Is(err, channel.ErrInvalidCursor) {
+			return openapi.ListChannelMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid cursor")}, nil
+		}
+		return nil, err
+	}
 
-	apiMembers := make([]openapi.ChannelMember, len(members))
-	for i, m := range members {
+	apiMembers := make([]openapi.ChannelMember, len(result.Members))
+	for i, m := range result.Members {
 		apiMembers[i] = channelMemberToAPI(m)
 	}
+	body := openapi.ChannelMemberListResult{Members: apiMembers, HasMore: result.HasMore}
+	if result.NextCursor != "" {
+		body.NextCursor = &result.NextCursor
+	}
 
 	return openapi.ListChannelMembers200JSONResponse{
-		Members: apiMembers,
+		Body:    body,
+		Headers: openapi.ListChannelMembers200ResponseHeaders{ETag: etag},
 	}, nil
 }
 
@@ -532,28 +1145,41 @@ func (h *Handler) JoinChannel(ctx context.Context, request openapi.JoinChannelRe
 	// Check workspace membership
 	_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.JoinChannel403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
-	memberRole := "poster"
+	memberRole := channel.ChannelRolePoster
 	_, err = h.channelRepo.AddMember(ctx, userID, string(request.Id), &memberRole)
-	wasAlreadyMember := errors.Is(err, channel.ErrAlreadyMember)
-	if wasAlreadyMember {
-		// Update role if already a member (in case role was NULL)
-		_ = h.channelRepo.UpdateMemberRole(ctx, userID, string(request.Id), &memberRole)
-	} else if err != nil {
-		return nil, err
+	if errors.Is(err, channel.ErrAlreadyMember) {
+		// Give existing members without a role the poster role, leaving
+		// anyone who already has one alone
+		membership, err := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+		if err != nil {
+			return nil, err
+		}
+		if membership.ChannelRole == nil {
+			if err := h.channelRepo.UpdateMemberRole(ctx, userID, string(request.Id), &memberRole); err != nil {
+				return nil, err
+			}
+			if h.hub != nil {
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelMemberRoleChangedEvent(openapi.ChannelMemberRoleChangedData{
+					ChannelId: ch.ID,
+					UserId:    userID,
+					NewRole:   openapi.ChannelRole(memberRole),
+				}))
+			}
+		}
+		return openapi.JoinChannel200JSONResponse{Success: true}, nil
 	}
-
-	// Update SSE hub cache for channel membership
-	if h.hub != nil {
-		h.hub.AddChannelMember(string(request.Id), userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create system message if this is a new join (not already a member)
-	if !wasAlreadyMember {
-		h.createJoinSystemMessage(ctx, ch, userID)
-	}
+	h.broadcastChannelMemberAdded(ctx, ch, userID)
+	h.createJoinSystemMessage(ctx, ch, userID)
 
 	return openapi.JoinChannel200JSONResponse{
 		Success: true,
@@ -589,7 +1215,7 @@ func (h *Handler) LeaveChannel(ctx context.Context, request openapi.LeaveChannel
 		h.hub.RemoveChannelMember(string(request.Id), userID)
 
 		// Broadcast member removed
-		h.hub.BroadcastToChannel(ch.WorkspaceID, string(request.Id), sse.NewChannelMemberRemovedEvent(openapi.ChannelMemberData{
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, string(request.Id), sse.NewChannelMemberRemovedEvent(openapi.ChannelMemberData{
 			ChannelId: string(request.Id),
 			UserId:    userID,
 		}))
@@ -599,7 +1225,7 @@ func (h *Handler) LeaveChannel(ctx context.Context, request openapi.LeaveChannel
 			updatedCh, err := h.channelRepo.GetByID(ctx, string(request.Id))
 			if err == nil {
 				apiCh := channelToAPI(updatedCh)
-				h.hub.BroadcastToChannel(ch.WorkspaceID, string(request.Id), sse.NewChannelUpdatedEvent(apiCh))
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, string(request.Id), sse.NewChannelUpdatedEvent(apiCh))
 			}
 		}
 	}
@@ -612,41 +1238,115 @@ func (h *Handler) LeaveChannel(ctx context.Context, request openapi.LeaveChannel
 	}, nil
 }
 
+// canPostIn reports whether userID may post in ch with the given channel
+// role, taking the channel's post policy into account.
+func (h *Handler) canPostIn(ctx context.Context, ch *channel.Channel, userID string, channelRole *string, threadReply bool) (bool, error) {
+	workspaceRole := ""
+	if ch.PostPolicy != "" && ch.PostPolicy != channel.PostPolicyEveryone {
+		membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+		if err != nil {
+			if errors.Is(err, workspace.ErrNotAMember) {
+				return false, nil
+			}
+			return false, err
+		}
+		workspaceRole = membership.Role
+	}
+	return ch.CanPost(workspaceRole, channelRole, threadReply), nil
+}
+
+// slowModeRetryAfter returns how many seconds the user must wait before
+// posting again in a slow mode channel, or 0 if they may post now. Channel
+// and workspace admins are never limited.
+func (h *Handler) slowModeRetryAfter(ctx context.Context, ch *channel.Channel, userID string, channelRole *string) (int, error) {
+	if ch.SlowModeSeconds <= 0 || channel.CanManageChannel(channelRole) {
+		return 0, nil
+	}
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	if workspace.CanManageMembers(membership.Role) {
+		return 0, nil
+	}
+	last, err := h.messageRepo.LastPostedAt(ctx, ch.ID, userID)
+	if err != nil || last == nil {
+		return 0, err
+	}
+	wait := time.Until(last.Add(time.Duration(ch.SlowModeSeconds) * time.Second))
+	if wait <= 0 {
+		return 0, nil
+	}
+	return int(math.Ceil(wait.Seconds())), nil
+}
+
+// postRolesToAPI converts stored post roles, omitting an empty list
+func postRolesToAPI(roles []string) *[]openapi.WorkspaceRole {
+	if len(roles) == 0 {
+		return nil
+	}
+	apiRoles := make([]openapi.WorkspaceRole, len(roles))
+	for i, role := range roles {
+		apiRoles[i] = openapi.WorkspaceRole(role)
+	}
+	return &apiRoles
+}
+
 // channelToAPI converts a channel.Channel to openapi.Channel
 func channelToAPI(ch *channel.Channel) openapi.Channel {
 	return openapi.Channel{
-		Id:                ch.ID,
-		WorkspaceId:       ch.WorkspaceID,
-		Name:              ch.Name,
-		Description:       ch.Description,
-		Type:              openapi.ChannelType(ch.Type),
-		IsDefault:         ch.IsDefault,
-		DmParticipantHash: ch.DMParticipantHash,
-		ArchivedAt:        ch.ArchivedAt,
-		CreatedBy:         ch.CreatedBy,
-		CreatedAt:         ch.CreatedAt,
-		UpdatedAt:         ch.UpdatedAt,
+		Id:                    ch.ID,
+		WorkspaceId:           ch.WorkspaceID,
+		Name:                  ch.Name,
+		Description:           ch.Description,
+		Topic:                 ch.Topic,
+		Type:                  openapi.ChannelType(ch.Type),
+		IsDefault:             ch.IsDefault,
+		AutoJoin:              ch.AutoJoin,
+		HistoryVisibility:     openapi.ChannelHistoryVisibility(ch.HistoryVisibility),
+		MessageRetentionDays:  ch.MessageRetentionDays,
+		WhoCanMentionChannel:  (*openapi.PermissionLevel)(ch.WhoCanMentionChannel),
+		PostPolicy:            openapi.ChannelPostPolicy(ch.PostPolicy),
+		PostRoles:             postRolesToAPI(ch.PostRoles),
+		RestrictThreadReplies: ch.RestrictThreadReplies,
+		SlowModeSeconds:       ch.SlowModeSeconds,
+		DmParticipantHash:     ch.DMParticipantHash,
+		ArchivedAt:            ch.ArchivedAt,
+		CreatedBy:             ch.CreatedBy,
+		CreatedAt:             ch.CreatedAt,
+		UpdatedAt:             ch.UpdatedAt,
 	}
 }
 
 // channelWithMembershipToAPI converts a channel.ChannelWithMembership to openapi.ChannelWithMembership
 func channelWithMembershipToAPI(ch channel.ChannelWithMembership) openapi.ChannelWithMembership {
 	apiCh := openapi.ChannelWithMembership{
-		Id:                ch.ID,
-		WorkspaceId:       ch.WorkspaceID,
-		Name:              ch.Name,
-		Description:       ch.Description,
-		Type:              openapi.ChannelType(ch.Type),
-		IsDefault:         ch.IsDefault,
-		DmParticipantHash: ch.DMParticipantHash,
-		ArchivedAt:        ch.ArchivedAt,
-		CreatedBy:         ch.CreatedBy,
-		CreatedAt:         ch.CreatedAt,
-		UpdatedAt:         ch.UpdatedAt,
-		LastReadMessageId: ch.LastReadMessageID,
-		UnreadCount:       ch.UnreadCount,
-		NotificationCount: ch.NotificationCount,
-		IsStarred:         ch.IsStarred,
+		Id:                    ch.ID,
+		WorkspaceId:           ch.WorkspaceID,
+		Name:                  ch.Name,
+		Description:           ch.Description,
+		Topic:                 ch.Topic,
+		Type:                  openapi.ChannelType(ch.Type),
+		IsDefault:             ch.IsDefault,
+		AutoJoin:              ch.AutoJoin,
+		HistoryVisibility:     openapi.ChannelHistoryVisibility(ch.HistoryVisibility),
+		MessageRetentionDays:  ch.MessageRetentionDays,
+		WhoCanMentionChannel:  (*openapi.PermissionLevel)(ch.WhoCanMentionChannel),
+		PostPolicy:            openapi.ChannelPostPolicy(ch.PostPolicy),
+		PostRoles:             postRolesToAPI(ch.PostRoles),
+		RestrictThreadReplies: ch.RestrictThreadReplies,
+		SlowModeSeconds:       ch.SlowModeSeconds,
+		DmParticipantHash:     ch.DMParticipantHash,
+		ArchivedAt:            ch.ArchivedAt,
+		CreatedBy:             ch.CreatedBy,
+		CreatedAt:             ch.CreatedAt,
+		UpdatedAt:             ch.UpdatedAt,
+		LastReadMessageId:     ch.LastReadMessageID,
+		UnreadCount:           ch.UnreadCount,
+		NotificationCount:     ch.NotificationCount,
+		IsStarred:             ch.IsStarred,
+		MemberCount:           ch.MemberCount,
+		SnoozedUntil:          ch.SnoozedUntil,
 	}
 	if ch.ChannelRole != nil {
 		role := openapi.ChannelRole(*ch.ChannelRole)
@@ -662,13 +1362,85 @@ func channelWithMembershipToAPI(ch channel.ChannelWithMembership) openapi.Channe
 	return apiCh
 }
 
+// dmConversationToAPI converts a channel.DMInboxEntry to openapi.DMConversation
+func dmConversationToAPI(e channel.DMInboxEntry) openapi.DMConversation {
+	ch := channelWithMembershipToAPI(e.ChannelWithMembership)
+	return openapi.DMConversation{
+		Id:                    ch.Id,
+		WorkspaceId:           ch.WorkspaceId,
+		Name:                  ch.Name,
+		Description:           ch.Description,
+		Topic:                 ch.Topic,
+		Type:                  ch.Type,
+		IsDefault:             ch.IsDefault,
+		AutoJoin:              ch.AutoJoin,
+		HistoryVisibility:     ch.HistoryVisibility,
+		MessageRetentionDays:  ch.MessageRetentionDays,
+		WhoCanMentionChannel:  ch.WhoCanMentionChannel,
+		PostPolicy:            ch.PostPolicy,
+		PostRoles:             ch.PostRoles,
+		RestrictThreadReplies: ch.RestrictThreadReplies,
+		SlowModeSeconds:       ch.SlowModeSeconds,
+		DmParticipantHash:     ch.DmParticipantHash,
+		ArchivedAt:            ch.ArchivedAt,
+		CreatedBy:             ch.CreatedBy,
+		CreatedAt:             ch.CreatedAt,
+		UpdatedAt:             ch.UpdatedAt,
+		ChannelRole:           ch.ChannelRole,
+		LastReadMessageId:     ch.LastReadMessageId,
+		UnreadCount:           ch.UnreadCount,
+		NotificationCount:     ch.NotificationCount,
+		IsStarred:             ch.IsStarred,
+		MemberCount:           ch.MemberCount,
+		DmParticipants:        ch.DmParticipants,
+		SnoozedUntil:          ch.SnoozedUntil,
+		WorkspaceName:         e.WorkspaceName,
+		WorkspaceIconUrl:      e.WorkspaceIconURL,
+		LastMessageAt:         e.LastMessageAt,
+		LastMessagePreview:    e.LastMessagePreview,
+	}
+}
+
+// channelDirectoryEntryToAPI converts a channel.DirectoryEntry to openapi.ChannelDirectoryEntry
+func channelDirectoryEntryToAPI(e *channel.DirectoryEntry) openapi.ChannelDirectoryEntry {
+	return openapi.ChannelDirectoryEntry{
+		Id:                    e.ID,
+		WorkspaceId:           e.WorkspaceID,
+		Name:                  e.Name,
+		Description:           e.Description,
+		Topic:                 e.Topic,
+		Type:                  openapi.ChannelType(e.Type),
+		IsDefault:             e.IsDefault,
+		AutoJoin:              e.AutoJoin,
+		HistoryVisibility:     openapi.ChannelHistoryVisibility(e.HistoryVisibility),
+		MessageRetentionDays:  e.MessageRetentionDays,
+		WhoCanMentionChannel:  (*openapi.PermissionLevel)(e.WhoCanMentionChannel),
+		PostPolicy:            openapi.ChannelPostPolicy(e.PostPolicy),
+		PostRoles:             postRolesToAPI(e.PostRoles),
+		RestrictThreadReplies: e.RestrictThreadReplies,
+		SlowModeSeconds:       e.SlowModeSeconds,
+		ArchivedAt:            e.ArchivedAt,
+		CreatedBy:             e.CreatedBy,
+		CreatedAt:             e.CreatedAt,
+		UpdatedAt:             e.UpdatedAt,
+		MemberCount:           e.MemberCount,
+		IsMember:              e.IsMember,
+		LastActivityAt:        e.LastActivityAt,
+		LastMessagePreview:    e.LastMessagePreview,
+	}
+}
+
 // channelMemberToAPI converts a channel.MemberInfo to openapi.ChannelMember
 func channelMemberToAPI(m channel.MemberInfo) openapi.ChannelMember {
 	apiMember := openapi.ChannelMember{
-		UserId:      m.UserID,
-		Email:       openapi_types.Email(m.Email),
-		DisplayName: m.DisplayName,
-		AvatarUrl:   m.AvatarURL,
+		UserId:        m.UserID,
+		Email:         openapi_types.Email(m.Email),
+		DisplayName:   m.DisplayName,
+		AvatarUrl:     m.AvatarURL,
+		Title:         m.Title,
+		Pronouns:      m.Pronouns,
+		Timezone:      m.Timezone,
+		IsDeactivated: &m.IsDeactivated,
 	}
 	if m.ChannelRole != nil {
 		role := openapi.ChannelRole(*m.ChannelRole)
@@ -700,6 +1472,9 @@ func (h *Handler) ConvertGroupDMToChannel(ctx context.Context, request openapi.C
 	// Check workspace membership and permissions
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ConvertGroupDMToChannel403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -714,7 +1489,7 @@ func (h *Handler) ConvertGroupDMToChannel(ctx context.Context, request openapi.C
 	}
 
 	// Must be a member of the group DM
-	_, err = h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	channelMembership, err := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			return openapi.ConvertGroupDMToChannel403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You must be a member of this conversation")}, nil
@@ -752,10 +1527,17 @@ func (h *Handler) ConvertGroupDMToChannel(ctx context.Context, request openapi.C
 		return nil, err
 	}
 
-	// Broadcast channel updated via SSE
+	// Broadcast channel updated via SSE, and the converting user's
+	// promotion to channel admin
 	if h.hub != nil {
 		apiCh := channelToAPI(converted)
-		h.hub.BroadcastToChannel(ch.WorkspaceID, converted.ID, sse.NewChannelUpdatedEvent(apiCh))
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, converted.ID, sse.NewChannelUpdatedEvent(apiCh))
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, converted.ID, sse.NewChannelMemberRoleChangedEvent(openapi.ChannelMemberRoleChangedData{
+			ChannelId: converted.ID,
+			UserId:    userID,
+			OldRole:   (*openapi.ChannelRole)(channelMembership.ChannelRole),
+			NewRole:   openapi.ChannelRoleAdmin,
+		}))
 	}
 
 	// Create system message for the conversion
@@ -792,7 +1574,7 @@ func (h *Handler) createConvertSystemMessage(ctx context.Context, ch *channel.Ch
 		msgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, msg.ID)
 		if msgWithUser != nil {
 			apiMsg := messageWithUserToAPI(msgWithUser)
-			h.hub.BroadcastToChannel(ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
 		}
 	}
 }
@@ -828,6 +1610,15 @@ func (h *Handler) MarkChannelRead(ctx context.Context, request openapi.MarkChann
 		}, nil
 	}
 
+	// Read state from before the update, when asked for counts
+	var before *channel.ReadState
+	if request.Body != nil && request.Body.IncludeCounts != nil && *request.Body.IncludeCounts {
+		before, err = h.channelRepo.GetReadState(ctx, userID, ch.ID)
+		if err != nil && !errors.Is(err, channel.ErrNotChannelMember) {
+			return nil, err
+		}
+	}
+
 	// Update last read
 	if err := h.channelRepo.UpdateLastRead(ctx, userID, string(request.Id), messageID); err != nil {
 		return nil, err
@@ -835,14 +1626,58 @@ func (h *Handler) MarkChannelRead(ctx context.Context, request openapi.MarkChann
 
 	// Broadcast to user's other clients
 	if h.hub != nil {
-		h.hub.BroadcastToUser(ch.WorkspaceID, userID, sse.NewChannelReadEvent(openapi.ChannelReadEventData{
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewChannelReadEvent(openapi.ChannelReadEventData{
 			ChannelId:         string(request.Id),
 			LastReadMessageId: messageID,
 		}))
 	}
 
-	return openapi.MarkChannelRead200JSONResponse{
+	resp := openapi.MarkChannelRead200JSONResponse{
 		LastReadMessageId: messageID,
+	}
+	if before != nil {
+		var lastRead string
+		if before.LastReadMessageID != nil {
+			lastRead = *before.LastReadMessageID
+		}
+		marked, err := h.channelRepo.CountReadBetween(ctx, ch.ID, lastRead, messageID)
+		if err != nil {
+			return nil, err
+		}
+		resp.MarkedCount = &marked
+		resp.FirstUnreadMessageId = before.FirstUnreadMessageID
+	}
+	return resp, nil
+}
+
+// GetChannelReadState returns where the caller's "New messages" divider
+// belongs in a channel
+func (h *Handler) GetChannelReadState(ctx context.Context, request openapi.GetChannelReadStateRequestObject) (openapi.GetChannelReadStateResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetChannelReadState401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.GetChannelReadState404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	state, err := h.channelRepo.GetReadState(ctx, userID, ch.ID)
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.GetChannelReadState403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+
+	return openapi.GetChannelReadState200JSONResponse{
+		LastReadMessageId:    state.LastReadMessageID,
+		FirstUnreadMessageId: state.FirstUnreadMessageID,
+		UnreadCount:          state.UnreadCount,
 	}, nil
 }
 
@@ -856,36 +1691,30 @@ func (h *Handler) MarkAllChannelsRead(ctx context.Context, request openapi.MarkA
 	// Check workspace membership
 	_, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.MarkAllChannelsRead403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
-	// Get all channels user is a member of
-	channelIDs, err := h.channelRepo.ListMemberChannelIDs(ctx, string(request.Wid), userID)
+	marks, err := h.channelRepo.MarkAllRead(ctx, string(request.Wid), userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Mark each channel as read
-	for _, channelID := range channelIDs {
-		messageID, err := h.channelRepo.GetLatestMessageID(ctx, channelID)
-		if err != nil {
-			continue
-		}
-		if messageID == "" {
-			continue
-		}
-
-		if err := h.channelRepo.UpdateLastRead(ctx, userID, channelID, messageID); err != nil {
-			continue
-		}
-
-		// Broadcast to user's other clients
-		if h.hub != nil {
-			h.hub.BroadcastToUser(string(request.Wid), userID, sse.NewChannelReadEvent(openapi.ChannelReadEventData{
-				ChannelId:         channelID,
-				LastReadMessageId: messageID,
-			}))
+	// Broadcast to user's other clients as one event rather than one per channel
+	if h.hub != nil && len(marks) > 0 {
+		channels := make([]openapi.ChannelReadEventData, len(marks))
+		for i, m := range marks {
+			channels[i] = openapi.ChannelReadEventData{
+				ChannelId:         m.ChannelID,
+				LastReadMessageId: m.MessageID,
+			}
 		}
+		h.hub.BroadcastToUser(ctx, string(request.Wid), userID, sse.NewWorkspaceReadEvent(openapi.WorkspaceReadEventData{
+			WorkspaceId: string(request.Wid),
+			Channels:    channels,
+		}))
 	}
 
 	return openapi.MarkAllChannelsRead200JSONResponse{
@@ -908,6 +1737,9 @@ func (h *Handler) GetChannelNotifications(ctx context.Context, request openapi.G
 	// Check workspace membership
 	_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.GetChannelNotifications403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -938,12 +1770,15 @@ func (h *Handler) UpdateChannelNotifications(ctx context.Context, request openap
 	// Check workspace membership
 	_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UpdateChannelNotifications403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
 	// Validate notify level
 	notifyLevel := string(request.Body.NotifyLevel)
-	if notifyLevel != notification.NotifyAll && notifyLevel != notification.NotifyMentions && notifyLevel != notification.NotifyNone {
+	if !validNotifyLevel(notifyLevel) {
 		return openapi.UpdateChannelNotifications400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid notify_level")}, nil
 	}
 
@@ -964,11 +1799,47 @@ func (h *Handler) UpdateChannelNotifications(ctx context.Context, request openap
 	}, nil
 }
 
+// ResetChannelNotifications removes a channel's notification preferences so
+// it inherits the user's workspace and global settings
+func (h *Handler) ResetChannelNotifications(ctx context.Context, request openapi.ResetChannelNotificationsRequestObject) (openapi.ResetChannelNotificationsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ResetChannelNotifications401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check workspace membership
+	_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ResetChannelNotifications403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	if err := h.notificationService.ResetPreferences(ctx, userID, ch.ID); err != nil {
+		return nil, err
+	}
+	pref, err := h.notificationService.GetPreferences(ctx, userID, ch.ID, ch.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.ResetChannelNotifications200JSONResponse{
+		Preferences: notificationPreferencesToAPI(pref),
+	}, nil
+}
+
 // notificationPreferencesToAPI converts notification preferences to API type
 func notificationPreferencesToAPI(pref *notification.NotificationPreference) openapi.NotificationPreferences {
 	return openapi.NotificationPreferences{
 		NotifyLevel:  openapi.NotifyLevel(pref.NotifyLevel),
 		EmailEnabled: pref.EmailEnabled,
+		Inherited:    &pref.Inherited,
 	}
 }
 
@@ -986,6 +1857,8 @@ func (h *Handler) StarChannel(ctx context.Context, request openapi.StarChannelRe
 		return nil, err
 	}
 
+	h.broadcastStarChange(ctx, userID, string(request.Id), sse.NewChannelStarredEvent)
+
 	return openapi.StarChannel200JSONResponse{
 		Success: true,
 	}, nil
@@ -1005,11 +1878,26 @@ func (h *Handler) UnstarChannel(ctx context.Context, request openapi.UnstarChann
 		return nil, err
 	}
 
+	h.broadcastStarChange(ctx, userID, string(request.Id), sse.NewChannelUnstarredEvent)
+
 	return openapi.UnstarChannel200JSONResponse{
 		Success: true,
 	}, nil
 }
 
+// broadcastStarChange notifies the user's other sessions that a channel's
+// starred state changed
+func (h *Handler) broadcastStarChange(ctx context.Context, userID, channelID string, newEvent func(openapi.ChannelStarredData) sse.Event) {
+	if h.hub == nil {
+		return
+	}
+	ch, err := h.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		return
+	}
+	h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, newEvent(openapi.ChannelStarredData{ChannelId: channelID}))
+}
+
 // createJoinSystemMessage creates a system message when a user joins a channel
 func (h *Handler) createJoinSystemMessage(ctx context.Context, ch *channel.Channel, userID string) {
 	user, err := h.userRepo.GetByID(ctx, userID)
@@ -1059,7 +1947,7 @@ func (h *Handler) createChannelSystemMessage(ctx context.Context, ch *channel.Ch
 		msgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, msg.ID)
 		if msgWithUser != nil {
 			apiMsg := messageWithUserToAPI(msgWithUser)
-			h.hub.BroadcastToChannel(ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
 		}
 	}
 }
@@ -1094,6 +1982,20 @@ func (h *Handler) createChannelVisibilityChangedSystemMessage(ctx context.Contex
 	})
 }
 
+// createChannelArchiveSystemMessage creates a system message when a channel is archived or unarchived
+func (h *Handler) createChannelArchiveSystemMessage(ctx context.Context, ch *channel.Channel, userID, eventType string) {
+	user, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return
+	}
+	h.createChannelSystemMessage(ctx, ch, &message.SystemEventData{
+		EventType:       eventType,
+		UserID:          userID,
+		UserDisplayName: user.DisplayName,
+		ChannelName:     ch.Name,
+	})
+}
+
 // createChannelDescriptionUpdatedSystemMessage creates a system message when the channel description changes
 func (h *Handler) createChannelDescriptionUpdatedSystemMessage(ctx context.Context, ch *channel.Channel, userID string) {
 	user, err := h.userRepo.GetByID(ctx, userID)
@@ -1152,7 +2054,7 @@ func (h *Handler) createAddedSystemMessage(ctx context.Context, ch *channel.Chan
 		msgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, msg.ID)
 		if msgWithUser != nil {
 			apiMsg := messageWithUserToAPI(msgWithUser)
-			h.hub.BroadcastToChannel(ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
 		}
 	}
 }
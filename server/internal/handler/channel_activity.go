@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// Channel activity periods, in days
+const (
+	defaultActivityDays = 30
+	maxActivityDays     = 365
+)
+
+// GetChannelActivity returns a channel's daily message counts for the
+// activity graph in channel details
+func (h *Handler) GetChannelActivity(ctx context.Context, request openapi.GetChannelActivityRequestObject) (openapi.GetChannelActivityResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetChannelActivity401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	days := defaultActivityDays
+	if request.Params.Days != nil {
+		days = *request.Params.Days
+		if days < 1 || days > maxActivityDays {
+			return openapi.GetChannelActivity400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "days must be between 1 and 365")}, nil
+		}
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.GetChannelActivity404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.GetChannelActivity403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -(days - 1))
+	activity, err := h.messageRepo.DailyActivity(ctx, ch.ID, since, days)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := openapi.GetChannelActivity200JSONResponse{
+		Days:          make([]openapi.ChannelActivityDay, len(activity.Days)),
+		TotalMessages: activity.Total,
+		UniquePosters: activity.Posters,
+	}
+	for i, d := range activity.Days {
+		resp.Days[i] = openapi.ChannelActivityDay{Date: d.Date, MessageCount: d.Count}
+	}
+	return resp, nil
+}
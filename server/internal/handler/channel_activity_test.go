@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestGetChannelActivity(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, outsider.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePrivate)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "today")
+	testutil.CreateTestMessage(t, db, ch.ID, member.ID, "today too")
+	old := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "two days ago")
+	deleted := testutil.CreateTestMessage(t, db, ch.ID, member.ID, "deleted")
+	outOfRange := testutil.CreateTestMessage(t, db, ch.ID, member.ID, "last week")
+
+	now := time.Now().UTC()
+	at := func(id string, ts time.Time) {
+		if _, err := db.Exec(`UPDATE messages SET created_at = ? WHERE id = ?`, ts.Format(time.RFC3339), id); err != nil {
+			t.Fatalf("updating message: %v", err)
+		}
+	}
+	at(old.ID, now.AddDate(0, 0, -2))
+	at(outOfRange.ID, now.AddDate(0, 0, -7))
+	if _, err := db.Exec(`UPDATE messages SET deleted_at = ? WHERE id = ?`, now.Format(time.RFC3339), deleted.ID); err != nil {
+		t.Fatalf("deleting message: %v", err)
+	}
+
+	days := 3
+	resp, err := h.GetChannelActivity(ctxWithUser(t, h, member.ID), openapi.GetChannelActivityRequestObject{
+		Id:     openapi.ChannelId(ch.ID),
+		Params: openapi.GetChannelActivityParams{Days: &days},
+	})
+	if err != nil {
+		t.Fatalf("GetChannelActivity: %v", err)
+	}
+	activity, ok := resp.(openapi.GetChannelActivity200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	if len(activity.Days) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(activity.Days))
+	}
+	want := []int{1, 0, 2}
+	for i, d := range activity.Days {
+		if d.MessageCount != want[i] {
+			t.Errorf("day %d (%s): expected %d messages, got %d", i, d.Date, want[i], d.MessageCount)
+		}
+	}
+	if last := activity.Days[2].Date; last != now.Format(time.DateOnly) {
+		t.Errorf("expected the series to end today, got %s", last)
+	}
+	if activity.TotalMessages != 3 || activity.UniquePosters != 2 {
+		t.Errorf("expected 3 messages from 2 posters, got %d from %d", activity.TotalMessages, activity.UniquePosters)
+	}
+
+	// Default period
+	resp, err = h.GetChannelActivity(ctxWithUser(t, h, member.ID), openapi.GetChannelActivityRequestObject{Id: openapi.ChannelId(ch.ID)})
+	if err != nil {
+		t.Fatalf("GetChannelActivity: %v", err)
+	}
+	if got := len(resp.(openapi.GetChannelActivity200JSONResponse).Days); got != defaultActivityDays {
+		t.Errorf("expected %d days by default, got %d", defaultActivityDays, got)
+	}
+
+	days = 366
+	resp, err = h.GetChannelActivity(ctxWithUser(t, h, member.ID), openapi.GetChannelActivityRequestObject{
+		Id:     openapi.ChannelId(ch.ID),
+		Params: openapi.GetChannelActivityParams{Days: &days},
+	})
+	if err != nil {
+		t.Fatalf("GetChannelActivity: %v", err)
+	}
+	if _, ok := resp.(openapi.GetChannelActivity400JSONResponse); !ok {
+		t.Errorf("expected 400 for too many days, got %T", resp)
+	}
+
+	resp, err = h.GetChannelActivity(ctxWithUser(t, h, outsider.ID), openapi.GetChannelActivityRequestObject{Id: openapi.ChannelId(ch.ID)})
+	if err != nil {
+		t.Fatalf("GetChannelActivity: %v", err)
+	}
+	if _, ok := resp.(openapi.GetChannelActivity403JSONResponse); !ok {
+		t.Errorf("expected 403 for non-member of private channel, got %T", resp)
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+)
+
+// FocusChannel records whether one of the caller's connections has a channel
+// on screen, marking the channel read when it comes into view
+func (h *Handler) FocusChannel(ctx context.Context, request openapi.FocusChannelRequestObject) (openapi.FocusChannelResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.FocusChannel401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.FocusChannel404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.FocusChannel403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot view this channel")}, nil
+	}
+
+	focused := request.Body.Focused == nil || *request.Body.Focused
+	if h.hub == nil || !h.hub.FocusChannel(ctx, ch.WorkspaceID, userID, request.Body.ClientId, ch.ID, focused) {
+		return openapi.FocusChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "client_id is not an open event stream connection")}, nil
+	}
+
+	if focused {
+		if _, err := h.channelRepo.GetMembership(ctx, userID, ch.ID); err == nil {
+			latestID, err := h.channelRepo.GetLatestMessageID(ctx, ch.ID)
+			if err != nil {
+				return nil, err
+			}
+			if latestID != "" {
+				h.markChannelReadBy(ctx, ch, latestID, userID)
+			}
+		}
+	}
+
+	return openapi.FocusChannel200JSONResponse{
+		ChannelId: ch.ID,
+		UserIds:   h.hub.ChannelViewers(ch.WorkspaceID, ch.ID),
+	}, nil
+}
+
+// ListChannelViewers lists the users who have a channel on screen
+func (h *Handler) ListChannelViewers(ctx context.Context, request openapi.ListChannelViewersRequestObject) (openapi.ListChannelViewersResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListChannelViewers401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.ListChannelViewers404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.ListChannelViewers403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot view this channel")}, nil
+	}
+
+	viewers := []string{}
+	if h.hub != nil {
+		viewers = h.hub.ChannelViewers(ch.WorkspaceID, ch.ID)
+	}
+	return openapi.ListChannelViewers200JSONResponse{ChannelId: ch.ID, UserIds: viewers}, nil
+}
+
+// markReadForViewers marks a new channel message read for everyone who has
+// the channel on screen, so it never shows up as unread for them.
+func (h *Handler) markReadForViewers(ctx context.Context, ch *channel.Channel, messageID string) {
+	if h.hub == nil {
+		return
+	}
+	h.markChannelReadBy(ctx, ch, messageID, h.hub.ChannelViewers(ch.WorkspaceID, ch.ID)...)
+}
+
+// markChannelReadBy moves the users' read position in a channel to
+// messageID and tells their clients.
+func (h *Handler) markChannelReadBy(ctx context.Context, ch *channel.Channel, messageID string, userIDs ...string) {
+	for _, userID := range userIDs {
+		if err := h.channelRepo.UpdateLastRead(ctx, userID, ch.ID, messageID); err != nil {
+			slog.Error("failed to mark channel read for viewer", "channel_id", ch.ID, "user_id", userID, "error", err)
+			continue
+		}
+		if h.hub != nil {
+			h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewChannelReadEvent(openapi.ChannelReadEventData{
+				ChannelId:         ch.ID,
+				LastReadMessageId: messageID,
+			}))
+		}
+	}
+}
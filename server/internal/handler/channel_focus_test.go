@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestFocusChannel_MarksArrivingMessagesRead(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+	first := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "before focus")
+
+	client := connectSSEClient(t, h, ws.ID, member.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+	focus := func(clientID string, focused bool) openapi.FocusChannelResponseObject {
+		t.Helper()
+		resp, err := h.FocusChannel(memberCtx, openapi.FocusChannelRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.FocusChannelJSONRequestBody{ClientId: clientID, Focused: &focused},
+		})
+		if err != nil {
+			t.Fatalf("FocusChannel: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := focus("not-a-client", true).(openapi.FocusChannel400JSONResponse); !ok {
+		t.Error("focusing with an unknown client_id should be rejected")
+	}
+
+	r, ok := focus(client.ID, true).(openapi.FocusChannel200JSONResponse)
+	if !ok || len(r.UserIds) != 1 || r.UserIds[0] != member.ID {
+		t.Fatalf("focus = %#v, want the member as the only viewer", r)
+	}
+	expectSSEEvent(t, client, sse.EventChannelViewers)
+	expectSSEEvent(t, client, sse.EventChannelRead)
+	assertLastRead(t, h, member.ID, ch.ID, first.ID)
+
+	content := "while focused"
+	resp, err := h.SendMessage(ctxWithUser(t, h, owner.ID), openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	sent := resp.(openapi.SendMessage200JSONResponse)
+	expectSSEEvent(t, client, sse.EventMessageNew)
+	expectSSEEvent(t, client, sse.EventChannelRead)
+	assertLastRead(t, h, member.ID, ch.ID, sent.Message.Id)
+
+	focus(client.ID, false)
+	expectSSEEvent(t, client, sse.EventChannelViewers)
+	viewersResp, err := h.ListChannelViewers(memberCtx, openapi.ListChannelViewersRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("ListChannelViewers: %v", err)
+	}
+	if v := viewersResp.(openapi.ListChannelViewers200JSONResponse); len(v.UserIds) != 0 {
+		t.Errorf("viewers after blur = %v, want none", v.UserIds)
+	}
+}
+
+func assertLastRead(t *testing.T, h *Handler, userID, channelID, want string) {
+	t.Helper()
+
+	membership, err := h.channelRepo.GetMembership(t.Context(), userID, channelID)
+	if err != nil {
+		t.Fatalf("GetMembership: %v", err)
+	}
+	if membership.LastReadMessageID == nil || *membership.LastReadMessageID != want {
+		t.Errorf("last read = %v, want %s", membership.LastReadMessageID, want)
+	}
+}
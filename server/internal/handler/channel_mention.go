@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// errChannelMentionDenied is the message returned when a sender may not use
+// @channel, @here or @everyone
+const errChannelMentionDenied = "You don't have permission to use @channel, @here or @everyone in this channel"
+
+// PreviewChannelMention reports how many people a channel-wide mention would
+// notify and whether the caller may use one
+func (h *Handler) PreviewChannelMention(ctx context.Context, request openapi.PreviewChannelMentionRequestObject) (openapi.PreviewChannelMentionResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.PreviewChannelMention401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.PreviewChannelMention404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.PreviewChannelMention403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+	}
+
+	level, allowed, err := h.channelMentionPermission(ctx, ch, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := h.channelRepo.GetChannelMentionRecipientIDs(ctx, ch.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	online := 0
+	if h.hub != nil {
+		for _, id := range recipients {
+			if h.hub.IsUserOnline(ch.WorkspaceID, id) {
+				online++
+			}
+		}
+	}
+
+	return openapi.PreviewChannelMention200JSONResponse{
+		CanMentionChannel:    allowed,
+		WhoCanMentionChannel: openapi.PermissionLevel(level),
+		MemberCount:          len(recipients),
+		OnlineCount:          online,
+	}, nil
+}
+
+// Mention autocomplete page sizes
+const (
+	defaultMentionCandidates = 10
+	maxMentionCandidates     = 50
+)
+
+// ListMentionCandidates returns the channel members and user groups matching
+// what the user has typed after an @, most recent posters first
+func (h *Handler) ListMentionCandidates(ctx context.Context, request openapi.ListMentionCandidatesRequestObject) (openapi.ListMentionCandidatesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListMentionCandidates401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.ListMentionCandidates404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.ListMentionCandidates403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+	}
+
+	var query string
+	if request.Params.Q != nil {
+		query = strings.TrimPrefix(strings.TrimSpace(*request.Params.Q), "@")
+	}
+	limit := defaultMentionCandidates
+	if request.Params.Limit != nil && *request.Params.Limit > 0 {
+		limit = min(*request.Params.Limit, maxMentionCandidates)
+	}
+
+	candidates, err := h.channelRepo.ListMentionCandidates(ctx, ch.ID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]openapi.MentionCandidate, len(candidates))
+	for i, c := range candidates {
+		members[i] = openapi.MentionCandidate{
+			User:         channelMemberToAPI(c.MemberInfo),
+			Online:       h.hub != nil && h.hub.IsUserOnline(ch.WorkspaceID, c.UserID),
+			LastPostedAt: c.LastPostedAt,
+		}
+	}
+
+	found, err := h.userGroupRepo.Search(ctx, ch.WorkspaceID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]openapi.UserGroup, len(found))
+	for i := range found {
+		groups[i] = userGroupToAPI(&found[i])
+	}
+
+	return openapi.ListMentionCandidates200JSONResponse{Members: members, Groups: groups}, nil
+}
+
+// checkChannelMentions reports whether content uses @channel, @here or
+// @everyone when userID is not allowed to in ch
+func (h *Handler) checkChannelMentions(ctx context.Context, ch *channel.Channel, userID, content string) (denied bool, err error) {
+	mentions, _ := notification.ParseMentions(ctx, nil, ch.WorkspaceID, content)
+	if !slices.ContainsFunc(mentions, notification.IsSpecialMention) {
+		return false, nil
+	}
+	_, allowed, err := h.channelMentionPermission(ctx, ch, userID)
+	return !allowed, err
+}
+
+// channelMentionPermission returns the level that applies to channel-wide
+// mentions in ch and whether userID satisfies it. DMs are never restricted.
+// At the admins level, channel admins are allowed as well as workspace admins.
+func (h *Handler) channelMentionPermission(ctx context.Context, ch *channel.Channel, userID string) (workspace.PermissionLevel, bool, error) {
+	ws, err := h.workspaceRepo.GetByID(ctx, ch.WorkspaceID)
+	if err != nil {
+		return "", false, err
+	}
+	level := ws.ParsedSettings().WhoCanMentionChannel
+	if ch.WhoCanMentionChannel != nil {
+		level = workspace.PermissionLevel(*ch.WhoCanMentionChannel)
+	}
+
+	if ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM {
+		return level, true, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil {
+		return level, false, nil
+	}
+	if workspace.HasPermission(membership.Role, level) {
+		return level, true, nil
+	}
+	if level == workspace.PermissionAdmins {
+		chMembership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+		if err == nil && channel.CanManageChannel(chMembership.ChannelRole) {
+			return level, true, nil
+		}
+	}
+	return level, false, nil
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/usergroup"
+	"github.com/oklog/ulid/v2"
+)
+
+func TestSendMessage_ChannelMentionPermission(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	chAdmin := testutil.CreateTestUser(t, db, "chadmin@test.com", "Channel Admin")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	adminRole := channel.ChannelRoleAdmin
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, chAdmin.ID, ws.ID, "member")
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+	addChannelMember(t, db, chAdmin.ID, ch.ID, &adminRole)
+
+	if _, err := db.Exec(`UPDATE workspaces SET settings = ? WHERE id = ?`, `{"who_can_mention_channel":"admins"}`, ws.ID); err != nil {
+		t.Fatalf("updating settings: %v", err)
+	}
+
+	send := func(userID, content string) openapi.SendMessageResponseObject {
+		t.Helper()
+		resp, err := h.SendMessage(ctxWithUser(t, h, userID), openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	for _, content := range []string{"<!channel> lunch", "anyone around? @here", "<!everyone>"} {
+		if _, ok := send(member.ID, content).(openapi.SendMessage403JSONResponse); !ok {
+			t.Errorf("member sending %q: expected 403 response", content)
+		}
+	}
+	if _, ok := send(member.ID, "just chatting").(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("member sending without channel mention: expected 200 response")
+	}
+	if _, ok := send(owner.ID, "<!channel> release is out").(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("workspace owner: expected 200 response")
+	}
+	if _, ok := send(chAdmin.ID, "<!here> standup").(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("channel admin: expected 200 response")
+	}
+
+	// A channel override takes precedence over the workspace setting
+	everyone := openapi.ChannelMentionEveryone
+	resp, err := h.UpdateChannel(ctxWithUser(t, h, owner.ID), openapi.UpdateChannelRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.UpdateChannelJSONRequestBody{WhoCanMentionChannel: &everyone},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated, ok := resp.(openapi.UpdateChannel200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if updated.Channel.WhoCanMentionChannel == nil || *updated.Channel.WhoCanMentionChannel != openapi.Everyone {
+		t.Errorf("who_can_mention_channel = %v, want everyone", updated.Channel.WhoCanMentionChannel)
+	}
+	if _, ok := send(member.ID, "<!channel> lunch").(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("member with channel override: expected 200 response")
+	}
+
+	workspaceDefault := openapi.ChannelMentionWorkspaceDefault
+	if _, err := h.UpdateChannel(ctxWithUser(t, h, owner.ID), openapi.UpdateChannelRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.UpdateChannelJSONRequestBody{WhoCanMentionChannel: &workspaceDefault},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := send(member.ID, "<!channel> lunch").(openapi.SendMessage403JSONResponse); !ok {
+		t.Error("member after clearing override: expected 403 response")
+	}
+}
+
+func TestPreviewChannelMention(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	online := testutil.CreateTestUser(t, db, "online@test.com", "Online")
+	offline := testutil.CreateTestUser(t, db, "offline@test.com", "Offline")
+	muted := testutil.CreateTestUser(t, db, "muted@test.com", "Muted")
+	blocker := testutil.CreateTestUser(t, db, "blocker@test.com", "Blocker")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	for _, u := range []string{online.ID, offline.ID, muted.ID, blocker.ID} {
+		addWorkspaceMember(t, db, u, ws.ID, "member")
+		addChannelMember(t, db, u, ch.ID, nil)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`
+		INSERT INTO notification_preferences (id, user_id, channel_id, notify_level, email_enabled, created_at, updated_at)
+		VALUES (?, ?, ?, 'none', 0, ?, ?)
+	`, ulid.Make().String(), muted.ID, ch.ID, now, now); err != nil {
+		t.Fatalf("muting channel: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO user_blocks (workspace_id, blocker_id, blocked_id) VALUES (?, ?, ?)
+	`, ws.ID, blocker.ID, owner.ID); err != nil {
+		t.Fatalf("blocking: %v", err)
+	}
+	connectSSEClient(t, h, ws.ID, online.ID)
+
+	resp, err := h.PreviewChannelMention(ctxWithUser(t, h, owner.ID), openapi.PreviewChannelMentionRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.PreviewChannelMention200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if r.MemberCount != 2 || r.OnlineCount != 1 {
+		t.Errorf("member_count = %d, online_count = %d, want 2 and 1", r.MemberCount, r.OnlineCount)
+	}
+	if !r.CanMentionChannel || r.WhoCanMentionChannel != openapi.Everyone {
+		t.Errorf("can_mention_channel = %v (%s), want true (everyone)", r.CanMentionChannel, r.WhoCanMentionChannel)
+	}
+
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	resp, err = h.PreviewChannelMention(ctxWithUser(t, h, outsider.ID), openapi.PreviewChannelMentionRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.PreviewChannelMention403JSONResponse); !ok {
+		t.Errorf("outsider: expected 403 response, got %T", resp)
+	}
+}
+
+func TestListMentionCandidates(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice Chen")
+	alan := testutil.CreateTestUser(t, db, "alan@test.com", "Alan Smith")
+	outsider := testutil.CreateTestUser(t, db, "albert@test.com", "Albert")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	for _, id := range []string{alice.ID, alan.ID, outsider.ID} {
+		addWorkspaceMember(t, db, id, ws.ID, "member")
+	}
+	addChannelMember(t, db, alice.ID, ch.ID, nil)
+	addChannelMember(t, db, alan.ID, ch.ID, nil)
+
+	// Alan posted more recently than Alice, so he ranks first
+	old := testutil.CreateTestMessage(t, db, ch.ID, alice.ID, "hello")
+	testutil.CreateTestMessage(t, db, ch.ID, alan.ID, "hi")
+	if _, err := db.Exec(`UPDATE messages SET created_at = ? WHERE id = ?`, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), old.ID); err != nil {
+		t.Fatalf("backdating message: %v", err)
+	}
+
+	group := &usergroup.Group{WorkspaceID: ws.ID, Handle: "alpha-team", Name: "Alpha"}
+	if err := h.userGroupRepo.Create(context.Background(), group, []string{alice.ID}); err != nil {
+		t.Fatalf("creating group: %v", err)
+	}
+
+	q := "@al"
+	resp, err := h.ListMentionCandidates(ctxWithUser(t, h, owner.ID), openapi.ListMentionCandidatesRequestObject{
+		Id:     ch.ID,
+		Params: openapi.ListMentionCandidatesParams{Q: &q},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListMentionCandidates200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Members) != 2 || r.Members[0].User.UserId != alan.ID || r.Members[1].User.UserId != alice.ID {
+		t.Fatalf("expected Alan then Alice, got %+v", r.Members)
+	}
+	if r.Members[0].LastPostedAt == nil || r.Members[0].Online {
+		t.Errorf("expected a last post time and offline presence, got %+v", r.Members[0])
+	}
+	if len(r.Groups) != 1 || r.Groups[0].Handle != "alpha-team" {
+		t.Errorf("expected the alpha-team group, got %+v", r.Groups)
+	}
+
+	// Word prefixes match too
+	q = "smi"
+	resp, _ = h.ListMentionCandidates(ctxWithUser(t, h, owner.ID), openapi.ListMentionCandidatesRequestObject{
+		Id:     ch.ID,
+		Params: openapi.ListMentionCandidatesParams{Q: &q},
+	})
+	if r := resp.(openapi.ListMentionCandidates200JSONResponse); len(r.Members) != 1 || r.Members[0].User.UserId != alan.ID {
+		t.Errorf("expected only Alan for %q, got %+v", q, r.Members)
+	}
+
+	// Private channels are closed to non-members
+	private := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	resp, _ = h.ListMentionCandidates(ctxWithUser(t, h, outsider.ID), openapi.ListMentionCandidatesRequestObject{Id: private.ID})
+	if _, ok := resp.(openapi.ListMentionCandidates403JSONResponse); !ok {
+		t.Errorf("expected 403 for a private channel, got %T", resp)
+	}
+}
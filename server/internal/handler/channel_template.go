@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/channeltemplate"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// maxChannelTemplateChannels bounds how many channels one template creates
+const maxChannelTemplateChannels = 50
+
+// errChannelTemplateForbidden is returned by getApplicableTemplate when the
+// caller does not administer the template's workspace
+var errChannelTemplateForbidden = errors.New("cannot use this channel template")
+
+// ListChannelTemplates lists a workspace's channel templates
+func (h *Handler) ListChannelTemplates(ctx context.Context, request openapi.ListChannelTemplatesRequestObject) (openapi.ListChannelTemplatesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListChannelTemplates401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.ListChannelTemplates403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.ListChannelTemplates403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage channel templates")}, nil
+	}
+
+	templates, err := h.channelTemplateRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiTemplates := make([]openapi.ChannelTemplate, len(templates))
+	for i := range templates {
+		apiTemplates[i] = channelTemplateToAPI(&templates[i])
+	}
+	return openapi.ListChannelTemplates200JSONResponse{Templates: apiTemplates}, nil
+}
+
+// CreateChannelTemplate defines a new set of channels
+func (h *Handler) CreateChannelTemplate(ctx context.Context, request openapi.CreateChannelTemplateRequestObject) (openapi.CreateChannelTemplateResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateChannelTemplate401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.CreateChannelTemplate403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.CreateChannelTemplate403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage channel templates")}, nil
+	}
+
+	specs, msg := channelSpecsFromAPI(request.Body.Channels)
+	if msg != "" {
+		return openapi.CreateChannelTemplate400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+	tmpl := &channeltemplate.Template{
+		WorkspaceID: workspaceID,
+		Name:        strings.TrimSpace(request.Body.Name),
+		Description: trimmedOrNil(request.Body.Description),
+		Channels:    specs,
+		CreatedBy:   &userID,
+	}
+	if msg := validateChannelTemplate(tmpl); msg != "" {
+		return openapi.CreateChannelTemplate400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if err := h.channelTemplateRepo.Create(ctx, tmpl); err != nil {
+		if errors.Is(err, channeltemplate.ErrNameExists) {
+			return openapi.CreateChannelTemplate409JSONResponse{ConflictJSONResponse: conflictResponse("A channel template with this name already exists")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "channel_template.created", "channel_template", tmpl.ID, map[string]interface{}{
+		"name": tmpl.Name,
+	})
+
+	return openapi.CreateChannelTemplate200JSONResponse{Template: channelTemplateToAPI(tmpl)}, nil
+}
+
+// UpdateChannelTemplate changes a channel template's name, description or channels
+func (h *Handler) UpdateChannelTemplate(ctx context.Context, request openapi.UpdateChannelTemplateRequestObject) (openapi.UpdateChannelTemplateResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateChannelTemplate401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	tmpl, err := h.channelTemplateRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, channeltemplate.ErrTemplateNotFound) {
+			return openapi.UpdateChannelTemplate404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel template not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, tmpl.WorkspaceID)
+	if err != nil {
+		return openapi.UpdateChannelTemplate404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel template not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.UpdateChannelTemplate403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage channel templates")}, nil
+	}
+
+	if request.Body.Name != nil {
+		tmpl.Name = strings.TrimSpace(*request.Body.Name)
+	}
+	if request.Body.Description != nil {
+		tmpl.Description = trimmedOrNil(request.Body.Description)
+	}
+	if request.Body.Channels != nil {
+		specs, msg := channelSpecsFromAPI(*request.Body.Channels)
+		if msg != "" {
+			return openapi.UpdateChannelTemplate400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+		}
+		tmpl.Channels = specs
+	}
+	if msg := validateChannelTemplate(tmpl); msg != "" {
+		return openapi.UpdateChannelTemplate400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if err := h.channelTemplateRepo.Update(ctx, tmpl); err != nil {
+		if errors.Is(err, channeltemplate.ErrNameExists) {
+			return openapi.UpdateChannelTemplate409JSONResponse{ConflictJSONResponse: conflictResponse("A channel template with this name already exists")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, tmpl.WorkspaceID, userID, "channel_template.updated", "channel_template", tmpl.ID, map[string]interface{}{
+		"name": tmpl.Name,
+	})
+
+	return openapi.UpdateChannelTemplate200JSONResponse{Template: channelTemplateToAPI(tmpl)}, nil
+}
+
+// DeleteChannelTemplate removes a channel template
+func (h *Handler) DeleteChannelTemplate(ctx context.Context, request openapi.DeleteChannelTemplateRequestObject) (openapi.DeleteChannelTemplateResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteChannelTemplate401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	tmpl, err := h.channelTemplateRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, channeltemplate.ErrTemplateNotFound) {
+			return openapi.DeleteChannelTemplate404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel template not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, tmpl.WorkspaceID)
+	if err != nil {
+		return openapi.DeleteChannelTemplate404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel template not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.DeleteChannelTemplate403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage channel templates")}, nil
+	}
+
+	if err := h.channelTemplateRepo.Delete(ctx, tmpl.ID); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, tmpl.WorkspaceID, userID, "channel_template.deleted", "channel_template", tmpl.ID, map[string]interface{}{
+		"name": tmpl.Name,
+	})
+
+	return openapi.DeleteChannelTemplate200JSONResponse{Success: true}, nil
+}
+
+// ApplyChannelTemplate creates a template's channels in a workspace
+func (h *Handler) ApplyChannelTemplate(ctx context.Context, request openapi.ApplyChannelTemplateRequestObject) (openapi.ApplyChannelTemplateResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ApplyChannelTemplate401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.ApplyChannelTemplate403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.ApplyChannelTemplate403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can apply channel templates")}, nil
+	}
+
+	tmpl, err := h.getApplicableTemplate(ctx, request.Body.TemplateId, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, channeltemplate.ErrTemplateNotFound):
+			return openapi.ApplyChannelTemplate404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel template not found")}, nil
+		case errors.Is(err, errChannelTemplateForbidden):
+			return openapi.ApplyChannelTemplate403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot use this channel template")}, nil
+		}
+		return nil, err
+	}
+
+	result, err := h.applyChannelTemplate(ctx, workspaceID, userID, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.ApplyChannelTemplate200JSONResponse(result), nil
+}
+
+// getApplicableTemplate loads a template the user may apply, which requires
+// being an admin of the workspace it belongs to
+func (h *Handler) getApplicableTemplate(ctx context.Context, templateID, userID string) (*channeltemplate.Template, error) {
+	tmpl, err := h.channelTemplateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, tmpl.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return nil, channeltemplate.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return nil, errChannelTemplateForbidden
+	}
+	return tmpl, nil
+}
+
+// applyChannelTemplate creates the template's channels in a workspace and
+// announces each new channel
+func (h *Handler) applyChannelTemplate(ctx context.Context, workspaceID, userID string, tmpl *channeltemplate.Template) (openapi.ApplyChannelTemplateResult, error) {
+	created, skipped, err := h.channelRepo.CreateFromSpecs(ctx, workspaceID, userID, tmpl.Channels)
+	if err != nil {
+		return openapi.ApplyChannelTemplateResult{}, err
+	}
+
+	result := openapi.ApplyChannelTemplateResult{
+		Channels: make([]openapi.Channel, len(created)),
+		Skipped:  []string{},
+	}
+	if skipped != nil {
+		result.Skipped = skipped
+	}
+	for i, c := range created {
+		apiCh := channelToAPI(c.Channel)
+		result.Channels[i] = apiCh
+		if h.hub == nil {
+			continue
+		}
+		for _, memberID := range c.MemberIDs {
+			h.hub.AddChannelMember(c.Channel.ID, memberID)
+		}
+		if c.Channel.Type == channel.TypePrivate {
+			h.hub.BroadcastToChannel(ctx, workspaceID, c.Channel.ID, sse.NewChannelCreatedEvent(apiCh))
+		} else {
+			h.hub.BroadcastToWorkspace(ctx, workspaceID, sse.NewChannelCreatedEvent(apiCh))
+		}
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "channel_template.applied", "channel_template", tmpl.ID, map[string]interface{}{
+		"name":    tmpl.Name,
+		"created": len(created),
+		"skipped": len(skipped),
+	})
+
+	return result, nil
+}
+
+// channelSpecsFromAPI validates a template's channel list, returning a
+// message describing the first problem
+func channelSpecsFromAPI(channels []openapi.ChannelTemplateChannel) ([]channel.Spec, string) {
+	if len(channels) == 0 {
+		return nil, "A template needs at least one channel"
+	}
+	if len(channels) > maxChannelTemplateChannels {
+		return nil, fmt.Sprintf("A template can have at most %d channels", maxChannelTemplateChannels)
+	}
+
+	specs := make([]channel.Spec, 0, len(channels))
+	seen := make(map[string]bool, len(channels))
+	for _, c := range channels {
+		name := strings.TrimSpace(c.Name)
+		if !validChannelName.MatchString(name) {
+			return nil, fmt.Sprintf("Invalid channel name %q: use only lowercase letters, numbers, and dashes", name)
+		}
+		if seen[name] {
+			return nil, fmt.Sprintf("Channel %q appears more than once", name)
+		}
+		seen[name] = true
+
+		channelType := string(c.Type)
+		if channelType != channel.TypePublic && channelType != channel.TypePrivate {
+			return nil, fmt.Sprintf("Channel %q must be public or private", name)
+		}
+
+		spec := channel.Spec{
+			Name:        name,
+			Type:        channelType,
+			Description: trimmedOrNil(c.Description),
+		}
+		if c.MemberRoles != nil {
+			for _, role := range *c.MemberRoles {
+				switch string(role) {
+				case workspace.RoleOwner, workspace.RoleAdmin, workspace.RoleMember, workspace.RoleGuest:
+				default:
+					return nil, fmt.Sprintf("Channel %q has an invalid member role", name)
+				}
+				if !slices.Contains(spec.MemberRoles, string(role)) {
+					spec.MemberRoles = append(spec.MemberRoles, string(role))
+				}
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, ""
+}
+
+func validateChannelTemplate(t *channeltemplate.Template) string {
+	if t.Name == "" {
+		return "Name is required"
+	}
+	if len(t.Name) > 80 {
+		return "Name must be 80 characters or fewer"
+	}
+	if t.Description != nil && len(*t.Description) > 250 {
+		return "Description must be 250 characters or fewer"
+	}
+	return ""
+}
+
+func channelTemplateToAPI(t *channeltemplate.Template) openapi.ChannelTemplate {
+	channels := make([]openapi.ChannelTemplateChannel, len(t.Channels))
+	for i, c := range t.Channels {
+		channels[i] = openapi.ChannelTemplateChannel{
+			Name:        c.Name,
+			Type:        openapi.ChannelType(c.Type),
+			Description: c.Description,
+			MemberRoles: postRolesToAPI(c.MemberRoles),
+		}
+	}
+	return openapi.ChannelTemplate{
+		Id:          t.ID,
+		WorkspaceId: t.WorkspaceID,
+		Name:        t.Name,
+		Description: t.Description,
+		Channels:    channels,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestChannelTemplates_CreateAndApply(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "random", channel.TypePublic)
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	memberRoles := []openapi.WorkspaceRole{openapi.WorkspaceRoleMember}
+	channels := []openapi.ChannelTemplateChannel{
+		{Name: "announcements", Type: openapi.ChannelTypePublic, MemberRoles: &memberRoles},
+		{Name: "random", Type: openapi.ChannelTypePublic},
+		{Name: "leads", Type: openapi.ChannelTypePrivate},
+	}
+
+	// Members cannot define templates
+	resp, err := h.CreateChannelTemplate(ctxWithUser(t, h, member.ID), openapi.CreateChannelTemplateRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateChannelTemplateJSONRequestBody{Name: "Onboarding", Channels: channels},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateChannelTemplate403JSONResponse); !ok {
+		t.Errorf("member create: expected 403 response, got %T", resp)
+	}
+
+	for _, bad := range [][]openapi.ChannelTemplateChannel{
+		nil,
+		{{Name: "Bad Name", Type: openapi.ChannelTypePublic}},
+		{{Name: "dm", Type: openapi.ChannelTypeDm}},
+		{{Name: "twice", Type: openapi.ChannelTypePublic}, {Name: "twice", Type: openapi.ChannelTypePrivate}},
+	} {
+		resp, err = h.CreateChannelTemplate(ctx, openapi.CreateChannelTemplateRequestObject{
+			Wid:  ws.ID,
+			Body: &openapi.CreateChannelTemplateJSONRequestBody{Name: "Bad", Channels: bad},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(openapi.CreateChannelTemplate400JSONResponse); !ok {
+			t.Errorf("channels %+v: expected 400 response, got %T", bad, resp)
+		}
+	}
+
+	resp, err = h.CreateChannelTemplate(ctx, openapi.CreateChannelTemplateRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateChannelTemplateJSONRequestBody{Name: "Onboarding", Channels: channels},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, ok := resp.(openapi.CreateChannelTemplate200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	client := connectSSEClient(t, h, ws.ID, member.ID)
+	applyResp, err := h.ApplyChannelTemplate(ctx, openapi.ApplyChannelTemplateRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.ApplyChannelTemplateJSONRequestBody{TemplateId: created.Template.Id},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	applied, ok := applyResp.(openapi.ApplyChannelTemplate200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", applyResp)
+	}
+	if len(applied.Channels) != 2 || len(applied.Skipped) != 1 || applied.Skipped[0] != "random" {
+		t.Fatalf("applied = %+v, want two new channels and random skipped", applied)
+	}
+	expectSSEEvent(t, client, sse.EventChannelCreated)
+
+	if _, err := h.channelRepo.GetMembership(t.Context(), member.ID, applied.Channels[0].Id); err != nil {
+		t.Errorf("member should have joined announcements: %v", err)
+	}
+	if _, err := h.channelRepo.GetMembership(t.Context(), member.ID, applied.Channels[1].Id); err == nil {
+		t.Error("member should not have joined leads")
+	}
+
+	// Applying again creates nothing new
+	applyResp, err = h.ApplyChannelTemplate(ctx, openapi.ApplyChannelTemplateRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.ApplyChannelTemplateJSONRequestBody{TemplateId: created.Template.Id},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := applyResp.(openapi.ApplyChannelTemplate200JSONResponse); len(r.Channels) != 0 || len(r.Skipped) != 3 {
+		t.Errorf("second apply = %+v, want everything skipped", r)
+	}
+}
+
+func TestCreateWorkspace_WithChannelTemplate(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	resp, err := h.CreateChannelTemplate(ctxWithUser(t, h, owner.ID), openapi.CreateChannelTemplateRequestObject{
+		Wid: ws.ID,
+		Body: &openapi.CreateChannelTemplateJSONRequestBody{Name: "Starter", Channels: []openapi.ChannelTemplateChannel{
+			{Name: "general", Type: openapi.ChannelTypePublic},
+			{Name: "help", Type: openapi.ChannelTypePublic},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	templateID := resp.(openapi.CreateChannelTemplate200JSONResponse).Template.Id
+
+	// Only admins of the template's workspace can reuse it
+	wsResp, err := h.CreateWorkspace(ctxWithUser(t, h, member.ID), openapi.CreateWorkspaceRequestObject{
+		Body: &openapi.CreateWorkspaceJSONRequestBody{Name: "Member WS", TemplateId: &templateID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := wsResp.(openapi.CreateWorkspace403JSONResponse); !ok {
+		t.Errorf("member: expected 403 response, got %T", wsResp)
+	}
+
+	wsResp, err = h.CreateWorkspace(ctxWithUser(t, h, owner.ID), openapi.CreateWorkspaceRequestObject{
+		Body: &openapi.CreateWorkspaceJSONRequestBody{Name: "New WS", TemplateId: &templateID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newWS, ok := wsResp.(openapi.CreateWorkspace200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", wsResp)
+	}
+	if _, err := h.channelRepo.GetByWorkspaceAndName(t.Context(), newWS.Workspace.Id, "help"); err != nil {
+		t.Errorf("template channel should exist in the new workspace: %v", err)
+	}
+}
@@ -2,11 +2,20 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
 	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/usergroup"
 )
 
 func TestCreateChannel_Success(t *testing.T) {
@@ -65,11 +74,12 @@ func TestCreateChannel_InvalidName(t *testing.T) {
 	tests := []struct {
 		name     string
 		chanName string
+		code     string
 	}{
-		{"empty", ""},
-		{"has spaces", "has spaces"},
-		{"uppercase", "UPPER"},
-		{"special chars", "chan!@#"},
+		{"empty", "", ErrCodeRequired},
+		{"has spaces", "has spaces", ErrCodeInvalidChannelName},
+		{"uppercase", "UPPER", ErrCodeInvalidChannelName},
+		{"special chars", "chan!@#", ErrCodeInvalidChannelName},
 	}
 
 	for _, tt := range tests {
@@ -84,13 +94,57 @@ func TestCreateChannel_InvalidName(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if _, ok := resp.(openapi.CreateChannel400JSONResponse); !ok {
+			r, ok := resp.(openapi.CreateChannel400JSONResponse)
+			if !ok {
 				t.Fatalf("expected 400 response for name %q, got %T", tt.chanName, resp)
 			}
+			if r.Error.Code != tt.code || r.Error.Params == nil || (*r.Error.Params)["field"] != "name" {
+				t.Errorf("expected %s for field name, got %+v", tt.code, r.Error)
+			}
 		})
 	}
 }
 
+func TestCreateChannel_NameTaken(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+
+	ctx := ctxWithUser(t, h, user.ID)
+	resp, err := h.CreateChannel(ctx, openapi.CreateChannelRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateChannelJSONRequestBody{Name: "general", Type: openapi.ChannelType("private")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.CreateChannel400JSONResponse)
+	if !ok || r.Error.Code != ErrCodeChannelNameTaken {
+		t.Fatalf("expected 400 %s, got %+v", ErrCodeChannelNameTaken, resp)
+	}
+}
+
+func TestCreateChannel_NotWorkspaceMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+	resp, err := h.CreateChannel(ctxWithUser(t, h, outsider.ID), openapi.CreateChannelRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateChannelJSONRequestBody{Name: "new-ch", Type: openapi.ChannelType("public")},
+	})
+	if err != nil {
+		t.Fatalf("expected a 403 response rather than an error, got %v", err)
+	}
+	if r, ok := resp.(openapi.CreateChannel403JSONResponse); !ok || r.Error.Code != ErrCodeNotAMember {
+		t.Fatalf("expected 403 %s, got %+v", ErrCodeNotAMember, resp)
+	}
+}
+
 func TestCreateChannel_GuestCannotCreate(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -285,6 +339,157 @@ func TestUpdateChannel_InvalidType(t *testing.T) {
 	}
 }
 
+func TestUpdateChannel_PostPolicy(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "announcements", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Launch is on Friday")
+
+	client := connectSSEClient(t, h, ws.ID, member.ID)
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	update := func(body openapi.UpdateChannelJSONRequestBody) openapi.UpdateChannelResponseObject {
+		t.Helper()
+		resp, err := h.UpdateChannel(ownerCtx, openapi.UpdateChannelRequestObject{Id: ch.ID, Body: &body})
+		if err != nil {
+			t.Fatalf("UpdateChannel: %v", err)
+		}
+		return resp
+	}
+	send := func(userID string, threadParentID *string) openapi.SendMessageResponseObject {
+		t.Helper()
+		content := "hello"
+		resp, err := h.SendMessage(ctxWithUser(t, h, userID), openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content, ThreadParentId: threadParentID},
+		})
+		if err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		return resp
+	}
+
+	adminsOnly := openapi.ChannelPostAdmins
+	r, ok := update(openapi.UpdateChannelJSONRequestBody{PostPolicy: &adminsOnly}).(openapi.UpdateChannel200JSONResponse)
+	if !ok || r.Channel.PostPolicy != openapi.ChannelPostAdmins {
+		t.Fatalf("update = %#v, want admins-only post policy", r)
+	}
+	expectSSEEvent(t, client, sse.EventChannelUpdated)
+
+	if _, ok := send(member.ID, nil).(openapi.SendMessage403JSONResponse); !ok {
+		t.Error("member posting in an announcement channel should be forbidden")
+	}
+	if _, ok := send(member.ID, &parent.ID).(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("member should still be able to reply in threads")
+	}
+	if _, ok := send(owner.ID, nil).(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("owner should be able to post in an announcement channel")
+	}
+
+	restrict := true
+	update(openapi.UpdateChannelJSONRequestBody{RestrictThreadReplies: &restrict})
+	if _, ok := send(member.ID, &parent.ID).(openapi.SendMessage403JSONResponse); !ok {
+		t.Error("member replies should be forbidden once thread replies are restricted")
+	}
+
+	byRole := openapi.ChannelPostRoles
+	roles := []openapi.WorkspaceRole{openapi.WorkspaceRoleMember}
+	update(openapi.UpdateChannelJSONRequestBody{PostPolicy: &byRole, PostRoles: &roles})
+	if _, ok := send(member.ID, nil).(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("members should be able to post when their role is allowed")
+	}
+
+	invalid := []openapi.WorkspaceRole{"superuser"}
+	if _, ok := update(openapi.UpdateChannelJSONRequestBody{PostRoles: &invalid}).(openapi.UpdateChannel400JSONResponse); !ok {
+		t.Error("unknown post roles should be rejected")
+	}
+}
+
+func TestUpdateChannel_SlowMode(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	lead := testutil.CreateTestUser(t, db, "lead@test.com", "Lead")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, lead.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+	leadRole := channel.ChannelRoleAdmin
+	addChannelMember(t, db, lead.ID, ch.ID, &leadRole)
+
+	update := func(userID string, seconds int) openapi.UpdateChannelResponseObject {
+		t.Helper()
+		resp, err := h.UpdateChannel(ctxWithUser(t, h, userID), openapi.UpdateChannelRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.UpdateChannelJSONRequestBody{SlowModeSeconds: &seconds},
+		})
+		if err != nil {
+			t.Fatalf("UpdateChannel: %v", err)
+		}
+		return resp
+	}
+	send := func(userID string) openapi.SendMessageResponseObject {
+		t.Helper()
+		content := "hello"
+		resp, err := h.SendMessage(ctxWithUser(t, h, userID), openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+		})
+		if err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := update(member.ID, 30).(openapi.UpdateChannel403JSONResponse); !ok {
+		t.Error("members should not be able to enable slow mode")
+	}
+	if _, ok := update(owner.ID, channel.MaxSlowModeSeconds+1).(openapi.UpdateChannel400JSONResponse); !ok {
+		t.Error("intervals above the maximum should be rejected")
+	}
+	r, ok := update(lead.ID, 30).(openapi.UpdateChannel200JSONResponse)
+	if !ok || r.Channel.SlowModeSeconds != 30 {
+		t.Fatalf("update = %#v, want slow mode of 30 seconds", r)
+	}
+
+	if _, ok := send(member.ID).(openapi.SendMessage200JSONResponse); !ok {
+		t.Fatal("first message should be allowed")
+	}
+	limited, ok := send(member.ID).(openapi.SendMessage429JSONResponse)
+	if !ok {
+		t.Fatal("second message within the interval should be rate limited")
+	}
+	if limited.Body.Error.Code != ErrCodeSlowMode || limited.Body.RetryAfter < 1 || limited.Body.RetryAfter > 30 || limited.Headers.RetryAfter != limited.Body.RetryAfter {
+		t.Errorf("429 = %+v, want SLOW_MODE with retry_after in (0, 30]", limited)
+	}
+
+	for _, userID := range []string{owner.ID, owner.ID, lead.ID, lead.ID} {
+		if _, ok := send(userID).(openapi.SendMessage200JSONResponse); !ok {
+			t.Error("channel and workspace admins should be exempt from slow mode")
+		}
+	}
+
+	// Once the interval has passed the member may post again
+	past := time.Now().Add(-31 * time.Second).UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`UPDATE messages SET created_at = ? WHERE user_id = ?`, past, member.ID); err != nil {
+		t.Fatalf("backdate messages: %v", err)
+	}
+	if _, ok := send(member.ID).(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("member should be able to post after the interval")
+	}
+
+	update(owner.ID, 0)
+	if _, ok := send(member.ID).(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("turning slow mode off should lift the limit")
+	}
+}
+
 func TestArchiveChannel_Success(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -349,6 +554,144 @@ func TestArchiveChannel_CannotArchiveDefault(t *testing.T) {
 	}
 }
 
+func TestUnarchiveChannel(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "old-project", channel.TypePublic)
+
+	client := connectSSEClient(t, h, ws.ID, member.ID)
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	if _, err := h.ArchiveChannel(ownerCtx, openapi.ArchiveChannelRequestObject{Id: ch.ID}); err != nil {
+		t.Fatalf("ArchiveChannel: %v", err)
+	}
+	expectSSEEvent(t, client, sse.EventChannelArchived)
+
+	// Only admins can browse archived channels
+	listResp, err := h.ListArchivedChannels(ctxWithUser(t, h, member.ID), openapi.ListArchivedChannelsRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListArchivedChannels: %v", err)
+	}
+	if _, ok := listResp.(openapi.ListArchivedChannels403JSONResponse); !ok {
+		t.Errorf("member list: expected 403 response, got %T", listResp)
+	}
+	listResp, err = h.ListArchivedChannels(ownerCtx, openapi.ListArchivedChannelsRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListArchivedChannels: %v", err)
+	}
+	if r, ok := listResp.(openapi.ListArchivedChannels200JSONResponse); !ok || len(r.Channels) != 1 || r.Channels[0].Id != ch.ID {
+		t.Fatalf("archived = %#v, want the archived channel", listResp)
+	}
+
+	resp, err := h.UnarchiveChannel(ctxWithUser(t, h, member.ID), openapi.UnarchiveChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("UnarchiveChannel: %v", err)
+	}
+	if _, ok := resp.(openapi.UnarchiveChannel403JSONResponse); !ok {
+		t.Errorf("member unarchive: expected 403 response, got %T", resp)
+	}
+
+	resp, err = h.UnarchiveChannel(ownerCtx, openapi.UnarchiveChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("UnarchiveChannel: %v", err)
+	}
+	r, ok := resp.(openapi.UnarchiveChannel200JSONResponse)
+	if !ok || r.Channel.ArchivedAt != nil {
+		t.Fatalf("unarchive = %#v, want an active channel", resp)
+	}
+	expectSSEEvent(t, client, sse.EventChannelUnarchived)
+
+	resp, err = h.UnarchiveChannel(ownerCtx, openapi.UnarchiveChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("UnarchiveChannel: %v", err)
+	}
+	if _, ok := resp.(openapi.UnarchiveChannel400JSONResponse); !ok {
+		t.Errorf("second unarchive: expected 400 response, got %T", resp)
+	}
+
+	msgResp, err := h.ListMessages(ownerCtx, openapi.ListMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("ListMessages: %v", err)
+	}
+	var events []openapi.SystemEventType
+	for _, m := range msgResp.(openapi.ListMessages200JSONResponse).Messages {
+		if m.SystemEvent != nil {
+			events = append(events, m.SystemEvent.EventType)
+		}
+	}
+	if !slices.Contains(events, openapi.SystemEventTypeChannelArchived) || !slices.Contains(events, openapi.SystemEventTypeChannelUnarchived) {
+		t.Errorf("system events = %v, want archive and unarchive messages", events)
+	}
+}
+
+func TestBrowseChannels(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	alpha := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "alpha", channel.TypePublic)
+	beta := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "beta", channel.TypePublic)
+	testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	archived := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "old", channel.TypePublic)
+	if _, err := db.Exec(`UPDATE channels SET archived_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), archived.ID); err != nil {
+		t.Fatalf("archiving channel: %v", err)
+	}
+	addChannelMember(t, db, member.ID, beta.ID, nil)
+	testutil.CreateTestMessage(t, db, beta.ID, owner.ID, "hello\n   there "+strings.Repeat("x", 200))
+
+	browse := func(userID string, params openapi.BrowseChannelsParams) openapi.BrowseChannelsResponseObject {
+		t.Helper()
+		resp, err := h.BrowseChannels(ctxWithUser(t, h, userID), openapi.BrowseChannelsRequestObject{Wid: ws.ID, Params: params})
+		if err != nil {
+			t.Fatalf("BrowseChannels: %v", err)
+		}
+		return resp
+	}
+
+	resp := browse(outsider.ID, openapi.BrowseChannelsParams{})
+	if _, ok := resp.(openapi.BrowseChannels403JSONResponse); !ok {
+		t.Errorf("outsider: expected 403 response, got %T", resp)
+	}
+
+	sort := openapi.ChannelDirectorySortMembers
+	r, ok := browse(member.ID, openapi.BrowseChannelsParams{Sort: &sort}).(openapi.BrowseChannels200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 response")
+	}
+	if r.TotalCount != 2 || len(r.Channels) != 2 || r.HasMore {
+		t.Fatalf("got %d of %d channels (has_more=%v), want alpha and beta only", len(r.Channels), r.TotalCount, r.HasMore)
+	}
+	top := r.Channels[0]
+	if top.Id != beta.ID || top.MemberCount != 2 || !top.IsMember {
+		t.Errorf("first channel = %s with %d members (is_member=%v), want beta with 2", top.Name, top.MemberCount, top.IsMember)
+	}
+	if top.LastActivityAt == nil || top.LastMessagePreview == nil || !strings.HasPrefix(*top.LastMessagePreview, "hello there x") || !strings.HasSuffix(*top.LastMessagePreview, "…") {
+		t.Errorf("preview = %v, want a truncated, whitespace-collapsed preview", top.LastMessagePreview)
+	}
+	if bottom := r.Channels[1]; bottom.Id != alpha.ID || bottom.IsMember || bottom.LastActivityAt != nil {
+		t.Errorf("second channel = %+v, want unjoined, empty alpha", bottom)
+	}
+
+	limit := 1
+	sort = openapi.ChannelDirectorySortName
+	r = browse(member.ID, openapi.BrowseChannelsParams{Sort: &sort, Limit: &limit}).(openapi.BrowseChannels200JSONResponse)
+	if len(r.Channels) != 1 || r.Channels[0].Id != alpha.ID || !r.HasMore {
+		t.Errorf("first page = %+v, want alpha with more to come", r)
+	}
+
+	q := "#BET"
+	r = browse(member.ID, openapi.BrowseChannelsParams{Q: &q}).(openapi.BrowseChannels200JSONResponse)
+	if r.TotalCount != 1 || r.Channels[0].Id != beta.ID {
+		t.Errorf("search = %+v, want only beta", r)
+	}
+}
 func TestJoinChannel_Public(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -371,6 +714,47 @@ func TestJoinChannel_Public(t *testing.T) {
 	}
 }
 
+func TestJoinChannel_KeepsExistingRole(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "public-ch", channel.TypePublic)
+
+	resp, err := h.JoinChannel(ctxWithUser(t, h, owner.ID), openapi.JoinChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.JoinChannel200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	membership, err := h.channelRepo.GetMembership(context.Background(), owner.ID, ch.ID)
+	if err != nil {
+		t.Fatalf("GetMembership: %v", err)
+	}
+	if membership.ChannelRole == nil || *membership.ChannelRole != channel.ChannelRoleAdmin {
+		t.Errorf("channel role = %v, want the creator to stay admin", membership.ChannelRole)
+	}
+}
+
+func TestJoinChannel_BroadcastsMemberAdded(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	joiner := testutil.CreateTestUser(t, db, "joiner@test.com", "Joiner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "public-ch", channel.TypePublic)
+	addWorkspaceMember(t, db, joiner.ID, ws.ID, "member")
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+
+	if _, err := h.JoinChannel(ctxWithUser(t, h, joiner.ID), openapi.JoinChannelRequestObject{Id: ch.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectSSEEvent(t, client, sse.EventMemberAdded)
+}
+
 func TestJoinChannel_Private(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -442,6 +826,87 @@ func TestAddChannelMember_Success(t *testing.T) {
 	}
 }
 
+func TestBulkAddChannelMembers(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "team", channel.TypePrivate)
+
+	var memberIDs []string
+	for i := 0; i < 4; i++ {
+		u := testutil.CreateTestUser(t, db, fmt.Sprintf("member%d@test.com", i), fmt.Sprintf("Member %d", i))
+		addWorkspaceMember(t, db, u.ID, ws.ID, "member")
+		memberIDs = append(memberIDs, u.ID)
+	}
+	addChannelMember(t, db, memberIDs[0], ch.ID, nil)
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	group := &usergroup.Group{WorkspaceID: ws.ID, Handle: "backend", Name: "Backend"}
+	if err := h.userGroupRepo.Create(context.Background(), group, memberIDs[2:]); err != nil {
+		t.Fatalf("creating group: %v", err)
+	}
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+
+	bulkAdd := func(body openapi.BulkAddChannelMembersJSONRequestBody) openapi.BulkAddChannelMembersResponseObject {
+		t.Helper()
+		resp, err := h.BulkAddChannelMembers(ctx, openapi.BulkAddChannelMembersRequestObject{Id: ch.ID, Body: &body})
+		if err != nil {
+			t.Fatalf("BulkAddChannelMembers: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := bulkAdd(openapi.BulkAddChannelMembersJSONRequestBody{}).(openapi.BulkAddChannelMembers400JSONResponse); !ok {
+		t.Fatal("expected 400 when no users are given")
+	}
+	missing := "missing"
+	if _, ok := bulkAdd(openapi.BulkAddChannelMembersJSONRequestBody{UserGroupId: &missing}).(openapi.BulkAddChannelMembers404JSONResponse); !ok {
+		t.Fatal("expected 404 for an unknown user group")
+	}
+
+	// The existing member and the user outside the workspace are skipped
+	userIDs := []string{memberIDs[0], memberIDs[1], outsider.ID}
+	resp, ok := bulkAdd(openapi.BulkAddChannelMembersJSONRequestBody{UserIds: &userIDs, UserGroupId: &group.ID}).(openapi.BulkAddChannelMembers200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200")
+	}
+	want := memberIDs[1:]
+	got := slices.Clone(resp.AddedUserIds)
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("added = %v, want %v", got, want)
+	}
+	expectSSEEvent(t, client, sse.EventMembersUpdated)
+
+	for _, id := range want {
+		if _, err := h.channelRepo.GetMembership(context.Background(), id, ch.ID); err != nil {
+			t.Errorf("user %s should be a channel member: %v", id, err)
+		}
+	}
+	if _, err := h.channelRepo.GetMembership(context.Background(), outsider.ID, ch.ID); err == nil {
+		t.Error("users outside the workspace should not be added")
+	}
+
+	var count int
+	var content, event string
+	if err := db.QueryRow(`SELECT COUNT(*), MAX(content), MAX(system_event) FROM messages WHERE channel_id = ? AND type = 'system'`, ch.ID).Scan(&count, &content, &event); err != nil {
+		t.Fatalf("querying system messages: %v", err)
+	}
+	if text := message.SystemText(i18n.Default, content, event); count != 1 || text != "added 3 people to #team" {
+		t.Errorf("system messages = %d (%q), want one summary", count, text)
+	}
+
+	// Repeating the request adds nobody
+	resp, ok = bulkAdd(openapi.BulkAddChannelMembersJSONRequestBody{UserGroupId: &group.ID}).(openapi.BulkAddChannelMembers200JSONResponse)
+	if !ok || len(resp.AddedUserIds) != 0 {
+		t.Errorf("expected nobody to be added again, got %+v", resp)
+	}
+}
+
 func TestListChannelMembers_PrivateNonMember(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -463,3 +928,524 @@ func TestListChannelMembers_PrivateNonMember(t *testing.T) {
 		t.Fatalf("expected 404 response, got %T", resp)
 	}
 }
+
+func TestStarChannel_NotifiesOtherSessions(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "starred", channel.TypePublic)
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+	ctx := ctxWithUser(t, h, owner.ID)
+
+	starResp, err := h.StarChannel(ctx, openapi.StarChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := starResp.(openapi.StarChannel200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", starResp)
+	}
+	expectSSEEvent(t, client, sse.EventChannelStarred)
+
+	unstarResp, err := h.UnstarChannel(ctx, openapi.UnstarChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := unstarResp.(openapi.UnstarChannel200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", unstarResp)
+	}
+	expectSSEEvent(t, client, sse.EventChannelUnstarred)
+}
+
+func TestStarChannel_NotMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+
+	ctx := ctxWithUser(t, h, other.ID)
+	resp, err := h.StarChannel(ctx, openapi.StarChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.StarChannel404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestGetUnreadCounts(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test Workspace")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	general := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	addChannelMember(t, db, member.ID, general.ID, nil)
+	testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "not-joined", "public")
+
+	testutil.CreateTestMessage(t, db, general.ID, owner.ID, "one")
+	last := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "two")
+
+	ctx := ctxWithUser(t, h, member.ID)
+	get := func(ifNoneMatch *string) openapi.GetUnreadCountsResponseObject {
+		t.Helper()
+		resp, err := h.GetUnreadCounts(ctx, openapi.GetUnreadCountsRequestObject{
+			Wid:    ws.ID,
+			Params: openapi.GetUnreadCountsParams{IfNoneMatch: ifNoneMatch},
+		})
+		if err != nil {
+			t.Fatalf("GetUnreadCounts: %v", err)
+		}
+		return resp
+	}
+
+	first, ok := get(nil).(openapi.GetUnreadCounts200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 response")
+	}
+	if len(first.Body.Channels) != 1 || first.Body.Channels[general.ID].UnreadCount != 2 {
+		t.Fatalf("expected 2 unread in the joined channel only, got %+v", first.Body.Channels)
+	}
+	etag := first.Headers.ETag
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	if _, ok := get(&etag).(openapi.GetUnreadCounts304Response); !ok {
+		t.Fatal("expected 304 for an unchanged ETag")
+	}
+
+	// Reading the channel changes the counts and the ETag
+	if _, err := db.Exec("UPDATE channel_memberships SET last_read_message_id = ? WHERE user_id = ? AND channel_id = ?", last.ID, member.ID, general.ID); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	second, ok := get(&etag).(openapi.GetUnreadCounts200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 after the read position changed")
+	}
+	if second.Headers.ETag == etag || second.Body.Channels[general.ID].UnreadCount != 0 {
+		t.Errorf("expected a new ETag and 0 unread, got %q %+v", second.Headers.ETag, second.Body.Channels)
+	}
+
+	// A new workspace event changes the ETag too
+	etag = second.Headers.ETag
+	if _, err := db.Exec("INSERT INTO workspace_events (id, workspace_id, event_type, payload, created_at) VALUES ('evt-1', ?, 'message.new', '{}', '2026-01-01T00:00:00Z')", ws.ID); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if _, ok := get(&etag).(openapi.GetUnreadCounts200JSONResponse); !ok {
+		t.Error("expected 200 after a new workspace event")
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestListChannels_ETag(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test Workspace")
+	testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	list := func(ifNoneMatch *string) openapi.ListChannelsResponseObject {
+		t.Helper()
+		resp, err := h.ListChannels(ctx, openapi.ListChannelsRequestObject{
+			Wid:    ws.ID,
+			Params: openapi.ListChannelsParams{IfNoneMatch: ifNoneMatch},
+		})
+		if err != nil {
+			t.Fatalf("ListChannels: %v", err)
+		}
+		return resp
+	}
+
+	first, ok := list(nil).(openapi.ListChannels200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 response")
+	}
+	etag := first.Headers.ETag
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+	if _, ok := list(&etag).(openapi.ListChannels304Response); !ok {
+		t.Fatal("expected 304 for an unchanged ETag")
+	}
+
+	// A new channel changes the ETag
+	testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "random", "public")
+	second, ok := list(&etag).(openapi.ListChannels200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 after a channel was created")
+	}
+	if second.Headers.ETag == etag || len(second.Body.Channels) != 2 {
+		t.Errorf("expected a new ETag and 2 channels, got %q %d", second.Headers.ETag, len(second.Body.Channels))
+	}
+}
+
+func TestListChannelMembers_ETag(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test Workspace")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	list := func(ifNoneMatch *string) openapi.ListChannelMembersResponseObject {
+		t.Helper()
+		resp, err := h.ListChannelMembers(ctx, openapi.ListChannelMembersRequestObject{
+			Id:     ch.ID,
+			Params: openapi.ListChannelMembersParams{IfNoneMatch: ifNoneMatch},
+		})
+		if err != nil {
+			t.Fatalf("ListChannelMembers: %v", err)
+		}
+		return resp
+	}
+
+	first, ok := list(nil).(openapi.ListChannelMembers200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 response")
+	}
+	etag := first.Headers.ETag
+	if _, ok := list(&etag).(openapi.ListChannelMembers304Response); !ok {
+		t.Fatal("expected 304 for an unchanged ETag")
+	}
+
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+	second, ok := list(&etag).(openapi.ListChannelMembers200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 after a member joined")
+	}
+	if second.Headers.ETag == etag || len(second.Body.Members) != len(first.Body.Members)+1 {
+		t.Errorf("expected a new ETag and one more member, got %q %d", second.Headers.ETag, len(second.Body.Members))
+	}
+}
+
+func TestSetChannelTopic(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, outsider.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+	setTopic := func(userID, topic string) openapi.SetChannelTopicResponseObject {
+		t.Helper()
+		resp, err := h.SetChannelTopic(ctxWithUser(t, h, userID), openapi.SetChannelTopicRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SetChannelTopicJSONRequestBody{Topic: topic},
+		})
+		if err != nil {
+			t.Fatalf("SetChannelTopic: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := setTopic(outsider.ID, "Hijacked").(openapi.SetChannelTopic403JSONResponse); !ok {
+		t.Error("non-members should not be able to set the topic")
+	}
+	if _, ok := setTopic(member.ID, strings.Repeat("x", maxChannelTopicLength+1)).(openapi.SetChannelTopic400JSONResponse); !ok {
+		t.Error("over-long topic should be rejected")
+	}
+
+	r, ok := setTopic(member.ID, "  Release   week ").(openapi.SetChannelTopic200JSONResponse)
+	if !ok || r.Channel.Topic == nil || *r.Channel.Topic != "Release week" {
+		t.Fatalf("set topic = %#v, want topic \"Release week\"", r)
+	}
+	expectSSEEvent(t, client, sse.EventChannelUpdated)
+	expectSSEEvent(t, client, sse.EventMessageNew)
+
+	listResp, err := h.ListChannels(ctxWithUser(t, h, member.ID), openapi.ListChannelsRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListChannels: %v", err)
+	}
+	for _, c := range listResp.(openapi.ListChannels200JSONResponse).Body.Channels {
+		if c.Id == ch.ID && (c.Topic == nil || *c.Topic != "Release week") {
+			t.Errorf("listed topic = %v, want Release week", c.Topic)
+		}
+	}
+
+	msgResp, err := h.ListMessages(ctxWithUser(t, h, member.ID), openapi.ListMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("ListMessages: %v", err)
+	}
+	found := false
+	for _, m := range msgResp.(openapi.ListMessages200JSONResponse).Messages {
+		if m.SystemEvent != nil && m.SystemEvent.EventType == openapi.SystemEventTypeChannelTopicChanged {
+			found = true
+			if m.SystemEvent.Topic == nil || *m.SystemEvent.Topic != "Release week" {
+				t.Errorf("system event topic = %v, want Release week", m.SystemEvent.Topic)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a channel_topic_changed system message")
+	}
+
+	r, ok = setTopic(member.ID, "").(openapi.SetChannelTopic200JSONResponse)
+	if !ok || r.Channel.Topic != nil {
+		t.Errorf("cleared topic = %#v, want nil", r.Channel.Topic)
+	}
+}
+
+func TestUpdateChannel_AutoJoinBackfill(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, member.ID, "onboarding", channel.TypePublic)
+	lead := testutil.CreateTestUser(t, db, "lead@test.com", "Lead")
+	addWorkspaceMember(t, db, lead.ID, ws.ID, "member")
+
+	autoJoin := true
+	// Channel admins who are not workspace admins cannot change auto-join
+	resp, err := h.UpdateChannel(ctxWithUser(t, h, member.ID), openapi.UpdateChannelRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.UpdateChannelJSONRequestBody{AutoJoin: &autoJoin},
+	})
+	if err != nil {
+		t.Fatalf("UpdateChannel: %v", err)
+	}
+	if _, ok := resp.(openapi.UpdateChannel403JSONResponse); !ok {
+		t.Errorf("channel admin: expected 403 response, got %T", resp)
+	}
+
+	client := connectSSEClient(t, h, ws.ID, lead.ID)
+	resp, err = h.UpdateChannel(ctxWithUser(t, h, owner.ID), openapi.UpdateChannelRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.UpdateChannelJSONRequestBody{AutoJoin: &autoJoin, BackfillMembers: &autoJoin},
+	})
+	if err != nil {
+		t.Fatalf("UpdateChannel: %v", err)
+	}
+	r, ok := resp.(openapi.UpdateChannel200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if !r.Channel.AutoJoin || r.AddedMemberCount == nil || *r.AddedMemberCount != 2 {
+		t.Errorf("update = %+v, want auto_join and the owner and lead backfilled", r)
+	}
+	expectSSEEvent(t, client, sse.EventMemberAdded)
+	if _, err := h.channelRepo.GetMembership(t.Context(), lead.ID, ch.ID); err != nil {
+		t.Errorf("lead should have been backfilled: %v", err)
+	}
+}
+
+func TestListMyDMs(t *testing.T) {
+	h, db := testHandler(t)
+
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws1 := testutil.CreateTestWorkspace(t, db, alice.ID, "First")
+	ws2 := testutil.CreateTestWorkspace(t, db, alice.ID, "Second")
+	addWorkspaceMember(t, db, bob.ID, ws1.ID, "member")
+	addWorkspaceMember(t, db, bob.ID, ws2.ID, "member")
+
+	dm1 := testutil.CreateTestChannel(t, db, ws1.ID, alice.ID, "dm1", channel.TypeDM)
+	addChannelMember(t, db, bob.ID, dm1.ID, nil)
+	dm2 := testutil.CreateTestChannel(t, db, ws2.ID, alice.ID, "dm2", channel.TypeDM)
+	addChannelMember(t, db, bob.ID, dm2.ID, nil)
+	testutil.CreateTestChannel(t, db, ws2.ID, alice.ID, "general", channel.TypePublic)
+	testutil.CreateTestMessage(t, db, dm2.ID, bob.ID, "see you\n  at standup")
+
+	resp, err := h.ListMyDMs(ctxWithUser(t, h, alice.ID), openapi.ListMyDMsRequestObject{})
+	if err != nil {
+		t.Fatalf("ListMyDMs: %v", err)
+	}
+	conversations := resp.(openapi.ListMyDMs200JSONResponse).Conversations
+	if len(conversations) != 2 {
+		t.Fatalf("got %d conversations, want the DM in each workspace", len(conversations))
+	}
+	byID := make(map[string]openapi.DMConversation)
+	for _, c := range conversations {
+		byID[c.Id] = c
+	}
+	if c := byID[dm1.ID]; c.WorkspaceName != "First" || c.LastMessagePreview != nil {
+		t.Errorf("dm1 = %+v, want the first workspace and no preview", c)
+	}
+	c := byID[dm2.ID]
+	if c.WorkspaceName != "Second" || c.LastMessagePreview == nil || *c.LastMessagePreview != "see you at standup" {
+		t.Errorf("dm2 = %+v, want the second workspace and the latest message", c)
+	}
+	if c.DmParticipants == nil || len(*c.DmParticipants) != 1 || (*c.DmParticipants)[0].UserId != bob.ID {
+		t.Errorf("dm2 participants = %+v, want bob", c.DmParticipants)
+	}
+
+	// Banned workspaces drop out of the inbox
+	if err := h.moderationRepo.CreateBan(t.Context(), &moderation.Ban{WorkspaceID: ws1.ID, UserID: bob.ID, BannedBy: &alice.ID}); err != nil {
+		t.Fatalf("banning bob: %v", err)
+	}
+	resp, err = h.ListMyDMs(ctxWithUser(t, h, bob.ID), openapi.ListMyDMsRequestObject{})
+	if err != nil {
+		t.Fatalf("ListMyDMs: %v", err)
+	}
+	if conversations := resp.(openapi.ListMyDMs200JSONResponse).Conversations; len(conversations) != 1 || conversations[0].Id != dm2.ID {
+		t.Errorf("conversations = %+v, want only the DM in the second workspace", conversations)
+	}
+}
+
+func TestMarkChannelRead_ReadState(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	m1 := testutil.CreateTestMessage(t, db, ch.ID, other.ID, "one")
+	m2 := testutil.CreateTestMessage(t, db, ch.ID, other.ID, "two")
+	m3 := testutil.CreateTestMessage(t, db, ch.ID, other.ID, "three")
+	m4 := testutil.CreateTestMessage(t, db, ch.ID, other.ID, "four")
+
+	readState := func(userID string) openapi.GetChannelReadStateResponseObject {
+		t.Helper()
+		resp, err := h.GetChannelReadState(ctxWithUser(t, h, userID), openapi.GetChannelReadStateRequestObject{Id: openapi.ChannelId(ch.ID)})
+		if err != nil {
+			t.Fatalf("GetChannelReadState: %v", err)
+		}
+		return resp
+	}
+
+	state := readState(user.ID).(openapi.GetChannelReadState200JSONResponse)
+	if state.LastReadMessageId != nil || state.FirstUnreadMessageId == nil || *state.FirstUnreadMessageId != m1.ID || state.UnreadCount != 4 {
+		t.Fatalf("expected 4 unread from %s, got %+v", m1.ID, state)
+	}
+
+	includeCounts := true
+	resp, err := h.MarkChannelRead(ctx, openapi.MarkChannelReadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.MarkChannelReadJSONRequestBody{MessageId: &m2.ID, IncludeCounts: &includeCounts},
+	})
+	if err != nil {
+		t.Fatalf("MarkChannelRead: %v", err)
+	}
+	marked := resp.(openapi.MarkChannelRead200JSONResponse)
+	if marked.MarkedCount == nil || *marked.MarkedCount != 2 {
+		t.Errorf("expected 2 marked read, got %v", marked.MarkedCount)
+	}
+	if marked.FirstUnreadMessageId == nil || *marked.FirstUnreadMessageId != m1.ID {
+		t.Errorf("expected first unread %s before the update, got %v", m1.ID, marked.FirstUnreadMessageId)
+	}
+
+	// A deleted message is skipped when placing the divider
+	if _, err := db.Exec(`UPDATE messages SET deleted_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), m3.ID); err != nil {
+		t.Fatalf("deleting message: %v", err)
+	}
+	state = readState(user.ID).(openapi.GetChannelReadState200JSONResponse)
+	if state.LastReadMessageId == nil || *state.LastReadMessageId != m2.ID {
+		t.Errorf("expected last read %s, got %v", m2.ID, state.LastReadMessageId)
+	}
+	if state.FirstUnreadMessageId == nil || *state.FirstUnreadMessageId != m4.ID || state.UnreadCount != 1 {
+		t.Errorf("expected 1 unread from %s, got %+v", m4.ID, state)
+	}
+
+	// Counts are only returned on request
+	resp, err = h.MarkChannelRead(ctx, openapi.MarkChannelReadRequestObject{Id: openapi.ChannelId(ch.ID)})
+	if err != nil {
+		t.Fatalf("MarkChannelRead: %v", err)
+	}
+	if marked := resp.(openapi.MarkChannelRead200JSONResponse); marked.MarkedCount != nil || marked.LastReadMessageId != m4.ID {
+		t.Errorf("expected plain response up to %s, got %+v", m4.ID, marked)
+	}
+	state = readState(user.ID).(openapi.GetChannelReadState200JSONResponse)
+	if state.FirstUnreadMessageId != nil || state.UnreadCount != 0 {
+		t.Errorf("expected everything read, got %+v", state)
+	}
+
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	addWorkspaceMember(t, db, outsider.ID, ws.ID, "member")
+	if _, ok := readState(outsider.ID).(openapi.GetChannelReadState403JSONResponse); !ok {
+		t.Error("expected 403 for a non-member")
+	}
+}
+
+func TestMarkAllChannelsRead(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	general := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	random := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "random", channel.TypePublic)
+	archived := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "old", channel.TypePublic)
+	empty := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "empty", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	testutil.CreateTestMessage(t, db, general.ID, other.ID, "one")
+	latest := testutil.CreateTestMessage(t, db, general.ID, other.ID, "two")
+	read := testutil.CreateTestMessage(t, db, random.ID, other.ID, "seen")
+	if err := h.channelRepo.UpdateLastRead(ctx, user.ID, random.ID, read.ID); err != nil {
+		t.Fatalf("UpdateLastRead: %v", err)
+	}
+	testutil.CreateTestMessage(t, db, archived.ID, other.ID, "archived")
+	if _, err := db.Exec(`UPDATE channels SET archived_at = ? WHERE id = ?`, time.Now().UTC().Format(time.RFC3339), archived.ID); err != nil {
+		t.Fatalf("archiving channel: %v", err)
+	}
+
+	resp, err := h.MarkAllChannelsRead(ctx, openapi.MarkAllChannelsReadRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("MarkAllChannelsRead: %v", err)
+	}
+	if _, ok := resp.(openapi.MarkAllChannelsRead200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+
+	state, err := h.channelRepo.GetReadState(ctx, user.ID, general.ID)
+	if err != nil {
+		t.Fatalf("GetReadState: %v", err)
+	}
+	if state.LastReadMessageID == nil || *state.LastReadMessageID != latest.ID || state.UnreadCount != 0 {
+		t.Errorf("expected general read up to %s, got %+v", latest.ID, state)
+	}
+	if state, _ := h.channelRepo.GetReadState(ctx, user.ID, archived.ID); state.LastReadMessageID != nil || state.UnreadCount != 1 {
+		t.Errorf("expected archived channel left unread, got %+v", state)
+	}
+	if state, _ := h.channelRepo.GetReadState(ctx, user.ID, empty.ID); state.LastReadMessageID != nil {
+		t.Errorf("expected empty channel untouched, got %+v", state)
+	}
+
+	// Only channels with something new to read are reported
+	testutil.CreateTestMessage(t, db, random.ID, other.ID, "new")
+	marks, err := h.channelRepo.MarkAllRead(ctx, ws.ID, user.ID)
+	if err != nil {
+		t.Fatalf("MarkAllRead: %v", err)
+	}
+	if len(marks) != 1 || marks[0].ChannelID != random.ID {
+		t.Errorf("expected only random to move, got %+v", marks)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/user"
+)
+
+// GetMyClientSettings returns the settings the user's clients sync between
+// devices
+func (h *Handler) GetMyClientSettings(ctx context.Context, request openapi.GetMyClientSettingsRequestObject) (openapi.GetMyClientSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetMyClientSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	settings, err := h.userRepo.GetClientSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.GetMyClientSettings200JSONResponse(clientSettingsToAPI(settings)), nil
+}
+
+// UpdateMyClientSettings merges changed keys into the user's client settings
+// and tells their other clients about it
+func (h *Handler) UpdateMyClientSettings(ctx context.Context, request openapi.UpdateMyClientSettingsRequestObject) (openapi.UpdateMyClientSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateMyClientSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	if request.Body.Settings == nil {
+		return openapi.UpdateMyClientSettings400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "settings is required")}, nil
+	}
+
+	settings, err := h.userRepo.PatchClientSettings(ctx, userID, request.Body.Settings, request.Body.UpdatedAt)
+	if errors.Is(err, user.ErrClientSettingsConflict) {
+		return openapi.UpdateMyClientSettings409JSONResponse{ConflictJSONResponse: conflictResponse("Settings were changed by another client; reload them and try again")}, nil
+	}
+	if errors.Is(err, user.ErrClientSettingsTooLarge) {
+		return openapi.UpdateMyClientSettings400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Settings cannot be larger than %d KiB", user.MaxClientSettingsBytes/1024))}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	apiSettings := clientSettingsToAPI(settings)
+	if h.hub != nil {
+		h.hub.BroadcastToUser(ctx, "", userID, sse.NewSettingsUpdatedEvent(apiSettings))
+	}
+	return openapi.UpdateMyClientSettings200JSONResponse(apiSettings), nil
+}
+
+func clientSettingsToAPI(s *user.ClientSettings) openapi.ClientSettings {
+	return openapi.ClientSettings{
+		Settings:  s.Settings,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
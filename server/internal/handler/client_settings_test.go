@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestUpdateMyClientSettings_MergesKeys(t *testing.T) {
+	h, db := testHandler(t)
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	ctx := ctxWithUser(t, h, alice.ID)
+
+	got, err := h.GetMyClientSettings(ctx, openapi.GetMyClientSettingsRequestObject{})
+	if err != nil {
+		t.Fatalf("GetMyClientSettings: %v", err)
+	}
+	empty := got.(openapi.GetMyClientSettings200JSONResponse)
+	if len(empty.Settings) != 0 || empty.UpdatedAt != nil {
+		t.Fatalf("expected no settings before the first save, got %+v", empty)
+	}
+
+	update := func(body openapi.UpdateClientSettingsInput) openapi.UpdateMyClientSettingsResponseObject {
+		t.Helper()
+		resp, err := h.UpdateMyClientSettings(ctx, openapi.UpdateMyClientSettingsRequestObject{Body: &body})
+		if err != nil {
+			t.Fatalf("UpdateMyClientSettings: %v", err)
+		}
+		return resp
+	}
+
+	first, ok := update(openapi.UpdateClientSettingsInput{Settings: map[string]interface{}{
+		"theme":        "dark",
+		"compact_mode": true,
+		"sounds":       map[string]interface{}{"mention": "chime", "dm": "pop"},
+	}}).(openapi.UpdateMyClientSettings200JSONResponse)
+	if !ok {
+		t.Fatal("expected the first save to succeed")
+	}
+
+	// Only the changed keys are sent; null removes a key
+	second, ok := update(openapi.UpdateClientSettingsInput{
+		Settings: map[string]interface{}{
+			"compact_mode": nil,
+			"locale":       "de-DE",
+			"sounds":       map[string]interface{}{"dm": "none"},
+		},
+		UpdatedAt: first.UpdatedAt,
+	}).(openapi.UpdateMyClientSettings200JSONResponse)
+	if !ok {
+		t.Fatal("expected a patch against the latest version to succeed")
+	}
+	if _, ok := second.Settings["compact_mode"]; ok {
+		t.Error("expected compact_mode to be removed")
+	}
+	if second.Settings["theme"] != "dark" || second.Settings["locale"] != "de-DE" {
+		t.Errorf("expected theme kept and locale added, got %v", second.Settings)
+	}
+	sounds, _ := second.Settings["sounds"].(map[string]interface{})
+	if sounds["mention"] != "chime" || sounds["dm"] != "none" {
+		t.Errorf("expected nested keys to be merged, got %v", second.Settings["sounds"])
+	}
+
+	// A client still holding the first version has missed a change
+	if _, ok := update(openapi.UpdateClientSettingsInput{
+		Settings:  map[string]interface{}{"theme": "light"},
+		UpdatedAt: first.UpdatedAt,
+	}).(openapi.UpdateMyClientSettings409JSONResponse); !ok {
+		t.Error("expected 409 for a stale updated_at")
+	}
+
+	if _, ok := update(openapi.UpdateClientSettingsInput{
+		Settings: map[string]interface{}{"blob": strings.Repeat("x", 70*1024)},
+	}).(openapi.UpdateMyClientSettings400JSONResponse); !ok {
+		t.Error("expected 400 for settings over the size cap")
+	}
+
+	got, err = h.GetMyClientSettings(ctx, openapi.GetMyClientSettingsRequestObject{})
+	if err != nil {
+		t.Fatalf("GetMyClientSettings: %v", err)
+	}
+	stored := got.(openapi.GetMyClientSettings200JSONResponse)
+	if stored.Settings["theme"] != "dark" || stored.UpdatedAt == nil || !stored.UpdatedAt.Equal(*second.UpdatedAt) {
+		t.Errorf("expected the second version to be stored, got %+v", stored)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// cutListContent shortens content to the list content limit. It reports
+// whether anything was cut; clients fetch the message for the rest.
+func (h *Handler) cutListContent(content string) (string, bool) {
+	if h.listContentLength <= 0 || len(content) <= h.listContentLength {
+		return content, false
+	}
+	runes := 0
+	for i := range content {
+		if runes == h.listContentLength {
+			return content[:i], true
+		}
+		runes++
+	}
+	return content, false
+}
+
+// truncateMessages applies the list content limit to a page of messages.
+func (h *Handler) truncateMessages(messages []openapi.MessageWithUser) {
+	for i := range messages {
+		m := &messages[i]
+		if content, cut := h.cutListContent(m.Content); cut {
+			m.Content = content
+			m.ContentRendered = renderContent(content, m.DeletedAt)
+			m.ContentTruncated = &cut
+		}
+	}
+}
+
+// truncateThreadMessages applies the list content limit to thread parents.
+func (h *Handler) truncateThreadMessages(messages []openapi.ThreadMessage) {
+	for i := range messages {
+		m := &messages[i]
+		if content, cut := h.cutListContent(m.Content); cut {
+			m.Content = content
+			m.ContentRendered = renderContent(content, m.DeletedAt)
+			m.ContentTruncated = &cut
+		}
+	}
+}
+
+// truncateSearchMessages applies the list content limit to search hits.
+func (h *Handler) truncateSearchMessages(messages []openapi.SearchMessage) {
+	for i := range messages {
+		m := &messages[i]
+		if content, cut := h.cutListContent(m.Content); cut {
+			m.Content = content
+			m.ContentRendered = renderContent(content, m.DeletedAt)
+			m.ContentTruncated = &cut
+		}
+	}
+}
+
+// truncateUnreadMessages applies the list content limit to unread messages.
+func (h *Handler) truncateUnreadMessages(messages []openapi.UnreadMessage) {
+	for i := range messages {
+		m := &messages[i]
+		if content, cut := h.cutListContent(m.Content); cut {
+			m.Content = content
+			m.ContentRendered = renderContent(content, m.DeletedAt)
+			m.ContentTruncated = &cut
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/contentfilter"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+const errContentBlocked = "Message contains blocked content"
+
+// filterContent checks a message against the workspace's blocked words. It
+// returns the content to store, with mask matches replaced, or blocked=true
+// if a reject rule matched. Blocked messages are recorded in the audit log.
+func (h *Handler) filterContent(ctx context.Context, workspaceID, channelID, userID, content string) (string, bool, error) {
+	if h.contentFilter == nil {
+		return content, false, nil
+	}
+	result, err := h.contentFilter.Check(ctx, workspaceID, content)
+	if err != nil {
+		return "", false, err
+	}
+	if result.Blocked {
+		if err := h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, moderation.ActionMessageBlocked, moderation.TargetTypeChannel, channelID, map[string]interface{}{
+			"rule_ids": result.RuleIDs,
+		}); err != nil {
+			slog.Error("failed to create audit log entry for blocked message", "error", err)
+		}
+	}
+	return result.Content, result.Blocked, nil
+}
+
+// ListBlockedWords lists a workspace's content filter entries
+func (h *Handler) ListBlockedWords(ctx context.Context, request openapi.ListBlockedWordsRequestObject) (openapi.ListBlockedWordsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListBlockedWords401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.ListBlockedWords403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.ListBlockedWords403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage blocked words")}, nil
+	}
+
+	rules, err := h.contentFilterRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	apiRules := make([]openapi.BlockedWord, len(rules))
+	for i := range rules {
+		apiRules[i] = blockedWordToAPI(&rules[i])
+	}
+	return openapi.ListBlockedWords200JSONResponse{BlockedWords: apiRules}, nil
+}
+
+// CreateBlockedWord adds a word or regular expression to the blocklist
+func (h *Handler) CreateBlockedWord(ctx context.Context, request openapi.CreateBlockedWordRequestObject) (openapi.CreateBlockedWordResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateBlockedWord401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.CreateBlockedWord403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.CreateBlockedWord403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage blocked words")}, nil
+	}
+
+	rule := &contentfilter.Rule{
+		WorkspaceID: workspaceID,
+		Pattern:     strings.TrimSpace(request.Body.Pattern),
+		IsRegex:     request.Body.IsRegex != nil && *request.Body.IsRegex,
+		Action:      contentfilter.ActionReject,
+		CreatedBy:   &userID,
+	}
+	if request.Body.Action != nil {
+		rule.Action = string(*request.Body.Action)
+	}
+	if rule.Pattern == "" {
+		return openapi.CreateBlockedWord400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Pattern is required")}, nil
+	}
+	if utf8.RuneCountInString(rule.Pattern) > contentfilter.MaxPatternLength {
+		return openapi.CreateBlockedWord400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Pattern must be at most %d characters", contentfilter.MaxPatternLength))}, nil
+	}
+	if !contentfilter.IsValidAction(rule.Action) {
+		return openapi.CreateBlockedWord400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Action must be reject or mask")}, nil
+	}
+	if re, err := contentfilter.Compile(rule.Pattern, rule.IsRegex); err != nil {
+		return openapi.CreateBlockedWord400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid regular expression")}, nil
+	} else if re.MatchString("") {
+		return openapi.CreateBlockedWord400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Pattern must not match empty text")}, nil
+	}
+
+	if err := h.contentFilterRepo.Create(ctx, rule); err != nil {
+		switch {
+		case errors.Is(err, contentfilter.ErrRuleExists):
+			return openapi.CreateBlockedWord409JSONResponse{ConflictJSONResponse: conflictResponse("This pattern is already blocked")}, nil
+		case errors.Is(err, contentfilter.ErrTooManyRules):
+			return openapi.CreateBlockedWord400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("A workspace can have at most %d blocked words", contentfilter.MaxRulesPerWorkspace))}, nil
+		}
+		return nil, err
+	}
+	if h.contentFilter != nil {
+		h.contentFilter.Invalidate(workspaceID)
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "blocked_word.created", "blocked_word", rule.ID, map[string]interface{}{
+		"pattern":  rule.Pattern,
+		"is_regex": rule.IsRegex,
+		"action":   rule.Action,
+	})
+
+	return openapi.CreateBlockedWord200JSONResponse{BlockedWord: blockedWordToAPI(rule)}, nil
+}
+
+// DeleteBlockedWord removes an entry from the blocklist
+func (h *Handler) DeleteBlockedWord(ctx context.Context, request openapi.DeleteBlockedWordRequestObject) (openapi.DeleteBlockedWordResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteBlockedWord401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	rule, err := h.contentFilterRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, contentfilter.ErrRuleNotFound) {
+			return openapi.DeleteBlockedWord404JSONResponse{NotFoundJSONResponse: notFoundResponse("Blocked word not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, rule.WorkspaceID)
+	if err != nil {
+		return openapi.DeleteBlockedWord404JSONResponse{NotFoundJSONResponse: notFoundResponse("Blocked word not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.DeleteBlockedWord403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage blocked words")}, nil
+	}
+
+	if err := h.contentFilterRepo.Delete(ctx, rule.ID); err != nil {
+		if errors.Is(err, contentfilter.ErrRuleNotFound) {
+			return openapi.DeleteBlockedWord404JSONResponse{NotFoundJSONResponse: notFoundResponse("Blocked word not found")}, nil
+		}
+		return nil, err
+	}
+	if h.contentFilter != nil {
+		h.contentFilter.Invalidate(rule.WorkspaceID)
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, rule.WorkspaceID, userID, "blocked_word.deleted", "blocked_word", rule.ID, map[string]interface{}{
+		"pattern": rule.Pattern,
+	})
+
+	return openapi.DeleteBlockedWord200JSONResponse{Success: true}, nil
+}
+
+func blockedWordToAPI(rule *contentfilter.Rule) openapi.BlockedWord {
+	return openapi.BlockedWord{
+		Id:          rule.ID,
+		WorkspaceId: rule.WorkspaceID,
+		Pattern:     rule.Pattern,
+		IsRegex:     rule.IsRegex,
+		Action:      openapi.BlockedWordAction(rule.Action),
+		CreatedBy:   rule.CreatedBy,
+		CreatedAt:   rule.CreatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestBlockedWords(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+
+	create := func(ctx context.Context, pattern string, isRegex bool, action openapi.BlockedWordAction) openapi.CreateBlockedWordResponseObject {
+		t.Helper()
+		resp, err := h.CreateBlockedWord(ctx, openapi.CreateBlockedWordRequestObject{
+			Wid:  ws.ID,
+			Body: &openapi.CreateBlockedWordJSONRequestBody{Pattern: pattern, IsRegex: &isRegex, Action: &action},
+		})
+		if err != nil {
+			t.Fatalf("CreateBlockedWord: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := create(memberCtx, "darn", false, openapi.BlockedWordActionMask).(openapi.CreateBlockedWord403JSONResponse); !ok {
+		t.Fatal("expected members to be unable to manage blocked words")
+	}
+	if _, ok := create(ownerCtx, "(unclosed", true, openapi.BlockedWordActionReject).(openapi.CreateBlockedWord400JSONResponse); !ok {
+		t.Fatal("expected 400 for an invalid regular expression")
+	}
+	if _, ok := create(ownerCtx, "a*", true, openapi.BlockedWordActionReject).(openapi.CreateBlockedWord400JSONResponse); !ok {
+		t.Fatal("expected 400 for a pattern that matches empty text")
+	}
+	if _, ok := create(ownerCtx, "darn", false, openapi.BlockedWordActionMask).(openapi.CreateBlockedWord200JSONResponse); !ok {
+		t.Fatal("expected the mask rule to be created")
+	}
+	if _, ok := create(ownerCtx, "darn", false, openapi.BlockedWordActionReject).(openapi.CreateBlockedWord409JSONResponse); !ok {
+		t.Fatal("expected 409 for a duplicate pattern")
+	}
+	rejectResp, ok := create(ownerCtx, `leak\w*`, true, openapi.BlockedWordActionReject).(openapi.CreateBlockedWord200JSONResponse)
+	if !ok {
+		t.Fatal("expected the reject rule to be created")
+	}
+
+	send := func(content string) openapi.SendMessageResponseObject {
+		t.Helper()
+		resp, err := h.SendMessage(memberCtx, openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+		})
+		if err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		return resp
+	}
+
+	sent, ok := send("well darn it").(openapi.SendMessage200JSONResponse)
+	if !ok {
+		t.Fatal("expected a masked message to be sent")
+	}
+	if sent.Message.Content != "well **** it" {
+		t.Errorf("content = %q, want the match masked", sent.Message.Content)
+	}
+
+	if _, ok := send("the LEAKED numbers").(openapi.SendMessage400JSONResponse); !ok {
+		t.Fatal("expected a message matching a reject rule to be refused")
+	}
+	entries, _, _, err := h.moderationRepo.ListAuditLog(context.Background(), ws.ID, moderation.AuditLogFilter{Action: moderation.ActionMessageBlocked}, "", 10)
+	if err != nil {
+		t.Fatalf("ListAuditLog: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ActorID != member.ID {
+		t.Errorf("expected one message.blocked audit entry by the sender, got %+v", entries)
+	}
+
+	// Edits are filtered too
+	update, err := h.UpdateMessage(memberCtx, openapi.UpdateMessageRequestObject{
+		Id:   sent.Message.Id,
+		Body: &openapi.UpdateMessageJSONRequestBody{Content: "leaks everywhere"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMessage: %v", err)
+	}
+	if _, ok := update.(openapi.UpdateMessage400JSONResponse); !ok {
+		t.Fatalf("expected the edit to be refused, got %T", update)
+	}
+
+	// Deleting the rule takes effect immediately
+	del, err := h.DeleteBlockedWord(ownerCtx, openapi.DeleteBlockedWordRequestObject{Id: rejectResp.BlockedWord.Id})
+	if err != nil {
+		t.Fatalf("DeleteBlockedWord: %v", err)
+	}
+	if _, ok := del.(openapi.DeleteBlockedWord200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", del)
+	}
+	if _, ok := send("the leaked numbers").(openapi.SendMessage200JSONResponse); !ok {
+		t.Fatal("expected the message to be allowed once the rule is deleted")
+	}
+
+	list, err := h.ListBlockedWords(ownerCtx, openapi.ListBlockedWordsRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListBlockedWords: %v", err)
+	}
+	if words := list.(openapi.ListBlockedWords200JSONResponse).BlockedWords; len(words) != 1 || words[0].Pattern != "darn" {
+		t.Errorf("expected only the mask rule to remain, got %+v", words)
+	}
+}
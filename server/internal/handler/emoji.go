@@ -169,7 +169,7 @@ func (h *Handler) UploadCustomEmoji(ctx context.Context, request openapi.UploadC
 
 	// Broadcast SSE event
 	if h.hub != nil {
-		h.hub.BroadcastToWorkspace(workspaceID, sse.NewEmojiCreatedEvent(apiEmoji))
+		h.hub.BroadcastToWorkspace(ctx, workspaceID, sse.NewEmojiCreatedEvent(apiEmoji))
 	}
 
 	return openapi.UploadCustomEmoji200JSONResponse{
@@ -257,7 +257,7 @@ func (h *Handler) DeleteCustomEmoji(ctx context.Context, request openapi.DeleteC
 
 	// Broadcast SSE event
 	if h.hub != nil {
-		h.hub.BroadcastToWorkspace(e.WorkspaceID, sse.NewEmojiDeletedEvent(openapi.EmojiDeletedData{
+		h.hub.BroadcastToWorkspace(ctx, e.WorkspaceID, sse.NewEmojiDeletedEvent(openapi.EmojiDeletedData{
 			Id:   e.ID,
 			Name: e.Name,
 		}))
@@ -1,7 +1,30 @@
 package handler
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/enzyme/server/internal/announcement"
+	"github.com/enzyme/server/internal/bot"
+	"github.com/enzyme/server/internal/call"
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/channeltemplate"
+	"github.com/enzyme/server/internal/contentfilter"
+	"github.com/enzyme/server/internal/emoji"
+	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/notification"
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/poll"
+	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/usergroup"
+	"github.com/enzyme/server/internal/webhook"
+	"github.com/enzyme/server/internal/workspace"
 )
 
 // newError creates an ApiError with the given code and message
@@ -19,8 +42,45 @@ func newErrorResponse(code, message string) openapi.ApiErrorResponse {
 	}
 }
 
+// codedErrorResponse creates an ApiErrorResponse whose message is the
+// catalog text for code filled in with params. Such errors are translated
+// per request by LocalizeError, and the params let clients word them their
+// own way.
+func codedErrorResponse(code string, params map[string]any) openapi.ApiErrorResponse {
+	msg, _ := i18n.Format(i18n.Default, errorMessageKey(code), params)
+	resp := newErrorResponse(code, msg)
+	if len(params) > 0 {
+		resp.Error.Params = &params
+	}
+	return resp
+}
+
+// errorMessageKey returns the i18n catalog key for an error code.
+func errorMessageKey(code string) string {
+	return "error." + strings.ToLower(code)
+}
+
+// LocalizeError translates e into locale if its message is the catalog text
+// for its code. Errors with a hand-written message are left alone, since the
+// code alone does not say what the message was.
+func LocalizeError(locale string, e *openapi.ApiError) {
+	if locale == i18n.Default {
+		return
+	}
+	var params map[string]any
+	if e.Params != nil {
+		params = *e.Params
+	}
+	key := errorMessageKey(e.Code)
+	if msg, ok := i18n.Format(i18n.Default, key, params); !ok || msg != e.Message {
+		return
+	}
+	e.Message, _ = i18n.Format(locale, key, params)
+}
+
 // Common error codes
 const (
+	ErrCodeBadRequest       = "BAD_REQUEST"
 	ErrCodeInvalidJSON      = "INVALID_JSON"
 	ErrCodeInternalError    = "INTERNAL_ERROR"
 	ErrCodeNotAuthenticated = "NOT_AUTHENTICATED"
@@ -30,13 +90,54 @@ const (
 	ErrCodeValidationError  = "VALIDATION_ERROR"
 	ErrCodeConflict         = "CONFLICT"
 	ErrCodeFilesDisabled    = "FILES_DISABLED"
+	ErrCodeRateLimited      = "RATE_LIMITED"
+	ErrCodeQuotaExceeded    = "STORAGE_QUOTA_EXCEEDED"
+	ErrCodeContentBlocked   = "CONTENT_BLOCKED"
+	ErrCodeSlowMode         = "SLOW_MODE"
+	ErrCodeReadOnly         = "READ_ONLY"
+	ErrCodeMessageTooLong   = "MESSAGE_TOO_LONG"
+	ErrCodeFileTypeBlocked  = "FILE_TYPE_NOT_ALLOWED"
+	ErrCodeFileQuarantined  = "FILE_QUARANTINED"
+	ErrCodeBanned           = "BANNED"
+	ErrCodeFeatureDisabled  = "FEATURE_DISABLED"
+)
+
+// Validation error codes. Each carries params naming the offending field and
+// the bounds it broke, and has catalog text in every supported locale.
+const (
+	ErrCodeRequired           = "REQUIRED"             // params: field
+	ErrCodeTooLong            = "TOO_LONG"             // params: field, max
+	ErrCodeInvalidValue       = "INVALID_VALUE"        // params: field
+	ErrCodeOutOfRange         = "OUT_OF_RANGE"         // params: field, min, max
+	ErrCodeInvalidChannelName = "INVALID_CHANNEL_NAME" // params: field
+	ErrCodeChannelNameTaken   = "CHANNEL_NAME_TAKEN"
+)
+
+// Account and device error codes
+const (
+	ErrCodeEmailInUse               = "EMAIL_IN_USE"
+	ErrCodePasswordTooShort         = "PASSWORD_TOO_SHORT"
+	ErrCodeDisplayNameRequired      = "DISPLAY_NAME_REQUIRED"
+	ErrCodeInvalidEmail             = "INVALID_EMAIL"
+	ErrCodeInvalidCredentials       = "INVALID_CREDENTIALS"
+	ErrCodeUserDeactivated          = "USER_DEACTIVATED"
+	ErrCodeRefreshTokenReused       = "REFRESH_TOKEN_REUSED"
+	ErrCodeInvalidRefreshToken      = "INVALID_REFRESH_TOKEN"
+	ErrCodeEmailNotEnabled          = "EMAIL_NOT_ENABLED"
+	ErrCodeInvalidResetToken        = "INVALID_RESET_TOKEN"
+	ErrCodeInvalidVerificationToken = "INVALID_VERIFICATION_TOKEN"
+	ErrCodeAlreadyVerified          = "ALREADY_VERIFIED"
+	ErrCodePushNotEnabled           = "PUSH_NOT_ENABLED"
+	ErrCodeInvalidDeviceToken       = "INVALID_TOKEN"
+	ErrCodeInvalidDeviceID          = "INVALID_DEVICE_ID"
+	ErrCodeDeviceTokenNotFound      = "TOKEN_NOT_FOUND"
 )
 
 // Error response helpers that return typed shared response components.
 // Usage: return openapi.SendMessage401JSONResponse{unauthorizedResponse()}, nil
 
 func unauthorizedResponse() openapi.UnauthorizedJSONResponse {
-	return openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated"))
+	return openapi.UnauthorizedJSONResponse(codedErrorResponse(ErrCodeNotAuthenticated, nil))
 }
 
 func forbiddenResponse(msg string) openapi.ForbiddenJSONResponse {
@@ -60,5 +161,130 @@ func conflictResponse(msg string) openapi.ConflictJSONResponse {
 }
 
 func filesDisabledResponse() openapi.ForbiddenJSONResponse {
-	return openapi.ForbiddenJSONResponse(newErrorResponse(ErrCodeFilesDisabled, "File uploads are disabled"))
+	return openapi.ForbiddenJSONResponse(codedErrorResponse(ErrCodeFilesDisabled, nil))
+}
+
+func quotaExceededResponse() openapi.ForbiddenJSONResponse {
+	return openapi.ForbiddenJSONResponse(codedErrorResponse(ErrCodeQuotaExceeded, nil))
+}
+
+func fileTypeBlockedResponse() openapi.ForbiddenJSONResponse {
+	return openapi.ForbiddenJSONResponse(codedErrorResponse(ErrCodeFileTypeBlocked, nil))
+}
+
+func fileQuarantinedResponse() openapi.ForbiddenJSONResponse {
+	return openapi.ForbiddenJSONResponse(codedErrorResponse(ErrCodeFileQuarantined, nil))
+}
+
+// featureDisabledResponse rejects a request for a feature whose flag is off
+// in the workspace.
+func featureDisabledResponse() openapi.ForbiddenJSONResponse {
+	return openapi.ForbiddenJSONResponse(codedErrorResponse(ErrCodeFeatureDisabled, nil))
+}
+
+// requiredResponse rejects a missing or blank field.
+func requiredResponse(field string) openapi.BadRequestJSONResponse {
+	return openapi.BadRequestJSONResponse(codedErrorResponse(ErrCodeRequired, map[string]any{"field": field}))
+}
+
+// tooLongResponse rejects a field longer than max characters.
+func tooLongResponse(field string, max int) openapi.BadRequestJSONResponse {
+	return openapi.BadRequestJSONResponse(codedErrorResponse(ErrCodeTooLong, map[string]any{"field": field, "max": max}))
+}
+
+// invalidValueResponse rejects a field whose value is not one of those allowed.
+func invalidValueResponse(field string) openapi.BadRequestJSONResponse {
+	return openapi.BadRequestJSONResponse(codedErrorResponse(ErrCodeInvalidValue, map[string]any{"field": field}))
+}
+
+// outOfRangeResponse rejects a number outside [min, max].
+func outOfRangeResponse(field string, min, max int) openapi.BadRequestJSONResponse {
+	return openapi.BadRequestJSONResponse(codedErrorResponse(ErrCodeOutOfRange, map[string]any{"field": field, "min": min, "max": max}))
+}
+
+func invalidChannelNameResponse() openapi.BadRequestJSONResponse {
+	return openapi.BadRequestJSONResponse(codedErrorResponse(ErrCodeInvalidChannelName, map[string]any{"field": "name"}))
+}
+
+func channelNameTakenResponse() openapi.BadRequestJSONResponse {
+	return openapi.BadRequestJSONResponse(codedErrorResponse(ErrCodeChannelNameTaken, nil))
+}
+
+func readOnlyResponse(msg string) openapi.ReadOnlyJSONResponse {
+	return openapi.ReadOnlyJSONResponse(newErrorResponse(ErrCodeReadOnly, msg))
+}
+
+// messageTooLongResponse rejects content over the message length limit. The
+// limit is included so clients can tell the user how much to cut.
+func messageTooLongResponse(what string, limit int) openapi.BadRequestJSONResponse {
+	resp := newErrorResponse(ErrCodeMessageTooLong, fmt.Sprintf("%s exceeds maximum length of %d characters", what, limit))
+	resp.Error.Limit = &limit
+	return openapi.BadRequestJSONResponse(resp)
+}
+
+func tooManyRequestsResponse(retryAfter int) openapi.TooManyRequestsJSONResponse {
+	return openapi.TooManyRequestsJSONResponse{
+		Body:    codedErrorResponse(ErrCodeRateLimited, map[string]any{"retry_after": retryAfter}),
+		Headers: openapi.TooManyRequestsResponseHeaders{RetryAfter: retryAfter},
+	}
+}
+
+// sentinelErrors maps repository sentinel errors to the response they stand
+// for when a handler returns one instead of a typed response. Handlers should
+// still check for the errors they expect; this keeps the ones they miss from
+// surfacing as 500s.
+var sentinelErrors = []struct {
+	err     error
+	status  int
+	code    string
+	message string
+}{
+	{sql.ErrNoRows, http.StatusNotFound, ErrCodeNotFound, "Not found"},
+	{workspace.ErrWorkspaceNotFound, http.StatusNotFound, ErrCodeNotFound, "Workspace not found"},
+	{workspace.ErrInviteNotFound, http.StatusNotFound, ErrCodeNotFound, "Invite not found"},
+	{workspace.ErrProfileFieldNotFound, http.StatusNotFound, ErrCodeNotFound, "Profile field not found"},
+	{channel.ErrChannelNotFound, http.StatusNotFound, ErrCodeNotFound, "Channel not found"},
+	{message.ErrMessageNotFound, http.StatusNotFound, ErrCodeNotFound, "Message not found"},
+	{message.ErrReactionNotFound, http.StatusNotFound, ErrCodeNotFound, "Reaction not found"},
+	{user.ErrUserNotFound, http.StatusNotFound, ErrCodeNotFound, "User not found"},
+	{file.ErrAttachmentNotFound, http.StatusNotFound, ErrCodeNotFound, "File not found"},
+	{file.ErrUploadSessionNotFound, http.StatusNotFound, ErrCodeNotFound, "Upload not found"},
+	{poll.ErrPollNotFound, http.StatusNotFound, ErrCodeNotFound, "Poll not found"},
+	{call.ErrCallNotFound, http.StatusNotFound, ErrCodeNotFound, "Call not found"},
+	{webhook.ErrWebhookNotFound, http.StatusNotFound, ErrCodeNotFound, "Webhook not found"},
+	{emoji.ErrEmojiNotFound, http.StatusNotFound, ErrCodeNotFound, "Emoji not found"},
+	{bot.ErrBotNotFound, http.StatusNotFound, ErrCodeNotFound, "Bot not found"},
+	{usergroup.ErrGroupNotFound, http.StatusNotFound, ErrCodeNotFound, "User group not found"},
+	{channeltemplate.ErrTemplateNotFound, http.StatusNotFound, ErrCodeNotFound, "Channel template not found"},
+	{announcement.ErrAnnouncementNotFound, http.StatusNotFound, ErrCodeNotFound, "Announcement not found"},
+	{contentfilter.ErrRuleNotFound, http.StatusNotFound, ErrCodeNotFound, "Blocked word not found"},
+	{notification.ErrKeywordNotFound, http.StatusNotFound, ErrCodeNotFound, "Keyword not found"},
+	{moderation.ErrBanNotFound, http.StatusNotFound, ErrCodeNotFound, "Ban not found"},
+	{moderation.ErrBlockNotFound, http.StatusNotFound, ErrCodeNotFound, "Block not found"},
+
+	{workspace.ErrNotAMember, http.StatusForbidden, ErrCodeNotAMember, "Not a member of this workspace"},
+	{channel.ErrNotChannelMember, http.StatusForbidden, ErrCodeNotAMember, "Not a member of this channel"},
+	{channel.ErrChannelArchived, http.StatusForbidden, ErrCodePermissionDenied, "Channel is archived"},
+
+	{channel.ErrAlreadyMember, http.StatusConflict, ErrCodeConflict, "Already a member of this channel"},
+	{workspace.ErrMembershipExists, http.StatusConflict, ErrCodeConflict, "Already a member of this workspace"},
+	{message.ErrReactionExists, http.StatusConflict, ErrCodeConflict, "Reaction already exists"},
+	{message.ErrDuplicateClientMsgID, http.StatusConflict, ErrCodeConflict, "Client message ID already used"},
+
+	{channel.ErrInvalidCursor, http.StatusBadRequest, ErrCodeValidationError, "Invalid cursor"},
+	{message.ErrInvalidCursor, http.StatusBadRequest, ErrCodeValidationError, "Invalid cursor"},
+	{workspace.ErrInvalidCursor, http.StatusBadRequest, ErrCodeValidationError, "Invalid cursor"},
+}
+
+// ErrorResponse returns the status and error body for an error a handler
+// returned instead of a typed response. Anything not in sentinelErrors is a
+// 500 whose message says nothing about the cause, so driver and SQL errors
+// never reach clients.
+func ErrorResponse(err error) (int, openapi.ApiError) {
+	for _, s := range sentinelErrors {
+		if errors.Is(err, s.err) {
+			return s.status, newError(s.code, s.message)
+		}
+	}
+	return http.StatusInternalServerError, codedErrorResponse(ErrCodeInternalError, nil).Error
 }
@@ -1,7 +1,13 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
 )
 
 func TestUnauthorizedResponse(t *testing.T) {
@@ -53,3 +59,49 @@ func TestNotAMemberResponse(t *testing.T) {
 		t.Errorf("expected message %q, got %q", "Not a member of this workspace", resp.Error.Message)
 	}
 }
+
+func TestOutOfRangeResponse(t *testing.T) {
+	resp := outOfRangeResponse("slow_mode_seconds", 0, 21600)
+	if resp.Error.Code != ErrCodeOutOfRange {
+		t.Errorf("expected code %q, got %q", ErrCodeOutOfRange, resp.Error.Code)
+	}
+	if resp.Error.Message != "slow_mode_seconds must be between 0 and 21600" {
+		t.Errorf("unexpected message %q", resp.Error.Message)
+	}
+	if resp.Error.Params == nil || (*resp.Error.Params)["field"] != "slow_mode_seconds" || (*resp.Error.Params)["max"] != 21600 {
+		t.Errorf("unexpected params %v", resp.Error.Params)
+	}
+}
+
+func TestLocalizeError(t *testing.T) {
+	e := tooLongResponse("default_notification_sound", 64).Error
+	LocalizeError("fr", &e)
+	if e.Message != "default_notification_sound ne doit pas dépasser 64 caractères" {
+		t.Errorf("expected a French message, got %q", e.Message)
+	}
+
+	// A message written for the occasion is not replaced by the catalog text
+	e = openapi.ApiError{Code: ErrCodeFileQuarantined, Message: "Attachment abc failed the virus scan"}
+	LocalizeError("de", &e)
+	if e.Message != "Attachment abc failed the virus scan" {
+		t.Errorf("expected a hand-written message to be kept, got %q", e.Message)
+	}
+
+	e = unauthorizedResponse().Error
+	LocalizeError("ja", &e)
+	if e.Message != "認証されていません" {
+		t.Errorf("expected a Japanese message, got %q", e.Message)
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	status, apiErr := ErrorResponse(fmt.Errorf("loading channel: %w", channel.ErrChannelNotFound))
+	if status != http.StatusNotFound || apiErr.Code != ErrCodeNotFound || apiErr.Message != "Channel not found" {
+		t.Errorf("wrapped sentinel = %d %+v, want 404 Channel not found", status, apiErr)
+	}
+
+	status, apiErr = ErrorResponse(errors.New("sqlite: no such column: secret"))
+	if status != http.StatusInternalServerError || apiErr.Code != ErrCodeInternalError || apiErr.Message != "An internal error occurred" {
+		t.Errorf("unknown error = %d %+v, want a generic 500", status, apiErr)
+	}
+}
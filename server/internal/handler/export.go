@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/export"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// CreateWorkspaceExport queues a full export of a workspace
+func (h *Handler) CreateWorkspaceExport(ctx context.Context, request openapi.CreateWorkspaceExportRequestObject) (openapi.CreateWorkspaceExportResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateWorkspaceExport401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil || membership.Role != workspace.RoleOwner {
+		return openapi.CreateWorkspaceExport403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only workspace owners can export workspace data")}, nil
+	}
+
+	// Archives are kept in file storage until they are downloaded
+	if h.storage == nil {
+		return openapi.CreateWorkspaceExport403JSONResponse{ForbiddenJSONResponse: filesDisabledResponse()}, nil
+	}
+
+	active, err := h.exportRepo.HasActive(ctx, string(request.Wid))
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return openapi.CreateWorkspaceExport409JSONResponse{ConflictJSONResponse: conflictResponse("An export of this workspace is already in progress")}, nil
+	}
+
+	e := &export.Export{
+		WorkspaceID: string(request.Wid),
+		RequestedBy: &userID,
+	}
+	if err := h.exportRepo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+
+	return openapi.CreateWorkspaceExport202JSONResponse(exportToAPI(e)), nil
+}
+
+// CreateChannelExport queues an export of a channel's messages as JSON or CSV
+func (h *Handler) CreateChannelExport(ctx context.Context, request openapi.CreateChannelExportRequestObject) (openapi.CreateChannelExportResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateChannelExport401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	format := export.FormatJSON
+	if request.Body.Format != nil {
+		format = string(*request.Body.Format)
+	}
+	if format != export.FormatJSON && format != export.FormatCSV {
+		return openapi.CreateChannelExport400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Format must be json or csv")}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.CreateChannelExport404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	allowed, err := h.canExportChannel(ctx, userID, ch)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return openapi.CreateChannelExport403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only channel admins and workspace admins can export a channel")}, nil
+	}
+
+	// Exports are kept in file storage until they are downloaded
+	if h.storage == nil {
+		return openapi.CreateChannelExport403JSONResponse{ForbiddenJSONResponse: filesDisabledResponse()}, nil
+	}
+
+	active, err := h.exportRepo.HasActiveForChannel(ctx, ch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return openapi.CreateChannelExport409JSONResponse{ConflictJSONResponse: conflictResponse("An export of this channel is already in progress")}, nil
+	}
+
+	e := &export.Export{
+		WorkspaceID: ch.WorkspaceID,
+		ChannelID:   &ch.ID,
+		Format:      format,
+		RequestedBy: &userID,
+	}
+	if err := h.exportRepo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+
+	return openapi.CreateChannelExport202JSONResponse(exportToAPI(e)), nil
+}
+
+// GetWorkspaceExport returns the status of a workspace or channel export
+func (h *Handler) GetWorkspaceExport(ctx context.Context, request openapi.GetWorkspaceExportRequestObject) (openapi.GetWorkspaceExportResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetWorkspaceExport401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	e, err := h.getVisibleExport(ctx, request.Id, userID)
+	if err != nil {
+		if errors.Is(err, export.ErrExportNotFound) {
+			return openapi.GetWorkspaceExport404JSONResponse{NotFoundJSONResponse: notFoundResponse("Export not found")}, nil
+		}
+		return nil, err
+	}
+
+	return openapi.GetWorkspaceExport200JSONResponse(exportToAPI(e)), nil
+}
+
+// DownloadWorkspaceExport streams a completed export file
+func (h *Handler) DownloadWorkspaceExport(ctx context.Context, request openapi.DownloadWorkspaceExportRequestObject) (openapi.DownloadWorkspaceExportResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DownloadWorkspaceExport401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	e, err := h.getVisibleExport(ctx, request.Id, userID)
+	if err != nil {
+		if errors.Is(err, export.ErrExportNotFound) {
+			return openapi.DownloadWorkspaceExport404JSONResponse{NotFoundJSONResponse: notFoundResponse("Export not found")}, nil
+		}
+		return nil, err
+	}
+	if e.Status != export.StatusCompleted || (e.ExpiresAt != nil && time.Now().After(*e.ExpiresAt)) || h.storage == nil {
+		return openapi.DownloadWorkspaceExport404JSONResponse{NotFoundJSONResponse: notFoundResponse("Export is not available for download")}, nil
+	}
+
+	// For S3 storage, redirect to a pre-signed URL instead of proxying
+	s3URL, err := h.storage.SignedURL(ctx, e.StoragePath, signedURLTTL)
+	if err == nil && s3URL != "" {
+		return openapi.DownloadWorkspaceExport302Response{
+			Headers: openapi.DownloadWorkspaceExport302ResponseHeaders{Location: s3URL},
+		}, nil
+	}
+
+	rc, err := h.storage.Get(ctx, e.StoragePath)
+	if err != nil {
+		return openapi.DownloadWorkspaceExport404JSONResponse{NotFoundJSONResponse: notFoundResponse("Export is not available for download")}, nil
+	}
+
+	headers := openapi.DownloadWorkspaceExport200ResponseHeaders{
+		ContentDisposition: `attachment; filename="enzyme-export-` + e.ID + e.Extension() + `"`,
+	}
+	switch e.Format {
+	case export.FormatJSON:
+		return openapi.DownloadWorkspaceExport200ApplicationjsonResponse{Body: rc, ContentLength: e.SizeBytes, Headers: headers}, nil
+	case export.FormatCSV:
+		return openapi.DownloadWorkspaceExport200TextcsvResponse{Body: rc, ContentLength: e.SizeBytes, Headers: headers}, nil
+	}
+	return openapi.DownloadWorkspaceExport200ApplicationzipResponse{Body: rc, ContentLength: e.SizeBytes, Headers: headers}, nil
+}
+
+// getVisibleExport loads an export, treating exports the user cannot see as
+// not found. Workspace exports are visible to workspace owners, and channel
+// exports to anyone who could export the channel now.
+func (h *Handler) getVisibleExport(ctx context.Context, id, userID string) (*export.Export, error) {
+	e, err := h.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.ChannelID != nil {
+		ch, err := h.channelRepo.GetByID(ctx, *e.ChannelID)
+		if err != nil {
+			if errors.Is(err, channel.ErrChannelNotFound) {
+				return nil, export.ErrExportNotFound
+			}
+			return nil, err
+		}
+		allowed, err := h.canExportChannel(ctx, userID, ch)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, export.ErrExportNotFound
+		}
+		return e, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, e.WorkspaceID)
+	if err != nil || membership.Role != workspace.RoleOwner {
+		return nil, export.ErrExportNotFound
+	}
+	return e, nil
+}
+
+// canExportChannel reports whether the user may export a channel's messages.
+// Channel admins can, and so can workspace admins, though only for private
+// channels and DMs they belong to. Workspace owners, who can export the whole
+// workspace anyway, can export any channel.
+func (h *Handler) canExportChannel(ctx context.Context, userID string, ch *channel.Channel) (bool, error) {
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return false, nil
+		}
+		return false, err
+	}
+	if membership.Role == workspace.RoleOwner {
+		return true, nil
+	}
+
+	channelMembership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if err != nil && !errors.Is(err, channel.ErrNotChannelMember) {
+		return false, err
+	}
+	if workspace.CanManageMembers(membership.Role) && (ch.Type == channel.TypePublic || channelMembership != nil) {
+		return true, nil
+	}
+	return channelMembership != nil && channel.CanManageChannel(channelMembership.ChannelRole), nil
+}
+
+func exportToAPI(e *export.Export) openapi.WorkspaceExport {
+	apiExport := openapi.WorkspaceExport{
+		Id:               e.ID,
+		WorkspaceId:      e.WorkspaceID,
+		ChannelId:        e.ChannelID,
+		Format:           openapi.WorkspaceExportFormat(e.Format),
+		RequestedBy:      e.RequestedBy,
+		Status:           openapi.WorkspaceExportStatus(e.Status),
+		SizeBytes:        e.SizeBytes,
+		MessagesTotal:    e.MessagesTotal,
+		MessagesExported: e.MessagesExported,
+		ExpiresAt:        e.ExpiresAt,
+		CompletedAt:      e.CompletedAt,
+		CreatedAt:        e.CreatedAt,
+	}
+	if e.LastError != "" {
+		apiExport.LastError = &e.LastError
+	}
+	return apiExport
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestCreateWorkspaceExport(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	admin := testutil.CreateTestUser(t, db, "admin@test.com", "Admin")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, admin.ID, ws.ID, "admin")
+
+	// Admins are not enough; exports contain private channels and DMs
+	resp, err := h.CreateWorkspaceExport(ctxWithUser(t, h, admin.ID), openapi.CreateWorkspaceExportRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("CreateWorkspaceExport: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateWorkspaceExport403JSONResponse); !ok {
+		t.Fatalf("expected 403 for admin, got %T", resp)
+	}
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	resp, err = h.CreateWorkspaceExport(ownerCtx, openapi.CreateWorkspaceExportRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("CreateWorkspaceExport: %v", err)
+	}
+	created, ok := resp.(openapi.CreateWorkspaceExport202JSONResponse)
+	if !ok {
+		t.Fatalf("expected 202 response, got %T", resp)
+	}
+	if created.Status != openapi.WorkspaceExportStatusPending {
+		t.Errorf("expected pending export, got %s", created.Status)
+	}
+
+	resp, err = h.CreateWorkspaceExport(ownerCtx, openapi.CreateWorkspaceExportRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("CreateWorkspaceExport: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateWorkspaceExport409JSONResponse); !ok {
+		t.Fatalf("expected 409 while an export is pending, got %T", resp)
+	}
+}
+
+func TestGetWorkspaceExport_OwnerOnly(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	resp, err := h.CreateWorkspaceExport(ctxWithUser(t, h, owner.ID), openapi.CreateWorkspaceExportRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("CreateWorkspaceExport: %v", err)
+	}
+	created := resp.(openapi.CreateWorkspaceExport202JSONResponse)
+
+	getResp, err := h.GetWorkspaceExport(ctxWithUser(t, h, owner.ID), openapi.GetWorkspaceExportRequestObject{Id: created.Id})
+	if err != nil {
+		t.Fatalf("GetWorkspaceExport: %v", err)
+	}
+	if _, ok := getResp.(openapi.GetWorkspaceExport200JSONResponse); !ok {
+		t.Fatalf("expected 200 for owner, got %T", getResp)
+	}
+
+	getResp, err = h.GetWorkspaceExport(ctxWithUser(t, h, member.ID), openapi.GetWorkspaceExportRequestObject{Id: created.Id})
+	if err != nil {
+		t.Fatalf("GetWorkspaceExport: %v", err)
+	}
+	if _, ok := getResp.(openapi.GetWorkspaceExport404JSONResponse); !ok {
+		t.Fatalf("expected 404 for non-owner, got %T", getResp)
+	}
+
+	// Not downloadable until the archive has been built
+	dlResp, err := h.DownloadWorkspaceExport(ctxWithUser(t, h, owner.ID), openapi.DownloadWorkspaceExportRequestObject{Id: created.Id})
+	if err != nil {
+		t.Fatalf("DownloadWorkspaceExport: %v", err)
+	}
+	if _, ok := dlResp.(openapi.DownloadWorkspaceExport404JSONResponse); !ok {
+		t.Fatalf("expected 404 for pending export, got %T", dlResp)
+	}
+}
+
+func TestCreateChannelExport_Permissions(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	admin := testutil.CreateTestUser(t, db, "admin@test.com", "Admin")
+	chanAdmin := testutil.CreateTestUser(t, db, "chanadmin@test.com", "Channel Admin")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, admin.ID, ws.ID, "admin")
+	addWorkspaceMember(t, db, chanAdmin.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	public := testutil.CreateTestChannel(t, db, ws.ID, chanAdmin.ID, "general", "public")
+	addChannelMember(t, db, member.ID, public.ID, nil)
+	private := testutil.CreateTestChannel(t, db, ws.ID, chanAdmin.ID, "secret", "private")
+
+	csv := openapi.CreateChannelExportInputFormatCsv
+	create := func(userID, channelID string) openapi.CreateChannelExportResponseObject {
+		t.Helper()
+		resp, err := h.CreateChannelExport(ctxWithUser(t, h, userID), openapi.CreateChannelExportRequestObject{
+			Id:   openapi.ChannelId(channelID),
+			Body: &openapi.CreateChannelExportJSONRequestBody{Format: &csv},
+		})
+		if err != nil {
+			t.Fatalf("CreateChannelExport: %v", err)
+		}
+		return resp
+	}
+
+	resp := create(member.ID, public.ID)
+	if _, ok := resp.(openapi.CreateChannelExport403JSONResponse); !ok {
+		t.Errorf("expected 403 for a plain member, got %T", resp)
+	}
+	resp = create(chanAdmin.ID, public.ID)
+	if _, ok := resp.(openapi.CreateChannelExport202JSONResponse); !ok {
+		t.Fatalf("expected 202 for the channel admin, got %T", resp)
+	}
+	resp = create(admin.ID, public.ID)
+	if _, ok := resp.(openapi.CreateChannelExport409JSONResponse); !ok {
+		t.Errorf("expected 409 while the channel export is pending, got %T", resp)
+	}
+
+	// Workspace admins need to be in a private channel to export it; owners
+	// do not
+	resp = create(admin.ID, private.ID)
+	if _, ok := resp.(openapi.CreateChannelExport403JSONResponse); !ok {
+		t.Errorf("expected 403 for a workspace admin outside the channel, got %T", resp)
+	}
+	resp = create(owner.ID, private.ID)
+	if _, ok := resp.(openapi.CreateChannelExport202JSONResponse); !ok {
+		t.Errorf("expected 202 for the workspace owner, got %T", resp)
+	}
+
+	// The channel admin's export can be seen by anyone else who could export
+	// the channel, but not by plain members
+	var exportID string
+	if err := db.QueryRow(`SELECT id FROM workspace_exports WHERE channel_id = ?`, public.ID).Scan(&exportID); err != nil {
+		t.Fatalf("finding export: %v", err)
+	}
+	getResp, err := h.GetWorkspaceExport(ctxWithUser(t, h, admin.ID), openapi.GetWorkspaceExportRequestObject{Id: exportID})
+	if err != nil {
+		t.Fatalf("GetWorkspaceExport: %v", err)
+	}
+	got, ok := getResp.(openapi.GetWorkspaceExport200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 for workspace admin, got %T", getResp)
+	}
+	if got.Format != openapi.WorkspaceExportFormatCsv || got.ChannelId == nil || *got.ChannelId != public.ID {
+		t.Errorf("expected a CSV export of the channel, got %+v", got)
+	}
+
+	getResp, err = h.GetWorkspaceExport(ctxWithUser(t, h, member.ID), openapi.GetWorkspaceExportRequestObject{Id: exportID})
+	if err != nil {
+		t.Fatalf("GetWorkspaceExport: %v", err)
+	}
+	if _, ok := getResp.(openapi.GetWorkspaceExport404JSONResponse); !ok {
+		t.Fatalf("expected 404 for a plain member, got %T", getResp)
+	}
+}
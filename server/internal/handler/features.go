@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/enzyme/server/internal/features"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// ListWorkspaceFeatures lists the feature flags in effect in a workspace
+func (h *Handler) ListWorkspaceFeatures(ctx context.Context, request openapi.ListWorkspaceFeaturesRequestObject) (openapi.ListWorkspaceFeaturesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListWorkspaceFeatures401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		return openapi.ListWorkspaceFeatures403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	flags, err := h.features.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	apiFlags := make([]openapi.WorkspaceFeature, len(flags))
+	for i, f := range flags {
+		apiFlags[i] = featureToAPI(f)
+	}
+	return openapi.ListWorkspaceFeatures200JSONResponse{Features: apiFlags}, nil
+}
+
+// SetWorkspaceFeature overrides a feature flag for a workspace
+func (h *Handler) SetWorkspaceFeature(ctx context.Context, request openapi.SetWorkspaceFeatureRequestObject) (openapi.SetWorkspaceFeatureResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SetWorkspaceFeature401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.SetWorkspaceFeature403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if membership.Role != workspace.RoleOwner && membership.Role != workspace.RoleAdmin {
+		return openapi.SetWorkspaceFeature403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can change feature flags")}, nil
+	}
+
+	flag, err := h.features.Set(ctx, workspaceID, request.Key, request.Body.Enabled)
+	if err != nil {
+		if errors.Is(err, features.ErrUnknownFlag) {
+			return openapi.SetWorkspaceFeature404JSONResponse{NotFoundJSONResponse: notFoundResponse("Feature flag not found")}, nil
+		}
+		return nil, err
+	}
+	h.auditFeatureChange(ctx, workspaceID, userID, flag)
+
+	return openapi.SetWorkspaceFeature200JSONResponse{Feature: featureToAPI(flag)}, nil
+}
+
+// ResetWorkspaceFeature removes a workspace's override of a feature flag
+func (h *Handler) ResetWorkspaceFeature(ctx context.Context, request openapi.ResetWorkspaceFeatureRequestObject) (openapi.ResetWorkspaceFeatureResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ResetWorkspaceFeature401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.ResetWorkspaceFeature403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if membership.Role != workspace.RoleOwner && membership.Role != workspace.RoleAdmin {
+		return openapi.ResetWorkspaceFeature403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can change feature flags")}, nil
+	}
+
+	flag, err := h.features.Reset(ctx, workspaceID, request.Key)
+	if err != nil {
+		if errors.Is(err, features.ErrUnknownFlag) {
+			return openapi.ResetWorkspaceFeature404JSONResponse{NotFoundJSONResponse: notFoundResponse("Feature flag not found")}, nil
+		}
+		return nil, err
+	}
+	h.auditFeatureChange(ctx, workspaceID, userID, flag)
+
+	return openapi.ResetWorkspaceFeature200JSONResponse{Feature: featureToAPI(flag)}, nil
+}
+
+// auditFeatureChange records the value a flag has after an admin changed it
+func (h *Handler) auditFeatureChange(ctx context.Context, workspaceID, userID string, flag features.Flag) {
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, moderation.ActionWorkspaceUpdated, moderation.TargetTypeWorkspace, workspaceID, map[string]interface{}{
+		"changes": map[string]interface{}{
+			"features." + flag.Key: flag.Enabled,
+		},
+	})
+}
+
+func featureToAPI(f features.Flag) openapi.WorkspaceFeature {
+	return openapi.WorkspaceFeature{
+		Key:         f.Key,
+		Description: f.Description,
+		Enabled:     f.Enabled,
+		Source:      openapi.WorkspaceFeatureSource(f.Source),
+	}
+}
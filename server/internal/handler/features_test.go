@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/features"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestWorkspaceFeatures(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+
+	resp, err := h.ListWorkspaceFeatures(memberCtx, openapi.ListWorkspaceFeaturesRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListWorkspaceFeatures: %v", err)
+	}
+	list, ok := resp.(openapi.ListWorkspaceFeatures200JSONResponse)
+	if !ok || len(list.Features) != len(features.Registry) {
+		t.Fatalf("expected every flag, got %#v", resp)
+	}
+	if f := list.Features[0]; f.Key != features.Calls || !f.Enabled || f.Source != openapi.WorkspaceFeatureSourceDefault {
+		t.Errorf("calls = %+v, want on by default", f)
+	}
+
+	resp, err = h.ListWorkspaceFeatures(ctxWithUser(t, h, outsider.ID), openapi.ListWorkspaceFeaturesRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListWorkspaceFeatures: %v", err)
+	}
+	if _, ok := resp.(openapi.ListWorkspaceFeatures403JSONResponse); !ok {
+		t.Errorf("non-member = %T, want 403", resp)
+	}
+
+	set := func(ctx context.Context, key string, enabled bool) openapi.SetWorkspaceFeatureResponseObject {
+		t.Helper()
+		resp, err := h.SetWorkspaceFeature(ctx, openapi.SetWorkspaceFeatureRequestObject{
+			Wid:  ws.ID,
+			Key:  key,
+			Body: &openapi.SetWorkspaceFeatureJSONRequestBody{Enabled: enabled},
+		})
+		if err != nil {
+			t.Fatalf("SetWorkspaceFeature: %v", err)
+		}
+		return resp
+	}
+	if _, ok := set(memberCtx, features.ThreadsV2, true).(openapi.SetWorkspaceFeature403JSONResponse); !ok {
+		t.Error("members must not change feature flags")
+	}
+	if _, ok := set(ownerCtx, "teleport", true).(openapi.SetWorkspaceFeature404JSONResponse); !ok {
+		t.Error("expected 404 for an unknown flag")
+	}
+	setResp, ok := set(ownerCtx, features.ThreadsV2, true).(openapi.SetWorkspaceFeature200JSONResponse)
+	if !ok || !setResp.Feature.Enabled || setResp.Feature.Source != openapi.WorkspaceFeatureSourceWorkspace {
+		t.Fatalf("set = %#v, want a workspace override", setResp)
+	}
+	if !h.features.Enabled(context.Background(), ws.ID, features.ThreadsV2) {
+		t.Error("expected the override to take effect")
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE workspace_id = ? AND metadata LIKE '%features.threads_v2%'`, ws.ID).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected an audit log entry, got %d", count)
+	}
+
+	resetResp, err := h.ResetWorkspaceFeature(ownerCtx, openapi.ResetWorkspaceFeatureRequestObject{Wid: ws.ID, Key: features.ThreadsV2})
+	if err != nil {
+		t.Fatalf("ResetWorkspaceFeature: %v", err)
+	}
+	reset, ok := resetResp.(openapi.ResetWorkspaceFeature200JSONResponse)
+	if !ok || reset.Feature.Enabled || reset.Feature.Source != openapi.WorkspaceFeatureSourceDefault {
+		t.Errorf("reset = %#v, want the default", resetResp)
+	}
+}
+
+func TestCall_FeatureDisabled(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	startResp, err := h.StartCall(ownerCtx, openapi.StartCallRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("StartCall: %v", err)
+	}
+	started, ok := startResp.(openapi.StartCall200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %#v", startResp)
+	}
+
+	// Turning calls off stops new calls and joining ones in progress
+	if _, err := h.features.Set(context.Background(), ws.ID, features.Calls, false); err != nil {
+		t.Fatal(err)
+	}
+	joinResp, err := h.JoinCall(ctxWithUser(t, h, member.ID), openapi.JoinCallRequestObject{Id: started.Call.Id})
+	if err != nil {
+		t.Fatalf("JoinCall: %v", err)
+	}
+	if r, ok := joinResp.(openapi.JoinCall403JSONResponse); !ok || r.Error.Code != ErrCodeFeatureDisabled {
+		t.Errorf("join = %#v, want 403 FEATURE_DISABLED", joinResp)
+	}
+
+	ch2 := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "random", channel.TypePublic)
+	startResp, err = h.StartCall(ownerCtx, openapi.StartCallRequestObject{Id: ch2.ID})
+	if err != nil {
+		t.Fatalf("StartCall: %v", err)
+	}
+	if r, ok := startResp.(openapi.StartCall403JSONResponse); !ok || r.Error.Code != ErrCodeFeatureDisabled {
+		t.Errorf("start = %#v, want 403 FEATURE_DISABLED", startResp)
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// messageFieldNames are the message properties a fields parameter may name,
+// taken from the API type so they follow the spec.
+var messageFieldNames = func() map[string]bool {
+	names := make(map[string]bool)
+	t := reflect.TypeOf(openapi.MessageWithUser{})
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// parseMessageFields parses a comma-separated fields parameter into the
+// message properties to keep. It returns nil when no trimming was asked for,
+// and the first name that isn't a message property if there is one.
+func parseMessageFields(param *openapi.MessageFields) (fields []string, unknown string) {
+	if param == nil || strings.TrimSpace(*param) == "" {
+		return nil, ""
+	}
+	fields = []string{"id"}
+	for _, name := range strings.Split(*param, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "id" {
+			continue
+		}
+		if !messageFieldNames[name] {
+			return nil, name
+		}
+		fields = append(fields, name)
+	}
+	return fields, ""
+}
+
+// projectedMessageList is a message list response with each message trimmed
+// to the requested fields. It serves both GET listing operations.
+type projectedMessageList struct {
+	result openapi.MessageListResult
+	fields []string
+}
+
+func (r projectedMessageList) VisitGetChannelMessagesResponse(w http.ResponseWriter) error {
+	return r.write(w)
+}
+
+func (r projectedMessageList) VisitGetThreadRepliesResponse(w http.ResponseWriter) error {
+	return r.write(w)
+}
+
+func (r projectedMessageList) write(w http.ResponseWriter) error {
+	messages := make([]map[string]json.RawMessage, len(r.result.Messages))
+	for i, msg := range r.result.Messages {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		var full map[string]json.RawMessage
+		if err := json.Unmarshal(data, &full); err != nil {
+			return err
+		}
+		trimmed := make(map[string]json.RawMessage, len(r.fields))
+		for _, name := range r.fields {
+			if v, ok := full[name]; ok {
+				trimmed[name] = v
+			}
+		}
+		messages[i] = trimmed
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(struct {
+		Messages   []map[string]json.RawMessage `json:"messages"`
+		HasMore    bool                         `json:"has_more"`
+		HasNewer   *bool                        `json:"has_newer,omitempty"`
+		NextCursor *string                      `json:"next_cursor,omitempty"`
+	}{messages, r.result.HasMore, r.result.HasNewer, r.result.NextCursor})
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/enzyme/server/internal/channel"
 	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/signing"
 	"github.com/enzyme/server/internal/sse"
@@ -38,21 +39,17 @@ func (h *Handler) UploadFile(ctx context.Context, request openapi.UploadFileRequ
 		return nil, err
 	}
 
-	// Check channel membership
-	_, err = h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	denied, err := h.checkUploadAccess(ctx, userID, ch)
 	if err != nil {
-		if errors.Is(err, channel.ErrNotChannelMember) {
-			if ch.Type != channel.TypePublic {
-				return openapi.UploadFile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
-			}
-			// Verify workspace membership for public channels
-			_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
-			if err != nil {
-				return openapi.UploadFile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
+	}
+	if denied != "" {
+		return openapi.UploadFile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse(denied)}, nil
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.UploadFile503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
 	}
 
 	// Parse multipart form
@@ -74,11 +71,6 @@ func (h *Handler) UploadFile(ctx context.Context, request openapi.UploadFileRequ
 	storageKey := ch.WorkspaceID + "/" + string(request.Id) + "/" + fileID + ext
 
 	// Read content into buffer with size limit (one extra byte to detect oversized files)
-	contentType := part.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
 	data, err := io.ReadAll(io.LimitReader(part, h.maxUploadSize+1))
 	if err != nil {
 		return nil, err
@@ -90,6 +82,32 @@ func (h *Handler) UploadFile(ctx context.Context, request openapi.UploadFileRequ
 		return openapi.UploadFile400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "File too large")}, nil
 	}
 
+	fits, err := h.fitsStorageQuota(ctx, ch.WorkspaceID, size)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return openapi.UploadFile403JSONResponse{ForbiddenJSONResponse: quotaExceededResponse()}, nil
+	}
+
+	// The part's Content-Type header is the client's claim; use the bytes
+	contentType := file.DetectContentType(data[:min(len(data), file.SniffLen)], filename)
+	allowed, err := h.uploadTypeAllowed(ctx, ch.WorkspaceID, filename, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return openapi.UploadFile403JSONResponse{ForbiddenJSONResponse: fileTypeBlockedResponse()}, nil
+	}
+
+	data, width, height := processImage(data, contentType, filename)
+	size = int64(len(data))
+
+	status, err := h.scanUpload(ctx, bytes.NewReader(data), filename)
+	if err != nil {
+		return nil, err
+	}
+
 	// Upload to storage with known size
 	if err := h.storage.Put(ctx, storageKey, bytes.NewReader(data), size, contentType); err != nil {
 		return nil, err
@@ -103,6 +121,9 @@ func (h *Handler) UploadFile(ctx context.Context, request openapi.UploadFileRequ
 		ContentType: contentType,
 		SizeBytes:   size,
 		StoragePath: storageKey,
+		Status:      status,
+		Width:       width,
+		Height:      height,
 	}
 
 	if err := h.fileRepo.Create(ctx, attachment); err != nil {
@@ -112,19 +133,98 @@ func (h *Handler) UploadFile(ctx context.Context, request openapi.UploadFileRequ
 
 	return openapi.UploadFile200JSONResponse{
 		File: struct {
-			ContentType string `json:"content_type"`
-			Filename    string `json:"filename"`
-			Id          string `json:"id"`
-			Size        int    `json:"size"`
+			ContentType string                   `json:"content_type"`
+			Filename    string                   `json:"filename"`
+			Id          string                   `json:"id"`
+			Size        int                      `json:"size"`
+			Status      openapi.AttachmentStatus `json:"status"`
 		}{
 			Id:          attachment.ID,
 			Filename:    attachment.Filename,
 			Size:        int(size),
 			ContentType: attachment.ContentType,
+			Status:      openapi.AttachmentStatus(attachment.Status),
 		},
 	}, nil
 }
 
+// processImage strips identifying metadata from an image upload, turns it
+// upright and measures it. Other files, and images that fail to parse, are
+// returned unchanged without dimensions.
+func processImage(data []byte, contentType, filename string) ([]byte, *int, *int) {
+	img, err := file.ProcessImage(data, contentType)
+	if err != nil {
+		slog.Warn("failed to process image upload", "filename", filename, "content_type", contentType, "error", err)
+		return data, nil, nil
+	}
+	if img == nil {
+		return data, nil, nil
+	}
+	return img.Data, &img.Width, &img.Height
+}
+
+// checkUploadAccess reports why userID may not upload to ch, or "" if they
+// may. Members can upload to any channel; other workspace members only to
+// public channels.
+func (h *Handler) checkUploadAccess(ctx context.Context, userID string, ch *channel.Channel) (string, error) {
+	_, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if err == nil {
+		return "", nil
+	}
+	if !errors.Is(err, channel.ErrNotChannelMember) {
+		return "", err
+	}
+	if ch.Type != channel.TypePublic {
+		return "Not a member of this channel", nil
+	}
+	// Verify workspace membership for public channels
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID); err != nil {
+		return "Not a member of this workspace", nil
+	}
+	return "", nil
+}
+
+// fitsStorageQuota reports whether size more bytes of attachments fit within
+// the workspace's storage quota.
+func (h *Handler) fitsStorageQuota(ctx context.Context, workspaceID string, size int64) (bool, error) {
+	if h.workspaceQuota <= 0 {
+		return true, nil
+	}
+	usage, err := h.fileRepo.WorkspaceUsage(ctx, workspaceID)
+	if err != nil {
+		return false, err
+	}
+	return usage.Bytes+size <= h.workspaceQuota, nil
+}
+
+// uploadTypeAllowed reports whether the workspace's upload allowlist and
+// denylist accept a file with this name and content type.
+func (h *Handler) uploadTypeAllowed(ctx context.Context, workspaceID, filename, contentType string) (bool, error) {
+	ws, err := h.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return false, err
+	}
+	return ws.ParsedSettings().AllowsUpload(filename, contentType), nil
+}
+
+// scanUpload runs the virus scanner, when one is configured, over an upload
+// and returns the status to create its attachment with. A failed scan is an
+// error rather than a pass, so nothing becomes downloadable unscanned.
+func (h *Handler) scanUpload(ctx context.Context, r io.Reader, filename string) (string, error) {
+	if h.scanner == nil {
+		return file.StatusReady, nil
+	}
+	result, err := h.scanner.Scan(ctx, r)
+	if err != nil {
+		return "", fmt.Errorf("scanning upload: %w", err)
+	}
+	if result.Infected {
+		slog.Warn("upload quarantined", "filename", filename, "signature", result.Signature)
+		return file.StatusQuarantined, nil
+	}
+	return file.StatusReady, nil
+}
+
 // downloadFileRedirectResponse implements DownloadFileResponseObject with a 302 redirect.
 type downloadFileRedirectResponse struct {
 	url string
@@ -161,7 +261,13 @@ func (h *Handler) DownloadFile(ctx context.Context, request openapi.DownloadFile
 		if errors.Is(err, file.ErrAttachmentNotFound) {
 			return openapi.DownloadFile404JSONResponse{NotFoundJSONResponse: notFoundResponse("File not found")}, nil
 		}
-		return openapi.DownloadFile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		if isAccessDenied(err) {
+			return openapi.DownloadFile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+	if attachment.Status == file.StatusQuarantined {
+		return openapi.DownloadFile403JSONResponse{ForbiddenJSONResponse: fileQuarantinedResponse()}, nil
 	}
 
 	if h.storage == nil {
@@ -195,6 +301,9 @@ func (h *Handler) DeleteFile(ctx context.Context, request openapi.DeleteFileRequ
 
 	attachment, err := h.fileRepo.GetByID(ctx, request.Id)
 	if err != nil {
+		if errors.Is(err, file.ErrAttachmentNotFound) {
+			return openapi.DeleteFile404JSONResponse{NotFoundJSONResponse: notFoundResponse("File not found")}, nil
+		}
 		return nil, err
 	}
 
@@ -237,17 +346,45 @@ func (h *Handler) DeleteFile(ctx context.Context, request openapi.DeleteFileRequ
 			if h.hub != nil {
 				if sch, err := h.channelRepo.GetByID(ctx, smsg.ChannelID); err == nil {
 					apiMsg := scheduledMessageToAPI(&smsg)
-					h.hub.BroadcastToUser(sch.WorkspaceID, smsg.UserID, sse.NewScheduledMessageUpdatedEvent(apiMsg))
+					h.hub.BroadcastToUser(ctx, sch.WorkspaceID, smsg.UserID, sse.NewScheduledMessageUpdatedEvent(apiMsg))
 				}
 			}
 		}
 	}
 
+	// Clients render attachments inline, so resend the message without it
+	if attachment.MessageID != nil && h.hub != nil {
+		if msgWithUser, err := h.messageRepo.GetByIDWithUser(ctx, *attachment.MessageID); err == nil {
+			attachments, _ := h.fileRepo.ListForMessage(ctx, msgWithUser.ID)
+			msgWithUser.Attachments = attachments
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageUpdatedEvent(messageWithUserToAPI(msgWithUser)))
+		}
+	}
+
 	return openapi.DeleteFile200JSONResponse{
 		Success: true,
 	}, nil
 }
 
+// RemoveFile is DeleteFile under the DELETE method
+func (h *Handler) RemoveFile(ctx context.Context, request openapi.RemoveFileRequestObject) (openapi.RemoveFileResponseObject, error) {
+	resp, err := h.DeleteFile(ctx, openapi.DeleteFileRequestObject{Id: request.Id})
+	if err != nil {
+		return nil, err
+	}
+	switch r := resp.(type) {
+	case openapi.DeleteFile200JSONResponse:
+		return openapi.RemoveFile200JSONResponse(r), nil
+	case openapi.DeleteFile401JSONResponse:
+		return openapi.RemoveFile401JSONResponse(r), nil
+	case openapi.DeleteFile403JSONResponse:
+		return openapi.RemoveFile403JSONResponse(r), nil
+	case openapi.DeleteFile404JSONResponse:
+		return openapi.RemoveFile404JSONResponse(r), nil
+	}
+	return nil, fmt.Errorf("unexpected DeleteFile response %T", resp)
+}
+
 const signedURLTTL = time.Hour
 
 // SignFileUrl generates a signed download URL for a single file.
@@ -263,7 +400,13 @@ func (h *Handler) SignFileUrl(ctx context.Context, request openapi.SignFileUrlRe
 		if errors.Is(err, file.ErrAttachmentNotFound) {
 			return openapi.SignFileUrl404JSONResponse{NotFoundJSONResponse: notFoundResponse("File not found")}, nil
 		}
-		return openapi.SignFileUrl403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+		if isAccessDenied(err) {
+			return openapi.SignFileUrl403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+		}
+		return nil, err
+	}
+	if attachment.Status == file.StatusQuarantined {
+		return openapi.SignFileUrl403JSONResponse{ForbiddenJSONResponse: fileQuarantinedResponse()}, nil
 	}
 
 	url, expiresAt, err := h.signFileURL(ctx, attachment, userID)
@@ -291,9 +434,15 @@ func (h *Handler) SignFileUrls(ctx context.Context, request openapi.SignFileUrls
 
 	urls := make([]openapi.SignedUrl, 0, len(request.Body.FileIds))
 	for _, fileID := range request.Body.FileIds {
-		// Skip files the user doesn't have access to
+		// Skip files the user doesn't have access to and quarantined files
 		attachment, err := h.checkFileAccess(ctx, fileID, userID)
 		if err != nil {
+			if errors.Is(err, file.ErrAttachmentNotFound) || isAccessDenied(err) {
+				continue
+			}
+			return nil, err
+		}
+		if attachment.Status == file.StatusQuarantined {
 			continue
 		}
 		url, expiresAt, err := h.signFileURL(ctx, attachment, userID)
@@ -311,7 +460,8 @@ func (h *Handler) SignFileUrls(ctx context.Context, request openapi.SignFileUrls
 }
 
 // checkFileAccess verifies the user has access to the file's channel and returns the attachment.
-// Returns the attachment if access is granted, or an error (including file.ErrAttachmentNotFound).
+// Returns the attachment if access is granted, or an error: file.ErrAttachmentNotFound,
+// channel.ErrNotChannelMember or workspace.ErrNotAMember when access is refused.
 func (h *Handler) checkFileAccess(ctx context.Context, fileID, userID string) (*file.Attachment, error) {
 	attachment, err := h.fileRepo.GetByID(ctx, fileID)
 	if err != nil {
@@ -323,21 +473,34 @@ func (h *Handler) checkFileAccess(ctx context.Context, fileID, userID string) (*
 		return nil, err
 	}
 
-	_, err = h.channelRepo.GetMembership(ctx, userID, attachment.ChannelID)
+	membership, err := h.channelRepo.GetMembership(ctx, userID, attachment.ChannelID)
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			if ch.Type != channel.TypePublic {
-				return nil, fmt.Errorf("not a member of this channel")
+				return nil, err
 			}
 			// Verify workspace membership for public channels
-			_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
-			if err != nil {
-				return nil, fmt.Errorf("not a member of this workspace")
+			if _, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID); err != nil {
+				return nil, err
 			}
 			return attachment, nil
 		}
 		return nil, err
 	}
+
+	// Files posted before the user joined may be hidden by the channel's
+	// history policy, like the messages they were posted in
+	if since := ch.HistoryVisibleSince(membership.CreatedAt); since != nil {
+		postedAt := attachment.CreatedAt
+		if attachment.MessageID != nil {
+			if msg, err := h.messageRepo.GetByID(ctx, *attachment.MessageID); err == nil {
+				postedAt = msg.CreatedAt
+			}
+		}
+		if postedAt.Before(*since) {
+			return nil, file.ErrAttachmentNotFound
+		}
+	}
 	return attachment, nil
 }
 
@@ -397,3 +560,101 @@ func sanitizeFilename(filename string) string {
 	}
 	return filename
 }
+
+// ListChannelFiles lists the files posted in a channel for its Files tab
+func (h *Handler) ListChannelFiles(ctx context.Context, request openapi.ListChannelFilesRequestObject) (openapi.ListChannelFilesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListChannelFiles401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.ListChannelFiles404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.ListChannelFiles403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+	}
+
+	opts := fileListOptions(request.Params.Type, request.Params.Sort, request.Params.Limit, request.Params.Offset)
+	if membership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID); err == nil {
+		opts.VisibleSince = ch.HistoryVisibleSince(membership.CreatedAt)
+	}
+	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
+	entries, total, err := h.fileRepo.ListForChannel(ctx, ch.ID, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.ListChannelFiles200JSONResponse(fileListResultToAPI(entries, total, opts)), nil
+}
+
+// ListWorkspaceFiles lists the files in every channel of the workspace the
+// caller can read
+func (h *Handler) ListWorkspaceFiles(ctx context.Context, request openapi.ListWorkspaceFilesRequestObject) (openapi.ListWorkspaceFilesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListWorkspaceFiles401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid)); err != nil {
+		return openapi.ListWorkspaceFiles403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	opts := fileListOptions(request.Params.Type, request.Params.Sort, request.Params.Limit, request.Params.Offset)
+	filter := &moderation.FilterOptions{WorkspaceID: string(request.Wid), RequestingUserID: userID}
+	entries, total, err := h.fileRepo.ListForWorkspace(ctx, string(request.Wid), userID, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.ListWorkspaceFiles200JSONResponse(fileListResultToAPI(entries, total, opts)), nil
+}
+
+func fileListOptions(fileType *openapi.FileType, sort *openapi.FileSort, limit, offset *int) file.ListOptions {
+	opts := file.ListOptions{Sort: file.SortNewest}
+	if fileType != nil {
+		opts.Type = string(*fileType)
+	}
+	if sort != nil {
+		opts.Sort = string(*sort)
+	}
+	if limit != nil {
+		opts.Limit = *limit
+	}
+	if offset != nil {
+		opts.Offset = *offset
+	}
+	return opts
+}
+
+func fileListResultToAPI(entries []file.Entry, total int, opts file.ListOptions) openapi.FileListResult {
+	files := make([]openapi.FileListEntry, len(entries))
+	for i := range entries {
+		e := &entries[i]
+		files[i] = openapi.FileListEntry{
+			File:            attachmentToAPI(&e.Attachment),
+			ChannelId:       e.ChannelID,
+			ChannelName:     e.ChannelName,
+			ThreadParentId:  e.ThreadParentID,
+			UserId:          e.UserID,
+			UserDisplayName: e.UserDisplayName,
+			UserAvatarUrl:   e.UserAvatarURL,
+		}
+		if e.MessageID != nil {
+			files[i].MessageId = *e.MessageID
+		}
+	}
+	return openapi.FileListResult{
+		Files:      files,
+		TotalCount: total,
+		HasMore:    opts.Offset+len(entries) < total,
+	}
+}
+
+// isAccessDenied reports whether err from checkFileAccess means the user may
+// not see the file, as opposed to a failure looking it up.
+func isAccessDenied(err error) bool {
+	return errors.Is(err, channel.ErrNotChannelMember) || errors.Is(err, workspace.ErrNotAMember) || errors.Is(err, channel.ErrChannelNotFound)
+}
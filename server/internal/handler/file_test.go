@@ -1,10 +1,20 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/enzyme/server/internal/antivirus"
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/file"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/testutil"
 )
@@ -131,6 +141,49 @@ func TestDeleteFile_Unauthenticated(t *testing.T) {
 	}
 }
 
+func TestDeleteFile_NotFound(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	resp, err := h.DeleteFile(ctxWithUser(t, h, user.ID), openapi.DeleteFileRequestObject{Id: "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.DeleteFile404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestRemoveFile_Linked(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "see attached")
+
+	fileID := createFileAttachment(t, db, ch.ID, user.ID)
+	if err := h.fileRepo.UpdateMessageID(context.Background(), fileID, msg.ID); err != nil {
+		t.Fatalf("linking attachment: %v", err)
+	}
+
+	resp, err := h.RemoveFile(ctxWithUser(t, h, user.ID), openapi.RemoveFileRequestObject{Id: fileID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RemoveFile200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	attachments, err := h.fileRepo.ListForMessage(context.Background(), msg.ID)
+	if err != nil {
+		t.Fatalf("ListForMessage: %v", err)
+	}
+	if len(attachments) != 0 {
+		t.Errorf("expected message to have no attachments, got %d", len(attachments))
+	}
+}
+
 func TestDownloadFile_StorageDisabled(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -200,3 +253,679 @@ func TestDeleteFile_StorageDisabled(t *testing.T) {
 		t.Fatalf("expected 200 response (DB record deleted even if storage off), got %T", resp)
 	}
 }
+
+func TestResumableUpload_Flow(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	content := "hello, resumable world"
+	createResp, err := h.CreateUpload(ctx, openapi.CreateUploadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.CreateUploadJSONRequestBody{Filename: "notes.txt", Size: int64(len(content))},
+	})
+	if err != nil {
+		t.Fatalf("CreateUpload: %v", err)
+	}
+	session, ok := createResp.(openapi.CreateUpload201JSONResponse)
+	if !ok {
+		t.Fatalf("expected 201 response, got %T", createResp)
+	}
+
+	appendChunk := func(offset int64, data string) openapi.AppendUploadResponseObject {
+		t.Helper()
+		resp, err := h.AppendUpload(ctx, openapi.AppendUploadRequestObject{
+			Id:     session.Id,
+			Params: openapi.AppendUploadParams{Offset: offset},
+			Body:   strings.NewReader(data),
+		})
+		if err != nil {
+			t.Fatalf("AppendUpload: %v", err)
+		}
+		return resp
+	}
+
+	if resp, ok := appendChunk(0, content[:10]).(openapi.AppendUpload200JSONResponse); !ok || resp.Offset != 10 {
+		t.Fatalf("expected offset 10 after first chunk, got %+v", resp)
+	}
+	// Retrying the first chunk is rejected
+	stale := appendChunk(0, content[:10])
+	if _, ok := stale.(openapi.AppendUpload409JSONResponse); !ok {
+		t.Fatalf("expected 409 for stale offset, got %T", stale)
+	}
+
+	// Completing early is rejected
+	completeResp, err := h.CompleteUpload(ctx, openapi.CompleteUploadRequestObject{Id: session.Id})
+	if err != nil {
+		t.Fatalf("CompleteUpload: %v", err)
+	}
+	if _, ok := completeResp.(openapi.CompleteUpload400JSONResponse); !ok {
+		t.Fatalf("expected 400 for incomplete upload, got %T", completeResp)
+	}
+
+	getResp, err := h.GetUpload(ctx, openapi.GetUploadRequestObject{Id: session.Id})
+	if err != nil {
+		t.Fatalf("GetUpload: %v", err)
+	}
+	if progress, ok := getResp.(openapi.GetUpload200JSONResponse); !ok || progress.Offset != 10 {
+		t.Fatalf("expected resumable offset 10, got %+v", getResp)
+	}
+
+	if resp, ok := appendChunk(10, content[10:]).(openapi.AppendUpload200JSONResponse); !ok || resp.Offset != int64(len(content)) {
+		t.Fatalf("expected upload to be fully received, got %+v", resp)
+	}
+
+	completeResp, err = h.CompleteUpload(ctx, openapi.CompleteUploadRequestObject{Id: session.Id})
+	if err != nil {
+		t.Fatalf("CompleteUpload: %v", err)
+	}
+	done, ok := completeResp.(openapi.CompleteUpload200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", completeResp)
+	}
+	if done.File.Filename != "notes.txt" || done.File.Size != len(content) {
+		t.Fatalf("unexpected file: %+v", done.File)
+	}
+
+	attachment, err := h.fileRepo.GetByID(context.Background(), done.File.Id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	rc, err := h.storage.Get(context.Background(), attachment.StoragePath)
+	if err != nil {
+		t.Fatalf("reading assembled file: %v", err)
+	}
+	defer rc.Close()
+	got, _ := io.ReadAll(rc)
+	if string(got) != content {
+		t.Fatalf("expected assembled content %q, got %q", content, got)
+	}
+
+	// The session is gone once completed
+	getResp, err = h.GetUpload(ctx, openapi.GetUploadRequestObject{Id: session.Id})
+	if err != nil {
+		t.Fatalf("GetUpload: %v", err)
+	}
+	if _, ok := getResp.(openapi.GetUpload404JSONResponse); !ok {
+		t.Fatalf("expected 404 after completion, got %T", getResp)
+	}
+}
+
+func TestAppendUpload_ChunkTooLarge(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	createResp, err := h.CreateUpload(ctx, openapi.CreateUploadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.CreateUploadJSONRequestBody{Filename: "a.bin", Size: 4},
+	})
+	if err != nil {
+		t.Fatalf("CreateUpload: %v", err)
+	}
+	session := createResp.(openapi.CreateUpload201JSONResponse)
+
+	resp, err := h.AppendUpload(ctx, openapi.AppendUploadRequestObject{
+		Id:     session.Id,
+		Params: openapi.AppendUploadParams{Offset: 0},
+		Body:   strings.NewReader("too long"),
+	})
+	if err != nil {
+		t.Fatalf("AppendUpload: %v", err)
+	}
+	if _, ok := resp.(openapi.AppendUpload400JSONResponse); !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestUpload_OtherUsersSessionNotFound(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+
+	createResp, err := h.CreateUpload(ctxWithUser(t, h, owner.ID), openapi.CreateUploadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.CreateUploadJSONRequestBody{Filename: "a.bin", Size: 4},
+	})
+	if err != nil {
+		t.Fatalf("CreateUpload: %v", err)
+	}
+	session := createResp.(openapi.CreateUpload201JSONResponse)
+
+	otherCtx := ctxWithUser(t, h, other.ID)
+	resp, err := h.CancelUpload(otherCtx, openapi.CancelUploadRequestObject{Id: session.Id})
+	if err != nil {
+		t.Fatalf("CancelUpload: %v", err)
+	}
+	if _, ok := resp.(openapi.CancelUpload404JSONResponse); !ok {
+		t.Fatalf("expected 404 for another user's upload, got %T", resp)
+	}
+
+	resp, err = h.CancelUpload(ctxWithUser(t, h, owner.ID), openapi.CancelUploadRequestObject{Id: session.Id})
+	if err != nil {
+		t.Fatalf("CancelUpload: %v", err)
+	}
+	if _, ok := resp.(openapi.CancelUpload200JSONResponse); !ok {
+		t.Fatalf("expected owner to cancel, got %T", resp)
+	}
+}
+
+func TestCreateUpload_PrivateChannelNonMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+
+	resp, err := h.CreateUpload(ctxWithUser(t, h, other.ID), openapi.CreateUploadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.CreateUploadJSONRequestBody{Filename: "a.bin", Size: 4},
+	})
+	if err != nil {
+		t.Fatalf("CreateUpload: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateUpload403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestCreateUpload_QuotaExceeded(t *testing.T) {
+	h, db := testHandler(t)
+	h.workspaceQuota = 100
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	if err := h.fileRepo.Create(context.Background(), &file.Attachment{
+		ChannelID: ch.ID, UserID: &user.ID, Filename: "existing.bin",
+		ContentType: "application/octet-stream", SizeBytes: 80, StoragePath: "existing.bin",
+	}); err != nil {
+		t.Fatalf("creating attachment: %v", err)
+	}
+
+	resp, err := h.CreateUpload(ctx, openapi.CreateUploadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.CreateUploadJSONRequestBody{Filename: "big.bin", Size: 30},
+	})
+	if err != nil {
+		t.Fatalf("CreateUpload: %v", err)
+	}
+	forbidden, ok := resp.(openapi.CreateUpload403JSONResponse)
+	if !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+	if forbidden.Error.Code != ErrCodeQuotaExceeded {
+		t.Errorf("expected code %s, got %s", ErrCodeQuotaExceeded, forbidden.Error.Code)
+	}
+
+	resp, err = h.CreateUpload(ctx, openapi.CreateUploadRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.CreateUploadJSONRequestBody{Filename: "small.bin", Size: 20},
+	})
+	if err != nil {
+		t.Fatalf("CreateUpload: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateUpload201JSONResponse); !ok {
+		t.Fatalf("expected upload that fits the quota to be accepted, got %T", resp)
+	}
+}
+
+func TestGetWorkspaceStorage(t *testing.T) {
+	h, db := testHandler(t)
+	h.workspaceQuota = 1000
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	for _, size := range []int64{100, 250} {
+		if err := h.fileRepo.Create(context.Background(), &file.Attachment{
+			ChannelID: ch.ID, UserID: &owner.ID, Filename: "f.bin",
+			ContentType: "application/octet-stream", SizeBytes: size, StoragePath: "f.bin",
+		}); err != nil {
+			t.Fatalf("creating attachment: %v", err)
+		}
+	}
+
+	resp, err := h.GetWorkspaceStorage(ctxWithUser(t, h, owner.ID), openapi.GetWorkspaceStorageRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("GetWorkspaceStorage: %v", err)
+	}
+	usage, ok := resp.(openapi.GetWorkspaceStorage200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if usage.UsedBytes != 350 || usage.AttachmentCount != 2 {
+		t.Errorf("expected 2 attachments totalling 350 bytes, got %+v", usage)
+	}
+	if usage.QuotaBytes == nil || *usage.QuotaBytes != 1000 {
+		t.Errorf("expected quota of 1000 bytes, got %v", usage.QuotaBytes)
+	}
+
+	resp, err = h.GetWorkspaceStorage(ctxWithUser(t, h, member.ID), openapi.GetWorkspaceStorageRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("GetWorkspaceStorage: %v", err)
+	}
+	if _, ok := resp.(openapi.GetWorkspaceStorage403JSONResponse); !ok {
+		t.Fatalf("expected 403 for non-admin, got %T", resp)
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	general := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	secret := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+
+	attach := func(channelID, filename, contentType string, size int64) {
+		t.Helper()
+		msg := testutil.CreateTestMessage(t, db, channelID, owner.ID, "see attached")
+		a := &file.Attachment{
+			ChannelID: channelID, UserID: &owner.ID, Filename: filename,
+			ContentType: contentType, SizeBytes: size, StoragePath: filename,
+		}
+		if err := h.fileRepo.Create(context.Background(), a); err != nil {
+			t.Fatalf("creating attachment: %v", err)
+		}
+		if err := h.fileRepo.UpdateMessageID(context.Background(), a.ID, msg.ID); err != nil {
+			t.Fatalf("linking attachment: %v", err)
+		}
+	}
+	attach(general.ID, "photo.png", "image/png", 300)
+	attach(general.ID, "report.pdf", "application/pdf", 900)
+	attach(secret.ID, "plans.pdf", "application/pdf", 100)
+
+	// Uploads not yet attached to a message are not listed
+	if err := h.fileRepo.Create(context.Background(), &file.Attachment{
+		ChannelID: general.ID, UserID: &owner.ID, Filename: "pending.png",
+		ContentType: "image/png", SizeBytes: 10, StoragePath: "pending.png",
+	}); err != nil {
+		t.Fatalf("creating attachment: %v", err)
+	}
+
+	sortLargest := openapi.FileSort(file.SortLargest)
+	resp, err := h.ListChannelFiles(ctxWithUser(t, h, member.ID), openapi.ListChannelFilesRequestObject{
+		Id:     openapi.ChannelId(general.ID),
+		Params: openapi.ListChannelFilesParams{Sort: &sortLargest},
+	})
+	if err != nil {
+		t.Fatalf("ListChannelFiles: %v", err)
+	}
+	result, ok := resp.(openapi.ListChannelFiles200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if result.TotalCount != 2 || len(result.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d (total %d)", len(result.Files), result.TotalCount)
+	}
+	if result.Files[0].File.Filename != "report.pdf" {
+		t.Errorf("expected largest file first, got %s", result.Files[0].File.Filename)
+	}
+	if result.Files[0].ChannelName != "general" || result.Files[0].UserDisplayName != "Owner" {
+		t.Errorf("unexpected entry context: %+v", result.Files[0])
+	}
+
+	images := openapi.FileType(file.TypeImages)
+	resp, err = h.ListChannelFiles(ctxWithUser(t, h, member.ID), openapi.ListChannelFilesRequestObject{
+		Id:     openapi.ChannelId(general.ID),
+		Params: openapi.ListChannelFilesParams{Type: &images},
+	})
+	if err != nil {
+		t.Fatalf("ListChannelFiles: %v", err)
+	}
+	result = resp.(openapi.ListChannelFiles200JSONResponse)
+	if len(result.Files) != 1 || result.Files[0].File.Filename != "photo.png" {
+		t.Errorf("expected only photo.png for images filter, got %+v", result.Files)
+	}
+
+	resp, err = h.ListChannelFiles(ctxWithUser(t, h, member.ID), openapi.ListChannelFilesRequestObject{
+		Id: openapi.ChannelId(secret.ID),
+	})
+	if err != nil {
+		t.Fatalf("ListChannelFiles: %v", err)
+	}
+	if _, ok := resp.(openapi.ListChannelFiles403JSONResponse); !ok {
+		t.Errorf("expected 403 for private channel non-member, got %T", resp)
+	}
+
+	// The workspace listing hides private channels the caller is not in
+	limit := 1
+	resp2, err := h.ListWorkspaceFiles(ctxWithUser(t, h, member.ID), openapi.ListWorkspaceFilesRequestObject{
+		Wid:    openapi.WorkspaceId(ws.ID),
+		Params: openapi.ListWorkspaceFilesParams{Limit: &limit},
+	})
+	if err != nil {
+		t.Fatalf("ListWorkspaceFiles: %v", err)
+	}
+	wsResult, ok := resp2.(openapi.ListWorkspaceFiles200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp2)
+	}
+	if wsResult.TotalCount != 2 || len(wsResult.Files) != 1 || !wsResult.HasMore {
+		t.Errorf("expected first of 2 visible files with more, got %d of %d (has_more %v)", len(wsResult.Files), wsResult.TotalCount, wsResult.HasMore)
+	}
+
+	resp2, err = h.ListWorkspaceFiles(ctxWithUser(t, h, owner.ID), openapi.ListWorkspaceFilesRequestObject{
+		Wid: openapi.WorkspaceId(ws.ID),
+	})
+	if err != nil {
+		t.Fatalf("ListWorkspaceFiles: %v", err)
+	}
+	if wsResult = resp2.(openapi.ListWorkspaceFiles200JSONResponse); wsResult.TotalCount != 3 {
+		t.Errorf("expected owner to see 3 files, got %d", wsResult.TotalCount)
+	}
+}
+
+func TestListFiles_PrivateChannelHistoryVisibility(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	newcomer := testutil.CreateTestUser(t, db, "new@test.com", "Newcomer")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, newcomer.ID, ws.ID, "member")
+
+	secret := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	if _, err := db.Exec(`UPDATE channels SET history_visibility = ? WHERE id = ?`, channel.HistoryVisibilityNone, secret.ID); err != nil {
+		t.Fatalf("setting history visibility: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE channel_memberships SET created_at = ? WHERE channel_id = ?`,
+		time.Now().UTC().Add(-2*time.Hour).Format(time.RFC3339), secret.ID); err != nil {
+		t.Fatalf("backdating owner membership: %v", err)
+	}
+
+	attach := func(filename string) *file.Attachment {
+		t.Helper()
+		msg := testutil.CreateTestMessage(t, db, secret.ID, owner.ID, "see attached")
+		a := &file.Attachment{
+			ChannelID: secret.ID, UserID: &owner.ID, Filename: filename,
+			ContentType: "application/pdf", SizeBytes: 100, StoragePath: filename,
+		}
+		if err := h.fileRepo.Create(context.Background(), a); err != nil {
+			t.Fatalf("creating attachment: %v", err)
+		}
+		if err := h.fileRepo.UpdateMessageID(context.Background(), a.ID, msg.ID); err != nil {
+			t.Fatalf("linking attachment: %v", err)
+		}
+		a.MessageID = &msg.ID
+		return a
+	}
+	early := attach("old-plans.pdf")
+	backdateMessage(t, db, *early.MessageID, time.Hour)
+	if _, err := db.Exec(`UPDATE attachments SET created_at = ? WHERE id = ?`,
+		time.Now().UTC().Add(-time.Hour).Format(time.RFC3339), early.ID); err != nil {
+		t.Fatalf("backdating attachment: %v", err)
+	}
+	addChannelMember(t, db, newcomer.ID, secret.ID, nil)
+	attach("new-plans.pdf")
+
+	ctx := ctxWithUser(t, h, newcomer.ID)
+	resp, err := h.ListChannelFiles(ctx, openapi.ListChannelFilesRequestObject{Id: openapi.ChannelId(secret.ID)})
+	if err != nil {
+		t.Fatalf("ListChannelFiles: %v", err)
+	}
+	result, ok := resp.(openapi.ListChannelFiles200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if result.TotalCount != 1 || len(result.Files) != 1 || result.Files[0].File.Filename != "new-plans.pdf" {
+		t.Errorf("expected only the file posted after joining, got %+v", result.Files)
+	}
+
+	resp2, err := h.ListWorkspaceFiles(ctx, openapi.ListWorkspaceFilesRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("ListWorkspaceFiles: %v", err)
+	}
+	wsResult, ok := resp2.(openapi.ListWorkspaceFiles200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp2)
+	}
+	if wsResult.TotalCount != 1 || len(wsResult.Files) != 1 || wsResult.Files[0].File.Filename != "new-plans.pdf" {
+		t.Errorf("expected only the file posted after joining, got %+v", wsResult.Files)
+	}
+
+	// Knowing the ID of an earlier file doesn't help either
+	signResp, err := h.SignFileUrl(ctx, openapi.SignFileUrlRequestObject{Id: early.ID})
+	if err != nil {
+		t.Fatalf("SignFileUrl: %v", err)
+	}
+	if _, ok := signResp.(openapi.SignFileUrl404JSONResponse); !ok {
+		t.Errorf("expected 404 for a file from before joining, got %T", signResp)
+	}
+
+	// The owner, who was there all along, still sees both
+	resp2, err = h.ListWorkspaceFiles(ctxWithUser(t, h, owner.ID), openapi.ListWorkspaceFilesRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("ListWorkspaceFiles: %v", err)
+	}
+	if wsResult = resp2.(openapi.ListWorkspaceFiles200JSONResponse); wsResult.TotalCount != 2 {
+		t.Errorf("expected owner to see 2 files, got %d", wsResult.TotalCount)
+	}
+}
+
+// pngHeader is enough of a PNG for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func fileUpload(t *testing.T, filename, contentType string, data []byte) *multipart.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+filename+`"`)
+	header.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		t.Fatalf("CreatePart: %v", err)
+	}
+	part.Write(data)
+	mw.Close()
+	return multipart.NewReader(&buf, mw.Boundary())
+}
+
+// fakeScanner reports every file as infected or clean.
+type fakeScanner struct{ infected bool }
+
+func (s fakeScanner) Scan(ctx context.Context, r io.Reader) (antivirus.Result, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return antivirus.Result{}, err
+	}
+	if s.infected {
+		return antivirus.Result{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return antivirus.Result{}, nil
+}
+
+func TestUploadFile_SniffsContentType(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	resp, err := h.UploadFile(ctx, openapi.UploadFileRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: fileUpload(t, "photo.png", "text/html", pngHeader),
+	})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	uploaded, ok := resp.(openapi.UploadFile200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if uploaded.File.ContentType != "image/png" {
+		t.Errorf("expected sniffed image/png, got %s", uploaded.File.ContentType)
+	}
+	if uploaded.File.Status != openapi.AttachmentStatusReady {
+		t.Errorf("expected ready status, got %s", uploaded.File.Status)
+	}
+}
+
+func TestUploadFile_RecordsImageDimensions(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	resp, err := h.UploadFile(ctx, openapi.UploadFileRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: fileUpload(t, "photo.png", "image/png", buf.Bytes()),
+	})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	uploaded, ok := resp.(openapi.UploadFile200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	a, err := h.fileRepo.GetByID(context.Background(), uploaded.File.Id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	apiAttachment := attachmentToAPI(a)
+	if apiAttachment.Width == nil || apiAttachment.Height == nil || *apiAttachment.Width != 40 || *apiAttachment.Height != 30 {
+		t.Errorf("expected 40x30 dimensions, got %v x %v", apiAttachment.Width, apiAttachment.Height)
+	}
+
+	// Files that are not images have no dimensions
+	resp, err = h.UploadFile(ctx, openapi.UploadFileRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: fileUpload(t, "notes.txt", "text/plain", []byte("hello")),
+	})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	a, err = h.fileRepo.GetByID(context.Background(), resp.(openapi.UploadFile200JSONResponse).File.Id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if a.Width != nil || a.Height != nil {
+		t.Errorf("expected no dimensions for text, got %v x %v", a.Width, a.Height)
+	}
+}
+
+func TestUploadFile_WorkspaceTypeLists(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	w, err := h.workspaceRepo.GetByID(context.Background(), ws.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	w.Settings = `{"upload_allowed_types":["image/*"],"upload_blocked_types":[".gif"]}`
+	if err := h.workspaceRepo.Update(context.Background(), w); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	tests := []struct {
+		filename, declared string
+		data               []byte
+		allowed            bool
+	}{
+		{"photo.png", "image/png", pngHeader, true},
+		{"anim.gif", "image/gif", []byte("GIF89a"), false},
+		// Declaring an image does not get a text file past the allowlist
+		{"notes.png", "image/png", []byte("just some text"), false},
+	}
+	for _, tt := range tests {
+		resp, err := h.UploadFile(ctx, openapi.UploadFileRequestObject{
+			Id:   openapi.ChannelId(ch.ID),
+			Body: fileUpload(t, tt.filename, tt.declared, tt.data),
+		})
+		if err != nil {
+			t.Fatalf("UploadFile(%s): %v", tt.filename, err)
+		}
+		if tt.allowed {
+			if _, ok := resp.(openapi.UploadFile200JSONResponse); !ok {
+				t.Errorf("UploadFile(%s): expected 200, got %T", tt.filename, resp)
+			}
+			continue
+		}
+		forbidden, ok := resp.(openapi.UploadFile403JSONResponse)
+		if !ok {
+			t.Errorf("UploadFile(%s): expected 403, got %T", tt.filename, resp)
+			continue
+		}
+		if forbidden.Error.Code != ErrCodeFileTypeBlocked {
+			t.Errorf("UploadFile(%s): expected code %s, got %s", tt.filename, ErrCodeFileTypeBlocked, forbidden.Error.Code)
+		}
+	}
+}
+
+func TestUploadFile_Quarantined(t *testing.T) {
+	h, db := testHandler(t)
+	h.scanner = fakeScanner{infected: true}
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	resp, err := h.UploadFile(ctx, openapi.UploadFileRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: fileUpload(t, "eicar.txt", "text/plain", []byte("EICAR")),
+	})
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	uploaded, ok := resp.(openapi.UploadFile200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if uploaded.File.Status != openapi.AttachmentStatusQuarantined {
+		t.Fatalf("expected quarantined status, got %s", uploaded.File.Status)
+	}
+
+	dl, err := h.DownloadFile(ctx, openapi.DownloadFileRequestObject{Id: uploaded.File.Id})
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	forbidden, ok := dl.(openapi.DownloadFile403JSONResponse)
+	if !ok || forbidden.Error.Code != ErrCodeFileQuarantined {
+		t.Errorf("expected 403 %s for quarantined download, got %+v", ErrCodeFileQuarantined, dl)
+	}
+
+	content := "see attached"
+	attachmentIDs := []string{uploaded.File.Id}
+	send, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content, AttachmentIds: &attachmentIDs},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if _, ok := send.(openapi.SendMessage400JSONResponse); !ok {
+		t.Errorf("expected 400 when attaching a quarantined file, got %T", send)
+	}
+}
@@ -3,24 +3,43 @@ package handler
 import (
 	"context"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/enzyme/server/internal/accountdeletion"
+	"github.com/enzyme/server/internal/activity"
+	"github.com/enzyme/server/internal/announcement"
+	"github.com/enzyme/server/internal/antivirus"
 	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/bot"
+	"github.com/enzyme/server/internal/call"
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/channeltemplate"
+	"github.com/enzyme/server/internal/contentfilter"
 	"github.com/enzyme/server/internal/email"
 	"github.com/enzyme/server/internal/emoji"
+	"github.com/enzyme/server/internal/export"
+	"github.com/enzyme/server/internal/features"
 	"github.com/enzyme/server/internal/file"
 	"github.com/enzyme/server/internal/linkpreview"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/notification"
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/poll"
 	"github.com/enzyme/server/internal/pushnotification"
+	"github.com/enzyme/server/internal/quickswitch"
+	"github.com/enzyme/server/internal/ratelimit"
+	"github.com/enzyme/server/internal/retention"
 	"github.com/enzyme/server/internal/scheduled"
 	"github.com/enzyme/server/internal/signing"
 	"github.com/enzyme/server/internal/sse"
 	"github.com/enzyme/server/internal/storage"
 	"github.com/enzyme/server/internal/thread"
 	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/usergroup"
+	"github.com/enzyme/server/internal/webhook"
 	"github.com/enzyme/server/internal/workspace"
 )
 
@@ -45,11 +64,47 @@ type Handler struct {
 	notificationService *notification.Service
 	pushTokenRepo       *pushnotification.Repository
 	moderationRepo      *moderation.Repository
+	webhookRepo         *webhook.Repository
+	botRepo             *bot.Repository
+	announcementRepo    *announcement.Repository
+	exportRepo          *export.Repository
+	retentionRepo       *retention.Repository
+	quickSwitchRepo     *quickswitch.Repository
+	activityRepo        *activity.Repository
+	userGroupRepo       *usergroup.Repository
+	channelTemplateRepo *channeltemplate.Repository
+	pollRepo            *poll.Repository
+	callRepo            *call.Repository
+	accountDeletionRepo *accountdeletion.Repository
+	contentFilterRepo   *contentfilter.Repository
+	contentFilter       *contentfilter.Filter
+	features            *features.Service
+	webhookLimiter      *ratelimit.Limiter
+	signupLimiter       *ratelimit.Limiter
 	hub                 *sse.Hub
 	signer              *signing.Signer
 	storage             storage.Storage
+	scanner             antivirus.Scanner
 	maxUploadSize       int64
+	uploadSessionTTL    time.Duration
+	workspaceQuota      int64
+	undeleteWindow      time.Duration
+	maxMessageLength    int
+	listContentLength   int
+	settings            atomic.Pointer[Settings]
 	publicURL           string
+
+	// background tracks work that outlives its request, such as async
+	// message sends, so shutdown can wait for it
+	background sync.WaitGroup
+}
+
+// Settings are the handler options that can change while the server runs,
+// on a config reload.
+type Settings struct {
+	SearchOffset    bool   // accept the deprecated offset parameter in message search
+	ReadOnly        bool   // server-wide maintenance mode
+	ReadOnlyMessage string // banner shown while ReadOnly is set
 }
 
 // Dependencies holds all dependencies for the Handler
@@ -70,16 +125,42 @@ type Dependencies struct {
 	NotificationService *notification.Service
 	PushTokenRepo       *pushnotification.Repository
 	ModerationRepo      *moderation.Repository
+	WebhookRepo         *webhook.Repository
+	BotRepo             *bot.Repository
+	AnnouncementRepo    *announcement.Repository
+	ExportRepo          *export.Repository
+	RetentionRepo       *retention.Repository
+	QuickSwitchRepo     *quickswitch.Repository
+	ActivityRepo        *activity.Repository
+	UserGroupRepo       *usergroup.Repository
+	ChannelTemplateRepo *channeltemplate.Repository
+	PollRepo            *poll.Repository
+	CallRepo            *call.Repository
+	AccountDeletionRepo *accountdeletion.Repository
+	ContentFilterRepo   *contentfilter.Repository
+	ContentFilter       *contentfilter.Filter
+	Features            *features.Service
+	WebhookLimiter      *ratelimit.Limiter // nil disables per-webhook rate limiting
+	SignupLimiter       *ratelimit.Limiter // nil disables per-workspace open signup rate limiting
 	Hub                 *sse.Hub
 	Signer              *signing.Signer
 	Storage             storage.Storage
+	Scanner             antivirus.Scanner // nil disables upload virus scanning
 	MaxUploadSize       int64
+	UploadSessionTTL    time.Duration // how long a resumable upload may sit idle
+	WorkspaceQuota      int64         // max attachment bytes per workspace; 0 is unlimited
+	UndeleteWindow      time.Duration // how long authors can restore a deleted message
+	MaxMessageLength    int           // longest message content in characters
+	ListContentLength   int           // list endpoints cut longer content short; 0 sends it whole
+	SearchOffset        bool          // accept the deprecated offset parameter in message search
+	ReadOnly            bool          // server-wide maintenance mode
+	ReadOnlyMessage     string        // banner shown while ReadOnly is set
 	PublicURL           string
 }
 
 // New creates a new Handler with all dependencies
 func New(deps Dependencies) *Handler {
-	return &Handler{
+	h := &Handler{
 		authService:         deps.AuthService,
 		sessionStore:        deps.SessionStore,
 		userRepo:            deps.UserRepo,
@@ -96,12 +177,73 @@ func New(deps Dependencies) *Handler {
 		notificationService: deps.NotificationService,
 		pushTokenRepo:       deps.PushTokenRepo,
 		moderationRepo:      deps.ModerationRepo,
+		webhookRepo:         deps.WebhookRepo,
+		botRepo:             deps.BotRepo,
+		announcementRepo:    deps.AnnouncementRepo,
+		exportRepo:          deps.ExportRepo,
+		retentionRepo:       deps.RetentionRepo,
+		quickSwitchRepo:     deps.QuickSwitchRepo,
+		activityRepo:        deps.ActivityRepo,
+		userGroupRepo:       deps.UserGroupRepo,
+		channelTemplateRepo: deps.ChannelTemplateRepo,
+		pollRepo:            deps.PollRepo,
+		callRepo:            deps.CallRepo,
+		accountDeletionRepo: deps.AccountDeletionRepo,
+		contentFilterRepo:   deps.ContentFilterRepo,
+		contentFilter:       deps.ContentFilter,
+		features:            deps.Features,
+		webhookLimiter:      deps.WebhookLimiter,
+		signupLimiter:       deps.SignupLimiter,
 		hub:                 deps.Hub,
 		signer:              deps.Signer,
 		storage:             deps.Storage,
+		scanner:             deps.Scanner,
 		maxUploadSize:       deps.MaxUploadSize,
+		uploadSessionTTL:    deps.UploadSessionTTL,
+		workspaceQuota:      deps.WorkspaceQuota,
+		undeleteWindow:      deps.UndeleteWindow,
+		maxMessageLength:    deps.MaxMessageLength,
+		listContentLength:   deps.ListContentLength,
 		publicURL:           deps.PublicURL,
 	}
+	h.Configure(Settings{
+		SearchOffset:    deps.SearchOffset,
+		ReadOnly:        deps.ReadOnly,
+		ReadOnlyMessage: deps.ReadOnlyMessage,
+	})
+	return h
+}
+
+// Configure replaces the handler's runtime settings. Requests already in
+// progress may still see the old ones.
+func (h *Handler) Configure(s Settings) {
+	h.settings.Store(&s)
+}
+
+// Wait blocks until background work started by requests, such as async
+// message sends, has finished or ctx ends. Call it once the HTTP server has
+// stopped taking requests.
+func (h *Handler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// currentSettings returns the settings in effect, or the zero Settings if
+// Configure was never called.
+func (h *Handler) currentSettings() Settings {
+	if s := h.settings.Load(); s != nil {
+		return *s
+	}
+	return Settings{}
 }
 
 // Context key for storing the http.Request
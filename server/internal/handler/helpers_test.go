@@ -7,24 +7,39 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/enzyme/server/internal/accountdeletion"
+	"github.com/enzyme/server/internal/activity"
+	"github.com/enzyme/server/internal/announcement"
 	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/bot"
+	"github.com/enzyme/server/internal/call"
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/channeltemplate"
+	"github.com/enzyme/server/internal/contentfilter"
 	"github.com/enzyme/server/internal/email"
 	"github.com/enzyme/server/internal/emoji"
+	"github.com/enzyme/server/internal/export"
+	"github.com/enzyme/server/internal/features"
 	"github.com/enzyme/server/internal/file"
 	"github.com/enzyme/server/internal/linkpreview"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/poll"
+	"github.com/enzyme/server/internal/quickswitch"
+	"github.com/enzyme/server/internal/retention"
 	"github.com/enzyme/server/internal/signing"
 	"github.com/enzyme/server/internal/sse"
 	"github.com/enzyme/server/internal/storage"
 	"github.com/enzyme/server/internal/testutil"
 	"github.com/enzyme/server/internal/thread"
 	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/usergroup"
+	"github.com/enzyme/server/internal/webhook"
 	"github.com/enzyme/server/internal/workspace"
 	"github.com/oklog/ulid/v2"
 )
@@ -48,11 +63,13 @@ func testHandler(t *testing.T) (*Handler, *sql.DB) {
 	emailVerifications := auth.NewEmailVerificationRepo(db)
 	authService := auth.NewService(userRepo, passwordResets, emailVerifications, 4)
 
-	sessionStore := auth.NewSessionStore(db, 24*time.Hour)
+	sessionStore := auth.NewSessionStore(db, 24*time.Hour, 0)
+	contentFilterRepo := contentfilter.NewRepository(db)
 
 	notifPrefsRepo := notification.NewPreferencesRepository(db)
 	notifPendingRepo := notification.NewPendingRepository(db)
-	notifService := notification.NewService(notifPrefsRepo, notifPendingRepo, channelRepo, hub)
+	notifKeywordRepo := notification.NewKeywordRepository(db)
+	notifService := notification.NewService(notifPrefsRepo, notifPendingRepo, notifKeywordRepo, channelRepo, hub)
 
 	moderationRepo := moderation.NewRepository(db)
 
@@ -69,12 +86,31 @@ func testHandler(t *testing.T) (*Handler, *sql.DB) {
 		ThreadRepo:          threadRepo,
 		EmojiRepo:           emojiRepo,
 		ModerationRepo:      moderationRepo,
+		WebhookRepo:         webhook.NewRepository(db),
+		BotRepo:             bot.NewRepository(db),
+		AnnouncementRepo:    announcement.NewRepository(db),
+		ExportRepo:          export.NewRepository(db),
+		RetentionRepo:       retention.NewRepository(db),
+		QuickSwitchRepo:     quickswitch.NewRepository(db),
+		ActivityRepo:        activity.NewRepository(db),
+		UserGroupRepo:       usergroup.NewRepository(db),
+		ChannelTemplateRepo: channeltemplate.NewRepository(db),
+		PollRepo:            poll.NewRepository(db),
+		CallRepo:            call.NewRepository(db),
+		AccountDeletionRepo: accountdeletion.NewRepository(db),
+		ContentFilterRepo:   contentFilterRepo,
+		ContentFilter:       contentfilter.NewFilter(contentFilterRepo),
+		Features:            features.NewService(features.NewRepository(db), nil),
 		NotificationService: notifService,
 		EmailService:        emailService,
 		Hub:                 hub,
 		Signer:              signing.NewSigner("test-signing-secret"),
 		Storage:             storage.NewLocal(t.TempDir()),
 		MaxUploadSize:       10 * 1024 * 1024,
+		UploadSessionTTL:    24 * time.Hour,
+		UndeleteWindow:      24 * time.Hour,
+		MaxMessageLength:    40000,
+		SearchOffset:        true,
 		PublicURL:           "http://localhost:8080",
 	})
 
@@ -88,7 +124,7 @@ func ctxWithUser(t *testing.T, h *Handler, userID string) context.Context {
 	t.Helper()
 
 	// Create a token for this user
-	token, err := h.sessionStore.Create(userID)
+	tokens, err := h.sessionStore.Create(userID, auth.ClientInfo{})
 	if err != nil {
 		t.Fatalf("creating session: %v", err)
 	}
@@ -96,10 +132,21 @@ func ctxWithUser(t *testing.T, h *Handler, userID string) context.Context {
 	// Build context with user ID, token, and request (as middleware would)
 	r := httptest.NewRequest(http.MethodGet, "/", nil)
 	ctx := auth.WithUserID(r.Context(), userID)
-	ctx = auth.WithToken(ctx, token)
+	ctx = auth.WithToken(ctx, tokens.AccessToken)
 	return WithRequest(ctx, r.WithContext(ctx))
 }
 
+// verifyEmail marks a user's email address as verified directly in the database.
+func verifyEmail(t *testing.T, db *sql.DB, userID string) {
+	t.Helper()
+
+	_, err := db.ExecContext(context.Background(), `UPDATE users SET email_verified_at = ? WHERE id = ?`,
+		time.Now().UTC().Format(time.RFC3339), userID)
+	if err != nil {
+		t.Fatalf("verifying email: %v", err)
+	}
+}
+
 // addWorkspaceMember adds a user to a workspace with the given role directly in the database.
 func addWorkspaceMember(t *testing.T, db *sql.DB, userID, workspaceID, role string) {
 	t.Helper()
@@ -183,11 +230,13 @@ func testHandlerWithLinkPreviews(t *testing.T, httpClient *http.Client) (*Handle
 	emailVerifications := auth.NewEmailVerificationRepo(db)
 	authService := auth.NewService(userRepo, passwordResets, emailVerifications, 4)
 
-	sessionStore := auth.NewSessionStore(db, 24*time.Hour)
+	sessionStore := auth.NewSessionStore(db, 24*time.Hour, 0)
+	contentFilterRepo := contentfilter.NewRepository(db)
 
 	notifPrefsRepo := notification.NewPreferencesRepository(db)
 	notifPendingRepo := notification.NewPendingRepository(db)
-	notifService := notification.NewService(notifPrefsRepo, notifPendingRepo, channelRepo, hub)
+	notifKeywordRepo := notification.NewKeywordRepository(db)
+	notifService := notification.NewService(notifPrefsRepo, notifPendingRepo, notifKeywordRepo, channelRepo, hub)
 
 	lpRepo := linkpreview.NewRepository(db)
 	lpFetcher := linkpreview.NewFetcherWithClient(lpRepo, httpClient)
@@ -208,12 +257,31 @@ func testHandlerWithLinkPreviews(t *testing.T, httpClient *http.Client) (*Handle
 		ThreadRepo:          threadRepo,
 		EmojiRepo:           emojiRepo,
 		ModerationRepo:      moderationRepo,
+		WebhookRepo:         webhook.NewRepository(db),
+		BotRepo:             bot.NewRepository(db),
+		AnnouncementRepo:    announcement.NewRepository(db),
+		ExportRepo:          export.NewRepository(db),
+		RetentionRepo:       retention.NewRepository(db),
+		QuickSwitchRepo:     quickswitch.NewRepository(db),
+		ActivityRepo:        activity.NewRepository(db),
+		UserGroupRepo:       usergroup.NewRepository(db),
+		ChannelTemplateRepo: channeltemplate.NewRepository(db),
+		PollRepo:            poll.NewRepository(db),
+		CallRepo:            call.NewRepository(db),
+		AccountDeletionRepo: accountdeletion.NewRepository(db),
+		ContentFilterRepo:   contentFilterRepo,
+		ContentFilter:       contentfilter.NewFilter(contentFilterRepo),
+		Features:            features.NewService(features.NewRepository(db), nil),
 		NotificationService: notifService,
 		EmailService:        emailService,
 		Hub:                 hub,
 		Signer:              signing.NewSigner("test-signing-secret"),
 		Storage:             storage.NewLocal(t.TempDir()),
 		MaxUploadSize:       10 * 1024 * 1024,
+		UploadSessionTTL:    24 * time.Hour,
+		UndeleteWindow:      24 * time.Hour,
+		MaxMessageLength:    40000,
+		SearchOffset:        true,
 		PublicURL:           "http://localhost:8080",
 	})
 
@@ -234,3 +302,38 @@ func seedLinkPreviewCache(t *testing.T, db *sql.DB, url, title string) {
 		t.Fatalf("seeding link preview cache: %v", err)
 	}
 }
+
+// connectSSEClient starts the handler's hub and registers an SSE client for
+// the user, draining the initial presence event so tests only see what follows.
+func connectSSEClient(t *testing.T, h *Handler, workspaceID, userID string) *sse.Client {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go h.hub.Run(ctx)
+
+	client := &sse.Client{
+		ID:          ulid.Make().String(),
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+		Send:        make(chan sse.SerializedEvent, 16),
+		Done:        make(chan struct{}),
+	}
+	h.hub.Register(client)
+	expectSSEEvent(t, client, sse.EventPresenceChanged)
+	return client
+}
+
+// expectSSEEvent waits for the next event delivered to the client and checks its type.
+func expectSSEEvent(t *testing.T, client *sse.Client, eventType string) {
+	t.Helper()
+
+	select {
+	case ev := <-client.Send:
+		if !strings.Contains(string(ev.Frame), `"type":"`+eventType+`"`) {
+			t.Fatalf("expected %s event, got %s", eventType, ev.Frame)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s event", eventType)
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// localizeMessages renders the text of system messages in the request's
+// language. Messages are converted with the default locale, which is also
+// what SSE events carry, since one event goes to readers of every language.
+func localizeMessages(ctx context.Context, messages []openapi.MessageWithUser) {
+	for i := range messages {
+		localizeMessage(ctx, &messages[i])
+	}
+}
+
+// localizeMessage is localizeMessages for a single message.
+func localizeMessage(ctx context.Context, m *openapi.MessageWithUser) {
+	locale := i18n.FromContext(ctx)
+	if m.SystemEvent == nil || locale == i18n.Default {
+		return
+	}
+	m.Content = systemEventFromAPI(m.SystemEvent).Text(locale)
+	m.ContentRendered = renderContent(m.Content, m.DeletedAt)
+}
+
+// localizePreview replaces a latest-message preview with the text of its
+// system event, if it has one, in the request's language.
+func localizePreview(ctx context.Context, preview **string, systemEvent *string) {
+	if systemEvent == nil {
+		return
+	}
+	content := ""
+	if *preview != nil {
+		content = **preview
+	}
+	text := channel.PreviewText(message.SystemText(i18n.FromContext(ctx), content, *systemEvent))
+	*preview = &text
+}
+
+// systemEventFromAPI is the inverse of the system_event conversion in
+// messageWithUserToAPI, for the fields that go into its text.
+func systemEventFromAPI(e *openapi.SystemEventData) *message.SystemEventData {
+	event := &message.SystemEventData{
+		EventType:        string(e.EventType),
+		UserID:           e.UserId,
+		UserDisplayName:  e.UserDisplayName,
+		ChannelName:      e.ChannelName,
+		ActorDisplayName: e.ActorDisplayName,
+		OldChannelName:   e.OldChannelName,
+		ChannelType:      e.ChannelType,
+		Topic:            e.Topic,
+		UserCount:        e.UserCount,
+	}
+	if e.WelcomeReason != nil {
+		reason := string(*e.WelcomeReason)
+		event.WelcomeReason = &reason
+	}
+	return event
+}
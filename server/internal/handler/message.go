@@ -14,7 +14,9 @@ import (
 	"github.com/enzyme/server/internal/channel"
 	"github.com/enzyme/server/internal/file"
 	"github.com/enzyme/server/internal/gravatar"
+	"github.com/enzyme/server/internal/i18n"
 	"github.com/enzyme/server/internal/linkpreview"
+	"github.com/enzyme/server/internal/logging"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/notification"
@@ -23,8 +25,6 @@ import (
 	"github.com/enzyme/server/internal/workspace"
 )
 
-const maxMessageLength = 40000
-
 // SendMessage sends a message to a channel
 func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRequestObject) (openapi.SendMessageResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -44,6 +44,12 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 		return openapi.SendMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
 	}
 
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.SendMessage503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
 	// Check channel is not archived
 	if ch.ArchivedAt != nil {
 		return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot post to archived channel")}, nil
@@ -61,10 +67,8 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 				}
 				// Auto-join public channel
 				memberRole := "poster"
-				_, _ = h.channelRepo.AddMember(ctx, userID, string(request.Id), &memberRole)
-				// Update SSE hub cache
-				if h.hub != nil {
-					h.hub.AddChannelMember(string(request.Id), userID)
+				if _, err := h.channelRepo.AddMember(ctx, userID, string(request.Id), &memberRole); err == nil {
+					h.broadcastChannelMemberAdded(ctx, ch, userID)
 				}
 			} else {
 				return openapi.SendMessage403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
@@ -72,17 +76,57 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 		} else {
 			return nil, err
 		}
-	} else if !channel.CanPost(membership.ChannelRole) {
+	}
+
+	// A retried send carries the same idempotency key and gets the original
+	// message back, so check for one before slow mode can turn it away
+	var clientMsgID *string
+	if key := request.Params.IdempotencyKey; key != nil && *key != "" {
+		clientMsgID = key
+	}
+	if key := request.Body.ClientMsgId; key != nil && *key != "" {
+		if clientMsgID != nil && *clientMsgID != *key {
+			return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Idempotency-Key and client_msg_id must match")}, nil
+		}
+		clientMsgID = key
+	}
+	if clientMsgID != nil {
+		if utf8.RuneCountInString(*clientMsgID) > message.MaxClientMsgIDLength {
+			return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Idempotency key must be at most %d characters", message.MaxClientMsgIDLength))}, nil
+		}
+		if resp, err := h.replaySend(ctx, ch, userID, *clientMsgID); err != nil || resp != nil {
+			return resp, err
+		}
+	}
+
+	var channelRole *string
+	if membership != nil {
+		channelRole = membership.ChannelRole
+	}
+	if ok, err := h.canPostIn(ctx, ch, userID, channelRole, request.Body.ThreadParentId != nil); err != nil {
+		return nil, err
+	} else if !ok {
 		return openapi.SendMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
 	}
+	if retryAfter, err := h.slowModeRetryAfter(ctx, ch, userID, channelRole); err != nil {
+		return nil, err
+	} else if retryAfter > 0 {
+		return openapi.SendMessage429JSONResponse{
+			Body: openapi.SlowModeErrorResponse{
+				Error:      newError(ErrCodeSlowMode, fmt.Sprintf("Slow mode is on. Try again in %d seconds.", retryAfter)),
+				RetryAfter: retryAfter,
+			},
+			Headers: openapi.SendMessage429ResponseHeaders{RetryAfter: retryAfter},
+		}, nil
+	}
 
 	// Content is required unless attachments are provided
 	content := ""
 	if request.Body.Content != nil {
 		content = strings.TrimSpace(*request.Body.Content)
 	}
-	if utf8.RuneCountInString(content) > maxMessageLength {
-		return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Message content exceeds maximum length of %d characters", maxMessageLength))}, nil
+	if utf8.RuneCountInString(content) > h.maxMessageLength {
+		return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: messageTooLongResponse("Message content", h.maxMessageLength)}, nil
 	}
 
 	hasContent := content != ""
@@ -92,6 +136,19 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 		return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Message content or attachments required")}, nil
 	}
 
+	if denied, err := h.checkChannelMentions(ctx, ch, userID, content); err != nil {
+		return nil, err
+	} else if denied {
+		return openapi.SendMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse(errChannelMentionDenied)}, nil
+	}
+
+	content, blocked, err := h.filterContent(ctx, ch.WorkspaceID, ch.ID, userID, content)
+	if err != nil {
+		return nil, err
+	} else if blocked {
+		return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeContentBlocked, errContentBlocked)}, nil
+	}
+
 	// Validate attachments if provided
 	var attachmentIDs []string
 	if hasAttachments {
@@ -114,6 +171,9 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 			if attachment.MessageID != nil {
 				return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Attachment %s is already linked to a message", attachmentID))}, nil
 			}
+			if attachment.Status == file.StatusQuarantined {
+				return openapi.SendMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeFileQuarantined, fmt.Sprintf("Attachment %s failed the virus scan", attachmentID))}, nil
+			}
 		}
 	}
 
@@ -137,49 +197,11 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 		}
 	}
 
-	// Parse mentions from content
-	var mentions []string
-	var originalMentions []string
-	if h.notificationService != nil && content != "" {
-		mentions, _ = notification.ParseMentions(ctx, h.userRepo, ch.WorkspaceID, content)
-
-		// Strip mentions of blocked users in either direction (workspace-scoped)
-		if len(mentions) > 0 {
-			// Batch-fetch block relationships to avoid N+1 queries
-			blockedByMe, err := h.moderationRepo.GetBlockedUserIDs(ctx, ch.WorkspaceID, userID)
-			if err != nil {
-				slog.Error("failed to get blocked user IDs for mention filtering", "error", err)
-				blockedByMe = nil
-			}
-			blockingMe, err := h.moderationRepo.GetUsersWhoBlocked(ctx, ch.WorkspaceID, userID)
-			if err != nil {
-				slog.Error("failed to get users who blocked sender for mention filtering", "error", err)
-				blockingMe = nil
-			}
-			var filtered []string
-			for _, mentionID := range mentions {
-				if notification.IsSpecialMention(mentionID) {
-					filtered = append(filtered, mentionID)
-					continue
-				}
-				if !blockedByMe[mentionID] && !blockingMe[mentionID] {
-					filtered = append(filtered, mentionID)
-				}
-			}
-			mentions = filtered
-		}
-
-		originalMentions = mentions
-
-		// Resolve @here to online user IDs for storage (badge count accuracy)
-		if h.hub != nil && slices.Contains(mentions, notification.MentionHere) {
-			memberIDs, err := h.channelRepo.GetMemberUserIDs(ctx, string(request.Id))
-			if err != nil {
-				slog.Error("failed to get channel members for @here resolution", "component", "mentions", "error", err)
-			} else {
-				mentions = notification.ResolveHereMentions(mentions, memberIDs, userID, h.hub, ch.WorkspaceID)
-			}
-		}
+	// Async sends parse mentions after the message is stored
+	async := request.Body.Async != nil && *request.Body.Async
+	var mentions, originalMentions []string
+	if !async {
+		mentions, originalMentions = h.sendMentions(ctx, ch, userID, content)
 	}
 
 	msg := &message.Message{
@@ -188,6 +210,7 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 		Content:        content,
 		Mentions:       mentions,
 		ThreadParentID: request.Body.ThreadParentId,
+		ClientMsgID:    clientMsgID,
 	}
 
 	// Set also_send_to_channel flag (only meaningful for thread replies)
@@ -196,9 +219,96 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 	}
 
 	if err := h.messageRepo.Create(ctx, msg); err != nil {
+		// A concurrent retry with the same key got there first
+		if errors.Is(err, message.ErrDuplicateClientMsgID) {
+			if resp, replayErr := h.replaySend(ctx, ch, userID, *clientMsgID); replayErr != nil || resp != nil {
+				return resp, replayErr
+			}
+		}
 		return nil, err
 	}
 
+	if async {
+		h.background.Go(func() {
+			ctx, cancel := context.WithTimeout(logging.Detach(ctx), finalizeTimeout)
+			defer cancel()
+			h.finalizeSend(ctx, ch, msg, threadParent, attachmentIDs)
+		})
+		return openapi.SendMessage202JSONResponse{
+			MessageId:   msg.ID,
+			ChannelId:   msg.ChannelID,
+			ClientMsgId: clientMsgID,
+		}, nil
+	}
+
+	apiMsg, err := h.finishSend(ctx, ch, msg, threadParent, attachmentIDs, originalMentions)
+	if err != nil {
+		return nil, err
+	}
+
+	return openapi.SendMessage200JSONResponse{
+		Message: apiMsg,
+	}, nil
+}
+
+// sendMentions works out who a new message mentions. It returns the mentions
+// to store, with @here resolved to the channel members online now, and the
+// mentions as written for notifications and activity.
+func (h *Handler) sendMentions(ctx context.Context, ch *channel.Channel, userID, content string) (mentions, originalMentions []string) {
+	if h.notificationService == nil || content == "" {
+		return nil, nil
+	}
+
+	mentions, _ = notification.ParseMentions(ctx, h.mentionResolver(), ch.WorkspaceID, content)
+	mentions = h.addKeywordMentions(ctx, ch.ID, userID, content, mentions)
+
+	// Strip mentions of blocked users in either direction (workspace-scoped)
+	if len(mentions) > 0 {
+		// Batch-fetch block relationships to avoid N+1 queries
+		blockedByMe, err := h.moderationRepo.GetBlockedUserIDs(ctx, ch.WorkspaceID, userID)
+		if err != nil {
+			slog.Error("failed to get blocked user IDs for mention filtering", "error", err)
+			blockedByMe = nil
+		}
+		blockingMe, err := h.moderationRepo.GetUsersWhoBlocked(ctx, ch.WorkspaceID, userID)
+		if err != nil {
+			slog.Error("failed to get users who blocked sender for mention filtering", "error", err)
+			blockingMe = nil
+		}
+		var filtered []string
+		for _, mentionID := range mentions {
+			if notification.IsSpecialMention(mentionID) {
+				filtered = append(filtered, mentionID)
+				continue
+			}
+			if !blockedByMe[mentionID] && !blockingMe[mentionID] {
+				filtered = append(filtered, mentionID)
+			}
+		}
+		mentions = filtered
+	}
+
+	originalMentions = mentions
+
+	// Resolve @here to online user IDs for storage (badge count accuracy)
+	if h.hub != nil && slices.Contains(mentions, notification.MentionHere) {
+		memberIDs, err := h.channelRepo.GetMemberUserIDs(ctx, ch.ID)
+		if err != nil {
+			slog.Error("failed to get channel members for @here resolution", "component", "mentions", "error", err)
+		} else {
+			mentions = notification.ResolveHereMentions(mentions, memberIDs, userID, h.hub, ch.WorkspaceID)
+		}
+	}
+	return mentions, originalMentions
+}
+
+// finishSend does everything that follows storing a new message: thread
+// subscriptions, activity, attachments, the link preview, the message.new
+// broadcast and notifications. It returns the message as broadcast.
+func (h *Handler) finishSend(ctx context.Context, ch *channel.Channel, msg *message.Message, threadParent *message.Message, attachmentIDs, originalMentions []string) (openapi.MessageWithUser, error) {
+	userID := *msg.UserID
+	content := msg.Content
+
 	// Handle thread subscription auto-subscribe
 	if threadParent != nil && h.threadRepo != nil {
 		// Auto-subscribe the sender to the thread (respects explicit unsubscribe)
@@ -210,11 +320,13 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 		}
 	}
 
+	h.recordMessageActivity(ctx, ch, msg, threadParent, originalMentions)
+
 	// Link attachments to the message
 	if len(attachmentIDs) > 0 {
 		for _, attachmentID := range attachmentIDs {
 			if err := h.fileRepo.UpdateMessageID(ctx, attachmentID, msg.ID); err != nil {
-				return nil, err
+				return openapi.MessageWithUser{}, err
 			}
 		}
 	}
@@ -248,7 +360,7 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 	if h.hub != nil {
 		if ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM {
 			// For DM channels, skip delivery to users who have blocked the sender (batch lookup)
-			memberIDs, _ := h.channelRepo.GetMemberUserIDs(ctx, string(request.Id))
+			memberIDs, _ := h.channelRepo.GetMemberUserIDs(ctx, msg.ChannelID)
 			usersWhoBlockedSender, err := h.moderationRepo.GetUsersWhoBlocked(ctx, ch.WorkspaceID, userID)
 			if err != nil {
 				slog.Error("failed to get block list for SSE filtering", "error", err)
@@ -258,13 +370,19 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 				if memberID != userID && usersWhoBlockedSender[memberID] {
 					continue
 				}
-				h.hub.BroadcastToUser(ch.WorkspaceID, memberID, sse.NewMessageNewEvent(apiMsg))
+				h.hub.BroadcastToUser(ctx, ch.WorkspaceID, memberID, sse.NewMessageNewEvent(apiMsg))
 			}
 		} else {
-			h.hub.BroadcastToChannel(ch.WorkspaceID, string(request.Id), sse.NewMessageNewEvent(apiMsg))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageNewEvent(apiMsg))
 		}
 	}
 
+	// Messages shown in the channel are read as soon as they arrive for
+	// anyone looking at it
+	if msg.ThreadParentID == nil || msg.AlsoSendToChannel {
+		h.markReadForViewers(ctx, ch, msg.ID)
+	}
+
 	// Trigger notifications
 	if h.notificationService != nil {
 		// Get sender's display name
@@ -288,15 +406,42 @@ func (h *Handler) SendMessage(ctx context.Context, request openapi.SendMessageRe
 			Mentions:       originalMentions,
 			ThreadParentID: msg.ThreadParentID,
 		}
-		// Send notifications asynchronously
-		go func() {
-			_ = h.notificationService.Notify(context.Background(), channelInfo, msgInfo)
-		}()
+		// Queue notifications for background delivery
+		h.notificationService.Enqueue(ctx, channelInfo, msgInfo)
 	}
 
-	return openapi.SendMessage200JSONResponse{
-		Message: apiMsg,
-	}, nil
+	return apiMsg, nil
+}
+
+// finalizeTimeout bounds the background half of an async send, including
+// waiting for room in the notification queue.
+const finalizeTimeout = 30 * time.Second
+
+// finalizeSend finishes an async send in the background and tells the
+// sender's clients with a message.finalized event.
+func (h *Handler) finalizeSend(ctx context.Context, ch *channel.Channel, msg *message.Message, threadParent *message.Message, attachmentIDs []string) {
+	data := openapi.MessageFinalizedData{MessageId: msg.ID, ChannelId: msg.ChannelID}
+
+	mentions, originalMentions := h.sendMentions(ctx, ch, *msg.UserID, msg.Content)
+	if len(mentions) > 0 {
+		if err := h.messageRepo.UpdateMentions(ctx, msg.ID, mentions); err != nil {
+			slog.Error("failed to store mentions of async message", "message_id", msg.ID, "error", err)
+		}
+		msg.Mentions = mentions
+	}
+
+	apiMsg, err := h.finishSend(ctx, ch, msg, threadParent, attachmentIDs, originalMentions)
+	if err != nil {
+		slog.Error("failed to finalize async message", "message_id", msg.ID, "error", err)
+		reason := "Failed to link attachments"
+		data.Error = &reason
+	} else {
+		data.Message = &apiMsg
+	}
+
+	if h.hub != nil {
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, *msg.UserID, sse.NewMessageFinalizedEvent(data))
+	}
 }
 
 // ListMessages lists messages in a channel
@@ -312,7 +457,7 @@ func (h *Handler) ListMessages(ctx context.Context, request openapi.ListMessages
 	}
 
 	// Check access
-	_, err = h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	membership, err := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			if ch.Type != channel.TypePublic {
@@ -329,6 +474,9 @@ func (h *Handler) ListMessages(ctx context.Context, request openapi.ListMessages
 	}
 
 	opts := message.ListOptions{}
+	if membership != nil {
+		opts.VisibleSince = ch.HistoryVisibleSince(membership.CreatedAt)
+	}
 	if request.Body != nil {
 		if request.Body.Cursor != nil {
 			opts.Cursor = *request.Body.Cursor
@@ -353,7 +501,226 @@ func (h *Handler) ListMessages(ctx context.Context, request openapi.ListMessages
 	// Load link previews for all messages
 	h.loadLinkPreviewsForMessages(ctx, result.Messages)
 
-	return openapi.ListMessages200JSONResponse(messageListResultToAPI(result)), nil
+	// Load polls for poll messages
+	h.loadPollsForMessages(ctx, result.Messages, userID)
+
+	// Load read receipts for direct messages
+	if isDMChannel(ch) && h.readReceiptsEnabled(ctx, ch.WorkspaceID) {
+		h.loadReceiptsForMessages(ctx, result.Messages)
+	}
+
+	apiResult := messageListResultToAPI(result)
+	localizeMessages(ctx, apiResult.Messages)
+	h.truncateMessages(apiResult.Messages)
+	return openapi.ListMessages200JSONResponse(apiResult), nil
+}
+
+// replaySend answers a send that repeats an idempotency key with the message
+// the user already sent with it. It returns nil if the key hasn't been used.
+func (h *Handler) replaySend(ctx context.Context, ch *channel.Channel, userID, clientMsgID string) (openapi.SendMessageResponseObject, error) {
+	original, err := h.messageRepo.GetByClientMsgID(ctx, userID, clientMsgID)
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if original.ChannelID != ch.ID {
+		return openapi.SendMessage409JSONResponse{ConflictJSONResponse: conflictResponse("This idempotency key was already used in another channel")}, nil
+	}
+	// Replaying a message deleted since would hand its content back
+	if original.DeletedAt != nil {
+		return openapi.SendMessage409JSONResponse{ConflictJSONResponse: conflictResponse("The message sent with this idempotency key has been deleted")}, nil
+	}
+
+	msgWithUser, err := h.messageRepo.GetByIDWithUser(ctx, original.ID)
+	if err != nil {
+		return nil, err
+	}
+	msgWithUser.Attachments, err = h.fileRepo.ListForMessage(ctx, original.ID)
+	if err != nil {
+		return nil, err
+	}
+	if h.linkPreviewRepo != nil {
+		if preview, err := h.linkPreviewRepo.GetForMessage(ctx, original.ID); err == nil && preview != nil {
+			msgWithUser.LinkPreview = preview
+		}
+	}
+	return openapi.SendMessage200JSONResponse{Message: messageWithUserToAPI(msgWithUser)}, nil
+}
+
+// GetChannelMessages is ListMessages with the options in the query string
+func (h *Handler) GetChannelMessages(ctx context.Context, request openapi.GetChannelMessagesRequestObject) (openapi.GetChannelMessagesResponseObject, error) {
+	fields, unknown := parseMessageFields(request.Params.Fields)
+	if unknown != "" {
+		return openapi.GetChannelMessages400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Unknown message field: "+unknown)}, nil
+	}
+
+	resp, err := h.ListMessages(ctx, openapi.ListMessagesRequestObject{
+		Id: request.Id,
+		Body: &openapi.ListMessagesInput{
+			Cursor:    request.Params.Cursor,
+			Limit:     request.Params.Limit,
+			Direction: request.Params.Direction,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch r := resp.(type) {
+	case openapi.ListMessages200JSONResponse:
+		if fields != nil {
+			return projectedMessageList{result: openapi.MessageListResult(r), fields: fields}, nil
+		}
+		return openapi.GetChannelMessages200JSONResponse(r), nil
+	case openapi.ListMessages401JSONResponse:
+		return openapi.GetChannelMessages401JSONResponse(r), nil
+	case openapi.ListMessages403JSONResponse:
+		return openapi.GetChannelMessages403JSONResponse(r), nil
+	case openapi.ListMessages404JSONResponse:
+		return openapi.GetChannelMessages404JSONResponse(r), nil
+	}
+	return nil, fmt.Errorf("unexpected ListMessages response %T", resp)
+}
+
+// ListArchivedMessages lists a channel's messages in cold storage, or the
+// replies of one archived thread
+func (h *Handler) ListArchivedMessages(ctx context.Context, request openapi.ListArchivedMessagesRequestObject) (openapi.ListArchivedMessagesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListArchivedMessages401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.ListArchivedMessages404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Check access
+	membership, err := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			if ch.Type != channel.TypePublic {
+				return openapi.ListArchivedMessages403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+			}
+			// Public channels: verify workspace membership
+			_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+			if err != nil {
+				return openapi.ListArchivedMessages403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	opts := message.ArchiveListOptions{At: request.Params.At}
+	if membership != nil {
+		opts.VisibleSince = ch.HistoryVisibleSince(membership.CreatedAt)
+	}
+	if request.Params.Cursor != nil {
+		opts.Cursor = *request.Params.Cursor
+	}
+	if request.Params.Limit != nil {
+		opts.Limit = *request.Params.Limit
+	}
+	if request.Params.Direction != nil {
+		opts.Direction = string(*request.Params.Direction)
+	}
+	if request.Params.ThreadId != nil {
+		opts.ThreadID = *request.Params.ThreadId
+	}
+
+	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
+	result, err := h.messageRepo.ListArchived(ctx, ch.ID, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Link previews, polls and receipts are not archived; attachments are
+	h.loadAttachmentsForMessages(ctx, result.Messages)
+
+	apiResult := messageListResultToAPI(result)
+	localizeMessages(ctx, apiResult.Messages)
+	h.truncateMessages(apiResult.Messages)
+	return openapi.ListArchivedMessages200JSONResponse(apiResult), nil
+}
+
+// ListMessagesByAuthor lists one user's messages in a channel
+func (h *Handler) ListMessagesByAuthor(ctx context.Context, request openapi.ListMessagesByAuthorRequestObject) (openapi.ListMessagesByAuthorResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListMessagesByAuthor401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.ListMessagesByAuthor404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Check access
+	membership, err := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			if ch.Type != channel.TypePublic {
+				return openapi.ListMessagesByAuthor403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+			}
+			// Public channels: verify workspace membership
+			_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+			if err != nil {
+				return openapi.ListMessagesByAuthor403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	if strings.TrimSpace(request.Body.UserId) == "" {
+		return openapi.ListMessagesByAuthor400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "user_id is required")}, nil
+	}
+	if request.Body.Since != nil && request.Body.Until != nil && !request.Body.Since.Before(*request.Body.Until) {
+		return openapi.ListMessagesByAuthor400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "since must be before until")}, nil
+	}
+
+	opts := message.AuthorListOptions{
+		UserID: request.Body.UserId,
+		Since:  request.Body.Since,
+		Until:  request.Body.Until,
+	}
+	if membership != nil {
+		opts.VisibleSince = ch.HistoryVisibleSince(membership.CreatedAt)
+	}
+	if request.Body.Cursor != nil {
+		opts.Cursor = *request.Body.Cursor
+	}
+	if request.Body.Limit != nil {
+		opts.Limit = *request.Body.Limit
+	}
+
+	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
+	result, err := h.messageRepo.ListByAuthor(ctx, ch.ID, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load attachments for all messages
+	h.loadAttachmentsForMessages(ctx, result.Messages)
+
+	// Load link previews for all messages
+	h.loadLinkPreviewsForMessages(ctx, result.Messages)
+
+	// Load polls for poll messages
+	h.loadPollsForMessages(ctx, result.Messages, userID)
+
+	apiResult := messageListResultToAPI(result)
+	localizeMessages(ctx, apiResult.Messages)
+	h.truncateMessages(apiResult.Messages)
+	return openapi.ListMessagesByAuthor200JSONResponse(apiResult), nil
 }
 
 // UpdateMessage updates a message
@@ -385,6 +752,11 @@ func (h *Handler) UpdateMessage(ctx context.Context, request openapi.UpdateMessa
 		return openapi.UpdateMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot edit system messages")}, nil
 	}
 
+	// The content of a poll message is its question, which is fixed
+	if msg.Type == message.MessageTypePoll {
+		return openapi.UpdateMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot edit polls")}, nil
+	}
+
 	// Only message author can edit
 	if msg.UserID == nil || *msg.UserID != userID {
 		return openapi.UpdateMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You can only edit your own messages")}, nil
@@ -399,20 +771,35 @@ func (h *Handler) UpdateMessage(ctx context.Context, request openapi.UpdateMessa
 		return openapi.UpdateMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Message content is required")}, nil
 	}
 
-	if utf8.RuneCountInString(request.Body.Content) > maxMessageLength {
-		return openapi.UpdateMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Message content exceeds maximum length of %d characters", maxMessageLength))}, nil
+	if utf8.RuneCountInString(request.Body.Content) > h.maxMessageLength {
+		return openapi.UpdateMessage400JSONResponse{BadRequestJSONResponse: messageTooLongResponse("Message content", h.maxMessageLength)}, nil
 	}
 
-	if err := h.messageRepo.Update(ctx, string(request.Id), request.Body.Content); err != nil {
+	// Get channel for workspace ID
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.UpdateMessage503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
+	content, blocked, err := h.filterContent(ctx, ch.WorkspaceID, ch.ID, userID, request.Body.Content)
+	if err != nil {
+		return nil, err
+	} else if blocked {
+		return openapi.UpdateMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeContentBlocked, errContentBlocked)}, nil
+	}
+
+	if err := h.messageRepo.Update(ctx, string(request.Id), content); err != nil {
 		return nil, err
 	}
 
 	// Get updated message with user info
 	msgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, string(request.Id))
 
-	// Get channel for workspace ID
-	ch, _ := h.channelRepo.GetByID(ctx, msg.ChannelID)
-
 	// Load attachments for the message
 	if msgWithUser != nil {
 		attachments, _ := h.fileRepo.ListForMessage(ctx, msg.ID)
@@ -427,7 +814,7 @@ func (h *Handler) UpdateMessage(ctx context.Context, request openapi.UpdateMessa
 				existingPreview = existing
 			}
 
-			newContent := strings.TrimSpace(request.Body.Content)
+			newContent := strings.TrimSpace(content)
 			newURL := ""
 			if h.linkPreviewFetcher != nil && newContent != "" {
 				newURL = linkpreview.ExtractFirstURL(newContent)
@@ -463,7 +850,7 @@ func (h *Handler) UpdateMessage(ctx context.Context, request openapi.UpdateMessa
 
 	// Broadcast update via SSE (use API type to include attachment URLs)
 	if h.hub != nil && ch != nil && msgWithUser != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessageUpdatedEvent(apiMsg))
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageUpdatedEvent(apiMsg))
 	}
 
 	return openapi.UpdateMessage200JSONResponse{
@@ -510,7 +897,7 @@ func (h *Handler) DeleteMessage(ctx context.Context, request openapi.DeleteMessa
 	// Capture content before deletion for audit log (only for admin delete)
 	isAdminDelete := msg.UserID == nil || *msg.UserID != userID
 
-	if err := h.messageRepo.Delete(ctx, string(request.Id)); err != nil {
+	if err := h.messageRepo.Delete(ctx, string(request.Id), userID); err != nil {
 		return nil, err
 	}
 
@@ -524,7 +911,7 @@ func (h *Handler) DeleteMessage(ctx context.Context, request openapi.DeleteMessa
 
 	// Broadcast delete via SSE
 	if h.hub != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessageDeletedEvent(openapi.MessageDeletedData{
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageDeletedEvent(openapi.MessageDeletedData{
 			Id:             string(request.Id),
 			ThreadParentId: msg.ThreadParentID,
 		}))
@@ -535,6 +922,60 @@ func (h *Handler) DeleteMessage(ctx context.Context, request openapi.DeleteMessa
 	}, nil
 }
 
+// RestoreMessage undeletes a message its author deleted within the undelete window
+func (h *Handler) RestoreMessage(ctx context.Context, request openapi.RestoreMessageRequestObject) (openapi.RestoreMessageResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RestoreMessage401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return openapi.RestoreMessage404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Only the author can restore, and only messages they deleted themselves
+	if msg.UserID == nil || *msg.UserID != userID {
+		return openapi.RestoreMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only the author can restore a message")}, nil
+	}
+	if msg.DeletedAt == nil {
+		return openapi.RestoreMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Message is not deleted")}, nil
+	}
+
+	if err := h.messageRepo.Restore(ctx, msg.ID, time.Now().Add(-h.undeleteWindow)); err != nil {
+		if errors.Is(err, message.ErrCannotRestoreMessage) {
+			return openapi.RestoreMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "This message can no longer be restored")}, nil
+		}
+		return nil, err
+	}
+
+	msgWithUser, err := h.messageRepo.GetByIDWithUser(ctx, msg.ID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, _ := h.fileRepo.ListForMessage(ctx, msg.ID)
+	msgWithUser.Attachments = attachments
+	if h.linkPreviewRepo != nil {
+		if preview, err := h.linkPreviewRepo.GetForMessage(ctx, msg.ID); err == nil {
+			msgWithUser.LinkPreview = preview
+		}
+	}
+	apiMsg := messageWithUserToAPI(msgWithUser)
+
+	if h.hub != nil {
+		if ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID); err == nil {
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageRestoredEvent(apiMsg))
+		}
+	}
+
+	return openapi.RestoreMessage200JSONResponse{
+		Message: apiMsg,
+	}, nil
+}
+
 // DeleteLinkPreview deletes the link preview for a message
 func (h *Handler) DeleteLinkPreview(ctx context.Context, request openapi.DeleteLinkPreviewRequestObject) (openapi.DeleteLinkPreviewResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -564,7 +1005,7 @@ func (h *Handler) DeleteLinkPreview(ctx context.Context, request openapi.DeleteL
 			attachments, _ := h.fileRepo.ListForMessage(ctx, msg.ID)
 			msgWithUser.Attachments = attachments
 			apiMsg := messageWithUserToAPI(msgWithUser)
-			h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessageUpdatedEvent(apiMsg))
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageUpdatedEvent(apiMsg))
 		}
 	}
 
@@ -596,6 +1037,11 @@ func (h *Handler) AddReaction(ctx context.Context, request openapi.AddReactionRe
 	if ban != nil {
 		return openapi.AddReaction403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
 	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.AddReaction503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
 
 	_, err = h.channelRepo.GetMembership(ctx, userID, msg.ChannelID)
 	if err != nil {
@@ -608,59 +1054,247 @@ func (h *Handler) AddReaction(ctx context.Context, request openapi.AddReactionRe
 		}
 	}
 
-	if strings.TrimSpace(request.Body.Emoji) == "" {
-		return openapi.AddReaction400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Emoji is required")}, nil
+	if strings.TrimSpace(request.Body.Emoji) == "" {
+		return openapi.AddReaction400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Emoji is required")}, nil
+	}
+
+	reaction, err := h.messageRepo.AddReaction(ctx, string(request.Id), userID, request.Body.Emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	apiReaction := reactionToAPI(reaction)
+
+	// Broadcast reaction via SSE
+	if h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewReactionAddedEvent(apiReaction))
+	}
+
+	h.recordReactionActivity(ctx, ch, msg, userID, request.Body.Emoji)
+
+	return openapi.AddReaction200JSONResponse{
+		Reaction: apiReaction,
+	}, nil
+}
+
+// RemoveReaction removes a reaction from a message
+func (h *Handler) RemoveReaction(ctx context.Context, request openapi.RemoveReactionRequestObject) (openapi.RemoveReactionResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RemoveReaction401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.RemoveReaction503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
+	err = h.messageRepo.RemoveReaction(ctx, string(request.Id), userID, request.Body.Emoji)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.activityRepo.DeleteReaction(ctx, msg.ID, userID, request.Body.Emoji); err != nil {
+		slog.Error("failed to remove reaction activity", "message_id", msg.ID, "error", err)
+	}
+
+	// Broadcast removal via SSE
+	if h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewReactionRemovedEvent(openapi.ReactionRemovedData{
+			MessageId: string(request.Id),
+			UserId:    userID,
+			Emoji:     request.Body.Emoji,
+		}))
+	}
+
+	return openapi.RemoveReaction200JSONResponse{
+		Success: true,
+	}, nil
+}
+
+// maxReactionBatchOps bounds the number of operations in one reaction batch
+const maxReactionBatchOps = 50
+
+// BatchReactions applies several reaction adds and removes for the current user
+func (h *Handler) BatchReactions(ctx context.Context, request openapi.BatchReactionsRequestObject) (openapi.BatchReactionsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.BatchReactions401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return openapi.BatchReactions404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Check channel membership
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user is banned from the workspace
+	ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID)
+	if ban != nil {
+		return openapi.BatchReactions403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.BatchReactions503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
+	_, err = h.channelRepo.GetMembership(ctx, userID, msg.ChannelID)
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			if ch.Type != channel.TypePublic {
+				return openapi.BatchReactions403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	if len(request.Body.Operations) == 0 {
+		return openapi.BatchReactions400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "At least one operation is required")}, nil
+	}
+	if len(request.Body.Operations) > maxReactionBatchOps {
+		return openapi.BatchReactions400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("At most %d operations are allowed", maxReactionBatchOps))}, nil
 	}
 
-	reaction, err := h.messageRepo.AddReaction(ctx, string(request.Id), userID, request.Body.Emoji)
+	ops := make([]message.ReactionOp, len(request.Body.Operations))
+	for i, op := range request.Body.Operations {
+		if op.Op != openapi.ReactionOperationAdd && op.Op != openapi.ReactionOperationRemove {
+			return openapi.BatchReactions400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Operation must be add or remove")}, nil
+		}
+		if strings.TrimSpace(op.Emoji) == "" {
+			return openapi.BatchReactions400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Emoji is required")}, nil
+		}
+		ops[i] = message.ReactionOp{Op: string(op.Op), Emoji: op.Emoji}
+	}
+
+	result, err := h.messageRepo.ApplyReactionBatch(ctx, msg.ID, userID, ops)
 	if err != nil {
 		return nil, err
 	}
 
-	apiReaction := reactionToAPI(reaction)
+	added := make([]openapi.Reaction, len(result.Added))
+	for i := range result.Added {
+		added[i] = reactionToAPI(&result.Added[i])
+	}
+	for _, emoji := range result.Removed {
+		if err := h.activityRepo.DeleteReaction(ctx, msg.ID, userID, emoji); err != nil {
+			slog.Error("failed to remove reaction activity", "message_id", msg.ID, "error", err)
+		}
+	}
 
-	// Broadcast reaction via SSE
-	if h.hub != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewReactionAddedEvent(apiReaction))
+	// Broadcast the net change as a single event
+	if h.hub != nil && (len(added) > 0 || len(result.Removed) > 0) {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewReactionBatchEvent(openapi.ReactionBatchData{
+			MessageId: msg.ID,
+			UserId:    userID,
+			Added:     added,
+			Removed:   result.Removed,
+		}))
 	}
 
-	return openapi.AddReaction200JSONResponse{
-		Reaction: apiReaction,
+	for _, r := range result.Added {
+		h.recordReactionActivity(ctx, ch, msg, userID, r.Emoji)
+	}
+
+	return openapi.BatchReactions200JSONResponse{
+		Added:   added,
+		Removed: result.Removed,
 	}, nil
 }
 
-// RemoveReaction removes a reaction from a message
-func (h *Handler) RemoveReaction(ctx context.Context, request openapi.RemoveReactionRequestObject) (openapi.RemoveReactionResponseObject, error) {
+// ListMessageReactions lists who reacted to a message, optionally for one
+// emoji, with per-emoji counts
+func (h *Handler) ListMessageReactions(ctx context.Context, request openapi.ListMessageReactionsRequestObject) (openapi.ListMessageReactionsResponseObject, error) {
 	userID := h.getUserID(ctx)
 	if userID == "" {
-		return openapi.RemoveReaction401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+		return openapi.ListMessageReactions401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
 	}
 
 	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
 	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return openapi.ListMessageReactions404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}, nil
+		}
 		return nil, err
 	}
 
-	err = h.messageRepo.RemoveReaction(ctx, string(request.Id), userID, request.Body.Emoji)
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
 	if err != nil {
 		return nil, err
 	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return openapi.ListMessageReactions403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+	}
+
+	// Messages from before the user joined may be hidden by the channel's history policy
+	membership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if err != nil && !errors.Is(err, channel.ErrNotChannelMember) {
+		return nil, err
+	}
+	if membership != nil {
+		if since := ch.HistoryVisibleSince(membership.CreatedAt); since != nil && msg.CreatedAt.Before(*since) {
+			return openapi.ListMessageReactions404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}, nil
+		}
+	}
 
-	// Get channel for broadcasting
-	ch, _ := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	opts := message.ListOptions{}
+	if request.Params.Cursor != nil {
+		opts.Cursor = *request.Params.Cursor
+	}
+	if request.Params.Limit != nil {
+		opts.Limit = *request.Params.Limit
+	}
+	var emoji string
+	if request.Params.Emoji != nil {
+		emoji = *request.Params.Emoji
+	}
 
-	// Broadcast removal via SSE
-	if h.hub != nil && ch != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewReactionRemovedEvent(openapi.ReactionRemovedData{
-			MessageId: string(request.Id),
-			UserId:    userID,
-			Emoji:     request.Body.Emoji,
-		}))
+	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
+	result, err := h.messageRepo.ListReactors(ctx, msg.ID, emoji, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := h.messageRepo.CountReactions(ctx, msg.ID, userID, filter)
+	if err != nil {
+		return nil, err
 	}
 
-	return openapi.RemoveReaction200JSONResponse{
-		Success: true,
-	}, nil
+	reactors := make([]openapi.Reactor, len(result.Reactors))
+	for i, r := range result.Reactors {
+		reactors[i] = reactorToAPI(&r)
+	}
+	apiCounts := make([]openapi.ReactionCount, len(counts))
+	for i, c := range counts {
+		apiCounts[i] = openapi.ReactionCount{Emoji: c.Emoji, Count: c.Count, Reacted: c.Reacted}
+	}
+
+	resp := openapi.ListMessageReactions200JSONResponse{
+		Reactors: reactors,
+		Counts:   apiCounts,
+		HasMore:  result.HasMore,
+	}
+	if result.NextCursor != "" {
+		resp.NextCursor = &result.NextCursor
+	}
+	return resp, nil
 }
 
 // ListThread lists thread replies
@@ -681,7 +1315,7 @@ func (h *Handler) ListThread(ctx context.Context, request openapi.ListThreadRequ
 		return nil, err
 	}
 
-	_, err = h.channelRepo.GetMembership(ctx, userID, msg.ChannelID)
+	membership, err := h.channelRepo.GetMembership(ctx, userID, msg.ChannelID)
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			if ch.Type != channel.TypePublic {
@@ -698,6 +1332,9 @@ func (h *Handler) ListThread(ctx context.Context, request openapi.ListThreadRequ
 	}
 
 	opts := message.ListOptions{}
+	if membership != nil {
+		opts.VisibleSince = ch.HistoryVisibleSince(membership.CreatedAt)
+	}
 	if request.Body != nil {
 		if request.Body.Cursor != nil {
 			opts.Cursor = *request.Body.Cursor
@@ -705,6 +1342,9 @@ func (h *Handler) ListThread(ctx context.Context, request openapi.ListThreadRequ
 		if request.Body.Limit != nil {
 			opts.Limit = *request.Body.Limit
 		}
+		if request.Body.Direction != nil {
+			opts.Direction = string(*request.Body.Direction)
+		}
 	}
 
 	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
@@ -719,7 +1359,114 @@ func (h *Handler) ListThread(ctx context.Context, request openapi.ListThreadRequ
 	// Load link previews for all messages
 	h.loadLinkPreviewsForMessages(ctx, result.Messages)
 
-	return openapi.ListThread200JSONResponse(messageListResultToAPI(result)), nil
+	// Load polls for poll messages
+	h.loadPollsForMessages(ctx, result.Messages, userID)
+
+	apiResult := messageListResultToAPI(result)
+	localizeMessages(ctx, apiResult.Messages)
+	h.truncateMessages(apiResult.Messages)
+	return openapi.ListThread200JSONResponse(apiResult), nil
+}
+
+// GetThreadReplies is ListThread with the options in the query string
+func (h *Handler) GetThreadReplies(ctx context.Context, request openapi.GetThreadRepliesRequestObject) (openapi.GetThreadRepliesResponseObject, error) {
+	fields, unknown := parseMessageFields(request.Params.Fields)
+	if unknown != "" {
+		return openapi.GetThreadReplies400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Unknown message field: "+unknown)}, nil
+	}
+
+	resp, err := h.ListThread(ctx, openapi.ListThreadRequestObject{
+		Id: request.Id,
+		Body: &openapi.ListMessagesInput{
+			Cursor:    request.Params.Cursor,
+			Limit:     request.Params.Limit,
+			Direction: request.Params.Direction,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch r := resp.(type) {
+	case openapi.ListThread200JSONResponse:
+		if fields != nil {
+			return projectedMessageList{result: openapi.MessageListResult(r), fields: fields}, nil
+		}
+		return openapi.GetThreadReplies200JSONResponse(r), nil
+	case openapi.ListThread401JSONResponse:
+		return openapi.GetThreadReplies401JSONResponse(r), nil
+	case openapi.ListThread403JSONResponse:
+		return openapi.GetThreadReplies403JSONResponse(r), nil
+	case openapi.ListThread404JSONResponse:
+		return openapi.GetThreadReplies404JSONResponse(r), nil
+	}
+	return nil, fmt.Errorf("unexpected ListThread response %T", resp)
+}
+
+// ListThreadParticipants lists everyone who has replied to a thread
+func (h *Handler) ListThreadParticipants(ctx context.Context, request openapi.ListThreadParticipantsRequestObject) (openapi.ListThreadParticipantsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListThreadParticipants401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return openapi.ListThreadParticipants404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Check channel access
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = h.channelRepo.GetMembership(ctx, userID, msg.ChannelID)
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			if ch.Type != channel.TypePublic {
+				return openapi.ListThreadParticipants403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+			}
+			// Verify workspace membership for public channels
+			_, err = h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+			if err != nil {
+				return openapi.ListThreadParticipants403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	opts := message.ListOptions{}
+	if request.Params.Cursor != nil {
+		opts.Cursor = *request.Params.Cursor
+	}
+	if request.Params.Limit != nil {
+		opts.Limit = *request.Params.Limit
+	}
+
+	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
+	result, err := h.messageRepo.ListThreadParticipants(ctx, msg.ID, opts, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]openapi.ThreadParticipant, len(result.Participants))
+	for i, p := range result.Participants {
+		participants[i] = threadParticipantToAPI(&p)
+	}
+
+	resp := openapi.ListThreadParticipants200JSONResponse{
+		Participants:     participants,
+		ParticipantCount: result.TotalCount,
+		HasMore:          result.HasMore,
+	}
+	if result.NextCursor != "" {
+		resp.NextCursor = &result.NextCursor
+	}
+	return resp, nil
 }
 
 // SearchMessages searches messages in a workspace
@@ -757,35 +1504,50 @@ func (h *Handler) SearchMessages(ctx context.Context, request openapi.SearchMess
 	if request.Body.Limit != nil {
 		opts.Limit = *request.Body.Limit
 	}
-	if request.Body.Offset != nil {
+	if request.Body.Cursor != nil {
+		opts.Cursor = *request.Body.Cursor
+	}
+	if request.Body.Offset != nil && *request.Body.Offset > 0 {
+		if !h.currentSettings().SearchOffset {
+			return openapi.SearchMessages400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "offset is no longer supported; page with cursor instead")}, nil
+		}
+		if opts.Cursor != "" {
+			return openapi.SearchMessages400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Use either cursor or offset, not both")}, nil
+		}
 		opts.Offset = *request.Body.Offset
 	}
 
 	filter := &moderation.FilterOptions{WorkspaceID: string(request.Wid), RequestingUserID: userID}
 	result, err := h.messageRepo.Search(ctx, string(request.Wid), userID, opts, filter)
 	if err != nil {
+		if errors.Is(err, message.ErrInvalidCursor) {
+			return openapi.SearchMessages400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid cursor")}, nil
+		}
 		return nil, err
 	}
 
-	return openapi.SearchMessages200JSONResponse(searchResultToAPI(result)), nil
+	apiResult := searchResultToAPI(result)
+	h.truncateSearchMessages(apiResult.Messages)
+	return openapi.SearchMessages200JSONResponse(apiResult), nil
 }
 
 // searchMessageToAPI converts a message.SearchMessage to openapi.SearchMessage
 func searchMessageToAPI(m *message.SearchMessage) openapi.SearchMessage {
 	apiMsg := openapi.SearchMessage{
-		Id:             m.ID,
-		ChannelId:      m.ChannelID,
-		UserId:         m.UserID,
-		Content:        m.Content,
-		ThreadParentId: m.ThreadParentID,
-		ReplyCount:     m.ReplyCount,
-		LastReplyAt:    m.LastReplyAt,
-		EditedAt:       m.EditedAt,
-		DeletedAt:      m.DeletedAt,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
-		ChannelName:    m.ChannelName,
-		ChannelType:    openapi.ChannelType(m.ChannelType),
+		Id:              m.ID,
+		ChannelId:       m.ChannelID,
+		UserId:          m.UserID,
+		Content:         m.Content,
+		ContentRendered: renderContent(m.Content, m.DeletedAt),
+		ThreadParentId:  m.ThreadParentID,
+		ReplyCount:      m.ReplyCount,
+		LastReplyAt:     m.LastReplyAt,
+		EditedAt:        m.EditedAt,
+		DeletedAt:       m.DeletedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		ChannelName:     m.ChannelName,
+		ChannelType:     openapi.ChannelType(m.ChannelType),
 	}
 	if m.UserDisplayName != "" {
 		apiMsg.UserDisplayName = &m.UserDisplayName
@@ -793,6 +1555,15 @@ func searchMessageToAPI(m *message.SearchMessage) openapi.SearchMessage {
 	if m.UserAvatarURL != nil {
 		apiMsg.UserAvatarUrl = m.UserAvatarURL
 	}
+	if m.UserIsDeactivated {
+		apiMsg.UserIsDeactivated = &m.UserIsDeactivated
+	}
+	if m.IsBot {
+		apiMsg.IsBot = &m.IsBot
+	}
+	if m.Archived {
+		apiMsg.Archived = &m.Archived
+	}
 	if g := gravatar.URL(m.UserEmail); g != "" {
 		apiMsg.UserGravatarUrl = &g
 	}
@@ -809,30 +1580,46 @@ func searchResultToAPI(result *message.SearchResult) openapi.SearchMessagesResul
 	for i, m := range result.Messages {
 		messages[i] = searchMessageToAPI(&m)
 	}
-	return openapi.SearchMessagesResult{
+	apiResult := openapi.SearchMessagesResult{
 		Messages:   messages,
 		TotalCount: result.TotalCount,
 		HasMore:    result.HasMore,
 		Query:      result.Query,
 	}
+	if result.TotalCountApproximate {
+		apiResult.TotalCountApproximate = &result.TotalCountApproximate
+	}
+	if result.NextCursor != "" {
+		apiResult.NextCursor = &result.NextCursor
+	}
+	return apiResult
 }
 
-// messageWithUserToAPI converts a message.MessageWithUser to openapi.MessageWithUser
+// messageWithUserToAPI converts a message.MessageWithUser to openapi.MessageWithUser.
+// System message text is rendered in the default locale; responses to a
+// request are localized with localizeMessages.
 func messageWithUserToAPI(m *message.MessageWithUser) openapi.MessageWithUser {
+	content := m.Content
+	if m.SystemEvent != nil {
+		content = m.SystemEvent.Text(i18n.Default)
+	}
 	apiMsg := openapi.MessageWithUser{
-		Id:             m.ID,
-		ChannelId:      m.ChannelID,
-		UserId:         m.UserID,
-		Content:        m.Content,
-		ThreadParentId: m.ThreadParentID,
-		ReplyCount:     m.ReplyCount,
-		LastReplyAt:    m.LastReplyAt,
-		EditedAt:       m.EditedAt,
-		DeletedAt:      m.DeletedAt,
-		PinnedAt:       m.PinnedAt,
-		PinnedBy:       m.PinnedBy,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
+		Id:              m.ID,
+		ChannelId:       m.ChannelID,
+		UserId:          m.UserID,
+		Content:         content,
+		ContentRendered: renderContent(content, m.DeletedAt),
+		ThreadParentId:  m.ThreadParentID,
+		ReplyCount:      m.ReplyCount,
+		LastReplyAt:     m.LastReplyAt,
+		EditedAt:        m.EditedAt,
+		DeletedAt:       m.DeletedAt,
+		PinnedAt:        m.PinnedAt,
+		PinnedBy:        m.PinnedBy,
+		WebhookId:       m.WebhookID,
+		AnnouncementId:  m.AnnouncementID,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
 	}
 	if m.AlsoSendToChannel {
 		apiMsg.AlsoSendToChannel = &m.AlsoSendToChannel
@@ -863,9 +1650,24 @@ func messageWithUserToAPI(m *message.MessageWithUser) openapi.MessageWithUser {
 		if m.SystemEvent.ChannelType != nil {
 			apiMsg.SystemEvent.ChannelType = m.SystemEvent.ChannelType
 		}
+		if m.SystemEvent.Topic != nil {
+			apiMsg.SystemEvent.Topic = m.SystemEvent.Topic
+		}
 		if m.SystemEvent.MessageID != nil {
 			apiMsg.SystemEvent.MessageId = m.SystemEvent.MessageID
 		}
+		if m.SystemEvent.WelcomeReason != nil {
+			reason := openapi.SystemEventDataWelcomeReason(*m.SystemEvent.WelcomeReason)
+			apiMsg.SystemEvent.WelcomeReason = &reason
+		}
+		if m.SystemEvent.CallID != nil {
+			apiMsg.SystemEvent.CallId = m.SystemEvent.CallID
+			apiMsg.SystemEvent.CallDurationSeconds = m.SystemEvent.CallDurationSeconds
+			apiMsg.SystemEvent.CallParticipantCount = m.SystemEvent.CallParticipantCount
+		}
+		if m.SystemEvent.UserCount != nil {
+			apiMsg.SystemEvent.UserCount = m.SystemEvent.UserCount
+		}
 	}
 	if m.UserDisplayName != "" {
 		apiMsg.UserDisplayName = &m.UserDisplayName
@@ -873,6 +1675,12 @@ func messageWithUserToAPI(m *message.MessageWithUser) openapi.MessageWithUser {
 	if m.UserAvatarURL != nil {
 		apiMsg.UserAvatarUrl = m.UserAvatarURL
 	}
+	if m.UserIsDeactivated {
+		apiMsg.UserIsDeactivated = &m.UserIsDeactivated
+	}
+	if m.IsBot {
+		apiMsg.IsBot = &m.IsBot
+	}
 	if g := gravatar.URL(m.UserEmail); g != "" {
 		apiMsg.UserGravatarUrl = &g
 	}
@@ -889,6 +1697,7 @@ func messageWithUserToAPI(m *message.MessageWithUser) openapi.MessageWithUser {
 			participants[i] = threadParticipantToAPI(&p)
 		}
 		apiMsg.ThreadParticipants = &participants
+		apiMsg.ThreadParticipantCount = &m.ThreadParticipantCount
 	}
 	if len(m.Attachments) > 0 {
 		attachments := make([]openapi.Attachment, len(m.Attachments))
@@ -901,6 +1710,17 @@ func messageWithUserToAPI(m *message.MessageWithUser) openapi.MessageWithUser {
 		lp := linkPreviewToAPI(m.LinkPreview)
 		apiMsg.LinkPreview = &lp
 	}
+	if len(m.ReadReceipts) > 0 {
+		receipts := make([]openapi.MessageReceipt, len(m.ReadReceipts))
+		for i, r := range m.ReadReceipts {
+			receipts[i] = openapi.MessageReceipt{UserId: r.UserID, ReadAt: r.ReadAt}
+		}
+		apiMsg.ReadReceipts = &receipts
+	}
+	if m.Poll != nil {
+		p := pollToAPI(m.Poll)
+		apiMsg.Poll = &p
+	}
 	return apiMsg
 }
 
@@ -913,6 +1733,9 @@ func attachmentToAPI(a *file.Attachment) openapi.Attachment {
 		ContentType: a.ContentType,
 		SizeBytes:   a.SizeBytes,
 		Url:         url,
+		Status:      openapi.AttachmentStatus(a.Status),
+		Width:       a.Width,
+		Height:      a.Height,
 		CreatedAt:   a.CreatedAt,
 	}
 }
@@ -940,6 +1763,51 @@ func (h *Handler) loadAttachmentsForMessages(ctx context.Context, messages []mes
 	}
 }
 
+// loadReceiptsForMessages loads read receipts for a slice of messages in batch
+func (h *Handler) loadReceiptsForMessages(ctx context.Context, messages []message.MessageWithUser) {
+	if len(messages) == 0 {
+		return
+	}
+
+	messageIDs := make([]string, len(messages))
+	for i, m := range messages {
+		messageIDs[i] = m.ID
+	}
+
+	receiptsMap, err := h.messageRepo.GetReceiptsForMessages(ctx, messageIDs)
+	if err != nil {
+		return
+	}
+
+	for i := range messages {
+		if receipts, ok := receiptsMap[messages[i].ID]; ok {
+			messages[i].ReadReceipts = receipts
+		}
+	}
+}
+
+// readReceiptsEnabled reports whether the workspace shares DM read receipts
+func (h *Handler) readReceiptsEnabled(ctx context.Context, workspaceID string) bool {
+	ws, err := h.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return false
+	}
+	return ws.ParsedSettings().DMReadReceipts
+}
+
+// linkPreviewsEnabled reports whether external links are unfurled in the workspace
+func (h *Handler) linkPreviewsEnabled(ctx context.Context, workspaceID string) bool {
+	ws, err := h.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return false
+	}
+	return ws.ParsedSettings().LinkPreviews
+}
+
+func isDMChannel(ch *channel.Channel) bool {
+	return ch.Type == channel.TypeDM || ch.Type == channel.TypeGroupDM
+}
+
 // resolveInternalMessagePreview checks if the URL is an internal message link,
 // looks up the referenced message in the database, persists the preview row,
 // and returns it. Returns nil if the URL is not an internal link or the message
@@ -1032,6 +1900,10 @@ func (h *Handler) fetchLinkPreview(ctx context.Context, url, msgID, channelID, w
 		return nil
 	}
 
+	if !h.linkPreviewFetcher.Allowed(url) || !h.linkPreviewsEnabled(ctx, workspaceID) {
+		return nil
+	}
+
 	cached, cacheErr := h.linkPreviewRepo.GetCachedURL(ctx, url)
 	if cacheErr != nil {
 		slog.Error("link preview cache lookup failed", "url", url, "error", cacheErr)
@@ -1047,26 +1919,26 @@ func (h *Handler) fetchLinkPreview(ctx context.Context, url, msgID, channelID, w
 			SiteName:    cached.SiteName,
 		}
 		if err := h.linkPreviewRepo.CreatePreview(ctx, preview); err != nil {
-			slog.Error("link preview create failed", "url", url, "error", err)
+			slog.ErrorContext(ctx, "link preview create failed", "url", url, "error", err)
 		}
 		return preview
 	}
 	if cached == nil && cacheErr == nil {
 		// Cache miss — fetch asynchronously
 		go func() {
-			bgCtx := context.Background()
+			bgCtx := logging.Detach(ctx)
 			p, fetchErr := h.linkPreviewFetcher.FetchPreview(bgCtx, url)
 			if fetchErr != nil {
-				slog.Error("link preview fetch failed", "url", url, "error", fetchErr)
+				slog.ErrorContext(bgCtx, "link preview fetch failed", "url", url, "error", fetchErr)
 				return
 			}
 			if p == nil {
-				slog.Debug("link preview returned no data", "url", url)
+				slog.DebugContext(bgCtx, "link preview returned no data", "url", url)
 				return
 			}
 			p.MessageID = msgID
 			if createErr := h.linkPreviewRepo.CreatePreview(bgCtx, p); createErr != nil {
-				slog.Error("link preview create failed", "url", url, "error", createErr)
+				slog.ErrorContext(bgCtx, "link preview create failed", "url", url, "error", createErr)
 				return
 			}
 			// Re-load full message and broadcast update
@@ -1080,7 +1952,7 @@ func (h *Handler) fetchLinkPreview(ctx context.Context, url, msgID, channelID, w
 			updated.LinkPreview = p
 			apiUpdated := messageWithUserToAPI(updated)
 			if h.hub != nil && workspaceID != "" {
-				h.hub.BroadcastToChannel(workspaceID, channelID, sse.NewMessageUpdatedEvent(apiUpdated))
+				h.hub.BroadcastToChannel(bgCtx, workspaceID, channelID, sse.NewMessageUpdatedEvent(apiUpdated))
 			}
 		}()
 	}
@@ -1280,9 +2152,30 @@ func threadParticipantToAPI(p *message.ThreadParticipant) openapi.ThreadParticip
 	if g := gravatar.URL(p.Email); g != "" {
 		participant.GravatarUrl = &g
 	}
+	if p.IsDeactivated {
+		participant.IsDeactivated = &p.IsDeactivated
+	}
 	return participant
 }
 
+// reactorToAPI converts a message.Reactor to openapi.Reactor
+func reactorToAPI(r *message.Reactor) openapi.Reactor {
+	reactor := openapi.Reactor{
+		UserId:      r.UserID,
+		DisplayName: r.DisplayName,
+		AvatarUrl:   r.AvatarURL,
+		Emoji:       r.Emoji,
+		CreatedAt:   r.CreatedAt,
+	}
+	if g := gravatar.URL(r.Email); g != "" {
+		reactor.GravatarUrl = &g
+	}
+	if r.IsDeactivated {
+		reactor.IsDeactivated = &r.IsDeactivated
+	}
+	return reactor
+}
+
 // reactionToAPI converts a message.Reaction to openapi.Reaction
 func reactionToAPI(r *message.Reaction) openapi.Reaction {
 	return openapi.Reaction{
@@ -1308,6 +2201,9 @@ func messageListResultToAPI(result *message.ListResult) openapi.MessageListResul
 	if result.HasNewer {
 		apiResult.HasNewer = &result.HasNewer
 	}
+	if result.HasOlder {
+		apiResult.HasOlder = &result.HasOlder
+	}
 	if result.NextCursor != "" {
 		apiResult.NextCursor = &result.NextCursor
 	}
@@ -1336,7 +2232,7 @@ func (h *Handler) GetMessage(ctx context.Context, request openapi.GetMessageRequ
 		return openapi.GetMessage404JSONResponse{}, nil
 	}
 
-	_, err = h.channelRepo.GetMembership(ctx, userID, msgWithUser.ChannelID)
+	membership, err := h.channelRepo.GetMembership(ctx, userID, msgWithUser.ChannelID)
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			if ch.Type != channel.TypePublic {
@@ -1352,6 +2248,13 @@ func (h *Handler) GetMessage(ctx context.Context, request openapi.GetMessageRequ
 		}
 	}
 
+	// Messages from before the user joined may be hidden by the channel's history policy
+	if membership != nil {
+		if since := ch.HistoryVisibleSince(membership.CreatedAt); since != nil && msgWithUser.CreatedAt.Before(*since) {
+			return openapi.GetMessage404JSONResponse{}, nil
+		}
+	}
+
 	// Load reactions for the message
 	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
 	reactions, err := h.messageRepo.GetReactionsForMessage(ctx, msgWithUser.ID, filter)
@@ -1371,20 +2274,91 @@ func (h *Handler) GetMessage(ctx context.Context, request openapi.GetMessageRequ
 		}
 	}
 
+	// Load the poll of a poll message
+	if msgWithUser.Type == message.MessageTypePoll {
+		if polls, err := h.pollRepo.ListForMessages(ctx, []string{msgWithUser.ID}, userID); err == nil {
+			msgWithUser.Poll = polls[msgWithUser.ID]
+		}
+	}
+
 	// Load thread participants if this is a parent message with replies
 	if msgWithUser.ReplyCount > 0 {
-		participants, err := h.messageRepo.GetThreadParticipants(ctx, msgWithUser.ID, filter)
+		participants, count, err := h.messageRepo.GetThreadParticipants(ctx, msgWithUser.ID, filter)
 		if err == nil {
 			msgWithUser.ThreadParticipants = participants
+			msgWithUser.ThreadParticipantCount = count
 		}
 	}
 
 	apiMsg := messageWithUserToAPI(msgWithUser)
+	localizeMessage(ctx, &apiMsg)
 	return openapi.GetMessage200JSONResponse{
 		Message: apiMsg,
 	}, nil
 }
 
+// GetMessagePermalink resolves a message to its canonical URL and the context
+// needed to open it. Messages the caller cannot see are reported as not found.
+func (h *Handler) GetMessagePermalink(ctx context.Context, request openapi.GetMessagePermalinkRequestObject) (openapi.GetMessagePermalinkResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetMessagePermalink401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	notFound := openapi.GetMessagePermalink404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}
+
+	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return notFound, nil
+		}
+		return nil, err
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	if err != nil {
+		return notFound, nil
+	}
+
+	membership, err := h.channelRepo.GetMembership(ctx, userID, msg.ChannelID)
+	if err != nil {
+		if !errors.Is(err, channel.ErrNotChannelMember) {
+			return nil, err
+		}
+		if ch.Type != channel.TypePublic {
+			return notFound, nil
+		}
+		if _, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID); err != nil {
+			return notFound, nil
+		}
+	}
+	if membership != nil {
+		if since := ch.HistoryVisibleSince(membership.CreatedAt); since != nil && msg.CreatedAt.Before(*since) {
+			return notFound, nil
+		}
+	}
+
+	// Replies that are only in the thread aren't in the channel listing, so
+	// the channel view centers on their parent instead
+	link := fmt.Sprintf("%s/workspaces/%s/channels/%s?", h.publicURL, ch.WorkspaceID, ch.ID)
+	cursor := msg.ID
+	if msg.ThreadParentID != nil {
+		link += "thread=" + *msg.ThreadParentID + "&"
+		if !msg.AlsoSendToChannel {
+			cursor = *msg.ThreadParentID
+		}
+	}
+	link += "msg=" + msg.ID
+
+	return openapi.GetMessagePermalink200JSONResponse{
+		Url:            link,
+		WorkspaceId:    ch.WorkspaceID,
+		ChannelId:      ch.ID,
+		MessageId:      msg.ID,
+		ThreadParentId: msg.ThreadParentID,
+		Cursor:         cursor,
+	}, nil
+}
+
 // MarkMessageUnread marks a message as unread by setting last_read to the previous message
 func (h *Handler) MarkMessageUnread(ctx context.Context, request openapi.MarkMessageUnreadRequestObject) (openapi.MarkMessageUnreadResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -1435,7 +2409,7 @@ func (h *Handler) MarkMessageUnread(ctx context.Context, request openapi.MarkMes
 
 	// Broadcast to user's other clients
 	if h.hub != nil {
-		h.hub.BroadcastToUser(ch.WorkspaceID, userID, sse.NewChannelReadEvent(openapi.ChannelReadEventData{
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewChannelReadEvent(openapi.ChannelReadEventData{
 			ChannelId:         msg.ChannelID,
 			LastReadMessageId: prevMessageID,
 		}))
@@ -1514,7 +2488,7 @@ func (h *Handler) PinMessage(ctx context.Context, request openapi.PinMessageRequ
 
 	// Pin (enforces 50-pin limit in transaction)
 	if err := h.messageRepo.PinMessage(ctx, string(request.Id), userID); err != nil {
-		if err.Error() == "maximum of 50 pinned messages per channel" {
+		if errors.Is(err, message.ErrTooManyPins) {
 			return openapi.PinMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, err.Error())}, nil
 		}
 		return nil, err
@@ -1545,13 +2519,13 @@ func (h *Handler) PinMessage(ctx context.Context, request openapi.PinMessageRequ
 
 	// Broadcast SSE events
 	if h.hub != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessagePinnedEvent(apiMsg))
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessagePinnedEvent(apiMsg))
 
 		// Broadcast system message
 		if sysMsg != nil {
 			sysMsgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, sysMsg.ID)
 			if sysMsgWithUser != nil {
-				h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessageNewEvent(messageWithUserToAPI(sysMsgWithUser)))
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageNewEvent(messageWithUserToAPI(sysMsgWithUser)))
 			}
 		}
 	}
@@ -1619,13 +2593,13 @@ func (h *Handler) UnpinMessage(ctx context.Context, request openapi.UnpinMessage
 
 	// Broadcast SSE event
 	if h.hub != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessageUnpinnedEvent(apiMsg))
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageUnpinnedEvent(apiMsg))
 
 		// Broadcast system message
 		if sysMsg != nil {
 			sysMsgWithUser, _ := h.messageRepo.GetByIDWithUser(ctx, sysMsg.ID)
 			if sysMsgWithUser != nil {
-				h.hub.BroadcastToChannel(ch.WorkspaceID, msg.ChannelID, sse.NewMessageNewEvent(messageWithUserToAPI(sysMsgWithUser)))
+				h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, msg.ChannelID, sse.NewMessageNewEvent(messageWithUserToAPI(sysMsgWithUser)))
 			}
 		}
 	}
@@ -1646,7 +2620,7 @@ func (h *Handler) ListPinnedMessages(ctx context.Context, request openapi.ListPi
 	}
 
 	// Check channel membership
-	_, memberErr := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	membership, memberErr := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
 	if memberErr != nil {
 		// For public channels, check workspace membership
 		if ch.Type == channel.TypePublic {
@@ -1670,8 +2644,14 @@ func (h *Handler) ListPinnedMessages(ctx context.Context, request openapi.ListPi
 		}
 	}
 
+	// Pins from before the user joined may be hidden by the channel's history policy
+	var visibleSince *time.Time
+	if membership != nil {
+		visibleSince = ch.HistoryVisibleSince(membership.CreatedAt)
+	}
+
 	filter := &moderation.FilterOptions{WorkspaceID: ch.WorkspaceID, RequestingUserID: userID}
-	messages, hasMore, nextCursor, err := h.messageRepo.ListPinnedMessages(ctx, string(request.Id), cursor, limit, filter)
+	messages, hasMore, nextCursor, err := h.messageRepo.ListPinnedMessages(ctx, string(request.Id), cursor, limit, visibleSince, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -1680,6 +2660,8 @@ func (h *Handler) ListPinnedMessages(ctx context.Context, request openapi.ListPi
 	for i, m := range messages {
 		apiMessages[i] = messageWithUserToAPI(&m)
 	}
+	localizeMessages(ctx, apiMessages)
+	h.truncateMessages(apiMessages)
 
 	return openapi.ListPinnedMessages200JSONResponse{
 		Messages:   apiMessages,
@@ -1687,3 +2669,59 @@ func (h *Handler) ListPinnedMessages(ctx context.Context, request openapi.ListPi
 		NextCursor: &nextCursor,
 	}, nil
 }
+
+// AckMessage records a read receipt for a direct message
+func (h *Handler) AckMessage(ctx context.Context, request openapi.AckMessageRequestObject) (openapi.AckMessageResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.AckMessage401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	msg, err := h.messageRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return openapi.AckMessage404JSONResponse{NotFoundJSONResponse: notFoundResponse("Message not found")}, nil
+		}
+		return nil, err
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.channelRepo.GetMembership(ctx, userID, ch.ID); err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.AckMessage403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+
+	if !isDMChannel(ch) {
+		return openapi.AckMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Read receipts are only available in direct messages")}, nil
+	}
+	if !h.readReceiptsEnabled(ctx, ch.WorkspaceID) {
+		return openapi.AckMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Read receipts are disabled in this workspace")}, nil
+	}
+
+	// Nothing to record for your own, system, or deleted messages
+	if msg.UserID == nil || *msg.UserID == userID || msg.Type == message.MessageTypeSystem || msg.DeletedAt != nil {
+		return openapi.AckMessage200JSONResponse{Success: true}, nil
+	}
+
+	receipt, created, err := h.messageRepo.AddReceipt(ctx, msg.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if created && h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageReadEvent(openapi.MessageReadData{
+			MessageId: msg.ID,
+			ChannelId: ch.ID,
+			UserId:    userID,
+			ReadAt:    receipt.ReadAt,
+		}))
+	}
+
+	return openapi.AckMessage200JSONResponse{Success: true}, nil
+}
@@ -2,12 +2,20 @@ package handler
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/i18n"
 	"github.com/enzyme/server/internal/linkpreview"
+	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
 	"github.com/enzyme/server/internal/testutil"
 	"github.com/oklog/ulid/v2"
 )
@@ -31,6 +39,82 @@ func TestSendMessage_Unauthenticated(t *testing.T) {
 	}
 }
 
+func TestSendMessage_IdempotencyKey(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	other := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "random", channel.TypePublic)
+	ctx := ctxWithUser(t, h, user.ID)
+
+	send := func(channelID string, header, clientMsgID *string) openapi.SendMessageResponseObject {
+		t.Helper()
+		content := "hello"
+		resp, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
+			Id:     channelID,
+			Params: openapi.SendMessageParams{IdempotencyKey: header},
+			Body:   &openapi.SendMessageJSONRequestBody{Content: &content, ClientMsgId: clientMsgID},
+		})
+		if err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		return resp
+	}
+	countMessages := func() int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM messages WHERE channel_id = ?", ch.ID).Scan(&n); err != nil {
+			t.Fatalf("QueryRow: %v", err)
+		}
+		return n
+	}
+
+	key := "key-1"
+	first, ok := send(ch.ID, &key, nil).(openapi.SendMessage200JSONResponse)
+	if !ok {
+		t.Fatal("expected the first send to succeed")
+	}
+
+	// The same key in the body is a retry of the same message
+	retry, ok := send(ch.ID, nil, &key).(openapi.SendMessage200JSONResponse)
+	if !ok {
+		t.Fatal("expected the retry to succeed")
+	}
+	if retry.Message.Id != first.Message.Id || countMessages() != 1 {
+		t.Errorf("retry returned %s with %d messages stored, want the original and 1", retry.Message.Id, countMessages())
+	}
+
+	if _, ok := send(other.ID, &key, nil).(openapi.SendMessage409JSONResponse); !ok {
+		t.Error("expected 409 for a key reused in another channel")
+	}
+	otherKey := "key-2"
+	if _, ok := send(ch.ID, &key, &otherKey).(openapi.SendMessage400JSONResponse); !ok {
+		t.Error("expected 400 when the header and body keys differ")
+	}
+
+	// A retry must not bring back a message deleted since
+	if _, err := h.DeleteMessage(ctx, openapi.DeleteMessageRequestObject{Id: first.Message.Id}); err != nil {
+		t.Fatalf("DeleteMessage: %v", err)
+	}
+	if _, ok := send(ch.ID, nil, &key).(openapi.SendMessage409JSONResponse); !ok {
+		t.Error("expected 409 for a retry of a deleted message")
+	}
+
+	// Keys are released after the idempotency window
+	old := time.Now().UTC().Add(-message.ClientMsgIDWindow - time.Hour).Format(time.RFC3339)
+	if _, err := db.Exec("UPDATE messages SET created_at = ? WHERE id = ?", old, first.Message.Id); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	later, ok := send(ch.ID, &key, nil).(openapi.SendMessage200JSONResponse)
+	if !ok {
+		t.Fatal("expected a send with an expired key to succeed")
+	}
+	if later.Message.Id == first.Message.Id || countMessages() != 2 {
+		t.Errorf("expected a new message once the key expired, got %s with %d stored", later.Message.Id, countMessages())
+	}
+}
+
 func TestSendMessage_Success(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -61,6 +145,53 @@ func TestSendMessage_Success(t *testing.T) {
 	}
 }
 
+func TestSendMessage_ContentRendered(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "sender@test.com", "Sender")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+
+	content := "*Hi* <@" + user.ID + ">, see <#" + ch.ID + ">\n- <https://example.com|docs>"
+	resp, err := h.SendMessage(ctxWithUser(t, h, user.ID), openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.SendMessage200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if r.Message.ContentRendered == nil {
+		t.Fatal("content_rendered is missing")
+	}
+
+	nodes := *r.Message.ContentRendered
+	var types []openapi.MrkdwnNodeType
+	for _, n := range nodes {
+		types = append(types, n.Type)
+	}
+	want := []openapi.MrkdwnNodeType{
+		openapi.MrkdwnBold, openapi.MrkdwnText, openapi.MrkdwnUserMention, openapi.MrkdwnText,
+		openapi.MrkdwnChannelMention, openapi.MrkdwnLineBreak, openapi.MrkdwnBulletList,
+	}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Fatalf("node types = %v, want %v", types, want)
+	}
+	if nodes[2].UserId == nil || *nodes[2].UserId != user.ID {
+		t.Errorf("user_id = %v, want %s", nodes[2].UserId, user.ID)
+	}
+	if nodes[4].ChannelId == nil || *nodes[4].ChannelId != ch.ID {
+		t.Errorf("channel_id = %v, want %s", nodes[4].ChannelId, ch.ID)
+	}
+	link := (*nodes[6].Items)[0][0]
+	if link.Type != openapi.MrkdwnLink || *link.Url != "https://example.com" || *link.Text != "docs" {
+		t.Errorf("list item = %+v, want link to https://example.com", link)
+	}
+}
+
 func TestSendMessage_ArchivedChannel(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -164,6 +295,44 @@ func TestSendMessage_EmptyContent_NoAttachments(t *testing.T) {
 	}
 }
 
+func TestSendMessage_TooLong(t *testing.T) {
+	h, db := testHandler(t)
+	h.maxMessageLength = 10
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+
+	ctx := ctxWithUser(t, h, user.ID)
+	content := "héllo wörld"
+	resp, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.SendMessage400JSONResponse)
+	if !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+	if r.Error.Code != ErrCodeMessageTooLong || r.Error.Limit == nil || *r.Error.Limit != 10 {
+		t.Fatalf("expected MESSAGE_TOO_LONG with limit 10, got %+v", r.Error)
+	}
+
+	content = "héllo wörl"
+	resp, err = h.SendMessage(ctx, openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.SendMessage200JSONResponse); !ok {
+		t.Fatalf("expected content at the limit to be accepted, got %T", resp)
+	}
+}
+
 func TestSendMessage_ThreadReply(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -327,6 +496,74 @@ func TestDeleteMessage_SystemMessage(t *testing.T) {
 	}
 }
 
+func TestRestoreMessage_Success(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Oops, not yet")
+
+	ctx := ctxWithUser(t, h, user.ID)
+	if _, err := h.DeleteMessage(ctx, openapi.DeleteMessageRequestObject{Id: msg.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := h.RestoreMessage(ctx, openapi.RestoreMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored, ok := resp.(openapi.RestoreMessage200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if restored.Message.Content != "Oops, not yet" {
+		t.Errorf("expected original content, got %q", restored.Message.Content)
+	}
+
+	// A message that is not deleted cannot be restored again
+	resp, err = h.RestoreMessage(ctx, openapi.RestoreMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RestoreMessage400JSONResponse); !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestRestoreMessage_DeletedByAdmin(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	author := testutil.CreateTestUser(t, db, "author@test.com", "Author")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addWorkspaceMember(t, db, author.ID, ws.ID, "member")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, author.ID, "Against the rules")
+
+	if _, err := h.DeleteMessage(ctxWithUser(t, h, owner.ID), openapi.DeleteMessageRequestObject{Id: msg.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The admin is not the author
+	resp, err := h.RestoreMessage(ctxWithUser(t, h, owner.ID), openapi.RestoreMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RestoreMessage403JSONResponse); !ok {
+		t.Fatalf("expected 403 response for admin, got %T", resp)
+	}
+
+	// The author cannot undo a moderator's delete
+	resp, err = h.RestoreMessage(ctxWithUser(t, h, author.ID), openapi.RestoreMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RestoreMessage400JSONResponse); !ok {
+		t.Fatalf("expected 400 response for author, got %T", resp)
+	}
+}
+
 func TestUpdateMessage_Success(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -437,6 +674,102 @@ func TestListMessages_NotMember_Private(t *testing.T) {
 	}
 }
 
+// backdateMessage moves a message's created_at into the past.
+func backdateMessage(t *testing.T, db *sql.DB, messageID string, age time.Duration) {
+	t.Helper()
+
+	_, err := db.Exec(`UPDATE messages SET created_at = ? WHERE id = ?`,
+		time.Now().UTC().Add(-age).Format(time.RFC3339), messageID)
+	if err != nil {
+		t.Fatalf("backdating message: %v", err)
+	}
+}
+
+func TestListMessages_HistoryVisibility(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	newcomer := testutil.CreateTestUser(t, db, "new@test.com", "Newcomer")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, newcomer.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	if _, err := db.Exec(`UPDATE channel_memberships SET created_at = ? WHERE user_id = ? AND channel_id = ?`,
+		time.Now().UTC().Add(-90*24*time.Hour).Format(time.RFC3339), owner.ID, ch.ID); err != nil {
+		t.Fatalf("backdating owner membership: %v", err)
+	}
+
+	old := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "From last quarter")
+	backdateMessage(t, db, old.ID, 60*24*time.Hour)
+	recent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "From last week")
+	backdateMessage(t, db, recent.ID, 7*24*time.Hour)
+
+	addChannelMember(t, db, newcomer.ID, ch.ID, nil)
+
+	tests := []struct {
+		visibility string
+		want       int
+	}{
+		{channel.HistoryVisibilityAll, 2},
+		{channel.HistoryVisibilityLast30Days, 1},
+		{channel.HistoryVisibilityNone, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.visibility, func(t *testing.T) {
+			if _, err := db.Exec(`UPDATE channels SET history_visibility = ? WHERE id = ?`, tt.visibility, ch.ID); err != nil {
+				t.Fatalf("setting history visibility: %v", err)
+			}
+
+			ctx := ctxWithUser(t, h, newcomer.ID)
+			resp, err := h.ListMessages(ctx, openapi.ListMessagesRequestObject{Id: ch.ID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			r, ok := resp.(openapi.ListMessages200JSONResponse)
+			if !ok {
+				t.Fatalf("expected 200 response, got %T", resp)
+			}
+			if len(r.Messages) != tt.want {
+				t.Errorf("got %d messages, want %d", len(r.Messages), tt.want)
+			}
+
+			// The owner joined before any message was posted and sees everything
+			ownerResp, err := h.ListMessages(ctxWithUser(t, h, owner.ID), openapi.ListMessagesRequestObject{Id: ch.ID})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := len(ownerResp.(openapi.ListMessages200JSONResponse).Messages); got != 2 {
+				t.Errorf("owner got %d messages, want 2", got)
+			}
+		})
+	}
+}
+
+func TestGetMessage_HiddenByHistoryVisibility(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	newcomer := testutil.CreateTestUser(t, db, "new@test.com", "Newcomer")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, newcomer.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	if _, err := db.Exec(`UPDATE channels SET history_visibility = ? WHERE id = ?`, channel.HistoryVisibilityNone, ch.ID); err != nil {
+		t.Fatalf("setting history visibility: %v", err)
+	}
+
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Before you joined")
+	backdateMessage(t, db, msg.ID, time.Hour)
+	addChannelMember(t, db, newcomer.ID, ch.ID, nil)
+
+	ctx := ctxWithUser(t, h, newcomer.ID)
+	resp, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.GetMessage404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
+	}
+}
+
 func TestAddReaction_Success(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -496,91 +829,190 @@ func TestRemoveReaction_Success(t *testing.T) {
 	}
 }
 
-func TestGetMessage_Success(t *testing.T) {
+func TestBatchReactions_Success(t *testing.T) {
 	h, db := testHandler(t)
 
 	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
 	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
 	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
-	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Hello")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "React to me")
 
 	ctx := ctxWithUser(t, h, user.ID)
-	resp, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{
+	if _, err := h.AddReaction(ctx, openapi.AddReactionRequestObject{
+		Id:   msg.ID,
+		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
+	}); err != nil {
+		t.Fatalf("adding reaction: %v", err)
+	}
+
+	client := connectSSEClient(t, h, ws.ID, user.ID)
+
+	resp, err := h.BatchReactions(ctx, openapi.BatchReactionsRequestObject{
 		Id: msg.ID,
+		Body: &openapi.BatchReactionsJSONRequestBody{
+			Operations: []openapi.ReactionOperation{
+				{Op: openapi.ReactionOperationRemove, Emoji: "👍"},
+				{Op: openapi.ReactionOperationAdd, Emoji: "🎉"},
+				{Op: openapi.ReactionOperationAdd, Emoji: "🚀"},
+			},
+		},
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	r, ok := resp.(openapi.GetMessage200JSONResponse)
+	r, ok := resp.(openapi.BatchReactions200JSONResponse)
 	if !ok {
 		t.Fatalf("expected 200 response, got %T", resp)
 	}
-	if r.Message.Content != "Hello" {
-		t.Errorf("content = %q, want %q", r.Message.Content, "Hello")
+	if len(r.Added) != 2 {
+		t.Errorf("added = %d reactions, want 2", len(r.Added))
+	}
+	if len(r.Removed) != 1 || r.Removed[0] != "👍" {
+		t.Errorf("removed = %v, want [👍]", r.Removed)
+	}
+
+	expectSSEEvent(t, client, sse.EventReactionBatch)
+	select {
+	case ev := <-client.Send:
+		t.Errorf("expected a single event, also got %s", ev.Frame)
+	default:
 	}
 }
 
-func TestGetMessage_PrivateNonMember(t *testing.T) {
+func TestListMessageReactions(t *testing.T) {
 	h, db := testHandler(t)
 
 	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
-	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
 	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
-	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
-	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Private message")
+	addWorkspaceMember(t, db, alice.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, bob.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "React to me")
 
-	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	react := func(userID, emoji string) {
+		t.Helper()
+		if _, err := h.AddReaction(ctxWithUser(t, h, userID), openapi.AddReactionRequestObject{
+			Id:   msg.ID,
+			Body: &openapi.AddReactionJSONRequestBody{Emoji: emoji},
+		}); err != nil {
+			t.Fatalf("adding reaction: %v", err)
+		}
+	}
+	react(alice.ID, "🎉")
+	react(bob.ID, "🎉")
+	react(owner.ID, "👍")
 
-	ctx := ctxWithUser(t, h, other.ID)
-	resp, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{
-		Id: msg.ID,
+	ctx := ctxWithUser(t, h, owner.ID)
+	emoji, limit := "🎉", 1
+	resp, err := h.ListMessageReactions(ctx, openapi.ListMessageReactionsRequestObject{
+		Id:     msg.ID,
+		Params: openapi.ListMessageReactionsParams{Emoji: &emoji, Limit: &limit},
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if _, ok := resp.(openapi.GetMessage404JSONResponse); !ok {
-		t.Fatalf("expected 404 response, got %T", resp)
+	r, ok := resp.(openapi.ListMessageReactions200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Reactors) != 1 || r.Reactors[0].UserId != alice.ID || r.Reactors[0].DisplayName != "Alice" || !r.HasMore || r.NextCursor == nil {
+		t.Fatalf("expected Alice on the first page with more to come, got %+v", r)
+	}
+	if len(r.Counts) != 2 || r.Counts[0].Emoji != "🎉" || r.Counts[0].Count != 2 || r.Counts[0].Reacted || !r.Counts[1].Reacted {
+		t.Errorf("unexpected counts: %+v", r.Counts)
+	}
+
+	resp, err = h.ListMessageReactions(ctx, openapi.ListMessageReactionsRequestObject{
+		Id:     msg.ID,
+		Params: openapi.ListMessageReactionsParams{Emoji: &emoji, Limit: &limit, Cursor: r.NextCursor},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r = resp.(openapi.ListMessageReactions200JSONResponse)
+	if len(r.Reactors) != 1 || r.Reactors[0].UserId != bob.ID || r.HasMore {
+		t.Fatalf("expected Bob on the last page, got %+v", r)
 	}
 }
 
-func TestListThread_Success(t *testing.T) {
+func TestListMessageReactions_HiddenByHistoryVisibility(t *testing.T) {
 	h, db := testHandler(t)
 
-	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
-	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
-	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
-	parent := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Thread parent")
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	newcomer := testutil.CreateTestUser(t, db, "new@test.com", "Newcomer")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, newcomer.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	if _, err := db.Exec(`UPDATE channels SET history_visibility = ? WHERE id = ?`, channel.HistoryVisibilityNone, ch.ID); err != nil {
+		t.Fatalf("setting history visibility: %v", err)
+	}
 
-	// Create a reply via SendMessage to properly set up thread
-	ctx := ctxWithUser(t, h, user.ID)
-	replyContent := "Thread reply"
-	_, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
-		Id: ch.ID,
-		Body: &openapi.SendMessageJSONRequestBody{
-			Content:        &replyContent,
-			ThreadParentId: &parent.ID,
-		},
-	})
-	if err != nil {
-		t.Fatalf("sending reply: %v", err)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Before you joined")
+	if _, err := h.AddReaction(ctxWithUser(t, h, owner.ID), openapi.AddReactionRequestObject{
+		Id:   msg.ID,
+		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
+	}); err != nil {
+		t.Fatalf("adding reaction: %v", err)
 	}
+	backdateMessage(t, db, msg.ID, time.Hour)
+	addChannelMember(t, db, newcomer.ID, ch.ID, nil)
 
-	resp, err := h.ListThread(ctx, openapi.ListThreadRequestObject{
-		Id: parent.ID,
-	})
+	resp, err := h.ListMessageReactions(ctxWithUser(t, h, newcomer.ID), openapi.ListMessageReactionsRequestObject{Id: msg.ID})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	r, ok := resp.(openapi.ListThread200JSONResponse)
-	if !ok {
-		t.Fatalf("expected 200 response, got %T", resp)
+	if _, ok := resp.(openapi.ListMessageReactions404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
 	}
-	if len(r.Messages) == 0 {
-		t.Error("expected at least one thread reply")
+}
+
+func TestListPinnedMessages_HistoryVisibility(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	newcomer := testutil.CreateTestUser(t, db, "new@test.com", "Newcomer")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, newcomer.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	if _, err := db.Exec(`UPDATE channels SET history_visibility = ? WHERE id = ?`, channel.HistoryVisibilityNone, ch.ID); err != nil {
+		t.Fatalf("setting history visibility: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE channel_memberships SET created_at = ? WHERE user_id = ? AND channel_id = ?`,
+		time.Now().UTC().Add(-2*time.Hour).Format(time.RFC3339), owner.ID, ch.ID); err != nil {
+		t.Fatalf("backdating owner membership: %v", err)
+	}
+
+	old := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Before you joined")
+	backdateMessage(t, db, old.ID, time.Hour)
+	addChannelMember(t, db, newcomer.ID, ch.ID, nil)
+	if _, err := db.Exec(`UPDATE messages SET pinned_at = ?, pinned_by = ? WHERE id = ?`,
+		time.Now().UTC().Format(time.RFC3339), owner.ID, old.ID); err != nil {
+		t.Fatalf("pinning message: %v", err)
+	}
+
+	list := func(userID string) []openapi.MessageWithUser {
+		t.Helper()
+		resp, err := h.ListPinnedMessages(ctxWithUser(t, h, userID), openapi.ListPinnedMessagesRequestObject{Id: ch.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r, ok := resp.(openapi.ListPinnedMessages200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		return r.Messages
+	}
+	if got := list(newcomer.ID); len(got) != 0 {
+		t.Errorf("newcomer got %d pinned messages, want 0", len(got))
+	}
+	if got := list(owner.ID); len(got) != 1 {
+		t.Errorf("owner got %d pinned messages, want 1", len(got))
 	}
 }
 
-func TestAddReaction_Duplicate(t *testing.T) {
+func TestBatchReactions_Validation(t *testing.T) {
 	h, db := testHandler(t)
 
 	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
@@ -589,19 +1021,623 @@ func TestAddReaction_Duplicate(t *testing.T) {
 	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "React to me")
 
 	ctx := ctxWithUser(t, h, user.ID)
-
-	// Add reaction first time
-	_, err := h.AddReaction(ctx, openapi.AddReactionRequestObject{
-		Id:   msg.ID,
-		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
-	})
+	tests := []struct {
+		name string
+		ops  []openapi.ReactionOperation
+	}{
+		{"empty", nil},
+		{"invalid op", []openapi.ReactionOperation{{Op: "toggle", Emoji: "👍"}}},
+		{"blank emoji", []openapi.ReactionOperation{{Op: openapi.ReactionOperationAdd, Emoji: "🎉"}, {Op: openapi.ReactionOperationAdd, Emoji: " "}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.BatchReactions(ctx, openapi.BatchReactionsRequestObject{
+				Id:   msg.ID,
+				Body: &openapi.BatchReactionsJSONRequestBody{Operations: tt.ops},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := resp.(openapi.BatchReactions400JSONResponse); !ok {
+				t.Fatalf("expected 400 response, got %T", resp)
+			}
+		})
+	}
+
+	// A rejected batch must not have applied any of its operations
+	reactions, err := h.messageRepo.GetReactionsForMessage(ctx, msg.ID, nil)
 	if err != nil {
-		t.Fatalf("first reaction: %v", err)
+		t.Fatalf("listing reactions: %v", err)
+	}
+	if len(reactions) != 0 {
+		t.Errorf("expected no reactions, got %d", len(reactions))
 	}
+}
 
-	// Add same reaction again — returns an error (duplicate)
-	_, err = h.AddReaction(ctx, openapi.AddReactionRequestObject{
-		Id:   msg.ID,
+func TestBatchReactions_PrivateChannelNonMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Private")
+
+	ctx := ctxWithUser(t, h, other.ID)
+	resp, err := h.BatchReactions(ctx, openapi.BatchReactionsRequestObject{
+		Id: msg.ID,
+		Body: &openapi.BatchReactionsJSONRequestBody{
+			Operations: []openapi.ReactionOperation{{Op: openapi.ReactionOperationAdd, Emoji: "👍"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.BatchReactions403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestGetMessage_Success(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Hello")
+
+	ctx := ctxWithUser(t, h, user.ID)
+	resp, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{
+		Id: msg.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.GetMessage200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if r.Message.Content != "Hello" {
+		t.Errorf("content = %q, want %q", r.Message.Content, "Hello")
+	}
+}
+
+func TestGetMessage_PrivateNonMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Private message")
+
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+
+	ctx := ctxWithUser(t, h, other.ID)
+	resp, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{
+		Id: msg.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.GetMessage404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestListThread_Success(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Thread parent")
+
+	// Create a reply via SendMessage to properly set up thread
+	ctx := ctxWithUser(t, h, user.ID)
+	replyContent := "Thread reply"
+	_, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
+		Id: ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{
+			Content:        &replyContent,
+			ThreadParentId: &parent.ID,
+		},
+	})
+	if err != nil {
+		t.Fatalf("sending reply: %v", err)
+	}
+
+	resp, err := h.ListThread(ctx, openapi.ListThreadRequestObject{
+		Id: parent.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListThread200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Messages) == 0 {
+		t.Error("expected at least one thread reply")
+	}
+}
+
+func TestGetChannelMessages_QueryParams(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "secret", channel.TypePrivate)
+	for _, content := range []string{"one", "two", "three"} {
+		testutil.CreateTestMessage(t, db, ch.ID, user.ID, content)
+	}
+	ctx := ctxWithUser(t, h, user.ID)
+
+	limit := 2
+	resp, err := h.GetChannelMessages(ctx, openapi.GetChannelMessagesRequestObject{
+		Id:     ch.ID,
+		Params: openapi.GetChannelMessagesParams{Limit: &limit},
+	})
+	if err != nil {
+		t.Fatalf("GetChannelMessages: %v", err)
+	}
+	first, ok := resp.(openapi.GetChannelMessages200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(first.Messages) != 2 || !first.HasMore || first.NextCursor == nil {
+		t.Fatalf("first page = %d messages, has_more %v; want 2 and more to come", len(first.Messages), first.HasMore)
+	}
+
+	before := openapi.MessageListDirectionBefore
+	resp, err = h.GetChannelMessages(ctx, openapi.GetChannelMessagesRequestObject{
+		Id:     ch.ID,
+		Params: openapi.GetChannelMessagesParams{Cursor: first.NextCursor, Limit: &limit, Direction: &before},
+	})
+	if err != nil {
+		t.Fatalf("GetChannelMessages: %v", err)
+	}
+	second := resp.(openapi.GetChannelMessages200JSONResponse)
+	if len(second.Messages) != 1 || second.Messages[0].Content != "one" || second.HasMore {
+		t.Errorf("second page = %+v, want only the oldest message", second.Messages)
+	}
+
+	resp, err = h.GetChannelMessages(ctxWithUser(t, h, outsider.ID), openapi.GetChannelMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("GetChannelMessages: %v", err)
+	}
+	if _, ok := resp.(openapi.GetChannelMessages403JSONResponse); !ok {
+		t.Errorf("expected 403 for a non-member, got %T", resp)
+	}
+}
+
+func TestGetChannelMessages_Fields(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	testutil.CreateTestMessage(t, db, ch.ID, user.ID, "hello")
+	ctx := ctxWithUser(t, h, user.ID)
+
+	fields := "content, user_id,created_at"
+	resp, err := h.GetChannelMessages(ctx, openapi.GetChannelMessagesRequestObject{
+		Id:     ch.ID,
+		Params: openapi.GetChannelMessagesParams{Fields: &fields},
+	})
+	if err != nil {
+		t.Fatalf("GetChannelMessages: %v", err)
+	}
+	w := httptest.NewRecorder()
+	if err := resp.VisitGetChannelMessagesResponse(w); err != nil {
+		t.Fatalf("VisitGetChannelMessagesResponse: %v", err)
+	}
+	var body struct {
+		Messages []map[string]any `json:"messages"`
+		HasMore  *bool            `json:"has_more"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(body.Messages) != 1 || body.HasMore == nil {
+		t.Fatalf("body = %s, want one message and has_more", w.Body.String())
+	}
+	msg := body.Messages[0]
+	if len(msg) != 4 || msg["id"] == nil || msg["content"] != "hello" || msg["user_id"] != user.ID || msg["created_at"] == nil {
+		t.Errorf("message = %v, want only id, content, user_id and created_at", msg)
+	}
+
+	fields = "content,password"
+	resp, err = h.GetChannelMessages(ctx, openapi.GetChannelMessagesRequestObject{
+		Id:     ch.ID,
+		Params: openapi.GetChannelMessagesParams{Fields: &fields},
+	})
+	if err != nil {
+		t.Fatalf("GetChannelMessages: %v", err)
+	}
+	if _, ok := resp.(openapi.GetChannelMessages400JSONResponse); !ok {
+		t.Errorf("expected 400 for an unknown field, got %T", resp)
+	}
+}
+
+func TestGetThreadReplies_QueryParams(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Thread parent")
+	ctx := ctxWithUser(t, h, user.ID)
+
+	for _, content := range []string{"first reply", "second reply"} {
+		if _, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content, ThreadParentId: &parent.ID},
+		}); err != nil {
+			t.Fatalf("sending reply: %v", err)
+		}
+	}
+
+	limit := 1
+	resp, err := h.GetThreadReplies(ctx, openapi.GetThreadRepliesRequestObject{
+		Id:     parent.ID,
+		Params: openapi.GetThreadRepliesParams{Limit: &limit},
+	})
+	if err != nil {
+		t.Fatalf("GetThreadReplies: %v", err)
+	}
+	r, ok := resp.(openapi.GetThreadReplies200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Messages) != 1 || !r.HasMore {
+		t.Errorf("got %d replies, has_more %v; want 1 and more to come", len(r.Messages), r.HasMore)
+	}
+}
+
+func TestListMessagesByAuthor_Success(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "From owner")
+	theirs := testutil.CreateTestMessage(t, db, ch.ID, other.ID, "From other")
+
+	resp, err := h.ListMessagesByAuthor(ctxWithUser(t, h, owner.ID), openapi.ListMessagesByAuthorRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.ListMessagesByAuthorJSONRequestBody{UserId: other.ID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListMessagesByAuthor200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Messages) != 1 || r.Messages[0].Id != theirs.ID {
+		t.Fatalf("expected only the other user's message, got %+v", r.Messages)
+	}
+}
+
+func TestListMessages_TruncatesLongContent(t *testing.T) {
+	h, db := testHandler(t)
+	h.listContentLength = 5
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	long := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "*bold* and more")
+	testutil.CreateTestMessage(t, db, ch.ID, user.ID, "short")
+
+	ctx := ctxWithUser(t, h, user.ID)
+	resp, err := h.ListMessages(ctx, openapi.ListMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListMessages200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	for _, m := range r.Messages {
+		truncated := m.ContentTruncated != nil && *m.ContentTruncated
+		if m.Id == long.ID {
+			if !truncated || m.Content != "*bold" {
+				t.Errorf("expected long message cut to %q and flagged, got %q (truncated=%v)", "*bold", m.Content, truncated)
+			}
+		} else if truncated || m.Content != "short" {
+			t.Errorf("expected short message untouched, got %q (truncated=%v)", m.Content, truncated)
+		}
+	}
+
+	full, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{Id: long.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g, ok := full.(openapi.GetMessage200JSONResponse); !ok || g.Message.Content != "*bold* and more" {
+		t.Fatalf("expected GetMessage to return the full content, got %+v", full)
+	}
+}
+
+func TestListMessages_LocalizesSystemMessages(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	sys, err := h.messageRepo.CreateSystemMessage(context.Background(), ch.ID, &message.SystemEventData{
+		EventType:   message.SystemEventUserJoined,
+		UserID:      user.ID,
+		ChannelName: ch.Name,
+	})
+	if err != nil {
+		t.Fatalf("creating system message: %v", err)
+	}
+
+	for locale, want := range map[string]string{
+		i18n.Default: "joined #general",
+		"de":         "ist #general beigetreten",
+		"ja":         "#general に参加しました",
+	} {
+		ctx := i18n.WithLocale(ctxWithUser(t, h, user.ID), locale)
+		resp, err := h.ListMessages(ctx, openapi.ListMessagesRequestObject{Id: ch.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r, ok := resp.(openapi.ListMessages200JSONResponse)
+		if !ok || len(r.Messages) != 1 || r.Messages[0].Content != want {
+			t.Errorf("%s: expected %q, got %+v", locale, want, resp)
+		}
+
+		got, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{Id: sys.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if g, ok := got.(openapi.GetMessage200JSONResponse); !ok || g.Message.Content != want {
+			t.Errorf("%s: expected GetMessage to return %q, got %+v", locale, want, got)
+		}
+	}
+}
+
+func TestListMessagesByAuthor_InvalidRange(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	since := time.Now()
+	until := since.Add(-time.Hour)
+	resp, err := h.ListMessagesByAuthor(ctxWithUser(t, h, owner.ID), openapi.ListMessagesByAuthorRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.ListMessagesByAuthorJSONRequestBody{UserId: owner.ID, Since: &since, Until: &until},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ListMessagesByAuthor400JSONResponse); !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestListMessagesByAuthor_PrivateChannelNonMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+
+	resp, err := h.ListMessagesByAuthor(ctxWithUser(t, h, other.ID), openapi.ListMessagesByAuthorRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.ListMessagesByAuthorJSONRequestBody{UserId: owner.ID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ListMessagesByAuthor403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestListArchivedMessages_PrivateChannel(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+
+	resp, err := h.ListArchivedMessages(ctxWithUser(t, h, other.ID), openapi.ListArchivedMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ListArchivedMessages403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+
+	resp, err = h.ListArchivedMessages(ctxWithUser(t, h, owner.ID), openapi.ListArchivedMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListArchivedMessages200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Messages) != 0 || r.HasMore {
+		t.Fatalf("expected an empty archive, got %+v", r)
+	}
+}
+
+func TestListThreadParticipants_Success(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Thread parent")
+
+	for i := 0; i < 4; i++ {
+		u := testutil.CreateTestUser(t, db, fmt.Sprintf("user%d@test.com", i), fmt.Sprintf("User %d", i))
+		addWorkspaceMember(t, db, u.ID, ws.ID, "member")
+		addChannelMember(t, db, u.ID, ch.ID, nil)
+		content := "Reply"
+		if _, err := h.SendMessage(ctxWithUser(t, h, u.ID), openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content, ThreadParentId: &parent.ID},
+		}); err != nil {
+			t.Fatalf("sending reply: %v", err)
+		}
+	}
+
+	ctx := ctxWithUser(t, h, owner.ID)
+
+	// The parent carries a preview and the full count
+	msgResp, err := h.GetMessage(ctx, openapi.GetMessageRequestObject{Id: parent.ID})
+	if err != nil {
+		t.Fatalf("getting message: %v", err)
+	}
+	msg := msgResp.(openapi.GetMessage200JSONResponse).Message
+	if msg.ThreadParticipants == nil || len(*msg.ThreadParticipants) != 3 {
+		t.Fatalf("expected 3 preview participants, got %v", msg.ThreadParticipants)
+	}
+	if msg.ThreadParticipantCount == nil || *msg.ThreadParticipantCount != 4 {
+		t.Fatalf("expected participant count 4, got %v", msg.ThreadParticipantCount)
+	}
+
+	limit := 3
+	resp, err := h.ListThreadParticipants(ctx, openapi.ListThreadParticipantsRequestObject{
+		Id:     parent.ID,
+		Params: openapi.ListThreadParticipantsParams{Limit: &limit},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ListThreadParticipants200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(r.Participants) != 3 || r.ParticipantCount != 4 || !r.HasMore || r.NextCursor == nil {
+		t.Fatalf("unexpected first page: %+v", r)
+	}
+
+	resp, err = h.ListThreadParticipants(ctx, openapi.ListThreadParticipantsRequestObject{
+		Id:     parent.ID,
+		Params: openapi.ListThreadParticipantsParams{Limit: &limit, Cursor: r.NextCursor},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r = resp.(openapi.ListThreadParticipants200JSONResponse)
+	if len(r.Participants) != 1 || r.HasMore {
+		t.Fatalf("unexpected second page: %+v", r)
+	}
+}
+
+func TestListThreadParticipants_PrivateChannelNonMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Thread parent")
+
+	resp, err := h.ListThreadParticipants(ctxWithUser(t, h, other.ID), openapi.ListThreadParticipantsRequestObject{Id: parent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ListThreadParticipants403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestMarkThreadRead_ClearsUnreadAndBroadcasts(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, other.ID, ch.ID, nil)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Thread parent")
+
+	otherCtx := ctxWithUser(t, h, other.ID)
+	for _, content := range []string{"first", "second"} {
+		if _, err := h.SendMessage(otherCtx, openapi.SendMessageRequestObject{
+			Id: ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{
+				Content:        &content,
+				ThreadParentId: &parent.ID,
+			},
+		}); err != nil {
+			t.Fatalf("sending reply: %v", err)
+		}
+	}
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	unread := func() int {
+		t.Helper()
+		resp, err := h.ListUserThreads(ownerCtx, openapi.ListUserThreadsRequestObject{Wid: ws.ID, Body: &openapi.ListUserThreadsJSONRequestBody{}})
+		if err != nil {
+			t.Fatalf("listing threads: %v", err)
+		}
+		r, ok := resp.(openapi.ListUserThreads200JSONResponse)
+		if !ok || len(r.Threads) != 1 {
+			t.Fatalf("expected one thread, got %#v", resp)
+		}
+		return r.Threads[0].UnreadReplyCount
+	}
+	if n := unread(); n != 2 {
+		t.Fatalf("unread_reply_count = %d, want 2", n)
+	}
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+
+	resp, err := h.MarkThreadRead(ownerCtx, openapi.MarkThreadReadRequestObject{Id: parent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.MarkThreadRead200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	expectSSEEvent(t, client, sse.EventThreadRead)
+
+	if n := unread(); n != 0 {
+		t.Errorf("unread_reply_count after mark read = %d, want 0", n)
+	}
+}
+
+func TestAddReaction_Duplicate(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "React to me")
+
+	ctx := ctxWithUser(t, h, user.ID)
+
+	// Add reaction first time
+	_, err := h.AddReaction(ctx, openapi.AddReactionRequestObject{
+		Id:   msg.ID,
+		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
+	})
+	if err != nil {
+		t.Fatalf("first reaction: %v", err)
+	}
+
+	// Add same reaction again — returns an error (duplicate)
+	_, err = h.AddReaction(ctx, openapi.AddReactionRequestObject{
+		Id:   msg.ID,
 		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
 	})
 	if err == nil {
@@ -785,3 +1821,240 @@ func TestUpdateMessage_LinkPreview_URLAdded(t *testing.T) {
 		t.Errorf("title = %q, want %q", *r.Message.LinkPreview.Title, "Added")
 	}
 }
+
+func TestUpdateMessage_LinkPreview_DisabledForWorkspace(t *testing.T) {
+	h, db := testHandlerWithLinkPreviews(t, &http.Client{})
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "No link here")
+
+	seedLinkPreviewCache(t, db, "https://added.com", "Added")
+
+	// Turn off link previews for the workspace
+	wsModel, err := h.workspaceRepo.GetByID(context.Background(), ws.ID)
+	if err != nil {
+		t.Fatalf("getting workspace: %v", err)
+	}
+	settings := wsModel.ParsedSettings()
+	settings.LinkPreviews = false
+	wsModel.Settings = settings.ToJSON()
+	if err := h.workspaceRepo.Update(context.Background(), wsModel); err != nil {
+		t.Fatalf("updating workspace: %v", err)
+	}
+
+	ctx := ctxWithUser(t, h, user.ID)
+	resp, err := h.UpdateMessage(ctx, openapi.UpdateMessageRequestObject{
+		Id: msg.ID,
+		Body: &openapi.UpdateMessageJSONRequestBody{
+			Content: "Now with https://added.com",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.UpdateMessage200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if r.Message.LinkPreview != nil {
+		t.Fatalf("expected no link preview when previews are disabled, got %+v", r.Message.LinkPreview)
+	}
+}
+
+func TestAckMessage_RecordsReceipt(t *testing.T) {
+	h, db := testHandler(t)
+
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "WS")
+	addWorkspaceMember(t, db, bob.ID, ws.ID, "member")
+	dm := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "dm", channel.TypeDM)
+	addChannelMember(t, db, bob.ID, dm.ID, nil)
+	msg := testutil.CreateTestMessage(t, db, dm.ID, alice.ID, "Did you see this?")
+
+	resp, err := h.AckMessage(ctxWithUser(t, h, bob.ID), openapi.AckMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.AckMessage200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	listResp, err := h.ListMessages(ctxWithUser(t, h, alice.ID), openapi.ListMessagesRequestObject{Id: dm.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := listResp.(openapi.ListMessages200JSONResponse)
+	if len(r.Messages) != 1 || r.Messages[0].ReadReceipts == nil {
+		t.Fatalf("expected the message to carry read receipts")
+	}
+	receipts := *r.Messages[0].ReadReceipts
+	if len(receipts) != 1 || receipts[0].UserId != bob.ID {
+		t.Errorf("receipts = %+v, want one receipt from Bob", receipts)
+	}
+}
+
+func TestAckMessage_DisabledByWorkspace(t *testing.T) {
+	h, db := testHandler(t)
+
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "WS")
+	addWorkspaceMember(t, db, bob.ID, ws.ID, "member")
+	dm := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "dm", channel.TypeDM)
+	addChannelMember(t, db, bob.ID, dm.ID, nil)
+	msg := testutil.CreateTestMessage(t, db, dm.ID, alice.ID, "Did you see this?")
+
+	if _, err := db.Exec(`UPDATE workspaces SET settings = ? WHERE id = ?`, `{"dm_read_receipts":false}`, ws.ID); err != nil {
+		t.Fatalf("updating settings: %v", err)
+	}
+
+	resp, err := h.AckMessage(ctxWithUser(t, h, bob.ID), openapi.AckMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.AckMessage403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestAckMessage_NotDirectMessage(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, other.ID, ch.ID, nil)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Hello")
+
+	resp, err := h.AckMessage(ctxWithUser(t, h, other.ID), openapi.AckMessageRequestObject{Id: msg.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.AckMessage400JSONResponse); !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestGetMessagePermalink(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, outsider.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	private := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", "private")
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "parent")
+	secret := testutil.CreateTestMessage(t, db, private.ID, owner.ID, "secret")
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	content := "reply"
+	sent, err := h.SendMessage(ownerCtx, openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content, ThreadParentId: &parent.ID},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	reply := sent.(openapi.SendMessage200JSONResponse).Message
+
+	permalink := func(ctx context.Context, id string) openapi.GetMessagePermalinkResponseObject {
+		t.Helper()
+		resp, err := h.GetMessagePermalink(ctx, openapi.GetMessagePermalinkRequestObject{Id: id})
+		if err != nil {
+			t.Fatalf("GetMessagePermalink: %v", err)
+		}
+		return resp
+	}
+
+	top, ok := permalink(ownerCtx, parent.ID).(openapi.GetMessagePermalink200JSONResponse)
+	if !ok {
+		t.Fatal("expected a permalink for a top-level message")
+	}
+	wantURL := "http://localhost:8080/workspaces/" + ws.ID + "/channels/" + ch.ID + "?msg=" + parent.ID
+	if top.Url != wantURL || top.Cursor != parent.ID || top.ThreadParentId != nil {
+		t.Errorf("permalink = %+v, want url %q centered on the message", top, wantURL)
+	}
+
+	threaded, ok := permalink(ownerCtx, reply.Id).(openapi.GetMessagePermalink200JSONResponse)
+	if !ok {
+		t.Fatal("expected a permalink for a thread reply")
+	}
+	wantURL = "http://localhost:8080/workspaces/" + ws.ID + "/channels/" + ch.ID + "?thread=" + parent.ID + "&msg=" + reply.Id
+	if threaded.Url != wantURL || threaded.Cursor != parent.ID || threaded.ThreadParentId == nil || *threaded.ThreadParentId != parent.ID {
+		t.Errorf("permalink = %+v, want url %q centered on the parent", threaded, wantURL)
+	}
+
+	// Workspace members can link into public channels they haven't joined
+	outsiderCtx := ctxWithUser(t, h, outsider.ID)
+	if _, ok := permalink(outsiderCtx, parent.ID).(openapi.GetMessagePermalink200JSONResponse); !ok {
+		t.Error("expected a permalink for a public channel message")
+	}
+	// Private channel messages look the same as missing ones
+	if _, ok := permalink(outsiderCtx, secret.ID).(openapi.GetMessagePermalink404JSONResponse); !ok {
+		t.Error("expected 404 for a private channel message")
+	}
+	if _, ok := permalink(outsiderCtx, "nonexistent").(openapi.GetMessagePermalink404JSONResponse); !ok {
+		t.Error("expected 404 for a missing message")
+	}
+}
+
+func TestSendMessage_Async(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addWorkspaceMember(t, db, bob.ID, ws.ID, "member")
+	addChannelMember(t, db, bob.ID, ch.ID, nil)
+	attachmentID := createFileAttachment(t, db, ch.ID, owner.ID)
+
+	content := "<@" + bob.ID + "> notes attached"
+	async := true
+	key := "async-1"
+	resp, err := h.SendMessage(ctxWithUser(t, h, owner.ID), openapi.SendMessageRequestObject{
+		Id: ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{
+			Content:       &content,
+			AttachmentIds: &[]string{attachmentID},
+			ClientMsgId:   &key,
+			Async:         &async,
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	ack, ok := resp.(openapi.SendMessage202JSONResponse)
+	if !ok {
+		t.Fatalf("expected 202, got %T", resp)
+	}
+	if ack.MessageId == "" || ack.ChannelId != ch.ID || ack.ClientMsgId == nil || *ack.ClientMsgId != key {
+		t.Errorf("unexpected ack %+v", ack)
+	}
+
+	// Attachments and mentions follow in the background, which shutdown
+	// waits for
+	waitCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Wait(waitCtx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	var linked string
+	var count int
+	db.QueryRow(`SELECT COALESCE(message_id, '') FROM attachments WHERE id = ?`, attachmentID).Scan(&linked)
+	db.QueryRow(`
+		SELECT notification_count FROM channel_memberships WHERE user_id = ? AND channel_id = ?
+	`, bob.ID, ch.ID).Scan(&count)
+	if linked != ack.MessageId {
+		t.Errorf("attachment linked to %q, want %q", linked, ack.MessageId)
+	}
+	if count != 1 {
+		t.Errorf("bob's notification_count = %d, want 1 once mentions are stored", count)
+	}
+}
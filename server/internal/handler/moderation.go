@@ -83,7 +83,7 @@ func (h *Handler) BanUser(ctx context.Context, request openapi.BanUserRequestObj
 
 	// Broadcast SSE event
 	if h.hub != nil {
-		h.hub.BroadcastToWorkspace(string(request.Wid), sse.NewMemberBannedEvent(openapi.WorkspaceMemberData{
+		h.hub.BroadcastToWorkspace(ctx, string(request.Wid), sse.NewMemberBannedEvent(openapi.WorkspaceMemberData{
 			UserId:      targetUserID,
 			WorkspaceId: string(request.Wid),
 		}))
@@ -141,7 +141,7 @@ func (h *Handler) UnbanUser(ctx context.Context, request openapi.UnbanUserReques
 
 	// Broadcast SSE event
 	if h.hub != nil {
-		h.hub.BroadcastToWorkspace(string(request.Wid), sse.NewMemberUnbannedEvent(openapi.WorkspaceMemberData{
+		h.hub.BroadcastToWorkspace(ctx, string(request.Wid), sse.NewMemberUnbannedEvent(openapi.WorkspaceMemberData{
 			UserId:      request.Body.UserId,
 			WorkspaceId: string(request.Wid),
 		}))
@@ -347,11 +347,70 @@ func (h *Handler) ListModerationLog(ctx context.Context, request openapi.ListMod
 		}
 	}
 
-	entries, hasMore, nextCursor, err := h.moderationRepo.ListAuditLog(ctx, string(request.Wid), cursor, limit)
+	entries, hasMore, nextCursor, err := h.moderationRepo.ListAuditLog(ctx, string(request.Wid), moderation.AuditLogFilter{}, cursor, limit)
 	if err != nil {
 		return nil, err
 	}
 
+	return openapi.ListModerationLog200JSONResponse{
+		Entries:    auditLogEntriesToAPI(entries),
+		HasMore:    hasMore,
+		NextCursor: &nextCursor,
+	}, nil
+}
+
+// ListAuditLog lists audit log entries for a workspace, filtered by actor, action and time range
+func (h *Handler) ListAuditLog(ctx context.Context, request openapi.ListAuditLogRequestObject) (openapi.ListAuditLogResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListAuditLog401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil {
+		return openapi.ListAuditLog403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Not a workspace member")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.ListAuditLog403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can view the audit log")}, nil
+	}
+
+	params := request.Params
+	if params.Since != nil && params.Until != nil && !params.Since.Before(*params.Until) {
+		return openapi.ListAuditLog400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "since must be before until")}, nil
+	}
+
+	filter := moderation.AuditLogFilter{Since: params.Since, Until: params.Until}
+	if params.ActorId != nil {
+		filter.ActorID = *params.ActorId
+	}
+	if params.Action != nil {
+		filter.Action = *params.Action
+	}
+	cursor := ""
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+	limit := 50
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	entries, hasMore, nextCursor, err := h.moderationRepo.ListAuditLog(ctx, string(request.Wid), filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := openapi.ListAuditLog200JSONResponse{
+		Entries: auditLogEntriesToAPI(entries),
+		HasMore: hasMore,
+	}
+	if hasMore {
+		resp.NextCursor = &nextCursor
+	}
+	return resp, nil
+}
+
+func auditLogEntriesToAPI(entries []moderation.AuditLogEntryWithActor) []openapi.ModerationLogEntryWithActor {
 	apiEntries := make([]openapi.ModerationLogEntryWithActor, len(entries))
 	for i, e := range entries {
 		var metadata *map[string]interface{}
@@ -375,10 +434,5 @@ func (h *Handler) ListModerationLog(ctx context.Context, request openapi.ListMod
 			TargetDisplayName: e.TargetDisplayName,
 		}
 	}
-
-	return openapi.ListModerationLog200JSONResponse{
-		Entries:    apiEntries,
-		HasMore:    hasMore,
-		NextCursor: &nextCursor,
-	}, nil
+	return apiEntries
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
 )
 
 func TestBlockUser_Success(t *testing.T) {
@@ -655,3 +656,79 @@ func TestListMessages_ExpiredBanDoesNotFilter(t *testing.T) {
 		t.Error("expired ban should not filter messages")
 	}
 }
+
+func TestListAuditLog_RecordsAdminActions(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+
+	// Saving an unchanged name is not logged; a rename is
+	for _, name := range []string{"WS", "Renamed"} {
+		if _, err := h.UpdateWorkspace(ownerCtx, openapi.UpdateWorkspaceRequestObject{
+			Wid:  ws.ID,
+			Body: &openapi.UpdateWorkspaceJSONRequestBody{Name: &name},
+		}); err != nil {
+			t.Fatalf("UpdateWorkspace: %v", err)
+		}
+	}
+	if _, err := h.CreateWorkspaceInvite(ownerCtx, openapi.CreateWorkspaceInviteRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateWorkspaceInviteJSONRequestBody{Role: openapi.WorkspaceRole("member")},
+	}); err != nil {
+		t.Fatalf("CreateWorkspaceInvite: %v", err)
+	}
+
+	list := func(ctx context.Context, params openapi.ListAuditLogParams) openapi.ListAuditLogResponseObject {
+		t.Helper()
+		resp, err := h.ListAuditLog(ctx, openapi.ListAuditLogRequestObject{Wid: ws.ID, Params: params})
+		if err != nil {
+			t.Fatalf("ListAuditLog: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := list(ctxWithUser(t, h, member.ID), openapi.ListAuditLogParams{}).(openapi.ListAuditLog403JSONResponse); !ok {
+		t.Fatal("expected 403 for a regular member")
+	}
+
+	resp, ok := list(ownerCtx, openapi.ListAuditLogParams{}).(openapi.ListAuditLog200JSONResponse)
+	if !ok || len(resp.Entries) != 2 {
+		t.Fatalf("expected a rename and an invite entry, got %+v", resp)
+	}
+	if resp.Entries[0].Action != "invite.created" || resp.Entries[1].Action != "workspace.updated" {
+		t.Errorf("unexpected actions %q, %q", resp.Entries[0].Action, resp.Entries[1].Action)
+	}
+	if changes, _ := (*resp.Entries[1].Metadata)["changes"].(map[string]interface{}); changes["name"] != "Renamed" {
+		t.Errorf("expected the rename in metadata, got %v", resp.Entries[1].Metadata)
+	}
+
+	action := "workspace."
+	filtered := list(ownerCtx, openapi.ListAuditLogParams{Action: &action}).(openapi.ListAuditLog200JSONResponse)
+	if len(filtered.Entries) != 1 || filtered.Entries[0].Action != "workspace.updated" {
+		t.Errorf("expected only the workspace update, got %+v", filtered.Entries)
+	}
+
+	since := time.Now()
+	until := since.Add(-time.Hour)
+	if _, ok := list(ownerCtx, openapi.ListAuditLogParams{Since: &since, Until: &until}).(openapi.ListAuditLog400JSONResponse); !ok {
+		t.Error("expected 400 when since is after until")
+	}
+}
+
+func TestChangedSettings(t *testing.T) {
+	before := workspace.WorkspaceSettings{LinkPreviews: true, MessageRetentionDays: 30}
+	after := before
+	after.LinkPreviews = false
+
+	changes := changedSettings(before, after)
+	if len(changes) != 1 || changes["link_previews"] != false {
+		t.Errorf("expected only link_previews to change, got %v", changes)
+	}
+	if len(changedSettings(before, before)) != 0 {
+		t.Error("expected no changes for identical settings")
+	}
+}
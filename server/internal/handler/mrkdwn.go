@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/enzyme/server/internal/mrkdwn"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// renderContent parses message content for content_rendered. Deleted and
+// empty messages have nothing to render.
+func renderContent(content string, deletedAt *time.Time) *[]openapi.MrkdwnNode {
+	if deletedAt != nil || content == "" {
+		return nil
+	}
+	nodes := mrkdwnNodesToAPI(mrkdwn.Parse(content))
+	return &nodes
+}
+
+func mrkdwnNodesToAPI(nodes []mrkdwn.Node) []openapi.MrkdwnNode {
+	result := make([]openapi.MrkdwnNode, len(nodes))
+	for i := range nodes {
+		result[i] = mrkdwnNodeToAPI(&nodes[i])
+	}
+	return result
+}
+
+func mrkdwnNodeToAPI(n *mrkdwn.Node) openapi.MrkdwnNode {
+	apiNode := openapi.MrkdwnNode{
+		Type:      openapi.MrkdwnNodeType(n.Type),
+		Text:      ptrIfNotEmpty(n.Text),
+		Language:  ptrIfNotEmpty(n.Language),
+		Url:       ptrIfNotEmpty(n.URL),
+		UserId:    ptrIfNotEmpty(n.UserID),
+		GroupId:   ptrIfNotEmpty(n.GroupID),
+		ChannelId: ptrIfNotEmpty(n.ChannelID),
+		Mention:   ptrIfNotEmpty(n.Mention),
+		Name:      ptrIfNotEmpty(n.Name),
+	}
+	if n.Children != nil {
+		children := mrkdwnNodesToAPI(n.Children)
+		apiNode.Children = &children
+	}
+	if n.Items != nil {
+		items := make([][]openapi.MrkdwnNode, len(n.Items))
+		for i, item := range n.Items {
+			items[i] = mrkdwnNodesToAPI(item)
+		}
+		apiNode.Items = &items
+	}
+	return apiNode
+}
+
+func ptrIfNotEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// GetMyNotificationSettings returns the user's global notification settings
+func (h *Handler) GetMyNotificationSettings(ctx context.Context, request openapi.GetMyNotificationSettingsRequestObject) (openapi.GetMyNotificationSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetMyNotificationSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	settings, err := h.notificationService.GetGlobalSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.GetMyNotificationSettings200JSONResponse{Preferences: notificationSettingsToAPI(settings)}, nil
+}
+
+// UpdateMyNotificationSettings sets the user's global notification settings
+func (h *Handler) UpdateMyNotificationSettings(ctx context.Context, request openapi.UpdateMyNotificationSettingsRequestObject) (openapi.UpdateMyNotificationSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateMyNotificationSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if !validNotifyLevel(string(request.Body.NotifyLevel)) {
+		return openapi.UpdateMyNotificationSettings400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid notify_level")}, nil
+	}
+
+	settings := &notification.Settings{
+		UserID:       userID,
+		NotifyLevel:  string(request.Body.NotifyLevel),
+		EmailEnabled: request.Body.EmailEnabled,
+	}
+	if err := h.notificationService.SetSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return openapi.UpdateMyNotificationSettings200JSONResponse{Preferences: notificationSettingsToAPI(settings)}, nil
+}
+
+// GetWorkspaceNotificationSettings returns the user's notification settings for a workspace
+func (h *Handler) GetWorkspaceNotificationSettings(ctx context.Context, request openapi.GetWorkspaceNotificationSettingsRequestObject) (openapi.GetWorkspaceNotificationSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetWorkspaceNotificationSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.GetWorkspaceNotificationSettings403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	settings, err := h.notificationService.GetWorkspaceSettings(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.GetWorkspaceNotificationSettings200JSONResponse{Preferences: notificationSettingsToAPI(settings)}, nil
+}
+
+// UpdateWorkspaceNotificationSettings sets the user's notification settings for a workspace
+func (h *Handler) UpdateWorkspaceNotificationSettings(ctx context.Context, request openapi.UpdateWorkspaceNotificationSettingsRequestObject) (openapi.UpdateWorkspaceNotificationSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateWorkspaceNotificationSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UpdateWorkspaceNotificationSettings403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	if !validNotifyLevel(string(request.Body.NotifyLevel)) {
+		return openapi.UpdateWorkspaceNotificationSettings400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid notify_level")}, nil
+	}
+
+	settings := &notification.Settings{
+		UserID:       userID,
+		WorkspaceID:  workspaceID,
+		NotifyLevel:  string(request.Body.NotifyLevel),
+		EmailEnabled: request.Body.EmailEnabled,
+	}
+	if err := h.notificationService.SetSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return openapi.UpdateWorkspaceNotificationSettings200JSONResponse{Preferences: notificationSettingsToAPI(settings)}, nil
+}
+
+// ResetWorkspaceNotificationSettings makes a workspace follow the user's global settings again
+func (h *Handler) ResetWorkspaceNotificationSettings(ctx context.Context, request openapi.ResetWorkspaceNotificationSettingsRequestObject) (openapi.ResetWorkspaceNotificationSettingsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ResetWorkspaceNotificationSettings401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ResetWorkspaceNotificationSettings403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	if err := h.notificationService.ResetWorkspaceSettings(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+	settings, err := h.notificationService.GetWorkspaceSettings(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.ResetWorkspaceNotificationSettings200JSONResponse{Preferences: notificationSettingsToAPI(settings)}, nil
+}
+
+// maxSnoozeMinutes is the longest snooze, one week
+const maxSnoozeMinutes = 7 * 24 * 60
+
+// SnoozeChannel silences the user's notifications for a channel for a while
+func (h *Handler) SnoozeChannel(ctx context.Context, request openapi.SnoozeChannelRequestObject) (openapi.SnoozeChannelResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SnoozeChannel401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	until, ok := snoozeEnd(request.Body.DurationMinutes)
+	if !ok {
+		return openapi.SnoozeChannel400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("duration_minutes must be between 1 and %d", maxSnoozeMinutes))}, nil
+	}
+
+	if err := h.channelRepo.SnoozeChannel(ctx, userID, string(request.Id), until); err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.SnoozeChannel404JSONResponse{NotFoundJSONResponse: notFoundResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+	return openapi.SnoozeChannel200JSONResponse{SnoozedUntil: until}, nil
+}
+
+// UnsnoozeChannel ends the user's snooze of a channel
+func (h *Handler) UnsnoozeChannel(ctx context.Context, request openapi.UnsnoozeChannelRequestObject) (openapi.UnsnoozeChannelResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UnsnoozeChannel401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if err := h.channelRepo.UnsnoozeChannel(ctx, userID, string(request.Id)); err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.UnsnoozeChannel404JSONResponse{NotFoundJSONResponse: notFoundResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+	return openapi.UnsnoozeChannel200JSONResponse{Success: true}, nil
+}
+
+// SnoozeWorkspace silences the user's notifications for every channel in a
+// workspace for a while
+func (h *Handler) SnoozeWorkspace(ctx context.Context, request openapi.SnoozeWorkspaceRequestObject) (openapi.SnoozeWorkspaceResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SnoozeWorkspace401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	until, ok := snoozeEnd(request.Body.DurationMinutes)
+	if !ok {
+		return openapi.SnoozeWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("duration_minutes must be between 1 and %d", maxSnoozeMinutes))}, nil
+	}
+
+	if err := h.workspaceRepo.SnoozeNotifications(ctx, userID, string(request.Wid), until); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.SnoozeWorkspace403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+	return openapi.SnoozeWorkspace200JSONResponse{SnoozedUntil: until}, nil
+}
+
+// UnsnoozeWorkspace ends the user's snooze of a workspace
+func (h *Handler) UnsnoozeWorkspace(ctx context.Context, request openapi.UnsnoozeWorkspaceRequestObject) (openapi.UnsnoozeWorkspaceResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UnsnoozeWorkspace401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if err := h.workspaceRepo.UnsnoozeNotifications(ctx, userID, string(request.Wid)); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UnsnoozeWorkspace403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+	return openapi.UnsnoozeWorkspace200JSONResponse{Success: true}, nil
+}
+
+// snoozeEnd returns when a snooze of the given length starting now ends, and
+// false if the length is out of range. The end is cut to whole seconds as
+// that is how it's stored.
+func snoozeEnd(minutes int) (time.Time, bool) {
+	if minutes < 1 || minutes > maxSnoozeMinutes {
+		return time.Time{}, false
+	}
+	return time.Now().UTC().Add(time.Duration(minutes) * time.Minute).Truncate(time.Second), true
+}
+
+// ListMyKeywords lists the user's notification keywords
+func (h *Handler) ListMyKeywords(ctx context.Context, request openapi.ListMyKeywordsRequestObject) (openapi.ListMyKeywordsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListMyKeywords401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	keywords, err := h.notificationService.ListKeywords(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	apiKeywords := make([]openapi.NotificationKeyword, len(keywords))
+	for i := range keywords {
+		apiKeywords[i] = notificationKeywordToAPI(&keywords[i])
+	}
+	return openapi.ListMyKeywords200JSONResponse{Keywords: apiKeywords}, nil
+}
+
+// AddMyKeyword adds a word or phrase that notifies the user like a mention
+func (h *Handler) AddMyKeyword(ctx context.Context, request openapi.AddMyKeywordRequestObject) (openapi.AddMyKeywordResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.AddMyKeyword401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	keyword := &notification.Keyword{
+		UserID:  userID,
+		Keyword: strings.Join(strings.Fields(request.Body.Keyword), " "),
+	}
+	if keyword.Keyword == "" {
+		return openapi.AddMyKeyword400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Keyword is required")}, nil
+	}
+	if utf8.RuneCountInString(keyword.Keyword) > notification.MaxKeywordLength {
+		return openapi.AddMyKeyword400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Keyword must be at most %d characters", notification.MaxKeywordLength))}, nil
+	}
+
+	if err := h.notificationService.AddKeyword(ctx, keyword); err != nil {
+		switch {
+		case errors.Is(err, notification.ErrKeywordExists):
+			return openapi.AddMyKeyword409JSONResponse{ConflictJSONResponse: conflictResponse("You already have this keyword")}, nil
+		case errors.Is(err, notification.ErrTooManyKeywords):
+			return openapi.AddMyKeyword400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("You can have at most %d keywords", notification.MaxKeywordsPerUser))}, nil
+		}
+		return nil, err
+	}
+	return openapi.AddMyKeyword200JSONResponse{Keyword: notificationKeywordToAPI(keyword)}, nil
+}
+
+// DeleteMyKeyword removes one of the user's notification keywords
+func (h *Handler) DeleteMyKeyword(ctx context.Context, request openapi.DeleteMyKeywordRequestObject) (openapi.DeleteMyKeywordResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteMyKeyword401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if err := h.notificationService.DeleteKeyword(ctx, userID, request.Id); err != nil {
+		if errors.Is(err, notification.ErrKeywordNotFound) {
+			return openapi.DeleteMyKeyword404JSONResponse{NotFoundJSONResponse: notFoundResponse("Keyword not found")}, nil
+		}
+		return nil, err
+	}
+	return openapi.DeleteMyKeyword200JSONResponse{Success: true}, nil
+}
+
+// addKeywordMentions adds the channel members with a notification keyword in
+// content to mentions. Failures are only logged: keywords are secondary to
+// sending the message.
+func (h *Handler) addKeywordMentions(ctx context.Context, channelID, senderID, content string, mentions []string) []string {
+	matched, err := h.notificationService.KeywordMentions(ctx, channelID, senderID, content)
+	if err != nil {
+		slog.Error("failed to match notification keywords", "component", "mentions", "error", err)
+		return mentions
+	}
+	for _, userID := range matched {
+		if !slices.Contains(mentions, userID) {
+			mentions = append(mentions, userID)
+		}
+	}
+	return mentions
+}
+
+func validNotifyLevel(level string) bool {
+	return level == notification.NotifyAll || level == notification.NotifyMentions || level == notification.NotifyNone
+}
+
+// notificationSettingsToAPI converts workspace or global notification settings to API type
+func notificationSettingsToAPI(settings *notification.Settings) openapi.NotificationPreferences {
+	return openapi.NotificationPreferences{
+		NotifyLevel:  openapi.NotifyLevel(settings.NotifyLevel),
+		EmailEnabled: settings.EmailEnabled,
+		Inherited:    &settings.Inherited,
+	}
+}
+
+// notificationKeywordToAPI converts a notification.Keyword to openapi.NotificationKeyword
+func notificationKeywordToAPI(k *notification.Keyword) openapi.NotificationKeyword {
+	return openapi.NotificationKeyword{
+		Id:        k.ID,
+		Keyword:   k.Keyword,
+		CreatedAt: k.CreatedAt,
+	}
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestSendMessage_KeywordNotifiesMember(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	for _, u := range []string{alice.ID, bob.ID} {
+		addWorkspaceMember(t, db, u, ws.ID, "member")
+		addChannelMember(t, db, u, ch.ID, nil)
+	}
+
+	aliceCtx := ctxWithUser(t, h, alice.ID)
+	addKeyword := func(keyword string) openapi.AddMyKeywordResponseObject {
+		t.Helper()
+		resp, err := h.AddMyKeyword(aliceCtx, openapi.AddMyKeywordRequestObject{
+			Body: &openapi.AddMyKeywordJSONRequestBody{Keyword: keyword},
+		})
+		if err != nil {
+			t.Fatalf("AddMyKeyword: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := addKeyword("   ").(openapi.AddMyKeyword400JSONResponse); !ok {
+		t.Fatal("expected 400 for a blank keyword")
+	}
+	added, ok := addKeyword("  release   train ").(openapi.AddMyKeyword200JSONResponse)
+	if !ok {
+		t.Fatal("expected the keyword to be added")
+	}
+	if added.Keyword.Keyword != "release train" {
+		t.Errorf("keyword = %q, want whitespace collapsed", added.Keyword.Keyword)
+	}
+	if _, ok := addKeyword("Release Train").(openapi.AddMyKeyword409JSONResponse); !ok {
+		t.Fatal("expected 409 for a keyword differing only in case")
+	}
+
+	send := func(content string) {
+		t.Helper()
+		if _, err := h.SendMessage(ctxWithUser(t, h, owner.ID), openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+		}); err != nil {
+			t.Fatalf("sending message: %v", err)
+		}
+	}
+	notificationCount := func(userID string) int {
+		t.Helper()
+		var count int
+		if err := db.QueryRow(`
+			SELECT notification_count FROM channel_memberships WHERE user_id = ? AND channel_id = ?
+		`, userID, ch.ID).Scan(&count); err != nil {
+			t.Fatalf("reading notification count: %v", err)
+		}
+		return count
+	}
+
+	send("The RELEASE TRAIN leaves at noon")
+	send("release trains are on hold")
+	if got := notificationCount(alice.ID); got != 1 {
+		t.Errorf("alice's notification_count = %d, want 1", got)
+	}
+	if got := notificationCount(bob.ID); got != 0 {
+		t.Errorf("bob's notification_count = %d, want 0", got)
+	}
+
+	del, err := h.DeleteMyKeyword(ctxWithUser(t, h, bob.ID), openapi.DeleteMyKeywordRequestObject{Id: added.Keyword.Id})
+	if err != nil {
+		t.Fatalf("DeleteMyKeyword: %v", err)
+	}
+	if _, ok := del.(openapi.DeleteMyKeyword404JSONResponse); !ok {
+		t.Fatalf("expected 404 deleting another user's keyword, got %T", del)
+	}
+	del, err = h.DeleteMyKeyword(aliceCtx, openapi.DeleteMyKeywordRequestObject{Id: added.Keyword.Id})
+	if err != nil {
+		t.Fatalf("DeleteMyKeyword: %v", err)
+	}
+	if _, ok := del.(openapi.DeleteMyKeyword200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", del)
+	}
+
+	send("release train is back")
+	if got := notificationCount(alice.ID); got != 1 {
+		t.Errorf("alice's notification_count = %d after deleting the keyword, want 1", got)
+	}
+
+	list, err := h.ListMyKeywords(aliceCtx, openapi.ListMyKeywordsRequestObject{})
+	if err != nil {
+		t.Fatalf("ListMyKeywords: %v", err)
+	}
+	if keywords := list.(openapi.ListMyKeywords200JSONResponse).Keywords; len(keywords) != 0 {
+		t.Errorf("expected no keywords left, got %+v", keywords)
+	}
+}
+
+func TestSnoozeChannel(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	memberCtx := ctxWithUser(t, h, member.ID)
+	snooze := func(ctx context.Context, minutes int) openapi.SnoozeChannelResponseObject {
+		t.Helper()
+		resp, err := h.SnoozeChannel(ctx, openapi.SnoozeChannelRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SnoozeChannelJSONRequestBody{DurationMinutes: minutes},
+		})
+		if err != nil {
+			t.Fatalf("SnoozeChannel: %v", err)
+		}
+		return resp
+	}
+	listedSnooze := func() *time.Time {
+		t.Helper()
+		resp, err := h.ListChannels(memberCtx, openapi.ListChannelsRequestObject{Wid: ws.ID})
+		if err != nil {
+			t.Fatalf("ListChannels: %v", err)
+		}
+		for _, c := range resp.(openapi.ListChannels200JSONResponse).Body.Channels {
+			if c.Id == ch.ID {
+				return c.SnoozedUntil
+			}
+		}
+		t.Fatal("channel not listed")
+		return nil
+	}
+
+	if _, ok := snooze(memberCtx, 0).(openapi.SnoozeChannel400JSONResponse); !ok {
+		t.Fatal("expected 400 for a zero duration")
+	}
+	if _, ok := snooze(memberCtx, maxSnoozeMinutes+1).(openapi.SnoozeChannel400JSONResponse); !ok {
+		t.Fatal("expected 400 for a snooze longer than a week")
+	}
+	if _, ok := snooze(ctxWithUser(t, h, outsider.ID), 30).(openapi.SnoozeChannel404JSONResponse); !ok {
+		t.Fatal("expected 404 for a non-member")
+	}
+
+	resp, ok := snooze(memberCtx, 30).(openapi.SnoozeChannel200JSONResponse)
+	if !ok {
+		t.Fatal("expected the channel to be snoozed")
+	}
+	if d := time.Until(resp.SnoozedUntil); d < 29*time.Minute || d > 30*time.Minute {
+		t.Errorf("snoozed_until is %v away, want about 30 minutes", d)
+	}
+	if until := listedSnooze(); until == nil || !until.Equal(resp.SnoozedUntil) {
+		t.Errorf("listed snoozed_until = %v, want %v", until, resp.SnoozedUntil)
+	}
+
+	unsnooze, err := h.UnsnoozeChannel(memberCtx, openapi.UnsnoozeChannelRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("UnsnoozeChannel: %v", err)
+	}
+	if _, ok := unsnooze.(openapi.UnsnoozeChannel200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", unsnooze)
+	}
+	if until := listedSnooze(); until != nil {
+		t.Errorf("listed snoozed_until = %v after unsnoozing, want none", until)
+	}
+
+	// A workspace snooze shows on its channels
+	wsResp, err := h.SnoozeWorkspace(memberCtx, openapi.SnoozeWorkspaceRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.SnoozeWorkspaceJSONRequestBody{DurationMinutes: 60},
+	})
+	if err != nil {
+		t.Fatalf("SnoozeWorkspace: %v", err)
+	}
+	snoozed, ok := wsResp.(openapi.SnoozeWorkspace200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", wsResp)
+	}
+	if until := listedSnooze(); until == nil || !until.Equal(snoozed.SnoozedUntil) {
+		t.Errorf("listed snoozed_until = %v, want the workspace snooze %v", until, snoozed.SnoozedUntil)
+	}
+
+	wsResp, err = h.SnoozeWorkspace(ctxWithUser(t, h, outsider.ID), openapi.SnoozeWorkspaceRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.SnoozeWorkspaceJSONRequestBody{DurationMinutes: 60},
+	})
+	if err != nil {
+		t.Fatalf("SnoozeWorkspace: %v", err)
+	}
+	if _, ok := wsResp.(openapi.SnoozeWorkspace403JSONResponse); !ok {
+		t.Errorf("expected 403 for a non-member, got %T", wsResp)
+	}
+}
@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/poll"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+const (
+	maxPollQuestionLength = 300
+	maxPollOptionLength   = 100
+)
+
+// CreatePoll posts a poll to a channel as a poll message
+func (h *Handler) CreatePoll(ctx context.Context, request openapi.CreatePollRequestObject) (openapi.CreatePollResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreatePoll401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.CreatePoll404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	// Ban check required here because this route uses channel ID, not workspace ID,
+	// so the ban middleware cannot intercept it.
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID); ban != nil {
+		return openapi.CreatePoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	if ch.ArchivedAt != nil {
+		return openapi.CreatePoll400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot post to archived channel")}, nil
+	}
+
+	membership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if err != nil {
+		if errors.Is(err, channel.ErrNotChannelMember) {
+			return openapi.CreatePoll403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
+		}
+		return nil, err
+	}
+	if ok, err := h.canPostIn(ctx, ch, userID, membership.ChannelRole, false); err != nil {
+		return nil, err
+	} else if !ok {
+		return openapi.CreatePoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
+
+	p, msg := validatePoll(request.Body)
+	if msg != "" {
+		return openapi.CreatePoll400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	pollMsg := &message.Message{
+		ChannelID: ch.ID,
+		UserID:    &userID,
+		Content:   p.Question,
+		Type:      message.MessageTypePoll,
+	}
+	if err := h.messageRepo.Create(ctx, pollMsg); err != nil {
+		return nil, err
+	}
+
+	p.MessageID = pollMsg.ID
+	p.ChannelID = ch.ID
+	p.CreatedBy = &userID
+	if err := h.pollRepo.Create(ctx, p); err != nil {
+		// Don't leave a poll message without its poll behind
+		if delErr := h.messageRepo.Delete(ctx, pollMsg.ID, userID); delErr != nil {
+			slog.Error("failed to delete message of failed poll", "message_id", pollMsg.ID, "error", delErr)
+		}
+		return nil, err
+	}
+
+	msgWithUser, err := h.messageRepo.GetByIDWithUser(ctx, pollMsg.ID)
+	if err != nil {
+		return nil, err
+	}
+	msgWithUser.Poll = p
+
+	apiMsg := messageWithUserToAPI(msgWithUser)
+	if h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(apiMsg))
+	}
+
+	return openapi.CreatePoll200JSONResponse{Message: apiMsg}, nil
+}
+
+// GetPoll returns a poll with its results and the caller's votes
+func (h *Handler) GetPoll(ctx context.Context, request openapi.GetPollRequestObject) (openapi.GetPollResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetPoll401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	p, _, err := h.getReadablePoll(ctx, request.Id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, poll.ErrPollNotFound):
+			return openapi.GetPoll404JSONResponse{NotFoundJSONResponse: notFoundResponse("Poll not found")}, nil
+		case errors.Is(err, errPollForbidden):
+			return openapi.GetPoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot view this poll")}, nil
+		}
+		return nil, err
+	}
+
+	return openapi.GetPoll200JSONResponse{Poll: pollToAPI(p)}, nil
+}
+
+// VotePoll replaces the caller's votes in a poll
+func (h *Handler) VotePoll(ctx context.Context, request openapi.VotePollRequestObject) (openapi.VotePollResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.VotePoll401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	p, ch, err := h.getReadablePoll(ctx, request.Id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, poll.ErrPollNotFound):
+			return openapi.VotePoll404JSONResponse{NotFoundJSONResponse: notFoundResponse("Poll not found")}, nil
+		case errors.Is(err, errPollForbidden):
+			return openapi.VotePoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot vote in this poll")}, nil
+		}
+		return nil, err
+	}
+
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ch.WorkspaceID, userID); ban != nil {
+		return openapi.VotePoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	if p.IsClosed(time.Now()) {
+		return openapi.VotePoll409JSONResponse{ConflictJSONResponse: conflictResponse("This poll is closed")}, nil
+	}
+
+	optionIDs := request.Body.OptionIds
+	if len(optionIDs) > 1 && !p.MultipleChoice {
+		return openapi.VotePoll400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "This poll allows only one choice")}, nil
+	}
+	for _, id := range optionIDs {
+		if !p.HasOption(id) {
+			return openapi.VotePoll400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Option %s is not part of this poll", id))}, nil
+		}
+	}
+
+	if err := h.pollRepo.Vote(ctx, p.ID, userID, optionIDs); err != nil {
+		if errors.Is(err, poll.ErrPollClosed) {
+			return openapi.VotePoll409JSONResponse{ConflictJSONResponse: conflictResponse("This poll is closed")}, nil
+		}
+		return nil, err
+	}
+
+	p, err = h.pollRepo.GetByID(ctx, p.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	h.broadcastPollUpdated(ctx, ch, p)
+
+	return openapi.VotePoll200JSONResponse{Poll: pollToAPI(p)}, nil
+}
+
+// ClosePoll ends voting on a poll before its close time
+func (h *Handler) ClosePoll(ctx context.Context, request openapi.ClosePollRequestObject) (openapi.ClosePollResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ClosePoll401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	p, ch, err := h.getReadablePoll(ctx, request.Id, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, poll.ErrPollNotFound):
+			return openapi.ClosePoll404JSONResponse{NotFoundJSONResponse: notFoundResponse("Poll not found")}, nil
+		case errors.Is(err, errPollForbidden):
+			return openapi.ClosePoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only the poll's creator or an admin can close it")}, nil
+		}
+		return nil, err
+	}
+
+	if p.CreatedBy == nil || *p.CreatedBy != userID {
+		membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+		if err != nil || !workspace.CanManageMembers(membership.Role) {
+			return openapi.ClosePoll403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only the poll's creator or an admin can close it")}, nil
+		}
+	}
+
+	closed, err := h.pollRepo.Close(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return openapi.ClosePoll409JSONResponse{ConflictJSONResponse: conflictResponse("This poll is already closed")}, nil
+	}
+
+	p, err = h.pollRepo.GetByID(ctx, p.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	h.broadcastPollUpdated(ctx, ch, p)
+
+	return openapi.ClosePoll200JSONResponse{Poll: pollToAPI(p)}, nil
+}
+
+// PollClosed implements poll.Notifier, publishing the final results of a
+// poll closed by the worker.
+func (h *Handler) PollClosed(ctx context.Context, pollID string) {
+	p, err := h.pollRepo.GetByID(ctx, pollID, "")
+	if err != nil {
+		slog.Error("failed to load closed poll", "poll_id", pollID, "error", err)
+		return
+	}
+	ch, err := h.channelRepo.GetByID(ctx, p.ChannelID)
+	if err != nil {
+		slog.Error("failed to load channel of closed poll", "poll_id", pollID, "error", err)
+		return
+	}
+	h.broadcastPollUpdated(ctx, ch, p)
+}
+
+// errPollForbidden is returned by getReadablePoll when the user cannot read
+// the poll's channel.
+var errPollForbidden = errors.New("poll not readable")
+
+// getReadablePoll loads a poll with userID's votes, along with its channel.
+// Polls of deleted messages are reported as not found.
+func (h *Handler) getReadablePoll(ctx context.Context, id, userID string) (*poll.Poll, *channel.Channel, error) {
+	p, err := h.pollRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := h.messageRepo.GetByID(ctx, p.MessageID)
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return nil, nil, poll.ErrPollNotFound
+		}
+		return nil, nil, err
+	}
+	if msg.DeletedAt != nil {
+		return nil, nil, poll.ErrPollNotFound
+	}
+	ch, err := h.channelRepo.GetByID(ctx, p.ChannelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !h.canReadChannel(ctx, ch, userID) {
+		return nil, nil, errPollForbidden
+	}
+	return p, ch, nil
+}
+
+// broadcastPollUpdated sends a poll's results to its channel. The results
+// are the same for every recipient, so the votes of whoever triggered the
+// update are left out.
+func (h *Handler) broadcastPollUpdated(ctx context.Context, ch *channel.Channel, p *poll.Poll) {
+	if h.hub == nil {
+		return
+	}
+	shared := *p
+	shared.MyVotes = nil
+	h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewPollUpdatedEvent(pollToAPI(&shared)))
+}
+
+// loadPollsForMessages loads the polls of poll messages in batch
+func (h *Handler) loadPollsForMessages(ctx context.Context, messages []message.MessageWithUser, userID string) {
+	var messageIDs []string
+	for _, m := range messages {
+		if m.Type == message.MessageTypePoll {
+			messageIDs = append(messageIDs, m.ID)
+		}
+	}
+	if len(messageIDs) == 0 {
+		return
+	}
+
+	polls, err := h.pollRepo.ListForMessages(ctx, messageIDs, userID)
+	if err != nil {
+		return
+	}
+
+	for i := range messages {
+		if p, ok := polls[messages[i].ID]; ok {
+			messages[i].Poll = p
+		}
+	}
+}
+
+// validatePoll checks a poll request and returns the poll to create, or a
+// validation message.
+func validatePoll(body *openapi.CreatePollJSONRequestBody) (*poll.Poll, string) {
+	question := strings.TrimSpace(body.Question)
+	if question == "" {
+		return nil, "Question is required"
+	}
+	if utf8.RuneCountInString(question) > maxPollQuestionLength {
+		return nil, fmt.Sprintf("Question must be at most %d characters", maxPollQuestionLength)
+	}
+
+	if len(body.Options) < poll.MinOptions || len(body.Options) > poll.MaxOptions {
+		return nil, fmt.Sprintf("A poll needs between %d and %d options", poll.MinOptions, poll.MaxOptions)
+	}
+	seen := make(map[string]bool, len(body.Options))
+	options := make([]poll.Option, len(body.Options))
+	for i, text := range body.Options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, "Options must not be empty"
+		}
+		if utf8.RuneCountInString(text) > maxPollOptionLength {
+			return nil, fmt.Sprintf("Options must be at most %d characters", maxPollOptionLength)
+		}
+		key := strings.ToLower(text)
+		if seen[key] {
+			return nil, "Options must be unique"
+		}
+		seen[key] = true
+		options[i] = poll.Option{Text: text}
+	}
+
+	if body.ClosesAt != nil && !body.ClosesAt.After(time.Now()) {
+		return nil, "closes_at must be in the future"
+	}
+
+	p := &poll.Poll{
+		Question: question,
+		Options:  options,
+		ClosesAt: body.ClosesAt,
+	}
+	if body.MultipleChoice != nil {
+		p.MultipleChoice = *body.MultipleChoice
+	}
+	if body.Anonymous != nil {
+		p.Anonymous = *body.Anonymous
+	}
+	return p, ""
+}
+
+func pollToAPI(p *poll.Poll) openapi.Poll {
+	options := make([]openapi.PollOption, len(p.Options))
+	for i, o := range p.Options {
+		options[i] = openapi.PollOption{
+			Id:        o.ID,
+			Text:      o.Text,
+			VoteCount: o.VoteCount,
+		}
+		if o.VoterIDs != nil {
+			voters := o.VoterIDs
+			options[i].VoterIds = &voters
+		}
+	}
+
+	apiPoll := openapi.Poll{
+		Id:             p.ID,
+		MessageId:      p.MessageID,
+		ChannelId:      p.ChannelID,
+		CreatedBy:      p.CreatedBy,
+		Question:       p.Question,
+		MultipleChoice: p.MultipleChoice,
+		Anonymous:      p.Anonymous,
+		ClosesAt:       p.ClosesAt,
+		ClosedAt:       p.ClosedAt,
+		Options:        options,
+		VoterCount:     p.VoterCount,
+		CreatedAt:      p.CreatedAt,
+	}
+	if p.MyVotes != nil {
+		myVotes := p.MyVotes
+		apiPoll.MyVotes = &myVotes
+	}
+	return apiPoll
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func createPollViaAPI(t *testing.T, h *Handler, userID, channelID string, body openapi.CreatePollJSONRequestBody) openapi.MessageWithUser {
+	t.Helper()
+
+	resp, err := h.CreatePoll(ctxWithUser(t, h, userID), openapi.CreatePollRequestObject{Id: channelID, Body: &body})
+	if err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+	r, ok := resp.(openapi.CreatePoll200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %#v", resp)
+	}
+	return r.Message
+}
+
+func TestCreatePoll_VoteAndClose(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+
+	msg := createPollViaAPI(t, h, owner.ID, ch.ID, openapi.CreatePollJSONRequestBody{
+		Question: " Lunch? ",
+		Options:  []string{"Pizza", "Sushi"},
+	})
+	if msg.Type == nil || *msg.Type != openapi.MessageTypePoll || msg.Content != "Lunch?" {
+		t.Fatalf("message = type %v content %q, want a poll message with the question", msg.Type, msg.Content)
+	}
+	if msg.Poll == nil || len(msg.Poll.Options) != 2 {
+		t.Fatalf("poll = %+v, want two options", msg.Poll)
+	}
+	expectSSEEvent(t, client, sse.EventMessageNew)
+
+	pollID := msg.Poll.Id
+	pizza, sushi := msg.Poll.Options[0].Id, msg.Poll.Options[1].Id
+	memberCtx := ctxWithUser(t, h, member.ID)
+	vote := func(optionIDs ...string) openapi.VotePollResponseObject {
+		t.Helper()
+		resp, err := h.VotePoll(memberCtx, openapi.VotePollRequestObject{
+			Id:   pollID,
+			Body: &openapi.VotePollJSONRequestBody{OptionIds: optionIDs},
+		})
+		if err != nil {
+			t.Fatalf("VotePoll: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := vote(pizza, sushi).(openapi.VotePoll400JSONResponse); !ok {
+		t.Fatal("voting for two options in a single-choice poll should be rejected")
+	}
+	r, ok := vote(sushi).(openapi.VotePoll200JSONResponse)
+	if !ok {
+		t.Fatal("expected vote to succeed")
+	}
+	if r.Poll.Options[1].VoteCount != 1 || r.Poll.MyVotes == nil || (*r.Poll.MyVotes)[0] != sushi {
+		t.Errorf("poll after vote = %+v, want one vote for sushi by the caller", r.Poll)
+	}
+	expectSSEEvent(t, client, sse.EventPollUpdated)
+
+	// The voter's choice is shown when listing messages
+	listResp, err := h.ListMessages(memberCtx, openapi.ListMessagesRequestObject{Id: ch.ID})
+	if err != nil {
+		t.Fatalf("ListMessages: %v", err)
+	}
+	list := listResp.(openapi.ListMessages200JSONResponse)
+	if p := list.Messages[0].Poll; p == nil || p.MyVotes == nil || (*p.MyVotes)[0] != sushi {
+		t.Errorf("listed poll = %+v, want the member's vote", p)
+	}
+
+	// Only the creator or an admin may close the poll
+	closeResp, err := h.ClosePoll(memberCtx, openapi.ClosePollRequestObject{Id: pollID})
+	if err != nil {
+		t.Fatalf("ClosePoll: %v", err)
+	}
+	if _, ok := closeResp.(openapi.ClosePoll403JSONResponse); !ok {
+		t.Fatalf("member closing poll = %T, want 403", closeResp)
+	}
+	closeResp, err = h.ClosePoll(ctxWithUser(t, h, owner.ID), openapi.ClosePollRequestObject{Id: pollID})
+	if err != nil {
+		t.Fatalf("ClosePoll: %v", err)
+	}
+	if c, ok := closeResp.(openapi.ClosePoll200JSONResponse); !ok || c.Poll.ClosedAt == nil {
+		t.Fatalf("creator closing poll = %#v, want closed poll", closeResp)
+	}
+	expectSSEEvent(t, client, sse.EventPollUpdated)
+
+	if _, ok := vote(pizza).(openapi.VotePoll409JSONResponse); !ok {
+		t.Error("voting in a closed poll should conflict")
+	}
+}
+
+func TestCreatePoll_Validation(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		body openapi.CreatePollJSONRequestBody
+	}{
+		{"empty question", openapi.CreatePollJSONRequestBody{Question: " ", Options: []string{"A", "B"}}},
+		{"one option", openapi.CreatePollJSONRequestBody{Question: "Q", Options: []string{"A"}}},
+		{"duplicate options", openapi.CreatePollJSONRequestBody{Question: "Q", Options: []string{"Yes", "yes"}}},
+		{"close time in the past", openapi.CreatePollJSONRequestBody{Question: "Q", Options: []string{"A", "B"}, ClosesAt: &past}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := h.CreatePoll(ctxWithUser(t, h, owner.ID), openapi.CreatePollRequestObject{Id: ch.ID, Body: &tt.body})
+			if err != nil {
+				t.Fatalf("CreatePoll: %v", err)
+			}
+			if _, ok := resp.(openapi.CreatePoll400JSONResponse); !ok {
+				t.Errorf("expected 400 response, got %T", resp)
+			}
+		})
+	}
+}
+
+func TestPollClosed_BroadcastsAndEditsRejected(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	closesAt := time.Now().Add(time.Hour)
+	msg := createPollViaAPI(t, h, owner.ID, ch.ID, openapi.CreatePollJSONRequestBody{
+		Question: "Ship it?",
+		Options:  []string{"Yes", "No"},
+		ClosesAt: &closesAt,
+	})
+
+	content := "Ship it today?"
+	resp, err := h.UpdateMessage(ctxWithUser(t, h, owner.ID), openapi.UpdateMessageRequestObject{
+		Id:   msg.Id,
+		Body: &openapi.UpdateMessageJSONRequestBody{Content: content},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMessage: %v", err)
+	}
+	if _, ok := resp.(openapi.UpdateMessage400JSONResponse); !ok {
+		t.Errorf("editing a poll = %T, want 400", resp)
+	}
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+	if _, err := db.Exec(`UPDATE polls SET closed_at = created_at WHERE id = ?`, msg.Poll.Id); err != nil {
+		t.Fatalf("closing poll: %v", err)
+	}
+	h.PollClosed(ctxWithUser(t, h, owner.ID), msg.Poll.Id)
+	expectSSEEvent(t, client, sse.EventPollUpdated)
+}
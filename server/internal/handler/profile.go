@@ -0,0 +1,493 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // timezone validation must not depend on the host's zoneinfo
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// maxProfileFieldLength caps profile values, built-in and custom alike
+const maxProfileFieldLength = 256
+
+// maxProfileFieldOptions caps the number of options on a select field
+const maxProfileFieldOptions = 50
+
+// GetMyProfile returns the current user's structured profile fields, plus
+// a workspace's custom fields and values when one is given
+func (h *Handler) GetMyProfile(ctx context.Context, request openapi.GetMyProfileRequestObject) (openapi.GetMyProfileResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetMyProfile401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	u, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return openapi.GetMyProfile401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+		}
+		return nil, err
+	}
+
+	profile := myProfileToAPI(u)
+	if request.Params.WorkspaceId != nil {
+		fields, values, err := h.workspaceProfile(ctx, userID, *request.Params.WorkspaceId)
+		if err != nil {
+			if errors.Is(err, workspace.ErrNotAMember) {
+				return openapi.GetMyProfile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+			}
+			return nil, err
+		}
+		profile.Fields = &fields
+		profile.CustomFields = &values
+	}
+
+	return openapi.GetMyProfile200JSONResponse{Profile: profile}, nil
+}
+
+// UpdateMyProfile updates the current user's structured profile fields and,
+// for a given workspace, their custom field values
+func (h *Handler) UpdateMyProfile(ctx context.Context, request openapi.UpdateMyProfileRequestObject) (openapi.UpdateMyProfileResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateMyProfile401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	body := request.Body
+
+	u, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return openapi.UpdateMyProfile401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+		}
+		return nil, err
+	}
+
+	for _, f := range []struct {
+		label string
+		value *string
+		dest  **string
+	}{
+		{"Title", body.Title, &u.Title},
+		{"Pronouns", body.Pronouns, &u.Pronouns},
+		{"Timezone", body.Timezone, &u.Timezone},
+		{"Phone", body.Phone, &u.Phone},
+	} {
+		if f.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*f.value)
+		if len(value) > maxProfileFieldLength {
+			return openapi.UpdateMyProfile400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, f.label+" is too long")}, nil
+		}
+		if value == "" {
+			*f.dest = nil
+			continue
+		}
+		*f.dest = &value
+	}
+	if u.Timezone != nil && body.Timezone != nil {
+		if _, err := time.LoadLocation(*u.Timezone); err != nil || *u.Timezone == "Local" {
+			return openapi.UpdateMyProfile400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Unknown timezone")}, nil
+		}
+	}
+
+	if body.CustomFields != nil && body.WorkspaceId == nil {
+		return openapi.UpdateMyProfile400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "workspace_id is required to set custom fields")}, nil
+	}
+
+	var values map[string]string
+	if body.WorkspaceId != nil {
+		schema, err := h.workspaceProfileSchema(ctx, userID, *body.WorkspaceId)
+		if err != nil {
+			if errors.Is(err, workspace.ErrNotAMember) {
+				return openapi.UpdateMyProfile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+			}
+			return nil, err
+		}
+		if body.CustomFields != nil {
+			var msg string
+			if values, msg = validateCustomProfileValues(schema, *body.CustomFields); msg != "" {
+				return openapi.UpdateMyProfile400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+			}
+		}
+	}
+
+	if err := h.userRepo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	if body.CustomFields != nil {
+		if err := h.workspaceRepo.SetMemberProfileValues(ctx, userID, *body.WorkspaceId, values); err != nil {
+			return nil, err
+		}
+	}
+
+	profile := myProfileToAPI(u)
+	if body.WorkspaceId != nil {
+		fields, values, err := h.workspaceProfile(ctx, userID, *body.WorkspaceId)
+		if err != nil {
+			return nil, err
+		}
+		profile.Fields = &fields
+		profile.CustomFields = &values
+	}
+
+	return openapi.UpdateMyProfile200JSONResponse{Profile: profile}, nil
+}
+
+// maxSharedChannels caps the shared channels listed on a profile card
+const maxSharedChannels = 10
+
+// GetMemberProfile returns what a profile hover card shows about a workspace
+// member, as seen by the caller
+func (h *Handler) GetMemberProfile(ctx context.Context, request openapi.GetMemberProfileRequestObject) (openapi.GetMemberProfileResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetMemberProfile401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.GetMemberProfile403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+	membership, err := h.workspaceRepo.GetMembership(ctx, request.UserId, workspaceID)
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.GetMemberProfile404JSONResponse{NotFoundJSONResponse: notFoundResponse("Member not found")}, nil
+		}
+		return nil, err
+	}
+	u, err := h.userRepo.GetByID(ctx, request.UserId)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return openapi.GetMemberProfile404JSONResponse{NotFoundJSONResponse: notFoundResponse("Member not found")}, nil
+		}
+		return nil, err
+	}
+
+	profile := openapi.MemberProfile{
+		User:   userProfileToAPI(u),
+		Role:   openapi.WorkspaceRole(membership.Role),
+		Online: h.hub != nil && h.hub.IsUserOnline(workspaceID, u.ID),
+	}
+	if membership.DisplayNameOverride != nil {
+		profile.User.DisplayName = *membership.DisplayNameOverride
+	}
+	if u.Timezone != nil {
+		if loc, err := time.LoadLocation(*u.Timezone); err == nil {
+			now := time.Now().In(loc).Truncate(time.Second)
+			profile.LocalTime = &now
+		}
+	}
+
+	if profile.CustomFields, err = h.workspaceRepo.GetMemberProfileValues(ctx, u.ID, workspaceID); err != nil {
+		return nil, err
+	}
+	if profile.CustomFields == nil {
+		profile.CustomFields = map[string]string{}
+	}
+	if profile.LastSeenAt, err = h.workspaceRepo.GetLastSeen(ctx, u.ID, workspaceID); err != nil {
+		return nil, err
+	}
+	if profile.MessageCount, err = h.messageRepo.CountVisibleByAuthor(ctx, workspaceID, u.ID, userID); err != nil {
+		return nil, err
+	}
+
+	shared, total, err := h.channelRepo.ListShared(ctx, workspaceID, userID, u.ID, maxSharedChannels)
+	if err != nil {
+		return nil, err
+	}
+	profile.SharedChannelCount = total
+	profile.SharedChannels = make([]openapi.SharedChannel, len(shared))
+	for i, ch := range shared {
+		profile.SharedChannels[i] = openapi.SharedChannel{Id: ch.ID, Name: ch.Name, Type: openapi.ChannelType(ch.Type)}
+	}
+
+	return openapi.GetMemberProfile200JSONResponse(profile), nil
+}
+
+// workspaceProfileSchema returns a workspace's custom profile fields after
+// checking the user belongs to it
+func (h *Handler) workspaceProfileSchema(ctx context.Context, userID, workspaceID string) ([]workspace.ProfileField, error) {
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		return nil, err
+	}
+	return h.workspaceRepo.ListProfileFields(ctx, workspaceID)
+}
+
+// workspaceProfile returns a workspace's custom profile fields along with
+// the user's values for them
+func (h *Handler) workspaceProfile(ctx context.Context, userID, workspaceID string) ([]openapi.ProfileField, map[string]string, error) {
+	schema, err := h.workspaceProfileSchema(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := h.workspaceRepo.GetMemberProfileValues(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	fields := make([]openapi.ProfileField, len(schema))
+	for i := range schema {
+		fields[i] = profileFieldToAPI(&schema[i])
+	}
+	return fields, values, nil
+}
+
+// validateCustomProfileValues checks submitted values against the
+// workspace's fields, dropping empty ones. It returns a message for the
+// first invalid value.
+func validateCustomProfileValues(schema []workspace.ProfileField, submitted map[string]string) (map[string]string, string) {
+	byID := make(map[string]*workspace.ProfileField, len(schema))
+	for i := range schema {
+		byID[schema[i].ID] = &schema[i]
+	}
+
+	values := make(map[string]string, len(submitted))
+	for id, value := range submitted {
+		field, ok := byID[id]
+		if !ok {
+			return nil, "Unknown profile field: " + id
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if len(value) > maxProfileFieldLength {
+			return nil, field.Name + " is too long"
+		}
+		switch field.Type {
+		case workspace.ProfileFieldSelect:
+			if !slices.Contains(field.Options, value) {
+				return nil, field.Name + " must be one of its options"
+			}
+		case workspace.ProfileFieldURL:
+			if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, field.Name + " must be an http or https URL"
+			}
+		}
+		values[id] = value
+	}
+	return values, ""
+}
+
+// ListProfileFields lists a workspace's custom profile fields
+func (h *Handler) ListProfileFields(ctx context.Context, request openapi.ListProfileFieldsRequestObject) (openapi.ListProfileFieldsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListProfileFields401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	schema, err := h.workspaceProfileSchema(ctx, userID, string(request.Wid))
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ListProfileFields403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	fields := make([]openapi.ProfileField, len(schema))
+	for i := range schema {
+		fields[i] = profileFieldToAPI(&schema[i])
+	}
+	return openapi.ListProfileFields200JSONResponse{Fields: fields}, nil
+}
+
+// CreateProfileField adds a custom profile field to a workspace
+func (h *Handler) CreateProfileField(ctx context.Context, request openapi.CreateProfileFieldRequestObject) (openapi.CreateProfileFieldResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateProfileField401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.CreateProfileField403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.CreateProfileField403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage profile fields")}, nil
+	}
+
+	field := &workspace.ProfileField{
+		WorkspaceID: workspaceID,
+		Name:        strings.TrimSpace(request.Body.Name),
+		Type:        workspace.ProfileFieldText,
+	}
+	if request.Body.Type != nil {
+		field.Type = string(*request.Body.Type)
+	}
+	if request.Body.Options != nil {
+		field.Options = *request.Body.Options
+	}
+	if msg := validateProfileField(field); msg != "" {
+		return openapi.CreateProfileField400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if err := h.workspaceRepo.CreateProfileField(ctx, field); err != nil {
+		if errors.Is(err, workspace.ErrProfileFieldExists) {
+			return openapi.CreateProfileField409JSONResponse{ConflictJSONResponse: conflictResponse("A profile field with this name already exists")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "profile_field.created", "profile_field", field.ID, map[string]interface{}{
+		"name": field.Name,
+		"type": field.Type,
+	})
+
+	return openapi.CreateProfileField200JSONResponse{Field: profileFieldToAPI(field)}, nil
+}
+
+// UpdateProfileField renames, reorders or changes the options of a custom profile field
+func (h *Handler) UpdateProfileField(ctx context.Context, request openapi.UpdateProfileFieldRequestObject) (openapi.UpdateProfileFieldResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateProfileField401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	field, err := h.workspaceRepo.GetProfileField(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, workspace.ErrProfileFieldNotFound) {
+			return openapi.UpdateProfileField404JSONResponse{NotFoundJSONResponse: notFoundResponse("Profile field not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, field.WorkspaceID)
+	if err != nil {
+		return openapi.UpdateProfileField404JSONResponse{NotFoundJSONResponse: notFoundResponse("Profile field not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.UpdateProfileField403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage profile fields")}, nil
+	}
+
+	if request.Body.Name != nil {
+		field.Name = strings.TrimSpace(*request.Body.Name)
+	}
+	if request.Body.Options != nil {
+		field.Options = *request.Body.Options
+	}
+	if request.Body.SortOrder != nil {
+		field.SortOrder = *request.Body.SortOrder
+	}
+	if msg := validateProfileField(field); msg != "" {
+		return openapi.UpdateProfileField400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if err := h.workspaceRepo.UpdateProfileField(ctx, field); err != nil {
+		if errors.Is(err, workspace.ErrProfileFieldExists) {
+			return openapi.UpdateProfileField409JSONResponse{ConflictJSONResponse: conflictResponse("A profile field with this name already exists")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, field.WorkspaceID, userID, "profile_field.updated", "profile_field", field.ID, map[string]interface{}{
+		"name": field.Name,
+	})
+
+	return openapi.UpdateProfileField200JSONResponse{Field: profileFieldToAPI(field)}, nil
+}
+
+// DeleteProfileField removes a custom profile field and all members' values for it
+func (h *Handler) DeleteProfileField(ctx context.Context, request openapi.DeleteProfileFieldRequestObject) (openapi.DeleteProfileFieldResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteProfileField401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	field, err := h.workspaceRepo.GetProfileField(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, workspace.ErrProfileFieldNotFound) {
+			return openapi.DeleteProfileField404JSONResponse{NotFoundJSONResponse: notFoundResponse("Profile field not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, field.WorkspaceID)
+	if err != nil {
+		return openapi.DeleteProfileField404JSONResponse{NotFoundJSONResponse: notFoundResponse("Profile field not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.DeleteProfileField403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage profile fields")}, nil
+	}
+
+	if err := h.workspaceRepo.DeleteProfileField(ctx, field); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, field.WorkspaceID, userID, "profile_field.deleted", "profile_field", field.ID, map[string]interface{}{
+		"name": field.Name,
+	})
+
+	return openapi.DeleteProfileField200JSONResponse{Success: true}, nil
+}
+
+// validateProfileField checks a field definition, returning a message for
+// the first problem found
+func validateProfileField(f *workspace.ProfileField) string {
+	if f.Name == "" {
+		return "Name is required"
+	}
+	if len(f.Name) > 64 {
+		return "Name must be 64 characters or fewer"
+	}
+	if !workspace.IsValidProfileFieldType(f.Type) {
+		return "Type must be text, url or select"
+	}
+	if f.Type != workspace.ProfileFieldSelect {
+		f.Options = nil
+		return ""
+	}
+
+	options := make([]string, 0, len(f.Options))
+	for _, o := range f.Options {
+		if o = strings.TrimSpace(o); o != "" && !slices.Contains(options, o) {
+			options = append(options, o)
+		}
+	}
+	if len(options) == 0 {
+		return "Select fields need at least one option"
+	}
+	if len(options) > maxProfileFieldOptions {
+		return "Select fields can have at most 50 options"
+	}
+	f.Options = options
+	return ""
+}
+
+func profileFieldToAPI(f *workspace.ProfileField) openapi.ProfileField {
+	field := openapi.ProfileField{
+		Id:          f.ID,
+		WorkspaceId: f.WorkspaceID,
+		Name:        f.Name,
+		Type:        openapi.ProfileFieldType(f.Type),
+		SortOrder:   f.SortOrder,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+	if len(f.Options) > 0 {
+		field.Options = &f.Options
+	}
+	return field
+}
+
+func myProfileToAPI(u *user.User) openapi.MyProfile {
+	return openapi.MyProfile{
+		Title:    u.Title,
+		Pronouns: u.Pronouns,
+		Timezone: u.Timezone,
+		Phone:    u.Phone,
+	}
+}
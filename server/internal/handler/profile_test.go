@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestUpdateMyProfile_BuiltInFields(t *testing.T) {
+	h, db := testHandler(t)
+	u := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ctx := ctxWithUser(t, h, u.ID)
+
+	title, pronouns, tz := "  Staff Engineer ", "they/them", "Europe/Berlin"
+	resp, err := h.UpdateMyProfile(ctx, openapi.UpdateMyProfileRequestObject{
+		Body: &openapi.UpdateMyProfileJSONRequestBody{Title: &title, Pronouns: &pronouns, Timezone: &tz},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMyProfile: %v", err)
+	}
+	updated, ok := resp.(openapi.UpdateMyProfile200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	if updated.Profile.Title == nil || *updated.Profile.Title != "Staff Engineer" {
+		t.Errorf("expected trimmed title, got %v", updated.Profile.Title)
+	}
+	if updated.Profile.Fields != nil {
+		t.Error("expected no custom fields without a workspace")
+	}
+
+	// Omitted fields are kept and empty strings clear
+	empty := ""
+	resp, err = h.UpdateMyProfile(ctx, openapi.UpdateMyProfileRequestObject{
+		Body: &openapi.UpdateMyProfileJSONRequestBody{Pronouns: &empty},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMyProfile: %v", err)
+	}
+	got, err := h.GetMyProfile(ctx, openapi.GetMyProfileRequestObject{})
+	if err != nil {
+		t.Fatalf("GetMyProfile: %v", err)
+	}
+	profile := got.(openapi.GetMyProfile200JSONResponse).Profile
+	if profile.Pronouns != nil || profile.Title == nil || profile.Timezone == nil || *profile.Timezone != tz {
+		t.Errorf("unexpected profile after partial update: %+v", profile)
+	}
+
+	bad := "Mars/Olympus_Mons"
+	resp, err = h.UpdateMyProfile(ctx, openapi.UpdateMyProfileRequestObject{
+		Body: &openapi.UpdateMyProfileJSONRequestBody{Timezone: &bad},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMyProfile: %v", err)
+	}
+	if _, ok := resp.(openapi.UpdateMyProfile400JSONResponse); !ok {
+		t.Fatalf("expected 400 for unknown timezone, got %T", resp)
+	}
+}
+
+func TestProfileFields_CustomValues(t *testing.T) {
+	h, db := testHandler(t)
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+
+	selectType := openapi.ProfileFieldTypeSelect
+	create := openapi.CreateProfileFieldRequestObject{
+		Wid:  openapi.WorkspaceId(ws.ID),
+		Body: &openapi.CreateProfileFieldJSONRequestBody{Name: "Team", Type: &selectType, Options: &[]string{"Design", " Engineering ", "Design"}},
+	}
+	resp, err := h.CreateProfileField(memberCtx, create)
+	if err != nil {
+		t.Fatalf("CreateProfileField: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateProfileField403JSONResponse); !ok {
+		t.Fatalf("expected 403 for member, got %T", resp)
+	}
+
+	resp, err = h.CreateProfileField(ownerCtx, create)
+	if err != nil {
+		t.Fatalf("CreateProfileField: %v", err)
+	}
+	created, ok := resp.(openapi.CreateProfileField200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	if created.Field.Options == nil || len(*created.Field.Options) != 2 {
+		t.Fatalf("expected trimmed, deduplicated options, got %v", created.Field.Options)
+	}
+	fieldID := created.Field.Id
+
+	wsID := ws.ID
+	set := func(value string) openapi.UpdateMyProfileResponseObject {
+		t.Helper()
+		resp, err := h.UpdateMyProfile(memberCtx, openapi.UpdateMyProfileRequestObject{
+			Body: &openapi.UpdateMyProfileJSONRequestBody{WorkspaceId: &wsID, CustomFields: &map[string]string{fieldID: value}},
+		})
+		if err != nil {
+			t.Fatalf("UpdateMyProfile: %v", err)
+		}
+		return resp
+	}
+	if _, ok := set("Marketing").(openapi.UpdateMyProfile400JSONResponse); !ok {
+		t.Fatal("expected 400 for a value outside the select options")
+	}
+	updated, ok := set("Engineering").(openapi.UpdateMyProfile200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 for a valid option")
+	}
+	if updated.Profile.CustomFields == nil || (*updated.Profile.CustomFields)[fieldID] != "Engineering" {
+		t.Errorf("expected custom value in response, got %v", updated.Profile.CustomFields)
+	}
+
+	// Values show up on the member listing
+	listResp, err := h.ListWorkspaceMembers(ownerCtx, openapi.ListWorkspaceMembersRequestObject{Wid: openapi.WorkspaceId(ws.ID)})
+	if err != nil {
+		t.Fatalf("ListWorkspaceMembers: %v", err)
+	}
+	var found bool
+	for _, m := range listResp.(openapi.ListWorkspaceMembers200JSONResponse).Body.Members {
+		if m.UserId == member.ID {
+			found = m.ProfileFields != nil && (*m.ProfileFields)[fieldID] == "Engineering"
+		}
+	}
+	if !found {
+		t.Error("expected member listing to include the custom value")
+	}
+
+	delResp, err := h.DeleteProfileField(ownerCtx, openapi.DeleteProfileFieldRequestObject{Id: fieldID})
+	if err != nil {
+		t.Fatalf("DeleteProfileField: %v", err)
+	}
+	if _, ok := delResp.(openapi.DeleteProfileField200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", delResp)
+	}
+	got, err := h.GetMyProfile(memberCtx, openapi.GetMyProfileRequestObject{Params: openapi.GetMyProfileParams{WorkspaceId: &wsID}})
+	if err != nil {
+		t.Fatalf("GetMyProfile: %v", err)
+	}
+	profile := got.(openapi.GetMyProfile200JSONResponse).Profile
+	if len(*profile.Fields) != 0 || len(*profile.CustomFields) != 0 {
+		t.Errorf("expected field and value to be gone, got %+v", profile)
+	}
+}
+
+func TestGetMemberProfile(t *testing.T) {
+	h, db := testHandler(t)
+
+	viewer := testutil.CreateTestUser(t, db, "viewer@test.com", "Viewer")
+	target := testutil.CreateTestUser(t, db, "target@test.com", "Target")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, viewer.ID, "WS")
+	addWorkspaceMember(t, db, target.ID, ws.ID, "admin")
+
+	general := testutil.CreateTestChannel(t, db, ws.ID, viewer.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, target.ID, general.ID, nil)
+	private := testutil.CreateTestChannel(t, db, ws.ID, target.ID, "private", channel.TypePrivate)
+	testutil.CreateTestMessage(t, db, general.ID, target.ID, "visible")
+	testutil.CreateTestMessage(t, db, private.ID, target.ID, "hidden")
+
+	lastSeen := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	if _, err := db.Exec(`
+		INSERT INTO user_presence (id, user_id, workspace_id, status, last_seen_at) VALUES ('p1', ?, ?, 'offline', ?)
+	`, target.ID, ws.ID, lastSeen.Format(time.RFC3339)); err != nil {
+		t.Fatalf("inserting presence: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE users SET timezone = 'Asia/Tokyo' WHERE id = ?`, target.ID); err != nil {
+		t.Fatalf("setting timezone: %v", err)
+	}
+
+	resp, err := h.GetMemberProfile(ctxWithUser(t, h, viewer.ID), openapi.GetMemberProfileRequestObject{
+		Wid:    openapi.WorkspaceId(ws.ID),
+		UserId: target.ID,
+	})
+	if err != nil {
+		t.Fatalf("GetMemberProfile: %v", err)
+	}
+	profile, ok := resp.(openapi.GetMemberProfile200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	if profile.User.Id != target.ID || profile.Role != openapi.WorkspaceRole("admin") {
+		t.Errorf("expected target as admin, got %s as %s", profile.User.Id, profile.Role)
+	}
+	if profile.LastSeenAt == nil || !profile.LastSeenAt.Equal(lastSeen) {
+		t.Errorf("expected last seen %v, got %v", lastSeen, profile.LastSeenAt)
+	}
+	if profile.LocalTime == nil {
+		t.Fatal("expected local time")
+	}
+	if _, offset := profile.LocalTime.Zone(); offset != 9*60*60 {
+		t.Errorf("expected Tokyo offset, got %d", offset)
+	}
+	// The private channel's message is not visible to the viewer
+	if profile.MessageCount != 1 {
+		t.Errorf("expected 1 visible message, got %d", profile.MessageCount)
+	}
+	if profile.SharedChannelCount != 1 || len(profile.SharedChannels) != 1 || profile.SharedChannels[0].Id != general.ID {
+		t.Errorf("expected general as the only shared channel, got %+v", profile.SharedChannels)
+	}
+
+	resp, err = h.GetMemberProfile(ctxWithUser(t, h, viewer.ID), openapi.GetMemberProfileRequestObject{
+		Wid:    openapi.WorkspaceId(ws.ID),
+		UserId: outsider.ID,
+	})
+	if err != nil {
+		t.Fatalf("GetMemberProfile: %v", err)
+	}
+	if _, ok := resp.(openapi.GetMemberProfile404JSONResponse); !ok {
+		t.Errorf("expected 404 for non-member, got %T", resp)
+	}
+
+	resp, err = h.GetMemberProfile(ctxWithUser(t, h, outsider.ID), openapi.GetMemberProfileRequestObject{
+		Wid:    openapi.WorkspaceId(ws.ID),
+		UserId: target.ID,
+	})
+	if err != nil {
+		t.Fatalf("GetMemberProfile: %v", err)
+	}
+	if _, ok := resp.(openapi.GetMemberProfile403JSONResponse); !ok {
+		t.Errorf("expected 403 for caller outside the workspace, got %T", resp)
+	}
+}
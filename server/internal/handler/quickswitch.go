@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/quickswitch"
+)
+
+// QuickSwitch searches channels, DMs and members in one call for the Cmd+K switcher
+func (h *Handler) QuickSwitch(ctx context.Context, request openapi.QuickSwitchRequestObject) (openapi.QuickSwitchResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.QuickSwitch401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid)); err != nil {
+		return openapi.QuickSwitch403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	var query string
+	if request.Params.Q != nil {
+		query = *request.Params.Q
+	}
+	limit := quickswitch.DefaultLimit
+	if request.Params.Limit != nil {
+		limit = *request.Params.Limit
+	}
+
+	results, err := h.quickSwitchRepo.Search(ctx, string(request.Wid), userID, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]openapi.QuickSwitchItem, len(results))
+	for i, r := range results {
+		items[i] = quickSwitchResultToAPI(r)
+	}
+	return openapi.QuickSwitch200JSONResponse{Results: items}, nil
+}
+
+func quickSwitchResultToAPI(r quickswitch.Result) openapi.QuickSwitchItem {
+	item := openapi.QuickSwitchItem{
+		Kind:           openapi.QuickSwitchKind(r.Kind),
+		Name:           r.Name,
+		IsMember:       r.IsMember,
+		LastActivityAt: r.LastActivityAt,
+	}
+	if r.ChannelID != "" {
+		item.ChannelId = &r.ChannelID
+	}
+	if r.ChannelType != "" {
+		channelType := openapi.ChannelType(r.ChannelType)
+		item.ChannelType = &channelType
+	}
+	if len(r.Participants) > 0 {
+		participants := make([]openapi.ChannelMember, len(r.Participants))
+		for i, p := range r.Participants {
+			participants[i] = channelMemberToAPI(p)
+		}
+		item.Participants = &participants
+	}
+	if r.User != nil {
+		user := channelMemberToAPI(*r.User)
+		item.User = &user
+	}
+	return item
+}
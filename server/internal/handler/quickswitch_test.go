@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestQuickSwitch(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	alex := testutil.CreateTestUser(t, db, "alex@test.com", "Alex")
+	outsider := testutil.CreateTestUser(t, db, "outsider@test.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test WS")
+	addWorkspaceMember(t, db, alex.ID, ws.ID, "member")
+	testutil.CreateTestChannel(t, db, ws.ID, user.ID, "alerts", channel.TypePublic)
+
+	q := "al"
+	req := openapi.QuickSwitchRequestObject{
+		Wid:    openapi.WorkspaceId(ws.ID),
+		Params: openapi.QuickSwitchParams{Q: &q},
+	}
+
+	resp, err := h.QuickSwitch(ctxWithUser(t, h, outsider.ID), req)
+	if err != nil {
+		t.Fatalf("QuickSwitch: %v", err)
+	}
+	if _, ok := resp.(openapi.QuickSwitch403JSONResponse); !ok {
+		t.Fatalf("expected 403 for non-member, got %T", resp)
+	}
+
+	resp, err = h.QuickSwitch(ctxWithUser(t, h, user.ID), req)
+	if err != nil {
+		t.Fatalf("QuickSwitch: %v", err)
+	}
+	r, ok := resp.(openapi.QuickSwitch200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	if len(r.Results) != 2 {
+		t.Fatalf("expected a channel and a member, got %+v", r.Results)
+	}
+	kinds := map[openapi.QuickSwitchKind]openapi.QuickSwitchItem{}
+	for _, item := range r.Results {
+		kinds[item.Kind] = item
+	}
+	if ch, ok := kinds[openapi.QuickSwitchKindChannel]; !ok || ch.Name != "alerts" || ch.ChannelId == nil {
+		t.Errorf("expected #alerts channel result, got %+v", ch)
+	}
+	if m, ok := kinds[openapi.QuickSwitchKindMember]; !ok || m.User == nil || m.User.UserId != alex.ID || m.ChannelId != nil {
+		t.Errorf("expected Alex as a member without a DM, got %+v", m)
+	}
+}
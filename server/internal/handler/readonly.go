@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+const (
+	defaultReadOnlyMessage   = "This workspace is read-only for maintenance. Try again later."
+	maxReadOnlyMessageLength = 500
+)
+
+// readOnlyNotice reports whether writes to the workspace are refused because
+// the server or the workspace is in read-only mode, and the message to show.
+func (h *Handler) readOnlyNotice(ctx context.Context, workspaceID string) (string, bool, error) {
+	if settings := h.currentSettings(); settings.ReadOnly {
+		if settings.ReadOnlyMessage != "" {
+			return settings.ReadOnlyMessage, true, nil
+		}
+		return defaultReadOnlyMessage, true, nil
+	}
+	ws, err := h.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return "", false, err
+	}
+	if !ws.ReadOnly {
+		return "", false, nil
+	}
+	if ws.ReadOnlyMessage != nil && *ws.ReadOnlyMessage != "" {
+		return *ws.ReadOnlyMessage, true, nil
+	}
+	return defaultReadOnlyMessage, true, nil
+}
+
+// SetWorkspaceReadOnly turns a workspace's read-only mode on or off
+func (h *Handler) SetWorkspaceReadOnly(ctx context.Context, request openapi.SetWorkspaceReadOnlyRequestObject) (openapi.SetWorkspaceReadOnlyResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SetWorkspaceReadOnly401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.SetWorkspaceReadOnly403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if membership.Role != workspace.RoleOwner {
+		return openapi.SetWorkspaceReadOnly403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only the workspace owner can change read-only mode")}, nil
+	}
+
+	var message *string
+	if request.Body.Message != nil {
+		if m := strings.TrimSpace(*request.Body.Message); m != "" {
+			message = &m
+		}
+	}
+	if message != nil && utf8.RuneCountInString(*message) > maxReadOnlyMessageLength {
+		return openapi.SetWorkspaceReadOnly400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Message must be at most %d characters", maxReadOnlyMessageLength))}, nil
+	}
+
+	if err := h.workspaceRepo.SetReadOnly(ctx, workspaceID, request.Body.ReadOnly, message); err != nil {
+		return nil, err
+	}
+	ws, err := h.workspaceRepo.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{"read_only": ws.ReadOnly}
+	if ws.ReadOnlyMessage != nil {
+		changes["read_only_message"] = *ws.ReadOnlyMessage
+	}
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, moderation.ActionWorkspaceUpdated, moderation.TargetTypeWorkspace, workspaceID, map[string]interface{}{
+		"changes": changes,
+	})
+
+	apiWs := workspaceToAPI(ws)
+	if h.hub != nil {
+		h.hub.BroadcastToWorkspace(ctx, workspaceID, sse.NewWorkspaceUpdatedEvent(apiWs))
+	}
+
+	return openapi.SetWorkspaceReadOnly200JSONResponse{Workspace: apiWs}, nil
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestSetWorkspaceReadOnly(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	admin := testutil.CreateTestUser(t, db, "admin@test.com", "Admin")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, admin.ID, ws.ID, "admin")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "before maintenance")
+
+	client := connectSSEClient(t, h, ws.ID, member.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+
+	setReadOnly := func(ctx context.Context, readOnly bool, message string) openapi.SetWorkspaceReadOnlyResponseObject {
+		t.Helper()
+		resp, err := h.SetWorkspaceReadOnly(ctx, openapi.SetWorkspaceReadOnlyRequestObject{
+			Wid:  ws.ID,
+			Body: &openapi.SetWorkspaceReadOnlyJSONRequestBody{ReadOnly: readOnly, Message: &message},
+		})
+		if err != nil {
+			t.Fatalf("SetWorkspaceReadOnly: %v", err)
+		}
+		return resp
+	}
+	send := func() openapi.SendMessageResponseObject {
+		t.Helper()
+		content := "hello"
+		resp, err := h.SendMessage(memberCtx, openapi.SendMessageRequestObject{
+			Id:   ch.ID,
+			Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+		})
+		if err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := setReadOnly(ctxWithUser(t, h, admin.ID), true, "").(openapi.SetWorkspaceReadOnly403JSONResponse); !ok {
+		t.Fatal("admins should not be able to change read-only mode")
+	}
+
+	resp, ok := setReadOnly(ctxWithUser(t, h, owner.ID), true, "  Moving servers  ").(openapi.SetWorkspaceReadOnly200JSONResponse)
+	if !ok {
+		t.Fatal("owner should be able to turn on read-only mode")
+	}
+	if !resp.Workspace.ReadOnly || resp.Workspace.ReadOnlyMessage == nil || *resp.Workspace.ReadOnlyMessage != "Moving servers" {
+		t.Errorf("workspace = %+v, want read-only with a trimmed message", resp.Workspace)
+	}
+	expectSSEEvent(t, client, sse.EventWorkspaceUpdated)
+
+	blocked, ok := send().(openapi.SendMessage503JSONResponse)
+	if !ok {
+		t.Fatal("sending should fail while read-only")
+	}
+	if blocked.Error.Code != ErrCodeReadOnly || blocked.Error.Message != "Moving servers" {
+		t.Errorf("error = %+v, want READ_ONLY with the banner message", blocked.Error)
+	}
+	reaction, err := h.AddReaction(memberCtx, openapi.AddReactionRequestObject{
+		Id:   msg.ID,
+		Body: &openapi.AddReactionJSONRequestBody{Emoji: "👍"},
+	})
+	if err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	if _, ok := reaction.(openapi.AddReaction503JSONResponse); !ok {
+		t.Errorf("reacting should fail while read-only, got %T", reaction)
+	}
+
+	// Reads keep working
+	list, err := h.ListMessages(memberCtx, openapi.ListMessagesRequestObject{Id: ch.ID, Body: &openapi.ListMessagesJSONRequestBody{}})
+	if err != nil {
+		t.Fatalf("ListMessages: %v", err)
+	}
+	if _, ok := list.(openapi.ListMessages200JSONResponse); !ok {
+		t.Errorf("reading should still work while read-only, got %T", list)
+	}
+
+	resp, ok = setReadOnly(ctxWithUser(t, h, owner.ID), false, "ignored").(openapi.SetWorkspaceReadOnly200JSONResponse)
+	if !ok || resp.Workspace.ReadOnly || resp.Workspace.ReadOnlyMessage != nil {
+		t.Fatalf("response = %+v, want read-only off and the message cleared", resp)
+	}
+	if _, ok := send().(openapi.SendMessage200JSONResponse); !ok {
+		t.Error("sending should work again once read-only mode is off")
+	}
+}
+
+func TestServerReadOnly(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	settings := h.currentSettings()
+	settings.ReadOnly = true
+	h.Configure(settings)
+
+	content := "hello"
+	resp, err := h.SendMessage(ctxWithUser(t, h, owner.ID), openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	blocked, ok := resp.(openapi.SendMessage503JSONResponse)
+	if !ok {
+		t.Fatalf("expected 503 while the server is read-only, got %T", resp)
+	}
+	if blocked.Error.Message != defaultReadOnlyMessage {
+		t.Errorf("message = %q, want the default message", blocked.Error.Message)
+	}
+
+	info, err := h.GetServerInfo(context.Background(), openapi.GetServerInfoRequestObject{})
+	if err != nil {
+		t.Fatalf("GetServerInfo: %v", err)
+	}
+	if r := info.(openapi.GetServerInfo200JSONResponse); r.ReadOnly == nil || !*r.ReadOnly {
+		t.Error("server info should report read-only mode")
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/retention"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// maxRetentionDays caps configurable retention periods at ten years.
+const maxRetentionDays = 3650
+
+// UpdateChannelRetention sets or clears a channel's message retention override
+func (h *Handler) UpdateChannelRetention(ctx context.Context, request openapi.UpdateChannelRetentionRequestObject) (openapi.UpdateChannelRetentionResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateChannelRetention401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.UpdateChannelRetention404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil || !workspace.CanManageMembers(membership.Role) {
+		return openapi.UpdateChannelRetention403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only workspace admins can change message retention")}, nil
+	}
+
+	days := request.Body.MessageRetentionDays
+	if days != nil && (*days < 0 || *days > maxRetentionDays) {
+		return openapi.UpdateChannelRetention400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "message_retention_days must be between 0 and 3650")}, nil
+	}
+
+	if err := h.channelRepo.SetMessageRetention(ctx, ch.ID, days); err != nil {
+		return nil, err
+	}
+	ch, err = h.channelRepo.GetByID(ctx, ch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiCh := channelToAPI(ch)
+
+	if h.hub != nil {
+		if ch.Type == channel.TypePublic {
+			h.hub.BroadcastToWorkspace(ctx, ch.WorkspaceID, sse.NewChannelUpdatedEvent(apiCh))
+		} else {
+			h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewChannelUpdatedEvent(apiCh))
+		}
+	}
+
+	// Audit log: retention changed (nil days means the workspace default)
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, ch.WorkspaceID, userID, "channel.retention_updated", "channel", ch.ID, map[string]interface{}{
+		"channel_name":           ch.Name,
+		"message_retention_days": days,
+	})
+
+	return openapi.UpdateChannelRetention200JSONResponse{Channel: apiCh}, nil
+}
+
+// PreviewChannelRetention reports what the purge job would delete from a channel
+func (h *Handler) PreviewChannelRetention(ctx context.Context, request openapi.PreviewChannelRetentionRequestObject) (openapi.PreviewChannelRetentionResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.PreviewChannelRetention401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.PreviewChannelRetention404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, ch.WorkspaceID)
+	if err != nil || !workspace.CanManageMembers(membership.Role) {
+		return openapi.PreviewChannelRetention403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only workspace admins can preview message retention")}, nil
+	}
+
+	// Default to the channel's effective retention
+	var days int
+	switch {
+	case request.Body != nil && request.Body.MessageRetentionDays != nil:
+		days = *request.Body.MessageRetentionDays
+		if days < 0 || days > maxRetentionDays {
+			return openapi.PreviewChannelRetention400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "message_retention_days must be between 0 and 3650")}, nil
+		}
+	case ch.MessageRetentionDays != nil:
+		days = *ch.MessageRetentionDays
+	default:
+		ws, err := h.workspaceRepo.GetByID(ctx, ch.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		days = ws.ParsedSettings().MessageRetentionDays
+	}
+
+	resp := openapi.PreviewChannelRetention200JSONResponse{MessageRetentionDays: days}
+	if days == 0 {
+		return resp, nil
+	}
+
+	policy := retention.Policy{ChannelID: ch.ID, WorkspaceID: ch.WorkspaceID, Days: days}
+	cutoff := policy.Cutoff(time.Now())
+	preview, err := h.retentionRepo.Preview(ctx, ch.ID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	resp.Before = &cutoff
+	resp.MessageCount = preview.Messages
+	resp.AttachmentCount = preview.Attachments
+	return resp, nil
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestUpdateChannelRetention(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+
+	days := 90
+	req := openapi.UpdateChannelRetentionRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.UpdateChannelRetentionJSONRequestBody{MessageRetentionDays: &days},
+	}
+
+	resp, err := h.UpdateChannelRetention(ctxWithUser(t, h, member.ID), req)
+	if err != nil {
+		t.Fatalf("UpdateChannelRetention: %v", err)
+	}
+	if _, ok := resp.(openapi.UpdateChannelRetention403JSONResponse); !ok {
+		t.Fatalf("expected 403 for member, got %T", resp)
+	}
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	resp, err = h.UpdateChannelRetention(ownerCtx, req)
+	if err != nil {
+		t.Fatalf("UpdateChannelRetention: %v", err)
+	}
+	updated, ok := resp.(openapi.UpdateChannelRetention200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if updated.Channel.MessageRetentionDays == nil || *updated.Channel.MessageRetentionDays != 90 {
+		t.Errorf("expected retention of 90 days, got %v", updated.Channel.MessageRetentionDays)
+	}
+
+	tooLong := 5000
+	resp, err = h.UpdateChannelRetention(ownerCtx, openapi.UpdateChannelRetentionRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.UpdateChannelRetentionJSONRequestBody{MessageRetentionDays: &tooLong},
+	})
+	if err != nil {
+		t.Fatalf("UpdateChannelRetention: %v", err)
+	}
+	if _, ok := resp.(openapi.UpdateChannelRetention400JSONResponse); !ok {
+		t.Fatalf("expected 400 for out of range retention, got %T", resp)
+	}
+
+	// Clearing the override falls back to the workspace default
+	resp, err = h.UpdateChannelRetention(ownerCtx, openapi.UpdateChannelRetentionRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.UpdateChannelRetentionJSONRequestBody{},
+	})
+	if err != nil {
+		t.Fatalf("UpdateChannelRetention: %v", err)
+	}
+	if cleared := resp.(openapi.UpdateChannelRetention200JSONResponse); cleared.Channel.MessageRetentionDays != nil {
+		t.Errorf("expected override to be cleared, got %d", *cleared.Channel.MessageRetentionDays)
+	}
+}
+
+func TestPreviewChannelRetention(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "old news")
+	testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "fresh")
+	if _, err := db.Exec(`UPDATE messages SET created_at = '2020-01-01T00:00:00Z' WHERE id = ?`, msg.ID); err != nil {
+		t.Fatalf("backdating message: %v", err)
+	}
+	ctx := ctxWithUser(t, h, owner.ID)
+
+	// No policy anywhere: nothing would be deleted
+	resp, err := h.PreviewChannelRetention(ctx, openapi.PreviewChannelRetentionRequestObject{Id: openapi.ChannelId(ch.ID)})
+	if err != nil {
+		t.Fatalf("PreviewChannelRetention: %v", err)
+	}
+	preview, ok := resp.(openapi.PreviewChannelRetention200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if preview.MessageRetentionDays != 0 || preview.Before != nil || preview.MessageCount != 0 {
+		t.Errorf("expected empty preview without a policy, got %+v", preview)
+	}
+
+	// The workspace default applies when the channel has no override
+	if _, err := db.Exec(`UPDATE workspaces SET settings = '{"message_retention_days":365}' WHERE id = ?`, ws.ID); err != nil {
+		t.Fatalf("setting workspace retention: %v", err)
+	}
+	resp, err = h.PreviewChannelRetention(ctx, openapi.PreviewChannelRetentionRequestObject{Id: openapi.ChannelId(ch.ID)})
+	if err != nil {
+		t.Fatalf("PreviewChannelRetention: %v", err)
+	}
+	preview = resp.(openapi.PreviewChannelRetention200JSONResponse)
+	if preview.MessageRetentionDays != 365 || preview.Before == nil || preview.MessageCount != 1 {
+		t.Errorf("expected one expired message under the workspace default, got %+v", preview)
+	}
+
+	// A proposed period is previewed without being saved
+	days := 0
+	resp, err = h.PreviewChannelRetention(ctx, openapi.PreviewChannelRetentionRequestObject{
+		Id:   openapi.ChannelId(ch.ID),
+		Body: &openapi.PreviewChannelRetentionJSONRequestBody{MessageRetentionDays: &days},
+	})
+	if err != nil {
+		t.Fatalf("PreviewChannelRetention: %v", err)
+	}
+	if preview = resp.(openapi.PreviewChannelRetention200JSONResponse); preview.MessageCount != 0 {
+		t.Errorf("expected nothing to be deleted when keeping forever, got %+v", preview)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages WHERE id = ?`, msg.ID).Scan(&n); err != nil || n != 1 {
+		t.Errorf("expected preview not to delete anything, got %d (err %v)", n, err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/file"
 	"github.com/enzyme/server/internal/linkpreview"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/notification"
@@ -74,20 +75,30 @@ func (h *Handler) ScheduleMessage(ctx context.Context, request openapi.ScheduleM
 	}
 
 	// Check channel membership
-	_, err = h.channelRepo.GetMembership(ctx, userID, string(request.Id))
+	membership, err := h.channelRepo.GetMembership(ctx, userID, string(request.Id))
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			return openapi.ScheduleMessage403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this channel")}, nil
 		}
 		return nil, err
 	}
+	if ok, err := h.canPostIn(ctx, ch, userID, membership.ChannelRole, request.Body.ThreadParentId != nil); err != nil {
+		return nil, err
+	} else if !ok {
+		return openapi.ScheduleMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+	}
 
 	content := strings.TrimSpace(request.Body.Content)
 	if content == "" {
 		return openapi.ScheduleMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Message content is required")}, nil
 	}
-	if utf8.RuneCountInString(content) > maxMessageLength {
-		return openapi.ScheduleMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Message content exceeds maximum length of %d characters", maxMessageLength))}, nil
+	if utf8.RuneCountInString(content) > h.maxMessageLength {
+		return openapi.ScheduleMessage400JSONResponse{BadRequestJSONResponse: messageTooLongResponse("Message content", h.maxMessageLength)}, nil
+	}
+	if denied, err := h.checkChannelMentions(ctx, ch, userID, content); err != nil {
+		return nil, err
+	} else if denied {
+		return openapi.ScheduleMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse(errChannelMentionDenied)}, nil
 	}
 
 	// Validate scheduled_for is in the future
@@ -113,6 +124,9 @@ func (h *Handler) ScheduleMessage(ctx context.Context, request openapi.ScheduleM
 			if att.MessageID != nil {
 				return openapi.ScheduleMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Attachment %s is already linked to a message", aid))}, nil
 			}
+			if att.Status == file.StatusQuarantined {
+				return openapi.ScheduleMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeFileQuarantined, fmt.Sprintf("Attachment %s failed the virus scan", aid))}, nil
+			}
 		}
 	}
 
@@ -138,7 +152,7 @@ func (h *Handler) ScheduleMessage(ctx context.Context, request openapi.ScheduleM
 
 	// Broadcast to the user
 	if h.hub != nil {
-		h.hub.BroadcastToUser(ch.WorkspaceID, userID, sse.NewScheduledMessageCreatedEvent(apiMsg))
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewScheduledMessageCreatedEvent(apiMsg))
 	}
 
 	return openapi.ScheduleMessage200JSONResponse{
@@ -222,8 +236,17 @@ func (h *Handler) UpdateScheduledMessage(ctx context.Context, request openapi.Up
 		if content == "" {
 			return openapi.UpdateScheduledMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Message content is required")}, nil
 		}
-		if utf8.RuneCountInString(content) > maxMessageLength {
-			return openapi.UpdateScheduledMessage400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Message content exceeds maximum length of %d characters", maxMessageLength))}, nil
+		if utf8.RuneCountInString(content) > h.maxMessageLength {
+			return openapi.UpdateScheduledMessage400JSONResponse{BadRequestJSONResponse: messageTooLongResponse("Message content", h.maxMessageLength)}, nil
+		}
+		ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if denied, err := h.checkChannelMentions(ctx, ch, userID, content); err != nil {
+			return nil, err
+		} else if denied {
+			return openapi.UpdateScheduledMessage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse(errChannelMentionDenied)}, nil
 		}
 		msg.Content = content
 	}
@@ -249,7 +272,7 @@ func (h *Handler) UpdateScheduledMessage(ctx context.Context, request openapi.Up
 	// Get workspace ID for broadcasting
 	if h.hub != nil {
 		if ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID); err == nil {
-			h.hub.BroadcastToUser(ch.WorkspaceID, userID, sse.NewScheduledMessageUpdatedEvent(apiMsg))
+			h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewScheduledMessageUpdatedEvent(apiMsg))
 		}
 	}
 
@@ -288,7 +311,7 @@ func (h *Handler) DeleteScheduledMessage(ctx context.Context, request openapi.De
 	// Broadcast deletion
 	if h.hub != nil {
 		if ch, err := h.channelRepo.GetByID(ctx, msg.ChannelID); err == nil {
-			h.hub.BroadcastToUser(ch.WorkspaceID, userID, sse.NewScheduledMessageDeletedEvent(openapi.ScheduledMessageDeletedData{Id: msg.ID}))
+			h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewScheduledMessageDeletedEvent(openapi.ScheduledMessageDeletedData{Id: msg.ID}))
 		}
 	}
 
@@ -358,7 +381,7 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 	}
 
 	// Check user is still a channel member
-	_, err = h.channelRepo.GetMembership(ctx, smsg.UserID, smsg.ChannelID)
+	membership, err := h.channelRepo.GetMembership(ctx, smsg.UserID, smsg.ChannelID)
 	if err != nil {
 		if errors.Is(err, channel.ErrNotChannelMember) {
 			return nil, &scheduled.PermanentError{Err: fmt.Errorf("user is no longer a channel member")}
@@ -366,11 +389,26 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 		return nil, fmt.Errorf("checking channel membership: %w", err)
 	}
 
+	// The channel's post policy may have been tightened since scheduling
+	if ok, err := h.canPostIn(ctx, ch, smsg.UserID, membership.ChannelRole, smsg.ThreadParentID != nil); err != nil {
+		return nil, fmt.Errorf("checking post permission: %w", err)
+	} else if !ok {
+		return nil, &scheduled.PermanentError{Err: errors.New("user may no longer post in this channel")}
+	}
+
+	// The mention permission may have been tightened since scheduling
+	if denied, err := h.checkChannelMentions(ctx, ch, smsg.UserID, smsg.Content); err != nil {
+		return nil, fmt.Errorf("checking mention permission: %w", err)
+	} else if denied {
+		return nil, &scheduled.PermanentError{Err: errors.New("user may no longer use @channel, @here or @everyone in this channel")}
+	}
+
 	// Parse mentions from content
 	var mentions []string
 	var originalMentions []string
 	if h.notificationService != nil && smsg.Content != "" {
-		mentions, _ = notification.ParseMentions(ctx, h.userRepo, ch.WorkspaceID, smsg.Content)
+		mentions, _ = notification.ParseMentions(ctx, h.mentionResolver(), ch.WorkspaceID, smsg.Content)
+		mentions = h.addKeywordMentions(ctx, ch.ID, smsg.UserID, smsg.Content, mentions)
 		originalMentions = mentions
 
 		if h.hub != nil && slices.Contains(mentions, notification.MentionHere) {
@@ -400,9 +438,11 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 	}
 
 	// Handle thread subscription auto-subscribe
+	var threadParent *message.Message
 	if smsg.ThreadParentID != nil && h.threadRepo != nil {
-		threadParent, tpErr := h.messageRepo.GetByID(ctx, *smsg.ThreadParentID)
+		tp, tpErr := h.messageRepo.GetByID(ctx, *smsg.ThreadParentID)
 		if tpErr == nil {
+			threadParent = tp
 			_ = h.threadRepo.AutoSubscribe(ctx, threadParent.ID, smsg.UserID)
 			if threadParent.ReplyCount == 0 && threadParent.UserID != nil && *threadParent.UserID != smsg.UserID {
 				_ = h.threadRepo.AutoSubscribe(ctx, threadParent.ID, *threadParent.UserID)
@@ -410,6 +450,8 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 		}
 	}
 
+	h.recordMessageActivity(ctx, ch, msg, threadParent, originalMentions)
+
 	// Link attachments
 	for _, attachmentID := range smsg.AttachmentIDs {
 		if err := h.fileRepo.UpdateMessageID(ctx, attachmentID, msg.ID); err != nil {
@@ -443,7 +485,7 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 
 	// Broadcast the new message
 	if h.hub != nil {
-		h.hub.BroadcastToChannel(ch.WorkspaceID, smsg.ChannelID, sse.NewMessageNewEvent(apiMsg))
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, smsg.ChannelID, sse.NewMessageNewEvent(apiMsg))
 	}
 
 	// Trigger notifications
@@ -468,9 +510,7 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 			Mentions:       originalMentions,
 			ThreadParentID: msg.ThreadParentID,
 		}
-		go func() {
-			_ = h.notificationService.Notify(context.Background(), channelInfo, msgInfo)
-		}()
+		h.notificationService.Enqueue(ctx, channelInfo, msgInfo)
 	}
 
 	// Delete the scheduled message
@@ -480,7 +520,7 @@ func (h *Handler) executeScheduledSend(ctx context.Context, smsg *scheduled.Sche
 
 	// Broadcast scheduled_message.sent event to the user
 	if h.hub != nil {
-		h.hub.BroadcastToUser(ch.WorkspaceID, smsg.UserID, sse.NewScheduledMessageSentEvent(openapi.ScheduledMessageSentData{
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, smsg.UserID, sse.NewScheduledMessageSentEvent(openapi.ScheduledMessageSentData{
 			Id:        smsg.ID,
 			ChannelId: smsg.ChannelID,
 			MessageId: msg.ID,
@@ -505,7 +545,7 @@ func (h *Handler) NotifyScheduledMessageFailed(ctx context.Context, smsg *schedu
 	if err != nil {
 		return
 	}
-	h.hub.BroadcastToUser(ch.WorkspaceID, smsg.UserID, sse.NewScheduledMessageFailedEvent(openapi.ScheduledMessageFailedData{
+	h.hub.BroadcastToUser(ctx, ch.WorkspaceID, smsg.UserID, sse.NewScheduledMessageFailedEvent(openapi.ScheduledMessageFailedData{
 		Id:        smsg.ID,
 		ChannelId: smsg.ChannelID,
 		Error:     reason,
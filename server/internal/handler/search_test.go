@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/testutil"
 )
@@ -36,8 +37,10 @@ func TestSearchMessages_Success(t *testing.T) {
 	if len(r.Messages) != 1 {
 		t.Fatalf("expected 1 message, got %d", len(r.Messages))
 	}
-	if r.TotalCount != 1 {
-		t.Errorf("total_count = %d, want 1", r.TotalCount)
+	if r.TotalCount == nil {
+		t.Error("total_count missing")
+	} else if *r.TotalCount != 1 {
+		t.Errorf("total_count = %d, want 1", *r.TotalCount)
 	}
 	if r.Query != query {
 		t.Errorf("query = %q, want %q", r.Query, query)
@@ -132,6 +135,40 @@ func TestSearchMessages_PrivateChannelAccessControl(t *testing.T) {
 	}
 }
 
+func TestSearchMessages_PrivateChannelHistoryVisibility(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	newcomer := testutil.CreateTestUser(t, db, "new@test.com", "Newcomer")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	addWorkspaceMember(t, db, newcomer.ID, ws.ID, "member")
+
+	privCh := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", channel.TypePrivate)
+	if _, err := db.Exec(`UPDATE channels SET history_visibility = ? WHERE id = ?`, channel.HistoryVisibilityNone, privCh.ID); err != nil {
+		t.Fatalf("setting history visibility: %v", err)
+	}
+	old := testutil.CreateTestMessage(t, db, privCh.ID, owner.ID, "roadmap draft")
+	backdateMessage(t, db, old.ID, time.Hour)
+	addChannelMember(t, db, newcomer.ID, privCh.ID, nil)
+	testutil.CreateTestMessage(t, db, privCh.ID, owner.ID, "roadmap final")
+
+	ctx := ctxWithUser(t, h, newcomer.ID)
+	resp, err := h.SearchMessages(ctx, openapi.SearchMessagesRequestObject{
+		Wid:  openapi.WorkspaceId(ws.ID),
+		Body: &openapi.SearchMessagesJSONRequestBody{Query: "roadmap"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.SearchMessages200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	if len(r.Messages) != 1 || r.Messages[0].Content != "roadmap final" {
+		t.Fatalf("expected only the message posted after joining, got %d messages", len(r.Messages))
+	}
+}
+
 func TestSearchMessages_PublicChannelAccess(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -329,8 +366,10 @@ func TestSearchMessages_Pagination(t *testing.T) {
 	if len(r.Messages) != 2 {
 		t.Fatalf("expected 2 messages (limit), got %d", len(r.Messages))
 	}
-	if r.TotalCount != 5 {
-		t.Errorf("total_count = %d, want 5", r.TotalCount)
+	if r.TotalCount == nil {
+		t.Error("total_count missing")
+	} else if *r.TotalCount != 5 {
+		t.Errorf("total_count = %d, want 5", *r.TotalCount)
 	}
 	if !r.HasMore {
 		t.Error("expected has_more = true")
@@ -361,6 +400,92 @@ func TestSearchMessages_Pagination(t *testing.T) {
 	}
 }
 
+func TestSearchMessages_CursorPagination(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+	for _, content := range []string{"deploy", "deploy deploy", "deploy again", "deploy deploy deploy", "the deploy"} {
+		testutil.CreateTestMessage(t, db, ch.ID, user.ID, content)
+	}
+	ctx := ctxWithUser(t, h, user.ID)
+
+	// Each pass adds a message, so the second counts six
+	for i, query := range []string{"deploy", "in:#general"} {
+		want := 5 + i
+		seen := map[string]bool{}
+		var cursor *string
+		limit := 2
+		for page := 0; ; page++ {
+			resp, err := h.SearchMessages(ctx, openapi.SearchMessagesRequestObject{
+				Wid:  openapi.WorkspaceId(ws.ID),
+				Body: &openapi.SearchMessagesJSONRequestBody{Query: query, Limit: &limit, Cursor: cursor},
+			})
+			if err != nil {
+				t.Fatalf("%s: SearchMessages: %v", query, err)
+			}
+			r, ok := resp.(openapi.SearchMessages200JSONResponse)
+			if !ok {
+				t.Fatalf("%s: expected 200, got %T", query, resp)
+			}
+			if page == 0 && r.TotalCount == nil {
+				t.Errorf("%s: first page total_count missing", query)
+			} else if page == 0 && *r.TotalCount != want {
+				t.Errorf("%s: first page total_count = %d, want %d", query, *r.TotalCount, want)
+			}
+			if page > 0 && r.TotalCount != nil {
+				t.Errorf("%s: page %d total_count = %d, want it left out", query, page, *r.TotalCount)
+			}
+			for _, m := range r.Messages {
+				if seen[m.Id] {
+					t.Errorf("%s: message %s returned twice", query, m.Id)
+				}
+				seen[m.Id] = true
+			}
+			if !r.HasMore {
+				break
+			}
+			if r.NextCursor == nil || page > 3 {
+				t.Fatalf("%s: has_more without a usable next_cursor", query)
+			}
+			cursor = r.NextCursor
+			// A message arriving mid-way doesn't shift later pages
+			if page == 0 {
+				testutil.CreateTestMessage(t, db, ch.ID, user.ID, "deploy late")
+			}
+		}
+		if len(seen) < want {
+			t.Errorf("%s: paged through %d messages, want at least %d", query, len(seen), want)
+		}
+	}
+
+	bad := "not-a-cursor"
+	resp, err := h.SearchMessages(ctx, openapi.SearchMessagesRequestObject{
+		Wid:  openapi.WorkspaceId(ws.ID),
+		Body: &openapi.SearchMessagesJSONRequestBody{Query: "deploy", Cursor: &bad},
+	})
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	if _, ok := resp.(openapi.SearchMessages400JSONResponse); !ok {
+		t.Errorf("expected 400 for a malformed cursor, got %T", resp)
+	}
+
+	h.Configure(Settings{SearchOffset: false})
+	offset := 2
+	resp, err = h.SearchMessages(ctx, openapi.SearchMessagesRequestObject{
+		Wid:  openapi.WorkspaceId(ws.ID),
+		Body: &openapi.SearchMessagesJSONRequestBody{Query: "deploy", Offset: &offset},
+	})
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	if _, ok := resp.(openapi.SearchMessages400JSONResponse); !ok {
+		t.Errorf("expected 400 for offset with offset paging off, got %T", resp)
+	}
+}
+
 func TestSearchMessages_SpecialCharsDontCrash(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -394,3 +519,101 @@ func TestSearchMessages_SpecialCharsDontCrash(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchMessages_QueryOperators(t *testing.T) {
+	h, db := testHandler(t)
+
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	addWorkspaceMember(t, db, bob.ID, ws.ID, "member")
+	general := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", channel.TypePublic)
+	ops := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "ops", channel.TypePublic)
+	addChannelMember(t, db, bob.ID, general.ID, nil)
+
+	withLink := testutil.CreateTestMessage(t, db, general.ID, alice.ID, "release notes at https://example.com")
+	testutil.CreateTestMessage(t, db, general.ID, bob.ID, "release party tonight")
+	inOps := testutil.CreateTestMessage(t, db, ops.ID, alice.ID, "release checklist")
+	attachmentID := createFileAttachment(t, db, ops.ID, alice.ID)
+	if _, err := db.Exec(`UPDATE attachments SET message_id = ? WHERE id = ?`, inOps.ID, attachmentID); err != nil {
+		t.Fatalf("linking attachment: %v", err)
+	}
+	old := testutil.CreateTestMessage(t, db, general.ID, bob.ID, "release retrospective")
+	if _, err := db.Exec(`UPDATE messages SET created_at = '2020-06-15T12:00:00Z' WHERE id = ?`, old.ID); err != nil {
+		t.Fatalf("backdating message: %v", err)
+	}
+
+	ctx := ctxWithUser(t, h, alice.ID)
+	search := func(query string) []openapi.SearchMessage {
+		t.Helper()
+		resp, err := h.SearchMessages(ctx, openapi.SearchMessagesRequestObject{
+			Wid:  openapi.WorkspaceId(ws.ID),
+			Body: &openapi.SearchMessagesJSONRequestBody{Query: query},
+		})
+		if err != nil {
+			t.Fatalf("query %q: unexpected error: %v", query, err)
+		}
+		r, ok := resp.(openapi.SearchMessages200JSONResponse)
+		if !ok {
+			t.Fatalf("query %q: expected 200, got %T", query, resp)
+		}
+		return r.Messages
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"release from:@alice", 2},
+		{"release from:@bob", 2},
+		{"release from:me", 2},
+		{"release in:#ops", 1},
+		{"release in:#general from:@Bob", 2},
+		{"has:link", 1},
+		{"release has:attachment", 1},
+		{"release before:2021-01-01", 1},
+		{"release after:2020-06-15", 3},
+		{`"release party"`, 1},
+		{`"party release"`, 0},
+	}
+	for _, tt := range tests {
+		if got := search(tt.query); len(got) != tt.want {
+			t.Errorf("query %q: got %d messages, want %d", tt.query, len(got), tt.want)
+		}
+	}
+
+	if got := search("has:link"); len(got) == 1 && got[0].Id != withLink.ID {
+		t.Errorf("has:link returned %s, want %s", got[0].Id, withLink.ID)
+	}
+}
+
+func TestSearchMessages_IsThread(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", channel.TypePublic)
+
+	parent := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "budget question")
+	testutil.CreateTestMessage(t, db, ch.ID, user.ID, "budget approved")
+	reply := &message.Message{ChannelID: ch.ID, UserID: &user.ID, Content: "budget answer", ThreadParentID: &parent.ID}
+	if err := message.NewRepository(db).Create(context.Background(), reply); err != nil {
+		t.Fatalf("creating reply: %v", err)
+	}
+
+	ctx := ctxWithUser(t, h, user.ID)
+	resp, err := h.SearchMessages(ctx, openapi.SearchMessagesRequestObject{
+		Wid:  openapi.WorkspaceId(ws.ID),
+		Body: &openapi.SearchMessagesJSONRequestBody{Query: "budget is:thread"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.SearchMessages200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200, got %T", resp)
+	}
+	if len(r.Messages) != 2 {
+		t.Fatalf("expected thread parent and reply, got %d messages", len(r.Messages))
+	}
+}
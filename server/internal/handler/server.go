@@ -4,15 +4,45 @@ import (
 	"context"
 
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/storage"
 	"github.com/enzyme/server/internal/version"
 )
 
+// GetServerInfo describes the server's version, features and limits so
+// clients can decide how to behave before the user logs in
 func (h *Handler) GetServerInfo(_ context.Context, _ openapi.GetServerInfoRequestObject) (openapi.GetServerInfoResponseObject, error) {
 	emailEnabled := h.emailService.IsEnabled()
 	filesEnabled := h.storage != nil
-	return openapi.GetServerInfo200JSONResponse{
-		Version:      version.Version,
-		EmailEnabled: &emailEnabled,
-		FilesEnabled: &filesEnabled,
-	}, nil
+	_, s3Storage := h.storage.(*storage.S3)
+	settings := h.currentSettings()
+	readOnly := settings.ReadOnly
+	resp := openapi.GetServerInfo200JSONResponse{
+		Version:          version.Version,
+		MinClientVersion: version.MinClientVersion,
+		EmailEnabled:     &emailEnabled,
+		FilesEnabled:     &filesEnabled,
+		ReadOnly:         &readOnly,
+		Features: openapi.ServerFeatures{
+			PushNotifications: h.pushTokenRepo != nil,
+			Webhooks:          h.webhookRepo != nil,
+			S3Storage:         s3Storage,
+			Sso:               false,
+		},
+		Limits: openapi.ServerLimits{
+			MaxMessageLength: h.maxMessageLength,
+		},
+		Endpoints: openapi.ServerEndpoints{
+			WorkspaceEvents: "/api/workspaces/{wid}/events",
+			UserEvents:      "/api/events",
+		},
+	}
+	if filesEnabled {
+		maxUploadSize := h.maxUploadSize
+		resp.Limits.MaxUploadSize = &maxUploadSize
+	}
+	if readOnly && settings.ReadOnlyMessage != "" {
+		message := settings.ReadOnlyMessage
+		resp.ReadOnlyMessage = &message
+	}
+	return resp, nil
 }
@@ -34,6 +34,19 @@ func TestGetServerInfo(t *testing.T) {
 	if jsonResp.FilesEnabled == nil || *jsonResp.FilesEnabled != true {
 		t.Error("expected files_enabled to be true")
 	}
+
+	if jsonResp.MinClientVersion != version.MinClientVersion {
+		t.Errorf("expected min_client_version %q, got %q", version.MinClientVersion, jsonResp.MinClientVersion)
+	}
+	if jsonResp.Features.PushNotifications || jsonResp.Features.S3Storage || jsonResp.Features.Sso {
+		t.Errorf("expected push, S3 and SSO to be off, got %+v", jsonResp.Features)
+	}
+	if jsonResp.Limits.MaxMessageLength != h.maxMessageLength || jsonResp.Limits.MaxUploadSize == nil {
+		t.Errorf("expected message and upload limits, got %+v", jsonResp.Limits)
+	}
+	if jsonResp.Endpoints.WorkspaceEvents != "/api/workspaces/{wid}/events" || jsonResp.Endpoints.UserEvents != "/api/events" {
+		t.Errorf("unexpected endpoints %+v", jsonResp.Endpoints)
+	}
 }
 
 func TestGetServerInfo_EmailEnabled(t *testing.T) {
@@ -62,4 +75,7 @@ func TestGetServerInfo_FilesDisabled(t *testing.T) {
 	if jsonResp.FilesEnabled == nil || *jsonResp.FilesEnabled != false {
 		t.Error("expected files_enabled to be false")
 	}
+	if jsonResp.Limits.MaxUploadSize != nil {
+		t.Error("expected no upload limit while files are disabled")
+	}
 }
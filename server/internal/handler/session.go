@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// maxUserAgentLength caps the stored User-Agent header
+const maxUserAgentLength = 512
+
+// clientInfo captures the device details recorded with a new session
+func clientInfo(ctx context.Context) auth.ClientInfo {
+	r := GetRequest(ctx)
+	if r == nil {
+		return auth.ClientInfo{}
+	}
+	ua := r.UserAgent()
+	if len(ua) > maxUserAgentLength {
+		ua = ua[:maxUserAgentLength]
+	}
+	// RemoteAddr has already been rewritten by the RealIP middleware
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return auth.ClientInfo{UserAgent: ua, IPAddress: ip}
+}
+
+// ListSessions lists the current user's active sessions
+func (h *Handler) ListSessions(ctx context.Context, request openapi.ListSessionsRequestObject) (openapi.ListSessionsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListSessions401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	sessions, err := h.sessionStore.ListForUser(userID, auth.GetToken(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	apiSessions := make([]openapi.Session, len(sessions))
+	for i, s := range sessions {
+		apiSessions[i] = openapi.Session{
+			Id:         s.ID,
+			Device:     auth.DescribeUserAgent(s.UserAgent),
+			UserAgent:  s.UserAgent,
+			IpAddress:  s.IPAddress,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.Current,
+		}
+	}
+	return openapi.ListSessions200JSONResponse{Sessions: apiSessions}, nil
+}
+
+// RevokeSession signs out one of the current user's sessions
+func (h *Handler) RevokeSession(ctx context.Context, request openapi.RevokeSessionRequestObject) (openapi.RevokeSessionResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RevokeSession401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if err := h.sessionStore.DeleteByID(userID, request.Id); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			return openapi.RevokeSession404JSONResponse{NotFoundJSONResponse: notFoundResponse("Session not found")}, nil
+		}
+		return nil, err
+	}
+	return openapi.RevokeSession200JSONResponse{Success: true}, nil
+}
+
+// RevokeOtherSessions signs out every session of the current user except this one
+func (h *Handler) RevokeOtherSessions(ctx context.Context, request openapi.RevokeOtherSessionsRequestObject) (openapi.RevokeOtherSessionsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RevokeOtherSessions401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	revoked, err := h.sessionStore.DeleteOthers(userID, auth.GetToken(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return openapi.RevokeOtherSessions200JSONResponse{Revoked: revoked}, nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestSessions_LoginRecordsDeviceAndRevoke(t *testing.T) {
+	h, db := testHandler(t)
+	u := testutil.CreateTestUser(t, db, "user@test.com", "User")
+
+	// Log in from a phone, as the router would hand the request to the handler
+	r := httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+	r.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) Safari/604.1")
+	r.RemoteAddr = "198.51.100.4:52814"
+	loginResp, err := h.Login(WithRequest(context.Background(), r), openapi.LoginRequestObject{
+		Body: &openapi.LoginJSONRequestBody{Email: "user@test.com", Password: "password123"},
+	})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	phoneToken := loginResp.(openapi.Login200JSONResponse).Token
+
+	ctx := ctxWithUser(t, h, u.ID)
+	resp, err := h.ListSessions(ctx, openapi.ListSessionsRequestObject{})
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	sessions := resp.(openapi.ListSessions200JSONResponse).Sessions
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	var phone openapi.Session
+	for _, s := range sessions {
+		if !s.Current {
+			phone = s
+		}
+	}
+	if phone.Device != "Safari on iOS" || phone.IpAddress != "198.51.100.4" {
+		t.Errorf("expected phone session details, got %+v", phone)
+	}
+
+	revokeResp, err := h.RevokeSession(ctx, openapi.RevokeSessionRequestObject{Id: phone.Id})
+	if err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if _, ok := revokeResp.(openapi.RevokeSession200JSONResponse); !ok {
+		t.Fatalf("expected 200, got %T", revokeResp)
+	}
+	if _, err := h.sessionStore.Validate(phoneToken); err != auth.ErrSessionNotFound {
+		t.Errorf("expected revoked token to stop working, got %v", err)
+	}
+
+	revokeResp, err = h.RevokeSession(ctx, openapi.RevokeSessionRequestObject{Id: phone.Id})
+	if err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if _, ok := revokeResp.(openapi.RevokeSession404JSONResponse); !ok {
+		t.Errorf("expected 404 for an already revoked session, got %T", revokeResp)
+	}
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	h, db := testHandler(t)
+	u := testutil.CreateTestUser(t, db, "user@test.com", "User")
+
+	ctxWithUser(t, h, u.ID)
+	ctxWithUser(t, h, u.ID)
+	ctx := ctxWithUser(t, h, u.ID)
+
+	resp, err := h.RevokeOtherSessions(ctx, openapi.RevokeOtherSessionsRequestObject{})
+	if err != nil {
+		t.Fatalf("RevokeOtherSessions: %v", err)
+	}
+	if got := resp.(openapi.RevokeOtherSessions200JSONResponse).Revoked; got != 2 {
+		t.Errorf("expected 2 revoked sessions, got %d", got)
+	}
+
+	list, err := h.ListSessions(ctx, openapi.ListSessionsRequestObject{})
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	sessions := list.(openapi.ListSessions200JSONResponse).Sessions
+	if len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("expected only the current session to remain, got %+v", sessions)
+	}
+}
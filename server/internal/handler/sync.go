@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+const (
+	// maxSyncChannels caps how many channels one sync request may cover
+	maxSyncChannels = 200
+	// syncMessageLimit is the most new messages returned per channel
+	syncMessageLimit = 100
+)
+
+// SyncWorkspace returns new messages, memberships and read state in one batch
+func (h *Handler) SyncWorkspace(ctx context.Context, request openapi.SyncWorkspaceRequestObject) (openapi.SyncWorkspaceResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.SyncWorkspace401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.SyncWorkspace403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	if len(request.Body.Channels) > maxSyncChannels {
+		return openapi.SyncWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Too many channels; at most 200 can be synced at once")}, nil
+	}
+	for channelID, lastMessageID := range request.Body.Channels {
+		if lastMessageID == "" {
+			return openapi.SyncWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "A last message ID is required for channel "+channelID)}, nil
+		}
+	}
+
+	result := openapi.SyncResult{
+		Channels:          []openapi.ChannelSync{},
+		RemovedChannelIds: []string{},
+	}
+	filter := &moderation.FilterOptions{WorkspaceID: workspaceID, RequestingUserID: userID}
+
+	// Sorted so the response order is stable
+	for _, channelID := range slices.Sorted(maps.Keys(request.Body.Channels)) {
+		ch, err := h.channelRepo.GetByID(ctx, channelID)
+		if err != nil && !errors.Is(err, channel.ErrChannelNotFound) {
+			return nil, err
+		}
+		if ch == nil || ch.WorkspaceID != workspaceID {
+			result.RemovedChannelIds = append(result.RemovedChannelIds, channelID)
+			continue
+		}
+
+		opts, canRead, err := h.channelReadOptions(ctx, userID, ch)
+		if err != nil {
+			return nil, err
+		}
+		if !canRead {
+			result.RemovedChannelIds = append(result.RemovedChannelIds, channelID)
+			continue
+		}
+
+		opts.Direction = "after"
+		opts.Cursor = request.Body.Channels[channelID]
+		opts.Limit = syncMessageLimit
+		list, err := h.messageRepo.List(ctx, channelID, opts, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		h.loadAttachmentsForMessages(ctx, list.Messages)
+		h.loadLinkPreviewsForMessages(ctx, list.Messages)
+		h.loadPollsForMessages(ctx, list.Messages, userID)
+		if isDMChannel(ch) && h.readReceiptsEnabled(ctx, workspaceID) {
+			h.loadReceiptsForMessages(ctx, list.Messages)
+		}
+
+		messages := messageListResultToAPI(list).Messages
+		localizeMessages(ctx, messages)
+		h.truncateMessages(messages)
+		result.Channels = append(result.Channels, openapi.ChannelSync{
+			ChannelId: channelID,
+			Messages:  messages,
+			HasMore:   list.HasMore,
+		})
+	}
+
+	channels, err := h.channelRepo.ListForWorkspace(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	result.Memberships = make([]openapi.ChannelWithMembership, len(channels))
+	for i, ch := range channels {
+		result.Memberships[i] = channelWithMembershipToAPI(ch)
+	}
+
+	return openapi.SyncWorkspace200JSONResponse(result), nil
+}
+
+// channelReadOptions reports whether a workspace member can read ch, and the
+// list options that apply its history visibility. Like listing messages,
+// public channels are readable without joining them.
+func (h *Handler) channelReadOptions(ctx context.Context, userID string, ch *channel.Channel) (message.ListOptions, bool, error) {
+	membership, err := h.channelRepo.GetMembership(ctx, userID, ch.ID)
+	if errors.Is(err, channel.ErrNotChannelMember) {
+		return message.ListOptions{}, ch.Type == channel.TypePublic, nil
+	}
+	if err != nil {
+		return message.ListOptions{}, false, err
+	}
+	return message.ListOptions{VisibleSince: ch.HistoryVisibleSince(membership.CreatedAt)}, true, nil
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestSyncWorkspace(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test Workspace")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	general := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	addChannelMember(t, db, member.ID, general.ID, nil)
+	secret := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "secret", "private")
+
+	first := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "first")
+	second := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "second")
+	third := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "third")
+
+	ctx := ctxWithUser(t, h, member.ID)
+	resp, err := h.SyncWorkspace(ctx, openapi.SyncWorkspaceRequestObject{
+		Wid: ws.ID,
+		Body: &openapi.SyncInput{Channels: map[string]string{
+			general.ID: first.ID,
+			secret.ID:  first.ID,
+			"missing":  first.ID,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("SyncWorkspace: %v", err)
+	}
+	result, ok := resp.(openapi.SyncWorkspace200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	if len(result.Channels) != 1 || result.Channels[0].ChannelId != general.ID {
+		t.Fatalf("expected only general to be synced, got %+v", result.Channels)
+	}
+	msgs := result.Channels[0].Messages
+	if len(msgs) != 2 || msgs[0].Id != second.ID || msgs[1].Id != third.ID {
+		t.Errorf("expected the two newer messages oldest first, got %+v", msgs)
+	}
+	if result.Channels[0].HasMore {
+		t.Error("expected has_more to be false")
+	}
+
+	if len(result.RemovedChannelIds) != 2 {
+		t.Errorf("expected the private and missing channels to be removed, got %v", result.RemovedChannelIds)
+	}
+
+	var found bool
+	for _, m := range result.Memberships {
+		if m.Id == secret.ID {
+			t.Error("expected memberships to exclude channels the user cannot see")
+		}
+		if m.Id == general.ID {
+			found = true
+			if m.UnreadCount != 3 {
+				t.Errorf("expected 3 unread in general, got %d", m.UnreadCount)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected memberships to include general")
+	}
+}
+
+func TestSyncWorkspace_Validation(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	outsider := testutil.CreateTestUser(t, db, "outsider@example.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test Workspace")
+	general := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+
+	resp, err := h.SyncWorkspace(ctxWithUser(t, h, outsider.ID), openapi.SyncWorkspaceRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.SyncInput{Channels: map[string]string{}},
+	})
+	if err != nil {
+		t.Fatalf("SyncWorkspace: %v", err)
+	}
+	if _, ok := resp.(openapi.SyncWorkspace403JSONResponse); !ok {
+		t.Errorf("expected 403 for a non-member, got %T", resp)
+	}
+
+	resp, err = h.SyncWorkspace(ctxWithUser(t, h, owner.ID), openapi.SyncWorkspaceRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.SyncInput{Channels: map[string]string{general.ID: ""}},
+	})
+	if err != nil {
+		t.Fatalf("SyncWorkspace: %v", err)
+	}
+	if _, ok := resp.(openapi.SyncWorkspace400JSONResponse); !ok {
+		t.Errorf("expected 400 for an empty message ID, got %T", resp)
+	}
+}
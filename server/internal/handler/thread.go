@@ -9,7 +9,9 @@ import (
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
 	"github.com/enzyme/server/internal/thread"
+	"github.com/enzyme/server/internal/workspace"
 )
 
 // GetThreadSubscription returns the user's subscription status for a thread
@@ -226,6 +228,15 @@ func (h *Handler) MarkThreadRead(ctx context.Context, request openapi.MarkThread
 		return nil, err
 	}
 
+	// Broadcast to user's other clients
+	if h.hub != nil {
+		h.hub.BroadcastToUser(ctx, ch.WorkspaceID, userID, sse.NewThreadReadEvent(openapi.ThreadReadEventData{
+			ThreadParentId:  string(request.Id),
+			ChannelId:       msg.ChannelID,
+			LastReadReplyId: replyID,
+		}))
+	}
+
 	return openapi.MarkThreadRead200JSONResponse{Success: true}, nil
 }
 
@@ -239,6 +250,9 @@ func (h *Handler) ListUserThreads(ctx context.Context, request openapi.ListUserT
 	// Check workspace membership
 	_, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ListUserThreads403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -267,26 +281,30 @@ func (h *Handler) ListUserThreads(ctx context.Context, request openapi.ListUserT
 	}
 	result.UnreadThreadCount = unreadCount
 
-	return openapi.ListUserThreads200JSONResponse(threadListResultToAPI(result)), nil
+	apiResult := threadListResultToAPI(result)
+	h.truncateThreadMessages(apiResult.Threads)
+	return openapi.ListUserThreads200JSONResponse(apiResult), nil
 }
 
 // threadMessageToAPI converts a message.ThreadMessage to openapi.ThreadMessage
 func threadMessageToAPI(m *message.ThreadMessage) openapi.ThreadMessage {
 	apiMsg := openapi.ThreadMessage{
-		Id:             m.ID,
-		ChannelId:      m.ChannelID,
-		UserId:         m.UserID,
-		Content:        m.Content,
-		ThreadParentId: m.ThreadParentID,
-		ReplyCount:     m.ReplyCount,
-		LastReplyAt:    m.LastReplyAt,
-		EditedAt:       m.EditedAt,
-		DeletedAt:      m.DeletedAt,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
-		ChannelName:    m.ChannelName,
-		ChannelType:    openapi.ChannelType(m.ChannelType),
-		HasNewReplies:  m.HasNewReplies,
+		Id:               m.ID,
+		ChannelId:        m.ChannelID,
+		UserId:           m.UserID,
+		Content:          m.Content,
+		ContentRendered:  renderContent(m.Content, m.DeletedAt),
+		ThreadParentId:   m.ThreadParentID,
+		ReplyCount:       m.ReplyCount,
+		LastReplyAt:      m.LastReplyAt,
+		EditedAt:         m.EditedAt,
+		DeletedAt:        m.DeletedAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+		ChannelName:      m.ChannelName,
+		ChannelType:      openapi.ChannelType(m.ChannelType),
+		HasNewReplies:    m.HasNewReplies,
+		UnreadReplyCount: m.UnreadReplyCount,
 	}
 	if m.Type != "" {
 		msgType := openapi.MessageType(m.Type)
@@ -298,6 +316,12 @@ func threadMessageToAPI(m *message.ThreadMessage) openapi.ThreadMessage {
 	if m.UserAvatarURL != nil {
 		apiMsg.UserAvatarUrl = m.UserAvatarURL
 	}
+	if m.UserIsDeactivated {
+		apiMsg.UserIsDeactivated = &m.UserIsDeactivated
+	}
+	if m.IsBot {
+		apiMsg.IsBot = &m.IsBot
+	}
 	if g := gravatar.URL(m.UserEmail); g != "" {
 		apiMsg.UserGravatarUrl = &g
 	}
@@ -325,8 +349,12 @@ func threadMessageToAPI(m *message.ThreadMessage) openapi.ThreadMessage {
 			if g := gravatar.URL(p.Email); g != "" {
 				participants[i].GravatarUrl = &g
 			}
+			if p.IsDeactivated {
+				participants[i].IsDeactivated = &p.IsDeactivated
+			}
 		}
 		apiMsg.ThreadParticipants = &participants
+		apiMsg.ThreadParticipantCount = &m.ThreadParticipantCount
 	}
 	return apiMsg
 }
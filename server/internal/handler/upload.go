@@ -0,0 +1,445 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/oklog/ulid/v2"
+)
+
+// CreateUpload starts a resumable upload to a channel
+func (h *Handler) CreateUpload(ctx context.Context, request openapi.CreateUploadRequestObject) (openapi.CreateUploadResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateUpload401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if h.storage == nil {
+		return openapi.CreateUpload403JSONResponse{ForbiddenJSONResponse: filesDisabledResponse()}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, string(request.Id))
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.CreateUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+
+	denied, err := h.checkUploadAccess(ctx, userID, ch)
+	if err != nil {
+		return nil, err
+	}
+	if denied != "" {
+		return openapi.CreateUpload403JSONResponse{ForbiddenJSONResponse: notAMemberResponse(denied)}, nil
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.CreateUpload503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
+	filename := sanitizeFilename(request.Body.Filename)
+	if filename == "" {
+		return openapi.CreateUpload400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid filename")}, nil
+	}
+	if request.Body.Size < 1 {
+		return openapi.CreateUpload400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Size must be at least 1 byte")}, nil
+	}
+	if request.Body.Size > h.maxUploadSize {
+		return openapi.CreateUpload400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "File too large")}, nil
+	}
+
+	fits, err := h.fitsStorageQuota(ctx, ch.WorkspaceID, request.Body.Size)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return openapi.CreateUpload403JSONResponse{ForbiddenJSONResponse: quotaExceededResponse()}, nil
+	}
+
+	contentType := "application/octet-stream"
+	if request.Body.ContentType != nil && *request.Body.ContentType != "" {
+		contentType = *request.Body.ContentType
+	}
+	// Refuse early on the declared type; completion checks the real one
+	allowed, err := h.uploadTypeAllowed(ctx, ch.WorkspaceID, filename, contentType)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return openapi.CreateUpload403JSONResponse{ForbiddenJSONResponse: fileTypeBlockedResponse()}, nil
+	}
+
+	session := &file.UploadSession{
+		ChannelID:   ch.ID,
+		UserID:      userID,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   request.Body.Size,
+		ExpiresAt:   time.Now().Add(h.uploadSessionTTL),
+	}
+	if err := h.fileRepo.CreateUploadSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return openapi.CreateUpload201JSONResponse(uploadSessionToAPI(session)), nil
+}
+
+// GetUpload returns the progress of one of the caller's uploads
+func (h *Handler) GetUpload(ctx context.Context, request openapi.GetUploadRequestObject) (openapi.GetUploadResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetUpload401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	session, err := h.getOwnUploadSession(ctx, request.Id, userID)
+	if err != nil {
+		if errors.Is(err, file.ErrUploadSessionNotFound) {
+			return openapi.GetUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+		}
+		return nil, err
+	}
+
+	return openapi.GetUpload200JSONResponse(uploadSessionToAPI(session)), nil
+}
+
+// AppendUpload stores the request body as the next chunk of an upload
+func (h *Handler) AppendUpload(ctx context.Context, request openapi.AppendUploadRequestObject) (openapi.AppendUploadResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.AppendUpload401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	session, err := h.getOwnUploadSession(ctx, request.Id, userID)
+	if err != nil {
+		if errors.Is(err, file.ErrUploadSessionNotFound) {
+			return openapi.AppendUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+		}
+		return nil, err
+	}
+	if h.storage == nil {
+		return openapi.AppendUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+	}
+
+	if request.Params.Offset != session.Offset {
+		return openapi.AppendUpload409JSONResponse{ConflictJSONResponse: conflictResponse("Offset does not match upload progress")}, nil
+	}
+
+	// Read one extra byte to detect chunks that run past the declared size
+	remaining := session.SizeBytes - session.Offset
+	data, err := io.ReadAll(io.LimitReader(request.Body, remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return openapi.AppendUpload400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Chunk is empty")}, nil
+	}
+	if int64(len(data)) > remaining {
+		return openapi.AppendUpload400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Chunk exceeds the declared upload size")}, nil
+	}
+
+	chunk := file.UploadChunk{
+		Offset:      session.Offset,
+		SizeBytes:   int64(len(data)),
+		StoragePath: "uploads/" + session.ID + "/" + ulid.Make().String(),
+	}
+	if err := h.storage.Put(ctx, chunk.StoragePath, bytes.NewReader(data), chunk.SizeBytes, "application/octet-stream"); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(h.uploadSessionTTL)
+	if err := h.fileRepo.AppendUploadChunk(ctx, session.ID, chunk, expiresAt); err != nil {
+		_ = h.storage.Delete(ctx, chunk.StoragePath)
+		if errors.Is(err, file.ErrUploadOffsetMismatch) {
+			return openapi.AppendUpload409JSONResponse{ConflictJSONResponse: conflictResponse("Offset does not match upload progress")}, nil
+		}
+		return nil, err
+	}
+
+	session.Offset += chunk.SizeBytes
+	session.ExpiresAt = expiresAt
+	return openapi.AppendUpload200JSONResponse(uploadSessionToAPI(session)), nil
+}
+
+// CancelUpload discards an upload and its stored chunks
+func (h *Handler) CancelUpload(ctx context.Context, request openapi.CancelUploadRequestObject) (openapi.CancelUploadResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CancelUpload401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	session, err := h.getOwnUploadSession(ctx, request.Id, userID)
+	if err != nil {
+		if errors.Is(err, file.ErrUploadSessionNotFound) {
+			return openapi.CancelUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+		}
+		return nil, err
+	}
+
+	chunks, err := h.fileRepo.ListUploadChunks(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.fileRepo.DeleteUploadSession(ctx, session.ID); err != nil {
+		if errors.Is(err, file.ErrUploadSessionNotFound) {
+			return openapi.CancelUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+		}
+		return nil, err
+	}
+	h.deleteUploadChunks(ctx, chunks)
+
+	return openapi.CancelUpload200JSONResponse{Success: true}, nil
+}
+
+// CompleteUpload assembles an upload's chunks into an attachment
+func (h *Handler) CompleteUpload(ctx context.Context, request openapi.CompleteUploadRequestObject) (openapi.CompleteUploadResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CompleteUpload401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	session, err := h.getOwnUploadSession(ctx, request.Id, userID)
+	if err != nil {
+		if errors.Is(err, file.ErrUploadSessionNotFound) {
+			return openapi.CompleteUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+		}
+		return nil, err
+	}
+	if h.storage == nil {
+		return openapi.CompleteUpload403JSONResponse{ForbiddenJSONResponse: filesDisabledResponse()}, nil
+	}
+
+	if session.Offset != session.SizeBytes {
+		return openapi.CompleteUpload400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Upload is incomplete")}, nil
+	}
+
+	// Membership may have changed since the upload started
+	ch, err := h.channelRepo.GetByID(ctx, session.ChannelID)
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return openapi.CompleteUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel not found")}, nil
+		}
+		return nil, err
+	}
+	denied, err := h.checkUploadAccess(ctx, userID, ch)
+	if err != nil {
+		return nil, err
+	}
+	if denied != "" {
+		return openapi.CompleteUpload403JSONResponse{ForbiddenJSONResponse: notAMemberResponse(denied)}, nil
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.CompleteUpload503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
+	// Other uploads may have used up the quota while this one was in flight
+	fits, err := h.fitsStorageQuota(ctx, ch.WorkspaceID, session.SizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return openapi.CompleteUpload403JSONResponse{ForbiddenJSONResponse: quotaExceededResponse()}, nil
+	}
+
+	chunks, err := h.fileRepo.ListUploadChunks(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileID := ulid.Make().String()
+	storageKey := ch.WorkspaceID + "/" + ch.ID + "/" + fileID + filepath.Ext(session.Filename)
+	body := &chunkReader{ctx: ctx, store: h.storage, chunks: chunks}
+	head := make([]byte, file.SniffLen)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		body.Close()
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := file.DetectContentType(head, session.Filename)
+	allowed, err := h.uploadTypeAllowed(ctx, ch.WorkspaceID, session.Filename, contentType)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	if !allowed {
+		body.Close()
+		return openapi.CompleteUpload403JSONResponse{ForbiddenJSONResponse: fileTypeBlockedResponse()}, nil
+	}
+
+	var width, height *int
+	size := session.SizeBytes
+	if file.IsImage(contentType) {
+		// Images are rewritten before storing; the session size is bounded
+		// by the max upload size, so buffer it
+		data, err := io.ReadAll(io.MultiReader(bytes.NewReader(head), body))
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+		data, width, height = processImage(data, contentType, session.Filename)
+		size = int64(len(data))
+		err = h.storage.Put(ctx, storageKey, bytes.NewReader(data), size, contentType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = h.storage.Put(ctx, storageKey, io.MultiReader(bytes.NewReader(head), body), size, contentType)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := h.scanStoredUpload(ctx, storageKey, session.Filename)
+	if err != nil {
+		_ = h.storage.Delete(ctx, storageKey)
+		return nil, err
+	}
+
+	// Deleting the session claims it, so a concurrent completion of the same
+	// upload cannot create a second attachment.
+	if err := h.fileRepo.DeleteUploadSession(ctx, session.ID); err != nil {
+		_ = h.storage.Delete(ctx, storageKey)
+		if errors.Is(err, file.ErrUploadSessionNotFound) {
+			return openapi.CompleteUpload404JSONResponse{NotFoundJSONResponse: notFoundResponse("Upload not found")}, nil
+		}
+		return nil, err
+	}
+
+	attachment := &file.Attachment{
+		ChannelID:   ch.ID,
+		UserID:      &userID,
+		Filename:    session.Filename,
+		ContentType: contentType,
+		SizeBytes:   size,
+		StoragePath: storageKey,
+		Status:      status,
+		Width:       width,
+		Height:      height,
+	}
+	if err := h.fileRepo.Create(ctx, attachment); err != nil {
+		_ = h.storage.Delete(ctx, storageKey)
+		return nil, err
+	}
+	h.deleteUploadChunks(ctx, chunks)
+
+	return openapi.CompleteUpload200JSONResponse{
+		File: struct {
+			ContentType string                   `json:"content_type"`
+			Filename    string                   `json:"filename"`
+			Id          string                   `json:"id"`
+			Size        int                      `json:"size"`
+			Status      openapi.AttachmentStatus `json:"status"`
+		}{
+			Id:          attachment.ID,
+			Filename:    attachment.Filename,
+			Size:        int(attachment.SizeBytes),
+			ContentType: attachment.ContentType,
+			Status:      openapi.AttachmentStatus(attachment.Status),
+		},
+	}, nil
+}
+
+// getOwnUploadSession loads an upload session, treating other users'
+// sessions as not found.
+func (h *Handler) getOwnUploadSession(ctx context.Context, id, userID string) (*file.UploadSession, error) {
+	session, err := h.fileRepo.GetUploadSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID || time.Now().After(session.ExpiresAt) {
+		return nil, file.ErrUploadSessionNotFound
+	}
+	return session, nil
+}
+
+func (h *Handler) deleteUploadChunks(ctx context.Context, chunks []file.UploadChunk) {
+	for _, c := range chunks {
+		if err := h.storage.Delete(ctx, c.StoragePath); err != nil {
+			slog.Error("failed to delete upload chunk", "key", c.StoragePath, "error", err)
+		}
+	}
+}
+
+func uploadSessionToAPI(s *file.UploadSession) openapi.UploadSession {
+	return openapi.UploadSession{
+		Id:          s.ID,
+		ChannelId:   s.ChannelID,
+		Filename:    s.Filename,
+		ContentType: s.ContentType,
+		Size:        s.SizeBytes,
+		Offset:      s.Offset,
+		ExpiresAt:   s.ExpiresAt,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// chunkReader streams an upload's chunks from storage in order, opening each
+// one only when the previous one is exhausted.
+// scanStoredUpload runs scanUpload over an object already in storage.
+func (h *Handler) scanStoredUpload(ctx context.Context, storageKey, filename string) (string, error) {
+	if h.scanner == nil {
+		return file.StatusReady, nil
+	}
+	rc, err := h.storage.Get(ctx, storageKey)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	return h.scanUpload(ctx, rc, filename)
+}
+
+type chunkReader struct {
+	ctx    context.Context
+	store  storage.Storage
+	chunks []file.UploadChunk
+	cur    io.ReadCloser
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if len(r.chunks) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := r.store.Get(r.ctx, r.chunks[0].StoragePath)
+			if err != nil {
+				return 0, err
+			}
+			r.cur = rc
+			r.chunks = r.chunks[1:]
+		}
+
+		n, err := r.cur.Read(p)
+		if err == io.EOF {
+			r.cur.Close()
+			r.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	if r.cur != nil {
+		return r.cur.Close()
+	}
+	return nil
+}
@@ -20,7 +20,7 @@ func (h *Handler) GetUser(ctx context.Context, request openapi.GetUserRequestObj
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.GetUser401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -34,19 +34,35 @@ func (h *Handler) GetUser(ctx context.Context, request openapi.GetUserRequestObj
 		return nil, err
 	}
 
+	return openapi.GetUser200JSONResponse{
+		User: userProfileToAPI(u),
+	}, nil
+}
+
+// userProfileToAPI converts a user to their public profile
+func userProfileToAPI(u *user.User) openapi.UserProfile {
 	profile := openapi.UserProfile{
 		Id:          u.ID,
 		DisplayName: u.DisplayName,
 		AvatarUrl:   u.AvatarURL,
+		Title:       u.Title,
+		Pronouns:    u.Pronouns,
+		Timezone:    u.Timezone,
+		Phone:       u.Phone,
 		Status:      u.Status,
 		CreatedAt:   u.CreatedAt,
 	}
 	if g := gravatar.URL(u.Email); g != "" {
 		profile.GravatarUrl = &g
 	}
-	return openapi.GetUser200JSONResponse{
-		User: profile,
-	}, nil
+	if u.Status == user.StatusDeactivated {
+		isDeactivated := true
+		profile.IsDeactivated = &isDeactivated
+	}
+	if u.IsBot {
+		profile.IsBot = &u.IsBot
+	}
+	return profile
 }
 
 // UpdateProfile updates the current user's profile
@@ -54,7 +70,7 @@ func (h *Handler) UpdateProfile(ctx context.Context, request openapi.UpdateProfi
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.UpdateProfile401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -62,7 +78,7 @@ func (h *Handler) UpdateProfile(ctx context.Context, request openapi.UpdateProfi
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return openapi.UpdateProfile401JSONResponse{
-				UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+				UnauthorizedJSONResponse: unauthorizedResponse(),
 			}, nil
 		}
 		return nil, err
@@ -103,7 +119,7 @@ func (h *Handler) UploadAvatar(ctx context.Context, request openapi.UploadAvatar
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.UploadAvatar401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -112,7 +128,7 @@ func (h *Handler) UploadAvatar(ctx context.Context, request openapi.UploadAvatar
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return openapi.UploadAvatar401JSONResponse{
-				UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+				UnauthorizedJSONResponse: unauthorizedResponse(),
 			}, nil
 		}
 		return nil, err
@@ -138,7 +154,7 @@ func (h *Handler) UploadAvatar(ctx context.Context, request openapi.UploadAvatar
 
 	if h.storage == nil {
 		return openapi.UploadAvatar403JSONResponse{
-			ForbiddenJSONResponse: openapi.ForbiddenJSONResponse(newErrorResponse(ErrCodeFilesDisabled, "File uploads are disabled")),
+			ForbiddenJSONResponse: filesDisabledResponse(),
 		}, nil
 	}
 
@@ -156,20 +172,19 @@ func (h *Handler) UploadAvatar(ctx context.Context, request openapi.UploadAvatar
 	// Generate storage key
 	fileID := ulid.Make().String()
 	filename := fileID + ext
-	storageKey := "avatars/" + filename
+	storageKey := "avatars/" + userID + "/" + filename
 
 	if err := h.storage.Put(ctx, storageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
 		return nil, err
 	}
 
 	// Delete old avatar file if it exists and is a local avatar
-	if u.AvatarURL != nil && strings.HasPrefix(*u.AvatarURL, "/api/avatars/") {
-		oldFilename := sanitizePathSegment(strings.TrimPrefix(*u.AvatarURL, "/api/avatars/"))
-		_ = h.storage.Delete(ctx, "avatars/"+oldFilename)
+	if oldKey, ok := avatarStorageKey(u.AvatarURL); ok {
+		_ = h.storage.Delete(ctx, oldKey)
 	}
 
 	// Update user's avatar URL
-	avatarURL := "/api/avatars/" + filename
+	avatarURL := "/api/avatars/" + userID + "/" + filename
 	u.AvatarURL = &avatarURL
 	if err := h.userRepo.Update(ctx, u); err != nil {
 		_ = h.storage.Delete(ctx, storageKey)
@@ -186,7 +201,7 @@ func (h *Handler) DeleteAvatar(ctx context.Context, request openapi.DeleteAvatar
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.DeleteAvatar401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -195,16 +210,15 @@ func (h *Handler) DeleteAvatar(ctx context.Context, request openapi.DeleteAvatar
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return openapi.DeleteAvatar401JSONResponse{
-				UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+				UnauthorizedJSONResponse: unauthorizedResponse(),
 			}, nil
 		}
 		return nil, err
 	}
 
 	// Delete avatar file if it's a local avatar
-	if h.storage != nil && u.AvatarURL != nil && strings.HasPrefix(*u.AvatarURL, "/api/avatars/") {
-		filename := sanitizePathSegment(strings.TrimPrefix(*u.AvatarURL, "/api/avatars/"))
-		_ = h.storage.Delete(ctx, "avatars/"+filename)
+	if key, ok := avatarStorageKey(u.AvatarURL); ok && h.storage != nil {
+		_ = h.storage.Delete(ctx, key)
 	}
 
 	// Clear user's avatar URL
@@ -218,19 +232,36 @@ func (h *Handler) DeleteAvatar(ctx context.Context, request openapi.DeleteAvatar
 	}, nil
 }
 
+// avatarStorageKey maps a locally hosted avatar URL to its storage key. Avatars
+// live under avatars/{userId}/; older uploads used a flat avatars/{filename}.
+func avatarStorageKey(avatarURL *string) (string, bool) {
+	if avatarURL == nil || !strings.HasPrefix(*avatarURL, "/api/avatars/") {
+		return "", false
+	}
+	path := strings.TrimPrefix(*avatarURL, "/api/avatars/")
+	if parts := strings.SplitN(path, "/", 2); len(parts) == 2 {
+		return "avatars/" + sanitizePathSegment(parts[0]) + "/" + sanitizePathSegment(parts[1]), true
+	}
+	return "avatars/" + sanitizePathSegment(path), true
+}
+
 // ServeAvatar serves avatar files (called manually from router, not generated)
 func (h *Handler) ServeAvatar(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userId")
 	filename := chi.URLParam(r, "filename")
 	if filename == "" {
 		http.Error(w, "Not found", http.StatusNotFound)
 		return
 	}
 
-	// Sanitize filename to prevent directory traversal
-	filename = sanitizePathSegment(filename)
+	// Sanitize to prevent directory traversal
+	key := "avatars/" + sanitizePathSegment(filename)
+	if userID != "" {
+		key = "avatars/" + sanitizePathSegment(userID) + "/" + sanitizePathSegment(filename)
+	}
 	if h.storage == nil {
 		http.Error(w, "Not found", http.StatusNotFound)
 		return
 	}
-	h.storage.Serve(w, r, "avatars/"+filename)
+	h.storage.Serve(w, r, key)
 }
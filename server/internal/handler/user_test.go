@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/go-chi/chi/v5"
+)
+
+func avatarUpload(t *testing.T, contentType string, data []byte) *multipart.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="avatar"`)
+	header.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		t.Fatalf("CreatePart: %v", err)
+	}
+	part.Write(data)
+	mw.Close()
+	return multipart.NewReader(&buf, mw.Boundary())
+}
+
+func TestUploadAvatar(t *testing.T) {
+	h, db := testHandler(t)
+	u := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	ctx := ctxWithUser(t, h, u.ID)
+
+	resp, err := h.UploadAvatar(ctx, openapi.UploadAvatarRequestObject{Body: avatarUpload(t, "text/plain", []byte("hi"))})
+	if err != nil {
+		t.Fatalf("UploadAvatar: %v", err)
+	}
+	if _, ok := resp.(openapi.UploadAvatar400JSONResponse); !ok {
+		t.Fatalf("expected 400 for non-image, got %T", resp)
+	}
+
+	upload := func(data string) string {
+		t.Helper()
+		resp, err := h.UploadAvatar(ctx, openapi.UploadAvatarRequestObject{Body: avatarUpload(t, "image/png", []byte(data))})
+		if err != nil {
+			t.Fatalf("UploadAvatar: %v", err)
+		}
+		r, ok := resp.(openapi.UploadAvatar200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200, got %T", resp)
+		}
+		return r.AvatarUrl
+	}
+
+	first := upload("first")
+	if !strings.HasPrefix(first, "/api/avatars/"+u.ID+"/") || !strings.HasSuffix(first, ".png") {
+		t.Fatalf("unexpected avatar URL %q", first)
+	}
+	second := upload("second")
+
+	firstKey, _ := avatarStorageKey(&first)
+	if _, err := h.storage.Get(context.Background(), firstKey); err == nil {
+		t.Error("expected the previous avatar file to be removed")
+	}
+
+	router := chi.NewRouter()
+	router.Get("/api/avatars/{userId}/{filename}", h.ServeAvatar)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, second, nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "second" {
+		t.Errorf("expected the new avatar to be served, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAvatarStorageKey(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantKey string
+		wantOK  bool
+	}{
+		{"/api/avatars/U1/A.png", "avatars/U1/A.png", true},
+		{"/api/avatars/A.png", "avatars/A.png", true},
+		{"/api/avatars/../etc/passwd", "avatars/_/etcpasswd", true},
+		{"https://example.com/a.png", "", false},
+	}
+	for _, tt := range tests {
+		url := tt.url
+		key, ok := avatarStorageKey(&url)
+		if key != tt.wantKey || ok != tt.wantOK {
+			t.Errorf("avatarStorageKey(%q) = %q, %v; want %q, %v", tt.url, key, ok, tt.wantKey, tt.wantOK)
+		}
+	}
+	if _, ok := avatarStorageKey(nil); ok {
+		t.Error("expected nil URL to have no storage key")
+	}
+}
@@ -0,0 +1,368 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/user"
+	"github.com/enzyme/server/internal/usergroup"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// maxUserGroupMembersPerRequest bounds the user IDs in one membership change
+const maxUserGroupMembersPerRequest = 100
+
+// workspaceMentionResolver resolves plain-text mentions of both display names and
+// user group handles
+type workspaceMentionResolver struct {
+	users  *user.Repository
+	groups *usergroup.Repository
+}
+
+func (r workspaceMentionResolver) ResolveDisplayNames(ctx context.Context, workspaceID string, names []string) (map[string]string, error) {
+	return r.users.ResolveDisplayNames(ctx, workspaceID, names)
+}
+
+func (r workspaceMentionResolver) ResolveGroupMembers(ctx context.Context, workspaceID string, refs []string) ([]string, error) {
+	if r.groups == nil {
+		return nil, nil
+	}
+	return r.groups.ResolveGroupMembers(ctx, workspaceID, refs)
+}
+
+// mentionResolver returns the resolver used to parse mentions in messages
+func (h *Handler) mentionResolver() notification.UserResolver {
+	return workspaceMentionResolver{users: h.userRepo, groups: h.userGroupRepo}
+}
+
+// ListUserGroups lists a workspace's user groups
+func (h *Handler) ListUserGroups(ctx context.Context, request openapi.ListUserGroupsRequestObject) (openapi.ListUserGroupsResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListUserGroups401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID); err != nil {
+		return openapi.ListUserGroups403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	groups, err := h.userGroupRepo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiGroups := make([]openapi.UserGroup, len(groups))
+	for i := range groups {
+		apiGroups[i] = userGroupToAPI(&groups[i])
+	}
+	return openapi.ListUserGroups200JSONResponse{Groups: apiGroups}, nil
+}
+
+// CreateUserGroup creates a user group with its initial members
+func (h *Handler) CreateUserGroup(ctx context.Context, request openapi.CreateUserGroupRequestObject) (openapi.CreateUserGroupResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateUserGroup401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+	workspaceID := string(request.Wid)
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return openapi.CreateUserGroup403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.CreateUserGroup403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage user groups")}, nil
+	}
+
+	group := &usergroup.Group{
+		WorkspaceID: workspaceID,
+		Handle:      strings.TrimSpace(request.Body.Handle),
+		Name:        strings.TrimSpace(request.Body.Name),
+		Description: trimmedOrNil(request.Body.Description),
+		CreatedBy:   &userID,
+	}
+	if msg := validateUserGroup(group); msg != "" {
+		return openapi.CreateUserGroup400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+	var memberIDs []string
+	if request.Body.MemberIds != nil {
+		memberIDs = *request.Body.MemberIds
+	}
+	if len(memberIDs) > maxUserGroupMembersPerRequest {
+		return openapi.CreateUserGroup400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("At most %d members can be added at once", maxUserGroupMembersPerRequest))}, nil
+	}
+
+	if err := h.userGroupRepo.Create(ctx, group, memberIDs); err != nil {
+		if errors.Is(err, usergroup.ErrHandleExists) {
+			return openapi.CreateUserGroup409JSONResponse{ConflictJSONResponse: conflictResponse("A user group with this handle already exists")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "user_group.created", "user_group", group.ID, map[string]interface{}{
+		"handle": group.Handle,
+	})
+
+	return openapi.CreateUserGroup200JSONResponse{Group: userGroupToAPI(group)}, nil
+}
+
+// UpdateUserGroup changes a user group's handle, name or description
+func (h *Handler) UpdateUserGroup(ctx context.Context, request openapi.UpdateUserGroupRequestObject) (openapi.UpdateUserGroupResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateUserGroup401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	group, err := h.userGroupRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, usergroup.ErrGroupNotFound) {
+			return openapi.UpdateUserGroup404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, group.WorkspaceID)
+	if err != nil {
+		return openapi.UpdateUserGroup404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.UpdateUserGroup403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage user groups")}, nil
+	}
+
+	if request.Body.Handle != nil {
+		group.Handle = strings.TrimSpace(*request.Body.Handle)
+	}
+	if request.Body.Name != nil {
+		group.Name = strings.TrimSpace(*request.Body.Name)
+	}
+	if request.Body.Description != nil {
+		group.Description = trimmedOrNil(request.Body.Description)
+	}
+	if msg := validateUserGroup(group); msg != "" {
+		return openapi.UpdateUserGroup400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if err := h.userGroupRepo.Update(ctx, group); err != nil {
+		if errors.Is(err, usergroup.ErrHandleExists) {
+			return openapi.UpdateUserGroup409JSONResponse{ConflictJSONResponse: conflictResponse("A user group with this handle already exists")}, nil
+		}
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, group.WorkspaceID, userID, "user_group.updated", "user_group", group.ID, map[string]interface{}{
+		"handle": group.Handle,
+	})
+
+	return openapi.UpdateUserGroup200JSONResponse{Group: userGroupToAPI(group)}, nil
+}
+
+// DeleteUserGroup removes a user group and its memberships
+func (h *Handler) DeleteUserGroup(ctx context.Context, request openapi.DeleteUserGroupRequestObject) (openapi.DeleteUserGroupResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteUserGroup401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	group, err := h.userGroupRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, usergroup.ErrGroupNotFound) {
+			return openapi.DeleteUserGroup404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, group.WorkspaceID)
+	if err != nil {
+		return openapi.DeleteUserGroup404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.DeleteUserGroup403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage user groups")}, nil
+	}
+
+	if err := h.userGroupRepo.Delete(ctx, group.ID); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, group.WorkspaceID, userID, "user_group.deleted", "user_group", group.ID, map[string]interface{}{
+		"handle": group.Handle,
+	})
+
+	return openapi.DeleteUserGroup200JSONResponse{Success: true}, nil
+}
+
+// ListUserGroupMembers lists the members of a user group
+func (h *Handler) ListUserGroupMembers(ctx context.Context, request openapi.ListUserGroupMembersRequestObject) (openapi.ListUserGroupMembersResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListUserGroupMembers401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	group, err := h.userGroupRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, usergroup.ErrGroupNotFound) {
+			return openapi.ListUserGroupMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+		}
+		return nil, err
+	}
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, group.WorkspaceID); err != nil {
+		return openapi.ListUserGroupMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+	}
+
+	members, err := h.userGroupRepo.ListMembers(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiMembers := make([]openapi.UserGroupMember, len(members))
+	for i, m := range members {
+		apiMembers[i] = openapi.UserGroupMember{
+			UserId:      m.UserID,
+			DisplayName: m.DisplayName,
+			AvatarUrl:   m.AvatarURL,
+			CreatedAt:   m.CreatedAt,
+		}
+	}
+	return openapi.ListUserGroupMembers200JSONResponse{Members: apiMembers}, nil
+}
+
+// AddUserGroupMembers adds workspace members to a user group
+func (h *Handler) AddUserGroupMembers(ctx context.Context, request openapi.AddUserGroupMembersRequestObject) (openapi.AddUserGroupMembersResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.AddUserGroupMembers401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	group, err := h.userGroupRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, usergroup.ErrGroupNotFound) {
+			return openapi.AddUserGroupMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, group.WorkspaceID)
+	if err != nil {
+		return openapi.AddUserGroupMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.AddUserGroupMembers403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage user groups")}, nil
+	}
+	if msg := validateUserGroupMemberIDs(request.Body.UserIds); msg != "" {
+		return openapi.AddUserGroupMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if _, err := h.userGroupRepo.AddMembers(ctx, group, request.Body.UserIds); err != nil {
+		return nil, err
+	}
+
+	group, err = h.userGroupRepo.GetByID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.AddUserGroupMembers200JSONResponse{Group: userGroupToAPI(group)}, nil
+}
+
+// RemoveUserGroupMembers removes users from a user group
+func (h *Handler) RemoveUserGroupMembers(ctx context.Context, request openapi.RemoveUserGroupMembersRequestObject) (openapi.RemoveUserGroupMembersResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RemoveUserGroupMembers401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	group, err := h.userGroupRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, usergroup.ErrGroupNotFound) {
+			return openapi.RemoveUserGroupMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+		}
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, group.WorkspaceID)
+	if err != nil {
+		return openapi.RemoveUserGroupMembers404JSONResponse{NotFoundJSONResponse: notFoundResponse("User group not found")}, nil
+	}
+	if !workspace.CanManageMembers(membership.Role) {
+		return openapi.RemoveUserGroupMembers403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage user groups")}, nil
+	}
+	if msg := validateUserGroupMemberIDs(request.Body.UserIds); msg != "" {
+		return openapi.RemoveUserGroupMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	if _, err := h.userGroupRepo.RemoveMembers(ctx, group.ID, request.Body.UserIds); err != nil {
+		return nil, err
+	}
+
+	group, err = h.userGroupRepo.GetByID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.RemoveUserGroupMembers200JSONResponse{Group: userGroupToAPI(group)}, nil
+}
+
+// validateUserGroup checks a group's handle and name, returning a message
+// for the first problem found
+func validateUserGroup(g *usergroup.Group) string {
+	if g.Handle == "" {
+		return "Handle is required"
+	}
+	if len(g.Handle) > 64 {
+		return "Handle must be 64 characters or fewer"
+	}
+	if !validChannelName.MatchString(g.Handle) {
+		return "Handle must contain only lowercase letters, numbers, and dashes"
+	}
+	switch g.Handle {
+	case "here", "channel", "everyone":
+		return "Handle cannot be here, channel or everyone"
+	}
+	if g.Name == "" {
+		return "Name is required"
+	}
+	if len(g.Name) > 80 {
+		return "Name must be 80 characters or fewer"
+	}
+	if g.Description != nil && len(*g.Description) > 250 {
+		return "Description must be 250 characters or fewer"
+	}
+	return ""
+}
+
+func validateUserGroupMemberIDs(userIDs []string) string {
+	if len(userIDs) == 0 {
+		return "user_ids must not be empty"
+	}
+	if len(userIDs) > maxUserGroupMembersPerRequest {
+		return fmt.Sprintf("At most %d members can be changed at once", maxUserGroupMembersPerRequest)
+	}
+	return ""
+}
+
+// trimmedOrNil trims s, returning nil when nothing is left
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	if t := strings.TrimSpace(*s); t != "" {
+		return &t
+	}
+	return nil
+}
+
+func userGroupToAPI(g *usergroup.Group) openapi.UserGroup {
+	return openapi.UserGroup{
+		Id:          g.ID,
+		WorkspaceId: g.WorkspaceID,
+		Handle:      g.Handle,
+		Name:        g.Name,
+		Description: g.Description,
+		CreatedBy:   g.CreatedBy,
+		MemberCount: g.MemberCount,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func TestUserGroups_CRUDAndMembers(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	desc := "  Server folks  "
+	resp, err := h.CreateUserGroup(ctx, openapi.CreateUserGroupRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateUserGroupJSONRequestBody{Handle: "backend", Name: "Backend", Description: &desc, MemberIds: &[]string{owner.ID}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, ok := resp.(openapi.CreateUserGroup200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if created.Group.MemberCount != 1 || created.Group.Description == nil || *created.Group.Description != "Server folks" {
+		t.Errorf("group = %+v, want one member and a trimmed description", created.Group)
+	}
+	groupID := created.Group.Id
+
+	// Duplicate and reserved handles are rejected
+	resp, err = h.CreateUserGroup(ctx, openapi.CreateUserGroupRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateUserGroupJSONRequestBody{Handle: "backend", Name: "Again"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateUserGroup409JSONResponse); !ok {
+		t.Errorf("duplicate handle: expected 409 response, got %T", resp)
+	}
+	for _, handle := range []string{"here", "Back End", ""} {
+		resp, err = h.CreateUserGroup(ctx, openapi.CreateUserGroupRequestObject{
+			Wid:  ws.ID,
+			Body: &openapi.CreateUserGroupJSONRequestBody{Handle: handle, Name: "Bad"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := resp.(openapi.CreateUserGroup400JSONResponse); !ok {
+			t.Errorf("handle %q: expected 400 response, got %T", handle, resp)
+		}
+	}
+
+	addResp, err := h.AddUserGroupMembers(ctx, openapi.AddUserGroupMembersRequestObject{
+		Id:   groupID,
+		Body: &openapi.AddUserGroupMembersJSONRequestBody{UserIds: []string{member.ID}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := addResp.(openapi.AddUserGroupMembers200JSONResponse); !ok || r.Group.MemberCount != 2 {
+		t.Fatalf("add members = %#v, want member_count 2", addResp)
+	}
+
+	// Any workspace member can list the group and its members
+	memberCtx := ctxWithUser(t, h, member.ID)
+	listResp, err := h.ListUserGroupMembers(memberCtx, openapi.ListUserGroupMembersRequestObject{Id: groupID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := listResp.(openapi.ListUserGroupMembers200JSONResponse); !ok || len(r.Members) != 2 {
+		t.Fatalf("list members = %#v, want 2 members", listResp)
+	}
+	groupsResp, err := h.ListUserGroups(memberCtx, openapi.ListUserGroupsRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := groupsResp.(openapi.ListUserGroups200JSONResponse); !ok || len(r.Groups) != 1 || r.Groups[0].Handle != "backend" {
+		t.Fatalf("list groups = %#v, want backend", groupsResp)
+	}
+
+	handle := "platform"
+	updResp, err := h.UpdateUserGroup(ctx, openapi.UpdateUserGroupRequestObject{
+		Id:   groupID,
+		Body: &openapi.UpdateUserGroupJSONRequestBody{Handle: &handle},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := updResp.(openapi.UpdateUserGroup200JSONResponse); !ok || r.Group.Handle != "platform" || r.Group.Name != "Backend" {
+		t.Fatalf("update = %#v, want handle platform and unchanged name", updResp)
+	}
+
+	rmResp, err := h.RemoveUserGroupMembers(ctx, openapi.RemoveUserGroupMembersRequestObject{
+		Id:   groupID,
+		Body: &openapi.RemoveUserGroupMembersJSONRequestBody{UserIds: []string{owner.ID}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r, ok := rmResp.(openapi.RemoveUserGroupMembers200JSONResponse); !ok || r.Group.MemberCount != 1 {
+		t.Fatalf("remove members = %#v, want member_count 1", rmResp)
+	}
+
+	delResp, err := h.DeleteUserGroup(ctx, openapi.DeleteUserGroupRequestObject{Id: groupID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := delResp.(openapi.DeleteUserGroup200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", delResp)
+	}
+	var audited int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE action LIKE 'user_group.%'`).Scan(&audited); err != nil {
+		t.Fatalf("counting audit entries: %v", err)
+	}
+	if audited != 3 {
+		t.Errorf("audit entries = %d, want 3", audited)
+	}
+}
+
+func TestUserGroups_MembersCannotManage(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	resp, err := h.CreateUserGroup(ctxWithUser(t, h, member.ID), openapi.CreateUserGroupRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateUserGroupJSONRequestBody{Handle: "oncall", Name: "On-call"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateUserGroup403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestSendMessage_GroupMentionNotifiesMembers(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	alice := testutil.CreateTestUser(t, db, "alice@test.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@test.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	for _, u := range []string{alice.ID, bob.ID} {
+		addWorkspaceMember(t, db, u, ws.ID, "member")
+		addChannelMember(t, db, u, ch.ID, nil)
+	}
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	resp, err := h.CreateUserGroup(ctx, openapi.CreateUserGroupRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateUserGroupJSONRequestBody{Handle: "oncall", Name: "On-call", MemberIds: &[]string{alice.ID}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateUserGroup200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	content := "@oncall the deploy is stuck"
+	if _, err := h.SendMessage(ctx, openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content},
+	}); err != nil {
+		t.Fatalf("sending message: %v", err)
+	}
+
+	for _, tc := range []struct {
+		userID string
+		want   int
+	}{{alice.ID, 1}, {bob.ID, 0}} {
+		var count int
+		if err := db.QueryRow(`
+			SELECT notification_count FROM channel_memberships WHERE user_id = ? AND channel_id = ?
+		`, tc.userID, ch.ID).Scan(&count); err != nil {
+			t.Fatalf("reading notification count: %v", err)
+		}
+		if count != tc.want {
+			t.Errorf("notification_count for %s = %d, want %d", tc.userID, count, tc.want)
+		}
+	}
+
+	if items := listActivity(t, h, alice.ID, ws.ID).Items; len(items) != 1 || items[0].Type != openapi.ActivityTypeMention {
+		t.Errorf("alice's activity = %+v, want one mention", items)
+	}
+}
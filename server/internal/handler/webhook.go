@@ -0,0 +1,422 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/linkpreview"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/notification"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/webhook"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+const maxWebhookNameLength = 80
+
+// WebhookRateLimitPath is the rule path the webhook limiter must be configured with.
+// The limiter is keyed by webhook ID rather than client IP so every webhook
+// gets its own budget regardless of where requests come from.
+const WebhookRateLimitPath = "/api/hooks"
+
+func webhookToAPI(wh *webhook.IncomingWebhook) openapi.IncomingWebhook {
+	return openapi.IncomingWebhook{
+		Id:          wh.ID,
+		WorkspaceId: wh.WorkspaceID,
+		ChannelId:   wh.ChannelID,
+		Name:        wh.Name,
+		AvatarUrl:   wh.AvatarURL,
+		CreatedBy:   wh.CreatedBy,
+		LastUsedAt:  wh.LastUsedAt,
+		CreatedAt:   wh.CreatedAt,
+		UpdatedAt:   wh.UpdatedAt,
+	}
+}
+
+func (h *Handler) webhookURL(token string) string {
+	return h.publicURL + "/api/hooks/" + token
+}
+
+// isValidImageURL accepts absolute http(s) URLs for bot avatars.
+func isValidImageURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateWebhookChannel checks that a channel can be the target of a webhook
+// in the given workspace. Returns a user-facing message when it cannot.
+func (h *Handler) validateWebhookChannel(ctx context.Context, workspaceID, channelID string) (string, error) {
+	ch, err := h.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		if errors.Is(err, channel.ErrChannelNotFound) {
+			return "Channel not found", nil
+		}
+		return "", err
+	}
+	if ch.WorkspaceID != workspaceID {
+		return "Channel not found", nil
+	}
+	if ch.Type != channel.TypePublic && ch.Type != channel.TypePrivate {
+		return "Webhooks can only post to public or private channels", nil
+	}
+	if ch.ArchivedAt != nil {
+		return "Cannot post to archived channel", nil
+	}
+	return "", nil
+}
+
+// canManageIntegrations reports whether the user is an admin or owner of the
+// workspace and may therefore manage webhooks and bots.
+func (h *Handler) canManageIntegrations(ctx context.Context, userID, workspaceID string) bool {
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
+	if err != nil {
+		return false
+	}
+	return workspace.CanManageMembers(membership.Role)
+}
+
+// CreateIncomingWebhook creates an incoming webhook for a channel
+func (h *Handler) CreateIncomingWebhook(ctx context.Context, request openapi.CreateIncomingWebhookRequestObject) (openapi.CreateIncomingWebhookResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CreateIncomingWebhook401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if !h.canManageIntegrations(ctx, userID, workspaceID) {
+		return openapi.CreateIncomingWebhook403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage webhooks")}, nil
+	}
+
+	name := strings.TrimSpace(request.Body.Name)
+	if name == "" {
+		return openapi.CreateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Name is required")}, nil
+	}
+	if utf8.RuneCountInString(name) > maxWebhookNameLength {
+		return openapi.CreateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Name exceeds maximum length of %d characters", maxWebhookNameLength))}, nil
+	}
+
+	var avatarURL *string
+	if request.Body.AvatarUrl != nil && *request.Body.AvatarUrl != "" {
+		if !isValidImageURL(*request.Body.AvatarUrl) {
+			return openapi.CreateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "avatar_url must be an http(s) URL")}, nil
+		}
+		avatarURL = request.Body.AvatarUrl
+	}
+
+	msg, err := h.validateWebhookChannel(ctx, workspaceID, request.Body.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		return openapi.CreateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+	}
+
+	wh := &webhook.IncomingWebhook{
+		WorkspaceID: workspaceID,
+		ChannelID:   request.Body.ChannelId,
+		Name:        name,
+		AvatarURL:   avatarURL,
+		CreatedBy:   &userID,
+	}
+	token, err := h.webhookRepo.Create(ctx, wh)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "webhook.created", "webhook", wh.ID, map[string]interface{}{
+		"name":       wh.Name,
+		"channel_id": wh.ChannelID,
+	})
+
+	return openapi.CreateIncomingWebhook200JSONResponse{
+		Webhook: webhookToAPI(wh),
+		Url:     h.webhookURL(token),
+	}, nil
+}
+
+// ListIncomingWebhooks lists the incoming webhooks of a workspace
+func (h *Handler) ListIncomingWebhooks(ctx context.Context, request openapi.ListIncomingWebhooksRequestObject) (openapi.ListIncomingWebhooksResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListIncomingWebhooks401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	workspaceID := string(request.Wid)
+	if !h.canManageIntegrations(ctx, userID, workspaceID) {
+		return openapi.ListIncomingWebhooks403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage webhooks")}, nil
+	}
+
+	webhooks, err := h.webhookRepo.ListByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiWebhooks := make([]openapi.IncomingWebhook, len(webhooks))
+	for i := range webhooks {
+		apiWebhooks[i] = webhookToAPI(&webhooks[i])
+	}
+
+	return openapi.ListIncomingWebhooks200JSONResponse{
+		Webhooks: apiWebhooks,
+	}, nil
+}
+
+// UpdateIncomingWebhook changes the name, avatar, or channel of a webhook
+func (h *Handler) UpdateIncomingWebhook(ctx context.Context, request openapi.UpdateIncomingWebhookRequestObject) (openapi.UpdateIncomingWebhookResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.UpdateIncomingWebhook401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	wh, err := h.webhookRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return openapi.UpdateIncomingWebhook404JSONResponse{NotFoundJSONResponse: notFoundResponse("Webhook not found")}, nil
+		}
+		return nil, err
+	}
+
+	if !h.canManageIntegrations(ctx, userID, wh.WorkspaceID) {
+		return openapi.UpdateIncomingWebhook403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage webhooks")}, nil
+	}
+
+	if request.Body.Name != nil {
+		name := strings.TrimSpace(*request.Body.Name)
+		if name == "" {
+			return openapi.UpdateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Name cannot be empty")}, nil
+		}
+		if utf8.RuneCountInString(name) > maxWebhookNameLength {
+			return openapi.UpdateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Name exceeds maximum length of %d characters", maxWebhookNameLength))}, nil
+		}
+		wh.Name = name
+	}
+
+	if request.Body.AvatarUrl != nil {
+		if *request.Body.AvatarUrl == "" {
+			wh.AvatarURL = nil
+		} else if !isValidImageURL(*request.Body.AvatarUrl) {
+			return openapi.UpdateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "avatar_url must be an http(s) URL")}, nil
+		} else {
+			wh.AvatarURL = request.Body.AvatarUrl
+		}
+	}
+
+	if request.Body.ChannelId != nil && *request.Body.ChannelId != wh.ChannelID {
+		msg, err := h.validateWebhookChannel(ctx, wh.WorkspaceID, *request.Body.ChannelId)
+		if err != nil {
+			return nil, err
+		}
+		if msg != "" {
+			return openapi.UpdateIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+		}
+		wh.ChannelID = *request.Body.ChannelId
+	}
+
+	if err := h.webhookRepo.Update(ctx, wh); err != nil {
+		return nil, err
+	}
+
+	return openapi.UpdateIncomingWebhook200JSONResponse{
+		Webhook: webhookToAPI(wh),
+	}, nil
+}
+
+// RegenerateIncomingWebhookToken issues a new token, invalidating the old URL
+func (h *Handler) RegenerateIncomingWebhookToken(ctx context.Context, request openapi.RegenerateIncomingWebhookTokenRequestObject) (openapi.RegenerateIncomingWebhookTokenResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RegenerateIncomingWebhookToken401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	wh, err := h.webhookRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return openapi.RegenerateIncomingWebhookToken404JSONResponse{NotFoundJSONResponse: notFoundResponse("Webhook not found")}, nil
+		}
+		return nil, err
+	}
+
+	if !h.canManageIntegrations(ctx, userID, wh.WorkspaceID) {
+		return openapi.RegenerateIncomingWebhookToken403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage webhooks")}, nil
+	}
+
+	token, err := h.webhookRepo.RegenerateToken(ctx, wh.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, wh.WorkspaceID, userID, "webhook.token_regenerated", "webhook", wh.ID, nil)
+
+	return openapi.RegenerateIncomingWebhookToken200JSONResponse{
+		Webhook: webhookToAPI(wh),
+		Url:     h.webhookURL(token),
+	}, nil
+}
+
+// DeleteIncomingWebhook deletes an incoming webhook
+func (h *Handler) DeleteIncomingWebhook(ctx context.Context, request openapi.DeleteIncomingWebhookRequestObject) (openapi.DeleteIncomingWebhookResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.DeleteIncomingWebhook401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	wh, err := h.webhookRepo.GetByID(ctx, request.Id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return openapi.DeleteIncomingWebhook404JSONResponse{NotFoundJSONResponse: notFoundResponse("Webhook not found")}, nil
+		}
+		return nil, err
+	}
+
+	if !h.canManageIntegrations(ctx, userID, wh.WorkspaceID) {
+		return openapi.DeleteIncomingWebhook403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can manage webhooks")}, nil
+	}
+
+	if err := h.webhookRepo.Delete(ctx, wh.ID); err != nil {
+		return nil, err
+	}
+
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, wh.WorkspaceID, userID, "webhook.deleted", "webhook", wh.ID, map[string]interface{}{
+		"name": wh.Name,
+	})
+
+	return openapi.DeleteIncomingWebhook200JSONResponse{
+		Success: true,
+	}, nil
+}
+
+// ExecuteIncomingWebhook posts a message through a webhook. Unauthenticated:
+// the token in the URL is the credential.
+func (h *Handler) ExecuteIncomingWebhook(ctx context.Context, request openapi.ExecuteIncomingWebhookRequestObject) (openapi.ExecuteIncomingWebhookResponseObject, error) {
+	wh, err := h.webhookRepo.GetByToken(ctx, request.Token)
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return openapi.ExecuteIncomingWebhook404JSONResponse{NotFoundJSONResponse: notFoundResponse("Webhook not found")}, nil
+		}
+		return nil, err
+	}
+
+	if h.webhookLimiter != nil {
+		result, allowed := h.webhookLimiter.Allow(wh.ID, "POST", WebhookRateLimitPath)
+		if !allowed {
+			retryAfter := int(math.Ceil(result.RetryIn.Seconds()))
+			return openapi.ExecuteIncomingWebhook429JSONResponse{TooManyRequestsJSONResponse: tooManyRequestsResponse(retryAfter)}, nil
+		}
+	}
+
+	content := strings.TrimSpace(request.Body.Text)
+	if content == "" {
+		return openapi.ExecuteIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Text is required")}, nil
+	}
+	if utf8.RuneCountInString(content) > h.maxMessageLength {
+		return openapi.ExecuteIncomingWebhook400JSONResponse{BadRequestJSONResponse: messageTooLongResponse("Message content", h.maxMessageLength)}, nil
+	}
+
+	ch, err := h.channelRepo.GetByID(ctx, wh.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if ch.ArchivedAt != nil {
+		return openapi.ExecuteIncomingWebhook400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Cannot post to archived channel")}, nil
+	}
+	if notice, readOnly, err := h.readOnlyNotice(ctx, ch.WorkspaceID); err != nil {
+		return nil, err
+	} else if readOnly {
+		return openapi.ExecuteIncomingWebhook503JSONResponse{ReadOnlyJSONResponse: readOnlyResponse(notice)}, nil
+	}
+
+	// Bot identity: webhook defaults, optionally overridden per request
+	botName := wh.Name
+	if request.Body.Username != nil {
+		if name := strings.TrimSpace(*request.Body.Username); name != "" && utf8.RuneCountInString(name) <= maxWebhookNameLength {
+			botName = name
+		}
+	}
+	botAvatarURL := wh.AvatarURL
+	if request.Body.IconUrl != nil && isValidImageURL(*request.Body.IconUrl) {
+		botAvatarURL = request.Body.IconUrl
+	}
+
+	var mentions []string
+	var originalMentions []string
+	if h.notificationService != nil {
+		mentions, _ = notification.ParseMentions(ctx, h.mentionResolver(), ch.WorkspaceID, content)
+		mentions = h.addKeywordMentions(ctx, ch.ID, "", content, mentions)
+		originalMentions = mentions
+
+		if h.hub != nil && slices.Contains(mentions, notification.MentionHere) {
+			memberIDs, err := h.channelRepo.GetMemberUserIDs(ctx, ch.ID)
+			if err != nil {
+				slog.Error("failed to get channel members for @here resolution", "component", "mentions", "error", err)
+			} else {
+				mentions = notification.ResolveHereMentions(mentions, memberIDs, "", h.hub, ch.WorkspaceID)
+			}
+		}
+	}
+
+	msg := &message.Message{
+		ChannelID:    ch.ID,
+		Content:      content,
+		Mentions:     mentions,
+		WebhookID:    &wh.ID,
+		BotName:      &botName,
+		BotAvatarURL: botAvatarURL,
+	}
+	if err := h.messageRepo.Create(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	if err := h.webhookRepo.TouchLastUsed(ctx, wh.ID); err != nil {
+		slog.Error("failed to record webhook usage", "webhook", wh.ID, "error", err)
+	}
+
+	msgWithUser, err := h.messageRepo.GetByIDWithUser(ctx, msg.ID)
+	if err != nil {
+		msgWithUser = &message.MessageWithUser{Message: *msg, UserDisplayName: botName, UserAvatarURL: botAvatarURL, IsBot: true}
+	}
+
+	if h.linkPreviewFetcher != nil {
+		if firstURL := linkpreview.ExtractFirstURL(content); firstURL != "" {
+			if preview := h.fetchLinkPreview(ctx, firstURL, msg.ID, msg.ChannelID, ch.WorkspaceID); preview != nil {
+				msgWithUser.LinkPreview = preview
+			}
+		}
+	}
+
+	if h.hub != nil {
+		h.hub.BroadcastToChannel(ctx, ch.WorkspaceID, ch.ID, sse.NewMessageNewEvent(messageWithUserToAPI(msgWithUser)))
+	}
+
+	if h.notificationService != nil {
+		channelInfo := &notification.ChannelInfo{
+			ID:          ch.ID,
+			WorkspaceID: ch.WorkspaceID,
+			Name:        ch.Name,
+			Type:        ch.Type,
+		}
+		msgInfo := &notification.MessageInfo{
+			ID:         msg.ID,
+			ChannelID:  msg.ChannelID,
+			SenderName: botName,
+			Content:    msg.Content,
+			Mentions:   originalMentions,
+		}
+		h.notificationService.Enqueue(ctx, channelInfo, msgInfo)
+	}
+
+	return openapi.ExecuteIncomingWebhook200JSONResponse{
+		MessageId: msg.ID,
+	}, nil
+}
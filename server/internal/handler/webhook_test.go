@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/ratelimit"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// createWebhookViaAPI creates a webhook as the given admin and returns the token from its URL.
+func createWebhookViaAPI(t *testing.T, h *Handler, userID, workspaceID, channelID string) (openapi.IncomingWebhook, string) {
+	t.Helper()
+
+	ctx := ctxWithUser(t, h, userID)
+	resp, err := h.CreateIncomingWebhook(ctx, openapi.CreateIncomingWebhookRequestObject{
+		Wid: workspaceID,
+		Body: &openapi.CreateIncomingWebhookJSONRequestBody{
+			ChannelId: channelID,
+			Name:      "CI Bot",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateIncomingWebhook: %v", err)
+	}
+	r, ok := resp.(openapi.CreateIncomingWebhook200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	idx := strings.LastIndex(r.Url, "/api/hooks/")
+	if idx < 0 {
+		t.Fatalf("unexpected webhook URL %q", r.Url)
+	}
+	return r.Webhook, r.Url[idx+len("/api/hooks/"):]
+}
+
+func TestCreateIncomingWebhook_RequiresAdmin(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, workspace.RoleMember)
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "deploys", "public")
+
+	ctx := ctxWithUser(t, h, member.ID)
+	resp, err := h.CreateIncomingWebhook(ctx, openapi.CreateIncomingWebhookRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateIncomingWebhookJSONRequestBody{ChannelId: ch.ID, Name: "CI Bot"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateIncomingWebhook403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+}
+
+func TestCreateIncomingWebhook_RejectsOtherWorkspaceChannel(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	otherWS := testutil.CreateTestWorkspace(t, db, owner.ID, "Other")
+	ch := testutil.CreateTestChannel(t, db, otherWS.ID, owner.ID, "deploys", "public")
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	resp, err := h.CreateIncomingWebhook(ctx, openapi.CreateIncomingWebhookRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateIncomingWebhookJSONRequestBody{ChannelId: ch.ID, Name: "CI Bot"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.CreateIncomingWebhook400JSONResponse); !ok {
+		t.Fatalf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestExecuteIncomingWebhook_PostsAsBot(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "deploys", "public")
+
+	wh, token := createWebhookViaAPI(t, h, owner.ID, ws.ID, ch.ID)
+
+	username := "Release Train"
+	resp, err := h.ExecuteIncomingWebhook(context.Background(), openapi.ExecuteIncomingWebhookRequestObject{
+		Token: token,
+		Body:  &openapi.ExecuteIncomingWebhookJSONRequestBody{Text: "Deployed v1.2.3", Username: &username},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ExecuteIncomingWebhook200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	msg, err := h.messageRepo.GetByIDWithUser(context.Background(), r.MessageId)
+	if err != nil {
+		t.Fatalf("GetByIDWithUser: %v", err)
+	}
+	if msg.ChannelID != ch.ID {
+		t.Errorf("channel = %q, want %q", msg.ChannelID, ch.ID)
+	}
+	if msg.UserID != nil {
+		t.Errorf("expected no user_id on webhook message, got %q", *msg.UserID)
+	}
+	if msg.WebhookID == nil || *msg.WebhookID != wh.Id {
+		t.Errorf("expected webhook_id %q, got %v", wh.Id, msg.WebhookID)
+	}
+	if msg.UserDisplayName != username {
+		t.Errorf("display name = %q, want %q", msg.UserDisplayName, username)
+	}
+
+	got, err := h.webhookRepo.GetByID(context.Background(), wh.Id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.LastUsedAt == nil {
+		t.Error("expected last_used_at to be set")
+	}
+}
+
+func TestExecuteIncomingWebhook_UnknownToken(t *testing.T) {
+	h, _ := testHandler(t)
+
+	resp, err := h.ExecuteIncomingWebhook(context.Background(), openapi.ExecuteIncomingWebhookRequestObject{
+		Token: "does-not-exist",
+		Body:  &openapi.ExecuteIncomingWebhookJSONRequestBody{Text: "hello"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ExecuteIncomingWebhook404JSONResponse); !ok {
+		t.Fatalf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestExecuteIncomingWebhook_RateLimited(t *testing.T) {
+	h, db := testHandler(t)
+	h.webhookLimiter = ratelimit.NewLimiter([]ratelimit.Rule{
+		{Method: "POST", Path: WebhookRateLimitPath, Limit: 1, Window: time.Minute},
+	})
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "deploys", "public")
+	_, token := createWebhookViaAPI(t, h, owner.ID, ws.ID, ch.ID)
+
+	req := openapi.ExecuteIncomingWebhookRequestObject{
+		Token: token,
+		Body:  &openapi.ExecuteIncomingWebhookJSONRequestBody{Text: "hello"},
+	}
+	resp, err := h.ExecuteIncomingWebhook(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.ExecuteIncomingWebhook200JSONResponse); !ok {
+		t.Fatalf("first request: expected 200 response, got %T", resp)
+	}
+
+	resp, err = h.ExecuteIncomingWebhook(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.ExecuteIncomingWebhook429JSONResponse)
+	if !ok {
+		t.Fatalf("second request: expected 429 response, got %T", resp)
+	}
+	if r.Headers.RetryAfter <= 0 {
+		t.Errorf("expected positive Retry-After, got %d", r.Headers.RetryAfter)
+	}
+}
+
+func TestDeleteIncomingWebhook_InvalidatesToken(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "deploys", "public")
+	wh, token := createWebhookViaAPI(t, h, owner.ID, ws.ID, ch.ID)
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	resp, err := h.DeleteIncomingWebhook(ctx, openapi.DeleteIncomingWebhookRequestObject{Id: wh.Id})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.DeleteIncomingWebhook200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	execResp, err := h.ExecuteIncomingWebhook(context.Background(), openapi.ExecuteIncomingWebhookRequestObject{
+		Token: token,
+		Body:  &openapi.ExecuteIncomingWebhookJSONRequestBody{Text: "hello"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := execResp.(openapi.ExecuteIncomingWebhook404JSONResponse); !ok {
+		t.Fatalf("expected 404 after delete, got %T", execResp)
+	}
+}
@@ -3,14 +3,27 @@ package handler
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"math"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/channeltemplate"
+	"github.com/enzyme/server/internal/email"
 	"github.com/enzyme/server/internal/gravatar"
+	"github.com/enzyme/server/internal/logging"
 	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/openapi"
@@ -29,7 +42,22 @@ func (h *Handler) CreateWorkspace(ctx context.Context, request openapi.CreateWor
 	}
 
 	if strings.TrimSpace(request.Body.Name) == "" {
-		return openapi.CreateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Name is required")}, nil
+		return openapi.CreateWorkspace400JSONResponse{BadRequestJSONResponse: requiredResponse("name")}, nil
+	}
+
+	var tmpl *channeltemplate.Template
+	if request.Body.TemplateId != nil && *request.Body.TemplateId != "" {
+		var err error
+		tmpl, err = h.getApplicableTemplate(ctx, *request.Body.TemplateId, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, channeltemplate.ErrTemplateNotFound):
+				return openapi.CreateWorkspace404JSONResponse{NotFoundJSONResponse: notFoundResponse("Channel template not found")}, nil
+			case errors.Is(err, errChannelTemplateForbidden):
+				return openapi.CreateWorkspace403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You cannot use this channel template")}, nil
+			}
+			return nil, err
+		}
 	}
 
 	ws := &workspace.Workspace{
@@ -51,6 +79,12 @@ func (h *Handler) CreateWorkspace(ctx context.Context, request openapi.CreateWor
 		h.hub.AddChannelMember(defaultChannel.ID, userID)
 	}
 
+	if tmpl != nil {
+		if _, err := h.applyChannelTemplate(ctx, ws.ID, userID, tmpl); err != nil {
+			slog.Error("failed to apply channel template to new workspace", "workspace_id", ws.ID, "template_id", tmpl.ID, "error", err)
+		}
+	}
+
 	apiWs := workspaceToAPI(ws)
 	return openapi.CreateWorkspace200JSONResponse{
 		Workspace: apiWs,
@@ -94,6 +128,9 @@ func (h *Handler) UpdateWorkspace(ctx context.Context, request openapi.UpdateWor
 	// Check permissions
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UpdateWorkspace403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -103,12 +140,16 @@ func (h *Handler) UpdateWorkspace(ctx context.Context, request openapi.UpdateWor
 
 	ws, err := h.workspaceRepo.GetByID(ctx, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrWorkspaceNotFound) {
+			return openapi.UpdateWorkspace404JSONResponse{NotFoundJSONResponse: notFoundResponse("Workspace not found")}, nil
+		}
 		return nil, err
 	}
+	oldName, oldSettings := ws.Name, ws.ParsedSettings()
 
 	if request.Body.Name != nil {
 		if strings.TrimSpace(*request.Body.Name) == "" {
-			return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Name cannot be empty")}, nil
+			return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: requiredResponse("name")}, nil
 		}
 		ws.Name = *request.Body.Name
 	}
@@ -125,31 +166,108 @@ func (h *Handler) UpdateWorkspace(ctx context.Context, request openapi.UpdateWor
 		if request.Body.Settings.WhoCanCreateChannels != nil {
 			v := workspace.PermissionLevel(*request.Body.Settings.WhoCanCreateChannels)
 			if !workspace.IsValidPermissionLevel(v) {
-				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid value for who_can_create_channels")}, nil
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: invalidValueResponse("who_can_create_channels")}, nil
 			}
 			settings.WhoCanCreateChannels = v
 		}
 		if request.Body.Settings.WhoCanCreateInvites != nil {
 			v := workspace.PermissionLevel(*request.Body.Settings.WhoCanCreateInvites)
 			if !workspace.IsValidPermissionLevel(v) {
-				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid value for who_can_create_invites")}, nil
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: invalidValueResponse("who_can_create_invites")}, nil
 			}
 			settings.WhoCanCreateInvites = v
 		}
 		if request.Body.Settings.WhoCanPinMessages != nil {
 			v := workspace.PermissionLevel(*request.Body.Settings.WhoCanPinMessages)
 			if !workspace.IsValidPermissionLevel(v) {
-				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid value for who_can_pin_messages")}, nil
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: invalidValueResponse("who_can_pin_messages")}, nil
 			}
 			settings.WhoCanPinMessages = v
 		}
 		if request.Body.Settings.WhoCanManageCustomEmoji != nil {
 			v := workspace.PermissionLevel(*request.Body.Settings.WhoCanManageCustomEmoji)
 			if !workspace.IsValidPermissionLevel(v) {
-				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid value for who_can_manage_custom_emoji")}, nil
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: invalidValueResponse("who_can_manage_custom_emoji")}, nil
 			}
 			settings.WhoCanManageCustomEmoji = v
 		}
+		if request.Body.Settings.WhoCanMentionChannel != nil {
+			v := workspace.PermissionLevel(*request.Body.Settings.WhoCanMentionChannel)
+			if !workspace.IsValidPermissionLevel(v) {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: invalidValueResponse("who_can_mention_channel")}, nil
+			}
+			settings.WhoCanMentionChannel = v
+		}
+		if request.Body.Settings.DmReadReceipts != nil {
+			settings.DMReadReceipts = *request.Body.Settings.DmReadReceipts
+		}
+		if request.Body.Settings.LinkPreviews != nil {
+			settings.LinkPreviews = *request.Body.Settings.LinkPreviews
+		}
+		if request.Body.Settings.AttachmentRetentionDays != nil {
+			v := *request.Body.Settings.AttachmentRetentionDays
+			if v < 0 || v > maxRetentionDays {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: outOfRangeResponse("attachment_retention_days", 0, maxRetentionDays)}, nil
+			}
+			settings.AttachmentRetentionDays = v
+		}
+		if request.Body.Settings.MessageRetentionDays != nil {
+			v := *request.Body.Settings.MessageRetentionDays
+			if v < 0 || v > maxRetentionDays {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: outOfRangeResponse("message_retention_days", 0, maxRetentionDays)}, nil
+			}
+			settings.MessageRetentionDays = v
+		}
+		if request.Body.Settings.AutoDmPolicy != nil {
+			v := workspace.AutoDMPolicy(*request.Body.Settings.AutoDmPolicy)
+			if !workspace.IsValidAutoDMPolicy(v) {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: invalidValueResponse("auto_dm_policy")}, nil
+			}
+			settings.AutoDMPolicy = v
+		}
+		if request.Body.Settings.OpenSignupDomains != nil {
+			if len(*request.Body.Settings.OpenSignupDomains) > workspace.MaxSignupDomains {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("open_signup_domains cannot have more than %d domains", workspace.MaxSignupDomains))}, nil
+			}
+			domains := []string{}
+			for _, d := range *request.Body.Settings.OpenSignupDomains {
+				domain, ok := workspace.NormalizeSignupDomain(d)
+				if !ok {
+					return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, fmt.Sprintf("Invalid email domain %q", d))}, nil
+				}
+				if !slices.Contains(domains, domain) {
+					domains = append(domains, domain)
+				}
+			}
+			settings.OpenSignupDomains = domains
+		}
+		if request.Body.Settings.OpenSignup != nil {
+			settings.OpenSignup = *request.Body.Settings.OpenSignup
+		}
+		if request.Body.Settings.UploadAllowedTypes != nil {
+			types, msg := normalizeUploadTypes("upload_allowed_types", *request.Body.Settings.UploadAllowedTypes)
+			if msg != "" {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+			}
+			settings.UploadAllowedTypes = types
+		}
+		if request.Body.Settings.UploadBlockedTypes != nil {
+			types, msg := normalizeUploadTypes("upload_blocked_types", *request.Body.Settings.UploadBlockedTypes)
+			if msg != "" {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, msg)}, nil
+			}
+			settings.UploadBlockedTypes = types
+		}
+		if request.Body.Settings.DefaultNotificationSound != nil {
+			v := strings.TrimSpace(*request.Body.Settings.DefaultNotificationSound)
+			if utf8.RuneCountInString(v) > workspace.MaxNotificationSoundLength {
+				return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: tooLongResponse("default_notification_sound", workspace.MaxNotificationSoundLength)}, nil
+			}
+			settings.DefaultNotificationSound = v
+		}
+		if settings.OpenSignup && len(settings.OpenSignupDomains) == 0 {
+			return openapi.UpdateWorkspace400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Open signup needs at least one allowed email domain")}, nil
+		}
 
 		// Serialize back to JSON string
 		ws.Settings = settings.ToJSON()
@@ -159,11 +277,22 @@ func (h *Handler) UpdateWorkspace(ctx context.Context, request openapi.UpdateWor
 		return nil, err
 	}
 
+	// Audit log: record only what actually changed
+	changes := changedSettings(oldSettings, ws.ParsedSettings())
+	if ws.Name != oldName {
+		changes["name"] = ws.Name
+	}
+	if len(changes) > 0 {
+		_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, ws.ID, userID, moderation.ActionWorkspaceUpdated, moderation.TargetTypeWorkspace, ws.ID, map[string]interface{}{
+			"changes": changes,
+		})
+	}
+
 	apiWs := workspaceToAPI(ws)
 
 	// Broadcast workspace update so all connected clients refresh permission-gated UI
 	if h.hub != nil {
-		h.hub.BroadcastToWorkspace(string(request.Wid), sse.NewWorkspaceUpdatedEvent(apiWs))
+		h.hub.BroadcastToWorkspace(ctx, string(request.Wid), sse.NewWorkspaceUpdatedEvent(apiWs))
 	}
 
 	return openapi.UpdateWorkspace200JSONResponse{
@@ -171,7 +300,77 @@ func (h *Handler) UpdateWorkspace(ctx context.Context, request openapi.UpdateWor
 	}, nil
 }
 
-// ListWorkspaceMembers lists members of a workspace
+// changedSettings returns the new value of every settings key that differs
+// between before and after, keyed by its JSON name.
+// normalizeUploadTypes validates an upload allowlist or denylist from a
+// settings update, returning a message naming field if it is invalid.
+func normalizeUploadTypes(field string, entries []string) ([]string, string) {
+	if len(entries) > workspace.MaxUploadTypes {
+		return nil, fmt.Sprintf("%s cannot have more than %d entries", field, workspace.MaxUploadTypes)
+	}
+	types := []string{}
+	for _, e := range entries {
+		t, ok := workspace.NormalizeUploadType(e)
+		if !ok {
+			return nil, fmt.Sprintf("Invalid %s entry %q: use a file extension like .pdf or a content type like image/png or image/*", field, e)
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	return types, ""
+}
+
+func changedSettings(before, after workspace.WorkspaceSettings) map[string]interface{} {
+	var oldValues, newValues map[string]interface{}
+	_ = json.Unmarshal([]byte(before.ToJSON()), &oldValues)
+	_ = json.Unmarshal([]byte(after.ToJSON()), &newValues)
+
+	changes := make(map[string]interface{})
+	for key, value := range newValues {
+		if !reflect.DeepEqual(oldValues[key], value) {
+			changes[key] = value
+		}
+	}
+	return changes
+}
+
+// GetWorkspaceStorage reports a workspace's attachment storage usage
+func (h *Handler) GetWorkspaceStorage(ctx context.Context, request openapi.GetWorkspaceStorageRequestObject) (openapi.GetWorkspaceStorageResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetWorkspaceStorage401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil || !workspace.CanManageMembers(membership.Role) {
+		return openapi.GetWorkspaceStorage403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only workspace admins can view storage usage")}, nil
+	}
+
+	ws, err := h.workspaceRepo.GetByID(ctx, string(request.Wid))
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := h.fileRepo.WorkspaceUsage(ctx, ws.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := openapi.GetWorkspaceStorage200JSONResponse{
+		UsedBytes:               usage.Bytes,
+		AttachmentCount:         usage.Attachments,
+		AttachmentRetentionDays: ws.ParsedSettings().AttachmentRetentionDays,
+	}
+	if h.workspaceQuota > 0 {
+		quota := h.workspaceQuota
+		resp.QuotaBytes = &quota
+	}
+	return resp, nil
+}
+
+// ListWorkspaceMembers lists a page of a workspace's members, or returns 304 if
+// the page is unchanged
 func (h *Handler) ListWorkspaceMembers(ctx context.Context, request openapi.ListWorkspaceMembersRequestObject) (openapi.ListWorkspaceMembersResponseObject, error) {
 	userID := h.getUserID(ctx)
 	if userID == "" {
@@ -179,26 +378,87 @@ func (h *Handler) ListWorkspaceMembers(ctx context.Context, request openapi.List
 	}
 
 	// Check membership
-	_, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ListWorkspaceMembers403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
+		return nil, err
+	}
+
+	opts := workspace.MemberListOptions{Sort: workspace.MemberSortJoined}
+	if request.Params.Cursor != nil {
+		opts.Cursor = *request.Params.Cursor
+	}
+	if request.Params.Limit != nil {
+		opts.Limit = *request.Params.Limit
+	}
+	if request.Params.Q != nil {
+		opts.Query = *request.Params.Q
+	}
+	if request.Params.Role != nil {
+		opts.Role = string(*request.Params.Role)
+	}
+	if request.Params.Sort != nil {
+		opts.Sort = string(*request.Params.Sort)
+	}
+	if request.Params.IncludeDeactivated != nil && *request.Params.IncludeDeactivated {
+		if !workspace.CanManageMembers(membership.Role) {
+			return openapi.ListWorkspaceMembers403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins can list deactivated members")}, nil
+		}
+		opts.IncludeDeactivated = true
+	}
+
+	version, err := h.workspaceRepo.MembersVersion(ctx, string(request.Wid))
 	if err != nil {
 		return nil, err
 	}
+	etag := pageETag(version, opts.Cursor, strconv.Itoa(opts.Limit), opts.Query, opts.Role, opts.Sort, strconv.FormatBool(opts.IncludeDeactivated))
+	if request.Params.IfNoneMatch != nil && etagMatches(*request.Params.IfNoneMatch, etag) {
+		return openapi.ListWorkspaceMembers304Response{Headers: openapi.ListWorkspaceMembers304ResponseHeaders{ETag: etag}}, nil
+	}
 
-	members, err := h.workspaceRepo.ListMembers(ctx, string(request.Wid))
+	result, err := h.workspaceRepo.ListMembersPage(ctx, string(request.Wid), opts)
 	if err != nil {
+		if errors.Is(err, workspace.ErrInvalidCursor) {
+			return openapi.ListWorkspaceMembers400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "Invalid cursor")}, nil
+		}
 		return nil, err
 	}
 
-	apiMembers := make([]openapi.WorkspaceMemberWithUser, len(members))
-	for i, m := range members {
+	apiMembers := make([]openapi.WorkspaceMemberWithUser, len(result.Members))
+	for i, m := range result.Members {
 		apiMembers[i] = memberWithUserToAPI(m)
 	}
+	body := openapi.WorkspaceMemberListResult{Members: apiMembers, HasMore: result.HasMore}
+	if result.NextCursor != "" {
+		body.NextCursor = &result.NextCursor
+	}
 
 	return openapi.ListWorkspaceMembers200JSONResponse{
-		Members: apiMembers,
+		Body:    body,
+		Headers: openapi.ListWorkspaceMembers200ResponseHeaders{ETag: etag},
 	}, nil
 }
 
+// CountWorkspaceMembers returns how many active members a workspace has
+func (h *Handler) CountWorkspaceMembers(ctx context.Context, request openapi.CountWorkspaceMembersRequestObject) (openapi.CountWorkspaceMembersResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.CountWorkspaceMembers401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid)); err != nil {
+		return openapi.CountWorkspaceMembers403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	count, err := h.workspaceRepo.CountMembers(ctx, string(request.Wid))
+	if err != nil {
+		return nil, err
+	}
+	return openapi.CountWorkspaceMembers200JSONResponse{Count: count}, nil
+}
+
 // RemoveWorkspaceMember removes a member from a workspace
 func (h *Handler) RemoveWorkspaceMember(ctx context.Context, request openapi.RemoveWorkspaceMemberRequestObject) (openapi.RemoveWorkspaceMemberResponseObject, error) {
 	userID := h.getUserID(ctx)
@@ -206,35 +466,74 @@ func (h *Handler) RemoveWorkspaceMember(ctx context.Context, request openapi.Rem
 		return openapi.RemoveWorkspaceMember401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
 	}
 
+	workspaceID := string(request.Wid)
+	targetUserID := request.Body.UserId
+	isSelf := targetUserID == userID
+
 	// Check permissions
-	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.RemoveWorkspaceMember403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
 	// Users can remove themselves, admins/owners can remove others
-	if request.Body.UserId != userID && !workspace.CanManageMembers(membership.Role) {
+	if !isSelf && !workspace.CanManageMembers(membership.Role) {
 		return openapi.RemoveWorkspaceMember403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
 	}
 
-	// Role hierarchy: actor can only remove users with strictly lower RoleRank
-	if request.Body.UserId != userID {
-		targetMembership, err := h.workspaceRepo.GetMembership(ctx, request.Body.UserId, string(request.Wid))
+	targetMembership := membership
+	if !isSelf {
+		targetMembership, err = h.workspaceRepo.GetMembership(ctx, targetUserID, workspaceID)
 		if err != nil {
 			return openapi.RemoveWorkspaceMember404JSONResponse{NotFoundJSONResponse: notFoundResponse("User is not a member of this workspace")}, nil
 		}
+		// Role hierarchy: actor can only remove users with strictly lower RoleRank
 		if workspace.RoleRank(membership.Role) <= workspace.RoleRank(targetMembership.Role) {
 			return openapi.RemoveWorkspaceMember403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Cannot remove a user with equal or higher role")}, nil
 		}
 	}
 
-	if err := h.workspaceRepo.RemoveMember(ctx, request.Body.UserId, string(request.Wid)); err != nil {
+	// Remove the workspace and channel memberships together, checking the
+	// owner count in the same transaction
+	tx, err := h.workspaceRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if targetMembership.Role == workspace.RoleOwner {
+		ownerCount, err := h.workspaceRepo.CountOwnersTx(ctx, tx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if ownerCount <= 1 {
+			return openapi.RemoveWorkspaceMember403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Workspace owners must transfer ownership before leaving")}, nil
+		}
+	}
+
+	removedChannelIDs, err := h.channelRepo.RemoveAllNonDMMemberships(ctx, tx, targetUserID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.workspaceRepo.RemoveMemberTx(ctx, tx, targetUserID, workspaceID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
+	event := sse.NewWorkspaceMemberRemovedEvent(openapi.WorkspaceMemberData{UserId: targetUserID, WorkspaceId: workspaceID})
+	if isSelf {
+		event = sse.NewMemberLeftEvent(openapi.WorkspaceMemberData{UserId: targetUserID, WorkspaceId: workspaceID})
+	}
+	h.broadcastMemberGone(ctx, workspaceID, targetUserID, event, removedChannelIDs)
+
 	// Audit log: only when an admin removes another user (not self-removal)
-	if request.Body.UserId != userID {
-		_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, string(request.Wid), userID, "member.removed", "user", request.Body.UserId, nil)
+	if !isSelf {
+		_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, workspaceID, userID, "member.removed", "user", targetUserID, nil)
 	}
 
 	return openapi.RemoveWorkspaceMember200JSONResponse{
@@ -291,27 +590,33 @@ func (h *Handler) LeaveWorkspace(ctx context.Context, request openapi.LeaveWorks
 		return nil, err
 	}
 
-	// Update SSE hub and broadcast events
-	if h.hub != nil {
-		for _, channelID := range removedChannelIDs {
-			// Broadcast before removing from hub so the leaving user's other
-			// sessions (if any) receive the removal event.
-			h.hub.BroadcastToChannel(workspaceID, channelID, sse.NewChannelMemberRemovedEvent(openapi.ChannelMemberData{
-				ChannelId: channelID,
-				UserId:    userID,
-			}))
-			h.hub.RemoveChannelMember(channelID, userID)
-		}
+	h.broadcastMemberGone(ctx, workspaceID, userID, sse.NewMemberLeftEvent(openapi.WorkspaceMemberData{
+		UserId:      userID,
+		WorkspaceId: workspaceID,
+	}), removedChannelIDs)
 
-		h.hub.BroadcastToWorkspace(workspaceID, sse.NewMemberLeftEvent(openapi.WorkspaceMemberData{
-			UserId:      userID,
-			WorkspaceId: workspaceID,
-		}))
+	return openapi.LeaveWorkspace200JSONResponse{Success: true}, nil
+}
 
-		h.hub.DisconnectUserClients(workspaceID, userID)
+// broadcastMemberGone updates the SSE hub after a user left or was removed
+// from the workspace: each channel they were dropped from hears about it,
+// then the workspace gets event and the user's streams are closed
+func (h *Handler) broadcastMemberGone(ctx context.Context, workspaceID, userID string, event sse.Event, channelIDs []string) {
+	if h.hub == nil {
+		return
+	}
+	for _, channelID := range channelIDs {
+		// Broadcast before removing from hub so the leaving user's other
+		// sessions (if any) receive the removal event.
+		h.hub.BroadcastToChannel(ctx, workspaceID, channelID, sse.NewChannelMemberRemovedEvent(openapi.ChannelMemberData{
+			ChannelId: channelID,
+			UserId:    userID,
+		}))
+		h.hub.RemoveChannelMember(channelID, userID)
 	}
 
-	return openapi.LeaveWorkspace200JSONResponse{Success: true}, nil
+	h.hub.BroadcastToWorkspace(ctx, workspaceID, event)
+	h.hub.DisconnectUserClients(workspaceID, userID)
 }
 
 // UpdateWorkspaceMemberRole updates a member's role
@@ -324,6 +629,9 @@ func (h *Handler) UpdateWorkspaceMemberRole(ctx context.Context, request openapi
 	// Check permissions
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UpdateWorkspaceMemberRole403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -353,6 +661,9 @@ func (h *Handler) UpdateWorkspaceMemberRole(ctx context.Context, request openapi
 
 	targetMembership, err := h.workspaceRepo.GetMembership(ctx, targetUserID, workspaceID)
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.UpdateWorkspaceMemberRole404JSONResponse{NotFoundJSONResponse: notFoundResponse("User is not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -396,7 +707,7 @@ func (h *Handler) UpdateWorkspaceMemberRole(ctx context.Context, request openapi
 
 	// SSE broadcast: role changed
 	if h.hub != nil {
-		h.hub.BroadcastToWorkspace(workspaceID, sse.NewMemberRoleChangedEvent(openapi.MemberRoleChangedData{
+		h.hub.BroadcastToWorkspace(ctx, workspaceID, sse.NewMemberRoleChangedEvent(openapi.MemberRoleChangedData{
 			UserId:  targetUserID,
 			OldRole: targetMembership.Role,
 			NewRole: newRole,
@@ -418,6 +729,9 @@ func (h *Handler) CreateWorkspaceInvite(ctx context.Context, request openapi.Cre
 	// Check permissions
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.CreateWorkspaceInvite403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -466,18 +780,128 @@ func (h *Handler) CreateWorkspaceInvite(ctx context.Context, request openapi.Cre
 		return nil, err
 	}
 
+	// Audit log: invite created
+	metadata := map[string]interface{}{"role": invite.Role}
+	if invite.InvitedEmail != nil {
+		metadata["invited_email"] = *invite.InvitedEmail
+	}
+	if invite.MaxUses != nil {
+		metadata["max_uses"] = *invite.MaxUses
+	}
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, invite.WorkspaceID, userID, moderation.ActionInviteCreated, moderation.TargetTypeInvite, invite.ID, metadata)
+
+	if invite.InvitedEmail != nil {
+		h.sendInviteEmail(ctx, ws, invite, userID)
+	}
+
 	apiInvite := inviteToAPI(invite)
 	return openapi.CreateWorkspaceInvite200JSONResponse{
 		Invite: apiInvite,
 	}, nil
 }
 
+// sendInviteEmail emails an email-bound invite's link to the invited address.
+// Delivery is best-effort and happens in the background.
+func (h *Handler) sendInviteEmail(ctx context.Context, ws *workspace.Workspace, invite *workspace.Invite, inviterID string) {
+	data := email.InviteEmailData{
+		WorkspaceName: ws.Name,
+		InviteURL:     h.emailService.GetPublicURL() + "/invites/" + url.PathEscape(invite.Code),
+	}
+	if inviter, err := h.userRepo.GetByID(ctx, inviterID); err == nil {
+		data.InviterName = inviter.DisplayName
+	}
+	to := *invite.InvitedEmail
+	go func() {
+		sendCtx, cancel := context.WithTimeout(logging.Detach(ctx), 30*time.Second)
+		defer cancel()
+		if err := h.emailService.SendWorkspaceInvite(sendCtx, to, data); err != nil {
+			slog.ErrorContext(sendCtx, "failed to send invite email", "invite_id", invite.ID, "error", err)
+		}
+	}()
+}
+
+// ListWorkspaceInvites lists a workspace's invites
+func (h *Handler) ListWorkspaceInvites(ctx context.Context, request openapi.ListWorkspaceInvitesRequestObject) (openapi.ListWorkspaceInvitesResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.ListWorkspaceInvites401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
+	if err != nil {
+		return openapi.ListWorkspaceInvites403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+	}
+
+	// Admins see every invite; anyone else allowed to invite sees their own
+	createdBy := ""
+	if !workspace.CanManageMembers(membership.Role) {
+		ws, err := h.workspaceRepo.GetByID(ctx, string(request.Wid))
+		if err != nil {
+			return nil, err
+		}
+		if !workspace.HasPermission(membership.Role, ws.ParsedSettings().WhoCanCreateInvites) {
+			return openapi.ListWorkspaceInvites403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Permission denied")}, nil
+		}
+		createdBy = userID
+	}
+
+	invites, err := h.workspaceRepo.ListInvites(ctx, string(request.Wid), createdBy)
+	if err != nil {
+		return nil, err
+	}
+	apiInvites := make([]openapi.Invite, len(invites))
+	for i := range invites {
+		apiInvites[i] = inviteToAPI(&invites[i])
+	}
+	return openapi.ListWorkspaceInvites200JSONResponse{Invites: apiInvites}, nil
+}
+
+// RevokeInvite deletes an invite so it can no longer be accepted
+func (h *Handler) RevokeInvite(ctx context.Context, request openapi.RevokeInviteRequestObject) (openapi.RevokeInviteResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.RevokeInvite401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	invite, err := h.workspaceRepo.GetInviteByID(ctx, request.Id)
+	if errors.Is(err, workspace.ErrInviteNotFound) {
+		return openapi.RevokeInvite404JSONResponse{NotFoundJSONResponse: notFoundResponse("Invite not found")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := h.workspaceRepo.GetMembership(ctx, userID, invite.WorkspaceID)
+	if err != nil {
+		return openapi.RevokeInvite404JSONResponse{NotFoundJSONResponse: notFoundResponse("Invite not found")}, nil
+	}
+	isCreator := invite.CreatedBy != nil && *invite.CreatedBy == userID
+	if !isCreator && !workspace.CanManageMembers(membership.Role) {
+		return openapi.RevokeInvite403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Only admins and the invite's creator can revoke it")}, nil
+	}
+
+	if err := h.workspaceRepo.DeleteInvite(ctx, invite.ID); err != nil {
+		if errors.Is(err, workspace.ErrInviteNotFound) {
+			return openapi.RevokeInvite404JSONResponse{NotFoundJSONResponse: notFoundResponse("Invite not found")}, nil
+		}
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{"role": invite.Role, "use_count": invite.UseCount}
+	if invite.InvitedEmail != nil {
+		metadata["invited_email"] = *invite.InvitedEmail
+	}
+	_ = h.moderationRepo.CreateAuditLogEntryWithMetadata(ctx, invite.WorkspaceID, userID, moderation.ActionInviteRevoked, moderation.TargetTypeInvite, invite.ID, metadata)
+
+	return openapi.RevokeInvite200JSONResponse{Success: true}, nil
+}
+
 // ReorderWorkspaces reorders workspaces for the current user
 func (h *Handler) ReorderWorkspaces(ctx context.Context, request openapi.ReorderWorkspacesRequestObject) (openapi.ReorderWorkspacesResponseObject, error) {
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.ReorderWorkspaces401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -511,29 +935,41 @@ func (h *Handler) AcceptInvite(ctx context.Context, request openapi.AcceptInvite
 		if ban != nil {
 			return openapi.AcceptInvite403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
 		}
+
+		// An email-bound invite proves nothing if the address is unverified,
+		// and without email it can't be verified, so such invites can only
+		// be redeemed by accounts already verified some other way
+		if invite.InvitedEmail != nil {
+			u, err := h.userRepo.GetByID(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			if u.EmailVerifiedAt == nil {
+				if !h.emailService.IsEnabled() {
+					return openapi.AcceptInvite403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Your email address must be verified to accept this invite, and this server can't send verification emails")}, nil
+				}
+				return openapi.AcceptInvite403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("Verify your email address to accept this invite")}, nil
+			}
+		}
 	}
 
 	ws, err := h.workspaceRepo.AcceptInvite(ctx, request.Code, userID)
-	if err != nil {
+	switch {
+	case errors.Is(err, workspace.ErrInviteNotFound):
+		return openapi.AcceptInvite404JSONResponse{NotFoundJSONResponse: notFoundResponse("Invite not found")}, nil
+	case errors.Is(err, workspace.ErrInviteExpired), errors.Is(err, workspace.ErrInviteMaxUsed):
+		return openapi.AcceptInvite400JSONResponse{BadRequestJSONResponse: badRequestResponse(ErrCodeValidationError, "This invite is no longer valid")}, nil
+	case errors.Is(err, workspace.ErrInviteEmailMismatch):
+		return openapi.AcceptInvite403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("This invite was sent to a different email address")}, nil
+	case err != nil:
 		return nil, err
 	}
 
-	// Add user to the default #general channel
-	defaultChannel, err := h.channelRepo.GetDefaultChannel(ctx, ws.ID)
-	if err == nil {
-		memberRole := channel.ChannelRolePoster
-		_, addErr := h.channelRepo.AddMember(ctx, userID, defaultChannel.ID, &memberRole)
-		if addErr == nil && h.hub != nil {
-			h.hub.AddChannelMember(defaultChannel.ID, userID)
-			h.hub.BroadcastToWorkspace(ws.ID, sse.NewChannelMemberAddedEvent(openapi.ChannelMemberData{
-				ChannelId: defaultChannel.ID,
-				UserId:    userID,
-			}))
-		}
+	var inviterID *string
+	if invite != nil {
+		inviterID = invite.CreatedBy
 	}
-
-	// Auto-create DMs with up to 5 existing members
-	h.autoCreateDMs(ctx, ws.ID, userID)
+	h.welcomeNewMember(ctx, ws, userID, inviterID)
 
 	apiWs := workspaceToAPI(ws)
 	return openapi.AcceptInvite200JSONResponse{
@@ -541,51 +977,250 @@ func (h *Handler) AcceptInvite(ctx context.Context, request openapi.AcceptInvite
 	}, nil
 }
 
-// autoCreateDMs creates DM channels between the joining user and up to 5
-// existing workspace members (earliest first). This is best-effort — errors
-// are logged but do not fail the join.
-func (h *Handler) autoCreateDMs(ctx context.Context, workspaceID, joiningUserID string) {
-	members, err := h.workspaceRepo.ListMembers(ctx, workspaceID)
+// welcomeNewMember adds a user who just joined the workspace to the default
+// and auto-join channels and opens DMs under the auto-DM policy
+func (h *Handler) welcomeNewMember(ctx context.Context, ws *workspace.Workspace, userID string, inviterID *string) {
+	if h.hub != nil {
+		h.hub.BroadcastToWorkspace(ctx, ws.ID, sse.NewWorkspaceMemberAddedEvent(openapi.WorkspaceMemberData{
+			UserId:      userID,
+			WorkspaceId: ws.ID,
+		}))
+	}
+
+	// Add user to the default #general channel and every auto-join channel
+	autoJoin, err := h.channelRepo.ListAutoJoinChannels(ctx, ws.ID)
+	if err != nil {
+		slog.Error("failed to list auto-join channels", "workspace_id", ws.ID, "error", err)
+	}
+	for i := range autoJoin {
+		memberRole := channel.ChannelRolePoster
+		if _, err := h.channelRepo.AddMember(ctx, userID, autoJoin[i].ID, &memberRole); err == nil {
+			h.broadcastChannelMemberAdded(ctx, &autoJoin[i], userID)
+		}
+	}
+
+	// Open DMs according to the workspace's auto-DM policy
+	h.autoCreateDMs(ctx, ws, userID, inviterID)
+}
+
+// SignupRateLimitPath is the rule path the open signup limiter must be
+// configured with. The limiter is keyed by workspace ID.
+const SignupRateLimitPath = "/api/workspaces/join"
+
+// canSignup reports whether a user may join the workspace through open
+// signup, with the reason when they may not
+func (h *Handler) canSignup(ctx context.Context, settings workspace.WorkspaceSettings, userID string) (bool, string, error) {
+	u, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, "", err
+	}
+	if !settings.AllowsSignup(u.Email) {
+		return false, "Your email domain is not allowed to join this workspace", nil
+	}
+	// The domain only vouches for the user once they've proven they own the
+	// address. Without email the address can't be proven, so signup is closed.
+	if u.EmailVerifiedAt == nil {
+		if !h.emailService.IsEnabled() {
+			return false, "Your email address must be verified to join this workspace, and this server can't send verification emails", nil
+		}
+		return false, "Verify your email address to join this workspace", nil
+	}
+	return true, "", nil
+}
+
+// GetWorkspaceSignup describes a workspace's open signup link
+func (h *Handler) GetWorkspaceSignup(ctx context.Context, request openapi.GetWorkspaceSignupRequestObject) (openapi.GetWorkspaceSignupResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.GetWorkspaceSignup401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ws, err := h.workspaceRepo.GetByID(ctx, string(request.Wid))
+	if errors.Is(err, workspace.ErrWorkspaceNotFound) {
+		return openapi.GetWorkspaceSignup404JSONResponse{NotFoundJSONResponse: notFoundResponse("Workspace not found")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	settings := ws.ParsedSettings()
+	if !settings.OpenSignup {
+		return openapi.GetWorkspaceSignup404JSONResponse{NotFoundJSONResponse: notFoundResponse("Workspace not found")}, nil
+	}
+
+	_, memberErr := h.workspaceRepo.GetMembership(ctx, userID, ws.ID)
+	canJoin, _, err := h.canSignup(ctx, settings, userID)
 	if err != nil {
+		return nil, err
+	}
+	return openapi.GetWorkspaceSignup200JSONResponse{
+		WorkspaceId:    ws.ID,
+		Name:           ws.Name,
+		IconUrl:        ws.IconURL,
+		AllowedDomains: settings.OpenSignupDomains,
+		IsMember:       memberErr == nil,
+		CanJoin:        canJoin,
+	}, nil
+}
+
+// JoinWorkspace adds the caller to a workspace through open signup
+func (h *Handler) JoinWorkspace(ctx context.Context, request openapi.JoinWorkspaceRequestObject) (openapi.JoinWorkspaceResponseObject, error) {
+	userID := h.getUserID(ctx)
+	if userID == "" {
+		return openapi.JoinWorkspace401JSONResponse{UnauthorizedJSONResponse: unauthorizedResponse()}, nil
+	}
+
+	ws, err := h.workspaceRepo.GetByID(ctx, string(request.Wid))
+	if errors.Is(err, workspace.ErrWorkspaceNotFound) {
+		return openapi.JoinWorkspace404JSONResponse{NotFoundJSONResponse: notFoundResponse("Workspace not found")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	settings := ws.ParsedSettings()
+	if !settings.OpenSignup {
+		return openapi.JoinWorkspace404JSONResponse{NotFoundJSONResponse: notFoundResponse("Workspace not found")}, nil
+	}
+
+	// Joining again is a no-op, so a stale join link does no harm
+	if _, err := h.workspaceRepo.GetMembership(ctx, userID, ws.ID); err == nil {
+		return openapi.JoinWorkspace200JSONResponse{Workspace: workspaceToAPI(ws)}, nil
+	}
+
+	if ban, _ := h.moderationRepo.GetActiveBan(ctx, ws.ID, userID); ban != nil {
+		return openapi.JoinWorkspace403JSONResponse{ForbiddenJSONResponse: forbiddenResponse("You are banned from this workspace")}, nil
+	}
+	ok, reason, err := h.canSignup(ctx, settings, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return openapi.JoinWorkspace403JSONResponse{ForbiddenJSONResponse: forbiddenResponse(reason)}, nil
+	}
+
+	if h.signupLimiter != nil {
+		result, allowed := h.signupLimiter.Allow(ws.ID, "POST", SignupRateLimitPath)
+		if !allowed {
+			retryAfter := int(math.Ceil(result.RetryIn.Seconds()))
+			return openapi.JoinWorkspace429JSONResponse{TooManyRequestsJSONResponse: tooManyRequestsResponse(retryAfter)}, nil
+		}
+	}
+
+	if _, err := h.workspaceRepo.AddMember(ctx, userID, ws.ID, workspace.RoleMember); err != nil && !errors.Is(err, workspace.ErrMembershipExists) {
+		return nil, err
+	}
+	h.welcomeNewMember(ctx, ws, userID, nil)
+
+	return openapi.JoinWorkspace200JSONResponse{Workspace: workspaceToAPI(ws)}, nil
+}
+
+// maxAutoDMs caps how many DMs are opened for a joining member
+const maxAutoDMs = 5
+
+// autoDMPartner is a member picked by the auto-DM policy, with the reason
+// shown in the welcome message
+type autoDMPartner struct {
+	member workspace.MemberWithUser
+	reason string
+}
+
+// autoCreateDMs opens DMs between the joining user and the members picked by
+// the workspace's auto-DM policy, posting a welcome message in each new
+// conversation. This is best-effort — errors do not fail the join.
+func (h *Handler) autoCreateDMs(ctx context.Context, ws *workspace.Workspace, joiningUserID string, inviterID *string) {
+	policy := ws.ParsedSettings().AutoDMPolicy
+	if policy == workspace.AutoDMNone {
 		return
 	}
 
-	const maxDMs = 5
-	created := 0
+	members, err := h.workspaceRepo.ListMembers(ctx, ws.ID)
+	if err != nil {
+		return
+	}
+
+	joiningName := ""
 	for _, m := range members {
-		if created >= maxDMs {
+		if m.UserID == joiningUserID {
+			joiningName = m.DisplayName
 			break
 		}
-		if m.UserID == joiningUserID {
+	}
+
+	created := 0
+	for _, p := range selectAutoDMPartners(policy, members, joiningUserID, inviterID) {
+		participants := []string{joiningUserID, p.member.UserID}
+
+		// Rejoining members keep their old conversations without a second welcome
+		if _, err := h.channelRepo.GetDM(ctx, ws.ID, participants); err == nil {
 			continue
 		}
 
-		dm, err := h.channelRepo.CreateDM(ctx, workspaceID, []string{joiningUserID, m.UserID})
+		dm, err := h.channelRepo.CreateDM(ctx, ws.ID, participants)
 		if err != nil {
 			continue
 		}
 		if h.hub != nil {
 			h.hub.AddChannelMember(dm.ID, joiningUserID)
-			h.hub.AddChannelMember(dm.ID, m.UserID)
+			h.hub.AddChannelMember(dm.ID, p.member.UserID)
 		}
+
+		reason := p.reason
+		_, _ = h.messageRepo.CreateSystemMessage(ctx, dm.ID, &message.SystemEventData{
+			EventType:        message.SystemEventDMWelcome,
+			UserID:           joiningUserID,
+			UserDisplayName:  joiningName,
+			ChannelName:      ws.Name,
+			ActorID:          &p.member.UserID,
+			ActorDisplayName: &p.member.DisplayName,
+			WelcomeReason:    &reason,
+		})
 		created++
 	}
 
 	// Single broadcast so all connected clients refetch their channel list
 	if created > 0 && h.hub != nil {
-		h.hub.BroadcastToWorkspace(workspaceID, sse.NewChannelsInvalidateEvent())
+		h.hub.BroadcastToWorkspace(ctx, ws.ID, sse.NewChannelsInvalidateEvent())
+	}
+}
+
+// selectAutoDMPartners picks the members to open DMs with under the given
+// policy. Bots, deactivated and banned members are never picked.
+func selectAutoDMPartners(policy workspace.AutoDMPolicy, members []workspace.MemberWithUser, joiningUserID string, inviterID *string) []autoDMPartner {
+	var partners []autoDMPartner
+	for _, m := range members {
+		if len(partners) >= maxAutoDMs {
+			break
+		}
+		if m.UserID == joiningUserID || m.IsBot || m.IsDeactivated || m.IsBanned {
+			continue
+		}
+
+		switch policy {
+		case workspace.AutoDMInviter:
+			if inviterID != nil && m.UserID == *inviterID {
+				partners = append(partners, autoDMPartner{member: m, reason: message.WelcomeReasonInviter})
+			}
+		case workspace.AutoDMAdmins:
+			if workspace.CanManageMembers(m.Role) {
+				partners = append(partners, autoDMPartner{member: m, reason: message.WelcomeReasonAdmin})
+			}
+		case workspace.AutoDMEarliestMembers:
+			partners = append(partners, autoDMPartner{member: m, reason: message.WelcomeReasonMember})
+		}
 	}
+	return partners
 }
 
 // workspaceToAPI converts a workspace.Workspace to openapi.Workspace
 func workspaceToAPI(ws *workspace.Workspace) openapi.Workspace {
 	apiWs := openapi.Workspace{
-		Id:        ws.ID,
-		Name:      ws.Name,
-		IconUrl:   ws.IconURL,
-		Settings:  ws.Settings,
-		CreatedAt: ws.CreatedAt,
-		UpdatedAt: ws.UpdatedAt,
+		Id:              ws.ID,
+		Name:            ws.Name,
+		IconUrl:         ws.IconURL,
+		Settings:        ws.Settings,
+		ReadOnly:        ws.ReadOnly,
+		ReadOnlyMessage: ws.ReadOnlyMessage,
+		CreatedAt:       ws.CreatedAt,
+		UpdatedAt:       ws.UpdatedAt,
 	}
 
 	// Add parsed settings
@@ -594,12 +1229,33 @@ func workspaceToAPI(ws *workspace.Workspace) openapi.Workspace {
 	whoCanCreateInvites := openapi.PermissionLevel(settings.WhoCanCreateInvites)
 	whoCanPinMessages := openapi.PermissionLevel(settings.WhoCanPinMessages)
 	whoCanManageCustomEmoji := openapi.PermissionLevel(settings.WhoCanManageCustomEmoji)
+	whoCanMentionChannel := openapi.PermissionLevel(settings.WhoCanMentionChannel)
+	autoDMPolicy := openapi.AutoDMPolicy(settings.AutoDMPolicy)
 	apiWs.ParsedSettings = &openapi.WorkspaceSettings{
 		ShowJoinLeaveMessages:   &settings.ShowJoinLeaveMessages,
 		WhoCanCreateChannels:    &whoCanCreateChannels,
 		WhoCanCreateInvites:     &whoCanCreateInvites,
 		WhoCanPinMessages:       &whoCanPinMessages,
 		WhoCanManageCustomEmoji: &whoCanManageCustomEmoji,
+		WhoCanMentionChannel:    &whoCanMentionChannel,
+		DmReadReceipts:          &settings.DMReadReceipts,
+		AutoDmPolicy:            &autoDMPolicy,
+		LinkPreviews:            &settings.LinkPreviews,
+		AttachmentRetentionDays: &settings.AttachmentRetentionDays,
+		MessageRetentionDays:    &settings.MessageRetentionDays,
+		OpenSignup:              &settings.OpenSignup,
+	}
+	if len(settings.OpenSignupDomains) > 0 {
+		apiWs.ParsedSettings.OpenSignupDomains = &settings.OpenSignupDomains
+	}
+	if len(settings.UploadAllowedTypes) > 0 {
+		apiWs.ParsedSettings.UploadAllowedTypes = &settings.UploadAllowedTypes
+	}
+	if len(settings.UploadBlockedTypes) > 0 {
+		apiWs.ParsedSettings.UploadBlockedTypes = &settings.UploadBlockedTypes
+	}
+	if settings.DefaultNotificationSound != "" {
+		apiWs.ParsedSettings.DefaultNotificationSound = &settings.DefaultNotificationSound
 	}
 
 	return apiWs
@@ -618,7 +1274,15 @@ func memberWithUserToAPI(m workspace.MemberWithUser) openapi.WorkspaceMemberWith
 		Email:               openapi_types.Email(m.Email),
 		DisplayName:         m.DisplayName,
 		AvatarUrl:           m.AvatarURL,
+		Title:               m.Title,
+		Pronouns:            m.Pronouns,
+		Timezone:            m.Timezone,
 		IsBanned:            &m.IsBanned,
+		IsDeactivated:       &m.IsDeactivated,
+		IsBot:               &m.IsBot,
+	}
+	if len(m.ProfileFields) > 0 {
+		member.ProfileFields = &m.ProfileFields
 	}
 	if g := gravatar.URL(m.Email); g != "" {
 		member.GravatarUrl = &g
@@ -661,7 +1325,7 @@ func (h *Handler) UploadWorkspaceIcon(ctx context.Context, request openapi.Uploa
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.UploadWorkspaceIcon401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -671,7 +1335,7 @@ func (h *Handler) UploadWorkspaceIcon(ctx context.Context, request openapi.Uploa
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
 	if err != nil {
 		return openapi.UploadWorkspaceIcon401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not a workspace member")),
+			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAMember, "Not a workspace member")),
 		}, nil
 	}
 
@@ -705,7 +1369,7 @@ func (h *Handler) UploadWorkspaceIcon(ctx context.Context, request openapi.Uploa
 
 	if h.storage == nil {
 		return openapi.UploadWorkspaceIcon403JSONResponse{
-			ForbiddenJSONResponse: openapi.ForbiddenJSONResponse(newErrorResponse(ErrCodeFilesDisabled, "File uploads are disabled")),
+			ForbiddenJSONResponse: filesDisabledResponse(),
 		}, nil
 	}
 
@@ -755,7 +1419,7 @@ func (h *Handler) DeleteWorkspaceIcon(ctx context.Context, request openapi.Delet
 	userID := h.getUserID(ctx)
 	if userID == "" {
 		return openapi.DeleteWorkspaceIcon401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not authenticated")),
+			UnauthorizedJSONResponse: unauthorizedResponse(),
 		}, nil
 	}
 
@@ -765,7 +1429,7 @@ func (h *Handler) DeleteWorkspaceIcon(ctx context.Context, request openapi.Delet
 	membership, err := h.workspaceRepo.GetMembership(ctx, userID, workspaceID)
 	if err != nil {
 		return openapi.DeleteWorkspaceIcon401JSONResponse{
-			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAuthenticated, "Not a workspace member")),
+			UnauthorizedJSONResponse: openapi.UnauthorizedJSONResponse(newErrorResponse(ErrCodeNotAMember, "Not a workspace member")),
 		}, nil
 	}
 
@@ -829,13 +1493,28 @@ func (h *Handler) GetWorkspaceNotifications(ctx context.Context, request openapi
 		return nil, err
 	}
 
+	threadCounts, err := h.threadRepo.CountUnreadThreadsByWorkspace(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	apiSummaries := make([]openapi.WorkspaceNotificationSummary, len(summaries))
 	for i, s := range summaries {
 		apiSummaries[i] = openapi.WorkspaceNotificationSummary{
 			WorkspaceId:       s.WorkspaceID,
 			UnreadCount:       s.UnreadCount,
 			NotificationCount: s.NotificationCount,
+			UnreadThreadCount: threadCounts[s.WorkspaceID],
 		}
+		delete(threadCounts, s.WorkspaceID)
+	}
+	// Threads can be followed in public channels the user hasn't joined, so a
+	// workspace may have unread threads without any channel memberships.
+	for _, wsID := range slices.Sorted(maps.Keys(threadCounts)) {
+		apiSummaries = append(apiSummaries, openapi.WorkspaceNotificationSummary{
+			WorkspaceId:       wsID,
+			UnreadThreadCount: threadCounts[wsID],
+		})
 	}
 
 	return openapi.GetWorkspaceNotifications200JSONResponse{
@@ -853,6 +1532,9 @@ func (h *Handler) ListAllUnreads(ctx context.Context, request openapi.ListAllUnr
 	// Check workspace membership
 	_, err := h.workspaceRepo.GetMembership(ctx, userID, string(request.Wid))
 	if err != nil {
+		if errors.Is(err, workspace.ErrNotAMember) {
+			return openapi.ListAllUnreads403JSONResponse{ForbiddenJSONResponse: notAMemberResponse("Not a member of this workspace")}, nil
+		}
 		return nil, err
 	}
 
@@ -874,25 +1556,49 @@ func (h *Handler) ListAllUnreads(ctx context.Context, request openapi.ListAllUnr
 		return nil, err
 	}
 
-	return openapi.ListAllUnreads200JSONResponse(unreadListResultToAPI(result)), nil
+	apiResult := unreadListResultToAPI(result)
+	h.truncateUnreadMessages(apiResult.Messages)
+	return openapi.ListAllUnreads200JSONResponse(apiResult), nil
+}
+
+// GetAllUnreads is ListAllUnreads with the options in the query string
+func (h *Handler) GetAllUnreads(ctx context.Context, request openapi.GetAllUnreadsRequestObject) (openapi.GetAllUnreadsResponseObject, error) {
+	resp, err := h.ListAllUnreads(ctx, openapi.ListAllUnreadsRequestObject{
+		Wid: request.Wid,
+		Body: &openapi.ListAllUnreadsJSONRequestBody{
+			Cursor: request.Params.Cursor,
+			Limit:  request.Params.Limit,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch r := resp.(type) {
+	case openapi.ListAllUnreads200JSONResponse:
+		return openapi.GetAllUnreads200JSONResponse(r), nil
+	case openapi.ListAllUnreads401JSONResponse:
+		return openapi.GetAllUnreads401JSONResponse(r), nil
+	}
+	return nil, fmt.Errorf("unexpected ListAllUnreads response %T", resp)
 }
 
 // unreadMessageToAPI converts a message.UnreadMessage to openapi.UnreadMessage
 func unreadMessageToAPI(m *message.UnreadMessage) openapi.UnreadMessage {
 	apiMsg := openapi.UnreadMessage{
-		Id:             m.ID,
-		ChannelId:      m.ChannelID,
-		UserId:         m.UserID,
-		Content:        m.Content,
-		ThreadParentId: m.ThreadParentID,
-		ReplyCount:     m.ReplyCount,
-		LastReplyAt:    m.LastReplyAt,
-		EditedAt:       m.EditedAt,
-		DeletedAt:      m.DeletedAt,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
-		ChannelName:    m.ChannelName,
-		ChannelType:    openapi.ChannelType(m.ChannelType),
+		Id:              m.ID,
+		ChannelId:       m.ChannelID,
+		UserId:          m.UserID,
+		Content:         m.Content,
+		ContentRendered: renderContent(m.Content, m.DeletedAt),
+		ThreadParentId:  m.ThreadParentID,
+		ReplyCount:      m.ReplyCount,
+		LastReplyAt:     m.LastReplyAt,
+		EditedAt:        m.EditedAt,
+		DeletedAt:       m.DeletedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		ChannelName:     m.ChannelName,
+		ChannelType:     openapi.ChannelType(m.ChannelType),
 	}
 	if m.UserDisplayName != "" {
 		apiMsg.UserDisplayName = &m.UserDisplayName
@@ -900,6 +1606,12 @@ func unreadMessageToAPI(m *message.UnreadMessage) openapi.UnreadMessage {
 	if m.UserAvatarURL != nil {
 		apiMsg.UserAvatarUrl = m.UserAvatarURL
 	}
+	if m.UserIsDeactivated {
+		apiMsg.UserIsDeactivated = &m.UserIsDeactivated
+	}
+	if m.IsBot {
+		apiMsg.IsBot = &m.IsBot
+	}
 	if g := gravatar.URL(m.UserEmail); g != "" {
 		apiMsg.UserGravatarUrl = &g
 	}
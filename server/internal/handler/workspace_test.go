@@ -2,10 +2,23 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/email"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/message"
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/ratelimit"
+	"github.com/enzyme/server/internal/sse"
 	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 func TestCreateWorkspace_Success(t *testing.T) {
@@ -153,8 +166,136 @@ func TestListWorkspaceMembers_Success(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected 200 response, got %T", resp)
 	}
-	if len(r.Members) < 2 {
-		t.Fatalf("expected at least 2 members, got %d", len(r.Members))
+	if len(r.Body.Members) < 2 {
+		t.Fatalf("expected at least 2 members, got %d", len(r.Body.Members))
+	}
+}
+
+func TestListWorkspaceMembers_ETag(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+	ctx := ctxWithUser(t, h, owner.ID)
+	list := func(ifNoneMatch *string) openapi.ListWorkspaceMembersResponseObject {
+		t.Helper()
+		resp, err := h.ListWorkspaceMembers(ctx, openapi.ListWorkspaceMembersRequestObject{
+			Wid:    ws.ID,
+			Params: openapi.ListWorkspaceMembersParams{IfNoneMatch: ifNoneMatch},
+		})
+		if err != nil {
+			t.Fatalf("ListWorkspaceMembers: %v", err)
+		}
+		return resp
+	}
+
+	first, ok := list(nil).(openapi.ListWorkspaceMembers200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 response")
+	}
+	etag := first.Headers.ETag
+	if _, ok := list(&etag).(openapi.ListWorkspaceMembers304Response); !ok {
+		t.Fatal("expected 304 for an unchanged ETag")
+	}
+
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	second, ok := list(&etag).(openapi.ListWorkspaceMembers200JSONResponse)
+	if !ok {
+		t.Fatal("expected 200 after a member joined")
+	}
+	if second.Headers.ETag == etag {
+		t.Error("expected a new ETag after a member joined")
+	}
+
+	// Banning a member changes the ETag too
+	etag = second.Headers.ETag
+	if _, err := db.Exec("INSERT INTO workspace_bans (id, workspace_id, user_id, banned_by) VALUES ('ban-1', ?, ?, ?)", ws.ID, member.ID, owner.ID); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if _, ok := list(&etag).(openapi.ListWorkspaceMembers200JSONResponse); !ok {
+		t.Error("expected 200 after a member was banned")
+	}
+}
+
+func TestListWorkspaceMembers_Paging(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Zed")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	var memberID string
+	for _, name := range []string{"Cal", "Ada", "Bea"} {
+		u := testutil.CreateTestUser(t, db, strings.ToLower(name)+"@test.com", name)
+		addWorkspaceMember(t, db, u.ID, ws.ID, "member")
+		memberID = u.ID
+	}
+	gone := testutil.CreateTestUser(t, db, "gone@test.com", "Gone")
+	addWorkspaceMember(t, db, gone.ID, ws.ID, "member")
+	if _, err := db.Exec(`UPDATE users SET status = 'deactivated' WHERE id = ?`, gone.ID); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+
+	list := func(userID string, params openapi.ListWorkspaceMembersParams) openapi.ListWorkspaceMembersResponseObject {
+		t.Helper()
+		resp, err := h.ListWorkspaceMembers(ctxWithUser(t, h, userID), openapi.ListWorkspaceMembersRequestObject{Wid: ws.ID, Params: params})
+		if err != nil {
+			t.Fatalf("ListWorkspaceMembers: %v", err)
+		}
+		return resp
+	}
+	names := func(members []openapi.WorkspaceMemberWithUser) string {
+		var out []string
+		for _, m := range members {
+			out = append(out, m.DisplayName)
+		}
+		return strings.Join(out, ",")
+	}
+
+	// Pages in name order, leaving out the deactivated account
+	sort := openapi.WorkspaceMemberSortName
+	limit := 3
+	first, ok := list(owner.ID, openapi.ListWorkspaceMembersParams{Sort: &sort, Limit: &limit}).(openapi.ListWorkspaceMembers200JSONResponse)
+	if !ok || !first.Body.HasMore || first.Body.NextCursor == nil {
+		t.Fatalf("expected a first page with more to come, got %+v", first.Body)
+	}
+	second, ok := list(owner.ID, openapi.ListWorkspaceMembersParams{Sort: &sort, Limit: &limit, Cursor: first.Body.NextCursor}).(openapi.ListWorkspaceMembers200JSONResponse)
+	if !ok || second.Body.HasMore {
+		t.Fatalf("expected a last page, got %+v", second.Body)
+	}
+	if got := names(first.Body.Members) + "|" + names(second.Body.Members); got != "Ada,Bea,Cal|Zed" {
+		t.Errorf("pages = %s, want Ada,Bea,Cal|Zed", got)
+	}
+
+	q := "BEA@"
+	role := openapi.WorkspaceRoleOwner
+	if r := list(owner.ID, openapi.ListWorkspaceMembersParams{Q: &q}).(openapi.ListWorkspaceMembers200JSONResponse); names(r.Body.Members) != "Bea" {
+		t.Errorf("search = %s, want Bea", names(r.Body.Members))
+	}
+	if r := list(owner.ID, openapi.ListWorkspaceMembersParams{Role: &role}).(openapi.ListWorkspaceMembers200JSONResponse); names(r.Body.Members) != "Zed" {
+		t.Errorf("owners = %s, want Zed", names(r.Body.Members))
+	}
+
+	// Only admins may include deactivated accounts
+	include := true
+	if _, ok := list(memberID, openapi.ListWorkspaceMembersParams{IncludeDeactivated: &include}).(openapi.ListWorkspaceMembers403JSONResponse); !ok {
+		t.Error("expected 403 for a member asking for deactivated accounts")
+	}
+	if r := list(owner.ID, openapi.ListWorkspaceMembersParams{IncludeDeactivated: &include}).(openapi.ListWorkspaceMembers200JSONResponse); len(r.Body.Members) != 5 {
+		t.Errorf("got %d members with deactivated accounts, want 5", len(r.Body.Members))
+	}
+
+	bogus := "missing"
+	if _, ok := list(owner.ID, openapi.ListWorkspaceMembersParams{Cursor: &bogus}).(openapi.ListWorkspaceMembers400JSONResponse); !ok {
+		t.Error("expected 400 for an unknown cursor")
+	}
+
+	count, err := h.CountWorkspaceMembers(ctxWithUser(t, h, memberID), openapi.CountWorkspaceMembersRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("CountWorkspaceMembers: %v", err)
+	}
+	if c := count.(openapi.CountWorkspaceMembers200JSONResponse).Count; c != 4 {
+		t.Errorf("count = %d, want 4", c)
 	}
 }
 
@@ -200,6 +341,80 @@ func TestRemoveWorkspaceMember_AdminRemovesOther(t *testing.T) {
 	}
 }
 
+func TestRemoveWorkspaceMember_Broadcasts(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+
+	resp, err := h.RemoveWorkspaceMember(ctxWithUser(t, h, owner.ID), openapi.RemoveWorkspaceMemberRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.RemoveWorkspaceMemberJSONRequestBody{UserId: member.ID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RemoveWorkspaceMember200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	expectSSEEvent(t, client, sse.EventWorkspaceMemberRemoved)
+}
+
+func TestRemoveWorkspaceMember_DropsChannelMemberships(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	addChannelMember(t, db, member.ID, ch.ID, nil)
+
+	client := connectSSEClient(t, h, ws.ID, owner.ID)
+
+	resp, err := h.RemoveWorkspaceMember(ctxWithUser(t, h, owner.ID), openapi.RemoveWorkspaceMemberRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.RemoveWorkspaceMemberJSONRequestBody{UserId: member.ID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RemoveWorkspaceMember200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	expectSSEEvent(t, client, sse.EventMemberRemoved)
+	expectSSEEvent(t, client, sse.EventWorkspaceMemberRemoved)
+
+	if _, err := h.channelRepo.GetMembership(context.Background(), member.ID, ch.ID); !errors.Is(err, channel.ErrNotChannelMember) {
+		t.Errorf("GetMembership error = %v, want the channel membership removed", err)
+	}
+}
+
+func TestRemoveWorkspaceMember_LastOwnerForbidden(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+	resp, err := h.RemoveWorkspaceMember(ctxWithUser(t, h, owner.ID), openapi.RemoveWorkspaceMemberRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.RemoveWorkspaceMemberJSONRequestBody{UserId: owner.ID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.RemoveWorkspaceMember403JSONResponse); !ok {
+		t.Fatalf("expected 403 response, got %T", resp)
+	}
+	if _, err := h.workspaceRepo.GetMembership(context.Background(), owner.ID, ws.ID); err != nil {
+		t.Errorf("GetMembership error = %v, want the owner to stay a member", err)
+	}
+}
+
 func TestRemoveWorkspaceMember_MemberCannotRemoveOther(t *testing.T) {
 	h, db := testHandler(t)
 
@@ -492,6 +707,59 @@ func TestGetWorkspaceNotifications_Success(t *testing.T) {
 	}
 }
 
+func TestGetWorkspaceNotifications_UnreadThreads(t *testing.T) {
+	h, db := testHandler(t)
+
+	user := testutil.CreateTestUser(t, db, "user@test.com", "User")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "WS")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", "public")
+	addChannelMember(t, db, other.ID, ch.ID, nil)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, user.ID, "Thread parent")
+
+	// A reply from someone else subscribes the parent's author
+	content := "reply"
+	if _, err := h.SendMessage(ctxWithUser(t, h, other.ID), openapi.SendMessageRequestObject{
+		Id:   ch.ID,
+		Body: &openapi.SendMessageJSONRequestBody{Content: &content, ThreadParentId: &parent.ID},
+	}); err != nil {
+		t.Fatalf("sending reply: %v", err)
+	}
+
+	ctx := ctxWithUser(t, h, user.ID)
+	resp, err := h.GetWorkspaceNotifications(ctx, openapi.GetWorkspaceNotificationsRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := resp.(openapi.GetWorkspaceNotifications200JSONResponse)
+	if !ok || len(r.Workspaces) != 1 {
+		t.Fatalf("expected one workspace summary, got %#v", resp)
+	}
+	if got := r.Workspaces[0].UnreadThreadCount; got != 1 {
+		t.Errorf("unread_thread_count = %d, want 1", got)
+	}
+
+	listResp, err := h.ListChannels(ctx, openapi.ListChannelsRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("listing channels: %v", err)
+	}
+	if got := listResp.(openapi.ListChannels200JSONResponse).Body.UnreadThreadCount; got != 1 {
+		t.Errorf("channel list unread_thread_count = %d, want 1", got)
+	}
+
+	if _, err := h.MarkThreadRead(ctx, openapi.MarkThreadReadRequestObject{Id: parent.ID}); err != nil {
+		t.Fatalf("marking thread read: %v", err)
+	}
+	resp, err = h.GetWorkspaceNotifications(ctx, openapi.GetWorkspaceNotificationsRequestObject{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.(openapi.GetWorkspaceNotifications200JSONResponse).Workspaces[0].UnreadThreadCount; got != 0 {
+		t.Errorf("unread_thread_count after mark read = %d, want 0", got)
+	}
+}
+
 func TestGetWorkspaceNotifications_Unauthenticated(t *testing.T) {
 	h, _ := testHandler(t)
 	ctx := context.Background()
@@ -635,3 +903,407 @@ func TestCreateWorkspaceInvite_NotAdmin(t *testing.T) {
 		t.Fatalf("expected 403 response, got %T", resp)
 	}
 }
+
+// setAutoDMPolicy stores the workspace's auto-DM policy.
+func setAutoDMPolicy(t *testing.T, h *Handler, workspaceID string, policy workspace.AutoDMPolicy) {
+	t.Helper()
+
+	ws, err := h.workspaceRepo.GetByID(context.Background(), workspaceID)
+	if err != nil {
+		t.Fatalf("getting workspace: %v", err)
+	}
+	settings := ws.ParsedSettings()
+	settings.AutoDMPolicy = policy
+	ws.Settings = settings.ToJSON()
+	if err := h.workspaceRepo.Update(context.Background(), ws); err != nil {
+		t.Fatalf("updating workspace: %v", err)
+	}
+}
+
+// createInvite creates a member invite from the given user.
+func createInvite(t *testing.T, h *Handler, workspaceID, createdBy string) string {
+	t.Helper()
+
+	invite := &workspace.Invite{WorkspaceID: workspaceID, Role: workspace.RoleMember, CreatedBy: &createdBy}
+	if err := h.workspaceRepo.CreateInvite(context.Background(), invite); err != nil {
+		t.Fatalf("creating invite: %v", err)
+	}
+	return invite.Code
+}
+
+func TestInviteManagement(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@test.com", "Member")
+	invited := testutil.CreateTestUser(t, db, "invited@test.com", "Invited")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+	addWorkspaceMember(t, db, member.ID, ws.ID, "member")
+	verifyEmail(t, db, member.ID)
+
+	ownerCtx := ctxWithUser(t, h, owner.ID)
+	memberCtx := ctxWithUser(t, h, member.ID)
+	email := openapi_types.Email("invited@test.com")
+	createResp, err := h.CreateWorkspaceInvite(ownerCtx, openapi.CreateWorkspaceInviteRequestObject{
+		Wid:  ws.ID,
+		Body: &openapi.CreateWorkspaceInviteJSONRequestBody{Role: openapi.WorkspaceRoleMember, InvitedEmail: &email},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkspaceInvite: %v", err)
+	}
+	emailInvite := createResp.(openapi.CreateWorkspaceInvite200JSONResponse).Invite
+	createInvite(t, h, ws.ID, owner.ID)
+
+	// Invites are an admin tool under the default settings
+	listResp, err := h.ListWorkspaceInvites(memberCtx, openapi.ListWorkspaceInvitesRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListWorkspaceInvites: %v", err)
+	}
+	if _, ok := listResp.(openapi.ListWorkspaceInvites403JSONResponse); !ok {
+		t.Errorf("member list: expected 403 response, got %T", listResp)
+	}
+	listResp, err = h.ListWorkspaceInvites(ownerCtx, openapi.ListWorkspaceInvitesRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("ListWorkspaceInvites: %v", err)
+	}
+	if r, ok := listResp.(openapi.ListWorkspaceInvites200JSONResponse); !ok || len(r.Invites) != 2 {
+		t.Fatalf("owner list = %#v, want 2 invites", listResp)
+	}
+
+	// Only the invited address can use an email-bound invite
+	acceptResp, err := h.AcceptInvite(memberCtx, openapi.AcceptInviteRequestObject{Code: emailInvite.Code})
+	if err != nil {
+		t.Fatalf("AcceptInvite: %v", err)
+	}
+	if _, ok := acceptResp.(openapi.AcceptInvite403JSONResponse); !ok {
+		t.Errorf("accept by other address: expected 403 response, got %T", acceptResp)
+	}
+
+	revokeResp, err := h.RevokeInvite(memberCtx, openapi.RevokeInviteRequestObject{Id: emailInvite.Id})
+	if err != nil {
+		t.Fatalf("RevokeInvite: %v", err)
+	}
+	if _, ok := revokeResp.(openapi.RevokeInvite403JSONResponse); !ok {
+		t.Errorf("member revoke: expected 403 response, got %T", revokeResp)
+	}
+	revokeResp, err = h.RevokeInvite(ownerCtx, openapi.RevokeInviteRequestObject{Id: emailInvite.Id})
+	if err != nil {
+		t.Fatalf("RevokeInvite: %v", err)
+	}
+	if _, ok := revokeResp.(openapi.RevokeInvite200JSONResponse); !ok {
+		t.Fatalf("owner revoke: expected 200 response, got %T", revokeResp)
+	}
+
+	acceptResp, err = h.AcceptInvite(ctxWithUser(t, h, invited.ID), openapi.AcceptInviteRequestObject{Code: emailInvite.Code})
+	if err != nil {
+		t.Fatalf("AcceptInvite: %v", err)
+	}
+	if _, ok := acceptResp.(openapi.AcceptInvite404JSONResponse); !ok {
+		t.Errorf("accept revoked invite: expected 404 response, got %T", acceptResp)
+	}
+}
+
+func TestAcceptInvite_EmailBoundUnverified(t *testing.T) {
+	for _, emailEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("email enabled %v", emailEnabled), func(t *testing.T) {
+			h, db := testHandler(t)
+			h.emailService = email.NewTestService(emailEnabled, "http://localhost:8080")
+
+			owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+			invited := testutil.CreateTestUser(t, db, "invited@test.com", "Invited")
+			ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+			invitedEmail := "invited@test.com"
+			invite := &workspace.Invite{WorkspaceID: ws.ID, Role: workspace.RoleMember, CreatedBy: &owner.ID, InvitedEmail: &invitedEmail}
+			if err := h.workspaceRepo.CreateInvite(context.Background(), invite); err != nil {
+				t.Fatalf("creating invite: %v", err)
+			}
+
+			// Whoever has the link can register the invited address; until it
+			// is verified it proves nothing, even where it can't be verified
+			ctx := ctxWithUser(t, h, invited.ID)
+			resp, err := h.AcceptInvite(ctx, openapi.AcceptInviteRequestObject{Code: invite.Code})
+			if err != nil {
+				t.Fatalf("AcceptInvite: %v", err)
+			}
+			if _, ok := resp.(openapi.AcceptInvite403JSONResponse); !ok {
+				t.Errorf("unverified accept: expected 403 response, got %T", resp)
+			}
+
+			verifyEmail(t, db, invited.ID)
+			resp, err = h.AcceptInvite(ctx, openapi.AcceptInviteRequestObject{Code: invite.Code})
+			if err != nil {
+				t.Fatalf("AcceptInvite: %v", err)
+			}
+			if _, ok := resp.(openapi.AcceptInvite200JSONResponse); !ok {
+				t.Errorf("verified accept: expected 200 response, got %T", resp)
+			}
+		})
+	}
+}
+
+// updateWorkspaceSettings calls UpdateWorkspace with settings given as JSON
+func updateWorkspaceSettings(t *testing.T, h *Handler, userID, workspaceID, settingsJSON string) openapi.UpdateWorkspaceResponseObject {
+	t.Helper()
+
+	var body openapi.UpdateWorkspaceJSONRequestBody
+	if err := json.Unmarshal([]byte(`{"settings":`+settingsJSON+`}`), &body); err != nil {
+		t.Fatalf("decoding settings: %v", err)
+	}
+	resp, err := h.UpdateWorkspace(ctxWithUser(t, h, userID), openapi.UpdateWorkspaceRequestObject{Wid: workspaceID, Body: &body})
+	if err != nil {
+		t.Fatalf("UpdateWorkspace: %v", err)
+	}
+	return resp
+}
+
+func TestUpdateWorkspace_DefaultNotificationSound(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+	resp := updateWorkspaceSettings(t, h, owner.ID, ws.ID, `{"default_notification_sound":"`+strings.Repeat("a", 65)+`"}`)
+	if _, ok := resp.(openapi.UpdateWorkspace400JSONResponse); !ok {
+		t.Errorf("too long: expected 400 response, got %T", resp)
+	}
+	resp = updateWorkspaceSettings(t, h, owner.ID, ws.ID, `{"default_notification_sound":" chime "}`)
+	r, ok := resp.(openapi.UpdateWorkspace200JSONResponse)
+	if !ok {
+		t.Fatalf("set: expected 200 response, got %T", resp)
+	}
+	if s := r.Workspace.ParsedSettings.DefaultNotificationSound; s == nil || *s != "chime" {
+		t.Errorf("default_notification_sound = %v, want chime", s)
+	}
+	resp = updateWorkspaceSettings(t, h, owner.ID, ws.ID, `{"default_notification_sound":""}`)
+	if r, ok := resp.(openapi.UpdateWorkspace200JSONResponse); !ok || r.Workspace.ParsedSettings.DefaultNotificationSound != nil {
+		t.Errorf("clear: expected the sound to be omitted, got %#v", resp)
+	}
+}
+
+func TestJoinWorkspace_OpenSignup(t *testing.T) {
+	h, db := testHandler(t)
+	h.signupLimiter = ratelimit.NewLimiter([]ratelimit.Rule{
+		{Method: "POST", Path: SignupRateLimitPath, Limit: 1, Window: time.Minute},
+	})
+
+	owner := testutil.CreateTestUser(t, db, "owner@acme.com", "Owner")
+	alice := testutil.CreateTestUser(t, db, "alice@ACME.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@acme.com", "Bob")
+	mallory := testutil.CreateTestUser(t, db, "mallory@evil.io", "Mallory")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	for _, id := range []string{alice.ID, bob.ID, mallory.ID} {
+		verifyEmail(t, db, id)
+	}
+
+	join := func(userID string) openapi.JoinWorkspaceResponseObject {
+		t.Helper()
+		resp, err := h.JoinWorkspace(ctxWithUser(t, h, userID), openapi.JoinWorkspaceRequestObject{Wid: ws.ID})
+		if err != nil {
+			t.Fatalf("JoinWorkspace: %v", err)
+		}
+		return resp
+	}
+
+	resp := join(alice.ID)
+	if _, ok := resp.(openapi.JoinWorkspace404JSONResponse); !ok {
+		t.Errorf("join while off: expected 404 response, got %T", resp)
+	}
+
+	updateResp := updateWorkspaceSettings(t, h, owner.ID, ws.ID, `{"open_signup":true}`)
+	if _, ok := updateResp.(openapi.UpdateWorkspace400JSONResponse); !ok {
+		t.Errorf("enable without domains: expected 400 response, got %T", updateResp)
+	}
+	updateResp = updateWorkspaceSettings(t, h, owner.ID, ws.ID, `{"open_signup":true,"open_signup_domains":["@Acme.com"]}`)
+	r, ok := updateResp.(openapi.UpdateWorkspace200JSONResponse)
+	if !ok {
+		t.Fatalf("enable: expected 200 response, got %T", updateResp)
+	}
+	if d := r.Workspace.ParsedSettings.OpenSignupDomains; d == nil || len(*d) != 1 || (*d)[0] != "acme.com" {
+		t.Errorf("open_signup_domains = %v, want [acme.com]", d)
+	}
+
+	signupResp, err := h.GetWorkspaceSignup(ctxWithUser(t, h, mallory.ID), openapi.GetWorkspaceSignupRequestObject{Wid: ws.ID})
+	if err != nil {
+		t.Fatalf("GetWorkspaceSignup: %v", err)
+	}
+	if info, ok := signupResp.(openapi.GetWorkspaceSignup200JSONResponse); !ok || info.Name != "Acme" || info.CanJoin || info.IsMember {
+		t.Errorf("signup info for other domain = %#v, want Acme and can_join false", signupResp)
+	}
+	resp = join(mallory.ID)
+	if _, ok := resp.(openapi.JoinWorkspace403JSONResponse); !ok {
+		t.Errorf("join from other domain: expected 403 response, got %T", resp)
+	}
+
+	resp = join(alice.ID)
+	if _, ok := resp.(openapi.JoinWorkspace200JSONResponse); !ok {
+		t.Fatalf("join: expected 200 response, got %T", resp)
+	}
+	m, err := h.workspaceRepo.GetMembership(context.Background(), alice.ID, ws.ID)
+	if err != nil || m.Role != workspace.RoleMember {
+		t.Errorf("membership = %+v (err %v), want member", m, err)
+	}
+	// Already a member: no-op, and it does not use up the rate limit
+	resp = join(alice.ID)
+	if _, ok := resp.(openapi.JoinWorkspace200JSONResponse); !ok {
+		t.Errorf("second join: expected 200 response, got %T", resp)
+	}
+
+	resp = join(bob.ID)
+	if _, ok := resp.(openapi.JoinWorkspace429JSONResponse); !ok {
+		t.Errorf("join past the rate limit: expected 429 response, got %T", resp)
+	}
+}
+
+func TestJoinWorkspace_OpenSignupUnverifiedEmail(t *testing.T) {
+	for _, emailEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("email enabled %v", emailEnabled), func(t *testing.T) {
+			h, db := testHandler(t)
+			h.emailService = email.NewTestService(emailEnabled, "http://localhost:8080")
+
+			owner := testutil.CreateTestUser(t, db, "owner@acme.com", "Owner")
+			alice := testutil.CreateTestUser(t, db, "alice@acme.com", "Alice")
+			ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+			if _, ok := updateWorkspaceSettings(t, h, owner.ID, ws.ID, `{"open_signup":true,"open_signup_domains":["acme.com"]}`).(openapi.UpdateWorkspace200JSONResponse); !ok {
+				t.Fatal("enabling open signup failed")
+			}
+
+			// Anyone can register an address at the domain; until it is
+			// verified it proves nothing, even where it can't be verified
+			ctx := ctxWithUser(t, h, alice.ID)
+			signupResp, err := h.GetWorkspaceSignup(ctx, openapi.GetWorkspaceSignupRequestObject{Wid: ws.ID})
+			if err != nil {
+				t.Fatalf("GetWorkspaceSignup: %v", err)
+			}
+			if info, ok := signupResp.(openapi.GetWorkspaceSignup200JSONResponse); !ok || info.CanJoin {
+				t.Errorf("signup info = %#v, want can_join false", signupResp)
+			}
+			resp, err := h.JoinWorkspace(ctx, openapi.JoinWorkspaceRequestObject{Wid: ws.ID})
+			if err != nil {
+				t.Fatalf("JoinWorkspace: %v", err)
+			}
+			if _, ok := resp.(openapi.JoinWorkspace403JSONResponse); !ok {
+				t.Errorf("unverified join: expected 403 response, got %T", resp)
+			}
+
+			verifyEmail(t, db, alice.ID)
+			resp, err = h.JoinWorkspace(ctx, openapi.JoinWorkspaceRequestObject{Wid: ws.ID})
+			if err != nil {
+				t.Fatalf("JoinWorkspace: %v", err)
+			}
+			if _, ok := resp.(openapi.JoinWorkspace200JSONResponse); !ok {
+				t.Errorf("verified join: expected 200 response, got %T", resp)
+			}
+		})
+	}
+}
+
+func TestAcceptInvite_AutoDMWithInviter(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	inviter := testutil.CreateTestUser(t, db, "inviter@test.com", "Inviter")
+	other := testutil.CreateTestUser(t, db, "other@test.com", "Other")
+	joiner := testutil.CreateTestUser(t, db, "joiner@test.com", "Joiner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	addWorkspaceMember(t, db, inviter.ID, ws.ID, "member")
+	addWorkspaceMember(t, db, other.ID, ws.ID, "member")
+
+	setAutoDMPolicy(t, h, ws.ID, workspace.AutoDMInviter)
+	code := createInvite(t, h, ws.ID, inviter.ID)
+
+	resp, err := h.AcceptInvite(ctxWithUser(t, h, joiner.ID), openapi.AcceptInviteRequestObject{Code: code})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.AcceptInvite200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	ctx := context.Background()
+	dm, err := h.channelRepo.GetDM(ctx, ws.ID, []string{joiner.ID, inviter.ID})
+	if err != nil {
+		t.Fatalf("expected DM with inviter: %v", err)
+	}
+	for _, userID := range []string{owner.ID, other.ID} {
+		if _, err := h.channelRepo.GetDM(ctx, ws.ID, []string{joiner.ID, userID}); err != channel.ErrChannelNotFound {
+			t.Errorf("expected no DM with %s, got err=%v", userID, err)
+		}
+	}
+
+	result, err := h.messageRepo.List(ctx, dm.ID, message.ListOptions{Limit: 10}, nil)
+	if err != nil {
+		t.Fatalf("listing DM messages: %v", err)
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("expected 1 welcome message, got %d", len(result.Messages))
+	}
+	welcome := result.Messages[0]
+	if welcome.SystemEvent == nil || welcome.SystemEvent.EventType != message.SystemEventDMWelcome {
+		t.Fatalf("expected dm_welcome system message, got %+v", welcome.SystemEvent)
+	}
+	if text := welcome.SystemEvent.Text(i18n.Default); text != "joined Acme using an invite from Inviter" {
+		t.Errorf("unexpected welcome text %q", text)
+	}
+}
+
+func TestAcceptInvite_AutoDMDisabled(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	joiner := testutil.CreateTestUser(t, db, "joiner@test.com", "Joiner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+
+	setAutoDMPolicy(t, h, ws.ID, workspace.AutoDMNone)
+	code := createInvite(t, h, ws.ID, owner.ID)
+
+	resp, err := h.AcceptInvite(ctxWithUser(t, h, joiner.ID), openapi.AcceptInviteRequestObject{Code: code})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(openapi.AcceptInvite200JSONResponse); !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+
+	if _, err := h.channelRepo.GetDM(context.Background(), ws.ID, []string{joiner.ID, owner.ID}); err != channel.ErrChannelNotFound {
+		t.Errorf("expected no DM to be created, got err=%v", err)
+	}
+}
+
+func TestAcceptInvite_JoinsAutoJoinChannels(t *testing.T) {
+	h, db := testHandler(t)
+
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	joiner := testutil.CreateTestUser(t, db, "joiner@test.com", "Joiner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Acme")
+	ctx := context.Background()
+
+	general, err := h.channelRepo.CreateDefaultChannel(ctx, ws.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("creating default channel: %v", err)
+	}
+	onboarding := &channel.Channel{WorkspaceID: ws.ID, Name: "onboarding", Type: channel.TypePrivate}
+	if err := h.channelRepo.Create(ctx, onboarding, owner.ID); err != nil {
+		t.Fatalf("creating channel: %v", err)
+	}
+	onboarding.AutoJoin = true
+	if err := h.channelRepo.Update(ctx, onboarding); err != nil {
+		t.Fatalf("updating channel: %v", err)
+	}
+	random := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "random", channel.TypePublic)
+
+	setAutoDMPolicy(t, h, ws.ID, workspace.AutoDMNone)
+	code := createInvite(t, h, ws.ID, owner.ID)
+	if _, err := h.AcceptInvite(ctxWithUser(t, h, joiner.ID), openapi.AcceptInviteRequestObject{Code: code}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, ch := range []*channel.Channel{general, onboarding} {
+		if _, err := h.channelRepo.GetMembership(ctx, joiner.ID, ch.ID); err != nil {
+			t.Errorf("expected joiner in #%s: %v", ch.Name, err)
+		}
+	}
+	if _, err := h.channelRepo.GetMembership(ctx, joiner.ID, random.ID); err == nil {
+		t.Error("joiner should not be added to #random")
+	}
+}
@@ -0,0 +1,163 @@
+package i18n
+
+// catalogs maps each supported locale to its strings. System message formats
+// use explicit argument indexes so translations can reorder them; error
+// messages use named {placeholders} matching the params sent with the error.
+var catalogs = map[string]map[string]string{
+	"en": {
+		"system.user_joined":                 "joined #%[1]s",
+		"system.user_left":                   "left #%[1]s",
+		"system.user_added":                  "was added to #%[1]s",
+		"system.user_added_by":               "was added by %[1]s",
+		"system.channel_renamed":             "renamed the channel to #%[1]s",
+		"system.channel_renamed_from":        "renamed the channel from #%[1]s to #%[2]s",
+		"system.channel_made_public":         "made the channel public",
+		"system.channel_made_private":        "made the channel private",
+		"system.channel_visibility_changed":  "changed the channel visibility",
+		"system.channel_description_updated": "updated the channel description",
+		"system.channel_topic_set":           "set the channel topic: %[1]s",
+		"system.channel_topic_cleared":       "cleared the channel topic",
+		"system.channel_archived":            "archived this channel",
+		"system.channel_unarchived":          "unarchived this channel",
+		"system.message_pinned":              "pinned a message to this channel",
+		"system.message_unpinned":            "unpinned a message from this channel",
+		"system.call_started":                "started a call",
+		"system.users_added":                 "added people to #%[1]s",
+		"system.users_added_count":           "added %[1]d people to #%[2]s",
+		"system.dm_welcome":                  "joined %[1]s",
+		"system.dm_welcome_inviter":          "joined %[1]s using an invite from %[2]s",
+		"system.dm_welcome_admin":            "joined %[1]s — %[2]s is a workspace admin and can help you get started",
+
+		"error.not_authenticated":      "Not authenticated",
+		"error.internal_error":         "An internal error occurred",
+		"error.rate_limited":           "Too many requests. Try again in {retry_after} seconds.",
+		"error.files_disabled":         "File uploads are disabled",
+		"error.storage_quota_exceeded": "Workspace storage quota exceeded",
+		"error.file_type_not_allowed":  "This file type is not allowed in this workspace",
+		"error.file_quarantined":       "This file failed the virus scan and cannot be downloaded",
+		"error.feature_disabled":       "This feature is turned off in this workspace",
+		"error.required":               "{field} is required",
+		"error.too_long":               "{field} must be at most {max} characters",
+		"error.invalid_value":          "Invalid value for {field}",
+		"error.out_of_range":           "{field} must be between {min} and {max}",
+		"error.invalid_channel_name":   "Channel name must contain only lowercase letters, numbers, and dashes",
+		"error.channel_name_taken":     "A channel with this name already exists",
+	},
+	"de": {
+		"system.user_joined":                 "ist #%[1]s beigetreten",
+		"system.user_left":                   "hat #%[1]s verlassen",
+		"system.user_added":                  "wurde zu #%[1]s hinzugefügt",
+		"system.user_added_by":               "wurde von %[1]s hinzugefügt",
+		"system.channel_renamed":             "hat den Channel in #%[1]s umbenannt",
+		"system.channel_renamed_from":        "hat den Channel von #%[1]s in #%[2]s umbenannt",
+		"system.channel_made_public":         "hat den Channel öffentlich gemacht",
+		"system.channel_made_private":        "hat den Channel privat gemacht",
+		"system.channel_visibility_changed":  "hat die Sichtbarkeit des Channels geändert",
+		"system.channel_description_updated": "hat die Channel-Beschreibung aktualisiert",
+		"system.channel_topic_set":           "hat das Channel-Thema festgelegt: %[1]s",
+		"system.channel_topic_cleared":       "hat das Channel-Thema entfernt",
+		"system.channel_archived":            "hat diesen Channel archiviert",
+		"system.channel_unarchived":          "hat diesen Channel wiederhergestellt",
+		"system.message_pinned":              "hat eine Nachricht in diesem Channel angeheftet",
+		"system.message_unpinned":            "hat eine angeheftete Nachricht aus diesem Channel entfernt",
+		"system.call_started":                "hat einen Anruf gestartet",
+		"system.users_added":                 "hat Personen zu #%[1]s hinzugefügt",
+		"system.users_added_count":           "hat %[1]d Personen zu #%[2]s hinzugefügt",
+		"system.dm_welcome":                  "ist %[1]s beigetreten",
+		"system.dm_welcome_inviter":          "ist %[1]s über eine Einladung von %[2]s beigetreten",
+		"system.dm_welcome_admin":            "ist %[1]s beigetreten – %[2]s ist Workspace-Admin und hilft dir gern beim Einstieg",
+
+		"error.not_authenticated":      "Nicht angemeldet",
+		"error.internal_error":         "Ein interner Fehler ist aufgetreten",
+		"error.rate_limited":           "Zu viele Anfragen. Versuche es in {retry_after} Sekunden erneut.",
+		"error.files_disabled":         "Datei-Uploads sind deaktiviert",
+		"error.storage_quota_exceeded": "Speicherkontingent des Workspace überschritten",
+		"error.file_type_not_allowed":  "Dieser Dateityp ist in diesem Workspace nicht erlaubt",
+		"error.file_quarantined":       "Diese Datei hat den Virenscan nicht bestanden und kann nicht heruntergeladen werden",
+		"error.feature_disabled":       "Diese Funktion ist in diesem Workspace deaktiviert",
+		"error.required":               "{field} ist erforderlich",
+		"error.too_long":               "{field} darf höchstens {max} Zeichen lang sein",
+		"error.invalid_value":          "Ungültiger Wert für {field}",
+		"error.out_of_range":           "{field} muss zwischen {min} und {max} liegen",
+		"error.invalid_channel_name":   "Channel-Namen dürfen nur Kleinbuchstaben, Ziffern und Bindestriche enthalten",
+		"error.channel_name_taken":     "Es gibt bereits einen Channel mit diesem Namen",
+	},
+	"fr": {
+		"system.user_joined":                 "a rejoint #%[1]s",
+		"system.user_left":                   "a quitté #%[1]s",
+		"system.user_added":                  "a été ajouté à #%[1]s",
+		"system.user_added_by":               "a été ajouté par %[1]s",
+		"system.channel_renamed":             "a renommé le canal en #%[1]s",
+		"system.channel_renamed_from":        "a renommé le canal #%[1]s en #%[2]s",
+		"system.channel_made_public":         "a rendu le canal public",
+		"system.channel_made_private":        "a rendu le canal privé",
+		"system.channel_visibility_changed":  "a modifié la visibilité du canal",
+		"system.channel_description_updated": "a mis à jour la description du canal",
+		"system.channel_topic_set":           "a défini le sujet du canal : %[1]s",
+		"system.channel_topic_cleared":       "a effacé le sujet du canal",
+		"system.channel_archived":            "a archivé ce canal",
+		"system.channel_unarchived":          "a désarchivé ce canal",
+		"system.message_pinned":              "a épinglé un message dans ce canal",
+		"system.message_unpinned":            "a désépinglé un message de ce canal",
+		"system.call_started":                "a lancé un appel",
+		"system.users_added":                 "a ajouté des personnes à #%[1]s",
+		"system.users_added_count":           "a ajouté %[1]d personnes à #%[2]s",
+		"system.dm_welcome":                  "a rejoint %[1]s",
+		"system.dm_welcome_inviter":          "a rejoint %[1]s grâce à une invitation de %[2]s",
+		"system.dm_welcome_admin":            "a rejoint %[1]s — %[2]s administre l'espace de travail et peut vous aider à démarrer",
+
+		"error.not_authenticated":      "Non authentifié",
+		"error.internal_error":         "Une erreur interne s'est produite",
+		"error.rate_limited":           "Trop de requêtes. Réessayez dans {retry_after} secondes.",
+		"error.files_disabled":         "L'envoi de fichiers est désactivé",
+		"error.storage_quota_exceeded": "Quota de stockage de l'espace de travail dépassé",
+		"error.file_type_not_allowed":  "Ce type de fichier n'est pas autorisé dans cet espace de travail",
+		"error.file_quarantined":       "Ce fichier n'a pas passé l'analyse antivirus et ne peut pas être téléchargé",
+		"error.feature_disabled":       "Cette fonctionnalité est désactivée dans cet espace de travail",
+		"error.required":               "{field} est obligatoire",
+		"error.too_long":               "{field} ne doit pas dépasser {max} caractères",
+		"error.invalid_value":          "Valeur non valide pour {field}",
+		"error.out_of_range":           "{field} doit être compris entre {min} et {max}",
+		"error.invalid_channel_name":   "Le nom du canal ne peut contenir que des lettres minuscules, des chiffres et des tirets",
+		"error.channel_name_taken":     "Un canal portant ce nom existe déjà",
+	},
+	"ja": {
+		"system.user_joined":                 "#%[1]s に参加しました",
+		"system.user_left":                   "#%[1]s から退出しました",
+		"system.user_added":                  "#%[1]s に追加されました",
+		"system.user_added_by":               "%[1]s によって追加されました",
+		"system.channel_renamed":             "チャンネル名を #%[1]s に変更しました",
+		"system.channel_renamed_from":        "チャンネル名を #%[1]s から #%[2]s に変更しました",
+		"system.channel_made_public":         "チャンネルを公開にしました",
+		"system.channel_made_private":        "チャンネルを非公開にしました",
+		"system.channel_visibility_changed":  "チャンネルの公開範囲を変更しました",
+		"system.channel_description_updated": "チャンネルの説明を更新しました",
+		"system.channel_topic_set":           "チャンネルのトピックを設定しました: %[1]s",
+		"system.channel_topic_cleared":       "チャンネルのトピックを削除しました",
+		"system.channel_archived":            "このチャンネルをアーカイブしました",
+		"system.channel_unarchived":          "このチャンネルのアーカイブを解除しました",
+		"system.message_pinned":              "このチャンネルにメッセージをピン留めしました",
+		"system.message_unpinned":            "このチャンネルのメッセージのピン留めを外しました",
+		"system.call_started":                "通話を開始しました",
+		"system.users_added":                 "#%[1]s にメンバーを追加しました",
+		"system.users_added_count":           "#%[2]s に %[1]d 人を追加しました",
+		"system.dm_welcome":                  "%[1]s に参加しました",
+		"system.dm_welcome_inviter":          "%[2]s さんの招待で %[1]s に参加しました",
+		"system.dm_welcome_admin":            "%[1]s に参加しました — %[2]s さんはワークスペース管理者で、使い始めるお手伝いができます",
+
+		"error.not_authenticated":      "認証されていません",
+		"error.internal_error":         "内部エラーが発生しました",
+		"error.rate_limited":           "リクエストが多すぎます。{retry_after} 秒後にもう一度お試しください。",
+		"error.files_disabled":         "ファイルのアップロードは無効になっています",
+		"error.storage_quota_exceeded": "ワークスペースのストレージ容量を超えました",
+		"error.file_type_not_allowed":  "このワークスペースではこの種類のファイルは許可されていません",
+		"error.file_quarantined":       "このファイルはウイルススキャンに合格しなかったため、ダウンロードできません",
+		"error.feature_disabled":       "この機能はこのワークスペースで無効になっています",
+		"error.required":               "{field} は必須です",
+		"error.too_long":               "{field} は {max} 文字以内で入力してください",
+		"error.invalid_value":          "{field} の値が無効です",
+		"error.out_of_range":           "{field} は {min} から {max} の範囲で指定してください",
+		"error.invalid_channel_name":   "チャンネル名には小文字の英字、数字、ハイフンのみ使用できます",
+		"error.channel_name_taken":     "この名前のチャンネルは既に存在します",
+	},
+}
@@ -0,0 +1,115 @@
+// Package i18n picks the language for API responses and holds the
+// translated strings the server renders itself, such as the text of system
+// messages.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the locale used when a request states no supported preference.
+const Default = "en"
+
+// Supported lists the locales with a catalog, in no particular order.
+var Supported = []string{"en", "de", "fr", "ja"}
+
+type localeKey struct{}
+
+// WithLocale returns a context carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale carried by ctx, or Default.
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return Default
+}
+
+// IsSupported reports whether locale has a catalog.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Negotiate picks the supported locale that best matches an Accept-Language
+// header, comparing primary language subtags only ("de-AT" matches "de").
+// Ranges are tried in order of their q-value; a q of 0 rules a language out.
+// It returns Default when nothing matches.
+func Negotiate(acceptLanguage string) string {
+	type choice struct {
+		lang string
+		q    float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(p), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "" || q <= 0 {
+			continue
+		}
+		choices = append(choices, choice{lang, q})
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+
+	for _, c := range choices {
+		if c.lang == "*" {
+			return Default
+		}
+		if IsSupported(c.lang) {
+			return c.lang
+		}
+	}
+	return Default
+}
+
+// T returns the string for key in locale, formatted with args. Keys missing
+// from a catalog fall back to the Default catalog, and unknown keys to the
+// key itself.
+func T(locale, key string, args ...any) string {
+	format, ok := catalogs[locale][key]
+	if !ok {
+		if format, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Format returns the string for key in locale with each {name} placeholder
+// replaced by params[name]. Unlike T, the arguments are named, which suits
+// strings whose parameters are also part of an API, such as error params.
+// It reports false when no catalog has key.
+func Format(locale, key string, params map[string]any) (string, bool) {
+	format, ok := catalogs[locale][key]
+	if !ok {
+		if format, ok = catalogs[Default][key]; !ok {
+			return "", false
+		}
+	}
+	if len(params) == 0 {
+		return format, true
+	}
+	pairs := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(format), true
+}
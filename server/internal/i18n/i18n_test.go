@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT,de;q=0.9,en;q=0.8", "de"},
+		{"FR-ca", "fr"},
+		{"es,ja;q=0.5", "ja"},
+		{"en;q=0.3, ja;q=0.9", "ja"},
+		{"ja;q=0, fr;q=0.1", "fr"},
+		{"*", "en"},
+		{"pt-BR, es", "en"},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("FromContext without a locale = %q, want %q", got, Default)
+	}
+	if got := FromContext(WithLocale(context.Background(), "ja")); got != "ja" {
+		t.Errorf("FromContext = %q, want ja", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("de", "system.users_added_count", 3, "general"); got != "hat 3 Personen zu #general hinzugefügt" {
+		t.Errorf("T(de) = %q", got)
+	}
+	if got := T("ja", "system.users_added_count", 3, "general"); got != "#general に 3 人を追加しました" {
+		t.Errorf("T(ja) = %q", got)
+	}
+	if got := T("xx", "system.user_left", "general"); got != "left #general" {
+		t.Errorf("T for an unknown locale = %q, want the default", got)
+	}
+	if got := T("en", "no.such.key"); got != "no.such.key" {
+		t.Errorf("T for an unknown key = %q, want the key", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got, ok := Format("de", "error.out_of_range", map[string]any{"field": "slow_mode_seconds", "min": 0, "max": 21600})
+	if !ok || got != "slow_mode_seconds muss zwischen 0 und 21600 liegen" {
+		t.Errorf("Format(de) = %q, %v", got, ok)
+	}
+	if got, ok := Format("xx", "error.required", map[string]any{"field": "name"}); !ok || got != "name is required" {
+		t.Errorf("Format for an unknown locale = %q, %v, want the default", got, ok)
+	}
+	if _, ok := Format("en", "error.no_such_code", nil); ok {
+		t.Error("expected Format to report an unknown key")
+	}
+}
+
+// Every catalog must translate every key and use the same arguments.
+func TestCatalogsComplete(t *testing.T) {
+	verbs := regexp.MustCompile(`%\[\d+\][a-z]|\{[a-z_]+\}`)
+	for _, locale := range Supported {
+		if !IsSupported(locale) {
+			t.Errorf("no catalog for supported locale %q", locale)
+		}
+	}
+	for locale, catalog := range catalogs {
+		if !slices.Contains(Supported, locale) {
+			t.Errorf("catalog %q is not listed in Supported", locale)
+		}
+		if len(catalog) != len(catalogs[Default]) {
+			t.Errorf("catalog %q has %d keys, want %d", locale, len(catalog), len(catalogs[Default]))
+		}
+		for key, format := range catalogs[Default] {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("catalog %q is missing %q", locale, key)
+				continue
+			}
+			want := verbs.FindAllString(format, -1)
+			got := verbs.FindAllString(translated, -1)
+			slices.Sort(want)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s %q uses %v, want %v", locale, key, got, want)
+			}
+		}
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/html"
@@ -62,6 +64,13 @@ type ogData struct {
 type Fetcher struct {
 	repo   *Repository
 	client *http.Client
+
+	// allowedDomains, when non-empty, restricts fetching to these domains and
+	// their subdomains. blockedDomains are never fetched. They can be
+	// replaced while previews are being fetched, so mu guards them.
+	mu             sync.RWMutex
+	allowedDomains []string
+	blockedDomains []string
 }
 
 // NewFetcher creates a Fetcher with an SSRF-safe HTTP client.
@@ -92,9 +101,60 @@ func NewFetcherWithClient(repo *Repository, client *http.Client) *Fetcher {
 	return &Fetcher{repo: repo, client: client}
 }
 
+// SetDomainLists configures which domains may be unfurled. Entries are
+// matched case-insensitively against the URL host and its parent domains.
+func (f *Fetcher) SetDomainLists(allowed, blocked []string) {
+	allowed, blocked = normalizeDomains(allowed), normalizeDomains(blocked)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.allowedDomains = allowed
+	f.blockedDomains = blocked
+}
+
+// Allowed reports whether the URL's host passes the domain allow/deny lists.
+func (f *Fetcher) Allowed(rawURL string) bool {
+	u, err := neturl.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if matchesDomain(host, f.blockedDomains) {
+		return false
+	}
+	return len(f.allowedDomains) == 0 || matchesDomain(host, f.allowedDomains)
+}
+
+func normalizeDomains(domains []string) []string {
+	var out []string
+	for _, d := range domains {
+		d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// matchesDomain reports whether host is one of domains or a subdomain of one.
+func matchesDomain(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
 // FetchPreview returns a Preview for the URL, using the cache when possible.
-// Returns nil if the URL could not be fetched or has no useful OG data.
+// Returns nil if the URL could not be fetched, has no useful OG data, or is
+// excluded by the domain lists.
 func (f *Fetcher) FetchPreview(ctx context.Context, url string) (*Preview, error) {
+	if !f.Allowed(url) {
+		return nil, nil
+	}
+
 	// Check cache.
 	cached, err := f.repo.GetCachedURL(ctx, url)
 	if err != nil {
@@ -183,10 +243,12 @@ func (f *Fetcher) fetchOG(ctx context.Context, url string) (*ogData, error) {
 	return parseOG(body)
 }
 
-// parseOG extracts og:* meta tags and falls back to <title> / <meta name="description">.
+// parseOG extracts og:* meta tags, falling back to twitter:* card tags and
+// then to <title> / <meta name="description">.
 func parseOG(r io.Reader) (*ogData, error) {
 	tokenizer := html.NewTokenizer(r)
 	data := &ogData{}
+	twitter := &ogData{}
 	var fallbackTitle string
 	var fallbackDesc string
 
@@ -196,10 +258,10 @@ func parseOG(r io.Reader) (*ogData, error) {
 		case html.ErrorToken:
 			err := tokenizer.Err()
 			if err == io.EOF {
-				applyFallbacks(data, fallbackTitle, fallbackDesc)
+				applyFallbacks(data, twitter, fallbackTitle, fallbackDesc)
 				return data, nil
 			}
-			applyFallbacks(data, fallbackTitle, fallbackDesc)
+			applyFallbacks(data, twitter, fallbackTitle, fallbackDesc)
 			return data, nil
 
 		case html.StartTagToken, html.SelfClosingTagToken:
@@ -208,7 +270,7 @@ func parseOG(r io.Reader) (*ogData, error) {
 
 			if tag == "body" {
 				// Stop parsing at <body>.
-				applyFallbacks(data, fallbackTitle, fallbackDesc)
+				applyFallbacks(data, twitter, fallbackTitle, fallbackDesc)
 				return data, nil
 			}
 
@@ -237,6 +299,22 @@ func parseOG(r io.Reader) (*ogData, error) {
 					data.SiteName = content
 				}
 
+				// Twitter cards normally use name=, but some sites use property=.
+				card := name
+				if card == "" {
+					card = prop
+				}
+				switch card {
+				case "twitter:title":
+					twitter.Title = content
+				case "twitter:description":
+					twitter.Description = content
+				case "twitter:image", "twitter:image:src":
+					twitter.ImageURL = content
+				case "twitter:site":
+					twitter.SiteName = strings.TrimPrefix(content, "@")
+				}
+
 				if name == "description" && fallbackDesc == "" {
 					fallbackDesc = content
 				}
@@ -245,13 +323,25 @@ func parseOG(r io.Reader) (*ogData, error) {
 	}
 }
 
-func applyFallbacks(data *ogData, fallbackTitle, fallbackDesc string) {
+func applyFallbacks(data, twitter *ogData, fallbackTitle, fallbackDesc string) {
+	if data.Title == "" {
+		data.Title = twitter.Title
+	}
 	if data.Title == "" {
 		data.Title = fallbackTitle
 	}
+	if data.Description == "" {
+		data.Description = twitter.Description
+	}
 	if data.Description == "" {
 		data.Description = fallbackDesc
 	}
+	if data.ImageURL == "" {
+		data.ImageURL = twitter.ImageURL
+	}
+	if data.SiteName == "" {
+		data.SiteName = twitter.SiteName
+	}
 }
 
 // readAttrs collects all attributes from the current tag token.
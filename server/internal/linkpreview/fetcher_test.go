@@ -254,3 +254,87 @@ func TestParseOG_StopsAtBody(t *testing.T) {
 		t.Errorf("title = %q, want %q", data.Title, "Head Title")
 	}
 }
+
+func TestParseOG_TwitterCardFallback(t *testing.T) {
+	html := `<html><head>
+		<title>Page Title</title>
+		<meta property="og:title" content="OG Title">
+		<meta name="twitter:title" content="Card Title">
+		<meta name="twitter:description" content="Card Description">
+		<meta name="twitter:image" content="https://example.com/card.png">
+		<meta name="twitter:site" content="@example">
+	</head><body></body></html>`
+
+	data, err := parseOG(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parseOG: %v", err)
+	}
+	if data.Title != "OG Title" {
+		t.Errorf("title = %q, want og:title to win", data.Title)
+	}
+	if data.Description != "Card Description" {
+		t.Errorf("description = %q, want %q", data.Description, "Card Description")
+	}
+	if data.ImageURL != "https://example.com/card.png" {
+		t.Errorf("image = %q, want %q", data.ImageURL, "https://example.com/card.png")
+	}
+	if data.SiteName != "example" {
+		t.Errorf("site name = %q, want %q", data.SiteName, "example")
+	}
+}
+
+func TestFetcher_Allowed(t *testing.T) {
+	db := testutil.TestDB(t)
+	f := NewFetcher(NewRepository(db))
+
+	if !f.Allowed("https://anything.example.com/page") {
+		t.Error("expected all domains to be allowed with no lists configured")
+	}
+
+	f.SetDomainLists(nil, []string{"Tracker.io"})
+	if f.Allowed("https://cdn.tracker.io/x") {
+		t.Error("expected subdomain of a blocked domain to be denied")
+	}
+	if !f.Allowed("https://nottracker.io/x") {
+		t.Error("expected a domain that only shares a suffix to be allowed")
+	}
+
+	f.SetDomainLists([]string{"github.com", " docs.example.com "}, []string{"gist.github.com"})
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://github.com/org/repo", true},
+		{"https://www.github.com/org/repo", true},
+		{"https://gist.github.com/abc", false},
+		{"https://docs.example.com/guide", true},
+		{"https://example.com/", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		if got := f.Allowed(tt.url); got != tt.want {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestFetchPreview_BlockedDomainNotFetched(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		fmt.Fprint(w, `<html><head><meta property="og:title" content="Title"></head></html>`)
+	}))
+	defer srv.Close()
+
+	db := testutil.TestDB(t)
+	f := NewFetcherWithClient(NewRepository(db), &http.Client{Timeout: fetchTimeout})
+	f.SetDomainLists(nil, []string{"127.0.0.1"})
+
+	preview, err := f.FetchPreview(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("FetchPreview: %v", err)
+	}
+	if preview != nil || hits != 0 {
+		t.Errorf("expected blocked URL not to be fetched, got preview %v after %d requests", preview, hits)
+	}
+}
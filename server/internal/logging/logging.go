@@ -8,24 +8,19 @@ import (
 	"github.com/enzyme/server/internal/telemetry"
 )
 
+// level is shared by every handler Setup builds, so SetLevel takes effect
+// without replacing the logger.
+var level slog.LevelVar
+
 // Setup configures the default slog logger based on the provided config.
 // This also bridges the standard "log" package via slog.SetDefault (Go 1.22+).
 // When otelLogs is true, log records are enriched with trace_id and span_id
-// and forwarded to the OTel log pipeline.
+// and forwarded to the OTel log pipeline. Records logged with a request
+// context carry its request_id.
 func Setup(cfg config.LogConfig, otelLogs bool, serviceName string) {
-	var level slog.Level
-	switch cfg.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
-	}
+	SetLevel(cfg.Level)
 
-	opts := &slog.HandlerOptions{Level: level}
+	opts := &slog.HandlerOptions{Level: &level}
 
 	var handler slog.Handler
 	if cfg.Format == "json" {
@@ -36,5 +31,20 @@ func Setup(cfg config.LogConfig, otelLogs bool, serviceName string) {
 
 	handler = telemetry.NewSlogHandler(handler, otelLogs, serviceName)
 
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(slog.New(&requestInjector{inner: handler}))
+}
+
+// SetLevel changes the minimum level logged: debug, info, warn or error.
+// Unknown names mean info.
+func SetLevel(name string) {
+	switch name {
+	case "debug":
+		level.Set(slog.LevelDebug)
+	case "warn":
+		level.Set(slog.LevelWarn)
+	case "error":
+		level.Set(slog.LevelError)
+	default:
+		level.Set(slog.LevelInfo)
+	}
 }
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestKey struct{}
+
+// requestInfo identifies the API request a context belongs to. The user ID is
+// filled in once authentication has run, which happens after the request ID
+// is assigned, so it is kept behind a pointer shared by derived contexts.
+type requestInfo struct {
+	id     string
+	userID string
+}
+
+// WithRequestID returns a context carrying the given request ID. Records
+// logged with that context include it as request_id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestKey{}, &requestInfo{id: id})
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		return info.id
+	}
+	return ""
+}
+
+// SetUserID records the authenticated user for the request carried by ctx.
+// It does nothing if ctx has no request ID.
+func SetUserID(ctx context.Context, userID string) {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		info.userID = userID
+	}
+}
+
+// UserID returns the user recorded by SetUserID, or "".
+func UserID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestKey{}).(*requestInfo); ok {
+		return info.userID
+	}
+	return ""
+}
+
+// Detach returns a background context carrying the request ID and user of
+// ctx but none of its deadlines or cancellation, for work that outlives the
+// request such as sending notifications.
+func Detach(ctx context.Context) context.Context {
+	info, ok := ctx.Value(requestKey{}).(*requestInfo)
+	if !ok {
+		return context.Background()
+	}
+	detached := *info
+	return context.WithValue(context.Background(), requestKey{}, &detached)
+}
+
+// requestInjector adds the request ID from the context to log records.
+type requestInjector struct {
+	inner slog.Handler
+}
+
+func (h *requestInjector) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *requestInjector) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.inner.Handle(ctx, record)
+}
+
+func (h *requestInjector) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &requestInjector{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *requestInjector) WithGroup(name string) slog.Handler {
+	return &requestInjector{inner: h.inner.WithGroup(name)}
+}
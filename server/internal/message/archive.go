@@ -0,0 +1,176 @@
+package message
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/enzyme/server/internal/moderation"
+	"github.com/enzyme/server/internal/telemetry"
+)
+
+// messageTables names where messages are stored along with the tables that
+// hang off them: the hot tables messages are written to, or the cold-storage
+// copies that dormant threads are archived into (see retention.Archiver).
+type messageTables struct {
+	messages         string
+	reactions        string
+	search           string // FTS5 index over messages
+	attachmentColumn string // column of attachments that links to a message
+}
+
+var (
+	hotTables     = messageTables{"messages", "reactions", "messages_fts", "message_id"}
+	archiveTables = messageTables{"messages_archive", "reactions_archive", "messages_archive_fts", "archived_message_id"}
+)
+
+// ListArchived lists a channel's archived messages, newest first. Without a
+// cursor it returns the newest page, or the page around At; with one it
+// pages "before" (the default) or "after" it, or loads messages "around" it.
+func (r *Repository) ListArchived(ctx context.Context, channelID string, opts ArchiveListOptions, filter *moderation.FilterOptions) (_ *ListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.ListArchived")
+	defer func() { endSpan(err) }()
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
+	}
+
+	where := "m.channel_id = ? AND (m.thread_parent_id IS NULL OR m.also_send_to_channel = TRUE)"
+	args := []interface{}{channelID}
+	if opts.ThreadID != "" {
+		where = "m.channel_id = ? AND m.thread_parent_id = ?"
+		args = append(args, opts.ThreadID)
+	}
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
+	filterSQL, filterArgs = appendVisibleSince(filterSQL, filterArgs, opts.VisibleSince)
+	where += filterSQL
+	args = append(args, filterArgs...)
+
+	if opts.Cursor == "" && opts.At != nil {
+		if opts.Cursor, err = r.archivedCursorAt(ctx, where, args, *opts.At); err != nil {
+			return nil, err
+		}
+		opts.Direction = "around"
+	}
+
+	result := &ListResult{}
+	var messages []MessageWithUser
+	switch {
+	case opts.Direction == "around" && opts.Cursor != "":
+		half := max(opts.Limit/2, 1)
+		older, err := r.queryArchived(ctx, where+" AND m.id <= ?", append(slices.Clone(args), opts.Cursor), "DESC", half+1)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasOlder = len(older) > half; result.HasOlder {
+			older = older[:half]
+		}
+		newer, err := r.queryArchived(ctx, where+" AND m.id > ?", append(slices.Clone(args), opts.Cursor), "ASC", half+1)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasNewer = len(newer) > half; result.HasNewer {
+			newer = newer[:half]
+		}
+		slices.Reverse(newer)
+		messages = append(newer, older...)
+		result.HasMore = result.HasOlder
+		if result.HasOlder {
+			result.NextCursor = messages[len(messages)-1].ID
+		}
+
+	case opts.Direction == "after" && opts.Cursor != "":
+		messages, err = r.queryArchived(ctx, where+" AND m.id > ?", append(args, opts.Cursor), "ASC", opts.Limit+1)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasNewer = len(messages) > opts.Limit; result.HasNewer {
+			messages = messages[:opts.Limit]
+		}
+		slices.Reverse(messages)
+		if result.HasNewer {
+			result.NextCursor = messages[0].ID
+		}
+		result.HasMore = result.HasNewer
+
+	default:
+		if opts.Cursor != "" {
+			where += " AND m.id < ?"
+			args = append(args, opts.Cursor)
+		}
+		messages, err = r.queryArchived(ctx, where, args, "DESC", opts.Limit+1)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasOlder = len(messages) > opts.Limit; result.HasOlder {
+			messages = messages[:opts.Limit]
+			result.NextCursor = messages[len(messages)-1].ID
+		}
+		result.HasMore = result.HasOlder
+	}
+
+	r.loadReactionsAndParticipants(ctx, archiveTables, messages, filter)
+
+	if messages == nil {
+		messages = []MessageWithUser{}
+	}
+	result.Messages = messages
+	return result, nil
+}
+
+// archivedCursorAt returns the ID of the first archived message matching
+// where that was created at or after at, or failing that the last one
+// before it. It returns "" when nothing matches.
+func (r *Repository) archivedCursorAt(ctx context.Context, where string, args []interface{}, at time.Time) (string, error) {
+	bound := at.UTC().Format(time.RFC3339)
+	var id string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT m.id FROM messages_archive m
+		WHERE `+where+` AND m.created_at >= ?
+		ORDER BY m.created_at, m.id
+		LIMIT 1
+	`, append(slices.Clone(args), bound)...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = r.db.QueryRowContext(ctx, `
+			SELECT m.id FROM messages_archive m
+			WHERE `+where+` AND m.created_at < ?
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT 1
+		`, append(slices.Clone(args), bound)...).Scan(&id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return id, err
+}
+
+// queryArchived loads up to limit archived messages matching where, by ID in
+// the given order.
+func (r *Repository) queryArchived(ctx context.Context, where string, args []interface{}, order string, limit int) ([]MessageWithUser, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+		       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+		       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+		       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
+		FROM messages_archive m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE `+where+`
+		ORDER BY m.id `+order+`
+		LIMIT ?
+	`, append(slices.Clone(args), limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []MessageWithUser
+	for rows.Next() {
+		msg, err := r.scanMessageWithUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *msg)
+	}
+	return messages, rows.Err()
+}
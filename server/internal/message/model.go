@@ -5,12 +5,14 @@ import (
 
 	"github.com/enzyme/server/internal/file"
 	"github.com/enzyme/server/internal/linkpreview"
+	"github.com/enzyme/server/internal/poll"
 )
 
 // Message types
 const (
 	MessageTypeUser   = "user"
 	MessageTypeSystem = "system"
+	MessageTypePoll   = "poll"
 )
 
 // System event types
@@ -22,8 +24,28 @@ const (
 	SystemEventChannelRenamed            = "channel_renamed"
 	SystemEventChannelVisibilityChanged  = "channel_visibility_changed"
 	SystemEventChannelDescriptionUpdated = "channel_description_updated"
+	SystemEventChannelTopicChanged       = "channel_topic_changed"
+	SystemEventChannelArchived           = "channel_archived"
+	SystemEventChannelUnarchived         = "channel_unarchived"
 	SystemEventMessagePinned             = "message_pinned"
 	SystemEventMessageUnpinned           = "message_unpinned"
+	SystemEventDMWelcome                 = "dm_welcome"
+	SystemEventCall                      = "call"
+	SystemEventUsersAdded                = "users_added"
+)
+
+// ClientMsgIDWindow is how long a sender's idempotency key keeps returning
+// the original message instead of sending a new one
+const ClientMsgIDWindow = 24 * time.Hour
+
+// MaxClientMsgIDLength bounds client-supplied idempotency keys
+const MaxClientMsgIDLength = 255
+
+// Welcome reasons for dm_welcome system events
+const (
+	WelcomeReasonInviter = "inviter"
+	WelcomeReasonAdmin   = "admin"
+	WelcomeReasonMember  = "member"
 )
 
 // SystemEventData contains metadata for system messages
@@ -36,7 +58,15 @@ type SystemEventData struct {
 	ActorDisplayName *string `json:"actor_display_name,omitempty"`
 	OldChannelName   *string `json:"old_channel_name,omitempty"`
 	ChannelType      *string `json:"channel_type,omitempty"`
+	Topic            *string `json:"topic,omitempty"` // Empty when the topic was cleared
 	MessageID        *string `json:"message_id,omitempty"`
+	WelcomeReason    *string `json:"welcome_reason,omitempty"`
+	CallID           *string `json:"call_id,omitempty"`
+	// Set on call events once the call has ended
+	CallDurationSeconds  *int `json:"call_duration_seconds,omitempty"`
+	CallParticipantCount *int `json:"call_participant_count,omitempty"`
+	// Set on users_added events, where UserID is the actor
+	UserCount *int `json:"user_count,omitempty"`
 }
 
 type Message struct {
@@ -55,26 +85,51 @@ type Message struct {
 	DeletedAt         *time.Time       `json:"deleted_at,omitempty"`
 	PinnedAt          *time.Time       `json:"pinned_at,omitempty"`
 	PinnedBy          *string          `json:"pinned_by,omitempty"`
+	WebhookID         *string          `json:"webhook_id,omitempty"`
+	AnnouncementID    *string          `json:"announcement_id,omitempty"`
+	BotName           *string          `json:"-"`
+	BotAvatarURL      *string          `json:"-"`
+	ClientMsgID       *string          `json:"-"` // Sender's idempotency key; only set on create
 	CreatedAt         time.Time        `json:"created_at"`
 	UpdatedAt         time.Time        `json:"updated_at"`
 }
 
 type MessageWithUser struct {
 	Message
-	UserDisplayName    string               `json:"user_display_name,omitempty"`
-	UserAvatarURL      *string              `json:"user_avatar_url,omitempty"`
-	UserEmail          string               `json:"-"`
-	Reactions          []Reaction           `json:"reactions,omitempty"`
-	ThreadParticipants []ThreadParticipant  `json:"thread_participants,omitempty"`
-	Attachments        []file.Attachment    `json:"attachments,omitempty"`
-	LinkPreview        *linkpreview.Preview `json:"link_preview,omitempty"`
+	UserDisplayName        string               `json:"user_display_name,omitempty"`
+	UserAvatarURL          *string              `json:"user_avatar_url,omitempty"`
+	UserEmail              string               `json:"-"`
+	UserIsDeactivated      bool                 `json:"user_is_deactivated,omitempty"`
+	IsBot                  bool                 `json:"is_bot,omitempty"`
+	Reactions              []Reaction           `json:"reactions,omitempty"`
+	ThreadParticipants     []ThreadParticipant  `json:"thread_participants,omitempty"`
+	ThreadParticipantCount int                  `json:"thread_participant_count,omitempty"`
+	Attachments            []file.Attachment    `json:"attachments,omitempty"`
+	LinkPreview            *linkpreview.Preview `json:"link_preview,omitempty"`
+	ReadReceipts           []Receipt            `json:"read_receipts,omitempty"`
+	Poll                   *poll.Poll           `json:"poll,omitempty"`
+}
+
+// Receipt records that a user has seen a direct message
+type Receipt struct {
+	UserID string    `json:"user_id"`
+	ReadAt time.Time `json:"read_at"`
 }
 
 type ThreadParticipant struct {
-	UserID      string  `json:"user_id"`
-	DisplayName string  `json:"display_name,omitempty"`
-	AvatarURL   *string `json:"avatar_url,omitempty"`
-	Email       string  `json:"-"`
+	UserID        string  `json:"user_id"`
+	DisplayName   string  `json:"display_name,omitempty"`
+	AvatarURL     *string `json:"avatar_url,omitempty"`
+	Email         string  `json:"-"`
+	IsDeactivated bool    `json:"is_deactivated,omitempty"`
+}
+
+// ThreadParticipantListResult is a page of a thread's participants
+type ThreadParticipantListResult struct {
+	Participants []ThreadParticipant `json:"participants"`
+	TotalCount   int                 `json:"total_count"`
+	HasMore      bool                `json:"has_more"`
+	NextCursor   string              `json:"next_cursor,omitempty"`
 }
 
 type Reaction struct {
@@ -85,6 +140,65 @@ type Reaction struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Reactor is one user's reaction as shown in the who-reacted list
+type Reactor struct {
+	ReactionID    string    `json:"-"`
+	UserID        string    `json:"user_id"`
+	DisplayName   string    `json:"display_name"`
+	AvatarURL     *string   `json:"avatar_url,omitempty"`
+	Email         string    `json:"-"`
+	IsDeactivated bool      `json:"is_deactivated,omitempty"`
+	Emoji         string    `json:"emoji"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ReactorListResult is a page of a message's reactions, oldest first
+type ReactorListResult struct {
+	Reactors   []Reactor `json:"reactors"`
+	HasMore    bool      `json:"has_more"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// ReactionCount is how many people reacted to a message with one emoji, and
+// whether the requesting user is one of them
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+// ChannelActivity is how much a channel was used on each day of a period
+type ChannelActivity struct {
+	Days    []DayCount
+	Total   int
+	Posters int // distinct people who posted during the period
+}
+
+// DayCount is the number of messages posted on one UTC day
+type DayCount struct {
+	Date  string // YYYY-MM-DD
+	Count int
+}
+
+// Reaction batch operations
+const (
+	ReactionOpAdd    = "add"
+	ReactionOpRemove = "remove"
+)
+
+// ReactionOp is a single add or remove in a reaction batch
+type ReactionOp struct {
+	Op    string
+	Emoji string
+}
+
+// ReactionBatchResult is the net change a reaction batch made to one
+// user's reactions on a message
+type ReactionBatchResult struct {
+	Added   []Reaction
+	Removed []string
+}
+
 type ReactionSummary struct {
 	Emoji   string   `json:"emoji"`
 	Count   int      `json:"count"`
@@ -95,12 +209,43 @@ type ListOptions struct {
 	Cursor    string
 	Limit     int
 	Direction string // "before", "after", or "around"
+
+	// VisibleSince hides messages created before this time (private channel
+	// history visibility). Nil means no restriction.
+	VisibleSince *time.Time
+}
+
+// AuthorListOptions selects one author's messages in a channel, newest first
+type AuthorListOptions struct {
+	UserID string
+	Since  *time.Time // inclusive lower bound on created_at
+	Until  *time.Time // exclusive upper bound on created_at
+	Cursor string
+	Limit  int
+
+	// VisibleSince hides messages created before this time (private channel
+	// history visibility). Nil means no restriction.
+	VisibleSince *time.Time
+}
+
+// ArchiveListOptions selects archived messages of a channel.
+type ArchiveListOptions struct {
+	ListOptions
+
+	// ThreadID lists the replies of an archived thread instead of the
+	// channel's messages
+	ThreadID string
+
+	// At jumps to a date: without a cursor, the page is loaded around the
+	// first message created at or after it
+	At *time.Time
 }
 
 type ListResult struct {
 	Messages   []MessageWithUser `json:"messages"`
 	HasMore    bool              `json:"has_more"`
 	HasNewer   bool              `json:"has_newer,omitempty"`
+	HasOlder   bool              `json:"has_older,omitempty"`
 	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
@@ -118,9 +263,10 @@ type UnreadListResult struct {
 
 type ThreadMessage struct {
 	MessageWithUser
-	ChannelName   string `json:"channel_name"`
-	ChannelType   string `json:"channel_type"`
-	HasNewReplies bool   `json:"has_new_replies"`
+	ChannelName      string `json:"channel_name"`
+	ChannelType      string `json:"channel_type"`
+	HasNewReplies    bool   `json:"has_new_replies"`
+	UnreadReplyCount int    `json:"unread_reply_count"`
 }
 
 type ThreadListResult struct {
@@ -137,18 +283,25 @@ type SearchOptions struct {
 	Before    *time.Time
 	After     *time.Time
 	Limit     int
-	Offset    int
+	Cursor    string // NextCursor from the previous page
+	Offset    int    // deprecated: counts every match on every page
 }
 
 type SearchMessage struct {
 	MessageWithUser
 	ChannelName string `json:"channel_name"`
 	ChannelType string `json:"channel_type"`
+	Archived    bool   `json:"archived,omitempty"` // In cold storage; open with ListArchived
 }
 
+// SearchResult is a page of search results. TotalCount is set on the first
+// page and on pages fetched by offset; on the first page of a cursor-paged
+// search it stops at searchCountCap and TotalCountApproximate is set.
 type SearchResult struct {
-	Messages   []SearchMessage `json:"messages"`
-	TotalCount int             `json:"total_count"`
-	HasMore    bool            `json:"has_more"`
-	Query      string          `json:"query"`
+	Messages              []SearchMessage `json:"messages"`
+	TotalCount            *int            `json:"total_count,omitempty"`
+	TotalCountApproximate bool            `json:"total_count_approximate,omitempty"`
+	HasMore               bool            `json:"has_more"`
+	NextCursor            string          `json:"next_cursor,omitempty"`
+	Query                 string          `json:"query"`
 }
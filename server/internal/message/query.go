@@ -0,0 +1,148 @@
+package message
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// searchDateLayout is the layout accepted by the before: and after: operators
+const searchDateLayout = "2006-01-02"
+
+// searchQuery is a search string split into full-text terms and the
+// Slack-style operators that narrow the results
+type searchQuery struct {
+	// terms are the words and quoted phrases matched against the FTS index
+	terms []string
+	// fromUsers are display names or user IDs from from:@user operators
+	fromUsers []string
+	// inChannels are channel names or IDs from in:#channel operators
+	inChannels    []string
+	hasLink       bool
+	hasAttachment bool
+	isThread      bool
+	before        *time.Time
+	after         *time.Time
+}
+
+// hasFilters reports whether any operator narrows the search
+func (q *searchQuery) hasFilters() bool {
+	return len(q.fromUsers) > 0 || len(q.inChannels) > 0 || q.hasLink || q.hasAttachment ||
+		q.isThread || q.before != nil || q.after != nil
+}
+
+// ftsMatch builds the FTS5 MATCH expression for the query's terms. Each
+// word and phrase is quoted to prevent FTS5 syntax injection.
+func (q *searchQuery) ftsMatch() string {
+	quoted := make([]string, 0, len(q.terms))
+	for _, t := range q.terms {
+		t = strings.Join(strings.Fields(strings.ReplaceAll(t, "\"", "")), " ")
+		if t != "" {
+			quoted = append(quoted, "\""+t+"\"")
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// parseSearchQuery splits a search string into terms and operators.
+// Supported operators are from:@user, in:#channel, has:link,
+// has:attachment, is:thread, before:YYYY-MM-DD and after:YYYY-MM-DD, and
+// operator values may be quoted to include spaces (from:@"Jane Doe").
+// Double-quoted text is matched as an exact phrase. Anything that is not a
+// recognised operator, including malformed dates, is searched as text.
+func parseSearchQuery(query string) searchQuery {
+	var q searchQuery
+	for _, tok := range tokenizeSearchQuery(query) {
+		if tok.phrase {
+			q.terms = append(q.terms, tok.text)
+			continue
+		}
+		key, value, ok := strings.Cut(tok.text, ":")
+		if !ok || value == "" || !q.applyOperator(strings.ToLower(key), value) {
+			q.terms = append(q.terms, tok.text)
+		}
+	}
+	return q
+}
+
+// applyOperator records a single operator, reporting false if it is not
+// recognised
+func (q *searchQuery) applyOperator(key, value string) bool {
+	switch key {
+	case "from":
+		q.fromUsers = append(q.fromUsers, strings.TrimPrefix(value, "@"))
+	case "in":
+		q.inChannels = append(q.inChannels, strings.TrimPrefix(value, "#"))
+	case "has":
+		switch strings.ToLower(value) {
+		case "link":
+			q.hasLink = true
+		case "attachment", "file":
+			q.hasAttachment = true
+		default:
+			return false
+		}
+	case "is":
+		if strings.ToLower(value) != "thread" {
+			return false
+		}
+		q.isThread = true
+	case "before", "after":
+		day, err := time.Parse(searchDateLayout, value)
+		if err != nil {
+			return false
+		}
+		if key == "before" {
+			q.before = &day
+		} else {
+			// after: excludes the named day itself
+			next := day.AddDate(0, 0, 1)
+			q.after = &next
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+type searchToken struct {
+	text   string
+	phrase bool
+}
+
+// tokenizeSearchQuery splits on whitespace while keeping double-quoted runs
+// together. A quote opening a token makes it a phrase; a quote inside a
+// token (from:@"Jane Doe") only groups the operator value. An unclosed
+// quote runs to the end of the string.
+func tokenizeSearchQuery(query string) []searchToken {
+	var tokens []searchToken
+	var cur strings.Builder
+	inQuote, phrase := false, false
+
+	flush := func() {
+		if text := strings.TrimSpace(cur.String()); text != "" {
+			tokens = append(tokens, searchToken{text: text, phrase: phrase})
+		}
+		cur.Reset()
+		phrase = false
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			if !inQuote && cur.Len() == 0 {
+				phrase = true
+			}
+			inQuote = !inQuote
+			if !inQuote && phrase {
+				flush()
+			}
+		case !inQuote && unicode.IsSpace(r):
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
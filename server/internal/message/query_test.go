@@ -0,0 +1,59 @@
+package message
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	q := parseSearchQuery(`deploy from:@alice in:#ops has:link has:attachment is:thread before:2024-03-01 after:2024-01-31 "rollback plan"`)
+
+	if want := []string{"deploy", "rollback plan"}; !reflect.DeepEqual(q.terms, want) {
+		t.Errorf("terms = %q, want %q", q.terms, want)
+	}
+	if want := []string{"alice"}; !reflect.DeepEqual(q.fromUsers, want) {
+		t.Errorf("fromUsers = %q, want %q", q.fromUsers, want)
+	}
+	if want := []string{"ops"}; !reflect.DeepEqual(q.inChannels, want) {
+		t.Errorf("inChannels = %q, want %q", q.inChannels, want)
+	}
+	if !q.hasLink || !q.hasAttachment || !q.isThread {
+		t.Errorf("expected has:link, has:attachment and is:thread to be set, got %+v", q)
+	}
+	if q.before == nil || !q.before.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("before = %v, want start of 2024-03-01", q.before)
+	}
+	if q.after == nil || !q.after.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("after = %v, want start of the day following 2024-01-31", q.after)
+	}
+	if got, want := q.ftsMatch(), `"deploy" "rollback plan"`; got != want {
+		t.Errorf("ftsMatch = %q, want %q", got, want)
+	}
+}
+
+func TestParseSearchQuery_QuotedOperatorValue(t *testing.T) {
+	q := parseSearchQuery(`from:@"Jane Doe" standup`)
+	if want := []string{"Jane Doe"}; !reflect.DeepEqual(q.fromUsers, want) {
+		t.Errorf("fromUsers = %q, want %q", q.fromUsers, want)
+	}
+	if want := []string{"standup"}; !reflect.DeepEqual(q.terms, want) {
+		t.Errorf("terms = %q, want %q", q.terms, want)
+	}
+}
+
+func TestParseSearchQuery_UnknownOperatorsAreText(t *testing.T) {
+	q := parseSearchQuery(`col:value has:unicorns before:yesterday from: "unclosed quote`)
+	if q.hasFilters() {
+		t.Errorf("expected no filters, got %+v", q)
+	}
+	want := []string{"col:value", "has:unicorns", "before:yesterday", "from:", "unclosed quote"}
+	if !reflect.DeepEqual(q.terms, want) {
+		t.Errorf("terms = %q, want %q", q.terms, want)
+	}
+	// Quotes never reach the FTS5 expression unescaped
+	stray := parseSearchQuery(`a"b`)
+	if got, want := stray.ftsMatch(), `"ab"`; got != want {
+		t.Errorf("ftsMatch = %q, want %q", got, want)
+	}
+}
@@ -3,14 +3,23 @@ package message
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/telemetry"
 	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 var (
@@ -20,14 +29,55 @@ var (
 	ErrCannotEditMessage     = errors.New("cannot edit this message")
 	ErrCannotEditSystemMsg   = errors.New("cannot edit system messages")
 	ErrCannotDeleteSystemMsg = errors.New("cannot delete system messages")
+	ErrCannotRestoreMessage  = errors.New("message cannot be restored")
+	ErrDuplicateClientMsgID  = errors.New("client message ID already used")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrTooManyPins           = fmt.Errorf("maximum of %d pinned messages per channel", MaxPinnedMessages)
 )
 
+// MaxPinnedMessages is how many messages a channel may have pinned at once
+const MaxPinnedMessages = 50
+
+// DefaultThreadParticipantPreview is how many participants are attached to
+// each thread parent when no preview size has been configured
+const DefaultThreadParticipantPreview = 3
+
 type Repository struct {
 	db *sql.DB
+
+	// participantPreview is how many thread participants are attached to
+	// thread parents in message and thread lists
+	participantPreview int
+
+	// OTel metrics (no-op when telemetry is disabled)
+	searchDuration metric.Float64Histogram
 }
 
+// Pre-computed metric attribute sets for search outcomes.
+var (
+	searchAttrsOK    = metric.WithAttributes(attribute.String("outcome", "ok"))
+	searchAttrsError = metric.WithAttributes(attribute.String("outcome", "error"))
+)
+
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	meter := otel.Meter("enzyme.message")
+	searchDuration, err := meter.Float64Histogram("search.fts.duration",
+		metric.WithDescription("Latency of full-text message search queries"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		slog.Error("failed to create search.fts.duration metric", "error", err)
+	}
+
+	return &Repository{db: db, participantPreview: DefaultThreadParticipantPreview, searchDuration: searchDuration}
+}
+
+// SetThreadParticipantPreview sets how many participants are attached to
+// thread parents. Values below 1 are ignored.
+func (r *Repository) SetThreadParticipantPreview(n int) {
+	if n > 0 {
+		r.participantPreview = n
+	}
 }
 
 func (r *Repository) Create(ctx context.Context, msg *Message) (err error) {
@@ -68,11 +118,25 @@ func (r *Repository) Create(ctx context.Context, msg *Message) (err error) {
 	}
 	defer tx.Rollback()
 
+	// Keys outside the idempotency window are free to be used again
+	if msg.ClientMsgID != nil && msg.UserID != nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE messages SET client_msg_id = NULL
+			WHERE user_id = ? AND client_msg_id = ? AND created_at < ?
+		`, *msg.UserID, *msg.ClientMsgID, now.Add(-ClientMsgIDWindow).Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO messages (id, channel_id, user_id, content, type, system_event, mentions, thread_parent_id, also_send_to_channel, reply_count, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)
-	`, msg.ID, msg.ChannelID, msg.UserID, msg.Content, msg.Type, systemEventJSON, mentionsJSON, msg.ThreadParentID, msg.AlsoSendToChannel, now.Format(time.RFC3339), now.Format(time.RFC3339))
+		INSERT INTO messages (id, channel_id, user_id, content, type, system_event, mentions, thread_parent_id, also_send_to_channel, reply_count, webhook_id, bot_name, bot_avatar_url, announcement_id, client_msg_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?)
+	`, msg.ID, msg.ChannelID, msg.UserID, msg.Content, msg.Type, systemEventJSON, mentionsJSON, msg.ThreadParentID, msg.AlsoSendToChannel, msg.WebhookID, msg.BotName, msg.BotAvatarURL, msg.AnnouncementID, msg.ClientMsgID, now.Format(time.RFC3339), now.Format(time.RFC3339))
 	if err != nil {
+		if isClientMsgIDConflict(err) {
+			return ErrDuplicateClientMsgID
+		}
 		return err
 	}
 
@@ -90,45 +154,13 @@ func (r *Repository) Create(ctx context.Context, msg *Message) (err error) {
 	return tx.Commit()
 }
 
-// CreateSystemMessage creates a system message for channel events
+// CreateSystemMessage creates a system message for channel events. Only the
+// event is stored; its text is rendered in the reader's language when the
+// message is read (see SystemEventData.Text).
 func (r *Repository) CreateSystemMessage(ctx context.Context, channelID string, event *SystemEventData) (*Message, error) {
-	// Build content based on event type
-	content := ""
-	switch event.EventType {
-	case SystemEventUserJoined:
-		content = "joined #" + event.ChannelName
-	case SystemEventUserLeft:
-		content = "left #" + event.ChannelName
-	case SystemEventUserAdded:
-		if event.ActorDisplayName != nil {
-			content = "was added by " + *event.ActorDisplayName
-		} else {
-			content = "was added to #" + event.ChannelName
-		}
-	case SystemEventChannelRenamed:
-		if event.OldChannelName != nil {
-			content = "renamed the channel from #" + *event.OldChannelName + " to #" + event.ChannelName
-		} else {
-			content = "renamed the channel to #" + event.ChannelName
-		}
-	case SystemEventChannelVisibilityChanged:
-		if event.ChannelType != nil {
-			content = "made the channel " + *event.ChannelType
-		} else {
-			content = "changed the channel visibility"
-		}
-	case SystemEventChannelDescriptionUpdated:
-		content = "updated the channel description"
-	case SystemEventMessagePinned:
-		content = "pinned a message to this channel"
-	case SystemEventMessageUnpinned:
-		content = "unpinned a message from this channel"
-	}
-
 	msg := &Message{
 		ChannelID:   channelID,
 		UserID:      &event.UserID,
-		Content:     content,
 		Type:        MessageTypeSystem,
 		SystemEvent: event,
 	}
@@ -140,17 +172,54 @@ func (r *Repository) CreateSystemMessage(ctx context.Context, channelID string,
 	return msg, nil
 }
 
+// UpdateSystemEvent replaces the event data of a system message, such as
+// when a call it announces ends.
+func (r *Repository) UpdateSystemEvent(ctx context.Context, id string, event *SystemEventData) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE messages SET system_event = ?, updated_at = ? WHERE id = ? AND type = ?
+	`, string(data), time.Now().UTC().Format(time.RFC3339), id, MessageTypeSystem)
+	return err
+}
+
+// UpdateMentions stores the mentions of a message sent before they were
+// parsed. Notification counts are adjusted by trigger.
+func (r *Repository) UpdateMentions(ctx context.Context, id string, mentions []string) error {
+	data, err := json.Marshal(mentions)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE messages SET mentions = ? WHERE id = ?
+	`, string(data), id)
+	return err
+}
+
 func (r *Repository) GetByID(ctx context.Context, id string) (*Message, error) {
 	return r.scanMessage(r.db.QueryRowContext(ctx, `
-		SELECT id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel, reply_count, last_reply_at, edited_at, deleted_at, pinned_at, pinned_by, created_at, updated_at
+		SELECT id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel, reply_count, last_reply_at, edited_at, deleted_at, pinned_at, pinned_by, webhook_id, announcement_id, created_at, updated_at
 		FROM messages WHERE id = ?
 	`, id))
 }
 
+// GetByClientMsgID returns the message a user sent with the given idempotency
+// key within the last ClientMsgIDWindow
+func (r *Repository) GetByClientMsgID(ctx context.Context, userID, clientMsgID string) (*Message, error) {
+	return r.scanMessage(r.db.QueryRowContext(ctx, `
+		SELECT id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel, reply_count, last_reply_at, edited_at, deleted_at, pinned_at, pinned_by, webhook_id, announcement_id, created_at, updated_at
+		FROM messages WHERE user_id = ? AND client_msg_id = ? AND created_at >= ?
+	`, userID, clientMsgID, time.Now().UTC().Add(-ClientMsgIDWindow).Format(time.RFC3339)))
+}
+
 func (r *Repository) GetByIDWithUser(ctx context.Context, id string) (*MessageWithUser, error) {
 	row := r.db.QueryRowContext(ctx, `
-		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-		       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+		       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+		       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+		       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 		FROM messages m
 		LEFT JOIN users u ON u.id = m.user_id
 		WHERE m.id = ?
@@ -179,7 +248,9 @@ func (r *Repository) Update(ctx context.Context, id, content string) error {
 	return nil
 }
 
-func (r *Repository) Delete(ctx context.Context, id string) error {
+// Delete soft-deletes a message, keeping its original content aside so it can
+// be restored until the undelete window passes.
+func (r *Repository) Delete(ctx context.Context, id, deletedBy string) error {
 	now := time.Now().UTC()
 
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -199,9 +270,9 @@ func (r *Repository) Delete(ctx context.Context, id string) error {
 	}
 
 	result, err := tx.ExecContext(ctx, `
-		UPDATE messages SET deleted_at = ?, content = '[deleted]', updated_at = ?
+		UPDATE messages SET deleted_at = ?, deleted_by = ?, deleted_content = content, content = '[deleted]', updated_at = ?
 		WHERE id = ? AND deleted_at IS NULL
-	`, now.Format(time.RFC3339), now.Format(time.RFC3339), id)
+	`, now.Format(time.RFC3339), deletedBy, now.Format(time.RFC3339), id)
 	if err != nil {
 		return err
 	}
@@ -224,6 +295,85 @@ func (r *Repository) Delete(ctx context.Context, id string) error {
 	return tx.Commit()
 }
 
+// Restore undeletes a message the author deleted themselves after
+// deletedSince, putting back its original content and, for thread replies,
+// the parent's reply count. The search index follows the content through the
+// FTS update trigger. Messages deleted by someone else, deleted before
+// deletedSince, or already purged return ErrCannotRestoreMessage.
+func (r *Repository) Restore(ctx context.Context, id string, deletedSince time.Time) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.Restore")
+	defer func() { endSpan(err) }()
+	now := time.Now().UTC()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var threadParentID sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		UPDATE messages
+		SET content = deleted_content, deleted_content = NULL, deleted_at = NULL, deleted_by = NULL, updated_at = ?
+		WHERE id = ? AND deleted_at >= ? AND deleted_content IS NOT NULL AND deleted_by = user_id
+		RETURNING thread_parent_id
+	`, now.Format(time.RFC3339), id, deletedSince.UTC().Format(time.RFC3339)).Scan(&threadParentID)
+	if err == sql.ErrNoRows {
+		return ErrCannotRestoreMessage
+	}
+	if err != nil {
+		return err
+	}
+
+	if threadParentID.Valid {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE messages SET reply_count = reply_count + 1, updated_at = ?
+			WHERE id = ?
+		`, now.Format(time.RFC3339), threadParentID.String)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PurgeDeletedContent permanently discards the original content and link
+// previews of messages deleted before the cutoff, after which they can no
+// longer be restored.
+func (r *Repository) PurgeDeletedContent(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.PurgeDeletedContent")
+	defer func() { endSpan(err) }()
+	cutoff := before.UTC().Format(time.RFC3339)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM link_previews WHERE message_id IN (
+			SELECT id FROM messages WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		)
+	`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE messages SET deleted_content = NULL
+		WHERE deleted_at IS NOT NULL AND deleted_at < ? AND deleted_content IS NOT NULL
+	`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (r *Repository) List(ctx context.Context, channelID string, opts ListOptions, filter *moderation.FilterOptions) (_ *ListResult, err error) {
 	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.List")
 	defer func() { endSpan(err) }()
@@ -237,6 +387,7 @@ func (r *Repository) List(ctx context.Context, channelID string, opts ListOption
 	}
 
 	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
+	filterSQL, filterArgs = appendVisibleSince(filterSQL, filterArgs, opts.VisibleSince)
 
 	var query string
 	var args []interface{}
@@ -244,8 +395,10 @@ func (r *Repository) List(ctx context.Context, channelID string, opts ListOption
 	// Get top-level messages and thread replies marked as "also send to channel"
 	if opts.Cursor == "" {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
 			WHERE m.channel_id = ? AND (m.thread_parent_id IS NULL OR m.also_send_to_channel = TRUE)` + filterSQL + `
@@ -256,8 +409,10 @@ func (r *Repository) List(ctx context.Context, channelID string, opts ListOption
 		args = append(args, opts.Limit+1)
 	} else if opts.Direction == "after" {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
 			WHERE m.channel_id = ? AND (m.thread_parent_id IS NULL OR m.also_send_to_channel = TRUE) AND m.id > ?` + filterSQL + `
@@ -268,8 +423,10 @@ func (r *Repository) List(ctx context.Context, channelID string, opts ListOption
 		args = append(args, opts.Limit+1)
 	} else {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
 			WHERE m.channel_id = ? AND (m.thread_parent_id IS NULL OR m.also_send_to_channel = TRUE) AND m.id < ?` + filterSQL + `
@@ -310,7 +467,87 @@ func (r *Repository) List(ctx context.Context, channelID string, opts ListOption
 	}
 
 	// Load reactions and thread participants for all messages
-	r.loadReactionsAndParticipants(ctx, messages, filter)
+	r.loadReactionsAndParticipants(ctx, hotTables, messages, filter)
+
+	if messages == nil {
+		messages = []MessageWithUser{}
+	}
+
+	return &ListResult{
+		Messages:   messages,
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// ListByAuthor lists one user's messages in a channel, including thread
+// replies, newest first. Deleted messages are skipped.
+func (r *Repository) ListByAuthor(ctx context.Context, channelID string, opts AuthorListOptions, filter *moderation.FilterOptions) (_ *ListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.ListByAuthor")
+	defer func() { endSpan(err) }()
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
+	}
+
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
+	filterSQL, filterArgs = appendVisibleSince(filterSQL, filterArgs, opts.VisibleSince)
+	if opts.Since != nil {
+		filterSQL += " AND m.created_at >= ?"
+		filterArgs = append(filterArgs, opts.Since.UTC().Format(time.RFC3339))
+	}
+	if opts.Until != nil {
+		filterSQL += " AND m.created_at < ?"
+		filterArgs = append(filterArgs, opts.Until.UTC().Format(time.RFC3339))
+	}
+	if opts.Cursor != "" {
+		filterSQL += " AND m.id < ?"
+		filterArgs = append(filterArgs, opts.Cursor)
+	}
+
+	query := `
+		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+		       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+		       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+		       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
+		FROM messages m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE m.channel_id = ? AND m.user_id = ? AND m.deleted_at IS NULL` + filterSQL + `
+		ORDER BY m.id DESC
+		LIMIT ?
+	`
+	args := append([]interface{}{channelID, opts.UserID}, filterArgs...)
+	args = append(args, opts.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []MessageWithUser
+	for rows.Next() {
+		msg, err := r.scanMessageWithUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hasMore := len(messages) > opts.Limit
+	if hasMore {
+		messages = messages[:opts.Limit]
+	}
+
+	var nextCursor string
+	if hasMore && len(messages) > 0 {
+		nextCursor = messages[len(messages)-1].ID
+	}
+
+	r.loadReactionsAndParticipants(ctx, hotTables, messages, filter)
 
 	if messages == nil {
 		messages = []MessageWithUser{}
@@ -331,11 +568,14 @@ func (r *Repository) listAround(ctx context.Context, channelID string, opts List
 	}
 
 	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
+	filterSQL, filterArgs = appendVisibleSince(filterSQL, filterArgs, opts.VisibleSince)
 
 	// Query messages at or before cursor (DESC order, includes the cursor message)
 	beforeQuery := `
-		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-		       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+		       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+		       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+		       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 		FROM messages m
 		LEFT JOIN users u ON u.id = m.user_id
 		WHERE m.channel_id = ? AND (m.thread_parent_id IS NULL OR m.also_send_to_channel = TRUE) AND m.id <= ?` + filterSQL + `
@@ -369,8 +609,10 @@ func (r *Repository) listAround(ctx context.Context, channelID string, opts List
 
 	// Query messages after cursor (ASC order)
 	afterQuery := `
-		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-		       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+		       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+		       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+		       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 		FROM messages m
 		LEFT JOIN users u ON u.id = m.user_id
 		WHERE m.channel_id = ? AND (m.thread_parent_id IS NULL OR m.also_send_to_channel = TRUE) AND m.id > ?` + filterSQL + `
@@ -417,7 +659,7 @@ func (r *Repository) listAround(ctx context.Context, channelID string, opts List
 	}
 
 	// Load reactions and thread participants
-	r.loadReactionsAndParticipants(ctx, messages, filter)
+	r.loadReactionsAndParticipants(ctx, hotTables, messages, filter)
 
 	if messages == nil {
 		messages = []MessageWithUser{}
@@ -431,8 +673,17 @@ func (r *Repository) listAround(ctx context.Context, channelID string, opts List
 	}, nil
 }
 
-// loadReactionsAndParticipants loads reactions and thread participants for a slice of messages.
-func (r *Repository) loadReactionsAndParticipants(ctx context.Context, messages []MessageWithUser, filter *moderation.FilterOptions) {
+// appendVisibleSince adds a created_at lower bound to a filter clause.
+func appendVisibleSince(filterSQL string, filterArgs []interface{}, since *time.Time) (string, []interface{}) {
+	if since == nil {
+		return filterSQL, filterArgs
+	}
+	return filterSQL + " AND m.created_at >= ?", append(filterArgs, since.UTC().Format(time.RFC3339))
+}
+
+// loadReactionsAndParticipants loads reactions and thread participants for a
+// slice of messages stored in t.
+func (r *Repository) loadReactionsAndParticipants(ctx context.Context, t messageTables, messages []MessageWithUser, filter *moderation.FilterOptions) {
 	if len(messages) == 0 {
 		return
 	}
@@ -445,7 +696,7 @@ func (r *Repository) loadReactionsAndParticipants(ctx context.Context, messages
 			threadParentIDs = append(threadParentIDs, m.ID)
 		}
 	}
-	reactions, err := r.getReactionsForMessages(ctx, messageIDs, filter)
+	reactions, err := r.getReactionsForMessages(ctx, t, messageIDs, filter)
 	if err != nil {
 		return
 	}
@@ -457,55 +708,147 @@ func (r *Repository) loadReactionsAndParticipants(ctx context.Context, messages
 
 	// Load thread participants for messages with replies
 	if len(threadParentIDs) > 0 {
-		participants, err := r.getThreadParticipantsForMessages(ctx, threadParentIDs, filter)
+		participants, participantCounts, err := r.getThreadParticipantsForMessages(ctx, t, threadParentIDs, filter)
 		if err != nil {
 			return
 		}
 		for i := range messages {
 			if p, ok := participants[messages[i].ID]; ok {
 				messages[i].ThreadParticipants = p
+				messages[i].ThreadParticipantCount = participantCounts[messages[i].ID]
 			}
 		}
 	}
 }
 
-func (r *Repository) ListThread(ctx context.Context, parentID string, opts ListOptions, filter *moderation.FilterOptions) (*ListResult, error) {
+// ListThread lists the replies to parentID a page at a time, each page in
+// chronological order. Without a cursor it returns the newest page, or the
+// first one when Direction is "after". With a cursor it pages "before" or
+// "after" it, or loads replies "around" it; a cursor without a direction
+// pages after it, as the thread list always has.
+func (r *Repository) ListThread(ctx context.Context, parentID string, opts ListOptions, filter *moderation.FilterOptions) (_ *ListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.ListThread")
+	defer func() { endSpan(err) }()
 	if opts.Limit <= 0 || opts.Limit > 100 {
 		opts.Limit = 50
 	}
 
 	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
+	filterSQL, filterArgs = appendVisibleSince(filterSQL, filterArgs, opts.VisibleSince)
 
-	var query string
-	var args []interface{}
+	direction := opts.Direction
+	if direction == "" {
+		direction = "before"
+		if opts.Cursor != "" {
+			direction = "after"
+		}
+	}
 
-	if opts.Cursor == "" {
-		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
-			FROM messages m
-			LEFT JOIN users u ON u.id = m.user_id
-			WHERE m.thread_parent_id = ?` + filterSQL + `
-			ORDER BY m.id ASC
-			LIMIT ?
-		`
-		args = append([]interface{}{parentID}, filterArgs...)
-		args = append(args, opts.Limit+1)
-	} else {
-		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
-			FROM messages m
-			LEFT JOIN users u ON u.id = m.user_id
-			WHERE m.thread_parent_id = ? AND m.id > ?` + filterSQL + `
-			ORDER BY m.id ASC
-			LIMIT ?
-		`
-		args = append([]interface{}{parentID, opts.Cursor}, filterArgs...)
-		args = append(args, opts.Limit+1)
+	result := &ListResult{}
+	var messages []MessageWithUser
+	switch {
+	case direction == "around" && opts.Cursor != "":
+		half := max(opts.Limit/2, 1)
+		older, err := r.queryThread(ctx, parentID, "m.id <= ?", opts.Cursor, "DESC", half+1, filterSQL, filterArgs)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasOlder = len(older) > half; result.HasOlder {
+			older = older[:half]
+		}
+		newer, err := r.queryThread(ctx, parentID, "m.id > ?", opts.Cursor, "ASC", half+1, filterSQL, filterArgs)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasNewer = len(newer) > half; result.HasNewer {
+			newer = newer[:half]
+		}
+		slices.Reverse(older)
+		messages = append(older, newer...)
+		result.HasMore = result.HasOlder
+
+	case direction == "after":
+		messages, err = r.queryThread(ctx, parentID, "m.id > ?", opts.Cursor, "ASC", opts.Limit+1, filterSQL, filterArgs)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasNewer = len(messages) > opts.Limit; result.HasNewer {
+			messages = messages[:opts.Limit]
+			result.NextCursor = messages[len(messages)-1].ID
+		}
+		result.HasMore = result.HasNewer
+		if opts.Cursor != "" {
+			if result.HasOlder, err = r.threadHasReplies(ctx, parentID, "m.id <= ?", opts.Cursor, filterSQL, filterArgs); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		messages, err = r.queryThread(ctx, parentID, "m.id < ?", opts.Cursor, "DESC", opts.Limit+1, filterSQL, filterArgs)
+		if err != nil {
+			return nil, err
+		}
+		if result.HasOlder = len(messages) > opts.Limit; result.HasOlder {
+			messages = messages[:opts.Limit]
+		}
+		slices.Reverse(messages)
+		if result.HasOlder {
+			result.NextCursor = messages[0].ID
+		}
+		result.HasMore = result.HasOlder
+		if opts.Cursor != "" {
+			if result.HasNewer, err = r.threadHasReplies(ctx, parentID, "m.id >= ?", opts.Cursor, filterSQL, filterArgs); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	// Load reactions
+	if len(messages) > 0 {
+		messageIDs := make([]string, len(messages))
+		for i, m := range messages {
+			messageIDs[i] = m.ID
+		}
+		reactions, err := r.getReactionsForMessages(ctx, hotTables, messageIDs, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := range messages {
+			if r, ok := reactions[messages[i].ID]; ok {
+				messages[i].Reactions = r
+			}
+		}
+	}
+
+	if messages == nil {
+		messages = []MessageWithUser{}
+	}
+	result.Messages = messages
+	return result, nil
+}
+
+// queryThread loads up to limit replies to parentID in the given order,
+// bounded by the cursor if there is one.
+func (r *Repository) queryThread(ctx context.Context, parentID, bound, cursor, order string, limit int, filterSQL string, filterArgs []interface{}) ([]MessageWithUser, error) {
+	args := []interface{}{parentID}
+	where := "m.thread_parent_id = ?"
+	if cursor != "" {
+		where += " AND " + bound
+		args = append(args, cursor)
+	}
+	args = append(append(args, filterArgs...), limit)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+		       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+		       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+		       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
+		FROM messages m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE `+where+filterSQL+`
+		ORDER BY m.id `+order+`
+		LIMIT ?
+	`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -513,114 +856,339 @@ func (r *Repository) ListThread(ctx context.Context, parentID string, opts ListO
 
 	var messages []MessageWithUser
 	for rows.Next() {
-		msg, err := r.scanMessageWithUser(rows)
-		if err != nil {
+		msg, err := r.scanMessageWithUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *msg)
+	}
+	return messages, rows.Err()
+}
+
+// threadHasReplies reports whether any reply to parentID that the filter lets
+// through lies within bound of the cursor.
+func (r *Repository) threadHasReplies(ctx context.Context, parentID, bound, cursor string, filterSQL string, filterArgs []interface{}) (bool, error) {
+	args := append([]interface{}{parentID, cursor}, filterArgs...)
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM messages m
+			WHERE m.thread_parent_id = ? AND `+bound+filterSQL+`
+		)
+	`, args...).Scan(&exists)
+	return exists, err
+}
+
+func (r *Repository) AddReaction(ctx context.Context, messageID, userID, emoji string) (*Reaction, error) {
+	id := ulid.Make().String()
+	now := time.Now().UTC()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO reactions (id, message_id, user_id, emoji, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, id, messageID, userID, emoji, now.Format(time.RFC3339))
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return nil, ErrReactionExists
+		}
+		return nil, err
+	}
+
+	return &Reaction{
+		ID:        id,
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: now,
+	}, nil
+}
+
+func (r *Repository) RemoveReaction(ctx context.Context, messageID, userID, emoji string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM reactions WHERE message_id = ? AND user_id = ? AND emoji = ?
+	`, messageID, userID, emoji)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrReactionNotFound
+	}
+	return nil
+}
+
+// ApplyReactionBatch applies add/remove operations for one user in order,
+// in a single transaction. Adding an existing reaction or removing a missing
+// one is a no-op. The result is the net change across the whole batch.
+func (r *Repository) ApplyReactionBatch(ctx context.Context, messageID, userID string, ops []ReactionOp) (_ *ReactionBatchResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.ApplyReactionBatch")
+	defer func() { endSpan(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := userReactionsTx(ctx, tx, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, op := range ops {
+		switch op.Op {
+		case ReactionOpAdd:
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO reactions (id, message_id, user_id, emoji, created_at)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (message_id, user_id, emoji) DO NOTHING
+			`, ulid.Make().String(), messageID, userID, op.Emoji, now)
+		case ReactionOpRemove:
+			_, err = tx.ExecContext(ctx, `
+				DELETE FROM reactions WHERE message_id = ? AND user_id = ? AND emoji = ?
+			`, messageID, userID, op.Emoji)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	after, err := userReactionsTx(ctx, tx, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result := &ReactionBatchResult{Added: []Reaction{}, Removed: []string{}}
+	for _, reaction := range after {
+		if _, ok := before[reaction.Emoji]; !ok {
+			result.Added = append(result.Added, reaction)
+		}
+	}
+	for emoji := range before {
+		if _, ok := after[emoji]; !ok {
+			result.Removed = append(result.Removed, emoji)
+		}
+	}
+	sort.Slice(result.Added, func(i, j int) bool { return result.Added[i].Emoji < result.Added[j].Emoji })
+	sort.Strings(result.Removed)
+	return result, nil
+}
+
+// userReactionsTx returns one user's reactions on a message, keyed by emoji
+func userReactionsTx(ctx context.Context, tx *sql.Tx, messageID, userID string) (map[string]Reaction, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, emoji, created_at FROM reactions WHERE message_id = ? AND user_id = ?
+	`, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := make(map[string]Reaction)
+	for rows.Next() {
+		reaction := Reaction{MessageID: messageID, UserID: userID}
+		var createdAt string
+		if err := rows.Scan(&reaction.ID, &reaction.Emoji, &createdAt); err != nil {
+			return nil, err
+		}
+		reaction.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		reactions[reaction.Emoji] = reaction
+	}
+	return reactions, rows.Err()
+}
+
+// GetReactionsForMessage returns reactions for a single message
+func (r *Repository) GetReactionsForMessage(ctx context.Context, messageID string, filter *moderation.FilterOptions) ([]Reaction, error) {
+	reactions, err := r.getReactionsForMessages(ctx, hotTables, []string{messageID}, filter)
+	if err != nil {
+		return nil, err
+	}
+	return reactions[messageID], nil
+}
+
+// ListReactors returns a page of the users who reacted to a message, oldest
+// reaction first, optionally limited to one emoji
+func (r *Repository) ListReactors(ctx context.Context, messageID, emoji string, opts ListOptions, filter *moderation.FilterOptions) (_ *ReactorListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.ListReactors")
+	defer func() { endSpan(err) }()
+
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
+	}
+
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "r.user_id")
+	query := `
+		SELECT r.id, r.user_id, COALESCE(u.display_name, 'Former member') as display_name, u.avatar_url, COALESCE(u.email, '') as email,
+		       (u.id IS NULL OR u.status = 'deactivated') as is_deactivated, r.emoji, r.created_at
+		FROM reactions r
+		LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.message_id = ?` + filterSQL
+	args := append([]interface{}{messageID}, filterArgs...)
+	if emoji != "" {
+		query += " AND r.emoji = ?"
+		args = append(args, emoji)
+	}
+	if opts.Cursor != "" {
+		query += " AND r.id > ?"
+		args = append(args, opts.Cursor)
+	}
+	query += " ORDER BY r.id LIMIT ?"
+	args = append(args, opts.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactors := []Reactor{}
+	for rows.Next() {
+		var rc Reactor
+		var avatarURL sql.NullString
+		var createdAt string
+		if err := rows.Scan(&rc.ReactionID, &rc.UserID, &rc.DisplayName, &avatarURL, &rc.Email, &rc.IsDeactivated, &rc.Emoji, &createdAt); err != nil {
 			return nil, err
 		}
-		messages = append(messages, *msg)
+		if avatarURL.Valid {
+			rc.AvatarURL = &avatarURL.String
+		}
+		rc.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		reactors = append(reactors, rc)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	hasMore := len(messages) > opts.Limit
-	if hasMore {
-		messages = messages[:opts.Limit]
+	result := &ReactorListResult{}
+	if len(reactors) > opts.Limit {
+		reactors = reactors[:opts.Limit]
+		result.HasMore = true
+		result.NextCursor = reactors[opts.Limit-1].ReactionID
 	}
+	result.Reactors = reactors
+	return result, nil
+}
 
-	var nextCursor string
-	if hasMore && len(messages) > 0 {
-		nextCursor = messages[len(messages)-1].ID
+// CountReactions returns each emoji's reaction count on a message in the
+// order the emoji were first used, and whether userID reacted with it
+func (r *Repository) CountReactions(ctx context.Context, messageID, userID string, filter *moderation.FilterOptions) ([]ReactionCount, error) {
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "user_id")
+	args := append([]interface{}{userID, messageID}, filterArgs...)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT emoji, COUNT(*), MAX(user_id = ?)
+		FROM reactions
+		WHERE message_id = ?`+filterSQL+`
+		GROUP BY emoji
+		ORDER BY MIN(id)
+	`, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Load reactions
-	if len(messages) > 0 {
-		messageIDs := make([]string, len(messages))
-		for i, m := range messages {
-			messageIDs[i] = m.ID
-		}
-		reactions, err := r.getReactionsForMessages(ctx, messageIDs, filter)
-		if err != nil {
+	counts := []ReactionCount{}
+	for rows.Next() {
+		var c ReactionCount
+		if err := rows.Scan(&c.Emoji, &c.Count, &c.Reacted); err != nil {
 			return nil, err
 		}
-		for i := range messages {
-			if r, ok := reactions[messages[i].ID]; ok {
-				messages[i].Reactions = r
-			}
-		}
+		counts = append(counts, c)
 	}
+	return counts, rows.Err()
+}
 
-	if messages == nil {
-		messages = []MessageWithUser{}
+// GetThreadParticipants returns the participant preview and total participant
+// count for a single parent message
+func (r *Repository) GetThreadParticipants(ctx context.Context, parentID string, filter *moderation.FilterOptions) ([]ThreadParticipant, int, error) {
+	participants, counts, err := r.getThreadParticipantsForMessages(ctx, hotTables, []string{parentID}, filter)
+	if err != nil {
+		return nil, 0, err
 	}
-
-	return &ListResult{
-		Messages:   messages,
-		HasMore:    hasMore,
-		NextCursor: nextCursor,
-	}, nil
+	return participants[parentID], counts[parentID], nil
 }
 
-func (r *Repository) AddReaction(ctx context.Context, messageID, userID, emoji string) (*Reaction, error) {
-	id := ulid.Make().String()
-	now := time.Now().UTC()
+// ListThreadParticipants returns a page of everyone who has replied to a
+// thread, ordered by their first reply
+func (r *Repository) ListThreadParticipants(ctx context.Context, parentID string, opts ListOptions, filter *moderation.FilterOptions) (_ *ThreadParticipantListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.ListThreadParticipants")
+	defer func() { endSpan(err) }()
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO reactions (id, message_id, user_id, emoji, created_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, id, messageID, userID, emoji, now.Format(time.RFC3339))
-	if err != nil {
-		if isUniqueConstraintError(err) {
-			return nil, ErrReactionExists
-		}
-		return nil, err
+	if opts.Limit <= 0 || opts.Limit > 100 {
+		opts.Limit = 50
 	}
 
-	return &Reaction{
-		ID:        id,
-		MessageID: messageID,
-		UserID:    userID,
-		Emoji:     emoji,
-		CreatedAt: now,
-	}, nil
-}
+	filterSQL, filterArgs := moderation.FilterSQL(filter, "user_id")
 
-func (r *Repository) RemoveReaction(ctx context.Context, messageID, userID, emoji string) error {
-	result, err := r.db.ExecContext(ctx, `
-		DELETE FROM reactions WHERE message_id = ? AND user_id = ? AND emoji = ?
-	`, messageID, userID, emoji)
+	var total int
+	countArgs := append([]interface{}{parentID}, filterArgs...)
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT user_id) FROM messages
+		WHERE thread_parent_id = ? AND user_id IS NOT NULL`+filterSQL,
+		countArgs...).Scan(&total)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	rows, _ := result.RowsAffected()
-	if rows == 0 {
-		return ErrReactionNotFound
+
+	query := `
+		SELECT m.first_reply_id, m.user_id, COALESCE(u.display_name, 'Former member') as display_name, u.avatar_url, COALESCE(u.email, '') as email,
+		       (u.id IS NULL OR u.status = 'deactivated') as is_deactivated
+		FROM (
+			SELECT user_id, MIN(id) as first_reply_id
+			FROM messages
+			WHERE thread_parent_id = ? AND user_id IS NOT NULL` + filterSQL + `
+			GROUP BY user_id
+		) m
+		LEFT JOIN users u ON u.id = m.user_id
+	`
+	args := append([]interface{}{parentID}, filterArgs...)
+	if opts.Cursor != "" {
+		query += " WHERE m.first_reply_id > ?"
+		args = append(args, opts.Cursor)
 	}
-	return nil
-}
+	query += " ORDER BY m.first_reply_id LIMIT ?"
+	args = append(args, opts.Limit+1)
 
-// GetReactionsForMessage returns reactions for a single message
-func (r *Repository) GetReactionsForMessage(ctx context.Context, messageID string, filter *moderation.FilterOptions) ([]Reaction, error) {
-	reactions, err := r.getReactionsForMessages(ctx, []string{messageID}, filter)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	return reactions[messageID], nil
-}
+	defer rows.Close()
 
-// GetThreadParticipants returns thread participants for a single parent message
-func (r *Repository) GetThreadParticipants(ctx context.Context, parentID string, filter *moderation.FilterOptions) ([]ThreadParticipant, error) {
-	participants, err := r.getThreadParticipantsForMessages(ctx, []string{parentID}, filter)
-	if err != nil {
+	participants := []ThreadParticipant{}
+	var cursors []string
+	for rows.Next() {
+		var firstReplyID string
+		p, err := scanThreadParticipant(rows, &firstReplyID)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+		cursors = append(cursors, firstReplyID)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return participants[parentID], nil
+
+	result := &ThreadParticipantListResult{TotalCount: total}
+	if len(participants) > opts.Limit {
+		participants = participants[:opts.Limit]
+		result.HasMore = true
+		result.NextCursor = cursors[opts.Limit-1]
+	}
+	result.Participants = participants
+	return result, nil
 }
 
-func (r *Repository) getThreadParticipantsForMessages(ctx context.Context, messageIDs []string, filter *moderation.FilterOptions) (map[string][]ThreadParticipant, error) {
+// getThreadParticipantsForMessages returns a preview of the first participants
+// of each thread, plus the total number of distinct participants per thread
+func (r *Repository) getThreadParticipantsForMessages(ctx context.Context, t messageTables, messageIDs []string, filter *moderation.FilterOptions) (map[string][]ThreadParticipant, map[string]int, error) {
 	if len(messageIDs) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	placeholders := make([]string, len(messageIDs))
@@ -632,12 +1200,13 @@ func (r *Repository) getThreadParticipantsForMessages(ctx context.Context, messa
 
 	filterSQL, filterArgs := moderation.FilterSQL(filter, "user_id")
 
-	// Get distinct users who replied to each thread, ordered by first reply, limited to 3
+	// Get distinct users who replied to each thread, ordered by first reply
 	query := `
-		SELECT m.thread_parent_id, m.user_id, COALESCE(u.display_name, '') as display_name, u.avatar_url, COALESCE(u.email, '') as email
+		SELECT m.thread_parent_id, m.user_id, COALESCE(u.display_name, 'Former member') as display_name, u.avatar_url, COALESCE(u.email, '') as email,
+		       (u.id IS NULL OR u.status = 'deactivated') as is_deactivated
 		FROM (
 			SELECT thread_parent_id, user_id, MIN(id) as first_reply_id
-			FROM messages
+			FROM ` + t.messages + `
 			WHERE thread_parent_id IN (` + strings.Join(placeholders, ",") + `) AND user_id IS NOT NULL` + filterSQL + `
 			GROUP BY thread_parent_id, user_id
 		) m
@@ -648,37 +1217,46 @@ func (r *Repository) getThreadParticipantsForMessages(ctx context.Context, messa
 	args = append(args, filterArgs...)
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	participants := make(map[string][]ThreadParticipant)
+	counts := make(map[string]int)
 	for rows.Next() {
-		var parentID, userID, displayName string
-		var avatarURL, email sql.NullString
-		if err := rows.Scan(&parentID, &userID, &displayName, &avatarURL, &email); err != nil {
-			return nil, err
+		var parentID string
+		p, err := scanThreadParticipant(rows, &parentID)
+		if err != nil {
+			return nil, nil, err
 		}
-		// Limit to 3 participants per thread
-		if len(participants[parentID]) < 3 {
-			p := ThreadParticipant{
-				UserID:      userID,
-				DisplayName: displayName,
-			}
-			if avatarURL.Valid {
-				p.AvatarURL = &avatarURL.String
-			}
-			if email.Valid {
-				p.Email = email.String
-			}
+		counts[parentID]++
+		// Only the first few participants are attached as a preview
+		if len(participants[parentID]) < r.participantPreview {
 			participants[parentID] = append(participants[parentID], p)
 		}
 	}
 
-	return participants, rows.Err()
+	return participants, counts, rows.Err()
+}
+
+// scanThreadParticipant scans a participant row whose first column (a thread
+// parent ID or cursor) is written to key
+func scanThreadParticipant(rows *sql.Rows, key *string) (ThreadParticipant, error) {
+	var p ThreadParticipant
+	var avatarURL, email sql.NullString
+	if err := rows.Scan(key, &p.UserID, &p.DisplayName, &avatarURL, &email, &p.IsDeactivated); err != nil {
+		return p, err
+	}
+	if avatarURL.Valid {
+		p.AvatarURL = &avatarURL.String
+	}
+	if email.Valid {
+		p.Email = email.String
+	}
+	return p, nil
 }
 
-func (r *Repository) getReactionsForMessages(ctx context.Context, messageIDs []string, filter *moderation.FilterOptions) (map[string][]Reaction, error) {
+func (r *Repository) getReactionsForMessages(ctx context.Context, t messageTables, messageIDs []string, filter *moderation.FilterOptions) (map[string][]Reaction, error) {
 	if len(messageIDs) == 0 {
 		return nil, nil
 	}
@@ -694,7 +1272,7 @@ func (r *Repository) getReactionsForMessages(ctx context.Context, messageIDs []s
 
 	query := `
 		SELECT id, message_id, user_id, emoji, created_at
-		FROM reactions
+		FROM ` + t.reactions + `
 		WHERE message_id IN (` + strings.Join(placeholders, ",") + `)` + filterSQL + `
 		ORDER BY created_at
 	`
@@ -723,10 +1301,10 @@ func (r *Repository) getReactionsForMessages(ctx context.Context, messageIDs []s
 
 func (r *Repository) scanMessage(row *sql.Row) (*Message, error) {
 	var msg Message
-	var userID, threadParentID, lastReplyAt, editedAt, deletedAt, pinnedAt, pinnedBy, systemEventJSON sql.NullString
+	var userID, threadParentID, lastReplyAt, editedAt, deletedAt, pinnedAt, pinnedBy, webhookID, announcementID, systemEventJSON sql.NullString
 	var createdAt, updatedAt string
 
-	err := row.Scan(&msg.ID, &msg.ChannelID, &userID, &msg.Content, &msg.Type, &systemEventJSON, &threadParentID, &msg.AlsoSendToChannel, &msg.ReplyCount, &lastReplyAt, &editedAt, &deletedAt, &pinnedAt, &pinnedBy, &createdAt, &updatedAt)
+	err := row.Scan(&msg.ID, &msg.ChannelID, &userID, &msg.Content, &msg.Type, &systemEventJSON, &threadParentID, &msg.AlsoSendToChannel, &msg.ReplyCount, &lastReplyAt, &editedAt, &deletedAt, &pinnedAt, &pinnedBy, &webhookID, &announcementID, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return nil, ErrMessageNotFound
 	}
@@ -770,6 +1348,12 @@ func (r *Repository) scanMessage(row *sql.Row) (*Message, error) {
 	if pinnedBy.Valid {
 		msg.PinnedBy = &pinnedBy.String
 	}
+	if webhookID.Valid {
+		msg.WebhookID = &webhookID.String
+	}
+	if announcementID.Valid {
+		msg.AnnouncementID = &announcementID.String
+	}
 	msg.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	msg.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -782,11 +1366,11 @@ type rowScanner interface {
 
 func (r *Repository) scanMessageWithUser(row rowScanner) (*MessageWithUser, error) {
 	var msg MessageWithUser
-	var userID, threadParentID, lastReplyAt, editedAt, deletedAt, pinnedAt, pinnedBy, avatarURL, userEmail, systemEventJSON sql.NullString
+	var userID, threadParentID, lastReplyAt, editedAt, deletedAt, pinnedAt, pinnedBy, webhookID, announcementID, avatarURL, userEmail, systemEventJSON sql.NullString
 	var createdAt, updatedAt string
 
-	err := row.Scan(&msg.ID, &msg.ChannelID, &userID, &msg.Content, &msg.Type, &systemEventJSON, &threadParentID, &msg.AlsoSendToChannel, &msg.ReplyCount, &lastReplyAt, &editedAt, &deletedAt, &pinnedAt, &pinnedBy, &createdAt, &updatedAt,
-		&msg.UserDisplayName, &avatarURL, &userEmail)
+	err := row.Scan(&msg.ID, &msg.ChannelID, &userID, &msg.Content, &msg.Type, &systemEventJSON, &threadParentID, &msg.AlsoSendToChannel, &msg.ReplyCount, &lastReplyAt, &editedAt, &deletedAt, &pinnedAt, &pinnedBy, &webhookID, &announcementID, &createdAt, &updatedAt,
+		&msg.UserDisplayName, &avatarURL, &userEmail, &msg.UserIsDeactivated, &msg.IsBot)
 	if err != nil {
 		return nil, err
 	}
@@ -827,6 +1411,12 @@ func (r *Repository) scanMessageWithUser(row rowScanner) (*MessageWithUser, erro
 	if pinnedBy.Valid {
 		msg.PinnedBy = &pinnedBy.String
 	}
+	if webhookID.Valid {
+		msg.WebhookID = &webhookID.String
+	}
+	if announcementID.Valid {
+		msg.AnnouncementID = &announcementID.String
+	}
 	if avatarURL.Valid {
 		msg.UserAvatarURL = &avatarURL.String
 	}
@@ -843,6 +1433,17 @@ func isUniqueConstraintError(err error) bool {
 	return err != nil && (strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key"))
 }
 
+// isClientMsgIDConflict reports whether err is a violation of
+// idx_messages_client_msg_id rather than of any other unique constraint.
+// SQLite names the index's columns, other databases the index itself.
+func isClientMsgIDConflict(err error) bool {
+	if !isUniqueConstraintError(err) {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "messages.user_id, messages.client_msg_id") || strings.Contains(msg, "idx_messages_client_msg_id")
+}
+
 // ListAllUnreads lists all unread messages across channels the user is a member of
 func (r *Repository) ListAllUnreads(ctx context.Context, workspaceID, userID string, opts ListOptions, filter *moderation.FilterOptions) (*UnreadListResult, error) {
 	if opts.Limit <= 0 || opts.Limit > 100 {
@@ -857,8 +1458,10 @@ func (r *Repository) ListAllUnreads(ctx context.Context, workspaceID, userID str
 	// Get messages from channels user is a member of that are newer than last_read_message_id
 	if opts.Cursor == "" {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email,
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot,
 			       c.name as channel_name, c.type as channel_type
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
@@ -875,8 +1478,10 @@ func (r *Repository) ListAllUnreads(ctx context.Context, workspaceID, userID str
 		args = append(args, opts.Limit+1)
 	} else {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email,
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot,
 			       c.name as channel_name, c.type as channel_type
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
@@ -931,7 +1536,7 @@ func (r *Repository) ListAllUnreads(ctx context.Context, workspaceID, userID str
 		for i, m := range messages {
 			messageIDs[i] = m.ID
 		}
-		reactions, err := r.getReactionsForMessages(ctx, messageIDs, filter)
+		reactions, err := r.getReactionsForMessages(ctx, hotTables, messageIDs, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -953,16 +1558,17 @@ func (r *Repository) ListAllUnreads(ctx context.Context, workspaceID, userID str
 	}, nil
 }
 
-// scanMessageColumns holds the raw scanned values from the standard 21-column
+// scanMessageColumns holds the raw scanned values from the standard 25-column
 // message+user+channel SELECT. Call scanDest to get scan targets, then
 // hydrate to populate a MessageWithUser.
 type scanMessageColumns struct {
-	userID, threadParentID, lastReplyAt, editedAt, deletedAt  sql.NullString
-	pinnedAt, pinnedBy, avatarURL, userEmail, systemEventJSON sql.NullString
-	createdAt, updatedAt, channelName, channelType            string
+	userID, threadParentID, lastReplyAt, editedAt, deletedAt sql.NullString
+	pinnedAt, pinnedBy, webhookID, avatarURL, userEmail      sql.NullString
+	announcementID, systemEventJSON                          sql.NullString
+	createdAt, updatedAt, channelName, channelType           string
 }
 
-// scanDest returns the scan destinations for the standard 21-column SELECT,
+// scanDest returns the scan destinations for the standard 25-column SELECT,
 // writing directly into msg fields and the scanMessageColumns temporaries.
 // The returned slice is always at full capacity (len == cap) so callers can
 // safely append extra destinations (e.g. &totalCount) without aliasing.
@@ -970,9 +1576,9 @@ func (s *scanMessageColumns) scanDest(msg *MessageWithUser) []interface{} {
 	return []interface{}{
 		&msg.ID, &msg.ChannelID, &s.userID, &msg.Content, &msg.Type, &s.systemEventJSON,
 		&s.threadParentID, &msg.AlsoSendToChannel, &msg.ReplyCount,
-		&s.lastReplyAt, &s.editedAt, &s.deletedAt, &s.pinnedAt, &s.pinnedBy,
+		&s.lastReplyAt, &s.editedAt, &s.deletedAt, &s.pinnedAt, &s.pinnedBy, &s.webhookID, &s.announcementID,
 		&s.createdAt, &s.updatedAt,
-		&msg.UserDisplayName, &s.avatarURL, &s.userEmail,
+		&msg.UserDisplayName, &s.avatarURL, &s.userEmail, &msg.UserIsDeactivated, &msg.IsBot,
 		&s.channelName, &s.channelType,
 	}
 }
@@ -1013,6 +1619,12 @@ func (s *scanMessageColumns) hydrate(msg *MessageWithUser) {
 	if s.pinnedBy.Valid {
 		msg.PinnedBy = &s.pinnedBy.String
 	}
+	if s.webhookID.Valid {
+		msg.WebhookID = &s.webhookID.String
+	}
+	if s.announcementID.Valid {
+		msg.AnnouncementID = &s.announcementID.String
+	}
 	if s.avatarURL.Valid {
 		msg.UserAvatarURL = &s.avatarURL.String
 	}
@@ -1034,24 +1646,21 @@ func (r *Repository) scanUnreadMessage(row rowScanner) (*UnreadMessage, string,
 	return &msg, cols.channelName, cols.channelType, nil
 }
 
-// sanitizeFTSQuery quotes each word in the query to prevent FTS5 syntax injection
-func sanitizeFTSQuery(query string) string {
-	words := strings.Fields(query)
-	quoted := make([]string, 0, len(words))
-	for _, w := range words {
-		// Remove any double quotes to prevent injection, then quote the word
-		w = strings.ReplaceAll(w, "\"", "")
-		if w != "" {
-			quoted = append(quoted, "\""+w+"\"")
-		}
-	}
-	return strings.Join(quoted, " ")
-}
-
-// Search searches messages across channels in a workspace using FTS5
+// Search searches messages across channels in a workspace using FTS5. The
+// query may contain operators (see parseSearchQuery), which are applied on
+// top of the structured filters in opts; a query made up only of operators
+// lists matching messages newest first.
 func (r *Repository) Search(ctx context.Context, workspaceID, currentUserID string, opts SearchOptions, filter *moderation.FilterOptions) (_ *SearchResult, err error) {
 	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.Search")
 	defer func() { endSpan(err) }()
+	start := time.Now()
+	defer func() {
+		attrs := searchAttrsOK
+		if err != nil {
+			attrs = searchAttrsError
+		}
+		r.searchDuration.Record(ctx, float64(time.Since(start).Microseconds())/1000, attrs)
+	}()
 	if opts.Limit <= 0 || opts.Limit > 100 {
 		opts.Limit = 20
 	}
@@ -1059,8 +1668,9 @@ func (r *Repository) Search(ctx context.Context, workspaceID, currentUserID stri
 		opts.Offset = 0
 	}
 
-	sanitized := sanitizeFTSQuery(opts.Query)
-	if sanitized == "" {
+	parsed := parseSearchQuery(opts.Query)
+	match := parsed.ftsMatch()
+	if match == "" && !parsed.hasFilters() {
 		return &SearchResult{
 			Messages: []SearchMessage{},
 			Query:    opts.Query,
@@ -1072,11 +1682,14 @@ func (r *Repository) Search(ctx context.Context, workspaceID, currentUserID stri
 		"m.deleted_at IS NULL",
 		"m.type != 'system'",
 		"c.workspace_id = ?",
-		"messages_fts.content MATCH ?",
 		// Access control: user must be a channel member OR channel must be public
 		"(cm.user_id IS NOT NULL OR c.type = 'public')",
+		// Private channel history visibility, relative to when the user joined
+		`(c.type != 'private' OR cm.user_id IS NULL OR c.history_visibility = 'all'
+		  OR (c.history_visibility = 'none' AND m.created_at >= cm.created_at)
+		  OR (c.history_visibility = 'last_30_days' AND m.created_at >= strftime('%Y-%m-%dT%H:%M:%SZ', cm.created_at, '-30 days')))`,
 	}
-	baseArgs := []interface{}{workspaceID, sanitized}
+	baseArgs := []interface{}{workspaceID}
 
 	// Add ban-hide and block filters
 	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
@@ -1103,29 +1716,129 @@ func (r *Repository) Search(ctx context.Context, workspaceID, currentUserID stri
 		baseArgs = append(baseArgs, opts.After.Format("2006-01-02T15:04:05Z07:00"))
 	}
 
+	// Operators parsed from the query string
+	if len(parsed.fromUsers) > 0 {
+		var conds []string
+		for _, from := range parsed.fromUsers {
+			if strings.EqualFold(from, "me") {
+				from = currentUserID
+			}
+			conds = append(conds, "m.user_id = ? OR u.display_name = ? COLLATE NOCASE")
+			baseArgs = append(baseArgs, from, from)
+		}
+		whereClauses = append(whereClauses, "("+strings.Join(conds, " OR ")+")")
+	}
+	if len(parsed.inChannels) > 0 {
+		var conds []string
+		for _, in := range parsed.inChannels {
+			conds = append(conds, "c.id = ? OR c.name = ? COLLATE NOCASE")
+			baseArgs = append(baseArgs, in, in)
+		}
+		whereClauses = append(whereClauses, "("+strings.Join(conds, " OR ")+")")
+	}
+	if parsed.hasLink {
+		whereClauses = append(whereClauses, "(m.content LIKE '%http://%' OR m.content LIKE '%https://%')")
+	}
+	if parsed.isThread {
+		whereClauses = append(whereClauses, "(m.thread_parent_id IS NOT NULL OR m.reply_count > 0)")
+	}
+	if parsed.before != nil {
+		whereClauses = append(whereClauses, "m.created_at < ?")
+		baseArgs = append(baseArgs, parsed.before.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	if parsed.after != nil {
+		whereClauses = append(whereClauses, "m.created_at >= ?")
+		baseArgs = append(baseArgs, parsed.after.Format("2006-01-02T15:04:05Z07:00"))
+	}
+
 	whereSQL := strings.Join(whereClauses, " AND ")
 
-	joinSQL := `
-		FROM messages_fts
-		JOIN messages m ON m.rowid = messages_fts.rowid
-		JOIN channels c ON c.id = m.channel_id
-		LEFT JOIN users u ON u.id = m.user_id
-		LEFT JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
-	`
+	// Archived messages are searched alongside the rest, each table through
+	// its own index. Without text terms there is nothing to rank, so skip the
+	// indexes and list newest first. Ties are broken by ID so pages never
+	// overlap.
+	branch := func(t messageTables, archived bool) string {
+		fromSQL := "FROM " + t.messages + " m"
+		keySQL := "m.created_at"
+		where := whereSQL
+		if match != "" {
+			fromSQL = "FROM " + t.search + " f JOIN " + t.messages + " m ON m.rowid = f.rowid"
+			keySQL = "f.rank"
+			where += " AND f.content MATCH ?"
+		}
+		if parsed.hasAttachment {
+			where += " AND EXISTS (SELECT 1 FROM attachments a WHERE a." + t.attachmentColumn + " = m.id)"
+		}
+		return `
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot,
+			       c.name as channel_name, c.type as channel_type,
+			       ` + keySQL + ` as sort_key, ` + strconv.FormatBool(archived) + ` as archived
+			` + fromSQL + `
+			JOIN channels c ON c.id = m.channel_id
+			LEFT JOIN users u ON u.id = m.user_id
+			LEFT JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
+			WHERE ` + where
+	}
 	// Prepend currentUserID for the channel_memberships join
-	joinArgs := append([]interface{}{currentUserID}, baseArgs...)
+	branchArgs := append([]interface{}{currentUserID}, baseArgs...)
+	if match != "" {
+		branchArgs = append(branchArgs, match)
+	}
+	unionSQL := "FROM (" + branch(hotTables, false) + " UNION ALL " + branch(archiveTables, true) + ")"
+	unionArgs := append(append([]interface{}{}, branchArgs...), branchArgs...)
+
+	orderSQL := "sort_key DESC, id DESC"
+	if match != "" {
+		orderSQL = "sort_key, id"
+	}
+	result := &SearchResult{Query: opts.Query}
+
+	// Paging by offset counts every match on every page; paging by cursor
+	// only counts, up to a cap, on the first
+	cursorSQL := ""
+	var pageSQL string
+	var pageArgs []interface{}
+	switch {
+	case opts.Cursor != "":
+		key, id, err := decodeSearchCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if match != "" {
+			rank, err := strconv.ParseFloat(key, 64)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			cursorSQL = " WHERE (sort_key > ? OR (sort_key = ? AND id > ?))"
+			unionArgs = append(unionArgs, rank, rank, id)
+		} else {
+			cursorSQL = " WHERE (sort_key < ? OR (sort_key = ? AND id < ?))"
+			unionArgs = append(unionArgs, key, key, id)
+		}
+		pageSQL = "LIMIT ?"
+		pageArgs = []interface{}{opts.Limit + 1}
+	case opts.Offset > 0:
+		pageSQL = "LIMIT ? OFFSET ?"
+		pageArgs = []interface{}{opts.Limit + 1, opts.Offset}
+	default:
+		pageSQL = "LIMIT ?"
+		pageArgs = []interface{}{opts.Limit + 1}
+	}
 
-	// Single query with COUNT(*) OVER() to avoid a separate count round-trip
+	countSQL := "NULL"
+	if opts.Cursor == "" && opts.Offset > 0 {
+		countSQL = "COUNT(*) OVER()"
+	}
 	dataQuery := `
-		SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-		       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email,
-		       c.name as channel_name, c.type as channel_type,
-		       COUNT(*) OVER() as total_count
-	` + joinSQL + " WHERE " + whereSQL + `
-		ORDER BY messages_fts.rank
-		LIMIT ? OFFSET ?
+		SELECT *, ` + countSQL + ` as total_count
+		` + unionSQL + cursorSQL + `
+		ORDER BY ` + orderSQL + `
+		` + pageSQL + `
 	`
-	dataArgs := append(joinArgs, opts.Limit, opts.Offset)
+	dataArgs := append(unionArgs, pageArgs...)
 
 	rows, err := r.db.QueryContext(ctx, dataQuery, dataArgs...)
 	if err != nil {
@@ -1134,11 +1847,14 @@ func (r *Repository) Search(ctx context.Context, workspaceID, currentUserID stri
 	defer rows.Close()
 
 	var messages []SearchMessage
-	var totalCount int
+	var keys []string
+	var totalCount sql.NullInt64
 	for rows.Next() {
 		var msg MessageWithUser
 		var cols scanMessageColumns
-		dest := append(cols.scanDest(&msg), &totalCount)
+		var key string
+		var archived bool
+		dest := append(cols.scanDest(&msg), &key, &archived, &totalCount)
 		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
@@ -1148,23 +1864,70 @@ func (r *Repository) Search(ctx context.Context, workspaceID, currentUserID stri
 			MessageWithUser: msg,
 			ChannelName:     cols.channelName,
 			ChannelType:     cols.channelType,
+			Archived:        archived,
 		})
+		keys = append(keys, key)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
+	if result.HasMore = len(messages) > opts.Limit; result.HasMore {
+		messages = messages[:opts.Limit]
+		last := len(messages) - 1
+		result.NextCursor = encodeSearchCursor(keys[last], messages[last].ID)
+	}
 	if messages == nil {
 		messages = []SearchMessage{}
 	}
+	result.Messages = messages
+
+	switch {
+	case totalCount.Valid:
+		total := int(totalCount.Int64)
+		result.TotalCount = &total
+	case opts.Offset > 0 && opts.Cursor == "":
+		// Paged past the last match, so there was no row to carry the count
+	case opts.Cursor == "":
+		total := len(messages)
+		if result.HasMore {
+			err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (SELECT 1 `+unionSQL+` LIMIT ?)`,
+				append(unionArgs, searchCountCap+1)...).Scan(&total)
+			if err != nil {
+				return nil, err
+			}
+			if total > searchCountCap {
+				total = searchCountCap
+				result.TotalCountApproximate = true
+			}
+		}
+		result.TotalCount = &total
+	}
+	return result, nil
+}
 
-	return &SearchResult{
-		Messages:   messages,
-		TotalCount: totalCount,
-		HasMore:    opts.Offset+len(messages) < totalCount,
-		Query:      opts.Query,
-	}, nil
+// searchCountCap is how many matches the first page of a cursor-paged search
+// counts before reporting the total as approximate.
+const searchCountCap = 1000
+
+// encodeSearchCursor makes the cursor for the page after a search result
+// with the given sort key (its rank, or its creation time when there is
+// nothing to rank) and ID.
+func encodeSearchCursor(key, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + "|" + id))
+}
+
+func decodeSearchCursor(cursor string) (key, id string, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	key, id, ok := strings.Cut(string(data), "|")
+	if !ok || key == "" || id == "" {
+		return "", "", ErrInvalidCursor
+	}
+	return key, id, nil
 }
 
 // ListUserThreads lists threads the user is subscribed to in a workspace, ordered by last_reply_at DESC
@@ -1181,12 +1944,16 @@ func (r *Repository) ListUserThreads(ctx context.Context, workspaceID, userID st
 	// Base query: get parent messages of threads the user is subscribed to
 	if opts.Cursor == "" {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email,
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot,
 			       c.name as channel_name, c.type as channel_type,
 			       CASE WHEN ts.last_read_reply_id IS NULL THEN 1
 			            WHEN EXISTS (SELECT 1 FROM messages r WHERE r.thread_parent_id = m.id AND r.id > ts.last_read_reply_id AND r.deleted_at IS NULL LIMIT 1) THEN 1
-			            ELSE 0 END as has_new_replies
+			            ELSE 0 END as has_new_replies,
+			       (SELECT COUNT(*) FROM messages r WHERE r.thread_parent_id = m.id AND r.deleted_at IS NULL
+			          AND (ts.last_read_reply_id IS NULL OR r.id > ts.last_read_reply_id)) as unread_reply_count
 			FROM thread_subscriptions ts
 			JOIN messages m ON m.id = ts.thread_parent_id
 			LEFT JOIN users u ON u.id = m.user_id
@@ -1203,12 +1970,16 @@ func (r *Repository) ListUserThreads(ctx context.Context, workspaceID, userID st
 		args = append(args, opts.Limit+1)
 	} else {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email,
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot,
 			       c.name as channel_name, c.type as channel_type,
 			       CASE WHEN ts.last_read_reply_id IS NULL THEN 1
 			            WHEN EXISTS (SELECT 1 FROM messages r WHERE r.thread_parent_id = m.id AND r.id > ts.last_read_reply_id AND r.deleted_at IS NULL LIMIT 1) THEN 1
-			            ELSE 0 END as has_new_replies
+			            ELSE 0 END as has_new_replies,
+			       (SELECT COUNT(*) FROM messages r WHERE r.thread_parent_id = m.id AND r.deleted_at IS NULL
+			          AND (ts.last_read_reply_id IS NULL OR r.id > ts.last_read_reply_id)) as unread_reply_count
 			FROM thread_subscriptions ts
 			JOIN messages m ON m.id = ts.thread_parent_id
 			LEFT JOIN users u ON u.id = m.user_id
@@ -1238,7 +2009,7 @@ func (r *Repository) ListUserThreads(ctx context.Context, workspaceID, userID st
 		var msg ThreadMessage
 		var cols scanMessageColumns
 		var hasNewReplies int
-		dest := append(cols.scanDest(&msg.MessageWithUser), &hasNewReplies)
+		dest := append(cols.scanDest(&msg.MessageWithUser), &hasNewReplies, &msg.UnreadReplyCount)
 		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
@@ -1271,11 +2042,11 @@ func (r *Repository) ListUserThreads(ctx context.Context, workspaceID, userID st
 			messageIDs[i] = m.ID
 		}
 
-		reactions, err := r.getReactionsForMessages(ctx, messageIDs, filter)
+		reactions, err := r.getReactionsForMessages(ctx, hotTables, messageIDs, filter)
 		if err != nil {
 			return nil, err
 		}
-		participants, err := r.getThreadParticipantsForMessages(ctx, messageIDs, filter)
+		participants, participantCounts, err := r.getThreadParticipantsForMessages(ctx, hotTables, messageIDs, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -1285,6 +2056,7 @@ func (r *Repository) ListUserThreads(ctx context.Context, workspaceID, userID st
 			}
 			if p, ok := participants[threads[i].ID]; ok {
 				threads[i].ThreadParticipants = p
+				threads[i].ThreadParticipantCount = participantCounts[threads[i].ID]
 			}
 		}
 	}
@@ -1323,8 +2095,8 @@ func (r *Repository) PinMessage(ctx context.Context, messageID, userID string) e
 	if err != nil {
 		return err
 	}
-	if count >= 50 {
-		return errors.New("maximum of 50 pinned messages per channel")
+	if count >= MaxPinnedMessages {
+		return ErrTooManyPins
 	}
 
 	// Pin the message
@@ -1349,21 +2121,115 @@ func (r *Repository) CountPinnedMessages(ctx context.Context, channelID string)
 	return count, err
 }
 
+// LastPostedAt returns when the user last posted in a channel, including
+// thread replies and messages since deleted, or nil if they never have.
+func (r *Repository) LastPostedAt(ctx context.Context, channelID, userID string) (*time.Time, error) {
+	var createdAt string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT created_at FROM messages
+		WHERE channel_id = ? AND user_id = ? AND type = 'user'
+		ORDER BY id DESC LIMIT 1
+	`, channelID, userID).Scan(&createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DailyActivity counts the messages posted in a channel on each UTC day of
+// the period starting at since's day, including thread replies but not
+// system or deleted messages. Days without messages have a zero count.
+func (r *Repository) DailyActivity(ctx context.Context, channelID string, since time.Time, days int) (_ *ChannelActivity, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.DailyActivity")
+	defer func() { endSpan(err) }()
+	start := since.UTC().Truncate(24 * time.Hour)
+	from := start.Format(time.RFC3339)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT substr(created_at, 1, 10) AS day, COUNT(*)
+		FROM messages
+		WHERE channel_id = ? AND created_at >= ? AND type != 'system' AND deleted_at IS NULL
+		GROUP BY day
+	`, channelID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var day string
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+		counts[day] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	activity := &ChannelActivity{Days: make([]DayCount, days)}
+	for i := range activity.Days {
+		date := start.AddDate(0, 0, i).Format(time.DateOnly)
+		activity.Days[i] = DayCount{Date: date, Count: counts[date]}
+		activity.Total += counts[date]
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT user_id)
+		FROM messages
+		WHERE channel_id = ? AND created_at >= ? AND type != 'system' AND deleted_at IS NULL
+	`, channelID, from).Scan(&activity.Posters)
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// CountVisibleByAuthor counts an author's messages in a workspace, including
+// thread replies, in the public channels and conversations that viewerID
+// can read.
+func (r *Repository) CountVisibleByAuthor(ctx context.Context, workspaceID, authorID, viewerID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM messages m
+		JOIN channels c ON c.id = m.channel_id
+		WHERE c.workspace_id = ? AND m.user_id = ? AND m.type = 'user' AND m.deleted_at IS NULL
+			AND (c.type = 'public' OR EXISTS (
+				SELECT 1 FROM channel_memberships cm WHERE cm.channel_id = c.id AND cm.user_id = ?
+			))
+	`, workspaceID, authorID, viewerID).Scan(&count)
+	return count, err
+}
+
 // ListPinnedMessages returns pinned messages in a channel, ordered by pinned_at DESC.
-func (r *Repository) ListPinnedMessages(ctx context.Context, channelID string, cursor string, limit int, filter *moderation.FilterOptions) ([]MessageWithUser, bool, string, error) {
+// A non-nil visibleSince leaves out messages created before it.
+func (r *Repository) ListPinnedMessages(ctx context.Context, channelID string, cursor string, limit int, visibleSince *time.Time, filter *moderation.FilterOptions) ([]MessageWithUser, bool, string, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 50
 	}
 
 	filterSQL, filterArgs := moderation.FilterSQL(filter, "m.user_id")
+	filterSQL, filterArgs = appendVisibleSince(filterSQL, filterArgs, visibleSince)
 
 	var query string
 	var args []interface{}
 
 	if cursor == "" {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
 			WHERE m.channel_id = ? AND m.pinned_at IS NOT NULL AND m.deleted_at IS NULL` + filterSQL + `
@@ -1374,8 +2240,10 @@ func (r *Repository) ListPinnedMessages(ctx context.Context, channelID string, c
 		args = append(args, limit+1)
 	} else {
 		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.created_at, m.updated_at,
-			       COALESCE(u.display_name, '') as user_display_name, u.avatar_url, COALESCE(u.email, '') as user_email
+			SELECT m.id, m.channel_id, m.user_id, m.content, m.type, m.system_event, m.thread_parent_id, m.also_send_to_channel, m.reply_count, m.last_reply_at, m.edited_at, m.deleted_at, m.pinned_at, m.pinned_by, m.webhook_id, m.announcement_id, m.created_at, m.updated_at,
+			       COALESCE(m.bot_name, u.display_name, 'Former member') as user_display_name, COALESCE(m.bot_avatar_url, u.avatar_url), COALESCE(u.email, '') as user_email,
+			       (m.bot_name IS NULL AND (u.id IS NULL OR u.status = 'deactivated')) as user_is_deactivated,
+			       (m.bot_name IS NOT NULL OR COALESCE(u.is_bot, 0) = 1) as is_bot
 			FROM messages m
 			LEFT JOIN users u ON u.id = m.user_id
 			WHERE m.channel_id = ? AND m.pinned_at IS NOT NULL AND m.deleted_at IS NULL AND m.id < ?` + filterSQL + `
@@ -1409,7 +2277,103 @@ func (r *Repository) ListPinnedMessages(ctx context.Context, channelID string, c
 	}
 
 	// Load reactions and thread participants
-	r.loadReactionsAndParticipants(ctx, messages, filter)
+	r.loadReactionsAndParticipants(ctx, hotTables, messages, filter)
 
 	return messages, hasMore, nextCursor, nil
 }
+
+// AddReceipt records that a user has read a message. Returns the receipt and
+// whether it was newly created; re-acknowledging keeps the original read time.
+func (r *Repository) AddReceipt(ctx context.Context, messageID, userID string) (*Receipt, bool, error) {
+	now := time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO message_receipts (message_id, user_id, read_at) VALUES (?, ?, ?)
+	`, messageID, userID, now.Format(time.RFC3339))
+	if err != nil {
+		return nil, false, err
+	}
+	rows, _ := result.RowsAffected()
+	if rows > 0 {
+		return &Receipt{UserID: userID, ReadAt: now.Truncate(time.Second)}, true, nil
+	}
+
+	var readAt string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT read_at FROM message_receipts WHERE message_id = ? AND user_id = ?
+	`, messageID, userID).Scan(&readAt)
+	if err != nil {
+		return nil, false, err
+	}
+	t, _ := time.Parse(time.RFC3339, readAt)
+	return &Receipt{UserID: userID, ReadAt: t}, false, nil
+}
+
+// GetReceiptsForMessages returns the read receipts of the given messages, keyed by message ID.
+func (r *Repository) GetReceiptsForMessages(ctx context.Context, messageIDs []string) (map[string][]Receipt, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(messageIDs))
+	args := make([]interface{}, len(messageIDs))
+	for i, id := range messageIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT message_id, user_id, read_at FROM message_receipts
+		WHERE message_id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY read_at
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make(map[string][]Receipt)
+	for rows.Next() {
+		var messageID, readAt string
+		var rec Receipt
+		if err := rows.Scan(&messageID, &rec.UserID, &readAt); err != nil {
+			return nil, err
+		}
+		rec.ReadAt, _ = time.Parse(time.RFC3339, readAt)
+		receipts[messageID] = append(receipts[messageID], rec)
+	}
+	return receipts, rows.Err()
+}
+
+// DeleteOrphanedReactions removes reactions on messages that have been
+// deleted, along with any left behind by a hard-deleted message, and returns
+// how many were removed.
+func (r *Repository) DeleteOrphanedReactions(ctx context.Context) (n int64, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.DeleteOrphanedReactions")
+	defer func() { endSpan(err) }()
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM reactions
+		WHERE message_id NOT IN (SELECT id FROM messages WHERE deleted_at IS NULL)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RepairSearchIndex checks the full-text index against the messages table and
+// rebuilds it when they disagree, which drops entries for rows that no longer
+// exist. It reports whether a rebuild was needed.
+func (r *Repository) RepairSearchIndex(ctx context.Context) (rebuilt bool, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "message.RepairSearchIndex")
+	defer func() { endSpan(err) }()
+
+	if _, err := r.db.ExecContext(ctx, `INSERT INTO messages_fts(messages_fts, rank) VALUES ('integrity-check', 1)`); err == nil {
+		return false, nil
+	}
+
+	if _, err := r.db.ExecContext(ctx, `INSERT INTO messages_fts(messages_fts) VALUES ('rebuild')`); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -3,7 +3,11 @@ package message
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/enzyme/server/internal/channel"
 	"github.com/enzyme/server/internal/testutil"
@@ -72,6 +76,59 @@ func TestRepository_Create_ThreadReply(t *testing.T) {
 	}
 }
 
+func TestRepository_Create_DuplicateClientMsgID(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@example.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	key := "retry-key"
+	first := &Message{ChannelID: ch.ID, UserID: &owner.ID, Content: "first", ClientMsgID: &key}
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	dup := &Message{ChannelID: ch.ID, UserID: &owner.ID, Content: "again", ClientMsgID: &key}
+	if err := repo.Create(ctx, dup); !errors.Is(err, ErrDuplicateClientMsgID) {
+		t.Fatalf("Create() error = %v, want ErrDuplicateClientMsgID", err)
+	}
+
+	// Keys are per user
+	if err := repo.Create(ctx, &Message{ChannelID: ch.ID, UserID: &other.ID, Content: "mine", ClientMsgID: &key}); err != nil {
+		t.Fatalf("Create() for another user error = %v", err)
+	}
+
+	got, err := repo.GetByClientMsgID(ctx, owner.ID, key)
+	if err != nil {
+		t.Fatalf("GetByClientMsgID() error = %v", err)
+	}
+	if got.ID != first.ID {
+		t.Errorf("GetByClientMsgID() = %s, want %s", got.ID, first.ID)
+	}
+}
+
+func TestIsClientMsgIDConflict(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("UNIQUE constraint failed: messages.user_id, messages.client_msg_id"), true},
+		{errors.New("UNIQUE constraint failed: messages.id"), false},
+		{errors.New("UNIQUE constraint failed: messages.announcement_id"), false},
+		{errors.New("FOREIGN KEY constraint failed"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isClientMsgIDConflict(tt.err); got != tt.want {
+			t.Errorf("isClientMsgIDConflict(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
 func TestRepository_GetByID(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -126,6 +183,68 @@ func TestRepository_GetByIDWithUser(t *testing.T) {
 	}
 }
 
+func TestRepository_GetByIDWithUser_DeactivatedAuthor(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	created := testutil.CreateTestMessage(t, db, ch.ID, member.ID, "Hello")
+
+	if _, err := db.Exec(`UPDATE users SET status = 'deactivated' WHERE id = ?`, member.ID); err != nil {
+		t.Fatalf("deactivate user: %v", err)
+	}
+
+	msg, err := repo.GetByIDWithUser(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByIDWithUser() error = %v", err)
+	}
+	if msg.UserDisplayName != "Member" {
+		t.Errorf("UserDisplayName = %q, want %q", msg.UserDisplayName, "Member")
+	}
+	if !msg.UserIsDeactivated {
+		t.Error("expected UserIsDeactivated for deactivated author")
+	}
+}
+
+func TestRepository_GetByIDWithUser_RemovedAuthor(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	created := testutil.CreateTestMessage(t, db, ch.ID, member.ID, "Hello")
+
+	if _, err := db.Exec(`DELETE FROM users WHERE id = ?`, member.ID); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+
+	msg, err := repo.GetByIDWithUser(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByIDWithUser() error = %v", err)
+	}
+	if msg.UserDisplayName != "Former member" {
+		t.Errorf("UserDisplayName = %q, want %q", msg.UserDisplayName, "Former member")
+	}
+	if !msg.UserIsDeactivated {
+		t.Error("expected UserIsDeactivated for removed author")
+	}
+
+	owned, err := repo.GetByIDWithUser(ctx, testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Hi").ID)
+	if err != nil {
+		t.Fatalf("GetByIDWithUser() error = %v", err)
+	}
+	if owned.UserIsDeactivated {
+		t.Error("expected active author not to be flagged")
+	}
+}
+
 func TestRepository_Update(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -160,7 +279,7 @@ func TestRepository_Delete(t *testing.T) {
 	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
 	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "To be deleted")
 
-	err := repo.Delete(ctx, msg.ID)
+	err := repo.Delete(ctx, msg.ID, owner.ID)
 	if err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
@@ -184,10 +303,10 @@ func TestRepository_Delete_AlreadyDeleted(t *testing.T) {
 	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
 	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "To be deleted")
 
-	repo.Delete(ctx, msg.ID)
+	repo.Delete(ctx, msg.ID, owner.ID)
 
 	// Second delete should fail
-	err := repo.Delete(ctx, msg.ID)
+	err := repo.Delete(ctx, msg.ID, owner.ID)
 	if !errors.Is(err, ErrMessageNotFound) {
 		t.Errorf("Delete() error = %v, want %v", err, ErrMessageNotFound)
 	}
@@ -332,6 +451,266 @@ func TestRepository_ListThread(t *testing.T) {
 	}
 }
 
+func TestRepository_ListThread_Directions(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Parent")
+
+	var ids []string
+	for i := 0; i < 7; i++ {
+		reply := &Message{
+			ChannelID:      ch.ID,
+			UserID:         &owner.ID,
+			Content:        fmt.Sprintf("Reply %d", i),
+			ThreadParentID: &parent.ID,
+		}
+		if err := repo.Create(ctx, reply); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, reply.ID)
+	}
+
+	tests := []struct {
+		name       string
+		opts       ListOptions
+		want       []int
+		older      bool
+		newer      bool
+		nextCursor string
+	}{
+		{"newest page", ListOptions{Limit: 3}, []int{4, 5, 6}, true, false, ids[4]},
+		{"before", ListOptions{Limit: 3, Cursor: ids[4], Direction: "before"}, []int{1, 2, 3}, true, true, ids[1]},
+		{"before the start", ListOptions{Limit: 3, Cursor: ids[1], Direction: "before"}, []int{0}, false, true, ""},
+		{"from the first reply", ListOptions{Limit: 3, Direction: "after"}, []int{0, 1, 2}, false, true, ids[2]},
+		{"cursor without direction", ListOptions{Limit: 3, Cursor: ids[2]}, []int{3, 4, 5}, true, true, ids[5]},
+		{"after to the end", ListOptions{Limit: 3, Cursor: ids[4], Direction: "after"}, []int{5, 6}, true, false, ""},
+		{"around", ListOptions{Limit: 4, Cursor: ids[3], Direction: "around"}, []int{2, 3, 4, 5}, true, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.ListThread(ctx, parent.ID, tt.opts, nil)
+			if err != nil {
+				t.Fatalf("ListThread() error = %v", err)
+			}
+			var got []int
+			for _, m := range result.Messages {
+				got = append(got, slices.Index(ids, m.ID))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("replies = %v, want %v", got, tt.want)
+			}
+			if result.HasOlder != tt.older || result.HasNewer != tt.newer {
+				t.Errorf("HasOlder, HasNewer = %v, %v, want %v, %v", result.HasOlder, result.HasNewer, tt.older, tt.newer)
+			}
+			if result.NextCursor != tt.nextCursor {
+				t.Errorf("NextCursor = %q, want %q", result.NextCursor, tt.nextCursor)
+			}
+		})
+	}
+}
+
+func TestRepository_ListUserThreads_UnreadReplyCount(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Parent")
+
+	var replies []*Message
+	for i := 0; i < 3; i++ {
+		reply := &Message{
+			ChannelID:      ch.ID,
+			UserID:         &owner.ID,
+			Content:        fmt.Sprintf("Reply %d", i),
+			ThreadParentID: &parent.ID,
+		}
+		if err := repo.Create(ctx, reply); err != nil {
+			t.Fatalf("Create() reply error = %v", err)
+		}
+		replies = append(replies, reply)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`
+		INSERT INTO thread_subscriptions (id, thread_parent_id, user_id, status, created_at, updated_at)
+		VALUES ('sub1', ?, ?, 'subscribed', ?, ?)
+	`, parent.ID, owner.ID, now, now); err != nil {
+		t.Fatalf("subscribing: %v", err)
+	}
+
+	unread := func() (int, bool) {
+		t.Helper()
+		result, err := repo.ListUserThreads(ctx, ws.ID, owner.ID, ListOptions{Limit: 10}, nil)
+		if err != nil {
+			t.Fatalf("ListUserThreads() error = %v", err)
+		}
+		if len(result.Threads) != 1 {
+			t.Fatalf("len(Threads) = %d, want 1", len(result.Threads))
+		}
+		return result.Threads[0].UnreadReplyCount, result.Threads[0].HasNewReplies
+	}
+
+	if n, hasNew := unread(); n != 3 || !hasNew {
+		t.Errorf("never read: unread = %d, has_new = %v, want 3, true", n, hasNew)
+	}
+
+	if _, err := db.Exec(`UPDATE thread_subscriptions SET last_read_reply_id = ? WHERE id = 'sub1'`, replies[0].ID); err != nil {
+		t.Fatalf("marking read: %v", err)
+	}
+	if n, hasNew := unread(); n != 2 || !hasNew {
+		t.Errorf("read first reply: unread = %d, has_new = %v, want 2, true", n, hasNew)
+	}
+
+	if _, err := db.Exec(`UPDATE thread_subscriptions SET last_read_reply_id = ? WHERE id = 'sub1'`, replies[2].ID); err != nil {
+		t.Fatalf("marking read: %v", err)
+	}
+	if n, hasNew := unread(); n != 0 || hasNew {
+		t.Errorf("read all: unread = %d, has_new = %v, want 0, false", n, hasNew)
+	}
+}
+
+func TestRepository_ListByAuthor(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	other := testutil.CreateTestUser(t, db, "other@example.com", "Other")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	old := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Old")
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Parent")
+	testutil.CreateTestMessage(t, db, ch.ID, other.ID, "Someone else")
+	reply := &Message{ChannelID: ch.ID, UserID: &owner.ID, Content: "Reply", ThreadParentID: &parent.ID}
+	if err := repo.Create(ctx, reply); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	deleted := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Deleted")
+	if err := repo.Delete(ctx, deleted.ID, owner.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := db.Exec(`UPDATE messages SET created_at = '2020-01-01T00:00:00Z' WHERE id = ?`, old.ID); err != nil {
+		t.Fatalf("backdating message: %v", err)
+	}
+
+	result, err := repo.ListByAuthor(ctx, ch.ID, AuthorListOptions{UserID: owner.ID}, nil)
+	if err != nil {
+		t.Fatalf("ListByAuthor() error = %v", err)
+	}
+	var ids []string
+	for _, m := range result.Messages {
+		ids = append(ids, m.ID)
+	}
+	want := []string{reply.ID, parent.ID, old.ID}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("messages = %v, want %v (newest first, replies included, deleted skipped)", ids, want)
+	}
+
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err = repo.ListByAuthor(ctx, ch.ID, AuthorListOptions{UserID: owner.ID, Since: &since}, nil)
+	if err != nil {
+		t.Fatalf("ListByAuthor() error = %v", err)
+	}
+	if len(result.Messages) != 2 {
+		t.Errorf("len(Messages) with since = %d, want 2", len(result.Messages))
+	}
+
+	result, err = repo.ListByAuthor(ctx, ch.ID, AuthorListOptions{UserID: owner.ID, Until: &since}, nil)
+	if err != nil {
+		t.Fatalf("ListByAuthor() error = %v", err)
+	}
+	if len(result.Messages) != 1 || result.Messages[0].ID != old.ID {
+		t.Errorf("messages with until = %v, want only the backdated message", result.Messages)
+	}
+
+	result, err = repo.ListByAuthor(ctx, ch.ID, AuthorListOptions{UserID: owner.ID, Limit: 2}, nil)
+	if err != nil {
+		t.Fatalf("ListByAuthor() error = %v", err)
+	}
+	if !result.HasMore || result.NextCursor != parent.ID {
+		t.Fatalf("HasMore = %v, NextCursor = %q, want true and %q", result.HasMore, result.NextCursor, parent.ID)
+	}
+	result, err = repo.ListByAuthor(ctx, ch.ID, AuthorListOptions{UserID: owner.ID, Limit: 2, Cursor: result.NextCursor}, nil)
+	if err != nil {
+		t.Fatalf("ListByAuthor() error = %v", err)
+	}
+	if len(result.Messages) != 1 || result.HasMore {
+		t.Errorf("second page = %d messages (HasMore %v), want 1 and false", len(result.Messages), result.HasMore)
+	}
+}
+
+func TestRepository_ThreadParticipants(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Parent")
+
+	var repliers []string
+	for i := 0; i < 5; i++ {
+		u := testutil.CreateTestUser(t, db, fmt.Sprintf("user%d@example.com", i), fmt.Sprintf("User %d", i))
+		repliers = append(repliers, u.ID)
+		for j := 0; j < 2; j++ {
+			reply := &Message{ChannelID: ch.ID, UserID: &u.ID, Content: "Reply", ThreadParentID: &parent.ID}
+			if err := repo.Create(ctx, reply); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+	}
+
+	preview, count, err := repo.GetThreadParticipants(ctx, parent.ID, nil)
+	if err != nil {
+		t.Fatalf("GetThreadParticipants() error = %v", err)
+	}
+	if len(preview) != DefaultThreadParticipantPreview || count != 5 {
+		t.Errorf("preview = %d, count = %d, want %d and 5", len(preview), count, DefaultThreadParticipantPreview)
+	}
+
+	repo.SetThreadParticipantPreview(2)
+	preview, _, _ = repo.GetThreadParticipants(ctx, parent.ID, nil)
+	if len(preview) != 2 {
+		t.Errorf("preview = %d after SetThreadParticipantPreview(2), want 2", len(preview))
+	}
+
+	// Page through the full list two at a time
+	var got []string
+	opts := ListOptions{Limit: 2}
+	for page := 0; ; page++ {
+		result, err := repo.ListThreadParticipants(ctx, parent.ID, opts, nil)
+		if err != nil {
+			t.Fatalf("ListThreadParticipants() error = %v", err)
+		}
+		if result.TotalCount != 5 {
+			t.Errorf("TotalCount = %d, want 5", result.TotalCount)
+		}
+		for _, p := range result.Participants {
+			got = append(got, p.UserID)
+		}
+		if !result.HasMore {
+			break
+		}
+		if page > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		opts.Cursor = result.NextCursor
+	}
+	if strings.Join(got, ",") != strings.Join(repliers, ",") {
+		t.Errorf("participants = %v, want %v in order of first reply", got, repliers)
+	}
+}
+
 func TestRepository_AddReaction(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -407,6 +786,51 @@ func TestRepository_RemoveReaction_NotFound(t *testing.T) {
 	}
 }
 
+func TestRepository_ApplyReactionBatch(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Hello")
+
+	repo.AddReaction(ctx, msg.ID, owner.ID, "👍")
+	repo.AddReaction(ctx, msg.ID, owner.ID, "🎉")
+
+	result, err := repo.ApplyReactionBatch(ctx, msg.ID, owner.ID, []ReactionOp{
+		{Op: ReactionOpRemove, Emoji: "👍"},
+		{Op: ReactionOpAdd, Emoji: "🎉"}, // already present
+		{Op: ReactionOpAdd, Emoji: "🚀"},
+		{Op: ReactionOpAdd, Emoji: "👀"},
+		{Op: ReactionOpRemove, Emoji: "👀"},  // added and removed in the same batch
+		{Op: ReactionOpRemove, Emoji: "❤️"}, // never present
+	})
+	if err != nil {
+		t.Fatalf("ApplyReactionBatch() error = %v", err)
+	}
+
+	if len(result.Added) != 1 || result.Added[0].Emoji != "🚀" || result.Added[0].ID == "" {
+		t.Errorf("Added = %+v, want a single 🚀 reaction", result.Added)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != "👍" {
+		t.Errorf("Removed = %v, want [👍]", result.Removed)
+	}
+
+	reactions, err := repo.GetReactionsForMessage(ctx, msg.ID, nil)
+	if err != nil {
+		t.Fatalf("GetReactionsForMessage() error = %v", err)
+	}
+	emojis := make(map[string]bool)
+	for _, r := range reactions {
+		emojis[r.Emoji] = true
+	}
+	if len(emojis) != 2 || !emojis["🎉"] || !emojis["🚀"] {
+		t.Errorf("reactions after batch = %v, want 🎉 and 🚀", emojis)
+	}
+}
+
 func TestRepository_List_IncludesReactions(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -558,7 +982,7 @@ func TestRepository_Delete_ThreadReply_DecrementsReplyCount(t *testing.T) {
 	}
 
 	// Delete one reply
-	err := repo.Delete(ctx, reply1.ID)
+	err := repo.Delete(ctx, reply1.ID, owner.ID)
 	if err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
@@ -570,7 +994,7 @@ func TestRepository_Delete_ThreadReply_DecrementsReplyCount(t *testing.T) {
 	}
 
 	// Delete second reply
-	err = repo.Delete(ctx, reply2.ID)
+	err = repo.Delete(ctx, reply2.ID, owner.ID)
 	if err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
@@ -596,7 +1020,7 @@ func TestRepository_Delete_NonThreadMessage_NoReplyCountChange(t *testing.T) {
 	msg2 := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Message 2")
 
 	// Delete msg1 should not affect msg2
-	err := repo.Delete(ctx, msg1.ID)
+	err := repo.Delete(ctx, msg1.ID, owner.ID)
 	if err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
@@ -606,3 +1030,208 @@ func TestRepository_Delete_NonThreadMessage_NoReplyCountChange(t *testing.T) {
 		t.Errorf("unrelated message ReplyCount = %d, want 0", msg2Fetched.ReplyCount)
 	}
 }
+
+func TestRepository_Restore(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	admin := testutil.CreateTestUser(t, db, "admin@example.com", "Admin")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	parent := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Parent")
+	reply := &Message{ChannelID: ch.ID, UserID: &owner.ID, Content: "Searchable reply", ThreadParentID: &parent.ID}
+	if err := repo.Create(ctx, reply); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Delete(ctx, reply.ID, owner.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// Outside the window
+	if err := repo.Restore(ctx, reply.ID, time.Now().Add(time.Hour)); !errors.Is(err, ErrCannotRestoreMessage) {
+		t.Fatalf("Restore() outside window error = %v, want %v", err, ErrCannotRestoreMessage)
+	}
+
+	if err := repo.Restore(ctx, reply.ID, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	restored, _ := repo.GetByID(ctx, reply.ID)
+	if restored.Content != "Searchable reply" || restored.DeletedAt != nil {
+		t.Errorf("restored message = %+v, want original content and no deleted_at", restored)
+	}
+	parentMsg, _ := repo.GetByID(ctx, parent.ID)
+	if parentMsg.ReplyCount != 1 {
+		t.Errorf("ReplyCount after restore = %d, want 1", parentMsg.ReplyCount)
+	}
+	var indexed int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages_fts WHERE messages_fts MATCH 'searchable'`).Scan(&indexed); err != nil || indexed != 1 {
+		t.Errorf("search index entries after restore = %d (err %v), want 1", indexed, err)
+	}
+
+	// Messages removed by someone else cannot be restored by the author
+	if err := repo.Delete(ctx, parent.ID, admin.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Restore(ctx, parent.ID, time.Now().Add(-time.Hour)); !errors.Is(err, ErrCannotRestoreMessage) {
+		t.Errorf("Restore() of moderated message error = %v, want %v", err, ErrCannotRestoreMessage)
+	}
+}
+
+func TestRepository_PurgeDeletedContent(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "Secret")
+	if err := repo.Delete(ctx, msg.ID, owner.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	n, err := repo.PurgeDeletedContent(ctx, time.Now().Add(-time.Hour))
+	if err != nil || n != 0 {
+		t.Fatalf("PurgeDeletedContent() before window = %d, %v; want 0", n, err)
+	}
+
+	n, err = repo.PurgeDeletedContent(ctx, time.Now().Add(time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PurgeDeletedContent() = %d, %v; want 1", n, err)
+	}
+	if err := repo.Restore(ctx, msg.ID, time.Now().Add(-24*time.Hour)); !errors.Is(err, ErrCannotRestoreMessage) {
+		t.Errorf("Restore() after purge error = %v, want %v", err, ErrCannotRestoreMessage)
+	}
+}
+
+func TestRepository_AddReceipt_KeepsFirstReadTime(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "dm", channel.TypeDM)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, alice.ID, "Hi Bob")
+
+	first, created, err := repo.AddReceipt(ctx, msg.ID, bob.ID)
+	if err != nil {
+		t.Fatalf("AddReceipt() error = %v", err)
+	}
+	if !created {
+		t.Error("expected first receipt to be created")
+	}
+
+	second, created, err := repo.AddReceipt(ctx, msg.ID, bob.ID)
+	if err != nil {
+		t.Fatalf("AddReceipt() error = %v", err)
+	}
+	if created {
+		t.Error("expected second receipt to be a no-op")
+	}
+	if !second.ReadAt.Equal(first.ReadAt) {
+		t.Errorf("ReadAt = %v, want %v", second.ReadAt, first.ReadAt)
+	}
+
+	receipts, err := repo.GetReceiptsForMessages(ctx, []string{msg.ID})
+	if err != nil {
+		t.Fatalf("GetReceiptsForMessages() error = %v", err)
+	}
+	if len(receipts[msg.ID]) != 1 || receipts[msg.ID][0].UserID != bob.ID {
+		t.Errorf("receipts = %+v, want one receipt from Bob", receipts[msg.ID])
+	}
+}
+
+func TestRepository_SearchIndex_FollowsEdits(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	msg := testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "the alpha plan")
+
+	expect := func(step string, want map[string]int) {
+		t.Helper()
+		if _, err := db.Exec(`INSERT INTO messages_fts(messages_fts, rank) VALUES ('integrity-check', 1)`); err != nil {
+			t.Errorf("%s: index does not match messages: %v", step, err)
+		}
+		for word, n := range want {
+			result, err := repo.Search(ctx, ws.ID, owner.ID, SearchOptions{Query: word}, nil)
+			if err != nil {
+				t.Fatalf("%s: Search(%q) error = %v", step, word, err)
+			}
+			if len(result.Messages) != n {
+				t.Errorf("%s: Search(%q) found %d, want %d", step, word, len(result.Messages), n)
+			}
+		}
+	}
+
+	expect("created", map[string]int{"alpha": 1})
+
+	if err := repo.Update(ctx, msg.ID, "the bravo plan"); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	expect("edited", map[string]int{"alpha": 0, "bravo": 1})
+
+	if err := repo.Delete(ctx, msg.ID, owner.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	expect("deleted", map[string]int{"bravo": 0, "deleted": 0})
+
+	if err := repo.Restore(ctx, msg.ID, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	expect("restored", map[string]int{"bravo": 1})
+
+	if _, err := db.Exec(`DELETE FROM messages WHERE id = ?`, msg.ID); err != nil {
+		t.Fatalf("purging message: %v", err)
+	}
+	expect("purged", map[string]int{"bravo": 0})
+}
+
+func TestRepository_RepairSearchIndex(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", channel.TypePublic)
+	testutil.CreateTestMessage(t, db, ch.ID, owner.ID, "searchable words")
+
+	rebuilt, err := repo.RepairSearchIndex(ctx)
+	if err != nil {
+		t.Fatalf("RepairSearchIndex: %v", err)
+	}
+	if rebuilt {
+		t.Fatal("expected a consistent index not to be rebuilt")
+	}
+
+	// Index an entry for a row that does not exist in messages
+	if _, err := db.Exec(`INSERT INTO messages_fts(rowid, content) VALUES (999999, 'ghost')`); err != nil {
+		t.Fatalf("inserting orphan index entry: %v", err)
+	}
+
+	rebuilt, err = repo.RepairSearchIndex(ctx)
+	if err != nil {
+		t.Fatalf("RepairSearchIndex: %v", err)
+	}
+	if !rebuilt {
+		t.Fatal("expected an inconsistent index to be rebuilt")
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages_fts WHERE messages_fts MATCH 'ghost'`).Scan(&n); err != nil {
+		t.Fatalf("querying index: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("expected orphan entry to be gone, got %d matches", n)
+	}
+}
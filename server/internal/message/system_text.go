@@ -0,0 +1,79 @@
+package message
+
+import (
+	"encoding/json"
+
+	"github.com/enzyme/server/internal/i18n"
+)
+
+// Text renders the system event as message text in locale, e.g. "joined
+// #general". The acting user's name is shown alongside by clients, so it is
+// left out. System messages store only the event, so the text follows the
+// reader's language rather than the one the event happened in.
+func (e *SystemEventData) Text(locale string) string {
+	switch e.EventType {
+	case SystemEventUserJoined:
+		return i18n.T(locale, "system.user_joined", e.ChannelName)
+	case SystemEventUserLeft:
+		return i18n.T(locale, "system.user_left", e.ChannelName)
+	case SystemEventUserAdded:
+		if e.ActorDisplayName != nil {
+			return i18n.T(locale, "system.user_added_by", *e.ActorDisplayName)
+		}
+		return i18n.T(locale, "system.user_added", e.ChannelName)
+	case SystemEventChannelRenamed:
+		if e.OldChannelName != nil {
+			return i18n.T(locale, "system.channel_renamed_from", *e.OldChannelName, e.ChannelName)
+		}
+		return i18n.T(locale, "system.channel_renamed", e.ChannelName)
+	case SystemEventChannelVisibilityChanged:
+		if e.ChannelType != nil && (*e.ChannelType == "public" || *e.ChannelType == "private") {
+			return i18n.T(locale, "system.channel_made_"+*e.ChannelType)
+		}
+		return i18n.T(locale, "system.channel_visibility_changed")
+	case SystemEventChannelDescriptionUpdated:
+		return i18n.T(locale, "system.channel_description_updated")
+	case SystemEventChannelTopicChanged:
+		if e.Topic != nil && *e.Topic != "" {
+			return i18n.T(locale, "system.channel_topic_set", *e.Topic)
+		}
+		return i18n.T(locale, "system.channel_topic_cleared")
+	case SystemEventChannelArchived:
+		return i18n.T(locale, "system.channel_archived")
+	case SystemEventChannelUnarchived:
+		return i18n.T(locale, "system.channel_unarchived")
+	case SystemEventMessagePinned:
+		return i18n.T(locale, "system.message_pinned")
+	case SystemEventMessageUnpinned:
+		return i18n.T(locale, "system.message_unpinned")
+	case SystemEventCall:
+		return i18n.T(locale, "system.call_started")
+	case SystemEventUsersAdded:
+		if e.UserCount != nil {
+			return i18n.T(locale, "system.users_added_count", *e.UserCount, e.ChannelName)
+		}
+		return i18n.T(locale, "system.users_added", e.ChannelName)
+	case SystemEventDMWelcome:
+		if e.WelcomeReason != nil && e.ActorDisplayName != nil {
+			switch *e.WelcomeReason {
+			case WelcomeReasonInviter:
+				return i18n.T(locale, "system.dm_welcome_inviter", e.ChannelName, *e.ActorDisplayName)
+			case WelcomeReasonAdmin:
+				return i18n.T(locale, "system.dm_welcome_admin", e.ChannelName, *e.ActorDisplayName)
+			}
+		}
+		return i18n.T(locale, "system.dm_welcome", e.ChannelName)
+	}
+	return ""
+}
+
+// SystemText returns the text of a system message in locale, given its stored
+// content and system_event JSON. Messages stored before text moved out of the
+// database keep their content if the event cannot be read.
+func SystemText(locale, content, systemEventJSON string) string {
+	var event SystemEventData
+	if systemEventJSON == "" || json.Unmarshal([]byte(systemEventJSON), &event) != nil {
+		return content
+	}
+	return event.Text(locale)
+}
@@ -61,21 +61,44 @@ type AuditLogEntryWithActor struct {
 	TargetDisplayName *string `json:"target_display_name,omitempty"`
 }
 
-// Moderation action constants
+// AuditLogFilter narrows an audit log listing. Zero values match everything.
+type AuditLogFilter struct {
+	ActorID string
+	// Action matches exactly, or as a prefix when it ends in "." (e.g. "member.")
+	Action string
+	Since  *time.Time
+	Until  *time.Time
+}
+
+// Audit action constants
 const (
 	ActionUserBanned        = "user.banned"
 	ActionUserUnbanned      = "user.unbanned"
 	ActionUserBlocked       = "user.blocked"
 	ActionUserUnblocked     = "user.unblocked"
+	ActionUserDeactivated   = "user.deactivated"
+	ActionUserReactivated   = "user.reactivated"
+	ActionUserDeleted       = "user.deleted"
 	ActionMessageDeleted    = "message.deleted"
+	ActionMessageBlocked    = "message.blocked"
 	ActionMemberRemoved     = "member.removed"
 	ActionMemberRoleChanged = "member.role_changed"
 	ActionChannelArchived   = "channel.archived"
+	ActionInviteCreated     = "invite.created"
+	ActionInviteRevoked     = "invite.revoked"
+	ActionWorkspaceUpdated  = "workspace.updated"
+)
+
+// Server audit action constants
+const (
+	ActionConfigReloaded = "config.reloaded"
 )
 
 // Target type constants
 const (
-	TargetTypeUser    = "user"
-	TargetTypeMessage = "message"
-	TargetTypeChannel = "channel"
+	TargetTypeUser      = "user"
+	TargetTypeMessage   = "message"
+	TargetTypeChannel   = "channel"
+	TargetTypeInvite    = "invite"
+	TargetTypeWorkspace = "workspace"
 )
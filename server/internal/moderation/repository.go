@@ -334,7 +334,7 @@ func (r *Repository) CreateAuditLogEntry(ctx context.Context, entry *AuditLogEnt
 	entry.CreatedAt = now
 
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO moderation_log (id, workspace_id, actor_id, action, target_type, target_id, metadata, created_at)
+		INSERT INTO audit_log (id, workspace_id, actor_id, action, target_type, target_id, metadata, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`, entry.ID, entry.WorkspaceID, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, entry.Metadata, now.Format(time.RFC3339))
 	return err
@@ -362,31 +362,72 @@ func (r *Repository) CreateAuditLogEntryWithMetadata(ctx context.Context, worksp
 	return r.CreateAuditLogEntry(ctx, entry)
 }
 
-// ListAuditLog returns audit log entries for a workspace with cursor-based pagination
-func (r *Repository) ListAuditLog(ctx context.Context, workspaceID string, cursor string, limit int) ([]AuditLogEntryWithActor, bool, string, error) {
+// CreateServerAuditLogEntry records a server-wide admin action with a
+// metadata map
+func (r *Repository) CreateServerAuditLogEntry(ctx context.Context, action, source string, metadata map[string]interface{}) error {
+	var metadataJSON *string
+	if metadata != nil {
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		s := string(data)
+		metadataJSON = &s
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO server_audit_log (id, action, source, metadata, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, ulid.Make().String(), action, source, metadataJSON, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// ListAuditLog returns audit log entries for a workspace matching filter, with cursor-based pagination
+func (r *Repository) ListAuditLog(ctx context.Context, workspaceID string, filter AuditLogFilter, cursor string, limit int) ([]AuditLogEntryWithActor, bool, string, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 50
 	}
 
 	args := []interface{}{workspaceID}
-	cursorClause := ""
+	var clauses []string
+	if filter.ActorID != "" {
+		clauses = append(clauses, "AND al.actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.Action != "" {
+		if strings.HasSuffix(filter.Action, ".") {
+			clauses = append(clauses, "AND substr(al.action, 1, ?) = ?")
+			args = append(args, len(filter.Action), filter.Action)
+		} else {
+			clauses = append(clauses, "AND al.action = ?")
+			args = append(args, filter.Action)
+		}
+	}
+	if filter.Since != nil {
+		clauses = append(clauses, "AND al.created_at >= ?")
+		args = append(args, filter.Since.UTC().Format(time.RFC3339))
+	}
+	if filter.Until != nil {
+		clauses = append(clauses, "AND al.created_at < ?")
+		args = append(args, filter.Until.UTC().Format(time.RFC3339))
+	}
 	if cursor != "" {
-		cursorClause = "AND ml.id < ?"
+		clauses = append(clauses, "AND al.id < ?")
 		args = append(args, cursor)
 	}
 	args = append(args, limit+1)
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT ml.id, ml.workspace_id, ml.actor_id, ml.action,
-			   ml.target_type, ml.target_id, ml.metadata, ml.created_at,
+		SELECT al.id, al.workspace_id, al.actor_id, al.action,
+			   al.target_type, al.target_id, al.metadata, al.created_at,
 			   u.display_name, u.avatar_url,
 			   tu.display_name
-		FROM moderation_log ml
-		JOIN users u ON u.id = ml.actor_id
-		LEFT JOIN users tu ON tu.id = ml.target_id AND ml.target_type = 'user'
-		WHERE ml.workspace_id = ?
-		`+cursorClause+`
-		ORDER BY ml.id DESC
+		FROM audit_log al
+		JOIN users u ON u.id = al.actor_id
+		LEFT JOIN users tu ON tu.id = al.target_id AND al.target_type = 'user'
+		WHERE al.workspace_id = ?
+		`+strings.Join(clauses, " ")+`
+		ORDER BY al.id DESC
 		LIMIT ?
 	`, args...)
 	if err != nil {
@@ -680,7 +680,7 @@ func TestCreateAuditLogEntryWithMetadata(t *testing.T) {
 	}
 
 	// Verify via ListAuditLog
-	entries, _, _, err := repo.ListAuditLog(ctx, ws.ID, "", 50)
+	entries, _, _, err := repo.ListAuditLog(ctx, ws.ID, AuditLogFilter{}, "", 50)
 	if err != nil {
 		t.Fatalf("ListAuditLog() error = %v", err)
 	}
@@ -714,7 +714,7 @@ func TestListAuditLog(t *testing.T) {
 		})
 	}
 
-	entries, hasMore, _, err := repo.ListAuditLog(ctx, ws.ID, "", 50)
+	entries, hasMore, _, err := repo.ListAuditLog(ctx, ws.ID, AuditLogFilter{}, "", 50)
 	if err != nil {
 		t.Fatalf("ListAuditLog() error = %v", err)
 	}
@@ -758,7 +758,7 @@ func TestListAuditLog_Pagination(t *testing.T) {
 	}
 
 	// Page 1
-	entries, hasMore, cursor, err := repo.ListAuditLog(ctx, ws.ID, "", 3)
+	entries, hasMore, cursor, err := repo.ListAuditLog(ctx, ws.ID, AuditLogFilter{}, "", 3)
 	if err != nil {
 		t.Fatalf("ListAuditLog() page 1 error = %v", err)
 	}
@@ -770,7 +770,7 @@ func TestListAuditLog_Pagination(t *testing.T) {
 	}
 
 	// Page 2
-	entries2, hasMore2, _, err := repo.ListAuditLog(ctx, ws.ID, cursor, 3)
+	entries2, hasMore2, _, err := repo.ListAuditLog(ctx, ws.ID, AuditLogFilter{}, cursor, 3)
 	if err != nil {
 		t.Fatalf("ListAuditLog() page 2 error = %v", err)
 	}
@@ -782,6 +782,58 @@ func TestListAuditLog_Pagination(t *testing.T) {
 	}
 }
 
+func TestListAuditLog_Filters(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	admin := testutil.CreateTestUser(t, db, "admin@example.com", "Admin")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+
+	// Actions outside the original moderation set must be accepted
+	for _, e := range []struct{ actor, action string }{
+		{owner.ID, ActionMemberRemoved},
+		{owner.ID, ActionMemberRoleChanged},
+		{admin.ID, ActionInviteCreated},
+		{admin.ID, "webhook.created"},
+	} {
+		if err := repo.CreateAuditLogEntry(ctx, &AuditLogEntry{
+			WorkspaceID: ws.ID, ActorID: e.actor, Action: e.action, TargetType: TargetTypeUser, TargetID: "target-id",
+		}); err != nil {
+			t.Fatalf("CreateAuditLogEntry(%s) error = %v", e.action, err)
+		}
+	}
+	// Backdate the first entry so the date range has something to exclude
+	if _, err := db.Exec(`UPDATE audit_log SET created_at = '2020-01-01T00:00:00Z' WHERE action = ?`, ActionMemberRemoved); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+
+	since := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		filter AuditLogFilter
+		want   int
+	}{
+		{"actor", AuditLogFilter{ActorID: admin.ID}, 2},
+		{"exact action", AuditLogFilter{Action: ActionMemberRoleChanged}, 1},
+		{"action prefix", AuditLogFilter{Action: "member."}, 2},
+		{"prefix needs trailing dot", AuditLogFilter{Action: "member"}, 0},
+		{"since", AuditLogFilter{Since: &since}, 3},
+		{"until", AuditLogFilter{Until: &since}, 1},
+		{"combined", AuditLogFilter{ActorID: owner.ID, Action: "member.", Since: &since}, 1},
+	}
+	for _, tt := range tests {
+		entries, _, _, err := repo.ListAuditLog(ctx, ws.ID, tt.filter, "", 50)
+		if err != nil {
+			t.Fatalf("%s: ListAuditLog() error = %v", tt.name, err)
+		}
+		if len(entries) != tt.want {
+			t.Errorf("%s: len(entries) = %d, want %d", tt.name, len(entries), tt.want)
+		}
+	}
+}
+
 func TestListAuditLog_Empty(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -790,7 +842,7 @@ func TestListAuditLog_Empty(t *testing.T) {
 	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
 	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
 
-	entries, hasMore, cursor, err := repo.ListAuditLog(ctx, ws.ID, "", 50)
+	entries, hasMore, cursor, err := repo.ListAuditLog(ctx, ws.ID, AuditLogFilter{}, "", 50)
 	if err != nil {
 		t.Fatalf("ListAuditLog() error = %v", err)
 	}
@@ -0,0 +1,214 @@
+// Package mrkdwn parses message content written in the mrkdwn markup used by
+// the clients' editor into a tree of nodes. The server returns the tree with
+// each message so every client renders formatting, mentions and links the
+// same way. It mirrors the parser in packages/shared/src/mrkdwn.
+package mrkdwn
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NodeType identifies the kind of a Node
+type NodeType string
+
+const (
+	NodeText           NodeType = "text"
+	NodeBold           NodeType = "bold"
+	NodeItalic         NodeType = "italic"
+	NodeStrike         NodeType = "strike"
+	NodeCode           NodeType = "code"
+	NodeCodeBlock      NodeType = "code_block"
+	NodeBlockquote     NodeType = "blockquote"
+	NodeBulletList     NodeType = "bullet_list"
+	NodeOrderedList    NodeType = "ordered_list"
+	NodeUserMention    NodeType = "user_mention"
+	NodeSpecialMention NodeType = "special_mention"
+	NodeGroupMention   NodeType = "group_mention"
+	NodeChannelMention NodeType = "channel_mention"
+	NodeLink           NodeType = "link"
+	NodeEmojiShortcode NodeType = "emoji_shortcode"
+	NodeLineBreak      NodeType = "line_break"
+)
+
+// Node is one element of parsed content. Which fields are set depends on
+// Type: Text for text, formatting, code and links; Language for code blocks;
+// URL for links; UserID, GroupID and ChannelID for mentions; Mention
+// (here, channel or everyone) for special mentions; Name for emoji
+// shortcodes; Children for block quotes; Items for lists.
+type Node struct {
+	Type      NodeType `json:"type"`
+	Text      string   `json:"text,omitempty"`
+	Language  string   `json:"language,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	GroupID   string   `json:"group_id,omitempty"`
+	ChannelID string   `json:"channel_id,omitempty"`
+	Mention   string   `json:"mention,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Children  []Node   `json:"children,omitempty"`
+	Items     [][]Node `json:"items,omitempty"`
+}
+
+var (
+	orderedItem = regexp.MustCompile(`^\d+\. `)
+
+	userMention    = regexp.MustCompile(`^<@([^>]+)>`)
+	specialMention = regexp.MustCompile(`^<!([^>]+)>`)
+	channelMention = regexp.MustCompile(`^<#([^>]+)>`)
+	linkWithText   = regexp.MustCompile(`^<(https?://[^|>]+)\|([^>]+)>`)
+	plainLink      = regexp.MustCompile(`^<(https?://[^>]+)>`)
+	inlineCode     = regexp.MustCompile("^`([^`]+)`")
+	emojiShortcode = regexp.MustCompile(`^:([a-zA-Z0-9_+-]+):`)
+	bold           = regexp.MustCompile(`^\*([^*]+)\*`)
+	italic         = regexp.MustCompile(`^_([^_]+)_`)
+	strike         = regexp.MustCompile(`^~([^~]+)~`)
+)
+
+// inlineSpecialChars are the characters that may start inline markup
+const inlineSpecialChars = "<`*_~:"
+
+// Parse parses content into nodes, handling both block-level markup (code
+// blocks, quotes, lists) and inline formatting. Markup that does not match
+// is kept as text.
+func Parse(content string) []Node {
+	if content == "" {
+		return nil
+	}
+
+	var nodes []Node
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.HasPrefix(line, "```"):
+			lang := strings.TrimSpace(line[3:])
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(lines[i], "```"); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // skip the closing fence
+			nodes = append(nodes, Node{Type: NodeCodeBlock, Text: strings.Join(code, "\n"), Language: lang})
+
+		case isQuoteLine(line):
+			var quoted []string
+			for ; i < len(lines) && isQuoteLine(lines[i]); i++ {
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimPrefix(lines[i], ">"), " "))
+			}
+			nodes = append(nodes, Node{Type: NodeBlockquote, Children: parseInline(strings.Join(quoted, "\n"))})
+
+		case isBulletLine(line):
+			var items [][]Node
+			for ; i < len(lines) && isBulletLine(lines[i]); i++ {
+				_, item, _ := strings.Cut(lines[i], " ")
+				items = append(items, parseInline(item))
+			}
+			nodes = append(nodes, Node{Type: NodeBulletList, Items: items})
+
+		case orderedItem.MatchString(line):
+			var items [][]Node
+			for ; i < len(lines) && orderedItem.MatchString(lines[i]); i++ {
+				items = append(items, parseInline(orderedItem.ReplaceAllString(lines[i], "")))
+			}
+			nodes = append(nodes, Node{Type: NodeOrderedList, Items: items})
+
+		case strings.TrimSpace(line) == "":
+			if len(nodes) > 0 {
+				nodes = append(nodes, Node{Type: NodeLineBreak})
+			}
+			i++
+
+		default:
+			nodes = append(nodes, parseInline(line)...)
+			if i < len(lines)-1 && strings.TrimSpace(lines[i+1]) != "" {
+				nodes = append(nodes, Node{Type: NodeLineBreak})
+			}
+			i++
+		}
+	}
+	return nodes
+}
+
+func isQuoteLine(line string) bool {
+	return strings.HasPrefix(line, "> ") || line == ">"
+}
+
+func isBulletLine(line string) bool {
+	return strings.HasPrefix(line, "• ") || strings.HasPrefix(line, "- ")
+}
+
+// parseInline parses inline formatting, mentions and links
+func parseInline(text string) []Node {
+	var nodes []Node
+	for rest := text; rest != ""; {
+		node, n := matchInline(rest)
+		if n > 0 {
+			nodes = append(nodes, node)
+			rest = rest[n:]
+			continue
+		}
+
+		// Consume plain text up to the next character that may start markup
+		switch next := strings.IndexAny(rest, inlineSpecialChars); next {
+		case -1:
+			nodes = appendText(nodes, rest)
+			rest = ""
+		case 0:
+			nodes = appendText(nodes, rest[:1])
+			rest = rest[1:]
+		default:
+			nodes = appendText(nodes, rest[:next])
+			rest = rest[next:]
+		}
+	}
+	return nodes
+}
+
+// matchInline matches inline markup at the start of s, returning the node
+// and the number of bytes consumed, or 0 when nothing matches
+func matchInline(s string) (Node, int) {
+	if m := userMention.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeUserMention, UserID: m[1]}, len(m[0])
+	}
+	if m := specialMention.FindStringSubmatch(s); m != nil {
+		if groupID, ok := strings.CutPrefix(m[1], "group:"); ok {
+			return Node{Type: NodeGroupMention, GroupID: groupID}, len(m[0])
+		}
+		return Node{Type: NodeSpecialMention, Mention: m[1]}, len(m[0])
+	}
+	if m := channelMention.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeChannelMention, ChannelID: m[1]}, len(m[0])
+	}
+	if m := linkWithText.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeLink, URL: m[1], Text: m[2]}, len(m[0])
+	}
+	if m := plainLink.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeLink, URL: m[1], Text: m[1]}, len(m[0])
+	}
+	if m := inlineCode.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeCode, Text: m[1]}, len(m[0])
+	}
+	if m := emojiShortcode.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeEmojiShortcode, Name: m[1]}, len(m[0])
+	}
+	if m := bold.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeBold, Text: m[1]}, len(m[0])
+	}
+	if m := italic.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeItalic, Text: m[1]}, len(m[0])
+	}
+	if m := strike.FindStringSubmatch(s); m != nil {
+		return Node{Type: NodeStrike, Text: m[1]}, len(m[0])
+	}
+	return Node{}, 0
+}
+
+// appendText appends text, merging it into a trailing text node
+func appendText(nodes []Node, text string) []Node {
+	if n := len(nodes); n > 0 && nodes[n-1].Type == NodeText {
+		nodes[n-1].Text += text
+		return nodes
+	}
+	return append(nodes, Node{Type: NodeText, Text: text})
+}
@@ -0,0 +1,112 @@
+package mrkdwn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Node
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    nil,
+		},
+		{
+			name:    "mixed inline formatting",
+			content: "Hello *bold* and _italic_ ~gone~ `code`",
+			want: []Node{
+				{Type: NodeText, Text: "Hello "},
+				{Type: NodeBold, Text: "bold"},
+				{Type: NodeText, Text: " and "},
+				{Type: NodeItalic, Text: "italic"},
+				{Type: NodeText, Text: " "},
+				{Type: NodeStrike, Text: "gone"},
+				{Type: NodeText, Text: " "},
+				{Type: NodeCode, Text: "code"},
+			},
+		},
+		{
+			name:    "unclosed markers are text",
+			content: "*unclosed",
+			want:    []Node{{Type: NodeText, Text: "*unclosed"}},
+		},
+		{
+			name:    "code block with language",
+			content: "Here is some code:\n```go\nx := 1\n\ny := *x*\n```",
+			want: []Node{
+				{Type: NodeText, Text: "Here is some code:"},
+				{Type: NodeLineBreak},
+				{Type: NodeCodeBlock, Text: "x := 1\n\ny := *x*", Language: "go"},
+			},
+		},
+		{
+			name:    "blockquote",
+			content: "> line 1\n> *line* 2",
+			want: []Node{{Type: NodeBlockquote, Children: []Node{
+				{Type: NodeText, Text: "line 1\n"},
+				{Type: NodeBold, Text: "line"},
+				{Type: NodeText, Text: " 2"},
+			}}},
+		},
+		{
+			name:    "bullet and ordered lists",
+			content: "• Item 1\n- *Item* 2\n1. First\n2. Second",
+			want: []Node{
+				{Type: NodeBulletList, Items: [][]Node{
+					{{Type: NodeText, Text: "Item 1"}},
+					{{Type: NodeBold, Text: "Item"}, {Type: NodeText, Text: " 2"}},
+				}},
+				{Type: NodeOrderedList, Items: [][]Node{
+					{{Type: NodeText, Text: "First"}},
+					{{Type: NodeText, Text: "Second"}},
+				}},
+			},
+		},
+		{
+			name:    "mentions",
+			content: "<@U1> <!here> <!group:G1> <#C1>",
+			want: []Node{
+				{Type: NodeUserMention, UserID: "U1"},
+				{Type: NodeText, Text: " "},
+				{Type: NodeSpecialMention, Mention: "here"},
+				{Type: NodeText, Text: " "},
+				{Type: NodeGroupMention, GroupID: "G1"},
+				{Type: NodeText, Text: " "},
+				{Type: NodeChannelMention, ChannelID: "C1"},
+			},
+		},
+		{
+			name:    "links and emoji",
+			content: "<https://example.com|click here> <http://a.test> <javascript:alert(1)> :+1:",
+			want: []Node{
+				{Type: NodeLink, URL: "https://example.com", Text: "click here"},
+				{Type: NodeText, Text: " "},
+				{Type: NodeLink, URL: "http://a.test", Text: "http://a.test"},
+				{Type: NodeText, Text: " <javascript:alert(1)> "},
+				{Type: NodeEmojiShortcode, Name: "+1"},
+			},
+		},
+		{
+			name:    "paragraphs",
+			content: "Paragraph 1\n\nParagraph 2",
+			want: []Node{
+				{Type: NodeText, Text: "Paragraph 1"},
+				{Type: NodeLineBreak},
+				{Type: NodeText, Text: "Paragraph 2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/enzyme/server/internal/logging"
+)
+
+// DispatcherOptions configures a Dispatcher.
+type DispatcherOptions struct {
+	Workers      int           // notifications delivered at once
+	QueueSize    int           // messages waiting for a worker before Enqueue blocks
+	MaxAttempts  int           // deliveries tried per message before it is dead-lettered
+	RetryBackoff time.Duration // wait before the first retry; doubled for each one after
+}
+
+// Dispatcher delivers notifications in the background. A fixed pool of
+// workers takes messages from a bounded queue. Failed deliveries are retried
+// with exponential backoff, only for the recipients that failed, and messages
+// that still fail after the last attempt are logged as dead letters.
+type Dispatcher struct {
+	deliver func(ctx context.Context, channel *ChannelInfo, msg *MessageInfo, only map[string]bool) ([]string, error)
+	opts    DispatcherOptions
+	jobs    chan *dispatchJob
+
+	mu        sync.RWMutex // guards closed, so nothing is queued once draining starts
+	closed    bool
+	drain     chan struct{} // closed when draining starts; retries and Enqueue stop waiting
+	drainOnce sync.Once
+	quit      chan struct{} // closed when workers should exit
+	pending   sync.WaitGroup
+	queued    atomic.Int64 // messages accepted and not yet delivered or dead-lettered
+	workers   sync.WaitGroup
+}
+
+type dispatchJob struct {
+	ctx      context.Context
+	channel  *ChannelInfo
+	msg      *MessageInfo
+	only     map[string]bool // recipients left to notify; nil means all
+	attempts int
+}
+
+// NewDispatcher creates a dispatcher that delivers through s. Call Start to
+// run its workers.
+func NewDispatcher(s *Service, opts DispatcherOptions) *Dispatcher {
+	return &Dispatcher{
+		deliver: s.notify,
+		opts:    opts,
+		jobs:    make(chan *dispatchJob, opts.QueueSize),
+		drain:   make(chan struct{}),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start runs the workers.
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.opts.Workers; i++ {
+		d.workers.Add(1)
+		go d.work()
+	}
+}
+
+// Enqueue queues notifications for msg, waiting for room in the queue while
+// ctx is live and the dispatcher is not shutting down. It reports whether msg
+// was queued; a message turned away is logged as a dead letter.
+func (d *Dispatcher) Enqueue(ctx context.Context, channel *ChannelInfo, msg *MessageInfo) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.deadLetter(channel, msg, nil, 0, "shutting down")
+		return false
+	}
+
+	job := &dispatchJob{ctx: logging.Detach(ctx), channel: channel, msg: msg}
+	d.pending.Add(1)
+	d.queued.Add(1)
+	select {
+	case d.jobs <- job:
+		return true
+	case <-ctx.Done():
+		d.done()
+		d.deadLetter(channel, msg, nil, 0, "queue full")
+		return false
+	case <-d.drain:
+		d.done()
+		d.deadLetter(channel, msg, nil, 0, "shutting down")
+		return false
+	}
+}
+
+// Shutdown stops accepting messages and waits for the queue to drain,
+// retrying failures without waiting out their backoff. Messages still queued
+// when ctx ends are logged as dead letters.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	// Release Enqueue calls waiting for room first: they hold the read lock
+	d.drainOnce.Do(func() { close(d.drain) })
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		d.pending.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		slog.Error("notification queue not drained before shutdown",
+			"component", "notification",
+			"dropped", d.queued.Load(),
+		)
+	}
+	close(d.quit)
+	d.workers.Wait()
+	return err
+}
+
+func (d *Dispatcher) work() {
+	defer d.workers.Done()
+	for {
+		select {
+		case job := <-d.jobs:
+			d.run(job)
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// run delivers a job and schedules a retry if any of it failed.
+func (d *Dispatcher) run(job *dispatchJob) {
+	job.attempts++
+	failed, err := d.deliver(job.ctx, job.channel, job.msg, job.only)
+	if err == nil {
+		d.done()
+		return
+	}
+
+	// Retry only the recipients that failed; with none listed, nobody was
+	// notified and the whole message is retried
+	if len(failed) > 0 {
+		job.only = make(map[string]bool, len(failed))
+		for _, userID := range failed {
+			job.only[userID] = true
+		}
+	}
+
+	if job.attempts >= d.opts.MaxAttempts {
+		d.done()
+		d.deadLetter(job.channel, job.msg, failed, job.attempts, err.Error())
+		return
+	}
+
+	delay := d.opts.RetryBackoff << (job.attempts - 1)
+	slog.Warn("notification delivery failed, retrying",
+		"component", "notification",
+		"message_id", job.msg.ID,
+		"attempt", job.attempts,
+		"retry_in", delay,
+		"error", err,
+	)
+	go d.retry(job, delay)
+}
+
+// retry requeues job after delay, or at once if the dispatcher is draining.
+func (d *Dispatcher) retry(job *dispatchJob, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-d.drain:
+	}
+
+	select {
+	case d.jobs <- job:
+	case <-d.quit:
+		d.deadLetter(job.channel, job.msg, nil, job.attempts, "shut down before retry")
+	}
+}
+
+func (d *Dispatcher) done() {
+	d.queued.Add(-1)
+	d.pending.Done()
+}
+
+// deadLetter logs a message whose notifications were given up on, with
+// enough detail to find it again.
+func (d *Dispatcher) deadLetter(channel *ChannelInfo, msg *MessageInfo, recipients []string, attempts int, reason string) {
+	slog.Error("notification dead letter",
+		"component", "notification",
+		"workspace_id", channel.WorkspaceID,
+		"channel_id", channel.ID,
+		"message_id", msg.ID,
+		"recipients", recipients,
+		"attempts", attempts,
+		"reason", reason,
+	)
+}
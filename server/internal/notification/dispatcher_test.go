@@ -0,0 +1,161 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder stands in for Service.notify. Its first failures calls fail,
+// either for the recipients in failing or for the whole message.
+type recorder struct {
+	mu       sync.Mutex
+	calls    []map[string]bool
+	failures int
+	failing  []string
+	whole    bool // fail without naming recipients
+}
+
+func (r *recorder) deliver(ctx context.Context, channel *ChannelInfo, msg *MessageInfo, only map[string]bool) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, only)
+	if len(r.calls) > r.failures {
+		return nil, nil
+	}
+	if r.whole {
+		return nil, errors.New("database is locked")
+	}
+	return r.failing, errors.New("queueing email failed")
+}
+
+func (r *recorder) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+func newTestDispatcher(r *recorder, maxAttempts int) *Dispatcher {
+	d := NewDispatcher(&Service{}, DispatcherOptions{
+		Workers:      2,
+		QueueSize:    4,
+		MaxAttempts:  maxAttempts,
+		RetryBackoff: time.Millisecond,
+	})
+	d.deliver = r.deliver
+	d.Start()
+	return d
+}
+
+var (
+	testChannel = &ChannelInfo{ID: "c1", WorkspaceID: "w1", Name: "general", Type: "public"}
+	testMessage = &MessageInfo{ID: "m1", ChannelID: "c1", SenderID: "u1"}
+)
+
+func TestDispatcher_RetriesFailedRecipients(t *testing.T) {
+	r := &recorder{failures: 2, failing: []string{"u3"}}
+	d := newTestDispatcher(r, 5)
+
+	if !d.Enqueue(context.Background(), testChannel, testMessage) {
+		t.Fatal("expected the message to be queued")
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if len(r.calls) != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", len(r.calls))
+	}
+	if r.calls[0] != nil {
+		t.Errorf("expected the first delivery to go to everyone, got %v", r.calls[0])
+	}
+	for _, only := range r.calls[1:] {
+		if len(only) != 1 || !only["u3"] {
+			t.Errorf("expected retries to go only to u3, got %v", only)
+		}
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	r := &recorder{failures: 10, whole: true}
+	d := newTestDispatcher(r, 3)
+
+	d.Enqueue(context.Background(), testChannel, testMessage)
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := r.callCount(); got != 3 {
+		t.Errorf("expected 3 attempts before giving up, got %d", got)
+	}
+	if slices.ContainsFunc(r.calls, func(only map[string]bool) bool { return only != nil }) {
+		t.Error("expected whole-message failures to retry everyone")
+	}
+}
+
+func TestDispatcher_DrainSkipsBackoff(t *testing.T) {
+	r := &recorder{failures: 1, whole: true}
+	d := NewDispatcher(&Service{}, DispatcherOptions{
+		Workers:      1,
+		QueueSize:    4,
+		MaxAttempts:  2,
+		RetryBackoff: time.Hour,
+	})
+	d.deliver = r.deliver
+	d.Start()
+
+	d.Enqueue(context.Background(), testChannel, testMessage)
+	for deadline := time.Now().Add(5 * time.Second); r.callCount() == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if got := r.callCount(); got != 2 {
+		t.Errorf("expected the retry to run during shutdown, got %d deliveries", got)
+	}
+
+	if d.Enqueue(context.Background(), testChannel, testMessage) {
+		t.Error("expected nothing to be queued after shutdown")
+	}
+}
+
+func TestDispatcher_ShutdownReleasesBlockedEnqueue(t *testing.T) {
+	// No workers, so the queue stays full
+	d := NewDispatcher(&Service{}, DispatcherOptions{QueueSize: 1, MaxAttempts: 1})
+	d.deliver = (&recorder{}).deliver
+	if !d.Enqueue(context.Background(), testChannel, testMessage) {
+		t.Fatal("expected the first message to be queued")
+	}
+
+	queued := make(chan bool)
+	go func() { queued <- d.Enqueue(context.Background(), testChannel, testMessage) }()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	shutdown := make(chan error)
+	go func() { shutdown <- d.Shutdown(ctx) }()
+
+	select {
+	case ok := <-queued:
+		if ok {
+			t.Error("expected the waiting message to be turned away")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Enqueue still blocked after Shutdown started")
+	}
+	select {
+	case err := <-shutdown:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Shutdown = %v, want the undrained queue to be reported", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown blocked by a waiting Enqueue")
+	}
+}
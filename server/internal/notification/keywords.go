@@ -0,0 +1,184 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Keyword limits
+const (
+	MaxKeywordsPerUser = 50
+	MaxKeywordLength   = 64
+)
+
+var (
+	ErrKeywordNotFound = errors.New("keyword not found")
+	ErrKeywordExists   = errors.New("keyword already exists")
+	ErrTooManyKeywords = errors.New("too many keywords")
+)
+
+// Keyword is a word or phrase that notifies a user like a mention when it
+// appears in a message in one of their channels
+type Keyword struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Keyword   string    `json:"keyword"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// KeywordRepository handles notification keyword persistence
+type KeywordRepository struct {
+	db *sql.DB
+}
+
+// NewKeywordRepository creates a new keyword repository
+func NewKeywordRepository(db *sql.DB) *KeywordRepository {
+	return &KeywordRepository{db: db}
+}
+
+// List returns a user's keywords, oldest first
+func (r *KeywordRepository) List(ctx context.Context, userID string) ([]Keyword, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, keyword, created_at
+		FROM notification_keywords
+		WHERE user_id = ?
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := []Keyword{}
+	for rows.Next() {
+		var k Keyword
+		var createdAt string
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Keyword, &createdAt); err != nil {
+			return nil, err
+		}
+		k.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		keywords = append(keywords, k)
+	}
+	return keywords, rows.Err()
+}
+
+// Create adds a keyword. Returns ErrKeywordExists if the user already has it,
+// ignoring case, and ErrTooManyKeywords once they have MaxKeywordsPerUser.
+func (r *KeywordRepository) Create(ctx context.Context, k *Keyword) error {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM notification_keywords WHERE user_id = ?
+	`, k.UserID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= MaxKeywordsPerUser {
+		return ErrTooManyKeywords
+	}
+
+	k.ID = ulid.Make().String()
+	k.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_keywords (id, user_id, keyword, created_at)
+		VALUES (?, ?, ?, ?)
+	`, k.ID, k.UserID, k.Keyword, k.CreatedAt.Format(time.RFC3339))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return ErrKeywordExists
+	}
+	return err
+}
+
+// Delete removes one of the user's keywords
+func (r *KeywordRepository) Delete(ctx context.Context, userID, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM notification_keywords WHERE id = ? AND user_id = ?
+	`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrKeywordNotFound
+	}
+	return nil
+}
+
+// ListForChannelMembers returns the keywords of the channel's members,
+// keyed by user ID. Members without keywords are left out.
+func (r *KeywordRepository) ListForChannelMembers(ctx context.Context, channelID string) (map[string][]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT nk.user_id, nk.keyword
+		FROM notification_keywords nk
+		JOIN channel_memberships cm ON cm.user_id = nk.user_id
+		WHERE cm.channel_id = ?
+	`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := make(map[string][]string)
+	for rows.Next() {
+		var userID, keyword string
+		if err := rows.Scan(&userID, &keyword); err != nil {
+			return nil, err
+		}
+		keywords[userID] = append(keywords[userID], keyword)
+	}
+	return keywords, rows.Err()
+}
+
+// MatchKeywords returns, sorted, the users with at least one keyword in
+// content. Keywords match whole words or phrases, ignoring case.
+func MatchKeywords(content string, keywords map[string][]string) []string {
+	if content == "" {
+		return nil
+	}
+	lowered := strings.ToLower(content)
+
+	var userIDs []string
+	for userID, words := range keywords {
+		for _, word := range words {
+			if containsWord(lowered, strings.ToLower(word)) {
+				userIDs = append(userIDs, userID)
+				break
+			}
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs
+}
+
+// containsWord reports whether word appears in s with no letter, digit or
+// underscore directly before or after it
+func containsWord(s, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; offset < len(s); {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(s) || !isWordRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		offset = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r)
+}
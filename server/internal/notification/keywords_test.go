@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchKeywords(t *testing.T) {
+	keywords := map[string][]string{
+		"alice": {"deploy"},
+		"bob":   {"on call", "Postgres"},
+		"carol": {"go"},
+	}
+
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"Deploy is done", []string{"alice"}},
+		{"redeployed overnight", nil},
+		{"who is ON CALL this week?", []string{"bob"}},
+		{"our postgres_backup job", nil},
+		{"postgres, then deploy.", []string{"alice", "bob"}},
+		{"let's go!", []string{"carol"}},
+		{"google it", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := MatchKeywords(tt.content, keywords); !slices.Equal(got, tt.want) {
+			t.Errorf("MatchKeywords(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
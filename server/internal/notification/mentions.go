@@ -16,9 +16,15 @@ const (
 // mrkdwnUserMention matches <@userId> format from the rich text editor
 var mrkdwnUserMention = regexp.MustCompile(`<@([^>]+)>`)
 
-// mrkdwnSpecialMention matches <!here>, <!channel>, <!everyone> from the rich text editor
+// mrkdwnSpecialMention matches <!here>, <!channel>, <!everyone> and
+// <!group:groupId> from the rich text editor
 var mrkdwnSpecialMention = regexp.MustCompile(`<!([^>]+)>`)
 
+// groupHandlePattern matches @handle patterns naming a user group. The @ must
+// not follow a word character or <, so email addresses and <@userId> are not
+// mistaken for one.
+var groupHandlePattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.<])@([A-Za-z0-9]+(?:-[A-Za-z0-9]+)*)\b`)
+
 // mentionPattern matches @display_name patterns (plain text fallback)
 // Matches @ followed by one or more words (display names can have spaces)
 var mentionPattern = regexp.MustCompile(`@([A-Za-z][A-Za-z0-9 ]*[A-Za-z0-9]|[A-Za-z])`)
@@ -28,8 +34,17 @@ type UserResolver interface {
 	ResolveDisplayNames(ctx context.Context, workspaceID string, names []string) (map[string]string, error)
 }
 
+// GroupResolver resolves user groups, referenced by ID or handle, to the IDs
+// of their members. A UserResolver that also implements GroupResolver lets
+// ParseMentions expand group mentions.
+type GroupResolver interface {
+	ResolveGroupMembers(ctx context.Context, workspaceID string, refs []string) ([]string, error)
+}
+
 // ParseMentions extracts and resolves mentions from message content.
-// Supports both mrkdwn format (<@userId>, <!here>) and plain text (@DisplayName, @here).
+// Supports both mrkdwn format (<@userId>, <!here>, <!group:groupId>) and plain
+// text (@DisplayName, @here, @group-handle). Group mentions are expanded to the
+// group's members when the resolver implements GroupResolver.
 // Returns a list of user IDs and special mention strings (@channel, @here, @everyone).
 // Invalid mentions are silently ignored.
 func ParseMentions(ctx context.Context, resolver UserResolver, workspaceID, content string) ([]string, error) {
@@ -38,6 +53,7 @@ func ParseMentions(ctx context.Context, resolver UserResolver, workspaceID, cont
 	}
 
 	var mentions []string
+	var groupRefs []string
 	seenUsers := make(map[string]bool)
 	seenSpecial := make(map[string]bool)
 
@@ -58,8 +74,14 @@ func ParseMentions(ctx context.Context, resolver UserResolver, workspaceID, cont
 		if len(match) < 2 {
 			continue
 		}
-		name := strings.ToLower(strings.TrimSpace(match[1]))
-		switch name {
+		raw := strings.TrimSpace(match[1])
+		if groupID, ok := strings.CutPrefix(raw, "group:"); ok {
+			if groupID != "" {
+				groupRefs = append(groupRefs, groupID)
+			}
+			continue
+		}
+		switch strings.ToLower(raw) {
 		case "channel":
 			if !seenSpecial[MentionChannel] {
 				mentions = append(mentions, MentionChannel)
@@ -131,6 +153,25 @@ func ParseMentions(ctx context.Context, resolver UserResolver, workspaceID, cont
 		}
 	}
 
+	// Resolve group IDs and @handles to their members
+	if groups, ok := resolver.(GroupResolver); ok {
+		for _, match := range groupHandlePattern.FindAllStringSubmatch(content, -1) {
+			if handle := strings.ToLower(match[1]); handle != "channel" && handle != "here" && handle != "everyone" {
+				groupRefs = append(groupRefs, handle)
+			}
+		}
+		if len(groupRefs) > 0 {
+			// Don't fail on resolution errors, just skip the group mentions
+			memberIDs, _ := groups.ResolveGroupMembers(ctx, workspaceID, groupRefs)
+			for _, userID := range memberIDs {
+				if !seenUsers[userID] {
+					mentions = append(mentions, userID)
+					seenUsers[userID] = true
+				}
+			}
+		}
+	}
+
 	return mentions, nil
 }
 
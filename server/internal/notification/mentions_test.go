@@ -109,6 +109,62 @@ func TestParseMentions_MixedMrkdwnAndPlainText(t *testing.T) {
 	}
 }
 
+// mockGroupResolver implements UserResolver and GroupResolver for testing
+type mockGroupResolver struct {
+	mockResolver
+	groups map[string][]string // group ID or handle -> member IDs
+	refs   []string
+}
+
+func (m *mockGroupResolver) ResolveGroupMembers(_ context.Context, _ string, refs []string) ([]string, error) {
+	m.refs = refs
+	var ids []string
+	for _, ref := range refs {
+		ids = append(ids, m.groups[ref]...)
+	}
+	return ids, nil
+}
+
+func TestParseMentions_GroupMentions(t *testing.T) {
+	ctx := context.Background()
+
+	resolver := &mockGroupResolver{
+		groups: map[string][]string{
+			"backend": {"alice-id", "bob-id"},
+			"grp1":    {"bob-id", "carol-id"},
+		},
+	}
+
+	mentions, err := ParseMentions(ctx, resolver, "ws1", "<@alice-id> cc @Backend and <!group:grp1>, not me@oncall.example.com or @here")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"alice-id", MentionHere, "bob-id", "carol-id"}
+	if len(mentions) != len(want) {
+		t.Fatalf("got %v, want %v", mentions, want)
+	}
+	for i := range want {
+		if mentions[i] != want[i] {
+			t.Errorf("mentions[%d] = %q, want %q", i, mentions[i], want[i])
+		}
+	}
+	if len(resolver.refs) != 2 || resolver.refs[0] != "grp1" || resolver.refs[1] != "backend" {
+		t.Errorf("resolved refs = %v, want [grp1 backend]", resolver.refs)
+	}
+}
+
+func TestParseMentions_GroupMentionsNeedGroupResolver(t *testing.T) {
+	ctx := context.Background()
+
+	mentions, err := ParseMentions(ctx, &mockResolver{}, "ws1", "ping <!group:grp1> and @backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mentions) != 0 {
+		t.Errorf("got %v, want no mentions", mentions)
+	}
+}
+
 func TestParseMentions_EmptyContent(t *testing.T) {
 	ctx := context.Background()
 
@@ -26,6 +26,25 @@ type NotificationPreference struct {
 	EmailEnabled bool      `json:"email_enabled"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Inherited is set when the channel has no preference of its own and
+	// the values come from the workspace or global settings
+	Inherited bool `json:"inherited"`
+}
+
+// Settings are a user's notification defaults for a workspace, or for all
+// workspaces when WorkspaceID is empty
+type Settings struct {
+	UserID       string    `json:"user_id"`
+	WorkspaceID  string    `json:"workspace_id,omitempty"`
+	NotifyLevel  string    `json:"notify_level"`
+	EmailEnabled bool      `json:"email_enabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Inherited is set when nothing is stored at this level and the values
+	// come from a broader one or the built-in default
+	Inherited bool `json:"inherited"`
 }
 
 var ErrPreferenceNotFound = errors.New("notification preference not found")
@@ -71,7 +90,10 @@ func (r *PreferencesRepository) Get(ctx context.Context, userID, channelID strin
 	return &pref, nil
 }
 
-// GetOrDefault retrieves preferences or returns defaults based on channel type
+// GetOrDefault retrieves the channel's preferences or, when there are none,
+// the ones it inherits: the workspace settings, then the global settings,
+// then the built-in default. DMs inherit email settings but keep notifying
+// for every message unless their own preference says otherwise.
 func (r *PreferencesRepository) GetOrDefault(ctx context.Context, userID, channelID, channelType string) (*NotificationPreference, error) {
 	pref, err := r.Get(ctx, userID, channelID)
 	if err == nil {
@@ -81,17 +103,27 @@ func (r *PreferencesRepository) GetOrDefault(ctx context.Context, userID, channe
 		return nil, err
 	}
 
-	// Return default preferences based on channel type
-	defaultLevel := NotifyMentions
+	var workspaceID string
+	err = r.db.QueryRowContext(ctx, `SELECT workspace_id FROM channels WHERE id = ?`, channelID).Scan(&workspaceID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	settings, err := r.GetWorkspaceSettingsOrDefault(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyLevel := settings.NotifyLevel
 	if channelType == "dm" || channelType == "group_dm" {
-		defaultLevel = NotifyAll
+		notifyLevel = NotifyAll
 	}
 
 	return &NotificationPreference{
 		UserID:       userID,
 		ChannelID:    channelID,
-		NotifyLevel:  defaultLevel,
-		EmailEnabled: true,
+		NotifyLevel:  notifyLevel,
+		EmailEnabled: settings.EmailEnabled,
+		Inherited:    true,
 	}, nil
 }
 
@@ -169,3 +201,146 @@ func (r *PreferencesRepository) ListForUser(ctx context.Context, userID string)
 
 	return prefs, rows.Err()
 }
+
+// GetGlobalSettingsOrDefault returns the user's global notification
+// settings, or the built-in default if they have none
+func (r *PreferencesRepository) GetGlobalSettingsOrDefault(ctx context.Context, userID string) (*Settings, error) {
+	settings := Settings{UserID: userID}
+	var createdAt, updatedAt string
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT notify_level, email_enabled, created_at, updated_at
+		FROM user_notification_settings
+		WHERE user_id = ?
+	`, userID).Scan(&settings.NotifyLevel, &settings.EmailEnabled, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return &Settings{
+			UserID:       userID,
+			NotifyLevel:  NotifyMentions,
+			EmailEnabled: true,
+			Inherited:    true,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := settings.parseTimes(createdAt, updatedAt); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// GetWorkspaceSettingsOrDefault returns the user's notification settings for
+// the workspace, or the global settings they inherit if there are none
+func (r *PreferencesRepository) GetWorkspaceSettingsOrDefault(ctx context.Context, userID, workspaceID string) (*Settings, error) {
+	settings := Settings{UserID: userID, WorkspaceID: workspaceID}
+	var createdAt, updatedAt string
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT notify_level, email_enabled, created_at, updated_at
+		FROM workspace_notification_settings
+		WHERE user_id = ? AND workspace_id = ?
+	`, userID, workspaceID).Scan(&settings.NotifyLevel, &settings.EmailEnabled, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		global, err := r.GetGlobalSettingsOrDefault(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		global.WorkspaceID = workspaceID
+		global.Inherited = true
+		return global, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := settings.parseTimes(createdAt, updatedAt); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpsertSettings creates or updates the user's global settings, or their
+// workspace settings when WorkspaceID is set
+func (r *PreferencesRepository) UpsertSettings(ctx context.Context, settings *Settings) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	var createdAt, updatedAt string
+	var err error
+	if settings.WorkspaceID == "" {
+		err = r.db.QueryRowContext(ctx, `
+			INSERT INTO user_notification_settings (user_id, notify_level, email_enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(user_id) DO UPDATE SET
+				notify_level = excluded.notify_level,
+				email_enabled = excluded.email_enabled,
+				updated_at = excluded.updated_at
+			RETURNING created_at, updated_at
+		`, settings.UserID, settings.NotifyLevel, settings.EmailEnabled, now, now).Scan(&createdAt, &updatedAt)
+	} else {
+		err = r.db.QueryRowContext(ctx, `
+			INSERT INTO workspace_notification_settings (user_id, workspace_id, notify_level, email_enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(user_id, workspace_id) DO UPDATE SET
+				notify_level = excluded.notify_level,
+				email_enabled = excluded.email_enabled,
+				updated_at = excluded.updated_at
+			RETURNING created_at, updated_at
+		`, settings.UserID, settings.WorkspaceID, settings.NotifyLevel, settings.EmailEnabled, now, now).Scan(&createdAt, &updatedAt)
+	}
+	if err != nil {
+		return err
+	}
+
+	settings.Inherited = false
+	return settings.parseTimes(createdAt, updatedAt)
+}
+
+// DeleteWorkspaceSettings removes the user's workspace settings so the
+// workspace follows their global settings again
+func (r *PreferencesRepository) DeleteWorkspaceSettings(ctx context.Context, userID, workspaceID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM workspace_notification_settings WHERE user_id = ? AND workspace_id = ?
+	`, userID, workspaceID)
+	return err
+}
+
+func (s *Settings) parseTimes(createdAt, updatedAt string) error {
+	var err error
+	if s.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return fmt.Errorf("parsing created_at: %w", err)
+	}
+	if s.UpdatedAt, err = time.Parse(time.RFC3339, updatedAt); err != nil {
+		return fmt.Errorf("parsing updated_at: %w", err)
+	}
+	return nil
+}
+
+// SnoozedUserIDs returns the members of the channel who have snoozed it, or
+// its whole workspace, until a time still in the future
+func (r *PreferencesRepository) SnoozedUserIDs(ctx context.Context, channelID string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cm.user_id
+		FROM channel_memberships cm
+		JOIN channels c ON c.id = cm.channel_id
+		LEFT JOIN workspace_memberships wm ON wm.workspace_id = c.workspace_id AND wm.user_id = cm.user_id
+		WHERE cm.channel_id = ?
+		  AND (cm.snoozed_until > strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		       OR wm.snoozed_until > strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+	`, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snoozed := make(map[string]bool)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		snoozed[userID] = true
+	}
+	return snoozed, rows.Err()
+}
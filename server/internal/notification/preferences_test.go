@@ -137,3 +137,63 @@ func TestPreferencesRepository_Upsert_PopulatesAllFields(t *testing.T) {
 		t.Errorf("NotifyLevel = %q, want %q", pref.NotifyLevel, NotifyNone)
 	}
 }
+
+func TestPreferencesRepository_GetOrDefault_Hierarchy(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewPreferencesRepository(db)
+	ctx := context.Background()
+
+	user := testutil.CreateTestUser(t, db, "user@example.com", "User")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "general", "public")
+	dm := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "dm", "dm")
+
+	resolve := func(channelID, channelType string) *NotificationPreference {
+		t.Helper()
+		pref, err := repo.GetOrDefault(ctx, user.ID, channelID, channelType)
+		if err != nil {
+			t.Fatalf("GetOrDefault() error = %v", err)
+		}
+		return pref
+	}
+
+	if pref := resolve(ch.ID, "public"); pref.NotifyLevel != NotifyMentions || !pref.EmailEnabled || !pref.Inherited {
+		t.Errorf("default = %+v, want inherited mentions with email", pref)
+	}
+
+	if err := repo.UpsertSettings(ctx, &Settings{UserID: user.ID, NotifyLevel: NotifyAll, EmailEnabled: false}); err != nil {
+		t.Fatalf("UpsertSettings(global) error = %v", err)
+	}
+	if pref := resolve(ch.ID, "public"); pref.NotifyLevel != NotifyAll || pref.EmailEnabled {
+		t.Errorf("with global settings = %+v, want all without email", pref)
+	}
+
+	if err := repo.UpsertSettings(ctx, &Settings{UserID: user.ID, WorkspaceID: ws.ID, NotifyLevel: NotifyNone, EmailEnabled: true}); err != nil {
+		t.Fatalf("UpsertSettings(workspace) error = %v", err)
+	}
+	if pref := resolve(ch.ID, "public"); pref.NotifyLevel != NotifyNone || !pref.EmailEnabled {
+		t.Errorf("with workspace settings = %+v, want none with email", pref)
+	}
+	// DMs take email settings from the workspace but keep notifying
+	if pref := resolve(dm.ID, "dm"); pref.NotifyLevel != NotifyAll || !pref.EmailEnabled {
+		t.Errorf("dm = %+v, want all with email", pref)
+	}
+
+	if err := repo.Upsert(ctx, &NotificationPreference{UserID: user.ID, ChannelID: ch.ID, NotifyLevel: NotifyMentions}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if pref := resolve(ch.ID, "public"); pref.NotifyLevel != NotifyMentions || pref.Inherited {
+		t.Errorf("with channel preference = %+v, want its own mentions", pref)
+	}
+
+	if err := repo.DeleteWorkspaceSettings(ctx, user.ID, ws.ID); err != nil {
+		t.Fatalf("DeleteWorkspaceSettings() error = %v", err)
+	}
+	settings, err := repo.GetWorkspaceSettingsOrDefault(ctx, user.ID, ws.ID)
+	if err != nil {
+		t.Fatalf("GetWorkspaceSettingsOrDefault() error = %v", err)
+	}
+	if settings.NotifyLevel != NotifyAll || !settings.Inherited {
+		t.Errorf("workspace after reset = %+v, want the inherited global level", settings)
+	}
+}
@@ -2,6 +2,9 @@ package notification
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/enzyme/server/internal/openapi"
@@ -60,6 +63,7 @@ type PushSender interface {
 type Service struct {
 	prefsRepo         *PreferencesRepository
 	pendingRepo       *PendingRepository
+	keywordRepo       *KeywordRepository
 	channelProvider   ChannelMemberProvider
 	threadSubProvider ThreadSubscriptionProvider
 	pushService       PushSender
@@ -67,18 +71,21 @@ type Service struct {
 	emailDelay        time.Duration
 	publicURL         string
 	includePreview    bool
+	dispatcher        *Dispatcher
 }
 
 // NewService creates a new notification service
 func NewService(
 	prefsRepo *PreferencesRepository,
 	pendingRepo *PendingRepository,
+	keywordRepo *KeywordRepository,
 	channelProvider ChannelMemberProvider,
 	hub *sse.Hub,
 ) *Service {
 	return &Service{
 		prefsRepo:         prefsRepo,
 		pendingRepo:       pendingRepo,
+		keywordRepo:       keywordRepo,
 		channelProvider:   channelProvider,
 		threadSubProvider: nil, // Set via SetThreadSubscriptionProvider
 		hub:               hub,
@@ -100,16 +107,65 @@ func (s *Service) SetPushService(sender PushSender, publicURL string, includePre
 	s.includePreview = includePreview
 }
 
+// SetDispatcher makes Enqueue queue notifications on d instead of sending
+// them right away.
+func (s *Service) SetDispatcher(d *Dispatcher) {
+	s.dispatcher = d
+}
+
+// Enqueue sends notifications for a message in the background through the
+// dispatcher. Without one (in tests and tools) it sends them before returning.
+func (s *Service) Enqueue(ctx context.Context, channel *ChannelInfo, msg *MessageInfo) {
+	if s.dispatcher != nil {
+		s.dispatcher.Enqueue(ctx, channel, msg)
+		return
+	}
+	if err := s.Notify(ctx, channel, msg); err != nil {
+		slog.Error("failed to send notifications", "component", "notification", "message_id", msg.ID, "error", err)
+	}
+}
+
 // Notify processes a message and sends notifications to appropriate recipients
 func (s *Service) Notify(ctx context.Context, channel *ChannelInfo, msg *MessageInfo) error {
-	_, notificationTypes := s.determineRecipients(ctx, channel, msg)
+	_, err := s.notify(ctx, channel, msg, nil)
+	return err
+}
+
+// notify sends notifications for msg to its recipients, or only to those in
+// only when it is not nil. It returns the recipients whose notification
+// failed; an error with none listed means no recipients could be worked out.
+func (s *Service) notify(ctx context.Context, channel *ChannelInfo, msg *MessageInfo, only map[string]bool) ([]string, error) {
+	_, notificationTypes, err := s.determineRecipients(ctx, channel, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed lookup only means snoozed users may still be notified
+	snoozed, _ := s.prefsRepo.SnoozedUserIDs(ctx, channel.ID)
 
+	var failed []string
+	var errs []error
 	for userID, notifType := range notificationTypes {
 		// Skip the sender
 		if userID == msg.SenderID {
 			continue
 		}
 
+		// Skip recipients already notified on an earlier attempt
+		if only != nil && !only[userID] {
+			continue
+		}
+
+		// Skip users who snoozed the channel or workspace
+		if snoozed[userID] {
+			continue
+		}
+
+		// Skip users who already have the channel on screen
+		if msg.ThreadParentID == nil && s.hub.IsViewingChannel(channel.WorkspaceID, userID, channel.ID) {
+			continue
+		}
+
 		// Check if user is online in this workspace
 		isOnline := s.hub.IsUserOnline(channel.WorkspaceID, userID)
 
@@ -127,7 +183,7 @@ func (s *Service) Notify(ctx context.Context, channel *ChannelInfo, msg *Message
 
 		if isOnline {
 			// Send real-time SSE notification
-			s.hub.BroadcastToUser(channel.WorkspaceID, userID, sseEvent)
+			s.hub.BroadcastToUser(ctx, channel.WorkspaceID, userID, sseEvent)
 		} else {
 			// Try push notification first
 			pushedOK := false
@@ -163,28 +219,31 @@ func (s *Service) Notify(ctx context.Context, channel *ChannelInfo, msg *Message
 					NotificationType: notifType,
 					SendAfter:        time.Now().UTC().Add(s.emailDelay),
 				}
-				// Ignore error - email is best effort
-				_ = s.pendingRepo.Create(ctx, pending)
+				if err := s.pendingRepo.Create(ctx, pending); err != nil {
+					failed = append(failed, userID)
+					errs = append(errs, fmt.Errorf("queueing email for %s: %w", userID, err))
+				}
 			}
 		}
 	}
 
-	return nil
+	return failed, errors.Join(errs...)
 }
 
 // determineRecipients determines who should receive notifications and why
-func (s *Service) determineRecipients(ctx context.Context, channel *ChannelInfo, msg *MessageInfo) ([]string, map[string]string) {
+func (s *Service) determineRecipients(ctx context.Context, channel *ChannelInfo, msg *MessageInfo) ([]string, map[string]string, error) {
 	notificationTypes := make(map[string]string) // userID -> notification type
 
 	// Handle thread replies - notify subscribers regardless of channel notification preferences
 	// Thread subscriptions override channel mute (like Slack behavior)
 	if msg.ThreadParentID != nil && s.threadSubProvider != nil {
 		subscriberIDs, err := s.threadSubProvider.GetSubscribedUserIDs(ctx, *msg.ThreadParentID)
-		if err == nil {
-			for _, userID := range subscriberIDs {
-				if userID != msg.SenderID {
-					notificationTypes[userID] = TypeThreadReply
-				}
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing thread subscribers: %w", err)
+		}
+		for _, userID := range subscriberIDs {
+			if userID != msg.SenderID {
+				notificationTypes[userID] = TypeThreadReply
 			}
 		}
 	}
@@ -208,7 +267,7 @@ func (s *Service) determineRecipients(ctx context.Context, channel *ChannelInfo,
 	// Get channel members
 	memberIDs, err := s.channelProvider.GetMemberUserIDs(ctx, channel.ID)
 	if err != nil {
-		memberIDs = []string{}
+		return nil, nil, fmt.Errorf("listing channel members: %w", err)
 	}
 
 	// DM channels: notify all participants
@@ -278,10 +337,11 @@ func (s *Service) determineRecipients(ctx context.Context, channel *ChannelInfo,
 		recipients = append(recipients, userID)
 	}
 
-	return recipients, notificationTypes
+	return recipients, notificationTypes, nil
 }
 
-// shouldNotify checks if a user should receive notifications based on preferences
+// shouldNotify checks if a user should receive notifications based on the
+// channel's preferences or the workspace and global settings it inherits
 func (s *Service) shouldNotify(ctx context.Context, userID, channelID, channelType string, isMention bool) bool {
 	pref, err := s.prefsRepo.GetOrDefault(ctx, userID, channelID, channelType)
 	if err != nil {
@@ -331,6 +391,58 @@ func (s *Service) SetPreferences(ctx context.Context, pref *NotificationPreferen
 	return s.prefsRepo.Upsert(ctx, pref)
 }
 
+// ResetPreferences removes a channel's preferences so it inherits the
+// workspace and global settings
+func (s *Service) ResetPreferences(ctx context.Context, userID, channelID string) error {
+	return s.prefsRepo.Delete(ctx, userID, channelID)
+}
+
+// GetGlobalSettings returns the user's notification settings for all workspaces
+func (s *Service) GetGlobalSettings(ctx context.Context, userID string) (*Settings, error) {
+	return s.prefsRepo.GetGlobalSettingsOrDefault(ctx, userID)
+}
+
+// GetWorkspaceSettings returns the user's notification settings for a workspace
+func (s *Service) GetWorkspaceSettings(ctx context.Context, userID, workspaceID string) (*Settings, error) {
+	return s.prefsRepo.GetWorkspaceSettingsOrDefault(ctx, userID, workspaceID)
+}
+
+// SetSettings updates the user's global or workspace notification settings
+func (s *Service) SetSettings(ctx context.Context, settings *Settings) error {
+	return s.prefsRepo.UpsertSettings(ctx, settings)
+}
+
+// ResetWorkspaceSettings makes a workspace follow the user's global settings again
+func (s *Service) ResetWorkspaceSettings(ctx context.Context, userID, workspaceID string) error {
+	return s.prefsRepo.DeleteWorkspaceSettings(ctx, userID, workspaceID)
+}
+
+// KeywordMentions returns the channel members, other than the sender, who
+// have a notification keyword in content. They are notified as if mentioned.
+func (s *Service) KeywordMentions(ctx context.Context, channelID, senderID, content string) ([]string, error) {
+	keywords, err := s.keywordRepo.ListForChannelMembers(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	delete(keywords, senderID)
+	return MatchKeywords(content, keywords), nil
+}
+
+// ListKeywords returns the user's notification keywords
+func (s *Service) ListKeywords(ctx context.Context, userID string) ([]Keyword, error) {
+	return s.keywordRepo.List(ctx, userID)
+}
+
+// AddKeyword adds a notification keyword for the user
+func (s *Service) AddKeyword(ctx context.Context, keyword *Keyword) error {
+	return s.keywordRepo.Create(ctx, keyword)
+}
+
+// DeleteKeyword removes one of the user's notification keywords
+func (s *Service) DeleteKeyword(ctx context.Context, userID, id string) error {
+	return s.keywordRepo.Delete(ctx, userID, id)
+}
+
 // buildTitle creates a push notification title based on the channel and message context
 func buildTitle(channel *ChannelInfo, msg *MessageInfo) string {
 	sender := "@" + msg.SenderName
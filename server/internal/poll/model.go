@@ -0,0 +1,60 @@
+package poll
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPollNotFound = errors.New("poll not found")
+	ErrPollClosed   = errors.New("poll is closed")
+)
+
+const (
+	MinOptions = 2
+	MaxOptions = 10
+)
+
+// Poll is a question with options that channel members vote on. It belongs to
+// a message of type poll whose content is the question.
+type Poll struct {
+	ID             string     `json:"id"`
+	MessageID      string     `json:"message_id"`
+	ChannelID      string     `json:"channel_id"`
+	CreatedBy      *string    `json:"created_by,omitempty"`
+	Question       string     `json:"question"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	Anonymous      bool       `json:"anonymous"`
+	ClosesAt       *time.Time `json:"closes_at,omitempty"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+	Options        []Option   `json:"options"`
+	VoterCount     int        `json:"voter_count"`
+	// MyVotes holds the IDs of the options the viewing user voted for.
+	MyVotes   []string  `json:"my_votes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Option is a choice in a poll with its results. VoterIDs is only loaded for
+// polls that are not anonymous.
+type Option struct {
+	ID        string   `json:"id"`
+	Text      string   `json:"text"`
+	VoteCount int      `json:"vote_count"`
+	VoterIDs  []string `json:"voter_ids,omitempty"`
+}
+
+// IsClosed reports whether voting has ended, either because the poll was
+// closed or because its close time has passed.
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.ClosesAt != nil && !now.Before(*p.ClosesAt))
+}
+
+// HasOption reports whether optionID is one of the poll's options.
+func (p *Poll) HasOption(optionID string) bool {
+	for _, o := range p.Options {
+		if o.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,299 @@
+package poll
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+const pollColumns = `id, message_id, channel_id, created_by, question, multiple_choice, anonymous, closes_at, closed_at, created_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create inserts a poll and its options for an existing poll message.
+func (r *Repository) Create(ctx context.Context, p *Poll) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "poll.Create")
+	defer func() { endSpan(err) }()
+
+	p.ID = ulid.Make().String()
+	p.CreatedAt = time.Now().UTC()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO polls (id, message_id, channel_id, created_by, question, multiple_choice, anonymous, closes_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, p.ID, p.MessageID, p.ChannelID, p.CreatedBy, p.Question, p.MultipleChoice, p.Anonymous, formatTime(p.ClosesAt), p.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+
+	for i := range p.Options {
+		p.Options[i].ID = ulid.Make().String()
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO poll_options (id, poll_id, position, text) VALUES (?, ?, ?, ?)
+		`, p.Options[i].ID, p.ID, i, p.Options[i].Text); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID returns a poll with its results. MyVotes is filled in for viewerID.
+func (r *Repository) GetByID(ctx context.Context, id, viewerID string) (*Poll, error) {
+	p, err := scanPoll(r.db.QueryRowContext(ctx, `SELECT `+pollColumns+` FROM polls WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadResults(ctx, []*Poll{p}, viewerID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ListForMessages returns the polls of the given messages with their results,
+// keyed by message ID.
+func (r *Repository) ListForMessages(ctx context.Context, messageIDs []string, viewerID string) (map[string]*Poll, error) {
+	if len(messageIDs) == 0 {
+		return map[string]*Poll{}, nil
+	}
+	placeholders, args := inClause(messageIDs)
+	polls, err := r.list(ctx, `SELECT `+pollColumns+` FROM polls WHERE message_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadResults(ctx, polls, viewerID); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*Poll, len(polls))
+	for _, p := range polls {
+		result[p.MessageID] = p
+	}
+	return result, nil
+}
+
+// ListDue returns open polls whose close time has passed.
+func (r *Repository) ListDue(ctx context.Context) ([]*Poll, error) {
+	return r.list(ctx, `
+		SELECT `+pollColumns+` FROM polls
+		WHERE closed_at IS NULL AND closes_at IS NOT NULL AND closes_at <= ?
+		ORDER BY closes_at ASC
+	`, time.Now().UTC().Format(time.RFC3339))
+}
+
+// Vote replaces the user's votes in a poll with optionIDs. An empty
+// optionIDs retracts the user's vote. Option IDs must belong to the poll.
+func (r *Repository) Vote(ctx context.Context, pollID, userID string, optionIDs []string) (err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "poll.Vote")
+	defer func() { endSpan(err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var closedAt sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT closed_at FROM polls WHERE id = ?`, pollID).Scan(&closedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPollNotFound
+		}
+		return err
+	}
+	if closedAt.Valid {
+		return ErrPollClosed
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM poll_votes WHERE poll_id = ? AND user_id = ?`, pollID, userID); err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, optionID := range optionIDs {
+		if _, err = tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO poll_votes (poll_id, option_id, user_id, created_at) VALUES (?, ?, ?, ?)
+		`, pollID, optionID, userID, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Close ends voting on a poll. Returns false if it was already closed.
+func (r *Repository) Close(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE polls SET closed_at = ? WHERE id = ? AND closed_at IS NULL
+	`, time.Now().UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *Repository) list(ctx context.Context, query string, args ...any) ([]*Poll, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var polls []*Poll
+	for rows.Next() {
+		p, err := scanPoll(rows)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, p)
+	}
+	return polls, rows.Err()
+}
+
+// loadResults loads the options of polls with their vote counts, the voters
+// of each option for polls that are not anonymous, and viewerID's votes.
+func (r *Repository) loadResults(ctx context.Context, polls []*Poll, viewerID string) error {
+	if len(polls) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Poll, len(polls))
+	ids := make([]string, len(polls))
+	for i, p := range polls {
+		p.Options = []Option{}
+		byID[p.ID] = p
+		ids[i] = p.ID
+	}
+	placeholders, args := inClause(ids)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT poll_id, id, text FROM poll_options
+		WHERE poll_id IN (`+placeholders+`)
+		ORDER BY poll_id, position
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	optionIndex := make(map[string]int)
+	for rows.Next() {
+		var pollID string
+		var o Option
+		if err := rows.Scan(&pollID, &o.ID, &o.Text); err != nil {
+			return err
+		}
+		p := byID[pollID]
+		optionIndex[o.ID] = len(p.Options)
+		p.Options = append(p.Options, o)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	votes, err := r.db.QueryContext(ctx, `
+		SELECT poll_id, option_id, user_id FROM poll_votes
+		WHERE poll_id IN (`+placeholders+`)
+		ORDER BY created_at, user_id
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer votes.Close()
+
+	voters := make(map[string]map[string]bool)
+	for votes.Next() {
+		var pollID, optionID, userID string
+		if err := votes.Scan(&pollID, &optionID, &userID); err != nil {
+			return err
+		}
+		p := byID[pollID]
+		o := &p.Options[optionIndex[optionID]]
+		o.VoteCount++
+		if !p.Anonymous {
+			o.VoterIDs = append(o.VoterIDs, userID)
+		}
+		if userID == viewerID {
+			p.MyVotes = append(p.MyVotes, optionID)
+		}
+		if voters[pollID] == nil {
+			voters[pollID] = make(map[string]bool)
+		}
+		voters[pollID][userID] = true
+	}
+	if err := votes.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range polls {
+		p.VoterCount = len(voters[p.ID])
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPoll(row scanner) (*Poll, error) {
+	var p Poll
+	var closesAt, closedAt sql.NullString
+	var createdAt string
+	err := row.Scan(&p.ID, &p.MessageID, &p.ChannelID, &p.CreatedBy, &p.Question, &p.MultipleChoice, &p.Anonymous, &closesAt, &closedAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	p.ClosesAt = parseTime(closesAt)
+	p.ClosedAt = parseTime(closedAt)
+	p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	return &p, nil
+}
+
+func parseTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, _ := time.Parse(time.RFC3339, s.String)
+	return &t
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
+
+func inClause(ids []string) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ","), args
+}
@@ -0,0 +1,159 @@
+package poll
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func createTestPoll(t *testing.T, repo *Repository, p *Poll, options ...string) {
+	t.Helper()
+	for _, text := range options {
+		p.Options = append(p.Options, Option{Text: text})
+	}
+	if err := repo.Create(context.Background(), p); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+}
+
+func TestRepository_VoteAndResults(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, alice.ID, "Lunch?")
+
+	p := &Poll{MessageID: msg.ID, ChannelID: ch.ID, CreatedBy: &alice.ID, Question: "Lunch?", MultipleChoice: true}
+	createTestPoll(t, repo, p, "Pizza", "Sushi", "Tacos")
+	pizza, sushi, tacos := p.Options[0].ID, p.Options[1].ID, p.Options[2].ID
+
+	if err := repo.Vote(ctx, p.ID, alice.ID, []string{pizza, sushi}); err != nil {
+		t.Fatalf("Vote() error = %v", err)
+	}
+	if err := repo.Vote(ctx, p.ID, bob.ID, []string{sushi}); err != nil {
+		t.Fatalf("Vote() error = %v", err)
+	}
+	// Voting again replaces the earlier votes
+	if err := repo.Vote(ctx, p.ID, alice.ID, []string{tacos}); err != nil {
+		t.Fatalf("Vote() error = %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, p.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	counts := [3]int{got.Options[0].VoteCount, got.Options[1].VoteCount, got.Options[2].VoteCount}
+	if counts != [3]int{0, 1, 1} {
+		t.Errorf("vote counts = %v, want [0 1 1]", counts)
+	}
+	if got.VoterCount != 2 {
+		t.Errorf("voter_count = %d, want 2", got.VoterCount)
+	}
+	if len(got.MyVotes) != 1 || got.MyVotes[0] != tacos {
+		t.Errorf("my_votes = %v, want [%s]", got.MyVotes, tacos)
+	}
+	if ids := got.Options[1].VoterIDs; len(ids) != 1 || ids[0] != bob.ID {
+		t.Errorf("sushi voters = %v, want [%s]", ids, bob.ID)
+	}
+
+	byMessage, err := repo.ListForMessages(ctx, []string{msg.ID}, bob.ID)
+	if err != nil {
+		t.Fatalf("ListForMessages() error = %v", err)
+	}
+	if bp := byMessage[msg.ID]; bp == nil || len(bp.MyVotes) != 1 || bp.MyVotes[0] != sushi {
+		t.Errorf("poll for bob = %+v, want my_votes [%s]", bp, sushi)
+	}
+
+	closed, err := repo.Close(ctx, p.ID)
+	if err != nil || !closed {
+		t.Fatalf("Close() = %v, %v, want true", closed, err)
+	}
+	if closed, _ := repo.Close(ctx, p.ID); closed {
+		t.Error("closing twice reported true")
+	}
+	if err := repo.Vote(ctx, p.ID, bob.ID, []string{pizza}); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("Vote() on closed poll error = %v, want ErrPollClosed", err)
+	}
+}
+
+func TestRepository_AnonymousHidesVoters(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+	msg := testutil.CreateTestMessage(t, db, ch.ID, alice.ID, "Secret ballot")
+
+	p := &Poll{MessageID: msg.ID, ChannelID: ch.ID, Question: "Secret ballot", Anonymous: true}
+	createTestPoll(t, repo, p, "Yes", "No")
+	if err := repo.Vote(ctx, p.ID, alice.ID, []string{p.Options[0].ID}); err != nil {
+		t.Fatalf("Vote() error = %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, p.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Options[0].VoteCount != 1 || got.Options[0].VoterIDs != nil {
+		t.Errorf("option = %+v, want one vote and no voter IDs", got.Options[0])
+	}
+	if len(got.MyVotes) != 1 {
+		t.Errorf("my_votes = %v, want the viewer's own vote", got.MyVotes)
+	}
+}
+
+type recordingNotifier struct {
+	closed []string
+}
+
+func (n *recordingNotifier) PollClosed(ctx context.Context, pollID string) {
+	n.closed = append(n.closed, pollID)
+}
+
+func TestWorker_ProcessDue(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test WS")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	var due, later *Poll
+	for _, closesAt := range []*time.Time{&past, &future, nil} {
+		msg := testutil.CreateTestMessage(t, db, ch.ID, alice.ID, "Q")
+		p := &Poll{MessageID: msg.ID, ChannelID: ch.ID, Question: "Q", ClosesAt: closesAt}
+		createTestPoll(t, repo, p, "A", "B")
+		switch closesAt {
+		case &past:
+			due = p
+		case &future:
+			later = p
+		}
+	}
+
+	notifier := &recordingNotifier{}
+	if err := NewWorker(repo, notifier).ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue() error = %v", err)
+	}
+	if len(notifier.closed) != 1 || notifier.closed[0] != due.ID {
+		t.Fatalf("closed = %v, want only %s", notifier.closed, due.ID)
+	}
+	if got, _ := repo.GetByID(ctx, due.ID, ""); got.ClosedAt == nil {
+		t.Error("due poll was not closed")
+	}
+	if got, _ := repo.GetByID(ctx, later.ID, ""); got.ClosedAt != nil {
+		t.Error("poll closing later was closed")
+	}
+}
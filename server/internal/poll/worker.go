@@ -0,0 +1,46 @@
+package poll
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Notifier is told about polls the worker closes so it can publish the
+// final results. Implemented by handler.Handler via PollClosed.
+type Notifier interface {
+	PollClosed(ctx context.Context, pollID string)
+}
+
+// Worker closes polls whose close time has passed.
+type Worker struct {
+	repo     *Repository
+	notifier Notifier
+}
+
+// NewWorker creates a new poll worker.
+func NewWorker(repo *Repository, notifier Notifier) *Worker {
+	return &Worker{
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+// ProcessDue closes all due polls.
+func (w *Worker) ProcessDue(ctx context.Context) error {
+	polls, err := w.repo.ListDue(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range polls {
+		closed, err := w.repo.Close(ctx, p.ID)
+		if err != nil {
+			slog.Error("failed to close poll", "component", "poll", "id", p.ID, "error", err)
+			continue
+		}
+		if closed && w.notifier != nil {
+			w.notifier.PollClosed(ctx, p.ID)
+		}
+	}
+	return nil
+}
@@ -278,7 +278,7 @@ func (m *Manager) broadcastPresenceChange(workspaceID, userID string, status ope
 		return
 	}
 
-	m.hub.BroadcastToWorkspace(workspaceID, sse.NewPresenceChangedEvent(openapi.PresenceData{
+	m.hub.BroadcastToWorkspace(context.Background(), workspaceID, sse.NewPresenceChangedEvent(openapi.PresenceData{
 		UserId: userID,
 		Status: status,
 	}))
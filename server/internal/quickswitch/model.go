@@ -0,0 +1,87 @@
+package quickswitch
+
+import (
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+)
+
+const (
+	KindChannel = "channel"
+	KindDM      = "dm"
+	KindMember  = "member"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 50
+)
+
+// Result is a single quick-switcher destination: a channel, a DM or group
+// DM, or a workspace member the user has no DM with yet.
+type Result struct {
+	Kind string `json:"kind"`
+	// Name is the label shown to the user: the channel name, the DM
+	// participants' names, or the member's display name
+	Name        string `json:"name"`
+	ChannelID   string `json:"channel_id,omitempty"`
+	ChannelType string `json:"channel_type,omitempty"`
+	// IsMember is whether the user has joined the channel
+	IsMember       bool                 `json:"is_member"`
+	Participants   []channel.MemberInfo `json:"participants,omitempty"`
+	User           *channel.MemberInfo  `json:"user,omitempty"`
+	LastActivityAt *time.Time           `json:"last_activity_at,omitempty"`
+
+	score int
+}
+
+// Match quality scores. An exact name beats a prefix, which beats the
+// start of a later word, which beats a match anywhere in the name.
+const (
+	scoreExact      = 100
+	scorePrefix     = 75
+	scoreWordPrefix = 50
+	scoreSubstring  = 25
+)
+
+// matchScore rates how well name matches the lowercased query. The empty
+// query matches everything equally so results fall back to recency.
+func matchScore(name, query string) int {
+	if query == "" {
+		return 0
+	}
+	name = strings.ToLower(name)
+	switch {
+	case name == query:
+		return scoreExact
+	case strings.HasPrefix(name, query):
+		return scorePrefix
+	}
+	for i := 1; i < len(name); i++ {
+		if strings.ContainsRune(" -_.", rune(name[i-1])) && strings.HasPrefix(name[i:], query) {
+			return scoreWordPrefix
+		}
+	}
+	if strings.Contains(name, query) {
+		return scoreSubstring
+	}
+	return 0
+}
+
+// activityScore boosts destinations with recent messages, so a busy
+// channel outranks an equally good match that has been quiet for months.
+func activityScore(lastActivity *time.Time, now time.Time) int {
+	if lastActivity == nil {
+		return 0
+	}
+	switch age := now.Sub(*lastActivity); {
+	case age < 24*time.Hour:
+		return 30
+	case age < 7*24*time.Hour:
+		return 20
+	case age < 30*24*time.Hour:
+		return 10
+	}
+	return 0
+}
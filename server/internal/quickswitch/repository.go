@@ -0,0 +1,225 @@
+package quickswitch
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/telemetry"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// lastActivitySQL is the time of the newest message in channel c
+const lastActivitySQL = `(SELECT MAX(m.created_at) FROM messages m WHERE m.channel_id = c.id AND m.deleted_at IS NULL)`
+
+// Search finds the channels, DMs and workspace members whose names contain
+// query, ranked by match quality with a boost for recent activity. Members
+// the user already has a 1:1 DM with are returned as that DM instead.
+func (r *Repository) Search(ctx context.Context, workspaceID, userID, query string, limit int) (_ []Result, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "quickswitch.Search")
+	defer func() { endSpan(err) }()
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	query = strings.ToLower(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(query), "#@")))
+
+	channels, err := r.searchChannels(ctx, workspaceID, userID, query)
+	if err != nil {
+		return nil, err
+	}
+	dms, err := r.searchDMs(ctx, workspaceID, userID, query)
+	if err != nil {
+		return nil, err
+	}
+	members, err := r.searchMembers(ctx, workspaceID, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	dmChannels := make(map[string]bool, len(dms))
+	for _, dm := range dms {
+		dmChannels[dm.ChannelID] = true
+	}
+	results := append(channels, dms...)
+	for _, m := range members {
+		if m.ChannelID == "" || !dmChannels[m.ChannelID] {
+			results = append(results, m)
+		}
+	}
+
+	now := time.Now()
+	for i := range results {
+		results[i].score += activityScore(results[i].LastActivityAt, now)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return strings.ToLower(results[i].Name) < strings.ToLower(results[j].Name)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchChannels matches public channels and private channels the user belongs to
+func (r *Repository) searchChannels(ctx context.Context, workspaceID, userID, query string) ([]Result, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.name, c.type, cm.id IS NOT NULL, `+lastActivitySQL+`
+		FROM channels c
+		LEFT JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
+		WHERE c.workspace_id = ? AND c.archived_at IS NULL
+		  AND (c.type = 'public' OR (c.type = 'private' AND cm.id IS NOT NULL))
+		  AND instr(LOWER(c.name), ?) > 0
+	`, userID, workspaceID, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		res := Result{Kind: KindChannel}
+		var lastActivity sql.NullString
+		if err := rows.Scan(&res.ChannelID, &res.Name, &res.ChannelType, &res.IsMember, &lastActivity); err != nil {
+			return nil, err
+		}
+		res.LastActivityAt = parseTime(lastActivity)
+		res.score = matchScore(res.Name, query)
+		if res.IsMember {
+			// Prefer channels the user has joined over equally good matches
+			res.score += 5
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+// searchDMs matches the user's DMs and group DMs by participant name
+func (r *Repository) searchDMs(ctx context.Context, workspaceID, userID, query string) ([]Result, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.type, `+lastActivitySQL+`,
+		       u.id, u.email, u.display_name, u.avatar_url, u.status = 'deactivated'
+		FROM channels c
+		JOIN channel_memberships cm ON cm.channel_id = c.id AND cm.user_id = ?
+		JOIN channel_memberships p ON p.channel_id = c.id AND p.user_id != cm.user_id
+		JOIN users u ON u.id = p.user_id
+		WHERE c.workspace_id = ? AND c.archived_at IS NULL AND c.type IN ('dm', 'group_dm')
+		  AND EXISTS (
+		      SELECT 1 FROM channel_memberships pm
+		      JOIN users pu ON pu.id = pm.user_id
+		      WHERE pm.channel_id = c.id AND pm.user_id != cm.user_id
+		        AND instr(LOWER(pu.display_name), ?) > 0
+		  )
+		ORDER BY c.id, u.display_name
+	`, userID, workspaceID, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		var channelID, channelType string
+		var lastActivity, avatarURL sql.NullString
+		var p channel.MemberInfo
+		if err := rows.Scan(&channelID, &channelType, &lastActivity, &p.UserID, &p.Email, &p.DisplayName, &avatarURL, &p.IsDeactivated); err != nil {
+			return nil, err
+		}
+		if avatarURL.Valid {
+			p.AvatarURL = &avatarURL.String
+		}
+		if n := len(results); n == 0 || results[n-1].ChannelID != channelID {
+			results = append(results, Result{
+				Kind:           KindDM,
+				ChannelID:      channelID,
+				ChannelType:    channelType,
+				IsMember:       true,
+				LastActivityAt: parseTime(lastActivity),
+			})
+		}
+		res := &results[len(results)-1]
+		res.Participants = append(res.Participants, p)
+		res.score = max(res.score, matchScore(p.DisplayName, query))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		names := make([]string, len(results[i].Participants))
+		for j, p := range results[i].Participants {
+			names[j] = p.DisplayName
+		}
+		results[i].Name = strings.Join(names, ", ")
+	}
+	return results, nil
+}
+
+// searchMembers matches active workspace members other than the user,
+// along with the 1:1 DM channel the two already share, if any
+func (r *Repository) searchMembers(ctx context.Context, workspaceID, userID, query string) ([]Result, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, COALESCE(wm.display_name_override, u.display_name), u.display_name, u.avatar_url,
+		       (SELECT c.id FROM channels c
+		        JOIN channel_memberships a ON a.channel_id = c.id AND a.user_id = ?
+		        JOIN channel_memberships b ON b.channel_id = c.id AND b.user_id = u.id
+		        WHERE c.workspace_id = wm.workspace_id AND c.type = 'dm'
+		        LIMIT 1) AS dm_channel_id
+		FROM workspace_memberships wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = ? AND wm.user_id != ? AND u.status != 'deactivated'
+		  AND (instr(LOWER(COALESCE(wm.display_name_override, u.display_name)), ?) > 0
+		       OR instr(LOWER(u.display_name), ?) > 0)
+	`, userID, workspaceID, userID, query, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		var u channel.MemberInfo
+		var accountName string
+		var avatarURL, dmChannelID sql.NullString
+		if err := rows.Scan(&u.UserID, &u.Email, &u.DisplayName, &accountName, &avatarURL, &dmChannelID); err != nil {
+			return nil, err
+		}
+		if avatarURL.Valid {
+			u.AvatarURL = &avatarURL.String
+		}
+		res := Result{
+			Kind:  KindMember,
+			Name:  u.DisplayName,
+			User:  &u,
+			score: max(matchScore(u.DisplayName, query), matchScore(accountName, query)),
+		}
+		if dmChannelID.Valid {
+			res.ChannelID = dmChannelID.String
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+func parseTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
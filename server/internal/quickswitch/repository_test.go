@@ -0,0 +1,113 @@
+package quickswitch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		name, query string
+		want        int
+	}{
+		{"design", "design", scoreExact},
+		{"Design", "design", scoreExact},
+		{"design-review", "design", scorePrefix},
+		{"team-design", "design", scoreWordPrefix},
+		{"Alice Chen", "chen", scoreWordPrefix},
+		{"redesign", "design", scoreSubstring},
+		{"marketing", "design", 0},
+		{"anything", "", 0},
+	}
+	for _, tt := range tests {
+		if got := matchScore(tt.name, tt.query); got != tt.want {
+			t.Errorf("matchScore(%q, %q) = %d, want %d", tt.name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestActivityScore(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+	if activityScore(nil, now) != 0 {
+		t.Error("expected no boost without activity")
+	}
+	if !(activityScore(ago(time.Hour), now) > activityScore(ago(3*24*time.Hour), now) &&
+		activityScore(ago(3*24*time.Hour), now) > activityScore(ago(14*24*time.Hour), now) &&
+		activityScore(ago(14*24*time.Hour), now) > activityScore(ago(90*24*time.Hour), now)) {
+		t.Error("expected the boost to shrink as activity gets older")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	channels := channel.NewRepository(db)
+
+	me := testutil.CreateTestUser(t, db, "me@example.com", "Me")
+	dana := testutil.CreateTestUser(t, db, "dana@example.com", "Dana Design")
+	desmond := testutil.CreateTestUser(t, db, "desmond@example.com", "Desmond")
+	outsider := testutil.CreateTestUser(t, db, "outsider@example.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, me.ID, "Acme")
+	for _, u := range []*testutil.TestUser{dana, desmond, outsider} {
+		if _, err := workspace.NewRepository(db).AddMember(ctx, u.ID, ws.ID, workspace.RoleMember); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+	}
+
+	design := testutil.CreateTestChannel(t, db, ws.ID, me.ID, "design", channel.TypePublic)
+	testutil.CreateTestChannel(t, db, ws.ID, outsider.ID, "redesign", channel.TypePublic)
+	testutil.CreateTestChannel(t, db, ws.ID, outsider.ID, "design-secret", channel.TypePrivate)
+	testutil.CreateTestMessage(t, db, design.ID, me.ID, "hello")
+
+	dm, err := channels.CreateDM(ctx, ws.ID, []string{me.ID, dana.ID})
+	if err != nil {
+		t.Fatalf("CreateDM: %v", err)
+	}
+
+	results, err := repo.Search(ctx, ws.ID, me.ID, "#des", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	got := make(map[string]Result)
+	for _, r := range results {
+		got[r.Kind+":"+r.Name] = r
+	}
+	if _, ok := got[KindChannel+":design-secret"]; ok {
+		t.Error("expected private channel the user is not in to be hidden")
+	}
+	if _, ok := got[KindMember+":Dana Design"]; ok {
+		t.Error("expected member with an existing DM to be returned as the DM only")
+	}
+	if r, ok := got[KindDM+":Dana Design"]; !ok || r.ChannelID != dm.ID || len(r.Participants) != 1 {
+		t.Errorf("expected DM with Dana, got %+v", r)
+	}
+	if r, ok := got[KindMember+":Desmond"]; !ok || r.ChannelID != "" {
+		t.Errorf("expected Desmond as a member without a DM, got %+v", r)
+	}
+	if _, ok := got[KindChannel+":redesign"]; !ok {
+		t.Error("expected substring channel match")
+	}
+
+	// The active, joined, prefix-matching channel ranks first
+	if len(results) == 0 || results[0].Name != "design" {
+		t.Fatalf("expected #design first, got %+v", results)
+	}
+	if results[len(results)-1].Name != "redesign" {
+		t.Errorf("expected the quiet substring match last, got %q", results[len(results)-1].Name)
+	}
+
+	if limited, err := repo.Search(ctx, ws.ID, me.ID, "des", 2); err != nil || len(limited) != 2 {
+		t.Errorf("expected limit to cap results at 2, got %d (err %v)", len(limited), err)
+	}
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Store keeps token buckets by key. MemoryStore is the in-process
+// implementation; a shared backend such as Redis can implement Store to
+// enforce limits across several server instances.
+type Store interface {
+	// Take removes one token from the bucket for key, which holds up to limit
+	// tokens and refills at limit per window. It reports whether a token was
+	// available.
+	Take(key string, limit int, window time.Duration) (Result, bool)
+
+	// Cleanup drops state that no longer affects any decision.
+	Cleanup()
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	window  time.Duration
+}
+
+// MemoryStore is a Store backed by an in-memory map.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	clock   Clock
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		clock:   realClock{},
+	}
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(key string, limit int, window time.Duration) (Result, bool) {
+	now := s.clock.Now()
+	rate := float64(limit) / window.Seconds() // tokens per second
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), updated: now, window: window}
+		s.buckets[key] = b
+	} else {
+		elapsed := now.Sub(b.updated).Seconds()
+		b.tokens = math.Min(float64(limit), b.tokens+elapsed*rate)
+		b.updated = now
+		b.window = window
+	}
+
+	if b.tokens < 1 {
+		retryIn := secondsToDuration((1 - b.tokens) / rate)
+		return Result{
+			Limit:   limit,
+			ResetAt: now.Add(secondsToDuration((float64(limit) - b.tokens) / rate)),
+			RetryIn: retryIn,
+		}, false
+	}
+
+	b.tokens--
+	return Result{
+		Limit:     limit,
+		Remaining: int(b.tokens),
+		ResetAt:   now.Add(secondsToDuration((float64(limit) - b.tokens) / rate)),
+	}, true
+}
+
+// Cleanup removes buckets that have been idle long enough to be full again.
+func (s *MemoryStore) Cleanup() {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.window {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
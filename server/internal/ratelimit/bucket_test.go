@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestMemoryStore(clock Clock) *MemoryStore {
+	s := NewMemoryStore()
+	s.clock = clock
+	return s
+}
+
+func TestMemoryStore_BurstThenRefill(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	s := newTestMemoryStore(clock)
+
+	// The full burst is available up front
+	for i := range 6 {
+		result, allowed := s.Take("user:1", 6, time.Minute)
+		if !allowed {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+		if result.Remaining != 5-i {
+			t.Fatalf("request %d: expected remaining %d, got %d", i+1, 5-i, result.Remaining)
+		}
+	}
+
+	result, allowed := s.Take("user:1", 6, time.Minute)
+	if allowed {
+		t.Fatal("request beyond the burst should be blocked")
+	}
+	// 6 per minute refills one token every 10s
+	if result.RetryIn != 10*time.Second {
+		t.Fatalf("expected RetryIn 10s, got %v", result.RetryIn)
+	}
+
+	clock.Advance(10 * time.Second)
+	if _, allowed := s.Take("user:1", 6, time.Minute); !allowed {
+		t.Fatal("a refilled token should be usable")
+	}
+	if _, allowed := s.Take("user:1", 6, time.Minute); allowed {
+		t.Fatal("only one token should have been refilled")
+	}
+}
+
+func TestMemoryStore_KeysAreIndependent(t *testing.T) {
+	s := newTestMemoryStore(newFakeClock(time.Now()))
+
+	s.Take("user:1", 1, time.Minute)
+	if _, allowed := s.Take("user:1", 1, time.Minute); allowed {
+		t.Fatal("user:1 should be limited")
+	}
+	if _, allowed := s.Take("user:2", 1, time.Minute); !allowed {
+		t.Fatal("user:2 should have its own bucket")
+	}
+}
+
+func TestMemoryStore_Cleanup(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	s := newTestMemoryStore(clock)
+
+	s.Take("idle", 5, time.Minute)
+	clock.Advance(30 * time.Second)
+	s.Take("active", 5, time.Minute)
+	clock.Advance(30 * time.Second)
+
+	s.Cleanup()
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("expected idle bucket to be removed once full again")
+	}
+	if _, ok := s.buckets["active"]; !ok {
+		t.Error("expected recently used bucket to be kept")
+	}
+}
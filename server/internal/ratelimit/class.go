@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Class is a token-bucket limit shared by a group of routes: each caller
+// may burst up to Limit requests, refilled at Limit per Window.
+type Class struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// Classifier picks the class for a request. ok is false for requests that
+// should not be limited.
+type Classifier func(r *http.Request) (class Class, ok bool)
+
+// ClassLimiter applies per-class token buckets kept in a Store.
+type ClassLimiter struct {
+	store    Store
+	classify atomic.Pointer[Classifier]
+}
+
+// NewClassLimiter creates a ClassLimiter.
+func NewClassLimiter(store Store, classify Classifier) *ClassLimiter {
+	l := &ClassLimiter{store: store}
+	l.SetClassifier(classify)
+	return l
+}
+
+// SetClassifier replaces the classifier, e.g. to apply new class limits.
+// Existing buckets are kept and refill at the new rate.
+func (l *ClassLimiter) SetClassifier(classify Classifier) {
+	l.classify.Store(&classify)
+}
+
+func (l *ClassLimiter) class(r *http.Request) (Class, bool) {
+	return (*l.classify.Load())(r)
+}
+
+// Cleanup removes idle buckets from the store. Call periodically.
+func (l *ClassLimiter) Cleanup() {
+	l.store.Cleanup()
+}
@@ -35,21 +35,37 @@ func Middleware(limiter *Limiter) func(http.Handler) http.Handler {
 				return
 			}
 
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
-			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+			if !writeResult(w, result, allowed) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClassMiddleware returns chi middleware that applies the limiter's route
+// classes. Requests are counted per user when userID reports an
+// authenticated user, and per client IP otherwise, so it must run after the
+// token middleware. If limiter is nil, requests pass through untouched.
+func ClassMiddleware(limiter *ClassLimiter, userID func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			class, ok := limiter.class(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-			if !allowed {
-				retryAfter := int(math.Ceil(result.RetryIn.Seconds()))
-				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusTooManyRequests)
-				_ = json.NewEncoder(w).Encode(errorResponse{
-					Error: errorDetail{
-						Code:    "RATE_LIMITED",
-						Message: fmt.Sprintf("Too many requests. Try again in %d seconds.", retryAfter),
-					},
-				})
+			key := class.Name + ":ip:" + stripPort(r.RemoteAddr)
+			if id := userID(r); id != "" {
+				key = class.Name + ":user:" + id
+			}
+			result, allowed := limiter.store.Take(key, class.Limit, class.Window)
+			if !writeResult(w, result, allowed) {
 				return
 			}
 
@@ -58,6 +74,30 @@ func Middleware(limiter *Limiter) func(http.Handler) http.Handler {
 	}
 }
 
+// writeResult sets the rate limit headers and, if the request was not
+// allowed, writes the 429 response. It returns whether to continue.
+func writeResult(w http.ResponseWriter, result Result, allowed bool) bool {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+	if allowed {
+		return true
+	}
+
+	retryAfter := int(math.Ceil(result.RetryIn.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(errorResponse{
+		Error: errorDetail{
+			Code:    "RATE_LIMITED",
+			Message: fmt.Sprintf("Too many requests. Try again in %d seconds.", retryAfter),
+		},
+	})
+	return false
+}
+
 // stripPort removes the port from an address (handles both IPv4 and IPv6).
 func stripPort(addr string) string {
 	host, _, err := net.SplitHostPort(addr)
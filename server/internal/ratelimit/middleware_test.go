@@ -194,3 +194,59 @@ func TestStripPort(t *testing.T) {
 		}
 	}
 }
+
+func TestClassMiddleware_PerUserAndPerIP(t *testing.T) {
+	class := Class{Name: "api", Limit: 1, Window: time.Minute}
+	l := NewClassLimiter(NewMemoryStore(), func(r *http.Request) (Class, bool) {
+		return class, r.URL.Path != "/health"
+	})
+	handler := ClassMiddleware(l, func(r *http.Request) string {
+		return r.Header.Get("X-Test-User")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(path, ip, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("X-Test-User", user)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("/api/x", "1.2.3.4", "alice"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	rec := do("/api/x", "5.6.7.8", "alice")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected alice to be limited from any IP, got %d", rec.Code)
+	}
+	if rec := do("/api/x", "1.2.3.4", "bob"); rec.Code != http.StatusOK {
+		t.Fatalf("expected bob to get a separate bucket on a shared IP, got %d", rec.Code)
+	}
+
+	// Signed-out requests fall back to the client IP
+	if rec := do("/api/x", "9.9.9.9", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec := do("/api/x", "9.9.9.9", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the IP to be limited, got %d", rec.Code)
+	}
+
+	// Unclassified requests pass through without headers
+	if rec := do("/health", "9.9.9.9", ""); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+		t.Fatalf("expected unclassified request to pass untouched, got %d", rec.Code)
+	}
+}
+
+func TestClassMiddleware_NilLimiter(t *testing.T) {
+	called := false
+	handler := ClassMiddleware(nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/x", nil))
+	if !called {
+		t.Fatal("handler should have been called with nil limiter")
+	}
+}
@@ -46,32 +46,45 @@ type Limiter struct {
 
 // NewLimiter creates a Limiter with the given rules.
 func NewLimiter(rules []Rule) *Limiter {
-	ruleMap := make(map[string]Rule, len(rules))
-	for _, r := range rules {
-		ruleMap[r.Method+":"+r.Path] = r
-	}
 	return &Limiter{
-		rules:   ruleMap,
+		rules:   ruleMap(rules),
 		entries: make(map[string]*entry),
 		clock:   realClock{},
 	}
 }
 
+// SetRules replaces the limiter's rules. Counts in the current window are
+// kept, so a lowered limit applies straight away.
+func (l *Limiter) SetRules(rules []Rule) {
+	m := ruleMap(rules)
+	l.mu.Lock()
+	l.rules = m
+	l.mu.Unlock()
+}
+
+func ruleMap(rules []Rule) map[string]Rule {
+	m := make(map[string]Rule, len(rules))
+	for _, r := range rules {
+		m[r.Method+":"+r.Path] = r
+	}
+	return m
+}
+
 // Allow checks whether a request from ip to method+path is allowed.
 // If no rule matches the method+path, it returns (Result{}, true).
 func (l *Limiter) Allow(ip, method, path string) (Result, bool) {
 	ruleKey := method + ":" + path
-	rule, ok := l.rules[ruleKey]
-	if !ok {
-		return Result{}, true
-	}
-
 	now := l.clock.Now()
 	key := ip + ":" + ruleKey
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	rule, ok := l.rules[ruleKey]
+	if !ok {
+		return Result{}, true
+	}
+
 	e, exists := l.entries[key]
 	if !exists || now.Sub(e.windowAt) >= rule.Window {
 		// New window
@@ -167,6 +167,29 @@ func TestAllow_MultipleRules(t *testing.T) {
 	}
 }
 
+func TestSetRules(t *testing.T) {
+	l := NewLimiter([]Rule{
+		{Method: "POST", Path: "/api/auth/login", Limit: 5, Window: time.Minute},
+	})
+	for range 2 {
+		l.Allow("1.2.3.4", "POST", "/api/auth/login")
+	}
+
+	// Lowering the limit applies to the window already in progress
+	l.SetRules([]Rule{
+		{Method: "POST", Path: "/api/auth/login", Limit: 2, Window: time.Minute},
+	})
+	result, allowed := l.Allow("1.2.3.4", "POST", "/api/auth/login")
+	if allowed || result.Limit != 2 {
+		t.Fatalf("expected the lowered limit to block, got allowed=%v limit=%d", allowed, result.Limit)
+	}
+
+	l.SetRules(nil)
+	if _, allowed := l.Allow("1.2.3.4", "POST", "/api/auth/login"); !allowed {
+		t.Fatal("expected requests to pass once the rule is removed")
+	}
+}
+
 func TestAllow_IPv6(t *testing.T) {
 	l := NewLimiter([]Rule{
 		{Method: "POST", Path: "/api/auth/login", Limit: 1, Window: time.Minute},
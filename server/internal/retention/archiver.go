@@ -0,0 +1,58 @@
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Archiver moves dormant threads out of the hot messages table into cold
+// storage on a schedule. Archived threads are read-only: they no longer
+// count towards unreads or appear in channel history, but are still found
+// by search and listed by the archive endpoint.
+type Archiver struct {
+	repo *Repository
+	days int
+}
+
+// NewArchiver creates an archiver for threads with no activity for days.
+func NewArchiver(repo *Repository, days int) *Archiver {
+	return &Archiver{
+		repo: repo,
+		days: days,
+	}
+}
+
+// Archive moves every dormant thread into cold storage a batch at a time.
+// Threads with a pinned message, a poll or a scheduled reply are kept hot,
+// since they can still change.
+func (a *Archiver) Archive(ctx context.Context) error {
+	cutoff := time.Now().UTC().AddDate(0, 0, -a.days)
+	var archived int64
+	defer func() {
+		if archived > 0 {
+			slog.Info("archived dormant messages",
+				"component", "retention",
+				"cold_storage_days", a.days,
+				"messages", archived,
+			)
+		}
+	}()
+
+	for {
+		threadIDs, err := a.repo.listDormantThreads(ctx, cutoff, batchSize)
+		if err != nil || len(threadIDs) == 0 {
+			return err
+		}
+
+		n, err := a.repo.archiveThreads(ctx, threadIDs, time.Now())
+		if err != nil {
+			return err
+		}
+		archived += n
+
+		if len(threadIDs) < batchSize {
+			return nil
+		}
+	}
+}
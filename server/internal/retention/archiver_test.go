@@ -0,0 +1,150 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/file"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/oklog/ulid/v2"
+)
+
+func unreadCount(t *testing.T, db *sql.DB, userID, channelID string) int {
+	t.Helper()
+	var n int
+	err := db.QueryRow(`SELECT unread_count FROM channel_memberships WHERE user_id = ? AND channel_id = ?`, userID, channelID).Scan(&n)
+	if err != nil {
+		t.Fatalf("reading unread count: %v", err)
+	}
+	return n
+}
+
+func TestArchive_MovesDormantThreads(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	messages := message.NewRepository(db)
+	files := file.NewRepository(db)
+	day := 24 * time.Hour
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	reader := testutil.CreateTestUser(t, db, "reader@example.com", "Reader")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Cold Co")
+	general := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "general", "public")
+	if _, err := channel.NewRepository(db).AddMember(ctx, reader.ID, general.ID, nil); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	// A thread quiet for 100 days, with a reaction and an attachment, is
+	// archived as a whole
+	dormant := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "ancient migration plan")
+	reply := &message.Message{ChannelID: general.ID, UserID: &owner.ID, Content: "old reply", ThreadParentID: &dormant.ID}
+	if err := messages.Create(ctx, reply); err != nil {
+		t.Fatalf("creating reply: %v", err)
+	}
+	if _, err := messages.AddReaction(ctx, dormant.ID, reader.ID, "wave"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	hundredDays := 100 * day
+	backdate(t, db, dormant.ID, 120*day, &hundredDays)
+	backdate(t, db, reply.ID, hundredDays, nil)
+
+	attachmentID := ulid.Make().String()
+	_, err := db.Exec(`
+		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at)
+		VALUES (?, ?, ?, ?, 'plan.txt', 'text/plain', 3, ?, ?)
+	`, attachmentID, reply.ID, general.ID, owner.ID, "files/"+attachmentID, time.Now().UTC().Add(-hundredDays).Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("inserting attachment: %v", err)
+	}
+
+	// Pinned messages may still change, so they stay hot however old
+	pinned := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "pinned decision")
+	if err := messages.PinMessage(ctx, pinned.ID, owner.ID); err != nil {
+		t.Fatalf("PinMessage: %v", err)
+	}
+	backdate(t, db, pinned.ID, hundredDays, nil)
+	recent := testutil.CreateTestMessage(t, db, general.ID, owner.ID, "fresh news")
+
+	if got := unreadCount(t, db, reader.ID, general.ID); got != 3 {
+		t.Fatalf("expected 3 unread before archiving, got %d", got)
+	}
+
+	if err := NewArchiver(repo, 90).Archive(ctx); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	if messageExists(t, db, dormant.ID) || messageExists(t, db, reply.ID) {
+		t.Error("expected the dormant thread to leave the messages table")
+	}
+	if !messageExists(t, db, pinned.ID) || !messageExists(t, db, recent.ID) {
+		t.Error("expected pinned and recent messages to stay")
+	}
+	if got := unreadCount(t, db, reader.ID, general.ID); got != 2 {
+		t.Errorf("expected archived messages to leave unread counts, got %d unread", got)
+	}
+
+	// Jump to the date the thread was started
+	at := time.Now().Add(-121 * day)
+	list, err := messages.ListArchived(ctx, general.ID, message.ArchiveListOptions{At: &at}, nil)
+	if err != nil {
+		t.Fatalf("ListArchived: %v", err)
+	}
+	if len(list.Messages) != 1 || list.Messages[0].ID != dormant.ID {
+		t.Fatalf("expected the archived parent, got %+v", list.Messages)
+	}
+	if got := list.Messages[0]; got.ReplyCount != 1 || len(got.Reactions) != 1 {
+		t.Errorf("expected the reply count and reaction to be archived, got %d replies, %d reactions", got.ReplyCount, len(got.Reactions))
+	}
+	replies, err := messages.ListArchived(ctx, general.ID, message.ArchiveListOptions{ThreadID: dormant.ID}, nil)
+	if err != nil {
+		t.Fatalf("ListArchived thread: %v", err)
+	}
+	if len(replies.Messages) != 1 || replies.Messages[0].ID != reply.ID {
+		t.Errorf("expected the archived reply, got %+v", replies.Messages)
+	}
+
+	results, err := messages.Search(ctx, ws.ID, reader.ID, message.SearchOptions{Query: "migration"}, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results.Messages) != 1 || results.Messages[0].ID != dormant.ID || !results.Messages[0].Archived {
+		t.Errorf("expected search to find the archived message, got %+v", results.Messages)
+	}
+
+	attachments, err := files.ListForMessages(ctx, []string{reply.ID})
+	if err != nil || len(attachments[reply.ID]) != 1 {
+		t.Errorf("expected the attachment to follow the archived reply, got %v (err %v)", attachments, err)
+	}
+	unlinked, err := files.ListUnlinkedBefore(ctx, time.Now(), 10)
+	if err != nil || len(unlinked) != 0 {
+		t.Errorf("expected archived attachments not to be collected, got %d (err %v)", len(unlinked), err)
+	}
+
+	// Retention purges archived threads like any other
+	cutoff := time.Now().UTC().AddDate(0, 0, -30)
+	preview, err := repo.Preview(ctx, general.ID, cutoff)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if preview.Messages != 3 || preview.Attachments != 1 {
+		t.Fatalf("expected preview of 3 messages and 1 attachment, got %+v", preview)
+	}
+	if _, err := db.Exec(`UPDATE workspaces SET settings = '{"message_retention_days":30}' WHERE id = ?`, ws.ID); err != nil {
+		t.Fatalf("setting workspace retention: %v", err)
+	}
+	if err := NewPurger(repo, nil, nil).Purge(ctx); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages_archive`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("expected the archived thread to be purged, got %d (err %v)", n, err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM attachments WHERE id = ?`, attachmentID).Scan(&n); err != nil || n != 0 {
+		t.Errorf("expected the archived attachment to be purged, got %d (err %v)", n, err)
+	}
+}
@@ -0,0 +1,127 @@
+// Package retention enforces message retention policies. Workspace admins set
+// a default retention for the workspace and may override it per channel; the
+// purger permanently deletes threads whose latest activity is older than the
+// channel's retention period. Separately, when cold storage is enabled, the
+// archiver moves threads that have been dormant for a while out of the hot
+// messages table; the purger deletes expired threads from both.
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/enzyme/server/internal/sse"
+	"github.com/enzyme/server/internal/storage"
+)
+
+// batchSize bounds how many threads are deleted per transaction.
+const batchSize = 500
+
+// Purger deletes expired messages on a schedule.
+type Purger struct {
+	repo  *Repository
+	store storage.Storage
+	hub   *sse.Hub
+}
+
+// NewPurger creates a purger. store may be nil when uploads are disabled.
+func NewPurger(repo *Repository, store storage.Storage, hub *sse.Hub) *Purger {
+	return &Purger{
+		repo:  repo,
+		store: store,
+		hub:   hub,
+	}
+}
+
+// Purge deletes expired messages from every channel with a retention policy
+// and notifies channel members of what was removed. A failure in one channel
+// does not stop the rest; the first error is returned.
+func (p *Purger) Purge(ctx context.Context) error {
+	policies, err := p.repo.ListPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var firstErr error
+	for _, policy := range policies {
+		cutoff := policy.Cutoff(now)
+		n, err := p.purgeChannel(ctx, policy.ChannelID, cutoff)
+		if err != nil {
+			slog.Error("failed to purge channel", "component", "retention", "channel_id", policy.ChannelID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if n == 0 {
+			continue
+		}
+
+		slog.Info("purged expired messages",
+			"component", "retention",
+			"channel_id", policy.ChannelID,
+			"retention_days", policy.Days,
+			"messages", n,
+		)
+		if p.hub != nil {
+			p.hub.BroadcastToChannel(ctx, policy.WorkspaceID, policy.ChannelID, sse.NewChannelPurgedEvent(openapi.ChannelPurgedData{
+				ChannelId: policy.ChannelID,
+				Before:    cutoff,
+				Count:     int(n),
+			}))
+		}
+	}
+	return firstErr
+}
+
+// purgeChannel deletes a channel's expired threads, archived ones included,
+// and returns how many messages it removed.
+func (p *Purger) purgeChannel(ctx context.Context, channelID string, cutoff time.Time) (int64, error) {
+	var deleted int64
+	for _, t := range []messageTable{hotMessages, archivedMessages} {
+		n, err := p.purgeTable(ctx, t, channelID, cutoff)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// purgeTable deletes a channel's expired threads in t a batch at a time and
+// returns how many messages it removed. Attachment files are deleted before
+// their rows; if a file cannot be deleted the batch is left for the next run
+// rather than leaking the file.
+func (p *Purger) purgeTable(ctx context.Context, t messageTable, channelID string, cutoff time.Time) (int64, error) {
+	var deleted int64
+	for {
+		threadIDs, err := p.repo.listExpiredThreads(ctx, t, channelID, cutoff, batchSize)
+		if err != nil || len(threadIDs) == 0 {
+			return deleted, err
+		}
+
+		if p.store != nil {
+			attachments, err := p.repo.listAttachments(ctx, t, threadIDs)
+			if err != nil {
+				return deleted, err
+			}
+			for _, a := range attachments {
+				if err := p.store.Delete(ctx, a.StoragePath); err != nil {
+					return deleted, err
+				}
+			}
+		}
+
+		n, err := p.repo.deleteThreads(ctx, t, threadIDs)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+
+		if len(threadIDs) < batchSize {
+			return deleted, nil
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/message"
+	"github.com/enzyme/server/internal/storage"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/oklog/ulid/v2"
+)
+
+// backdate moves a message's creation and latest reply times into the past.
+func backdate(t *testing.T, db *sql.DB, messageID string, age time.Duration, lastReplyAge *time.Duration) {
+	t.Helper()
+	createdAt := time.Now().UTC().Add(-age).Format(time.RFC3339)
+	var lastReplyAt any
+	if lastReplyAge != nil {
+		lastReplyAt = time.Now().UTC().Add(-*lastReplyAge).Format(time.RFC3339)
+	}
+	if _, err := db.Exec(`UPDATE messages SET created_at = ?, last_reply_at = ? WHERE id = ?`, createdAt, lastReplyAt, messageID); err != nil {
+		t.Fatalf("backdating message: %v", err)
+	}
+}
+
+func messageExists(t *testing.T, db *sql.DB, id string) bool {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages WHERE id = ?`, id).Scan(&n); err != nil {
+		t.Fatalf("counting messages: %v", err)
+	}
+	return n > 0
+}
+
+func TestPurge_DeletesExpiredThreads(t *testing.T) {
+	db := testutil.TestDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+	store := storage.NewLocal(t.TempDir())
+	messages := message.NewRepository(db)
+	day := 24 * time.Hour
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Retention Co")
+	if _, err := db.Exec(`UPDATE workspaces SET settings = '{"message_retention_days":30}' WHERE id = ?`, ws.ID); err != nil {
+		t.Fatalf("setting workspace retention: %v", err)
+	}
+	inherits := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "inherits", "public")
+	keepsForever := testutil.CreateTestChannel(t, db, ws.ID, owner.ID, "forever", "public")
+	forever := 0
+	if err := channel.NewRepository(db).SetMessageRetention(ctx, keepsForever.ID, &forever); err != nil {
+		t.Fatalf("SetMessageRetention: %v", err)
+	}
+
+	// An old thread with an old reply and an attachment is purged as a whole
+	expired := testutil.CreateTestMessage(t, db, inherits.ID, owner.ID, "ancient history")
+	reply := &message.Message{ChannelID: inherits.ID, UserID: &owner.ID, Content: "old reply", ThreadParentID: &expired.ID}
+	if err := messages.Create(ctx, reply); err != nil {
+		t.Fatalf("creating reply: %v", err)
+	}
+	if _, err := messages.AddReaction(ctx, expired.ID, owner.ID, "wave"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	fortyDays := 40 * day
+	backdate(t, db, expired.ID, 60*day, &fortyDays)
+	backdate(t, db, reply.ID, fortyDays, nil)
+
+	attachmentID := ulid.Make().String()
+	key := "files/" + attachmentID
+	if err := store.Put(ctx, key, bytes.NewReader([]byte("old")), 3, "text/plain"); err != nil {
+		t.Fatalf("storing attachment: %v", err)
+	}
+	_, err := db.Exec(`
+		INSERT INTO attachments (id, message_id, channel_id, user_id, filename, content_type, size_bytes, storage_path, created_at)
+		VALUES (?, ?, ?, ?, 'old.txt', 'text/plain', 3, ?, ?)
+	`, attachmentID, reply.ID, inherits.ID, owner.ID, key, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("inserting attachment: %v", err)
+	}
+
+	// An old thread with a recent reply is still active and kept
+	active := testutil.CreateTestMessage(t, db, inherits.ID, owner.ID, "still discussed")
+	oneDay := day
+	backdate(t, db, active.ID, 60*day, &oneDay)
+
+	recent := testutil.CreateTestMessage(t, db, inherits.ID, owner.ID, "recent")
+	kept := testutil.CreateTestMessage(t, db, keepsForever.ID, owner.ID, "kept forever")
+	backdate(t, db, kept.ID, 60*day, nil)
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -30)
+	preview, err := repo.Preview(ctx, inherits.ID, cutoff)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if preview.Messages != 2 || preview.Attachments != 1 {
+		t.Fatalf("expected preview of 2 messages and 1 attachment, got %+v", preview)
+	}
+
+	if err := NewPurger(repo, store, nil).Purge(ctx); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	if messageExists(t, db, expired.ID) || messageExists(t, db, reply.ID) {
+		t.Error("expected expired thread and its reply to be deleted")
+	}
+	for _, id := range []string{active.ID, recent.ID, kept.ID} {
+		if !messageExists(t, db, id) {
+			t.Errorf("expected message %s to be kept", id)
+		}
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM attachments WHERE id = ?`, attachmentID).Scan(&n); err != nil || n != 0 {
+		t.Errorf("expected attachment row to be deleted, got %d (err %v)", n, err)
+	}
+	if _, err := store.Get(ctx, key); err == nil {
+		t.Error("expected attachment file to be deleted")
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM reactions WHERE message_id = ?`, expired.ID).Scan(&n); err != nil || n != 0 {
+		t.Errorf("expected reactions to be deleted, got %d (err %v)", n, err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM messages_fts WHERE messages_fts MATCH 'ancient'`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("expected search index entry to be deleted, got %d (err %v)", n, err)
+	}
+}
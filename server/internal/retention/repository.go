@@ -0,0 +1,350 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/enzyme/server/internal/workspace"
+)
+
+// Policy is the effective message retention of a channel.
+type Policy struct {
+	ChannelID   string
+	WorkspaceID string
+	Days        int
+}
+
+// Cutoff returns the time before which a thread's latest activity makes it
+// expired.
+func (p Policy) Cutoff(now time.Time) time.Time {
+	return now.UTC().AddDate(0, 0, -p.Days)
+}
+
+// Preview counts what a purge would delete.
+type Preview struct {
+	Messages    int64
+	Attachments int64
+}
+
+type attachment struct {
+	ID          string
+	StoragePath string
+}
+
+// messageTable is a table that threads are purged from, with the column of
+// attachments that links to its messages.
+type messageTable struct {
+	name             string
+	attachmentColumn string
+}
+
+// Threads are purged from the hot messages table and from cold storage,
+// where the archiver moves dormant threads.
+var (
+	hotMessages      = messageTable{"messages", "message_id"}
+	archivedMessages = messageTable{"messages_archive", "archived_message_id"}
+)
+
+// expiredThreads selects the top-level messages of a channel in t whose
+// thread has had no activity since the cutoff. Replies are only ever purged
+// along with their parent so a thread is never left partially deleted.
+func expiredThreads(t messageTable) string {
+	return `
+	SELECT id FROM ` + t.name + `
+	WHERE channel_id = ? AND thread_parent_id IS NULL
+	  AND COALESCE(last_reply_at, created_at) < ?`
+}
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// ListPolicies returns the effective retention of every channel that has
+// one: the channel's own setting if present, otherwise its workspace default.
+func (r *Repository) ListPolicies(ctx context.Context) (_ []Policy, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.ListPolicies")
+	defer func() { endSpan(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.workspace_id, c.message_retention_days, w.settings
+		FROM channels c
+		JOIN workspaces w ON w.id = c.workspace_id
+		ORDER BY c.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []Policy
+	for rows.Next() {
+		var p Policy
+		var override sql.NullInt64
+		var settings sql.NullString
+		if err := rows.Scan(&p.ChannelID, &p.WorkspaceID, &override, &settings); err != nil {
+			return nil, err
+		}
+		if override.Valid {
+			p.Days = int(override.Int64)
+		} else {
+			p.Days = workspace.ParseSettings(settings.String).MessageRetentionDays
+		}
+		if p.Days > 0 {
+			policies = append(policies, p)
+		}
+	}
+	return policies, rows.Err()
+}
+
+// Preview counts the messages, replies included, and attachments that a
+// purge of the channel with the given cutoff would delete, archived ones
+// included.
+func (r *Repository) Preview(ctx context.Context, channelID string, cutoff time.Time) (_ *Preview, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.Preview")
+	defer func() { endSpan(err) }()
+
+	var p Preview
+	for _, t := range []messageTable{hotMessages, archivedMessages} {
+		var messages, attachments int64
+		err = r.db.QueryRowContext(ctx, `
+			WITH expired AS (`+expiredThreads(t)+`),
+			purged AS (
+				SELECT id FROM `+t.name+`
+				WHERE id IN (SELECT id FROM expired) OR thread_parent_id IN (SELECT id FROM expired)
+			)
+			SELECT
+				(SELECT COUNT(*) FROM purged),
+				(SELECT COUNT(*) FROM attachments WHERE `+t.attachmentColumn+` IN (SELECT id FROM purged))
+		`, channelID, cutoff.UTC().Format(time.RFC3339)).Scan(&messages, &attachments)
+		if err != nil {
+			return nil, err
+		}
+		p.Messages += messages
+		p.Attachments += attachments
+	}
+	return &p, nil
+}
+
+// listExpiredThreads returns up to limit expired top-level message IDs in t.
+func (r *Repository) listExpiredThreads(ctx context.Context, t messageTable, channelID string, cutoff time.Time, limit int) (_ []string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.listExpiredThreads")
+	defer func() { endSpan(err) }()
+
+	rows, err := r.db.QueryContext(ctx, expiredThreads(t)+`
+		ORDER BY id
+		LIMIT ?
+	`, channelID, cutoff.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// listAttachments returns the attachments of the given threads in t,
+// replies included.
+func (r *Repository) listAttachments(ctx context.Context, t messageTable, threadIDs []string) (_ []attachment, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.listAttachments")
+	defer func() { endSpan(err) }()
+
+	in, args := inClause(threadIDs)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.storage_path
+		FROM attachments a
+		JOIN `+t.name+` m ON m.id = a.`+t.attachmentColumn+`
+		WHERE m.id IN `+in+` OR m.thread_parent_id IN `+in+`
+	`, append(args, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []attachment
+	for rows.Next() {
+		var a attachment
+		if err := rows.Scan(&a.ID, &a.StoragePath); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// deleteThreads hard-deletes the given threads in t and their attachment
+// rows in one transaction and returns the number of messages removed.
+// Reactions, receipts, link previews and thread subscriptions go with them
+// through ON DELETE CASCADE, and the FTS delete trigger removes their search
+// index entries.
+func (r *Repository) deleteThreads(ctx context.Context, t messageTable, threadIDs []string) (_ int64, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.deleteThreads")
+	defer func() { endSpan(err) }()
+
+	in, args := inClause(threadIDs)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Attachments are only unlinked by a message delete, so remove them
+	// explicitly; their files were deleted from storage by the caller.
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM attachments WHERE `+t.attachmentColumn+` IN (
+			SELECT id FROM `+t.name+` WHERE id IN `+in+` OR thread_parent_id IN `+in+`
+		)
+	`, append(args, args...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	replies, err := tx.ExecContext(ctx, `DELETE FROM `+t.name+` WHERE thread_parent_id IN `+in, args...)
+	if err != nil {
+		return 0, err
+	}
+	parents, err := tx.ExecContext(ctx, `DELETE FROM `+t.name+` WHERE id IN `+in, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	nReplies, _ := replies.RowsAffected()
+	nParents, _ := parents.RowsAffected()
+	return nReplies + nParents, nil
+}
+
+// listDormantThreads returns up to limit top-level message IDs, across all
+// channels, whose thread has had no activity since the cutoff and can be
+// archived.
+func (r *Repository) listDormantThreads(ctx context.Context, cutoff time.Time, limit int) (_ []string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.listDormantThreads")
+	defer func() { endSpan(err) }()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id FROM messages m
+		WHERE m.thread_parent_id IS NULL
+		  AND COALESCE(m.last_reply_at, m.created_at) < ?
+		  AND m.pinned_at IS NULL AND m.type != 'poll'
+		  AND NOT EXISTS (
+			SELECT 1 FROM messages r
+			WHERE r.thread_parent_id = m.id AND (r.pinned_at IS NOT NULL OR r.type = 'poll')
+		  )
+		  AND NOT EXISTS (SELECT 1 FROM scheduled_messages s WHERE s.thread_parent_id = m.id)
+		ORDER BY m.id
+		LIMIT ?
+	`, cutoff.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// archiveThreads moves the given threads, replies included, with their
+// reactions into cold storage in one transaction and returns the number of
+// messages moved. Attachments are relinked to the archived messages.
+// Receipts, link previews, activities and thread subscriptions are dropped
+// through ON DELETE CASCADE, the unread triggers take the messages out of
+// unread counts, and the FTS triggers move their search index entries.
+func (r *Repository) archiveThreads(ctx context.Context, threadIDs []string, now time.Time) (_ int64, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "retention.archiveThreads")
+	defer func() { endSpan(err) }()
+
+	in, args := inClause(threadIDs)
+	threadArgs := append(append([]any{}, args...), args...)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO messages_archive (id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel,
+			reply_count, last_reply_at, mentions, edited_at, deleted_at, deleted_by, pinned_at, pinned_by, webhook_id,
+			bot_name, bot_avatar_url, announcement_id, created_at, updated_at, archived_at)
+		SELECT id, channel_id, user_id, content, type, system_event, thread_parent_id, also_send_to_channel,
+			reply_count, last_reply_at, mentions, edited_at, deleted_at, deleted_by, pinned_at, pinned_by, webhook_id,
+			bot_name, bot_avatar_url, announcement_id, created_at, updated_at, ?
+		FROM messages
+		WHERE id IN `+in+` OR thread_parent_id IN `+in+`
+	`, append([]any{now.UTC().Format(time.RFC3339)}, threadArgs...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reactions_archive (id, message_id, user_id, emoji, created_at)
+		SELECT r.id, r.message_id, r.user_id, r.emoji, r.created_at
+		FROM reactions r
+		JOIN messages m ON m.id = r.message_id
+		WHERE m.id IN `+in+` OR m.thread_parent_id IN `+in+`
+	`, threadArgs...)
+	if err != nil {
+		return 0, err
+	}
+
+	// Deleting the messages would unlink their attachments, leaving them to
+	// be collected as abandoned uploads
+	_, err = tx.ExecContext(ctx, `
+		UPDATE attachments SET archived_message_id = message_id, message_id = NULL
+		WHERE message_id IN (
+			SELECT id FROM messages WHERE id IN `+in+` OR thread_parent_id IN `+in+`
+		)
+	`, threadArgs...)
+	if err != nil {
+		return 0, err
+	}
+
+	replies, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE thread_parent_id IN `+in, args...)
+	if err != nil {
+		return 0, err
+	}
+	parents, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE id IN `+in, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	nReplies, _ := replies.RowsAffected()
+	nParents, _ := parents.RowsAffected()
+	return nReplies + nParents, nil
+}
+
+// inClause builds a parenthesised placeholder list for ids.
+func inClause(ids []string) (string, []any) {
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return "(" + strings.Join(placeholders, ",") + ")", args
+}
@@ -15,9 +15,21 @@ import (
 	"github.com/enzyme/server/internal/workspace"
 )
 
+// Options controls how much data is seeded.
+type Options struct {
+	// Small generates a twentieth of the bulk channel history so seeding
+	// finishes in a moment, e.g. for a throwaway dev database.
+	Small bool
+}
+
 // Run populates the database with seed data for development.
 // It is idempotent — if data already exists, it logs and returns nil.
 func Run(ctx context.Context, db *sql.DB) error {
+	return RunWithOptions(ctx, db, Options{})
+}
+
+// RunWithOptions is Run with control over the size of the dataset.
+func RunWithOptions(ctx context.Context, db *sql.DB, opts Options) error {
 	// Idempotency check
 	var count int
 	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE email = 'alice@example.com'`).Scan(&count); err != nil {
@@ -347,6 +359,9 @@ func Run(ctx context.Context, db *sql.DB) error {
 
 	totalGenerated := 0
 	for _, cfg := range configs {
+		if opts.Small {
+			cfg.count /= 20
+		}
 		if err := generateMessages(ctx, rng, messageRepo, cfg.channelID, cfg.memberIDs, cfg.count, &totalGenerated); err != nil {
 			return fmt.Errorf("generate messages for channel: %w", err)
 		}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/database"
+	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// ConfigReloader re-reads the configuration and applies the settings that
+// can change without a restart. source names what asked for the reload, for
+// the log.
+type ConfigReloader func(ctx context.Context, source string) (config.ReloadResult, error)
+
+// NewAdminHandler serves the server administration endpoints, to be mounted
+// at /admin. Callers must send token as a bearer token; with an empty token
+// the endpoints do not exist. These endpoints expose server-wide state, so
+// no user role, not even a workspace owner's, grants access.
+//
+// slowQueries is nil when slow-query logging is disabled.
+func NewAdminHandler(token string, reload ConfigReloader, slowQueries *database.SlowQueryLog) http.Handler {
+	r := chi.NewRouter()
+	r.Use(requireAdminToken(token))
+	r.Post("/reload-config", reloadConfig(reload))
+	r.Get("/slow-queries", listSlowQueries(slowQueries))
+	return r
+}
+
+// requireAdminToken rejects requests that don't carry token as a bearer
+// token: 401 without credentials and 403 with any others, such as a user's
+// session token.
+func requireAdminToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				writeError(w, r, http.StatusNotFound, openapi.ApiError{Code: handler.ErrCodeNotFound, Message: "Not found"})
+				return
+			}
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || bearer == "" {
+				writeError(w, r, http.StatusUnauthorized, openapi.ApiError{Code: handler.ErrCodeNotAuthenticated, Message: "Admin token required"})
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+				writeError(w, r, http.StatusForbidden, openapi.ApiError{Code: handler.ErrCodePermissionDenied, Message: "Admin token required"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// reloadConfig serves POST /admin/reload-config. A config that fails
+// validation is rejected with 400 and nothing is applied.
+func reloadConfig(reload ConfigReloader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := reload(r.Context(), "api")
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, openapi.ApiError{Code: handler.ErrCodeValidationError, Message: err.Error()})
+			return
+		}
+		writeJSON(w, result)
+	}
+}
+
+// slowQueryReport is the response of GET /admin/slow-queries
+type slowQueryReport struct {
+	Enabled     bool              `json:"enabled"`
+	ThresholdMs float64           `json:"threshold_ms"`
+	Since       *time.Time        `json:"since,omitempty"`
+	Queries     []slowQueryDigest `json:"queries"`
+}
+
+type slowQueryDigest struct {
+	Digest     string    `json:"digest"`
+	Count      int64     `json:"count"`
+	TotalMs    float64   `json:"total_ms"`
+	MaxMs      float64   `json:"max_ms"`
+	AvgMs      float64   `json:"avg_ms"`
+	LastCaller string    `json:"last_caller"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// listSlowQueries serves GET /admin/slow-queries?limit=N: the slowest
+// statement digests recorded since boot, ordered by total time spent.
+func listSlowQueries(log *database.SlowQueryLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if log == nil {
+			writeJSON(w, slowQueryReport{Queries: []slowQueryDigest{}})
+			return
+		}
+
+		limit := 20
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				writeError(w, r, http.StatusBadRequest, openapi.ApiError{Code: handler.ErrCodeBadRequest, Message: "limit must be a positive integer"})
+				return
+			}
+			limit = min(n, 100)
+		}
+
+		stats := log.Top(limit)
+		queries := make([]slowQueryDigest, len(stats))
+		for i, stat := range stats {
+			queries[i] = slowQueryDigest{
+				Digest:     stat.Digest,
+				Count:      stat.Count,
+				TotalMs:    durationMs(stat.TotalDuration),
+				MaxMs:      durationMs(stat.MaxDuration),
+				AvgMs:      durationMs(stat.TotalDuration / time.Duration(stat.Count)),
+				LastCaller: stat.LastCaller,
+				LastSeenAt: stat.LastSeenAt,
+			}
+		}
+
+		since := log.StartedAt()
+		writeJSON(w, slowQueryReport{
+			Enabled:     true,
+			ThresholdMs: durationMs(log.Threshold()),
+			Since:       &since,
+			Queries:     queries,
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
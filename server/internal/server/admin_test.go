@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/config"
+	"github.com/enzyme/server/internal/database"
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func adminRequest(h http.Handler, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminReloadHandler(t *testing.T) {
+	var sources []string
+	var reloadErr error
+	reload := func(ctx context.Context, source string) (config.ReloadResult, error) {
+		sources = append(sources, source)
+		if reloadErr != nil {
+			return config.ReloadResult{}, reloadErr
+		}
+		return config.ReloadResult{
+			Changed:         []config.Change{{Key: "log.level", Old: "info", New: "debug"}},
+			RestartRequired: []string{"server.port"},
+		}, nil
+	}
+
+	post := func(h http.Handler, token string) *httptest.ResponseRecorder {
+		return adminRequest(h, http.MethodPost, "/reload-config", token)
+	}
+
+	if rec := post(NewAdminHandler("", reload, nil), "anything"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without an admin token configured, got %d", rec.Code)
+	}
+
+	h := NewAdminHandler("s3cret", reload, nil)
+	if rec := post(h, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", rec.Code)
+	}
+	if rec := post(h, "wrong"); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a wrong token, got %d", rec.Code)
+	}
+	if len(sources) != 0 {
+		t.Fatalf("expected no reload for rejected requests, got %v", sources)
+	}
+
+	rec := post(h, "s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result config.ReloadResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(result.Changed) != 1 || result.Changed[0].Key != "log.level" || len(result.RestartRequired) != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(sources) != 1 || sources[0] != "api" {
+		t.Errorf("expected one reload from the api, got %v", sources)
+	}
+
+	reloadErr = errors.New("validating config: log.level must be one of: debug, info, warn, error")
+	if rec := post(h, "s3cret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid config, got %d", rec.Code)
+	}
+}
+
+func TestAdminSlowQueries(t *testing.T) {
+	log := database.NewSlowQueryLog(100 * time.Millisecond)
+	log.Record("SELECT * FROM messages_fts WHERE content MATCH 'deploy'", 300*time.Millisecond)
+	log.Record("SELECT * FROM messages_fts WHERE content MATCH 'release'", 100*time.Millisecond)
+	h := NewAdminHandler("s3cret", nil, log)
+
+	rec := adminRequest(h, http.MethodGet, "/slow-queries", "s3cret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var report slowQueryReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	if !report.Enabled || report.ThresholdMs != 100 || report.Since == nil {
+		t.Errorf("expected an enabled report with a 100ms threshold, got %+v", report)
+	}
+	if len(report.Queries) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(report.Queries))
+	}
+	q := report.Queries[0]
+	if q.Digest != "SELECT * FROM messages_fts WHERE content MATCH ?" || q.Count != 2 || q.TotalMs != 400 || q.MaxMs != 300 || q.AvgMs != 200 {
+		t.Errorf("unexpected digest: %+v", q)
+	}
+
+	if rec := adminRequest(h, http.MethodGet, "/slow-queries?limit=zero", "s3cret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad limit, got %d", rec.Code)
+	}
+
+	rec = adminRequest(NewAdminHandler("s3cret", nil, nil), http.MethodGet, "/slow-queries", "s3cret")
+	report = slowQueryReport{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	if report.Enabled || report.Queries == nil || len(report.Queries) != 0 {
+		t.Errorf("expected a disabled report with no queries, got %+v", report)
+	}
+}
+
+func TestAdminSlowQueries_WorkspaceOwnerForbidden(t *testing.T) {
+	db := testutil.TestDB(t)
+	owner := testutil.CreateTestUser(t, db, "owner@test.com", "Owner")
+	testutil.CreateTestWorkspace(t, db, owner.ID, "WS")
+
+	sessions := auth.NewSessionStore(db, time.Hour, 0)
+	tokens, err := sessions.Create(owner.ID, auth.ClientInfo{})
+	if err != nil {
+		t.Fatalf("creating session: %v", err)
+	}
+
+	// Owning a workspace, which any user can create, grants nothing here
+	h := auth.TokenMiddleware(sessions, nil)(NewAdminHandler("s3cret", nil, database.NewSlowQueryLog(0)))
+	if rec := adminRequest(h, http.MethodGet, "/slow-queries", tokens.AccessToken); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a workspace owner's session, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at the spec served
+// alongside it. It is only mounted when API docs are enabled, which is meant
+// for development, so depending on the CDN is acceptable.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Enzyme API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: 'openapi.yaml',
+      dom_id: '#swagger-ui',
+      persistAuthorization: true,
+    });
+  </script>
+</body>
+</html>
+`
+
+// NewAPIDocsHandler serves a Swagger UI page and the OpenAPI spec at
+// specPath. The spec is read from disk on every request so edits show up on
+// reload without restarting the server.
+func NewAPIDocsHandler(specPath string) http.Handler {
+	r := chi.NewRouter()
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		// Relative spec URL below only resolves correctly with a trailing slash
+		if r.URL.Path == "" || r.URL.Path[len(r.URL.Path)-1] != '/' {
+			http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(swaggerUIPage))
+	})
+
+	r.Get("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		spec, err := os.ReadFile(specPath)
+		if err != nil {
+			slog.Error("failed to read OpenAPI spec", "path", specPath, "error", err)
+			http.Error(w, "OpenAPI spec not available", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(spec)
+	})
+
+	return r
+}
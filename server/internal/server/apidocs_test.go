@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestAPIDocsHandler(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.3\ninfo:\n  title: v1\n"), 0644); err != nil {
+		t.Fatalf("writing spec: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Mount("/api/docs", NewAPIDocsHandler(specPath))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/api/docs"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/api/docs/" {
+		t.Fatalf("expected redirect to /api/docs/, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec := get("/api/docs/")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "SwaggerUIBundle") {
+		t.Fatalf("expected Swagger UI page, got %d", rec.Code)
+	}
+
+	rec = get("/api/docs/openapi.yaml")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "title: v1") {
+		t.Fatalf("expected spec, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Edits to the spec are served without restarting
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.3\ninfo:\n  title: v2\n"), 0644); err != nil {
+		t.Fatalf("rewriting spec: %v", err)
+	}
+	rec = get("/api/docs/openapi.yaml")
+	if !strings.Contains(rec.Body.String(), "title: v2") {
+		t.Fatalf("expected reloaded spec, got %q", rec.Body.String())
+	}
+}
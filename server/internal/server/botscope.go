@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/enzyme/server/internal/bot"
+	"github.com/enzyme/server/internal/handler"
+)
+
+// botOperationScopes maps API operation IDs to the bot token scope required
+// to call them. Operations not listed here are unavailable to bot tokens.
+// An empty scope means any valid bot token may call the operation.
+var botOperationScopes = map[string]string{
+	"GetMe": "",
+
+	"SendMessage":   bot.ScopeChatWrite,
+	"UpdateMessage": bot.ScopeChatWrite,
+	"DeleteMessage": bot.ScopeChatWrite,
+
+	"AddReaction":    bot.ScopeReactionsWrite,
+	"RemoveReaction": bot.ScopeReactionsWrite,
+
+	"ListChannels":       bot.ScopeChannelsRead,
+	"ListChannelMembers": bot.ScopeChannelsRead,
+
+	"GetMessage":          bot.ScopeChannelsHistory,
+	"GetMessagePermalink": bot.ScopeChannelsHistory,
+	"ListMessages":        bot.ScopeChannelsHistory,
+	"GetChannelMessages":  bot.ScopeChannelsHistory,
+	"ListThread":          bot.ScopeChannelsHistory,
+	"GetThreadReplies":    bot.ScopeChannelsHistory,
+	"ListPinnedMessages":  bot.ScopeChannelsHistory,
+
+	"JoinChannel":  bot.ScopeChannelsJoin,
+	"LeaveChannel": bot.ScopeChannelsJoin,
+
+	"GetUser":               bot.ScopeUsersRead,
+	"ListWorkspaceMembers":  bot.ScopeUsersRead,
+	"CountWorkspaceMembers": bot.ScopeUsersRead,
+}
+
+// botCanCall reports whether a bot token with the given scopes may call the
+// operation.
+func botCanCall(operationID string, scopes []string) bool {
+	required, ok := botOperationScopes[operationID]
+	if !ok {
+		return false
+	}
+	return required == "" || slices.Contains(scopes, required)
+}
+
+// writeBotScopeResponse writes a 403 JSON response for bot tokens that lack
+// the scope required by the operation.
+func writeBotScopeResponse(w http.ResponseWriter, operationID string) {
+	message := "Bot tokens cannot access this endpoint"
+	if required := botOperationScopes[operationID]; required != "" {
+		message = "Bot token is missing the " + required + " scope"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":    handler.ErrCodePermissionDenied,
+			"message": message,
+		},
+	})
+}
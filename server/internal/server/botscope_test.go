@@ -0,0 +1,26 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/enzyme/server/internal/bot"
+)
+
+func TestBotCanCall(t *testing.T) {
+	tests := []struct {
+		operationID string
+		scopes      []string
+		want        bool
+	}{
+		{"SendMessage", []string{bot.ScopeChatWrite}, true},
+		{"SendMessage", []string{bot.ScopeChannelsRead}, false},
+		{"GetMe", nil, true},
+		{"CreateBot", []string{bot.ScopeChatWrite, bot.ScopeUsersRead}, false},
+		{"BanUser", bot.AllScopes, false},
+	}
+	for _, tt := range tests {
+		if got := botCanCall(tt.operationID, tt.scopes); got != tt.want {
+			t.Errorf("botCanCall(%q, %v) = %v, want %v", tt.operationID, tt.scopes, got, tt.want)
+		}
+	}
+}
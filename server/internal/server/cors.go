@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-chi/cors"
+)
+
+// CORS applies the cross-origin policy for the allowed origins. The origins
+// can be replaced while the server runs; with none, no CORS headers are sent.
+type CORS struct {
+	allowedHeaders []string
+	policy         atomic.Pointer[cors.Cors]
+}
+
+// NewCORS creates a CORS policy. Trace headers are allowed when
+// telemetryEnabled is set, so browsers can propagate traces.
+func NewCORS(allowedOrigins []string, telemetryEnabled bool) *CORS {
+	c := &CORS{allowedHeaders: []string{"Content-Type", "Authorization", "If-None-Match", "X-Request-ID"}}
+	if telemetryEnabled {
+		c.allowedHeaders = append(c.allowedHeaders, "traceparent", "tracestate")
+	}
+	c.SetOrigins(allowedOrigins)
+	return c
+}
+
+// SetOrigins replaces the allowed origins.
+func (c *CORS) SetOrigins(allowedOrigins []string) {
+	if len(allowedOrigins) == 0 {
+		c.policy.Store(nil)
+		return
+	}
+	c.policy.Store(cors.New(cors.Options{
+		AllowedOrigins: allowedOrigins,
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: c.allowedHeaders,
+		ExposedHeaders: []string{"X-Request-Id", "ETag"},
+		MaxAge:         86400,
+	}))
+}
+
+// Handler is the CORS middleware.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if policy := c.policy.Load(); policy != nil {
+			policy.Handler(next).ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
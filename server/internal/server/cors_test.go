@@ -5,22 +5,17 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/enzyme/server/internal/server"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/cors"
 )
 
 func newTestRouter(allowedOrigins []string) http.Handler {
-	r := chi.NewRouter()
+	return newTestRouterWithCORS(server.NewCORS(allowedOrigins, false))
+}
 
-	if len(allowedOrigins) > 0 {
-		r.Use(cors.Handler(cors.Options{
-			AllowedOrigins: allowedOrigins,
-			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-			AllowedHeaders: []string{"Content-Type", "Authorization"},
-			ExposedHeaders: []string{"X-Request-Id"},
-			MaxAge:         86400,
-		}))
-	}
+func newTestRouterWithCORS(c *server.CORS) http.Handler {
+	r := chi.NewRouter()
+	r.Use(c.Handler)
 
 	r.Get("/api/test", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -97,3 +92,29 @@ func TestCORS_NoMiddlewareWhenEmpty(t *testing.T) {
 		t.Fatalf("expected no CORS headers when origins empty, got %q", got)
 	}
 }
+
+func TestCORS_SetOrigins(t *testing.T) {
+	c := server.NewCORS([]string{"http://localhost:3000"}, false)
+	router := newTestRouterWithCORS(c)
+
+	allowOrigin := func(origin string) string {
+		req := httptest.NewRequest("GET", "/api/test", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	c.SetOrigins([]string{"https://chat.example.com"})
+	if got := allowOrigin("https://chat.example.com"); got != "https://chat.example.com" {
+		t.Fatalf("expected the new origin to be allowed, got %q", got)
+	}
+	if got := allowOrigin("http://localhost:3000"); got != "" {
+		t.Fatalf("expected the old origin to be refused, got %q", got)
+	}
+
+	c.SetOrigins(nil)
+	if got := allowOrigin("https://chat.example.com"); got != "" {
+		t.Fatalf("expected no CORS headers once origins are cleared, got %q", got)
+	}
+}
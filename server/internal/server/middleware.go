@@ -1,15 +1,125 @@
 package server
 
 import (
+	"io"
 	"log/slog"
 	"net/http"
+	"reflect"
+	"sync"
 	"time"
 
+	"github.com/andybalholm/brotli"
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/logging"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/oklog/ulid/v2"
 )
 
+// maxRequestIDLength bounds client-supplied request IDs so they cannot bloat
+// log records.
+const maxRequestIDLength = 128
+
+// RequestID assigns each request an ID, reusing a well-formed X-Request-ID
+// header from the client or a proxy and generating one otherwise. The ID is
+// echoed in the X-Request-Id response header and carried in the request
+// context, where logging picks it up.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = ulid.Make().String()
+		}
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts short IDs of printable ASCII without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Locale picks the response language from the Accept-Language header and
+// carries it in the request context for handlers that render text, such as
+// system messages.
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), locale)))
+	})
+}
+
+var (
+	apiErrorType = reflect.TypeOf(openapi.ApiError{})
+	// errorResponseTypes caches whether a response type carries an ApiError.
+	errorResponseTypes sync.Map
+)
+
+// localizeErrorResponse translates the error in a strict handler response
+// into locale. Error responses are structs embedding the shared error body,
+// so the ApiError is found by walking their fields; other responses are
+// returned as they are.
+func localizeErrorResponse(locale string, response interface{}) interface{} {
+	if locale == i18n.Default || response == nil {
+		return response
+	}
+	t := reflect.TypeOf(response)
+	if !hasAPIError(t) {
+		return response
+	}
+	v := reflect.New(t).Elem()
+	v.Set(reflect.ValueOf(response))
+	localizeAPIErrors(locale, v)
+	return v.Interface()
+}
+
+func hasAPIError(t reflect.Type) bool {
+	if cached, ok := errorResponseTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	found := false
+	if t == apiErrorType {
+		found = true
+	} else if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField() && !found; i++ {
+			found = t.Field(i).IsExported() && hasAPIError(t.Field(i).Type)
+		}
+	}
+	errorResponseTypes.Store(t, found)
+	return found
+}
+
+func localizeAPIErrors(locale string, v reflect.Value) {
+	if v.Type() == apiErrorType {
+		handler.LocalizeError(locale, v.Addr().Interface().(*openapi.ApiError))
+		return
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.CanSet() {
+			localizeAPIErrors(locale, f)
+		}
+	}
+}
+
 // RequestLogger is a structured logging middleware that logs each HTTP request
-// with method, path, status, duration, and response size.
+// with method, route, status, duration, response size, and the user and
+// workspace it was made for.
 func RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip logging health checks
@@ -23,12 +133,61 @@ func RequestLogger(next http.Handler) http.Handler {
 
 		next.ServeHTTP(ww, r)
 
-		slog.Info("http request",
+		attrs := []any{
 			"method", r.Method,
 			"path", r.URL.Path,
 			"status", ww.Status(),
-			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
 			"bytes", ww.BytesWritten(),
-		)
+		}
+		// Routing and authentication run further down the chain; the route
+		// context and request info are shared, so their results are visible here.
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if route := rctx.RoutePattern(); route != "" {
+				attrs = append(attrs, "route", route)
+			}
+			if wid := rctx.URLParam("wid"); wid != "" {
+				attrs = append(attrs, "workspace_id", wid)
+			}
+		}
+		if userID := logging.UserID(r.Context()); userID != "" {
+			attrs = append(attrs, "user_id", userID)
+		}
+		slog.InfoContext(r.Context(), "http request", attrs...)
+	})
+}
+
+// recordRequestUser notes the authenticated user for the request log. It runs
+// after auth.TokenMiddleware.
+func recordRequestUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userID := auth.GetUserID(r.Context()); userID != "" {
+			logging.SetUserID(r.Context(), userID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// compressibleTypes are the response content types worth compressing. Event
+// streams are left out so SSE events are flushed to clients as they happen.
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"text/html",
+	"text/css",
+	"text/plain",
+	"text/javascript",
+	"image/svg+xml",
+}
+
+// Compress brotli-, gzip- or deflate-encodes responses of compressible types
+// for clients that accept it, at the given level (1-9). Brotli is preferred
+// when a client accepts several.
+func Compress(level int) func(http.Handler) http.Handler {
+	c := middleware.NewCompressor(level, compressibleTypes...)
+	c.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+		return brotli.NewWriterLevel(w, level)
 	})
+	return c.Handler
 }
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/logging"
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"honors client ID", "abc-123", true},
+		{"generates when missing", "", false},
+		{"replaces IDs with spaces", "abc 123", false},
+		{"replaces overlong IDs", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get("X-Request-Id")
+			if got == "" || got != seen {
+				t.Fatalf("response ID %q, context ID %q; want matching non-empty IDs", got, seen)
+			}
+			if tt.keep != (got == tt.header) {
+				t.Errorf("ID = %q, header %q, keep = %v", got, tt.header, tt.keep)
+			}
+		})
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Use(RequestLogger)
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logging.SetUserID(r.Context(), "user-1")
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Get("/api/workspaces/{wid}/channels", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/workspaces/ws-1/channels", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decoding log entry %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"method":       "GET",
+		"route":        "/api/workspaces/{wid}/channels",
+		"status":       float64(http.StatusTeapot),
+		"workspace_id": "ws-1",
+		"user_id":      "user-1",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	var got string
+	r := chi.NewRouter()
+	r.Use(Locale)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		got = i18n.FromContext(r.Context())
+	})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "fr-CH, fr;q=0.9, en;q=0.8")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if got != "fr" {
+		t.Errorf("locale = %q, want fr", got)
+	}
+	if vary := rec.Header().Get("Vary"); vary != "Accept-Language" {
+		t.Errorf("Vary = %q, want Accept-Language", vary)
+	}
+}
+
+func TestLocalizeErrorResponse(t *testing.T) {
+	params := map[string]any{"field": "name"}
+	resp := openapi.CreateChannel400JSONResponse{BadRequestJSONResponse: openapi.BadRequestJSONResponse{
+		Error: openapi.ApiError{Code: handler.ErrCodeRequired, Message: "name is required", Params: &params},
+	}}
+
+	got, ok := localizeErrorResponse("de", resp).(openapi.CreateChannel400JSONResponse)
+	if !ok {
+		t.Fatalf("expected the response type to be kept, got %T", got)
+	}
+	if got.Error.Message != "name ist erforderlich" {
+		t.Errorf("message = %q, want the German text", got.Error.Message)
+	}
+	if resp.Error.Message != "name is required" {
+		t.Error("expected the handler's response to be left untouched")
+	}
+
+	ok200 := openapi.CreateChannel200JSONResponse{}
+	if got := localizeErrorResponse("de", ok200); got != any(ok200) {
+		t.Errorf("expected a success response to pass through, got %+v", got)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Compress(5))
+	r.Get("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(strings.Repeat(`{"content":"hello"}`, 100)))
+	})
+	r.Get("/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {}\n\n"))
+	})
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/json", "gzip")
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !strings.HasPrefix(string(body), `{"content":"hello"}`) {
+		t.Errorf("unexpected decompressed body %q", body[:20])
+	}
+
+	w = get("/json", "gzip, br")
+	if got := w.Header().Get("Content-Encoding"); got != "br" {
+		t.Fatalf("Content-Encoding = %q, want br", got)
+	}
+	body, err = io.ReadAll(brotli.NewReader(w.Body))
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !strings.HasPrefix(string(body), `{"content":"hello"}`) {
+		t.Errorf("unexpected decompressed body %q", body[:20])
+	}
+
+	if got := get("/json", "").Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q without Accept-Encoding, want none", got)
+	}
+	if got := get("/events", "gzip").Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q for an event stream, want none", got)
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/i18n"
+	"github.com/enzyme/server/internal/logging"
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// problemContentType is the media type of RFC 7807 problem documents.
+const problemContentType = "application/problem+json"
+
+// wantsProblem reports whether the request's Accept header asks for problem
+// documents. Clients that don't keep getting the {"error": ...} envelope.
+func wantsProblem(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != problemContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// newProblem builds the problem document for an error envelope.
+func newProblem(r *http.Request, status int, apiErr openapi.ApiError) openapi.Problem {
+	p := openapi.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   apiErr.Code,
+		Params: apiErr.Params,
+		Limit:  apiErr.Limit,
+	}
+	if apiErr.Message != "" {
+		p.Detail = &apiErr.Message
+	}
+	if path := r.URL.Path; path != "" {
+		p.Instance = &path
+	}
+	if id := logging.RequestID(r.Context()); id != "" {
+		p.RequestId = &id
+	}
+	return p
+}
+
+// writeError writes an error response in the format the client asked for:
+// a problem document or the usual envelope. The message is translated into
+// the request's language when the catalog has it.
+func writeError(w http.ResponseWriter, r *http.Request, status int, apiErr openapi.ApiError) {
+	handler.LocalizeError(i18n.FromContext(r.Context()), &apiErr)
+	if wantsProblem(r) {
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(newProblem(r, status, apiErr))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(openapi.ApiErrorResponse{Error: apiErr})
+}
+
+// Problems rewrites JSON error envelopes as problem documents for clients
+// that ask for them, so handlers and middleware only ever write envelopes.
+// It must run inside Compress, which would otherwise hand it encoded bytes.
+func Problems(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !wantsProblem(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		pw := &problemWriter{ResponseWriter: w}
+		next.ServeHTTP(pw, r)
+		if pw.body != nil {
+			pw.writeProblem(r)
+		}
+	})
+}
+
+// problemWriter holds back JSON error responses so they can be rewritten;
+// everything else is passed straight through.
+type problemWriter struct {
+	http.ResponseWriter
+	status int
+	body   *bytes.Buffer
+}
+
+func (pw *problemWriter) WriteHeader(status int) {
+	mediaType, _, _ := mime.ParseMediaType(pw.Header().Get("Content-Type"))
+	if status >= 400 && mediaType == "application/json" {
+		pw.status = status
+		pw.body = &bytes.Buffer{}
+		return
+	}
+	pw.ResponseWriter.WriteHeader(status)
+}
+
+func (pw *problemWriter) Write(b []byte) (int, error) {
+	if pw.body != nil {
+		return pw.body.Write(b)
+	}
+	return pw.ResponseWriter.Write(b)
+}
+
+func (pw *problemWriter) Flush() {
+	if pw.body != nil {
+		return
+	}
+	if f, ok := pw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (pw *problemWriter) Unwrap() http.ResponseWriter {
+	return pw.ResponseWriter
+}
+
+// writeProblem sends the held-back response as a problem document. Bodies
+// that aren't an error envelope are sent unchanged.
+func (pw *problemWriter) writeProblem(r *http.Request) {
+	var envelope struct {
+		Error      *openapi.ApiError `json:"error"`
+		RetryAfter *int              `json:"retry_after"`
+	}
+	if err := json.Unmarshal(pw.body.Bytes(), &envelope); err != nil || envelope.Error == nil {
+		pw.ResponseWriter.WriteHeader(pw.status)
+		_, _ = pw.ResponseWriter.Write(pw.body.Bytes())
+		return
+	}
+	p := newProblem(r, pw.status, *envelope.Error)
+	p.RetryAfter = envelope.RetryAfter
+	pw.Header().Set("Content-Type", problemContentType)
+	pw.ResponseWriter.WriteHeader(pw.status)
+	_ = json.NewEncoder(pw.ResponseWriter).Encode(p)
+}
+
+// Recoverer turns a panic in a handler into a 500 error response. The panic
+// value and stack trace are logged along with the request ID, which is also
+// in the response, so a report from a user can be matched to the trace.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// Deliberate abort of the response; let net/http handle it
+				panic(rec)
+			}
+			slog.ErrorContext(r.Context(), "panic serving request",
+				"panic", fmt.Sprint(rec),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+			if r.Header.Get("Connection") == "Upgrade" {
+				return
+			}
+			writeError(w, r, http.StatusInternalServerError, openapi.ApiError{
+				Code:    handler.ErrCodeInternalError,
+				Message: "An internal error occurred",
+			})
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/enzyme/server/internal/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestWantsProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/json, application/problem+json;q=0.9", true},
+		{"application/problem+json;q=0", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		if got := wantsProblem(req); got != tt.want {
+			t.Errorf("wantsProblem(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestProblems(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Use(Problems)
+	r.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":"SLOW_MODE","message":"Slow mode is on"},"retry_after":12}`))
+	})
+	r.Get("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	})
+	r.Get("/text", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("X-Request-ID", "req-1")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/slow", "application/problem+json")
+	if ct := rec.Header().Get("Content-Type"); ct != problemContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, problemContentType)
+	}
+	var p openapi.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decoding problem: %v", err)
+	}
+	if rec.Code != http.StatusTooManyRequests || p.Status != http.StatusTooManyRequests || p.Title != "Too Many Requests" || p.Code != "SLOW_MODE" {
+		t.Errorf("unexpected problem %+v", p)
+	}
+	if p.Detail == nil || *p.Detail != "Slow mode is on" || p.Instance == nil || *p.Instance != "/slow" {
+		t.Errorf("expected detail and instance, got %+v", p)
+	}
+	if p.RetryAfter == nil || *p.RetryAfter != 12 || p.RequestId == nil || *p.RequestId != "req-1" {
+		t.Errorf("expected retry_after and request_id, got %+v", p)
+	}
+
+	// Clients that don't ask keep the envelope
+	rec = get("/slow", "application/json")
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" || !strings.Contains(rec.Body.String(), `"error"`) {
+		t.Errorf("expected the envelope, got %q: %s", ct, rec.Body.String())
+	}
+
+	if rec = get("/ok", "application/problem+json"); rec.Code != http.StatusOK || rec.Body.String() != `{"ok":true}` {
+		t.Errorf("expected a success response to pass through, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec = get("/text", "application/problem+json"); rec.Code != http.StatusGone || !strings.Contains(rec.Body.String(), "gone") {
+		t.Errorf("expected a non-JSON error to pass through, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Use(Recoverer)
+	r.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("secret detail")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	req.Header.Set("X-Request-ID", "req-2")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var p openapi.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatalf("decoding problem %q: %v", rec.Body.String(), err)
+	}
+	if rec.Code != http.StatusInternalServerError || p.Code != "INTERNAL_ERROR" || p.RequestId == nil || *p.RequestId != "req-2" {
+		t.Errorf("unexpected response %d %+v", rec.Code, p)
+	}
+	if strings.Contains(rec.Body.String(), "secret detail") {
+		t.Error("the panic value must not reach the client")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decoding log entry %q: %v", buf.String(), err)
+	}
+	if entry["panic"] != "secret detail" || !strings.Contains(entry["stack"].(string), "TestRecoverer") {
+		t.Errorf("expected the panic and its stack to be logged, got %v", entry)
+	}
+}
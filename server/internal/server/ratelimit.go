@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/enzyme/server/internal/ratelimit"
+)
+
+// unlimitedAPIPrefixes are API routes the class limiter skips: public images
+// that browsers load in bulk through <img> tags, and incoming webhooks, which
+// have their own per-webhook limiter.
+var unlimitedAPIPrefixes = []string{
+	"/api/avatars/",
+	"/api/workspace-icons/",
+	"/api/emojis/",
+	"/api/hooks/",
+}
+
+// APIRateLimitClassifier sorts API requests into the auth, message send, and
+// general classes. Non-API requests (the SPA, health checks) are not limited.
+func APIRateLimitClassifier(authClass, sendMessage, general ratelimit.Class) ratelimit.Classifier {
+	return func(r *http.Request) (ratelimit.Class, bool) {
+		path := r.URL.Path
+		if !strings.HasPrefix(path, "/api/") {
+			return ratelimit.Class{}, false
+		}
+		for _, prefix := range unlimitedAPIPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				return ratelimit.Class{}, false
+			}
+		}
+		switch {
+		case strings.HasPrefix(path, "/api/auth/"):
+			return authClass, true
+		case r.Method == http.MethodPost && strings.HasPrefix(path, "/api/channels/") && strings.HasSuffix(path, "/messages/send"):
+			return sendMessage, true
+		default:
+			return general, true
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/ratelimit"
+)
+
+func TestAPIRateLimitClassifier(t *testing.T) {
+	authClass := ratelimit.Class{Name: "auth", Limit: 1, Window: time.Minute}
+	send := ratelimit.Class{Name: "send_message", Limit: 2, Window: time.Minute}
+	general := ratelimit.Class{Name: "api", Limit: 3, Window: time.Minute}
+	classify := APIRateLimitClassifier(authClass, send, general)
+
+	tests := []struct {
+		method, path string
+		want         string // empty means not limited
+	}{
+		{"POST", "/api/auth/login", "auth"},
+		{"GET", "/api/auth/me", "auth"},
+		{"POST", "/api/channels/C1/messages/send", "send_message"},
+		{"POST", "/api/channels/C1/messages/list", "api"},
+		{"GET", "/api/workspaces/W1/quick-switch", "api"},
+		{"GET", "/api/avatars/U1/a.png", ""},
+		{"POST", "/api/hooks/abc", ""},
+		{"GET", "/", ""},
+		{"GET", "/health", ""},
+	}
+	for _, tt := range tests {
+		class, ok := classify(httptest.NewRequest(tt.method, tt.path, nil))
+		got := ""
+		if ok {
+			got = class.Name
+		}
+		if got != tt.want {
+			t.Errorf("%s %s classified as %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
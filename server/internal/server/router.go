@@ -11,6 +11,7 @@ import (
 
 	"github.com/enzyme/server/internal/auth"
 	"github.com/enzyme/server/internal/handler"
+	"github.com/enzyme/server/internal/i18n"
 	"github.com/enzyme/server/internal/moderation"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/ratelimit"
@@ -18,7 +19,6 @@ import (
 	"github.com/enzyme/server/internal/telemetry"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 )
 
@@ -52,37 +52,48 @@ func InvalidateBanCacheByWorkspace(workspaceID string) {
 	})
 }
 
-// NewRouter creates a new HTTP router with all routes registered.
-// If spaHandler is non-nil, it is mounted as a fallback for unmatched routes
-// to serve the embedded web client.
-func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.SessionStore, moderationRepo *moderation.Repository, limiter *ratelimit.Limiter, allowedOrigins []string, telemetryEnabled bool, spaHandler http.Handler, otlpProxy http.Handler) http.Handler {
+// RouterOptions are the optional parts of a router. Nil handlers are not
+// mounted.
+type RouterOptions struct {
+	CORS             *CORS // nil sends no CORS headers
+	CompressionLevel int   // brotli/gzip level for responses; 0 turns compression off
+	TelemetryEnabled bool
+
+	Admin     http.Handler // mounted at /admin
+	APIDocs   http.Handler // mounted at /api/docs
+	OTLPProxy http.Handler // receives the web client's traces
+	SPA       http.Handler // serves the embedded web client for unmatched routes
+}
+
+// NewRouter creates a new HTTP router with all routes registered, plus the
+// optional handlers and settings in opts.
+func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.SessionStore, botTokens auth.BotTokenValidator, moderationRepo *moderation.Repository, limiter *ratelimit.Limiter, apiLimiter *ratelimit.ClassLimiter, opts RouterOptions) http.Handler {
 	r := chi.NewRouter()
 
 	// Middleware
+	r.Use(RequestID)
 	r.Use(RequestLogger)
-	r.Use(middleware.Recoverer)
+	r.Use(Locale)
+	r.Use(Recoverer)
 	r.Use(middleware.RealIP)
 
-	if telemetryEnabled {
+	if opts.TelemetryEnabled {
 		r.Use(telemetry.Middleware())
 	}
 
-	if len(allowedOrigins) > 0 {
-		allowedHeaders := []string{"Content-Type", "Authorization"}
-		if telemetryEnabled {
-			allowedHeaders = append(allowedHeaders, "traceparent", "tracestate")
-		}
-		r.Use(cors.Handler(cors.Options{
-			AllowedOrigins: allowedOrigins,
-			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-			AllowedHeaders: allowedHeaders,
-			ExposedHeaders: []string{"X-Request-Id"},
-			MaxAge:         86400,
-		}))
+	if opts.CompressionLevel > 0 {
+		r.Use(Compress(opts.CompressionLevel))
+	}
+	r.Use(Problems)
+
+	if opts.CORS != nil {
+		r.Use(opts.CORS.Handler)
 	}
 
 	r.Use(ratelimit.Middleware(limiter))
-	r.Use(auth.TokenMiddleware(sessionStore))
+	r.Use(auth.TokenMiddleware(sessionStore, botTokens))
+	r.Use(recordRequestUser)
+	r.Use(ratelimit.ClassMiddleware(apiLimiter, func(r *http.Request) string { return auth.GetUserID(r.Context()) }))
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -90,12 +101,22 @@ func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.S
 		_, _ = w.Write([]byte("OK"))
 	})
 
-	// Create strict middleware that adds request to context
+	if opts.Admin != nil {
+		r.Mount("/admin", opts.Admin)
+	}
+
+	// Create strict middleware that adds request to context and enforces
+	// bot token scopes per operation
 	strictMiddleware := func(f strictnethttp.StrictHTTPHandlerFunc, operationID string) strictnethttp.StrictHTTPHandlerFunc {
 		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+			if scopes, ok := auth.GetBotScopes(ctx); ok && !botCanCall(operationID, scopes) {
+				writeBotScopeResponse(w, operationID)
+				return nil, nil
+			}
 			// Add the http.Request to context so handlers can access session
 			ctx = handler.WithRequest(ctx, r)
-			return f(ctx, w, r, request)
+			response, err := f(ctx, w, r, request)
+			return localizeErrorResponse(i18n.FromContext(ctx), response), err
 		}
 	}
 
@@ -104,23 +125,25 @@ func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.S
 	// Create the strict handler with middleware
 	strictHandler := openapi.NewStrictHandlerWithOptions(h, []openapi.StrictMiddlewareFunc{strictMiddleware}, openapi.StrictHTTPServerOptions{
 		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(openapi.ApiErrorResponse{
-				Error: openapi.ApiError{Code: "BAD_REQUEST", Message: err.Error()},
-			})
+			writeError(w, r, http.StatusBadRequest, openapi.ApiError{Code: handler.ErrCodeBadRequest, Message: err.Error()})
 		},
 		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			slog.Error("unhandled handler error",
-				"error", err.Error(),
-				"method", r.Method,
-				"path", r.URL.Path,
-			)
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			_ = json.NewEncoder(w).Encode(openapi.ApiErrorResponse{
-				Error: openapi.ApiError{Code: "INTERNAL_ERROR", Message: "An internal error occurred"},
-			})
+			status, apiErr := handler.ErrorResponse(err)
+			if status >= http.StatusInternalServerError {
+				slog.ErrorContext(r.Context(), "unhandled handler error",
+					"error", err.Error(),
+					"method", r.Method,
+					"path", r.URL.Path,
+				)
+			} else {
+				slog.WarnContext(r.Context(), "handler returned an unchecked repository error",
+					"error", err.Error(),
+					"status", status,
+					"method", r.Method,
+					"path", r.URL.Path,
+				)
+			}
+			writeError(w, r, status, apiErr)
 		},
 	})
 
@@ -129,7 +152,7 @@ func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.S
 	// so chi.URLParam is available). SpanRenameMiddleware updates the OTel span name
 	// with the matched route pattern (e.g. "GET /api/workspaces/{wid}/channels").
 	routeMiddlewares := []openapi.MiddlewareFunc{banCheckMw}
-	if telemetryEnabled {
+	if opts.TelemetryEnabled {
 		routeMiddlewares = append([]openapi.MiddlewareFunc{telemetry.SpanRenameMiddleware()}, routeMiddlewares...)
 	}
 	openapi.HandlerWithOptions(strictHandler, openapi.ChiServerOptions{
@@ -142,12 +165,15 @@ func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.S
 	r.Route("/api", func(r chi.Router) {
 		// Public routes (no auth required)
 		r.Get("/avatars/{filename}", h.ServeAvatar)
+		r.Get("/avatars/{userId}/{filename}", h.ServeAvatar)
 		r.Get("/workspace-icons/{workspaceId}/{filename}", h.ServeWorkspaceIcon)
 		r.Get("/emojis/{workspaceId}/{filename}", h.ServeEmoji)
 
 		r.Group(func(r chi.Router) {
 			r.Use(auth.RequireAuth())
+			r.Use(auth.RejectBotTokens())
 			r.Use(banCheckMw)
+			r.Get("/events", sseHandler.UserEvents)
 			r.Get("/workspaces/{wid}/events", sseHandler.Events)
 			r.Post("/workspaces/{wid}/typing/start", sseHandler.StartTyping)
 			r.Post("/workspaces/{wid}/typing/stop", sseHandler.StopTyping)
@@ -155,13 +181,28 @@ func NewRouter(h *handler.Handler, sseHandler *sse.Handler, sessionStore *auth.S
 	})
 
 	// Mount OTLP trace proxy for frontend telemetry
-	if otlpProxy != nil {
-		r.Post("/api/telemetry/traces", otlpProxy.ServeHTTP)
+	if opts.OTLPProxy != nil {
+		r.Post("/api/telemetry/traces", opts.OTLPProxy.ServeHTTP)
+	}
+
+	// Mount API reference (Swagger UI + spec)
+	if opts.APIDocs != nil {
+		r.Mount("/api/docs", opts.APIDocs)
 	}
 
-	// Mount embedded SPA as fallback for all unmatched routes
-	if spaHandler != nil {
-		r.NotFound(spaHandler.ServeHTTP)
+	// Mount embedded SPA as fallback for all unmatched routes. Unknown API
+	// paths still get a JSON 404 rather than the client's index.html.
+	if opts.SPA != nil {
+		r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/") {
+				writeError(w, r, http.StatusNotFound, openapi.ApiError{
+					Code:    handler.ErrCodeNotFound,
+					Message: "Not found",
+				})
+				return
+			}
+			opts.SPA.ServeHTTP(w, r)
+		})
 	}
 
 	return r
@@ -215,7 +256,7 @@ func BanCheckMiddleware(moderationRepo *moderation.Repository) func(http.Handler
 				// issues. This means banned users may temporarily bypass
 				// enforcement during database problems. This is a deliberate
 				// availability-over-security tradeoff.
-				slog.Error("ban check failed", "error", err, "workspace", wid, "user", userID)
+				slog.ErrorContext(r.Context(), "ban check failed", "error", err, "workspace", wid, "user", userID)
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -242,7 +283,7 @@ func writeBannedResponse(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusForbidden)
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"error": map[string]string{
-			"code":    "BANNED",
+			"code":    handler.ErrCodeBanned,
 			"message": "You are banned from this workspace",
 		},
 	})
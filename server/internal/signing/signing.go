@@ -64,3 +64,10 @@ func (s *Signer) SignedURL(baseURL, fileID, userID string, ttl time.Duration) (s
 
 	return u.String(), expires, nil
 }
+
+// HashToken returns the hex-encoded SHA-256 hash of a plaintext token. Session,
+// bot and webhook tokens are stored only in this form.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
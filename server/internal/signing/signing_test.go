@@ -98,3 +98,11 @@ func TestSignedURLInvalidBaseURL(t *testing.T) {
 		t.Fatal("expected error for invalid base URL")
 	}
 }
+
+func TestHashToken(t *testing.T) {
+	// SHA-256 of "abc"
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := HashToken("abc"); got != want {
+		t.Errorf("HashToken(abc) = %s, want %s", got, want)
+	}
+}
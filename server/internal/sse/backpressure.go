@@ -0,0 +1,99 @@
+package sse
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OverflowPolicy decides what happens to a client whose buffer is full when
+// an event arrives for it. Broadcasts never wait on a client either way.
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest discards the oldest queued event to make room, so
+	// a client that falls behind skips stale events but keeps its stream.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDisconnect closes the client's stream. It reconnects with
+	// Last-Event-ID and replays what it missed from the event store.
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+var (
+	droppedAttrsDropOldest = metric.WithAttributes(attribute.String("policy", string(OverflowDropOldest)))
+	droppedAttrsDisconnect = metric.WithAttributes(attribute.String("policy", string(OverflowDisconnect)))
+)
+
+// SetOverflowPolicy sets how the hub treats slow clients. It must be called
+// before Run; the default is OverflowDropOldest.
+func (h *Hub) SetOverflowPolicy(policy OverflowPolicy) {
+	h.overflowPolicy = policy
+}
+
+// send queues serialized for client without blocking, applying the overflow
+// policy if the client's buffer is full. Callers hold h.mu for reading, which
+// keeps removeClient from closing client.Send underneath us.
+func (h *Hub) send(client *Client, serialized SerializedEvent) {
+	select {
+	case client.Send <- serialized:
+		return
+	default:
+	}
+
+	if h.overflowPolicy == OverflowDisconnect {
+		h.eventsDropped.Add(context.Background(), 1, droppedAttrsDisconnect)
+		if client.disconnect() {
+			slog.Warn("sse client too slow, disconnecting", "client_id", client.ID, "user_id", client.UserID, "workspace_id", client.WorkspaceID)
+		}
+		return
+	}
+
+	// Make room by discarding the oldest event. Another broadcast may take
+	// the freed slot first, in which case this event is the one dropped.
+	select {
+	case <-client.Send:
+		h.eventsDropped.Add(context.Background(), 1, droppedAttrsDropOldest)
+	default:
+	}
+	select {
+	case client.Send <- serialized:
+	default:
+		h.eventsDropped.Add(context.Background(), 1, droppedAttrsDropOldest)
+	}
+}
+
+// disconnect ends the client's stream. It reports whether this call closed
+// it, as opposed to an earlier one.
+func (c *Client) disconnect() bool {
+	closed := false
+	c.closeOnce.Do(func() {
+		close(c.Done)
+		closed = true
+	})
+	return closed
+}
+
+// observeBufferOccupancy reports the number of events queued for the most
+// backed-up client on this node.
+func (h *Hub) observeBufferOccupancy(_ context.Context, o metric.Int64Observer) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	highest := 0
+	for _, workspace := range h.workspaces {
+		for _, clients := range workspace {
+			for _, client := range clients {
+				highest = max(highest, len(client.Send))
+			}
+		}
+	}
+	for _, clients := range h.users {
+		for _, client := range clients {
+			highest = max(highest, len(client.Send))
+		}
+	}
+	o.Observe(int64(highest))
+	return nil
+}
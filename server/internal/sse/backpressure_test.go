@@ -0,0 +1,51 @@
+package sse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOverflowPolicy(t *testing.T) {
+	fill := func(hub *Hub, n int) {
+		for i := range n {
+			hub.BroadcastToUser(context.Background(), "ws1", "alice", Event{Type: EventNotification, Data: fmt.Sprintf("event-%d", i)})
+		}
+	}
+
+	t.Run("drop oldest", func(t *testing.T) {
+		hub := NewHub(nil, 0)
+		c := &Client{ID: "c1", UserID: "alice", WorkspaceID: "ws1", Send: make(chan SerializedEvent, 2), Done: make(chan struct{})}
+		hub.addClient(c)
+
+		fill(hub, 3)
+		if frame := receive(t, c); !strings.Contains(frame, "event-1") {
+			t.Errorf("first queued event = %q, want event-0 dropped", frame)
+		}
+		if frame := receive(t, c); !strings.Contains(frame, "event-2") {
+			t.Errorf("second queued event = %q, want the newest", frame)
+		}
+		select {
+		case <-c.Done:
+			t.Error("a slow client should keep its stream under drop_oldest")
+		default:
+		}
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		hub := NewHub(nil, 0)
+		hub.SetOverflowPolicy(OverflowDisconnect)
+		c := &Client{ID: "c1", UserID: "alice", WorkspaceID: "ws1", Send: make(chan SerializedEvent, 2), Done: make(chan struct{})}
+		hub.addClient(c)
+
+		fill(hub, 4)
+		select {
+		case <-c.Done:
+		default:
+			t.Fatal("expected the slow client to be disconnected")
+		}
+		// Disconnecting again, e.g. on a ban, must not panic
+		hub.DisconnectUserClients("ws1", "alice")
+	})
+}
@@ -0,0 +1,164 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// BroadcastBackend relays broadcasts between server instances. Each node
+// delivers events to its own SSE clients and publishes them to the backend so
+// the other nodes can do the same. Without a backend the hub only reaches
+// clients connected to this process.
+type BroadcastBackend interface {
+	// Publish sends msg to every node subscribed to the backend.
+	Publish(ctx context.Context, msg []byte) error
+
+	// Subscribe calls handle for each published message until ctx is done or
+	// the subscription fails. Messages published by this node are included.
+	Subscribe(ctx context.Context, handle func(msg []byte)) error
+
+	Close() error
+}
+
+// Kinds of message relayed through the backend.
+const (
+	relayWorkspace  = "workspace"
+	relayChannel    = "channel"
+	relayUser       = "user"
+	relayMembers    = "members"    // channel membership changed; drop the cached member set
+	relayDisconnect = "disconnect" // close a user's connections, e.g. after a ban
+)
+
+// relayMessage is the wire format between nodes. Frame carries the
+// serialized SSE frame so receivers do not re-encode the event.
+type relayMessage struct {
+	Node        string `json:"node"`
+	Kind        string `json:"kind"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	ChannelID   string `json:"channel_id,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	Frame       []byte `json:"frame,omitempty"`
+}
+
+const (
+	publishTimeout        = 5 * time.Second
+	resubscribeMinBackoff = time.Second
+	resubscribeMaxBackoff = 30 * time.Second
+)
+
+// SetBackend makes the hub relay broadcasts through backend. It must be
+// called before Run. The hub closes the backend when Run's context ends.
+func (h *Hub) SetBackend(backend BroadcastBackend) {
+	h.backend = backend
+}
+
+// relay queues msg for publishing. Like event storage, publishing happens on
+// a background goroutine so broadcast callers never wait on the network.
+func (h *Hub) relay(msg relayMessage) {
+	if h.backend == nil {
+		return
+	}
+	msg.Node = h.nodeID
+	select {
+	case h.publishQueue <- msg:
+	default:
+		slog.Error("sse publish queue full, dropping relay message", "kind", msg.Kind)
+	}
+}
+
+// runBackend publishes queued relay messages and applies messages from other
+// nodes. Runs as a background goroutine started by Hub.Run.
+func (h *Hub) runBackend(ctx context.Context) {
+	defer func() {
+		if err := h.backend.Close(); err != nil {
+			slog.Error("failed to close sse broadcast backend", "error", err)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.runSubscribeLoop(ctx)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			<-done
+			return
+		case msg := <-h.publishQueue:
+			h.publish(ctx, msg)
+		}
+	}
+}
+
+func (h *Hub) publish(ctx context.Context, msg relayMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		slog.Error("failed to marshal sse relay message", "kind", msg.Kind, "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	if err := h.backend.Publish(ctx, data); err != nil {
+		// Clients on other nodes miss this event; they catch up from
+		// workspace_events when they reconnect.
+		slog.Error("failed to publish sse relay message", "kind", msg.Kind, "error", err)
+	}
+}
+
+// runSubscribeLoop keeps a subscription open, retrying with backoff if the
+// backend drops it.
+func (h *Hub) runSubscribeLoop(ctx context.Context) {
+	backoff := resubscribeMinBackoff
+	for {
+		started := time.Now()
+		err := h.backend.Subscribe(ctx, h.handleRelay)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > resubscribeMaxBackoff {
+			backoff = resubscribeMinBackoff
+		}
+		slog.Error("sse broadcast subscription lost, retrying", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, resubscribeMaxBackoff)
+	}
+}
+
+// handleRelay applies a message published by another node. Events are only
+// delivered locally: the publishing node already stored them.
+func (h *Hub) handleRelay(data []byte) {
+	var msg relayMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		slog.Error("failed to unmarshal sse relay message", "error", err)
+		return
+	}
+	if msg.Node == h.nodeID {
+		return
+	}
+
+	event := SerializedEvent{Frame: msg.Frame}
+	switch msg.Kind {
+	case relayWorkspace:
+		h.deliverToWorkspace(msg.WorkspaceID, event)
+	case relayChannel:
+		h.deliverToChannel(msg.WorkspaceID, msg.ChannelID, event)
+	case relayUser:
+		h.deliverToUser(msg.WorkspaceID, msg.UserID, event)
+	case relayMembers:
+		h.mu.Lock()
+		delete(h.channelMembers, msg.ChannelID)
+		h.mu.Unlock()
+	case relayDisconnect:
+		h.disconnectLocalClients(msg.WorkspaceID, msg.UserID)
+	default:
+		slog.Warn("unknown sse relay message kind", "kind", msg.Kind)
+	}
+}
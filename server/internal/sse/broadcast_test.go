@@ -0,0 +1,156 @@
+package sse
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBus connects the backends of several hubs in the same process.
+type testBus struct {
+	mu   sync.Mutex
+	subs []func([]byte)
+}
+
+type testBackend struct {
+	bus *testBus
+}
+
+func (b *testBackend) Publish(ctx context.Context, msg []byte) error {
+	b.bus.mu.Lock()
+	subs := append([]func([]byte){}, b.bus.subs...)
+	b.bus.mu.Unlock()
+	for _, handle := range subs {
+		handle(msg)
+	}
+	return nil
+}
+
+func (b *testBackend) Subscribe(ctx context.Context, handle func([]byte)) error {
+	b.bus.mu.Lock()
+	b.bus.subs = append(b.bus.subs, handle)
+	b.bus.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+func (b *testBackend) Close() error { return nil }
+
+// startRelayedHubs starts n hubs sharing one bus and waits until every hub
+// is subscribed.
+func startRelayedHubs(t *testing.T, n int) []*Hub {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	bus := &testBus{}
+	hubs := make([]*Hub, n)
+	for i := range hubs {
+		hubs[i] = NewHub(nil, 0)
+		hubs[i].SetBackend(&testBackend{bus: bus})
+		go hubs[i].Run(ctx)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		bus.mu.Lock()
+		subscribed := len(bus.subs)
+		bus.mu.Unlock()
+		if subscribed == n {
+			return hubs
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d of %d hubs subscribed", subscribed, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func testClient(id, workspaceID, userID string) *Client {
+	return &Client{ID: id, UserID: userID, WorkspaceID: workspaceID, Send: make(chan SerializedEvent, 16), Done: make(chan struct{})}
+}
+
+// receive returns the next non-presence event sent to c, or fails.
+func receive(t *testing.T, c *Client) string {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e := <-c.Send:
+			if frame := string(e.Frame); !strings.Contains(frame, `"type":"`+EventPresenceChanged+`"`) {
+				return frame
+			}
+		case <-timeout:
+			t.Fatalf("client %s received nothing", c.ID)
+			return ""
+		}
+	}
+}
+
+func TestHubRelaysBroadcastsAcrossNodes(t *testing.T) {
+	hubs := startRelayedHubs(t, 2)
+	a, b := hubs[0], hubs[1]
+
+	alice := testClient("c1", "ws1", "alice")
+	bob := testClient("c2", "ws1", "bob")
+	a.addClient(alice)
+	b.addClient(bob)
+	// Seed each node's member cache as if loaded from the shared database;
+	// UpdateChannelMembers would relay an invalidation to the other node.
+	for _, h := range hubs {
+		h.mu.Lock()
+		h.channelMembers["ch1"] = map[string]bool{"alice": true, "bob": true}
+		h.mu.Unlock()
+	}
+
+	a.BroadcastToUser(context.Background(), "ws1", "bob", Event{Type: EventNotification, Data: "for bob"})
+	if frame := receive(t, bob); !strings.Contains(frame, "for bob") {
+		t.Errorf("expected bob to receive the user event, got %q", frame)
+	}
+
+	a.BroadcastToChannel(context.Background(), "ws1", "ch1", Event{Type: EventMessageNew, Data: "hello"})
+	if frame := receive(t, bob); !strings.Contains(frame, "hello") {
+		t.Errorf("expected bob to receive the channel event, got %q", frame)
+	}
+	if frame := receive(t, alice); !strings.Contains(frame, "hello") {
+		t.Errorf("expected alice to receive the channel event once, got %q", frame)
+	}
+	select {
+	case e := <-alice.Send:
+		t.Errorf("expected no echo of the node's own broadcast, got %q", e.Frame)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHubRelaysMembershipAndDisconnects(t *testing.T) {
+	hubs := startRelayedHubs(t, 2)
+	a, b := hubs[0], hubs[1]
+
+	bob := testClient("c2", "ws1", "bob")
+	b.addClient(bob)
+	b.UpdateChannelMembers("ch1", []string{"bob"})
+
+	// Removing bob on node a invalidates node b's cached member set
+	a.RemoveChannelMember("ch1", "bob")
+	deadline := time.Now().Add(time.Second)
+	for {
+		b.mu.RLock()
+		_, cached := b.channelMembers["ch1"]
+		b.mu.RUnlock()
+		if !cached {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected node b to drop its cached channel members")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	a.DisconnectUserClients("ws1", "bob")
+	select {
+	case <-bob.Done:
+	case <-time.After(time.Second):
+		t.Fatal("expected bob's connection on node b to be closed")
+	}
+}
@@ -28,6 +28,10 @@ func NewMessageDeletedEvent(data openapi.MessageDeletedData) Event {
 	return Event{Type: EventMessageDeleted, Data: data}
 }
 
+func NewMessageRestoredEvent(data openapi.MessageWithUser) Event {
+	return Event{Type: EventMessageRestored, Data: data}
+}
+
 func NewReactionAddedEvent(data openapi.Reaction) Event {
 	return Event{Type: EventReactionAdded, Data: data}
 }
@@ -36,6 +40,10 @@ func NewReactionRemovedEvent(data openapi.ReactionRemovedData) Event {
 	return Event{Type: EventReactionRemoved, Data: data}
 }
 
+func NewReactionBatchEvent(data openapi.ReactionBatchData) Event {
+	return Event{Type: EventReactionBatch, Data: data}
+}
+
 func NewChannelCreatedEvent(data openapi.Channel) Event {
 	return Event{Type: EventChannelCreated, Data: data}
 }
@@ -55,6 +63,14 @@ func NewChannelArchivedEvent(data openapi.Channel) Event {
 	return Event{Type: EventChannelArchived, Data: data}
 }
 
+func NewChannelUnarchivedEvent(data openapi.Channel) Event {
+	return Event{Type: EventChannelUnarchived, Data: data}
+}
+
+func NewChannelPurgedEvent(data openapi.ChannelPurgedData) Event {
+	return Event{Type: EventChannelPurged, Data: data}
+}
+
 func NewChannelMemberAddedEvent(data openapi.ChannelMemberData) Event {
 	return Event{Type: EventMemberAdded, Data: data}
 }
@@ -123,6 +139,10 @@ func NewWorkspaceUpdatedEvent(data openapi.Workspace) Event {
 	return Event{Type: EventWorkspaceUpdated, Data: data}
 }
 
+func NewWorkspaceReadEvent(data openapi.WorkspaceReadEventData) Event {
+	return Event{Type: EventWorkspaceRead, Data: data}
+}
+
 func NewScheduledMessageCreatedEvent(data openapi.ScheduledMessage) Event {
 	return Event{Type: EventScheduledMessageCreated, Data: data}
 }
@@ -142,3 +162,75 @@ func NewScheduledMessageSentEvent(data openapi.ScheduledMessageSentData) Event {
 func NewScheduledMessageFailedEvent(data openapi.ScheduledMessageFailedData) Event {
 	return Event{Type: EventScheduledMessageFailed, Data: data}
 }
+
+func NewMessageReadEvent(data openapi.MessageReadData) Event {
+	return Event{Type: EventMessageRead, Data: data}
+}
+
+func NewMessageFinalizedEvent(data openapi.MessageFinalizedData) Event {
+	return Event{Type: EventMessageFinalized, Data: data}
+}
+
+func NewChannelStarredEvent(data openapi.ChannelStarredData) Event {
+	return Event{Type: EventChannelStarred, Data: data}
+}
+
+func NewChannelUnstarredEvent(data openapi.ChannelStarredData) Event {
+	return Event{Type: EventChannelUnstarred, Data: data}
+}
+
+func NewServerRestartingEvent(data openapi.ServerRestartingData) Event {
+	return Event{Type: EventServerRestarting, Data: data}
+}
+
+func NewThreadReadEvent(data openapi.ThreadReadEventData) Event {
+	return Event{Type: EventThreadRead, Data: data}
+}
+
+func NewActivityNewEvent(data openapi.ActivityItem) Event {
+	return Event{Type: EventActivityNew, Data: data}
+}
+
+func NewPollUpdatedEvent(data openapi.Poll) Event {
+	return Event{Type: EventPollUpdated, Data: data}
+}
+
+func NewCallStartedEvent(data openapi.Call) Event {
+	return Event{Type: EventCallStarted, Data: data}
+}
+
+func NewCallUpdatedEvent(data openapi.Call) Event {
+	return Event{Type: EventCallUpdated, Data: data}
+}
+
+func NewCallEndedEvent(data openapi.Call) Event {
+	return Event{Type: EventCallEnded, Data: data}
+}
+
+func NewCallSignalEvent(data openapi.CallSignalData) Event {
+	return Event{Type: EventCallSignal, Data: data}
+}
+
+func NewChannelViewersEvent(data openapi.ChannelViewers) Event {
+	return Event{Type: EventChannelViewers, Data: data}
+}
+
+func NewChannelMembersUpdatedEvent(data openapi.ChannelMembersUpdated) Event {
+	return Event{Type: EventMembersUpdated, Data: data}
+}
+
+func NewWorkspaceMemberAddedEvent(data openapi.WorkspaceMemberData) Event {
+	return Event{Type: EventWorkspaceMemberAdded, Data: data}
+}
+
+func NewWorkspaceMemberRemovedEvent(data openapi.WorkspaceMemberData) Event {
+	return Event{Type: EventWorkspaceMemberRemoved, Data: data}
+}
+
+func NewChannelMemberRoleChangedEvent(data openapi.ChannelMemberRoleChangedData) Event {
+	return Event{Type: EventChannelMemberRoleChanged, Data: data}
+}
+
+func NewSettingsUpdatedEvent(data openapi.ClientSettings) Event {
+	return Event{Type: EventSettingsUpdated, Data: data}
+}
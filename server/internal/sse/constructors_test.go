@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/enzyme/server/internal/openapi"
 )
@@ -20,11 +21,15 @@ func TestConstructorsRoundTrip(t *testing.T) {
 		NewMessageNewEvent(openapi.MessageWithUser{Id: "m1"}),
 		NewMessageUpdatedEvent(openapi.MessageWithUser{Id: "m1"}),
 		NewMessageDeletedEvent(openapi.MessageDeletedData{Id: "m1"}),
+		NewMessageRestoredEvent(openapi.MessageWithUser{Id: "m1"}),
 		NewReactionAddedEvent(openapi.Reaction{Id: "r1"}),
 		NewReactionRemovedEvent(openapi.ReactionRemovedData{MessageId: "m1", UserId: "u1", Emoji: "\U0001f44d"}),
+		NewReactionBatchEvent(openapi.ReactionBatchData{MessageId: "m1", UserId: "u1", Removed: []string{"\U0001f44d"}}),
 		NewChannelCreatedEvent(openapi.Channel{Id: "c1"}),
 		NewChannelUpdatedEvent(openapi.Channel{Id: "c1"}),
 		NewChannelArchivedEvent(openapi.Channel{Id: "c1"}),
+		NewChannelUnarchivedEvent(openapi.Channel{Id: "c1"}),
+		NewChannelPurgedEvent(openapi.ChannelPurgedData{ChannelId: "c1", Before: time.Now(), Count: 3}),
 		NewChannelMemberAddedEvent(openapi.ChannelMemberData{ChannelId: "c1", UserId: "u1"}),
 		NewChannelMemberRemovedEvent(openapi.ChannelMemberData{ChannelId: "c1", UserId: "u1"}),
 		NewChannelReadEvent(openapi.ChannelReadEventData{ChannelId: "c1", LastReadMessageId: "m1"}),
@@ -42,12 +47,30 @@ func TestConstructorsRoundTrip(t *testing.T) {
 		NewMemberLeftEvent(openapi.WorkspaceMemberData{UserId: "u1", WorkspaceId: "w1"}),
 		NewMemberRoleChangedEvent(openapi.MemberRoleChangedData{UserId: "u1", OldRole: "member", NewRole: "admin"}),
 		NewWorkspaceUpdatedEvent(openapi.Workspace{Id: "w1"}),
+		NewWorkspaceReadEvent(openapi.WorkspaceReadEventData{WorkspaceId: "w1", Channels: []openapi.ChannelReadEventData{{ChannelId: "c1", LastReadMessageId: "m1"}}}),
 		NewScheduledMessageCreatedEvent(openapi.ScheduledMessage{Id: "s1"}),
 		NewScheduledMessageUpdatedEvent(openapi.ScheduledMessage{Id: "s1"}),
 		NewScheduledMessageDeletedEvent(openapi.ScheduledMessageDeletedData{Id: "s1"}),
 		NewScheduledMessageSentEvent(openapi.ScheduledMessageSentData{Id: "s1", ChannelId: "c1", MessageId: "m1"}),
 		NewScheduledMessageFailedEvent(openapi.ScheduledMessageFailedData{Id: "s1", ChannelId: "c1", Error: "timeout"}),
 		NewChannelsInvalidateEvent(),
+		NewMessageReadEvent(openapi.MessageReadData{MessageId: "m1", ChannelId: "c1", UserId: "u1"}),
+		NewMessageFinalizedEvent(openapi.MessageFinalizedData{MessageId: "m1", ChannelId: "c1", Message: &openapi.MessageWithUser{Id: "m1"}}),
+		NewChannelStarredEvent(openapi.ChannelStarredData{ChannelId: "c1"}),
+		NewChannelUnstarredEvent(openapi.ChannelStarredData{ChannelId: "c1"}),
+		NewServerRestartingEvent(openapi.ServerRestartingData{ReconnectAfterMs: 1000}),
+		NewThreadReadEvent(openapi.ThreadReadEventData{ThreadParentId: "m1", ChannelId: "c1", LastReadReplyId: "m2"}),
+		NewActivityNewEvent(openapi.ActivityItem{Id: "a1", Type: openapi.ActivityTypeMention, ChannelId: "c1"}),
+		NewPollUpdatedEvent(openapi.Poll{Id: "p1", MessageId: "m1", ChannelId: "c1"}),
+		NewCallStartedEvent(openapi.Call{Id: "call1", ChannelId: "c1"}),
+		NewCallUpdatedEvent(openapi.Call{Id: "call1", ChannelId: "c1"}),
+		NewCallEndedEvent(openapi.Call{Id: "call1", ChannelId: "c1"}),
+		NewCallSignalEvent(openapi.CallSignalData{CallId: "call1", FromUserId: "u1", Type: openapi.CallSignalOffer, Payload: "sdp"}),
+		NewChannelViewersEvent(openapi.ChannelViewers{ChannelId: "c1", UserIds: []string{"u1"}}),
+		NewChannelMembersUpdatedEvent(openapi.ChannelMembersUpdated{ChannelId: "c1", AddedUserIds: []string{"u1"}}),
+		NewWorkspaceMemberAddedEvent(openapi.WorkspaceMemberData{UserId: "u1", WorkspaceId: "w1"}),
+		NewWorkspaceMemberRemovedEvent(openapi.WorkspaceMemberData{UserId: "u1", WorkspaceId: "w1"}),
+		NewChannelMemberRoleChangedEvent(openapi.ChannelMemberRoleChangedData{ChannelId: "c1", UserId: "u1", NewRole: openapi.ChannelRoleAdmin}),
 	}
 
 	for _, e := range events {
@@ -0,0 +1,51 @@
+package sse
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// Clients are told to reconnect after a random delay in this range so a
+// restarting node is not hit by every client at once.
+const (
+	restartReconnectMin = 2 * time.Second
+	restartReconnectMax = 10 * time.Second
+)
+
+// Drain ends every SSE stream on this node and refuses new ones, for use
+// during shutdown. Each open stream is sent a server.restarting event with a
+// suggested reconnect delay before it closes, so clients back off instead of
+// reconnecting immediately. Drain does not wait for the streams to finish;
+// http.Server.Shutdown does that along with other in-flight requests.
+func (h *Hub) Drain() {
+	h.drainOnce.Do(func() { close(h.draining) })
+}
+
+// IsDraining reports whether Drain has been called.
+func (h *Hub) IsDraining() bool {
+	select {
+	case <-h.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnectDelay picks a suggested reconnect delay for one client.
+func reconnectDelay() time.Duration {
+	return restartReconnectMin + rand.N(restartReconnectMax-restartReconnectMin)
+}
+
+// writeRestarting sends a server.restarting event. The SSE retry field makes
+// browsers' EventSource wait the same delay before reconnecting.
+func (h *Handler) writeRestarting(w http.ResponseWriter, flusher http.Flusher) {
+	delay := reconnectDelay()
+	_, _ = fmt.Fprintf(w, "retry: %d\n", delay.Milliseconds())
+	h.writeLocalEvent(w, flusher, NewServerRestartingEvent(openapi.ServerRestartingData{
+		ReconnectAfterMs: int(delay.Milliseconds()),
+	}))
+}
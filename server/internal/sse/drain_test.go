@@ -0,0 +1,90 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/auth"
+	"github.com/enzyme/server/internal/channel"
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/enzyme/server/internal/workspace"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestDrainEndsStreamsAndRefusesNewOnes(t *testing.T) {
+	db := testutil.TestDB(t)
+	user := testutil.CreateTestUser(t, db, "test@example.com", "Test")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test Workspace")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	hub := NewHub(db, time.Hour)
+	go hub.Run(ctx)
+
+	h := NewHandler(hub, workspace.NewRepository(db), channel.NewRepository(db), time.Minute, 16, 0)
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), user.ID)))
+		})
+	})
+	r.Get("/workspaces/{wid}/events", h.Events)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/workspaces/" + ws.ID + "/events")
+	if err != nil {
+		t.Fatalf("opening stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Wait for the connected event before draining
+	reader := bufio.NewReader(resp.Body)
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, "id:") {
+		t.Fatalf("reading first frame: %q, %v", line, err)
+	}
+
+	hub.Drain()
+
+	var rest strings.Builder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 4096)
+		for {
+			n, err := reader.Read(buf)
+			rest.Write(buf[:n])
+			if err != nil {
+				return
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream was not closed after Drain")
+	}
+	if !strings.Contains(rest.String(), `"type":"`+EventServerRestarting+`"`) {
+		t.Errorf("expected a server.restarting event, got %q", rest.String())
+	}
+	if !strings.Contains(rest.String(), "retry: ") {
+		t.Errorf("expected an SSE retry field, got %q", rest.String())
+	}
+
+	resp2, err := http.Get(srv.URL + "/workspaces/" + ws.ID + "/events")
+	if err != nil {
+		t.Fatalf("opening second stream: %v", err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503 while draining", resp2.StatusCode)
+	}
+	if resp2.Header.Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+}
@@ -1,10 +1,12 @@
 package sse
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/enzyme/server/internal/logging"
 	"github.com/enzyme/server/internal/openapi"
 	"github.com/oklog/ulid/v2"
 )
@@ -13,26 +15,30 @@ import (
 // Using string() on the generated constants ensures compile-time linkage:
 // if the spec changes, the generated type changes, and these still track it.
 const (
-	EventConnected       = string(openapi.SSEEventTypeConnected)
-	EventHeartbeat       = string(openapi.SSEEventTypeHeartbeat)
-	EventMessageNew      = string(openapi.SSEEventTypeMessageNew)
-	EventMessageUpdated  = string(openapi.SSEEventTypeMessageUpdated)
-	EventMessageDeleted  = string(openapi.SSEEventTypeMessageDeleted)
-	EventReactionAdded   = string(openapi.SSEEventTypeReactionAdded)
-	EventReactionRemoved = string(openapi.SSEEventTypeReactionRemoved)
-	EventChannelCreated  = string(openapi.SSEEventTypeChannelCreated)
-	EventChannelUpdated  = string(openapi.SSEEventTypeChannelUpdated)
-	EventChannelArchived = string(openapi.SSEEventTypeChannelArchived)
-	EventMemberAdded     = string(openapi.SSEEventTypeChannelMemberAdded)
-	EventMemberRemoved   = string(openapi.SSEEventTypeChannelMemberRemoved)
-	EventChannelRead     = string(openapi.SSEEventTypeChannelRead)
-	EventTypingStart     = string(openapi.SSEEventTypeTypingStart)
-	EventTypingStop      = string(openapi.SSEEventTypeTypingStop)
-	EventPresenceChanged = string(openapi.SSEEventTypePresenceChanged)
-	EventPresenceInitial = string(openapi.SSEEventTypePresenceInitial)
-	EventNotification    = string(openapi.SSEEventTypeNotification)
-	EventEmojiCreated    = string(openapi.SSEEventTypeEmojiCreated)
-	EventEmojiDeleted    = string(openapi.SSEEventTypeEmojiDeleted)
+	EventConnected         = string(openapi.SSEEventTypeConnected)
+	EventHeartbeat         = string(openapi.SSEEventTypeHeartbeat)
+	EventMessageNew        = string(openapi.SSEEventTypeMessageNew)
+	EventMessageUpdated    = string(openapi.SSEEventTypeMessageUpdated)
+	EventMessageDeleted    = string(openapi.SSEEventTypeMessageDeleted)
+	EventMessageRestored   = string(openapi.SSEEventTypeMessageRestored)
+	EventReactionAdded     = string(openapi.SSEEventTypeReactionAdded)
+	EventReactionRemoved   = string(openapi.SSEEventTypeReactionRemoved)
+	EventReactionBatch     = string(openapi.SSEEventTypeReactionBatch)
+	EventChannelCreated    = string(openapi.SSEEventTypeChannelCreated)
+	EventChannelUpdated    = string(openapi.SSEEventTypeChannelUpdated)
+	EventChannelArchived   = string(openapi.SSEEventTypeChannelArchived)
+	EventChannelUnarchived = string(openapi.SSEEventTypeChannelUnarchived)
+	EventChannelPurged     = string(openapi.SSEEventTypeChannelPurged)
+	EventMemberAdded       = string(openapi.SSEEventTypeChannelMemberAdded)
+	EventMemberRemoved     = string(openapi.SSEEventTypeChannelMemberRemoved)
+	EventChannelRead       = string(openapi.SSEEventTypeChannelRead)
+	EventTypingStart       = string(openapi.SSEEventTypeTypingStart)
+	EventTypingStop        = string(openapi.SSEEventTypeTypingStop)
+	EventPresenceChanged   = string(openapi.SSEEventTypePresenceChanged)
+	EventPresenceInitial   = string(openapi.SSEEventTypePresenceInitial)
+	EventNotification      = string(openapi.SSEEventTypeNotification)
+	EventEmojiCreated      = string(openapi.SSEEventTypeEmojiCreated)
+	EventEmojiDeleted      = string(openapi.SSEEventTypeEmojiDeleted)
 
 	EventMessagePinned     = string(openapi.SSEEventTypeMessagePinned)
 	EventMessageUnpinned   = string(openapi.SSEEventTypeMessageUnpinned)
@@ -42,6 +48,7 @@ const (
 	EventMemberRoleChanged = string(openapi.SSEEventTypeMemberRoleChanged)
 
 	EventWorkspaceUpdated   = string(openapi.SSEEventTypeWorkspaceUpdated)
+	EventWorkspaceRead      = string(openapi.SSEEventTypeWorkspaceRead)
 	EventChannelsInvalidate = string(openapi.SSEEventTypeChannelsInvalidate)
 
 	EventScheduledMessageCreated = string(openapi.SSEEventTypeScheduledMessageCreated)
@@ -49,12 +56,51 @@ const (
 	EventScheduledMessageDeleted = string(openapi.SSEEventTypeScheduledMessageDeleted)
 	EventScheduledMessageSent    = string(openapi.SSEEventTypeScheduledMessageSent)
 	EventScheduledMessageFailed  = string(openapi.SSEEventTypeScheduledMessageFailed)
+
+	EventMessageRead      = string(openapi.SSEEventTypeMessageRead)
+	EventMessageFinalized = string(openapi.SSEEventTypeMessageFinalized)
+
+	EventChannelStarred   = string(openapi.SSEEventTypeChannelStarred)
+	EventChannelUnstarred = string(openapi.SSEEventTypeChannelUnstarred)
+
+	EventServerRestarting = string(openapi.SSEEventTypeServerRestarting)
+
+	EventThreadRead = string(openapi.SSEEventTypeThreadRead)
+
+	EventActivityNew = string(openapi.SSEEventTypeActivityNew)
+
+	EventPollUpdated = string(openapi.SSEEventTypePollUpdated)
+
+	EventCallStarted = string(openapi.SSEEventTypeCallStarted)
+	EventCallUpdated = string(openapi.SSEEventTypeCallUpdated)
+	EventCallEnded   = string(openapi.SSEEventTypeCallEnded)
+	EventCallSignal  = string(openapi.SSEEventTypeCallSignal)
+
+	EventChannelViewers = string(openapi.SSEEventTypeChannelViewers)
+
+	EventMembersUpdated = string(openapi.SSEEventTypeChannelMembersUpdated)
+
+	EventWorkspaceMemberAdded     = string(openapi.SSEEventTypeMemberAdded)
+	EventWorkspaceMemberRemoved   = string(openapi.SSEEventTypeMemberRemoved)
+	EventChannelMemberRoleChanged = string(openapi.SSEEventTypeChannelMemberRoleChanged)
+
+	EventSettingsUpdated = string(openapi.SSEEventTypeSettingsUpdated)
 )
 
 type Event struct {
 	ID   string      `json:"id,omitempty"`
 	Type string      `json:"type"`
 	Data interface{} `json:"data,omitempty"`
+	// RequestID is the API request that caused the event, for correlating
+	// client behaviour with server logs.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// tagRequest sets RequestID from ctx unless the event already has one.
+func (e *Event) tagRequest(ctx context.Context) {
+	if e.RequestID == "" {
+		e.RequestID = logging.RequestID(ctx)
+	}
 }
 
 // SerializedEvent is a pre-formatted SSE frame ready for writing to clients.
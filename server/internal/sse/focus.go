@@ -0,0 +1,104 @@
+package sse
+
+import (
+	"context"
+	"slices"
+
+	"github.com/enzyme/server/internal/openapi"
+)
+
+// FocusChannel records whether a client has channelID on screen. A client
+// views one channel at a time, so focusing a channel moves it off the one it
+// was viewing before. Members of each channel whose viewers changed receive a
+// channel.viewers event. Returns false if the client is not connected to
+// this node.
+func (h *Hub) FocusChannel(ctx context.Context, workspaceID, userID, clientID, channelID string, focused bool) bool {
+	h.mu.Lock()
+	client := h.findClient(workspaceID, userID, clientID)
+	if client == nil {
+		h.mu.Unlock()
+		return false
+	}
+	prev := client.focusedChannelID
+	switch {
+	case focused:
+		client.focusedChannelID = channelID
+	case prev == channelID:
+		client.focusedChannelID = ""
+	}
+	next := client.focusedChannelID
+	h.mu.Unlock()
+
+	if prev == next {
+		return true
+	}
+	if prev != "" {
+		h.broadcastViewers(ctx, workspaceID, prev)
+	}
+	if next != "" {
+		h.broadcastViewers(ctx, workspaceID, next)
+	}
+	return true
+}
+
+// ChannelViewers returns the users with a client on this node that has the
+// channel on screen.
+func (h *Hub) ChannelViewers(workspaceID, channelID string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	userIDs := []string{}
+	for userID, clients := range h.workspaces[workspaceID] {
+		for _, c := range clients {
+			if c.focusedChannelID == channelID {
+				userIDs = append(userIDs, userID)
+				break
+			}
+		}
+	}
+	slices.Sort(userIDs)
+	return userIDs
+}
+
+// IsViewingChannel reports whether any of the user's clients has the channel
+// on screen.
+func (h *Hub) IsViewingChannel(workspaceID, userID, channelID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, c := range h.workspaces[workspaceID][userID] {
+		if c.focusedChannelID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// findClient returns the user's client with the given ID. Must be called
+// with h.mu held.
+func (h *Hub) findClient(workspaceID, userID, clientID string) *Client {
+	for _, c := range h.workspaces[workspaceID][userID] {
+		if c.ID == clientID {
+			return c
+		}
+	}
+	return nil
+}
+
+// clearFocus clears a disconnecting client's focus and returns the channel
+// it was viewing.
+func (h *Hub) clearFocus(client *Client) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	channelID := client.focusedChannelID
+	client.focusedChannelID = ""
+	return channelID
+}
+
+func (h *Hub) broadcastViewers(ctx context.Context, workspaceID, channelID string) {
+	h.BroadcastToChannel(ctx, workspaceID, channelID, NewChannelViewersEvent(openapi.ChannelViewers{
+		ChannelId: channelID,
+		UserIds:   h.ChannelViewers(workspaceID, channelID),
+	}))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/enzyme/server/internal/auth"
@@ -21,15 +22,17 @@ type Handler struct {
 	channelRepo       *channel.Repository
 	heartbeatInterval time.Duration
 	clientBufferSize  int
+	writeTimeout      time.Duration // per write to a stream; zero means none
 }
 
-func NewHandler(hub *Hub, workspaceRepo *workspace.Repository, channelRepo *channel.Repository, heartbeatInterval time.Duration, clientBufferSize int) *Handler {
+func NewHandler(hub *Hub, workspaceRepo *workspace.Repository, channelRepo *channel.Repository, heartbeatInterval time.Duration, clientBufferSize int, writeTimeout time.Duration) *Handler {
 	return &Handler{
 		hub:               hub,
 		workspaceRepo:     workspaceRepo,
 		channelRepo:       channelRepo,
 		heartbeatInterval: heartbeatInterval,
 		clientBufferSize:  clientBufferSize,
+		writeTimeout:      writeTimeout,
 	}
 }
 
@@ -37,6 +40,13 @@ func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
 	workspaceID := chi.URLParam(r, "wid")
 	userID := auth.GetUserID(r.Context())
 
+	// Shutting down: send the client elsewhere or back later
+	if h.hub.IsDraining() {
+		w.Header().Set("Retry-After", strconv.Itoa(int(reconnectDelay().Seconds())))
+		writeError(w, http.StatusServiceUnavailable, "SERVER_RESTARTING", "Server is restarting")
+		return
+	}
+
 	// Check workspace membership
 	_, err := h.workspaceRepo.GetMembership(r.Context(), userID, workspaceID)
 	if err != nil {
@@ -48,22 +58,11 @@ func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
-
-	flusher, ok := w.(http.Flusher)
+	flusher, ok := startStream(w)
 	if !ok {
-		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
 		return
 	}
 
-	// Disable write deadline for SSE (otherwise server's WriteTimeout kills the connection)
-	rc := http.NewResponseController(w)
-	_ = rc.SetWriteDeadline(time.Time{}) // Zero time = no deadline
-
 	// Create client
 	client := &Client{
 		ID:          ulid.Make().String(),
@@ -97,9 +96,67 @@ func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Start heartbeat
+	h.serve(w, r, flusher, client)
+}
+
+// UserEvents streams the user's DM activity from every workspace. Unlike
+// Events it sends no presence and replays nothing on reconnect.
+func (h *Handler) UserEvents(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserID(r.Context())
+
+	if h.hub.IsDraining() {
+		w.Header().Set("Retry-After", strconv.Itoa(int(reconnectDelay().Seconds())))
+		writeError(w, http.StatusServiceUnavailable, "SERVER_RESTARTING", "Server is restarting")
+		return
+	}
+
+	flusher, ok := startStream(w)
+	if !ok {
+		return
+	}
+
+	client := &Client{
+		ID:     ulid.Make().String(),
+		UserID: userID,
+		Send:   make(chan SerializedEvent, h.clientBufferSize),
+		Done:   make(chan struct{}),
+	}
+
+	h.hub.Register(client)
+	defer h.hub.Unregister(client)
+
+	h.writeLocalEvent(w, flusher, NewConnectedEvent(openapi.ConnectedData{ClientId: client.ID}))
+	h.serve(w, r, flusher, client)
+}
+
+// startStream sets the SSE response headers. It writes an error and returns
+// false if the response can't be streamed.
+func startStream(w http.ResponseWriter) (http.Flusher, bool) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	// Disable write deadline for SSE (otherwise server's WriteTimeout kills the connection)
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{}) // Zero time = no deadline
+	return flusher, true
+}
+
+// serve writes the client's events and heartbeats until the request ends,
+// the client is disconnected or the server starts draining. A client that
+// can't take a write within the write timeout is dropped, so a stalled
+// connection doesn't hold its buffer forever.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, flusher http.Flusher, client *Client) {
 	heartbeat := time.NewTicker(h.heartbeatInterval)
 	defer heartbeat.Stop()
+	rc := http.NewResponseController(w)
 
 	for {
 		select {
@@ -107,18 +164,35 @@ func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
 			return
 		case <-client.Done:
 			return
+		case <-h.hub.draining:
+			h.writeRestarting(w, flusher)
+			return
 		case event := <-client.Send:
+			h.extendWriteDeadline(rc)
 			if err := h.writeSerializedEvent(w, event); err != nil {
 				return
 			}
 			// Drain any pending events before flushing (batch flush)
-			h.drainAndFlush(w, flusher, client)
+			if err := h.drainAndFlush(w, rc, client); err != nil {
+				return
+			}
 		case <-heartbeat.C:
-			h.writeLocalEvent(w, flusher, NewHeartbeatEvent(openapi.HeartbeatData{Timestamp: time.Now().Unix()}))
+			h.extendWriteDeadline(rc)
+			if err := h.writeLocalEvent(w, flusher, NewHeartbeatEvent(openapi.HeartbeatData{Timestamp: time.Now().Unix()})); err != nil {
+				return
+			}
 		}
 	}
 }
 
+// extendWriteDeadline gives the next write and flush the write timeout to
+// complete
+func (h *Handler) extendWriteDeadline(rc *http.ResponseController) {
+	if h.writeTimeout > 0 {
+		_ = rc.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+	}
+}
+
 // writeSerializedEvent writes a pre-formatted SSE frame to the response without flushing.
 // The caller is responsible for flushing (enables batch flush).
 func (h *Handler) writeSerializedEvent(w http.ResponseWriter, event SerializedEvent) error {
@@ -128,32 +202,35 @@ func (h *Handler) writeSerializedEvent(w http.ResponseWriter, event SerializedEv
 
 // drainAndFlush drains pending events from the client channel and flushes once.
 // Capped to avoid unbounded draining if events arrive faster than writes.
-func (h *Handler) drainAndFlush(w http.ResponseWriter, flusher http.Flusher, client *Client) {
+func (h *Handler) drainAndFlush(w http.ResponseWriter, rc *http.ResponseController, client *Client) error {
 	const maxDrain = 64
 	for range maxDrain {
 		select {
 		case event := <-client.Send:
 			if err := h.writeSerializedEvent(w, event); err != nil {
-				return
+				return err
 			}
 		default:
-			flusher.Flush()
-			return
+			return rc.Flush()
 		}
 	}
-	flusher.Flush()
+	return rc.Flush()
 }
 
 // writeLocalEvent serializes and writes an event generated locally (not from broadcast).
 // Used for connected, heartbeat, presence_initial, and reconnection replay events.
-func (h *Handler) writeLocalEvent(w http.ResponseWriter, flusher http.Flusher, event Event) {
+// It returns the write error, if any; a failed serialization is only logged.
+func (h *Handler) writeLocalEvent(w http.ResponseWriter, flusher http.Flusher, event Event) error {
 	serialized, err := event.Serialize()
 	if err != nil {
 		slog.Error("failed to serialize local SSE event", "type", event.Type, "error", err)
-		return
+		return nil
+	}
+	if err := h.writeSerializedEvent(w, serialized); err != nil {
+		return err
 	}
-	_ = h.writeSerializedEvent(w, serialized)
 	flusher.Flush()
+	return nil
 }
 
 type TypingInput struct {
@@ -226,7 +303,7 @@ func (h *Handler) StartTyping(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.hub.BroadcastToChannel(workspaceID, input.ChannelID, NewTypingStartEvent(openapi.TypingEventData{
+	h.hub.BroadcastToChannel(r.Context(), workspaceID, input.ChannelID, NewTypingStartEvent(openapi.TypingEventData{
 		UserId:    userID,
 		ChannelId: input.ChannelID,
 	}))
@@ -245,7 +322,7 @@ func (h *Handler) StopTyping(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.hub.BroadcastToChannel(workspaceID, input.ChannelID, NewTypingStopEvent(openapi.TypingEventData{
+	h.hub.BroadcastToChannel(r.Context(), workspaceID, input.ChannelID, NewTypingStopEvent(openapi.TypingEventData{
 		UserId:    userID,
 		ChannelId: input.ChannelID,
 	}))
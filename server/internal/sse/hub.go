@@ -9,17 +9,25 @@ import (
 	"time"
 
 	"github.com/enzyme/server/internal/openapi"
+	"github.com/oklog/ulid/v2"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
 type Client struct {
-	ID          string
-	UserID      string
+	ID     string
+	UserID string
+	// WorkspaceID is empty for a user-scoped client, which follows the
+	// user's direct messages in every workspace
 	WorkspaceID string
 	Send        chan SerializedEvent
 	Done        chan struct{}
+
+	// Channel the client has on screen, if any. Guarded by Hub.mu.
+	focusedChannelID string
+
+	closeOnce sync.Once
 }
 
 type Hub struct {
@@ -28,9 +36,16 @@ type Hub struct {
 	// workspaceID -> userID -> []*Client
 	workspaces map[string]map[string][]*Client
 
+	// userID -> []*Client for user-scoped streams
+	users map[string][]*Client
+
 	// channelID -> set of userIDs (for scoped broadcasts)
 	channelMembers map[string]map[string]bool
 
+	// channelID -> whether it is a DM or group DM. A channel's type never
+	// changes, so entries are never invalidated.
+	dmChannels map[string]bool
+
 	db *sql.DB
 
 	retention time.Duration
@@ -42,9 +57,23 @@ type Hub struct {
 	// so broadcast callers never block on DB writes.
 	storeQueue chan storeRequest
 
+	// Optional cross-node relay; nil keeps broadcasts in this process.
+	// nodeID tells this hub's own messages apart when they come back.
+	backend      BroadcastBackend
+	nodeID       string
+	publishQueue chan relayMessage
+
+	// Closed by Drain to end every stream on this node
+	draining  chan struct{}
+	drainOnce sync.Once
+
+	// What to do with a client whose buffer is full
+	overflowPolicy OverflowPolicy
+
 	// OTel metrics (no-op when telemetry is disabled)
 	connectionsActive metric.Int64UpDownCounter
 	eventsBroadcast   metric.Int64Counter
+	eventsDropped     metric.Int64Counter
 }
 
 type storeRequest struct {
@@ -74,22 +103,46 @@ func NewHub(db *sql.DB, retention time.Duration) *Hub {
 	if err != nil {
 		slog.Error("failed to create sse.events.broadcast metric", "error", err)
 	}
+	eventsDropped, err := meter.Int64Counter("sse.events.dropped",
+		metric.WithDescription("SSE events dropped because a client's buffer was full"),
+	)
+	if err != nil {
+		slog.Error("failed to create sse.events.dropped metric", "error", err)
+	}
 
-	return &Hub{
+	h := &Hub{
 		workspaces:        make(map[string]map[string][]*Client),
+		users:             make(map[string][]*Client),
 		channelMembers:    make(map[string]map[string]bool),
+		dmChannels:        make(map[string]bool),
 		db:                db,
 		retention:         retention,
 		register:          make(chan *Client, 256),
 		unregister:        make(chan *Client, 256),
 		storeQueue:        make(chan storeRequest, 1024),
+		nodeID:            ulid.Make().String(),
+		publishQueue:      make(chan relayMessage, 1024),
+		draining:          make(chan struct{}),
+		overflowPolicy:    OverflowDropOldest,
 		connectionsActive: connectionsActive,
 		eventsBroadcast:   eventsBroadcast,
+		eventsDropped:     eventsDropped,
 	}
+
+	if _, err := meter.Int64ObservableGauge("sse.client.buffer.occupancy",
+		metric.WithDescription("Events queued for the most backed-up SSE client"),
+		metric.WithInt64Callback(h.observeBufferOccupancy),
+	); err != nil {
+		slog.Error("failed to create sse.client.buffer.occupancy metric", "error", err)
+	}
+	return h
 }
 
 func (h *Hub) Run(ctx context.Context) {
 	go h.runStoreLoop(ctx)
+	if h.backend != nil {
+		go h.runBackend(ctx)
+	}
 
 	for {
 		select {
@@ -99,20 +152,24 @@ func (h *Hub) Run(ctx context.Context) {
 			isFirstConnection := h.addClient(client)
 			if isFirstConnection {
 				// User just came online - broadcast to workspace
-				h.BroadcastToWorkspace(client.WorkspaceID, NewPresenceChangedEvent(openapi.PresenceData{
+				h.BroadcastToWorkspace(ctx, client.WorkspaceID, NewPresenceChangedEvent(openapi.PresenceData{
 					UserId: client.UserID,
 					Status: openapi.Online,
 				}))
 			}
 		case client := <-h.unregister:
+			focused := h.clearFocus(client)
 			isLastConnection := h.removeClient(client)
 			if isLastConnection {
 				// User just went offline - broadcast to workspace
-				h.BroadcastToWorkspace(client.WorkspaceID, NewPresenceChangedEvent(openapi.PresenceData{
+				h.BroadcastToWorkspace(ctx, client.WorkspaceID, NewPresenceChangedEvent(openapi.PresenceData{
 					UserId: client.UserID,
 					Status: openapi.Offline,
 				}))
 			}
+			if focused != "" {
+				h.broadcastViewers(ctx, client.WorkspaceID, focused)
+			}
 		}
 	}
 }
@@ -125,10 +182,17 @@ func (h *Hub) Unregister(client *Client) {
 	h.unregister <- client
 }
 
+// addClient reports whether this is the user's first connection to the
+// workspace. User-scoped clients never count, as they carry no presence.
 func (h *Hub) addClient(client *Client) bool {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if client.WorkspaceID == "" {
+		h.users[client.UserID] = append(h.users[client.UserID], client)
+		h.connectionsActive.Add(context.Background(), 1)
+		return false
+	}
 	if h.workspaces[client.WorkspaceID] == nil {
 		h.workspaces[client.WorkspaceID] = make(map[string][]*Client)
 	}
@@ -143,7 +207,18 @@ func (h *Hub) removeClient(client *Client) bool {
 	defer h.mu.Unlock()
 
 	isLast := false
-	if workspace, ok := h.workspaces[client.WorkspaceID]; ok {
+	if client.WorkspaceID == "" {
+		clients := h.users[client.UserID]
+		for i, c := range clients {
+			if c.ID == client.ID {
+				h.users[client.UserID] = append(clients[:i], clients[i+1:]...)
+				break
+			}
+		}
+		if len(h.users[client.UserID]) == 0 {
+			delete(h.users, client.UserID)
+		}
+	} else if workspace, ok := h.workspaces[client.WorkspaceID]; ok {
 		if clients, ok := workspace[client.UserID]; ok {
 			for i, c := range clients {
 				if c.ID == client.ID {
@@ -166,8 +241,11 @@ func (h *Hub) removeClient(client *Client) bool {
 	return isLast
 }
 
-func (h *Hub) BroadcastToWorkspace(workspaceID string, event Event) {
-	h.eventsBroadcast.Add(context.Background(), 1, broadcastAttrsWorkspace)
+// BroadcastToWorkspace sends event to every client in the workspace. The
+// request ID carried by ctx, if any, is attached to the event.
+func (h *Hub) BroadcastToWorkspace(ctx context.Context, workspaceID string, event Event) {
+	h.eventsBroadcast.Add(ctx, 1, broadcastAttrsWorkspace)
+	event.tagRequest(ctx)
 
 	// Pre-serialize once for all subscribers (also assigns event ID if empty)
 	serialized, err := event.Serialize()
@@ -179,24 +257,28 @@ func (h *Hub) BroadcastToWorkspace(workspaceID string, event Event) {
 	// Queue event storage asynchronously (no DB I/O on this goroutine)
 	h.enqueueStoreEvent(workspaceID, "", event)
 
+	h.deliverToWorkspace(workspaceID, serialized)
+	h.relay(relayMessage{Kind: relayWorkspace, WorkspaceID: workspaceID, Frame: serialized.Frame})
+}
+
+func (h *Hub) deliverToWorkspace(workspaceID string, serialized SerializedEvent) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
 	if workspace, ok := h.workspaces[workspaceID]; ok {
 		for _, clients := range workspace {
 			for _, client := range clients {
-				select {
-				case client.Send <- serialized:
-				default:
-					// Client buffer full, skip
-				}
+				h.send(client, serialized)
 			}
 		}
 	}
 }
 
-func (h *Hub) BroadcastToChannel(workspaceID, channelID string, event Event) {
-	h.eventsBroadcast.Add(context.Background(), 1, broadcastAttrsChannel)
+// BroadcastToChannel sends event to the channel's members in the workspace.
+// The request ID carried by ctx, if any, is attached to the event.
+func (h *Hub) BroadcastToChannel(ctx context.Context, workspaceID, channelID string, event Event) {
+	h.eventsBroadcast.Add(ctx, 1, broadcastAttrsChannel)
+	event.tagRequest(ctx)
 
 	// Pre-serialize once for all subscribers (also assigns event ID if empty)
 	serialized, err := event.Serialize()
@@ -208,9 +290,15 @@ func (h *Hub) BroadcastToChannel(workspaceID, channelID string, event Event) {
 	// Queue event storage asynchronously (no DB I/O on this goroutine)
 	h.enqueueStoreEvent(workspaceID, channelID, event)
 
+	h.deliverToChannel(workspaceID, channelID, serialized)
+	h.relay(relayMessage{Kind: relayChannel, WorkspaceID: workspaceID, ChannelID: channelID, Frame: serialized.Frame})
+}
+
+func (h *Hub) deliverToChannel(workspaceID, channelID string, serialized SerializedEvent) {
 	// Resolve channel members before taking the broadcast lock.
 	// getChannelMembers manages its own locking internally.
 	members := h.getChannelMembers(channelID)
+	isDM := h.isDMChannel(channelID)
 
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -219,19 +307,51 @@ func (h *Hub) BroadcastToChannel(workspaceID, channelID string, event Event) {
 		for userID, clients := range workspace {
 			if members[userID] {
 				for _, client := range clients {
-					select {
-					case client.Send <- serialized:
-					default:
-						// Client buffer full, skip
-					}
+					h.send(client, serialized)
 				}
 			}
 		}
 	}
+
+	// User-scoped streams follow DMs in every workspace
+	if isDM {
+		for userID := range members {
+			for _, client := range h.users[userID] {
+				h.send(client, serialized)
+			}
+		}
+	}
 }
 
-func (h *Hub) BroadcastToUser(workspaceID, userID string, event Event) {
-	h.eventsBroadcast.Add(context.Background(), 1, broadcastAttrsUser)
+// isDMChannel reports whether the channel is a DM or group DM, loading its
+// type from the database on first use
+func (h *Hub) isDMChannel(channelID string) bool {
+	h.mu.RLock()
+	isDM, ok := h.dmChannels[channelID]
+	h.mu.RUnlock()
+	if ok || h.db == nil {
+		return isDM
+	}
+
+	var channelType string
+	if err := h.db.QueryRow(`SELECT type FROM channels WHERE id = ?`, channelID).Scan(&channelType); err != nil {
+		// Leave it uncached and look again next time
+		return false
+	}
+	isDM = channelType == "dm" || channelType == "group_dm"
+
+	h.mu.Lock()
+	h.dmChannels[channelID] = isDM
+	h.mu.Unlock()
+	return isDM
+}
+
+// BroadcastToUser sends event to the user's clients in the workspace, or to
+// all of their clients, user-scoped streams included, when workspaceID is
+// empty. The request ID carried by ctx, if any, is attached to the event.
+func (h *Hub) BroadcastToUser(ctx context.Context, workspaceID, userID string, event Event) {
+	h.eventsBroadcast.Add(ctx, 1, broadcastAttrsUser)
+	event.tagRequest(ctx)
 
 	// Pre-serialize once for all subscriber connections (also assigns event ID if empty)
 	serialized, err := event.Serialize()
@@ -240,16 +360,29 @@ func (h *Hub) BroadcastToUser(workspaceID, userID string, event Event) {
 		return
 	}
 
+	h.deliverToUser(workspaceID, userID, serialized)
+	h.relay(relayMessage{Kind: relayUser, WorkspaceID: workspaceID, UserID: userID, Frame: serialized.Frame})
+}
+
+func (h *Hub) deliverToUser(workspaceID, userID string, serialized SerializedEvent) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	if workspaceID == "" {
+		for _, workspace := range h.workspaces {
+			for _, client := range workspace[userID] {
+				h.send(client, serialized)
+			}
+		}
+		for _, client := range h.users[userID] {
+			h.send(client, serialized)
+		}
+		return
+	}
 	if workspace, ok := h.workspaces[workspaceID]; ok {
 		if clients, ok := workspace[userID]; ok {
 			for _, client := range clients {
-				select {
-				case client.Send <- serialized:
-				default:
-				}
+				h.send(client, serialized)
 			}
 		}
 	}
@@ -264,16 +397,20 @@ func (h *Hub) UpdateChannelMembers(channelID string, userIDs []string) {
 		members[id] = true
 	}
 	h.channelMembers[channelID] = members
+	h.relay(relayMessage{Kind: relayMembers, ChannelID: channelID})
 }
 
+// AddChannelMember records a new member in the cached member set. A channel
+// whose members are not cached yet is left alone: it is loaded from the
+// database, which already has the member, on first use.
 func (h *Hub) AddChannelMember(channelID, userID string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if h.channelMembers[channelID] == nil {
-		h.channelMembers[channelID] = make(map[string]bool)
+	if members := h.channelMembers[channelID]; members != nil {
+		members[userID] = true
 	}
-	h.channelMembers[channelID][userID] = true
+	h.relay(relayMessage{Kind: relayMembers, ChannelID: channelID})
 }
 
 func (h *Hub) RemoveChannelMember(channelID, userID string) {
@@ -283,6 +420,7 @@ func (h *Hub) RemoveChannelMember(channelID, userID string) {
 	if h.channelMembers[channelID] != nil {
 		delete(h.channelMembers[channelID], userID)
 	}
+	h.relay(relayMessage{Kind: relayMembers, ChannelID: channelID})
 }
 
 func (h *Hub) getChannelMembers(channelID string) map[string]bool {
@@ -497,6 +635,11 @@ func (h *Hub) IsUserOnline(workspaceID, userID string) bool {
 // DisconnectUserClients forcefully disconnects all SSE clients for a user in a workspace.
 // Used when a user is banned to immediately terminate their connections.
 func (h *Hub) DisconnectUserClients(workspaceID, userID string) {
+	h.disconnectLocalClients(workspaceID, userID)
+	h.relay(relayMessage{Kind: relayDisconnect, WorkspaceID: workspaceID, UserID: userID})
+}
+
+func (h *Hub) disconnectLocalClients(workspaceID, userID string) {
 	h.mu.RLock()
 	var clientsToClose []*Client
 	if workspace, ok := h.workspaces[workspaceID]; ok {
@@ -509,11 +652,6 @@ func (h *Hub) DisconnectUserClients(workspaceID, userID string) {
 
 	// Close Done channels outside the lock to trigger disconnect
 	for _, client := range clientsToClose {
-		select {
-		case <-client.Done:
-			// Already closed
-		default:
-			close(client.Done)
-		}
+		client.disconnect()
 	}
 }
@@ -2,9 +2,12 @@ package sse
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/enzyme/server/internal/logging"
+	"github.com/enzyme/server/internal/openapi"
 	"github.com/enzyme/server/internal/testutil"
 	"github.com/oklog/ulid/v2"
 )
@@ -240,3 +243,146 @@ func TestStoreEventChannelID(t *testing.T) {
 		t.Fatalf("channel event channel_id = %s, want %s", *chID, ch.ID)
 	}
 }
+
+func TestBroadcastTagsRequestID(t *testing.T) {
+	hub := NewHub(nil, 0)
+	c := testClient("c1", "ws1", "alice")
+	hub.addClient(c)
+
+	ctx := logging.WithRequestID(context.Background(), "req-123")
+	hub.BroadcastToUser(ctx, "ws1", "alice", Event{Type: EventNotification, Data: "hi"})
+	if frame := receive(t, c); !strings.Contains(frame, `"request_id":"req-123"`) {
+		t.Errorf("expected the event to carry the request ID, got %q", frame)
+	}
+
+	hub.BroadcastToUser(context.Background(), "ws1", "alice", Event{Type: EventNotification, Data: "hi"})
+	if frame := receive(t, c); strings.Contains(frame, "request_id") {
+		t.Errorf("expected no request ID outside a request, got %q", frame)
+	}
+}
+
+func TestFocusChannel(t *testing.T) {
+	hub := NewHub(nil, 0)
+	aliceTab1 := testClient("c1", "ws1", "alice")
+	aliceTab2 := testClient("c2", "ws1", "alice")
+	bob := testClient("c3", "ws1", "bob")
+	for _, c := range []*Client{aliceTab1, aliceTab2, bob} {
+		hub.addClient(c)
+	}
+	hub.UpdateChannelMembers("ch1", []string{"alice", "bob"})
+	ctx := context.Background()
+
+	if hub.FocusChannel(ctx, "ws1", "alice", "unknown", "ch1", true) {
+		t.Error("focusing from an unknown client should fail")
+	}
+	if hub.FocusChannel(ctx, "ws1", "bob", "c1", "ch1", true) {
+		t.Error("focusing from another user's client should fail")
+	}
+
+	if !hub.FocusChannel(ctx, "ws1", "alice", "c1", "ch1", true) {
+		t.Fatal("expected focus to succeed")
+	}
+	if frame := receive(t, bob); !strings.Contains(frame, `"user_ids":["alice"]`) {
+		t.Errorf("expected bob to see alice viewing, got %q", frame)
+	}
+	if !hub.IsViewingChannel("ws1", "alice", "ch1") || hub.IsViewingChannel("ws1", "bob", "ch1") {
+		t.Error("only alice should be viewing ch1")
+	}
+
+	// Blurring a channel the client is not viewing changes nothing
+	hub.FocusChannel(ctx, "ws1", "alice", "c2", "ch1", false)
+	if got := hub.ChannelViewers("ws1", "ch1"); len(got) != 1 {
+		t.Errorf("viewers = %v, want alice still viewing from her first tab", got)
+	}
+
+	// Moving to another channel leaves the first
+	hub.FocusChannel(ctx, "ws1", "alice", "c1", "ch2", true)
+	if got := hub.ChannelViewers("ws1", "ch1"); len(got) != 0 {
+		t.Errorf("ch1 viewers = %v, want none", got)
+	}
+
+	if got := hub.clearFocus(aliceTab1); got != "ch2" {
+		t.Errorf("clearFocus = %q, want ch2", got)
+	}
+	if hub.IsViewingChannel("ws1", "alice", "ch2") {
+		t.Error("alice should no longer be viewing ch2 after disconnecting")
+	}
+}
+
+func TestAddChannelMemberColdCache(t *testing.T) {
+	db := testutil.TestDB(t)
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+
+	hub := NewHub(db, 1*time.Hour)
+
+	// Adding to a channel that isn't cached must not hide existing members
+	hub.AddChannelMember(ch.ID, bob.ID)
+	if members := hub.getChannelMembers(ch.ID); !members[alice.ID] {
+		t.Fatalf("members = %v, want alice loaded from the database", members)
+	}
+
+	hub.AddChannelMember(ch.ID, bob.ID)
+	if members := hub.getChannelMembers(ch.ID); !members[alice.ID] || !members[bob.ID] {
+		t.Fatalf("members = %v, want alice and bob", members)
+	}
+}
+
+func TestUserScopedClientReceivesDMs(t *testing.T) {
+	db := testutil.TestDB(t)
+
+	alice := testutil.CreateTestUser(t, db, "alice@example.com", "Alice")
+	bob := testutil.CreateTestUser(t, db, "bob@example.com", "Bob")
+	ws := testutil.CreateTestWorkspace(t, db, alice.ID, "Test Workspace")
+	dm := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "dm", "dm")
+	general := testutil.CreateTestChannel(t, db, ws.ID, alice.ID, "general", "public")
+
+	hub := NewHub(db, 0)
+	aliceEverywhere := testClient("c1", "", alice.ID)
+	bobEverywhere := testClient("c2", "", bob.ID)
+	if hub.addClient(aliceEverywhere) {
+		t.Error("user-scoped clients should not count towards presence")
+	}
+	hub.addClient(bobEverywhere)
+	ctx := context.Background()
+
+	hub.BroadcastToChannel(ctx, ws.ID, general.ID, NewHeartbeatEvent(openapi.HeartbeatData{Timestamp: 1}))
+	hub.BroadcastToChannel(ctx, ws.ID, dm.ID, NewHeartbeatEvent(openapi.HeartbeatData{Timestamp: 2}))
+	if frame := receive(t, aliceEverywhere); !strings.Contains(frame, `"timestamp":2`) {
+		t.Errorf("frame = %q, want only the DM event", frame)
+	}
+	if len(bobEverywhere.Send) != 0 {
+		t.Error("bob is not in the DM and should receive nothing")
+	}
+
+	if hub.removeClient(aliceEverywhere) {
+		t.Error("user-scoped clients should not count towards presence")
+	}
+	if len(hub.users[alice.ID]) != 0 {
+		t.Error("alice's client should be removed")
+	}
+}
+
+func TestBroadcastToUserEverywhere(t *testing.T) {
+	hub := NewHub(nil, 0)
+	inWorkspace := testClient("c1", "ws1", "alice")
+	inOther := testClient("c2", "ws2", "alice")
+	everywhere := testClient("c3", "", "alice")
+	bob := testClient("c4", "ws1", "bob")
+	for _, c := range []*Client{inWorkspace, inOther, everywhere, bob} {
+		hub.addClient(c)
+	}
+
+	hub.BroadcastToUser(context.Background(), "", "alice", NewHeartbeatEvent(openapi.HeartbeatData{Timestamp: 1}))
+	for _, c := range []*Client{inWorkspace, inOther, everywhere} {
+		if frame := receive(t, c); !strings.Contains(frame, `"timestamp":1`) {
+			t.Errorf("client %s: frame = %q, want the event", c.ID, frame)
+		}
+	}
+	if len(bob.Send) != 0 {
+		t.Error("bob should receive nothing")
+	}
+}
@@ -0,0 +1,66 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend is a BroadcastBackend using Redis pub/sub on a single channel.
+type RedisBackend struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisBackend connects to the Redis server at url (redis:// or rediss://)
+// and fails if it cannot be reached.
+func NewRedisBackend(ctx context.Context, url, channel string) (*RedisBackend, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parsing redis url: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("connecting to redis: %w", err)
+	}
+	return &RedisBackend{client: client, channel: channel}, nil
+}
+
+// Publish implements BroadcastBackend.
+func (b *RedisBackend) Publish(ctx context.Context, msg []byte) error {
+	return b.client.Publish(ctx, b.channel, msg).Err()
+}
+
+// Subscribe implements BroadcastBackend. The client reconnects dropped
+// connections by itself; Subscribe only returns once ctx is done or the
+// subscription is closed.
+func (b *RedisBackend) Subscribe(ctx context.Context, handle func(msg []byte)) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+
+	// Wait for the subscription to be confirmed so errors surface here
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case m, ok := <-ch:
+			if !ok {
+				return errors.New("redis subscription closed")
+			}
+			handle([]byte(m.Payload))
+		}
+	}
+}
+
+// Close implements BroadcastBackend.
+func (b *RedisBackend) Close() error {
+	return b.client.Close()
+}
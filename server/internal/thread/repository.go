@@ -180,6 +180,49 @@ func (r *Repository) CountUnreadThreads(ctx context.Context, workspaceID, userID
 	return count, err
 }
 
+// CountUnreadThreadsByWorkspace counts subscribed threads with new replies for a user,
+// keyed by workspace ID. Workspaces with no unread threads are omitted.
+func (r *Repository) CountUnreadThreadsByWorkspace(ctx context.Context, userID string) (map[string]int, error) {
+	query := `
+		SELECT c.workspace_id, COUNT(DISTINCT ts.thread_parent_id)
+		FROM thread_subscriptions ts
+		JOIN messages m ON m.id = ts.thread_parent_id
+		JOIN channels c ON c.id = m.channel_id
+		WHERE ts.user_id = ?
+		  AND ts.status = 'subscribed'
+		  AND m.deleted_at IS NULL
+		  AND (
+		    ts.last_read_reply_id IS NULL
+		    OR EXISTS (
+		      SELECT 1 FROM messages r
+		      WHERE r.thread_parent_id = m.id
+		        AND r.id > ts.last_read_reply_id
+		        AND r.deleted_at IS NULL
+		      LIMIT 1
+		    )
+		  )
+		  AND m.reply_count > 0
+		GROUP BY c.workspace_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var workspaceID string
+		var count int
+		if err := rows.Scan(&workspaceID, &count); err != nil {
+			return nil, err
+		}
+		counts[workspaceID] = count
+	}
+	return counts, rows.Err()
+}
+
 // GetLatestReplyID returns the ID of the latest reply in a thread
 func (r *Repository) GetLatestReplyID(ctx context.Context, threadParentID string) (string, error) {
 	query := `
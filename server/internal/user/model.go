@@ -4,6 +4,11 @@ import (
 	"time"
 )
 
+const (
+	StatusActive      = "active"
+	StatusDeactivated = "deactivated"
+)
+
 type User struct {
 	ID              string     `json:"id"`
 	Email           string     `json:"email"`
@@ -11,7 +16,12 @@ type User struct {
 	PasswordHash    string     `json:"-"`
 	DisplayName     string     `json:"display_name"`
 	AvatarURL       *string    `json:"avatar_url,omitempty"`
+	Title           *string    `json:"title,omitempty"`
+	Pronouns        *string    `json:"pronouns,omitempty"`
+	Timezone        *string    `json:"timezone,omitempty"`
+	Phone           *string    `json:"phone,omitempty"`
 	Status          string     `json:"status"`
+	IsBot           bool       `json:"is_bot"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
@@ -50,14 +50,14 @@ func (r *Repository) Create(ctx context.Context, input CreateUserInput) (*User,
 
 func (r *Repository) GetByID(ctx context.Context, id string) (*User, error) {
 	return r.scanUser(r.db.QueryRowContext(ctx, `
-		SELECT id, email, email_verified_at, password_hash, display_name, avatar_url, status, created_at, updated_at
+		SELECT id, email, email_verified_at, password_hash, display_name, avatar_url, title, pronouns, timezone, phone, status, is_bot, created_at, updated_at
 		FROM users WHERE id = ?
 	`, id))
 }
 
 func (r *Repository) GetByEmail(ctx context.Context, email string) (*User, error) {
 	return r.scanUser(r.db.QueryRowContext(ctx, `
-		SELECT id, email, email_verified_at, password_hash, display_name, avatar_url, status, created_at, updated_at
+		SELECT id, email, email_verified_at, password_hash, display_name, avatar_url, title, pronouns, timezone, phone, status, is_bot, created_at, updated_at
 		FROM users WHERE email = ?
 	`, email))
 }
@@ -66,9 +66,11 @@ func (r *Repository) Update(ctx context.Context, user *User) error {
 	user.UpdatedAt = time.Now().UTC()
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE users SET
-			email = ?, email_verified_at = ?, display_name = ?, avatar_url = ?, status = ?, updated_at = ?
+			email = ?, email_verified_at = ?, display_name = ?, avatar_url = ?,
+			title = ?, pronouns = ?, timezone = ?, phone = ?, status = ?, updated_at = ?
 		WHERE id = ?
-	`, user.Email, formatNullableTime(user.EmailVerifiedAt), user.DisplayName, user.AvatarURL, user.Status, user.UpdatedAt.Format(time.RFC3339), user.ID)
+	`, user.Email, formatNullableTime(user.EmailVerifiedAt), user.DisplayName, user.AvatarURL,
+		user.Title, user.Pronouns, user.Timezone, user.Phone, user.Status, user.UpdatedAt.Format(time.RFC3339), user.ID)
 	return err
 }
 
@@ -89,7 +91,7 @@ func (r *Repository) VerifyEmail(ctx context.Context, userID string) error {
 
 func (r *Repository) scanUser(row *sql.Row) (*User, error) {
 	var user User
-	var emailVerifiedAt, avatarURL sql.NullString
+	var emailVerifiedAt, avatarURL, title, pronouns, timezone, phone sql.NullString
 	var createdAt, updatedAt string
 
 	err := row.Scan(
@@ -99,7 +101,12 @@ func (r *Repository) scanUser(row *sql.Row) (*User, error) {
 		&user.PasswordHash,
 		&user.DisplayName,
 		&avatarURL,
+		&title,
+		&pronouns,
+		&timezone,
+		&phone,
 		&user.Status,
+		&user.IsBot,
 		&createdAt,
 		&updatedAt,
 	)
@@ -117,6 +124,18 @@ func (r *Repository) scanUser(row *sql.Row) (*User, error) {
 	if avatarURL.Valid {
 		user.AvatarURL = &avatarURL.String
 	}
+	if title.Valid {
+		user.Title = &title.String
+	}
+	if pronouns.Valid {
+		user.Pronouns = &pronouns.String
+	}
+	if timezone.Valid {
+		user.Timezone = &timezone.String
+	}
+	if phone.Valid {
+		user.Phone = &phone.String
+	}
 	user.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	user.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
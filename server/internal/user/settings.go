@@ -0,0 +1,110 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// MaxClientSettingsBytes caps the size of a user's stored client settings.
+const MaxClientSettingsBytes = 64 * 1024
+
+var (
+	ErrClientSettingsConflict = errors.New("client settings changed since they were read")
+	ErrClientSettingsTooLarge = errors.New("client settings too large")
+)
+
+// ClientSettings is an opaque JSON object that clients use to sync
+// preferences such as theme, notification sound or locale between devices.
+// UpdatedAt doubles as its version and is nil until the settings are first
+// saved.
+type ClientSettings struct {
+	Settings  map[string]interface{}
+	UpdatedAt *time.Time
+}
+
+// GetClientSettings returns the user's client settings, or an empty object
+// if they have never saved any.
+func (r *Repository) GetClientSettings(ctx context.Context, userID string) (*ClientSettings, error) {
+	var settings, updatedAt string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT settings, updated_at FROM user_client_settings WHERE user_id = ?
+	`, userID).Scan(&settings, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &ClientSettings{Settings: map[string]interface{}{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseClientSettings(settings, updatedAt)
+}
+
+// PatchClientSettings merges patch into the user's client settings as a JSON
+// merge patch (RFC 7396): keys set to null are removed, objects are merged
+// key by key and anything else replaces the stored value. When expected is
+// set the patch only applies if the settings were last updated at that time;
+// otherwise ErrClientSettingsConflict is returned.
+func (r *Repository) PatchClientSettings(ctx context.Context, userID string, patch map[string]interface{}, expected *time.Time) (_ *ClientSettings, err error) {
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var settings, updatedAt string
+	if expected != nil {
+		err = tx.QueryRowContext(ctx, `
+			UPDATE user_client_settings SET settings = json_patch(settings, ?), updated_at = ?
+			WHERE user_id = ? AND updated_at = ?
+			RETURNING settings, updated_at
+		`, string(patchJSON), now, userID, expected.UTC().Format(time.RFC3339Nano)).Scan(&settings, &updatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrClientSettingsConflict
+		}
+	} else {
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO user_client_settings (user_id, settings, updated_at)
+			VALUES (?, json_patch('{}', ?), ?)
+			ON CONFLICT(user_id) DO UPDATE SET
+				settings = json_patch(user_client_settings.settings, ?),
+				updated_at = excluded.updated_at
+			RETURNING settings, updated_at
+		`, userID, string(patchJSON), now, string(patchJSON)).Scan(&settings, &updatedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) > MaxClientSettingsBytes {
+		err = ErrClientSettingsTooLarge
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return parseClientSettings(settings, updatedAt)
+}
+
+func parseClientSettings(settings, updatedAt string) (*ClientSettings, error) {
+	cs := &ClientSettings{}
+	if err := json.Unmarshal([]byte(settings), &cs.Settings); err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	cs.UpdatedAt = &t
+	return cs, nil
+}
@@ -0,0 +1,33 @@
+package usergroup
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrGroupNotFound = errors.New("user group not found")
+	ErrHandleExists  = errors.New("a user group with this handle already exists")
+)
+
+// Group is a named set of workspace members that can be mentioned together
+// by its handle, e.g. @backend.
+type Group struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Handle      string    `json:"handle"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	CreatedBy   *string   `json:"created_by,omitempty"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Member is a user in a group
+type Member struct {
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   *string   `json:"avatar_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,316 @@
+package usergroup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const groupColumns = `g.id, g.workspace_id, g.handle, g.name, g.description, g.created_by, g.created_at, g.updated_at,
+	(SELECT COUNT(*) FROM user_group_members gm WHERE gm.group_id = g.id)`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// List returns a workspace's groups ordered by handle
+func (r *Repository) List(ctx context.Context, workspaceID string) ([]Group, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+groupColumns+`
+		FROM user_groups g
+		WHERE g.workspace_id = ?
+		ORDER BY g.handle
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *g)
+	}
+	return groups, rows.Err()
+}
+
+// Search returns a workspace's groups whose handle or name starts with
+// query, ordered by handle
+func (r *Repository) Search(ctx context.Context, workspaceID, query string, limit int) ([]Group, error) {
+	prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(strings.TrimSpace(query))) + "%"
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+groupColumns+`
+		FROM user_groups g
+		WHERE g.workspace_id = ? AND (LOWER(g.handle) LIKE ? ESCAPE '\' OR LOWER(g.name) LIKE ? ESCAPE '\')
+		ORDER BY g.handle
+		LIMIT ?
+	`, workspaceID, prefix, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []Group{}
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *g)
+	}
+	return groups, rows.Err()
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*Group, error) {
+	g, err := scanGroup(r.db.QueryRowContext(ctx, `
+		SELECT `+groupColumns+`
+		FROM user_groups g
+		WHERE g.id = ?
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	return g, err
+}
+
+// Create adds a group with its initial members. Members who do not belong
+// to the workspace are skipped.
+func (r *Repository) Create(ctx context.Context, g *Group, memberIDs []string) error {
+	g.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	g.CreatedAt = now
+	g.UpdatedAt = now
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_groups (id, workspace_id, handle, name, description, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, g.ID, g.WorkspaceID, g.Handle, g.Name, g.Description, g.CreatedBy, now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if isUniqueConstraintError(err) {
+		return ErrHandleExists
+	}
+	if err != nil {
+		return err
+	}
+
+	added, err := addMembers(ctx, tx, g.ID, g.WorkspaceID, memberIDs)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	g.MemberCount = added
+	return nil
+}
+
+// Update saves a group's handle, name and description
+func (r *Repository) Update(ctx context.Context, g *Group) error {
+	g.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_groups SET handle = ?, name = ?, description = ?, updated_at = ?
+		WHERE id = ?
+	`, g.Handle, g.Name, g.Description, g.UpdatedAt.Format(time.RFC3339), g.ID)
+	if isUniqueConstraintError(err) {
+		return ErrHandleExists
+	}
+	return err
+}
+
+// Delete removes a group and its memberships
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_groups WHERE id = ?`, id)
+	return err
+}
+
+// ListMembers returns a group's members ordered by display name
+func (r *Repository) ListMembers(ctx context.Context, groupID string) ([]Member, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.display_name, u.avatar_url, gm.created_at
+		FROM user_group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = ?
+		ORDER BY LOWER(u.display_name), u.id
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []Member{}
+	for rows.Next() {
+		var m Member
+		var createdAt string
+		if err := rows.Scan(&m.UserID, &m.DisplayName, &m.AvatarURL, &createdAt); err != nil {
+			return nil, err
+		}
+		m.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// AddMembers adds users to a group, skipping existing members and users who
+// do not belong to the group's workspace. It returns the number added.
+func (r *Repository) AddMembers(ctx context.Context, g *Group, userIDs []string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	added, err := addMembers(ctx, tx, g.ID, g.WorkspaceID, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	if added > 0 {
+		if err := touch(ctx, tx, g.ID); err != nil {
+			return 0, err
+		}
+	}
+	return added, tx.Commit()
+}
+
+// RemoveMembers removes users from a group. It returns the number removed.
+func (r *Repository) RemoveMembers(ctx context.Context, groupID string, userIDs []string) (int, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	placeholders, args := inClause(userIDs)
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM user_group_members WHERE group_id = ? AND user_id IN (`+placeholders+`)
+	`, append([]interface{}{groupID}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if removed > 0 {
+		if err := touch(ctx, tx, groupID); err != nil {
+			return 0, err
+		}
+	}
+	return int(removed), tx.Commit()
+}
+
+// ResolveGroupMembers returns the IDs of the current workspace members of the
+// groups referenced by ID or handle. Handles are matched case-insensitively
+// and unknown references are ignored.
+func (r *Repository) ResolveGroupMembers(ctx context.Context, workspaceID string, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	lowered := make([]string, len(refs))
+	for i, ref := range refs {
+		lowered[i] = strings.ToLower(ref)
+	}
+	idPlaceholders, idArgs := inClause(refs)
+	handlePlaceholders, handleArgs := inClause(lowered)
+
+	args := []interface{}{workspaceID}
+	args = append(args, idArgs...)
+	args = append(args, handleArgs...)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT gm.user_id
+		FROM user_groups g
+		JOIN user_group_members gm ON gm.group_id = g.id
+		JOIN workspace_memberships wm ON wm.user_id = gm.user_id AND wm.workspace_id = g.workspace_id
+		WHERE g.workspace_id = ? AND (g.id IN (`+idPlaceholders+`) OR g.handle IN (`+handlePlaceholders+`))
+		ORDER BY gm.user_id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// addMembers inserts the given workspace members into a group, returning the
+// number of rows added
+func addMembers(ctx context.Context, tx *sql.Tx, groupID, workspaceID string, userIDs []string) (int, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	added := 0
+	for _, userID := range userIDs {
+		result, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO user_group_members (group_id, user_id, created_at)
+			SELECT ?, user_id, ? FROM workspace_memberships WHERE user_id = ? AND workspace_id = ?
+		`, groupID, now, userID, workspaceID)
+		if err != nil {
+			return 0, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		added += int(n)
+	}
+	return added, nil
+}
+
+func touch(ctx context.Context, tx *sql.Tx, groupID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE user_groups SET updated_at = ? WHERE id = ?
+	`, time.Now().UTC().Format(time.RFC3339), groupID)
+	return err
+}
+
+func inClause(values []string) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = "?"
+		args[i] = v
+	}
+	return strings.Join(placeholders, ","), args
+}
+
+func scanGroup(row interface{ Scan(...any) error }) (*Group, error) {
+	var g Group
+	var createdAt, updatedAt string
+	err := row.Scan(&g.ID, &g.WorkspaceID, &g.Handle, &g.Name, &g.Description, &g.CreatedBy, &createdAt, &updatedAt, &g.MemberCount)
+	if err != nil {
+		return nil, err
+	}
+	g.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	g.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &g, nil
+}
+
+func isUniqueConstraintError(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key"))
+}
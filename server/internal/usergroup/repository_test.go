@@ -0,0 +1,134 @@
+package usergroup
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/enzyme/server/internal/testutil"
+	"github.com/oklog/ulid/v2"
+)
+
+func TestRepository_CreateAndMembers(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+	outsider := testutil.CreateTestUser(t, db, "outsider@example.com", "Outsider")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`
+		INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+		VALUES (?, ?, ?, 'member', ?, ?)
+	`, ulid.Make().String(), member.ID, ws.ID, now, now); err != nil {
+		t.Fatalf("adding member: %v", err)
+	}
+
+	g := &Group{WorkspaceID: ws.ID, Handle: "backend", Name: "Backend", CreatedBy: &owner.ID}
+	if err := repo.Create(ctx, g, []string{owner.ID, outsider.ID, owner.ID}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if g.MemberCount != 1 {
+		t.Errorf("member count = %d, want 1 (outsider and duplicate skipped)", g.MemberCount)
+	}
+
+	if err := repo.Create(ctx, &Group{WorkspaceID: ws.ID, Handle: "backend", Name: "Other"}, nil); !errors.Is(err, ErrHandleExists) {
+		t.Errorf("duplicate handle error = %v, want ErrHandleExists", err)
+	}
+
+	added, err := repo.AddMembers(ctx, g, []string{owner.ID, member.ID})
+	if err != nil {
+		t.Fatalf("AddMembers() error = %v", err)
+	}
+	if added != 1 {
+		t.Errorf("added = %d, want 1", added)
+	}
+
+	members, err := repo.ListMembers(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("ListMembers() error = %v", err)
+	}
+	if len(members) != 2 || members[0].DisplayName != "Member" || members[1].DisplayName != "Owner" {
+		t.Errorf("members = %+v, want Member and Owner", members)
+	}
+
+	removed, err := repo.RemoveMembers(ctx, g.ID, []string{member.ID, outsider.ID})
+	if err != nil {
+		t.Fatalf("RemoveMembers() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+
+	got, err := repo.GetByID(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.MemberCount != 1 {
+		t.Errorf("member count = %d, want 1", got.MemberCount)
+	}
+
+	if err := repo.Delete(ctx, g.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByID(ctx, g.ID); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("GetByID() after delete error = %v, want ErrGroupNotFound", err)
+	}
+}
+
+func TestRepository_ResolveGroupMembers(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	former := testutil.CreateTestUser(t, db, "former@example.com", "Former")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Test WS")
+	other := testutil.CreateTestWorkspace(t, db, former.ID, "Other WS")
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := db.Exec(`
+		INSERT INTO workspace_memberships (id, user_id, workspace_id, role, created_at, updated_at)
+		VALUES (?, ?, ?, 'member', ?, ?)
+	`, ulid.Make().String(), former.ID, ws.ID, now, now); err != nil {
+		t.Fatalf("adding member: %v", err)
+	}
+
+	backend := &Group{WorkspaceID: ws.ID, Handle: "backend", Name: "Backend"}
+	if err := repo.Create(ctx, backend, []string{owner.ID, former.ID}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	oncall := &Group{WorkspaceID: ws.ID, Handle: "oncall", Name: "On-call"}
+	if err := repo.Create(ctx, oncall, []string{owner.ID}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// Same handle in another workspace is not resolved
+	if err := repo.Create(ctx, &Group{WorkspaceID: other.ID, Handle: "frontend", Name: "Frontend"}, []string{former.ID}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	ids, err := repo.ResolveGroupMembers(ctx, ws.ID, []string{"Backend", oncall.ID, "frontend", "nobody"})
+	if err != nil {
+		t.Fatalf("ResolveGroupMembers() error = %v", err)
+	}
+	want := []string{owner.ID, former.ID}
+	slices.Sort(want)
+	if !slices.Equal(ids, want) {
+		t.Errorf("resolved = %v, want %v", ids, want)
+	}
+
+	// Members who leave the workspace are no longer mentioned
+	if _, err := db.Exec(`DELETE FROM workspace_memberships WHERE user_id = ? AND workspace_id = ?`, former.ID, ws.ID); err != nil {
+		t.Fatalf("removing member: %v", err)
+	}
+	ids, err = repo.ResolveGroupMembers(ctx, ws.ID, []string{"backend"})
+	if err != nil {
+		t.Fatalf("ResolveGroupMembers() error = %v", err)
+	}
+	if !slices.Equal(ids, []string{owner.ID}) {
+		t.Errorf("resolved after leaving = %v, want only %s", ids, owner.ID)
+	}
+}
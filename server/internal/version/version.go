@@ -2,3 +2,7 @@ package version
 
 // Version is the server version, set at build time via -ldflags.
 var Version = "dev"
+
+// MinClientVersion is the oldest client release this server supports, also
+// settable at build time. Clients older than this should prompt to update.
+var MinClientVersion = "0.1.0"
@@ -19,7 +19,11 @@ func Handler(telemetryEnabled bool) http.Handler {
 	if err != nil {
 		panic("web: " + err.Error())
 	}
+	return newHandler(sub, telemetryEnabled)
+}
 
+// newHandler serves the SPA from sub, which holds index.html at its root.
+func newHandler(sub fs.FS, telemetryEnabled bool) http.Handler {
 	fileServer := http.FileServer(http.FS(sub))
 
 	// Build a patched index.html with runtime config injected.
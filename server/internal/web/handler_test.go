@@ -1,6 +1,8 @@
 package web
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -48,3 +50,38 @@ func TestBuildIndexHTML(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":          &fstest.MapFile{Data: []byte("<html><head></head><body>app</body></html>")},
+		"assets/index-abc.js": &fstest.MapFile{Data: []byte("console.log(1)")},
+		"favicon.svg":         &fstest.MapFile{Data: []byte("<svg/>")},
+	}
+	h := newHandler(fsys, false)
+
+	tests := []struct {
+		path         string
+		cacheControl string
+		body         string
+	}{
+		{"/", "no-cache", "app"},
+		{"/assets/index-abc.js", "public, max-age=31536000, immutable", "console.log(1)"},
+		{"/favicon.svg", "", "<svg/>"},
+		{"/workspaces/abc/channels/def", "no-cache", "app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"time"
+)
+
+// IncomingWebhook is a token-authenticated endpoint that posts messages into a
+// single channel. Only the SHA-256 hash of the token is persisted; the
+// plaintext token is returned once at creation time.
+type IncomingWebhook struct {
+	ID          string     `json:"id"`
+	WorkspaceID string     `json:"workspace_id"`
+	ChannelID   string     `json:"channel_id"`
+	Name        string     `json:"name"`
+	AvatarURL   *string    `json:"avatar_url,omitempty"`
+	CreatedBy   *string    `json:"created_by,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
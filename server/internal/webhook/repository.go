@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/enzyme/server/internal/signing"
+	"github.com/enzyme/server/internal/telemetry"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+const webhookColumns = `id, workspace_id, channel_id, name, avatar_url, created_by, last_used_at, created_at, updated_at`
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Create inserts a new webhook and returns the plaintext token. The token is
+// not recoverable afterwards.
+func (r *Repository) Create(ctx context.Context, wh *IncomingWebhook) (_ string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "webhook.Create")
+	defer func() { endSpan(err) }()
+
+	token := generateToken()
+	wh.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	wh.CreatedAt = now
+	wh.UpdatedAt = now
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO incoming_webhooks (id, workspace_id, channel_id, name, avatar_url, token_hash, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, wh.ID, wh.WorkspaceID, wh.ChannelID, wh.Name, wh.AvatarURL, signing.HashToken(token), wh.CreatedBy, now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id string) (*IncomingWebhook, error) {
+	return scanWebhook(r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM incoming_webhooks WHERE id = ?`, id))
+}
+
+// GetByToken looks up a webhook by its plaintext token.
+func (r *Repository) GetByToken(ctx context.Context, token string) (_ *IncomingWebhook, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "webhook.GetByToken")
+	defer func() { endSpan(err) }()
+
+	return scanWebhook(r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM incoming_webhooks WHERE token_hash = ?`, signing.HashToken(token)))
+}
+
+func (r *Repository) ListByWorkspace(ctx context.Context, workspaceID string) ([]IncomingWebhook, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+webhookColumns+`
+		FROM incoming_webhooks WHERE workspace_id = ? ORDER BY created_at ASC, id ASC
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []IncomingWebhook{}
+	for rows.Next() {
+		wh, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *wh)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *Repository) Update(ctx context.Context, wh *IncomingWebhook) error {
+	wh.UpdatedAt = time.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE incoming_webhooks SET channel_id = ?, name = ?, avatar_url = ?, updated_at = ?
+		WHERE id = ?
+	`, wh.ChannelID, wh.Name, wh.AvatarURL, wh.UpdatedAt.Format(time.RFC3339), wh.ID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// RegenerateToken replaces the webhook's token, invalidating the old URL.
+func (r *Repository) RegenerateToken(ctx context.Context, id string) (string, error) {
+	token := generateToken()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE incoming_webhooks SET token_hash = ?, updated_at = ? WHERE id = ?
+	`, signing.HashToken(token), time.Now().UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return "", err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return "", ErrWebhookNotFound
+	}
+	return token, nil
+}
+
+// TouchLastUsed records that the webhook was just used to post a message.
+func (r *Repository) TouchLastUsed(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE incoming_webhooks SET last_used_at = ? WHERE id = ?
+	`, time.Now().UTC().Format(time.RFC3339), id)
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM incoming_webhooks WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func scanWebhook(row interface{ Scan(dest ...any) error }) (*IncomingWebhook, error) {
+	var wh IncomingWebhook
+	var avatarURL, createdBy, lastUsedAt sql.NullString
+	var createdAt, updatedAt string
+
+	err := row.Scan(&wh.ID, &wh.WorkspaceID, &wh.ChannelID, &wh.Name, &avatarURL, &createdBy, &lastUsedAt, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if avatarURL.Valid {
+		wh.AvatarURL = &avatarURL.String
+	}
+	if createdBy.Valid {
+		wh.CreatedBy = &createdBy.String
+	}
+	if lastUsedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, lastUsedAt.String)
+		wh.LastUsedAt = &t
+	}
+	wh.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	wh.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	return &wh, nil
+}
+
+func generateToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/enzyme/server/internal/testutil"
+)
+
+func createTestWebhook(t *testing.T, repo *Repository, workspaceID, channelID, userID string) (*IncomingWebhook, string) {
+	t.Helper()
+
+	wh := &IncomingWebhook{
+		WorkspaceID: workspaceID,
+		ChannelID:   channelID,
+		Name:        "CI Bot",
+		CreatedBy:   &userID,
+	}
+	token, err := repo.Create(context.Background(), wh)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return wh, token
+}
+
+func TestRepository_CreateAndGetByToken(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user := testutil.CreateTestUser(t, db, "test@example.com", "Test")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "deploys", "public")
+
+	wh, token := createTestWebhook(t, repo, ws.ID, ch.ID, user.ID)
+	if wh.ID == "" {
+		t.Error("expected non-empty ID")
+	}
+	if token == "" {
+		t.Fatal("expected non-empty token")
+	}
+
+	got, err := repo.GetByToken(ctx, token)
+	if err != nil {
+		t.Fatalf("GetByToken() error = %v", err)
+	}
+	if got.ID != wh.ID {
+		t.Errorf("ID = %q, want %q", got.ID, wh.ID)
+	}
+	if got.ChannelID != ch.ID {
+		t.Errorf("ChannelID = %q, want %q", got.ChannelID, ch.ID)
+	}
+
+	// The plaintext token must not be stored
+	var stored string
+	if err := db.QueryRowContext(ctx, `SELECT token_hash FROM incoming_webhooks WHERE id = ?`, wh.ID).Scan(&stored); err != nil {
+		t.Fatalf("querying token hash: %v", err)
+	}
+	if stored == token {
+		t.Error("expected token to be stored hashed")
+	}
+}
+
+func TestRepository_GetByToken_Unknown(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+
+	_, err := repo.GetByToken(context.Background(), "nonexistent")
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestRepository_RegenerateToken(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user := testutil.CreateTestUser(t, db, "test@example.com", "Test")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "deploys", "public")
+
+	wh, oldToken := createTestWebhook(t, repo, ws.ID, ch.ID, user.ID)
+
+	newToken, err := repo.RegenerateToken(ctx, wh.ID)
+	if err != nil {
+		t.Fatalf("RegenerateToken() error = %v", err)
+	}
+	if newToken == oldToken {
+		t.Error("expected a new token")
+	}
+
+	if _, err := repo.GetByToken(ctx, oldToken); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("old token: expected ErrWebhookNotFound, got %v", err)
+	}
+	if _, err := repo.GetByToken(ctx, newToken); err != nil {
+		t.Errorf("new token: GetByToken() error = %v", err)
+	}
+}
+
+func TestRepository_ListUpdateDelete(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	user := testutil.CreateTestUser(t, db, "test@example.com", "Test")
+	ws := testutil.CreateTestWorkspace(t, db, user.ID, "Test Workspace")
+	ch := testutil.CreateTestChannel(t, db, ws.ID, user.ID, "deploys", "public")
+
+	wh, _ := createTestWebhook(t, repo, ws.ID, ch.ID, user.ID)
+	createTestWebhook(t, repo, ws.ID, ch.ID, user.ID)
+
+	list, err := repo.ListByWorkspace(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("ListByWorkspace() error = %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 webhooks, got %d", len(list))
+	}
+
+	avatar := "https://example.com/bot.png"
+	wh.Name = "Deploy Bot"
+	wh.AvatarURL = &avatar
+	if err := repo.Update(ctx, wh); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := repo.GetByID(ctx, wh.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Name != "Deploy Bot" || got.AvatarURL == nil || *got.AvatarURL != avatar {
+		t.Errorf("update not persisted: %+v", got)
+	}
+
+	if err := repo.Delete(ctx, wh.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, wh.ID); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("second Delete(): expected ErrWebhookNotFound, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package workspace
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,25 @@ type WorkspaceSettings struct {
 	WhoCanCreateInvites     PermissionLevel `json:"who_can_create_invites"`
 	WhoCanPinMessages       PermissionLevel `json:"who_can_pin_messages"`
 	WhoCanManageCustomEmoji PermissionLevel `json:"who_can_manage_custom_emoji"`
+	WhoCanMentionChannel    PermissionLevel `json:"who_can_mention_channel"` // @channel, @here and @everyone
+	DMReadReceipts          bool            `json:"dm_read_receipts"`
+	AutoDMPolicy            AutoDMPolicy    `json:"auto_dm_policy"`
+	LinkPreviews            bool            `json:"link_previews"`
+	AttachmentRetentionDays int             `json:"attachment_retention_days"` // 0 keeps attachments forever
+	MessageRetentionDays    int             `json:"message_retention_days"`    // default for channels; 0 keeps messages forever
+	// OpenSignup lets anyone whose email is on one of OpenSignupDomains join
+	// as a member through the workspace's join link, without an invite
+	OpenSignup        bool     `json:"open_signup"`
+	OpenSignupDomains []string `json:"open_signup_domains,omitempty"`
+	// UploadAllowedTypes and UploadBlockedTypes restrict uploads by file
+	// extension (".pdf") or content type ("image/png", "image/*"). An empty
+	// allowlist allows anything not blocked.
+	UploadAllowedTypes []string `json:"upload_allowed_types,omitempty"`
+	UploadBlockedTypes []string `json:"upload_blocked_types,omitempty"`
+	// DefaultNotificationSound names the sound clients play for members who
+	// have not picked one in their client settings. Empty leaves it to the
+	// client.
+	DefaultNotificationSound string `json:"default_notification_sound,omitempty"`
 }
 
 // DefaultSettings returns the default workspace settings
@@ -22,6 +42,10 @@ func DefaultSettings() WorkspaceSettings {
 		WhoCanCreateInvites:     PermissionAdmins,
 		WhoCanPinMessages:       PermissionMembers,
 		WhoCanManageCustomEmoji: PermissionMembers,
+		WhoCanMentionChannel:    PermissionEveryone,
+		DMReadReceipts:          true,
+		AutoDMPolicy:            AutoDMEarliestMembers,
+		LinkPreviews:            true,
 	}
 }
 
@@ -45,9 +69,139 @@ func ParseSettings(settingsJSON string) WorkspaceSettings {
 	if !IsValidPermissionLevel(settings.WhoCanManageCustomEmoji) {
 		settings.WhoCanManageCustomEmoji = defaults.WhoCanManageCustomEmoji
 	}
+	if !IsValidPermissionLevel(settings.WhoCanMentionChannel) {
+		settings.WhoCanMentionChannel = defaults.WhoCanMentionChannel
+	}
+	if !IsValidAutoDMPolicy(settings.AutoDMPolicy) {
+		settings.AutoDMPolicy = defaults.AutoDMPolicy
+	}
+	if settings.AttachmentRetentionDays < 0 {
+		settings.AttachmentRetentionDays = defaults.AttachmentRetentionDays
+	}
+	if settings.MessageRetentionDays < 0 {
+		settings.MessageRetentionDays = defaults.MessageRetentionDays
+	}
+	var domains []string
+	for _, d := range settings.OpenSignupDomains {
+		if d, ok := NormalizeSignupDomain(d); ok {
+			domains = append(domains, d)
+		}
+	}
+	settings.OpenSignupDomains = domains
+	settings.UploadAllowedTypes = normalizeUploadTypes(settings.UploadAllowedTypes)
+	settings.UploadBlockedTypes = normalizeUploadTypes(settings.UploadBlockedTypes)
 	return settings
 }
 
+func normalizeUploadTypes(types []string) []string {
+	var out []string
+	for _, t := range types {
+		if t, ok := NormalizeUploadType(t); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// MaxNotificationSoundLength caps the length of a notification sound name
+const MaxNotificationSoundLength = 64
+
+// MaxSignupDomains caps how many email domains open signup can allow
+const MaxSignupDomains = 20
+
+// NormalizeSignupDomain lowercases an email domain, dropping a leading "@",
+// and reports whether it looks like a domain name
+func NormalizeSignupDomain(domain string) (string, bool) {
+	domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+	if len(domain) > 253 || !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", false
+	}
+	for _, r := range domain {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return "", false
+		}
+	}
+	return domain, true
+}
+
+// AllowsSignup reports whether open signup is on and email's domain is one
+// of the allowed domains. Subdomains must be listed separately.
+func (s WorkspaceSettings) AllowsSignup(email string) bool {
+	if !s.OpenSignup {
+		return false
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, d := range s.OpenSignupDomains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxUploadTypes caps how many entries an upload allowlist or denylist can have
+const MaxUploadTypes = 50
+
+// NormalizeUploadType lowercases an upload allowlist or denylist entry and
+// reports whether it is a file extension (".pdf"), a content type
+// ("image/png") or a content type wildcard ("image/*")
+func NormalizeUploadType(t string) (string, bool) {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if len(t) < 2 || len(t) > 100 {
+		return "", false
+	}
+	if strings.HasPrefix(t, ".") {
+		for _, r := range t[1:] {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+				return "", false
+			}
+		}
+		return t, true
+	}
+	major, minor, ok := strings.Cut(t, "/")
+	if !ok || major == "" || minor == "" || major == "*" || strings.ContainsAny(t, " ;,") {
+		return "", false
+	}
+	if strings.Contains(minor, "*") && minor != "*" {
+		return "", false
+	}
+	return t, true
+}
+
+// AllowsUpload reports whether a file with this name and content type may be
+// uploaded. The denylist wins over the allowlist.
+func (s WorkspaceSettings) AllowsUpload(filename, contentType string) bool {
+	if matchesUploadType(s.UploadBlockedTypes, filename, contentType) {
+		return false
+	}
+	return len(s.UploadAllowedTypes) == 0 || matchesUploadType(s.UploadAllowedTypes, filename, contentType)
+}
+
+func matchesUploadType(types []string, filename, contentType string) bool {
+	filename = strings.ToLower(filename)
+	contentType = strings.ToLower(contentType)
+	major, _, _ := strings.Cut(contentType, "/")
+	for _, t := range types {
+		switch {
+		case strings.HasPrefix(t, "."):
+			if strings.HasSuffix(filename, t) {
+				return true
+			}
+		case strings.HasSuffix(t, "/*"):
+			if strings.TrimSuffix(t, "/*") == major {
+				return true
+			}
+		case t == contentType:
+			return true
+		}
+	}
+	return false
+}
+
 // ToJSON serializes WorkspaceSettings to a JSON string
 func (s WorkspaceSettings) ToJSON() string {
 	data, err := json.Marshal(s)
@@ -58,12 +212,16 @@ func (s WorkspaceSettings) ToJSON() string {
 }
 
 type Workspace struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	IconURL   *string   `json:"icon_url,omitempty"`
-	Settings  string    `json:"settings"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	IconURL  *string `json:"icon_url,omitempty"`
+	Settings string  `json:"settings"`
+	// ReadOnly is set by the owner during maintenance; sends, edits,
+	// reactions and uploads are refused while it is on
+	ReadOnly        bool      `json:"read_only"`
+	ReadOnlyMessage *string   `json:"read_only_message,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // ParsedSettings returns the parsed workspace settings
@@ -82,12 +240,67 @@ type Membership struct {
 	UpdatedAt           time.Time `json:"updated_at"`
 }
 
+// Member list sort orders
+const (
+	MemberSortJoined = "joined"
+	MemberSortName   = "name"
+)
+
+// MemberListOptions filters and pages a workspace's member list. Cursor is
+// the user ID of the last member on the previous page.
+type MemberListOptions struct {
+	Query              string
+	Role               string
+	Sort               string
+	IncludeDeactivated bool
+	Cursor             string
+	Limit              int
+}
+
+// MemberListResult is one page of a workspace's members
+type MemberListResult struct {
+	Members    []MemberWithUser
+	HasMore    bool
+	NextCursor string
+}
+
 type MemberWithUser struct {
 	Membership
-	Email       string  `json:"email"`
-	DisplayName string  `json:"display_name"`
-	AvatarURL   *string `json:"avatar_url,omitempty"`
-	IsBanned    bool    `json:"is_banned"`
+	Email         string  `json:"email"`
+	DisplayName   string  `json:"display_name"`
+	AvatarURL     *string `json:"avatar_url,omitempty"`
+	Title         *string `json:"title,omitempty"`
+	Pronouns      *string `json:"pronouns,omitempty"`
+	Timezone      *string `json:"timezone,omitempty"`
+	IsBanned      bool    `json:"is_banned"`
+	IsDeactivated bool    `json:"is_deactivated"`
+	IsBot         bool    `json:"is_bot"`
+	// ProfileFields holds the member's custom profile values, keyed by field ID
+	ProfileFields map[string]string `json:"profile_fields,omitempty"`
+}
+
+const (
+	ProfileFieldText   = "text"
+	ProfileFieldURL    = "url"
+	ProfileFieldSelect = "select"
+)
+
+// IsValidProfileFieldType returns true if t is a known profile field type
+func IsValidProfileFieldType(t string) bool {
+	return t == ProfileFieldText || t == ProfileFieldURL || t == ProfileFieldSelect
+}
+
+// ProfileField is an admin-defined field shown on member profiles in a
+// workspace. Options lists the allowed values of select fields.
+type ProfileField struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Options     []string  `json:"options,omitempty"`
+	SortOrder   int       `json:"sort_order"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 type Invite struct {
@@ -126,6 +339,27 @@ func HasPermission(role string, level PermissionLevel) bool {
 	}
 }
 
+// AutoDMPolicy controls which direct messages are opened for a member when
+// they join the workspace
+type AutoDMPolicy string
+
+const (
+	AutoDMNone            AutoDMPolicy = "none"
+	AutoDMInviter         AutoDMPolicy = "inviter"
+	AutoDMAdmins          AutoDMPolicy = "admins"
+	AutoDMEarliestMembers AutoDMPolicy = "earliest_members"
+)
+
+// IsValidAutoDMPolicy returns true if the policy is a known auto-DM policy
+func IsValidAutoDMPolicy(policy AutoDMPolicy) bool {
+	switch policy {
+	case AutoDMNone, AutoDMInviter, AutoDMAdmins, AutoDMEarliestMembers:
+		return true
+	default:
+		return false
+	}
+}
+
 const (
 	RoleOwner  = "owner"
 	RoleAdmin  = "admin"
@@ -1,6 +1,9 @@
 package workspace
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestCanManageMembers(t *testing.T) {
 	tests := []struct {
@@ -177,8 +180,62 @@ func TestParseSettings(t *testing.T) {
 				WhoCanCreateInvites:     PermissionAdmins,
 				WhoCanPinMessages:       PermissionMembers,
 				WhoCanManageCustomEmoji: PermissionMembers,
+				WhoCanMentionChannel:    PermissionEveryone,
+				DMReadReceipts:          true,
+				AutoDMPolicy:            AutoDMEarliestMembers,
+				LinkPreviews:            true,
 			},
 		},
+		{
+			name: "dm_read_receipts false",
+			json: `{"dm_read_receipts":false}`,
+			expected: WorkspaceSettings{
+				ShowJoinLeaveMessages:   true,
+				WhoCanCreateChannels:    PermissionMembers,
+				WhoCanCreateInvites:     PermissionAdmins,
+				WhoCanPinMessages:       PermissionMembers,
+				WhoCanManageCustomEmoji: PermissionMembers,
+				WhoCanMentionChannel:    PermissionEveryone,
+				DMReadReceipts:          false,
+				AutoDMPolicy:            AutoDMEarliestMembers,
+				LinkPreviews:            true,
+			},
+		},
+		{
+			name: "link_previews false",
+			json: `{"link_previews":false}`,
+			expected: WorkspaceSettings{
+				ShowJoinLeaveMessages:   true,
+				WhoCanCreateChannels:    PermissionMembers,
+				WhoCanCreateInvites:     PermissionAdmins,
+				WhoCanPinMessages:       PermissionMembers,
+				WhoCanManageCustomEmoji: PermissionMembers,
+				WhoCanMentionChannel:    PermissionEveryone,
+				DMReadReceipts:          true,
+				AutoDMPolicy:            AutoDMEarliestMembers,
+				LinkPreviews:            false,
+			},
+		},
+		{
+			name: "auto_dm_policy inviter",
+			json: `{"auto_dm_policy":"inviter"}`,
+			expected: WorkspaceSettings{
+				ShowJoinLeaveMessages:   true,
+				WhoCanCreateChannels:    PermissionMembers,
+				WhoCanCreateInvites:     PermissionAdmins,
+				WhoCanPinMessages:       PermissionMembers,
+				WhoCanManageCustomEmoji: PermissionMembers,
+				WhoCanMentionChannel:    PermissionEveryone,
+				DMReadReceipts:          true,
+				AutoDMPolicy:            AutoDMInviter,
+				LinkPreviews:            true,
+			},
+		},
+		{
+			name:     "invalid auto_dm_policy falls back to default",
+			json:     `{"auto_dm_policy":"everyone"}`,
+			expected: DefaultSettings(),
+		},
 		{
 			name:     "invalid json returns defaults",
 			json:     "not json",
@@ -186,13 +243,17 @@ func TestParseSettings(t *testing.T) {
 		},
 		{
 			name: "permission fields override defaults",
-			json: `{"who_can_create_channels":"admins","who_can_create_invites":"members","who_can_pin_messages":"everyone","who_can_manage_custom_emoji":"admins"}`,
+			json: `{"who_can_create_channels":"admins","who_can_create_invites":"members","who_can_pin_messages":"everyone","who_can_manage_custom_emoji":"admins","who_can_mention_channel":"members"}`,
 			expected: WorkspaceSettings{
 				ShowJoinLeaveMessages:   true,
 				WhoCanCreateChannels:    PermissionAdmins,
 				WhoCanCreateInvites:     PermissionMembers,
 				WhoCanPinMessages:       PermissionEveryone,
 				WhoCanManageCustomEmoji: PermissionAdmins,
+				WhoCanMentionChannel:    PermissionMembers,
+				DMReadReceipts:          true,
+				AutoDMPolicy:            AutoDMEarliestMembers,
+				LinkPreviews:            true,
 			},
 		},
 		{
@@ -205,7 +266,7 @@ func TestParseSettings(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := ParseSettings(tt.json)
-			if got != tt.expected {
+			if !reflect.DeepEqual(got, tt.expected) {
 				t.Errorf("ParseSettings(%q) = %+v, want %+v", tt.json, got, tt.expected)
 			}
 		})
@@ -219,12 +280,14 @@ func TestWorkspaceSettings_ToJSON(t *testing.T) {
 		WhoCanCreateInvites:     PermissionMembers,
 		WhoCanPinMessages:       PermissionEveryone,
 		WhoCanManageCustomEmoji: PermissionAdmins,
+		WhoCanMentionChannel:    PermissionEveryone,
+		AutoDMPolicy:            AutoDMNone,
 	}
 	jsonStr := settings.ToJSON()
 
 	// Verify round-trip
 	parsed := ParseSettings(jsonStr)
-	if parsed != settings {
+	if !reflect.DeepEqual(parsed, settings) {
 		t.Errorf("Round-trip failed: got %+v, want %+v", parsed, settings)
 	}
 }
@@ -246,6 +309,12 @@ func TestDefaultSettings(t *testing.T) {
 	if defaults.WhoCanManageCustomEmoji != PermissionMembers {
 		t.Errorf("default WhoCanManageCustomEmoji should be %q, got %q", PermissionMembers, defaults.WhoCanManageCustomEmoji)
 	}
+	if defaults.WhoCanMentionChannel != PermissionEveryone {
+		t.Errorf("default WhoCanMentionChannel should be %q, got %q", PermissionEveryone, defaults.WhoCanMentionChannel)
+	}
+	if defaults.AutoDMPolicy != AutoDMEarliestMembers {
+		t.Errorf("default AutoDMPolicy should be %q, got %q", AutoDMEarliestMembers, defaults.AutoDMPolicy)
+	}
 }
 
 func TestWorkspace_ParsedSettings(t *testing.T) {
@@ -255,3 +324,60 @@ func TestWorkspace_ParsedSettings(t *testing.T) {
 		t.Error("ParsedSettings should return false for show_join_leave_messages")
 	}
 }
+
+func TestWorkspaceSettings_AllowsSignup(t *testing.T) {
+	settings := ParseSettings(`{"open_signup":true,"open_signup_domains":["@Acme.com","not a domain","eu.acme.com"]}`)
+	if !reflect.DeepEqual(settings.OpenSignupDomains, []string{"acme.com", "eu.acme.com"}) {
+		t.Fatalf("OpenSignupDomains = %v, want normalized valid domains only", settings.OpenSignupDomains)
+	}
+
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"alice@acme.com", true},
+		{"Bob@ACME.COM", true},
+		{"carol@eu.acme.com", true},
+		{"dave@us.acme.com", false},
+		{"eve@acme.com.evil.io", false},
+		{"no-at-sign", false},
+	}
+	for _, tt := range tests {
+		if got := settings.AllowsSignup(tt.email); got != tt.want {
+			t.Errorf("AllowsSignup(%q) = %v, want %v", tt.email, got, tt.want)
+		}
+	}
+
+	settings.OpenSignup = false
+	if settings.AllowsSignup("alice@acme.com") {
+		t.Error("AllowsSignup() = true with open signup off")
+	}
+}
+
+func TestWorkspaceSettings_AllowsUpload(t *testing.T) {
+	settings := ParseSettings(`{"upload_allowed_types":["image/*",".PDF","text/plain","bad type"],"upload_blocked_types":["image/svg+xml",".exe"]}`)
+	if !reflect.DeepEqual(settings.UploadAllowedTypes, []string{"image/*", ".pdf", "text/plain"}) {
+		t.Fatalf("UploadAllowedTypes = %v, want normalized valid entries only", settings.UploadAllowedTypes)
+	}
+
+	tests := []struct {
+		filename, contentType string
+		want                  bool
+	}{
+		{"photo.png", "image/png", true},
+		{"report.PDF", "application/pdf", true},
+		{"notes.txt", "text/plain", true},
+		{"logo.svg", "image/svg+xml", false},
+		{"setup.exe", "image/png", false},
+		{"data.csv", "text/csv", false},
+	}
+	for _, tt := range tests {
+		if got := settings.AllowsUpload(tt.filename, tt.contentType); got != tt.want {
+			t.Errorf("AllowsUpload(%q, %q) = %v, want %v", tt.filename, tt.contentType, got, tt.want)
+		}
+	}
+
+	if !DefaultSettings().AllowsUpload("anything.bin", "application/octet-stream") {
+		t.Error("AllowsUpload() = false with no lists set")
+	}
+}
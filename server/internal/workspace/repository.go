@@ -3,10 +3,13 @@ package workspace
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/enzyme/server/internal/auth"
@@ -15,13 +18,18 @@ import (
 )
 
 var (
-	ErrWorkspaceNotFound = errors.New("workspace not found")
-	ErrMembershipExists  = errors.New("user is already a member")
-	ErrNotAMember        = errors.New("user is not a member of this workspace")
-	ErrInviteNotFound    = errors.New("invite not found")
-	ErrInviteExpired     = errors.New("invite has expired")
-	ErrInviteMaxUsed     = errors.New("invite has reached max uses")
-	ErrCannotRemoveOwner = errors.New("cannot remove workspace owner")
+	ErrWorkspaceNotFound   = errors.New("workspace not found")
+	ErrMembershipExists    = errors.New("user is already a member")
+	ErrNotAMember          = errors.New("user is not a member of this workspace")
+	ErrInviteNotFound      = errors.New("invite not found")
+	ErrInviteExpired       = errors.New("invite has expired")
+	ErrInviteMaxUsed       = errors.New("invite has reached max uses")
+	ErrInviteEmailMismatch = errors.New("invite is for a different email address")
+	ErrCannotRemoveOwner   = errors.New("cannot remove workspace owner")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+
+	ErrProfileFieldNotFound = errors.New("profile field not found")
+	ErrProfileFieldExists   = errors.New("a profile field with this name already exists")
 )
 
 type Repository struct {
@@ -67,11 +75,32 @@ func (r *Repository) Create(ctx context.Context, workspace *Workspace, ownerUser
 
 func (r *Repository) GetByID(ctx context.Context, id string) (*Workspace, error) {
 	return r.scanWorkspace(r.db.QueryRowContext(ctx, `
-		SELECT id, name, icon_url, settings, created_at, updated_at
+		SELECT id, name, icon_url, settings, read_only, read_only_message, created_at, updated_at
 		FROM workspaces WHERE id = ?
 	`, id))
 }
 
+// SetReadOnly turns the workspace's read-only mode on or off. The message is
+// cleared when read-only mode is turned off.
+func (r *Repository) SetReadOnly(ctx context.Context, id string, readOnly bool, message *string) error {
+	if !readOnly {
+		message = nil
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE workspaces SET read_only = ?, read_only_message = ?, updated_at = ?
+		WHERE id = ?
+	`, readOnly, message, time.Now().UTC().Format(time.RFC3339), id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrWorkspaceNotFound
+	}
+	return nil
+}
+
 func (r *Repository) Update(ctx context.Context, workspace *Workspace) error {
 	workspace.UpdatedAt = time.Now().UTC()
 	result, err := r.db.ExecContext(ctx, `
@@ -116,6 +145,36 @@ func (r *Repository) GetMembership(ctx context.Context, userID, workspaceID stri
 	return &m, nil
 }
 
+// ListMembershipsForUser returns every workspace membership of a user
+func (r *Repository) ListMembershipsForUser(ctx context.Context, userID string) ([]Membership, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, workspace_id, role, display_name_override, created_at, updated_at
+		FROM workspace_memberships WHERE user_id = ?
+		ORDER BY workspace_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memberships []Membership
+	for rows.Next() {
+		var m Membership
+		var displayNameOverride sql.NullString
+		var createdAt, updatedAt string
+		if err := rows.Scan(&m.ID, &m.UserID, &m.WorkspaceID, &m.Role, &displayNameOverride, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		if displayNameOverride.Valid {
+			m.DisplayNameOverride = &displayNameOverride.String
+		}
+		m.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		m.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+		memberships = append(memberships, m)
+	}
+	return memberships, rows.Err()
+}
+
 func (r *Repository) AddMember(ctx context.Context, userID, workspaceID, role string) (*Membership, error) {
 	id := ulid.Make().String()
 	now := time.Now().UTC()
@@ -220,18 +279,123 @@ func (r *Repository) UpdateMemberRole(ctx context.Context, userID, workspaceID,
 	return nil
 }
 
+// SnoozeNotifications silences the user's notifications and notification
+// badges for every channel in the workspace until the given time
+func (r *Repository) SnoozeNotifications(ctx context.Context, userID, workspaceID string, until time.Time) error {
+	value := until.UTC().Format(time.RFC3339)
+	return r.setSnoozedUntil(ctx, userID, workspaceID, &value)
+}
+
+// UnsnoozeNotifications ends a workspace snooze early
+func (r *Repository) UnsnoozeNotifications(ctx context.Context, userID, workspaceID string) error {
+	return r.setSnoozedUntil(ctx, userID, workspaceID, nil)
+}
+
+func (r *Repository) setSnoozedUntil(ctx context.Context, userID, workspaceID string, until *string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_memberships SET snoozed_until = ?, updated_at = ?
+		WHERE user_id = ? AND workspace_id = ?
+	`, until, time.Now().UTC().Format(time.RFC3339), userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrNotAMember
+	}
+	return nil
+}
+
 func (r *Repository) ListMembers(ctx context.Context, workspaceID string) ([]MemberWithUser, error) {
+	return r.queryMembers(ctx, `wm.workspace_id = ? ORDER BY wm.created_at`, workspaceID)
+}
+
+// ListMembersPage returns a page of the workspace's members, filtered by name
+// or email and by role, in join or name order. Deactivated accounts are left
+// out unless opts.IncludeDeactivated is set.
+func (r *Repository) ListMembersPage(ctx context.Context, workspaceID string, opts MemberListOptions) (_ *MemberListResult, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "workspace.ListMembersPage")
+	defer func() { endSpan(err) }()
+
+	if opts.Limit <= 0 || opts.Limit > 1000 {
+		opts.Limit = 100
+	}
+
+	where := `wm.workspace_id = ?`
+	args := []interface{}{workspaceID}
+	if !opts.IncludeDeactivated {
+		where += ` AND u.status != 'deactivated'`
+	}
+	if q := strings.ToLower(strings.TrimSpace(opts.Query)); q != "" {
+		where += ` AND (LOWER(u.display_name) LIKE ? ESCAPE '\' OR LOWER(u.email) LIKE ? ESCAPE '\')`
+		pattern := "%" + escapeLike(q) + "%"
+		args = append(args, pattern, pattern)
+	}
+	if opts.Role != "" {
+		where += ` AND wm.role = ?`
+		args = append(args, opts.Role)
+	}
+
+	sortKey := `wm.created_at`
+	if opts.Sort == MemberSortName {
+		sortKey = `u.display_name`
+	}
+	if opts.Cursor != "" {
+		var key, membershipID string
+		err := r.db.QueryRowContext(ctx, `
+			SELECT `+sortKey+`, wm.id FROM workspace_memberships wm
+			JOIN users u ON u.id = wm.user_id
+			WHERE wm.workspace_id = ? AND wm.user_id = ?
+		`, workspaceID, opts.Cursor).Scan(&key, &membershipID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidCursor
+		}
+		if err != nil {
+			return nil, err
+		}
+		where += ` AND (` + sortKey + ` > ? OR (` + sortKey + ` = ? AND wm.id > ?))`
+		args = append(args, key, key, membershipID)
+	}
+
+	members, err := r.queryMembers(ctx, where+` ORDER BY `+sortKey+`, wm.id LIMIT ?`, append(args, opts.Limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MemberListResult{Members: members}
+	if len(members) > opts.Limit {
+		result.Members = members[:opts.Limit]
+		result.HasMore = true
+		result.NextCursor = result.Members[opts.Limit-1].UserID
+	}
+	return result, nil
+}
+
+// CountMembers returns how many members the workspace has, not counting
+// deactivated accounts
+func (r *Repository) CountMembers(ctx context.Context, workspaceID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM workspace_memberships wm
+		JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = ? AND u.status != 'deactivated'
+	`, workspaceID).Scan(&count)
+	return count, err
+}
+
+// queryMembers loads members with their user details. The condition follows
+// WHERE and may end with ORDER BY and LIMIT clauses.
+func (r *Repository) queryMembers(ctx context.Context, condition string, args ...interface{}) ([]MemberWithUser, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT wm.id, wm.user_id, wm.workspace_id, wm.role, wm.display_name_override, wm.created_at, wm.updated_at,
-		       u.email, u.display_name, u.avatar_url,
+		SELECT wm.id, wm.user_id, wm.workspace_id, wm.role, wm.display_name_override, wm.profile_fields, wm.created_at, wm.updated_at,
+		       u.email, u.display_name, u.avatar_url, u.title, u.pronouns, u.timezone,
+		       u.status = 'deactivated' as is_deactivated, u.is_bot,
 		       CASE WHEN wb.id IS NOT NULL THEN 1 ELSE 0 END as is_banned
 		FROM workspace_memberships wm
 		JOIN users u ON u.id = wm.user_id
 		LEFT JOIN workspace_bans wb ON wb.workspace_id = wm.workspace_id AND wb.user_id = wm.user_id
 			AND (wb.expires_at IS NULL OR wb.expires_at > strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
-		WHERE wm.workspace_id = ?
-		ORDER BY wm.created_at
-	`, workspaceID)
+		WHERE `+condition, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -240,11 +404,11 @@ func (r *Repository) ListMembers(ctx context.Context, workspaceID string) ([]Mem
 	var members []MemberWithUser
 	for rows.Next() {
 		var m MemberWithUser
-		var displayNameOverride, avatarURL sql.NullString
+		var displayNameOverride, profileFields, avatarURL, title, pronouns, timezone sql.NullString
 		var createdAt, updatedAt string
 
-		err := rows.Scan(&m.ID, &m.UserID, &m.WorkspaceID, &m.Role, &displayNameOverride, &createdAt, &updatedAt,
-			&m.Email, &m.DisplayName, &avatarURL, &m.IsBanned)
+		err := rows.Scan(&m.ID, &m.UserID, &m.WorkspaceID, &m.Role, &displayNameOverride, &profileFields, &createdAt, &updatedAt,
+			&m.Email, &m.DisplayName, &avatarURL, &title, &pronouns, &timezone, &m.IsDeactivated, &m.IsBot, &m.IsBanned)
 		if err != nil {
 			return nil, err
 		}
@@ -255,6 +419,16 @@ func (r *Repository) ListMembers(ctx context.Context, workspaceID string) ([]Mem
 		if avatarURL.Valid {
 			m.AvatarURL = &avatarURL.String
 		}
+		if title.Valid {
+			m.Title = &title.String
+		}
+		if pronouns.Valid {
+			m.Pronouns = &pronouns.String
+		}
+		if timezone.Valid {
+			m.Timezone = &timezone.String
+		}
+		m.ProfileFields = parseProfileValues(profileFields)
 		m.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 		m.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -264,6 +438,34 @@ func (r *Repository) ListMembers(ctx context.Context, workspaceID string) ([]Mem
 	return members, rows.Err()
 }
 
+// MembersVersion returns a fingerprint that changes whenever ListMembers may
+// return something different for the workspace: the count and newest update
+// of its memberships and of its members' profiles, and its active bans.
+func (r *Repository) MembersVersion(ctx context.Context, workspaceID string) (_ string, err error) {
+	ctx, endSpan := telemetry.StartDBSpan(ctx, "workspace.MembersVersion")
+	defer func() { endSpan(err) }()
+
+	var members, bans string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) || '/' || COALESCE(MAX(wm.id), '') || '/' || COALESCE(MAX(wm.updated_at), '') || '/' || COALESCE(MAX(u.updated_at), '')
+			 FROM workspace_memberships wm
+			 JOIN users u ON u.id = wm.user_id
+			 WHERE wm.workspace_id = ?),
+			(SELECT COUNT(*) || '/' || COALESCE(MAX(id), '')
+			 FROM workspace_bans
+			 WHERE workspace_id = ?
+			   AND (expires_at IS NULL OR expires_at > strftime('%Y-%m-%dT%H:%M:%SZ', 'now')))
+	`, workspaceID, workspaceID).Scan(&members, &bans)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(members + "\x00" + bans))
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
 func (r *Repository) GetWorkspacesForUser(req *http.Request, userID string) ([]auth.WorkspaceSummary, error) {
 	rows, err := r.db.QueryContext(req.Context(), `
 		SELECT w.id, w.name, w.icon_url, wm.role, wm.sort_order
@@ -347,15 +549,70 @@ func (r *Repository) CreateInvite(ctx context.Context, invite *Invite) error {
 }
 
 func (r *Repository) GetInviteByCode(ctx context.Context, code string) (*Invite, error) {
+	return r.scanInvite(r.db.QueryRowContext(ctx, `
+		SELECT `+inviteColumns+`
+		FROM workspace_invites WHERE code = ?
+	`, code))
+}
+
+// GetInviteByID returns an invite by its ID
+func (r *Repository) GetInviteByID(ctx context.Context, id string) (*Invite, error) {
+	return r.scanInvite(r.db.QueryRowContext(ctx, `
+		SELECT `+inviteColumns+`
+		FROM workspace_invites WHERE id = ?
+	`, id))
+}
+
+// ListInvites returns the workspace's invites, newest first. When createdBy
+// is non-empty only invites created by that user are returned.
+func (r *Repository) ListInvites(ctx context.Context, workspaceID, createdBy string) ([]Invite, error) {
+	query := `SELECT ` + inviteColumns + ` FROM workspace_invites WHERE workspace_id = ?`
+	args := []interface{}{workspaceID}
+	if createdBy != "" {
+		query += ` AND created_by = ?`
+		args = append(args, createdBy)
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invites []Invite
+	for rows.Next() {
+		invite, err := r.scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, *invite)
+	}
+	return invites, rows.Err()
+}
+
+// DeleteInvite revokes an invite so its code can no longer be used
+func (r *Repository) DeleteInvite(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM workspace_invites WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrInviteNotFound
+	}
+	return nil
+}
+
+const inviteColumns = `id, workspace_id, code, invited_email, role, created_by, max_uses, use_count, expires_at, created_at`
+
+func (r *Repository) scanInvite(row interface{ Scan(...interface{}) error }) (*Invite, error) {
 	var invite Invite
 	var invitedEmail, createdBy, expiresAt sql.NullString
 	var maxUses sql.NullInt64
 	var createdAt string
 
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, workspace_id, code, invited_email, role, created_by, max_uses, use_count, expires_at, created_at
-		FROM workspace_invites WHERE code = ?
-	`, code).Scan(&invite.ID, &invite.WorkspaceID, &invite.Code, &invitedEmail, &invite.Role, &createdBy, &maxUses, &invite.UseCount, &expiresAt, &createdAt)
+	err := row.Scan(&invite.ID, &invite.WorkspaceID, &invite.Code, &invitedEmail, &invite.Role, &createdBy, &maxUses, &invite.UseCount, &expiresAt, &createdAt)
 	if err == sql.ErrNoRows {
 		return nil, ErrInviteNotFound
 	}
@@ -389,6 +646,40 @@ func (r *Repository) IncrementInviteUseCount(ctx context.Context, inviteID strin
 	return err
 }
 
+// DeleteExpiredInvites removes invites whose expiry has passed and returns
+// how many were deleted.
+func (r *Repository) DeleteExpiredInvites(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM workspace_invites WHERE expires_at IS NOT NULL AND expires_at < ?
+	`, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListAttachmentRetention returns the attachment retention period in days
+// for every workspace that has one configured, keyed by workspace ID.
+func (r *Repository) ListAttachmentRetention(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, settings FROM workspaces`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	retention := make(map[string]int)
+	for rows.Next() {
+		var id, settings string
+		if err := rows.Scan(&id, &settings); err != nil {
+			return nil, err
+		}
+		if days := ParseSettings(settings).AttachmentRetentionDays; days > 0 {
+			retention[id] = days
+		}
+	}
+	return retention, rows.Err()
+}
+
 func (r *Repository) AcceptInvite(ctx context.Context, code string, userID string) (*Workspace, error) {
 	invite, err := r.GetInviteByCode(ctx, code)
 	if err != nil {
@@ -405,6 +696,17 @@ func (r *Repository) AcceptInvite(ctx context.Context, code string, userID strin
 		return nil, ErrInviteMaxUsed
 	}
 
+	// Email-bound invites can only be used by that address
+	if invite.InvitedEmail != nil {
+		var email string
+		if err := r.db.QueryRowContext(ctx, `SELECT email FROM users WHERE id = ?`, userID).Scan(&email); err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(strings.TrimSpace(email), strings.TrimSpace(*invite.InvitedEmail)) {
+			return nil, ErrInviteEmailMismatch
+		}
+	}
+
 	// Add member
 	_, err = r.AddMember(ctx, userID, invite.WorkspaceID, invite.Role)
 	if err != nil && !errors.Is(err, ErrMembershipExists) {
@@ -421,10 +723,10 @@ func (r *Repository) AcceptInvite(ctx context.Context, code string, userID strin
 
 func (r *Repository) scanWorkspace(row *sql.Row) (*Workspace, error) {
 	var w Workspace
-	var iconURL sql.NullString
+	var iconURL, readOnlyMessage sql.NullString
 	var createdAt, updatedAt string
 
-	err := row.Scan(&w.ID, &w.Name, &iconURL, &w.Settings, &createdAt, &updatedAt)
+	err := row.Scan(&w.ID, &w.Name, &iconURL, &w.Settings, &w.ReadOnly, &readOnlyMessage, &createdAt, &updatedAt)
 	if err == sql.ErrNoRows {
 		return nil, ErrWorkspaceNotFound
 	}
@@ -435,12 +737,194 @@ func (r *Repository) scanWorkspace(row *sql.Row) (*Workspace, error) {
 	if iconURL.Valid {
 		w.IconURL = &iconURL.String
 	}
+	if readOnlyMessage.Valid {
+		w.ReadOnlyMessage = &readOnlyMessage.String
+	}
 	w.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	w.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
 	return &w, nil
 }
 
+// ListProfileFields returns a workspace's custom profile fields in display order
+func (r *Repository) ListProfileFields(ctx context.Context, workspaceID string) ([]ProfileField, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, workspace_id, name, field_type, options, sort_order, created_at, updated_at
+		FROM workspace_profile_fields
+		WHERE workspace_id = ?
+		ORDER BY sort_order, created_at
+	`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []ProfileField{}
+	for rows.Next() {
+		f, err := scanProfileField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, *f)
+	}
+	return fields, rows.Err()
+}
+
+func (r *Repository) GetProfileField(ctx context.Context, id string) (*ProfileField, error) {
+	f, err := scanProfileField(r.db.QueryRowContext(ctx, `
+		SELECT id, workspace_id, name, field_type, options, sort_order, created_at, updated_at
+		FROM workspace_profile_fields WHERE id = ?
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrProfileFieldNotFound
+	}
+	return f, err
+}
+
+// CreateProfileField adds a field after the workspace's existing fields
+func (r *Repository) CreateProfileField(ctx context.Context, f *ProfileField) error {
+	f.ID = ulid.Make().String()
+	now := time.Now().UTC()
+	f.CreatedAt = now
+	f.UpdatedAt = now
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO workspace_profile_fields (id, workspace_id, name, field_type, options, sort_order, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, (SELECT COALESCE(MAX(sort_order), -1) + 1 FROM workspace_profile_fields WHERE workspace_id = ?), ?, ?)
+		RETURNING sort_order
+	`, f.ID, f.WorkspaceID, f.Name, f.Type, formatProfileOptions(f.Options), f.WorkspaceID,
+		now.Format(time.RFC3339), now.Format(time.RFC3339)).Scan(&f.SortOrder)
+	if isUniqueConstraintError(err) {
+		return ErrProfileFieldExists
+	}
+	return err
+}
+
+// UpdateProfileField saves a field's name, options and position. Its type
+// cannot change once members may have filled it in.
+func (r *Repository) UpdateProfileField(ctx context.Context, f *ProfileField) error {
+	f.UpdatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_profile_fields SET name = ?, options = ?, sort_order = ?, updated_at = ?
+		WHERE id = ?
+	`, f.Name, formatProfileOptions(f.Options), f.SortOrder, f.UpdatedAt.Format(time.RFC3339), f.ID)
+	if isUniqueConstraintError(err) {
+		return ErrProfileFieldExists
+	}
+	return err
+}
+
+// DeleteProfileField removes a field along with every member's value for it
+func (r *Repository) DeleteProfileField(ctx context.Context, f *ProfileField) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM workspace_profile_fields WHERE id = ?`, f.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE workspace_memberships SET profile_fields = json_remove(profile_fields, '$."' || ? || '"'), updated_at = ?
+		WHERE workspace_id = ? AND profile_fields IS NOT NULL
+	`, f.ID, time.Now().UTC().Format(time.RFC3339), f.WorkspaceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetLastSeen returns when a member was last connected to the workspace, or
+// nil if they never have been.
+func (r *Repository) GetLastSeen(ctx context.Context, userID, workspaceID string) (*time.Time, error) {
+	var lastSeenAt string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT last_seen_at FROM user_presence WHERE user_id = ? AND workspace_id = ?
+	`, userID, workspaceID).Scan(&lastSeenAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339, lastSeenAt)
+	if err != nil {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// GetMemberProfileValues returns a member's custom profile values, keyed by field ID
+func (r *Repository) GetMemberProfileValues(ctx context.Context, userID, workspaceID string) (map[string]string, error) {
+	var values sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT profile_fields FROM workspace_memberships WHERE user_id = ? AND workspace_id = ?
+	`, userID, workspaceID).Scan(&values)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotAMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseProfileValues(values), nil
+}
+
+// SetMemberProfileValues replaces a member's custom profile values
+func (r *Repository) SetMemberProfileValues(ctx context.Context, userID, workspaceID string, values map[string]string) error {
+	var data *string
+	if len(values) > 0 {
+		b, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		data = &s
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE workspace_memberships SET profile_fields = ?, updated_at = ? WHERE user_id = ? AND workspace_id = ?
+	`, data, time.Now().UTC().Format(time.RFC3339), userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrNotAMember
+	}
+	return nil
+}
+
+func scanProfileField(row interface{ Scan(...any) error }) (*ProfileField, error) {
+	var f ProfileField
+	var options sql.NullString
+	var createdAt, updatedAt string
+	if err := row.Scan(&f.ID, &f.WorkspaceID, &f.Name, &f.Type, &options, &f.SortOrder, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	if options.Valid {
+		_ = json.Unmarshal([]byte(options.String), &f.Options)
+	}
+	f.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	f.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return &f, nil
+}
+
+func formatProfileOptions(options []string) *string {
+	if len(options) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(options)
+	s := string(b)
+	return &s
+}
+
+func parseProfileValues(data sql.NullString) map[string]string {
+	if !data.Valid {
+		return nil
+	}
+	var values map[string]string
+	_ = json.Unmarshal([]byte(data.String), &values)
+	return values
+}
+
 func generateInviteCode() string {
 	bytes := make([]byte, 16)
 	rand.Read(bytes)
@@ -451,6 +935,10 @@ func isUniqueConstraintError(err error) bool {
 	return err != nil && (contains(err.Error(), "UNIQUE constraint failed") || contains(err.Error(), "duplicate key"))
 }
 
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // BeginTx starts a database transaction
 func (r *Repository) BeginTx(ctx context.Context) (*sql.Tx, error) {
 	return r.db.BeginTx(ctx, nil)
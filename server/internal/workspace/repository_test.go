@@ -392,6 +392,69 @@ func TestRepository_AcceptInvite_Expired(t *testing.T) {
 	}
 }
 
+func TestRepository_AcceptInvite_EmailBound(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	invited := testutil.CreateTestUser(t, db, "invited@example.com", "Invited")
+	other := testutil.CreateTestUser(t, db, "other@example.com", "Other")
+
+	ws := &Workspace{Name: "Test WS", Settings: "{}"}
+	repo.Create(ctx, ws, owner.ID)
+
+	email := "Invited@Example.com"
+	invite := &Invite{WorkspaceID: ws.ID, Role: RoleMember, InvitedEmail: &email}
+	repo.CreateInvite(ctx, invite)
+
+	if _, err := repo.AcceptInvite(ctx, invite.Code, other.ID); !errors.Is(err, ErrInviteEmailMismatch) {
+		t.Errorf("AcceptInvite() by other error = %v, want %v", err, ErrInviteEmailMismatch)
+	}
+	if _, err := repo.AcceptInvite(ctx, invite.Code, invited.ID); err != nil {
+		t.Errorf("AcceptInvite() by invited address error = %v", err)
+	}
+}
+
+func TestRepository_ListAndDeleteInvites(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	member := testutil.CreateTestUser(t, db, "member@example.com", "Member")
+
+	ws := &Workspace{Name: "Test WS", Settings: "{}"}
+	repo.Create(ctx, ws, owner.ID)
+
+	first := &Invite{WorkspaceID: ws.ID, Role: RoleMember, CreatedBy: &owner.ID}
+	second := &Invite{WorkspaceID: ws.ID, Role: RoleGuest, CreatedBy: &member.ID}
+	repo.CreateInvite(ctx, first)
+	repo.CreateInvite(ctx, second)
+
+	invites, err := repo.ListInvites(ctx, ws.ID, "")
+	if err != nil {
+		t.Fatalf("ListInvites() error = %v", err)
+	}
+	if len(invites) != 2 || invites[0].ID != second.ID {
+		t.Errorf("ListInvites() = %+v, want both, newest first", invites)
+	}
+	invites, _ = repo.ListInvites(ctx, ws.ID, member.ID)
+	if len(invites) != 1 || invites[0].ID != second.ID {
+		t.Errorf("ListInvites(createdBy) = %+v, want only the member's invite", invites)
+	}
+
+	if err := repo.DeleteInvite(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteInvite() error = %v", err)
+	}
+	if _, err := repo.GetInviteByCode(ctx, second.Code); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("GetInviteByCode() after delete error = %v, want %v", err, ErrInviteNotFound)
+	}
+	if err := repo.DeleteInvite(ctx, second.ID); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("second DeleteInvite() error = %v, want %v", err, ErrInviteNotFound)
+	}
+}
+
 func TestRepository_AcceptInvite_MaxUsed(t *testing.T) {
 	db := testutil.TestDB(t)
 	repo := NewRepository(db)
@@ -424,3 +487,55 @@ func TestRepository_AcceptInvite_MaxUsed(t *testing.T) {
 		t.Errorf("second AcceptInvite() error = %v, want %v", err, ErrInviteMaxUsed)
 	}
 }
+
+func TestRepository_ProfileFields(t *testing.T) {
+	db := testutil.TestDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	owner := testutil.CreateTestUser(t, db, "owner@example.com", "Owner")
+	ws := testutil.CreateTestWorkspace(t, db, owner.ID, "Profiles")
+
+	team := &ProfileField{WorkspaceID: ws.ID, Name: "Team", Type: ProfileFieldSelect, Options: []string{"Design", "Engineering"}}
+	if err := repo.CreateProfileField(ctx, team); err != nil {
+		t.Fatalf("CreateProfileField: %v", err)
+	}
+	site := &ProfileField{WorkspaceID: ws.ID, Name: "Website", Type: ProfileFieldURL}
+	if err := repo.CreateProfileField(ctx, site); err != nil {
+		t.Fatalf("CreateProfileField: %v", err)
+	}
+	if team.SortOrder != 0 || site.SortOrder != 1 {
+		t.Errorf("expected fields to be appended in order, got %d and %d", team.SortOrder, site.SortOrder)
+	}
+	if err := repo.CreateProfileField(ctx, &ProfileField{WorkspaceID: ws.ID, Name: "Team", Type: ProfileFieldText}); !errors.Is(err, ErrProfileFieldExists) {
+		t.Errorf("expected ErrProfileFieldExists for a duplicate name, got %v", err)
+	}
+
+	fields, err := repo.ListProfileFields(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("ListProfileFields: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Name != "Team" || len(fields[0].Options) != 2 {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+
+	values := map[string]string{team.ID: "Design", site.ID: "https://example.com"}
+	if err := repo.SetMemberProfileValues(ctx, owner.ID, ws.ID, values); err != nil {
+		t.Fatalf("SetMemberProfileValues: %v", err)
+	}
+
+	// Deleting a field drops every member's value for it
+	if err := repo.DeleteProfileField(ctx, team); err != nil {
+		t.Fatalf("DeleteProfileField: %v", err)
+	}
+	got, err := repo.GetMemberProfileValues(ctx, owner.ID, ws.ID)
+	if err != nil {
+		t.Fatalf("GetMemberProfileValues: %v", err)
+	}
+	if len(got) != 1 || got[site.ID] != "https://example.com" {
+		t.Errorf("expected only the website value to remain, got %v", got)
+	}
+	if _, err := repo.GetProfileField(ctx, team.ID); !errors.Is(err, ErrProfileFieldNotFound) {
+		t.Errorf("expected ErrProfileFieldNotFound, got %v", err)
+	}
+}